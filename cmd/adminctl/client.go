@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// apiClient is a thin HTTP client for the admin API
+type apiClient struct {
+	baseURL     string
+	operatorKey string
+	http        *http.Client
+	stream      *http.Client // without timeout, for event streams
+}
+
+// newAPIClient creates a client for the API served at baseURL, presenting operatorKey when set
+func newAPIClient(baseURL, operatorKey string, timeout time.Duration) *apiClient {
+	return &apiClient{
+		baseURL:     strings.TrimRight(baseURL, "/"),
+		operatorKey: operatorKey,
+		http:        &http.Client{Timeout: timeout},
+		stream:      &http.Client{},
+	}
+}
+
+// apiError is returned for non-2xx responses
+type apiError struct {
+	StatusCode int
+	Response   handlers.ErrorResponse
+}
+
+func (e *apiError) Error() string {
+	msg := fmt.Sprintf("api returned %d: %s: %s", e.StatusCode, e.Response.Error, e.Response.Message)
+	if e.Response.Code != "" {
+		msg += " (" + e.Response.Code + ")"
+	}
+	return msg
+}
+
+// ListStuckTrips returns the active trips not updated for longer than olderThan
+func (c *apiClient) ListStuckTrips(ctx context.Context, olderThan time.Duration, limit int) (*handlers.StuckTripsResponse, error) {
+	query := url.Values{}
+	query.Set("older_than", olderThan.String())
+	query.Set("limit", strconv.Itoa(limit))
+
+	var resp handlers.StuckTripsResponse
+	if err := c.do(ctx, http.MethodGet, "/api/v1/admin/trips/stuck?"+query.Encode(), nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to list stuck trips: %w", err)
+	}
+	return &resp, nil
+}
+
+// CancelTrip force-cancels a trip and returns it cancelled
+func (c *apiClient) CancelTrip(ctx context.Context, tripID uuid.UUID, reason models.CancellationReason, note string) (*models.Trip, error) {
+	req := handlers.ForceCancelTripRequest{Reason: reason, Note: note}
+
+	var trip models.Trip
+	if err := c.do(ctx, http.MethodPost, fmt.Sprintf("/api/v1/admin/trips/%s/cancel", tripID), req, &trip); err != nil {
+		return nil, fmt.Errorf("failed to cancel trip: %w", err)
+	}
+	return &trip, nil
+}
+
+// SetDriverStatus sets a driver online, offline or busy
+func (c *apiClient) SetDriverStatus(ctx context.Context, driverID uuid.UUID, status models.DriverStatus) error {
+	path := fmt.Sprintf("/api/v1/drivers/%s/status", driverID)
+	if err := c.do(ctx, http.MethodPut, path, handlers.UpdateDriverStatusRequest{Status: string(status)}, nil); err != nil {
+		return fmt.Errorf("failed to set driver status: %w", err)
+	}
+	return nil
+}
+
+// RunRetention runs the partition retention now
+func (c *apiClient) RunRetention(ctx context.Context) (*handlers.RetentionRunResponse, error) {
+	var resp handlers.RetentionRunResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/retention/run", nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to run retention: %w", err)
+	}
+	return &resp, nil
+}
+
+// Reconcile runs the payment reconciliation now
+func (c *apiClient) Reconcile(ctx context.Context) (*models.PaymentReconciliationRun, error) {
+	var run models.PaymentReconciliationRun
+	if err := c.do(ctx, http.MethodPost, "/api/v1/admin/payments/reconcile", nil, &run); err != nil {
+		return nil, fmt.Errorf("failed to reconcile payments: %w", err)
+	}
+	return &run, nil
+}
+
+// TailEvents streams the event logs matching the filter query to handle until ctx is done or
+// the server closes the stream
+func (c *apiClient) TailEvents(ctx context.Context, filter url.Values, handle func(*models.EventLog) error) error {
+	path := "/api/v1/observability/events/stream"
+	if len(filter) > 0 {
+		path += "?" + filter.Encode()
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := c.stream.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to tail events: %w", err)
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return fmt.Errorf("failed to tail events: %w", err)
+	}
+
+	// Each event_log event has a single data line carrying the event as JSON
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		var event models.EventLog
+		if err := json.Unmarshal([]byte(strings.TrimSpace(data)), &event); err != nil {
+			return fmt.Errorf("failed to decode event: %w", err)
+		}
+		if err := handle(&event); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("event stream failed: %w", err)
+	}
+	return nil
+}
+
+// do sends a JSON request and decodes a JSON response into out when out is non-nil
+func (c *apiClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// newRequest builds a request with body encoded as JSON when non-nil
+func (c *apiClient) newRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.operatorKey != "" {
+		req.Header.Set(middleware.OperatorKeyHeader, c.operatorKey)
+	}
+	req.Header.Set("User-Agent", "actor-model-adminctl")
+	return req, nil
+}
+
+// checkResponse returns an apiError for non-2xx responses
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	apiErr := &apiError{StatusCode: resp.StatusCode}
+	_ = json.NewDecoder(resp.Body).Decode(&apiErr.Response)
+	return apiErr
+}
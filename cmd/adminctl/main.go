@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Output formats
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// command is an adminctl subcommand
+type command struct {
+	name    string
+	args    string // positional arguments, for the usage
+	summary string
+	run     func(ctx context.Context, app *app, args []string) error
+}
+
+var commands = []command{
+	{"stuck-trips", "", "List trips that stopped progressing", runStuckTrips},
+	{"cancel-trip", "<trip-id>", "Force-cancel a trip (operator key required)", runCancelTrip},
+	{"driver-status", "<driver-id> <online|offline|busy>", "Set a driver's status", runDriverStatus},
+	{"tail-events", "", "Stream event logs as they are recorded, until interrupted", runTailEvents},
+	{"retention", "", "Run partition creation and retention now (operator key required)", runRetention},
+	{"reconcile", "", "Run the payment reconciliation now", runReconcile},
+}
+
+// app holds the settings shared by the subcommands
+type app struct {
+	client *apiClient
+	output string
+	out    io.Writer
+}
+
+func main() {
+	baseURL := flag.String("url", envOr("ADMINCTL_URL", "http://localhost:8080"), "Base URL of the API server (ADMINCTL_URL)")
+	operatorKey := flag.String("operator-key", os.Getenv("ADMINCTL_OPERATOR_KEY"), "Operator key, one of the server's OPERATOR_API_KEYS (ADMINCTL_OPERATOR_KEY)")
+	output := flag.String("o", outputTable, "Output format: table or json")
+	timeout := flag.Duration("timeout", 30*time.Second, "HTTP request timeout, except for tail-events")
+	flag.Usage = usage
+	flag.Parse()
+
+	if *output != outputTable && *output != outputJSON {
+		fmt.Fprintf(os.Stderr, "Invalid output format %q: must be table or json\n", *output)
+		os.Exit(2)
+	}
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == flag.Arg(0) {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	a := &app{
+		client: newAPIClient(*baseURL, *operatorKey, *timeout),
+		output: *output,
+		out:    os.Stdout,
+	}
+	if err := cmd.run(ctx, a, flag.Args()[1:]); err != nil {
+		fmt.Fprintf(os.Stderr, "adminctl %s: %v\n", cmd.name, err)
+		os.Exit(1)
+	}
+}
+
+// usage prints the global flags and the subcommands
+func usage() {
+	w := flag.CommandLine.Output()
+	fmt.Fprintf(w, "Usage: adminctl [flags] <command> [command flags] [args]\n\nCommands:\n")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", cmd.name, cmd.args, cmd.summary)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nRun adminctl <command> -h for the flags of a command.\n\nFlags:\n")
+	flag.PrintDefaults()
+}
+
+// newFlagSet creates the flag set of a subcommand
+func newFlagSet(name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: adminctl %s [flags] %s\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func runStuckTrips(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("stuck-trips", "")
+	olderThan := fs.Duration("older-than", 15*time.Minute, "List trips not updated for longer than this")
+	limit := fs.Int("limit", 50, "Maximum number of trips, at most 500")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	resp, err := a.client.ListStuckTrips(ctx, *olderThan, *limit)
+	if err != nil {
+		return err
+	}
+	if a.output == outputJSON {
+		return a.writeJSON(resp)
+	}
+
+	return a.writeTable(func(w io.Writer) {
+		fmt.Fprintln(w, "TRIP\tSTATUS\tPASSENGER\tDRIVER\tREQUESTED\tLAST UPDATE")
+		for _, trip := range resp.Trips {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s ago\n", trip.ID, trip.Status, trip.PassengerID,
+				optional(trip.DriverID), formatTime(trip.RequestedAt), time.Since(trip.UpdatedAt).Round(time.Second))
+		}
+	})
+}
+
+func runCancelTrip(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("cancel-trip", "<trip-id>")
+	reason := fs.String("reason", string(models.CancellationReasonOther), "Cancellation reason, one of "+cancellationReasons())
+	note := fs.String("note", "", "Details recorded with the cancellation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("expected the trip ID")
+	}
+	tripID, err := uuid.Parse(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid trip ID %q", fs.Arg(0))
+	}
+
+	trip, err := a.client.CancelTrip(ctx, tripID, models.CancellationReason(*reason), *note)
+	if err != nil {
+		return err
+	}
+	if a.output == outputJSON {
+		return a.writeJSON(trip)
+	}
+
+	return a.writeTable(func(w io.Writer) {
+		fmt.Fprintln(w, "TRIP\tSTATUS\tREASON\tCANCELLED")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", trip.ID, trip.Status, optional(trip.CancellationReason), optionalTime(trip.CancelledAt))
+	})
+}
+
+func runDriverStatus(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("driver-status", "<driver-id> <online|offline|busy>")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return errors.New("expected the driver ID and the status")
+	}
+	driverID, err := uuid.Parse(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid driver ID %q", fs.Arg(0))
+	}
+	status := models.DriverStatus(fs.Arg(1))
+	switch status {
+	case models.DriverStatusOnline, models.DriverStatusOffline, models.DriverStatusBusy:
+	default:
+		return fmt.Errorf("invalid status %q: must be online, offline or busy", status)
+	}
+
+	if err := a.client.SetDriverStatus(ctx, driverID, status); err != nil {
+		return err
+	}
+	if a.output == outputJSON {
+		return a.writeJSON(map[string]string{"driver_id": driverID.String(), "status": string(status)})
+	}
+
+	return a.writeTable(func(w io.Writer) {
+		fmt.Fprintln(w, "DRIVER\tSTATUS")
+		fmt.Fprintf(w, "%s\t%s\n", driverID, status)
+	})
+}
+
+func runTailEvents(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("tail-events", "")
+	severity := fs.String("severity", "", "Only events of this severity: debug, info, warn, error or fatal")
+	category := fs.String("category", "", "Only events of this category: business, system, error, performance or security")
+	actorType := fs.String("actor-type", "", "Only events of this actor type")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	filter := url.Values{}
+	for key, value := range map[string]string{"severity": *severity, "category": *category, "actor_type": *actorType} {
+		if value != "" {
+			filter.Set(key, value)
+		}
+	}
+
+	// Events are written as they arrive, one JSON object or row per line; the rows have fixed
+	// column widths as later events cannot realign the earlier ones
+	const row = "%-19s  %-8s  %-11s  %-28s  %-24s  %s\n"
+	if a.output == outputTable {
+		fmt.Fprintf(a.out, row, "TIME", "SEVERITY", "CATEGORY", "TYPE", "ACTOR", "MESSAGE")
+	}
+	encoder := json.NewEncoder(a.out)
+
+	return a.client.TailEvents(ctx, filter, func(event *models.EventLog) error {
+		if a.output == outputJSON {
+			return encoder.Encode(event)
+		}
+		_, err := fmt.Fprintf(a.out, row, formatTime(event.Timestamp), event.Severity, event.EventCategory,
+			event.EventType, optional(event.ActorID), event.Message)
+		return err
+	})
+}
+
+func runRetention(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("retention", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	resp, err := a.client.RunRetention(ctx)
+	if err != nil {
+		return err
+	}
+	if a.output == outputJSON {
+		return a.writeJSON(resp)
+	}
+
+	return a.writeTable(func(w io.Writer) {
+		fmt.Fprintln(w, "STARTED\tDURATION")
+		fmt.Fprintf(w, "%s\t%s\n", formatTime(resp.StartedAt), time.Duration(resp.DurationMs)*time.Millisecond)
+	})
+}
+
+func runReconcile(ctx context.Context, a *app, args []string) error {
+	fs := newFlagSet("reconcile", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	run, err := a.client.Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+	if a.output == outputJSON {
+		return a.writeJSON(run)
+	}
+
+	return a.writeTable(func(w io.Writer) {
+		fmt.Fprintln(w, "WINDOW\tCHECKED\tBALANCED\tALREADY OPEN\tNEW DISCREPANCIES\tDURATION")
+		fmt.Fprintf(w, "%s - %s\t%d\t%d\t%d\t%d\t%s\n", formatTime(run.WindowStart), formatTime(run.WindowEnd),
+			run.Checked, run.Balanced, run.AlreadyOpen, len(run.Discrepancies), time.Duration(run.DurationMs)*time.Millisecond)
+		if len(run.Discrepancies) == 0 {
+			return
+		}
+		fmt.Fprintln(w, "\nDISCREPANCY\tTRIP\tTYPE\tEXPECTED\tPAID")
+		for _, d := range run.Discrepancies {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%.2f\t%.2f\n", d.ID, d.TripID, d.Type, d.ExpectedAmount, d.PaidAmount)
+		}
+	})
+}
+
+// writeJSON writes v as indented JSON
+func (a *app) writeJSON(v interface{}) error {
+	encoder := json.NewEncoder(a.out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// writeTable writes the tab-separated rows written by write as aligned columns
+func (a *app) writeTable(write func(w io.Writer)) error {
+	tw := tabwriter.NewWriter(a.out, 0, 0, 2, ' ', 0)
+	write(tw)
+	return tw.Flush()
+}
+
+// optional formats a value that may be missing as "-"
+func optional[T any](v *T) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprint(*v)
+}
+
+// formatTime formats a time in local time, to the second
+func formatTime(t time.Time) string {
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// optionalTime formats a time that may be missing as "-"
+func optionalTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return formatTime(*t)
+}
+
+// cancellationReasons lists the cancellation reasons for the flag usage
+func cancellationReasons() string {
+	reasons := make([]string, len(models.CancellationReasons))
+	for i, reason := range models.CancellationReasons {
+		reasons[i] = string(reason)
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// envOr returns the environment variable key, or fallback when it is not set
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/loadtest"
+	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// apiClient is a thin HTTP client for the endpoints the load test exercises
+type apiClient struct {
+	baseURL string
+	mode    string
+	http    *http.Client
+}
+
+// newAPIClient creates a client for the API served at baseURL
+func newAPIClient(baseURL, mode string, timeout time.Duration, connections int) *apiClient {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Keep a connection per user so requests don't queue for connection setup
+	transport.MaxIdleConnsPerHost = connections
+	return &apiClient{
+		baseURL: baseURL,
+		mode:    mode,
+		http:    &http.Client{Timeout: timeout, Transport: transport},
+	}
+}
+
+// statusError is returned for non-2xx responses
+type statusError struct {
+	StatusCode int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("api returned %d", e.StatusCode)
+}
+
+// CreatePassenger registers a passenger and returns its passenger ID
+func (c *apiClient) CreatePassenger(ctx context.Context, req handlers.CreatePassengerRequest) (uuid.UUID, error) {
+	var user models.User
+	if err := c.do(ctx, http.MethodPost, "/api/v1/passengers", req, &user); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to create passenger: %w", err)
+	}
+
+	var passenger models.Passenger
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/users/%s/passenger", user.ID), nil, &passenger); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to get passenger: %w", err)
+	}
+	return passenger.ID, nil
+}
+
+// RequestRide requests a ride with the client's processing approach and returns the trip ID
+func (c *apiClient) RequestRide(ctx context.Context, req handlers.RequestRideRequest) (uuid.UUID, error) {
+	var resp handlers.RequestRideResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/rides/request?approach="+url.QueryEscape(c.mode), req, &resp); err != nil {
+		return uuid.Nil, err
+	}
+	return resp.TripID, nil
+}
+
+// CancelRide cancels a trip on behalf of its passenger
+func (c *apiClient) CancelRide(ctx context.Context, tripID, passengerID uuid.UUID) error {
+	req := handlers.CancelRideRequest{TripID: tripID, PassengerID: passengerID, Reason: models.CancellationReasonOther, Note: "load test"}
+	path := fmt.Sprintf("/api/v1/rides/%s/cancel?approach=%s", tripID, url.QueryEscape(c.mode))
+	return c.do(ctx, http.MethodPost, path, req, nil)
+}
+
+// Get requests path and discards the response
+func (c *apiClient) Get(ctx context.Context, path string) error {
+	return c.do(ctx, http.MethodGet, path, nil, nil)
+}
+
+// Replay re-issues a recorded request, failing with a statusError when the response's status class
+// differs from the recorded one, e.g. a 500 for a request recorded as a 200 or a 404
+func (c *apiClient) Replay(ctx context.Context, recorded *loadtest.RecordedRequest) error {
+	var body io.Reader
+	if len(recorded.Body) > 0 {
+		body = bytes.NewReader(recorded.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, recorded.Method, c.baseURL+recorded.Path, body)
+	if err != nil {
+		return err
+	}
+	if recorded.ContentType != "" {
+		req.Header.Set("Content-Type", recorded.ContentType)
+	}
+	req.Header.Set("User-Agent", "actor-model-load-test")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode/100 != recorded.Status/100 {
+		return &statusError{StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// do sends a JSON request and decodes a JSON response into out when out is non-nil
+func (c *apiClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("User-Agent", "actor-model-load-test")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return &statusError{StatusCode: resp.StatusCode}
+	}
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
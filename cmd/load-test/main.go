@@ -0,0 +1,651 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/loadtest"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/scenario"
+)
+
+// exitSLAViolated is the exit code of a run that completed but exceeded an asserted limit
+const exitSLAViolated = 3
+
+// rideEndpoint selects the ride request workload instead of a plain GET endpoint
+const rideEndpoint = "rides"
+
+// loadConfig holds the load test settings parsed from flags
+type loadConfig struct {
+	baseURL        string
+	mode           string
+	endpoint       string
+	users          int
+	duration       time.Duration
+	thinkTime      time.Duration
+	rate           float64
+	requestTimeout time.Duration
+	output         string
+	sla            loadtest.SLA
+	replay         string  // recording file to replay instead of generating load
+	speed          float64 // replay pace as a multiple of the recorded pace
+	durationSet    bool    // -duration given explicitly, bounding a replay
+	scenarioPath   string
+	scenario       *scenario.Scenario // places the requested rides when set
+	phases         []loadtest.Phase   // open-loop arrival rate by phase, from the scenario
+}
+
+// workerResult is what one user recorded. In open-loop runs corrected holds the latencies
+// measured from each request's intended start, including requests unfinished at the end.
+type workerResult struct {
+	latencies  *loadtest.Histogram
+	corrected  *loadtest.Histogram
+	errors     int64
+	errorKinds map[string]int64
+}
+
+// openLoopStats describes how well an open-loop run kept to its arrival rate
+type openLoopStats struct {
+	sent    int64
+	missed  int64
+	overdue *loadtest.Histogram
+}
+
+// runResult is the JSON report of a run
+type runResult struct {
+	Mode       string               `json:"mode"`
+	LoadModel  string               `json:"load_model"` // closed, open or replay
+	TargetRate float64              `json:"target_rate_rps,omitempty"`
+	Scenario   string               `json:"scenario,omitempty"`
+	Phases     []loadtest.Phase     `json:"phases,omitempty"`
+	Speed      float64              `json:"replay_speed,omitempty"`
+	BaseURL    string               `json:"base_url"`
+	Endpoint   string               `json:"endpoint"`
+	Users      int                  `json:"users"`
+	Duration   string               `json:"duration"`
+	Requests   int64                `json:"requests"`
+	Errors     int64                `json:"errors"`
+	ErrorRate  float64              `json:"error_rate"`
+	Throughput float64              `json:"throughput_rps"`
+	Latency    latencySummary       `json:"latency"`
+	Histogram  []loadtest.Bucket    `json:"histogram"`
+	Corrected  *correctedLatency    `json:"corrected_latency,omitempty"`
+	ErrorKinds map[string]int64     `json:"error_kinds,omitempty"`
+	Violations []loadtest.Violation `json:"sla_violations,omitempty"`
+}
+
+// latencySummary holds the latency percentiles of a run in milliseconds
+type latencySummary struct {
+	Min  float64 `json:"min_ms"`
+	Mean float64 `json:"mean_ms"`
+	P50  float64 `json:"p50_ms"`
+	P90  float64 `json:"p90_ms"`
+	P95  float64 `json:"p95_ms"`
+	P99  float64 `json:"p99_ms"`
+	P999 float64 `json:"p999_ms"`
+	Max  float64 `json:"max_ms"`
+}
+
+// correctedLatency is the latency of an open-loop run measured from each request's intended
+// start, so time spent waiting behind slow requests is not omitted
+type correctedLatency struct {
+	latencySummary
+	Histogram   []loadtest.Bucket `json:"histogram"`
+	MissedTicks int64             `json:"missed_ticks"` // arrivals sent over one interval late
+	Unfinished  int64             `json:"unfinished"`   // arrivals still waiting or in flight at the end
+}
+
+func main() {
+	var cfg loadConfig
+	var errorRate string
+	var scenarioPath string
+
+	flag.StringVar(&cfg.baseURL, "url", "http://localhost:8080", "Base URL of the API server")
+	flag.StringVar(&cfg.mode, "mode", "actor", "Processing approach: actor or traditional")
+	flag.StringVar(&cfg.endpoint, "endpoint", rideEndpoint, "'rides' to request and cancel rides, or an API path to GET")
+	flag.IntVar(&cfg.users, "users", 10, "Number of concurrent users")
+	flag.IntVar(&cfg.users, "concurrency", 10, "Alias of -users")
+	flag.DurationVar(&cfg.duration, "duration", time.Minute, "How long to generate load")
+	flag.DurationVar(&cfg.thinkTime, "think-time", 100*time.Millisecond, "Pause of each user between requests")
+	flag.Float64Var(&cfg.rate, "rate", 0, "Requests per second for an open-loop run, where -users bounds the requests in flight (0 = closed-loop users)")
+	flag.DurationVar(&cfg.requestTimeout, "request-timeout", 10*time.Second, "HTTP request timeout")
+	flag.StringVar(&cfg.output, "output", "", "Write the JSON results to this file")
+	flag.DurationVar(&cfg.sla.P50, "assert-p50", 0, "Exit with code 3 if the p50 latency exceeds this (0 = not asserted); corrected latency with -rate")
+	flag.DurationVar(&cfg.sla.P95, "assert-p95", 0, "Exit with code 3 if the p95 latency exceeds this (0 = not asserted); corrected latency with -rate")
+	flag.DurationVar(&cfg.sla.P99, "assert-p99", 0, "Exit with code 3 if the p99 latency exceeds this (0 = not asserted); corrected latency with -rate")
+	flag.StringVar(&errorRate, "assert-error-rate", "", "Exit with code 3 if more than this share of requests fail, e.g. 1%")
+	flag.StringVar(&cfg.replay, "replay", "", "Replay the traffic recording in this file at its recorded pace instead of generating load; -users bounds the requests in flight and -duration, when given, the run")
+	flag.Float64Var(&cfg.speed, "speed", 1, "Replay pace as a multiple of the recorded pace, e.g. 2 for twice as fast")
+	flag.StringVar(&scenarioPath, "scenario", "", "YAML scenario file: its area and hotspots place the rides, and its passengers, arrival rate and phases, duration and seed set the load; flags given explicitly override its values")
+	flag.Parse()
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+	cfg.durationSet = explicit["duration"]
+
+	if scenarioPath != "" {
+		if err := cfg.applyScenario(scenarioPath, explicit); err != nil {
+			log.Fatalf("Failed to load scenario: %v", err)
+		}
+	}
+
+	if errorRate != "" {
+		rate, err := loadtest.ParsePercent(errorRate)
+		if err != nil {
+			log.Fatalf("Invalid flags: %v", err)
+		}
+		cfg.sla.ErrorRate = rate
+	}
+	if err := cfg.validate(); err != nil {
+		log.Fatalf("Invalid flags: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client := newAPIClient(cfg.baseURL, cfg.mode, cfg.requestTimeout, cfg.users)
+	var requests []request
+	var recording []*loadtest.RecordedRequest
+	var err error
+	if cfg.replay != "" {
+		recording, err = loadtest.ReadRecordingFile(cfg.replay)
+	} else {
+		requests, err = newWorkload(ctx, cfg, client)
+	}
+	if err != nil {
+		log.Fatalf("Failed to prepare load test: %v", err)
+	}
+
+	started := time.Now()
+	var result *runResult
+	if cfg.replay != "" {
+		replay := loadtest.NewReplay(recording, cfg.speed)
+		fmt.Printf("Replaying %d requests from %s against %s at %gx speed (%s) with up to %d in flight\n", len(recording), cfg.replay, cfg.baseURL, cfg.speed, replay.Length().Round(time.Millisecond), cfg.users)
+		results, stats := runReplay(ctx, cfg, client, replay)
+		result = summarize(cfg, results, &stats, time.Since(started))
+		result.LoadModel = "replay"
+		result.Speed = cfg.speed
+	} else if cfg.openLoop() {
+		fmt.Printf("Load testing %s%s (%s) at %s with up to %d in flight for %s\n", cfg.baseURL, cfg.endpointLabel(), cfg.mode, cfg.rateLabel(), cfg.users, cfg.duration)
+		results, stats := runOpenLoop(ctx, cfg, requests)
+		result = summarize(cfg, results, &stats, time.Since(started))
+	} else {
+		fmt.Printf("Load testing %s%s (%s) with %d users for %s\n", cfg.baseURL, cfg.endpointLabel(), cfg.mode, cfg.users, cfg.duration)
+		results := run(ctx, cfg, requests)
+		result = summarize(cfg, results, nil, time.Since(started))
+	}
+
+	printResult(result)
+	if cfg.output != "" {
+		if err := writeResult(cfg.output, result); err != nil {
+			log.Fatalf("Failed to write results: %v", err)
+		}
+	}
+
+	if len(result.Violations) > 0 {
+		fmt.Println("SLA violated:")
+		for _, v := range result.Violations {
+			fmt.Printf("  %s\n", v)
+		}
+		os.Exit(exitSLAViolated)
+	}
+}
+
+func (c loadConfig) validate() error {
+	if c.mode != "actor" && c.mode != "traditional" {
+		return fmt.Errorf("mode must be either 'actor' or 'traditional'")
+	}
+	if c.users <= 0 {
+		return fmt.Errorf("users must be positive")
+	}
+	if c.duration <= 0 {
+		return fmt.Errorf("duration must be positive")
+	}
+	if c.thinkTime < 0 {
+		return fmt.Errorf("think time must not be negative")
+	}
+	if c.rate < 0 {
+		return fmt.Errorf("rate must not be negative")
+	}
+	if c.requestTimeout <= 0 {
+		return fmt.Errorf("request timeout must be positive")
+	}
+	if c.endpoint != rideEndpoint && (c.endpoint == "" || c.endpoint[0] != '/') {
+		return fmt.Errorf("endpoint must be 'rides' or a path starting with /")
+	}
+	if c.speed <= 0 {
+		return fmt.Errorf("speed must be positive")
+	}
+	if c.replay != "" && c.rate > 0 {
+		return fmt.Errorf("rate cannot be combined with replay; use speed to change the replay pace")
+	}
+	if c.replay != "" && c.scenario != nil {
+		return fmt.Errorf("scenario cannot be combined with replay")
+	}
+	return nil
+}
+
+// applyScenario loads the scenario file at path. Its passengers, arrival rate or phases,
+// duration and seed replace the defaults of the flags not given explicitly; its driver settings
+// do not apply to a load test. A scenario without an arrival rate keeps closed-loop users.
+func (c *loadConfig) applyScenario(path string, explicit map[string]bool) error {
+	sc, err := scenario.Load(path)
+	if err != nil {
+		return err
+	}
+	if sc.Seed == 0 {
+		sc.Seed = time.Now().UnixNano()
+	}
+	c.scenarioPath = path
+	c.scenario = sc
+
+	if !explicit["users"] && !explicit["concurrency"] && sc.Passengers.Count > 0 {
+		c.users = sc.Passengers.Count
+	}
+	if !explicit["duration"] {
+		c.duration = sc.Duration
+	}
+	if !explicit["rate"] {
+		c.phases = arrivalPhases(sc)
+	}
+	return nil
+}
+
+// arrivalPhases converts the scenario's ride requests per minute to open-loop phases in
+// requests per second, or returns none when the scenario has no arrival rate
+func arrivalPhases(sc *scenario.Scenario) []loadtest.Phase {
+	if len(sc.Phases) == 0 {
+		if sc.Passengers.ArrivalRate <= 0 {
+			return nil
+		}
+		return []loadtest.Phase{{Rate: sc.Passengers.ArrivalRate / 60}}
+	}
+
+	phases := make([]loadtest.Phase, 0, len(sc.Phases))
+	for _, p := range sc.Phases {
+		phases = append(phases, loadtest.Phase{Duration: p.Duration, Rate: p.ArrivalRate / 60})
+	}
+	return phases
+}
+
+// openLoop reports whether requests are issued at an arrival rate rather than by closed-loop users
+func (c loadConfig) openLoop() bool {
+	return c.rate > 0 || len(c.phases) > 0
+}
+
+// rateLabel describes the arrival rate of an open-loop run
+func (c loadConfig) rateLabel() string {
+	if c.rate > 0 {
+		return fmt.Sprintf("%g requests/s", c.rate)
+	}
+	return fmt.Sprintf("the arrival rate of scenario %q (%d phases, seed %d)", c.scenario.Name, len(c.phases), c.scenario.Seed)
+}
+
+// endpointLabel describes the endpoint under load
+func (c loadConfig) endpointLabel() string {
+	if c.replay != "" {
+		return "replay of " + c.replay
+	}
+	if c.endpoint == rideEndpoint {
+		return "/api/v1/rides/request"
+	}
+	return c.endpoint
+}
+
+// request is one timed operation of a user. It may return a cleanup that runs after the
+// request is timed, such as cancelling a requested ride.
+type request func(ctx context.Context) (cleanup func(ctx context.Context) error, err error)
+
+// newWorkload returns the request of each user. The ride workload registers a passenger per
+// user; every request asks for a ride, which is cancelled outside the measurement so the
+// passenger is free to request again.
+func newWorkload(ctx context.Context, cfg loadConfig, client *apiClient) ([]request, error) {
+	requests := make([]request, cfg.users)
+	if cfg.endpoint != rideEndpoint {
+		for i := range requests {
+			requests[i] = func(ctx context.Context) (func(context.Context) error, error) {
+				return nil, client.Get(ctx, cfg.endpoint)
+			}
+		}
+		return requests, nil
+	}
+
+	// Unique per run so repeated runs don't collide on email and phone
+	runID := time.Now().Unix()
+	// Rides start and end in a 0.1 degree square of Jakarta unless a scenario places them
+	seed := runID
+	randomLocation := func(rng *rand.Rand) models.Location {
+		return models.Location{Latitude: -6.2 + rng.Float64()*0.1, Longitude: 106.8 + rng.Float64()*0.1}
+	}
+	if cfg.scenario != nil {
+		seed = cfg.scenario.Seed
+		randomLocation = cfg.scenario.RandomLocation
+	}
+	for i := range requests {
+		passengerID, err := client.CreatePassenger(ctx, handlers.CreatePassengerRequest{
+			CreateUserRequest: handlers.CreateUserRequest{
+				Email:    fmt.Sprintf("load-passenger-%d-%d@example.com", runID, i),
+				Phone:    fmt.Sprintf("+3%d%04d", runID, i),
+				Name:     fmt.Sprintf("Load Test Passenger %d", i+1),
+				UserType: string(models.UserTypePassenger),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		rng := rand.New(rand.NewSource(seed + int64(i)))
+		requests[i] = func(ctx context.Context) (func(context.Context) error, error) {
+			pickup, dropoff := randomLocation(rng), randomLocation(rng)
+			tripID, err := client.RequestRide(ctx, handlers.RequestRideRequest{
+				PassengerID:    passengerID,
+				PickupLat:      pickup.Latitude,
+				PickupLng:      pickup.Longitude,
+				DestinationLat: dropoff.Latitude,
+				DestinationLng: dropoff.Longitude,
+				RideType:       "standard",
+			})
+			if err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) error {
+				return client.CancelRide(ctx, tripID, passengerID)
+			}, nil
+		}
+	}
+	return requests, nil
+}
+
+// runOpenLoop issues requests at the configured arrival rate until the duration elapses or
+// ctx is cancelled. Each user is a worker taking the next arrival when it is free, so -users
+// bounds the requests in flight; arrivals waiting for a worker count towards their corrected
+// latency.
+func runOpenLoop(ctx context.Context, cfg loadConfig, requests []request) ([]*workerResult, openLoopStats) {
+	runCtx, cancel := context.WithTimeout(ctx, cfg.duration)
+	defer cancel()
+
+	arrivals := loadtest.NewArrivals(cfg.rate)
+	if len(cfg.phases) > 0 {
+		arrivals = loadtest.NewPhasedArrivals(cfg.phases)
+	}
+	queue := make(chan time.Time, len(requests))
+	var sent int64
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sent = arrivals.Run(runCtx, queue)
+	}()
+
+	results := make([]*workerResult, len(requests))
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		results[i] = &workerResult{
+			latencies:  loadtest.NewHistogram(),
+			corrected:  loadtest.NewHistogram(),
+			errorKinds: make(map[string]int64),
+		}
+		wg.Add(1)
+		go func(result *workerResult, req request) {
+			defer wg.Done()
+			for intended := range queue {
+				result.serveArrival(runCtx, intended, req)
+			}
+		}(results[i], req)
+	}
+	wg.Wait()
+	<-done
+
+	return results, openLoopStats{sent: sent, missed: arrivals.Missed(), overdue: arrivals.Overdue()}
+}
+
+// runReplay re-issues the recorded requests at their replayed offsets until the recording ends,
+// the duration elapses when given, or ctx is cancelled. Like an open-loop run, -users workers
+// take the next due request when free, and latency is also measured from each request's
+// intended start. A replayed request fails when its status class differs from the recorded one.
+func runReplay(ctx context.Context, cfg loadConfig, client *apiClient, replay *loadtest.Replay) ([]*workerResult, openLoopStats) {
+	runCtx, cancel := context.WithCancel(ctx)
+	if cfg.durationSet {
+		runCtx, cancel = context.WithTimeout(ctx, cfg.duration)
+	}
+	defer cancel()
+
+	queue := make(chan loadtest.ReplayArrival, cfg.users)
+	var sent int64
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		sent = replay.Run(runCtx, queue)
+	}()
+
+	results := make([]*workerResult, cfg.users)
+	var wg sync.WaitGroup
+	for i := range results {
+		results[i] = &workerResult{
+			latencies:  loadtest.NewHistogram(),
+			corrected:  loadtest.NewHistogram(),
+			errorKinds: make(map[string]int64),
+		}
+		wg.Add(1)
+		go func(result *workerResult) {
+			defer wg.Done()
+			for arrival := range queue {
+				recorded := arrival.Request
+				result.serveArrival(runCtx, arrival.Intended, func(ctx context.Context) (func(context.Context) error, error) {
+					return nil, client.Replay(ctx, recorded)
+				})
+			}
+		}(results[i])
+	}
+	wg.Wait()
+	<-done
+
+	return results, openLoopStats{sent: sent, missed: replay.Missed(), overdue: replay.Overdue()}
+}
+
+// serveArrival sends the request of an arrival intended to start at intended and records its
+// latency, measured from when it was sent and from intended
+func (result *workerResult) serveArrival(runCtx context.Context, intended time.Time, req request) {
+	// Arrivals left when the run ends are not sent but still waited this long
+	if runCtx.Err() != nil {
+		result.corrected.Record(time.Since(intended))
+		return
+	}
+
+	started := time.Now()
+	cleanup, err := req(runCtx)
+	finished := time.Now()
+	if runCtx.Err() != nil {
+		result.corrected.Record(finished.Sub(intended))
+		return
+	}
+
+	result.latencies.Record(finished.Sub(started))
+	result.corrected.Record(finished.Sub(intended))
+	if err != nil {
+		result.errors++
+		result.errorKinds[errorKind(err)]++
+	}
+	if cleanup != nil {
+		if err := cleanup(runCtx); err != nil && runCtx.Err() == nil {
+			result.errorKinds["cleanup: "+errorKind(err)]++
+		}
+	}
+}
+
+// run drives every user until the duration elapses or ctx is cancelled
+func run(ctx context.Context, cfg loadConfig, requests []request) []*workerResult {
+	runCtx, cancel := context.WithTimeout(ctx, cfg.duration)
+	defer cancel()
+
+	results := make([]*workerResult, len(requests))
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		results[i] = &workerResult{latencies: loadtest.NewHistogram(), errorKinds: make(map[string]int64)}
+		wg.Add(1)
+		go func(result *workerResult, req request) {
+			defer wg.Done()
+			for runCtx.Err() == nil {
+				started := time.Now()
+				cleanup, err := req(runCtx)
+				elapsed := time.Since(started)
+				// Requests cut off by the end of the run are neither successes nor failures
+				if runCtx.Err() != nil {
+					return
+				}
+
+				result.latencies.Record(elapsed)
+				if err != nil {
+					result.errors++
+					result.errorKinds[errorKind(err)]++
+				}
+				if cleanup != nil {
+					if err := cleanup(runCtx); err != nil && runCtx.Err() == nil {
+						result.errorKinds["cleanup: "+errorKind(err)]++
+					}
+				}
+
+				select {
+				case <-runCtx.Done():
+					return
+				case <-time.After(cfg.thinkTime):
+				}
+			}
+		}(results[i], req)
+	}
+	wg.Wait()
+	return results
+}
+
+// errorKind groups failures by status code or transport error for the report
+func errorKind(err error) string {
+	var status *statusError
+	if errors.As(err, &status) {
+		return fmt.Sprintf("HTTP %d", status.StatusCode)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	return "transport error"
+}
+
+// summarize merges the users' results into the run's report and checks the SLA. open is set
+// for open-loop runs, whose latency limits are checked against the corrected latencies.
+func summarize(cfg loadConfig, results []*workerResult, open *openLoopStats, elapsed time.Duration) *runResult {
+	latencies := loadtest.NewHistogram()
+	result := &runResult{
+		Mode:       cfg.mode,
+		LoadModel:  "closed",
+		BaseURL:    cfg.baseURL,
+		Endpoint:   cfg.endpointLabel(),
+		Users:      cfg.users,
+		Duration:   elapsed.Round(time.Millisecond).String(),
+		ErrorKinds: make(map[string]int64),
+	}
+	if cfg.scenario != nil {
+		result.Scenario = cfg.scenarioPath
+	}
+	for _, r := range results {
+		latencies.Merge(r.latencies)
+		result.Errors += r.errors
+		for kind, n := range r.errorKinds {
+			result.ErrorKinds[kind] += n
+		}
+	}
+
+	result.Requests = latencies.Count()
+	if result.Requests > 0 {
+		result.ErrorRate = float64(result.Errors) / float64(result.Requests)
+	}
+	result.Throughput = float64(result.Requests) / elapsed.Seconds()
+	result.Latency = summarizeLatency(latencies)
+	result.Histogram = latencies.Buckets()
+
+	if open == nil {
+		result.Violations = cfg.sla.Check(latencies, result.ErrorRate)
+		return result
+	}
+
+	corrected := loadtest.NewHistogram()
+	for _, r := range results {
+		corrected.Merge(r.corrected)
+	}
+	corrected.Merge(open.overdue)
+	result.LoadModel = "open"
+	result.TargetRate = cfg.rate
+	result.Phases = cfg.phases
+	result.Corrected = &correctedLatency{
+		latencySummary: summarizeLatency(corrected),
+		Histogram:      corrected.Buckets(),
+		MissedTicks:    open.missed,
+		Unfinished:     corrected.Count() - result.Requests,
+	}
+	result.Violations = cfg.sla.Check(corrected, result.ErrorRate)
+	return result
+}
+
+// summarizeLatency returns the percentiles of a latency histogram
+func summarizeLatency(h *loadtest.Histogram) latencySummary {
+	return latencySummary{
+		Min:  milliseconds(h.Min()),
+		Mean: milliseconds(h.Mean()),
+		P50:  milliseconds(h.Quantile(0.50)),
+		P90:  milliseconds(h.Quantile(0.90)),
+		P95:  milliseconds(h.Quantile(0.95)),
+		P99:  milliseconds(h.Quantile(0.99)),
+		P999: milliseconds(h.Quantile(0.999)),
+		Max:  milliseconds(h.Max()),
+	}
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// printResult prints the run's summary
+func printResult(r *runResult) {
+	fmt.Printf("Load test finished after %s\n", r.Duration)
+	fmt.Printf("  requests:    %d (%.1f/s)\n", r.Requests, r.Throughput)
+	fmt.Printf("  errors:      %d (%s)\n", r.Errors, loadtest.FormatPercent(r.ErrorRate))
+	for kind, n := range r.ErrorKinds {
+		fmt.Printf("    %-20s %d\n", kind+":", n)
+	}
+	printLatency("latency", r.Latency)
+	if r.Corrected != nil && r.Speed > 0 {
+		fmt.Printf("  replay speed: %gx, missed ticks: %d, unfinished: %d\n", r.Speed, r.Corrected.MissedTicks, r.Corrected.Unfinished)
+		printLatency("corrected latency", r.Corrected.latencySummary)
+	} else if r.Corrected != nil && len(r.Phases) > 0 {
+		fmt.Printf("  scenario: %s (%d phases), missed ticks: %d, unfinished: %d\n", r.Scenario, len(r.Phases), r.Corrected.MissedTicks, r.Corrected.Unfinished)
+		printLatency("corrected latency", r.Corrected.latencySummary)
+	} else if r.Corrected != nil {
+		fmt.Printf("  target rate: %g/s, missed ticks: %d, unfinished: %d\n", r.TargetRate, r.Corrected.MissedTicks, r.Corrected.Unfinished)
+		printLatency("corrected latency", r.Corrected.latencySummary)
+	}
+}
+
+// printLatency prints a latency summary
+func printLatency(label string, l latencySummary) {
+	fmt.Printf("  %s min: %.2fms mean: %.2fms max: %.2fms\n", label, l.Min, l.Mean, l.Max)
+	fmt.Printf("    p50: %.2fms p90: %.2fms p95: %.2fms p99: %.2fms p99.9: %.2fms\n", l.P50, l.P90, l.P95, l.P99, l.P999)
+}
+
+// writeResult writes the run's report as JSON
+func writeResult(path string, r *runResult) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
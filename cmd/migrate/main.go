@@ -4,6 +4,8 @@ import (
 	"actor-model-observability/internal/config"
 	"actor-model-observability/internal/database"
 	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -15,8 +17,10 @@ import (
 
 func main() {
 	var (
-		command = flag.String("command", "up", "Migration command: up, down, status")
+		command = flag.String("command", "up", "Migration command: up, down, status, validate")
 		steps   = flag.Int("steps", 0, "Number of migration steps (0 = all)")
+		dryRun  = flag.Bool("dry-run", false, "Print the SQL that would be executed without executing it")
+		force   = flag.Bool("force", false, "Allow down migrations that drop data in release mode")
 	)
 	flag.Parse()
 
@@ -47,10 +51,31 @@ func main() {
 	// Execute command
 	switch *command {
 	case "up":
+		if *dryRun {
+			if err := printPlan(db, migrations, migrate.Up, *steps); err != nil {
+				log.Fatalf("Migration dry run failed: %v", err)
+			}
+			return
+		}
 		if err := migrateUp(db, migrations, *steps); err != nil {
 			log.Fatalf("Migration up failed: %v", err)
 		}
+		if err := validateAfterUp(db, migrations); err != nil {
+			log.Fatalf("Schema validation failed: %v", err)
+		}
 	case "down":
+		if *steps == 0 {
+			*steps = 1 // Default to rolling back 1 migration
+		}
+		if *dryRun {
+			if err := printPlan(db, migrations, migrate.Down, *steps); err != nil {
+				log.Fatalf("Migration dry run failed: %v", err)
+			}
+			return
+		}
+		if err := checkDestructive(db, migrations, *steps, cfg.Server.Mode == "release", *force); err != nil {
+			log.Fatalf("Migration down refused: %v", err)
+		}
 		if err := migrateDown(db, migrations, *steps); err != nil {
 			log.Fatalf("Migration down failed: %v", err)
 		}
@@ -58,6 +83,10 @@ func main() {
 		if err := migrationStatus(db, migrations); err != nil {
 			log.Fatalf("Migration status failed: %v", err)
 		}
+	case "validate":
+		if err := validateSchema(db); err != nil {
+			log.Fatalf("Schema validation failed: %v", err)
+		}
 	default:
 		log.Fatalf("Unknown command: %s", *command)
 	}
@@ -87,10 +116,6 @@ func migrateUp(db *database.PostgresDB, migrations *migrate.FileMigrationSource,
 }
 
 func migrateDown(db *database.PostgresDB, migrations *migrate.FileMigrationSource, steps int) error {
-	if steps == 0 {
-		steps = 1 // Default to rolling back 1 migration
-	}
-
 	n, err := migrate.ExecMax(db.DB.DB, "postgres", migrations, migrate.Down, steps)
 	if err != nil {
 		return fmt.Errorf("failed to rollback migrations: %w", err)
@@ -139,6 +164,100 @@ func migrationStatus(db *database.PostgresDB, migrations *migrate.FileMigrationS
 	return nil
 }
 
+// printPlan prints the SQL of the migrations that would be executed, marking the statements that
+// drop data
+func printPlan(db *database.PostgresDB, migrations *migrate.FileMigrationSource, dir migrate.MigrationDirection, steps int) error {
+	planned, _, err := migrate.PlanMigration(db.DB.DB, "postgres", migrations, dir, steps)
+	if err != nil {
+		return fmt.Errorf("failed to plan migrations: %w", err)
+	}
+
+	if len(planned) == 0 {
+		log.Println("No migrations to execute")
+		return nil
+	}
+
+	destructive := 0
+	for _, migration := range planned {
+		fmt.Printf("-- Migration %s\n", migration.Id)
+		if migration.DisableTransaction {
+			fmt.Println("-- (executed without a transaction)")
+		}
+		for _, query := range migration.Queries {
+			if len(database.DestructiveStatements([]string{query})) > 0 {
+				fmt.Println("-- DESTRUCTIVE: drops data")
+				destructive++
+			}
+			fmt.Println(strings.TrimSpace(query))
+		}
+		fmt.Println()
+	}
+
+	log.Printf("Dry run: %d migrations would be executed, nothing was changed", len(planned))
+	if destructive > 0 && dir == migrate.Down {
+		log.Printf("%d statements drop data; in release mode this needs -force", destructive)
+	}
+	return nil
+}
+
+// checkDestructive refuses down migrations that drop data in release mode unless forced
+func checkDestructive(db *database.PostgresDB, migrations *migrate.FileMigrationSource, steps int, release, force bool) error {
+	planned, _, err := migrate.PlanMigration(db.DB.DB, "postgres", migrations, migrate.Down, steps)
+	if err != nil {
+		return fmt.Errorf("failed to plan migrations: %w", err)
+	}
+
+	var found []string
+	for _, migration := range planned {
+		for _, statement := range database.DestructiveStatements(migration.Queries) {
+			found = append(found, fmt.Sprintf("%s: %s", migration.Id, statement))
+		}
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	for _, statement := range found {
+		log.Printf("Destructive statement in %s", statement)
+	}
+	if release && !force {
+		return fmt.Errorf("%d statements drop data in release mode; review them with -dry-run and pass -force to execute", len(found))
+	}
+	return nil
+}
+
+// validateAfterUp validates the schema once no migrations are pending
+func validateAfterUp(db *database.PostgresDB, migrations *migrate.FileMigrationSource) error {
+	pending, _, err := migrate.PlanMigration(db.DB.DB, "postgres", migrations, migrate.Up, 0)
+	if err != nil {
+		return fmt.Errorf("failed to plan migrations: %w", err)
+	}
+	if len(pending) > 0 {
+		log.Printf("Skipping schema validation, %d migrations still pending", len(pending))
+		return nil
+	}
+	return validateSchema(db)
+}
+
+// validateSchema checks the schema against the columns the models map
+func validateSchema(db *database.PostgresDB) error {
+	tables := models.MappedTables()
+	mismatches, err := database.ValidateSchema(context.Background(), db.DB, tables)
+	if err != nil {
+		return err
+	}
+
+	if len(mismatches) > 0 {
+		for _, mismatch := range mismatches {
+			log.Printf("Schema mismatch: %s", mismatch)
+		}
+		return fmt.Errorf("%d tables or columns mapped by models are missing", len(mismatches))
+	}
+
+	log.Printf("Schema matches the %d mapped models", len(tables))
+	return nil
+}
+
 // Helper function to check if migrations directory exists
 func init() {
 	if _, err := os.Stat("migrations"); os.IsNotExist(err) {
@@ -45,18 +45,37 @@ import (
 	"time"
 
 	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/appversion"
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/chat"
 	"actor-model-observability/internal/config"
 	"actor-model-observability/internal/database"
+	"actor-model-observability/internal/experiment"
+	"actor-model-observability/internal/hlc"
+	"actor-model-observability/internal/httpclient"
+	"actor-model-observability/internal/leader"
+	"actor-model-observability/internal/lock"
 	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/middleware"
 	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/notification"
 	"actor-model-observability/internal/observability"
+	"actor-model-observability/internal/payload"
+	"actor-model-observability/internal/redaction"
+	"actor-model-observability/internal/repository/factory"
 	"actor-model-observability/internal/repository/postgres"
+	"actor-model-observability/internal/repository/traced"
+	"actor-model-observability/internal/retention"
 	"actor-model-observability/internal/router"
 	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/storage"
 	"actor-model-observability/internal/traditional"
+	"actor-model-observability/internal/tripcache"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
 )
 
 func main() {
@@ -77,48 +96,171 @@ func main() {
 		"mode":    cfg.Server.Mode,
 	}).Info("Starting Actor Model Observability Application")
 
-	// Initialize database
-	db, err := database.NewPostgresConnection(&cfg.Database, logger)
-	if err != nil {
-		logger.WithFields(logging.Fields{
-			"host": cfg.Database.Host,
-			"port": cfg.Database.Port,
-			"name": cfg.Database.DBName,
-		}).WithError(err).Fatal("Failed to connect to database")
+	// Dump effective configuration with secrets masked
+	logger.WithFields(logging.Fields{
+		"config":           cfg.Redacted(),
+		"secrets_provider": cfg.Secrets.Provider,
+	}).Debug("Configuration loaded")
+
+	// Initialize database (the memory driver runs without one)
+	var db *database.PostgresDB
+	var dbx *sqlx.DB
+	if cfg.Database.Driver != "memory" {
+		db, err = database.NewConnection(&cfg.Database, logger)
+		if err != nil {
+			logger.WithFields(logging.Fields{
+				"driver": cfg.Database.Driver,
+				"host":   cfg.Database.Host,
+				"port":   cfg.Database.Port,
+				"name":   cfg.Database.DBName,
+			}).WithError(err).Fatal("Failed to connect to database")
+		}
+
+		// Test database connection
+		if err := db.HealthCheck(context.Background()); err != nil {
+			logger.WithError(err).Fatal("Database health check failed")
+		}
+		dbx = db.DB
+		logger.Info("Database connection established successfully")
+	} else {
+		logger.Warn("Using in-memory repositories, data will not be persisted")
 	}
 
-	// Test database connection
-	if err := db.HealthCheck(context.Background()); err != nil {
-		logger.WithError(err).Fatal("Database health check failed")
+	// Initialize Redis (optional for local development)
+	var redisClient *database.RedisClient
+	var redisCache *redis.Client
+	if cfg.Redis.Enabled {
+		redisClient, err = database.NewRedisConnection(&cfg.Redis, logger)
+		if err != nil {
+			logger.WithFields(logging.Fields{
+				"host": cfg.Redis.Host,
+				"port": cfg.Redis.Port,
+				"db":   cfg.Redis.DB,
+			}).WithError(err).Fatal("Failed to connect to Redis")
+		}
+
+		// Test Redis connection
+		if err := redisClient.HealthCheck(context.Background()); err != nil {
+			logger.WithError(err).Fatal("Redis health check failed")
+		}
+		redisCache = redisClient.Client
+		logger.Info("Redis connection established successfully")
+	} else {
+		logger.Info("Redis disabled, running without cache")
 	}
-	logger.Info("Database connection established successfully")
 
-	// Initialize Redis
-	redisClient, err := database.NewRedisConnection(&cfg.Redis, logger)
-	if err != nil {
-		logger.WithFields(logging.Fields{
-			"host": cfg.Redis.Host,
-			"port": cfg.Redis.Port,
-			"db":   cfg.Redis.DB,
-		}).WithError(err).Fatal("Failed to connect to Redis")
+	// Detect drift between the schema and the models, logged as warnings without delaying startup
+	var schemaChecker *database.SchemaDriftChecker
+	if cfg.Database.Driver == "postgres" {
+		schemaChecker = database.NewSchemaDriftChecker(dbx, models.MappedTables(), cfg.Database.MigrationsDir, logger)
+		if cfg.Database.SchemaCheckOnStartup {
+			go func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+				defer cancel()
+				if _, err := schemaChecker.Check(ctx); err != nil {
+					logger.WithError(err).Warn("Schema drift check failed")
+				}
+			}()
+		}
+	}
+
+	// Route read-heavy observability queries to the read replica when configured
+	var replicaRouter *database.ReplicaRouter
+	var reader postgres.DBReader
+	if dbx != nil {
+		replicaRouter = database.NewReplicaRouter(dbx, &cfg.Database, logger)
+		replicaRouter.Start(context.Background())
+		reader = replicaRouter
 	}
 
-	// Test Redis connection
-	if err := redisClient.HealthCheck(context.Background()); err != nil {
-		logger.WithError(err).Fatal("Redis health check failed")
+	// Initialize OpenTelemetry monitor
+	otelMonitor, err := observability.NewOTelMonitor(&cfg.OpenTelemetry, logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize OpenTelemetry monitor")
 	}
-	logger.Info("Redis connection established successfully")
 
 	// Initialize repositories
-	userRepo := postgres.NewUserRepository(db.DB)
-	driverRepo := postgres.NewDriverRepository(db.DB)
-	passengerRepo := postgres.NewPassengerRepository(db.DB)
-	tripRepo := postgres.NewTripRepository(db.DB)
-	observabilityRepo := postgres.NewObservabilityRepository(db.DB)
-	traditionalRepo := postgres.NewTraditionalRepository(db.DB)
+	repos, err := factory.NewRepositories(&cfg.Database, dbx, reader)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize repositories")
+	}
+
+	// Isolate the observability records of experiment runs in their own schemas
+	experimentTarget := experiment.NewTarget()
+	var experiments *experiment.Manager
+	if cfg.Database.Driver == "postgres" {
+		experiments = experiment.NewManager(dbx, experimentTarget, cfg.Experiment.SchemaPrefix, logger)
+		if repo, ok := repos.Observability.(*postgres.ObservabilityRepositoryImpl); ok {
+			repo.SetTarget(experimentTarget)
+		}
+		if cfg.Experiment.Active != "" {
+			if _, err := experiments.Activate(context.Background(), cfg.Experiment.Active); err != nil {
+				logger.WithError(err).Fatal("Failed to activate experiment")
+			}
+		}
+	}
+
+	// Trace repository calls and log the slow ones
+	var slowQueryLog *observability.SlowQueryLog
+	if cfg.Observability.SlowQueryThreshold > 0 {
+		slowQueryLog = observability.NewSlowQueryLog(cfg.Observability.SlowQueryThreshold, cfg.Observability.SlowQueryTopN, logger)
+		if redisCache != nil {
+			slowQueryLog.SetRedis(redisCache)
+		}
+	}
+	tracer, meter := otelMonitor.Tracer(), otelMonitor.Meter()
+	if !cfg.OpenTelemetry.RepositoryTracing {
+		tracer, meter = nil, nil
+	}
+	if tracer != nil || meter != nil || slowQueryLog != nil {
+		instrumentation, err := traced.New(tracer, meter, cfg.Database.Driver)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize repository instrumentation")
+		}
+		if slowQueryLog != nil {
+			instrumentation.SetSlowQueryRecorder(slowQueryLog)
+		}
+		repos = traced.Wrap(repos, instrumentation)
+	}
+
+	// Cache active trips in Redis for the ride status polls, written through on every trip change
+	var tripCache *tripcache.Cache
+	if redisCache != nil && cfg.TripCache.TTL > 0 {
+		tripCache, err = tripcache.New(redisCache, cfg.TripCache, otelMonitor.Meter(), logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize trip status cache")
+		}
+		repos.Trips = tripCache.Wrap(repos.Trips)
+	}
+
+	userRepo := repos.Users
+	driverRepo := repos.Drivers
+	passengerRepo := repos.Passengers
+	tripRepo := repos.Trips
+	savedLocationRepo := repos.SavedLocations
+	webhookRepo := repos.Webhooks
+	sessionRepo := repos.Sessions
+	fareDisputeRepo := repos.FareDisputes
+	incentiveRepo := repos.Incentives
+	fleetRepo := repos.Fleets
+	corporateRepo := repos.Corporate
+	invoiceRepo := repos.Invoices
+	chatRepo := repos.Chat
+	incidentRepo := repos.Incidents
+	dashboardRepo := repos.Dashboard
+	observabilityRepo := repos.Observability
+	traditionalRepo := repos.Traditional
+
+	// Initialize the event bus observability records are published to
+	eventBus := bus.New(cfg.Observability.EventBusBufferSize, logger)
+
+	// Order the published records and actor messages by hybrid timestamps
+	hybridClock := newHybridClock(cfg.Observability, logger)
+	eventBus.SetClock(hybridClock)
 
 	// Initialize actor system
 	actorSystem := actor.NewActorSystem("main-system")
+	actorSystem.SetHybridClock(hybridClock)
 
 	// Set up actor system event handlers for observability
 	actorSystem.SetEventHandlers(
@@ -131,24 +273,19 @@ func main() {
 				return
 			}
 
-			// Create actor instance record
+			// Create actor instance record, linked to the business entity the actor represents
 			actorInstance := &models.ActorInstance{
 				ID:            uuid.New(),
 				ActorType:     models.ActorType(actorRef.Type),
 				ActorID:       actorID,
-				EntityID:      nil, // Will be set when actor processes specific entities
 				Status:        models.ActorStatusActive,
 				LastHeartbeat: time.Now(),
 				CreatedAt:     time.Now(),
 				UpdatedAt:     time.Now(),
 			}
+			actorInstance.EntityType, actorInstance.EntityID = models.EntityLink(actorRef.EntityType, actorRef.EntityID)
 
-			if err := observabilityRepo.CreateActorInstance(context.Background(), actorInstance); err != nil {
-				logger.WithError(err).WithFields(logging.Fields{
-					"actor_id":   actorID,
-					"actor_type": actorRef.Type,
-				}).Error("Failed to create actor instance record")
-			}
+			eventBus.Publish(bus.TopicActorInstance, actorInstance)
 
 			// Create event log for actor started
 			eventData, _ := json.Marshal(map[string]interface{}{
@@ -168,9 +305,7 @@ func main() {
 				Timestamp:     time.Now(),
 				CreatedAt:     time.Now(),
 			}
-			if err := observabilityRepo.CreateEventLog(context.Background(), eventLog); err != nil {
-				logger.WithError(err).Error("Failed to create actor started event log")
-			}
+			eventBus.Publish(bus.TopicEventLog, eventLog)
 		},
 		// onActorStopped
 		func(actorID string) {
@@ -190,9 +325,7 @@ func main() {
 				Timestamp:     time.Now(),
 				CreatedAt:     time.Now(),
 			}
-			if err := observabilityRepo.CreateEventLog(context.Background(), eventLog); err != nil {
-				logger.WithError(err).Error("Failed to create actor stopped event log")
-			}
+			eventBus.Publish(bus.TopicEventLog, eventLog)
 			logger.WithField("actor_id", actorID).Debug("Actor stopped - observability tracking")
 		},
 		// onActorFailed
@@ -214,9 +347,7 @@ func main() {
 				Timestamp:     time.Now(),
 				CreatedAt:     time.Now(),
 			}
-			if createErr := observabilityRepo.CreateEventLog(context.Background(), eventLog); createErr != nil {
-				logger.WithError(createErr).Error("Failed to create actor failed event log")
-			}
+			eventBus.Publish(bus.TopicEventLog, eventLog)
 			logger.WithError(err).WithField("actor_id", actorID).Error("Actor failed - observability tracking")
 		},
 		// onMessage
@@ -238,9 +369,7 @@ func main() {
 				Timestamp:     time.Now(),
 				CreatedAt:     time.Now(),
 			}
-			if err := observabilityRepo.CreateEventLog(context.Background(), eventLog); err != nil {
-				logger.WithError(err).Error("Failed to create message sent event log")
-			}
+			eventBus.Publish(bus.TopicEventLog, eventLog)
 			logger.WithFields(logging.Fields{
 				"from":         from,
 				"to":           to,
@@ -249,17 +378,78 @@ func main() {
 		},
 	)
 
-	// Initialize OpenTelemetry monitor
-	otelMonitor, err := observability.NewOTelMonitor(&cfg.OpenTelemetry, logger)
-	if err != nil {
-		logger.WithError(err).Fatal("Failed to initialize OpenTelemetry monitor")
+	// Retry failed messages before dead-lettering them, recording every failed attempt
+	retryPolicy := actorRetryPolicy(cfg.Actor.Retry, cfg.Actor.Retry.MaxAttempts)
+	actorSystem.SetDefaultRetryPolicy(retryPolicy)
+	for messageType, attempts := range cfg.Actor.Retry.MaxAttemptsByType {
+		actorSystem.SetRetryPolicy(messageType, actorRetryPolicy(cfg.Actor.Retry, attempts))
 	}
+	for actorType, max := range cfg.Actor.MaxActorsByType {
+		actorSystem.SetActorLimit(actorType, max)
+	}
+	actorSystem.SetGoroutineLeakGrace(cfg.Actor.GoroutineLeakGrace)
+	actorSystem.SetDefaultIdleTimeout(cfg.Actor.IdleTimeout)
+	for actorType, timeout := range cfg.Actor.IdleTimeoutsByType {
+		actorSystem.SetIdleTimeout(actorType, timeout)
+	}
+	actorSystem.SetMessageFailureHandler(func(failure actor.MessageFailure) {
+		message := actorMessageRecord(failure.ActorID, failure.ActorType, failure.Message, failure.Effects)
+		message.Status = models.MessageStatusFailed
+		if failure.DeadLettered {
+			message.Status = models.MessageStatusDeadLettered
+		}
+		errorMessage := failure.Err.Error()
+		message.ErrorMessage = &errorMessage
+		message.RetryCount = failure.Retries()
+		eventBus.Publish(bus.TopicActorMessage, message)
+	})
+	// Record the entities changed by processed messages
+	actorSystem.SetMessageProcessedHandler(func(result actor.MessageResult) {
+		otelMonitor.RecordMessageProcessing(context.Background(), result.ActorType, result.Message.GetType(), result.Duration)
+		if len(result.Effects) == 0 {
+			return
+		}
+		message := actorMessageRecord(result.ActorID, result.ActorType, result.Message, result.Effects)
+		message.MarkProcessed(result.Duration)
+		eventBus.Publish(bus.TopicActorMessage, message)
+	})
 
 	// Initialize observability collector
-	metricsCollector := observability.NewMetricsCollector(db, redisClient.Client, cfg, logger)
+	metricsCollector := observability.NewMetricsCollector(db, redisCache, cfg, logger)
+	metricsCollector.SetEventBus(eventBus)
+	if redisCache != nil && cfg.Redis.RealtimeStreams {
+		metricsCollector.SetRealtimeStreams(observability.NewRealtimeStreams(redisCache, cfg.Redis.StreamMaxLen))
+		logger.WithField("max_len", cfg.Redis.StreamMaxLen).Info("Publishing real-time data to Redis Streams")
+	}
+
+	// Bound the size of persisted message payloads and event data
+	payloadGuard := payload.NewGuard(cfg.Payload)
+	metricsCollector.SetPayloadGuard(payloadGuard)
+	metricsCollector.SetExperimentTarget(experimentTarget)
+	if err := otelMonitor.RegisterPayloadCounters(payloadGuard); err != nil {
+		logger.WithError(err).Fatal("Failed to register payload metrics")
+	}
+	if err := otelMonitor.RegisterCollectorMetrics(metricsCollector); err != nil {
+		logger.WithError(err).Fatal("Failed to register metrics collector metrics")
+	}
+
+	// Persist, stream and evaluate the records published on the event bus
+	persister := observability.NewPersister(observabilityRepo, logger)
+	persister.SetPayloadGuard(payloadGuard)
+	eventBus.Subscribe("persistence", persister.HandleMessage, persister.Topics()...)
+	eventStream := observability.NewEventStream()
+	eventBus.Subscribe("event_stream", eventStream.HandleMessage, bus.TopicEventLog)
+	if cfg.Observability.AlertErrorThreshold > 0 {
+		alerts := observability.NewAlertEvaluator(cfg.Observability.AlertErrorThreshold, cfg.Observability.AlertWindow, eventBus, logger)
+		eventBus.Subscribe("alerts", alerts.HandleMessage, bus.TopicEventLog)
+	}
 
 	// Initialize traditional monitoring
 	traditionalMonitor := traditional.NewTraditionalMonitor(logger, otelMonitor)
+	sloTracker := observability.NewSLOTracker(cfg.SLO)
+	if err := traditionalMonitor.SetSLOTracker(sloTracker); err != nil {
+		logger.WithError(err).Fatal("Failed to register SLO metrics")
+	}
 
 	// Initialize services
 	rideService := service.NewRideService(
@@ -274,6 +464,305 @@ func main() {
 		true, // useActorModel
 	)
 
+	// Reject ride requests outside the service area or operating hours
+	serviceArea, err := service.NewServiceAreaPolicy(cfg.ServiceArea, traditionalMonitor)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize service area")
+	}
+	rideService.SetServiceArea(serviceArea)
+	// Count the demand for rides requiring special-assistance vehicles and their nearby supply by zone
+	rideService.SetAccessibilityZones(cfg.Accessibility.ZonePrecision)
+	// Match trips again, a limited number of times, when their driver cancels before pickup
+	rideService.SetRematch(cfg.Rematch)
+	// Quote and charge passengers cancelling rides under the cancellation policy
+	cancellationPolicy, err := service.NewCancellationPolicy(cfg.Cancellation)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize cancellation policy")
+	}
+	rideService.SetCancellationPolicy(cancellationPolicy)
+	// Match ride requests in traditional mode on a bounded worker pool
+	traditionalMatcher := service.NewTraditionalMatcher(cfg.Matching, driverRepo, traditionalMonitor, logger)
+	rideService.SetTraditionalMatcher(traditionalMatcher)
+	// Buffer driver location updates, keeping each driver's latest, and match on the freshest
+	var locationIngester *service.LocationIngester
+	if cfg.Location.Enabled {
+		locationIngester = service.NewLocationIngester(cfg.Location, driverRepo, redisCache, traditionalMonitor, logger)
+		rideService.SetLocationIngester(locationIngester)
+	}
+	// Serve ride status polls from the trip cache
+	if tripCache != nil {
+		rideService.SetTripCache(tripCache)
+	}
+
+	// Redis locks coordinating work across instances, taken on the lock nodes or else the Redis cache
+	var locker *lock.Locker
+	if redisCache != nil || len(cfg.Lock.Addresses) > 0 {
+		lockNodes := make([]redis.Cmdable, 0, len(cfg.Lock.Addresses))
+		for _, addr := range cfg.Lock.Addresses {
+			lockNodes = append(lockNodes, redis.NewClient(&redis.Options{
+				Addr:         addr,
+				Password:     cfg.Redis.Password,
+				DialTimeout:  cfg.Redis.DialTimeout,
+				ReadTimeout:  cfg.Redis.ReadTimeout,
+				WriteTimeout: cfg.Redis.WriteTimeout,
+			}))
+		}
+		if len(lockNodes) == 0 {
+			lockNodes = append(lockNodes, redisCache)
+		}
+		locker, err = lock.NewLocker(lockNodes, cfg.Lock, otelMonitor.Meter(), logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize distributed locks")
+		}
+		logger.WithField("nodes", len(lockNodes)).Info("Distributed locks enabled")
+	}
+
+	// Publish trip lifecycle transitions to registered webhooks and count completed trips
+	// towards driver incentive campaigns
+	webhookDispatcher := service.NewWebhookDispatcher(webhookRepo, cfg.Webhook, logger)
+	httpInstrumentation, err := httpclient.NewInstrumentation(otelMonitor.Tracer(), otelMonitor.Meter())
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize HTTP client instrumentation")
+	}
+	webhookHTTP := cfg.HTTPClient
+	webhookHTTP.Timeout = cfg.Webhook.Timeout
+	webhookDispatcher.SetHTTPClient(httpclient.New("webhook", webhookHTTP, httpInstrumentation, logger))
+	incentiveService := service.NewIncentiveService(incentiveRepo, driverRepo, eventBus, logger)
+	tripEventPublishers := service.TripEventPublishers{webhookDispatcher, incentiveService}
+
+	// Email receipts, password resets and weekly earnings summaries, when an SMTP server is configured
+	var emailNotifier *service.EmailNotifier
+	if cfg.Email.Enabled {
+		smtpSender, err := notification.NewSMTPSender(cfg.Email)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize SMTP sender")
+		}
+		emailNotifier, err = service.NewEmailNotifier(smtpSender, userRepo, passengerRepo, driverRepo, traditionalMonitor, cfg.Email, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize email notifier")
+		}
+		tripEventPublishers = append(tripEventPublishers, emailNotifier)
+	}
+	rideService.SetEventPublisher(tripEventPublishers)
+
+	// Profile pictures, stored on the local disk or in an S3-compatible object store
+	fileStore, err := storage.New(cfg.Storage, httpclient.New("storage", cfg.HTTPClient, httpInstrumentation, logger))
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize file storage")
+	}
+	avatarService := service.NewAvatarService(userRepo, fileStore, cfg.Storage, logger)
+
+	// Corporate accounts billed for the rides of their passengers within monthly spending limits
+	corporateService := service.NewCorporateAccountService(corporateRepo, passengerRepo, logger)
+	rideService.SetCorporateBilling(corporateService)
+
+	// Record every trip state change in both the actor and traditional pipelines, labelled by mode
+	rideService.SetTripEventEmitter(service.NewTripEventEmitter(metricsCollector, traditionalRepo, traditionalMonitor, cfg.OpenTelemetry.ServiceName, logger))
+
+	// Push trip status transitions and driver ETAs to passengers streaming their ride's status
+	rideService.SetStatusPublisher(eventBus)
+	tripStatusStream := service.NewTripStatusStream(tripRepo, driverRepo)
+	eventBus.Subscribe("trip_status_stream", tripStatusStream.HandleMessage, tripStatusStream.Topics()...)
+
+	// Measure the ETAs given at matching time against the drivers' arrivals, adjusting the ETA
+	// model's average speed when configured
+	etaModel := service.NewETAModel(cfg.ETA.AverageSpeedKmh)
+	rideService.SetETAModel(etaModel)
+	tripStatusStream.SetETAModel(etaModel)
+
+	// Follow the changes to drivers and trips made outside the API, by manual SQL fixes or other
+	// services: cached driver locations and trips are dropped and ride status streams updated
+	var changeListener *database.ChangeListener
+	if cfg.Database.Driver == "postgres" && cfg.Database.ChangeNotifications {
+		changeListener = database.NewChangeListener(&cfg.Database, logger)
+		changeListener.Handle("trips", func(ctx context.Context, change database.RowChange) {
+			if tripCache != nil {
+				tripCache.Invalidate(ctx, change.ID)
+			}
+			if change.Operation == database.RowDeleted {
+				return
+			}
+			if err := tripStatusStream.Refresh(ctx, change.ID); err != nil {
+				logger.WithError(err).WithField("trip_id", change.ID).Warn("Failed to refresh streamed trip")
+			}
+		})
+		changeListener.OnResync(func(ctx context.Context) {
+			if err := tripStatusStream.RefreshAll(ctx); err != nil {
+				logger.WithError(err).Warn("Failed to refresh streamed trips")
+			}
+		})
+		if locationIngester != nil {
+			changeListener.Handle("drivers", func(ctx context.Context, change database.RowChange) {
+				if driverID, err := uuid.Parse(change.ID); err == nil {
+					locationIngester.Invalidate(ctx, driverID)
+				}
+			})
+		}
+	}
+	etaAccuracyService := service.NewETAAccuracyService(repos.ETAPredictions, driverRepo, etaModel, cfg.ETA, cfg.Reporting, logger)
+	eventBus.Subscribe("eta_accuracy", etaAccuracyService.HandleMessage, etaAccuracyService.Topics()...)
+
+	// Keep the trail of the locations drivers report for support and fraud investigations
+	locationTrailService := service.NewLocationTrailService(repos.LocationTrail, driverRepo, cfg.LocationTrail, logger)
+	eventBus.Subscribe("location_trail", locationTrailService.HandleMessage, locationTrailService.Topics()...)
+
+	// Rules-based fraud detection on trips and driver locations, raising signals for admins to review
+	fraudService := service.NewFraudService(repos.FraudSignals, tripRepo, driverRepo, passengerRepo, sessionRepo, cfg.Fraud, eventBus, traditionalMonitor, logger)
+	eventBus.Subscribe("fraud_detection", fraudService.HandleMessage, fraudService.Topics()...)
+
+	// Trip payments ledger, reconciled against the fares of completed trips on the leader
+	paymentService := service.NewPaymentReconciliationService(repos.Payments, tripRepo, cfg.Payments, eventBus, traditionalMonitor, logger)
+
+	// Passenger fare disputes, with decisions sent to webhooks and audited through business event logs
+	fareDisputeService := service.NewFareDisputeService(fareDisputeRepo, tripRepo, webhookDispatcher, eventBus, logger)
+
+	// Ride demand forecasts from historical trips
+	forecastService := service.NewForecastService(tripRepo, cfg.Forecast, cfg.Reporting)
+	reportService := service.NewReportService(tripRepo, cfg.Reporting)
+	cancellationService := service.NewCancellationService(tripRepo)
+	heatmapService := service.NewHeatmapService(tripRepo, redisCache, cfg.Heatmap, logger)
+
+	// Anonymized trips dataset for research on the actor and traditional experiments
+	researchService, err := service.NewResearchExportService(tripRepo, cfg.Research)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize research export")
+	}
+	dashboardService := service.NewDashboardService(dashboardRepo, cfg.Dashboard, logger)
+
+	// Mailbox backlogs, pending ride requests and latency headroom for KEDA/HPA autoscaling
+	scalingSignalService := service.NewScalingSignalService(actorSystem, tripRepo, sloTracker)
+
+	// Observability rows and bytes per ride request, comparing the actor and traditional pipelines
+	storageCostService := service.NewStorageCostService(repos.StorageCost)
+
+	// Driver and passenger device sessions, audited through security event logs
+	sessionService := service.NewSessionService(sessionRepo, userRepo, cfg.Auth, eventBus, logger)
+
+	// Initialize runtime configuration reload
+	rateLimiter := middleware.NewRateLimiter(cfg.RateLimit.RequestsPerMinute, cfg.RateLimit.Burst)
+	configReloader := service.NewConfigReloader(cfg, eventBus, logger)
+	configReloader.OnReload(func(c *config.Config) {
+		logger.SetLevel(c.Logging.Level)
+		otelMonitor.SetSampleRate(c.OpenTelemetry.SampleRate)
+		metricsCollector.SetIntervals(c.Observability.MetricsInterval, c.Observability.MetricsInterval)
+		rateLimiter.SetLimit(c.RateLimit.RequestsPerMinute, c.RateLimit.Burst)
+	})
+
+	// Redact personal data from observability responses for callers without the operator role
+	var redactor *redaction.Redactor
+	if cfg.Redaction.Enabled {
+		redactor, err = redaction.New(cfg.Redaction.Rules, cfg.Redaction.HashKey)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize observability data redaction")
+		}
+	}
+
+	// Sampled request/response bodies for debugging, always redacted with the configured rules
+	bodyRedactor, err := redaction.New(cfg.Redaction.Rules, cfg.Redaction.HashKey)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize body log redaction")
+	}
+	bodyLogger := middleware.NewBodyLogger(cfg.BodyLogging, traditionalRepo, bodyRedactor, cfg.OpenTelemetry.ServiceName, logger)
+	configReloader.OnReload(func(c *config.Config) {
+		bodyLogger.SetConfig(c.BodyLogging)
+	})
+
+	// Request traces for load-test replays, redacted like the body logs
+	var trafficRecorder *middleware.TrafficRecorder
+	if cfg.Recording.Enabled {
+		trafficRecorder, err = middleware.NewTrafficRecorder(cfg.Recording, bodyRedactor, logger)
+		if err != nil {
+			logger.WithError(err).Fatal("Failed to initialize traffic recording")
+		}
+		logger.WithField("path", cfg.Recording.Path).Info("Recording traffic for load-test replays")
+	}
+
+	// Minimum app versions and progressively rolled out features of the driver and passenger apps
+	appVersionGate, err := appversion.NewGate(cfg.AppVersion, otelMonitor.Meter())
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize app version gating")
+	}
+	configReloader.OnReload(func(c *config.Config) {
+		if err := appVersionGate.SetConfig(c.AppVersion); err != nil {
+			logger.WithError(err).Error("Failed to apply reloaded app versions")
+		}
+	})
+
+	// Trip timelines merging trip events, actor messages, traces and traditional logs
+	timelineService := service.NewTimelineService(tripRepo, observabilityRepo, traditionalRepo, redactor)
+
+	// Fleet partner bulk driver status imports and CSV exports
+	fleetService := service.NewFleetService(fleetRepo, driverRepo, tripRepo, logger)
+
+	// Usage analytics and daily request quotas of fleet API keys
+	apiUsageService := service.NewAPIUsageService(repos.APIUsage, cfg.APIUsage, traditionalMonitor, logger)
+
+	// Monthly invoices of corporate accounts and fleet partners, kept in the file storage
+	invoiceService := service.NewInvoiceService(
+		invoiceRepo,
+		corporateRepo,
+		fleetRepo,
+		corporateService,
+		fleetService,
+		fileStore,
+		cfg.Invoice,
+		cfg.Storage.URLTTL,
+		logger,
+	)
+	if locker != nil {
+		invoiceService.SetLocker(locker)
+	}
+
+	// Daily Parquet archive of the system and traditional metrics, for offline analysis
+	var metricsArchiveService *service.MetricsArchiveService
+	if cfg.Archive.Enabled {
+		var archiveStore storage.Store = fileStore
+		if cfg.Archive.Sink == "local" {
+			archiveStore, err = storage.NewLocalStore(cfg.Archive.LocalDir, "")
+			if err != nil {
+				logger.WithError(err).Fatal("Failed to initialize metrics archive directory")
+			}
+		}
+		metricsArchiveService = service.NewMetricsArchiveService(observabilityRepo, traditionalRepo, archiveStore, cfg.Archive, logger)
+	}
+
+	// Per-hour checksums of the observability tables, compared between runs to detect lost rows.
+	// The SQL checksums rely on PostgreSQL's md5 and date_trunc, so sqlite databases are not checked
+	var integrityService *service.IntegrityService
+	if cfg.Integrity.Enabled && cfg.Database.Driver != "sqlite" {
+		integrityService = service.NewIntegrityService(repos.Integrity, cfg.Integrity, eventBus, traditionalMonitor, logger)
+	}
+
+	// In-trip chat between passengers and drivers, purged after the chat retention period
+	chatFilters, err := chat.NewFilters(cfg.Chat.Filters, cfg.Chat.ProfanityWords)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize chat filters")
+	}
+	chatService := service.NewChatService(chatRepo, tripRepo, driverRepo, passengerRepo, chatFilters, cfg.Chat, logger)
+
+	// SOS safety incidents, alerted to webhook subscribers and frozen until admins close them
+	incidentService := service.NewSafetyIncidentService(incidentRepo, tripRepo, driverRepo, passengerRepo, timelineService, webhookDispatcher, eventBus, logger)
+
+	// Driver trip completions, reconciled against the requested route and flagged for review on large discrepancies
+	completionService := service.NewTripCompletionService(tripRepo, driverRepo, passengerRepo, cfg.Fare, tripEventPublishers, eventBus, logger)
+	completionService.SetLatencyRecorder(traditionalMonitor)
+
+	// Drivers waiting at the pickup, charging passengers for long waits and no-shows
+	pickupWaitService := service.NewPickupWaitService(tripRepo, driverRepo, passengerRepo, cfg.PickupWait, tripEventPublishers, eventBus, logger)
+
+	// Driver online and driving time, setting drivers offline to rest once they reach a fatigue limit
+	fatigueService := service.NewDriverFatigueService(driverRepo, cfg.Fatigue, eventBus, traditionalMonitor, logger)
+
+	// Driver onboarding stages; with activation required, drivers go online only once activated
+	onboardingService := service.NewDriverOnboardingService(repos.Onboarding, cfg.Onboarding, eventBus, logger)
+
+	// Partition creation and retention of the time-partitioned tables, run by the leader and by
+	// operators on demand
+	var partitionManager *retention.PartitionManager
+	if cfg.Database.Driver == "postgres" {
+		partitionManager = retention.NewPartitionManager(dbx, cfg, logger)
+	}
+
 	// Set Gin mode based on server mode
 	if cfg.Server.Mode == "release" {
 		gin.SetMode(gin.ReleaseMode)
@@ -283,17 +772,68 @@ func main() {
 
 	// Initialize router
 	routerConfig := &router.RouterConfig{
-		UserRepo:           userRepo,
-		DriverRepo:         driverRepo,
-		PassengerRepo:      passengerRepo,
-		TripRepo:           tripRepo,
-		ObservabilityRepo:  observabilityRepo,
-		TraditionalRepo:    traditionalRepo,
-		RideService:        rideService,
-		ActorSystem:        actorSystem,
-		TraditionalMonitor: traditionalMonitor,
-		Logger:             logger,
-		Config:             cfg,
+		UserRepo:             userRepo,
+		DriverRepo:           driverRepo,
+		PassengerRepo:        passengerRepo,
+		TripRepo:             tripRepo,
+		SavedLocationRepo:    savedLocationRepo,
+		WebhookRepo:          webhookRepo,
+		ObservabilityRepo:    observabilityRepo,
+		TraditionalRepo:      traditionalRepo,
+		RideService:          rideService,
+		SessionService:       sessionService,
+		FareDisputeService:   fareDisputeService,
+		IncentiveService:     incentiveService,
+		ForecastService:      forecastService,
+		ReportService:        reportService,
+		CancellationService:  cancellationService,
+		HeatmapService:       heatmapService,
+		ScalingSignalService: scalingSignalService,
+		StorageCostService:   storageCostService,
+		ResearchService:      researchService,
+		DashboardService:     dashboardService,
+		TimelineService:      timelineService,
+		FleetService:         fleetService,
+		CorporateService:     corporateService,
+		ChatService:          chatService,
+		IncidentService:      incidentService,
+		CompletionService:    completionService,
+		PickupWaitService:    pickupWaitService,
+		ETAAccuracyService:   etaAccuracyService,
+		LocationTrailService: locationTrailService,
+		FraudService:         fraudService,
+		PaymentService:       paymentService,
+		IntegrityService:     integrityService,
+		APIUsageService:      apiUsageService,
+		FatigueService:       fatigueService,
+		OnboardingService:    onboardingService,
+		EmailNotifier:        emailNotifier,
+		TraditionalMatcher:   traditionalMatcher,
+		LocationIngester:     locationIngester,
+		ConfigReloader:       configReloader,
+		Locker:               locker,
+		SchemaChecker:        schemaChecker,
+		ChangeListener:       changeListener,
+		PartitionManager:     partitionManager,
+		Experiments:          experiments,
+		FileStore:            fileStore,
+		AvatarService:        avatarService,
+		InvoiceService:       invoiceService,
+		RateLimiter:          rateLimiter,
+		BodyLogger:           bodyLogger,
+		TrafficRecorder:      trafficRecorder,
+		AppVersionGate:       appVersionGate,
+		ActorSystem:          actorSystem,
+		TraditionalMonitor:   traditionalMonitor,
+		SLOTracker:           sloTracker,
+		EventStream:          eventStream,
+		SlowQueryLog:         slowQueryLog,
+		MetricsCollector:     metricsCollector,
+		EventBus:             eventBus,
+		TripStatusStream:     tripStatusStream,
+		Redactor:             redactor,
+		Logger:               logger,
+		Config:               cfg,
 	}
 
 	ginEngine := router.SetupRouter(routerConfig)
@@ -315,12 +855,100 @@ func main() {
 	if err := actorSystem.Start(context.Background()); err != nil {
 		logger.WithError(err).Fatal("Failed to start actor system")
 	}
+	// Pre-spawn the warm pools so rides don't pay for spawning their actors
+	for actorType, size := range cfg.Actor.PoolSizes {
+		if err := actorSystem.WarmPool(actorType, size, 100, actor.SupervisionStrategy(cfg.Actor.SupervisionStrategy)); err != nil {
+			logger.WithError(err).Fatal("Failed to warm actor pool")
+		}
+	}
 
 	// Start metrics collector
 	if err := metricsCollector.Start(context.Background()); err != nil {
 		logger.WithError(err).Fatal("Failed to start metrics collector")
 	}
 
+	// Run the singleton background workers on the one instance elected leader: partition
+	// maintenance for the time-partitioned observability tables, webhook delivery, the scheduled
+	// dashboard refresh, the purge of chats past the retention period, fatigue enforcement and
+	// payment reconciliation
+	var electionBackend leader.Backend
+	switch backend := cfg.Leader.Backend; {
+	case backend == "redis" || (backend == "auto" && locker != nil):
+		if locker == nil {
+			logger.Fatal("Leader election on Redis requires Redis")
+		}
+		electionBackend = leader.NewRedisBackend(locker)
+	case backend == "postgres" || (backend == "auto" && cfg.Database.Driver == "postgres"):
+		if cfg.Database.Driver != "postgres" {
+			logger.Fatal("Leader election on Postgres requires the postgres database driver")
+		}
+		electionBackend = leader.NewPostgresBackend(dbx.DB, cfg.Leader.RetryInterval)
+	default:
+		electionBackend = leader.LocalBackend{}
+	}
+	elector, err := leader.NewElector("background-workers", electionBackend, cfg.Leader.RetryInterval, otelMonitor.Meter(), logger)
+	if err != nil {
+		logger.WithError(err).Fatal("Failed to initialize leader election")
+	}
+	if partitionManager != nil {
+		elector.Add("partition_manager", partitionManager)
+	}
+	elector.Add("webhook_dispatcher", webhookDispatcher)
+	elector.Add("dashboard_refresher", dashboardService)
+	elector.Add("chat_purger", chatService)
+	elector.Add("driver_fatigue_enforcer", fatigueService)
+	elector.Add("invoice_generator", invoiceService)
+	elector.Add("payment_reconciler", paymentService)
+	if metricsArchiveService != nil {
+		elector.Add("metrics_archiver", metricsArchiveService)
+	}
+	if integrityService != nil {
+		elector.Add("integrity_checker", integrityService)
+	}
+	// Downsampling relies on PostgreSQL's DISTINCT ON and data-modifying CTEs
+	if cfg.Database.Driver != "sqlite" {
+		elector.Add("location_trail_downsampler", locationTrailService)
+	}
+	if err := elector.Start(context.Background()); err != nil {
+		logger.WithError(err).Fatal("Failed to start leader election")
+	}
+
+	// Recompute the ETA accuracy on every instance, as each adjusts its own ETA model
+	if err := etaAccuracyService.Start(context.Background()); err != nil {
+		logger.WithError(err).Fatal("Failed to start ETA accuracy service")
+	}
+
+	// Flush the API usage every instance rolls up
+	if err := apiUsageService.Start(context.Background()); err != nil {
+		logger.WithError(err).Fatal("Failed to start API usage service")
+	}
+
+	// Send the emails each instance queues
+	if emailNotifier != nil {
+		if err := emailNotifier.Start(context.Background()); err != nil {
+			logger.WithError(err).Fatal("Failed to start email notifier")
+		}
+	}
+
+	// Match traditional ride requests on the worker pool
+	if err := traditionalMatcher.Start(context.Background()); err != nil {
+		logger.WithError(err).Fatal("Failed to start traditional matcher")
+	}
+
+	// Write buffered driver locations to the database on an interval
+	if locationIngester != nil {
+		if err := locationIngester.Start(context.Background()); err != nil {
+			logger.WithError(err).Fatal("Failed to start location ingester")
+		}
+	}
+
+	// Listen for row changes, reconnecting in the background whenever the connection is lost
+	if changeListener != nil {
+		if err := changeListener.Start(context.Background()); err != nil {
+			logger.WithError(err).Fatal("Failed to start change listener")
+		}
+	}
+
 	// Start traditional monitor
 	if err := traditionalMonitor.Start(context.Background()); err != nil {
 		logger.WithError(err).Fatal("Failed to start traditional monitor")
@@ -340,6 +968,18 @@ func main() {
 		}
 	}()
 
+	// Reload configuration on SIGHUP
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	go func() {
+		for range reload {
+			logger.Info("Received SIGHUP, reloading configuration")
+			if _, err := configReloader.Reload(context.Background()); err != nil {
+				logger.WithError(err).Error("Configuration reload failed")
+			}
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -347,8 +987,57 @@ func main() {
 
 	logger.Info("Shutting down server...")
 
+	// Stop the singleton workers and give leadership up, then release the locks this instance holds
+	// so that other instances need not wait for them to expire
+	if err := elector.Stop(); err != nil {
+		logger.WithError(err).Error("Failed to stop leader election")
+	}
+	if err := etaAccuracyService.Stop(); err != nil {
+		logger.WithError(err).Error("Failed to stop ETA accuracy service")
+	}
+	// Requests still being served after the final flush are not recorded
+	if err := apiUsageService.Stop(); err != nil {
+		logger.WithError(err).Error("Failed to stop API usage service")
+	}
+	// Emails still queued are not sent
+	if emailNotifier != nil {
+		if err := emailNotifier.Stop(); err != nil {
+			logger.WithError(err).Error("Failed to stop email notifier")
+		}
+	}
+	// Requests still queued for a matching worker time out
+	if err := traditionalMatcher.Stop(); err != nil {
+		logger.WithError(err).Error("Failed to stop traditional matcher")
+	}
+	// Requests still being served are not recorded
+	if trafficRecorder != nil {
+		if err := trafficRecorder.Close(); err != nil {
+			logger.WithError(err).Error("Failed to close traffic recording")
+		} else {
+			logger.WithField("requests", trafficRecorder.Recorded()).Info("Traffic recording closed")
+		}
+	}
+	if changeListener != nil {
+		if err := changeListener.Stop(); err != nil {
+			logger.WithError(err).Error("Failed to stop change listener")
+		}
+	}
+	// Buffered driver locations are written before the database is closed
+	if locationIngester != nil {
+		if err := locationIngester.Stop(); err != nil {
+			logger.WithError(err).Error("Failed to write buffered driver locations")
+		}
+	}
+	if locker != nil {
+		releaseCtx, releaseCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := locker.Close(releaseCtx); err != nil {
+			logger.WithError(err).Warn("Failed to release distributed locks")
+		}
+		releaseCancel()
+	}
+
 	// Perform graceful shutdown with proper error handling
-	performGracefulShutdown(server, actorSystem, metricsCollector, traditionalMonitor, db, redisClient, logger)
+	performGracefulShutdown(server, actorSystem, eventBus, metricsCollector, traditionalMonitor, db, replicaRouter, redisClient, logger)
 
 	logger.Info("Application shutdown completed")
 }
@@ -357,9 +1046,11 @@ func main() {
 func performGracefulShutdown(
 	server *http.Server,
 	actorSystem *actor.ActorSystem,
+	eventBus *bus.Bus,
 	metricsCollector *observability.MetricsCollector,
 	traditionalMonitor *traditional.TraditionalMonitor,
 	db *database.PostgresDB,
+	replicaRouter *database.ReplicaRouter,
 	redisClient *database.RedisClient,
 	logger *logging.Logger,
 ) {
@@ -368,7 +1059,7 @@ func performGracefulShutdown(
 	defer shutdownCancel()
 
 	// Channel to collect shutdown errors
-	errorChan := make(chan error, 6)
+	errorChan := make(chan error, 16)
 	var shutdownWg sync.WaitGroup
 
 	// Shutdown HTTP server first
@@ -448,35 +1139,56 @@ func performGracefulShutdown(
 		} else {
 			logger.Info("Actor system stopped")
 		}
+
+		// Deliver the events published while stopping before the bus goes away
+		eventBus.Close()
+		logger.Info("Event bus drained")
 	}()
 
 	// Close database connection
-	shutdownWg.Add(1)
-	go func() {
-		defer shutdownWg.Done()
-		logger.Info("Closing database connection...")
+	if db != nil {
+		shutdownWg.Add(1)
+		go func() {
+			defer shutdownWg.Done()
+			logger.Info("Closing database connection...")
 
-		if err := db.Close(); err != nil {
-			errorChan <- fmt.Errorf("database close error: %w", err)
-			logger.WithError(err).Error("Failed to close database connection")
-		} else {
-			logger.Info("Database connection closed")
-		}
-	}()
+			if err := db.Close(); err != nil {
+				errorChan <- fmt.Errorf("database close error: %w", err)
+				logger.WithError(err).Error("Failed to close database connection")
+			} else {
+				logger.Info("Database connection closed")
+			}
+		}()
+	}
+
+	// Close read replica connection
+	if replicaRouter != nil {
+		shutdownWg.Add(1)
+		go func() {
+			defer shutdownWg.Done()
+
+			if err := replicaRouter.Stop(); err != nil {
+				errorChan <- fmt.Errorf("read replica close error: %w", err)
+				logger.WithError(err).Error("Failed to close read replica connection")
+			}
+		}()
+	}
 
 	// Close Redis connection
-	shutdownWg.Add(1)
-	go func() {
-		defer shutdownWg.Done()
-		logger.Info("Closing Redis connection...")
+	if redisClient != nil {
+		shutdownWg.Add(1)
+		go func() {
+			defer shutdownWg.Done()
+			logger.Info("Closing Redis connection...")
 
-		if err := redisClient.Close(); err != nil {
-			errorChan <- fmt.Errorf("Redis close error: %w", err)
-			logger.WithError(err).Error("Failed to close Redis connection")
-		} else {
-			logger.Info("Redis connection closed")
-		}
-	}()
+			if err := redisClient.Close(); err != nil {
+				errorChan <- fmt.Errorf("Redis close error: %w", err)
+				logger.WithError(err).Error("Failed to close Redis connection")
+			} else {
+				logger.Info("Redis connection closed")
+			}
+		}()
+	}
 
 	// Wait for all shutdown operations to complete or timeout
 	done := make(chan struct{})
@@ -508,3 +1220,64 @@ func performGracefulShutdown(
 		logger.Info("Shutdown completed successfully")
 	}
 }
+
+// actorRetryPolicy converts the configured retry settings into an actor retry policy
+func actorRetryPolicy(cfg config.RetryConfig, maxAttempts int) actor.RetryPolicy {
+	return actor.RetryPolicy{
+		MaxAttempts:    maxAttempts,
+		InitialBackoff: cfg.InitialBackoff,
+		MaxBackoff:     cfg.MaxBackoff,
+		Multiplier:     cfg.Multiplier,
+		Jitter:         cfg.Jitter,
+	}
+}
+
+// newHybridClock creates the clock of this instance, warning about the first skewed clock and
+// every hundredth after it
+func newHybridClock(cfg config.ObservabilityConfig, logger *logging.Logger) *hlc.Clock {
+	source := cfg.ClockSource
+	if source == "" {
+		source, _ = os.Hostname()
+	}
+	clock := hlc.NewClock(source, cfg.ClockMaxSkew)
+	clockLogger := logger.WithComponent("hybrid_clock")
+	clock.SetSkewHandler(func(skew hlc.Skew) {
+		if count := clock.Skews(); count == 1 || count%100 == 0 {
+			clockLogger.WithFields(logging.Fields{
+				"source":   skew.Source,
+				"offset":   skew.Offset.String(),
+				"max_skew": cfg.ClockMaxSkew.String(),
+				"skews":    count,
+			}).Warn("Clock skew detected, records are ordered as if the clock was at most max_skew ahead")
+		}
+	})
+	return clock
+}
+
+// actorMessageRecord builds the record of a message delivered to an actor, with the entity
+// changes its handler made
+func actorMessageRecord(actorID, actorType string, msg actor.Message, effects models.MessageEffects) *models.ActorMessage {
+	payload, _ := json.Marshal(msg.GetPayload())
+	version := msg.GetVersion()
+	if version == 0 {
+		version = actor.InitialMessageVersion
+	}
+	message := &models.ActorMessage{
+		ID:                uuid.New(),
+		TraceID:           uuid.New(),
+		SpanID:            uuid.New(),
+		SenderActorType:   models.ActorTypeObservability,
+		SenderActorID:     msg.GetSender(),
+		ReceiverActorType: models.ActorType(actorType),
+		ReceiverActorID:   actorID,
+		MessageType:       msg.GetType(),
+		MessagePayload:    payload,
+		MessageVersion:    version,
+		SentAt:            msg.GetTimestamp(),
+		Effects:           effects,
+		HLC:               msg.GetHLC(),
+		CreatedAt:         time.Now(),
+	}
+	message.EntityType, message.EntityID = models.EntityLink(msg.GetEntity())
+	return message
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"actor-model-observability/internal/scenario"
+	"actor-model-observability/internal/simulate"
+)
+
+func main() {
+	cfg := simulate.Config{Scenario: scenario.Default()}
+	sc := cfg.Scenario
+
+	scenarioPath := flag.String("scenario", "", "YAML scenario file; flags given explicitly override its values")
+	flag.StringVar(&cfg.BaseURL, "url", "http://localhost:8080", "Base URL of the API server")
+	flag.StringVar(&cfg.Approach, "approach", "actor", "Processing approach: actor or traditional")
+	flag.DurationVar(&cfg.RequestTimeout, "request-timeout", 10*time.Second, "HTTP request timeout")
+	flag.BoolVar(&cfg.Verbose, "verbose", false, "Log every failed API call")
+	flag.IntVar(&sc.Drivers.Count, "drivers", sc.Drivers.Count, "Number of synthetic drivers")
+	flag.IntVar(&sc.Passengers.Count, "passengers", sc.Passengers.Count, "Number of synthetic passengers")
+	flag.DurationVar(&sc.Duration, "duration", sc.Duration, "How long to run the simulation")
+	flag.DurationVar(&sc.Tick, "tick", sc.Tick, "Interval between driver location updates and ride status polls")
+	flag.Float64Var(&sc.Passengers.ArrivalRate, "arrival-rate", sc.Passengers.ArrivalRate, "Ride requests per minute across all passengers")
+	flag.Float64Var(&sc.Passengers.CancellationProbability, "cancel-probability", sc.Passengers.CancellationProbability, "Probability a passenger cancels before being matched")
+	flag.DurationVar(&sc.Passengers.MatchTimeout, "match-timeout", sc.Passengers.MatchTimeout, "Cancel ride requests that are not matched within this time")
+	flag.Float64Var(&sc.Drivers.SpeedKmh, "speed", sc.Drivers.SpeedKmh, "Driver speed in km/h")
+	flag.Float64Var(&sc.Area.Latitude, "lat", sc.Area.Latitude, "Latitude of the simulated area center")
+	flag.Float64Var(&sc.Area.Longitude, "lng", sc.Area.Longitude, "Longitude of the simulated area center")
+	flag.Float64Var(&sc.Area.RadiusKm, "radius", sc.Area.RadiusKm, "Radius of the simulated area in km")
+	flag.Int64Var(&sc.Seed, "seed", sc.Seed, "Random seed for reproducible routes and request timing (0 = random)")
+	flag.Parse()
+
+	if *scenarioPath != "" {
+		if err := loadScenario(sc, *scenarioPath); err != nil {
+			log.Fatalf("Failed to load scenario: %v", err)
+		}
+	}
+	if sc.Seed == 0 {
+		sc.Seed = time.Now().UnixNano()
+	}
+
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid flags: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	sim := simulate.New(cfg)
+
+	started := time.Now()
+	if err := sim.Run(ctx); err != nil {
+		log.Fatalf("Simulation failed: %v", err)
+	}
+
+	sim.PrintSummary(time.Since(started))
+}
+
+// loadScenario replaces sc with the scenario file at path, then re-applies the flags
+// that were set explicitly on the command line
+func loadScenario(sc *scenario.Scenario, path string) error {
+	explicit := make(map[string]string)
+	flag.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = f.Value.String()
+	})
+
+	loaded, err := scenario.Load(path)
+	if err != nil {
+		return err
+	}
+	*sc = *loaded
+
+	for name, value := range explicit {
+		if err := flag.Set(name, value); err != nil {
+			return fmt.Errorf("failed to apply flag -%s: %w", name, err)
+		}
+	}
+	return nil
+}
@@ -2,11 +2,15 @@ package actor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"actor-model-observability/internal/hlc"
 	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
 
 	"github.com/google/uuid"
 )
@@ -21,6 +25,13 @@ const (
 	ActorStateError      ActorState = "error"
 )
 
+// DefaultDedupWindow is how long an actor remembers the IDs of processed messages so that
+// redelivered copies are dropped instead of being applied twice
+const DefaultDedupWindow = 10 * time.Minute
+
+// ErrMailboxFull is returned when sending to an actor whose mailbox is full
+var ErrMailboxFull = errors.New("mailbox is full")
+
 // Message represents a message that can be sent between actors
 type Message interface {
 	GetID() string
@@ -28,6 +39,12 @@ type Message interface {
 	GetPayload() interface{}
 	GetSender() string
 	GetTimestamp() time.Time
+	// GetEntity returns the business entity the message relates to, empty when it has none
+	GetEntity() (entityType, entityID string)
+	// GetVersion returns the schema version of the payload, 0 when not yet stamped
+	GetVersion() int
+	// GetHLC returns the hybrid timestamp of sending the message, nil when not stamped
+	GetHLC() *hlc.Timestamp
 }
 
 // BaseMessage provides a basic implementation of Message
@@ -37,6 +54,18 @@ type BaseMessage struct {
 	Payload   interface{} `json:"payload"`
 	Sender    string      `json:"sender"`
 	Timestamp time.Time   `json:"timestamp"`
+
+	// Business entity the message relates to, such as the trip it is about
+	EntityType string `json:"entity_type,omitempty"`
+	EntityID   string `json:"entity_id,omitempty"`
+
+	// Schema version of the payload, stamped by the system when sent. Envelopes persisted
+	// without one are at the initial version.
+	Version int `json:"version,omitempty"`
+
+	// Hybrid timestamp of sending the message, stamped by the system when sent if it has a
+	// hybrid clock, ordering the message across instances whose wall clocks disagree
+	HLC *hlc.Timestamp `json:"hlc,omitempty"`
 }
 
 func NewBaseMessage(msgType string, payload interface{}, sender string) *BaseMessage {
@@ -54,6 +83,27 @@ func (m *BaseMessage) GetType() string         { return m.Type }
 func (m *BaseMessage) GetPayload() interface{} { return m.Payload }
 func (m *BaseMessage) GetSender() string       { return m.Sender }
 func (m *BaseMessage) GetTimestamp() time.Time { return m.Timestamp }
+func (m *BaseMessage) GetVersion() int         { return m.Version }
+func (m *BaseMessage) GetHLC() *hlc.Timestamp  { return m.HLC }
+func (m *BaseMessage) GetEntity() (string, string) {
+	return m.EntityType, m.EntityID
+}
+
+// HybridTimestamp returns the hybrid timestamp of sending the message, nil when not stamped
+func (m *BaseMessage) HybridTimestamp() *hlc.Timestamp { return m.HLC }
+
+// SetHybridTimestamp stamps the message with the hybrid timestamp of sending it
+func (m *BaseMessage) SetHybridTimestamp(ts hlc.Timestamp) { m.HLC = &ts }
+
+// SourceTime returns when and by whom the message was sent, by the sender's wall clock
+func (m *BaseMessage) SourceTime() (time.Time, string) { return m.Timestamp, m.Sender }
+
+// WithEntity links the message to the business entity it relates to and returns it
+func (m *BaseMessage) WithEntity(entityType, entityID string) *BaseMessage {
+	m.EntityType = entityType
+	m.EntityID = entityID
+	return m
+}
 
 // Actor represents the core actor interface
 type Actor interface {
@@ -76,6 +126,16 @@ type ActorMetrics struct {
 	LastActivity       time.Time     `json:"last_activity"`
 	Uptime             time.Duration `json:"uptime"`
 	CurrentQueueSize   int           `json:"current_queue_size"`
+	MessagesDuplicated int64         `json:"messages_duplicated"`
+}
+
+// MailboxSnapshot is the messages waiting in an actor's mailbox at one point in time
+type MailboxSnapshot struct {
+	ActorID   string    `json:"actor_id"`
+	ActorType string    `json:"actor_type"`
+	Capacity  int       `json:"capacity"`
+	Pending   int       `json:"pending"`
+	Messages  []Message `json:"messages"` // oldest first, at most the limit peeked
 }
 
 // BaseActor provides a basic implementation of Actor
@@ -91,9 +151,34 @@ type BaseActor struct {
 	metricsLock sync.RWMutex
 	startTime   time.Time
 	wg          sync.WaitGroup
+	clock       Clock
+	manual      bool              // messages are processed by the system scheduler instead of a message loop
+	goroutines  *goroutineTracker // set by the system to audit goroutines outliving Stop
+	duplicates  *atomic.Int64     // set by the system to count the duplicates of all its actors
+
+	// Messages in the mailbox, in delivery order, for inspection. A channel cannot be peeked, so
+	// sends append here and processing removes the head.
+	pending     []Message
+	handling    bool // a message taken from pending is being processed
+	pendingLock sync.Mutex
 
 	// Message handler function
 	handler func(Message) error
+	// Called after every processing attempt with the effects the handler recorded, e.g. for the
+	// system to retry failures
+	onProcessed func(message Message, effects models.MessageEffects, duration time.Duration, err error)
+
+	// Deduplication of redelivered messages
+	dedupWindow time.Duration
+	processed   map[string]time.Time
+	processedAt []processedMessage // in processing order, for expiry
+	dedupLock   sync.Mutex
+}
+
+// processedMessage records when a message ID was successfully processed
+type processedMessage struct {
+	id string
+	at time.Time
 }
 
 // NewBaseActor creates a new base actor
@@ -103,12 +188,15 @@ func NewBaseActor(id, actorType string, mailboxSize int, handler func(Message) e
 	}
 
 	return &BaseActor{
-		id:        id,
-		actorType: actorType,
-		state:     ActorStateIdle,
-		mailbox:   make(chan Message, mailboxSize),
-		logger:    logging.GetGlobalLogger().WithActor(id, actorType),
-		handler:   handler,
+		id:          id,
+		actorType:   actorType,
+		state:       ActorStateIdle,
+		mailbox:     make(chan Message, mailboxSize),
+		logger:      logging.GetGlobalLogger().WithActor(id, actorType),
+		handler:     handler,
+		clock:       RealClock{},
+		dedupWindow: DefaultDedupWindow,
+		processed:   make(map[string]time.Time),
 		metrics: ActorMetrics{
 			LastActivity: time.Now(),
 		},
@@ -133,11 +221,13 @@ func (a *BaseActor) Start(ctx context.Context) error {
 	}
 
 	a.ctx, a.cancel = context.WithCancel(ctx)
-	a.startTime = time.Now()
+	a.startTime = a.clock.Now()
 	a.state = ActorStateProcessing
 
-	a.wg.Add(1)
-	go a.messageLoop()
+	if !a.manual {
+		a.wg.Add(1)
+		a.Go("message_loop", func(context.Context) { a.messageLoop() })
+	}
 
 	a.logger.Info("Actor started")
 	return nil
@@ -148,7 +238,31 @@ func (a *BaseActor) Stop() error {
 		return nil
 	}
 
+	a.pendingLock.Lock()
+	a.state = ActorStateStopped
+	a.pendingLock.Unlock()
+	a.shutdown()
+	return nil
+}
+
+// passivate stops the actor if its mailbox is empty and no message is being processed, and
+// reports whether it stopped. Sends check the state under the mailbox lock, so none can land in
+// the mailbox once it is found empty.
+func (a *BaseActor) passivate() bool {
+	a.pendingLock.Lock()
+	if a.state == ActorStateStopped || len(a.pending) > 0 || a.handling {
+		a.pendingLock.Unlock()
+		return false
+	}
 	a.state = ActorStateStopped
+	a.pendingLock.Unlock()
+
+	a.shutdown()
+	return true
+}
+
+// shutdown stops the message loop of an actor whose state is stopped
+func (a *BaseActor) shutdown() {
 	if a.cancel != nil {
 		a.cancel()
 	}
@@ -158,31 +272,98 @@ func (a *BaseActor) Stop() error {
 
 	// Wait for message loop to finish
 	a.wg.Wait()
+	if a.goroutines != nil {
+		a.goroutines.stop(a.id)
+	}
 
 	a.logger.Info("Actor stopped")
-	return nil
+}
+
+// Go runs fn in a goroutine of the actor, called after Start. fn receives the actor's context,
+// which Stop cancels, and should return soon after; Stop does not wait for it, but goroutines
+// still running a while after Stop are reported by the system's leak audit.
+func (a *BaseActor) Go(name string, fn func(ctx context.Context)) {
+	ctx := a.ctx
+	if a.goroutines == nil {
+		go fn(ctx)
+		return
+	}
+
+	id := a.goroutines.start(a.id, a.actorType, name)
+	go func() {
+		defer a.goroutines.finish(a.id, id)
+		fn(ctx)
+	}()
 }
 
 func (a *BaseActor) Send(message Message) error {
+	// The send never blocks, and holding the lock through it keeps pending in mailbox order
+	a.pendingLock.Lock()
 	if a.state == ActorStateStopped {
+		a.pendingLock.Unlock()
 		return fmt.Errorf("actor %s is stopped", a.id)
 	}
-
 	select {
 	case a.mailbox <- message:
+		a.pending = append(a.pending, message)
+		a.pendingLock.Unlock()
 		a.updateMetrics(func(m *ActorMetrics) {
 			m.MessagesReceived++
 			m.CurrentQueueSize = len(a.mailbox)
-			m.LastActivity = time.Now()
+			m.LastActivity = a.clock.Now()
 		})
 		return nil
 	case <-a.ctx.Done():
+		a.pendingLock.Unlock()
 		return fmt.Errorf("actor %s context cancelled", a.id)
 	default:
-		return fmt.Errorf("actor %s mailbox is full", a.id)
+		a.pendingLock.Unlock()
+		return fmt.Errorf("actor %s %w", a.id, ErrMailboxFull)
 	}
 }
 
+// PeekMailbox returns up to limit of the messages waiting in the mailbox, oldest first, without
+// removing them. A limit of zero or less returns them all.
+func (a *BaseActor) PeekMailbox(limit int) MailboxSnapshot {
+	a.pendingLock.Lock()
+	defer a.pendingLock.Unlock()
+
+	n := len(a.pending)
+	if limit > 0 && limit < n {
+		n = limit
+	}
+	messages := make([]Message, n)
+	copy(messages, a.pending)
+
+	return MailboxSnapshot{
+		ActorID:   a.id,
+		ActorType: a.actorType,
+		Capacity:  cap(a.mailbox),
+		Pending:   len(a.pending),
+		Messages:  messages,
+	}
+}
+
+// dequeued removes the head of pending once the message loop or scheduler has taken it from the
+// mailbox
+func (a *BaseActor) dequeued() {
+	a.pendingLock.Lock()
+	defer a.pendingLock.Unlock()
+
+	if len(a.pending) > 0 {
+		a.pending[0] = nil
+		a.pending = a.pending[1:]
+	}
+	a.handling = true
+}
+
+// handled records that the message taken from pending has been processed
+func (a *BaseActor) handled() {
+	a.pendingLock.Lock()
+	defer a.pendingLock.Unlock()
+	a.handling = false
+}
+
 func (a *BaseActor) Receive() <-chan Message {
 	return a.mailbox
 }
@@ -193,7 +374,7 @@ func (a *BaseActor) GetMetrics() ActorMetrics {
 
 	metrics := a.metrics
 	if !a.startTime.IsZero() {
-		metrics.Uptime = time.Since(a.startTime)
+		metrics.Uptime = a.clock.Since(a.startTime)
 	}
 	metrics.CurrentQueueSize = len(a.mailbox)
 
@@ -220,21 +401,75 @@ func (a *BaseActor) messageLoop() {
 	}
 }
 
+// processNext processes the next queued message without blocking.
+// It is used by the deterministic scheduler and reports whether a message was processed.
+func (a *BaseActor) processNext() bool {
+	select {
+	case message, ok := <-a.mailbox:
+		if !ok {
+			return false
+		}
+		a.processMessage(message)
+		return true
+	default:
+		return false
+	}
+}
+
+// useSimulation makes the actor read time from clock and leave message processing to the scheduler
+func (a *BaseActor) useSimulation(clock Clock) {
+	a.clock = clock
+	a.manual = true
+	a.metrics.LastActivity = clock.Now()
+}
+
+// SetDedupWindow sets how long processed message IDs are remembered. Zero disables deduplication.
+func (a *BaseActor) SetDedupWindow(window time.Duration) {
+	a.dedupLock.Lock()
+	defer a.dedupLock.Unlock()
+
+	a.dedupWindow = window
+	if window <= 0 {
+		a.processed = make(map[string]time.Time)
+		a.processedAt = nil
+	}
+}
+
 func (a *BaseActor) processMessage(message Message) {
-	start := time.Now()
+	a.dequeued()
+	defer a.handled()
+	start := a.clock.Now()
+
+	if a.isDuplicate(message.GetID(), start) {
+		a.updateMetrics(func(m *ActorMetrics) {
+			m.MessagesDuplicated++
+			m.CurrentQueueSize = len(a.mailbox)
+			m.LastActivity = start
+		})
+		if a.duplicates != nil {
+			a.duplicates.Add(1)
+		}
+		a.logger.WithMessage(message.GetID(), message.GetType(), message.GetSender(), a.id).Warn("Dropping duplicate message")
+		return
+	}
 
 	a.logger.WithMessage(message.GetID(), message.GetType(), message.GetSender(), a.id).Debug("Processing message")
 
+	processing := &effectsMessage{Message: message}
 	var err error
 	if a.handler != nil {
-		err = a.handler(message)
+		err = a.handler(processing)
+	}
+	if err == nil {
+		// Failed messages are not remembered so that a retry can still apply them
+		a.markProcessed(message.GetID(), start)
 	}
 
-	processTime := time.Since(start)
+	processTime := a.clock.Since(start)
 
 	a.updateMetrics(func(m *ActorMetrics) {
 		m.CurrentQueueSize = len(a.mailbox)
-		m.LastActivity = time.Now()
+		m.LastActivity = a.clock.Now()
 
 		if err != nil {
 			m.MessagesFailed++
@@ -251,6 +486,86 @@ func (a *BaseActor) processMessage(message Message) {
 			)
 		}
 	})
+
+	if a.onProcessed != nil {
+		a.onProcessed(message, processing.effects, processTime, err)
+	}
+}
+
+// isDuplicate reports whether a message with this ID was processed within the dedup window
+func (a *BaseActor) isDuplicate(id string, now time.Time) bool {
+	if id == "" {
+		return false
+	}
+
+	a.dedupLock.Lock()
+	defer a.dedupLock.Unlock()
+
+	if a.dedupWindow <= 0 {
+		return false
+	}
+	a.expireProcessed(now)
+
+	_, seen := a.processed[id]
+	return seen
+}
+
+// markProcessed remembers a successfully processed message ID
+func (a *BaseActor) markProcessed(id string, now time.Time) {
+	if id == "" {
+		return
+	}
+
+	a.dedupLock.Lock()
+	defer a.dedupLock.Unlock()
+
+	if a.dedupWindow <= 0 {
+		return
+	}
+	a.processed[id] = now
+	a.processedAt = append(a.processedAt, processedMessage{id: id, at: now})
+}
+
+// processedMessages returns the message IDs processed within the dedup window, oldest first, for
+// an actor revived after passivation to keep dropping redelivered copies
+func (a *BaseActor) processedMessages() []processedMessage {
+	a.dedupLock.Lock()
+	defer a.dedupLock.Unlock()
+
+	if a.dedupWindow <= 0 {
+		return nil
+	}
+	a.expireProcessed(a.clock.Now())
+	return append([]processedMessage(nil), a.processedAt...)
+}
+
+// restoreProcessed remembers message IDs processed before the actor was passivated
+func (a *BaseActor) restoreProcessed(processed []processedMessage) {
+	a.dedupLock.Lock()
+	defer a.dedupLock.Unlock()
+
+	if a.dedupWindow <= 0 {
+		return
+	}
+	for _, p := range processed {
+		a.processed[p.id] = p.at
+	}
+	a.processedAt = append(a.processedAt, processed...)
+}
+
+// expireProcessed forgets message IDs processed before the dedup window. Callers hold dedupLock.
+func (a *BaseActor) expireProcessed(now time.Time) {
+	cutoff := now.Add(-a.dedupWindow)
+
+	expired := 0
+	for _, p := range a.processedAt {
+		if p.at.After(cutoff) {
+			break
+		}
+		delete(a.processed, p.id)
+		expired++
+	}
+	a.processedAt = a.processedAt[expired:]
 }
 
 func (a *BaseActor) updateMetrics(updater func(*ActorMetrics)) {
@@ -0,0 +1,150 @@
+package actor
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock abstracts time so the actor system can run against a simulated clock in tests
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+	// AfterFunc calls f once d has elapsed on this clock
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+// Timer is a pending AfterFunc call
+type Timer interface {
+	// Stop prevents the call from firing; it returns false if it already fired or was stopped
+	Stop() bool
+}
+
+// RealClock is a Clock backed by the time package
+type RealClock struct{}
+
+// Now returns the current wall clock time
+func (RealClock) Now() time.Time { return time.Now() }
+
+// Since returns the wall clock time elapsed since t
+func (RealClock) Since(t time.Time) time.Duration { return time.Since(t) }
+
+// AfterFunc calls f in its own goroutine after d
+func (RealClock) AfterFunc(d time.Duration, f func()) Timer { return time.AfterFunc(d, f) }
+
+// SimulatedClock is a Clock whose time only moves when Advance is called.
+// Timers fire synchronously, in deadline order, on the goroutine calling Advance.
+type SimulatedClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	seq    uint64
+	timers []*simulatedTimer
+}
+
+type simulatedTimer struct {
+	clock    *SimulatedClock
+	deadline time.Time
+	seq      uint64 // preserves scheduling order between timers with the same deadline
+	fn       func()
+}
+
+// NewSimulatedClock creates a simulated clock starting at start
+func NewSimulatedClock(start time.Time) *SimulatedClock {
+	return &SimulatedClock{now: start}
+}
+
+// Now returns the current simulated time
+func (c *SimulatedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Since returns the simulated time elapsed since t
+func (c *SimulatedClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+// AfterFunc schedules f to run when the simulated time reaches now+d
+func (c *SimulatedClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.seq++
+	timer := &simulatedTimer{
+		clock:    c,
+		deadline: c.now.Add(d),
+		seq:      c.seq,
+		fn:       f,
+	}
+	c.timers = append(c.timers, timer)
+	sort.Slice(c.timers, func(i, j int) bool {
+		a, b := c.timers[i], c.timers[j]
+		if !a.deadline.Equal(b.deadline) {
+			return a.deadline.Before(b.deadline)
+		}
+		return a.seq < b.seq
+	})
+
+	return timer
+}
+
+// Advance moves the simulated time forward by d, firing every timer that becomes due
+func (c *SimulatedClock) Advance(d time.Duration) {
+	target := c.Now().Add(d)
+	for c.fireNext(target) {
+	}
+	c.setNow(target)
+}
+
+// PendingTimers returns the number of timers that have not fired yet
+func (c *SimulatedClock) PendingTimers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// fireNext fires the earliest timer due at or before until, moving the clock to its
+// deadline. It reports whether a timer fired.
+func (c *SimulatedClock) fireNext(until time.Time) bool {
+	c.mu.Lock()
+	if len(c.timers) == 0 || c.timers[0].deadline.After(until) {
+		c.mu.Unlock()
+		return false
+	}
+
+	timer := c.timers[0]
+	c.timers = c.timers[1:]
+	if timer.deadline.After(c.now) {
+		c.now = timer.deadline
+	}
+	c.mu.Unlock()
+
+	// Run outside the lock so callbacks can schedule new timers
+	timer.fn()
+	return true
+}
+
+// setNow moves the clock to t if t is later than the current time
+func (c *SimulatedClock) setNow(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t.After(c.now) {
+		c.now = t
+	}
+}
+
+// Stop removes the timer from the clock
+func (t *simulatedTimer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, pending := range c.timers {
+		if pending == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
@@ -170,7 +170,7 @@ func (da *DriverActor) handleRideRequest(message Message) error {
 	// 4. Timeout handling for request expiration
 
 	// For demo purposes, we'll auto-accept if driver is online
-	return da.acceptRideRequest(payload.TripID)
+	return da.acceptRideRequest(message, payload.TripID)
 }
 
 // handlePassengerRated processes passenger rating notifications
@@ -191,6 +191,15 @@ func (da *DriverActor) handlePassengerRated(message Message) error {
 	totalRating := da.driver.Rating*float64(da.driver.TotalTrips) + payload.Rating
 	da.driver.TotalTrips++
 	da.driver.Rating = totalRating / float64(da.driver.TotalTrips)
+	RecordEffect(message, models.MessageEffect{
+		EntityType: models.EntityTypeDriver,
+		EntityID:   da.driver.ID.String(),
+		Action:     "rating_updated",
+		Changes: map[string]interface{}{
+			"rating":      da.driver.Rating,
+			"total_trips": da.driver.TotalTrips,
+		},
+	})
 
 	// Here you could:
 	// 1. Update driver rating in database
@@ -217,7 +226,7 @@ func (da *DriverActor) GoOnline(system *ActorSystem, lat, lng float64) error {
 		Timestamp: time.Now(),
 	}
 
-	message := NewBaseMessage(MsgTypeGoOnline, payload, da.GetID())
+	message := NewBaseMessage(MsgTypeGoOnline, payload, da.GetID()).WithEntity(models.EntityTypeDriver, da.driver.ID.String())
 
 	// Notify location service
 	if err := system.SendMessage("location-service", message); err != nil {
@@ -245,7 +254,7 @@ func (da *DriverActor) GoOffline(system *ActorSystem, reason string) error {
 		Timestamp: time.Now(),
 	}
 
-	message := NewBaseMessage(MsgTypeGoOffline, payload, da.GetID())
+	message := NewBaseMessage(MsgTypeGoOffline, payload, da.GetID()).WithEntity(models.EntityTypeDriver, da.driver.ID.String())
 
 	// Notify location service
 	if err := system.SendMessage("location-service", message); err != nil {
@@ -258,8 +267,14 @@ func (da *DriverActor) GoOffline(system *ActorSystem, reason string) error {
 }
 
 // acceptRideRequest sends an accept ride message
-func (da *DriverActor) acceptRideRequest(tripID string) error {
+func (da *DriverActor) acceptRideRequest(message Message, tripID string) error {
 	da.driver.Status = models.DriverStatusBusy
+	RecordEffect(message, models.MessageEffect{
+		EntityType: models.EntityTypeDriver,
+		EntityID:   da.driver.ID.String(),
+		Action:     "status_changed",
+		Changes:    map[string]interface{}{"status": da.driver.Status, "trip_id": tripID},
+	})
 
 	// Here you would send to trip management service
 	// For now, just log the acceptance
@@ -289,7 +304,7 @@ func (da *DriverActor) StartRide(system *ActorSystem, tripID string, startLat, s
 		StartLng:  startLng,
 	}
 
-	message := NewBaseMessage(MsgTypeStartRide, payload, da.GetID())
+	message := NewBaseMessage(MsgTypeStartRide, payload, da.GetID()).WithEntity(models.EntityTypeTrip, tripID)
 
 	// Send to trip management service
 	if err := system.SendMessage("trip-manager", message); err != nil {
@@ -320,7 +335,7 @@ func (da *DriverActor) CompleteRide(system *ActorSystem, tripID string, endLat,
 		CompletedAt: time.Now(),
 	}
 
-	message := NewBaseMessage(MsgTypeCompleteRide, payload, da.GetID())
+	message := NewBaseMessage(MsgTypeCompleteRide, payload, da.GetID()).WithEntity(models.EntityTypeTrip, tripID)
 
 	// Send to trip management service
 	if err := system.SendMessage("trip-manager", message); err != nil {
@@ -352,7 +367,7 @@ func (da *DriverActor) UpdateLocation(system *ActorSystem, lat, lng, heading, sp
 		Timestamp: time.Now(),
 	}
 
-	message := NewBaseMessage(MsgTypeDriverLocation, payload, da.GetID())
+	message := NewBaseMessage(MsgTypeDriverLocation, payload, da.GetID()).WithEntity(models.EntityTypeDriver, da.driver.ID.String())
 
 	// Send to location service
 	if err := system.SendMessage("location-service", message); err != nil {
@@ -367,6 +382,26 @@ func (da *DriverActor) GetDriver() *models.Driver {
 	return da.driver
 }
 
+// Snapshot captures the driver while the actor is passivated and releases it
+func (da *DriverActor) Snapshot() ([]byte, error) {
+	snapshot, err := json.Marshal(da.driver)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot driver: %w", err)
+	}
+	da.driver = nil
+	return snapshot, nil
+}
+
+// Restore brings the driver back from a snapshot before the revived actor processes a message
+func (da *DriverActor) Restore(snapshot []byte) error {
+	driver := &models.Driver{}
+	if err := json.Unmarshal(snapshot, driver); err != nil {
+		return fmt.Errorf("failed to restore driver: %w", err)
+	}
+	da.driver = driver
+	return nil
+}
+
 // unmarshalPayload is a helper function to unmarshal message payloads
 func (da *DriverActor) unmarshalPayload(payload interface{}, target interface{}) error {
 	// Convert payload to JSON bytes first
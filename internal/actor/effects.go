@@ -0,0 +1,41 @@
+package actor
+
+import (
+	"time"
+
+	"actor-model-observability/internal/models"
+)
+
+// MessageResult is a message an actor processed successfully, with the changes its handler
+// recorded
+type MessageResult struct {
+	ActorID   string
+	ActorType string
+	Message   Message
+	Duration  time.Duration
+	Effects   models.MessageEffects
+}
+
+// effectsMessage is the message handed to a handler, collecting the effects it records
+type effectsMessage struct {
+	Message
+	effects models.MessageEffects
+}
+
+// RecordEffect records a change to a business entity made by the handler processing message,
+// such as a trip status change, so that the message's record shows what it did. It must be
+// called with the message the handler was given and does nothing for any other message.
+func RecordEffect(message Message, effect models.MessageEffect) {
+	if m, ok := message.(*effectsMessage); ok {
+		m.effects = append(m.effects, effect)
+	}
+}
+
+// unwrapMessage returns the message a handler was given as it was sent, e.g. when the handler
+// forwards it to another actor
+func unwrapMessage(message Message) Message {
+	if m, ok := message.(*effectsMessage); ok {
+		return m.Message
+	}
+	return message
+}
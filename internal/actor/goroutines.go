@@ -0,0 +1,146 @@
+package actor
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultGoroutineLeakGrace is how long a goroutine may outlive its actor's Stop before the
+// audit reports it as leaked
+const DefaultGoroutineLeakGrace = 30 * time.Second
+
+// GoroutineMetrics describes the goroutines of the process and those started by actors
+type GoroutineMetrics struct {
+	Total   int            `json:"total"`   // every goroutine of the process
+	Tracked int            `json:"tracked"` // running goroutines started by actors
+	Leaked  map[string]int `json:"leaked"`  // goroutines outliving their actor's Stop, by actor type
+}
+
+// LeakedGoroutine is a goroutine still running after its actor stopped
+type LeakedGoroutine struct {
+	Name      string    `json:"name"`
+	StartedAt time.Time `json:"started_at"`
+}
+
+// GoroutineLeak is an actor whose goroutines outlived its Stop by more than the grace period
+type GoroutineLeak struct {
+	ActorID    string            `json:"actor_id"`
+	ActorType  string            `json:"actor_type"`
+	StoppedAt  time.Time         `json:"stopped_at"`
+	Goroutines []LeakedGoroutine `json:"goroutines"`
+}
+
+// GoroutineAudit is the result of checking the actors' goroutines for leaks
+type GoroutineAudit struct {
+	GoroutineMetrics
+	Grace     time.Duration   `json:"grace" swaggertype:"integer"`
+	Leaks     []GoroutineLeak `json:"leaks"` // oldest stop first
+	AuditedAt time.Time       `json:"audited_at"`
+}
+
+// goroutineTracker counts the goroutines each actor starts and remembers when the actor
+// stopped, so the ones outliving Stop can be reported
+type goroutineTracker struct {
+	clock  Clock
+	nextID uint64
+	actors map[string]*trackedActor
+	mutex  sync.Mutex
+}
+
+// trackedActor is an actor with running goroutines, or one that has not stopped yet
+type trackedActor struct {
+	actorType  string
+	goroutines map[uint64]LeakedGoroutine
+	stoppedAt  time.Time
+}
+
+func newGoroutineTracker(clock Clock) *goroutineTracker {
+	return &goroutineTracker{
+		clock:  clock,
+		actors: make(map[string]*trackedActor),
+	}
+}
+
+// start records a goroutine started by an actor and returns its ID
+func (t *goroutineTracker) start(actorID, actorType, name string) uint64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	actor, ok := t.actors[actorID]
+	if !ok {
+		actor = &trackedActor{actorType: actorType, goroutines: make(map[uint64]LeakedGoroutine)}
+		t.actors[actorID] = actor
+	}
+	t.nextID++
+	actor.goroutines[t.nextID] = LeakedGoroutine{Name: name, StartedAt: t.clock.Now()}
+	return t.nextID
+}
+
+// finish records that a goroutine returned, forgetting its actor once stopped and idle
+func (t *goroutineTracker) finish(actorID string, id uint64) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	actor, ok := t.actors[actorID]
+	if !ok {
+		return
+	}
+	delete(actor.goroutines, id)
+	if !actor.stoppedAt.IsZero() && len(actor.goroutines) == 0 {
+		delete(t.actors, actorID)
+	}
+}
+
+// stop records that an actor stopped, forgetting it unless goroutines are still running
+func (t *goroutineTracker) stop(actorID string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	actor, ok := t.actors[actorID]
+	if !ok {
+		return
+	}
+	if len(actor.goroutines) == 0 {
+		delete(t.actors, actorID)
+		return
+	}
+	actor.stoppedAt = t.clock.Now()
+}
+
+// audit reports the actors whose goroutines outlived their Stop by more than grace
+func (t *goroutineTracker) audit(grace time.Duration) GoroutineAudit {
+	t.mutex.Lock()
+	now := t.clock.Now()
+	audit := GoroutineAudit{
+		GoroutineMetrics: GoroutineMetrics{Leaked: make(map[string]int)},
+		Grace:            grace,
+		Leaks:            []GoroutineLeak{},
+		AuditedAt:        now,
+	}
+	for actorID, actor := range t.actors {
+		audit.Tracked += len(actor.goroutines)
+		if actor.stoppedAt.IsZero() || now.Sub(actor.stoppedAt) < grace {
+			continue
+		}
+
+		leak := GoroutineLeak{ActorID: actorID, ActorType: actor.actorType, StoppedAt: actor.stoppedAt}
+		for _, goroutine := range actor.goroutines {
+			leak.Goroutines = append(leak.Goroutines, goroutine)
+		}
+		sort.Slice(leak.Goroutines, func(i, j int) bool { return leak.Goroutines[i].StartedAt.Before(leak.Goroutines[j].StartedAt) })
+		audit.Leaks = append(audit.Leaks, leak)
+		audit.Leaked[actor.actorType] += len(actor.goroutines)
+	}
+	t.mutex.Unlock()
+
+	audit.Total = runtime.NumGoroutine()
+	sort.Slice(audit.Leaks, func(i, j int) bool {
+		if !audit.Leaks[i].StoppedAt.Equal(audit.Leaks[j].StoppedAt) {
+			return audit.Leaks[i].StoppedAt.Before(audit.Leaks[j].StoppedAt)
+		}
+		return audit.Leaks[i].ActorID < audit.Leaks[j].ActorID
+	})
+	return audit
+}
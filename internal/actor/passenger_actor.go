@@ -37,6 +37,9 @@ type RequestRidePayload struct {
 	PickupAddr  string    `json:"pickup_address"`
 	DropoffAddr string    `json:"dropoff_address"`
 	RequestedAt time.Time `json:"requested_at"`
+
+	Preferences          *models.RidePreferences    `json:"preferences,omitempty"`
+	RequiredCapabilities []models.VehicleCapability `json:"required_capabilities,omitempty"`
 }
 
 type CancelRidePayload struct {
@@ -195,6 +198,12 @@ func (pa *PassengerActor) handleRideCompleted(message Message) error {
 
 	// Update passenger statistics
 	pa.passenger.TotalTrips++
+	RecordEffect(message, models.MessageEffect{
+		EntityType: models.EntityTypePassenger,
+		EntityID:   pa.passenger.ID.String(),
+		Action:     "trip_completed",
+		Changes:    map[string]interface{}{"total_trips": pa.passenger.TotalTrips, "trip_id": payload.TripID},
+	})
 
 	// Here you could:
 	// 1. Process payment
@@ -241,7 +250,8 @@ func (pa *PassengerActor) RequestRide(system *ActorSystem, pickup, dropoff model
 		RequestedAt: time.Now(),
 	}
 
-	message := NewBaseMessage(MsgTypeRequestRide, payload, pa.GetID())
+	message := NewBaseMessage(MsgTypeRequestRide, payload, pa.GetID()).
+		WithEntity(models.EntityTypePassenger, pa.passenger.ID.String())
 
 	// Send to trip matching service
 	if err := system.SendMessage("trip-matcher", message); err != nil {
@@ -268,7 +278,7 @@ func (pa *PassengerActor) CancelRide(system *ActorSystem, tripID, reason string)
 		CancelledAt: time.Now(),
 	}
 
-	message := NewBaseMessage(MsgTypeCancelRide, payload, pa.GetID())
+	message := NewBaseMessage(MsgTypeCancelRide, payload, pa.GetID()).WithEntity(models.EntityTypeTrip, tripID)
 
 	// Send to trip management service
 	if err := system.SendMessage("trip-manager", message); err != nil {
@@ -297,7 +307,7 @@ func (pa *PassengerActor) RateDriver(system *ActorSystem, tripID, driverID strin
 		RatedAt:  time.Now(),
 	}
 
-	message := NewBaseMessage(MsgTypeRateDriver, payload, pa.GetID())
+	message := NewBaseMessage(MsgTypeRateDriver, payload, pa.GetID()).WithEntity(models.EntityTypeTrip, tripID)
 
 	// Send to rating service
 	if err := system.SendMessage("rating-service", message); err != nil {
@@ -319,6 +329,26 @@ func (pa *PassengerActor) GetPassenger() *models.Passenger {
 	return pa.passenger
 }
 
+// Snapshot captures the passenger while the actor is passivated and releases it
+func (pa *PassengerActor) Snapshot() ([]byte, error) {
+	snapshot, err := json.Marshal(pa.passenger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot passenger: %w", err)
+	}
+	pa.passenger = nil
+	return snapshot, nil
+}
+
+// Restore brings the passenger back from a snapshot before the revived actor processes a message
+func (pa *PassengerActor) Restore(snapshot []byte) error {
+	passenger := &models.Passenger{}
+	if err := json.Unmarshal(snapshot, passenger); err != nil {
+		return fmt.Errorf("failed to restore passenger: %w", err)
+	}
+	pa.passenger = passenger
+	return nil
+}
+
 // unmarshalPayload is a helper function to unmarshal message payloads
 func (pa *PassengerActor) unmarshalPayload(payload interface{}, target interface{}) error {
 	// Convert payload to JSON bytes first
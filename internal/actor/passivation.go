@@ -0,0 +1,322 @@
+package actor
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/logging"
+)
+
+// Passivator snapshots the state of an actor while it is passivated. The snapshot is taken once
+// the actor has stopped and should release the state it captures; the state is restored before
+// the revived actor processes its first message. Neither may call the actor system.
+type Passivator interface {
+	Snapshot() ([]byte, error)
+	Restore(snapshot []byte) error
+}
+
+// WithPassivator snapshots the state of a spawned actor with p when it is passivated. Actors
+// spawned without one keep whatever state their handler holds while passivated.
+func WithPassivator(p Passivator) SpawnOption {
+	return func(ref *ActorRef) {
+		ref.passivator = p
+	}
+}
+
+// PassivationMetrics describes the passivation of the actors of one type
+type PassivationMetrics struct {
+	IdleTimeout  time.Duration `json:"idle_timeout"`
+	Passivated   int           `json:"passivated"`   // actors passivated now
+	Passivations int64         `json:"passivations"` // actors stopped after their idle timeout
+	Revivals     int64         `json:"revivals"`     // passivated actors restarted by a message
+	Failures     int64         `json:"failures"`     // snapshots or restores that failed
+}
+
+// passivatedActor is what is kept of a passivated actor to revive it
+type passivatedActor struct {
+	ref          ActorRef // without its actor
+	mailboxSize  int
+	handler      func(Message) error
+	snapshot     []byte
+	processed    []processedMessage // message IDs within the dedup window
+	passivatedAt time.Time
+}
+
+// passivationStats counts the passivations and revivals of one actor type
+type passivationStats struct {
+	passivations int64
+	revivals     int64
+	failures     int64
+}
+
+// passivationState is the idle timeouts and passivation counters of a system, by actor type. The
+// passivated actors themselves are kept with the live ones, under the system's actorsMutex.
+type passivationState struct {
+	defaultTimeout time.Duration
+	timeouts       map[string]time.Duration
+	stats          map[string]*passivationStats
+	mutex          sync.Mutex
+}
+
+func newPassivationState() *passivationState {
+	return &passivationState{
+		timeouts: make(map[string]time.Duration),
+		stats:    make(map[string]*passivationStats),
+	}
+}
+
+// timeout returns the idle timeout of an actor type. The caller holds the mutex.
+func (p *passivationState) timeout(actorType string) time.Duration {
+	if timeout, ok := p.timeouts[actorType]; ok {
+		return timeout
+	}
+	return p.defaultTimeout
+}
+
+// idleTimeout returns the idle timeout of an actor type
+func (p *passivationState) idleTimeout(actorType string) time.Duration {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.timeout(actorType)
+}
+
+// enabled reports whether the actors of any type are passivated
+func (p *passivationState) enabled() bool {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.defaultTimeout > 0 {
+		return true
+	}
+	for _, timeout := range p.timeouts {
+		if timeout > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// record updates the counters of an actor type
+func (p *passivationState) record(actorType string, update func(*passivationStats)) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	stats, ok := p.stats[actorType]
+	if !ok {
+		stats = &passivationStats{}
+		p.stats[actorType] = stats
+	}
+	update(stats)
+}
+
+// SetDefaultIdleTimeout sets how long actors of types without their own timeout may stay idle
+// before they are passivated: stopped, with their state snapshotted, until the next message
+// revives them. Pooled actors are never passivated. Idle actors are found with every metrics
+// collection, so they may stay idle up to that interval longer. Zero, the default, disables it.
+func (s *ActorSystem) SetDefaultIdleTimeout(timeout time.Duration) {
+	s.passivation.mutex.Lock()
+	defer s.passivation.mutex.Unlock()
+	s.passivation.defaultTimeout = timeout
+}
+
+// SetIdleTimeout sets the idle timeout of one actor type. Zero disables passivation for the type.
+func (s *ActorSystem) SetIdleTimeout(actorType string, timeout time.Duration) {
+	s.passivation.mutex.Lock()
+	defer s.passivation.mutex.Unlock()
+	s.passivation.timeouts[actorType] = timeout
+}
+
+// HasActor reports whether an actor is in the system, alive or passivated
+func (s *ActorSystem) HasActor(actorID string) bool {
+	s.actorsMutex.RLock()
+	defer s.actorsMutex.RUnlock()
+
+	_, alive := s.actors[actorID]
+	_, passivated := s.passivated[actorID]
+	return alive || passivated
+}
+
+// IsPassivated reports whether an actor is passivated
+func (s *ActorSystem) IsPassivated(actorID string) bool {
+	s.actorsMutex.RLock()
+	defer s.actorsMutex.RUnlock()
+
+	_, passivated := s.passivated[actorID]
+	return passivated
+}
+
+// PassivateIdle passivates the actors idle for longer than the timeout of their type and returns
+// their IDs. An actor is idle while its mailbox is empty and it is not processing a message.
+// It runs with every metrics collection.
+func (s *ActorSystem) PassivateIdle() []string {
+	if !s.passivation.enabled() {
+		return nil
+	}
+
+	now := s.clock.Now()
+	var passivated []*ActorRef
+	var failed []string
+
+	s.actorsMutex.Lock()
+	var candidates []*ActorRef
+	for _, actorRef := range s.actors {
+		idle := s.passivation.idleTimeout(actorRef.Type)
+		if idle <= 0 || s.isPooled(actorRef.ID) {
+			continue
+		}
+		if now.Sub(actorRef.Actor.GetMetrics().LastActivity) >= idle {
+			candidates = append(candidates, actorRef)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+
+	for _, actorRef := range candidates {
+		actor, ok := actorRef.Actor.(*BaseActor)
+		if !ok || !actor.passivate() {
+			continue
+		}
+
+		record := &passivatedActor{
+			ref:          *actorRef,
+			mailboxSize:  cap(actor.mailbox),
+			handler:      actor.handler,
+			processed:    actor.processedMessages(),
+			passivatedAt: now,
+		}
+		record.ref.Actor = nil
+		if actorRef.passivator != nil {
+			snapshot, err := actorRef.passivator.Snapshot()
+			if err != nil {
+				// The state is still held by the passivator, which the record keeps
+				s.logger.WithError(err).WithField("actor_id", actorRef.ID).Warn("Failed to snapshot passivated actor")
+				failed = append(failed, actorRef.Type)
+			}
+			record.snapshot = snapshot
+		}
+
+		delete(s.actors, actorRef.ID)
+		s.typeCounts[actorRef.Type]--
+		s.passivated[actorRef.ID] = record
+		s.forgetHeartbeat(actorRef.ID)
+		passivated = append(passivated, actorRef)
+	}
+	s.actorsMutex.Unlock()
+
+	for _, actorType := range failed {
+		s.passivation.record(actorType, func(stats *passivationStats) { stats.failures++ })
+	}
+	ids := make([]string, len(passivated))
+	for i, actorRef := range passivated {
+		ids[i] = actorRef.ID
+		s.passivation.record(actorRef.Type, func(stats *passivationStats) { stats.passivations++ })
+		s.logger.WithFields(logging.Fields{
+			"actor_id":   actorRef.ID,
+			"actor_type": actorRef.Type,
+		}).Debug("Idle actor passivated")
+	}
+	if len(passivated) > 0 {
+		s.updateMetrics(func(m *SystemMetrics) {
+			m.ActiveActors -= len(passivated)
+		})
+	}
+
+	return ids
+}
+
+// liveActor returns an actor to deliver a message to, reviving it if it is passivated
+func (s *ActorSystem) liveActor(actorID string) (*ActorRef, error) {
+	if actorRef, err := s.GetActor(actorID); err == nil {
+		return actorRef, nil
+	}
+	return s.revive(actorID)
+}
+
+// revive restarts a passivated actor with its state and the message IDs it processed within the
+// dedup window. It returns ErrActorNotFound for an actor that is neither alive nor passivated.
+func (s *ActorSystem) revive(actorID string) (*ActorRef, error) {
+	start := time.Now()
+
+	s.actorsMutex.Lock()
+	if actorRef, exists := s.actors[actorID]; exists {
+		// Revived by another sender meanwhile
+		s.actorsMutex.Unlock()
+		return actorRef, nil
+	}
+	record, ok := s.passivated[actorID]
+	if !ok {
+		s.actorsMutex.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrActorNotFound, actorID)
+	}
+
+	actorRef := record.ref
+	if actorRef.passivator != nil && record.snapshot != nil {
+		if err := actorRef.passivator.Restore(record.snapshot); err != nil {
+			s.actorsMutex.Unlock()
+			s.passivation.record(actorRef.Type, func(stats *passivationStats) { stats.failures++ })
+			return nil, fmt.Errorf("failed to restore passivated actor %s: %w", actorID, err)
+		}
+	}
+	if err := s.startActor(&actorRef, record.mailboxSize, record.handler, record.processed); err != nil {
+		s.actorsMutex.Unlock()
+		return nil, err
+	}
+	delete(s.passivated, actorID)
+	s.actorsMutex.Unlock()
+	s.recordSpawn(actorRef.Type, time.Since(start))
+
+	s.passivation.record(actorRef.Type, func(stats *passivationStats) { stats.revivals++ })
+	s.updateMetrics(func(m *SystemMetrics) {
+		m.ActiveActors++
+	})
+	s.logger.WithFields(logging.Fields{
+		"actor_id":   actorID,
+		"actor_type": actorRef.Type,
+		"idle_for":   s.clock.Since(record.passivatedAt).String(),
+	}).Debug("Passivated actor revived")
+
+	return &actorRef, nil
+}
+
+// forgetPassivated drops a passivated actor along with its state, reporting whether there was one.
+// The caller holds actorsMutex.
+func (s *ActorSystem) forgetPassivated(actorID string) bool {
+	if _, ok := s.passivated[actorID]; !ok {
+		return false
+	}
+	delete(s.passivated, actorID)
+	return true
+}
+
+// passivationMetrics returns the passivation metrics by actor type
+func (s *ActorSystem) passivationMetrics() map[string]PassivationMetrics {
+	s.actorsMutex.RLock()
+	counts := make(map[string]int)
+	for _, record := range s.passivated {
+		counts[record.ref.Type]++
+	}
+	s.actorsMutex.RUnlock()
+
+	s.passivation.mutex.Lock()
+	defer s.passivation.mutex.Unlock()
+
+	metrics := make(map[string]PassivationMetrics)
+	add := func(actorType string) {
+		if _, ok := metrics[actorType]; ok {
+			return
+		}
+		m := PassivationMetrics{IdleTimeout: s.passivation.timeout(actorType), Passivated: counts[actorType]}
+		if stats, ok := s.passivation.stats[actorType]; ok {
+			m.Passivations, m.Revivals, m.Failures = stats.passivations, stats.revivals, stats.failures
+		}
+		metrics[actorType] = m
+	}
+	for actorType := range s.passivation.stats {
+		add(actorType)
+	}
+	for actorType := range counts {
+		add(actorType)
+	}
+	return metrics
+}
@@ -0,0 +1,315 @@
+package actor
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrActorLimitReached is returned when spawning an actor would exceed the limit of its type
+var ErrActorLimitReached = errors.New("actor limit reached")
+
+// Defaults for actors acquired lazily for a type without a warm pool
+const (
+	defaultPoolMailboxSize = 100
+	defaultPoolStrategy    = SupervisionRestart
+)
+
+// SpawnMetrics describes the actors spawned of one type
+type SpawnMetrics struct {
+	Spawned        int64         `json:"spawned"`
+	Rejected       int64         `json:"rejected"` // spawns refused by the type's limit
+	Active         int           `json:"active"`
+	Limit          int           `json:"limit,omitempty"`
+	AverageLatency time.Duration `json:"average_latency"`
+	MaxLatency     time.Duration `json:"max_latency"`
+}
+
+// PoolMetrics describes the warm pool of one actor type
+type PoolMetrics struct {
+	Size        int     `json:"size"` // pooled actors, idle or in use
+	InUse       int     `json:"in_use"`
+	Utilization float64 `json:"utilization"` // fraction of the pool in use
+	Hits        int64   `json:"hits"`        // acquisitions served by an idle pooled actor
+	Misses      int64   `json:"misses"`      // acquisitions that spawned an actor
+}
+
+// spawnStats accumulates the spawn metrics of one actor type
+type spawnStats struct {
+	spawned      int64
+	rejected     int64
+	totalLatency time.Duration
+	maxLatency   time.Duration
+}
+
+// actorPool holds pre-spawned actors of one type. Pooled actors are spawned with a handler
+// delegating to the one set by whoever acquired them, so they can be reused for other entities.
+type actorPool struct {
+	mailboxSize int
+	strategy    SupervisionStrategy
+	members     map[string]bool
+	idle        []string
+	handlers    map[string]func(Message) error
+	hits        int64
+	misses      int64
+}
+
+// poolState is the warm pools, limits and spawn statistics of a system, by actor type
+type poolState struct {
+	pools  map[string]*actorPool
+	limits map[string]int
+	spawns map[string]*spawnStats
+	mutex  sync.Mutex
+}
+
+func newPoolState() *poolState {
+	return &poolState{
+		pools:  make(map[string]*actorPool),
+		limits: make(map[string]int),
+		spawns: make(map[string]*spawnStats),
+	}
+}
+
+// stats returns the spawn statistics of a type, creating them if needed. The caller holds the mutex.
+func (p *poolState) stats(actorType string) *spawnStats {
+	stats, ok := p.spawns[actorType]
+	if !ok {
+		stats = &spawnStats{}
+		p.spawns[actorType] = stats
+	}
+	return stats
+}
+
+// SetActorLimit limits the number of actors of a type alive at once, pooled ones included.
+// Spawning beyond it fails with ErrActorLimitReached. Zero removes the limit.
+func (s *ActorSystem) SetActorLimit(actorType string, max int) {
+	s.pools.mutex.Lock()
+	defer s.pools.mutex.Unlock()
+	if max <= 0 {
+		delete(s.pools.limits, actorType)
+		return
+	}
+	s.pools.limits[actorType] = max
+}
+
+// WarmPool pre-spawns size idle actors of a type, which AcquireActor hands out before
+// spawning new ones. The system must be started. Calling it again grows the pool to size.
+func (s *ActorSystem) WarmPool(actorType string, size, mailboxSize int, strategy SupervisionStrategy) error {
+	if !s.IsStarted() {
+		return fmt.Errorf("actor system is not started")
+	}
+
+	s.pools.mutex.Lock()
+	pool, ok := s.pools.pools[actorType]
+	if !ok {
+		pool = &actorPool{
+			mailboxSize: mailboxSize,
+			strategy:    strategy,
+			members:     make(map[string]bool),
+			handlers:    make(map[string]func(Message) error),
+		}
+		s.pools.pools[actorType] = pool
+	}
+	missing := size - len(pool.members)
+	s.pools.mutex.Unlock()
+
+	for i := 0; i < missing; i++ {
+		actorID := fmt.Sprintf("%s-pool-%s", actorType, uuid.New().String())
+		if _, err := s.SpawnActor(actorType, actorID, pool.mailboxSize, s.pooledHandler(pool, actorID), pool.strategy); err != nil {
+			return fmt.Errorf("failed to warm %s pool: %w", actorType, err)
+		}
+
+		s.pools.mutex.Lock()
+		pool.members[actorID] = true
+		pool.idle = append(pool.idle, actorID)
+		s.pools.mutex.Unlock()
+	}
+	return nil
+}
+
+// pooledHandler returns the handler of a pooled actor, which passes messages to the handler of
+// its current holder and drops them while the actor is idle
+func (s *ActorSystem) pooledHandler(pool *actorPool, actorID string) func(Message) error {
+	return func(message Message) error {
+		s.pools.mutex.Lock()
+		handler := pool.handlers[actorID]
+		s.pools.mutex.Unlock()
+		if handler == nil {
+			return nil
+		}
+		return handler(message)
+	}
+}
+
+// AcquireActor returns an actor of a type handling messages with handler: an idle actor from
+// the type's warm pool when there is one, otherwise a newly spawned actor. Release it with
+// ReleaseActor once done.
+func (s *ActorSystem) AcquireActor(actorType string, handler func(Message) error, opts ...SpawnOption) (*ActorRef, error) {
+	s.pools.mutex.Lock()
+	pool, ok := s.pools.pools[actorType]
+	if ok && len(pool.idle) > 0 {
+		actorID := pool.idle[len(pool.idle)-1]
+		pool.idle = pool.idle[:len(pool.idle)-1]
+		pool.handlers[actorID] = handler
+		pool.hits++
+		s.pools.mutex.Unlock()
+
+		s.actorsMutex.Lock()
+		defer s.actorsMutex.Unlock()
+		actorRef, exists := s.actors[actorID]
+		if !exists {
+			return nil, fmt.Errorf("pooled actor %s not found", actorID)
+		}
+		for _, opt := range opts {
+			opt(actorRef)
+		}
+		return actorRef, nil
+	}
+
+	mailboxSize, strategy := defaultPoolMailboxSize, defaultPoolStrategy
+	if ok {
+		pool.misses++
+		mailboxSize, strategy = pool.mailboxSize, pool.strategy
+	}
+	s.pools.mutex.Unlock()
+
+	actorID := fmt.Sprintf("%s-%s", actorType, uuid.New().String())
+	return s.SpawnActor(actorType, actorID, mailboxSize, handler, strategy, opts...)
+}
+
+// ReleaseActor gives back an actor obtained from AcquireActor. Pooled actors return to their
+// pool idle, while actors spawned on demand are stopped.
+func (s *ActorSystem) ReleaseActor(actorID string) error {
+	s.pools.mutex.Lock()
+	for _, pool := range s.pools.pools {
+		if !pool.members[actorID] {
+			continue
+		}
+		if _, held := pool.handlers[actorID]; !held {
+			s.pools.mutex.Unlock()
+			return fmt.Errorf("actor %s is not acquired", actorID)
+		}
+		delete(pool.handlers, actorID)
+		pool.idle = append(pool.idle, actorID)
+		s.pools.mutex.Unlock()
+
+		// Forget the entity and state of the previous holder
+		s.actorsMutex.Lock()
+		if actorRef, exists := s.actors[actorID]; exists {
+			actorRef.EntityType, actorRef.EntityID = "", ""
+			actorRef.passivator = nil
+		}
+		s.actorsMutex.Unlock()
+		return nil
+	}
+	s.pools.mutex.Unlock()
+
+	return s.StopActor(actorID)
+}
+
+// reserveSpawn checks a spawn against the limit of its type. The caller holds actorsMutex, so
+// the count of live actors cannot change before the spawn is recorded.
+func (s *ActorSystem) reserveSpawn(actorType string) error {
+	s.pools.mutex.Lock()
+	defer s.pools.mutex.Unlock()
+
+	limit, ok := s.pools.limits[actorType]
+	if ok && s.typeCounts[actorType] >= limit {
+		s.pools.stats(actorType).rejected++
+		return fmt.Errorf("%w: %d %s actors", ErrActorLimitReached, limit, actorType)
+	}
+	return nil
+}
+
+// recordSpawn records how long spawning an actor of a type took
+func (s *ActorSystem) recordSpawn(actorType string, latency time.Duration) {
+	s.pools.mutex.Lock()
+	defer s.pools.mutex.Unlock()
+
+	stats := s.pools.stats(actorType)
+	stats.spawned++
+	stats.totalLatency += latency
+	if latency > stats.maxLatency {
+		stats.maxLatency = latency
+	}
+}
+
+// forgetPooled removes a stopped actor from its pool
+func (s *ActorSystem) forgetPooled(actorID string) {
+	s.pools.mutex.Lock()
+	defer s.pools.mutex.Unlock()
+
+	for _, pool := range s.pools.pools {
+		if !pool.members[actorID] {
+			continue
+		}
+		delete(pool.members, actorID)
+		delete(pool.handlers, actorID)
+		for i, id := range pool.idle {
+			if id == actorID {
+				pool.idle = append(pool.idle[:i], pool.idle[i+1:]...)
+				break
+			}
+		}
+		return
+	}
+}
+
+// isPooled reports whether an actor belongs to a warm pool
+func (s *ActorSystem) isPooled(actorID string) bool {
+	s.pools.mutex.Lock()
+	defer s.pools.mutex.Unlock()
+
+	for _, pool := range s.pools.pools {
+		if pool.members[actorID] {
+			return true
+		}
+	}
+	return false
+}
+
+// poolMetrics returns the spawn and pool metrics by actor type
+func (s *ActorSystem) poolMetrics() (map[string]SpawnMetrics, map[string]PoolMetrics) {
+	s.actorsMutex.RLock()
+	counts := make(map[string]int, len(s.typeCounts))
+	for actorType, count := range s.typeCounts {
+		counts[actorType] = count
+	}
+	s.actorsMutex.RUnlock()
+
+	s.pools.mutex.Lock()
+	defer s.pools.mutex.Unlock()
+
+	spawns := make(map[string]SpawnMetrics, len(s.pools.spawns))
+	for actorType, stats := range s.pools.spawns {
+		metrics := SpawnMetrics{
+			Spawned:    stats.spawned,
+			Rejected:   stats.rejected,
+			Active:     counts[actorType],
+			Limit:      s.pools.limits[actorType],
+			MaxLatency: stats.maxLatency,
+		}
+		if stats.spawned > 0 {
+			metrics.AverageLatency = stats.totalLatency / time.Duration(stats.spawned)
+		}
+		spawns[actorType] = metrics
+	}
+
+	pools := make(map[string]PoolMetrics, len(s.pools.pools))
+	for actorType, pool := range s.pools.pools {
+		metrics := PoolMetrics{
+			Size:   len(pool.members),
+			InUse:  len(pool.members) - len(pool.idle),
+			Hits:   pool.hits,
+			Misses: pool.misses,
+		}
+		if metrics.Size > 0 {
+			metrics.Utilization = float64(metrics.InUse) / float64(metrics.Size)
+		}
+		pools[actorType] = metrics
+	}
+	return spawns, pools
+}
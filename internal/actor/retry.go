@@ -0,0 +1,59 @@
+package actor
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"actor-model-observability/internal/models"
+)
+
+// RetryPolicy controls how a message whose handler failed is redelivered before it is dead-lettered
+type RetryPolicy struct {
+	MaxAttempts    int           // total processing attempts; 1 or less dead-letters on the first failure
+	InitialBackoff time.Duration // delay before the first retry
+	MaxBackoff     time.Duration // caps the delay between retries; 0 leaves it uncapped
+	Multiplier     float64       // backoff growth per attempt; values below 1 are treated as 2
+	Jitter         float64       // fraction of the backoff randomized in either direction, from 0 to 1
+}
+
+// NoRetry dead-letters a message as soon as its handler fails
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// Backoff returns the delay before retrying a message that has failed attempt times
+func (p RetryPolicy) Backoff(attempt int, rng *rand.Rand) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 && rng != nil {
+		backoff += backoff * p.Jitter * (2*rng.Float64() - 1)
+	}
+
+	return time.Duration(backoff)
+}
+
+// MessageFailure describes a failed processing attempt reported to the message failure handler
+type MessageFailure struct {
+	ActorID      string
+	ActorType    string
+	Message      Message
+	Attempt      int // 1 for the first delivery
+	Err          error
+	RetryIn      time.Duration         // delay before the next attempt, when not dead-lettered
+	DeadLettered bool                  // the policy's attempts are exhausted and the message is dropped
+	Effects      models.MessageEffects // changes the handler made before it failed
+}
+
+// Retries returns how many times the message had been retried when this attempt failed
+func (f MessageFailure) Retries() int {
+	return f.Attempt - 1
+}
@@ -0,0 +1,161 @@
+package actor
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// InitialMessageVersion is the schema version of a message type without up-converters, and
+// the version assumed for envelopes persisted before messages were versioned
+const InitialMessageVersion = 1
+
+// MessageConverter rewrites a payload from one schema version of its message type to the next
+type MessageConverter func(payload json.RawMessage) (json.RawMessage, error)
+
+// MessageConversion counts the payloads upgraded from one schema version to the next
+type MessageConversion struct {
+	MessageType string `json:"message_type"`
+	FromVersion int    `json:"from_version"`
+	ToVersion   int    `json:"to_version"`
+	Count       int64  `json:"count"`
+}
+
+// conversionKey identifies an up-converter
+type conversionKey struct {
+	messageType string
+	from        int
+}
+
+// MessageSchemas holds the schema versions of message payloads and the up-converters between
+// them, so payloads persisted with an older version can still be processed after the message
+// structs change
+type MessageSchemas struct {
+	converters  map[conversionKey]MessageConverter
+	versions    map[string]int // current version by message type
+	conversions map[conversionKey]int64
+	mutex       sync.RWMutex
+}
+
+// NewMessageSchemas creates a registry where every message type is at its initial version
+func NewMessageSchemas() *MessageSchemas {
+	return &MessageSchemas{
+		converters:  make(map[conversionKey]MessageConverter),
+		versions:    make(map[string]int),
+		conversions: make(map[conversionKey]int64),
+	}
+}
+
+// Register adds the up-converter from version from of a message type to version from+1, which
+// becomes the current version. Converters are registered in order, from the current version.
+func (s *MessageSchemas) Register(messageType string, from int, convert MessageConverter) error {
+	if convert == nil {
+		return fmt.Errorf("converter for %s version %d is nil", messageType, from)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if current := s.version(messageType); from != current {
+		return fmt.Errorf("converter for %s must upgrade from current version %d, not %d", messageType, current, from)
+	}
+	s.converters[conversionKey{messageType: messageType, from: from}] = convert
+	s.versions[messageType] = from + 1
+	return nil
+}
+
+// Version returns the current schema version of a message type
+func (s *MessageSchemas) Version(messageType string) int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.version(messageType)
+}
+
+func (s *MessageSchemas) version(messageType string) int {
+	if version, ok := s.versions[messageType]; ok {
+		return version
+	}
+	return InitialMessageVersion
+}
+
+// Upgrade converts a payload of the given version to the current version of its message type,
+// one version at a time, and returns it with the version reached
+func (s *MessageSchemas) Upgrade(messageType string, version int, payload json.RawMessage) (json.RawMessage, int, error) {
+	if version == 0 {
+		version = InitialMessageVersion
+	}
+
+	s.mutex.RLock()
+	current := s.version(messageType)
+	s.mutex.RUnlock()
+
+	if version > current {
+		return nil, version, fmt.Errorf("%s version %d is newer than the current version %d", messageType, version, current)
+	}
+
+	for ; version < current; version++ {
+		key := conversionKey{messageType: messageType, from: version}
+		s.mutex.RLock()
+		convert := s.converters[key]
+		s.mutex.RUnlock()
+
+		upgraded, err := convert(payload)
+		if err != nil {
+			return nil, version, fmt.Errorf("failed to upgrade %s from version %d to %d: %w", messageType, version, version+1, err)
+		}
+		payload = upgraded
+
+		s.mutex.Lock()
+		s.conversions[key]++
+		s.mutex.Unlock()
+	}
+	return payload, current, nil
+}
+
+// Decode reads a persisted message envelope, such as one kept by a durable mailbox or replayed
+// from the message log, and upgrades its payload to the current version. The payload is left
+// as raw JSON for the receiving actor to unmarshal.
+func (s *MessageSchemas) Decode(data []byte) (*BaseMessage, error) {
+	var envelope struct {
+		BaseMessage
+		Payload json.RawMessage `json:"payload"`
+	}
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal message envelope: %w", err)
+	}
+
+	payload, version, err := s.Upgrade(envelope.Type, envelope.Version, envelope.Payload)
+	if err != nil {
+		return nil, err
+	}
+
+	message := envelope.BaseMessage
+	message.Payload = payload
+	message.Version = version
+	return &message, nil
+}
+
+// Conversions returns the number of payloads upgraded by each converter, by message type and
+// version
+func (s *MessageSchemas) Conversions() []MessageConversion {
+	s.mutex.RLock()
+	conversions := make([]MessageConversion, 0, len(s.conversions))
+	for key, count := range s.conversions {
+		conversions = append(conversions, MessageConversion{
+			MessageType: key.messageType,
+			FromVersion: key.from,
+			ToVersion:   key.from + 1,
+			Count:       count,
+		})
+	}
+	s.mutex.RUnlock()
+
+	sort.Slice(conversions, func(i, j int) bool {
+		if conversions[i].MessageType != conversions[j].MessageType {
+			return conversions[i].MessageType < conversions[j].MessageType
+		}
+		return conversions[i].FromVersion < conversions[j].FromVersion
+	})
+	return conversions
+}
@@ -2,11 +2,19 @@ package actor
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/rand"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"actor-model-observability/internal/deadline"
+	"actor-model-observability/internal/hlc"
 	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
 )
 
 // SupervisionStrategy defines how to handle actor failures
@@ -18,12 +26,39 @@ const (
 	SupervisionIgnore  SupervisionStrategy = "ignore"
 )
 
+// metricsInterval is how often system metrics, heartbeats and idle actors are checked
+const metricsInterval = 5 * time.Second
+
+// ErrHeartbeatExpired is reported to the actor failure handler when an actor misses its heartbeat
+var ErrHeartbeatExpired = errors.New("actor heartbeat expired")
+
+// ErrActorNotFound is returned for an actor that is not in the system
+var ErrActorNotFound = errors.New("actor not found")
+
 // ActorRef represents a reference to an actor
 type ActorRef struct {
 	ID       string
 	Type     string
 	Actor    Actor
 	Strategy SupervisionStrategy
+
+	// Business entity the actor represents, such as the passenger of a passenger actor
+	EntityType string
+	EntityID   string
+
+	// Snapshots the actor's state while passivated; nil keeps it in the handler
+	passivator Passivator
+}
+
+// SpawnOption configures an actor spawned by the system
+type SpawnOption func(*ActorRef)
+
+// ForEntity links a spawned actor to the business entity it represents
+func ForEntity(entityType, entityID string) SpawnOption {
+	return func(ref *ActorRef) {
+		ref.EntityType = entityType
+		ref.EntityID = entityID
+	}
 }
 
 // SystemMetrics holds metrics for the entire actor system
@@ -33,14 +68,31 @@ type SystemMetrics struct {
 	TotalMessages     int64         `json:"total_messages"`
 	MessagesPerSecond float64       `json:"messages_per_second"`
 	AverageLatency    time.Duration `json:"average_latency"`
+	DuplicateMessages int64         `json:"duplicate_messages"`
+	RetriedMessages   int64         `json:"retried_messages"`
+	DeadLetters       int64         `json:"dead_letters"`
 	SystemUptime      time.Duration `json:"system_uptime"`
 	LastMetricsUpdate time.Time     `json:"last_metrics_update"`
+
+	// Spawning and warm pools, by actor type
+	Spawns map[string]SpawnMetrics `json:"spawns,omitempty"`
+	Pools  map[string]PoolMetrics  `json:"pools,omitempty"`
+
+	// Goroutines as of the last leak audit
+	Goroutines GoroutineMetrics `json:"goroutines"`
+
+	// Payloads upgraded to a newer schema version, by message type and version
+	MessageConversions []MessageConversion `json:"message_conversions,omitempty"`
+
+	// Idle actors passivated and revived, by actor type
+	Passivation map[string]PassivationMetrics `json:"passivation,omitempty"`
 }
 
 // ActorSystem manages a collection of actors
 type ActorSystem struct {
 	name         string
 	actors       map[string]*ActorRef
+	typeCounts   map[string]int // live actors by type
 	actorsMutex  sync.RWMutex
 	ctx          context.Context
 	cancel       context.CancelFunc
@@ -51,26 +103,107 @@ type ActorSystem struct {
 	wg           sync.WaitGroup
 	started      bool
 	startedMutex sync.RWMutex
+	clock        Clock
+	dedupWindow  time.Duration
+
+	// Duplicates dropped by the actors, including those since stopped or passivated
+	duplicates atomic.Int64
+
+	// Deterministic scheduling (simulation mode only)
+	simulated    bool
+	runQueue     []string
+	queueMutex   sync.Mutex
+	metricsTimer Timer
+
+	// Retries of failed messages, keyed by receiving actor and message ID
+	defaultRetry  RetryPolicy
+	retryPolicies map[string]RetryPolicy
+	attempts      map[string]int
+	retryRand     *rand.Rand
+	retryMutex    sync.Mutex
+
+	// Warm pools and per-type limits
+	pools *poolState
+
+	// Idle timeouts, and the actors passivated after them, under actorsMutex
+	passivation *passivationState
+	passivated  map[string]*passivatedActor
+
+	// Schema versions of message payloads
+	schemas *MessageSchemas
+
+	// Hybrid clock stamping sent messages; nil leaves them unstamped
+	hybridClock *hlc.Clock
+
+	// Goroutines outliving their actor's Stop
+	goroutines     *goroutineTracker
+	leakGrace      time.Duration
+	reportedLeaks  map[string]bool // leaked actors already logged
+	goroutineMutex sync.Mutex
+
+	// Heartbeat expiry
+	heartbeatTimeout time.Duration
+	heartbeats       map[string]time.Time
+	expired          map[string]bool
+	heartbeatMutex   sync.Mutex
 
 	// Event handlers
 	onActorStarted func(actorID string)
 	onActorStopped func(actorID string)
 	onActorFailed  func(actorID string, err error)
 	onMessage      func(from, to, messageType string)
+	onMessageFail  func(failure MessageFailure)
+	onMessageDone  func(result MessageResult)
 }
 
 // NewActorSystem creates a new actor system
 func NewActorSystem(name string) *ActorSystem {
+	return newActorSystem(name, RealClock{})
+}
+
+// NewSimulatedActorSystem creates an actor system for deterministic tests. Time only moves
+// when AdvanceTime is called, and messages are processed one at a time, in send order,
+// by Step, RunUntilIdle and AdvanceTime instead of per-actor goroutines.
+func NewSimulatedActorSystem(name string, clock *SimulatedClock) *ActorSystem {
+	s := newActorSystem(name, clock)
+	s.simulated = true
+	// Fixed seed so retry jitter is reproducible
+	s.retryRand = rand.New(rand.NewSource(1))
+	return s
+}
+
+func newActorSystem(name string, clock Clock) *ActorSystem {
 	return &ActorSystem{
-		name:   name,
-		actors: make(map[string]*ActorRef),
-		logger: logging.GetGlobalLogger().WithComponent("actor_system").WithField("system", name),
+		name:          name,
+		actors:        make(map[string]*ActorRef),
+		typeCounts:    make(map[string]int),
+		pools:         newPoolState(),
+		passivation:   newPassivationState(),
+		passivated:    make(map[string]*passivatedActor),
+		schemas:       NewMessageSchemas(),
+		goroutines:    newGoroutineTracker(clock),
+		leakGrace:     DefaultGoroutineLeakGrace,
+		reportedLeaks: make(map[string]bool),
+		logger:        logging.GetGlobalLogger().WithComponent("actor_system").WithField("system", name),
+		clock:         clock,
+		dedupWindow:   DefaultDedupWindow,
+		defaultRetry:  NoRetry,
+		retryPolicies: make(map[string]RetryPolicy),
+		attempts:      make(map[string]int),
+		retryRand:     rand.New(rand.NewSource(time.Now().UnixNano())),
+		heartbeats:    make(map[string]time.Time),
+		expired:       make(map[string]bool),
 		metrics: SystemMetrics{
-			LastMetricsUpdate: time.Now(),
+			LastMetricsUpdate: clock.Now(),
 		},
 	}
 }
 
+// Clock returns the clock used by the actor system
+func (s *ActorSystem) Clock() Clock {
+	return s.clock
+}
+
 // Start initializes and starts the actor system
 func (s *ActorSystem) Start(ctx context.Context) error {
 	s.startedMutex.Lock()
@@ -82,17 +215,30 @@ func (s *ActorSystem) Start(ctx context.Context) error {
 	}
 
 	s.ctx, s.cancel = context.WithCancel(ctx)
-	s.startTime = time.Now()
+	s.startTime = s.clock.Now()
 	s.started = true
 
-	// Start metrics collection goroutine
-	s.wg.Add(1)
-	go s.metricsCollector()
+	if s.simulated {
+		// Metrics collection is driven by the simulated clock
+		s.scheduleMetricsTick(0, s.startTime)
+	} else {
+		// Start metrics collection goroutine
+		s.wg.Add(1)
+		go s.metricsCollector()
+	}
 
 	s.logger.Info("Actor system started")
 	return nil
 }
 
+// SetDedupWindow sets how long actors spawned afterwards remember processed message IDs,
+// dropping redelivered copies within that window. Zero disables deduplication.
+func (s *ActorSystem) SetDedupWindow(window time.Duration) {
+	s.actorsMutex.Lock()
+	defer s.actorsMutex.Unlock()
+	s.dedupWindow = window
+}
+
 // IsStarted returns whether the actor system is currently started
 func (s *ActorSystem) IsStarted() bool {
 	s.startedMutex.RLock()
@@ -116,6 +262,9 @@ func (s *ActorSystem) Stop() error {
 	if s.cancel != nil {
 		s.cancel()
 	}
+	if s.metricsTimer != nil {
+		s.metricsTimer.Stop()
+	}
 
 	// Stop all actors
 	s.actorsMutex.Lock()
@@ -134,36 +283,38 @@ func (s *ActorSystem) Stop() error {
 	return nil
 }
 
-// SpawnActor creates and starts a new actor
-func (s *ActorSystem) SpawnActor(actorType, actorID string, mailboxSize int, handler func(Message) error, strategy SupervisionStrategy) (*ActorRef, error) {
+// SpawnActor creates and starts a new actor. Spawning an actor that is passivated replaces it,
+// dropping its state.
+func (s *ActorSystem) SpawnActor(actorType, actorID string, mailboxSize int, handler func(Message) error, strategy SupervisionStrategy, opts ...SpawnOption) (*ActorRef, error) {
 	if actorID == "" {
 		return nil, fmt.Errorf("actor ID cannot be empty")
 	}
 
-	s.actorsMutex.Lock()
-	defer s.actorsMutex.Unlock()
-
-	// Check if actor already exists
-	if _, exists := s.actors[actorID]; exists {
-		return nil, fmt.Errorf("actor with ID %s already exists", actorID)
-	}
-
-	// Create new actor
-	actor := NewBaseActor(actorID, actorType, mailboxSize, handler)
+	start := time.Now()
 	actorRef := &ActorRef{
 		ID:       actorID,
 		Type:     actorType,
-		Actor:    actor,
 		Strategy: strategy,
 	}
-
-	// Start the actor
-	if err := actor.Start(s.ctx); err != nil {
-		return nil, fmt.Errorf("failed to start actor %s: %w", actorID, err)
+	for _, opt := range opts {
+		opt(actorRef)
 	}
 
-	// Add to actors map
-	s.actors[actorID] = actorRef
+	// The lock is released before the started handler runs, so that it can look the actor up
+	s.actorsMutex.Lock()
+
+	// Check if actor already exists
+	if _, exists := s.actors[actorID]; exists {
+		s.actorsMutex.Unlock()
+		return nil, fmt.Errorf("actor with ID %s already exists", actorID)
+	}
+	if err := s.startActor(actorRef, mailboxSize, handler, nil); err != nil {
+		s.actorsMutex.Unlock()
+		return nil, err
+	}
+	replaced := s.forgetPassivated(actorID)
+	s.actorsMutex.Unlock()
+	s.recordSpawn(actorType, time.Since(start))
 
 	// Update metrics
 	s.updateMetrics(func(m *SystemMetrics) {
@@ -176,14 +327,52 @@ func (s *ActorSystem) SpawnActor(actorType, actorID string, mailboxSize int, han
 		s.onActorStarted(actorID)
 	}
 
-	s.logger.WithFields(logging.Fields{
+	logger := s.logger.WithFields(logging.Fields{
 		"actor_id":   actorID,
 		"actor_type": actorType,
-	}).Info("Actor spawned")
+	})
+	if replaced {
+		logger.Warn("Passivated actor replaced by a new spawn")
+	}
+	logger.Info("Actor spawned")
 
 	return actorRef, nil
 }
 
+// startActor creates and starts the actor of actorRef and adds it to the system, remembering the
+// message IDs it processed before, if any. The caller holds actorsMutex.
+func (s *ActorSystem) startActor(actorRef *ActorRef, mailboxSize int, handler func(Message) error, processed []processedMessage) error {
+	actorID, actorType := actorRef.ID, actorRef.Type
+	if err := s.reserveSpawn(actorType); err != nil {
+		return err
+	}
+
+	// Create new actor
+	actor := NewBaseActor(actorID, actorType, mailboxSize, handler)
+	if s.simulated {
+		actor.useSimulation(s.clock)
+	}
+	actor.SetDedupWindow(s.dedupWindow)
+	actor.restoreProcessed(processed)
+	actor.goroutines = s.goroutines
+	actor.duplicates = &s.duplicates
+	actor.onProcessed = func(message Message, effects models.MessageEffects, duration time.Duration, err error) {
+		s.messageProcessed(actorID, actorType, message, effects, duration, err)
+	}
+	actorRef.Actor = actor
+
+	// Start the actor
+	if err := actor.Start(s.ctx); err != nil {
+		return fmt.Errorf("failed to start actor %s: %w", actorID, err)
+	}
+
+	// Add to actors map
+	s.actors[actorID] = actorRef
+	s.typeCounts[actorType]++
+	s.recordHeartbeat(actorID)
+	return nil
+}
+
 // StopActor stops and removes an actor from the system
 func (s *ActorSystem) StopActor(actorID string) error {
 	s.actorsMutex.Lock()
@@ -191,7 +380,14 @@ func (s *ActorSystem) StopActor(actorID string) error {
 
 	actorRef, exists := s.actors[actorID]
 	if !exists {
-		return fmt.Errorf("actor %s not found", actorID)
+		if !s.forgetPassivated(actorID) {
+			return fmt.Errorf("%w: %s", ErrActorNotFound, actorID)
+		}
+		if s.onActorStopped != nil {
+			s.onActorStopped(actorID)
+		}
+		s.logger.WithField("actor_id", actorID).Info("Passivated actor stopped")
+		return nil
 	}
 
 	// Stop the actor
@@ -201,6 +397,9 @@ func (s *ActorSystem) StopActor(actorID string) error {
 
 	// Remove from actors map
 	delete(s.actors, actorID)
+	s.typeCounts[actorRef.Type]--
+	s.forgetHeartbeat(actorID)
+	s.forgetPooled(actorID)
 
 	// Update metrics
 	s.updateMetrics(func(m *SystemMetrics) {
@@ -223,22 +422,107 @@ func (s *ActorSystem) GetActor(actorID string) (*ActorRef, error) {
 
 	actorRef, exists := s.actors[actorID]
 	if !exists {
-		return nil, fmt.Errorf("actor %s not found", actorID)
+		return nil, fmt.Errorf("%w: %s", ErrActorNotFound, actorID)
 	}
 
 	return actorRef, nil
 }
 
-// SendMessage sends a message to an actor
+// mailboxPeeker is implemented by actors whose mailbox can be inspected, such as those built on
+// BaseActor
+type mailboxPeeker interface {
+	PeekMailbox(limit int) MailboxSnapshot
+}
+
+// PeekMailbox returns up to limit of the messages waiting in an actor's mailbox, oldest first,
+// without removing them
+func (s *ActorSystem) PeekMailbox(actorID string, limit int) (MailboxSnapshot, error) {
+	actorRef, err := s.GetActor(actorID)
+	if err != nil {
+		return MailboxSnapshot{}, err
+	}
+
+	peeker, ok := actorRef.Actor.(mailboxPeeker)
+	if !ok {
+		return MailboxSnapshot{}, fmt.Errorf("actor %s does not support mailbox inspection", actorID)
+	}
+	return peeker.PeekMailbox(limit), nil
+}
+
+// MessageSchemas returns the registry of message schema versions and up-converters
+func (s *ActorSystem) MessageSchemas() *MessageSchemas {
+	return s.schemas
+}
+
+// SetHybridClock sets the clock stamping every sent message with a hybrid timestamp, so that
+// messages are ordered across instances whose wall clocks disagree. Messages stamped already,
+// such as those read back from another instance's durable mailbox or forwarded by a handler,
+// keep their timestamp and move the clock past it. It must be set before messages are sent.
+func (s *ActorSystem) SetHybridClock(clock *hlc.Clock) {
+	s.hybridClock = clock
+}
+
+// stampMessage gives a versioned message the hybrid timestamp of sending it
+func (s *ActorSystem) stampMessage(message Message) {
+	if base, ok := message.(*BaseMessage); ok && s.hybridClock != nil {
+		s.hybridClock.Stamp(base)
+	}
+}
+
+// versionMessage stamps a message with the current schema version of its type. A message
+// carrying a raw JSON payload, as read back from storage, is upgraded to it first.
+func (s *ActorSystem) versionMessage(message Message) (Message, error) {
+	message = unwrapMessage(message)
+	base, ok := message.(*BaseMessage)
+	if !ok {
+		return message, nil
+	}
+
+	raw, ok := base.Payload.(json.RawMessage)
+	if !ok {
+		if base.Version == 0 {
+			base.Version = s.schemas.Version(base.Type)
+		}
+		return base, nil
+	}
+
+	payload, version, err := s.schemas.Upgrade(base.Type, base.Version, raw)
+	if err != nil {
+		return nil, err
+	}
+	upgraded := *base
+	upgraded.Payload = payload
+	upgraded.Version = version
+	return &upgraded, nil
+}
+
+// SendMessage sends a message to an actor, reviving it first if it is passivated
 func (s *ActorSystem) SendMessage(toActorID string, message Message) error {
-	actorRef, err := s.GetActor(toActorID)
+	actorRef, err := s.liveActor(toActorID)
 	if err != nil {
 		return err
 	}
 
-	if err := actorRef.Actor.Send(message); err != nil {
+	message, err = s.versionMessage(message)
+	if err != nil {
+		return fmt.Errorf("failed to version message for actor %s: %w", toActorID, err)
+	}
+	s.stampMessage(message)
+
+	err = actorRef.Actor.Send(message)
+	for err != nil {
+		// The actor may have been passivated after it was looked up, and revived since
+		current, lookupErr := s.liveActor(toActorID)
+		if lookupErr != nil || current == actorRef {
+			break
+		}
+		actorRef = current
+		err = actorRef.Actor.Send(message)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to send message to actor %s: %w", toActorID, err)
 	}
+	s.enqueue(toActorID)
 
 	// Update metrics
 	s.updateMetrics(func(m *SystemMetrics) {
@@ -253,7 +537,18 @@ func (s *ActorSystem) SendMessage(toActorID string, message Message) error {
 	return nil
 }
 
-// BroadcastMessage sends a message to all actors of a specific type
+// SendMessageContext sends a message to an actor on behalf of the request ctx belongs to. Nothing
+// is sent once ctx is done, since the actor would be working for a requester that stopped
+// waiting; a passed deadline is reported as the actor layer running out of the request's budget.
+func (s *ActorSystem) SendMessageContext(ctx context.Context, toActorID string, message Message) error {
+	if err := ctx.Err(); err != nil {
+		deadline.Exceeded(ctx, deadline.LayerActor, err)
+		return fmt.Errorf("message to actor %s not sent: %w", toActorID, err)
+	}
+	return s.SendMessage(toActorID, message)
+}
+
+// BroadcastMessage sends a message to all actors of a specific type, reviving the passivated ones
 func (s *ActorSystem) BroadcastMessage(actorType string, message Message) error {
 	s.actorsMutex.RLock()
 	var targetActors []*ActorRef
@@ -262,21 +557,48 @@ func (s *ActorSystem) BroadcastMessage(actorType string, message Message) error
 			targetActors = append(targetActors, actorRef)
 		}
 	}
+	var passivated []string
+	for actorID, record := range s.passivated {
+		if record.ref.Type == actorType {
+			passivated = append(passivated, actorID)
+		}
+	}
 	s.actorsMutex.RUnlock()
 
+	sort.Strings(passivated)
+	for _, actorID := range passivated {
+		actorRef, err := s.revive(actorID)
+		if err != nil {
+			s.logger.WithError(err).WithField("actor_id", actorID).Warn("Failed to revive passivated actor for broadcast")
+			continue
+		}
+		targetActors = append(targetActors, actorRef)
+	}
+
 	if len(targetActors) == 0 {
 		return fmt.Errorf("no actors of type %s found", actorType)
 	}
 
-	var errors []error
+	message, err := s.versionMessage(message)
+	if err != nil {
+		return fmt.Errorf("failed to version message for %s actors: %w", actorType, err)
+	}
+	s.stampMessage(message)
+
+	// Deliver in ID order so broadcasts are reproducible in simulation mode
+	sort.Slice(targetActors, func(i, j int) bool { return targetActors[i].ID < targetActors[j].ID })
+
+	var sendErrors []error
 	for _, actorRef := range targetActors {
 		if err := actorRef.Actor.Send(message); err != nil {
-			errors = append(errors, fmt.Errorf("failed to send to %s: %w", actorRef.ID, err))
+			sendErrors = append(sendErrors, fmt.Errorf("failed to send to %s: %w", actorRef.ID, err))
+			continue
 		}
+		s.enqueue(actorRef.ID)
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("broadcast failed for some actors: %v", errors)
+	if len(sendErrors) > 0 {
+		return fmt.Errorf("broadcast failed for some actors: %v", sendErrors)
 	}
 
 	// Update metrics
@@ -303,16 +625,29 @@ func (s *ActorSystem) ListActors() []*ActorRef {
 // GetMetrics returns current system metrics
 func (s *ActorSystem) GetMetrics() SystemMetrics {
 	s.metricsLock.RLock()
-	defer s.metricsLock.RUnlock()
-
 	metrics := s.metrics
 	if !s.startTime.IsZero() {
-		metrics.SystemUptime = time.Since(s.startTime)
+		metrics.SystemUptime = s.clock.Since(s.startTime)
 	}
+	s.metricsLock.RUnlock()
 
+	metrics.Spawns, metrics.Pools = s.poolMetrics()
+	metrics.MessageConversions = s.schemas.Conversions()
+	metrics.Passivation = s.passivationMetrics()
 	return metrics
 }
 
+// SetMessageFailureHandler sets a handler called for every failed processing attempt,
+// including the final one that dead-letters the message
+func (s *ActorSystem) SetMessageFailureHandler(handler func(failure MessageFailure)) {
+	s.onMessageFail = handler
+}
+
+// SetMessageProcessedHandler sets a handler called for every message processed successfully
+func (s *ActorSystem) SetMessageProcessedHandler(handler func(result MessageResult)) {
+	s.onMessageDone = handler
+}
+
 // SetEventHandlers sets event handlers for system events
 func (s *ActorSystem) SetEventHandlers(
 	onActorStarted func(actorID string),
@@ -330,47 +665,19 @@ func (s *ActorSystem) SetEventHandlers(
 func (s *ActorSystem) metricsCollector() {
 	defer s.wg.Done()
 
-	ticker := time.NewTicker(5 * time.Second)
+	ticker := time.NewTicker(metricsInterval)
 	defer ticker.Stop()
 
 	lastMessageCount := int64(0)
-	lastUpdate := time.Now()
+	lastUpdate := s.clock.Now()
 
 	for {
 		select {
 		case <-ticker.C:
-			now := time.Now()
-			duration := now.Sub(lastUpdate)
-
-			s.updateMetrics(func(m *SystemMetrics) {
-				// Calculate messages per second
-				messageDiff := m.TotalMessages - lastMessageCount
-				if duration.Seconds() > 0 {
-					m.MessagesPerSecond = float64(messageDiff) / duration.Seconds()
-				}
-
-				// Calculate average latency from all actors
-				s.actorsMutex.RLock()
-				var totalLatency time.Duration
-				activeCount := 0
-				for _, actorRef := range s.actors {
-					if actorRef.Actor.GetState() == ActorStateProcessing {
-						actorMetrics := actorRef.Actor.GetMetrics()
-						totalLatency += actorMetrics.AverageProcessTime
-						activeCount++
-					}
-				}
-				s.actorsMutex.RUnlock()
-
-				if activeCount > 0 {
-					m.AverageLatency = totalLatency / time.Duration(activeCount)
-				}
-
-				m.LastMetricsUpdate = now
-			})
-
-			lastMessageCount = s.metrics.TotalMessages
-			lastUpdate = now
+			lastMessageCount, lastUpdate = s.collectMetrics(lastMessageCount, lastUpdate)
+			s.CheckHeartbeats()
+			s.AuditGoroutines()
+			s.PassivateIdle()
 
 		case <-s.ctx.Done():
 			return
@@ -378,8 +685,395 @@ func (s *ActorSystem) metricsCollector() {
 	}
 }
 
+// scheduleMetricsTick schedules the next metrics collection on the simulated clock
+func (s *ActorSystem) scheduleMetricsTick(lastMessageCount int64, lastUpdate time.Time) {
+	s.metricsTimer = s.clock.AfterFunc(metricsInterval, func() {
+		if s.ctx.Err() != nil {
+			return
+		}
+		count, updated := s.collectMetrics(lastMessageCount, lastUpdate)
+		s.CheckHeartbeats()
+		s.AuditGoroutines()
+		s.PassivateIdle()
+		s.scheduleMetricsTick(count, updated)
+	})
+}
+
+// collectMetrics updates throughput and latency metrics and returns the new baseline
+func (s *ActorSystem) collectMetrics(lastMessageCount int64, lastUpdate time.Time) (int64, time.Time) {
+	now := s.clock.Now()
+	duration := now.Sub(lastUpdate)
+
+	var totalMessages int64
+	s.updateMetrics(func(m *SystemMetrics) {
+		// Calculate messages per second
+		messageDiff := m.TotalMessages - lastMessageCount
+		if duration.Seconds() > 0 {
+			m.MessagesPerSecond = float64(messageDiff) / duration.Seconds()
+		}
+
+		// Calculate average latency from all actors
+		s.actorsMutex.RLock()
+		var totalLatency time.Duration
+		activeCount := 0
+		for _, actorRef := range s.actors {
+			actorMetrics := actorRef.Actor.GetMetrics()
+			if actorRef.Actor.GetState() == ActorStateProcessing {
+				totalLatency += actorMetrics.AverageProcessTime
+				activeCount++
+			}
+		}
+		s.actorsMutex.RUnlock()
+
+		if activeCount > 0 {
+			m.AverageLatency = totalLatency / time.Duration(activeCount)
+		}
+
+		m.DuplicateMessages = s.duplicates.Load()
+		m.LastMetricsUpdate = now
+		totalMessages = m.TotalMessages
+	})
+
+	return totalMessages, now
+}
+
 func (s *ActorSystem) updateMetrics(updater func(*SystemMetrics)) {
 	s.metricsLock.Lock()
 	defer s.metricsLock.Unlock()
 	updater(&s.metrics)
 }
+
+// Deterministic scheduling (simulation mode)
+
+// scheduledActor is implemented by actors whose mailbox is drained by the system scheduler
+type scheduledActor interface {
+	processNext() bool
+}
+
+// enqueue records a delivered message so the scheduler processes it in send order
+func (s *ActorSystem) enqueue(actorID string) {
+	if !s.simulated {
+		return
+	}
+
+	s.queueMutex.Lock()
+	s.runQueue = append(s.runQueue, actorID)
+	s.queueMutex.Unlock()
+}
+
+// Step processes the next pending message in send order. It reports whether a message was processed.
+// Step is a no-op outside simulation mode.
+func (s *ActorSystem) Step() bool {
+	for {
+		s.queueMutex.Lock()
+		if len(s.runQueue) == 0 {
+			s.queueMutex.Unlock()
+			return false
+		}
+		actorID := s.runQueue[0]
+		s.runQueue = s.runQueue[1:]
+		s.queueMutex.Unlock()
+
+		actorRef, err := s.GetActor(actorID)
+		if err != nil {
+			// Actor was stopped after the message was sent
+			continue
+		}
+
+		if actor, ok := actorRef.Actor.(scheduledActor); ok && actor.processNext() {
+			return true
+		}
+	}
+}
+
+// RunUntilIdle processes messages until every mailbox is empty and returns how many were processed.
+// Messages sent directly to an actor rather than through the system are drained in actor ID order.
+func (s *ActorSystem) RunUntilIdle() int {
+	if !s.simulated {
+		return 0
+	}
+
+	processed := 0
+	for {
+		for s.Step() {
+			processed++
+		}
+
+		swept := false
+		actors := s.ListActors()
+		sort.Slice(actors, func(i, j int) bool { return actors[i].ID < actors[j].ID })
+		for _, actorRef := range actors {
+			if actor, ok := actorRef.Actor.(scheduledActor); ok && actor.processNext() {
+				processed++
+				swept = true
+			}
+		}
+
+		if !swept {
+			return processed
+		}
+	}
+}
+
+// AdvanceTime moves the simulated clock forward by d. Timers fire in deadline order and
+// the messages they produce are processed before the next timer fires.
+func (s *ActorSystem) AdvanceTime(d time.Duration) error {
+	clock, ok := s.clock.(*SimulatedClock)
+	if !ok || !s.simulated {
+		return fmt.Errorf("actor system %s is not running in simulation mode", s.name)
+	}
+
+	target := clock.Now().Add(d)
+	s.RunUntilIdle()
+	for clock.fireNext(target) {
+		s.RunUntilIdle()
+	}
+	clock.setNow(target)
+
+	return nil
+}
+
+// ScheduleMessage sends message to an actor once delay has elapsed on the system clock,
+// e.g. to deliver a matching timeout. Stopping the returned timer cancels the delivery.
+func (s *ActorSystem) ScheduleMessage(delay time.Duration, toActorID string, message Message) Timer {
+	return s.clock.AfterFunc(delay, func() {
+		if err := s.SendMessage(toActorID, message); err != nil {
+			s.logger.WithError(err).WithFields(logging.Fields{
+				"to_actor":     toActorID,
+				"message_type": message.GetType(),
+			}).Warn("Failed to deliver scheduled message")
+		}
+	})
+}
+
+// Heartbeats
+
+// SetHeartbeatTimeout enables heartbeat expiry. Actors that do not call Heartbeat within timeout
+// are reported to the actor failed handler with ErrHeartbeatExpired. Zero disables expiry.
+func (s *ActorSystem) SetHeartbeatTimeout(timeout time.Duration) {
+	s.heartbeatMutex.Lock()
+	defer s.heartbeatMutex.Unlock()
+	s.heartbeatTimeout = timeout
+}
+
+// Heartbeat records that an actor is alive
+func (s *ActorSystem) Heartbeat(actorID string) error {
+	if _, err := s.GetActor(actorID); err != nil {
+		return err
+	}
+
+	s.recordHeartbeat(actorID)
+	return nil
+}
+
+// CheckHeartbeats reports every actor whose heartbeat has expired and returns their IDs.
+// An actor is reported once per expiry; a new heartbeat re-arms it.
+func (s *ActorSystem) CheckHeartbeats() []string {
+	s.heartbeatMutex.Lock()
+	if s.heartbeatTimeout <= 0 {
+		s.heartbeatMutex.Unlock()
+		return nil
+	}
+
+	now := s.clock.Now()
+	var expired []string
+	for actorID, last := range s.heartbeats {
+		if !s.expired[actorID] && now.Sub(last) >= s.heartbeatTimeout {
+			s.expired[actorID] = true
+			expired = append(expired, actorID)
+		}
+	}
+	s.heartbeatMutex.Unlock()
+
+	sort.Strings(expired)
+	for _, actorID := range expired {
+		s.logger.WithField("actor_id", actorID).Warn("Actor heartbeat expired")
+		if s.onActorFailed != nil {
+			s.onActorFailed(actorID, ErrHeartbeatExpired)
+		}
+	}
+
+	return expired
+}
+
+func (s *ActorSystem) recordHeartbeat(actorID string) {
+	s.heartbeatMutex.Lock()
+	defer s.heartbeatMutex.Unlock()
+	s.heartbeats[actorID] = s.clock.Now()
+	delete(s.expired, actorID)
+}
+
+func (s *ActorSystem) forgetHeartbeat(actorID string) {
+	s.heartbeatMutex.Lock()
+	defer s.heartbeatMutex.Unlock()
+	delete(s.heartbeats, actorID)
+	delete(s.expired, actorID)
+}
+
+// Goroutine leaks
+
+// SetGoroutineLeakGrace sets how long an actor's goroutines may outlive its Stop before the
+// audit reports them as leaked
+func (s *ActorSystem) SetGoroutineLeakGrace(grace time.Duration) {
+	s.goroutineMutex.Lock()
+	defer s.goroutineMutex.Unlock()
+	s.leakGrace = grace
+}
+
+// AuditGoroutines reports the actors whose goroutines outlived their Stop by more than the
+// grace period. Each leaking actor is logged once; the counts are kept in the system metrics.
+// The audit runs with every metrics collection.
+func (s *ActorSystem) AuditGoroutines() GoroutineAudit {
+	s.goroutineMutex.Lock()
+	audit := s.goroutines.audit(s.leakGrace)
+	var newLeaks []GoroutineLeak
+	leaking := make(map[string]bool, len(audit.Leaks))
+	for _, leak := range audit.Leaks {
+		leaking[leak.ActorID] = true
+		if !s.reportedLeaks[leak.ActorID] {
+			newLeaks = append(newLeaks, leak)
+		}
+	}
+	s.reportedLeaks = leaking
+	s.goroutineMutex.Unlock()
+
+	s.updateMetrics(func(m *SystemMetrics) {
+		m.Goroutines = audit.GoroutineMetrics
+	})
+
+	for _, leak := range newLeaks {
+		names := make([]string, len(leak.Goroutines))
+		for i, goroutine := range leak.Goroutines {
+			names[i] = goroutine.Name
+		}
+		s.logger.WithFields(logging.Fields{
+			"actor_id":   leak.ActorID,
+			"actor_type": leak.ActorType,
+			"stopped_at": leak.StoppedAt,
+			"goroutines": names,
+		}).Warn("Actor goroutines outlived Stop")
+	}
+
+	return audit
+}
+
+// Retries
+
+// SetDefaultRetryPolicy sets the retry policy for message types without their own policy.
+// The default is NoRetry.
+func (s *ActorSystem) SetDefaultRetryPolicy(policy RetryPolicy) {
+	s.retryMutex.Lock()
+	defer s.retryMutex.Unlock()
+	s.defaultRetry = policy
+}
+
+// SetRetryPolicy sets the retry policy for one message type
+func (s *ActorSystem) SetRetryPolicy(messageType string, policy RetryPolicy) {
+	s.retryMutex.Lock()
+	defer s.retryMutex.Unlock()
+	s.retryPolicies[messageType] = policy
+}
+
+// RetryPolicy returns the retry policy applied to a message type
+func (s *ActorSystem) RetryPolicy(messageType string) RetryPolicy {
+	s.retryMutex.Lock()
+	defer s.retryMutex.Unlock()
+	return s.retryPolicyLocked(messageType)
+}
+
+func (s *ActorSystem) retryPolicyLocked(messageType string) RetryPolicy {
+	if policy, ok := s.retryPolicies[messageType]; ok {
+		return policy
+	}
+	return s.defaultRetry
+}
+
+// messageProcessed reports a processed message, or schedules a retry for a failed message and
+// dead-letters it once its retry policy is exhausted
+func (s *ActorSystem) messageProcessed(actorID, actorType string, message Message, effects models.MessageEffects, duration time.Duration, err error) {
+	key := actorID + "/" + message.GetID()
+
+	s.retryMutex.Lock()
+	if err == nil {
+		delete(s.attempts, key)
+		s.retryMutex.Unlock()
+
+		if s.onMessageDone != nil {
+			s.onMessageDone(MessageResult{
+				ActorID:   actorID,
+				ActorType: actorType,
+				Message:   message,
+				Duration:  duration,
+				Effects:   effects,
+			})
+		}
+		return
+	}
+
+	s.attempts[key]++
+	failure := MessageFailure{
+		ActorID:   actorID,
+		ActorType: actorType,
+		Message:   message,
+		Attempt:   s.attempts[key],
+		Err:       err,
+		Effects:   effects,
+	}
+	policy := s.retryPolicyLocked(message.GetType())
+	if failure.Attempt < policy.MaxAttempts {
+		failure.RetryIn = policy.Backoff(failure.Attempt, s.retryRand)
+	} else {
+		failure.DeadLettered = true
+		delete(s.attempts, key)
+	}
+	s.retryMutex.Unlock()
+
+	logger := s.logger.WithError(err).WithFields(logging.Fields{
+		"actor_id":     actorID,
+		"message_id":   message.GetID(),
+		"message_type": message.GetType(),
+		"attempt":      failure.Attempt,
+	})
+	if failure.DeadLettered {
+		s.updateMetrics(func(m *SystemMetrics) {
+			m.DeadLetters++
+		})
+		logger.Warn("Message dead-lettered after exhausting retries")
+	} else {
+		s.updateMetrics(func(m *SystemMetrics) {
+			m.RetriedMessages++
+		})
+		logger.WithField("retry_in", failure.RetryIn).Debug("Retrying failed message")
+		s.clock.AfterFunc(failure.RetryIn, func() {
+			s.redeliver(actorID, message)
+		})
+	}
+
+	if s.onMessageFail != nil {
+		s.onMessageFail(failure)
+	}
+}
+
+// redeliver puts a failed message back in its actor's mailbox
+func (s *ActorSystem) redeliver(actorID string, message Message) {
+	if s.ctx != nil && s.ctx.Err() != nil {
+		return
+	}
+
+	actorRef, err := s.liveActor(actorID)
+	if err == nil {
+		err = actorRef.Actor.Send(message)
+	}
+	if err != nil {
+		s.retryMutex.Lock()
+		delete(s.attempts, actorID+"/"+message.GetID())
+		s.retryMutex.Unlock()
+
+		s.logger.WithError(err).WithFields(logging.Fields{
+			"actor_id":     actorID,
+			"message_type": message.GetType(),
+		}).Warn("Failed to redeliver message for retry")
+		return
+	}
+	s.enqueue(actorID)
+}
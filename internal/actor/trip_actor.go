@@ -0,0 +1,119 @@
+package actor
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// TripActor holds the state of the trip a trip actor handles. Matching and driving are simulated
+// by the ride service, so the actor only follows the trip through its lifecycle messages.
+type TripActor struct {
+	mu   sync.Mutex
+	trip *models.Trip
+}
+
+// NewTripActor creates the state of a trip actor for trip, which it copies
+func NewTripActor(trip *models.Trip) *TripActor {
+	copied := *trip
+	return &TripActor{trip: &copied}
+}
+
+// HandleMessage applies the lifecycle messages of the trip to its state; other messages are
+// ignored
+func (ta *TripActor) HandleMessage(message Message) error {
+	ta.mu.Lock()
+	defer ta.mu.Unlock()
+
+	switch message.GetType() {
+	case MsgTypeRideMatched:
+		var payload RideMatchedPayload
+		if err := decodeTripPayload(message.GetPayload(), &payload); err != nil {
+			return err
+		}
+		driverID, err := uuid.Parse(payload.DriverID)
+		if err != nil {
+			return fmt.Errorf("invalid driver ID in ride matched payload: %w", err)
+		}
+		ta.trip.DriverID = &driverID
+		ta.trip.Status = models.TripStatusMatched
+		ta.trip.MatchedAt = &payload.MatchedAt
+	case MsgTypeRideStarted:
+		var payload RideStartedPayload
+		if err := decodeTripPayload(message.GetPayload(), &payload); err != nil {
+			return err
+		}
+		ta.trip.Status = models.TripStatusInProgress
+		ta.trip.PickupAt = &payload.StartedAt
+	case MsgTypeRideCompleted:
+		var payload RideCompletedPayload
+		if err := decodeTripPayload(message.GetPayload(), &payload); err != nil {
+			return err
+		}
+		ta.trip.Status = models.TripStatusCompleted
+		ta.trip.FareAmount = &payload.Fare
+		ta.trip.CompletedAt = &payload.CompletedAt
+	case MsgTypeRideCancelled, MsgTypeCancelRide:
+		var payload RideCancelledPayload
+		if err := decodeTripPayload(message.GetPayload(), &payload); err != nil {
+			return err
+		}
+		ta.trip.Status = models.TripStatusCancelled
+		ta.trip.CancelledAt = &payload.CancelledAt
+	}
+	return nil
+}
+
+// Trip returns a copy of the trip as the actor last saw it, nil while the actor is passivated
+func (ta *TripActor) Trip() *models.Trip {
+	ta.mu.Lock()
+	defer ta.mu.Unlock()
+
+	if ta.trip == nil {
+		return nil
+	}
+	copied := *ta.trip
+	return &copied
+}
+
+// Snapshot captures the trip while the actor is passivated and releases it
+func (ta *TripActor) Snapshot() ([]byte, error) {
+	ta.mu.Lock()
+	defer ta.mu.Unlock()
+
+	snapshot, err := json.Marshal(ta.trip)
+	if err != nil {
+		return nil, fmt.Errorf("failed to snapshot trip: %w", err)
+	}
+	ta.trip = nil
+	return snapshot, nil
+}
+
+// Restore brings the trip back from a snapshot before the revived actor processes a message
+func (ta *TripActor) Restore(snapshot []byte) error {
+	trip := &models.Trip{}
+	if err := json.Unmarshal(snapshot, trip); err != nil {
+		return fmt.Errorf("failed to restore trip: %w", err)
+	}
+
+	ta.mu.Lock()
+	defer ta.mu.Unlock()
+	ta.trip = trip
+	return nil
+}
+
+// decodeTripPayload decodes a message payload, which may be a payload struct or its JSON form
+func decodeTripPayload(payload interface{}, target interface{}) error {
+	jsonBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	if err := json.Unmarshal(jsonBytes, target); err != nil {
+		return fmt.Errorf("failed to unmarshal payload: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,302 @@
+// Package appversion gates the driver and passenger apps by the version they run. Apps report
+// their platform and version on every request; versions below their platform's configured
+// minimum must upgrade, and features are only offered to the versions they are enabled for, so
+// that features such as the WebSocket offer channel can be rolled out progressively. Requests
+// are counted per platform and version to see when an old version can be retired.
+package appversion
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+)
+
+// Headers the apps report their platform and version with, and the minimum version is sent back
+// with when an upgrade is required
+const (
+	PlatformHeader   = "X-App-Platform"
+	VersionHeader    = "X-App-Version"
+	MinVersionHeader = "X-App-Min-Version"
+)
+
+// maxUsageKeys bounds the platform and version pairs whose usage is tracked, so that made-up
+// versions cannot grow the usage without bound. Requests of further pairs are counted as other.
+const maxUsageKeys = 500
+
+// otherUsage is the platform and version requests beyond maxUsageKeys are counted under
+const otherUsage = "other"
+
+var (
+	versionPattern  = regexp.MustCompile(`^v?\d{1,6}(\.\d{1,6}){0,2}$`)
+	platformPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,19}$`)
+)
+
+// Version is a major.minor.patch app version
+type Version struct {
+	Major, Minor, Patch int
+}
+
+// ParseVersion parses a major[.minor[.patch]] version with an optional v prefix, e.g. 2.3.0 or
+// v2.3; missing parts are zero
+func ParseVersion(s string) (Version, error) {
+	if !versionPattern.MatchString(s) {
+		return Version{}, models.ErrInvalidAppVersion
+	}
+
+	var parts [3]int
+	for i, part := range strings.Split(strings.TrimPrefix(s, "v"), ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Version{}, models.ErrInvalidAppVersion
+		}
+		parts[i] = n
+	}
+	return Version{Major: parts[0], Minor: parts[1], Patch: parts[2]}, nil
+}
+
+// Compare returns -1, 0 or 1 as v is below, equal to or above other
+func (v Version) Compare(other Version) int {
+	for _, d := range [3]int{v.Major - other.Major, v.Minor - other.Minor, v.Patch - other.Patch} {
+		if d < 0 {
+			return -1
+		}
+		if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Less reports whether v is below other
+func (v Version) Less(other Version) bool {
+	return v.Compare(other) < 0
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Client is the app a request was made with
+type Client struct {
+	Platform string  // lowercase, e.g. ios or android
+	Version  Version // normalized to major.minor.patch
+}
+
+// ParseClient parses the platform and version headers of a request. ok is false when the app
+// did not report its version; an invalid platform or version is an error.
+func ParseClient(platform, version string) (client Client, ok bool, err error) {
+	if version == "" {
+		return Client{}, false, nil
+	}
+
+	client.Version, err = ParseVersion(strings.TrimSpace(version))
+	if err != nil {
+		return Client{}, false, err
+	}
+	client.Platform = strings.ToLower(strings.TrimSpace(platform))
+	if !platformPattern.MatchString(client.Platform) {
+		return Client{}, false, fmt.Errorf("%w: the platform must be reported along with the version, e.g. ios or android", models.ErrInvalidAppVersion)
+	}
+	return client, true, nil
+}
+
+// Usage is how many requests an app platform and version made since this instance started
+type Usage struct {
+	Platform   string    `json:"platform"`
+	Version    string    `json:"version"`
+	Requests   int64     `json:"requests"`
+	Rejected   int64     `json:"rejected"` // rejected as below the platform's minimum version
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+// Gate decides which app versions may use the API and which features they are offered. The
+// versions are set from configuration and can be changed at runtime with SetConfig.
+type Gate struct {
+	mu          sync.RWMutex
+	minVersions map[string]Version
+	features    map[string]Version
+
+	usageMu sync.Mutex
+	usage   map[string]*Usage
+
+	requests metric.Int64Counter
+	now      func() time.Time
+}
+
+// NewGate creates a gate enforcing the configured versions. A nil meter disables metrics.
+func NewGate(cfg config.AppVersionConfig, meter metric.Meter) (*Gate, error) {
+	if meter == nil {
+		meter = metricnoop.NewMeterProvider().Meter("")
+	}
+
+	requests, err := meter.Int64Counter(
+		"app_requests_total",
+		metric.WithDescription("Requests made by the driver and passenger apps, by platform, version and whether an upgrade was required"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Gate{
+		usage:    make(map[string]*Usage),
+		requests: requests,
+		now:      time.Now,
+	}
+	if err := g.SetConfig(cfg); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// SetConfig replaces the minimum and feature versions
+func (g *Gate) SetConfig(cfg config.AppVersionConfig) error {
+	minVersions, err := parseVersions(cfg.MinVersions, strings.ToLower)
+	if err != nil {
+		return fmt.Errorf("invalid minimum app version: %w", err)
+	}
+	features, err := parseVersions(cfg.FeatureVersions, nil)
+	if err != nil {
+		return fmt.Errorf("invalid feature app version: %w", err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.minVersions = minVersions
+	g.features = features
+	return nil
+}
+
+// parseVersions parses the versions of a configuration map, normalizing its keys with key
+// when non-nil
+func parseVersions(versions map[string]string, key func(string) string) (map[string]Version, error) {
+	parsed := make(map[string]Version, len(versions))
+	for name, value := range versions {
+		version, err := ParseVersion(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s=%s: %w", name, value, err)
+		}
+		if key != nil {
+			name = key(name)
+		}
+		parsed[name] = version
+	}
+	return parsed, nil
+}
+
+// MinVersion returns the minimum version of a platform; ok is false when the platform is not gated
+func (g *Gate) MinVersion(platform string) (version Version, ok bool) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	version, ok = g.minVersions[platform]
+	return version, ok
+}
+
+// MinVersions returns the minimum version of each gated platform
+func (g *Gate) MinVersions() map[string]string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	versions := make(map[string]string, len(g.minVersions))
+	for platform, version := range g.minVersions {
+		versions[platform] = version.String()
+	}
+	return versions
+}
+
+// UpgradeRequired reports whether the client runs a version below its platform's minimum
+func (g *Gate) UpgradeRequired(client Client) bool {
+	min, ok := g.MinVersion(client.Platform)
+	return ok && client.Version.Less(min)
+}
+
+// Supports reports whether the feature is enabled for the client's version. Features without a
+// configured version are not enabled for any version.
+func (g *Gate) Supports(client Client, feature string) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	min, ok := g.features[feature]
+	return ok && !client.Version.Less(min)
+}
+
+// Features returns the features enabled for the client's version, sorted by name
+func (g *Gate) Features(client Client) []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	features := []string{}
+	for feature, min := range g.features {
+		if !client.Version.Less(min) {
+			features = append(features, feature)
+		}
+	}
+	sort.Strings(features)
+	return features
+}
+
+// Record counts a request of the client, rejected when it was below the minimum version
+func (g *Gate) Record(ctx context.Context, client Client, rejected bool) {
+	platform, version := client.Platform, client.Version.String()
+	key := platform + "/" + version
+
+	g.usageMu.Lock()
+	usage, ok := g.usage[key]
+	if !ok {
+		if len(g.usage) >= maxUsageKeys {
+			platform, version = otherUsage, otherUsage
+			key = otherUsage
+			usage = g.usage[key]
+		}
+		if usage == nil {
+			usage = &Usage{Platform: platform, Version: version}
+			g.usage[key] = usage
+		}
+	}
+	usage.Requests++
+	if rejected {
+		usage.Rejected++
+	}
+	usage.LastSeenAt = g.now()
+	g.usageMu.Unlock()
+
+	g.requests.Add(ctx, 1, metric.WithAttributes(
+		attribute.String("platform", platform),
+		attribute.String("version", version),
+		attribute.Bool("upgrade_required", rejected),
+	))
+}
+
+// Usage returns the requests per platform and version since this instance started, by platform
+// and newest version first
+func (g *Gate) Usage() []Usage {
+	g.usageMu.Lock()
+	result := make([]Usage, 0, len(g.usage))
+	for _, usage := range g.usage {
+		result = append(result, *usage)
+	}
+	g.usageMu.Unlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Platform != result[j].Platform {
+			return result[i].Platform < result[j].Platform
+		}
+		vi, erri := ParseVersion(result[i].Version)
+		vj, errj := ParseVersion(result[j].Version)
+		if erri != nil || errj != nil {
+			return errj != nil && erri == nil
+		}
+		return vj.Less(vi)
+	})
+	return result
+}
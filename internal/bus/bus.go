@@ -0,0 +1,202 @@
+// Package bus provides an in-process publish/subscribe bus for observability events, so
+// producers such as the actor system and services don't write to storage directly.
+package bus
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"actor-model-observability/internal/hlc"
+	"actor-model-observability/internal/logging"
+)
+
+// Topics published on the bus and the payload type each carries
+const (
+	TopicEventLog       = "event_log"       // *models.EventLog
+	TopicActorInstance  = "actor_instance"  // *models.ActorInstance
+	TopicActorMessage   = "actor_message"   // *models.ActorMessage
+	TopicTripStatus     = "trip_status"     // *models.Trip, a copy taken when it changed status
+	TopicDriverLocation = "driver_location" // *models.DriverLocationUpdate
+)
+
+// DefaultBufferSize is the subscriber queue size used when none is configured
+const DefaultBufferSize = 1024
+
+// Message is a payload published on a topic
+type Message struct {
+	Topic       string
+	Payload     interface{}
+	PublishedAt time.Time
+}
+
+// Handler processes the messages delivered to a subscriber
+type Handler func(msg Message)
+
+// Publisher publishes payloads to the subscribers of a topic
+type Publisher interface {
+	Publish(topic string, payload interface{})
+}
+
+// SubscriberStats reports the delivery counters of one subscriber
+type SubscriberStats struct {
+	Name      string   `json:"name"`
+	Topics    []string `json:"topics"`
+	Queued    int      `json:"queued"`
+	Delivered int64    `json:"delivered"`
+	Dropped   int64    `json:"dropped"`
+}
+
+// Bus delivers published messages to subscribers asynchronously. Each subscriber has its own
+// queue and goroutine, so a slow subscriber never blocks publishers or other subscribers;
+// when its queue is full, new messages are dropped for it.
+type Bus struct {
+	bufferSize  int
+	logger      *logging.Logger
+	subscribers []*subscriber
+	byTopic     map[string][]*subscriber
+	closed      bool
+	mu          sync.RWMutex
+	wg          sync.WaitGroup
+
+	// clock stamps published hlc.Event payloads; nil leaves them unstamped
+	clock *hlc.Clock
+}
+
+type subscriber struct {
+	name      string
+	topics    []string
+	handler   Handler
+	queue     chan Message
+	delivered int64
+	dropped   int64
+}
+
+// New creates a bus whose subscribers each queue up to bufferSize messages
+func New(bufferSize int, logger *logging.Logger) *Bus {
+	if bufferSize <= 0 {
+		bufferSize = DefaultBufferSize
+	}
+	return &Bus{
+		bufferSize: bufferSize,
+		logger:     logger.WithComponent("event_bus"),
+		byTopic:    make(map[string][]*subscriber),
+	}
+}
+
+// SetClock sets the hybrid clock stamping the payloads published that are hlc.Events, such as
+// event logs and actor messages, so that subscribers can order them across instances whose wall
+// clocks disagree. It must be set before anything is published.
+func (b *Bus) SetClock(clock *hlc.Clock) {
+	b.clock = clock
+}
+
+// Subscribe registers handler for messages published on any of topics
+func (b *Bus) Subscribe(name string, handler Handler, topics ...string) {
+	sub := &subscriber{
+		name:    name,
+		topics:  topics,
+		handler: handler,
+		queue:   make(chan Message, b.bufferSize),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+
+	b.subscribers = append(b.subscribers, sub)
+	for _, topic := range topics {
+		b.byTopic[topic] = append(b.byTopic[topic], sub)
+	}
+
+	b.wg.Add(1)
+	go b.run(sub)
+}
+
+// Publish queues payload for every subscriber of topic. Publishing after Close is a no-op.
+func (b *Bus) Publish(topic string, payload interface{}) {
+	if event, ok := payload.(hlc.Event); ok && b.clock != nil {
+		b.clock.Stamp(event)
+	}
+	msg := Message{Topic: topic, Payload: payload, PublishedAt: time.Now()}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return
+	}
+
+	for _, sub := range b.byTopic[topic] {
+		select {
+		case sub.queue <- msg:
+		default:
+			// Log the first drop and then every thousandth to avoid flooding the log
+			if dropped := atomic.AddInt64(&sub.dropped, 1); dropped%1000 == 1 {
+				b.logger.WithFields(logging.Fields{
+					"subscriber": sub.name,
+					"topic":      topic,
+					"dropped":    dropped,
+				}).Warn("Event bus subscriber queue full, dropping messages")
+			}
+		}
+	}
+}
+
+// Close stops accepting messages and waits until subscribers have handled the queued ones
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for _, sub := range b.subscribers {
+		close(sub.queue)
+	}
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
+
+// Stats returns the delivery counters of every subscriber, in subscription order
+func (b *Bus) Stats() []SubscriberStats {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	stats := make([]SubscriberStats, 0, len(b.subscribers))
+	for _, sub := range b.subscribers {
+		stats = append(stats, SubscriberStats{
+			Name:      sub.name,
+			Topics:    sub.topics,
+			Queued:    len(sub.queue),
+			Delivered: atomic.LoadInt64(&sub.delivered),
+			Dropped:   atomic.LoadInt64(&sub.dropped),
+		})
+	}
+	return stats
+}
+
+// run delivers a subscriber's queued messages until its queue is closed
+func (b *Bus) run(sub *subscriber) {
+	defer b.wg.Done()
+	for msg := range sub.queue {
+		b.deliver(sub, msg)
+	}
+}
+
+// deliver calls the handler, recovering from panics so one bad message doesn't stop the subscriber
+func (b *Bus) deliver(sub *subscriber, msg Message) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			b.logger.LogPanic(recovered, "event_bus", "deliver", logging.Fields{
+				"subscriber": sub.name,
+				"topic":      msg.Topic,
+			})
+		}
+	}()
+
+	sub.handler(msg)
+	atomic.AddInt64(&sub.delivered, 1)
+}
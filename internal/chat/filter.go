@@ -0,0 +1,112 @@
+// Package chat holds the pieces of the in-trip chat between a passenger and a driver that are
+// independent of storage: the filters message bodies pass through before they are stored, and
+// the hub delivering new messages to live subscribers of a trip.
+package chat
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Filter names accepted by NewFilters
+const (
+	FilterProfanity = "profanity"
+	FilterPII       = "pii"
+)
+
+// Filter rewrites a message body before it is stored, e.g. to mask words or personal data
+type Filter interface {
+	Apply(body string) string
+}
+
+// FilterFunc adapts a function to the Filter interface
+type FilterFunc func(body string) string
+
+// Apply calls f
+func (f FilterFunc) Apply(body string) string {
+	return f(body)
+}
+
+// Chain applies filters in order
+type Chain []Filter
+
+// Apply runs body through every filter of the chain
+func (c Chain) Apply(body string) string {
+	for _, f := range c {
+		body = f.Apply(body)
+	}
+	return body
+}
+
+// NewFilters builds the chain of the named filters, in order. words are the words masked by
+// the profanity filter.
+func NewFilters(names []string, words []string) (Chain, error) {
+	chain := make(Chain, 0, len(names))
+	for _, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case FilterProfanity:
+			chain = append(chain, NewProfanityFilter(words))
+		case FilterPII:
+			chain = append(chain, NewPIIFilter())
+		default:
+			return nil, fmt.Errorf("unknown chat filter %q", name)
+		}
+	}
+	return chain, nil
+}
+
+// ProfanityFilter masks listed words with asterisks, ignoring case. Only whole words are
+// masked, so a listed word inside a longer word is kept.
+type ProfanityFilter struct {
+	pattern *regexp.Regexp
+}
+
+// NewProfanityFilter creates a filter masking words
+func NewProfanityFilter(words []string) *ProfanityFilter {
+	quoted := make([]string, 0, len(words))
+	for _, w := range words {
+		if w = strings.TrimSpace(w); w != "" {
+			quoted = append(quoted, regexp.QuoteMeta(w))
+		}
+	}
+	if len(quoted) == 0 {
+		return &ProfanityFilter{}
+	}
+	return &ProfanityFilter{
+		pattern: regexp.MustCompile(`(?i)\b(?:` + strings.Join(quoted, "|") + `)\b`),
+	}
+}
+
+// Apply masks the listed words in body
+func (f *ProfanityFilter) Apply(body string) string {
+	if f.pattern == nil {
+		return body
+	}
+	return f.pattern.ReplaceAllStringFunc(body, func(word string) string {
+		return strings.Repeat("*", len([]rune(word)))
+	})
+}
+
+var (
+	// emailPattern matches email addresses
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	// phonePattern matches phone numbers of 8 or more digits, optionally with a leading + and
+	// spaces, dots, dashes or parentheses between the digits
+	phonePattern = regexp.MustCompile(`\+?\(?\d(?:[\s.()-]*\d){7,}`)
+)
+
+// PIIFilter replaces email addresses and phone numbers, so participants cannot move the
+// conversation off the platform or leak contact details through the trip history
+type PIIFilter struct{}
+
+// NewPIIFilter creates a filter removing contact details
+func NewPIIFilter() *PIIFilter {
+	return &PIIFilter{}
+}
+
+// Apply replaces the email addresses and phone numbers in body
+func (f *PIIFilter) Apply(body string) string {
+	body = emailPattern.ReplaceAllString(body, "[email removed]")
+	return phonePattern.ReplaceAllString(body, "[phone removed]")
+}
@@ -0,0 +1,77 @@
+package chat
+
+import (
+	"sync"
+
+	"actor-model-observability/internal/models"
+)
+
+// subscriberBuffer is the number of messages queued for a subscriber before newer messages are
+// dropped for it, so a slow connection never blocks sending. Dropped messages are still stored
+// and listed by the REST endpoint.
+const subscriberBuffer = 16
+
+// Hub fans out new chat messages to the live subscribers of their trip
+type Hub struct {
+	subscribers map[string]map[*subscriber]struct{} // keyed by trip ID
+	mu          sync.RWMutex
+}
+
+type subscriber struct {
+	messages chan *models.TripChatMessage
+}
+
+// NewHub creates a hub without subscribers
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[string]map[*subscriber]struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving the messages published to a trip, and a function that
+// ends the subscription and closes the channel
+func (h *Hub) Subscribe(tripID string) (<-chan *models.TripChatMessage, func()) {
+	sub := &subscriber{
+		messages: make(chan *models.TripChatMessage, subscriberBuffer),
+	}
+
+	h.mu.Lock()
+	if h.subscribers[tripID] == nil {
+		h.subscribers[tripID] = make(map[*subscriber]struct{})
+	}
+	h.subscribers[tripID][sub] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return sub.messages, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subscribers[tripID], sub)
+			if len(h.subscribers[tripID]) == 0 {
+				delete(h.subscribers, tripID)
+			}
+			h.mu.Unlock()
+			close(sub.messages)
+		})
+	}
+}
+
+// Publish delivers a message to every subscriber of its trip
+func (h *Hub) Publish(msg *models.TripChatMessage) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.subscribers[msg.TripID.String()] {
+		select {
+		case sub.messages <- msg:
+		default:
+		}
+	}
+}
+
+// Subscribers returns the number of active subscriptions to a trip
+func (h *Hub) Subscribers(tripID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.subscribers[tripID])
+}
@@ -2,7 +2,10 @@ package config
 
 import (
 	"fmt"
+	"net/mail"
 	"os"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -18,6 +21,51 @@ type Config struct {
 	Observability ObservabilityConfig
 	Metrics       MetricsConfig
 	OpenTelemetry OpenTelemetryConfig
+	RateLimit     RateLimitConfig
+	Compression   CompressionConfig
+	BodyLogging   BodyLoggingConfig
+	Recording     TrafficRecordingConfig
+	HTTPCache     HTTPCacheConfig
+	Secrets       SecretsConfig
+	Retention     RetentionConfig
+	SLO           SLOConfig
+	Webhook       WebhookConfig
+	HTTPClient    HTTPClientConfig
+	Auth          AuthConfig
+	Redaction     RedactionConfig
+	Payload       PayloadConfig
+	Forecast      ForecastConfig
+	Heatmap       HeatmapConfig
+	Dashboard     DashboardConfig
+	Chat          ChatConfig
+	Reporting     ReportingConfig
+	Fare          FareConfig
+	PickupWait    PickupWaitConfig
+	Cancellation  CancellationPolicyConfig
+	Rematch       RematchConfig
+	Matching      MatchingConfig
+	Timeout       TimeoutConfig
+	Location      LocationIngestConfig
+	Payments      PaymentReconciliationConfig
+	Fatigue       FatigueConfig
+	Onboarding    OnboardingConfig
+	ServiceArea   ServiceAreaConfig
+	Lock          LockConfig
+	Leader        LeaderConfig
+	Storage       StorageConfig
+	Invoice       InvoiceConfig
+	Experiment    ExperimentConfig
+	ETA           ETAConfig
+	Accessibility AccessibilityConfig
+	LocationTrail LocationTrailConfig
+	Fraud         FraudConfig
+	APIUsage      APIUsageConfig
+	Research      ResearchExportConfig
+	Archive       MetricsArchiveConfig
+	Email         EmailConfig
+	AppVersion    AppVersionConfig
+	Integrity     IntegrityCheckConfig
+	TripCache     TripCacheConfig
 }
 
 // ServerConfig holds HTTP server configuration
@@ -30,8 +78,10 @@ type ServerConfig struct {
 	Mode         string // gin mode: debug, release, test
 }
 
-// DatabaseConfig holds PostgreSQL database configuration
+// DatabaseConfig holds database configuration
 type DatabaseConfig struct {
+	Driver          string // postgres, sqlite or memory
+	SQLitePath      string // database file used by the sqlite driver
 	Host            string
 	Port            string
 	User            string
@@ -42,10 +92,26 @@ type DatabaseConfig struct {
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
 	ConnMaxIdleTime time.Duration
+
+	// Optional read replica used for read-heavy observability queries
+	ReplicaDSN                 string
+	ReplicaHealthCheckInterval time.Duration
+	ReplicaMaxLag              time.Duration // replication lag beyond which reads go to the primary; zero ignores lag
+
+	// Schema drift detection against the models and the migrations in MigrationsDir
+	SchemaCheckOnStartup bool
+	MigrationsDir        string
+
+	// Notifications of changes to the drivers and trips tables, including those made outside the
+	// API, which invalidate cached driver locations and update trip status streams
+	ChangeNotifications        bool
+	ChangeListenerMinReconnect time.Duration // first wait before reconnecting a lost listener connection
+	ChangeListenerMaxReconnect time.Duration // longest wait between reconnection attempts
 }
 
 // RedisConfig holds Redis configuration
 type RedisConfig struct {
+	Enabled      bool // when false the service runs without a Redis cache
 	Host         string
 	Port         string
 	Password     string
@@ -55,12 +121,45 @@ type RedisConfig struct {
 	DialTimeout  time.Duration
 	ReadTimeout  time.Duration
 	WriteTimeout time.Duration
+
+	// RealtimeStreams publishes real-time messages and metrics to Redis Streams, which consumer
+	// groups read independently, instead of individual keys and lists
+	RealtimeStreams bool
+	StreamMaxLen    int // approximate number of entries each stream is trimmed to
 }
 
 // ActorConfig holds actor system configuration
 type ActorConfig struct {
 	MaxActors           int
 	SupervisionStrategy string // restart, stop, ignore
+	Retry               RetryConfig
+
+	// PoolSizes is the number of trip and matching actors pre-spawned at startup, by actor type.
+	// Types without a pool spawn an actor for every ride.
+	PoolSizes map[string]int
+	// MaxActorsByType limits the actors of a type alive at once, pooled ones included
+	MaxActorsByType map[string]int
+	// GoroutineLeakGrace is how long an actor's goroutines may outlive its Stop before they are
+	// reported as leaked
+	GoroutineLeakGrace time.Duration
+	// IdleTimeout is how long an actor may stay idle before it is passivated, stopped until its
+	// next message revives it; zero disables passivation. Pooled actors are never passivated.
+	IdleTimeout time.Duration
+	// IdleTimeoutsByType overrides IdleTimeout by actor type
+	IdleTimeoutsByType map[string]time.Duration
+}
+
+// PooledActorTypes are the actor types that can be pre-spawned into a warm pool
+var PooledActorTypes = []string{"trip", "matching"}
+
+// RetryConfig holds the retry policy for failed actor messages
+type RetryConfig struct {
+	MaxAttempts       int // total attempts before a message is dead-lettered
+	InitialBackoff    time.Duration
+	MaxBackoff        time.Duration
+	Multiplier        float64
+	Jitter            float64        // fraction of the backoff randomized, from 0 to 1
+	MaxAttemptsByType map[string]int // per message type overrides of MaxAttempts
 }
 
 // LoggingConfig holds logging configuration
@@ -80,6 +179,18 @@ type LoggingConfig struct {
 // ObservabilityConfig holds observability configuration
 type ObservabilityConfig struct {
 	MetricsInterval time.Duration
+
+	EventBusBufferSize  int           // messages queued per event bus subscriber before dropping
+	AlertErrorThreshold int           // error events within AlertWindow that raise an alert; 0 disables alerting
+	AlertWindow         time.Duration // rolling window error events are counted over
+
+	SlowQueryThreshold time.Duration // repository calls at least this slow are logged and ranked; 0 disables
+	SlowQueryTopN      int           // slowest calls kept for GET /observability/slow-queries
+
+	MaxTimeRange time.Duration // longest start to end range the time-range endpoints accept; 0 accepts any
+
+	ClockSource  string        // names this instance in hybrid timestamps; the host name if empty
+	ClockMaxSkew time.Duration // how far ahead of the local clock other clocks may be before skew is reported; 0 disables
 }
 
 // MetricsConfig holds metrics collection configuration
@@ -104,10 +215,531 @@ type OpenTelemetryConfig struct {
 	SampleRate         float64
 	MetricsInterval    time.Duration
 	ResourceAttributes map[string]string
+	RepositoryTracing  bool // wrap every repository call in a span and latency histogram
+	Histograms         HistogramConfig
+}
+
+// HistogramConfig holds the bucket boundaries of the latency histograms, in seconds, so that
+// experiments with very fast actors are not all counted in the lowest bucket. With native
+// histograms, Prometheus servers scraping them also get sparse exponential buckets, which resolve
+// latencies of any magnitude whatever the boundaries.
+type HistogramConfig struct {
+	MatchingBuckets          []float64 // ride_matching_duration_seconds, from request to driver match
+	TripDurationBuckets      []float64 // trip_duration_seconds, from pickup to completion
+	MessageProcessingBuckets []float64 // actor_message_processing_duration_seconds
+	MatchingQueueWaitBuckets []float64 // matching_queue_wait_seconds, from a traditional request being queued to a worker taking it
+	NativeHistograms         bool
+	NativeBucketFactor       float64 // largest ratio between consecutive native buckets, e.g. 1.1
+}
+
+// SLOConfig holds the per-endpoint service level objectives
+type SLOConfig struct {
+	Window     time.Duration // rolling window compliance and error budgets are computed over
+	Objectives []SLOObjective
+}
+
+// SLOObjective defines the latency and availability targets of one endpoint
+type SLOObjective struct {
+	Method                string
+	Endpoint              string        // route pattern, e.g. /api/v1/rides/:id/status
+	LatencyTarget         time.Duration // requests slower than this count against the latency SLO
+	LatencyObjective      float64       // fraction of requests that must meet LatencyTarget
+	AvailabilityObjective float64       // fraction of requests that must not fail with a 5xx
+}
+
+// WebhookConfig holds the delivery settings for trip lifecycle webhooks
+type WebhookConfig struct {
+	Timeout        time.Duration // per request timeout when calling a subscriber
+	PollInterval   time.Duration // how often due deliveries are picked up
+	BatchSize      int           // deliveries attempted per poll
+	MaxAttempts    int           // total attempts before a delivery is marked failed
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// HTTPClientConfig holds the settings of the outbound HTTP client shared by the integrations,
+// such as webhook delivery. Retries, the retry budget and the circuit breaker apply per
+// destination host.
+type HTTPClientConfig struct {
+	Timeout          time.Duration // per attempt timeout
+	MaxRetries       int           // retries of a failed idempotent request after the first attempt
+	InitialBackoff   time.Duration
+	MaxBackoff       time.Duration
+	RetryBudgetRatio float64       // retries earned by every request sent to a destination, e.g. 0.2
+	RetryBudgetBurst int           // most retries a destination can bank; also its starting budget
+	BreakerFailures  int           // consecutive failures opening a destination's circuit; 0 disables it
+	BreakerCooldown  time.Duration // how long an open circuit fails fast before a trial request
+}
+
+// AuthConfig holds driver and passenger device session settings
+type AuthConfig struct {
+	AccessTokenTTL     time.Duration // lifetime of the access token sent on API calls
+	RefreshTokenTTL    time.Duration // lifetime of a session; refreshing does not extend it
+	MaxSessionsPerUser int           // concurrent sessions per user; the least recently used is revoked beyond it
+}
+
+// RedactionConfig holds the rules applied to actor message payloads and event data returned
+// by observability endpoints. Callers presenting one of OperatorKeys see unredacted data.
+type RedactionConfig struct {
+	Enabled      bool
+	Rules        []RedactionRule
+	HashKey      string   // HMAC key for hashed values; a random key is used when empty
+	OperatorKeys []string // X-Operator-Key values granting the operator role
+}
+
+// PayloadConfig limits the size of the actor message payloads and event data persisted by the
+// observability pipeline. Larger documents are truncated or compressed before they are stored.
+type PayloadConfig struct {
+	MaxSize              int // bytes stored per payload, after compression; larger payloads are truncated
+	CompressionThreshold int // payloads larger than this many bytes are gzip compressed; 0 disables compression
+	PreviewSize          int // bytes of a truncated payload kept as a preview
+}
+
+// ForecastConfig holds the settings of the ride demand forecasts built from historical trips
+type ForecastConfig struct {
+	HistoryWindow       time.Duration // trips requested within this window before now are used
+	MaxHorizon          int           // furthest forecast, in hours
+	MovingAverageWindow int           // hours averaged by the moving average model
+	Alpha               float64       // Holt-Winters level smoothing, between 0 and 1
+	Beta                float64       // Holt-Winters trend smoothing, between 0 and 1
+	Gamma               float64       // Holt-Winters daily seasonality smoothing, between 0 and 1
+}
+
+// HeatmapConfig holds the settings of the trip origin/destination heatmaps
+type HeatmapConfig struct {
+	DefaultRange time.Duration // period covered when no start is given
+	MaxRange     time.Duration // longest period a heatmap may cover
+	CacheTTL     time.Duration // how long heatmaps are cached in Redis; 0 disables caching
+}
+
+// TripCacheConfig holds the Redis cache of active trips read by the ride status polls. Trips are
+// written through on every change made by this service and dropped once completed or cancelled;
+// the TTL bounds how stale a trip changed by other means can be read when database change
+// notifications are off.
+type TripCacheConfig struct {
+	TTL time.Duration // how long an active trip is cached; 0 disables caching
+}
+
+// DashboardConfig holds the refresh schedule of the precomputed dashboard summaries
+type DashboardConfig struct {
+	TripRefreshInterval   time.Duration // how often the hourly trip and matching time summaries are refreshed
+	SupplyRefreshInterval time.Duration // how often the zone supply summary is refreshed
+	RefreshWindow         time.Duration // hours recomputed by each refresh; older hours are kept as they are
+	MaxHours              int           // longest range of hourly summaries served
+	ZonePrecision         int           // geohash length of the supply zones
+}
+
+// ChatConfig holds the settings of the in-trip chat between passengers and drivers
+type ChatConfig struct {
+	Filters         []string      // filters message bodies pass through, in order: profanity, pii
+	ProfanityWords  []string      // words masked by the profanity filter
+	RetentionPeriod time.Duration // messages are purged this long after their trip completed or was cancelled
+	PurgeInterval   time.Duration // how often expired messages are purged
+}
+
+// ReportingConfig holds the settings of the reports aggregated by day, such as the admin daily
+// summary. Fleets set their own timezone for their exports.
+type ReportingConfig struct {
+	Timezone string // IANA timezone days are computed in when a report request names none
+	MaxDays  int    // most days a daily report may cover
+}
+
+// FareConfig holds how final fares are computed from the distance a driver reports when
+// completing a trip, and the deviations from the requested route flagged for review
+type FareConfig struct {
+	BaseFare              float64 // flat amount of every fare
+	PerKm                 float64 // rate per billed kilometer
+	MaxDetourRatio        float64 // odometer distances beyond this multiple of the straight line from pickup to dropoff are flagged
+	MaxDropoffDeviationKm float64 // actual dropoffs farther than this from the requested destination are flagged
+	MaxFareDeviation      float64 // final fares deviating from the estimate by more than this fraction of it are flagged
+}
+
+// PickupWaitConfig holds how long drivers wait for passengers at the pickup and what the wait costs
+type PickupWaitConfig struct {
+	FreePeriod    time.Duration // waiting up to this long after the driver arrives is free
+	FeePerMinute  float64       // charged per started minute of waiting past the free period
+	NoShowWindow  time.Duration // after this long the driver may report the passenger as a no-show
+	NoShowFee     float64       // charged for a no-show instead of the fare
+	ZonePrecision int           // geohash length of the zones wait-time metrics are grouped by
+}
+
+// CancellationPolicyConfig holds what passengers are charged for cancelling a ride
+type CancellationPolicyConfig struct {
+	GracePeriod time.Duration // cancelling up to this long after a driver is matched is free
+	Fee         float64       // charged for cancelling after the grace period, before the driver arrives
+	ArrivedFee  float64       // charged for cancelling once the driver has arrived
+	FreeReasons []string      // cancellation reasons never charged, such as the driver not moving
+}
+
+// RematchConfig holds how trips whose matched driver cancels before pickup are matched again
+type RematchConfig struct {
+	MaxRematches int // re-matches of a trip before a driver cancelling cancels the trip; 0 never re-matches
+}
+
+// MatchingConfig holds the worker pool matching ride requests in traditional mode. Requests wait
+// in a bounded queue for a worker, so under load they are turned away instead of all scanning the
+// drivers at once.
+type MatchingConfig struct {
+	Workers      int           // requests matched concurrently
+	QueueSize    int           // requests waiting for a worker before new ones are rejected
+	QueueTimeout time.Duration // longest a request waits for a worker before it is given up on
+}
+
+// TimeoutConfig holds the time budgets of HTTP requests. A request's budget is its context
+// deadline, so services, repositories and actor sends give up once it is spent and the client gets
+// a 504 instead of waiting on work nobody will use.
+type TimeoutConfig struct {
+	Default   time.Duration // budget of endpoints without their own, 0 for none
+	Endpoints []EndpointTimeout
+}
+
+// EndpointTimeout is the time budget of one endpoint
+type EndpointTimeout struct {
+	Method   string
+	Endpoint string // route pattern, e.g. /api/v1/rides/:id/status
+	Timeout  time.Duration
+}
+
+// LocationIngestConfig holds the buffering of driver location updates. Only the latest update of
+// each driver is kept and written to the database on an interval, while Redis, when configured,
+// gets it immediately so matching sees where drivers are now.
+type LocationIngestConfig struct {
+	Enabled       bool          // when false every update is written to the database as it comes
+	FlushInterval time.Duration // how often buffered locations are written to the database
+	MaxPending    int           // drivers buffered before a write is started early
+	CacheTTL      time.Duration // how long a location is kept in Redis after the driver's last update
+}
+
+// PaymentReconciliationConfig holds the periodic check of the trip payments ledger against the
+// fares of completed trips. Trips are checked once their grace period is over, so payments still
+// being captured are not reported, and for as long as the lookback.
+type PaymentReconciliationConfig struct {
+	Interval    time.Duration // how often completed trips are reconciled
+	GracePeriod time.Duration // time after completion a trip's payment has to be recorded
+	Lookback    time.Duration // how far back completed trips are reconciled
+	Tolerance   float64       // difference between the fare and the amount paid ignored as rounding
+}
+
+// ETAConfig holds the ETA model, which assumes an average speed over the straight-line distance
+// left, and the feedback loop measuring the ETAs given at matching time against the drivers'
+// actual arrivals at the pickup
+type ETAConfig struct {
+	AverageSpeedKmh  float64       // speed the ETA model starts with
+	ZonePrecision    int           // geohash length of the zones accuracy metrics are grouped by
+	AccuracyWindow   time.Duration // matches the accuracy metrics are computed over, back from now
+	AccuracyInterval time.Duration // how often the accuracy metrics are recomputed
+	AutoAdjust       bool          // move the average speed toward the observed one on every recompute
+	AdjustRate       float64       // fraction of the gap to the observed speed closed per adjustment
+	MinSamples       int           // arrivals in the window needed before the speed is adjusted
+	MinSpeedKmh      float64       // bounds of the adjusted speed
+	MaxSpeedKmh      float64
+}
+
+// AccessibilityConfig holds the tracking of rides requiring special-assistance vehicles
+type AccessibilityConfig struct {
+	ZonePrecision int // geohash length of the zones accessible ride supply and demand metrics are grouped by
+}
+
+// LocationTrailConfig holds the storage of the location pings drivers report, kept as their
+// location trail for support and fraud investigations. Pings are downsampled as they age, to one
+// per minute after MinuteAfter and one per ten minutes after TenMinutesAfter, and dropped with
+// their daily partition after the retention period.
+type LocationTrailConfig struct {
+	RetentionPeriod    time.Duration // how long pings are kept
+	DownsampleInterval time.Duration // how often aged pings are downsampled
+	MinuteAfter        time.Duration // age after which pings are kept at one per minute
+	TenMinutesAfter    time.Duration // age after which pings are kept at one per ten minutes
+	MaxPoints          int           // pings returned by a trail query at most
 }
 
-// Load loads configuration from environment variables with defaults
+// FraudConfig holds the thresholds of the fraud detection rules run on trips and driver
+// locations
+type FraudConfig struct {
+	MaxTripSpeedKmh       float64       // average speed above which a completed trip is impossible
+	TeleportSpeedKmh      float64       // speed between two location pings above which a driver teleported
+	TeleportMinDistanceKm float64       // jumps shorter than this are GPS noise, never teleports
+	CancellationWindow    time.Duration // cancellations after the match counted back from the latest
+	CancellationThreshold int           // cancellations after the match in the window that raise a signal
+	PairWindow            time.Duration // completed trips of a passenger with the same driver counted back from now
+	PairThreshold         int           // completed trips with the same driver in the window that raise a signal
+}
+
+// APIUsageConfig holds the recording of the requests made with fleet API keys and their daily
+// request quotas. Each instance rolls usage up in memory and flushes it every interval, so usage
+// reports and other instances' quota checks lag by up to one interval.
+type APIUsageConfig struct {
+	FlushInterval     time.Duration // how often the usage rolled up in memory is stored
+	DefaultDailyQuota int64         // requests per UTC day allowed to keys without a quota of their own; 0 for unlimited
+	RetentionPeriod   time.Duration // how long usage rollups are kept
+}
+
+// ResearchExportConfig declares how the trips dataset exported for research is anonymized. Only
+// the columns with a rule are exported, in the order of the rules. Trips whose pickup and dropoff
+// cells are shared by fewer than KAnonymity trips of the export are left out.
+type ResearchExportConfig struct {
+	Columns          []AnonymizationRule
+	GeohashPrecision int           // precision of the geohash cells pickups and dropoffs are generalized to
+	KAnonymity       int           // fewest trips that may share the pickup and dropoff cells of an exported trip
+	TimestampJitter  time.Duration // jittered timestamps of a trip are all shifted by the same random offset up to this, either way
+	HashKey          string        // HMAC key for hashed IDs and jitter offsets; a random key is used when empty
+	MaxPeriod        time.Duration // longest period of trip requests exported at once
+}
+
+// MetricsArchiveConfig holds the archive of system and traditional metrics as Parquet files, one
+// per table and finished UTC day, for offline analysis with pandas or DuckDB without querying
+// the database
+type MetricsArchiveConfig struct {
+	Enabled       bool
+	Sink          string        // local, a directory, or storage, the file storage such as S3
+	LocalDir      string        // directory of the local sink
+	Prefix        string        // key prefix of the archived files
+	CheckInterval time.Duration // how often the archiver checks for days to archive
+	BackfillDays  int           // finished days before today that are archived when missing
+	BatchSize     int           // metrics read per query and written per row group
+}
+
+// EmailConfig holds the email notification channel: trip receipts, weekly earnings summaries
+// and password resets rendered from templates and sent over SMTP from an in-memory queue, with
+// failed sends retried with exponential backoff
+type EmailConfig struct {
+	Enabled        bool
+	SMTPHost       string
+	SMTPPort       int
+	Username       string        // SMTP AUTH PLAIN user; the server is not authenticated with when empty
+	Password       string        // SMTP AUTH PLAIN password
+	From           string        // sender address, e.g. "Rides <no-reply@example.com>"
+	Timeout        time.Duration // per message timeout talking to the SMTP server
+	QueueSize      int           // messages waiting to be sent; new messages are dropped while full
+	Workers        int           // messages sent at once
+	MaxAttempts    int           // total send attempts before a message is given up on
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Receipts       bool // email passengers a receipt when their trip completes
+}
+
+// AppVersionConfig holds the versions driver and passenger apps must run. Apps report their
+// platform and version on every request; requests from versions below their platform's minimum
+// are rejected with 426 Upgrade Required, and features are only offered to the versions they
+// are enabled for, so that they can be rolled out progressively.
+type AppVersionConfig struct {
+	MinVersions     map[string]string // minimum version per platform, e.g. ios=2.3.0; other platforms are not gated
+	FeatureVersions map[string]string // minimum version per feature, e.g. offer_websocket=2.4.0
+}
+
+// IntegrityCheckConfig holds the periodic integrity check of the observability tables. Every
+// interval the rows of each table are counted and checksummed per hour over the finished hours of
+// the lookback; hours with fewer rows than in the run before lost rows, e.g. to dropped batches.
+// The lookback should stay within the tables' retention, or rows removed by retention are
+// reported as lost.
+type IntegrityCheckConfig struct {
+	Enabled         bool
+	Interval        time.Duration // how often the tables are checksummed
+	Lookback        time.Duration // finished hours checksummed, rounded up to whole hours
+	RetentionPeriod time.Duration // how long runs and their checksums are kept
+}
+
+// AnonymizationRule exports a trip column anonymized with an action
+type AnonymizationRule struct {
+	Column string
+	Action string // hash, geohash, jitter, hour, round or keep; see researchExportColumnActions
+}
+
+// researchExportColumnActions are the actions each column of the research dataset may be
+// anonymized with
+var researchExportColumnActions = map[string][]string{
+	"trip_id":          {"hash"},
+	"passenger_id":     {"hash"},
+	"driver_id":        {"hash"},
+	"status":           {"keep"},
+	"pickup":           {"geohash"},
+	"dropoff":          {"geohash"},
+	"requested_at":     {"jitter", "hour"},
+	"matched_at":       {"jitter", "hour"},
+	"pickup_at":        {"jitter", "hour"},
+	"completed_at":     {"jitter", "hour"},
+	"cancelled_at":     {"jitter", "hour"},
+	"distance_km":      {"keep", "round"},
+	"duration_minutes": {"keep"},
+	"fare_amount":      {"keep", "round"},
+}
+
+// FatigueConfig holds the limits on how long drivers may be online and driving over a rolling
+// window before they are set offline to rest
+type FatigueConfig struct {
+	Enabled       bool
+	Window        time.Duration // rolling window the time online and driving is summed over
+	MaxOnline     time.Duration // most time online or busy within the window
+	MaxDriving    time.Duration // most time busy with trips within the window
+	RestPeriod    time.Duration // how long a driver stays offline after reaching a limit
+	CheckInterval time.Duration // how often online drivers are checked against the limits
+}
+
+// OnboardingConfig holds whether drivers must complete onboarding before going online
+type OnboardingConfig struct {
+	RequireActivation bool // drivers whose onboarding is not activated cannot go online
+}
+
+// LockConfig holds the settings of the Redis locks coordinating work across instances. Locks are
+// taken on a majority of the nodes; without Addresses the single configured Redis is used.
+type LockConfig struct {
+	Addresses      []string      // host:port of independent Redis nodes, e.g. 3 or 5 of them
+	KeyPrefix      string        // prefix of the lock keys, e.g. lock:
+	TTL            time.Duration // how long a lock outlives its holder; held locks are renewed every TTL/3
+	RetryInterval  time.Duration // how often a held lock is tried again while acquiring
+	AcquireTimeout time.Duration // how long acquiring waits for a held lock
+}
+
+// LeaderConfig holds how the instance running the singleton background workers, such as
+// partition maintenance and the dashboard refresh, is elected among the replicas
+type LeaderConfig struct {
+	Backend       string        // redis, postgres, none (every instance leads) or auto, the first available
+	RetryInterval time.Duration // how often followers try to take over leadership
+}
+
+// StorageConfig holds where uploaded files, such as profile pictures, are stored and how long the
+// signed URLs they are retrieved by stay valid
+type StorageConfig struct {
+	Backend       string        // local or s3, any S3-compatible object store
+	LocalDir      string        // directory of the local backend
+	SigningKey    string        // HMAC key of the local backend's signed URLs; a random key is used when empty
+	URLTTL        time.Duration // how long signed URLs stay valid
+	MaxAvatarSize int           // largest profile picture accepted, in bytes
+
+	S3Endpoint  string // e.g. https://s3.eu-west-1.amazonaws.com or http://localhost:9000
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PathStyle bool // address the bucket in the path rather than the host name, as MinIO expects
+}
+
+// InvoiceConfig holds the monthly invoice batch job, which generates the invoices of every
+// corporate account and fleet partner for the previous month and stores them in the file storage
+type InvoiceConfig struct {
+	Formats       []string      // file formats invoices are generated in: csv, pdf
+	CheckInterval time.Duration // how often the job checks for invoices to generate or retry
+	MaxAttempts   int           // generation attempts of an invoice before the job gives up on it
+}
+
+// ExperimentConfig holds the experiment datasets. Each experiment run gets its own PostgreSQL
+// schema with a copy of the observability tables, so its records can be told apart from others and
+// dropped wholesale; the active experiment is switched at runtime through the admin API.
+type ExperimentConfig struct {
+	SchemaPrefix string // prefix of experiment schema names, e.g. experiment_ for experiment_baseline
+	Active       string // experiment observability records are written to on startup; empty for none
+}
+
+// ServiceAreaConfig holds where and when rides may be requested. Requests picking up or dropping
+// off outside every area, or made outside the operating hours, are rejected.
+type ServiceAreaConfig struct {
+	Enabled  bool
+	Areas    []ServiceArea    // none serves every location
+	Timezone string           // IANA timezone the operating hours are in
+	Hours    []OperatingHours // none is always open
+}
+
+// ServiceArea is a named polygon rides are served in
+type ServiceArea struct {
+	Name    string
+	Polygon []GeoPoint // vertices in order; the last connects back to the first
+}
+
+// GeoPoint is a latitude/longitude pair
+type GeoPoint struct {
+	Lat float64
+	Lng float64
+}
+
+// OperatingHours opens the service on some weekdays between two local times. A close time
+// before the open time runs past midnight into the next day.
+type OperatingHours struct {
+	Days  []time.Weekday // none for every day
+	Open  time.Duration  // since local midnight
+	Close time.Duration  // since local midnight, at most 24h
+}
+
+// RedactionRule redacts the fields whose key path ends with Path, e.g. "phone" or "pickup.*_lat"
+type RedactionRule struct {
+	Path   string
+	Action string // remove, hash or round
+}
+
+// RateLimitConfig holds HTTP rate limiting configuration
+type RateLimitConfig struct {
+	RequestsPerMinute int
+	Burst             int
+}
+
+// CompressionConfig holds HTTP response compression configuration
+type CompressionConfig struct {
+	Enabled       bool
+	Level         int      // gzip level, 1 (fastest) to 9 (smallest)
+	MinSize       int      // responses smaller than this many bytes are sent uncompressed
+	ExcludedPaths []string // paths never compressed, e.g. Prometheus scrape endpoints
+}
+
+// BodyLoggingConfig holds the sampling of request and response bodies into the traditional logs
+// for debugging. Routes can also be switched on or off at runtime through the admin config API.
+type BodyLoggingConfig struct {
+	Enabled     bool
+	SampleRate  float64  // fraction of the requests to logged routes whose bodies are captured
+	MaxBodySize int      // bodies larger than this many bytes are left out of the log
+	Routes      []string // route templates logged, e.g. /api/v1/rides/:id; empty logs every route
+}
+
+// TrafficRecordingConfig holds the recording of request traces to a file, replayed by
+// cmd/load-test -replay for regression load tests. Only the method, path, route, content type and
+// redacted JSON body of a request are kept, never its headers.
+type TrafficRecordingConfig struct {
+	Enabled       bool
+	Path          string   // recording file, truncated at startup
+	MaxBodySize   int      // requests with larger bodies are not recorded
+	MaxRequests   int64    // recording stops after this many requests, bounding the file size
+	ExcludedPaths []string // paths never recorded, e.g. health checks and Prometheus scrapes
+}
+
+// HTTPCacheConfig holds ETag and Cache-Control settings for read endpoints, per route group
+type HTTPCacheConfig struct {
+	Enabled             bool
+	UsersCacheControl   string
+	DriversCacheControl string
+	RidesCacheControl   string
+}
+
+// RetentionConfig holds data retention and partition maintenance configuration. Each partitioned
+// observability table has a retention period of its own; zero keeps Metrics.RetentionPeriod.
+type RetentionConfig struct {
+	MaintenanceInterval  time.Duration
+	PartitionPremakeDays int
+	ActorMessagesPeriod  time.Duration // how long actor_messages partitions are kept
+	EventLogsPeriod      time.Duration // how long event_logs partitions are kept
+	SystemMetricsPeriod  time.Duration // how long system_metrics partitions are kept
+}
+
+// SecretsConfig holds configuration for loading credentials from a secret provider
+type SecretsConfig struct {
+	Provider   string // env, file, vault
+	Dir        string // directory of secret files for the file provider
+	VaultAddr  string
+	VaultToken string
+	VaultPath  string // KV v2 API path, e.g. secret/data/actor-observability
+}
+
+// Load loads configuration from environment variables with defaults. Variables not set in the
+// environment are read from the env file named by CONFIG_ENV_FILE, if any.
 func Load() (*Config, error) {
+	loadMu.Lock()
+	defer loadMu.Unlock()
+
+	if path := os.Getenv(EnvFileKey); path != "" {
+		values, err := readEnvFile(path)
+		if err != nil {
+			return nil, err
+		}
+		fileValues = values
+		defer func() { fileValues = nil }()
+	}
+
 	config := &Config{
 		Server: ServerConfig{
 			Port:         getEnv("SERVER_PORT", "8080"),
@@ -118,6 +750,8 @@ func Load() (*Config, error) {
 			Mode:         getEnv("GIN_MODE", "debug"),
 		},
 		Database: DatabaseConfig{
+			Driver:          getEnv("DB_DRIVER", "postgres"),
+			SQLitePath:      getEnv("DB_SQLITE_PATH", "./data/actor_observability.db"),
 			Host:            getEnv("DB_HOST", "localhost"),
 			Port:            getEnv("DB_PORT", "5432"),
 			User:            getEnv("DB_USER", "postgres"),
@@ -128,8 +762,20 @@ func Load() (*Config, error) {
 			MaxIdleConns:    getIntEnv("DB_MAX_IDLE_CONNS", 5),
 			ConnMaxLifetime: getDurationEnv("DB_CONN_MAX_LIFETIME", 5*time.Minute),
 			ConnMaxIdleTime: getDurationEnv("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+
+			ReplicaDSN:                 getEnv("DB_REPLICA_DSN", ""),
+			ReplicaHealthCheckInterval: getDurationEnv("DB_REPLICA_HEALTH_CHECK_INTERVAL", 10*time.Second),
+			ReplicaMaxLag:              getDurationEnv("DB_REPLICA_MAX_LAG", 30*time.Second),
+
+			SchemaCheckOnStartup: getBoolEnv("DB_SCHEMA_CHECK_ON_STARTUP", true),
+			MigrationsDir:        getEnv("DB_MIGRATIONS_DIR", "migrations"),
+
+			ChangeNotifications:        getBoolEnv("DB_CHANGE_NOTIFICATIONS", true),
+			ChangeListenerMinReconnect: getDurationEnv("DB_CHANGE_LISTENER_MIN_RECONNECT", time.Second),
+			ChangeListenerMaxReconnect: getDurationEnv("DB_CHANGE_LISTENER_MAX_RECONNECT", time.Minute),
 		},
 		Redis: RedisConfig{
+			Enabled:      getBoolEnv("REDIS_ENABLED", true),
 			Host:         getEnv("REDIS_HOST", "localhost"),
 			Port:         getEnv("REDIS_PORT", "6379"),
 			Password:     getEnv("REDIS_PASSWORD", ""),
@@ -139,10 +785,27 @@ func Load() (*Config, error) {
 			DialTimeout:  getDurationEnv("REDIS_DIAL_TIMEOUT", 5*time.Second),
 			ReadTimeout:  getDurationEnv("REDIS_READ_TIMEOUT", 3*time.Second),
 			WriteTimeout: getDurationEnv("REDIS_WRITE_TIMEOUT", 3*time.Second),
+
+			RealtimeStreams: getBoolEnv("REDIS_REALTIME_STREAMS", false),
+			StreamMaxLen:    getIntEnv("REDIS_STREAM_MAX_LEN", 10000),
 		},
 		Actor: ActorConfig{
 			MaxActors:           getIntEnv("ACTOR_MAX_ACTORS", 10000),
 			SupervisionStrategy: getEnv("ACTOR_SUPERVISION_STRATEGY", "restart"),
+			Retry: RetryConfig{
+				MaxAttempts:       getIntEnv("ACTOR_RETRY_MAX_ATTEMPTS", 3),
+				InitialBackoff:    getDurationEnv("ACTOR_RETRY_INITIAL_BACKOFF", 100*time.Millisecond),
+				MaxBackoff:        getDurationEnv("ACTOR_RETRY_MAX_BACKOFF", 5*time.Second),
+				Multiplier:        getFloatEnv("ACTOR_RETRY_MULTIPLIER", 2),
+				Jitter:            getFloatEnv("ACTOR_RETRY_JITTER", 0.2),
+				MaxAttemptsByType: getIntMapEnv("ACTOR_RETRY_MAX_ATTEMPTS_BY_TYPE"),
+			},
+			PoolSizes:       getIntMapEnv("ACTOR_POOL_SIZES"),
+			MaxActorsByType: getIntMapEnv("ACTOR_MAX_ACTORS_BY_TYPE"),
+
+			GoroutineLeakGrace: getDurationEnv("ACTOR_GOROUTINE_LEAK_GRACE", 30*time.Second),
+			IdleTimeout:        getDurationEnv("ACTOR_IDLE_TIMEOUT", 0),
+			IdleTimeoutsByType: getDurationMapEnv("ACTOR_IDLE_TIMEOUTS_BY_TYPE"),
 		},
 		Logging: LoggingConfig{
 			Level:          getEnv("LOG_LEVEL", "info"),
@@ -158,6 +821,18 @@ func Load() (*Config, error) {
 		},
 		Observability: ObservabilityConfig{
 			MetricsInterval: getDurationEnv("METRICS_INTERVAL", 10*time.Second),
+
+			EventBusBufferSize:  getIntEnv("EVENT_BUS_BUFFER_SIZE", 1024),
+			AlertErrorThreshold: getIntEnv("ALERT_ERROR_THRESHOLD", 20),
+			AlertWindow:         getDurationEnv("ALERT_WINDOW", time.Minute),
+
+			SlowQueryThreshold: getDurationEnv("SLOW_QUERY_THRESHOLD", 200*time.Millisecond),
+			SlowQueryTopN:      getIntEnv("SLOW_QUERY_TOP_N", 50),
+
+			MaxTimeRange: getDurationEnv("MAX_TIME_RANGE", 7*24*time.Hour),
+
+			ClockSource:  getEnv("CLOCK_SOURCE", ""),
+			ClockMaxSkew: getDurationEnv("CLOCK_MAX_SKEW", 500*time.Millisecond),
 		},
 		Metrics: MetricsConfig{
 			CollectInterval: getDurationEnv("METRICS_COLLECT_INTERVAL", 30*time.Second),
@@ -178,7 +853,310 @@ func Load() (*Config, error) {
 			SampleRate:         getFloatEnv("OTEL_SAMPLE_RATE", 1.0),
 			MetricsInterval:    getDurationEnv("OTEL_METRICS_INTERVAL", 10*time.Second),
 			ResourceAttributes: getMapEnv("OTEL_RESOURCE_ATTRIBUTES"),
+			RepositoryTracing:  getBoolEnv("OTEL_REPOSITORY_TRACING", true),
+			Histograms: HistogramConfig{
+				MatchingBuckets:          getFloatSliceEnv("OTEL_HISTOGRAM_MATCHING_BUCKETS", DefaultHistogramConfig().MatchingBuckets),
+				TripDurationBuckets:      getFloatSliceEnv("OTEL_HISTOGRAM_TRIP_DURATION_BUCKETS", DefaultHistogramConfig().TripDurationBuckets),
+				MessageProcessingBuckets: getFloatSliceEnv("OTEL_HISTOGRAM_MESSAGE_PROCESSING_BUCKETS", DefaultHistogramConfig().MessageProcessingBuckets),
+				MatchingQueueWaitBuckets: getFloatSliceEnv("OTEL_HISTOGRAM_MATCHING_QUEUE_WAIT_BUCKETS", DefaultHistogramConfig().MatchingQueueWaitBuckets),
+				NativeHistograms:         getBoolEnv("OTEL_HISTOGRAM_NATIVE", false),
+				NativeBucketFactor:       getFloatEnv("OTEL_HISTOGRAM_NATIVE_BUCKET_FACTOR", 1.1),
+			},
+		},
+		RateLimit: RateLimitConfig{
+			RequestsPerMinute: getIntEnv("RATE_LIMIT_REQUESTS_PER_MINUTE", 100),
+			Burst:             getIntEnv("RATE_LIMIT_BURST", 10),
+		},
+		Compression: CompressionConfig{
+			Enabled:       getBoolEnv("COMPRESSION_ENABLED", true),
+			Level:         getIntEnv("COMPRESSION_LEVEL", 5),
+			MinSize:       getIntEnv("COMPRESSION_MIN_SIZE", 1024),
+			ExcludedPaths: getStringSliceEnv("COMPRESSION_EXCLUDED_PATHS", []string{"/prometheus"}),
+		},
+		BodyLogging: BodyLoggingConfig{
+			Enabled:     getBoolEnv("BODY_LOGGING_ENABLED", false),
+			SampleRate:  getFloatEnv("BODY_LOGGING_SAMPLE_RATE", 0.01),
+			MaxBodySize: getIntEnv("BODY_LOGGING_MAX_BODY_SIZE", 4096),
+			Routes:      getStringSliceEnv("BODY_LOGGING_ROUTES", nil),
+		},
+		Recording: TrafficRecordingConfig{
+			Enabled:       getBoolEnv("TRAFFIC_RECORDING_ENABLED", false),
+			Path:          getEnv("TRAFFIC_RECORDING_PATH", "traffic-recording.jsonl"),
+			MaxBodySize:   getIntEnv("TRAFFIC_RECORDING_MAX_BODY_SIZE", 65536),
+			MaxRequests:   int64(getIntEnv("TRAFFIC_RECORDING_MAX_REQUESTS", 1000000)),
+			ExcludedPaths: getStringSliceEnv("TRAFFIC_RECORDING_EXCLUDED_PATHS", []string{"/health", "/prometheus", "/swagger"}),
+		},
+		HTTPCache: HTTPCacheConfig{
+			Enabled:             getBoolEnv("HTTP_CACHE_ENABLED", true),
+			UsersCacheControl:   getEnv("HTTP_CACHE_CONTROL_USERS", "private, no-cache"),
+			DriversCacheControl: getEnv("HTTP_CACHE_CONTROL_DRIVERS", "private, no-cache"),
+			RidesCacheControl:   getEnv("HTTP_CACHE_CONTROL_RIDES", "private, no-cache"),
+		},
+		Retention: RetentionConfig{
+			MaintenanceInterval:  getDurationEnv("RETENTION_MAINTENANCE_INTERVAL", time.Hour),
+			PartitionPremakeDays: getIntEnv("RETENTION_PARTITION_PREMAKE_DAYS", 7),
+			ActorMessagesPeriod:  getDurationEnv("RETENTION_ACTOR_MESSAGES_PERIOD", 0),
+			EventLogsPeriod:      getDurationEnv("RETENTION_EVENT_LOGS_PERIOD", 0),
+			SystemMetricsPeriod:  getDurationEnv("RETENTION_SYSTEM_METRICS_PERIOD", 0),
+		},
+		SLO: SLOConfig{
+			Window:     getDurationEnv("SLO_WINDOW", time.Hour),
+			Objectives: getSLOObjectivesEnv("SLO_OBJECTIVES", DefaultSLOObjectives()),
+		},
+		Webhook: WebhookConfig{
+			Timeout:        getDurationEnv("WEBHOOK_TIMEOUT", 5*time.Second),
+			PollInterval:   getDurationEnv("WEBHOOK_POLL_INTERVAL", time.Second),
+			BatchSize:      getIntEnv("WEBHOOK_BATCH_SIZE", 50),
+			MaxAttempts:    getIntEnv("WEBHOOK_MAX_ATTEMPTS", 6),
+			InitialBackoff: getDurationEnv("WEBHOOK_INITIAL_BACKOFF", 10*time.Second),
+			MaxBackoff:     getDurationEnv("WEBHOOK_MAX_BACKOFF", time.Hour),
+		},
+		HTTPClient: HTTPClientConfig{
+			Timeout:          getDurationEnv("HTTP_CLIENT_TIMEOUT", 10*time.Second),
+			MaxRetries:       getIntEnv("HTTP_CLIENT_MAX_RETRIES", 2),
+			InitialBackoff:   getDurationEnv("HTTP_CLIENT_INITIAL_BACKOFF", 100*time.Millisecond),
+			MaxBackoff:       getDurationEnv("HTTP_CLIENT_MAX_BACKOFF", 2*time.Second),
+			RetryBudgetRatio: getFloatEnv("HTTP_CLIENT_RETRY_BUDGET_RATIO", 0.2),
+			RetryBudgetBurst: getIntEnv("HTTP_CLIENT_RETRY_BUDGET_BURST", 10),
+			BreakerFailures:  getIntEnv("HTTP_CLIENT_BREAKER_FAILURES", 5),
+			BreakerCooldown:  getDurationEnv("HTTP_CLIENT_BREAKER_COOLDOWN", 30*time.Second),
+		},
+		Auth: AuthConfig{
+			AccessTokenTTL:     getDurationEnv("AUTH_ACCESS_TOKEN_TTL", 15*time.Minute),
+			RefreshTokenTTL:    getDurationEnv("AUTH_REFRESH_TOKEN_TTL", 30*24*time.Hour),
+			MaxSessionsPerUser: getIntEnv("AUTH_MAX_SESSIONS_PER_USER", 5),
+		},
+		Redaction: RedactionConfig{
+			Enabled:      getBoolEnv("REDACTION_ENABLED", true),
+			Rules:        getRedactionRulesEnv("REDACTION_RULES", DefaultRedactionRules()),
+			HashKey:      getEnv("REDACTION_HASH_KEY", ""),
+			OperatorKeys: getStringSliceEnv("OPERATOR_API_KEYS", nil),
+		},
+		Payload: PayloadConfig{
+			MaxSize:              getIntEnv("PAYLOAD_MAX_SIZE", 64*1024),
+			CompressionThreshold: getIntEnv("PAYLOAD_COMPRESSION_THRESHOLD", 8*1024),
+			PreviewSize:          getIntEnv("PAYLOAD_PREVIEW_SIZE", 1024),
+		},
+		Forecast: ForecastConfig{
+			HistoryWindow:       getDurationEnv("FORECAST_HISTORY_WINDOW", 28*24*time.Hour),
+			MaxHorizon:          getIntEnv("FORECAST_MAX_HORIZON", 168),
+			MovingAverageWindow: getIntEnv("FORECAST_MOVING_AVERAGE_WINDOW", 24),
+			Alpha:               getFloatEnv("FORECAST_ALPHA", 0.3),
+			Beta:                getFloatEnv("FORECAST_BETA", 0.05),
+			Gamma:               getFloatEnv("FORECAST_GAMMA", 0.2),
+		},
+		Heatmap: HeatmapConfig{
+			DefaultRange: getDurationEnv("HEATMAP_DEFAULT_RANGE", 24*time.Hour),
+			MaxRange:     getDurationEnv("HEATMAP_MAX_RANGE", 31*24*time.Hour),
+			CacheTTL:     getDurationEnv("HEATMAP_CACHE_TTL", 5*time.Minute),
+		},
+		TripCache: TripCacheConfig{
+			TTL: getDurationEnv("TRIP_STATUS_CACHE_TTL", 30*time.Second),
+		},
+		Dashboard: DashboardConfig{
+			TripRefreshInterval:   getDurationEnv("DASHBOARD_TRIP_REFRESH_INTERVAL", 5*time.Minute),
+			SupplyRefreshInterval: getDurationEnv("DASHBOARD_SUPPLY_REFRESH_INTERVAL", 30*time.Second),
+			RefreshWindow:         getDurationEnv("DASHBOARD_REFRESH_WINDOW", 48*time.Hour),
+			MaxHours:              getIntEnv("DASHBOARD_MAX_HOURS", 168),
+			ZonePrecision:         getIntEnv("DASHBOARD_ZONE_PRECISION", 5),
+		},
+		Chat: ChatConfig{
+			Filters:         getStringSliceEnv("CHAT_FILTERS", []string{"profanity", "pii"}),
+			ProfanityWords:  getStringSliceEnv("CHAT_PROFANITY_WORDS", nil),
+			RetentionPeriod: getDurationEnv("CHAT_RETENTION_PERIOD", 30*24*time.Hour),
+			PurgeInterval:   getDurationEnv("CHAT_PURGE_INTERVAL", time.Hour),
+		},
+		Reporting: ReportingConfig{
+			Timezone: getEnv("REPORT_TIMEZONE", "UTC"),
+			MaxDays:  getIntEnv("REPORT_MAX_DAYS", 93),
+		},
+		Fare: FareConfig{
+			BaseFare:              getFloatEnv("FARE_BASE", 5),
+			PerKm:                 getFloatEnv("FARE_PER_KM", 2),
+			MaxDetourRatio:        getFloatEnv("TRIP_COMPLETION_MAX_DETOUR_RATIO", 2),
+			MaxDropoffDeviationKm: getFloatEnv("TRIP_COMPLETION_MAX_DROPOFF_DEVIATION_KM", 1),
+			MaxFareDeviation:      getFloatEnv("TRIP_COMPLETION_MAX_FARE_DEVIATION", 0.5),
+		},
+		PickupWait: PickupWaitConfig{
+			FreePeriod:    getDurationEnv("PICKUP_WAIT_FREE_PERIOD", 2*time.Minute),
+			FeePerMinute:  getFloatEnv("PICKUP_WAIT_FEE_PER_MINUTE", 0.5),
+			NoShowWindow:  getDurationEnv("PICKUP_NO_SHOW_WINDOW", 5*time.Minute),
+			NoShowFee:     getFloatEnv("PICKUP_NO_SHOW_FEE", 5),
+			ZonePrecision: getIntEnv("PICKUP_WAIT_ZONE_PRECISION", 5),
+		},
+		Cancellation: CancellationPolicyConfig{
+			GracePeriod: getDurationEnv("CANCELLATION_GRACE_PERIOD", 2*time.Minute),
+			Fee:         getFloatEnv("CANCELLATION_FEE", 3),
+			ArrivedFee:  getFloatEnv("CANCELLATION_ARRIVED_FEE", 5),
+			FreeReasons: getStringSliceEnv("CANCELLATION_FREE_REASONS", []string{"driver_not_moving", "safety_concern", "driver_cancelled"}),
+		},
+		Rematch: RematchConfig{
+			MaxRematches: getIntEnv("REMATCH_MAX_ATTEMPTS", 2),
+		},
+		Timeout: TimeoutConfig{
+			Default:   getDurationEnv("REQUEST_TIMEOUT_DEFAULT", 0),
+			Endpoints: getEndpointTimeoutsEnv("REQUEST_TIMEOUTS", DefaultEndpointTimeouts()),
+		},
+		Location: LocationIngestConfig{
+			Enabled:       getBoolEnv("LOCATION_INGEST_ENABLED", true),
+			FlushInterval: getDurationEnv("LOCATION_INGEST_FLUSH_INTERVAL", 2*time.Second),
+			MaxPending:    getIntEnv("LOCATION_INGEST_MAX_PENDING", 1000),
+			CacheTTL:      getDurationEnv("LOCATION_INGEST_CACHE_TTL", 5*time.Minute),
+		},
+		Payments: PaymentReconciliationConfig{
+			Interval:    getDurationEnv("PAYMENT_RECONCILIATION_INTERVAL", 15*time.Minute),
+			GracePeriod: getDurationEnv("PAYMENT_RECONCILIATION_GRACE_PERIOD", time.Hour),
+			Lookback:    getDurationEnv("PAYMENT_RECONCILIATION_LOOKBACK", 7*24*time.Hour),
+			Tolerance:   getFloatEnv("PAYMENT_RECONCILIATION_TOLERANCE", 0.01),
+		},
+		Matching: MatchingConfig{
+			Workers:      getIntEnv("MATCHING_WORKERS", 8),
+			QueueSize:    getIntEnv("MATCHING_QUEUE_SIZE", 100),
+			QueueTimeout: getDurationEnv("MATCHING_QUEUE_TIMEOUT", 5*time.Second),
 		},
+		Fatigue: FatigueConfig{
+			Enabled:       getBoolEnv("FATIGUE_ENABLED", true),
+			Window:        getDurationEnv("FATIGUE_WINDOW", 24*time.Hour),
+			MaxOnline:     getDurationEnv("FATIGUE_MAX_ONLINE", 12*time.Hour),
+			MaxDriving:    getDurationEnv("FATIGUE_MAX_DRIVING", 10*time.Hour),
+			RestPeriod:    getDurationEnv("FATIGUE_REST_PERIOD", 8*time.Hour),
+			CheckInterval: getDurationEnv("FATIGUE_CHECK_INTERVAL", time.Minute),
+		},
+		Onboarding: OnboardingConfig{
+			RequireActivation: getBoolEnv("DRIVER_ONBOARDING_REQUIRE_ACTIVATION", false),
+		},
+		ServiceArea: ServiceAreaConfig{
+			Enabled:  getBoolEnv("SERVICE_AREA_ENABLED", false),
+			Areas:    getServiceAreasEnv("SERVICE_AREAS", nil),
+			Timezone: getEnv("SERVICE_AREA_TIMEZONE", "UTC"),
+			Hours:    getOperatingHoursEnv("SERVICE_HOURS", nil),
+		},
+		Lock: LockConfig{
+			Addresses:      getStringSliceEnv("LOCK_REDIS_ADDRS", nil),
+			KeyPrefix:      getEnv("LOCK_KEY_PREFIX", "lock:"),
+			TTL:            getDurationEnv("LOCK_TTL", 30*time.Second),
+			RetryInterval:  getDurationEnv("LOCK_RETRY_INTERVAL", 100*time.Millisecond),
+			AcquireTimeout: getDurationEnv("LOCK_ACQUIRE_TIMEOUT", 10*time.Second),
+		},
+		Leader: LeaderConfig{
+			Backend:       getEnv("LEADER_ELECTION_BACKEND", "auto"),
+			RetryInterval: getDurationEnv("LEADER_ELECTION_RETRY_INTERVAL", 5*time.Second),
+		},
+		Storage: StorageConfig{
+			Backend:       getEnv("STORAGE_BACKEND", "local"),
+			LocalDir:      getEnv("STORAGE_LOCAL_DIR", "./data/uploads"),
+			SigningKey:    getEnv("STORAGE_SIGNING_KEY", ""),
+			URLTTL:        getDurationEnv("STORAGE_URL_TTL", 15*time.Minute),
+			MaxAvatarSize: getIntEnv("STORAGE_MAX_AVATAR_SIZE", 5<<20),
+			S3Endpoint:    getEnv("STORAGE_S3_ENDPOINT", ""),
+			S3Region:      getEnv("STORAGE_S3_REGION", "us-east-1"),
+			S3Bucket:      getEnv("STORAGE_S3_BUCKET", ""),
+			S3AccessKey:   getEnv("STORAGE_S3_ACCESS_KEY", ""),
+			S3SecretKey:   getEnv("STORAGE_S3_SECRET_KEY", ""),
+			S3PathStyle:   getBoolEnv("STORAGE_S3_PATH_STYLE", false),
+		},
+		Invoice: InvoiceConfig{
+			Formats:       getStringSliceEnv("INVOICE_FORMATS", []string{"csv", "pdf"}),
+			CheckInterval: getDurationEnv("INVOICE_CHECK_INTERVAL", time.Hour),
+			MaxAttempts:   getIntEnv("INVOICE_MAX_ATTEMPTS", 5),
+		},
+		ETA: ETAConfig{
+			AverageSpeedKmh:  getFloatEnv("ETA_AVERAGE_SPEED_KMH", 30),
+			ZonePrecision:    getIntEnv("ETA_ZONE_PRECISION", 5),
+			AccuracyWindow:   getDurationEnv("ETA_ACCURACY_WINDOW", 24*time.Hour),
+			AccuracyInterval: getDurationEnv("ETA_ACCURACY_INTERVAL", 5*time.Minute),
+			AutoAdjust:       getBoolEnv("ETA_AUTO_ADJUST", false),
+			AdjustRate:       getFloatEnv("ETA_ADJUST_RATE", 0.2),
+			MinSamples:       getIntEnv("ETA_ADJUST_MIN_SAMPLES", 50),
+			MinSpeedKmh:      getFloatEnv("ETA_MIN_SPEED_KMH", 5),
+			MaxSpeedKmh:      getFloatEnv("ETA_MAX_SPEED_KMH", 80),
+		},
+		Accessibility: AccessibilityConfig{
+			ZonePrecision: getIntEnv("ACCESSIBILITY_ZONE_PRECISION", 5),
+		},
+		LocationTrail: LocationTrailConfig{
+			RetentionPeriod:    getDurationEnv("LOCATION_TRAIL_RETENTION_PERIOD", 90*24*time.Hour),
+			DownsampleInterval: getDurationEnv("LOCATION_TRAIL_DOWNSAMPLE_INTERVAL", time.Hour),
+			MinuteAfter:        getDurationEnv("LOCATION_TRAIL_MINUTE_AFTER", 24*time.Hour),
+			TenMinutesAfter:    getDurationEnv("LOCATION_TRAIL_TEN_MINUTES_AFTER", 7*24*time.Hour),
+			MaxPoints:          getIntEnv("LOCATION_TRAIL_MAX_POINTS", 5000),
+		},
+		Fraud: FraudConfig{
+			MaxTripSpeedKmh:       getFloatEnv("FRAUD_MAX_TRIP_SPEED_KMH", 150),
+			TeleportSpeedKmh:      getFloatEnv("FRAUD_TELEPORT_SPEED_KMH", 300),
+			TeleportMinDistanceKm: getFloatEnv("FRAUD_TELEPORT_MIN_DISTANCE_KM", 1),
+			CancellationWindow:    getDurationEnv("FRAUD_CANCELLATION_WINDOW", 24*time.Hour),
+			CancellationThreshold: getIntEnv("FRAUD_CANCELLATION_THRESHOLD", 3),
+			PairWindow:            getDurationEnv("FRAUD_PAIR_WINDOW", 7*24*time.Hour),
+			PairThreshold:         getIntEnv("FRAUD_PAIR_THRESHOLD", 5),
+		},
+		APIUsage: APIUsageConfig{
+			FlushInterval:     getDurationEnv("API_USAGE_FLUSH_INTERVAL", 30*time.Second),
+			DefaultDailyQuota: int64(getIntEnv("API_USAGE_DEFAULT_DAILY_QUOTA", 0)),
+			RetentionPeriod:   getDurationEnv("API_USAGE_RETENTION_PERIOD", 90*24*time.Hour),
+		},
+		Research: ResearchExportConfig{
+			Columns:          getAnonymizationRulesEnv("RESEARCH_EXPORT_COLUMNS", DefaultAnonymizationRules()),
+			GeohashPrecision: getIntEnv("RESEARCH_EXPORT_GEOHASH_PRECISION", 6),
+			KAnonymity:       getIntEnv("RESEARCH_EXPORT_K_ANONYMITY", 5),
+			TimestampJitter:  getDurationEnv("RESEARCH_EXPORT_TIMESTAMP_JITTER", 10*time.Minute),
+			HashKey:          getEnv("RESEARCH_EXPORT_HASH_KEY", ""),
+			MaxPeriod:        getDurationEnv("RESEARCH_EXPORT_MAX_PERIOD", 92*24*time.Hour),
+		},
+		Archive: MetricsArchiveConfig{
+			Enabled:       getBoolEnv("METRICS_ARCHIVE_ENABLED", false),
+			Sink:          getEnv("METRICS_ARCHIVE_SINK", "local"),
+			LocalDir:      getEnv("METRICS_ARCHIVE_LOCAL_DIR", "./data/metrics"),
+			Prefix:        getEnv("METRICS_ARCHIVE_PREFIX", "metrics"),
+			CheckInterval: getDurationEnv("METRICS_ARCHIVE_CHECK_INTERVAL", time.Hour),
+			BackfillDays:  getIntEnv("METRICS_ARCHIVE_BACKFILL_DAYS", 7),
+			BatchSize:     getIntEnv("METRICS_ARCHIVE_BATCH_SIZE", 10000),
+		},
+		Email: EmailConfig{
+			Enabled:        getBoolEnv("EMAIL_ENABLED", false),
+			SMTPHost:       getEnv("SMTP_HOST", ""),
+			SMTPPort:       getIntEnv("SMTP_PORT", 587),
+			Username:       getEnv("SMTP_USERNAME", ""),
+			Password:       getEnv("SMTP_PASSWORD", ""),
+			From:           getEnv("EMAIL_FROM", ""),
+			Timeout:        getDurationEnv("EMAIL_TIMEOUT", 10*time.Second),
+			QueueSize:      getIntEnv("EMAIL_QUEUE_SIZE", 1000),
+			Workers:        getIntEnv("EMAIL_WORKERS", 2),
+			MaxAttempts:    getIntEnv("EMAIL_MAX_ATTEMPTS", 5),
+			InitialBackoff: getDurationEnv("EMAIL_INITIAL_BACKOFF", 5*time.Second),
+			MaxBackoff:     getDurationEnv("EMAIL_MAX_BACKOFF", 5*time.Minute),
+			Receipts:       getBoolEnv("EMAIL_RECEIPTS_ENABLED", true),
+		},
+		AppVersion: AppVersionConfig{
+			MinVersions:     getMapEnv("APP_MIN_VERSIONS"),
+			FeatureVersions: getMapEnv("APP_FEATURE_VERSIONS"),
+		},
+		Integrity: IntegrityCheckConfig{
+			Enabled:         getBoolEnv("INTEGRITY_CHECK_ENABLED", false),
+			Interval:        getDurationEnv("INTEGRITY_CHECK_INTERVAL", time.Hour),
+			Lookback:        getDurationEnv("INTEGRITY_CHECK_LOOKBACK", 24*time.Hour),
+			RetentionPeriod: getDurationEnv("INTEGRITY_CHECK_RETENTION_PERIOD", 7*24*time.Hour),
+		},
+		Experiment: ExperimentConfig{
+			SchemaPrefix: getEnv("EXPERIMENT_SCHEMA_PREFIX", "experiment_"),
+			Active:       getEnv("EXPERIMENT_ACTIVE", ""),
+		},
+		Secrets: SecretsConfig{
+			Provider:   getEnv("SECRETS_PROVIDER", "env"),
+			Dir:        getEnv("SECRETS_DIR", "/run/secrets"),
+			VaultAddr:  getEnv("VAULT_ADDR", ""),
+			VaultToken: getEnv("VAULT_TOKEN", ""),
+			VaultPath:  getEnv("VAULT_SECRET_PATH", ""),
+		},
+	}
+
+	// Load credentials from the configured secret provider
+	provider, err := NewSecretProvider(&config.Secrets)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets configuration: %w", err)
+	}
+	if err := config.applySecrets(provider); err != nil {
+		return nil, fmt.Errorf("failed to load secrets: %w", err)
 	}
 
 	// Validate configuration
@@ -203,17 +1181,33 @@ func (c *Config) Validate() error {
 	}
 
 	// Validate database config
-	if c.Database.Host == "" {
-		return fmt.Errorf("database host is required")
-	}
-	if c.Database.Port == "" {
-		return fmt.Errorf("database port is required")
-	}
-	if c.Database.User == "" {
-		return fmt.Errorf("database user is required")
-	}
-	if c.Database.DBName == "" {
-		return fmt.Errorf("database name is required")
+	switch c.Database.Driver {
+	case "postgres":
+		if c.Database.Host == "" {
+			return fmt.Errorf("database host is required")
+		}
+		if c.Database.Port == "" {
+			return fmt.Errorf("database port is required")
+		}
+		if c.Database.User == "" {
+			return fmt.Errorf("database user is required")
+		}
+		if c.Database.DBName == "" {
+			return fmt.Errorf("database name is required")
+		}
+	case "sqlite":
+		if c.Database.SQLitePath == "" {
+			return fmt.Errorf("sqlite database path is required")
+		}
+		if c.Database.ReplicaDSN != "" {
+			return fmt.Errorf("read replica is not supported with the sqlite driver")
+		}
+	case "memory":
+		if c.Database.ReplicaDSN != "" {
+			return fmt.Errorf("read replica is not supported with the memory driver")
+		}
+	default:
+		return fmt.Errorf("invalid database driver: %s", c.Database.Driver)
 	}
 	if c.Database.MaxOpenConns <= 0 {
 		return fmt.Errorf("database max open connections must be positive")
@@ -221,12 +1215,26 @@ func (c *Config) Validate() error {
 	if c.Database.MaxIdleConns <= 0 {
 		return fmt.Errorf("database max idle connections must be positive")
 	}
+	if c.Database.ReplicaDSN != "" && c.Database.ReplicaHealthCheckInterval <= 0 {
+		return fmt.Errorf("database replica health check interval must be positive")
+	}
+	if c.Database.ReplicaMaxLag < 0 {
+		return fmt.Errorf("database replica max lag must not be negative")
+	}
+	if c.Database.ChangeNotifications {
+		if c.Database.ChangeListenerMinReconnect <= 0 {
+			return fmt.Errorf("database change listener min reconnect interval must be positive")
+		}
+		if c.Database.ChangeListenerMaxReconnect < c.Database.ChangeListenerMinReconnect {
+			return fmt.Errorf("database change listener max reconnect interval must not be less than the min")
+		}
+	}
 
 	// Validate Redis config
-	if c.Redis.Host == "" {
+	if c.Redis.Enabled && c.Redis.Host == "" {
 		return fmt.Errorf("redis host is required")
 	}
-	if c.Redis.Port == "" {
+	if c.Redis.Enabled && c.Redis.Port == "" {
 		return fmt.Errorf("redis port is required")
 	}
 	if c.Redis.DB < 0 || c.Redis.DB > 15 {
@@ -235,6 +1243,9 @@ func (c *Config) Validate() error {
 	if c.Redis.PoolSize <= 0 {
 		return fmt.Errorf("redis pool size must be positive")
 	}
+	if c.Redis.RealtimeStreams && c.Redis.StreamMaxLen <= 0 {
+		return fmt.Errorf("redis stream max length must be positive")
+	}
 
 	// Validate actor config
 	if c.Actor.MaxActors <= 0 {
@@ -243,6 +1254,36 @@ func (c *Config) Validate() error {
 	if c.Actor.SupervisionStrategy != "restart" && c.Actor.SupervisionStrategy != "stop" && c.Actor.SupervisionStrategy != "ignore" {
 		return fmt.Errorf("invalid actor supervision strategy: %s", c.Actor.SupervisionStrategy)
 	}
+	if err := c.Actor.Retry.Validate(); err != nil {
+		return err
+	}
+	if c.Actor.GoroutineLeakGrace <= 0 {
+		return fmt.Errorf("actor goroutine leak grace must be positive")
+	}
+	if c.Actor.IdleTimeout < 0 {
+		return fmt.Errorf("actor idle timeout must not be negative")
+	}
+	for actorType, timeout := range c.Actor.IdleTimeoutsByType {
+		if timeout < 0 {
+			return fmt.Errorf("actor idle timeout for %s must not be negative", actorType)
+		}
+	}
+	for actorType, max := range c.Actor.MaxActorsByType {
+		if max <= 0 {
+			return fmt.Errorf("actor max actors for %s must be positive", actorType)
+		}
+	}
+	for actorType, size := range c.Actor.PoolSizes {
+		if !slices.Contains(PooledActorTypes, actorType) {
+			return fmt.Errorf("invalid actor pool type: %s", actorType)
+		}
+		if size < 0 {
+			return fmt.Errorf("actor pool size for %s must not be negative", actorType)
+		}
+		if max, ok := c.Actor.MaxActorsByType[actorType]; ok && size > max {
+			return fmt.Errorf("actor pool size for %s exceeds its max actors", actorType)
+		}
+	}
 
 	// Validate logging config
 	if c.Logging.Level != "debug" && c.Logging.Level != "info" && c.Logging.Level != "warn" && c.Logging.Level != "error" {
@@ -263,43 +1304,599 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("metrics batch size must be positive")
 	}
 
-	return nil
-}
+	// Validate retention config
+	if c.Retention.MaintenanceInterval <= 0 {
+		return fmt.Errorf("retention maintenance interval must be positive")
+	}
+	if c.Retention.PartitionPremakeDays < 0 {
+		return fmt.Errorf("retention partition premake days must not be negative")
+	}
+	if c.Retention.ActorMessagesPeriod < 0 || c.Retention.EventLogsPeriod < 0 || c.Retention.SystemMetricsPeriod < 0 {
+		return fmt.Errorf("retention periods of the observability tables must not be negative")
+	}
 
-// GetDSN returns the PostgreSQL data source name
-func (c *Config) GetDSN() string {
-	return fmt.Sprintf(
-		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
-		c.Database.Host,
-		c.Database.Port,
-		c.Database.User,
-		c.Database.Password,
-		c.Database.DBName,
-		c.Database.SSLMode,
-	)
-}
+	// Validate SLO config
+	if c.SLO.Window <= 0 {
+		return fmt.Errorf("slo window must be positive")
+	}
+	for _, o := range c.SLO.Objectives {
+		if o.Method == "" || o.Endpoint == "" || o.LatencyTarget <= 0 {
+			return fmt.Errorf("slo for %s %s must have a method, an endpoint and a positive latency target", o.Method, o.Endpoint)
+		}
+		if o.LatencyObjective <= 0 || o.LatencyObjective >= 1 || o.AvailabilityObjective <= 0 || o.AvailabilityObjective >= 1 {
+			return fmt.Errorf("slo objectives for %s %s must be between 0 and 1", o.Method, o.Endpoint)
+		}
+	}
 
-// GetRedisAddr returns the Redis address
-func (c *Config) GetRedisAddr() string {
-	return fmt.Sprintf("%s:%s", c.Redis.Host, c.Redis.Port)
-}
+	// Validate webhook config
+	if c.Webhook.Timeout <= 0 || c.Webhook.PollInterval <= 0 {
+		return fmt.Errorf("webhook timeout and poll interval must be positive")
+	}
+	if c.Webhook.BatchSize <= 0 {
+		return fmt.Errorf("webhook batch size must be positive")
+	}
+	if c.Webhook.MaxAttempts <= 0 {
+		return fmt.Errorf("webhook max attempts must be positive")
+	}
+	if c.Webhook.InitialBackoff < 0 || c.Webhook.MaxBackoff < 0 {
+		return fmt.Errorf("webhook backoff must not be negative")
+	}
 
-// GetServerAddr returns the server address
-func (c *Config) GetServerAddr() string {
-	return fmt.Sprintf("%s:%s", c.Server.Host, c.Server.Port)
-}
+	// Validate outbound HTTP client config
+	if c.HTTPClient.Timeout <= 0 {
+		return fmt.Errorf("HTTP client timeout must be positive")
+	}
+	if c.HTTPClient.MaxRetries < 0 || c.HTTPClient.InitialBackoff < 0 || c.HTTPClient.MaxBackoff < 0 {
+		return fmt.Errorf("HTTP client retries and backoff must not be negative")
+	}
+	if c.HTTPClient.RetryBudgetRatio < 0 || c.HTTPClient.RetryBudgetBurst < 0 {
+		return fmt.Errorf("HTTP client retry budget must not be negative")
+	}
+	if c.HTTPClient.BreakerFailures < 0 || (c.HTTPClient.BreakerFailures > 0 && c.HTTPClient.BreakerCooldown <= 0) {
+		return fmt.Errorf("HTTP client breaker failures must not be negative and its cooldown must be positive")
+	}
 
-// Helper functions for environment variable parsing
+	// Validate auth config
+	if c.Auth.AccessTokenTTL <= 0 || c.Auth.RefreshTokenTTL <= 0 {
+		return fmt.Errorf("auth token TTLs must be positive")
+	}
+	if c.Auth.AccessTokenTTL > c.Auth.RefreshTokenTTL {
+		return fmt.Errorf("auth access token TTL must not exceed the refresh token TTL")
+	}
+	if c.Auth.MaxSessionsPerUser <= 0 {
+		return fmt.Errorf("auth max sessions per user must be positive")
+	}
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+	// Validate redaction config
+	for _, rule := range c.Redaction.Rules {
+		if rule.Path == "" {
+			return fmt.Errorf("redaction rules must have a path")
+		}
+		switch rule.Action {
+		case "remove", "hash", "round":
+		default:
+			return fmt.Errorf("redaction action for %s must be remove, hash or round", rule.Path)
+		}
 	}
-	return defaultValue
-}
+
+	// Validate payload config
+	if c.Payload.MaxSize <= 0 {
+		return fmt.Errorf("payload max size must be positive")
+	}
+	if c.Payload.CompressionThreshold < 0 {
+		return fmt.Errorf("payload compression threshold must not be negative")
+	}
+	if c.Payload.PreviewSize < 0 || c.Payload.PreviewSize > c.Payload.MaxSize {
+		return fmt.Errorf("payload preview size must be between 0 and the max size")
+	}
+
+	// Validate forecast config
+	if c.Forecast.HistoryWindow < 2*24*time.Hour {
+		return fmt.Errorf("forecast history window must cover at least two days")
+	}
+	if c.Forecast.MaxHorizon <= 0 {
+		return fmt.Errorf("forecast max horizon must be positive")
+	}
+	if c.Forecast.MovingAverageWindow <= 0 {
+		return fmt.Errorf("forecast moving average window must be positive")
+	}
+	for name, v := range map[string]float64{"alpha": c.Forecast.Alpha, "beta": c.Forecast.Beta, "gamma": c.Forecast.Gamma} {
+		if v <= 0 || v >= 1 {
+			return fmt.Errorf("forecast %s must be between 0 and 1", name)
+		}
+	}
+
+	// Validate heatmap config
+	if c.Heatmap.DefaultRange <= 0 || c.Heatmap.DefaultRange > c.Heatmap.MaxRange {
+		return fmt.Errorf("heatmap default range must be positive and at most the max range")
+	}
+	if c.Heatmap.CacheTTL < 0 {
+		return fmt.Errorf("heatmap cache TTL cannot be negative")
+	}
+	if c.TripCache.TTL < 0 {
+		return fmt.Errorf("trip status cache TTL cannot be negative")
+	}
+
+	// Validate dashboard config
+	if c.Dashboard.TripRefreshInterval <= 0 || c.Dashboard.SupplyRefreshInterval <= 0 {
+		return fmt.Errorf("dashboard refresh intervals must be positive")
+	}
+	if c.Dashboard.RefreshWindow < time.Hour {
+		return fmt.Errorf("dashboard refresh window must cover at least an hour")
+	}
+	if c.Dashboard.MaxHours <= 0 {
+		return fmt.Errorf("dashboard max hours must be positive")
+	}
+	if c.Dashboard.ZonePrecision < 1 || c.Dashboard.ZonePrecision > 12 {
+		return fmt.Errorf("dashboard zone precision must be between 1 and 12")
+	}
+
+	// Validate chat config
+	for _, filter := range c.Chat.Filters {
+		if filter != "profanity" && filter != "pii" {
+			return fmt.Errorf("invalid chat filter: %s", filter)
+		}
+	}
+	if c.Chat.RetentionPeriod <= 0 || c.Chat.PurgeInterval <= 0 {
+		return fmt.Errorf("chat retention period and purge interval must be positive")
+	}
+
+	// Validate reporting config
+	if _, err := time.LoadLocation(c.Reporting.Timezone); err != nil || c.Reporting.Timezone == "" || c.Reporting.Timezone == "Local" {
+		return fmt.Errorf("invalid report timezone: %q", c.Reporting.Timezone)
+	}
+	if c.Reporting.MaxDays <= 0 {
+		return fmt.Errorf("report max days must be positive")
+	}
+
+	// Validate fare config
+	if c.Fare.BaseFare < 0 || c.Fare.PerKm < 0 {
+		return fmt.Errorf("fare base and per km rate cannot be negative")
+	}
+	if c.Fare.MaxDetourRatio < 1 {
+		return fmt.Errorf("trip completion max detour ratio must be at least 1")
+	}
+	if c.Fare.MaxDropoffDeviationKm <= 0 || c.Fare.MaxFareDeviation <= 0 {
+		return fmt.Errorf("trip completion max dropoff and fare deviations must be positive")
+	}
+
+	// Validate pickup wait config
+	if c.PickupWait.FreePeriod < 0 || c.PickupWait.NoShowWindow <= 0 {
+		return fmt.Errorf("pickup wait free period cannot be negative and no-show window must be positive")
+	}
+	if c.PickupWait.FeePerMinute < 0 || c.PickupWait.NoShowFee < 0 {
+		return fmt.Errorf("pickup wait and no-show fees cannot be negative")
+	}
+	if c.PickupWait.ZonePrecision < 1 || c.PickupWait.ZonePrecision > 12 {
+		return fmt.Errorf("pickup wait zone precision must be between 1 and 12")
+	}
+
+	// Validate cancellation policy config
+	if c.Cancellation.GracePeriod < 0 {
+		return fmt.Errorf("cancellation grace period cannot be negative")
+	}
+	if c.Cancellation.Fee < 0 || c.Cancellation.ArrivedFee < 0 {
+		return fmt.Errorf("cancellation fees cannot be negative")
+	}
+
+	// Validate rematch config
+	if c.Rematch.MaxRematches < 0 {
+		return fmt.Errorf("rematch max attempts cannot be negative")
+	}
+
+	// Validate matching config
+	if c.Matching.Workers <= 0 || c.Matching.QueueSize <= 0 {
+		return fmt.Errorf("matching workers and queue size must be positive")
+	}
+	if c.Matching.QueueTimeout <= 0 {
+		return fmt.Errorf("matching queue timeout must be positive")
+	}
+
+	// Validate location ingestion config
+	if c.Location.Enabled {
+		if c.Location.FlushInterval <= 0 || c.Location.CacheTTL <= 0 {
+			return fmt.Errorf("location ingest flush interval and cache TTL must be positive")
+		}
+		if c.Location.MaxPending <= 0 {
+			return fmt.Errorf("location ingest max pending must be positive")
+		}
+	}
+
+	// Validate payment reconciliation config
+	if c.Payments.Interval <= 0 || c.Payments.Lookback <= 0 {
+		return fmt.Errorf("payment reconciliation interval and lookback must be positive")
+	}
+	if c.Payments.GracePeriod < 0 || c.Payments.GracePeriod >= c.Payments.Lookback {
+		return fmt.Errorf("payment reconciliation grace period must not be negative and must be shorter than the lookback")
+	}
+	if c.Payments.Tolerance < 0 {
+		return fmt.Errorf("payment reconciliation tolerance must not be negative")
+	}
+
+	// Validate request timeout config
+	if c.Timeout.Default < 0 {
+		return fmt.Errorf("default request timeout must not be negative")
+	}
+	for _, t := range c.Timeout.Endpoints {
+		if t.Method == "" || t.Endpoint == "" || t.Timeout <= 0 {
+			return fmt.Errorf("request timeout for %s %s must have a method, an endpoint and a positive timeout", t.Method, t.Endpoint)
+		}
+	}
+
+	// Validate fatigue config
+	if c.Fatigue.Window <= 0 || c.Fatigue.RestPeriod <= 0 || c.Fatigue.CheckInterval <= 0 {
+		return fmt.Errorf("fatigue window, rest period and check interval must be positive")
+	}
+	if c.Fatigue.MaxOnline <= 0 || c.Fatigue.MaxOnline > c.Fatigue.Window {
+		return fmt.Errorf("fatigue max online time must be positive and within the window")
+	}
+	if c.Fatigue.MaxDriving <= 0 || c.Fatigue.MaxDriving > c.Fatigue.MaxOnline {
+		return fmt.Errorf("fatigue max driving time must be positive and at most the max online time")
+	}
+
+	// Validate service area config
+	if _, err := time.LoadLocation(c.ServiceArea.Timezone); err != nil || c.ServiceArea.Timezone == "" || c.ServiceArea.Timezone == "Local" {
+		return fmt.Errorf("invalid service area timezone: %q", c.ServiceArea.Timezone)
+	}
+	for _, area := range c.ServiceArea.Areas {
+		if len(area.Polygon) < 3 {
+			return fmt.Errorf("service area %q needs at least 3 vertices", area.Name)
+		}
+		for _, p := range area.Polygon {
+			if p.Lat < -90 || p.Lat > 90 || p.Lng < -180 || p.Lng > 180 {
+				return fmt.Errorf("service area %q has an invalid vertex: %v,%v", area.Name, p.Lat, p.Lng)
+			}
+		}
+	}
+	for _, hours := range c.ServiceArea.Hours {
+		if hours.Open < 0 || hours.Open >= 24*time.Hour || hours.Close <= 0 || hours.Close > 24*time.Hour || hours.Open == hours.Close {
+			return fmt.Errorf("invalid service hours: %v-%v", hours.Open, hours.Close)
+		}
+	}
+
+	// Validate lock config
+	if c.Lock.KeyPrefix == "" {
+		return fmt.Errorf("lock key prefix must not be empty")
+	}
+	if c.Lock.TTL <= 0 || c.Lock.RetryInterval <= 0 {
+		return fmt.Errorf("lock TTL and retry interval must be positive")
+	}
+	if c.Lock.AcquireTimeout < 0 {
+		return fmt.Errorf("lock acquire timeout must not be negative")
+	}
+
+	// Validate leader election config
+	switch c.Leader.Backend {
+	case "auto", "redis", "postgres", "none":
+	default:
+		return fmt.Errorf("leader election backend must be auto, redis, postgres or none")
+	}
+	if c.Leader.RetryInterval <= 0 {
+		return fmt.Errorf("leader election retry interval must be positive")
+	}
+
+	// Validate storage config
+	switch c.Storage.Backend {
+	case "local":
+		if c.Storage.LocalDir == "" {
+			return fmt.Errorf("storage local directory is required for the local backend")
+		}
+	case "s3":
+		if c.Storage.S3Endpoint == "" || c.Storage.S3Bucket == "" || c.Storage.S3Region == "" {
+			return fmt.Errorf("storage S3 endpoint, region and bucket are required for the s3 backend")
+		}
+		if c.Storage.S3AccessKey == "" || c.Storage.S3SecretKey == "" {
+			return fmt.Errorf("storage S3 access key and secret key are required for the s3 backend")
+		}
+		if c.Storage.URLTTL > 7*24*time.Hour {
+			return fmt.Errorf("storage URL TTL must be at most 7 days for the s3 backend")
+		}
+	default:
+		return fmt.Errorf("storage backend must be local or s3")
+	}
+	if c.Storage.URLTTL <= 0 {
+		return fmt.Errorf("storage URL TTL must be positive")
+	}
+	if c.Storage.MaxAvatarSize <= 0 {
+		return fmt.Errorf("storage max avatar size must be positive")
+	}
+
+	// Validate invoice config
+	if len(c.Invoice.Formats) == 0 {
+		return fmt.Errorf("at least one invoice format is required")
+	}
+	for _, format := range c.Invoice.Formats {
+		if format != "csv" && format != "pdf" {
+			return fmt.Errorf("unsupported invoice format: %s", format)
+		}
+	}
+	if c.Invoice.CheckInterval <= 0 {
+		return fmt.Errorf("invoice check interval must be positive")
+	}
+	if c.Invoice.MaxAttempts <= 0 {
+		return fmt.Errorf("invoice max attempts must be positive")
+	}
+
+	// Validate ETA config
+	if c.ETA.MinSpeedKmh <= 0 || c.ETA.MaxSpeedKmh < c.ETA.MinSpeedKmh {
+		return fmt.Errorf("ETA speed bounds must be positive, the minimum not above the maximum")
+	}
+	if c.ETA.AverageSpeedKmh < c.ETA.MinSpeedKmh || c.ETA.AverageSpeedKmh > c.ETA.MaxSpeedKmh {
+		return fmt.Errorf("ETA average speed must be within the speed bounds")
+	}
+	if c.ETA.ZonePrecision < 1 || c.ETA.ZonePrecision > 12 {
+		return fmt.Errorf("ETA zone precision must be between 1 and 12")
+	}
+	if c.ETA.AccuracyWindow <= 0 || c.ETA.AccuracyInterval <= 0 {
+		return fmt.Errorf("ETA accuracy window and interval must be positive")
+	}
+	if c.ETA.AdjustRate <= 0 || c.ETA.AdjustRate > 1 {
+		return fmt.Errorf("ETA adjust rate must be between 0 (exclusive) and 1")
+	}
+	if c.ETA.MinSamples <= 0 {
+		return fmt.Errorf("ETA adjust min samples must be positive")
+	}
+
+	// Validate accessibility config
+	if c.Accessibility.ZonePrecision < 1 || c.Accessibility.ZonePrecision > 12 {
+		return fmt.Errorf("accessibility zone precision must be between 1 and 12")
+	}
+
+	// Validate location trail config
+	if c.LocationTrail.RetentionPeriod <= 0 || c.LocationTrail.DownsampleInterval <= 0 {
+		return fmt.Errorf("location trail retention period and downsample interval must be positive")
+	}
+	if c.LocationTrail.MinuteAfter <= 0 || c.LocationTrail.TenMinutesAfter < c.LocationTrail.MinuteAfter {
+		return fmt.Errorf("location trail downsampling ages must be positive, the ten-minute age not below the one-minute age")
+	}
+	if c.LocationTrail.MaxPoints <= 0 {
+		return fmt.Errorf("location trail max points must be positive")
+	}
+
+	// Validate fraud config
+	if c.Fraud.MaxTripSpeedKmh <= 0 || c.Fraud.TeleportSpeedKmh <= 0 {
+		return fmt.Errorf("fraud speed thresholds must be positive")
+	}
+	if c.Fraud.TeleportMinDistanceKm < 0 {
+		return fmt.Errorf("fraud teleport min distance must not be negative")
+	}
+	if c.Fraud.CancellationWindow <= 0 || c.Fraud.PairWindow <= 0 {
+		return fmt.Errorf("fraud cancellation and pair windows must be positive")
+	}
+	if c.Fraud.CancellationThreshold < 2 || c.Fraud.PairThreshold < 2 {
+		return fmt.Errorf("fraud cancellation and pair thresholds must be at least 2")
+	}
+
+	// Validate API usage config
+	if c.APIUsage.FlushInterval <= 0 {
+		return fmt.Errorf("API usage flush interval must be positive")
+	}
+	if c.APIUsage.DefaultDailyQuota < 0 {
+		return fmt.Errorf("API usage default daily quota must not be negative")
+	}
+	if c.APIUsage.RetentionPeriod < 24*time.Hour {
+		return fmt.Errorf("API usage retention period must be at least 24h")
+	}
+
+	// Validate research export config
+	if len(c.Research.Columns) == 0 {
+		return fmt.Errorf("research export must have at least one column")
+	}
+	exportedColumns := make(map[string]bool)
+	for _, rule := range c.Research.Columns {
+		actions, ok := researchExportColumnActions[rule.Column]
+		if !ok {
+			return fmt.Errorf("research export column %q is unknown", rule.Column)
+		}
+		if exportedColumns[rule.Column] {
+			return fmt.Errorf("research export column %s is declared twice", rule.Column)
+		}
+		exportedColumns[rule.Column] = true
+		if !slices.Contains(actions, rule.Action) {
+			return fmt.Errorf("research export action for %s must be %s", rule.Column, strings.Join(actions, " or "))
+		}
+	}
+	if c.Research.GeohashPrecision < 1 || c.Research.GeohashPrecision > 12 {
+		return fmt.Errorf("research export geohash precision must be between 1 and 12")
+	}
+	if c.Research.KAnonymity < 1 {
+		return fmt.Errorf("research export k-anonymity must be at least 1")
+	}
+	if c.Research.TimestampJitter < 0 {
+		return fmt.Errorf("research export timestamp jitter must not be negative")
+	}
+	if c.Research.MaxPeriod <= 0 {
+		return fmt.Errorf("research export max period must be positive")
+	}
+
+	// Validate metrics archive config
+	switch c.Archive.Sink {
+	case "local":
+		if c.Archive.LocalDir == "" {
+			return fmt.Errorf("metrics archive local directory is required for the local sink")
+		}
+	case "storage":
+	default:
+		return fmt.Errorf("metrics archive sink must be local or storage")
+	}
+	if c.Archive.CheckInterval <= 0 {
+		return fmt.Errorf("metrics archive check interval must be positive")
+	}
+	if c.Archive.BackfillDays < 1 {
+		return fmt.Errorf("metrics archive backfill days must be at least 1")
+	}
+	if c.Archive.BatchSize <= 0 {
+		return fmt.Errorf("metrics archive batch size must be positive")
+	}
+
+	// Validate email config
+	if c.Email.Enabled {
+		if c.Email.SMTPHost == "" {
+			return fmt.Errorf("SMTP host is required when email is enabled")
+		}
+		if _, err := mail.ParseAddress(c.Email.From); err != nil {
+			return fmt.Errorf("email from address is invalid: %w", err)
+		}
+	}
+	if c.Email.SMTPPort < 1 || c.Email.SMTPPort > 65535 {
+		return fmt.Errorf("SMTP port must be between 1 and 65535")
+	}
+	if c.Email.Timeout <= 0 {
+		return fmt.Errorf("email timeout must be positive")
+	}
+	if c.Email.QueueSize <= 0 || c.Email.Workers <= 0 {
+		return fmt.Errorf("email queue size and workers must be positive")
+	}
+	if c.Email.MaxAttempts <= 0 {
+		return fmt.Errorf("email max attempts must be positive")
+	}
+	if c.Email.InitialBackoff < 0 || c.Email.MaxBackoff < 0 {
+		return fmt.Errorf("email backoff must not be negative")
+	}
+
+	// Validate app version config
+	for platform, version := range c.AppVersion.MinVersions {
+		if !appVersionPattern.MatchString(version) {
+			return fmt.Errorf("minimum app version %q of platform %s must be a version such as 2.3.0", version, platform)
+		}
+	}
+	for feature, version := range c.AppVersion.FeatureVersions {
+		if !appVersionPattern.MatchString(version) {
+			return fmt.Errorf("app version %q of feature %s must be a version such as 2.4.0", version, feature)
+		}
+	}
+
+	// Validate integrity check config
+	if c.Integrity.Interval <= 0 || c.Integrity.Lookback < time.Hour {
+		return fmt.Errorf("integrity check interval must be positive and lookback at least 1h")
+	}
+	if c.Integrity.RetentionPeriod < c.Integrity.Interval {
+		return fmt.Errorf("integrity check retention period must be at least the interval, to keep the previous run")
+	}
+
+	// Validate experiment config
+	if !isSchemaPrefix(c.Experiment.SchemaPrefix) {
+		return fmt.Errorf("experiment schema prefix must be 1 to 20 lowercase letters, digits or underscores, starting with a letter or underscore")
+	}
+
+	// Validate observability config
+	if c.Observability.EventBusBufferSize <= 0 {
+		return fmt.Errorf("event bus buffer size must be positive")
+	}
+	if c.Observability.AlertErrorThreshold < 0 {
+		return fmt.Errorf("alert error threshold must not be negative")
+	}
+	if c.Observability.AlertErrorThreshold > 0 && c.Observability.AlertWindow <= 0 {
+		return fmt.Errorf("alert window must be positive")
+	}
+	if c.Observability.SlowQueryThreshold < 0 {
+		return fmt.Errorf("slow query threshold must not be negative")
+	}
+	if c.Observability.SlowQueryThreshold > 0 && c.Observability.SlowQueryTopN <= 0 {
+		return fmt.Errorf("slow query top N must be positive")
+	}
+	if c.Observability.MaxTimeRange < 0 {
+		return fmt.Errorf("max time range must not be negative")
+	}
+	if c.Observability.ClockMaxSkew < 0 {
+		return fmt.Errorf("clock max skew must not be negative")
+	}
+
+	// Validate histogram config
+	for _, histogram := range []struct {
+		name    string
+		buckets []float64
+	}{
+		{"matching", c.OpenTelemetry.Histograms.MatchingBuckets},
+		{"trip duration", c.OpenTelemetry.Histograms.TripDurationBuckets},
+		{"message processing", c.OpenTelemetry.Histograms.MessageProcessingBuckets},
+		{"matching queue wait", c.OpenTelemetry.Histograms.MatchingQueueWaitBuckets},
+	} {
+		if len(histogram.buckets) == 0 {
+			return fmt.Errorf("%s histogram buckets are required", histogram.name)
+		}
+		for i, bound := range histogram.buckets {
+			if bound <= 0 || (i > 0 && bound <= histogram.buckets[i-1]) {
+				return fmt.Errorf("%s histogram buckets must be positive and increasing", histogram.name)
+			}
+		}
+	}
+	if c.OpenTelemetry.Histograms.NativeHistograms && c.OpenTelemetry.Histograms.NativeBucketFactor <= 1 {
+		return fmt.Errorf("native histogram bucket factor must be greater than 1")
+	}
+
+	// Validate rate limit config
+	if c.RateLimit.RequestsPerMinute <= 0 {
+		return fmt.Errorf("rate limit requests per minute must be positive")
+	}
+	if c.RateLimit.Burst <= 0 {
+		return fmt.Errorf("rate limit burst must be positive")
+	}
+
+	// Validate compression config
+	if c.Compression.Level < 1 || c.Compression.Level > 9 {
+		return fmt.Errorf("compression level must be between 1 and 9")
+	}
+	if c.Compression.MinSize < 0 {
+		return fmt.Errorf("compression min size must not be negative")
+	}
+
+	// Validate body logging config
+	if c.BodyLogging.SampleRate < 0 || c.BodyLogging.SampleRate > 1 {
+		return fmt.Errorf("body logging sample rate must be between 0 and 1")
+	}
+	if c.BodyLogging.MaxBodySize <= 0 {
+		return fmt.Errorf("body logging max body size must be positive")
+	}
+
+	// Validate traffic recording config
+	if c.Recording.Enabled && c.Recording.Path == "" {
+		return fmt.Errorf("traffic recording path is required when recording is enabled")
+	}
+	if c.Recording.MaxBodySize <= 0 {
+		return fmt.Errorf("traffic recording max body size must be positive")
+	}
+	if c.Recording.MaxRequests <= 0 {
+		return fmt.Errorf("traffic recording max requests must be positive")
+	}
+
+	return nil
+}
+
+// GetDSN returns the PostgreSQL data source name
+func (c *Config) GetDSN() string {
+	return fmt.Sprintf(
+		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
+		c.Database.Host,
+		c.Database.Port,
+		c.Database.User,
+		c.Database.Password,
+		c.Database.DBName,
+		c.Database.SSLMode,
+	)
+}
+
+// GetRedisAddr returns the Redis address
+func (c *Config) GetRedisAddr() string {
+	return fmt.Sprintf("%s:%s", c.Redis.Host, c.Redis.Port)
+}
+
+// GetServerAddr returns the server address
+func (c *Config) GetServerAddr() string {
+	return fmt.Sprintf("%s:%s", c.Server.Host, c.Server.Port)
+}
+
+// Helper functions for environment variable parsing
+
+func getEnv(key, defaultValue string) string {
+	if value := lookupEnv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
 
 func getIntEnv(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		if intValue, err := strconv.Atoi(value); err == nil {
 			return intValue
 		}
@@ -308,7 +1905,7 @@ func getIntEnv(key string, defaultValue int) int {
 }
 
 func getBoolEnv(key string, defaultValue bool) bool {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		if boolValue, err := strconv.ParseBool(value); err == nil {
 			return boolValue
 		}
@@ -317,7 +1914,7 @@ func getBoolEnv(key string, defaultValue bool) bool {
 }
 
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		if duration, err := time.ParseDuration(value); err == nil {
 			return duration
 		}
@@ -326,7 +1923,7 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 }
 
 func getFloatEnv(key string, defaultValue float64) float64 {
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
 			return floatVal
 		}
@@ -336,7 +1933,7 @@ func getFloatEnv(key string, defaultValue float64) float64 {
 
 func getMapEnv(key string) map[string]string {
 	result := make(map[string]string)
-	if value := os.Getenv(key); value != "" {
+	if value := lookupEnv(key); value != "" {
 		// Parse comma-separated key=value pairs
 		pairs := strings.Split(value, ",")
 		for _, pair := range pairs {
@@ -348,9 +1945,32 @@ func getMapEnv(key string) map[string]string {
 	return result
 }
 
+// getIntMapEnv parses comma-separated key=value pairs with integer values, skipping invalid ones
+func getIntMapEnv(key string) map[string]int {
+	result := make(map[string]int)
+	for k, v := range getMapEnv(key) {
+		if intVal, err := strconv.Atoi(v); err == nil {
+			result[k] = intVal
+		}
+	}
+	return result
+}
+
+// getDurationMapEnv parses comma-separated key=value pairs with duration values, skipping invalid
+// ones
+func getDurationMapEnv(key string) map[string]time.Duration {
+	result := make(map[string]time.Duration)
+	for k, v := range getMapEnv(key) {
+		if duration, err := time.ParseDuration(v); err == nil {
+			result[k] = duration
+		}
+	}
+	return result
+}
+
 // getStringSliceEnv gets a string slice from environment variable (comma-separated)
 func getStringSliceEnv(key string, defaultValue []string) []string {
-	value := os.Getenv(key)
+	value := lookupEnv(key)
 	if value == "" {
 		return defaultValue
 	}
@@ -366,6 +1986,782 @@ func getStringSliceEnv(key string, defaultValue []string) []string {
 	return result
 }
 
+// getFloatSliceEnv parses a comma-separated list of numbers, such as histogram buckets.
+// Entries that are not numbers are skipped.
+func getFloatSliceEnv(key string, defaultValue []float64) []float64 {
+	value := lookupEnv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []float64
+	for _, part := range strings.Split(value, ",") {
+		if number, err := strconv.ParseFloat(strings.TrimSpace(part), 64); err == nil {
+			result = append(result, number)
+		}
+	}
+	return result
+}
+
+// DefaultHistogramConfig returns the latency histogram buckets used when none are configured.
+// Message processing buckets start at 10µs, as actors handle most messages in microseconds.
+func DefaultHistogramConfig() HistogramConfig {
+	return HistogramConfig{
+		MatchingBuckets:          []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		TripDurationBuckets:      []float64{60, 300, 600, 900, 1200, 1800, 2700, 3600, 5400, 7200},
+		MessageProcessingBuckets: []float64{0.00001, 0.000025, 0.00005, 0.0001, 0.00025, 0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1},
+		MatchingQueueWaitBuckets: []float64{0.0001, 0.0005, 0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+		NativeBucketFactor:       1.1,
+	}
+}
+
+// DefaultSLOObjectives returns the objectives for the ride endpoints used when none are configured
+func DefaultSLOObjectives() []SLOObjective {
+	return []SLOObjective{
+		{Method: "POST", Endpoint: "/api/v1/rides/request", LatencyTarget: 500 * time.Millisecond, LatencyObjective: 0.99, AvailabilityObjective: 0.999},
+		{Method: "POST", Endpoint: "/api/v1/rides/:id/cancel", LatencyTarget: 300 * time.Millisecond, LatencyObjective: 0.99, AvailabilityObjective: 0.999},
+		{Method: "GET", Endpoint: "/api/v1/rides/:id/status", LatencyTarget: 200 * time.Millisecond, LatencyObjective: 0.99, AvailabilityObjective: 0.999},
+	}
+}
+
+// getSLOObjectivesEnv parses semicolon-separated objectives of the form
+// "METHOD /path|latency_target|latency_objective|availability_objective",
+// e.g. "GET /api/v1/rides/:id/status|200ms|0.99|0.999". Invalid entries are skipped.
+func getSLOObjectivesEnv(key string, defaultValue []SLOObjective) []SLOObjective {
+	value := lookupEnv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []SLOObjective
+	for _, entry := range strings.Split(value, ";") {
+		parts := strings.Split(strings.TrimSpace(entry), "|")
+		if len(parts) != 4 {
+			continue
+		}
+		route := strings.Fields(parts[0])
+		if len(route) != 2 {
+			continue
+		}
+		target, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil {
+			continue
+		}
+		latency, err := strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+		if err != nil {
+			continue
+		}
+		availability, err := strconv.ParseFloat(strings.TrimSpace(parts[3]), 64)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, SLOObjective{
+			Method:                strings.ToUpper(route[0]),
+			Endpoint:              route[1],
+			LatencyTarget:         target,
+			LatencyObjective:      latency,
+			AvailabilityObjective: availability,
+		})
+	}
+	return result
+}
+
+// getEndpointTimeoutsEnv parses semicolon-separated time budgets of the form "METHOD /path|timeout",
+// e.g. "GET /api/v1/rides/:id/status|2s". Invalid entries are skipped.
+func getEndpointTimeoutsEnv(key string, defaultValue []EndpointTimeout) []EndpointTimeout {
+	value := lookupEnv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []EndpointTimeout
+	for _, entry := range strings.Split(value, ";") {
+		parts := strings.Split(strings.TrimSpace(entry), "|")
+		if len(parts) != 2 {
+			continue
+		}
+		route := strings.Fields(parts[0])
+		if len(route) != 2 {
+			continue
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil {
+			continue
+		}
+
+		result = append(result, EndpointTimeout{
+			Method:   strings.ToUpper(route[0]),
+			Endpoint: route[1],
+			Timeout:  timeout,
+		})
+	}
+	return result
+}
+
+// getServiceAreasEnv parses semicolon-separated areas of the form "name:lat lng,lat lng,...", e.g.
+// "jakarta:-6.08 106.68,-6.08 107.0,-6.38 107.0,-6.38 106.68". Invalid entries are skipped.
+func getServiceAreasEnv(key string, defaultValue []ServiceArea) []ServiceArea {
+	value := lookupEnv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []ServiceArea
+	for _, entry := range strings.Split(value, ";") {
+		name, vertices, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			continue
+		}
+		area := ServiceArea{Name: strings.TrimSpace(name)}
+		for _, vertex := range strings.Split(vertices, ",") {
+			coords := strings.Fields(vertex)
+			if len(coords) != 2 {
+				area.Polygon = nil
+				break
+			}
+			lat, latErr := strconv.ParseFloat(coords[0], 64)
+			lng, lngErr := strconv.ParseFloat(coords[1], 64)
+			if latErr != nil || lngErr != nil {
+				area.Polygon = nil
+				break
+			}
+			area.Polygon = append(area.Polygon, GeoPoint{Lat: lat, Lng: lng})
+		}
+		if area.Polygon != nil {
+			result = append(result, area)
+		}
+	}
+	return result
+}
+
+// weekdays maps the day names used in SERVICE_HOURS to weekdays
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// getOperatingHoursEnv parses semicolon-separated hours of the form "days=HH:MM-HH:MM", the days
+// being comma-separated names or ranges, or * for every day, e.g.
+// "mon-fri=06:00-23:00;sat,sun=08:00-02:00". Invalid entries are skipped.
+func getOperatingHoursEnv(key string, defaultValue []OperatingHours) []OperatingHours {
+	value := lookupEnv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []OperatingHours
+	for _, entry := range strings.Split(value, ";") {
+		days, span, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		opens, closes, ok := strings.Cut(span, "-")
+		if !ok {
+			continue
+		}
+		hours := OperatingHours{}
+		var openErr, closeErr error
+		hours.Open, openErr = parseClockTime(opens)
+		hours.Close, closeErr = parseClockTime(closes)
+		if openErr != nil || closeErr != nil {
+			continue
+		}
+		if hours.Days, ok = parseWeekdays(days); ok {
+			result = append(result, hours)
+		}
+	}
+	return result
+}
+
+// parseClockTime parses a local time of day of the form HH:MM, up to 24:00, as the time since midnight
+func parseClockTime(value string) (time.Duration, error) {
+	hours, minutes, ok := strings.Cut(strings.TrimSpace(value), ":")
+	if !ok {
+		return 0, fmt.Errorf("invalid time of day: %q", value)
+	}
+	h, hErr := strconv.Atoi(hours)
+	m, mErr := strconv.Atoi(minutes)
+	if hErr != nil || mErr != nil || h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, fmt.Errorf("invalid time of day: %q", value)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
+
+// parseWeekdays parses comma-separated day names and ranges such as "mon-fri,sun"; * is every
+// day and returns no days
+func parseWeekdays(value string) ([]time.Weekday, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "*" {
+		return nil, true
+	}
+
+	var days []time.Weekday
+	for _, part := range strings.Split(value, ",") {
+		first, last, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		from, ok := weekdays[first]
+		if !ok {
+			return nil, false
+		}
+		to := from
+		if isRange {
+			if to, ok = weekdays[last]; !ok {
+				return nil, false
+			}
+		}
+		for day := from; ; day = (day + 1) % 7 {
+			days = append(days, day)
+			if day == to {
+				break
+			}
+		}
+	}
+	return days, true
+}
+
+// getRedactionRulesEnv parses comma-separated path=action pairs, keeping their order since the
+// first matching rule wins
+func getRedactionRulesEnv(key string, defaultValue []RedactionRule) []RedactionRule {
+	value := lookupEnv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []RedactionRule
+	for _, pair := range strings.Split(value, ",") {
+		if kv := strings.SplitN(strings.TrimSpace(pair), "=", 2); len(kv) == 2 {
+			result = append(result, RedactionRule{
+				Path:   strings.TrimSpace(kv[0]),
+				Action: strings.TrimSpace(kv[1]),
+			})
+		}
+	}
+	return result
+}
+
+// getAnonymizationRulesEnv parses comma-separated column=action pairs, keeping their order as
+// the order of the exported columns
+func getAnonymizationRulesEnv(key string, defaultValue []AnonymizationRule) []AnonymizationRule {
+	value := lookupEnv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	var result []AnonymizationRule
+	for _, pair := range strings.Split(value, ",") {
+		if kv := strings.SplitN(strings.TrimSpace(pair), "=", 2); len(kv) == 2 {
+			result = append(result, AnonymizationRule{
+				Column: strings.TrimSpace(kv[0]),
+				Action: strings.TrimSpace(kv[1]),
+			})
+		}
+	}
+	return result
+}
+
+// DefaultRedactionRules returns the rules hiding contact details, addresses and exact
+// coordinates used when none are configured
+func DefaultRedactionRules() []RedactionRule {
+	return []RedactionRule{
+		{Path: "*email", Action: "hash"},
+		{Path: "*phone", Action: "hash"},
+		{Path: "*address", Action: "remove"},
+		{Path: "*latitude", Action: "round"},
+		{Path: "*longitude", Action: "round"},
+		{Path: "*lat", Action: "round"},
+		{Path: "*lng", Action: "round"},
+	}
+}
+
+// DefaultRedactionConfig returns the redaction settings used when none are configured
+func DefaultRedactionConfig() RedactionConfig {
+	return RedactionConfig{
+		Enabled: true,
+		Rules:   DefaultRedactionRules(),
+	}
+}
+
+// DefaultPayloadConfig returns the payload size limits used when none are configured
+func DefaultPayloadConfig() PayloadConfig {
+	return PayloadConfig{
+		MaxSize:              64 * 1024,
+		CompressionThreshold: 8 * 1024,
+		PreviewSize:          1024,
+	}
+}
+
+// DefaultForecastConfig returns the demand forecast settings used when none are configured
+func DefaultForecastConfig() ForecastConfig {
+	return ForecastConfig{
+		HistoryWindow:       28 * 24 * time.Hour,
+		MaxHorizon:          168,
+		MovingAverageWindow: 24,
+		Alpha:               0.3,
+		Beta:                0.05,
+		Gamma:               0.2,
+	}
+}
+
+// DefaultHeatmapConfig returns the heatmap settings used when none are configured
+func DefaultHeatmapConfig() HeatmapConfig {
+	return HeatmapConfig{
+		DefaultRange: 24 * time.Hour,
+		MaxRange:     31 * 24 * time.Hour,
+		CacheTTL:     5 * time.Minute,
+	}
+}
+
+// DefaultDashboardConfig returns the dashboard refresh schedule used when none is configured
+func DefaultDashboardConfig() DashboardConfig {
+	return DashboardConfig{
+		TripRefreshInterval:   5 * time.Minute,
+		SupplyRefreshInterval: 30 * time.Second,
+		RefreshWindow:         48 * time.Hour,
+		MaxHours:              168,
+		ZonePrecision:         5,
+	}
+}
+
+// DefaultChatConfig returns the in-trip chat settings used when none are configured
+func DefaultChatConfig() ChatConfig {
+	return ChatConfig{
+		Filters:         []string{"profanity", "pii"},
+		RetentionPeriod: 30 * 24 * time.Hour,
+		PurgeInterval:   time.Hour,
+	}
+}
+
+// DefaultReportingConfig returns the reporting settings used when none are configured
+func DefaultReportingConfig() ReportingConfig {
+	return ReportingConfig{
+		Timezone: "UTC",
+		MaxDays:  93,
+	}
+}
+
+// DefaultFareConfig returns the fare settings used when none are configured
+func DefaultFareConfig() FareConfig {
+	return FareConfig{
+		BaseFare:              5,
+		PerKm:                 2,
+		MaxDetourRatio:        2,
+		MaxDropoffDeviationKm: 1,
+		MaxFareDeviation:      0.5,
+	}
+}
+
+// DefaultPickupWaitConfig returns the pickup wait settings used when none are configured
+func DefaultPickupWaitConfig() PickupWaitConfig {
+	return PickupWaitConfig{
+		FreePeriod:    2 * time.Minute,
+		FeePerMinute:  0.5,
+		NoShowWindow:  5 * time.Minute,
+		NoShowFee:     5,
+		ZonePrecision: 5,
+	}
+}
+
+// DefaultCancellationPolicyConfig returns the cancellation policy used when none is configured
+func DefaultCancellationPolicyConfig() CancellationPolicyConfig {
+	return CancellationPolicyConfig{
+		GracePeriod: 2 * time.Minute,
+		Fee:         3,
+		ArrivedFee:  5,
+		FreeReasons: []string{"driver_not_moving", "safety_concern", "driver_cancelled"},
+	}
+}
+
+// DefaultRematchConfig returns the re-matching settings used when none are configured
+func DefaultRematchConfig() RematchConfig {
+	return RematchConfig{
+		MaxRematches: 2,
+	}
+}
+
+// DefaultMatchingConfig returns the traditional matching worker pool used when none is configured
+func DefaultMatchingConfig() MatchingConfig {
+	return MatchingConfig{
+		Workers:      8,
+		QueueSize:    100,
+		QueueTimeout: 5 * time.Second,
+	}
+}
+
+// DefaultLocationIngestConfig returns the location update buffering used when none is configured
+func DefaultLocationIngestConfig() LocationIngestConfig {
+	return LocationIngestConfig{
+		Enabled:       true,
+		FlushInterval: 2 * time.Second,
+		MaxPending:    1000,
+		CacheTTL:      5 * time.Minute,
+	}
+}
+
+// DefaultPaymentReconciliationConfig returns the payment reconciliation schedule used when none is
+// configured
+func DefaultPaymentReconciliationConfig() PaymentReconciliationConfig {
+	return PaymentReconciliationConfig{
+		Interval:    15 * time.Minute,
+		GracePeriod: time.Hour,
+		Lookback:    7 * 24 * time.Hour,
+		Tolerance:   0.01,
+	}
+}
+
+// DefaultTimeoutConfig returns the request time budgets used when none are configured
+func DefaultTimeoutConfig() TimeoutConfig {
+	return TimeoutConfig{
+		Endpoints: DefaultEndpointTimeouts(),
+	}
+}
+
+// DefaultEndpointTimeouts returns the time budgets of the ride endpoints used when none are
+// configured
+func DefaultEndpointTimeouts() []EndpointTimeout {
+	return []EndpointTimeout{
+		{Method: "POST", Endpoint: "/api/v1/rides/request", Timeout: 10 * time.Second},
+		{Method: "POST", Endpoint: "/api/v1/rides/:id/cancel", Timeout: 5 * time.Second},
+		{Method: "GET", Endpoint: "/api/v1/rides/:id/status", Timeout: 2 * time.Second},
+	}
+}
+
+// DefaultFatigueConfig returns the fatigue limits used when none are configured
+func DefaultFatigueConfig() FatigueConfig {
+	return FatigueConfig{
+		Enabled:       true,
+		Window:        24 * time.Hour,
+		MaxOnline:     12 * time.Hour,
+		MaxDriving:    10 * time.Hour,
+		RestPeriod:    8 * time.Hour,
+		CheckInterval: time.Minute,
+	}
+}
+
+// DefaultOnboardingConfig returns the onboarding settings used when none are configured: drivers
+// go online whatever their onboarding stage
+func DefaultOnboardingConfig() OnboardingConfig {
+	return OnboardingConfig{RequireActivation: false}
+}
+
+// DefaultServiceAreaConfig returns the service area settings used when none are configured: rides
+// may be requested anywhere at any time
+func DefaultServiceAreaConfig() ServiceAreaConfig {
+	return ServiceAreaConfig{
+		Enabled:  false,
+		Timezone: "UTC",
+	}
+}
+
+// DefaultLockConfig returns the lock settings used when none are configured
+func DefaultLockConfig() LockConfig {
+	return LockConfig{
+		KeyPrefix:      "lock:",
+		TTL:            30 * time.Second,
+		RetryInterval:  100 * time.Millisecond,
+		AcquireTimeout: 10 * time.Second,
+	}
+}
+
+// DefaultLeaderConfig returns the leader election settings used when none are configured
+func DefaultLeaderConfig() LeaderConfig {
+	return LeaderConfig{
+		Backend:       "auto",
+		RetryInterval: 5 * time.Second,
+	}
+}
+
+// DefaultStorageConfig returns the file storage settings used when none are configured
+func DefaultStorageConfig() StorageConfig {
+	return StorageConfig{
+		Backend:       "local",
+		LocalDir:      "./data/uploads",
+		URLTTL:        15 * time.Minute,
+		MaxAvatarSize: 5 << 20,
+		S3Region:      "us-east-1",
+	}
+}
+
+// DefaultInvoiceConfig returns the invoice batch job settings used when none are configured
+func DefaultInvoiceConfig() InvoiceConfig {
+	return InvoiceConfig{
+		Formats:       []string{"csv", "pdf"},
+		CheckInterval: time.Hour,
+		MaxAttempts:   5,
+	}
+}
+
+// DefaultETAConfig returns the ETA model and accuracy settings used when none are configured
+func DefaultETAConfig() ETAConfig {
+	return ETAConfig{
+		AverageSpeedKmh:  30,
+		ZonePrecision:    5,
+		AccuracyWindow:   24 * time.Hour,
+		AccuracyInterval: 5 * time.Minute,
+		AdjustRate:       0.2,
+		MinSamples:       50,
+		MinSpeedKmh:      5,
+		MaxSpeedKmh:      80,
+	}
+}
+
+// DefaultAccessibilityConfig returns the accessible ride tracking settings used when none are
+// configured
+func DefaultAccessibilityConfig() AccessibilityConfig {
+	return AccessibilityConfig{
+		ZonePrecision: 5,
+	}
+}
+
+// DefaultLocationTrailConfig returns the location trail settings used when none are configured
+func DefaultLocationTrailConfig() LocationTrailConfig {
+	return LocationTrailConfig{
+		RetentionPeriod:    90 * 24 * time.Hour,
+		DownsampleInterval: time.Hour,
+		MinuteAfter:        24 * time.Hour,
+		TenMinutesAfter:    7 * 24 * time.Hour,
+		MaxPoints:          5000,
+	}
+}
+
+// DefaultFraudConfig returns the fraud detection thresholds used when none are configured
+func DefaultFraudConfig() FraudConfig {
+	return FraudConfig{
+		MaxTripSpeedKmh:       150,
+		TeleportSpeedKmh:      300,
+		TeleportMinDistanceKm: 1,
+		CancellationWindow:    24 * time.Hour,
+		CancellationThreshold: 3,
+		PairWindow:            7 * 24 * time.Hour,
+		PairThreshold:         5,
+	}
+}
+
+// DefaultAPIUsageConfig returns the API usage recording settings used when none are configured
+func DefaultAPIUsageConfig() APIUsageConfig {
+	return APIUsageConfig{
+		FlushInterval:   30 * time.Second,
+		RetentionPeriod: 90 * 24 * time.Hour,
+	}
+}
+
+// DefaultAnonymizationRules returns the columns of the research dataset exported when none are
+// configured: every column, with hashed IDs, geohashed coordinates and jittered timestamps
+func DefaultAnonymizationRules() []AnonymizationRule {
+	return []AnonymizationRule{
+		{Column: "trip_id", Action: "hash"},
+		{Column: "passenger_id", Action: "hash"},
+		{Column: "driver_id", Action: "hash"},
+		{Column: "status", Action: "keep"},
+		{Column: "pickup", Action: "geohash"},
+		{Column: "dropoff", Action: "geohash"},
+		{Column: "requested_at", Action: "jitter"},
+		{Column: "matched_at", Action: "jitter"},
+		{Column: "pickup_at", Action: "jitter"},
+		{Column: "completed_at", Action: "jitter"},
+		{Column: "cancelled_at", Action: "jitter"},
+		{Column: "distance_km", Action: "keep"},
+		{Column: "duration_minutes", Action: "keep"},
+		{Column: "fare_amount", Action: "round"},
+	}
+}
+
+// DefaultResearchExportConfig returns the research export settings used when none are configured
+func DefaultResearchExportConfig() ResearchExportConfig {
+	return ResearchExportConfig{
+		Columns:          DefaultAnonymizationRules(),
+		GeohashPrecision: 6,
+		KAnonymity:       5,
+		TimestampJitter:  10 * time.Minute,
+		MaxPeriod:        92 * 24 * time.Hour,
+	}
+}
+
+// DefaultMetricsArchiveConfig returns the metrics archive settings used when none are configured
+func DefaultMetricsArchiveConfig() MetricsArchiveConfig {
+	return MetricsArchiveConfig{
+		Sink:          "local",
+		LocalDir:      "./data/metrics",
+		Prefix:        "metrics",
+		CheckInterval: time.Hour,
+		BackfillDays:  7,
+		BatchSize:     10000,
+	}
+}
+
+// DefaultEmailConfig returns the email notification settings used when none are configured
+func DefaultEmailConfig() EmailConfig {
+	return EmailConfig{
+		SMTPPort:       587,
+		Timeout:        10 * time.Second,
+		QueueSize:      1000,
+		Workers:        2,
+		MaxAttempts:    5,
+		InitialBackoff: 5 * time.Second,
+		MaxBackoff:     5 * time.Minute,
+		Receipts:       true,
+	}
+}
+
+// DefaultAppVersionConfig returns the app version settings used when none are configured: no
+// platform is gated and no feature is enabled
+func DefaultAppVersionConfig() AppVersionConfig {
+	return AppVersionConfig{
+		MinVersions:     map[string]string{},
+		FeatureVersions: map[string]string{},
+	}
+}
+
+// DefaultTripCacheConfig returns the trip status cache settings used when none are configured
+func DefaultTripCacheConfig() TripCacheConfig {
+	return TripCacheConfig{TTL: 30 * time.Second}
+}
+
+// DefaultIntegrityCheckConfig returns the observability integrity check settings used when none
+// are configured: hourly runs over the last day, kept for a week, disabled
+func DefaultIntegrityCheckConfig() IntegrityCheckConfig {
+	return IntegrityCheckConfig{
+		Enabled:         false,
+		Interval:        time.Hour,
+		Lookback:        24 * time.Hour,
+		RetentionPeriod: 7 * 24 * time.Hour,
+	}
+}
+
+// appVersionPattern matches the major[.minor[.patch]] app versions, with an optional v prefix
+var appVersionPattern = regexp.MustCompile(`^v?\d{1,6}(\.\d{1,6}){0,2}$`)
+
+// DefaultExperimentConfig returns the experiment dataset settings used when none are configured
+func DefaultExperimentConfig() ExperimentConfig {
+	return ExperimentConfig{
+		SchemaPrefix: "experiment_",
+	}
+}
+
+// isSchemaPrefix reports whether prefix can start an unquoted PostgreSQL schema name, leaving
+// room for the experiment name within the 63 byte identifier limit
+func isSchemaPrefix(prefix string) bool {
+	if prefix == "" || len(prefix) > 20 {
+		return false
+	}
+	for i, r := range prefix {
+		switch {
+		case r >= 'a' && r <= 'z', r == '_':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultHTTPClientConfig returns the outbound HTTP client settings used when none are configured
+func DefaultHTTPClientConfig() HTTPClientConfig {
+	return HTTPClientConfig{
+		Timeout:          10 * time.Second,
+		MaxRetries:       2,
+		InitialBackoff:   100 * time.Millisecond,
+		MaxBackoff:       2 * time.Second,
+		RetryBudgetRatio: 0.2,
+		RetryBudgetBurst: 10,
+		BreakerFailures:  5,
+		BreakerCooldown:  30 * time.Second,
+	}
+}
+
+// DefaultRetryConfig returns the retry policy used when none is configured
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts:       3,
+		InitialBackoff:    100 * time.Millisecond,
+		MaxBackoff:        5 * time.Second,
+		Multiplier:        2,
+		Jitter:            0.2,
+		MaxAttemptsByType: make(map[string]int),
+	}
+}
+
+// Validate checks the retry policy
+func (r RetryConfig) Validate() error {
+	if r.MaxAttempts <= 0 {
+		return fmt.Errorf("actor retry max attempts must be positive")
+	}
+	for messageType, attempts := range r.MaxAttemptsByType {
+		if attempts <= 0 {
+			return fmt.Errorf("actor retry max attempts for %s must be positive", messageType)
+		}
+	}
+	if r.InitialBackoff < 0 || r.MaxBackoff < 0 {
+		return fmt.Errorf("actor retry backoff must not be negative")
+	}
+	if r.Jitter < 0 || r.Jitter > 1 {
+		return fmt.Errorf("actor retry jitter must be between 0 and 1")
+	}
+	return nil
+}
+
+// DefaultWebhookConfig returns the webhook delivery settings used when none are configured
+func DefaultWebhookConfig() WebhookConfig {
+	return WebhookConfig{
+		Timeout:        5 * time.Second,
+		PollInterval:   time.Second,
+		BatchSize:      50,
+		MaxAttempts:    6,
+		InitialBackoff: 10 * time.Second,
+		MaxBackoff:     time.Hour,
+	}
+}
+
+// DefaultAuthConfig returns the device session settings used when none are configured
+func DefaultAuthConfig() AuthConfig {
+	return AuthConfig{
+		AccessTokenTTL:     15 * time.Minute,
+		RefreshTokenTTL:    30 * 24 * time.Hour,
+		MaxSessionsPerUser: 5,
+	}
+}
+
+// DefaultCompressionConfig returns the response compression settings used when none are configured
+func DefaultCompressionConfig() CompressionConfig {
+	return CompressionConfig{
+		Enabled:       true,
+		Level:         5,
+		MinSize:       1024,
+		ExcludedPaths: []string{"/prometheus"},
+	}
+}
+
+// DefaultBodyLoggingConfig returns the body logging settings used when none are configured
+func DefaultBodyLoggingConfig() BodyLoggingConfig {
+	return BodyLoggingConfig{
+		Enabled:     false,
+		SampleRate:  0.01,
+		MaxBodySize: 4096,
+	}
+}
+
+// DefaultTrafficRecordingConfig returns the traffic recording settings used when none are
+// configured
+func DefaultTrafficRecordingConfig() TrafficRecordingConfig {
+	return TrafficRecordingConfig{
+		Enabled:       false,
+		Path:          "traffic-recording.jsonl",
+		MaxBodySize:   65536,
+		MaxRequests:   1000000,
+		ExcludedPaths: []string{"/health", "/prometheus", "/swagger"},
+	}
+}
+
+// DefaultHTTPCacheConfig returns the read endpoint caching settings used when none are configured.
+// Clients may keep responses but must revalidate them with If-None-Match before reuse.
+func DefaultHTTPCacheConfig() HTTPCacheConfig {
+	return HTTPCacheConfig{
+		Enabled:             true,
+		UsersCacheControl:   "private, no-cache",
+		DriversCacheControl: "private, no-cache",
+		RidesCacheControl:   "private, no-cache",
+	}
+}
+
 // Development returns a configuration suitable for development
 func Development() *Config {
 	return &Config{
@@ -378,6 +2774,7 @@ func Development() *Config {
 			Mode:         "debug",
 		},
 		Database: DatabaseConfig{
+			Driver:          "postgres",
 			Host:            "localhost",
 			Port:            "5432",
 			User:            "postgres",
@@ -388,8 +2785,16 @@ func Development() *Config {
 			MaxIdleConns:    2,
 			ConnMaxLifetime: 5 * time.Minute,
 			ConnMaxIdleTime: 5 * time.Minute,
+
+			SchemaCheckOnStartup: true,
+			MigrationsDir:        "migrations",
+
+			ChangeNotifications:        true,
+			ChangeListenerMinReconnect: time.Second,
+			ChangeListenerMaxReconnect: time.Minute,
 		},
 		Redis: RedisConfig{
+			Enabled:      true,
 			Host:         "localhost",
 			Port:         "6379",
 			Password:     "",
@@ -403,6 +2808,8 @@ func Development() *Config {
 		Actor: ActorConfig{
 			MaxActors:           1000,
 			SupervisionStrategy: "restart",
+			Retry:               DefaultRetryConfig(),
+			GoroutineLeakGrace:  30 * time.Second,
 		},
 		Logging: LoggingConfig{
 			Level:          "debug",
@@ -422,6 +2829,59 @@ func Development() *Config {
 			RetentionPeriod: 24 * time.Hour,
 			BatchSize:       50,
 		},
+		RateLimit: RateLimitConfig{
+			RequestsPerMinute: 1000,
+			Burst:             50,
+		},
+		Compression: DefaultCompressionConfig(),
+		BodyLogging: DefaultBodyLoggingConfig(),
+		Recording:   DefaultTrafficRecordingConfig(),
+		HTTPCache:   DefaultHTTPCacheConfig(),
+		Retention: RetentionConfig{
+			MaintenanceInterval:  time.Hour,
+			PartitionPremakeDays: 3,
+		},
+		SLO: SLOConfig{
+			Window:     time.Hour,
+			Objectives: DefaultSLOObjectives(),
+		},
+		Webhook:       DefaultWebhookConfig(),
+		Auth:          DefaultAuthConfig(),
+		Redaction:     DefaultRedactionConfig(),
+		Payload:       DefaultPayloadConfig(),
+		Forecast:      DefaultForecastConfig(),
+		Heatmap:       DefaultHeatmapConfig(),
+		Dashboard:     DefaultDashboardConfig(),
+		Chat:          DefaultChatConfig(),
+		Reporting:     DefaultReportingConfig(),
+		Fare:          DefaultFareConfig(),
+		PickupWait:    DefaultPickupWaitConfig(),
+		Cancellation:  DefaultCancellationPolicyConfig(),
+		Rematch:       DefaultRematchConfig(),
+		Matching:      DefaultMatchingConfig(),
+		Timeout:       DefaultTimeoutConfig(),
+		Location:      DefaultLocationIngestConfig(),
+		Payments:      DefaultPaymentReconciliationConfig(),
+		Fatigue:       DefaultFatigueConfig(),
+		Onboarding:    DefaultOnboardingConfig(),
+		ServiceArea:   DefaultServiceAreaConfig(),
+		Lock:          DefaultLockConfig(),
+		Leader:        DefaultLeaderConfig(),
+		Storage:       DefaultStorageConfig(),
+		Invoice:       DefaultInvoiceConfig(),
+		Experiment:    DefaultExperimentConfig(),
+		ETA:           DefaultETAConfig(),
+		Accessibility: DefaultAccessibilityConfig(),
+		LocationTrail: DefaultLocationTrailConfig(),
+		Fraud:         DefaultFraudConfig(),
+		APIUsage:      DefaultAPIUsageConfig(),
+		Research:      DefaultResearchExportConfig(),
+		Archive:       DefaultMetricsArchiveConfig(),
+		Email:         DefaultEmailConfig(),
+		AppVersion:    DefaultAppVersionConfig(),
+		Integrity:     DefaultIntegrityCheckConfig(),
+		TripCache:     DefaultTripCacheConfig(),
+		HTTPClient:    DefaultHTTPClientConfig(),
 	}
 }
 
@@ -437,6 +2897,7 @@ func Production() *Config {
 			Mode:         "release",
 		},
 		Database: DatabaseConfig{
+			Driver:          "postgres",
 			Host:            "localhost",
 			Port:            "5432",
 			User:            "postgres",
@@ -447,8 +2908,16 @@ func Production() *Config {
 			MaxIdleConns:    10,
 			ConnMaxLifetime: 10 * time.Minute,
 			ConnMaxIdleTime: 10 * time.Minute,
+
+			SchemaCheckOnStartup: true,
+			MigrationsDir:        "migrations",
+
+			ChangeNotifications:        true,
+			ChangeListenerMinReconnect: time.Second,
+			ChangeListenerMaxReconnect: time.Minute,
 		},
 		Redis: RedisConfig{
+			Enabled:      true,
 			Host:         "localhost",
 			Port:         "6379",
 			Password:     "",
@@ -462,6 +2931,8 @@ func Production() *Config {
 		Actor: ActorConfig{
 			MaxActors:           50000,
 			SupervisionStrategy: "restart",
+			Retry:               DefaultRetryConfig(),
+			GoroutineLeakGrace:  30 * time.Second,
 		},
 		Logging: LoggingConfig{
 			Level:          "info",
@@ -481,5 +2952,58 @@ func Production() *Config {
 			RetentionPeriod: 7 * 24 * time.Hour,
 			BatchSize:       100,
 		},
+		RateLimit: RateLimitConfig{
+			RequestsPerMinute: 100,
+			Burst:             10,
+		},
+		Compression: DefaultCompressionConfig(),
+		BodyLogging: DefaultBodyLoggingConfig(),
+		Recording:   DefaultTrafficRecordingConfig(),
+		HTTPCache:   DefaultHTTPCacheConfig(),
+		Retention: RetentionConfig{
+			MaintenanceInterval:  time.Hour,
+			PartitionPremakeDays: 7,
+		},
+		SLO: SLOConfig{
+			Window:     time.Hour,
+			Objectives: DefaultSLOObjectives(),
+		},
+		Webhook:       DefaultWebhookConfig(),
+		Auth:          DefaultAuthConfig(),
+		Redaction:     DefaultRedactionConfig(),
+		Payload:       DefaultPayloadConfig(),
+		Forecast:      DefaultForecastConfig(),
+		Heatmap:       DefaultHeatmapConfig(),
+		Dashboard:     DefaultDashboardConfig(),
+		Chat:          DefaultChatConfig(),
+		Reporting:     DefaultReportingConfig(),
+		Fare:          DefaultFareConfig(),
+		PickupWait:    DefaultPickupWaitConfig(),
+		Cancellation:  DefaultCancellationPolicyConfig(),
+		Rematch:       DefaultRematchConfig(),
+		Matching:      DefaultMatchingConfig(),
+		Timeout:       DefaultTimeoutConfig(),
+		Location:      DefaultLocationIngestConfig(),
+		Payments:      DefaultPaymentReconciliationConfig(),
+		Fatigue:       DefaultFatigueConfig(),
+		Onboarding:    DefaultOnboardingConfig(),
+		ServiceArea:   DefaultServiceAreaConfig(),
+		Lock:          DefaultLockConfig(),
+		Leader:        DefaultLeaderConfig(),
+		Storage:       DefaultStorageConfig(),
+		Invoice:       DefaultInvoiceConfig(),
+		Experiment:    DefaultExperimentConfig(),
+		ETA:           DefaultETAConfig(),
+		Accessibility: DefaultAccessibilityConfig(),
+		LocationTrail: DefaultLocationTrailConfig(),
+		Fraud:         DefaultFraudConfig(),
+		APIUsage:      DefaultAPIUsageConfig(),
+		Research:      DefaultResearchExportConfig(),
+		Archive:       DefaultMetricsArchiveConfig(),
+		Email:         DefaultEmailConfig(),
+		AppVersion:    DefaultAppVersionConfig(),
+		Integrity:     DefaultIntegrityCheckConfig(),
+		TripCache:     DefaultTripCacheConfig(),
+		HTTPClient:    DefaultHTTPClientConfig(),
 	}
 }
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// EnvFileKey names the environment variable holding the path of an env file that Load reads
+// settings from. The file is read again on every Load and its settings take precedence over the
+// process environment, so editing it and reloading applies the new values of the
+// runtime-changeable settings even when the process was started with other values.
+const EnvFileKey = "CONFIG_ENV_FILE"
+
+var (
+	// loadMu serializes loads, which share the values of the env file being read
+	loadMu sync.Mutex
+	// fileValues holds the settings of the env file while a configuration is loaded
+	fileValues map[string]string
+)
+
+// lookupEnv returns the value of a setting from the env file being loaded or, when the file does
+// not set it, from the process environment
+func lookupEnv(key string) string {
+	if value := fileValues[key]; value != "" {
+		return value
+	}
+	return os.Getenv(key)
+}
+
+// readEnvFile parses an env file of KEY=VALUE lines. Blank lines and lines starting with # are
+// skipped, an export prefix is allowed and values may be wrapped in single or double quotes.
+func readEnvFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open env file: %w", err)
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		text = strings.TrimPrefix(text, "export ")
+
+		key, value, ok := strings.Cut(text, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid env file %s: line %d is not KEY=VALUE", path, line)
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		values[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read env file: %w", err)
+	}
+	return values, nil
+}
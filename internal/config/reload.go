@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Change describes a single configuration value that differs between two configurations
+type Change struct {
+	Field    string      `json:"field"`
+	OldValue interface{} `json:"old_value"`
+	NewValue interface{} `json:"new_value"`
+	Applied  bool        `json:"applied"` // false when the setting requires a restart
+}
+
+// reloadableFields lists the settings that can be changed at runtime without a restart
+var reloadableFields = map[string]bool{
+	"Logging.Level":                 true,
+	"OpenTelemetry.SampleRate":      true,
+	"Observability.MetricsInterval": true,
+	"RateLimit.RequestsPerMinute":   true,
+	"RateLimit.Burst":               true,
+	"BodyLogging.Enabled":           true,
+	"BodyLogging.SampleRate":        true,
+	"BodyLogging.MaxBodySize":       true,
+	"BodyLogging.Routes":            true,
+	"AppVersion.MinVersions":        true,
+	"AppVersion.FeatureVersions":    true,
+}
+
+// IsReloadable reports whether the given field can be changed at runtime
+func IsReloadable(field string) bool {
+	return reloadableFields[field]
+}
+
+// Diff compares c against next and returns every field that differs.
+// Changes to reloadable fields are marked as applied.
+func (c *Config) Diff(next *Config) []Change {
+	var changes []Change
+	diffStruct("", reflect.ValueOf(*c), reflect.ValueOf(*next), &changes)
+	return changes
+}
+
+// ApplyReloadable copies the reloadable settings from next into c
+func (c *Config) ApplyReloadable(next *Config) {
+	c.Logging.Level = next.Logging.Level
+	c.OpenTelemetry.SampleRate = next.OpenTelemetry.SampleRate
+	c.Observability.MetricsInterval = next.Observability.MetricsInterval
+	c.RateLimit.RequestsPerMinute = next.RateLimit.RequestsPerMinute
+	c.RateLimit.Burst = next.RateLimit.Burst
+	c.BodyLogging = next.BodyLogging
+	c.AppVersion = next.AppVersion
+}
+
+// diffStruct walks two struct values field by field and records differences
+func diffStruct(prefix string, oldVal, newVal reflect.Value, changes *[]Change) {
+	for i := 0; i < oldVal.NumField(); i++ {
+		field := oldVal.Type().Field(i)
+		name := field.Name
+		if prefix != "" {
+			name = fmt.Sprintf("%s.%s", prefix, field.Name)
+		}
+
+		oldField := oldVal.Field(i)
+		newField := newVal.Field(i)
+
+		if oldField.Kind() == reflect.Struct {
+			diffStruct(name, oldField, newField, changes)
+			continue
+		}
+
+		if !reflect.DeepEqual(oldField.Interface(), newField.Interface()) {
+			change := Change{
+				Field:    name,
+				OldValue: oldField.Interface(),
+				NewValue: newField.Interface(),
+				Applied:  IsReloadable(name),
+			}
+			// Never expose credentials in change reports
+			if isSecretField(field.Name) {
+				change.OldValue = redactedValue
+				change.NewValue = redactedValue
+			}
+			*changes = append(*changes, change)
+		}
+	}
+}
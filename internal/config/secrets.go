@@ -0,0 +1,248 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// redactedValue replaces secret values in config dumps and logs
+const redactedValue = "[REDACTED]"
+
+// SecretProvider resolves secret values such as credentials by key (e.g. DB_PASSWORD)
+type SecretProvider interface {
+	// Name returns the provider name used in logs and errors
+	Name() string
+	// GetSecret returns the secret value and whether it was found
+	GetSecret(key string) (string, bool, error)
+}
+
+// secretKeys lists the configuration values that may be supplied by a secret provider
+var secretKeys = []string{"DB_USER", "DB_PASSWORD", "REDIS_PASSWORD", "REDACTION_HASH_KEY", "OPERATOR_API_KEYS", "STORAGE_SIGNING_KEY", "STORAGE_S3_SECRET_KEY", "SMTP_PASSWORD", "RESEARCH_EXPORT_HASH_KEY"}
+
+// NewSecretProvider creates the secret provider selected by the secrets configuration
+func NewSecretProvider(cfg *SecretsConfig) (SecretProvider, error) {
+	switch cfg.Provider {
+	case "", "env":
+		return &EnvSecretProvider{}, nil
+	case "file":
+		return &FileSecretProvider{Dir: cfg.Dir}, nil
+	case "vault":
+		if cfg.VaultAddr == "" || cfg.VaultPath == "" {
+			return nil, fmt.Errorf("vault address and secret path are required for the vault secret provider")
+		}
+		return &VaultSecretProvider{
+			Address: strings.TrimRight(cfg.VaultAddr, "/"),
+			Token:   cfg.VaultToken,
+			Path:    strings.Trim(cfg.VaultPath, "/"),
+			Client:  &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported secrets provider: %s", cfg.Provider)
+	}
+}
+
+// EnvSecretProvider reads secrets from environment variables.
+// A variable named <KEY>_FILE pointing at a file takes precedence over <KEY>.
+type EnvSecretProvider struct{}
+
+// Name returns the provider name
+func (p *EnvSecretProvider) Name() string {
+	return "env"
+}
+
+// GetSecret returns the secret from <KEY>_FILE or <KEY>
+func (p *EnvSecretProvider) GetSecret(key string) (string, bool, error) {
+	if path := os.Getenv(key + "_FILE"); path != "" {
+		value, err := readSecretFile(path)
+		if err != nil {
+			return "", false, err
+		}
+		return value, true, nil
+	}
+	if value := os.Getenv(key); value != "" {
+		return value, true, nil
+	}
+	return "", false, nil
+}
+
+// FileSecretProvider reads secrets from a directory of files, one secret per file,
+// as mounted by Docker and Kubernetes secrets (e.g. /run/secrets/db_password)
+type FileSecretProvider struct {
+	Dir string
+}
+
+// Name returns the provider name
+func (p *FileSecretProvider) Name() string {
+	return "file"
+}
+
+// GetSecret returns the contents of the file named after the lowercased key
+func (p *FileSecretProvider) GetSecret(key string) (string, bool, error) {
+	path := filepath.Join(p.Dir, strings.ToLower(key))
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", false, nil
+	}
+	value, err := readSecretFile(path)
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// VaultSecretProvider reads secrets from a HashiCorp Vault KV version 2 secret.
+// Path is the full API path of the secret, e.g. "secret/data/actor-observability".
+type VaultSecretProvider struct {
+	Address string
+	Token   string
+	Path    string
+	Client  *http.Client
+
+	data map[string]string
+}
+
+// Name returns the provider name
+func (p *VaultSecretProvider) Name() string {
+	return "vault"
+}
+
+// GetSecret returns the value stored under key in the Vault secret
+func (p *VaultSecretProvider) GetSecret(key string) (string, bool, error) {
+	if p.data == nil {
+		if err := p.fetch(); err != nil {
+			return "", false, err
+		}
+	}
+	value, ok := p.data[key]
+	return value, ok, nil
+}
+
+// fetch reads the secret data from Vault once and caches it
+func (p *VaultSecretProvider) fetch() error {
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/v1/%s", p.Address, p.Path), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to read secret from vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("vault returned status %d for %s", resp.StatusCode, p.Path)
+	}
+
+	var payload struct {
+		Data struct {
+			Data map[string]string `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return fmt.Errorf("failed to decode vault response: %w", err)
+	}
+
+	p.data = payload.Data.Data
+	if p.data == nil {
+		p.data = make(map[string]string)
+	}
+	return nil
+}
+
+// readSecretFile reads a secret file and trims the trailing newline
+func readSecretFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret file %s: %w", path, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
+
+// applySecrets overrides credential settings with values from the secret provider
+func (c *Config) applySecrets(provider SecretProvider) error {
+	for _, key := range secretKeys {
+		value, found, err := provider.GetSecret(key)
+		if err != nil {
+			return fmt.Errorf("%s secret provider: %w", provider.Name(), err)
+		}
+		if !found {
+			continue
+		}
+
+		switch key {
+		case "DB_USER":
+			c.Database.User = value
+		case "DB_PASSWORD":
+			c.Database.Password = value
+		case "REDIS_PASSWORD":
+			c.Redis.Password = value
+		case "REDACTION_HASH_KEY":
+			c.Redaction.HashKey = value
+		case "OPERATOR_API_KEYS":
+			c.Redaction.OperatorKeys = nil
+			for _, key := range strings.Split(value, ",") {
+				if key = strings.TrimSpace(key); key != "" {
+					c.Redaction.OperatorKeys = append(c.Redaction.OperatorKeys, key)
+				}
+			}
+		case "STORAGE_SIGNING_KEY":
+			c.Storage.SigningKey = value
+		case "STORAGE_S3_SECRET_KEY":
+			c.Storage.S3SecretKey = value
+		case "SMTP_PASSWORD":
+			c.Email.Password = value
+		case "RESEARCH_EXPORT_HASH_KEY":
+			c.Research.HashKey = value
+		}
+	}
+	return nil
+}
+
+// Redacted returns a copy of the configuration with all secret values masked,
+// suitable for logging or returning from diagnostic endpoints
+func (c *Config) Redacted() Config {
+	redacted := *c
+	if redacted.Database.Password != "" {
+		redacted.Database.Password = redactedValue
+	}
+	if redacted.Database.ReplicaDSN != "" {
+		redacted.Database.ReplicaDSN = redactedValue
+	}
+	if redacted.Redis.Password != "" {
+		redacted.Redis.Password = redactedValue
+	}
+	if redacted.Secrets.VaultToken != "" {
+		redacted.Secrets.VaultToken = redactedValue
+	}
+	if redacted.Redaction.HashKey != "" {
+		redacted.Redaction.HashKey = redactedValue
+	}
+	if len(redacted.Redaction.OperatorKeys) > 0 {
+		redacted.Redaction.OperatorKeys = []string{redactedValue}
+	}
+	if redacted.Storage.SigningKey != "" {
+		redacted.Storage.SigningKey = redactedValue
+	}
+	if redacted.Storage.S3SecretKey != "" {
+		redacted.Storage.S3SecretKey = redactedValue
+	}
+	if redacted.Email.Password != "" {
+		redacted.Email.Password = redactedValue
+	}
+	if redacted.Research.HashKey != "" {
+		redacted.Research.HashKey = redactedValue
+	}
+	return redacted
+}
+
+// isSecretField reports whether a config field holds a secret value
+func isSecretField(name string) bool {
+	return name == "Password" || name == "VaultToken" || name == "ReplicaDSN" || name == "HashKey" || name == "OperatorKeys" ||
+		name == "SigningKey" || name == "S3SecretKey"
+}
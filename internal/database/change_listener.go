@@ -0,0 +1,249 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// RowChangeChannel is the channel the row change triggers notify, see migration 038
+	RowChangeChannel = "row_changes"
+	// changeListenerPingInterval is how often an idle listener connection is checked, so that a
+	// connection dropped without an error is noticed and reconnected
+	changeListenerPingInterval = 90 * time.Second
+	// rowChangeHandlerTimeout bounds each handler call
+	rowChangeHandlerTimeout = 10 * time.Second
+)
+
+// Row change operations
+const (
+	RowInserted = "INSERT"
+	RowUpdated  = "UPDATE"
+	RowDeleted  = "DELETE"
+)
+
+// RowChange is a change to a row of a table whose changes are notified
+type RowChange struct {
+	Table     string `json:"table"`
+	Operation string `json:"op"`
+	ID        string `json:"id"`
+}
+
+// RowChangeHandler handles the changes to the rows of a table
+type RowChangeHandler func(ctx context.Context, change RowChange)
+
+// ChangeListenerStats is the state of a change listener
+type ChangeListenerStats struct {
+	Connected  bool       `json:"connected"`
+	Received   int64      `json:"received"`
+	Invalid    int64      `json:"invalid"` // notifications whose payload is not a row change
+	Reconnects int64      `json:"reconnects"`
+	LastError  string     `json:"last_error,omitempty"`
+	LastSyncAt *time.Time `json:"last_sync_at,omitempty"`
+}
+
+// ChangeListener listens for the row changes notified by the triggers on the drivers and trips
+// tables and hands them to the handlers of their table. Changes made outside the API, by manual
+// SQL fixes or other services, reach every instance this way. The listener has a connection of
+// its own, reconnected with exponential backoff when lost; changes notified while it was down
+// are lost, so the resync handlers are called once it is back, to reload what they depend on.
+type ChangeListener struct {
+	dsn          string
+	minReconnect time.Duration
+	maxReconnect time.Duration
+	logger       *logging.Logger
+
+	mu       sync.Mutex
+	handlers map[string][]RowChangeHandler // by table
+	onResync []func(ctx context.Context)
+	stats    ChangeListenerStats
+
+	listener *pq.Listener
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewChangeListener creates a listener on the configured PostgreSQL database without handlers
+func NewChangeListener(cfg *config.DatabaseConfig, logger *logging.Logger) *ChangeListener {
+	return &ChangeListener{
+		dsn:          PostgresDSN(cfg),
+		minReconnect: cfg.ChangeListenerMinReconnect,
+		maxReconnect: cfg.ChangeListenerMaxReconnect,
+		logger:       logger.WithComponent("change_listener"),
+		handlers:     make(map[string][]RowChangeHandler),
+	}
+}
+
+// Handle registers handler for the changes to the rows of table. Handlers are called one at a
+// time, in the order the changes were committed, and should return quickly.
+func (l *ChangeListener) Handle(table string, handler RowChangeHandler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handlers[table] = append(l.handlers[table], handler)
+}
+
+// OnResync registers handler to be called after the listener reconnected, as changes may have
+// been missed while it was disconnected
+func (l *ChangeListener) OnResync(handler func(ctx context.Context)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.onResync = append(l.onResync, handler)
+}
+
+// Stats returns the state of the listener
+func (l *ChangeListener) Stats() ChangeListenerStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// Start listens for row changes in the background until Stop is called. A database unreachable
+// at startup is retried like a lost connection.
+func (l *ChangeListener) Start(ctx context.Context) error {
+	l.ctx, l.cancel = context.WithCancel(ctx)
+	l.listener = pq.NewListener(l.dsn, l.minReconnect, l.maxReconnect, l.handleEvent)
+
+	l.wg.Add(1)
+	go l.run()
+	return nil
+}
+
+// Stop stops listening and closes the listener connection
+func (l *ChangeListener) Stop() error {
+	if l.cancel == nil {
+		return nil
+	}
+	l.cancel()
+	err := l.listener.Close()
+	l.wg.Wait()
+	return err
+}
+
+// run listens on the row change channel, waiting for the first connection, and dispatches the
+// notifications received until the listener is stopped
+func (l *ChangeListener) run() {
+	defer l.wg.Done()
+
+	if err := l.listener.Listen(RowChangeChannel); err != nil {
+		if l.ctx.Err() == nil {
+			l.logger.WithError(err).Error("Failed to listen for row changes")
+		}
+		return
+	}
+	l.logger.WithField("channel", RowChangeChannel).Info("Listening for row changes")
+
+	ticker := time.NewTicker(changeListenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.ctx.Done():
+			return
+		case notification, ok := <-l.listener.Notify:
+			if !ok {
+				return
+			}
+			l.HandleNotification(notification)
+		case <-ticker.C:
+			// A failed ping closes the connection, which the listener then reconnects
+			if err := l.listener.Ping(); err != nil {
+				l.logger.WithError(err).Warn("Change listener connection check failed")
+			}
+		}
+	}
+}
+
+// HandleNotification dispatches a notification received on the row change channel to the
+// handlers of its table. A nil notification, sent by the listener once it reconnected, calls the
+// resync handlers instead.
+func (l *ChangeListener) HandleNotification(notification *pq.Notification) {
+	if notification == nil {
+		l.resync()
+		return
+	}
+
+	var change RowChange
+	if err := json.Unmarshal([]byte(notification.Extra), &change); err != nil || change.Table == "" || change.ID == "" {
+		l.mu.Lock()
+		l.stats.Invalid++
+		l.mu.Unlock()
+		l.logger.WithField("payload", notification.Extra).Warn("Ignoring invalid row change notification")
+		return
+	}
+
+	l.mu.Lock()
+	l.stats.Received++
+	handlers := l.handlers[change.Table]
+	l.mu.Unlock()
+
+	for _, handler := range handlers {
+		l.call(func(ctx context.Context) { handler(ctx, change) })
+	}
+}
+
+// resync calls the resync handlers
+func (l *ChangeListener) resync() {
+	l.mu.Lock()
+	now := time.Now()
+	l.stats.LastSyncAt = &now
+	handlers := l.onResync
+	l.mu.Unlock()
+
+	l.logger.Info("Resyncing, row changes may have been missed while disconnected")
+	for _, handler := range handlers {
+		l.call(handler)
+	}
+}
+
+// call runs a handler with a bounded context, recovering from panics so that one faulty handler
+// does not stop the listener
+func (l *ChangeListener) call(handler func(ctx context.Context)) {
+	parent := l.ctx
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, rowChangeHandlerTimeout)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			l.logger.WithField("panic", fmt.Sprint(r)).Error("Row change handler panicked")
+		}
+	}()
+	handler(ctx)
+}
+
+// handleEvent tracks the state of the listener connection
+func (l *ChangeListener) handleEvent(event pq.ListenerEventType, err error) {
+	l.mu.Lock()
+	switch event {
+	case pq.ListenerEventConnected:
+		l.stats.Connected = true
+	case pq.ListenerEventReconnected:
+		l.stats.Connected = true
+		l.stats.Reconnects++
+	case pq.ListenerEventDisconnected, pq.ListenerEventConnectionAttemptFailed:
+		l.stats.Connected = false
+	}
+	if err != nil {
+		l.stats.LastError = err.Error()
+	}
+	l.mu.Unlock()
+
+	switch event {
+	case pq.ListenerEventDisconnected:
+		l.logger.WithError(err).Warn("Change listener disconnected, reconnecting")
+	case pq.ListenerEventConnectionAttemptFailed:
+		l.logger.WithError(err).Warn("Change listener failed to connect, retrying")
+	case pq.ListenerEventReconnected:
+		l.logger.Info("Change listener reconnected")
+	}
+}
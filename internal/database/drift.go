@@ -0,0 +1,220 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rubenv/sql-migrate"
+)
+
+// Index statements of the migrations, tracked to know which indexes should exist
+var (
+	createIndexStatement = regexp.MustCompile(`(?is)^CREATE\s+(UNIQUE\s+)?INDEX\s+(CONCURRENTLY\s+)?(IF\s+NOT\s+EXISTS\s+)?"?(\w+)"?\s+ON\s+(ONLY\s+)?"?(\w+)"?`)
+	dropIndexStatement   = regexp.MustCompile(`(?is)^DROP\s+INDEX\s+(CONCURRENTLY\s+)?(IF\s+EXISTS\s+)?(.+?)(\s+CASCADE|\s+RESTRICT)?\s*;?$`)
+	dropTableStatement   = regexp.MustCompile(`(?is)^DROP\s+TABLE\s+(IF\s+EXISTS\s+)?(.+?)(\s+CASCADE|\s+RESTRICT)?\s*;?$`)
+	renameTableStatement = regexp.MustCompile(`(?is)^ALTER\s+TABLE\s+(IF\s+EXISTS\s+)?"?(\w+)"?\s+RENAME\s+TO\s+"?(\w+)"?`)
+	renameIndexStatement = regexp.MustCompile(`(?is)^ALTER\s+INDEX\s+(IF\s+EXISTS\s+)?"?(\w+)"?\s+RENAME\s+TO\s+"?(\w+)"?`)
+)
+
+// IndexDefinition is an index created by the migrations
+type IndexDefinition struct {
+	Name  string `json:"name"`
+	Table string `json:"table"`
+}
+
+// ExpectedIndexes returns the indexes left by applying the up statements of the migrations in
+// order: those created and not dropped since, by name or with their table
+func ExpectedIndexes(statements []string) []IndexDefinition {
+	var indexes []IndexDefinition
+	remove := func(keep func(IndexDefinition) bool) {
+		kept := indexes[:0]
+		for _, index := range indexes {
+			if keep(index) {
+				kept = append(kept, index)
+			}
+		}
+		indexes = kept
+	}
+
+	for _, query := range statements {
+		statement := strings.TrimSpace(sqlLineComment.ReplaceAllString(query, ""))
+		if m := createIndexStatement.FindStringSubmatch(statement); m != nil {
+			name := m[4]
+			remove(func(index IndexDefinition) bool { return index.Name != name })
+			indexes = append(indexes, IndexDefinition{Name: name, Table: m[6]})
+		} else if m := dropIndexStatement.FindStringSubmatch(statement); m != nil {
+			names := identifierList(m[3])
+			remove(func(index IndexDefinition) bool { return !names[index.Name] })
+		} else if m := dropTableStatement.FindStringSubmatch(statement); m != nil {
+			tables := identifierList(m[2])
+			remove(func(index IndexDefinition) bool { return !tables[index.Table] })
+		} else if m := renameTableStatement.FindStringSubmatch(statement); m != nil {
+			for i := range indexes {
+				if indexes[i].Table == m[2] {
+					indexes[i].Table = m[3]
+				}
+			}
+		} else if m := renameIndexStatement.FindStringSubmatch(statement); m != nil {
+			for i := range indexes {
+				if indexes[i].Name == m[2] {
+					indexes[i].Name = m[3]
+				}
+			}
+		}
+	}
+	return indexes
+}
+
+// identifierList returns the names of a comma-separated list of identifiers
+func identifierList(list string) map[string]bool {
+	names := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		names[strings.Trim(strings.TrimSpace(name), `"`)] = true
+	}
+	return names
+}
+
+// MissingIndexes returns the expected indexes missing from the current schema
+func MissingIndexes(ctx context.Context, db *sqlx.DB, expected []IndexDefinition) ([]SchemaMismatch, error) {
+	var names []string
+	if err := db.SelectContext(ctx, &names, `
+		SELECT indexname
+		FROM pg_indexes
+		WHERE schemaname = current_schema()`); err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %w", err)
+	}
+
+	existing := make(map[string]bool, len(names))
+	for _, name := range names {
+		existing[name] = true
+	}
+
+	var mismatches []SchemaMismatch
+	for _, index := range expected {
+		if !existing[index.Name] {
+			mismatches = append(mismatches, SchemaMismatch{Table: index.Table, Index: index.Name})
+		}
+	}
+	return mismatches, nil
+}
+
+// SchemaDriftReport is the result of comparing the live schema with the models and migrations
+type SchemaDriftReport struct {
+	CheckedAt      time.Time        `json:"checked_at"`
+	DurationMs     int64            `json:"duration_ms"`
+	Tables         int              `json:"tables"`                    // models checked
+	Indexes        int              `json:"indexes"`                   // indexes expected from the migrations
+	IndexesSkipped string           `json:"indexes_skipped,omitempty"` // why indexes were not checked
+	Drifted        bool             `json:"drifted"`
+	Mismatches     []SchemaMismatch `json:"mismatches"`
+}
+
+// SchemaDriftChecker detects drift between the live schema and the columns mapped by the models'
+// db tags and the indexes created by the migrations. Drift is reported as warnings, as a
+// mismatch usually breaks only the queries touching it.
+type SchemaDriftChecker struct {
+	db            *sqlx.DB
+	tables        []models.Table
+	migrationsDir string
+	logger        *logging.Logger
+
+	mu   sync.Mutex
+	last *SchemaDriftReport
+}
+
+// NewSchemaDriftChecker creates a checker of the schema against the models' tables and the
+// migrations in the directory. Indexes are not checked when the directory is empty or missing.
+func NewSchemaDriftChecker(db *sqlx.DB, tables []models.Table, migrationsDir string, logger *logging.Logger) *SchemaDriftChecker {
+	return &SchemaDriftChecker{
+		db:            db,
+		tables:        tables,
+		migrationsDir: migrationsDir,
+		logger:        logger.WithComponent("schema_drift"),
+	}
+}
+
+// Check compares the live schema with the models and migrations, logging each mismatch
+func (c *SchemaDriftChecker) Check(ctx context.Context) (*SchemaDriftReport, error) {
+	start := time.Now()
+	report := &SchemaDriftReport{
+		CheckedAt:  start,
+		Tables:     len(c.tables),
+		Mismatches: []SchemaMismatch{},
+	}
+
+	mismatches, err := ValidateSchema(ctx, c.db, c.tables)
+	if err != nil {
+		return nil, err
+	}
+	report.Mismatches = append(report.Mismatches, mismatches...)
+
+	expected, err := c.expectedIndexes()
+	if err != nil {
+		report.IndexesSkipped = err.Error()
+		c.logger.WithError(err).Warn("Skipping index drift check")
+	} else {
+		report.Indexes = len(expected)
+		mismatches, err := MissingIndexes(ctx, c.db, expected)
+		if err != nil {
+			return nil, err
+		}
+		report.Mismatches = append(report.Mismatches, mismatches...)
+	}
+
+	report.Drifted = len(report.Mismatches) > 0
+	report.DurationMs = time.Since(start).Milliseconds()
+
+	for _, mismatch := range report.Mismatches {
+		c.logger.WithFields(logging.Fields{
+			"table":  mismatch.Table,
+			"column": mismatch.Column,
+			"index":  mismatch.Index,
+		}).Warn("Schema drift: " + mismatch.String())
+	}
+	c.logger.WithFields(logging.Fields{
+		"tables":     report.Tables,
+		"indexes":    report.Indexes,
+		"mismatches": len(report.Mismatches),
+	}).Info("Schema drift check completed")
+
+	c.mu.Lock()
+	c.last = report
+	c.mu.Unlock()
+	return report, nil
+}
+
+// Last returns the report of the last check, nil before the first
+func (c *SchemaDriftChecker) Last() *SchemaDriftReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.last
+}
+
+// expectedIndexes reads the indexes the migrations create
+func (c *SchemaDriftChecker) expectedIndexes() ([]IndexDefinition, error) {
+	if c.migrationsDir == "" {
+		return nil, fmt.Errorf("no migrations directory configured")
+	}
+
+	migrations, err := migrate.FileMigrationSource{Dir: c.migrationsDir}.FindMigrations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+	if len(migrations) == 0 {
+		return nil, fmt.Errorf("no migrations found in %s", c.migrationsDir)
+	}
+
+	var statements []string
+	for _, migration := range migrations {
+		statements = append(statements, migration.Up...)
+	}
+	return ExpectedIndexes(statements), nil
+}
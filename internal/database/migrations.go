@@ -0,0 +1,129 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"actor-model-observability/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// destructiveStatement matches statements that drop stored data: dropping a table, schema or
+// column, or truncating or deleting rows. Dropping indexes, constraints, views and functions is
+// not destructive, as re-applying the migration restores them.
+var destructiveStatement = regexp.MustCompile(`(?is)\b(DROP\s+(TABLE|SCHEMA|DATABASE|COLUMN)\b|ALTER\s+TABLE\s+.*\bDROP\s+(IF\s+EXISTS\s+)?"?\w+"?\s*(,|;|$)|TRUNCATE\b|DELETE\s+FROM\b)`)
+
+// sqlLineComment matches a -- comment up to the end of its line
+var sqlLineComment = regexp.MustCompile(`--[^\n]*`)
+
+// DestructiveStatements returns the statements of a migration that drop stored data
+func DestructiveStatements(queries []string) []string {
+	var destructive []string
+	for _, query := range queries {
+		statement := strings.TrimSpace(sqlLineComment.ReplaceAllString(query, ""))
+		if destructiveStatement.MatchString(statement) {
+			destructive = append(destructive, statement)
+		}
+	}
+	return destructive
+}
+
+// SchemaMismatch is a table or column mapped by a model, or an index created by the migrations,
+// but missing from the database
+type SchemaMismatch struct {
+	Table  string `json:"table"`
+	Column string `json:"column,omitempty"` // empty when the whole table is missing
+	Index  string `json:"index,omitempty"`
+}
+
+func (m SchemaMismatch) String() string {
+	switch {
+	case m.Index != "":
+		return fmt.Sprintf("index %s on %s is missing", m.Index, m.Table)
+	case m.Column != "":
+		return fmt.Sprintf("column %s.%s is missing", m.Table, m.Column)
+	default:
+		return fmt.Sprintf("table %s is missing", m.Table)
+	}
+}
+
+// ValidateSchema compares the tables of the current schema with the columns mapped by the db tags
+// of the models, returning the tables and columns missing from the database. Columns of the
+// database not mapped by a model are not reported.
+func ValidateSchema(ctx context.Context, db *sqlx.DB, tables []models.Table) ([]SchemaMismatch, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT table_name, column_name
+		FROM information_schema.columns
+		WHERE table_schema = current_schema()`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+	defer rows.Close()
+
+	existing := make(map[string]map[string]bool)
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return nil, fmt.Errorf("failed to scan schema column: %w", err)
+		}
+		if existing[table] == nil {
+			existing[table] = make(map[string]bool)
+		}
+		existing[table][column] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read schema: %w", err)
+	}
+
+	var mismatches []SchemaMismatch
+	for _, table := range tables {
+		name := table.TableName()
+		columns, ok := existing[name]
+		if !ok {
+			mismatches = append(mismatches, SchemaMismatch{Table: name})
+			continue
+		}
+		for _, column := range MappedColumns(table) {
+			if !columns[column] {
+				mismatches = append(mismatches, SchemaMismatch{Table: name, Column: column})
+			}
+		}
+	}
+	return mismatches, nil
+}
+
+// MappedColumns returns the sorted columns mapped by the db tags of a model, including those of
+// embedded structs
+func MappedColumns(model interface{}) []string {
+	var columns []string
+	collectColumns(reflect.TypeOf(model), &columns)
+	sort.Strings(columns)
+	return columns
+}
+
+func collectColumns(t reflect.Type, columns *[]string) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("db")
+		if field.Anonymous && tag == "" {
+			collectColumns(field.Type, columns)
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		*columns = append(*columns, name)
+	}
+}
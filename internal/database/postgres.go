@@ -21,9 +21,22 @@ type PostgresDB struct {
 	logger *logging.Logger
 }
 
-// NewPostgresConnection creates a new PostgreSQL database connection
-func NewPostgresConnection(cfg *config.DatabaseConfig, logger *logging.Logger) (*PostgresDB, error) {
-	dsn := fmt.Sprintf(
+// NewConnection creates a database connection for the configured driver.
+// PostgreSQL is the production backend; SQLite is intended for local development and demos.
+func NewConnection(cfg *config.DatabaseConfig, logger *logging.Logger) (*PostgresDB, error) {
+	switch cfg.Driver {
+	case "", "postgres":
+		return NewPostgresConnection(cfg, logger)
+	case "sqlite":
+		return NewSQLiteConnection(cfg, logger)
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", cfg.Driver)
+	}
+}
+
+// PostgresDSN returns the connection string of the configured PostgreSQL database
+func PostgresDSN(cfg *config.DatabaseConfig) string {
+	return fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s sslmode=%s",
 		cfg.Host,
 		cfg.Port,
@@ -32,8 +45,11 @@ func NewPostgresConnection(cfg *config.DatabaseConfig, logger *logging.Logger) (
 		cfg.DBName,
 		cfg.SSLMode,
 	)
+}
 
-	db, err := sqlx.Open("postgres", dsn)
+// NewPostgresConnection creates a new PostgreSQL database connection
+func NewPostgresConnection(cfg *config.DatabaseConfig, logger *logging.Logger) (*PostgresDB, error) {
+	db, err := sqlx.Open("postgres", PostgresDSN(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
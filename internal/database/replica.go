@@ -0,0 +1,178 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// replicaLagQuery measures how far the replica's replay is behind the primary, in seconds. A
+// replica that replayed everything it received is not lagging, however long the primary was idle.
+const replicaLagQuery = `
+	SELECT CASE
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END
+`
+
+// ReplicaRouter routes read queries to a read replica and falls back to the
+// primary while the replica is unhealthy or lagging
+type ReplicaRouter struct {
+	primary       *sqlx.DB
+	replica       *sqlx.DB
+	healthy       atomic.Bool
+	checkInterval time.Duration
+	maxLag        time.Duration
+	logger        *logging.Logger
+	ctx           context.Context
+	cancel        context.CancelFunc
+	wg            sync.WaitGroup
+}
+
+// NewReplicaRouter connects to the read replica described by cfg.ReplicaDSN.
+// If the replica cannot be reached at startup, reads go to the primary until it recovers.
+func NewReplicaRouter(primary *sqlx.DB, cfg *config.DatabaseConfig, logger *logging.Logger) *ReplicaRouter {
+	if cfg.ReplicaDSN == "" {
+		return NewReplicaRouterWithReplica(primary, nil, cfg, logger)
+	}
+
+	replica, err := sqlx.Open("postgres", cfg.ReplicaDSN)
+	if err != nil {
+		logger.WithComponent("database_replica").WithError(err).Error("Failed to open read replica connection, using primary for reads")
+		return NewReplicaRouterWithReplica(primary, nil, cfg, logger)
+	}
+
+	replica.SetMaxOpenConns(cfg.MaxOpenConns)
+	replica.SetMaxIdleConns(cfg.MaxIdleConns)
+	replica.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	replica.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	return NewReplicaRouterWithReplica(primary, replica, cfg, logger)
+}
+
+// NewReplicaRouterWithReplica routes reads to an already opened replica, which may be nil to
+// read from the primary. The replica's health is checked before it returns.
+func NewReplicaRouterWithReplica(primary, replica *sqlx.DB, cfg *config.DatabaseConfig, logger *logging.Logger) *ReplicaRouter {
+	router := &ReplicaRouter{
+		primary:       primary,
+		replica:       replica,
+		checkInterval: cfg.ReplicaHealthCheckInterval,
+		maxLag:        cfg.ReplicaMaxLag,
+		logger:        logger.WithComponent("database_replica"),
+	}
+	if replica != nil {
+		router.CheckHealth()
+	}
+	return router
+}
+
+// Reader returns the connection to use for read-only queries
+func (r *ReplicaRouter) Reader() *sqlx.DB {
+	if r.replica != nil && r.healthy.Load() {
+		return r.replica
+	}
+	return r.primary
+}
+
+// Writer returns the primary connection
+func (r *ReplicaRouter) Writer() *sqlx.DB {
+	return r.primary
+}
+
+// IsReplicaHealthy reports whether reads are currently served by the replica
+func (r *ReplicaRouter) IsReplicaHealthy() bool {
+	return r.replica != nil && r.healthy.Load()
+}
+
+// Start begins periodic health checks of the replica
+func (r *ReplicaRouter) Start(ctx context.Context) {
+	if r.replica == nil {
+		return
+	}
+
+	r.ctx, r.cancel = context.WithCancel(ctx)
+	r.wg.Add(1)
+	go r.healthCheckLoop()
+}
+
+// Stop stops health checks and closes the replica connection
+func (r *ReplicaRouter) Stop() error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+
+	if r.replica != nil {
+		return r.replica.Close()
+	}
+	return nil
+}
+
+// healthCheckLoop periodically pings the replica
+func (r *ReplicaRouter) healthCheckLoop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.CheckHealth()
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// CheckHealth pings the replica and measures its replication lag, routing reads to the primary
+// while the replica is unreachable or lags more than the configured maximum. It runs every health
+// check interval once started; transitions are logged.
+func (r *ReplicaRouter) CheckHealth() {
+	if r.replica == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := r.replica.PingContext(ctx)
+	var lag time.Duration
+	if err == nil && r.maxLag > 0 {
+		lag, err = r.replicationLag(ctx)
+	}
+	healthy := err == nil
+	previous := r.healthy.Swap(healthy)
+
+	if healthy && !previous {
+		r.logger.Info("Read replica healthy, routing reads to replica")
+	} else if !healthy && previous {
+		r.logger.WithError(err).Warn("Read replica unhealthy, falling back to primary for reads")
+	} else if !healthy {
+		r.logger.WithError(err).Debug("Read replica still unavailable")
+	}
+	if healthy && lag > 0 {
+		r.logger.WithField("lag", lag.String()).Debug("Read replica lag within limit")
+	}
+}
+
+// replicationLag returns how far the replica is behind the primary, failing once that is more
+// than the maximum lag
+func (r *ReplicaRouter) replicationLag(ctx context.Context) (time.Duration, error) {
+	var seconds float64
+	if err := r.replica.GetContext(ctx, &seconds, replicaLagQuery); err != nil {
+		return 0, fmt.Errorf("failed to measure replica lag: %w", err)
+	}
+	lag := time.Duration(seconds * float64(time.Second))
+	if lag > r.maxLag {
+		return lag, fmt.Errorf("replica lags %s behind the primary, more than %s", lag.Round(time.Millisecond), r.maxLag)
+	}
+	return lag, nil
+}
@@ -0,0 +1,130 @@
+//go:build sqlite
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	_ "embed"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
+)
+
+// sqliteDriverName is the database/sql driver registered with the math functions the repositories need
+const sqliteDriverName = "sqlite3_observability"
+
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+func init() {
+	sql.Register(sqliteDriverName, &sqliteDriver{&sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			// Functions used by the driver proximity queries (Haversine distance) and the trip
+			// heatmap grid
+			functions := map[string]interface{}{
+				"radians": func(deg float64) float64 { return deg * math.Pi / 180 },
+				"acos":    math.Acos,
+				"cos":     math.Cos,
+				"sin":     math.Sin,
+				"floor":   math.Floor,
+			}
+			for name, fn := range functions {
+				if err := conn.RegisterFunc(name, fn, true); err != nil {
+					return fmt.Errorf("failed to register sqlite function %s: %w", name, err)
+				}
+			}
+			return nil
+		},
+	}})
+}
+
+// sqliteDriver opens SQLite connections running the repositories' PostgreSQL queries with their
+// placeholders rewritten
+type sqliteDriver struct {
+	*sqlite3.SQLiteDriver
+}
+
+func (d *sqliteDriver) Open(dsn string) (driver.Conn, error) {
+	conn, err := d.SQLiteDriver.Open(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return &sqliteConn{conn.(*sqlite3.SQLiteConn)}, nil
+}
+
+// sqliteConn rewrites the placeholders of every query it runs with SQLitePlaceholders
+type sqliteConn struct {
+	*sqlite3.SQLiteConn
+}
+
+func (c *sqliteConn) Prepare(query string) (driver.Stmt, error) {
+	return c.SQLiteConn.Prepare(SQLitePlaceholders(query))
+}
+
+func (c *sqliteConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	return c.SQLiteConn.PrepareContext(ctx, SQLitePlaceholders(query))
+}
+
+func (c *sqliteConn) Exec(query string, args []driver.Value) (driver.Result, error) {
+	return c.SQLiteConn.Exec(SQLitePlaceholders(query), args)
+}
+
+func (c *sqliteConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return c.SQLiteConn.ExecContext(ctx, SQLitePlaceholders(query), args)
+}
+
+func (c *sqliteConn) Query(query string, args []driver.Value) (driver.Rows, error) {
+	return c.SQLiteConn.Query(SQLitePlaceholders(query), args)
+}
+
+func (c *sqliteConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return c.SQLiteConn.QueryContext(ctx, SQLitePlaceholders(query), args)
+}
+
+// NewSQLiteConnection opens (creating if needed) a SQLite database file and applies
+// the development schema. The returned wrapper is used exactly like a PostgreSQL connection.
+func NewSQLiteConnection(cfg *config.DatabaseConfig, logger *logging.Logger) (*PostgresDB, error) {
+	if dir := filepath.Dir(cfg.SQLitePath); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, fmt.Errorf("failed to create sqlite directory: %w", err)
+		}
+	}
+
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000&_journal_mode=WAL", cfg.SQLitePath)
+	db, err := sqlx.Open(sqliteDriverName, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+
+	// SQLite allows a single writer; one connection avoids "database is locked" errors
+	db.SetMaxOpenConns(1)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if _, err := db.ExecContext(ctx, sqliteSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to apply sqlite schema: %w", err)
+	}
+
+	sqliteDB := &PostgresDB{
+		DB:     db,
+		config: cfg,
+		logger: logger,
+	}
+
+	logger.WithComponent("database").WithField("path", cfg.SQLitePath).Info("SQLite connection established")
+
+	return sqliteDB, nil
+}
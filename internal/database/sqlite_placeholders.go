@@ -0,0 +1,43 @@
+package database
+
+import "strings"
+
+// SQLitePlaceholders rewrites the PostgreSQL $N placeholders of a query into SQLite's ?N. SQLite
+// binds $N as a named parameter numbered in order of first appearance, so a query mentioning $2
+// before $1 would bind its arguments swapped; ?N is bound to the Nth argument wherever it appears.
+// Placeholders inside string literals, quoted identifiers and comments are left alone.
+func SQLitePlaceholders(query string) string {
+	if !strings.Contains(query, "$") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query))
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"':
+			// Copy up to the closing quote; doubled quotes escape and reopen it
+			end := strings.IndexByte(query[i+1:], c)
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+2])
+			i += end + 1
+		case c == '-' && i+1 < len(query) && query[i+1] == '-':
+			end := strings.IndexByte(query[i:], '\n')
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String()
+			}
+			b.WriteString(query[i : i+end+1])
+			i += end
+		case c == '$' && i+1 < len(query) && query[i+1] >= '0' && query[i+1] <= '9':
+			b.WriteByte('?')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,722 @@
+-- SQLite schema for local development.
+-- Mirrors the PostgreSQL migrations using SQLite types; UUIDs are stored as TEXT,
+-- JSONB as TEXT and timestamps as DATETIME so the shared repositories can scan them.
+
+PRAGMA foreign_keys = ON;
+
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT PRIMARY KEY,
+    email TEXT UNIQUE NOT NULL,
+    phone TEXT UNIQUE NOT NULL,
+    name TEXT NOT NULL,
+    user_type TEXT NOT NULL CHECK (user_type IN ('passenger', 'driver')),
+    avatar_key TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS fleets (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    api_key_hash TEXT NOT NULL UNIQUE,
+    timezone TEXT NOT NULL DEFAULT 'UTC',
+    daily_request_quota INTEGER CHECK (daily_request_quota > 0),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS drivers (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    license_number TEXT UNIQUE NOT NULL,
+    vehicle_type TEXT NOT NULL,
+    vehicle_plate TEXT NOT NULL,
+    status TEXT DEFAULT 'offline' CHECK (status IN ('online', 'offline', 'busy')),
+    current_latitude REAL,
+    current_longitude REAL,
+    rating REAL DEFAULT 5.00,
+    total_trips INTEGER DEFAULT 0,
+    fleet_id TEXT REFERENCES fleets(id) ON DELETE SET NULL,
+    pet_friendly BOOLEAN NOT NULL DEFAULT 0,
+    wheelchair_accessible BOOLEAN NOT NULL DEFAULT 0,
+    quiet_rides BOOLEAN NOT NULL DEFAULT 0,
+    child_seat BOOLEAN NOT NULL DEFAULT 0,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS driver_status_history (
+    id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
+    driver_id TEXT NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('online', 'offline', 'busy')),
+    changed_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER IF NOT EXISTS record_driver_status_insert AFTER INSERT ON drivers
+BEGIN
+    INSERT INTO driver_status_history (driver_id, status) VALUES (NEW.id, NEW.status);
+END;
+
+CREATE TRIGGER IF NOT EXISTS record_driver_status_update AFTER UPDATE OF status ON drivers
+    WHEN NEW.status IS NOT OLD.status
+BEGIN
+    INSERT INTO driver_status_history (driver_id, status) VALUES (NEW.id, NEW.status);
+END;
+
+CREATE TABLE IF NOT EXISTS driver_rests (
+    id TEXT PRIMARY KEY,
+    driver_id TEXT NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    limit_reached TEXT NOT NULL CHECK (limit_reached IN ('online_time', 'driving_time')),
+    online_seconds INTEGER NOT NULL,
+    driving_seconds INTEGER NOT NULL,
+    started_at DATETIME NOT NULL,
+    rest_until DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS driver_destinations (
+    id TEXT PRIMARY KEY,
+    driver_id TEXT NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    latitude REAL NOT NULL,
+    longitude REAL NOT NULL,
+    address TEXT,
+    expires_at DATETIME NOT NULL,
+    cleared_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS corporate_accounts (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    monthly_spending_limit REAL CHECK (monthly_spending_limit >= 0),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS passengers (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating REAL DEFAULT 5.00,
+    total_trips INTEGER DEFAULT 0,
+    corporate_account_id TEXT REFERENCES corporate_accounts(id) ON DELETE SET NULL,
+    quiet_ride BOOLEAN NOT NULL DEFAULT 0,
+    pet_friendly BOOLEAN NOT NULL DEFAULT 0,
+    accessible_vehicle BOOLEAN NOT NULL DEFAULT 0,
+    min_driver_rating REAL CHECK (min_driver_rating BETWEEN 1 AND 5),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS saved_locations (
+    id TEXT PRIMARY KEY,
+    passenger_id TEXT NOT NULL REFERENCES passengers(id) ON DELETE CASCADE,
+    label TEXT NOT NULL CHECK (label IN ('home', 'work', 'custom')),
+    name TEXT NOT NULL,
+    latitude REAL NOT NULL,
+    longitude REAL NOT NULL,
+    address TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS trips (
+    id TEXT PRIMARY KEY,
+    passenger_id TEXT NOT NULL REFERENCES passengers(id),
+    driver_id TEXT REFERENCES drivers(id),
+    pickup_latitude REAL NOT NULL,
+    pickup_longitude REAL NOT NULL,
+    destination_latitude REAL NOT NULL,
+    destination_longitude REAL NOT NULL,
+    pickup_address TEXT,
+    destination_address TEXT,
+    status TEXT DEFAULT 'requested' CHECK (status IN (
+        'requested', 'matched', 'accepted', 'driver_arrived',
+        'in_progress', 'completed', 'cancelled'
+    )),
+    fare_amount REAL,
+    distance_km REAL,
+    duration_minutes INTEGER,
+    requested_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    matched_at DATETIME,
+    accepted_at DATETIME,
+    pickup_at DATETIME,
+    completed_at DATETIME,
+    cancelled_at DATETIME,
+    cancellation_reason TEXT CHECK (cancellation_reason IN (
+        'changed_plans', 'wait_too_long', 'driver_not_moving', 'wrong_pickup',
+        'fare_too_high', 'safety_concern', 'passenger_no_show', 'driver_cancelled', 'other'
+    )),
+    cancellation_note TEXT,
+    cancellation_mode TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS actor_instances (
+    id TEXT PRIMARY KEY,
+    actor_type TEXT NOT NULL CHECK (actor_type IN (
+        'passenger', 'driver', 'trip', 'matching', 'observability'
+    )),
+    actor_id TEXT NOT NULL,
+    entity_type TEXT,
+    entity_id TEXT,
+    status TEXT DEFAULT 'active' CHECK (status IN ('active', 'inactive', 'error')),
+    last_heartbeat DATETIME DEFAULT CURRENT_TIMESTAMP,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(actor_type, actor_id)
+);
+
+CREATE TABLE IF NOT EXISTS actor_messages (
+    id TEXT PRIMARY KEY,
+    trace_id TEXT NOT NULL,
+    span_id TEXT NOT NULL,
+    parent_span_id TEXT,
+    sender_actor_type TEXT NOT NULL,
+    sender_actor_id TEXT NOT NULL,
+    receiver_actor_type TEXT NOT NULL,
+    receiver_actor_id TEXT NOT NULL,
+    entity_type TEXT,
+    entity_id TEXT,
+    message_type TEXT NOT NULL,
+    message_payload TEXT,
+    message_version INTEGER NOT NULL DEFAULT 1,
+    status TEXT DEFAULT 'sent' CHECK (status IN ('sent', 'received', 'processed', 'failed', 'dead_lettered')),
+    sent_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    received_at DATETIME,
+    processed_at DATETIME,
+    processing_duration_ms INTEGER,
+    error_message TEXT,
+    retry_count INTEGER NOT NULL DEFAULT 0,
+    effects TEXT,
+    hlc TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS system_metrics (
+    id TEXT PRIMARY KEY,
+    metric_name TEXT NOT NULL,
+    metric_type TEXT NOT NULL CHECK (metric_type IN ('counter', 'gauge', 'histogram')),
+    metric_value REAL NOT NULL,
+    labels TEXT,
+    actor_type TEXT,
+    actor_id TEXT,
+    timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS distributed_traces (
+    id TEXT PRIMARY KEY,
+    trace_id TEXT NOT NULL,
+    span_id TEXT NOT NULL,
+    parent_span_id TEXT,
+    operation_name TEXT NOT NULL,
+    actor_type TEXT,
+    actor_id TEXT,
+    start_time DATETIME NOT NULL,
+    end_time DATETIME,
+    duration_ms INTEGER,
+    status TEXT DEFAULT 'ok' CHECK (status IN ('ok', 'error', 'timeout')),
+    tags TEXT,
+    logs TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS event_logs (
+    id TEXT PRIMARY KEY,
+    trace_id TEXT,
+    event_type TEXT NOT NULL,
+    event_category TEXT NOT NULL CHECK (event_category IN (
+        'business', 'system', 'error', 'performance', 'security'
+    )),
+    actor_type TEXT,
+    actor_id TEXT,
+    entity_type TEXT,
+    entity_id TEXT,
+    event_data TEXT,
+    severity TEXT DEFAULT 'info' CHECK (severity IN ('debug', 'info', 'warn', 'error', 'fatal')),
+    message TEXT,
+    timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+    hlc TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS traditional_logs (
+    id TEXT PRIMARY KEY,
+    level TEXT NOT NULL,
+    message TEXT NOT NULL,
+    service_name TEXT,
+    instance_id TEXT,
+    fields TEXT,
+    timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS traditional_metrics (
+    id TEXT PRIMARY KEY,
+    metric_name TEXT NOT NULL,
+    metric_type TEXT NOT NULL,
+    metric_value REAL NOT NULL,
+    labels TEXT,
+    service_name TEXT,
+    instance_id TEXT,
+    timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS service_health (
+    id TEXT PRIMARY KEY,
+    service_name TEXT NOT NULL,
+    status TEXT NOT NULL,
+    response_time_ms INTEGER,
+    cpu_usage_percent REAL,
+    memory_usage_percent REAL,
+    disk_usage_percent REAL,
+    active_connections INTEGER,
+    error_rate_percent REAL,
+    last_error TEXT,
+    timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS webhook_subscriptions (
+    id TEXT PRIMARY KEY,
+    owner TEXT NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,
+    events TEXT NOT NULL DEFAULT '',
+    active BOOLEAN NOT NULL DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id TEXT PRIMARY KEY,
+    subscription_id TEXT NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    trip_id TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    response_status INTEGER,
+    last_error TEXT,
+    next_attempt_at DATETIME,
+    delivered_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS sessions (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_type TEXT NOT NULL CHECK (user_type IN ('passenger', 'driver')),
+    device_id TEXT NOT NULL,
+    device_name TEXT,
+    app_platform TEXT,
+    app_version TEXT,
+    access_token_hash TEXT NOT NULL UNIQUE,
+    refresh_token_hash TEXT NOT NULL UNIQUE,
+    access_expires_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    last_used_at DATETIME NOT NULL,
+    revoked_at DATETIME,
+    revoked_reason TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS fare_disputes (
+    id TEXT PRIMARY KEY,
+    trip_id TEXT NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    passenger_id TEXT NOT NULL REFERENCES passengers(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    original_fare REAL NOT NULL,
+    requested_fare REAL,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'under_review', 'resolved', 'rejected')),
+    adjusted_fare REAL,
+    reviewed_by TEXT,
+    resolution_note TEXT,
+    reviewed_at DATETIME,
+    closed_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS fare_adjustments (
+    id TEXT PRIMARY KEY,
+    trip_id TEXT NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    dispute_id TEXT NOT NULL REFERENCES fare_disputes(id) ON DELETE CASCADE,
+    previous_fare REAL NOT NULL,
+    new_fare REAL NOT NULL,
+    amount REAL NOT NULL,
+    reason TEXT NOT NULL,
+    adjusted_by TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS trip_completions (
+    trip_id TEXT PRIMARY KEY REFERENCES trips(id) ON DELETE CASCADE,
+    driver_id TEXT NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    dropoff_latitude REAL NOT NULL,
+    dropoff_longitude REAL NOT NULL,
+    odometer_distance_km REAL NOT NULL,
+    tolls_amount REAL NOT NULL DEFAULT 0,
+    extras_amount REAL NOT NULL DEFAULT 0,
+    wait_fee REAL NOT NULL DEFAULT 0,
+    route_distance_km REAL NOT NULL,
+    dropoff_deviation_km REAL NOT NULL,
+    billed_distance_km REAL NOT NULL,
+    estimated_fare REAL NOT NULL,
+    final_fare REAL NOT NULL,
+    flagged BOOLEAN NOT NULL DEFAULT 0,
+    flag_reason TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS pickup_waits (
+    trip_id TEXT PRIMARY KEY REFERENCES trips(id) ON DELETE CASCADE,
+    driver_id TEXT NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    zone TEXT NOT NULL,
+    arrived_at DATETIME NOT NULL,
+    no_show_after DATETIME NOT NULL,
+    outcome TEXT NOT NULL DEFAULT 'waiting' CHECK (outcome IN ('waiting', 'picked_up', 'no_show')),
+    ended_at DATETIME,
+    wait_seconds INTEGER,
+    wait_fee REAL NOT NULL DEFAULT 0,
+    no_show_fee REAL NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS incentive_campaigns (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    description TEXT,
+    required_trips INTEGER NOT NULL CHECK (required_trips > 0),
+    bonus_amount REAL NOT NULL CHECK (bonus_amount > 0),
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT 1,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE TABLE IF NOT EXISTS incentive_trip_credits (
+    campaign_id TEXT NOT NULL REFERENCES incentive_campaigns(id) ON DELETE CASCADE,
+    trip_id TEXT NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    driver_id TEXT NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    completed_at DATETIME NOT NULL,
+    PRIMARY KEY (campaign_id, trip_id)
+);
+
+CREATE TABLE IF NOT EXISTS incentive_progress (
+    campaign_id TEXT NOT NULL REFERENCES incentive_campaigns(id) ON DELETE CASCADE,
+    driver_id TEXT NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    completed_trips INTEGER NOT NULL DEFAULT 0,
+    completed_at DATETIME,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (campaign_id, driver_id)
+);
+
+CREATE TABLE IF NOT EXISTS incentive_payouts (
+    id TEXT PRIMARY KEY,
+    campaign_id TEXT NOT NULL REFERENCES incentive_campaigns(id) ON DELETE CASCADE,
+    driver_id TEXT NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    amount REAL NOT NULL,
+    trip_count INTEGER NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (campaign_id, driver_id)
+);
+
+CREATE TABLE IF NOT EXISTS dashboard_hourly_trips (
+    hour DATETIME PRIMARY KEY,
+    requested INTEGER NOT NULL DEFAULT 0,
+    matched INTEGER NOT NULL DEFAULT 0,
+    completed INTEGER NOT NULL DEFAULT 0,
+    cancelled INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS dashboard_matching_times (
+    hour DATETIME PRIMARY KEY,
+    samples INTEGER NOT NULL,
+    avg_seconds REAL NOT NULL,
+    p50_seconds REAL NOT NULL,
+    p90_seconds REAL NOT NULL,
+    p99_seconds REAL NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS dashboard_zone_supply (
+    zone TEXT PRIMARY KEY,
+    available_drivers INTEGER NOT NULL DEFAULT 0,
+    busy_drivers INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS dashboard_refreshes (
+    view_name TEXT PRIMARY KEY,
+    refreshed_at DATETIME NOT NULL,
+    duration_ms INTEGER NOT NULL,
+    row_count INTEGER NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS trip_chat_messages (
+    id TEXT PRIMARY KEY,
+    trip_id TEXT NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    sender_role TEXT NOT NULL CHECK (sender_role IN ('passenger', 'driver')),
+    sender_id TEXT NOT NULL,
+    body TEXT NOT NULL,
+    read_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS safety_incidents (
+    id TEXT PRIMARY KEY,
+    trip_id TEXT NOT NULL REFERENCES trips(id) ON DELETE RESTRICT,
+    reporter_type TEXT NOT NULL CHECK (reporter_type IN ('passenger', 'driver')),
+    reporter_id TEXT NOT NULL,
+    latitude REAL,
+    longitude REAL,
+    location_source TEXT CHECK (location_source IN ('reporter', 'driver')),
+    trip_status TEXT NOT NULL,
+    note TEXT,
+    snapshot TEXT,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'under_review', 'closed')),
+    reviewed_by TEXT,
+    resolution_note TEXT,
+    reviewed_at DATETIME,
+    closed_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS fraud_signals (
+    id TEXT PRIMARY KEY,
+    rule TEXT NOT NULL CHECK (rule IN ('impossible_speed', 'repeated_cancellations', 'gps_teleport', 'self_referral')),
+    severity TEXT NOT NULL CHECK (severity IN ('low', 'medium', 'high')),
+    subject_type TEXT NOT NULL CHECK (subject_type IN ('passenger', 'driver')),
+    subject_id TEXT NOT NULL,
+    trip_id TEXT REFERENCES trips(id) ON DELETE SET NULL,
+    details TEXT,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'confirmed', 'dismissed')),
+    reviewed_by TEXT,
+    review_note TEXT,
+    reviewed_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS api_usage_rollups (
+    key_id TEXT NOT NULL,
+    tenant_id TEXT NOT NULL,
+    bucket_start DATETIME NOT NULL,
+    requests INTEGER NOT NULL DEFAULT 0,
+    client_errors INTEGER NOT NULL DEFAULT 0,
+    server_errors INTEGER NOT NULL DEFAULT 0,
+    quota_rejections INTEGER NOT NULL DEFAULT 0,
+    latency_sum_ms REAL NOT NULL DEFAULT 0,
+    latency_max_ms REAL NOT NULL DEFAULT 0,
+    PRIMARY KEY (key_id, bucket_start)
+);
+
+CREATE TABLE IF NOT EXISTS corporate_trip_charges (
+    trip_id TEXT PRIMARY KEY REFERENCES trips(id) ON DELETE CASCADE,
+    account_id TEXT NOT NULL REFERENCES corporate_accounts(id) ON DELETE RESTRICT,
+    passenger_id TEXT NOT NULL REFERENCES passengers(id) ON DELETE CASCADE,
+    estimated_fare REAL NOT NULL CHECK (estimated_fare >= 0),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS invoices (
+    id TEXT PRIMARY KEY,
+    party_type TEXT NOT NULL CHECK (party_type IN ('corporate_account', 'fleet')),
+    party_id TEXT NOT NULL,
+    party_name TEXT NOT NULL,
+    month TEXT NOT NULL,
+    status TEXT NOT NULL CHECK (status IN ('pending', 'generated', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    trips INTEGER NOT NULL DEFAULT 0,
+    total_amount REAL NOT NULL DEFAULT 0,
+    csv_key TEXT,
+    pdf_key TEXT,
+    generated_at DATETIME,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (party_type, party_id, month)
+);
+
+CREATE TABLE IF NOT EXISTS eta_predictions (
+    trip_id TEXT PRIMARY KEY REFERENCES trips(id) ON DELETE CASCADE,
+    driver_id TEXT NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    zone TEXT NOT NULL,
+    hour INTEGER NOT NULL CHECK (hour BETWEEN 0 AND 23),
+    distance_km REAL NOT NULL,
+    speed_kmh REAL NOT NULL,
+    predicted_seconds INTEGER NOT NULL,
+    predicted_at DATETIME NOT NULL,
+    arrived_at DATETIME,
+    actual_seconds INTEGER,
+    error_seconds INTEGER
+);
+
+CREATE TABLE IF NOT EXISTS driver_location_pings (
+    driver_id TEXT NOT NULL,
+    latitude REAL NOT NULL,
+    longitude REAL NOT NULL,
+    recorded_at DATETIME NOT NULL,
+    resolution_seconds INTEGER NOT NULL DEFAULT 0,
+    samples INTEGER NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS driver_onboardings (
+    driver_id TEXT PRIMARY KEY REFERENCES drivers(id) ON DELETE CASCADE,
+    stage TEXT NOT NULL DEFAULT 'registered' CHECK (stage IN (
+        'registered', 'documents_submitted', 'verified', 'training_completed', 'activated'
+    )),
+    documents TEXT NOT NULL DEFAULT '',
+    registered_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    documents_submitted_at DATETIME,
+    verified_at DATETIME,
+    verified_by TEXT,
+    training_completed_at DATETIME,
+    activated_at DATETIME,
+    activated_by TEXT,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS trip_rematches (
+    id TEXT PRIMARY KEY,
+    trip_id TEXT NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL CHECK (attempt > 0),
+    cancelled_driver_id TEXT NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    cancelled_at DATETIME NOT NULL,
+    note TEXT,
+    mode TEXT NOT NULL,
+    outcome TEXT NOT NULL DEFAULT 'matching' CHECK (outcome IN ('matching', 'matched', 'unmatched', 'limit_reached')),
+    matched_driver_id TEXT REFERENCES drivers(id) ON DELETE SET NULL,
+    ended_at DATETIME,
+    added_wait_seconds INTEGER,
+    UNIQUE (trip_id, attempt)
+);
+
+CREATE TABLE IF NOT EXISTS trip_payments (
+    id TEXT PRIMARY KEY,
+    trip_id TEXT NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('charge', 'refund')),
+    method TEXT NOT NULL CHECK (method IN ('card', 'cash', 'wallet', 'corporate')),
+    amount REAL NOT NULL CHECK (amount > 0),
+    reference TEXT,
+    recorded_by TEXT,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS payment_discrepancies (
+    id TEXT PRIMARY KEY,
+    trip_id TEXT NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('missing_payment', 'amount_mismatch')),
+    expected_amount REAL NOT NULL,
+    paid_amount REAL NOT NULL,
+    status TEXT NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved', 'dismissed')),
+    resolution TEXT CHECK (resolution IN ('record_payment', 'refund', 'write_off', 'dismiss', 'settled')),
+    payment_id TEXT REFERENCES trip_payments(id) ON DELETE SET NULL,
+    resolved_by TEXT,
+    resolution_note TEXT,
+    resolved_at DATETIME,
+    detected_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS driver_trip_stats (
+    driver_id TEXT PRIMARY KEY REFERENCES drivers(id) ON DELETE CASCADE,
+    total_trips INTEGER NOT NULL DEFAULT 0,
+    total_distance_km REAL NOT NULL DEFAULT 0,
+    last_trip_at DATETIME
+);
+
+CREATE TABLE IF NOT EXISTS driver_daily_earnings (
+    driver_id TEXT NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    day TEXT NOT NULL,
+    trips INTEGER NOT NULL DEFAULT 0,
+    earnings REAL NOT NULL DEFAULT 0,
+    PRIMARY KEY (driver_id, day)
+);
+
+CREATE TABLE IF NOT EXISTS integrity_runs (
+    id TEXT PRIMARY KEY,
+    window_start DATETIME NOT NULL,
+    window_end DATETIME NOT NULL,
+    total_rows INTEGER NOT NULL DEFAULT 0,
+    started_at DATETIME NOT NULL,
+    duration_ms INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS integrity_checksums (
+    run_id TEXT NOT NULL REFERENCES integrity_runs(id) ON DELETE CASCADE,
+    table_name TEXT NOT NULL,
+    hour DATETIME NOT NULL,
+    row_count INTEGER NOT NULL,
+    checksum INTEGER NOT NULL,
+    PRIMARY KEY (run_id, table_name, hour)
+);
+
+CREATE INDEX IF NOT EXISTS idx_drivers_status ON drivers(status);
+CREATE INDEX IF NOT EXISTS idx_drivers_location ON drivers(current_latitude, current_longitude);
+CREATE INDEX IF NOT EXISTS idx_drivers_fleet_id ON drivers(fleet_id) WHERE fleet_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_invoices_month_status ON invoices(month, status);
+CREATE INDEX IF NOT EXISTS idx_drivers_plate_search ON drivers(UPPER(REPLACE(vehicle_plate, ' ', '')));
+CREATE INDEX IF NOT EXISTS idx_users_email_lower ON users(LOWER(email));
+CREATE INDEX IF NOT EXISTS idx_trips_passenger_id ON trips(passenger_id);
+CREATE INDEX IF NOT EXISTS idx_trips_passenger_requested_at ON trips(passenger_id, requested_at);
+CREATE INDEX IF NOT EXISTS idx_trips_driver_id ON trips(driver_id);
+CREATE INDEX IF NOT EXISTS idx_trips_driver_requested_at ON trips(driver_id, requested_at);
+CREATE INDEX IF NOT EXISTS idx_driver_status_history_driver ON driver_status_history(driver_id, changed_at);
+CREATE INDEX IF NOT EXISTS idx_driver_rests_driver_rest_until ON driver_rests(driver_id, rest_until);
+CREATE INDEX IF NOT EXISTS idx_driver_destinations_driver_created_at ON driver_destinations(driver_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_trips_status ON trips(status);
+CREATE INDEX IF NOT EXISTS idx_trips_requested_at ON trips(requested_at);
+CREATE INDEX IF NOT EXISTS idx_trips_cancelled_at ON trips(cancelled_at);
+CREATE INDEX IF NOT EXISTS idx_trips_pickup_location ON trips(pickup_latitude, pickup_longitude);
+CREATE INDEX IF NOT EXISTS idx_saved_locations_passenger_id ON saved_locations(passenger_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_saved_locations_passenger_label ON saved_locations(passenger_id, label)
+    WHERE label IN ('home', 'work');
+CREATE INDEX IF NOT EXISTS idx_actor_messages_sender ON actor_messages(sender_actor_type, sender_actor_id);
+CREATE INDEX IF NOT EXISTS idx_actor_messages_receiver ON actor_messages(receiver_actor_type, receiver_actor_id);
+CREATE INDEX IF NOT EXISTS idx_actor_messages_created_at ON actor_messages(created_at);
+CREATE INDEX IF NOT EXISTS idx_actor_messages_entity ON actor_messages(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_actor_messages_trace_hlc ON actor_messages(trace_id, hlc);
+CREATE INDEX IF NOT EXISTS idx_actor_instances_entity ON actor_instances(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_system_metrics_timestamp ON system_metrics(timestamp);
+CREATE INDEX IF NOT EXISTS idx_distributed_traces_trace_id ON distributed_traces(trace_id);
+CREATE INDEX IF NOT EXISTS idx_event_logs_timestamp ON event_logs(timestamp);
+CREATE INDEX IF NOT EXISTS idx_traditional_logs_timestamp ON traditional_logs(timestamp);
+CREATE INDEX IF NOT EXISTS idx_traditional_metrics_timestamp ON traditional_metrics(timestamp);
+CREATE INDEX IF NOT EXISTS idx_service_health_service ON service_health(service_name, timestamp);
+CREATE INDEX IF NOT EXISTS idx_webhook_subscriptions_owner ON webhook_subscriptions(owner);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_subscription_id ON webhook_deliveries(subscription_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_trip_id ON webhook_deliveries(trip_id);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_sessions_user_id ON sessions(user_id, last_used_at) WHERE revoked_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fare_disputes_active_trip ON fare_disputes(trip_id)
+    WHERE status IN ('open', 'under_review');
+CREATE INDEX IF NOT EXISTS idx_fare_disputes_status ON fare_disputes(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_fare_adjustments_trip_id ON fare_adjustments(trip_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_trip_completions_flagged ON trip_completions(created_at) WHERE flagged;
+CREATE INDEX IF NOT EXISTS idx_pickup_waits_arrived_at ON pickup_waits(arrived_at);
+CREATE INDEX IF NOT EXISTS idx_eta_predictions_predicted_at ON eta_predictions(predicted_at) WHERE arrived_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_driver_location_pings_driver ON driver_location_pings(driver_id, recorded_at);
+CREATE INDEX IF NOT EXISTS idx_incentive_campaigns_window ON incentive_campaigns(starts_at, ends_at) WHERE active;
+CREATE INDEX IF NOT EXISTS idx_incentive_progress_driver_id ON incentive_progress(driver_id);
+CREATE INDEX IF NOT EXISTS idx_incentive_payouts_driver_id ON incentive_payouts(driver_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_trip_chat_messages_trip_id ON trip_chat_messages(trip_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_trip_chat_messages_unread ON trip_chat_messages(trip_id, sender_role) WHERE read_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_safety_incidents_trip_id ON safety_incidents(trip_id);
+CREATE INDEX IF NOT EXISTS idx_safety_incidents_status ON safety_incidents(status, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_fraud_signals_trip ON fraud_signals(rule, trip_id, subject_id) WHERE trip_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_fraud_signals_subject ON fraud_signals(subject_id, rule, status);
+CREATE INDEX IF NOT EXISTS idx_fraud_signals_status ON fraud_signals(status, created_at);
+CREATE INDEX IF NOT EXISTS idx_api_usage_rollups_tenant ON api_usage_rollups(tenant_id, bucket_start);
+CREATE INDEX IF NOT EXISTS idx_api_usage_rollups_bucket_start ON api_usage_rollups(bucket_start);
+CREATE INDEX IF NOT EXISTS idx_corporate_trip_charges_account ON corporate_trip_charges(account_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_driver_onboardings_registered_at ON driver_onboardings(registered_at, stage);
+CREATE INDEX IF NOT EXISTS idx_trip_rematches_cancelled_at ON trip_rematches(cancelled_at);
+CREATE INDEX IF NOT EXISTS idx_trip_payments_trip_id ON trip_payments(trip_id, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payment_discrepancies_open_trip ON payment_discrepancies(trip_id, type) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_payment_discrepancies_status ON payment_discrepancies(status, detected_at);
+CREATE INDEX IF NOT EXISTS idx_integrity_runs_started_at ON integrity_runs(started_at);
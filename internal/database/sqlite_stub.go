@@ -0,0 +1,16 @@
+//go:build !sqlite
+
+package database
+
+import (
+	"fmt"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+)
+
+// NewSQLiteConnection is unavailable unless the binary is built with the sqlite build tag,
+// which pulls in the CGO SQLite driver
+func NewSQLiteConnection(cfg *config.DatabaseConfig, logger *logging.Logger) (*PostgresDB, error) {
+	return nil, fmt.Errorf("sqlite driver not available: rebuild with -tags sqlite")
+}
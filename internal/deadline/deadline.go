@@ -0,0 +1,69 @@
+// Package deadline carries the time budget of a request in its context. The budget is the
+// context's deadline, so it propagates to everything the request calls; each layer that gives up
+// because the deadline passed reports itself, and the first one to do so is recorded as the layer
+// the budget ran out in.
+package deadline
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Layers a budget can run out in
+const (
+	LayerHTTP       = "http" // the handler, or nothing below it reported the deadline
+	LayerService    = "service"
+	LayerRepository = "repository"
+	LayerActor      = "actor"
+)
+
+// Budget is the time budget of a request
+type Budget struct {
+	Timeout time.Duration
+
+	mu    sync.Mutex
+	layer string
+}
+
+type budgetKey struct{}
+
+// WithBudget returns a copy of ctx that is done once timeout has passed, carrying a budget the
+// layers report to when they run out of time
+func WithBudget(ctx context.Context, timeout time.Duration) (context.Context, *Budget, context.CancelFunc) {
+	budget := &Budget{Timeout: timeout}
+	ctx, cancel := context.WithTimeout(context.WithValue(ctx, budgetKey{}, budget), timeout)
+	return ctx, budget, cancel
+}
+
+// FromContext returns the budget of the request ctx belongs to, if it has one
+func FromContext(ctx context.Context) (*Budget, bool) {
+	budget, ok := ctx.Value(budgetKey{}).(*Budget)
+	return budget, ok
+}
+
+// Layer returns the layer the budget ran out in, empty while no layer reported it
+func (b *Budget) Layer() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.layer
+}
+
+// Exceeded reports whether err is caused by the request's deadline having passed: it is
+// context.DeadlineExceeded or, as database drivers return their own errors when a query is
+// cancelled, any error once ctx's deadline passed. If so layer is recorded on the request's
+// budget, unless a layer below it already was.
+func Exceeded(ctx context.Context, layer string, err error) bool {
+	if err == nil || (!errors.Is(err, context.DeadlineExceeded) && !errors.Is(ctx.Err(), context.DeadlineExceeded)) {
+		return false
+	}
+	if budget, ok := FromContext(ctx); ok {
+		budget.mu.Lock()
+		if budget.layer == "" {
+			budget.layer = layer
+		}
+		budget.mu.Unlock()
+	}
+	return true
+}
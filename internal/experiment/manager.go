@@ -0,0 +1,209 @@
+package experiment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// maxIdentifierLength is the length PostgreSQL truncates identifiers to
+const maxIdentifierLength = 63
+
+// experimentName is the form of experiment names, which are used unquoted in schema names
+var experimentName = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Experiment errors
+var (
+	ErrExperimentExists = errors.New("experiment already exists")
+	ErrExperimentActive = errors.New("experiment is active")
+)
+
+// Experiment is an experiment run's dataset
+type Experiment struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+	Active bool   `json:"active"` // observability records are written to the experiment
+}
+
+// Manager creates, switches to and drops experiment schemas. The active experiment is held by this
+// instance only: every instance is switched through its own API or started with the same
+// experiment. Records buffered by the metrics collector when switching are written to the newly
+// active experiment.
+type Manager struct {
+	db     *sqlx.DB
+	target *Target
+	prefix string
+	logger *logging.Logger
+	mu     sync.Mutex // serializes dropping experiments with switching to them
+}
+
+// NewManager creates a Manager of the experiments whose schemas are named prefix followed by their
+// name, switching target between them
+func NewManager(db *sqlx.DB, target *Target, prefix string, logger *logging.Logger) *Manager {
+	return &Manager{
+		db:     db,
+		target: target,
+		prefix: prefix,
+		logger: logger,
+	}
+}
+
+// Create creates an experiment, with empty copies of the observability tables of the default schema
+func (m *Manager) Create(ctx context.Context, name string) (*Experiment, error) {
+	if err := m.validate(name); err != nil {
+		return nil, err
+	}
+	schema := m.prefix + name
+
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, "CREATE SCHEMA "+pq.QuoteIdentifier(schema)); err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "42P06" { // duplicate_schema
+			return nil, ErrExperimentExists
+		}
+		return nil, fmt.Errorf("failed to create experiment schema: %w", err)
+	}
+	// The copies are plain tables: experiments are dropped wholesale rather than partitioned for
+	// retention
+	for _, table := range Tables {
+		query := fmt.Sprintf("CREATE TABLE %s.%s (LIKE %s INCLUDING DEFAULTS INCLUDING CONSTRAINTS INCLUDING INDEXES)",
+			pq.QuoteIdentifier(schema), table, table)
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			return nil, fmt.Errorf("failed to create experiment table %s: %w", table, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit experiment: %w", err)
+	}
+
+	m.logger.WithFields(logging.Fields{
+		"experiment": name,
+		"schema":     schema,
+	}).Info("Experiment created")
+	return &Experiment{Name: name, Schema: schema}, nil
+}
+
+// List returns the experiments by name
+func (m *Manager) List(ctx context.Context) ([]*Experiment, error) {
+	var schemas []string
+	query := `SELECT nspname FROM pg_namespace WHERE left(nspname, length($1)) = $1 ORDER BY nspname`
+	if err := m.db.SelectContext(ctx, &schemas, query, m.prefix); err != nil {
+		return nil, fmt.Errorf("failed to list experiments: %w", err)
+	}
+
+	active := m.target.Schema()
+	experiments := make([]*Experiment, 0, len(schemas))
+	for _, schema := range schemas {
+		experiments = append(experiments, &Experiment{
+			Name:   strings.TrimPrefix(schema, m.prefix),
+			Schema: schema,
+			Active: schema == active,
+		})
+	}
+	return experiments, nil
+}
+
+// Active returns the active experiment, or nil while records are written to the default schema
+func (m *Manager) Active() *Experiment {
+	schema := m.target.Schema()
+	if schema == "" {
+		return nil
+	}
+	return &Experiment{Name: strings.TrimPrefix(schema, m.prefix), Schema: schema, Active: true}
+}
+
+// Activate writes observability records to the experiment from now on. An empty name switches
+// back to the default schema.
+func (m *Manager) Activate(ctx context.Context, name string) (*Experiment, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if name == "" {
+		m.switchTo("")
+		return nil, nil
+	}
+	if err := m.validate(name); err != nil {
+		return nil, err
+	}
+	schema := m.prefix + name
+	if err := m.ensureExists(ctx, name, schema); err != nil {
+		return nil, err
+	}
+
+	m.switchTo(schema)
+	return &Experiment{Name: name, Schema: schema, Active: true}, nil
+}
+
+// Drop drops an experiment with its records. The active experiment cannot be dropped.
+func (m *Manager) Drop(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := m.validate(name); err != nil {
+		return err
+	}
+	schema := m.prefix + name
+	if schema == m.target.Schema() {
+		return ErrExperimentActive
+	}
+	if err := m.ensureExists(ctx, name, schema); err != nil {
+		return err
+	}
+
+	if _, err := m.db.ExecContext(ctx, "DROP SCHEMA "+pq.QuoteIdentifier(schema)+" CASCADE"); err != nil {
+		return fmt.Errorf("failed to drop experiment schema: %w", err)
+	}
+
+	m.logger.WithFields(logging.Fields{
+		"experiment": name,
+		"schema":     schema,
+	}).Info("Experiment dropped")
+	return nil
+}
+
+// switchTo targets schema, logging the switch
+func (m *Manager) switchTo(schema string) {
+	previous := m.target.Schema()
+	m.target.set(schema)
+	m.logger.WithFields(logging.Fields{
+		"previous": previous,
+		"schema":   schema,
+	}).Info("Observability target switched")
+}
+
+// validate checks that name can name an experiment schema
+func (m *Manager) validate(name string) error {
+	if !experimentName.MatchString(name) {
+		return &models.ValidationError{Field: "name", Message: "must start with a lowercase letter followed by lowercase letters, digits or underscores"}
+	}
+	if len(m.prefix)+len(name) > maxIdentifierLength {
+		return &models.ValidationError{Field: "name", Message: fmt.Sprintf("must be at most %d characters", maxIdentifierLength-len(m.prefix))}
+	}
+	return nil
+}
+
+// ensureExists returns a NotFoundError unless the experiment's schema exists
+func (m *Manager) ensureExists(ctx context.Context, name, schema string) error {
+	var exists bool
+	if err := m.db.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = $1)`, schema); err != nil {
+		return fmt.Errorf("failed to check experiment: %w", err)
+	}
+	if !exists {
+		return &models.NotFoundError{Resource: "experiment", ID: name}
+	}
+	return nil
+}
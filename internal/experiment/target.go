@@ -0,0 +1,55 @@
+// Package experiment isolates the observability records of experiment runs. Each experiment has
+// its own PostgreSQL schema holding a copy of the observability tables; while an experiment is
+// active, observability records are written to and read from its schema instead of the default
+// one, so a run's dataset never mixes with others and is dropped with its schema.
+package experiment
+
+import (
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/lib/pq"
+)
+
+// Tables are the observability tables each experiment has its own copy of
+var Tables = []string{"actor_instances", "actor_messages", "system_metrics", "distributed_traces", "event_logs"}
+
+// tableReference matches a statement's references to the observability tables, after the keyword
+// naming the table read or written
+var tableReference = regexp.MustCompile(`(?i)\b(FROM|INTO|UPDATE|JOIN)(\s+)(` + strings.Join(Tables, "|") + `)\b`)
+
+// Target is the schema observability records are written to and read from. The zero value, like a
+// nil Target, is the default schema. It is safe for concurrent use.
+type Target struct {
+	schema atomic.Value // string
+}
+
+// NewTarget creates a Target of the default schema
+func NewTarget() *Target {
+	return &Target{}
+}
+
+// Schema returns the targeted schema, or an empty string for the default schema
+func (t *Target) Schema() string {
+	if t == nil {
+		return ""
+	}
+	schema, _ := t.schema.Load().(string)
+	return schema
+}
+
+// set targets schema; an empty schema targets the default schema
+func (t *Target) set(schema string) {
+	t.schema.Store(schema)
+}
+
+// Qualify rewrites the references of query to the observability tables to name the tables of the
+// targeted schema. Queries are returned unchanged while the default schema is targeted.
+func (t *Target) Qualify(query string) string {
+	schema := t.Schema()
+	if schema == "" {
+		return query
+	}
+	return tableReference.ReplaceAllString(query, "${1}${2}"+pq.QuoteIdentifier(schema)+".${3}")
+}
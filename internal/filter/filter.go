@@ -0,0 +1,404 @@
+// Package filter parses the filter expressions accepted by the observability list endpoints,
+// such as
+//
+//	severity>=warn AND actor_type=trip AND message~"timeout"
+//
+// into a Filter that PostgreSQL and SQLite repositories translate into a WHERE clause and the
+// memory repositories evaluate against records.
+//
+// An expression compares fields with values, combined with AND, OR, NOT and parentheses; AND
+// binds tighter than OR. The operators are = and != for every field, <, <=, > and >= for numbers,
+// times and ordered values such as severity, and ~ and !~ for case-insensitive substring matches
+// of text. Values are bare words or double-quoted strings, which may contain spaces and escape
+// quotes and backslashes with a backslash. Comparisons of absent (NULL) fields are false.
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxLength is the longest expression accepted
+const MaxLength = 1024
+
+// MaxComparisons is the most comparisons an expression may contain
+const MaxComparisons = 32
+
+// Kind is the type of a filterable field, which decides the operators it supports
+type Kind int
+
+const (
+	// KindText fields support =, !=, ~ and !~
+	KindText Kind = iota
+	// KindEnum fields support = and != against their values, and ordering operators when ordered
+	KindEnum
+	// KindNumber fields support every comparison against numbers
+	KindNumber
+	// KindTime fields support every comparison against RFC3339 times
+	KindTime
+	// KindUUID fields support = and != against UUIDs
+	KindUUID
+)
+
+// Field describes a filterable field. Its name is both the column and the JSON field of the
+// filtered records.
+type Field struct {
+	Kind Kind
+	// Values lists the values of an enum field, lowest first when Ordered
+	Values  []string
+	Ordered bool
+	// Aliases maps alternative spellings of enum values to their values
+	Aliases map[string]string
+}
+
+// Schema is the set of fields a filter may refer to, by name
+type Schema map[string]Field
+
+// Op is a comparison operator
+type Op string
+
+const (
+	OpEq          Op = "="
+	OpNe          Op = "!="
+	OpLt          Op = "<"
+	OpLe          Op = "<="
+	OpGt          Op = ">"
+	OpGe          Op = ">="
+	OpContains    Op = "~"
+	OpNotContains Op = "!~"
+)
+
+// ordering reports whether the operator compares order rather than equality
+func (o Op) ordering() bool {
+	return o == OpLt || o == OpLe || o == OpGt || o == OpGe
+}
+
+// SyntaxError is returned for expressions that cannot be parsed or refer to unknown fields
+type SyntaxError struct {
+	Pos     int // byte offset of the offending token
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("at position %d: %s", e.Pos, e.Message)
+}
+
+// Filter is a parsed filter expression
+type Filter struct {
+	expr expr
+	text string
+}
+
+// String returns the expression the filter was parsed from
+func (f *Filter) String() string {
+	return f.text
+}
+
+// Parse parses an expression against the fields of schema
+func Parse(text string, schema Schema) (*Filter, error) {
+	if len(text) > MaxLength {
+		return nil, &SyntaxError{Pos: MaxLength, Message: fmt.Sprintf("expression longer than %d characters", MaxLength)}
+	}
+
+	tokens, err := lex(text)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens, schema: schema}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, &SyntaxError{Pos: tok.pos, Message: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+	return &Filter{expr: e, text: text}, nil
+}
+
+// expr is a node of a parsed expression
+type expr interface {
+	sql(b *sqlBuilder) string
+	match(lookup func(string) interface{}) bool
+}
+
+type andExpr struct{ left, right expr }
+
+type orExpr struct{ left, right expr }
+
+type notExpr struct{ inner expr }
+
+// comparison compares a field with a value parsed according to the field's kind: a string,
+// float64, time.Time or uuid.UUID. Ordered enum comparisons are resolved into the set of
+// values they accept.
+type comparison struct {
+	field string
+	op    Op
+	value interface{}
+	set   bool // compares with the values in in rather than with value
+	in    []string
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// lex splits an expression into tokens
+func lex(text string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case c == '"':
+			var value strings.Builder
+			start := i
+			i++
+			for {
+				if i >= len(text) {
+					return nil, &SyntaxError{Pos: start, Message: "unterminated string"}
+				}
+				if text[i] == '"' {
+					i++
+					break
+				}
+				if text[i] == '\\' && i+1 < len(text) {
+					i++
+				}
+				value.WriteByte(text[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenString, text: value.String(), pos: start})
+		case strings.IndexByte("=!<>~", c) >= 0:
+			start := i
+			i++
+			if i < len(text) && (text[i] == '=' || (c == '!' && text[i] == '~')) {
+				i++
+			}
+			op := text[start:i]
+			switch Op(op) {
+			case OpEq, OpNe, OpLt, OpLe, OpGt, OpGe, OpContains, OpNotContains:
+			default:
+				return nil, &SyntaxError{Pos: start, Message: fmt.Sprintf("unknown operator %q", op)}
+			}
+			tokens = append(tokens, token{kind: tokenOp, text: op, pos: start})
+		default:
+			start := i
+			for i < len(text) && strings.IndexByte(" \t\n\r()\"=!<>~", text[i]) < 0 {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: text[start:i], pos: start})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, text: "end of expression", pos: len(text)}), nil
+}
+
+// parser is a recursive descent parser over the tokens of an expression
+type parser struct {
+	tokens      []token
+	pos         int
+	schema      Schema
+	comparisons int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// keyword reports whether the next token is the given keyword, consuming it if so
+func (p *parser) keyword(word string) bool {
+	if tok := p.peek(); tok.kind == tokenWord && strings.EqualFold(tok.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("OR") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("AND") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.keyword("NOT") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{inner: inner}, nil
+	}
+
+	if tok := p.peek(); tok.kind == tokenLParen {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokenRParen {
+			return nil, &SyntaxError{Pos: tok.pos, Message: fmt.Sprintf("expected \")\", got %q", tok.text)}
+		}
+		return inner, nil
+	}
+
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	name := p.next()
+	if name.kind != tokenWord {
+		return nil, &SyntaxError{Pos: name.pos, Message: fmt.Sprintf("expected a field name, got %q", name.text)}
+	}
+	field, ok := p.schema[name.text]
+	if !ok {
+		return nil, &SyntaxError{Pos: name.pos, Message: fmt.Sprintf("unknown field %q", name.text)}
+	}
+
+	opTok := p.next()
+	if opTok.kind != tokenOp {
+		return nil, &SyntaxError{Pos: opTok.pos, Message: fmt.Sprintf("expected an operator after %s, got %q", name.text, opTok.text)}
+	}
+	op := Op(opTok.text)
+
+	valueTok := p.next()
+	if valueTok.kind != tokenWord && valueTok.kind != tokenString {
+		return nil, &SyntaxError{Pos: valueTok.pos, Message: fmt.Sprintf("expected a value after %s%s, got %q", name.text, op, valueTok.text)}
+	}
+
+	p.comparisons++
+	if p.comparisons > MaxComparisons {
+		return nil, &SyntaxError{Pos: name.pos, Message: fmt.Sprintf("more than %d comparisons", MaxComparisons)}
+	}
+
+	c := &comparison{field: name.text, op: op}
+	if err := c.parseValue(field, valueTok.text); err != nil {
+		return nil, &SyntaxError{Pos: valueTok.pos, Message: err.Error()}
+	}
+	return c, nil
+}
+
+// parseValue checks the operator suits the field and parses the value
+func (c *comparison) parseValue(field Field, value string) error {
+	unsupported := fmt.Errorf("%s does not support %s", c.field, c.op)
+
+	switch field.Kind {
+	case KindText:
+		if c.op.ordering() {
+			return unsupported
+		}
+		c.value = value
+
+	case KindEnum:
+		if c.op == OpContains || c.op == OpNotContains || (c.op.ordering() && !field.Ordered) {
+			return unsupported
+		}
+		if alias, ok := field.Aliases[strings.ToLower(value)]; ok {
+			value = alias
+		}
+		index := -1
+		for i, v := range field.Values {
+			if strings.EqualFold(v, value) {
+				index = i
+			}
+		}
+		if index < 0 {
+			return fmt.Errorf("%s must be one of %s", c.field, strings.Join(field.Values, ", "))
+		}
+		c.value = field.Values[index]
+		if c.op.ordering() {
+			c.set = true
+			for i, v := range field.Values {
+				if (c.op == OpLt && i < index) || (c.op == OpLe && i <= index) ||
+					(c.op == OpGt && i > index) || (c.op == OpGe && i >= index) {
+					c.in = append(c.in, v)
+				}
+			}
+		}
+
+	case KindNumber:
+		if c.op == OpContains || c.op == OpNotContains {
+			return unsupported
+		}
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s must be compared with a number", c.field)
+		}
+		c.value = n
+
+	case KindTime:
+		if c.op == OpContains || c.op == OpNotContains {
+			return unsupported
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return fmt.Errorf("%s must be compared with an RFC3339 time", c.field)
+		}
+		c.value = t
+
+	case KindUUID:
+		if c.op != OpEq && c.op != OpNe {
+			return unsupported
+		}
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return fmt.Errorf("%s must be compared with a UUID", c.field)
+		}
+		c.value = id
+	}
+	return nil
+}
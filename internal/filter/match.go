@@ -0,0 +1,141 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Match reports whether a record satisfies the filter. lookup returns the value of a field of
+// the record: a string or string-typed value, a number, a time.Time or a uuid.UUID, or a
+// pointer to one of them, with nil for absent values.
+func (f *Filter) Match(lookup func(field string) interface{}) bool {
+	return f.expr.match(lookup)
+}
+
+func (e andExpr) match(lookup func(string) interface{}) bool {
+	return e.left.match(lookup) && e.right.match(lookup)
+}
+
+func (e orExpr) match(lookup func(string) interface{}) bool {
+	return e.left.match(lookup) || e.right.match(lookup)
+}
+
+func (e notExpr) match(lookup func(string) interface{}) bool {
+	return !e.inner.match(lookup)
+}
+
+func (c *comparison) match(lookup func(string) interface{}) bool {
+	actual := normalize(lookup(c.field))
+	if actual == nil {
+		return false
+	}
+
+	if c.set {
+		for _, v := range c.in {
+			if actual == v {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch want := c.value.(type) {
+	case string:
+		got, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		switch c.op {
+		case OpContains:
+			return strings.Contains(strings.ToLower(got), strings.ToLower(want))
+		case OpNotContains:
+			return !strings.Contains(strings.ToLower(got), strings.ToLower(want))
+		}
+		return compare(c.op, strings.Compare(got, want))
+	case float64:
+		got, ok := actual.(float64)
+		if !ok {
+			return false
+		}
+		switch {
+		case got < want:
+			return compare(c.op, -1)
+		case got > want:
+			return compare(c.op, 1)
+		}
+		return compare(c.op, 0)
+	case time.Time:
+		got, ok := actual.(time.Time)
+		if !ok {
+			return false
+		}
+		return compare(c.op, got.Compare(want))
+	case uuid.UUID:
+		got, ok := actual.(uuid.UUID)
+		if !ok {
+			return false
+		}
+		return compare(c.op, strings.Compare(got.String(), want.String()))
+	}
+	return false
+}
+
+// compare applies an operator to the result of comparing a value with the filter's
+func compare(op Op, cmp int) bool {
+	switch op {
+	case OpEq:
+		return cmp == 0
+	case OpNe:
+		return cmp != 0
+	case OpLt:
+		return cmp < 0
+	case OpLe:
+		return cmp <= 0
+	case OpGt:
+		return cmp > 0
+	case OpGe:
+		return cmp >= 0
+	}
+	return false
+}
+
+// normalize dereferences a looked up value and converts it into a string, float64, time.Time
+// or uuid.UUID, or nil when it is absent
+func normalize(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case time.Time, uuid.UUID:
+		return v
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *uuid.UUID:
+		if v == nil {
+			return nil
+		}
+		return *v
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	}
+	return nil
+}
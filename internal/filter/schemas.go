@@ -0,0 +1,62 @@
+package filter
+
+import "actor-model-observability/internal/models"
+
+var (
+	actorTypes      = []string{string(models.ActorTypePassenger), string(models.ActorTypeDriver), string(models.ActorTypeTrip), string(models.ActorTypeMatching), string(models.ActorTypeObservability)}
+	actorTypeField  = Field{Kind: KindEnum, Values: actorTypes}
+	entityTypeField = Field{Kind: KindEnum, Values: []string{models.EntityTypeTrip, models.EntityTypeDriver, models.EntityTypePassenger}}
+)
+
+// EventLogFields are the fields event log filters may refer to
+var EventLogFields = Schema{
+	"trace_id":   {Kind: KindUUID},
+	"event_type": {Kind: KindText},
+	"event_category": {Kind: KindEnum, Values: []string{
+		string(models.EventCategoryBusiness), string(models.EventCategorySystem), string(models.EventCategoryError),
+		string(models.EventCategoryPerformance), string(models.EventCategorySecurity),
+	}},
+	"actor_type":  actorTypeField,
+	"actor_id":    {Kind: KindText},
+	"entity_type": entityTypeField,
+	"entity_id":   {Kind: KindUUID},
+	"severity": {Kind: KindEnum, Ordered: true, Values: []string{
+		string(models.EventSeverityDebug), string(models.EventSeverityInfo), string(models.EventSeverityWarn),
+		string(models.EventSeverityError), string(models.EventSeverityFatal),
+	}, Aliases: map[string]string{"warning": string(models.EventSeverityWarn)}},
+	"message":   {Kind: KindText},
+	"timestamp": {Kind: KindTime},
+}
+
+// ActorMessageFields are the fields actor message filters may refer to
+var ActorMessageFields = Schema{
+	"trace_id":            {Kind: KindUUID},
+	"sender_actor_type":   actorTypeField,
+	"sender_actor_id":     {Kind: KindText},
+	"receiver_actor_type": actorTypeField,
+	"receiver_actor_id":   {Kind: KindText},
+	"entity_type":         entityTypeField,
+	"entity_id":           {Kind: KindUUID},
+	"message_type":        {Kind: KindText},
+	"status": {Kind: KindEnum, Values: []string{
+		string(models.MessageStatusSent), string(models.MessageStatusReceived), string(models.MessageStatusProcessed),
+		string(models.MessageStatusFailed), string(models.MessageStatusDeadLettered),
+	}},
+	"sent_at":                {Kind: KindTime},
+	"processing_duration_ms": {Kind: KindNumber},
+	"error_message":          {Kind: KindText},
+	"retry_count":            {Kind: KindNumber},
+}
+
+// DistributedTraceFields are the fields distributed trace filters may refer to
+var DistributedTraceFields = Schema{
+	"trace_id":       {Kind: KindUUID},
+	"operation_name": {Kind: KindText},
+	"actor_type":     actorTypeField,
+	"actor_id":       {Kind: KindText},
+	"start_time":     {Kind: KindTime},
+	"duration_ms":    {Kind: KindNumber},
+	"status": {Kind: KindEnum, Values: []string{
+		string(models.TraceStatusOK), string(models.TraceStatusError), string(models.TraceStatusTimeout),
+	}},
+}
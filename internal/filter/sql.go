@@ -0,0 +1,85 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sqlBuilder collects the arguments of a WHERE clause, numbering their placeholders
+type sqlBuilder struct {
+	args []interface{}
+	next int
+}
+
+// arg adds an argument and returns its placeholder
+func (b *sqlBuilder) arg(value interface{}) string {
+	b.args = append(b.args, value)
+	placeholder := fmt.Sprintf("$%d", b.next)
+	b.next++
+	return placeholder
+}
+
+// SQL returns the filter as a WHERE condition whose placeholders start at $first, and the
+// arguments to bind to them. Field names are used as column names.
+func (f *Filter) SQL(first int) (string, []interface{}) {
+	b := &sqlBuilder{next: first}
+	return f.expr.sql(b), b.args
+}
+
+func (e andExpr) sql(b *sqlBuilder) string {
+	return "(" + e.left.sql(b) + " AND " + e.right.sql(b) + ")"
+}
+
+func (e orExpr) sql(b *sqlBuilder) string {
+	return "(" + e.left.sql(b) + " OR " + e.right.sql(b) + ")"
+}
+
+// A comparison of a NULL column is NULL, which NOT would leave NULL; it is treated as false
+// first so NOT selects the same rows as the memory repositories
+func (e notExpr) sql(b *sqlBuilder) string {
+	return "NOT COALESCE(" + e.inner.sql(b) + ", FALSE)"
+}
+
+// likeEscaper escapes the LIKE wildcards of a substring
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func (c *comparison) sql(b *sqlBuilder) string {
+	if c.set {
+		if len(c.in) == 0 {
+			return "FALSE"
+		}
+		placeholders := make([]string, len(c.in))
+		for i, v := range c.in {
+			placeholders[i] = b.arg(v)
+		}
+		return c.field + " IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	switch c.op {
+	case OpContains, OpNotContains:
+		pattern := "%" + likeEscaper.Replace(strings.ToLower(c.value.(string))) + "%"
+		not := ""
+		if c.op == OpNotContains {
+			not = "NOT "
+		}
+		return fmt.Sprintf(`LOWER(%s) %sLIKE %s ESCAPE '\'`, c.field, not, b.arg(pattern))
+	case OpNe:
+		return c.field + " <> " + b.arg(sqlValue(c.value))
+	default:
+		return c.field + " " + string(c.op) + " " + b.arg(sqlValue(c.value))
+	}
+}
+
+// sqlValue converts a comparison value into a driver argument
+func sqlValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case uuid.UUID:
+		return v.String()
+	case time.Time:
+		return v.UTC()
+	}
+	return value
+}
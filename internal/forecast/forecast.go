@@ -0,0 +1,166 @@
+// Package forecast predicts hourly ride demand from historical trip request times, using a
+// moving average or additive Holt-Winters model with a daily season, and measures a model's
+// accuracy by backtesting it on the most recent history.
+package forecast
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+// SeasonLength is the number of hourly buckets in the daily demand cycle
+const SeasonLength = 24
+
+// Forecasting methods
+const (
+	MethodMovingAverage = "moving_average"
+	MethodHoltWinters   = "holt_winters"
+)
+
+// ErrInsufficientHistory is returned when the history is too short for the method
+var ErrInsufficientHistory = errors.New("insufficient history for forecasting method")
+
+// Params holds the model settings
+type Params struct {
+	MovingAverageWindow int     // hours averaged by the moving average model
+	Alpha               float64 // Holt-Winters level smoothing
+	Beta                float64 // Holt-Winters trend smoothing
+	Gamma               float64 // Holt-Winters seasonal smoothing
+}
+
+// Accuracy reports how closely a backtested forecast matched the demand that occurred
+type Accuracy struct {
+	Points int     // hours compared
+	MAE    float64 // mean absolute error, in trips per hour
+	RMSE   float64 // root mean squared error, in trips per hour
+	MAPE   float64 // mean absolute percentage error over the hours with demand; NaN when there were none
+}
+
+// HourlyCounts counts the times falling in each of the hours hourly buckets starting at start
+func HourlyCounts(times []time.Time, start time.Time, hours int) []float64 {
+	counts := make([]float64, hours)
+	for _, t := range times {
+		bucket := int(t.Sub(start) / time.Hour)
+		if t.Before(start) || bucket >= hours {
+			continue
+		}
+		counts[bucket]++
+	}
+	return counts
+}
+
+// MinHistory returns the number of hourly buckets the method needs to forecast
+func MinHistory(method string, params Params) int {
+	if method == MethodHoltWinters {
+		return 2 * SeasonLength
+	}
+	return params.MovingAverageWindow
+}
+
+// Predict forecasts the next horizon values of series with the method
+func Predict(method string, series []float64, horizon int, params Params) ([]float64, error) {
+	switch method {
+	case MethodMovingAverage:
+		return MovingAverage(series, params.MovingAverageWindow, horizon)
+	case MethodHoltWinters:
+		return HoltWinters(series, params.Alpha, params.Beta, params.Gamma, horizon)
+	default:
+		return nil, fmt.Errorf("unknown forecasting method %q", method)
+	}
+}
+
+// MovingAverage forecasts every future value as the mean of the last window values
+func MovingAverage(series []float64, window, horizon int) ([]float64, error) {
+	if window <= 0 || len(series) < window {
+		return nil, ErrInsufficientHistory
+	}
+
+	sum := 0.0
+	for _, v := range series[len(series)-window:] {
+		sum += v
+	}
+	mean := sum / float64(window)
+
+	forecast := make([]float64, horizon)
+	for i := range forecast {
+		forecast[i] = mean
+	}
+	return forecast, nil
+}
+
+// HoltWinters forecasts with additive Holt-Winters smoothing of level, trend and daily season.
+// It needs at least two full seasons of history to initialise the trend and season.
+func HoltWinters(series []float64, alpha, beta, gamma float64, horizon int) ([]float64, error) {
+	m := SeasonLength
+	if len(series) < 2*m {
+		return nil, ErrInsufficientHistory
+	}
+
+	// Initialise the level from the first season, the trend from the change between the first
+	// two seasons and the seasonal components from the first season's deviations
+	var first, second float64
+	for i := 0; i < m; i++ {
+		first += series[i]
+		second += series[m+i]
+	}
+	level := first / float64(m)
+	trend := (second - first) / float64(m*m)
+	season := make([]float64, m)
+	for i := 0; i < m; i++ {
+		season[i] = series[i] - level
+	}
+
+	for t := m; t < len(series); t++ {
+		s := season[t%m]
+		previousLevel := level
+		level = alpha*(series[t]-s) + (1-alpha)*(level+trend)
+		trend = beta*(level-previousLevel) + (1-beta)*trend
+		season[t%m] = gamma*(series[t]-level) + (1-gamma)*s
+	}
+
+	forecast := make([]float64, horizon)
+	for h := 1; h <= horizon; h++ {
+		// Demand can't be negative
+		forecast[h-1] = math.Max(0, level+float64(h)*trend+season[(len(series)+h-1)%m])
+	}
+	return forecast, nil
+}
+
+// Backtest holds out the last holdout values of series, forecasts them from the rest with the
+// method and compares the forecast with what happened
+func Backtest(method string, series []float64, holdout int, params Params) (Accuracy, error) {
+	if holdout <= 0 || holdout >= len(series) {
+		return Accuracy{}, ErrInsufficientHistory
+	}
+	train, actual := series[:len(series)-holdout], series[len(series)-holdout:]
+
+	predicted, err := Predict(method, train, holdout, params)
+	if err != nil {
+		return Accuracy{}, err
+	}
+
+	var absSum, sqSum, pctSum float64
+	pctPoints := 0
+	for i, a := range actual {
+		diff := predicted[i] - a
+		absSum += math.Abs(diff)
+		sqSum += diff * diff
+		if a != 0 {
+			pctSum += math.Abs(diff / a)
+			pctPoints++
+		}
+	}
+
+	accuracy := Accuracy{
+		Points: holdout,
+		MAE:    absSum / float64(holdout),
+		RMSE:   math.Sqrt(sqSum / float64(holdout)),
+		MAPE:   math.NaN(),
+	}
+	if pctPoints > 0 {
+		accuracy.MAPE = 100 * pctSum / float64(pctPoints)
+	}
+	return accuracy, nil
+}
@@ -0,0 +1,39 @@
+package forecast
+
+import (
+	"fmt"
+	"strings"
+
+	"actor-model-observability/internal/geohash"
+)
+
+// MaxZonePrecision is the longest geohash accepted as a zone (~1.2km cells at 6)
+const MaxZonePrecision = 6
+
+// Zone is a geohash cell that trips are attributed to by their pickup location
+type Zone struct {
+	Geohash string  `json:"geohash"`
+	MinLat  float64 `json:"min_lat"`
+	MaxLat  float64 `json:"max_lat"`
+	MinLng  float64 `json:"min_lng"`
+	MaxLng  float64 `json:"max_lng"`
+}
+
+// ParseZone decodes a geohash of 1 to MaxZonePrecision characters into its bounding box
+func ParseZone(hash string) (Zone, error) {
+	hash = strings.ToLower(hash)
+	if hash == "" || len(hash) > MaxZonePrecision {
+		return Zone{}, fmt.Errorf("zone must be a geohash of 1 to %d characters", MaxZonePrecision)
+	}
+
+	box, err := geohash.Decode(hash)
+	if err != nil {
+		return Zone{}, fmt.Errorf("zone %q is not a valid geohash", hash)
+	}
+	return Zone{Geohash: hash, MinLat: box.MinLat, MaxLat: box.MaxLat, MinLng: box.MinLng, MaxLng: box.MaxLng}, nil
+}
+
+// Contains returns true if the point lies in the zone. Cells include their south and west edges.
+func (z Zone) Contains(lat, lng float64) bool {
+	return lat >= z.MinLat && lat < z.MaxLat && lng >= z.MinLng && lng < z.MaxLng
+}
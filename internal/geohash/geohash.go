@@ -0,0 +1,153 @@
+// Package geohash decodes geohashes and maps points to the geohash grid.
+//
+// The cells of a geohash of a given precision form a regular latitude/longitude grid, so a point's
+// cell can be computed with plain arithmetic (in SQL as well as in Go) and turned back into its
+// geohash with Cell.Geohash.
+package geohash
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Alphabet is the base32 alphabet of geohashes
+const Alphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// MaxPrecision is the longest geohash supported (~4.8cm cells at 12)
+const MaxPrecision = 12
+
+// Box is the bounding box of a geohash. It includes its south and west edges.
+type Box struct {
+	MinLat float64
+	MaxLat float64
+	MinLng float64
+	MaxLng float64
+}
+
+// Contains returns true if the point lies in the box
+func (b Box) Contains(lat, lng float64) bool {
+	return lat >= b.MinLat && lat < b.MaxLat && lng >= b.MinLng && lng < b.MaxLng
+}
+
+// Decode returns the bounding box of a geohash. Upper case characters are accepted.
+func Decode(hash string) (Box, error) {
+	hash = strings.ToLower(hash)
+	if hash == "" || len(hash) > MaxPrecision {
+		return Box{}, fmt.Errorf("geohash must have 1 to %d characters", MaxPrecision)
+	}
+
+	box := Box{MinLat: -90, MaxLat: 90, MinLng: -180, MaxLng: 180}
+	even := true // bits alternate between longitude and latitude, starting with longitude
+	for _, c := range hash {
+		index := strings.IndexRune(Alphabet, c)
+		if index < 0 {
+			return Box{}, fmt.Errorf("%q is not a valid geohash", hash)
+		}
+		for bit := 4; bit >= 0; bit-- {
+			set := index&(1<<bit) != 0
+			if even {
+				mid := (box.MinLng + box.MaxLng) / 2
+				if set {
+					box.MinLng = mid
+				} else {
+					box.MaxLng = mid
+				}
+			} else {
+				mid := (box.MinLat + box.MaxLat) / 2
+				if set {
+					box.MinLat = mid
+				} else {
+					box.MaxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return box, nil
+}
+
+// Grid is the grid of the geohash cells of one precision
+type Grid struct {
+	Precision int
+	latBits   int
+	lngBits   int
+}
+
+// NewGrid returns the grid of the geohashes with precision characters
+func NewGrid(precision int) (Grid, error) {
+	if precision < 1 || precision > MaxPrecision {
+		return Grid{}, fmt.Errorf("geohash precision must be between 1 and %d", MaxPrecision)
+	}
+	bits := 5 * precision
+	return Grid{Precision: precision, latBits: bits / 2, lngBits: bits - bits/2}, nil
+}
+
+// CellHeight returns the height of the grid's cells in degrees of latitude
+func (g Grid) CellHeight() float64 {
+	return 180 / math.Exp2(float64(g.latBits))
+}
+
+// CellWidth returns the width of the grid's cells in degrees of longitude
+func (g Grid) CellWidth() float64 {
+	return 360 / math.Exp2(float64(g.lngBits))
+}
+
+// Cell is a cell of a Grid, counted from the south-west corner of the world
+type Cell struct {
+	Row int // floor((lat + 90) / cell height)
+	Col int // floor((lng + 180) / cell width)
+}
+
+// Locate returns the cell containing a point. Points on the north pole and antimeridian are
+// placed in the northernmost and easternmost cells.
+func (g Grid) Locate(lat, lng float64) Cell {
+	return g.clamp(Cell{
+		Row: int(math.Floor((lat + 90) / g.CellHeight())),
+		Col: int(math.Floor((lng + 180) / g.CellWidth())),
+	})
+}
+
+// Geohash returns the geohash of a cell
+func (g Grid) Geohash(cell Cell) string {
+	cell = g.clamp(cell)
+
+	hash := make([]byte, g.Precision)
+	latBit, lngBit := g.latBits, g.lngBits
+	for i := range hash {
+		index := 0
+		for bit := 0; bit < 5; bit++ {
+			var set int
+			if (5*i+bit)%2 == 0 {
+				lngBit--
+				set = (cell.Col >> lngBit) & 1
+			} else {
+				latBit--
+				set = (cell.Row >> latBit) & 1
+			}
+			index = index<<1 | set
+		}
+		hash[i] = Alphabet[index]
+	}
+	return string(hash)
+}
+
+// Bounds returns the bounding box of a cell
+func (g Grid) Bounds(cell Cell) Box {
+	cell = g.clamp(cell)
+	height, width := g.CellHeight(), g.CellWidth()
+	return Box{
+		MinLat: -90 + float64(cell.Row)*height,
+		MaxLat: -90 + float64(cell.Row+1)*height,
+		MinLng: -180 + float64(cell.Col)*width,
+		MaxLng: -180 + float64(cell.Col+1)*width,
+	}
+}
+
+// clamp keeps a cell within the grid
+func (g Grid) clamp(cell Cell) Cell {
+	maxRow, maxCol := 1<<g.latBits-1, 1<<g.lngBits-1
+	cell.Row = min(max(cell.Row, 0), maxRow)
+	cell.Col = min(max(cell.Col, 0), maxCol)
+	return cell
+}
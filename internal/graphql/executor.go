@@ -0,0 +1,641 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+// Params is a GraphQL request
+type Params struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Result is a GraphQL response. Data is nil when the request failed before execution
+// (syntax, validation or variable errors); field errors leave the field null and are listed in Errors.
+type Result struct {
+	Data   *OrderedMap `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error is a GraphQL error with the query location and response path it relates to
+type Error struct {
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+}
+
+func newError(message string, location Location) *Error {
+	return &Error{Message: message, Locations: []Location{location}}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// OrderedMap is a JSON object that keeps the order of the selections that produced it
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func newOrderedMap() *OrderedMap {
+	return &OrderedMap{values: make(map[string]interface{})}
+}
+
+// Set sets a key, appending it if it is new
+func (m *OrderedMap) Set(key string, value interface{}) {
+	if _, exists := m.values[key]; !exists {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// Get returns the value of a key
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+// Keys returns the keys in order
+func (m *OrderedMap) Keys() []string {
+	return m.keys
+}
+
+// MarshalJSON implements json.Marshaler
+func (m *OrderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Execute parses, validates and executes a query against the schema
+func (s *Schema) Execute(ctx context.Context, params Params) *Result {
+	doc, err := Parse(params.Query)
+	if err != nil {
+		return &Result{Errors: []*Error{asError(err)}}
+	}
+
+	op, err := selectOperation(doc, params.OperationName)
+	if err != nil {
+		return &Result{Errors: []*Error{asError(err)}}
+	}
+
+	maxDepth := s.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	v := &validator{doc: doc, op: op, maxDepth: maxDepth, variables: make(map[string]*VariableDefinition)}
+	v.validate(s.Query)
+	if len(v.errors) > 0 {
+		return &Result{Errors: v.errors}
+	}
+
+	variables, err := coerceVariables(op, params.Variables)
+	if err != nil {
+		return &Result{Errors: []*Error{asError(err)}}
+	}
+
+	e := &executor{doc: doc, variables: variables}
+	data := e.executeSelectionSet(ctx, s.Query, nil, op.SelectionSet, nil)
+	return &Result{Data: data, Errors: e.errors}
+}
+
+func asError(err error) *Error {
+	if e, ok := err.(*Error); ok {
+		return e
+	}
+	return &Error{Message: err.Error()}
+}
+
+// selectOperation picks the operation to run; the name is required when the document has several
+func selectOperation(doc *Document, name string) (*Operation, error) {
+	if name == "" {
+		if len(doc.Operations) > 1 {
+			return nil, &Error{Message: "Must provide operation name if query contains multiple operations"}
+		}
+		return doc.Operations[0], nil
+	}
+
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, &Error{Message: fmt.Sprintf("Unknown operation named %q", name)}
+}
+
+// coerceVariables applies defaults and converts the request variables to the declared types
+func coerceVariables(op *Operation, input map[string]interface{}) (map[string]interface{}, error) {
+	variables := make(map[string]interface{}, len(op.Variables))
+	for _, definition := range op.Variables {
+		scalar := scalarsByName[definition.Type]
+
+		raw, provided := input[definition.Name]
+		if !provided && definition.Default != nil {
+			raw, provided = definition.Default, true
+		}
+		if raw == nil {
+			if definition.NonNull {
+				return nil, newError(fmt.Sprintf("Variable \"$%s\" of required type %q was not provided", definition.Name, typeName(definition)), definition.Location)
+			}
+			if provided {
+				variables[definition.Name] = nil
+			}
+			continue
+		}
+
+		value, err := coerceInput(scalar, definition.List, raw)
+		if err != nil {
+			return nil, newError(fmt.Sprintf("Variable \"$%s\" got invalid value: %s", definition.Name, err), definition.Location)
+		}
+		variables[definition.Name] = value
+	}
+	return variables, nil
+}
+
+// coerceInput converts a literal or JSON value to a scalar, or to a list of scalars
+func coerceInput(scalar *Scalar, list bool, raw interface{}) (interface{}, error) {
+	if !list {
+		return scalar.ParseValue(raw)
+	}
+
+	var items []interface{}
+	switch v := raw.(type) {
+	case []interface{}:
+		items = v
+	case ListValue:
+		items = []interface{}(v)
+	default:
+		// A single value is accepted where a list is expected
+		items = []interface{}{v}
+	}
+
+	values := make([]interface{}, len(items))
+	for i, item := range items {
+		value, err := scalar.ParseValue(item)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func typeName(definition *VariableDefinition) string {
+	name := definition.Type
+	if definition.List {
+		name = "[" + name + "]"
+	}
+	if definition.NonNull {
+		name += "!"
+	}
+	return name
+}
+
+// validator checks an operation against the schema before anything is resolved
+type validator struct {
+	doc       *Document
+	op        *Operation
+	maxDepth  int
+	variables map[string]*VariableDefinition
+	errors    []*Error
+}
+
+func (v *validator) errorf(location Location, format string, args ...interface{}) {
+	v.errors = append(v.errors, newError(fmt.Sprintf(format, args...), location))
+}
+
+func (v *validator) validate(query *Object) {
+	for _, definition := range v.op.Variables {
+		if _, exists := v.variables[definition.Name]; exists {
+			v.errorf(definition.Location, "There can be only one variable named \"$%s\"", definition.Name)
+		}
+		v.variables[definition.Name] = definition
+		if _, ok := scalarsByName[definition.Type]; !ok {
+			v.errorf(definition.Location, "Unknown type %q", definition.Type)
+		}
+	}
+
+	v.validateSelectionSet(query, v.op.SelectionSet, 1, map[string]bool{})
+}
+
+// validateSelectionSet validates selections on an object. visiting holds the fragments being
+// expanded so that fragment cycles are reported instead of recursing forever.
+func (v *validator) validateSelectionSet(object *Object, selections []Selection, depth int, visiting map[string]bool) {
+	for _, selection := range selections {
+		switch sel := selection.(type) {
+		case *FieldSelection:
+			v.validateDirectives(sel.Directives)
+			v.validateField(object, sel, depth, visiting)
+		case *FragmentSpread:
+			v.validateDirectives(sel.Directives)
+			fragment, ok := v.doc.Fragments[sel.Name]
+			if !ok {
+				v.errorf(sel.Location, "Unknown fragment %q", sel.Name)
+				continue
+			}
+			if visiting[sel.Name] {
+				v.errorf(sel.Location, "Cannot spread fragment %q within itself", sel.Name)
+				continue
+			}
+			if !v.validateTypeCondition(object, fragment.TypeCondition, sel.Location) {
+				continue
+			}
+			visiting[sel.Name] = true
+			v.validateSelectionSet(object, fragment.SelectionSet, depth, visiting)
+			delete(visiting, sel.Name)
+		case *InlineFragment:
+			v.validateDirectives(sel.Directives)
+			if sel.TypeCondition != "" && !v.validateTypeCondition(object, sel.TypeCondition, sel.Location) {
+				continue
+			}
+			v.validateSelectionSet(object, sel.SelectionSet, depth, visiting)
+		}
+	}
+}
+
+// validateTypeCondition checks a fragment applies to the object. The schema has no interfaces or
+// unions, so the condition must name the object itself.
+func (v *validator) validateTypeCondition(object *Object, condition string, location Location) bool {
+	if condition != object.Name {
+		v.errorf(location, "Fragment on %q cannot be spread on type %q", condition, object.Name)
+		return false
+	}
+	return true
+}
+
+func (v *validator) validateField(object *Object, field *FieldSelection, depth int, visiting map[string]bool) {
+	if field.Name == "__typename" {
+		if len(field.Arguments) > 0 || len(field.SelectionSet) > 0 {
+			v.errorf(field.Location, "Field \"__typename\" takes no arguments or selections")
+		}
+		return
+	}
+
+	definition, ok := object.Fields[field.Name]
+	if !ok {
+		v.errorf(field.Location, "Cannot query field %q on type %q", field.Name, object.Name)
+		return
+	}
+	if depth > v.maxDepth {
+		v.errorf(field.Location, "Query exceeds the maximum depth of %d", v.maxDepth)
+		return
+	}
+
+	v.validateArguments(field.Name, definition.Args, field.Arguments, field.Location)
+
+	switch t := namedType(definition.Type).(type) {
+	case *Scalar:
+		if len(field.SelectionSet) > 0 {
+			v.errorf(field.Location, "Field %q must not have a selection since type %q has no subfields", field.Name, definition.Type)
+		}
+	case *Object:
+		if len(field.SelectionSet) == 0 {
+			v.errorf(field.Location, "Field %q of type %q must have a selection of subfields", field.Name, definition.Type)
+			return
+		}
+		v.validateSelectionSet(t, field.SelectionSet, depth+1, visiting)
+	}
+}
+
+func (v *validator) validateArguments(owner string, definitions map[string]*ArgumentDefinition, arguments []*Argument, location Location) {
+	seen := make(map[string]bool, len(arguments))
+	for _, argument := range arguments {
+		definition, ok := definitions[argument.Name]
+		if !ok {
+			v.errorf(argument.Location, "Unknown argument %q on %q", argument.Name, owner)
+			continue
+		}
+		if seen[argument.Name] {
+			v.errorf(argument.Location, "There can be only one argument named %q", argument.Name)
+			continue
+		}
+		seen[argument.Name] = true
+		v.validateValue(argument, definition)
+	}
+
+	for name, definition := range definitions {
+		if definition.Required && !seen[name] {
+			v.errorf(location, "Argument %q of type \"%s!\" is required on %q", name, definition.Type, owner)
+		}
+	}
+}
+
+func (v *validator) validateValue(argument *Argument, definition *ArgumentDefinition) {
+	if variable, ok := argument.Value.(*Variable); ok {
+		declared, ok := v.variables[variable.Name]
+		if !ok {
+			v.errorf(argument.Location, "Variable \"$%s\" is not defined", variable.Name)
+			return
+		}
+		if declared.List || declared.Type != definition.Type.Name {
+			v.errorf(argument.Location, "Variable \"$%s\" of type %q used in position expecting %q", variable.Name, typeName(declared), definition.Type.Name)
+		}
+		return
+	}
+
+	if argument.Value == nil {
+		if definition.Required {
+			v.errorf(argument.Location, "Argument %q of type \"%s!\" must not be null", argument.Name, definition.Type)
+		}
+		return
+	}
+	if _, err := definition.Type.ParseValue(argument.Value); err != nil {
+		v.errorf(argument.Location, "Argument %q has invalid value: %s", argument.Name, err)
+	}
+}
+
+func (v *validator) validateDirectives(directives []*Directive) {
+	for _, directive := range directives {
+		if directive.Name != "skip" && directive.Name != "include" {
+			v.errorf(directive.Location, "Unknown directive \"@%s\"", directive.Name)
+			continue
+		}
+		v.validateArguments("@"+directive.Name, conditionArgs, directive.Arguments, directive.Location)
+	}
+}
+
+// conditionArgs are the arguments of @skip and @include
+var conditionArgs = map[string]*ArgumentDefinition{
+	"if": {Type: Boolean, Required: true},
+}
+
+// namedType unwraps lists
+func namedType(t Type) Type {
+	for {
+		list, ok := t.(List)
+		if !ok {
+			return t
+		}
+		t = list.Of
+	}
+}
+
+// executor resolves a validated operation
+type executor struct {
+	doc       *Document
+	variables map[string]interface{}
+	errors    []*Error
+}
+
+func (e *executor) addError(err error, location Location, path []interface{}) {
+	e.errors = append(e.errors, &Error{
+		Message:   err.Error(),
+		Locations: []Location{location},
+		Path:      path,
+	})
+}
+
+func (e *executor) executeSelectionSet(ctx context.Context, object *Object, source interface{}, selections []Selection, path []interface{}) *OrderedMap {
+	result := newOrderedMap()
+	for _, group := range e.collectFields(selections, map[string]bool{}) {
+		result.Set(group.key, e.executeField(ctx, object, source, group.fields, appendPath(path, group.key)))
+	}
+	return result
+}
+
+// fieldGroup is every selection of one response key, merged as the spec requires
+type fieldGroup struct {
+	key    string
+	fields []*FieldSelection
+}
+
+// collectFields flattens fragments and applies @skip and @include, grouping fields by response key
+func (e *executor) collectFields(selections []Selection, visited map[string]bool) []*fieldGroup {
+	var groups []*fieldGroup
+	index := make(map[string]*fieldGroup)
+
+	var collect func(selections []Selection)
+	collect = func(selections []Selection) {
+		for _, selection := range selections {
+			switch sel := selection.(type) {
+			case *FieldSelection:
+				if !e.included(sel.Directives) {
+					continue
+				}
+				key := sel.ResponseKey()
+				group, ok := index[key]
+				if !ok {
+					group = &fieldGroup{key: key}
+					index[key] = group
+					groups = append(groups, group)
+				}
+				group.fields = append(group.fields, sel)
+			case *FragmentSpread:
+				if visited[sel.Name] || !e.included(sel.Directives) {
+					continue
+				}
+				visited[sel.Name] = true
+				collect(e.doc.Fragments[sel.Name].SelectionSet)
+			case *InlineFragment:
+				if e.included(sel.Directives) {
+					collect(sel.SelectionSet)
+				}
+			}
+		}
+	}
+	collect(selections)
+
+	return groups
+}
+
+// included evaluates @skip(if:) and @include(if:)
+func (e *executor) included(directives []*Directive) bool {
+	for _, directive := range directives {
+		condition, _ := e.argumentValues(conditionArgs, directive.Arguments)["if"].(bool)
+		if directive.Name == "skip" && condition {
+			return false
+		}
+		if directive.Name == "include" && !condition {
+			return false
+		}
+	}
+	return true
+}
+
+func (e *executor) executeField(ctx context.Context, object *Object, source interface{}, fields []*FieldSelection, path []interface{}) interface{} {
+	field := fields[0]
+	if field.Name == "__typename" {
+		return object.Name
+	}
+
+	definition := object.Fields[field.Name]
+	args := e.argumentValues(definition.Args, field.Arguments)
+	for name, argument := range definition.Args {
+		if argument.Required && args[name] == nil {
+			e.addError(fmt.Errorf("argument %q of type \"%s!\" must not be null", name, argument.Type), field.Location, path)
+			return nil
+		}
+	}
+
+	var value interface{}
+	var err error
+	if definition.Resolve != nil {
+		value, err = definition.Resolve(ResolveParams{Context: ctx, Source: source, Args: args})
+	} else {
+		value, err = defaultResolve(source, field.Name)
+	}
+	if err != nil {
+		e.addError(err, field.Location, path)
+		return nil
+	}
+
+	return e.completeValue(ctx, definition.Type, fields, value, path)
+}
+
+// argumentValues resolves variables and applies defaults. Values were checked by the validator.
+func (e *executor) argumentValues(definitions map[string]*ArgumentDefinition, arguments []*Argument) map[string]interface{} {
+	args := make(map[string]interface{}, len(definitions))
+	for name, definition := range definitions {
+		if definition.Default != nil {
+			args[name] = definition.Default
+		}
+	}
+
+	for _, argument := range arguments {
+		definition := definitions[argument.Name]
+		if variable, ok := argument.Value.(*Variable); ok {
+			value, provided := e.variables[variable.Name]
+			if provided && value != nil {
+				args[argument.Name] = value
+			} else if provided {
+				args[argument.Name] = nil
+			}
+			continue
+		}
+		if argument.Value == nil {
+			args[argument.Name] = nil
+			continue
+		}
+		args[argument.Name], _ = definition.Type.ParseValue(argument.Value)
+	}
+	return args
+}
+
+func (e *executor) completeValue(ctx context.Context, t Type, fields []*FieldSelection, value interface{}, path []interface{}) interface{} {
+	if isNil(value) {
+		// Repositories return nil slices when nothing matches; those are empty lists, not null
+		if _, ok := t.(List); ok && value != nil && reflect.ValueOf(value).Kind() == reflect.Slice {
+			return []interface{}{}
+		}
+		return nil
+	}
+
+	switch t := t.(type) {
+	case *Scalar:
+		serialized, err := t.Serialize(indirect(value))
+		if err != nil {
+			e.addError(err, fields[0].Location, path)
+			return nil
+		}
+		return serialized
+	case *Object:
+		var selections []Selection
+		for _, field := range fields {
+			selections = append(selections, field.SelectionSet...)
+		}
+		return e.executeSelectionSet(ctx, t, value, selections, path)
+	case List:
+		items := reflect.ValueOf(indirect(value))
+		if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+			e.addError(fmt.Errorf("expected a list for %s", t), fields[0].Location, path)
+			return nil
+		}
+		completed := make([]interface{}, items.Len())
+		for i := range completed {
+			completed[i] = e.completeValue(ctx, t.Of, fields, items.Index(i).Interface(), appendPath(path, i))
+		}
+		return completed
+	}
+	return nil
+}
+
+// isNil reports whether a resolved value is null, including nil pointers, slices and maps
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+		return v.IsNil()
+	}
+	return false
+}
+
+// indirect follows pointers so scalars such as *string and *time.Time serialize like their values
+func indirect(value interface{}) interface{} {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+// defaultResolve reads the struct field whose json tag matches the snake_case field name
+func defaultResolve(source interface{}, name string) (interface{}, error) {
+	v := reflect.ValueOf(source)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot resolve field %q on %T", name, source)
+	}
+
+	tag := snakeCase(name)
+	for i := 0; i < v.NumField(); i++ {
+		jsonName, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		if jsonName == tag {
+			return v.Field(i).Interface(), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot resolve field %q on %T", name, source)
+}
+
+// snakeCase converts a camelCase field name such as "traceId" to "trace_id"
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func appendPath(path []interface{}, segment interface{}) []interface{} {
+	extended := make([]interface{}, len(path), len(path)+1)
+	copy(extended, path)
+	return append(extended, segment)
+}
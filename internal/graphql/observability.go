@@ -0,0 +1,466 @@
+package graphql
+
+import (
+	"fmt"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/redaction"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Page size bounds for list fields, matching the REST endpoints
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// tripEntityType is the event log entity type of trip events
+const tripEntityType = "trip"
+
+// NewObservabilitySchema builds the schema served at /api/v1/graphql. Actor instances, messages,
+// spans, events and trips can be fetched at the top level and resolved through their relations:
+// trip → events → messages → spans, and actor instance → sent/received messages.
+// Message payloads and event data are redacted with redactor for callers without the
+// operator role; redactor may be nil.
+func NewObservabilitySchema(obsRepo repository.ObservabilityRepository, tripRepo repository.TripRepository, redactor *redaction.Redactor) *Schema {
+	r := &resolver{obsRepo: obsRepo, tripRepo: tripRepo, redactor: redactor}
+
+	actorInstance := &Object{Name: "ActorInstance"}
+	actorMessage := &Object{Name: "ActorMessage"}
+	span := &Object{Name: "Span"}
+	event := &Object{Name: "Event"}
+	trip := &Object{Name: "Trip"}
+
+	actorInstance.Fields = map[string]*Field{
+		"id":            {Type: ID},
+		"actorType":     {Type: String},
+		"actorId":       {Type: String},
+		"entityType":    {Type: String},
+		"entityId":      {Type: ID},
+		"status":        {Type: String},
+		"lastHeartbeat": {Type: DateTime},
+		"createdAt":     {Type: DateTime},
+		"updatedAt":     {Type: DateTime},
+		"sentMessages": {
+			Type:        List{Of: actorMessage},
+			Description: "Messages sent by the actor, newest first",
+			Args:        pageArgs(),
+			Resolve:     r.sentMessages,
+		},
+		"receivedMessages": {
+			Type:        List{Of: actorMessage},
+			Description: "Messages received by the actor, newest first",
+			Args:        pageArgs(),
+			Resolve:     r.receivedMessages,
+		},
+	}
+
+	actorMessage.Fields = map[string]*Field{
+		"id":                   {Type: ID},
+		"traceId":              {Type: ID},
+		"spanId":               {Type: ID},
+		"parentSpanId":         {Type: ID},
+		"senderActorType":      {Type: String},
+		"senderActorId":        {Type: String},
+		"receiverActorType":    {Type: String},
+		"receiverActorId":      {Type: String},
+		"entityType":           {Type: String},
+		"entityId":             {Type: ID},
+		"messageType":          {Type: String},
+		"messagePayload":       {Type: JSON, Resolve: r.messagePayload},
+		"status":               {Type: String},
+		"sentAt":               {Type: DateTime},
+		"receivedAt":           {Type: DateTime},
+		"processedAt":          {Type: DateTime},
+		"processingDurationMs": {Type: Int},
+		"errorMessage":         {Type: String},
+		"retryCount":           {Type: Int},
+		"effects":              {Type: JSON, Description: "The entities changed while the message was processed"},
+		"createdAt":            {Type: DateTime},
+		"spans": {
+			Type:        List{Of: span},
+			Description: "The message's span and its child spans, in start order",
+			Resolve:     r.messageSpans,
+		},
+	}
+
+	span.Fields = map[string]*Field{
+		"id":            {Type: ID},
+		"traceId":       {Type: ID},
+		"spanId":        {Type: ID},
+		"parentSpanId":  {Type: ID},
+		"operationName": {Type: String},
+		"actorType":     {Type: String},
+		"actorId":       {Type: String},
+		"startTime":     {Type: DateTime},
+		"endTime":       {Type: DateTime},
+		"durationMs":    {Type: Int},
+		"status":        {Type: String},
+		"tags":          {Type: JSON},
+		"logs":          {Type: JSON},
+		"createdAt":     {Type: DateTime},
+	}
+
+	event.Fields = map[string]*Field{
+		"id":            {Type: ID},
+		"traceId":       {Type: ID},
+		"eventType":     {Type: String},
+		"eventCategory": {Type: String},
+		"actorType":     {Type: String},
+		"actorId":       {Type: String},
+		"entityType":    {Type: String},
+		"entityId":      {Type: ID},
+		"eventData":     {Type: JSON, Resolve: r.eventData},
+		"severity":      {Type: String},
+		"message":       {Type: String},
+		"timestamp":     {Type: DateTime},
+		"createdAt":     {Type: DateTime},
+		"messages": {
+			Type:        List{Of: actorMessage},
+			Description: "Messages of the event's trace, in send order",
+			Resolve:     r.eventMessages,
+		},
+	}
+
+	trip.Fields = map[string]*Field{
+		"id":                   {Type: ID},
+		"passengerId":          {Type: ID},
+		"driverId":             {Type: ID},
+		"pickupLatitude":       {Type: Float},
+		"pickupLongitude":      {Type: Float},
+		"pickupAddress":        {Type: String},
+		"destinationLatitude":  {Type: Float},
+		"destinationLongitude": {Type: Float},
+		"destinationAddress":   {Type: String},
+		"status":               {Type: String},
+		"fareAmount":           {Type: Float},
+		"distanceKm":           {Type: Float},
+		"durationMinutes":      {Type: Int},
+		"requestedAt":          {Type: DateTime},
+		"matchedAt":            {Type: DateTime},
+		"acceptedAt":           {Type: DateTime},
+		"pickupAt":             {Type: DateTime},
+		"completedAt":          {Type: DateTime},
+		"cancelledAt":          {Type: DateTime},
+		"createdAt":            {Type: DateTime},
+		"updatedAt":            {Type: DateTime},
+		"events": {
+			Type:        List{Of: event},
+			Description: "Events recorded for the trip, newest first",
+			Args:        pageArgs(),
+			Resolve:     r.tripEvents,
+		},
+	}
+
+	query := &Object{
+		Name: "Query",
+		Fields: map[string]*Field{
+			"actorInstances": {
+				Type: List{Of: actorInstance},
+				Args: pageArgs(map[string]*ArgumentDefinition{
+					"actorType": {Type: String},
+				}),
+				Resolve: r.actorInstances,
+			},
+			"actorInstance": {
+				Type:    actorInstance,
+				Args:    idArgs("id"),
+				Resolve: r.actorInstance,
+			},
+			"actorMessages": {
+				Type: List{Of: actorMessage},
+				Args: pageArgs(map[string]*ArgumentDefinition{
+					"fromActor": {Type: String},
+					"toActor":   {Type: String},
+					"traceId":   {Type: ID},
+				}),
+				Resolve: r.actorMessages,
+			},
+			"actorMessage": {
+				Type:    actorMessage,
+				Args:    idArgs("id"),
+				Resolve: r.actorMessage,
+			},
+			"traces": {
+				Type: List{Of: span},
+				Args: pageArgs(map[string]*ArgumentDefinition{
+					"operation": {Type: String},
+				}),
+				Resolve: r.traces,
+			},
+			"trace": {
+				Type:        List{Of: span},
+				Description: "All spans of a trace, in start order",
+				Args:        idArgs("traceId"),
+				Resolve:     r.trace,
+			},
+			"events": {
+				Type: List{Of: event},
+				Args: pageArgs(map[string]*ArgumentDefinition{
+					"eventType": {Type: String},
+					"source":    {Type: String},
+				}),
+				Resolve: r.events,
+			},
+			"event": {
+				Type:    event,
+				Args:    idArgs("id"),
+				Resolve: r.event,
+			},
+			"trips": {
+				Type: List{Of: trip},
+				Args: pageArgs(map[string]*ArgumentDefinition{
+					"status": {Type: String},
+				}),
+				Resolve: r.trips,
+			},
+			"trip": {
+				Type:    trip,
+				Args:    idArgs("id"),
+				Resolve: r.trip,
+			},
+		},
+	}
+
+	return &Schema{Query: query}
+}
+
+// pageArgs returns the limit and offset arguments merged with any extra arguments
+func pageArgs(extra ...map[string]*ArgumentDefinition) map[string]*ArgumentDefinition {
+	args := map[string]*ArgumentDefinition{
+		"limit":  {Type: Int, Default: defaultPageSize},
+		"offset": {Type: Int, Default: 0},
+	}
+	for _, e := range extra {
+		for name, arg := range e {
+			args[name] = arg
+		}
+	}
+	return args
+}
+
+// idArgs returns a single required ID argument
+func idArgs(name string) map[string]*ArgumentDefinition {
+	return map[string]*ArgumentDefinition{
+		name: {Type: ID, Required: true},
+	}
+}
+
+// resolver resolves the observability schema from the repositories
+type resolver struct {
+	obsRepo  repository.ObservabilityRepository
+	tripRepo repository.TripRepository
+	redactor *redaction.Redactor
+}
+
+func (r *resolver) messagePayload(p ResolveParams) (interface{}, error) {
+	message := p.Source.(*models.ActorMessage)
+	if redaction.IsOperator(p.Context) {
+		return message.MessagePayload, nil
+	}
+	return r.redactor.JSON(message.MessagePayload), nil
+}
+
+func (r *resolver) eventData(p ResolveParams) (interface{}, error) {
+	return r.redactor.EventLog(p.Context, p.Source.(*models.EventLog)).EventData, nil
+}
+
+func (r *resolver) actorInstances(p ResolveParams) (interface{}, error) {
+	limit, offset, err := page(p)
+	if err != nil {
+		return nil, err
+	}
+	actorType, _ := p.Args["actorType"].(string)
+	return r.obsRepo.ListActorInstances(p.Context, actorType, limit, offset)
+}
+
+func (r *resolver) actorInstance(p ResolveParams) (interface{}, error) {
+	id, err := uuidArg(p, "id")
+	if err != nil {
+		return nil, err
+	}
+	return nullIfNotFound(r.obsRepo.GetActorInstance(p.Context, id))
+}
+
+func (r *resolver) sentMessages(p ResolveParams) (interface{}, error) {
+	limit, offset, err := page(p)
+	if err != nil {
+		return nil, err
+	}
+	return r.obsRepo.ListActorMessages(p.Context, p.Source.(*models.ActorInstance).ActorID, "", limit, offset)
+}
+
+func (r *resolver) receivedMessages(p ResolveParams) (interface{}, error) {
+	limit, offset, err := page(p)
+	if err != nil {
+		return nil, err
+	}
+	return r.obsRepo.ListActorMessages(p.Context, "", p.Source.(*models.ActorInstance).ActorID, limit, offset)
+}
+
+func (r *resolver) actorMessages(p ResolveParams) (interface{}, error) {
+	limit, offset, err := page(p)
+	if err != nil {
+		return nil, err
+	}
+	fromActor, _ := p.Args["fromActor"].(string)
+	toActor, _ := p.Args["toActor"].(string)
+
+	traceID, ok := p.Args["traceId"].(string)
+	if !ok {
+		return r.obsRepo.ListActorMessages(p.Context, fromActor, toActor, limit, offset)
+	}
+
+	if _, err := uuid.Parse(traceID); err != nil {
+		return nil, fmt.Errorf("traceId must be a valid UUID")
+	}
+	messages, err := r.obsRepo.GetMessagesByTraceID(p.Context, traceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A trace holds few messages, so the actor filters and paging are applied here
+	var filtered []*models.ActorMessage
+	for _, m := range messages {
+		if (fromActor == "" || m.SenderActorID == fromActor) && (toActor == "" || m.ReceiverActorID == toActor) {
+			filtered = append(filtered, m)
+		}
+	}
+	if offset >= len(filtered) {
+		return []*models.ActorMessage{}, nil
+	}
+	filtered = filtered[offset:]
+	if len(filtered) > limit {
+		filtered = filtered[:limit]
+	}
+	return filtered, nil
+}
+
+func (r *resolver) actorMessage(p ResolveParams) (interface{}, error) {
+	id, err := uuidArg(p, "id")
+	if err != nil {
+		return nil, err
+	}
+	return nullIfNotFound(r.obsRepo.GetActorMessage(p.Context, id))
+}
+
+func (r *resolver) messageSpans(p ResolveParams) (interface{}, error) {
+	message := p.Source.(*models.ActorMessage)
+	spans, err := r.obsRepo.GetTracesByTraceID(p.Context, message.TraceID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	related := []*models.DistributedTrace{}
+	for _, s := range spans {
+		if s.SpanID == message.SpanID || (s.ParentSpanID != nil && *s.ParentSpanID == message.SpanID) {
+			related = append(related, s)
+		}
+	}
+	return related, nil
+}
+
+func (r *resolver) traces(p ResolveParams) (interface{}, error) {
+	limit, offset, err := page(p)
+	if err != nil {
+		return nil, err
+	}
+	operation, _ := p.Args["operation"].(string)
+	return r.obsRepo.ListDistributedTraces(p.Context, operation, limit, offset)
+}
+
+func (r *resolver) trace(p ResolveParams) (interface{}, error) {
+	traceID, err := uuidArg(p, "traceId")
+	if err != nil {
+		return nil, err
+	}
+	return r.obsRepo.GetTracesByTraceID(p.Context, traceID)
+}
+
+func (r *resolver) events(p ResolveParams) (interface{}, error) {
+	limit, offset, err := page(p)
+	if err != nil {
+		return nil, err
+	}
+	eventType, _ := p.Args["eventType"].(string)
+	source, _ := p.Args["source"].(string)
+	return r.obsRepo.ListEventLogs(p.Context, eventType, source, limit, offset)
+}
+
+func (r *resolver) event(p ResolveParams) (interface{}, error) {
+	id, err := uuidArg(p, "id")
+	if err != nil {
+		return nil, err
+	}
+	return nullIfNotFound(r.obsRepo.GetEventLog(p.Context, id))
+}
+
+func (r *resolver) eventMessages(p ResolveParams) (interface{}, error) {
+	event := p.Source.(*models.EventLog)
+	if event.TraceID == nil {
+		return []*models.ActorMessage{}, nil
+	}
+	return r.obsRepo.GetMessagesByTraceID(p.Context, event.TraceID.String())
+}
+
+func (r *resolver) trips(p ResolveParams) (interface{}, error) {
+	limit, offset, err := page(p)
+	if err != nil {
+		return nil, err
+	}
+	if status, ok := p.Args["status"].(string); ok {
+		return r.tripRepo.GetTripsByStatus(p.Context, models.TripStatus(status), limit, offset)
+	}
+	return r.tripRepo.List(p.Context, limit, offset)
+}
+
+func (r *resolver) trip(p ResolveParams) (interface{}, error) {
+	id, err := uuidArg(p, "id")
+	if err != nil {
+		return nil, err
+	}
+	return nullIfNotFound(r.tripRepo.GetByID(p.Context, id))
+}
+
+func (r *resolver) tripEvents(p ResolveParams) (interface{}, error) {
+	limit, offset, err := page(p)
+	if err != nil {
+		return nil, err
+	}
+	return r.obsRepo.ListEventLogsByEntity(p.Context, tripEntityType, p.Source.(*models.Trip).ID.String(), limit, offset)
+}
+
+// page returns the limit and offset arguments, rejecting values outside the REST bounds
+func page(p ResolveParams) (int, int, error) {
+	limit, _ := p.Args["limit"].(int)
+	offset, _ := p.Args["offset"].(int)
+	if limit <= 0 || limit > maxPageSize {
+		return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+	}
+	if offset < 0 {
+		return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+	}
+	return limit, offset, nil
+}
+
+// uuidArg returns an ID argument, rejecting values that are not UUIDs before they reach the database
+func uuidArg(p ResolveParams, name string) (string, error) {
+	id, _ := p.Args[name].(string)
+	if _, err := uuid.Parse(id); err != nil {
+		return "", fmt.Errorf("%s must be a valid UUID", name)
+	}
+	return id, nil
+}
+
+// nullIfNotFound resolves missing records to null rather than an error
+func nullIfNotFound(value interface{}, err error) (interface{}, error) {
+	if err != nil {
+		if _, ok := err.(*models.NotFoundError); ok {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,828 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Document is a parsed GraphQL request document
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation is a query in a document. Mutations and subscriptions are rejected by the parser.
+type Operation struct {
+	Name         string
+	Variables    []*VariableDefinition
+	SelectionSet []Selection
+	Location     Location
+}
+
+// VariableDefinition declares a variable of an operation
+type VariableDefinition struct {
+	Name     string
+	Type     string // named type, without list or non-null wrappers
+	List     bool
+	NonNull  bool
+	Default  Value
+	Location Location
+}
+
+// Fragment is a named fragment definition
+type Fragment struct {
+	Name          string
+	TypeCondition string
+	SelectionSet  []Selection
+	Location      Location
+}
+
+// Selection is a *FieldSelection, *FragmentSpread or *InlineFragment
+type Selection interface {
+	selection()
+}
+
+// FieldSelection selects a field, optionally under an alias
+type FieldSelection struct {
+	Alias        string
+	Name         string
+	Arguments    []*Argument
+	Directives   []*Directive
+	SelectionSet []Selection
+	Location     Location
+}
+
+// ResponseKey returns the key the field is written under in the result
+func (f *FieldSelection) ResponseKey() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// FragmentSpread includes a named fragment
+type FragmentSpread struct {
+	Name       string
+	Directives []*Directive
+	Location   Location
+}
+
+// InlineFragment includes a selection set, optionally restricted to a type
+type InlineFragment struct {
+	TypeCondition string
+	Directives    []*Directive
+	SelectionSet  []Selection
+	Location      Location
+}
+
+func (*FieldSelection) selection() {}
+func (*FragmentSpread) selection() {}
+func (*InlineFragment) selection() {}
+
+// Argument is a named argument of a field or directive
+type Argument struct {
+	Name     string
+	Value    Value
+	Location Location
+}
+
+// Directive is a directive such as @include(if: $flag)
+type Directive struct {
+	Name      string
+	Arguments []*Argument
+	Location  Location
+}
+
+// Value is a literal or variable in a document. Scalars are represented as Go values
+// (int64, float64, string, bool, nil); Enum values are represented as strings.
+type Value = interface{}
+
+// Variable references an operation variable
+type Variable struct {
+	Name string
+}
+
+// ListValue is a list literal
+type ListValue []Value
+
+// ObjectValue is an input object literal
+type ObjectValue map[string]Value
+
+// Location is a 1-based line and column in the query
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Parse parses a GraphQL request document
+func Parse(query string) (*Document, error) {
+	p := &parser{lexer: lexer{source: query}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &Document{Fragments: make(map[string]*Fragment)}
+	for p.token.kind != tokenEOF {
+		switch {
+		case p.peek(tokenPunct, "{"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.peek(tokenName, "query"):
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.Operations = append(doc.Operations, op)
+		case p.peek(tokenName, "mutation"), p.peek(tokenName, "subscription"):
+			return nil, p.errorf("%s operations are not supported", p.token.value)
+		case p.peek(tokenName, "fragment"):
+			fragment, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			if _, exists := doc.Fragments[fragment.Name]; exists {
+				return nil, newError(fmt.Sprintf("There can be only one fragment named %q", fragment.Name), fragment.Location)
+			}
+			doc.Fragments[fragment.Name] = fragment
+		default:
+			return nil, p.unexpected()
+		}
+	}
+
+	if len(doc.Operations) == 0 {
+		return nil, newError("Document does not contain an operation", Location{Line: 1, Column: 1})
+	}
+	return doc, nil
+}
+
+type parser struct {
+	lexer lexer
+	token token
+}
+
+func (p *parser) advance() error {
+	t, err := p.lexer.next()
+	if err != nil {
+		return err
+	}
+	p.token = t
+	return nil
+}
+
+// peek reports whether the current token has the kind and, when value is not empty, the value
+func (p *parser) peek(kind tokenKind, value string) bool {
+	return p.token.kind == kind && (value == "" || p.token.value == value)
+}
+
+// skip consumes the current token if it matches
+func (p *parser) skip(kind tokenKind, value string) (bool, error) {
+	if !p.peek(kind, value) {
+		return false, nil
+	}
+	return true, p.advance()
+}
+
+// expect consumes the current token, failing if it does not match
+func (p *parser) expect(kind tokenKind, value string) (token, error) {
+	t := p.token
+	if !p.peek(kind, value) {
+		return t, p.unexpected()
+	}
+	return t, p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	t, err := p.expect(tokenName, "")
+	return t.value, err
+}
+
+func (p *parser) location() Location {
+	return p.lexer.location(p.token.start)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return newError("Syntax Error: "+fmt.Sprintf(format, args...), p.location())
+}
+
+func (p *parser) unexpected() error {
+	if p.token.kind == tokenEOF {
+		return p.errorf("Unexpected end of document")
+	}
+	return p.errorf("Unexpected %q", p.token.value)
+}
+
+func (p *parser) parseOperation() (*Operation, error) {
+	op := &Operation{Location: p.location()}
+	if p.peek(tokenPunct, "{") {
+		selections, err := p.parseSelectionSet()
+		op.SelectionSet = selections
+		return op, err
+	}
+
+	if _, err := p.expect(tokenName, "query"); err != nil {
+		return nil, err
+	}
+	if p.peek(tokenName, "") {
+		op.Name = p.token.value
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if p.peek(tokenPunct, "(") {
+		variables, err := p.parseVariableDefinitions()
+		if err != nil {
+			return nil, err
+		}
+		op.Variables = variables
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	selections, err := p.parseSelectionSet()
+	op.SelectionSet = selections
+	return op, err
+}
+
+func (p *parser) parseVariableDefinitions() ([]*VariableDefinition, error) {
+	if _, err := p.expect(tokenPunct, "("); err != nil {
+		return nil, err
+	}
+
+	var definitions []*VariableDefinition
+	for {
+		if ok, err := p.skip(tokenPunct, ")"); err != nil || ok {
+			return definitions, err
+		}
+
+		definition := &VariableDefinition{Location: p.location()}
+		if _, err := p.expect(tokenPunct, "$"); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		definition.Name = name
+		if _, err := p.expect(tokenPunct, ":"); err != nil {
+			return nil, err
+		}
+		if err := p.parseType(definition); err != nil {
+			return nil, err
+		}
+		if ok, err := p.skip(tokenPunct, "="); err != nil {
+			return nil, err
+		} else if ok {
+			if definition.Default, err = p.parseValue(true); err != nil {
+				return nil, err
+			}
+		}
+		definitions = append(definitions, definition)
+	}
+}
+
+// parseType parses a variable type. Nested lists are not needed by the schema and are rejected.
+func (p *parser) parseType(definition *VariableDefinition) error {
+	if ok, err := p.skip(tokenPunct, "["); err != nil {
+		return err
+	} else if ok {
+		definition.List = true
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		definition.Type = name
+		if _, err := p.skip(tokenPunct, "!"); err != nil {
+			return err
+		}
+		if _, err := p.expect(tokenPunct, "]"); err != nil {
+			return err
+		}
+	} else {
+		name, err := p.expectName()
+		if err != nil {
+			return err
+		}
+		definition.Type = name
+	}
+
+	nonNull, err := p.skip(tokenPunct, "!")
+	definition.NonNull = nonNull
+	return err
+}
+
+func (p *parser) parseFragment() (*Fragment, error) {
+	fragment := &Fragment{Location: p.location()}
+	if _, err := p.expect(tokenName, "fragment"); err != nil {
+		return nil, err
+	}
+
+	if p.peek(tokenName, "on") {
+		return nil, p.unexpected()
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	fragment.Name = name
+
+	if _, err := p.expect(tokenName, "on"); err != nil {
+		return nil, err
+	}
+	if fragment.TypeCondition, err = p.expectName(); err != nil {
+		return nil, err
+	}
+	if _, err := p.parseDirectives(); err != nil {
+		return nil, err
+	}
+
+	fragment.SelectionSet, err = p.parseSelectionSet()
+	return fragment, err
+}
+
+func (p *parser) parseSelectionSet() ([]Selection, error) {
+	if _, err := p.expect(tokenPunct, "{"); err != nil {
+		return nil, err
+	}
+
+	var selections []Selection
+	for {
+		if ok, err := p.skip(tokenPunct, "}"); err != nil {
+			return nil, err
+		} else if ok {
+			if len(selections) == 0 {
+				return nil, newError("Syntax Error: Selection set must not be empty", p.location())
+			}
+			return selections, nil
+		}
+
+		selection, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, selection)
+	}
+}
+
+func (p *parser) parseSelection() (Selection, error) {
+	location := p.location()
+	if ok, err := p.skip(tokenPunct, "..."); err != nil {
+		return nil, err
+	} else if !ok {
+		return p.parseField()
+	}
+
+	// A name other than "on" is a fragment spread; otherwise this is an inline fragment
+	if p.peek(tokenName, "") && p.token.value != "on" {
+		spread := &FragmentSpread{Name: p.token.value, Location: location}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		directives, err := p.parseDirectives()
+		spread.Directives = directives
+		return spread, err
+	}
+
+	inline := &InlineFragment{Location: location}
+	if ok, err := p.skip(tokenName, "on"); err != nil {
+		return nil, err
+	} else if ok {
+		if inline.TypeCondition, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+	directives, err := p.parseDirectives()
+	if err != nil {
+		return nil, err
+	}
+	inline.Directives = directives
+	inline.SelectionSet, err = p.parseSelectionSet()
+	return inline, err
+}
+
+func (p *parser) parseField() (*FieldSelection, error) {
+	field := &FieldSelection{Location: p.location()}
+	name, err := p.expectName()
+	if err != nil {
+		return nil, err
+	}
+	field.Name = name
+
+	if ok, err := p.skip(tokenPunct, ":"); err != nil {
+		return nil, err
+	} else if ok {
+		field.Alias = name
+		if field.Name, err = p.expectName(); err != nil {
+			return nil, err
+		}
+	}
+
+	if field.Arguments, err = p.parseArguments(); err != nil {
+		return nil, err
+	}
+	if field.Directives, err = p.parseDirectives(); err != nil {
+		return nil, err
+	}
+	if p.peek(tokenPunct, "{") {
+		field.SelectionSet, err = p.parseSelectionSet()
+	}
+	return field, err
+}
+
+func (p *parser) parseArguments() ([]*Argument, error) {
+	if ok, err := p.skip(tokenPunct, "("); err != nil || !ok {
+		return nil, err
+	}
+
+	var arguments []*Argument
+	for {
+		if ok, err := p.skip(tokenPunct, ")"); err != nil {
+			return nil, err
+		} else if ok {
+			if len(arguments) == 0 {
+				return nil, newError("Syntax Error: Argument list must not be empty", p.location())
+			}
+			return arguments, nil
+		}
+
+		argument := &Argument{Location: p.location()}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		argument.Name = name
+		if _, err := p.expect(tokenPunct, ":"); err != nil {
+			return nil, err
+		}
+		if argument.Value, err = p.parseValue(false); err != nil {
+			return nil, err
+		}
+		arguments = append(arguments, argument)
+	}
+}
+
+func (p *parser) parseDirectives() ([]*Directive, error) {
+	var directives []*Directive
+	for p.peek(tokenPunct, "@") {
+		directive := &Directive{Location: p.location()}
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		directive.Name = name
+		if directive.Arguments, err = p.parseArguments(); err != nil {
+			return nil, err
+		}
+		directives = append(directives, directive)
+	}
+	return directives, nil
+}
+
+// parseValue parses a value literal. Variables are not allowed in constant positions such as defaults.
+func (p *parser) parseValue(constant bool) (Value, error) {
+	t := p.token
+	switch t.kind {
+	case tokenPunct:
+		switch t.value {
+		case "$":
+			if constant {
+				return nil, p.unexpected()
+			}
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			name, err := p.expectName()
+			return &Variable{Name: name}, err
+		case "[":
+			return p.parseList(constant)
+		case "{":
+			return p.parseObject(constant)
+		}
+	case tokenInt:
+		n, err := strconv.ParseInt(t.value, 10, 64)
+		if err != nil {
+			return nil, p.errorf("Invalid integer %s", t.value)
+		}
+		return n, p.advance()
+	case tokenFloat:
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, p.errorf("Invalid float %s", t.value)
+		}
+		return f, p.advance()
+	case tokenString:
+		return t.value, p.advance()
+	case tokenName:
+		var value Value = t.value
+		switch t.value {
+		case "true":
+			value = true
+		case "false":
+			value = false
+		case "null":
+			value = nil
+		}
+		return value, p.advance()
+	}
+	return nil, p.unexpected()
+}
+
+func (p *parser) parseList(constant bool) (Value, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	list := ListValue{}
+	for {
+		if ok, err := p.skip(tokenPunct, "]"); err != nil || ok {
+			return list, err
+		}
+		value, err := p.parseValue(constant)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, value)
+	}
+}
+
+func (p *parser) parseObject(constant bool) (Value, error) {
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	object := ObjectValue{}
+	for {
+		if ok, err := p.skip(tokenPunct, "}"); err != nil || ok {
+			return object, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(tokenPunct, ":"); err != nil {
+			return nil, err
+		}
+		if object[name], err = p.parseValue(constant); err != nil {
+			return nil, err
+		}
+	}
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenPunct
+	tokenName
+	tokenInt
+	tokenFloat
+	tokenString
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	start int
+}
+
+type lexer struct {
+	source string
+	pos    int
+}
+
+// location converts a byte offset into a line and column
+func (l *lexer) location(offset int) Location {
+	before := l.source[:offset]
+	line := strings.Count(before, "\n") + 1
+	column := utf8.RuneCountInString(before[strings.LastIndex(before, "\n")+1:]) + 1
+	return Location{Line: line, Column: column}
+}
+
+func (l *lexer) errorf(offset int, format string, args ...interface{}) error {
+	return newError("Syntax Error: "+fmt.Sprintf(format, args...), l.location(offset))
+}
+
+func (l *lexer) next() (token, error) {
+	l.skipIgnored()
+	start := l.pos
+	if l.pos >= len(l.source) {
+		return token{kind: tokenEOF, start: start}, nil
+	}
+
+	c := l.source[l.pos]
+	switch {
+	case strings.IndexByte("!$():=@[]{}|", c) >= 0:
+		l.pos++
+		return token{kind: tokenPunct, value: string(c), start: start}, nil
+	case c == '.':
+		if strings.HasPrefix(l.source[l.pos:], "...") {
+			l.pos += 3
+			return token{kind: tokenPunct, value: "...", start: start}, nil
+		}
+		return token{}, l.errorf(start, "Unexpected \".\"")
+	case c == '_' || isLetter(c):
+		for l.pos < len(l.source) && (l.source[l.pos] == '_' || isLetter(l.source[l.pos]) || isDigit(l.source[l.pos])) {
+			l.pos++
+		}
+		return token{kind: tokenName, value: l.source[start:l.pos], start: start}, nil
+	case c == '-' || isDigit(c):
+		return l.readNumber()
+	case c == '"':
+		if strings.HasPrefix(l.source[l.pos:], `"""`) {
+			return l.readBlockString()
+		}
+		return l.readString()
+	}
+
+	r, _ := utf8.DecodeRuneInString(l.source[l.pos:])
+	return token{}, l.errorf(start, "Unexpected character %q", r)
+}
+
+// skipIgnored skips whitespace, commas, comments and a byte order mark
+func (l *lexer) skipIgnored() {
+	for l.pos < len(l.source) {
+		switch c := l.source[l.pos]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			l.pos++
+		case c == '#':
+			for l.pos < len(l.source) && l.source[l.pos] != '\n' && l.source[l.pos] != '\r' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.source[l.pos:], "\ufeff"):
+			l.pos += len("\ufeff")
+		default:
+			return
+		}
+	}
+}
+
+func (l *lexer) readNumber() (token, error) {
+	start := l.pos
+	kind := tokenInt
+
+	if l.source[l.pos] == '-' {
+		l.pos++
+	}
+	if l.pos < len(l.source) && l.source[l.pos] == '0' {
+		l.pos++
+		if l.pos < len(l.source) && isDigit(l.source[l.pos]) {
+			return token{}, l.errorf(l.pos, "Invalid number, unexpected digit after 0")
+		}
+	} else if !l.readDigits() {
+		return token{}, l.errorf(l.pos, "Invalid number, expected digit")
+	}
+
+	if l.pos < len(l.source) && l.source[l.pos] == '.' {
+		kind = tokenFloat
+		l.pos++
+		if !l.readDigits() {
+			return token{}, l.errorf(l.pos, "Invalid number, expected digit")
+		}
+	}
+	if l.pos < len(l.source) && (l.source[l.pos] == 'e' || l.source[l.pos] == 'E') {
+		kind = tokenFloat
+		l.pos++
+		if l.pos < len(l.source) && (l.source[l.pos] == '+' || l.source[l.pos] == '-') {
+			l.pos++
+		}
+		if !l.readDigits() {
+			return token{}, l.errorf(l.pos, "Invalid number, expected digit")
+		}
+	}
+	if l.pos < len(l.source) && (l.source[l.pos] == '_' || l.source[l.pos] == '.' || isLetter(l.source[l.pos])) {
+		return token{}, l.errorf(l.pos, "Invalid number, expected digit")
+	}
+
+	return token{kind: kind, value: l.source[start:l.pos], start: start}, nil
+}
+
+func (l *lexer) readDigits() bool {
+	start := l.pos
+	for l.pos < len(l.source) && isDigit(l.source[l.pos]) {
+		l.pos++
+	}
+	return l.pos > start
+}
+
+func (l *lexer) readString() (token, error) {
+	start := l.pos
+	l.pos++
+
+	var b strings.Builder
+	for l.pos < len(l.source) {
+		c := l.source[l.pos]
+		switch {
+		case c == '"':
+			l.pos++
+			return token{kind: tokenString, value: b.String(), start: start}, nil
+		case c == '\n' || c == '\r':
+			return token{}, l.errorf(l.pos, "Unterminated string")
+		case c == '\\':
+			if l.pos+1 >= len(l.source) {
+				return token{}, l.errorf(l.pos, "Unterminated string")
+			}
+			escape := l.source[l.pos+1]
+			l.pos += 2
+			switch escape {
+			case '"', '\\', '/':
+				b.WriteByte(escape)
+			case 'b':
+				b.WriteByte('\b')
+			case 'f':
+				b.WriteByte('\f')
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case 'u':
+				if l.pos+4 > len(l.source) {
+					return token{}, l.errorf(l.pos, "Invalid unicode escape")
+				}
+				code, err := strconv.ParseUint(l.source[l.pos:l.pos+4], 16, 32)
+				if err != nil {
+					return token{}, l.errorf(l.pos, "Invalid unicode escape")
+				}
+				b.WriteRune(rune(code))
+				l.pos += 4
+			default:
+				return token{}, l.errorf(l.pos-2, "Invalid escape sequence \\%c", escape)
+			}
+		default:
+			b.WriteByte(c)
+			l.pos++
+		}
+	}
+	return token{}, l.errorf(l.pos, "Unterminated string")
+}
+
+// readBlockString reads a """block string""", removing common indentation and blank edge lines
+func (l *lexer) readBlockString() (token, error) {
+	start := l.pos
+	l.pos += 3
+
+	var raw strings.Builder
+	for l.pos < len(l.source) {
+		switch {
+		case strings.HasPrefix(l.source[l.pos:], `\"""`):
+			raw.WriteString(`"""`)
+			l.pos += 4
+		case strings.HasPrefix(l.source[l.pos:], `"""`):
+			l.pos += 3
+			return token{kind: tokenString, value: blockStringValue(raw.String()), start: start}, nil
+		default:
+			raw.WriteByte(l.source[l.pos])
+			l.pos++
+		}
+	}
+	return token{}, l.errorf(l.pos, "Unterminated string")
+}
+
+func blockStringValue(raw string) string {
+	lines := strings.Split(strings.ReplaceAll(strings.ReplaceAll(raw, "\r\n", "\n"), "\r", "\n"), "\n")
+
+	indent := -1
+	for _, line := range lines[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" {
+			continue
+		}
+		if n := len(line) - len(trimmed); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	if indent > 0 {
+		for i := 1; i < len(lines); i++ {
+			if len(lines[i]) >= indent {
+				lines[i] = lines[i][indent:]
+			} else {
+				lines[i] = strings.TrimLeft(lines[i], " \t")
+			}
+		}
+	}
+
+	for len(lines) > 0 && strings.TrimLeft(lines[0], " \t") == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimLeft(lines[len(lines)-1], " \t") == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
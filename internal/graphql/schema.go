@@ -0,0 +1,228 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+)
+
+// Type is a *Scalar, *Object or List
+type Type interface {
+	String() string
+}
+
+// Scalar is a leaf type. Serialize converts a resolved Go value into its JSON form;
+// ParseValue converts a literal or variable value into the Go value resolvers receive.
+type Scalar struct {
+	Name       string
+	Serialize  func(value interface{}) (interface{}, error)
+	ParseValue func(value interface{}) (interface{}, error)
+}
+
+func (s *Scalar) String() string { return s.Name }
+
+// Object is a type with fields. Fields is assigned after construction so objects can refer to each other.
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+func (o *Object) String() string { return o.Name }
+
+// List wraps a type whose resolved value is a slice
+type List struct {
+	Of Type
+}
+
+func (l List) String() string { return "[" + l.Of.String() + "]" }
+
+// Field is a field of an object. All fields are nullable; a resolver error nulls the field.
+// When Resolve is nil the value is read from the source struct field whose json tag is the
+// snake_case form of the field name.
+type Field struct {
+	Type        Type
+	Description string
+	Args        map[string]*ArgumentDefinition
+	Resolve     func(p ResolveParams) (interface{}, error)
+}
+
+// ArgumentDefinition declares an argument of a field
+type ArgumentDefinition struct {
+	Type     *Scalar
+	Default  interface{}
+	Required bool
+}
+
+// ResolveParams is passed to field resolvers
+type ResolveParams struct {
+	Context context.Context
+	Source  interface{}
+	Args    map[string]interface{}
+}
+
+// Schema is the root of a GraphQL API. Only queries are supported.
+type Schema struct {
+	Query *Object
+	// MaxDepth limits how deeply selections can be nested; zero uses DefaultMaxDepth
+	MaxDepth int
+}
+
+// DefaultMaxDepth bounds nested resolution so a single request cannot fan out without limit
+const DefaultMaxDepth = 10
+
+// Built-in scalars
+var (
+	ID = &Scalar{
+		Name:      "ID",
+		Serialize: serializeString,
+		ParseValue: func(value interface{}) (interface{}, error) {
+			switch v := value.(type) {
+			case string:
+				return v, nil
+			case int64:
+				return fmt.Sprint(v), nil
+			}
+			return nil, fmt.Errorf("ID cannot represent %v", value)
+		},
+	}
+
+	String = &Scalar{
+		Name:      "String",
+		Serialize: serializeString,
+		ParseValue: func(value interface{}) (interface{}, error) {
+			if s, ok := value.(string); ok {
+				return s, nil
+			}
+			return nil, fmt.Errorf("String cannot represent %v", value)
+		},
+	}
+
+	Int = &Scalar{
+		Name: "Int",
+		Serialize: func(value interface{}) (interface{}, error) {
+			v := reflect.ValueOf(value)
+			switch v.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return v.Int(), nil
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return v.Uint(), nil
+			}
+			return nil, fmt.Errorf("Int cannot represent %v", value)
+		},
+		ParseValue: func(value interface{}) (interface{}, error) {
+			switch v := value.(type) {
+			case int64:
+				if v >= math.MinInt32 && v <= math.MaxInt32 {
+					return int(v), nil
+				}
+			case float64:
+				// Variables decoded from JSON arrive as float64
+				if v == math.Trunc(v) && v >= math.MinInt32 && v <= math.MaxInt32 {
+					return int(v), nil
+				}
+			}
+			return nil, fmt.Errorf("Int cannot represent %v", value)
+		},
+	}
+
+	Float = &Scalar{
+		Name: "Float",
+		Serialize: func(value interface{}) (interface{}, error) {
+			v := reflect.ValueOf(value)
+			switch v.Kind() {
+			case reflect.Float32, reflect.Float64:
+				return v.Float(), nil
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return float64(v.Int()), nil
+			}
+			return nil, fmt.Errorf("Float cannot represent %v", value)
+		},
+		ParseValue: func(value interface{}) (interface{}, error) {
+			switch v := value.(type) {
+			case int64:
+				return float64(v), nil
+			case float64:
+				return v, nil
+			}
+			return nil, fmt.Errorf("Float cannot represent %v", value)
+		},
+	}
+
+	Boolean = &Scalar{
+		Name: "Boolean",
+		Serialize: func(value interface{}) (interface{}, error) {
+			v := reflect.ValueOf(value)
+			if v.Kind() == reflect.Bool {
+				return v.Bool(), nil
+			}
+			return nil, fmt.Errorf("Boolean cannot represent %v", value)
+		},
+		ParseValue: func(value interface{}) (interface{}, error) {
+			if b, ok := value.(bool); ok {
+				return b, nil
+			}
+			return nil, fmt.Errorf("Boolean cannot represent %v", value)
+		},
+	}
+
+	// DateTime is an RFC 3339 timestamp
+	DateTime = &Scalar{
+		Name: "DateTime",
+		Serialize: func(value interface{}) (interface{}, error) {
+			if t, ok := value.(time.Time); ok {
+				return t.Format(time.RFC3339Nano), nil
+			}
+			return nil, fmt.Errorf("DateTime cannot represent %v", value)
+		},
+		ParseValue: func(value interface{}) (interface{}, error) {
+			if s, ok := value.(string); ok {
+				if t, err := time.Parse(time.RFC3339, s); err == nil {
+					return t, nil
+				}
+			}
+			return nil, fmt.Errorf("DateTime cannot represent %v", value)
+		},
+	}
+
+	// JSON is arbitrary JSON, such as message payloads and span tags
+	JSON = &Scalar{
+		Name: "JSON",
+		Serialize: func(value interface{}) (interface{}, error) {
+			if raw, ok := value.(json.RawMessage); ok {
+				if len(raw) == 0 {
+					return nil, nil
+				}
+				return raw, nil
+			}
+			return value, nil
+		},
+		ParseValue: func(value interface{}) (interface{}, error) {
+			return value, nil
+		},
+	}
+)
+
+// serializeString writes strings, string-based enums and UUIDs as strings
+func serializeString(value interface{}) (interface{}, error) {
+	if stringer, ok := value.(fmt.Stringer); ok {
+		return stringer.String(), nil
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	return nil, fmt.Errorf("String cannot represent %v", value)
+}
+
+// scalarsByName resolves variable types declared in queries
+var scalarsByName = map[string]*Scalar{
+	ID.Name:       ID,
+	String.Name:   String,
+	Int.Name:      Int,
+	Float.Name:    Float,
+	Boolean.Name:  Boolean,
+	DateTime.Name: DateTime,
+	JSON.Name:     JSON,
+}
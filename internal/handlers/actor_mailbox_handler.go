@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Mailbox peek limits
+const (
+	defaultMailboxPeekLimit = 50
+	maxMailboxPeekLimit     = 500
+)
+
+// injectedMessageSender is the sender of messages injected by operators
+const injectedMessageSender = "admin"
+
+// ActorMailboxHandler handles the operator remediation of actors: inspecting the messages waiting
+// in an actor's mailbox and injecting messages into it. Every call is audited to the log and, as
+// security event logs, to the event bus.
+type ActorMailboxHandler struct {
+	system *actor.ActorSystem
+	events bus.Publisher
+	logger *logging.Logger
+}
+
+// NewActorMailboxHandler creates a new ActorMailboxHandler instance. A nil system reports mailboxes
+// as unavailable; audit events are only logged when events is nil.
+func NewActorMailboxHandler(system *actor.ActorSystem, events bus.Publisher, logger *logging.Logger) *ActorMailboxHandler {
+	return &ActorMailboxHandler{
+		system: system,
+		events: events,
+		logger: logger.WithComponent("actor_mailbox_handler"),
+	}
+}
+
+// InjectActorMessageRequest represents the request payload for injecting a message into an actor
+type InjectActorMessageRequest struct {
+	Type       string          `json:"type" binding:"required" example:"ride_matched"`
+	Payload    json.RawMessage `json:"payload" swaggertype:"object"`
+	Version    int             `json:"version"` // schema version of the payload; the current version when 0
+	EntityType string          `json:"entity_type" example:"trip"`
+	EntityID   string          `json:"entity_id"`
+	Reason     string          `json:"reason" binding:"required" example:"Trip stuck in matching after the driver app crashed"`
+}
+
+// InjectActorMessageResponse is a message injected into an actor's mailbox
+type InjectActorMessageResponse struct {
+	ActorID string             `json:"actor_id"`
+	Message *actor.BaseMessage `json:"message"`
+}
+
+// PeekActorMailbox handles inspecting the messages waiting in an actor's mailbox
+// @Summary Peek at an actor's mailbox
+// @Description Get the messages waiting in an actor's mailbox, oldest first, without removing them, with unredacted payloads. Requires the operator role; every peek is audited. Only actors of the instance serving the request are visible.
+// @Tags admin
+// @Produce json
+// @Param X-Operator-Key header string true "Operator key"
+// @Param id path string true "Actor ID"
+// @Param limit query int false "Number of messages, at most 500" default(50)
+// @Success 200 {object} actor.MailboxSnapshot
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/actors/{id}/mailbox [get]
+func (h *ActorMailboxHandler) PeekActorMailbox(c *gin.Context) {
+	if h.system == nil {
+		h.unavailable(c)
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultMailboxPeekLimit)))
+	if err != nil || limit <= 0 || limit > maxMailboxPeekLimit {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be between 1 and 500",
+		})
+		return
+	}
+
+	actorID := c.Param("id")
+	snapshot, err := h.system.PeekMailbox(actorID, limit)
+	if err != nil {
+		h.writeError(c, err, "Failed to peek at actor mailbox")
+		return
+	}
+
+	h.audit(c, "actor_mailbox_peeked", models.EventSeverityInfo, "Actor mailbox inspected", snapshot.ActorID, snapshot.ActorType, nil, map[string]interface{}{
+		"pending":  snapshot.Pending,
+		"returned": len(snapshot.Messages),
+	})
+
+	c.JSON(http.StatusOK, snapshot)
+}
+
+// InjectActorMessage handles injecting a crafted message into an actor's mailbox
+// @Summary Inject a message into an actor
+// @Description Send a crafted message to an actor, as incident remediation such as nudging a stuck trip. The message is sent by "admin", versioned and processed like any other, behind the messages already waiting. Requires the operator role and a reason; every injection is audited. Only actors of the instance serving the request can be reached.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param X-Operator-Key header string true "Operator key"
+// @Param id path string true "Actor ID"
+// @Param request body InjectActorMessageRequest true "Message"
+// @Success 202 {object} InjectActorMessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/actors/{id}/messages [post]
+func (h *ActorMailboxHandler) InjectActorMessage(c *gin.Context) {
+	if h.system == nil {
+		h.unavailable(c)
+		return
+	}
+
+	var req InjectActorMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+	if (req.EntityType == "") != (req.EntityID == "") {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: "entity_type and entity_id must be given together",
+		})
+		return
+	}
+
+	actorID := c.Param("id")
+	actorRef, err := h.system.GetActor(actorID)
+	if err != nil {
+		h.writeError(c, err, "Failed to inject message")
+		return
+	}
+
+	message := actor.NewBaseMessage(req.Type, req.Payload, injectedMessageSender)
+	if len(req.Payload) == 0 {
+		message.Payload = json.RawMessage("{}")
+	}
+	message.WithEntity(req.EntityType, req.EntityID)
+	// Operators write payloads at the current schema version unless they say otherwise
+	message.Version = req.Version
+	if message.Version == 0 {
+		message.Version = h.system.MessageSchemas().Version(req.Type)
+	}
+
+	data := map[string]interface{}{
+		"message_id":   message.ID,
+		"message_type": message.Type,
+		"version":      message.Version,
+		"reason":       req.Reason,
+		"payload":      message.Payload,
+	}
+	if err := h.system.SendMessage(actorID, message); err != nil {
+		data["error"] = err.Error()
+		h.audit(c, "actor_message_injection_failed", models.EventSeverityError, "Actor message injection failed", actorRef.ID, actorRef.Type, &req, data)
+		h.writeError(c, err, "Failed to inject message")
+		return
+	}
+	h.audit(c, "actor_message_injected", models.EventSeverityWarn, "Message injected into actor", actorRef.ID, actorRef.Type, &req, data)
+
+	c.JSON(http.StatusAccepted, InjectActorMessageResponse{
+		ActorID: actorID,
+		Message: message,
+	})
+}
+
+// audit logs an operator action on an actor and publishes it as a security event log. The
+// entity of an injected message, when a UUID, is the event's entity so the action shows in the
+// entity's audit trail.
+func (h *ActorMailboxHandler) audit(c *gin.Context, eventType string, severity models.EventSeverity, message, actorID, actorType string, req *InjectActorMessageRequest, data map[string]interface{}) {
+	data["request_id"] = c.GetString("request_id")
+	data["client_ip"] = c.ClientIP()
+
+	fields := logging.Fields{
+		"event_type": eventType,
+		"actor_id":   actorID,
+		"actor_type": actorType,
+		"request_id": data["request_id"],
+		"client_ip":  data["client_ip"],
+	}
+	if req != nil {
+		fields["message_type"] = req.Type
+		fields["reason"] = req.Reason
+	}
+	logger := h.logger.WithFields(fields)
+	switch severity {
+	case models.EventSeverityError:
+		logger.Error(message)
+	case models.EventSeverityWarn:
+		logger.Warn(message)
+	default:
+		logger.Info(message)
+	}
+
+	if h.events == nil {
+		return
+	}
+
+	now := time.Now()
+	modelActorType := models.ActorType(actorType)
+	eventLog := &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     eventType,
+		EventCategory: models.EventCategorySecurity,
+		ActorType:     &modelActorType,
+		ActorID:       &actorID,
+		Severity:      severity,
+		Message:       message,
+		Timestamp:     now,
+		CreatedAt:     now,
+	}
+	if req != nil && req.EntityType != "" {
+		if entityID, err := uuid.Parse(req.EntityID); err == nil {
+			eventLog.EntityType = &req.EntityType
+			eventLog.EntityID = &entityID
+		}
+	}
+	eventLog.EventData, _ = json.Marshal(data)
+	h.events.Publish(bus.TopicEventLog, eventLog)
+}
+
+func (h *ActorMailboxHandler) unavailable(c *gin.Context) {
+	c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+		Error:   "Actor system not available",
+		Message: "The actor system is not running on this instance",
+	})
+}
+
+func (h *ActorMailboxHandler) writeError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, actor.ErrActorNotFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.Is(err, actor.ErrMailboxFull):
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Mailbox full",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
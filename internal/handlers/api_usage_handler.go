@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// APIUsageHandler handles the usage analytics of API keys
+type APIUsageHandler struct {
+	usageService *service.APIUsageService
+}
+
+// NewAPIUsageHandler creates a new APIUsageHandler instance
+func NewAPIUsageHandler(usageService *service.APIUsageService) *APIUsageHandler {
+	return &APIUsageHandler{
+		usageService: usageService,
+	}
+}
+
+// GetAPIUsage handles the usage report of API keys
+// @Summary Get API key usage
+// @Description Get the requests made with fleet API keys over a time window, by key and UTC time bucket, with their client and server errors, quota rejections, error rate and latency. The window starts on a bucket boundary and spans at most 1440 buckets. Requests are reported once the instance serving them flushes its usage, every 30 seconds by default.
+// @Tags admin
+// @Produce json
+// @Param key_id query string false "API key ID, the first 12 characters of the key's SHA-256 hash"
+// @Param tenant_id query string false "Fleet ID"
+// @Param bucket query string false "Bucket size: minute, hour, or day" default(hour)
+// @Param start query string false "Start of the window (RFC3339); defaults to 24 hours before end"
+// @Param end query string false "End of the window (RFC3339); defaults to now"
+// @Success 200 {object} models.APIUsageReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/usage [get]
+func (h *APIUsageHandler) GetAPIUsage(c *gin.Context) {
+	end := time.Now()
+	if v := c.Query("end"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid end time",
+				Message: "End time must be in RFC3339 format",
+			})
+			return
+		}
+		end = t
+	}
+	start := end.Add(-24 * time.Hour)
+	if v := c.Query("start"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid start time",
+				Message: "Start time must be in RFC3339 format",
+			})
+			return
+		}
+		start = t
+	}
+
+	filter := models.APIUsageFilter{
+		KeyID: c.Query("key_id"),
+		From:  start,
+		To:    end,
+	}
+	if v := c.Query("tenant_id"); v != "" {
+		tenantID, err := uuid.Parse(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid tenant ID",
+				Message: "Tenant ID must be a valid UUID",
+			})
+			return
+		}
+		filter.TenantID = &tenantID
+	}
+
+	report, err := h.usageService.Report(c.Request.Context(), filter, c.DefaultQuery("bucket", models.APIUsageBucketHour))
+	if err != nil {
+		var validation *models.ValidationError
+		if errors.As(err, &validation) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation error",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get API usage",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
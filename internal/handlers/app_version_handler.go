@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+
+	"actor-model-observability/internal/appversion"
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AppVersionHandler handles the apps negotiating their features and operators following the
+// adoption of app versions
+type AppVersionHandler struct {
+	gate           *appversion.Gate
+	sessionService service.SessionServiceInterface
+}
+
+// NewAppVersionHandler creates a new AppVersionHandler instance. Without a gate, apps are not
+// gated and get no features.
+func NewAppVersionHandler(gate *appversion.Gate, sessionService service.SessionServiceInterface) *AppVersionHandler {
+	return &AppVersionHandler{
+		gate:           gate,
+		sessionService: sessionService,
+	}
+}
+
+// CapabilitiesResponse describes what the calling app version may use
+type CapabilitiesResponse struct {
+	Platform   string   `json:"platform,omitempty"`
+	Version    string   `json:"version,omitempty"`
+	MinVersion string   `json:"min_version,omitempty"` // minimum version of the platform, when it is gated
+	Features   []string `json:"features"`              // features enabled for the version, sorted by name
+}
+
+// AppVersionsResponse reports the adoption of app versions
+type AppVersionsResponse struct {
+	MinVersions map[string]string            `json:"min_versions"`
+	Requests    []appversion.Usage           `json:"requests"` // requests per version since this instance started
+	Sessions    []*models.AppVersionSessions `json:"sessions"` // active sessions per version
+}
+
+// GetCapabilities handles an app asking which features its version may use
+// @Summary Get app capabilities
+// @Description Get the features enabled for the app version reported in the X-App-Platform and X-App-Version headers, and the minimum version of its platform. Apps reporting no version get no features. Versions below the minimum get 426 Upgrade Required instead, like every other endpoint.
+// @Tags meta
+// @Produce json
+// @Param X-App-Platform header string false "App platform, e.g. ios or android"
+// @Param X-App-Version header string false "App version, e.g. 2.4.0"
+// @Success 200 {object} CapabilitiesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 426 {object} ErrorResponse "The app version is below the platform's minimum; code is APP_UPGRADE_REQUIRED"
+// @Router /api/v1/meta/capabilities [get]
+func (h *AppVersionHandler) GetCapabilities(c *gin.Context) {
+	client, ok := middleware.AppClientFromContext(c)
+	if !ok || h.gate == nil {
+		c.JSON(http.StatusOK, CapabilitiesResponse{Features: []string{}})
+		return
+	}
+
+	response := CapabilitiesResponse{
+		Platform: client.Platform,
+		Version:  client.Version.String(),
+		Features: h.gate.Features(client),
+	}
+	if min, gated := h.gate.MinVersion(client.Platform); gated {
+		response.MinVersion = min.String()
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAppVersions handles listing the usage of each app version
+// @Summary List app version usage
+// @Description Get the minimum version of each gated platform, the requests each app platform and version made since this instance started, including those rejected for an upgrade, and the active sessions of each version, to decide when a minimum version can be raised
+// @Tags admin
+// @Produce json
+// @Success 200 {object} AppVersionsResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/app-versions [get]
+func (h *AppVersionHandler) GetAppVersions(c *gin.Context) {
+	if h.gate == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "App versions not available",
+			Message: "App version gating is not configured",
+		})
+		return
+	}
+
+	sessions, err := h.sessionService.CountSessionsByAppVersion(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to count sessions by app version",
+		})
+		return
+	}
+	if sessions == nil {
+		sessions = []*models.AppVersionSessions{}
+	}
+
+	c.JSON(http.StatusOK, AppVersionsResponse{
+		MinVersions: h.gate.MinVersions(),
+		Requests:    h.gate.Usage(),
+		Sessions:    sessions,
+	})
+}
@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AuthHandler handles driver and passenger device sessions
+type AuthHandler struct {
+	sessionService service.SessionServiceInterface
+}
+
+// NewAuthHandler creates a new AuthHandler instance
+func NewAuthHandler(sessionService service.SessionServiceInterface) *AuthHandler {
+	return &AuthHandler{
+		sessionService: sessionService,
+	}
+}
+
+// LoginRequest represents the request payload for starting a device session
+type LoginRequest struct {
+	Email      string  `json:"email" binding:"required,email"`
+	Phone      string  `json:"phone" binding:"required"`
+	DeviceID   string  `json:"device_id" binding:"required,max=255"`
+	DeviceName *string `json:"device_name,omitempty" binding:"omitempty,max=255"`
+}
+
+// RefreshSessionRequest represents the request payload for refreshing a session
+type RefreshSessionRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// Login handles starting a device session
+// @Summary Log in a device
+// @Description Start a session for a driver or passenger device and return an access token and a refresh token. Logging in again from the same device replaces its session; beyond the concurrent session limit the least recently used session is revoked. The app platform and version reported in the X-App-Platform and X-App-Version headers are recorded on the session.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body LoginRequest true "Account and device details"
+// @Success 201 {object} models.SessionTokens
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/sessions [post]
+func (h *AuthHandler) Login(c *gin.Context) {
+	var req LoginRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	tokens, err := h.sessionService.Login(c.Request.Context(), service.LoginCredentials{
+		Email:      req.Email,
+		Phone:      req.Phone,
+		DeviceID:   req.DeviceID,
+		DeviceName: req.DeviceName,
+		ClientIP:   c.ClientIP(),
+		App:        clientApp(c),
+	})
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidCredentials) {
+			c.JSON(http.StatusUnauthorized, ErrorResponse{
+				Error:   "Unauthorized",
+				Message: err.Error(),
+				Code:    string(models.ErrorCodeOf(err)),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to start session",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, tokens)
+}
+
+// RefreshSession handles exchanging a refresh token for a new token pair
+// @Summary Refresh a session
+// @Description Exchange a refresh token for a new access token and refresh token. The presented refresh token can't be used again. The app version reported in the X-App-Version header replaces the session's.
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body RefreshSessionRequest true "Refresh token"
+// @Success 200 {object} models.SessionTokens
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/sessions/refresh [post]
+func (h *AuthHandler) RefreshSession(c *gin.Context) {
+	var req RefreshSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	tokens, err := h.sessionService.Refresh(c.Request.Context(), req.RefreshToken, c.ClientIP(), clientApp(c))
+	if err != nil {
+		if errors.Is(err, models.ErrInvalidToken) {
+			c.JSON(http.StatusUnauthorized, ErrorResponse{
+				Error:   "Unauthorized",
+				Message: err.Error(),
+				Code:    string(models.ErrorCodeOf(err)),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to refresh session",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// ListSessions handles listing the caller's active sessions
+// @Summary List sessions
+// @Description Get the caller's active device sessions, most recently used first
+// @Tags auth
+// @Produce json
+// @Security bearer
+// @Success 200 {array} models.Session
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	session, ok := h.session(c)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.sessionService.ListSessions(c.Request.Context(), session.UserID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to list sessions",
+		})
+		return
+	}
+	if sessions == nil {
+		sessions = []*models.Session{}
+	}
+
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeSession handles revoking one of the caller's sessions
+// @Summary Revoke a session
+// @Description Revoke a device session of the caller, including the one making the request. Its tokens stop working immediately.
+// @Tags auth
+// @Security bearer
+// @Param id path string true "Session ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/auth/sessions/{id} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	session, ok := h.session(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid session ID",
+			Message: "Session ID must be a valid UUID",
+		})
+		return
+	}
+
+	err = h.sessionService.RevokeSession(c.Request.Context(), session.UserID.String(), id.String(), c.ClientIP())
+	if err != nil {
+		if _, notFound := err.(*models.NotFoundError); notFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Resource not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to revoke session",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// session returns the caller's session, responding with 401 when the request is not authenticated
+func (h *AuthHandler) session(c *gin.Context) (*models.Session, bool) {
+	session, ok := middleware.SessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "A session access token is required",
+		})
+		return nil, false
+	}
+	return session, true
+}
+
+// clientApp returns the app the request was made with, empty when it reported no version
+func clientApp(c *gin.Context) service.ClientApp {
+	client, ok := middleware.AppClientFromContext(c)
+	if !ok {
+		return service.ClientApp{}
+	}
+	return service.ClientApp{Platform: client.Platform, Version: client.Version.String()}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/timerange"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultCancellationWindow is the period of the cancellation analytics given only one of its bounds
+const defaultCancellationWindow = 24 * time.Hour
+
+// CancellationHandler handles the analytics of trip cancellations
+type CancellationHandler struct {
+	cancellationService *service.CancellationService
+	timeRanges          timerange.Parser
+}
+
+// NewCancellationHandler creates a new CancellationHandler instance
+func NewCancellationHandler(cancellationService *service.CancellationService) *CancellationHandler {
+	return &CancellationHandler{
+		cancellationService: cancellationService,
+	}
+}
+
+// SetMaxTimeRange sets the longest period cancellations are broken down over. Without one,
+// periods may be of any length.
+func (h *CancellationHandler) SetMaxTimeRange(max time.Duration) {
+	h.timeRanges.MaxWindow = max
+}
+
+// GetCancellationAnalytics handles the cancellation analytics
+// @Summary Get cancellation analytics
+// @Description Break down the trips cancelled in the period by reason, by the mode (actor_model or traditional) of the pipeline that cancelled them, by pickup geohash zone and over time buckets, with each count's share of the cancellations. Trips cancelled without a recorded reason or mode are counted as unknown.
+// @Tags admin
+// @Produce json
+// @Param start query string false "Start of the period: RFC3339, now, or an offset from now such as -7d; defaults to 24 hours before end"
+// @Param end query string false "End of the period: RFC3339, now, or an offset from now; defaults to now"
+// @Param bucket query string false "Time bucket length as a Go duration of at least 1m; the period may span at most 1000 buckets" default(1h)
+// @Param zone_precision query int false "Geohash precision of the pickup zones, between 1 and 12" default(5)
+// @Success 200 {object} models.CancellationAnalytics
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/cancellations/analytics [get]
+func (h *CancellationHandler) GetCancellationAnalytics(c *gin.Context) {
+	period, ok := parseTimeRange(c, h.timeRanges, "start", "end", defaultCancellationWindow)
+	if !ok {
+		return
+	}
+
+	bucket := service.DefaultCancellationBucket
+	if raw := c.Query("bucket"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid bucket",
+				Message: "Bucket must be a duration such as 15m or 1h",
+			})
+			return
+		}
+		bucket = parsed
+	}
+
+	precision := service.DefaultCancellationZonePrecision
+	if raw := c.Query("zone_precision"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid zone precision",
+				Message: "Zone precision must be an integer",
+			})
+			return
+		}
+		precision = parsed
+	}
+
+	analytics, err := h.cancellationService.Analytics(c.Request.Context(), period.Start, period.End, bucket, precision)
+	if err != nil {
+		var validation *models.ValidationError
+		if errors.As(err, &validation) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation error",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get cancellation analytics",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, analytics)
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"actor-model-observability/internal/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeListenerHandler handles the admin view of the row change listener
+type ChangeListenerHandler struct {
+	listener *database.ChangeListener
+}
+
+// NewChangeListenerHandler creates a new ChangeListenerHandler instance. A nil listener, when the
+// database is not PostgreSQL or change notifications are disabled, reports the listener as
+// unavailable.
+func NewChangeListenerHandler(listener *database.ChangeListener) *ChangeListenerHandler {
+	return &ChangeListenerHandler{
+		listener: listener,
+	}
+}
+
+// GetChangeListenerStats handles the row change listener statistics
+// @Summary Get row change listener statistics
+// @Description Get whether the listener for the changes to drivers and trips, which drops cached driver locations and updates ride status streams when rows change outside the API, is connected, how many changes it received and how often it reconnected since the instance started
+// @Tags admin
+// @Produce json
+// @Success 200 {object} database.ChangeListenerStats
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/database/change-listener [get]
+func (h *ChangeListenerHandler) GetChangeListenerStats(c *gin.Context) {
+	if h.listener == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Change listener not available",
+			Message: "Row change notifications need the postgres database driver and DB_CHANGE_NOTIFICATIONS enabled",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.listener.Stats())
+}
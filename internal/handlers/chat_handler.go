@@ -0,0 +1,309 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// ChatHandler handles the in-trip chat between a trip's passenger and driver
+type ChatHandler struct {
+	chatService *service.ChatService
+}
+
+// NewChatHandler creates a new ChatHandler instance
+func NewChatHandler(chatService *service.ChatService) *ChatHandler {
+	return &ChatHandler{
+		chatService: chatService,
+	}
+}
+
+// SendChatMessageRequest represents the request payload for sending a chat message
+type SendChatMessageRequest struct {
+	Body string `json:"body" binding:"required"`
+}
+
+// MarkChatReadResponse is the number of messages marked as read
+type MarkChatReadResponse struct {
+	Marked int `json:"marked"`
+}
+
+// ChatSocketFrame is a frame sent to chat WebSocket clients: a new message, or the error of a
+// message the client failed to send
+type ChatSocketFrame struct {
+	Type    string                  `json:"type"` // message or error
+	Message *models.TripChatMessage `json:"message,omitempty"`
+	Error   string                  `json:"error,omitempty"`
+}
+
+// ListChatMessages handles listing a trip's chat messages
+// @Summary List chat messages
+// @Description Get the chat messages of a trip, oldest first. Only the trip's passenger and driver can read the chat; it stays readable after the trip until it is purged, the configured retention period after the trip completed or was cancelled.
+// @Tags chat
+// @Produce json
+// @Security bearer
+// @Param id path string true "Trip ID"
+// @Param limit query int false "Number of items per page" default(50)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} PaginatedResponse{data=[]models.TripChatMessage}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/chat/messages [get]
+func (h *ChatHandler) ListChatMessages(c *gin.Context) {
+	session, ok := h.session(c)
+	if !ok {
+		return
+	}
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer between 1 and 100",
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid offset",
+			Message: "Offset must be a non-negative integer",
+		})
+		return
+	}
+
+	messages, err := h.chatService.ListMessages(c.Request.Context(), tripID.String(), session.UserID, session.UserType, limit, offset)
+	if err != nil {
+		h.writeError(c, err, "Failed to list chat messages")
+		return
+	}
+	if messages == nil {
+		messages = []*models.TripChatMessage{}
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:    messages,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: len(messages) == limit,
+	})
+}
+
+// SendChatMessage handles sending a chat message
+// @Summary Send a chat message
+// @Description Send a message to the other participant of a trip. Messages can be sent while the trip has a driver and has not completed or been cancelled. The body is at most 1000 characters and is stored after the configured filters masked profanity and removed contact details, as returned.
+// @Tags chat
+// @Accept json
+// @Produce json
+// @Security bearer
+// @Param id path string true "Trip ID"
+// @Param request body SendChatMessageRequest true "Message"
+// @Success 201 {object} models.TripChatMessage
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/chat/messages [post]
+func (h *ChatHandler) SendChatMessage(c *gin.Context) {
+	session, ok := h.session(c)
+	if !ok {
+		return
+	}
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req SendChatMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	msg, err := h.chatService.SendMessage(c.Request.Context(), tripID.String(), session.UserID, session.UserType, req.Body)
+	if err != nil {
+		h.writeError(c, err, "Failed to send chat message")
+		return
+	}
+
+	c.JSON(http.StatusCreated, msg)
+}
+
+// MarkChatRead handles marking a trip's chat as read
+// @Summary Mark chat messages read
+// @Description Mark every message the caller received on a trip as read, resetting the caller's unread count in the ride status.
+// @Tags chat
+// @Produce json
+// @Security bearer
+// @Param id path string true "Trip ID"
+// @Success 200 {object} MarkChatReadResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/chat/read [post]
+func (h *ChatHandler) MarkChatRead(c *gin.Context) {
+	session, ok := h.session(c)
+	if !ok {
+		return
+	}
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	marked, err := h.chatService.MarkRead(c.Request.Context(), tripID.String(), session.UserID, session.UserType)
+	if err != nil {
+		h.writeError(c, err, "Failed to mark chat messages read")
+		return
+	}
+
+	c.JSON(http.StatusOK, MarkChatReadResponse{Marked: marked})
+}
+
+// StreamChat handles the chat WebSocket
+// @Summary Chat over WebSocket
+// @Description Upgrade to a WebSocket carrying the trip's chat live. The server sends a ChatSocketFrame for every new message, including the caller's own; the client sends messages as SendChatMessageRequest JSON frames, and a message that cannot be sent is answered with an error frame. Messages are not replayed, so clients list the messages after connecting and a client too slow to keep up misses messages.
+// @Tags chat
+// @Security bearer
+// @Param id path string true "Trip ID"
+// @Success 101 {object} ChatSocketFrame
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/chat/ws [get]
+func (h *ChatHandler) StreamChat(c *gin.Context) {
+	session, ok := h.session(c)
+	if !ok {
+		return
+	}
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	messages, unsubscribe, err := h.chatService.Subscribe(c.Request.Context(), tripID.String(), session.UserID, session.UserType)
+	if err != nil {
+		h.writeError(c, err, "Failed to open chat")
+		return
+	}
+	defer unsubscribe()
+
+	// websocket.Server without a Handshake accepts clients that send no Origin, like mobile apps;
+	// the session token already authenticates the connection
+	server := websocket.Server{Handler: func(ws *websocket.Conn) {
+		// The connection outlives the server read and write timeouts
+		ws.SetDeadline(time.Time{})
+
+		// Messages from the client are sent on the request's behalf; the hub echoes them back
+		errs := make(chan string, 1)
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			for {
+				var req SendChatMessageRequest
+				if err := websocket.JSON.Receive(ws, &req); err != nil {
+					return
+				}
+				if _, err := h.chatService.SendMessage(c.Request.Context(), tripID.String(), session.UserID, session.UserType, req.Body); err != nil {
+					select {
+					case errs <- err.Error():
+					default:
+					}
+				}
+			}
+		}()
+
+		for {
+			var frame ChatSocketFrame
+			select {
+			case <-closed:
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				frame = ChatSocketFrame{Type: "message", Message: msg}
+			case e := <-errs:
+				frame = ChatSocketFrame{Type: "error", Error: e}
+			}
+			if err := websocket.JSON.Send(ws, frame); err != nil {
+				return
+			}
+		}
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// session returns the caller's session, responding with 401 when the request is not authenticated
+func (h *ChatHandler) session(c *gin.Context) (*models.Session, bool) {
+	session, ok := middleware.SessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "A session access token is required",
+		})
+		return nil, false
+	}
+	return session, true
+}
+
+// writeError maps service errors to responses
+func (h *ChatHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	case errors.Is(err, models.ErrUnauthorizedOperation):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Only the trip's passenger and driver can use its chat",
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	case errors.Is(err, models.ErrTripChatClosed):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
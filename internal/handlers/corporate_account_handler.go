@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CorporateAccountHandler handles corporate accounts, their passengers and monthly statements
+type CorporateAccountHandler struct {
+	corporateService *service.CorporateAccountService
+}
+
+// NewCorporateAccountHandler creates a new CorporateAccountHandler instance
+func NewCorporateAccountHandler(corporateService *service.CorporateAccountService) *CorporateAccountHandler {
+	return &CorporateAccountHandler{
+		corporateService: corporateService,
+	}
+}
+
+// CreateCorporateAccountRequest represents the request payload for creating a corporate account
+type CreateCorporateAccountRequest struct {
+	Name                 string   `json:"name" binding:"required"`
+	MonthlySpendingLimit *float64 `json:"monthly_spending_limit,omitempty" binding:"omitempty,min=0"`
+}
+
+// SetSpendingLimitRequest represents the request payload for changing a spending limit; a null
+// or missing limit removes it
+type SetSpendingLimitRequest struct {
+	MonthlySpendingLimit *float64 `json:"monthly_spending_limit" binding:"omitempty,min=0"`
+}
+
+// CreateAccount handles creating a corporate account
+// @Summary Create a corporate account
+// @Description Create a corporate account that linked passengers can bill rides to with bill_to=corporate. Without a monthly spending limit billing is unlimited.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body CreateCorporateAccountRequest true "Corporate account details"
+// @Success 201 {object} models.CorporateAccount
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/corporate-accounts [post]
+func (h *CorporateAccountHandler) CreateAccount(c *gin.Context) {
+	var req CreateCorporateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	account, err := h.corporateService.CreateAccount(c.Request.Context(), req.Name, req.MonthlySpendingLimit)
+	if err != nil {
+		h.writeError(c, err, "Failed to create corporate account")
+		return
+	}
+
+	c.JSON(http.StatusCreated, account)
+}
+
+// GetAccount handles retrieving a corporate account
+// @Summary Get a corporate account
+// @Description Get a corporate account and its monthly spending limit
+// @Tags admin
+// @Produce json
+// @Param id path string true "Corporate account ID"
+// @Success 200 {object} models.CorporateAccount
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/corporate-accounts/{id} [get]
+func (h *CorporateAccountHandler) GetAccount(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id", "Invalid corporate account ID", "Corporate account ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	account, err := h.corporateService.GetAccount(c.Request.Context(), id.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to get corporate account")
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// SetSpendingLimit handles changing the monthly spending limit of a corporate account
+// @Summary Set a corporate spending limit
+// @Description Set the monthly spending limit of a corporate account, or remove it with a null limit. Ride requests billed to the account are rejected with 403 once the estimated fares and final fares of the month's trips would exceed it; cancelled trips do not count.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Corporate account ID"
+// @Param request body SetSpendingLimitRequest true "Monthly spending limit"
+// @Success 200 {object} models.CorporateAccount
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/corporate-accounts/{id}/spending-limit [put]
+func (h *CorporateAccountHandler) SetSpendingLimit(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id", "Invalid corporate account ID", "Corporate account ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req SetSpendingLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	account, err := h.corporateService.SetSpendingLimit(c.Request.Context(), id.String(), req.MonthlySpendingLimit)
+	if err != nil {
+		h.writeError(c, err, "Failed to set spending limit")
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+// LinkPassenger handles linking a passenger to a corporate account
+// @Summary Link a passenger to a corporate account
+// @Description Link a passenger to a corporate account, replacing any account it was linked to
+// @Tags admin
+// @Param id path string true "Corporate account ID"
+// @Param passenger_id path string true "Passenger ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/corporate-accounts/{id}/passengers/{passenger_id} [put]
+func (h *CorporateAccountHandler) LinkPassenger(c *gin.Context) {
+	accountID, passengerID, ok := parseAccountPassenger(c)
+	if !ok {
+		return
+	}
+
+	if err := h.corporateService.LinkPassenger(c.Request.Context(), accountID, passengerID); err != nil {
+		h.writeError(c, err, "Failed to link passenger")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// UnlinkPassenger handles removing a passenger from a corporate account
+// @Summary Unlink a passenger from a corporate account
+// @Description Remove a passenger from a corporate account. Trips it already billed stay on the account's statements.
+// @Tags admin
+// @Param id path string true "Corporate account ID"
+// @Param passenger_id path string true "Passenger ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/corporate-accounts/{id}/passengers/{passenger_id} [delete]
+func (h *CorporateAccountHandler) UnlinkPassenger(c *gin.Context) {
+	accountID, passengerID, ok := parseAccountPassenger(c)
+	if !ok {
+		return
+	}
+
+	if err := h.corporateService.UnlinkPassenger(c.Request.Context(), accountID, passengerID); err != nil {
+		h.writeError(c, err, "Failed to unlink passenger")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListStatements handles listing the monthly statements of a corporate account
+// @Summary List corporate statements
+// @Description List the monthly statements (UTC calendar months) of a corporate account, newest first, starting with the current month. Completed trips count with their final fare, cancelled trips with nothing and other trips with the fare estimated when they were requested.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Corporate account ID"
+// @Param months query int false "Number of months, at most 24" default(12)
+// @Success 200 {array} models.CorporateStatement
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/corporate-accounts/{id}/statements [get]
+func (h *CorporateAccountHandler) ListStatements(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id", "Invalid corporate account ID", "Corporate account ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	months := 0
+	if v := c.Query("months"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid months",
+				Message: "months must be a positive integer",
+			})
+			return
+		}
+		months = n
+	}
+
+	statements, err := h.corporateService.ListStatements(c.Request.Context(), id.String(), months)
+	if err != nil {
+		h.writeError(c, err, "Failed to list statements")
+		return
+	}
+
+	c.JSON(http.StatusOK, statements)
+}
+
+// GetStatement handles retrieving one monthly statement of a corporate account
+// @Summary Get a corporate statement
+// @Description Get the statement of a corporate account for a UTC calendar month, with the trips and spend of each passenger
+// @Tags admin
+// @Produce json
+// @Param id path string true "Corporate account ID"
+// @Param month path string true "Month as YYYY-MM"
+// @Success 200 {object} models.CorporateStatement
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/corporate-accounts/{id}/statements/{month} [get]
+func (h *CorporateAccountHandler) GetStatement(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id", "Invalid corporate account ID", "Corporate account ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	statement, err := h.corporateService.GetStatement(c.Request.Context(), id.String(), c.Param("month"))
+	if err != nil {
+		h.writeError(c, err, "Failed to get statement")
+		return
+	}
+
+	c.JSON(http.StatusOK, statement)
+}
+
+// parseAccountPassenger parses the account and passenger ID path parameters, responding with
+// 400 when either is invalid
+func parseAccountPassenger(c *gin.Context) (string, string, bool) {
+	accountID, ok := parseUUIDParam(c, "id", "Invalid corporate account ID", "Corporate account ID must be a valid UUID")
+	if !ok {
+		return "", "", false
+	}
+	passengerID, ok := parseUUIDParam(c, "passenger_id", "Invalid passenger ID", "Passenger ID must be a valid UUID")
+	if !ok {
+		return "", "", false
+	}
+	return accountID.String(), passengerID.String(), true
+}
+
+// writeError maps corporate account errors to HTTP responses
+func (h *CorporateAccountHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DashboardHandler handles the precomputed dashboard summaries
+type DashboardHandler struct {
+	dashboardService *service.DashboardService
+}
+
+// NewDashboardHandler creates a new DashboardHandler instance
+func NewDashboardHandler(dashboardService *service.DashboardService) *DashboardHandler {
+	return &DashboardHandler{
+		dashboardService: dashboardService,
+	}
+}
+
+// GetHourlyTrips handles the hourly trip counts summary
+// @Summary Get hourly trip counts
+// @Description Get the trips requested in each of the last hours, by how far they got, from the precomputed summary. The response tells when the summary was last refreshed.
+// @Tags dashboard
+// @Produce json
+// @Param hours query int false "Hours to return, including the current one" default(24)
+// @Success 200 {object} models.DashboardSummary{data=[]models.HourlyTripSummary}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/dashboard/hourly-trips [get]
+func (h *DashboardHandler) GetHourlyTrips(c *gin.Context) {
+	hours, ok := parseHoursParam(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.dashboardService.HourlyTrips(c.Request.Context(), hours)
+	if err != nil {
+		h.writeError(c, err, "Failed to get hourly trip counts")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetMatchingTimes handles the matching time percentiles summary
+// @Summary Get matching time percentiles
+// @Description Get how long the trips requested in each of the last hours waited to be matched with a driver (average, p50, p90 and p99 in seconds), from the precomputed summary. Hours without matched trips are omitted.
+// @Tags dashboard
+// @Produce json
+// @Param hours query int false "Hours to return, including the current one" default(24)
+// @Success 200 {object} models.DashboardSummary{data=[]models.MatchingTimeSummary}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/dashboard/matching-times [get]
+func (h *DashboardHandler) GetMatchingTimes(c *gin.Context) {
+	hours, ok := parseHoursParam(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.dashboardService.MatchingTimes(c.Request.Context(), hours)
+	if err != nil {
+		h.writeError(c, err, "Failed to get matching times")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetZoneSupply handles the driver supply per zone summary
+// @Summary Get driver supply per zone
+// @Description Get the available and busy drivers in each geohash zone, from the precomputed summary
+// @Tags dashboard
+// @Produce json
+// @Success 200 {object} models.DashboardSummary{data=[]models.ZoneSupplySummary}
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/dashboard/zone-supply [get]
+func (h *DashboardHandler) GetZoneSupply(c *gin.Context) {
+	summary, err := h.dashboardService.ZoneSupply(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err, "Failed to get zone supply")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// RefreshDashboard handles refreshing dashboard summaries on demand
+// @Summary Refresh dashboard summaries
+// @Description Recompute a dashboard summary now instead of waiting for its scheduled refresh; every summary when view is omitted
+// @Tags admin
+// @Produce json
+// @Param view query string false "hourly_trips, matching_times or zone_supply"
+// @Success 200 {array} models.DashboardFreshness
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/dashboard/refresh [post]
+func (h *DashboardHandler) RefreshDashboard(c *gin.Context) {
+	var freshness []*models.DashboardFreshness
+	if view := c.Query("view"); view != "" {
+		f, err := h.dashboardService.Refresh(c.Request.Context(), view)
+		if err != nil {
+			h.writeError(c, err, "Failed to refresh dashboard summary")
+			return
+		}
+		freshness = append(freshness, f)
+	} else {
+		var err error
+		if freshness, err = h.dashboardService.RefreshAll(c.Request.Context()); err != nil {
+			h.writeError(c, err, "Failed to refresh dashboard summaries")
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, freshness)
+}
+
+// writeError maps dashboard service errors to HTTP responses
+func (h *DashboardHandler) writeError(c *gin.Context, err error, message string) {
+	var validation *models.ValidationError
+	if errors.As(err, &validation) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "Internal server error",
+		Message: message,
+	})
+}
+
+// parseHoursParam parses the hours query parameter, writing a 400 response when it isn't a number
+func parseHoursParam(c *gin.Context) (int, bool) {
+	hours, err := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid hours",
+			Message: "Hours must be a number",
+		})
+		return 0, false
+	}
+	return hours, true
+}
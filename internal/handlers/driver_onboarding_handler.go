@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/timerange"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultOnboardingFunnelWindow is the registration period of the onboarding funnel given only
+// one of its bounds
+const defaultOnboardingFunnelWindow = 30 * 24 * time.Hour
+
+// DriverOnboardingHandler handles drivers' progress through onboarding and its review by admins
+type DriverOnboardingHandler struct {
+	onboardingService *service.DriverOnboardingService
+	timeRanges        timerange.Parser
+}
+
+// NewDriverOnboardingHandler creates a new DriverOnboardingHandler instance
+func NewDriverOnboardingHandler(onboardingService *service.DriverOnboardingService) *DriverOnboardingHandler {
+	return &DriverOnboardingHandler{
+		onboardingService: onboardingService,
+	}
+}
+
+// SetMaxTimeRange sets the longest registration period the funnel is reported over. Without
+// one, periods may be of any length.
+func (h *DriverOnboardingHandler) SetMaxTimeRange(max time.Duration) {
+	h.timeRanges.MaxWindow = max
+}
+
+// SubmitOnboardingDocumentsRequest represents the request payload for submitting onboarding documents
+type SubmitOnboardingDocumentsRequest struct {
+	Documents []models.OnboardingDocument `json:"documents" binding:"required,min=1"`
+}
+
+// ReviewDriverOnboardingRequest represents the request payload for an admin verifying or
+// activating a driver
+type ReviewDriverOnboardingRequest struct {
+	ReviewedBy string `json:"reviewed_by" binding:"required,max=255"`
+}
+
+// GetOnboardingProgress handles retrieving a driver's onboarding progress
+// @Summary Get a driver's onboarding progress
+// @Description Get the driver's current onboarding stage, when each stage (registered, documents_submitted, verified, training_completed, activated) was reached, and the requirements outstanding before the next one. Drivers can only go online once activated.
+// @Tags drivers
+// @Produce json
+// @Param id path string true "Driver ID"
+// @Success 200 {object} models.DriverOnboardingProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/onboarding [get]
+func (h *DriverOnboardingHandler) GetOnboardingProgress(c *gin.Context) {
+	driverID, ok := parseUUIDParam(c, "id", "Invalid driver ID", "Driver ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	progress, err := h.onboardingService.Progress(c.Request.Context(), driverID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to get driver onboarding")
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// SubmitOnboardingDocuments handles a driver submitting onboarding documents
+// @Summary Submit onboarding documents
+// @Description Record documents (driver_license, vehicle_registration, insurance) submitted by a registered driver. Documents may be submitted one at a time; the driver moves to documents_submitted once all of them are in.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID"
+// @Param request body SubmitOnboardingDocumentsRequest true "Documents"
+// @Success 200 {object} models.DriverOnboardingProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/onboarding/documents [post]
+func (h *DriverOnboardingHandler) SubmitOnboardingDocuments(c *gin.Context) {
+	driverID, ok := parseUUIDParam(c, "id", "Invalid driver ID", "Driver ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req SubmitOnboardingDocumentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	progress, err := h.onboardingService.SubmitDocuments(c.Request.Context(), driverID.String(), req.Documents)
+	if err != nil {
+		h.writeError(c, err, "Failed to submit onboarding documents")
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// CompleteOnboardingTraining handles a verified driver completing training
+// @Summary Complete onboarding training
+// @Description Record that a driver whose documents were verified completed the driver training
+// @Tags drivers
+// @Produce json
+// @Param id path string true "Driver ID"
+// @Success 200 {object} models.DriverOnboardingProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/onboarding/training [post]
+func (h *DriverOnboardingHandler) CompleteOnboardingTraining(c *gin.Context) {
+	driverID, ok := parseUUIDParam(c, "id", "Invalid driver ID", "Driver ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	progress, err := h.onboardingService.CompleteTraining(c.Request.Context(), driverID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to complete onboarding training")
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// VerifyDriverOnboarding handles an admin verifying a driver's documents
+// @Summary Verify a driver's documents
+// @Description Move a driver who submitted every document to verified
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID"
+// @Param request body ReviewDriverOnboardingRequest true "Reviewer"
+// @Success 200 {object} models.DriverOnboardingProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/driver-onboarding/{id}/verify [post]
+func (h *DriverOnboardingHandler) VerifyDriverOnboarding(c *gin.Context) {
+	driverID, req, ok := h.bindReview(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.onboardingService.Verify(c.Request.Context(), driverID, req.ReviewedBy)
+	if err != nil {
+		h.writeError(c, err, "Failed to verify driver onboarding")
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// ActivateDriver handles an admin activating a driver
+// @Summary Activate a driver
+// @Description Activate a driver who completed training, allowing the driver to go online
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID"
+// @Param request body ReviewDriverOnboardingRequest true "Reviewer"
+// @Success 200 {object} models.DriverOnboardingProgress
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/driver-onboarding/{id}/activate [post]
+func (h *DriverOnboardingHandler) ActivateDriver(c *gin.Context) {
+	driverID, req, ok := h.bindReview(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.onboardingService.Activate(c.Request.Context(), driverID, req.ReviewedBy)
+	if err != nil {
+		h.writeError(c, err, "Failed to activate driver")
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// GetOnboardingFunnel handles the onboarding funnel
+// @Summary Get the driver onboarding funnel
+// @Description Count the drivers registered in the period who reached each onboarding stage, and who are still at it, with the conversion from the previous stage and from registration
+// @Tags admin
+// @Produce json
+// @Param start query string false "Start of the registration period: RFC3339, now, or an offset from now such as -7d; defaults to 30 days before end"
+// @Param end query string false "End of the registration period: RFC3339, now, or an offset from now; defaults to now"
+// @Success 200 {object} models.OnboardingFunnel
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/driver-onboarding/funnel [get]
+func (h *DriverOnboardingHandler) GetOnboardingFunnel(c *gin.Context) {
+	period, ok := parseTimeRange(c, h.timeRanges, "start", "end", defaultOnboardingFunnelWindow)
+	if !ok {
+		return
+	}
+
+	funnel, err := h.onboardingService.Funnel(c.Request.Context(), period.Start, period.End)
+	if err != nil {
+		h.writeError(c, err, "Failed to get driver onboarding funnel")
+		return
+	}
+
+	c.JSON(http.StatusOK, funnel)
+}
+
+// bindReview parses the driver ID and reviewer of an admin review, writing the error response
+// if either is invalid
+func (h *DriverOnboardingHandler) bindReview(c *gin.Context) (string, ReviewDriverOnboardingRequest, bool) {
+	var req ReviewDriverOnboardingRequest
+	driverID, ok := parseUUIDParam(c, "id", "Invalid driver ID", "Driver ID must be a valid UUID")
+	if !ok {
+		return "", req, false
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return "", req, false
+	}
+	return driverID.String(), req, true
+}
+
+// writeError maps a driver onboarding error to its HTTP response
+func (h *DriverOnboardingHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	case errors.Is(err, models.ErrInvalidStatusTransition),
+		errors.Is(err, models.ErrOnboardingRequirementsOpen),
+		errors.Is(err, models.ErrOnboardingStageConflict):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailHandler handles the admin endpoints of the email notifications
+type EmailHandler struct {
+	notifier *service.EmailNotifier
+	now      func() time.Time
+}
+
+// NewEmailHandler creates a new EmailHandler instance. A nil notifier, when no SMTP server is
+// configured, reports email as unavailable.
+func NewEmailHandler(notifier *service.EmailNotifier) *EmailHandler {
+	return &EmailHandler{
+		notifier: notifier,
+		now:      time.Now,
+	}
+}
+
+// SendWeeklyEarningsRequest represents the request payload for sending the weekly earnings summaries
+type SendWeeklyEarningsRequest struct {
+	WeekStart string `json:"week_start" example:"2024-03-04"` // Monday the week starts on, YYYY-MM-DD; defaults to last week
+}
+
+// SendWeeklyEarningsResponse represents the response of sending the weekly earnings summaries
+type SendWeeklyEarningsResponse struct {
+	WeekStart string `json:"week_start"`
+	Queued    int    `json:"queued"` // summaries queued, one per driver who completed a trip in the week
+}
+
+// GetEmailStats handles the email delivery statistics
+// @Summary Get email delivery statistics
+// @Description Get how many emails were queued, sent, retried, given up on and dropped since the instance started, and how many are waiting to be sent
+// @Tags admin
+// @Produce json
+// @Success 200 {object} service.EmailStats
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/notifications/email/stats [get]
+func (h *EmailHandler) GetEmailStats(c *gin.Context) {
+	if h.notifier == nil {
+		h.unavailable(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, h.notifier.Stats())
+}
+
+// SendWeeklyEarnings handles sending drivers their weekly earnings summaries
+// @Summary Send the weekly earnings summaries
+// @Description Email every driver who completed a trip in the week a summary of their earnings, trips, hours online and rating
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body SendWeeklyEarningsRequest false "Week"
+// @Success 202 {object} SendWeeklyEarningsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/notifications/weekly-earnings [post]
+func (h *EmailHandler) SendWeeklyEarnings(c *gin.Context) {
+	if h.notifier == nil {
+		h.unavailable(c)
+		return
+	}
+
+	var req SendWeeklyEarningsRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request payload",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	weekStart := lastWeekStart(h.now())
+	if req.WeekStart != "" {
+		parsed, err := time.Parse("2006-01-02", req.WeekStart)
+		if err != nil || parsed.Weekday() != time.Monday {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid week start",
+				Message: "week_start must be a Monday in YYYY-MM-DD format",
+			})
+			return
+		}
+		weekStart = parsed
+	}
+
+	queued, err := h.notifier.SendWeeklyEarnings(c.Request.Context(), weekStart)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to send weekly earnings summaries",
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, SendWeeklyEarningsResponse{
+		WeekStart: weekStart.Format("2006-01-02"),
+		Queued:    queued,
+	})
+}
+
+// lastWeekStart returns the Monday, in UTC, of the week before the one now falls in
+func lastWeekStart(now time.Time) time.Time {
+	now = now.UTC()
+	daysSinceMonday := (int(now.Weekday()) + 6) % 7
+	return time.Date(now.Year(), now.Month(), now.Day()-daysSinceMonday-7, 0, 0, 0, 0, time.UTC)
+}
+
+func (h *EmailHandler) unavailable(c *gin.Context) {
+	c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+		Error:   "Email not available",
+		Message: "Email notifications are disabled",
+	})
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/timerange"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETAHandler handles the accuracy of the ETAs given to passengers
+type ETAHandler struct {
+	accuracyService *service.ETAAccuracyService
+	timeRanges      timerange.Parser
+}
+
+// NewETAHandler creates a new ETAHandler instance
+func NewETAHandler(accuracyService *service.ETAAccuracyService) *ETAHandler {
+	return &ETAHandler{
+		accuracyService: accuracyService,
+	}
+}
+
+// SetMaxTimeRange sets the longest range accuracy is computed over on request. Without one,
+// ranges may be of any length.
+func (h *ETAHandler) SetMaxTimeRange(max time.Duration) {
+	h.timeRanges.MaxWindow = max
+}
+
+// GetETAAccuracy handles the ETA accuracy metrics
+// @Summary Get ETA accuracy
+// @Description Get the accuracy of the ETAs to the pickup given when drivers were matched, measured against the drivers' arrivals: the mean absolute error and the bias (actual minus predicted, positive when drivers arrive late) overall and by pickup geohash zone and hour of day, with the average speed the ETA model assumes. Without start and end, the metrics recomputed periodically over the configured window are returned.
+// @Tags admin
+// @Produce json
+// @Param start query string false "Start of the matches: RFC3339, now, or an offset from now such as -7d; defaults to 24 hours before end"
+// @Param end query string false "End of the matches: RFC3339, now, or an offset from now; defaults to now"
+// @Success 200 {object} models.ETAAccuracyReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/eta/accuracy [get]
+func (h *ETAHandler) GetETAAccuracy(c *gin.Context) {
+	if c.Query("start") == "" && c.Query("end") == "" {
+		report := h.accuracyService.Latest()
+		if report == nil {
+			var err error
+			if report, err = h.accuracyService.Refresh(c.Request.Context()); err != nil {
+				h.writeError(c, err, "Failed to compute ETA accuracy")
+				return
+			}
+		}
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	window, ok := parseTimeRange(c, h.timeRanges, "start", "end", 24*time.Hour)
+	if !ok {
+		return
+	}
+
+	report, err := h.accuracyService.Compute(c.Request.Context(), window.Start, window.End)
+	if err != nil {
+		h.writeError(c, err, "Failed to compute ETA accuracy")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// writeError maps an ETA accuracy error to its HTTP response
+func (h *ETAHandler) writeError(c *gin.Context, err error, message string) {
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
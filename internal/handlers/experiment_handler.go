@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"actor-model-observability/internal/experiment"
+	"actor-model-observability/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExperimentHandler handles the experiment datasets observability records are isolated in
+type ExperimentHandler struct {
+	manager *experiment.Manager
+}
+
+// NewExperimentHandler creates a new ExperimentHandler instance. A nil manager, when the database
+// is not PostgreSQL, reports experiments as unavailable.
+func NewExperimentHandler(manager *experiment.Manager) *ExperimentHandler {
+	return &ExperimentHandler{
+		manager: manager,
+	}
+}
+
+// CreateExperimentRequest represents the request payload for creating an experiment
+type CreateExperimentRequest struct {
+	Name     string `json:"name" binding:"required" example:"surge_pricing_v2"`
+	Activate bool   `json:"activate"` // write observability records to the experiment right away
+}
+
+// SetActiveExperimentRequest represents the request payload for switching the active experiment
+type SetActiveExperimentRequest struct {
+	Name string `json:"name" example:"surge_pricing_v2"` // empty for the default schema
+}
+
+// ActiveExperimentResponse is the experiment observability records are written to
+type ActiveExperimentResponse struct {
+	Active *experiment.Experiment `json:"active"` // null for the default schema
+}
+
+// ExperimentListResponse lists the experiments
+type ExperimentListResponse struct {
+	Active      *experiment.Experiment   `json:"active"` // null for the default schema
+	Experiments []*experiment.Experiment `json:"experiments"`
+}
+
+// CreateExperiment handles creating an experiment
+// @Summary Create experiment
+// @Description Create an experiment run's dataset: a schema with empty copies of the observability tables, optionally writing observability records to it right away
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body CreateExperimentRequest true "Experiment"
+// @Success 201 {object} experiment.Experiment
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "The operator role is required"
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/experiments [post]
+func (h *ExperimentHandler) CreateExperiment(c *gin.Context) {
+	if h.manager == nil {
+		h.unavailable(c)
+		return
+	}
+
+	var req CreateExperimentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	created, err := h.manager.Create(c.Request.Context(), req.Name)
+	if err != nil {
+		h.writeError(c, err, "Failed to create experiment")
+		return
+	}
+	if req.Activate {
+		if created, err = h.manager.Activate(c.Request.Context(), req.Name); err != nil {
+			h.writeError(c, err, "Failed to activate experiment")
+			return
+		}
+	}
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// ListExperiments handles listing the experiments
+// @Summary List experiments
+// @Description List the experiment datasets by name, with the experiment observability records are currently written to. The active experiment is that of the instance serving the request.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} ExperimentListResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/experiments [get]
+func (h *ExperimentHandler) ListExperiments(c *gin.Context) {
+	if h.manager == nil {
+		h.unavailable(c)
+		return
+	}
+
+	experiments, err := h.manager.List(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err, "Failed to list experiments")
+		return
+	}
+
+	c.JSON(http.StatusOK, ExperimentListResponse{
+		Active:      h.manager.Active(),
+		Experiments: experiments,
+	})
+}
+
+// SetActiveExperiment handles switching the experiment observability records are written to
+// @Summary Switch active experiment
+// @Description Write observability records to an experiment's dataset, and serve observability queries from it, from now on; an empty name switches back to the default schema. Only the instance serving the request is switched.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body SetActiveExperimentRequest true "Experiment name"
+// @Success 200 {object} ActiveExperimentResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "The operator role is required"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/experiments/active [put]
+func (h *ExperimentHandler) SetActiveExperiment(c *gin.Context) {
+	if h.manager == nil {
+		h.unavailable(c)
+		return
+	}
+
+	var req SetActiveExperimentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	active, err := h.manager.Activate(c.Request.Context(), req.Name)
+	if err != nil {
+		h.writeError(c, err, "Failed to switch experiment")
+		return
+	}
+
+	c.JSON(http.StatusOK, ActiveExperimentResponse{Active: active})
+}
+
+// DropExperiment handles dropping an experiment
+// @Summary Drop experiment
+// @Description Drop an experiment's dataset with every record in it. The active experiment cannot be dropped; switch away from it first.
+// @Tags admin
+// @Param name path string true "Experiment name"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "The operator role is required"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/experiments/{name} [delete]
+func (h *ExperimentHandler) DropExperiment(c *gin.Context) {
+	if h.manager == nil {
+		h.unavailable(c)
+		return
+	}
+
+	if err := h.manager.Drop(c.Request.Context(), c.Param("name")); err != nil {
+		h.writeError(c, err, "Failed to drop experiment")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *ExperimentHandler) unavailable(c *gin.Context) {
+	c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+		Error:   "Experiments not available",
+		Message: "Experiments require PostgreSQL",
+	})
+}
+
+func (h *ExperimentHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	case errors.Is(err, experiment.ErrExperimentExists), errors.Is(err, experiment.ErrExperimentActive):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
@@ -0,0 +1,460 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// fareDisputeEntityType is the entity type of the audit event logs recorded for a dispute
+const fareDisputeEntityType = "fare_dispute"
+
+// maxDisputeAuditEvents caps the audit log returned for one dispute
+const maxDisputeAuditEvents = 100
+
+// FareDisputeHandler handles passenger fare disputes and their review by admins
+type FareDisputeHandler struct {
+	disputeService    service.FareDisputeServiceInterface
+	observabilityRepo repository.ObservabilityRepository
+}
+
+// NewFareDisputeHandler creates a new FareDisputeHandler instance. The dispute audit log is
+// read from the event logs in observabilityRepo.
+func NewFareDisputeHandler(disputeService service.FareDisputeServiceInterface, observabilityRepo repository.ObservabilityRepository) *FareDisputeHandler {
+	return &FareDisputeHandler{
+		disputeService:    disputeService,
+		observabilityRepo: observabilityRepo,
+	}
+}
+
+// OpenFareDisputeRequest represents the request payload for disputing a trip's fare
+type OpenFareDisputeRequest struct {
+	PassengerID   uuid.UUID `json:"passenger_id" binding:"required"`
+	Reason        string    `json:"reason" binding:"required,max=1000"`
+	RequestedFare *float64  `json:"requested_fare,omitempty" binding:"omitempty,min=0"`
+}
+
+// ReviewFareDisputeRequest represents the request payload for starting a dispute review
+type ReviewFareDisputeRequest struct {
+	ReviewedBy string `json:"reviewed_by" binding:"required,max=255"`
+}
+
+// ResolveFareDisputeRequest represents the request payload for resolving a dispute
+type ResolveFareDisputeRequest struct {
+	ReviewedBy   string   `json:"reviewed_by" binding:"required,max=255"`
+	AdjustedFare *float64 `json:"adjusted_fare" binding:"required,min=0"`
+	Note         string   `json:"note" binding:"max=1000"`
+}
+
+// RejectFareDisputeRequest represents the request payload for rejecting a dispute
+type RejectFareDisputeRequest struct {
+	ReviewedBy string `json:"reviewed_by" binding:"required,max=255"`
+	Note       string `json:"note" binding:"required,max=1000"`
+}
+
+// OpenFareDispute handles a passenger disputing the fare of a completed trip
+// @Summary Dispute a trip fare
+// @Description Open a dispute of the fare charged for a completed trip. A trip has at most one open dispute at a time.
+// @Tags fare-disputes
+// @Accept json
+// @Produce json
+// @Param id path string true "Trip ID"
+// @Param request body OpenFareDisputeRequest true "Dispute details"
+// @Success 201 {object} models.FareDispute
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/disputes [post]
+func (h *FareDisputeHandler) OpenFareDispute(c *gin.Context) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req OpenFareDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	dispute, err := h.disputeService.OpenDispute(c.Request.Context(), tripID.String(), req.PassengerID.String(), req.Reason, req.RequestedFare)
+	if err != nil {
+		h.writeError(c, err, "Failed to open fare dispute")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dispute)
+}
+
+// ListTripFareDisputes handles listing the disputes of a trip
+// @Summary List a trip's fare disputes
+// @Description Get every fare dispute opened on a trip, oldest first
+// @Tags fare-disputes
+// @Produce json
+// @Param id path string true "Trip ID"
+// @Success 200 {array} models.FareDispute
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/disputes [get]
+func (h *FareDisputeHandler) ListTripFareDisputes(c *gin.Context) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	disputes, _, err := h.disputeService.ListDisputes(c.Request.Context(), models.FareDisputeFilter{
+		TripID: &tripID,
+		Limit:  100,
+	})
+	if err != nil {
+		h.writeError(c, err, "Failed to list fare disputes")
+		return
+	}
+	if disputes == nil {
+		disputes = []*models.FareDispute{}
+	}
+
+	c.JSON(http.StatusOK, disputes)
+}
+
+// ListFareAdjustments handles listing a trip's fare adjustments ledger
+// @Summary List a trip's fare adjustments
+// @Description Get the fare adjustments ledger of a trip, oldest first. The trip's current fare is the original fare plus every adjustment amount.
+// @Tags fare-disputes
+// @Produce json
+// @Param id path string true "Trip ID"
+// @Success 200 {array} models.FareAdjustment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/fare-adjustments [get]
+func (h *FareDisputeHandler) ListFareAdjustments(c *gin.Context) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	adjustments, err := h.disputeService.ListAdjustments(c.Request.Context(), tripID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to list fare adjustments")
+		return
+	}
+	if adjustments == nil {
+		adjustments = []*models.FareAdjustment{}
+	}
+
+	c.JSON(http.StatusOK, adjustments)
+}
+
+// ListFareDisputes handles the admin review queue
+// @Summary List fare disputes
+// @Description Get fare disputes, oldest first, optionally filtered by status and trip
+// @Tags admin
+// @Produce json
+// @Param status query string false "open, under_review, resolved or rejected"
+// @Param trip_id query string false "Filter by trip ID"
+// @Param limit query int false "Number of items per page" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} PaginatedResponse{data=[]models.FareDispute}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/fare-disputes [get]
+func (h *FareDisputeHandler) ListFareDisputes(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer between 1 and 100",
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid offset",
+			Message: "Offset must be a non-negative integer",
+		})
+		return
+	}
+
+	filter := models.FareDisputeFilter{
+		Status: models.FareDisputeStatus(c.Query("status")),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid status",
+			Message: "Status must be one of open, under_review, resolved or rejected",
+		})
+		return
+	}
+	if tripID := c.Query("trip_id"); tripID != "" {
+		id, err := uuid.Parse(tripID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid trip ID",
+				Message: "trip_id must be a valid UUID",
+			})
+			return
+		}
+		filter.TripID = &id
+	}
+
+	disputes, total, err := h.disputeService.ListDisputes(c.Request.Context(), filter)
+	if err != nil {
+		h.writeError(c, err, "Failed to list fare disputes")
+		return
+	}
+	if disputes == nil {
+		disputes = []*models.FareDispute{}
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:    disputes,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: offset+len(disputes) < int(total),
+	})
+}
+
+// GetFareDispute handles retrieving a dispute
+// @Summary Get a fare dispute
+// @Description Get a fare dispute by ID
+// @Tags admin
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Success 200 {object} models.FareDispute
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/fare-disputes/{id} [get]
+func (h *FareDisputeHandler) GetFareDispute(c *gin.Context) {
+	disputeID, ok := parseUUIDParam(c, "id", "Invalid dispute ID", "Dispute ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	dispute, err := h.disputeService.GetDispute(c.Request.Context(), disputeID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to get fare dispute")
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// GetFareDisputeAuditLog handles retrieving the audit log of a dispute
+// @Summary Get a fare dispute's audit log
+// @Description Get the event logs recorded for every step of a fare dispute, newest first
+// @Tags admin
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Success 200 {array} models.EventLog
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/fare-disputes/{id}/audit [get]
+func (h *FareDisputeHandler) GetFareDisputeAuditLog(c *gin.Context) {
+	disputeID, ok := parseUUIDParam(c, "id", "Invalid dispute ID", "Dispute ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	if _, err := h.disputeService.GetDispute(c.Request.Context(), disputeID.String()); err != nil {
+		h.writeError(c, err, "Failed to get fare dispute audit log")
+		return
+	}
+
+	events, err := h.observabilityRepo.ListEventLogsByEntity(c.Request.Context(), fareDisputeEntityType, disputeID.String(), maxDisputeAuditEvents, 0)
+	if err != nil {
+		h.writeError(c, err, "Failed to get fare dispute audit log")
+		return
+	}
+	if events == nil {
+		events = []*models.EventLog{}
+	}
+
+	c.JSON(http.StatusOK, events)
+}
+
+// StartFareDisputeReview handles an admin taking an open dispute under review
+// @Summary Review a fare dispute
+// @Description Move an open fare dispute under review
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Param request body ReviewFareDisputeRequest true "Reviewer"
+// @Success 200 {object} models.FareDispute
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/fare-disputes/{id}/review [post]
+func (h *FareDisputeHandler) StartFareDisputeReview(c *gin.Context) {
+	disputeID, ok := parseUUIDParam(c, "id", "Invalid dispute ID", "Dispute ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req ReviewFareDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	dispute, err := h.disputeService.StartReview(c.Request.Context(), disputeID.String(), req.ReviewedBy)
+	if err != nil {
+		h.writeError(c, err, "Failed to start fare dispute review")
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// ResolveFareDispute handles an admin resolving a dispute with an adjusted fare
+// @Summary Resolve a fare dispute
+// @Description Resolve a fare dispute under review, charging the adjusted fare from now on. The change is recorded in the trip's fare adjustments ledger and sent to fare_dispute.resolved webhook subscribers.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Param request body ResolveFareDisputeRequest true "Decision"
+// @Success 200 {object} models.FareDispute
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "The operator role is required"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/fare-disputes/{id}/resolve [post]
+func (h *FareDisputeHandler) ResolveFareDispute(c *gin.Context) {
+	disputeID, ok := parseUUIDParam(c, "id", "Invalid dispute ID", "Dispute ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req ResolveFareDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	dispute, err := h.disputeService.Resolve(c.Request.Context(), disputeID.String(), req.ReviewedBy, *req.AdjustedFare, req.Note)
+	if err != nil {
+		h.writeError(c, err, "Failed to resolve fare dispute")
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// RejectFareDispute handles an admin rejecting a dispute
+// @Summary Reject a fare dispute
+// @Description Reject an open or under review fare dispute, leaving the fare unchanged. The decision is sent to fare_dispute.rejected webhook subscribers.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Param request body RejectFareDisputeRequest true "Decision"
+// @Success 200 {object} models.FareDispute
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/fare-disputes/{id}/reject [post]
+func (h *FareDisputeHandler) RejectFareDispute(c *gin.Context) {
+	disputeID, ok := parseUUIDParam(c, "id", "Invalid dispute ID", "Dispute ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req RejectFareDisputeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	dispute, err := h.disputeService.Reject(c.Request.Context(), disputeID.String(), req.ReviewedBy, req.Note)
+	if err != nil {
+		h.writeError(c, err, "Failed to reject fare dispute")
+		return
+	}
+
+	c.JSON(http.StatusOK, dispute)
+}
+
+// writeError maps a dispute workflow error to its HTTP response
+func (h *FareDisputeHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	case errors.Is(err, models.ErrUnauthorizedOperation):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Only the trip's passenger can dispute its fare",
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	case errors.Is(err, models.ErrInvalidStatusTransition),
+		errors.Is(err, models.ErrDisputeStatusConflict),
+		errors.Is(err, models.ErrDisputeAlreadyOpen),
+		errors.Is(err, models.ErrTripNotDisputable):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
+
+// parseUUIDParam parses a UUID path parameter, writing a 400 response and returning false when it is invalid
+func parseUUIDParam(c *gin.Context, name, errorTitle, errorMessage string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(name))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   errorTitle,
+			Message: errorMessage,
+		})
+		return uuid.Nil, false
+	}
+	return id, true
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parseFields reads the comma-separated fields query parameter and checks each name against
+// the JSON fields of model. It returns nil when no fields were requested; on an unknown field
+// it writes a 400 response and returns false.
+func parseFields(c *gin.Context, model interface{}) ([]string, bool) {
+	raw := c.Query("fields")
+	if raw == "" {
+		return nil, true
+	}
+
+	known := make(map[string]bool)
+	t := reflect.TypeOf(model)
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			known[name] = true
+		}
+	}
+
+	var fields []string
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		if !known[field] {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid fields",
+				Message: fmt.Sprintf("Unknown field %q", field),
+			})
+			return nil, false
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+
+	return fields, true
+}
+
+// selectFields trims each item of a list to the requested JSON fields. Items are returned
+// unchanged when no fields were requested.
+func selectFields(items interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 {
+		return items, nil
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &objects); err != nil {
+		return nil, err
+	}
+
+	for i, object := range objects {
+		selected := make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := object[field]; ok {
+				selected[field] = value
+			}
+		}
+		objects[i] = selected
+	}
+
+	return objects, nil
+}
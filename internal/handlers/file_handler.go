@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"actor-model-observability/internal/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FileHandler serves stored files by their signed URLs
+type FileHandler struct {
+	store storage.Store
+}
+
+// NewFileHandler creates a new FileHandler instance. Files are only served by stores that verify
+// their own signed URLs, such as the local store; others sign URLs of the object store.
+func NewFileHandler(store storage.Store) *FileHandler {
+	return &FileHandler{
+		store: store,
+	}
+}
+
+// GetFile handles retrieving a stored file
+// @Summary Get file
+// @Description Retrieve a stored file, such as a profile picture, by a signed URL returned in another payload. The URL is rejected once it expires.
+// @Tags files
+// @Produce octet-stream
+// @Param key path string true "File key"
+// @Param expires query int true "Expiry, in Unix seconds"
+// @Param signature query string true "Signature"
+// @Success 200 {file} file
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/files/{key} [get]
+func (h *FileHandler) GetFile(c *gin.Context) {
+	verifier, ok := h.store.(storage.URLVerifier)
+	if !ok {
+		h.notFound(c)
+		return
+	}
+
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if err := verifier.VerifyURL(key, c.Request.URL.Query()); err != nil {
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Forbidden",
+			Message: "The file URL is invalid or has expired",
+		})
+		return
+	}
+
+	object, err := h.store.Get(c.Request.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+		h.notFound(c)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get file",
+		})
+		return
+	}
+	defer object.Body.Close()
+
+	c.DataFromReader(http.StatusOK, object.Size, object.ContentType, object.Body, map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"Cache-Control":          "private, max-age=300",
+	})
+}
+
+// notFound responds that no file can be served
+func (h *FileHandler) notFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, ErrorResponse{
+		Error:   "File not found",
+		Message: "No file is stored under this key",
+	})
+}
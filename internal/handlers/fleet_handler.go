@@ -0,0 +1,360 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// maxFleetImportBytes caps the size of a driver status import file
+	maxFleetImportBytes = 1 << 20
+	// defaultFleetExportDays is the export period when from is omitted
+	defaultFleetExportDays = 30
+)
+
+// FleetHandler handles fleet partners and their bulk driver operations
+type FleetHandler struct {
+	fleetService *service.FleetService
+}
+
+// NewFleetHandler creates a new FleetHandler instance
+func NewFleetHandler(fleetService *service.FleetService) *FleetHandler {
+	return &FleetHandler{
+		fleetService: fleetService,
+	}
+}
+
+// CreateFleetRequest represents the request payload for creating a fleet
+type CreateFleetRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Timezone string `json:"timezone"`
+}
+
+// CreateFleetResponse is a new fleet with its API key, which is only returned here
+type CreateFleetResponse struct {
+	*models.Fleet
+	APIKey string `json:"api_key"`
+}
+
+// SetRequestQuotaRequest represents the request payload for changing a fleet's daily request
+// quota; a null or missing quota falls back to the default quota
+type SetRequestQuotaRequest struct {
+	DailyRequestQuota *int64 `json:"daily_request_quota" binding:"omitempty,min=1"`
+}
+
+// CreateFleet handles creating a fleet partner
+// @Summary Create a fleet
+// @Description Create a fleet partner and its API key. Fleet operators send the key in the X-Fleet-Key header; it is only returned here. Drivers join a fleet with fleet_id when they are created. The timezone, an IANA name defaulting to UTC, is the one daily earnings are grouped in.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body CreateFleetRequest true "Fleet details"
+// @Success 201 {object} CreateFleetResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/fleets [post]
+func (h *FleetHandler) CreateFleet(c *gin.Context) {
+	var req CreateFleetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	fleet, apiKey, err := h.fleetService.CreateFleet(c.Request.Context(), req.Name, req.Timezone)
+	if err != nil {
+		h.writeError(c, err, "Failed to create fleet")
+		return
+	}
+
+	c.JSON(http.StatusCreated, CreateFleetResponse{Fleet: fleet, APIKey: apiKey})
+}
+
+// SetRequestQuota handles changing the daily request quota of a fleet
+// @Summary Set a fleet request quota
+// @Description Set the number of requests the fleet's API key may make per UTC day, or fall back to the default quota with a null quota. Requests over the quota are rejected with 429; responses carry the X-Quota-Limit, X-Quota-Remaining and X-Quota-Reset headers. Requests made today count against a new quota.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Fleet ID"
+// @Param request body SetRequestQuotaRequest true "Daily request quota"
+// @Success 200 {object} models.Fleet
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/fleets/{id}/request-quota [put]
+func (h *FleetHandler) SetRequestQuota(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id", "Invalid fleet ID", "Fleet ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req SetRequestQuotaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	fleet, err := h.fleetService.SetDailyRequestQuota(c.Request.Context(), id.String(), req.DailyRequestQuota)
+	if err != nil {
+		h.writeError(c, err, "Failed to set request quota")
+		return
+	}
+
+	c.JSON(http.StatusOK, fleet)
+}
+
+// ImportDriverStatuses handles bulk driver status updates
+// @Summary Import driver statuses
+// @Description Set the statuses of the fleet's drivers from a CSV file, sent as the request body or as the file field of a multipart form. The header row must name the driver_id and status columns, in any order; other columns are ignored, so an edited driver export can be imported. Drivers can be set online or offline. Rows for drivers of other fleets, drivers on a trip or with invalid values fail individually while the other rows are applied; a malformed file is rejected as a whole.
+// @Tags fleet
+// @Accept text/csv
+// @Accept multipart/form-data
+// @Produce json
+// @Security fleet_key
+// @Param file formData file false "CSV file"
+// @Success 200 {object} models.FleetStatusImportResult
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/fleet/drivers/status [post]
+func (h *FleetHandler) ImportDriverStatuses(c *gin.Context) {
+	fleet, ok := h.fleet(c)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxFleetImportBytes)
+	var file io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request payload",
+				Message: "The multipart form must have a file field",
+			})
+			return
+		}
+		upload, err := header.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request payload",
+				Message: err.Error(),
+			})
+			return
+		}
+		defer upload.Close()
+		file = upload
+	}
+
+	result, err := h.fleetService.ImportDriverStatuses(c.Request.Context(), fleet, file)
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request payload",
+				Message: "The file must not exceed 1 MiB",
+			})
+			return
+		}
+		h.writeError(c, err, "Failed to import driver statuses")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// ExportDrivers handles exporting the fleet's drivers
+// @Summary Export fleet drivers
+// @Description Download the fleet's drivers as CSV, oldest first, with the columns driver_id, user_id, license_number, vehicle_type, vehicle_plate, status, rating and total_trips.
+// @Tags fleet
+// @Produce text/csv
+// @Security fleet_key
+// @Success 200 {string} string "CSV file"
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/fleet/drivers/export [get]
+func (h *FleetHandler) ExportDrivers(c *gin.Context) {
+	fleet, ok := h.fleet(c)
+	if !ok {
+		return
+	}
+
+	write, err := h.fleetService.ExportDrivers(c.Request.Context(), fleet)
+	if err != nil {
+		h.writeError(c, err, "Failed to export fleet drivers")
+		return
+	}
+
+	h.writeCSV(c, "fleet-drivers.csv", write)
+}
+
+// ExportTrips handles exporting the fleet's trips
+// @Summary Export fleet trips
+// @Description Download the trips of the fleet's drivers requested in [from, to) as CSV, oldest first, with the columns trip_id, driver_id, passenger_id, status, requested_at, completed_at, cancelled_at, distance_km, duration_minutes and fare_amount. Empty cells are unknown values.
+// @Tags fleet
+// @Produce text/csv
+// @Security fleet_key
+// @Param from query string false "Start time (RFC3339); defaults to 30 days before to"
+// @Param to query string false "End time (RFC3339); defaults to now"
+// @Success 200 {string} string "CSV file"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/fleet/trips/export [get]
+func (h *FleetHandler) ExportTrips(c *gin.Context) {
+	fleet, ok := h.fleet(c)
+	if !ok {
+		return
+	}
+	from, to, ok := parseExportPeriod(c)
+	if !ok {
+		return
+	}
+
+	write, err := h.fleetService.ExportTrips(c.Request.Context(), fleet, from, to)
+	if err != nil {
+		h.writeError(c, err, "Failed to export fleet trips")
+		return
+	}
+
+	h.writeCSV(c, "fleet-trips.csv", write)
+}
+
+// ExportEarnings handles exporting the fleet's earnings
+// @Summary Export fleet earnings
+// @Description Download the completed and cancelled trips and the fares earned per fleet driver over the trips requested in [from, to) as CSV, with the columns driver_id, vehicle_plate, trips_completed, trips_cancelled and earnings. Every driver of the fleet is listed. With group_by=day the totals are per driver and local date of the trip request instead, in a leading date column, listing only the days a driver had trips; dates are in tz or the fleet's timezone, so days the clocks change are 23 or 25 hours long.
+// @Tags fleet
+// @Produce text/csv
+// @Security fleet_key
+// @Param from query string false "Start time (RFC3339); defaults to 30 days before to"
+// @Param to query string false "End time (RFC3339); defaults to now"
+// @Param group_by query string false "day to total per local date"
+// @Param tz query string false "IANA timezone of the dates, e.g. Asia/Jakarta; defaults to the fleet's timezone"
+// @Success 200 {string} string "CSV file"
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/fleet/earnings/export [get]
+func (h *FleetHandler) ExportEarnings(c *gin.Context) {
+	fleet, ok := h.fleet(c)
+	if !ok {
+		return
+	}
+	from, to, ok := parseExportPeriod(c)
+	if !ok {
+		return
+	}
+
+	var write func(io.Writer) error
+	var err error
+	switch c.Query("group_by") {
+	case "":
+		write, err = h.fleetService.ExportEarnings(c.Request.Context(), fleet, from, to)
+	case "day":
+		write, err = h.fleetService.ExportDailyEarnings(c.Request.Context(), fleet, from, to, c.Query("tz"))
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid grouping",
+			Message: "group_by must be day",
+		})
+		return
+	}
+	if err != nil {
+		h.writeError(c, err, "Failed to export fleet earnings")
+		return
+	}
+
+	h.writeCSV(c, "fleet-earnings.csv", write)
+}
+
+// fleet returns the caller's fleet, responding with 401 when the request is not authenticated
+func (h *FleetHandler) fleet(c *gin.Context) (*models.Fleet, bool) {
+	fleet, ok := middleware.FleetFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "A fleet API key is required",
+		})
+		return nil, false
+	}
+	return fleet, true
+}
+
+// writeCSV responds with a CSV attachment written by write
+func (h *FleetHandler) writeCSV(c *gin.Context, filename string, write func(io.Writer) error) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	c.Status(http.StatusOK)
+	if err := write(c.Writer); err != nil {
+		// The status is already sent, so the truncated download is all the client sees
+		c.Error(err)
+	}
+}
+
+// writeError maps service errors to responses
+func (h *FleetHandler) writeError(c *gin.Context, err error, message string) {
+	var validation *models.ValidationError
+	if errors.As(err, &validation) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+		return
+	}
+	var notFound *models.NotFoundError
+	if errors.As(err, &notFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "Internal server error",
+		Message: message,
+	})
+}
+
+// parseExportPeriod parses the from and to query parameters, responding with 400 when invalid
+func parseExportPeriod(c *gin.Context) (time.Time, time.Time, bool) {
+	to := time.Now()
+	if v := c.Query("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid end time",
+				Message: "to must be in RFC3339 format",
+			})
+			return time.Time{}, time.Time{}, false
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -defaultFleetExportDays)
+	if v := c.Query("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid start time",
+				Message: "from must be in RFC3339 format",
+			})
+			return time.Time{}, time.Time{}, false
+		}
+		from = t
+	}
+	return from, to, true
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ForecastHandler handles ride demand forecasts
+type ForecastHandler struct {
+	forecastService *service.ForecastService
+}
+
+// NewForecastHandler creates a new ForecastHandler instance
+func NewForecastHandler(forecastService *service.ForecastService) *ForecastHandler {
+	return &ForecastHandler{
+		forecastService: forecastService,
+	}
+}
+
+// GetDemandForecast handles forecasting ride demand
+// @Summary Forecast ride demand
+// @Description Forecast the hourly ride requests of a zone from historical trips, with the accuracy of the method backtested on the most recent history, and the forecast totalled per local date. Hours are local hours of tz, so daily totals follow its calendar days, DST changes included. Zones are geohashes of the pickup location, e.g. qqguw; omit zone for all zones.
+// @Tags admin
+// @Produce json
+// @Param zone query string false "Geohash of 1 to 6 characters"
+// @Param horizon query int false "Hours to forecast" default(24)
+// @Param method query string false "moving_average or holt_winters; Holt-Winters when there are two days of history"
+// @Param tz query string false "IANA timezone of the hours and daily totals, e.g. Asia/Kolkata; defaults to REPORT_TIMEZONE"
+// @Success 200 {object} models.DemandForecast
+// @Failure 400 {object} ErrorResponse
+// @Failure 422 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/forecast [get]
+func (h *ForecastHandler) GetDemandForecast(c *gin.Context) {
+	horizon, err := strconv.Atoi(c.DefaultQuery("horizon", "24"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid horizon",
+			Message: "Horizon must be a number of hours",
+		})
+		return
+	}
+
+	forecast, err := h.forecastService.Forecast(c.Request.Context(), c.Query("zone"), horizon, c.Query("method"), c.Query("tz"))
+	if err != nil {
+		var validation *models.ValidationError
+		switch {
+		case errors.As(err, &validation):
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation error",
+				Message: err.Error(),
+			})
+		case errors.Is(err, models.ErrInsufficientForecastHistory):
+			c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Error:   "Insufficient history",
+				Message: err.Error(),
+				Code:    string(models.ErrorCodeOf(err)),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Internal server error",
+				Message: "Failed to forecast demand",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, forecast)
+}
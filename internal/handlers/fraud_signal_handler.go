@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// FraudSignalHandler handles the review of fraud signals by admins
+type FraudSignalHandler struct {
+	fraudService *service.FraudService
+}
+
+// NewFraudSignalHandler creates a new FraudSignalHandler instance
+func NewFraudSignalHandler(fraudService *service.FraudService) *FraudSignalHandler {
+	return &FraudSignalHandler{
+		fraudService: fraudService,
+	}
+}
+
+// ReviewFraudSignalRequest represents the request payload for deciding on a fraud signal
+type ReviewFraudSignalRequest struct {
+	Decision   models.FraudSignalStatus `json:"decision" binding:"required" example:"confirmed"` // confirmed or dismissed
+	ReviewedBy string                   `json:"reviewed_by" binding:"required,max=255"`
+	Note       string                   `json:"note" binding:"max=1000"`
+}
+
+// ListFraudSignals handles the admin review queue of fraud signals
+// @Summary List fraud signals
+// @Description Get the fraud signals raised by the detection rules, newest first, optionally filtered by rule, severity, status and flagged driver or passenger
+// @Tags admin
+// @Produce json
+// @Param rule query string false "impossible_speed, repeated_cancellations, gps_teleport or self_referral"
+// @Param severity query string false "low, medium or high"
+// @Param status query string false "open, confirmed or dismissed"
+// @Param subject_id query string false "Filter by flagged driver or passenger ID"
+// @Param limit query int false "Number of items per page" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} PaginatedResponse{data=[]models.FraudSignal}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/fraud-signals [get]
+func (h *FraudSignalHandler) ListFraudSignals(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer between 1 and 100",
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid offset",
+			Message: "Offset must be a non-negative integer",
+		})
+		return
+	}
+
+	filter := models.FraudSignalFilter{
+		Rule:     models.FraudRule(c.Query("rule")),
+		Severity: models.FraudSeverity(c.Query("severity")),
+		Status:   models.FraudSignalStatus(c.Query("status")),
+		Limit:    limit,
+		Offset:   offset,
+	}
+	if filter.Rule != "" && !filter.Rule.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid rule",
+			Message: "Rule must be one of impossible_speed, repeated_cancellations, gps_teleport or self_referral",
+		})
+		return
+	}
+	if filter.Severity != "" && !filter.Severity.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid severity",
+			Message: "Severity must be one of low, medium or high",
+		})
+		return
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid status",
+			Message: "Status must be one of open, confirmed or dismissed",
+		})
+		return
+	}
+	if subjectID := c.Query("subject_id"); subjectID != "" {
+		id, err := uuid.Parse(subjectID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid subject ID",
+				Message: "subject_id must be a valid UUID",
+			})
+			return
+		}
+		filter.SubjectID = &id
+	}
+
+	signals, total, err := h.fraudService.ListSignals(c.Request.Context(), filter)
+	if err != nil {
+		h.writeError(c, err, "Failed to list fraud signals")
+		return
+	}
+	if signals == nil {
+		signals = []*models.FraudSignal{}
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:    signals,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: offset+len(signals) < int(total),
+	})
+}
+
+// GetFraudSignal handles retrieving a fraud signal
+// @Summary Get a fraud signal
+// @Description Get a fraud signal by ID, with the evidence the rule found
+// @Tags admin
+// @Produce json
+// @Param id path string true "Fraud signal ID"
+// @Success 200 {object} models.FraudSignal
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/fraud-signals/{id} [get]
+func (h *FraudSignalHandler) GetFraudSignal(c *gin.Context) {
+	signalID, ok := parseUUIDParam(c, "id", "Invalid fraud signal ID", "Fraud signal ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	signal, err := h.fraudService.GetSignal(c.Request.Context(), signalID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to get fraud signal")
+		return
+	}
+
+	c.JSON(http.StatusOK, signal)
+}
+
+// ReviewFraudSignal handles an admin confirming or dismissing a fraud signal
+// @Summary Review a fraud signal
+// @Description Confirm or dismiss an open fraud signal. Decisions are final and counted per rule.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Fraud signal ID"
+// @Param request body ReviewFraudSignalRequest true "Decision"
+// @Success 200 {object} models.FraudSignal
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/fraud-signals/{id}/review [post]
+func (h *FraudSignalHandler) ReviewFraudSignal(c *gin.Context) {
+	signalID, ok := parseUUIDParam(c, "id", "Invalid fraud signal ID", "Fraud signal ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req ReviewFraudSignalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	signal, err := h.fraudService.Review(c.Request.Context(), signalID.String(), req.Decision, req.ReviewedBy, req.Note)
+	if err != nil {
+		h.writeError(c, err, "Failed to review fraud signal")
+		return
+	}
+
+	c.JSON(http.StatusOK, signal)
+}
+
+// writeError maps a fraud signal error to its HTTP response
+func (h *FraudSignalHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	case errors.Is(err, models.ErrInvalidStatusTransition),
+		errors.Is(err, models.ErrFraudSignalStatusConflict):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
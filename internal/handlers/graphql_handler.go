@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"actor-model-observability/internal/graphql"
+	"actor-model-observability/internal/redaction"
+	"actor-model-observability/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQLHandler serves the GraphQL facade over observability data
+type GraphQLHandler struct {
+	schema *graphql.Schema
+}
+
+// NewGraphQLHandler creates a new GraphQLHandler instance. redactor may be nil, in which case
+// message payloads and event data are returned unredacted.
+func NewGraphQLHandler(obsRepo repository.ObservabilityRepository, tripRepo repository.TripRepository, redactor *redaction.Redactor) *GraphQLHandler {
+	return &GraphQLHandler{
+		schema: graphql.NewObservabilitySchema(obsRepo, tripRepo, redactor),
+	}
+}
+
+// GraphQLRequest represents a GraphQL request body
+type GraphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Query handles GraphQL queries sent as a JSON body
+// @Summary Query observability data with GraphQL
+// @Description Fetch actor instances, messages, traces, events and trips in one request, following relations such as trip → events → messages → spans. Only queries are supported; list fields take limit (default 20, max 100) and offset.
+// @Tags observability
+// @Accept json
+// @Produce json
+// @Param request body GraphQLRequest true "GraphQL query"
+// @Success 200 {object} graphql.Result
+// @Failure 400 {object} graphql.Result
+// @Router /api/v1/graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var req GraphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	h.execute(c, graphql.Params{
+		Query:         req.Query,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+	})
+}
+
+// QueryGet handles GraphQL queries sent as URL parameters
+// @Summary Query observability data with GraphQL
+// @Description GET form of the GraphQL endpoint; variables are a JSON-encoded object
+// @Tags observability
+// @Produce json
+// @Param query query string true "GraphQL query"
+// @Param operationName query string false "Operation to run when the query has several"
+// @Param variables query string false "JSON-encoded variables"
+// @Success 200 {object} graphql.Result
+// @Failure 400 {object} graphql.Result
+// @Router /api/v1/graphql [get]
+func (h *GraphQLHandler) QueryGet(c *gin.Context) {
+	params := graphql.Params{
+		Query:         c.Query("query"),
+		OperationName: c.Query("operationName"),
+	}
+	if params.Query == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing query",
+			Message: "The query parameter is required",
+		})
+		return
+	}
+	if variables := c.Query("variables"); variables != "" {
+		if err := json.Unmarshal([]byte(variables), &params.Variables); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid variables",
+				Message: "variables must be a JSON object",
+			})
+			return
+		}
+	}
+
+	h.execute(c, params)
+}
+
+// execute runs the query. Requests rejected before execution (syntax, validation or variable
+// errors) get 400; field errors are reported alongside the partial data with 200.
+func (h *GraphQLHandler) execute(c *gin.Context, params graphql.Params) {
+	result := h.schema.Execute(c.Request.Context(), params)
+	if result.Data == nil {
+		c.JSON(http.StatusBadRequest, result)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/timerange"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HeatmapHandler handles trip origin/destination heatmaps
+type HeatmapHandler struct {
+	heatmapService *service.HeatmapService
+}
+
+// NewHeatmapHandler creates a new HeatmapHandler instance
+func NewHeatmapHandler(heatmapService *service.HeatmapService) *HeatmapHandler {
+	return &HeatmapHandler{
+		heatmapService: heatmapService,
+	}
+}
+
+// GetTripHeatmap handles trip heatmap aggregation
+// @Summary Get trip heatmap
+// @Description Count the pickups of the trips requested and the dropoffs of the trips completed in [start, end) per geohash cell, busiest cells first, for map visualizations. Results are cached briefly.
+// @Tags observability
+// @Produce json
+// @Param start query string false "Start time: RFC3339, now, or an offset from now such as -6h; defaults to 24 hours before end"
+// @Param end query string false "End time: RFC3339, now, or an offset from now; defaults to now"
+// @Param bucket query string false "Cell size, geohash3 to geohash7" default(geohash5)
+// @Success 200 {object} models.Heatmap
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/observability/heatmap [get]
+func (h *HeatmapHandler) GetTripHeatmap(c *gin.Context) {
+	// The service defaults and limits the range
+	start, ok := parseTimeParam(c, timerange.Parser{}, "start")
+	if !ok {
+		return
+	}
+	end, ok := parseTimeParam(c, timerange.Parser{}, "end")
+	if !ok {
+		return
+	}
+
+	heatmap, err := h.heatmapService.GetHeatmap(c.Request.Context(), start, end, c.Query("bucket"))
+	if err != nil {
+		var validation *models.ValidationError
+		if errors.As(err, &validation) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation error",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to build trip heatmap",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, heatmap)
+}
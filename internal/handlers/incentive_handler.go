@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IncentiveHandler handles driver incentive campaigns and quest progress
+type IncentiveHandler struct {
+	incentiveService service.IncentiveServiceInterface
+}
+
+// NewIncentiveHandler creates a new IncentiveHandler instance
+func NewIncentiveHandler(incentiveService service.IncentiveServiceInterface) *IncentiveHandler {
+	return &IncentiveHandler{
+		incentiveService: incentiveService,
+	}
+}
+
+// CreateIncentiveCampaignRequest represents the request payload for creating an incentive campaign
+type CreateIncentiveCampaignRequest struct {
+	Name          string    `json:"name" binding:"required,max=255"`
+	Description   *string   `json:"description,omitempty"`
+	RequiredTrips int       `json:"required_trips" binding:"required,min=1"`
+	BonusAmount   float64   `json:"bonus_amount" binding:"required,gt=0"`
+	StartsAt      time.Time `json:"starts_at" binding:"required"`
+	EndsAt        time.Time `json:"ends_at" binding:"required"`
+}
+
+// CreateIncentiveCampaign handles creating a driver quest
+// @Summary Create an incentive campaign
+// @Description Create a driver quest: drivers completing required_trips trips between starts_at and ends_at earn bonus_amount
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body CreateIncentiveCampaignRequest true "Campaign details"
+// @Success 201 {object} models.IncentiveCampaign
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/incentive-campaigns [post]
+func (h *IncentiveHandler) CreateIncentiveCampaign(c *gin.Context) {
+	var req CreateIncentiveCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	campaign, err := h.incentiveService.CreateCampaign(c.Request.Context(), &models.IncentiveCampaign{
+		Name:          req.Name,
+		Description:   req.Description,
+		RequiredTrips: req.RequiredTrips,
+		BonusAmount:   req.BonusAmount,
+		StartsAt:      req.StartsAt,
+		EndsAt:        req.EndsAt,
+	})
+	if err != nil {
+		h.writeError(c, err, "Failed to create incentive campaign")
+		return
+	}
+
+	c.JSON(http.StatusCreated, campaign)
+}
+
+// ListIncentiveCampaigns handles listing incentive campaigns
+// @Summary List incentive campaigns
+// @Description Get incentive campaigns ordered by start time
+// @Tags admin
+// @Produce json
+// @Param running query bool false "Only the active campaigns whose window contains the current time"
+// @Success 200 {array} models.IncentiveCampaign
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/incentive-campaigns [get]
+func (h *IncentiveHandler) ListIncentiveCampaigns(c *gin.Context) {
+	running, err := strconv.ParseBool(c.DefaultQuery("running", "false"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid running",
+			Message: "running must be true or false",
+		})
+		return
+	}
+
+	campaigns, err := h.incentiveService.ListCampaigns(c.Request.Context(), running)
+	if err != nil {
+		h.writeError(c, err, "Failed to list incentive campaigns")
+		return
+	}
+	if campaigns == nil {
+		campaigns = []*models.IncentiveCampaign{}
+	}
+
+	c.JSON(http.StatusOK, campaigns)
+}
+
+// ActivateIncentiveCampaign handles reactivating a campaign
+// @Summary Activate an incentive campaign
+// @Description Resume counting completed trips towards a campaign
+// @Tags admin
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} models.IncentiveCampaign
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/incentive-campaigns/{id}/activate [post]
+func (h *IncentiveHandler) ActivateIncentiveCampaign(c *gin.Context) {
+	h.setCampaignActive(c, true)
+}
+
+// DeactivateIncentiveCampaign handles pausing a campaign
+// @Summary Deactivate an incentive campaign
+// @Description Stop counting completed trips towards a campaign. Progress and payouts already recorded are kept.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} models.IncentiveCampaign
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/incentive-campaigns/{id}/deactivate [post]
+func (h *IncentiveHandler) DeactivateIncentiveCampaign(c *gin.Context) {
+	h.setCampaignActive(c, false)
+}
+
+func (h *IncentiveHandler) setCampaignActive(c *gin.Context, active bool) {
+	campaignID, ok := parseUUIDParam(c, "id", "Invalid campaign ID", "Campaign ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	campaign, err := h.incentiveService.SetCampaignActive(c.Request.Context(), campaignID.String(), active)
+	if err != nil {
+		h.writeError(c, err, "Failed to update incentive campaign")
+		return
+	}
+
+	c.JSON(http.StatusOK, campaign)
+}
+
+// GetDriverQuests handles retrieving a driver's active quests
+// @Summary Get a driver's quests
+// @Description Get the incentive campaigns running now with the driver's progress and payout on each
+// @Tags drivers
+// @Produce json
+// @Param id path string true "Driver ID"
+// @Success 200 {array} models.DriverQuest
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/quests [get]
+func (h *IncentiveHandler) GetDriverQuests(c *gin.Context) {
+	driverID, ok := parseUUIDParam(c, "id", "Invalid driver ID", "Driver ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	quests, err := h.incentiveService.GetDriverQuests(c.Request.Context(), driverID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to get driver quests")
+		return
+	}
+
+	c.JSON(http.StatusOK, quests)
+}
+
+// ListDriverIncentivePayouts handles listing a driver's incentive payouts
+// @Summary List a driver's incentive payouts
+// @Description Get the bonuses a driver earned by completing incentive campaigns, newest first
+// @Tags drivers
+// @Produce json
+// @Param id path string true "Driver ID"
+// @Success 200 {array} models.IncentivePayout
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/incentive-payouts [get]
+func (h *IncentiveHandler) ListDriverIncentivePayouts(c *gin.Context) {
+	driverID, ok := parseUUIDParam(c, "id", "Invalid driver ID", "Driver ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	payouts, err := h.incentiveService.ListDriverPayouts(c.Request.Context(), driverID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to list incentive payouts")
+		return
+	}
+	if payouts == nil {
+		payouts = []*models.IncentivePayout{}
+	}
+
+	c.JSON(http.StatusOK, payouts)
+}
+
+// writeError maps an incentive error to its HTTP response
+func (h *IncentiveHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// IntegrityHandler handles the integrity checks of the observability tables
+type IntegrityHandler struct {
+	integrityService *service.IntegrityService
+}
+
+// NewIntegrityHandler creates a new IntegrityHandler instance. Without a service, integrity
+// checks are reported as not available.
+func NewIntegrityHandler(integrityService *service.IntegrityService) *IntegrityHandler {
+	return &IntegrityHandler{
+		integrityService: integrityService,
+	}
+}
+
+// ListIntegrityRuns handles listing the latest integrity runs
+// @Summary List integrity runs
+// @Description Get the latest runs of the observability integrity check, newest first, with the window of finished hours each checksummed and the rows counted in it
+// @Tags admin
+// @Produce json
+// @Param limit query int false "Number of runs" default(24)
+// @Success 200 {array} models.IntegrityRun
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/integrity/runs [get]
+func (h *IntegrityHandler) ListIntegrityRuns(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "24"))
+	if err != nil || limit <= 0 || limit > 500 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer between 1 and 500",
+		})
+		return
+	}
+
+	runs, err := h.integrityService.ListRuns(c.Request.Context(), limit)
+	if err != nil {
+		h.writeError(c, err, "Failed to list integrity runs")
+		return
+	}
+	if runs == nil {
+		runs = []*models.IntegrityRun{}
+	}
+
+	c.JSON(http.StatusOK, runs)
+}
+
+// RunIntegrityCheck handles an admin running the integrity check now
+// @Summary Run integrity check
+// @Description Checksum the rows of every observability table per hour over the finished hours of the lookback now, instead of waiting for the next run, and compare them with the previous run. rows_missing counts the rows of hours in both windows that the previous run counted but this one no longer finds.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.IntegrityComparison
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/integrity/runs [post]
+func (h *IntegrityHandler) RunIntegrityCheck(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+
+	comparison, err := h.integrityService.Run(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err, "Failed to check observability integrity")
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// CompareIntegrityRuns handles comparing an integrity run with the run before it
+// @Summary Compare integrity runs
+// @Description Compare the per-hour row counts and checksums of a run, the latest by default, with those of the run before it over the hours both windows cover. Hours with fewer rows lost rows; hours with more rows got rows written late; hours with as many rows but another checksum had rows replaced.
+// @Tags admin
+// @Produce json
+// @Param run_id query string false "Run ID; the latest run when omitted"
+// @Success 200 {object} models.IntegrityComparison
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/integrity/compare [get]
+func (h *IntegrityHandler) CompareIntegrityRuns(c *gin.Context) {
+	if !h.available(c) {
+		return
+	}
+
+	runID := c.Query("run_id")
+	if runID != "" {
+		if _, err := uuid.Parse(runID); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid run ID",
+				Message: "run_id must be a valid UUID",
+			})
+			return
+		}
+	}
+
+	comparison, err := h.integrityService.Compare(c.Request.Context(), runID)
+	if err != nil {
+		h.writeError(c, err, "Failed to compare integrity runs")
+		return
+	}
+
+	c.JSON(http.StatusOK, comparison)
+}
+
+// available writes a 503 when integrity checks are not enabled
+func (h *IntegrityHandler) available(c *gin.Context) bool {
+	if h.integrityService == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Integrity checks not available",
+			Message: "Observability integrity checks are not enabled",
+		})
+		return false
+	}
+	return true
+}
+
+// writeError maps an integrity error to its HTTP response
+func (h *IntegrityHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	if errors.As(err, &notFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, ErrorResponse{
+		Error:   "Internal server error",
+		Message: message,
+	})
+}
@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InvoiceHandler handles the monthly invoices of corporate accounts and fleet partners
+type InvoiceHandler struct {
+	invoiceService *service.InvoiceService
+}
+
+// NewInvoiceHandler creates a new InvoiceHandler instance
+func NewInvoiceHandler(invoiceService *service.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoiceService: invoiceService,
+	}
+}
+
+// RunInvoicesRequest represents the request payload for generating the invoices of a month
+type RunInvoicesRequest struct {
+	Month string `json:"month,omitempty" example:"2026-09"`
+}
+
+// RunInvoices handles generating the invoices of a month
+// @Summary Generate invoices
+// @Description Generate the invoices of every corporate account and fleet partner for a UTC calendar month that is over, the previous month by default, as the monthly batch job does. Invoices already generated, and failed ones whose attempts are exhausted, are skipped; retry those individually.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body RunInvoicesRequest false "Month as YYYY-MM"
+// @Success 200 {object} models.InvoiceRun
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/invoices/runs [post]
+func (h *InvoiceHandler) RunInvoices(c *gin.Context) {
+	var req RunInvoicesRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request payload",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	run, err := h.invoiceService.Generate(c.Request.Context(), req.Month)
+	if err != nil {
+		h.writeError(c, err, "Failed to generate invoices")
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// ListInvoices handles listing invoices
+// @Summary List invoices
+// @Description List invoices with their generation status and signed download URLs of their files, newest month first
+// @Tags admin
+// @Produce json
+// @Param month query string false "Filter by month as YYYY-MM"
+// @Param party_type query string false "corporate_account or fleet"
+// @Param status query string false "pending, generated or failed"
+// @Param limit query int false "Number of items per page" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} PaginatedResponse{data=[]models.Invoice}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/invoices [get]
+func (h *InvoiceHandler) ListInvoices(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer between 1 and 100",
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid offset",
+			Message: "Offset must be a non-negative integer",
+		})
+		return
+	}
+
+	filter := models.InvoiceFilter{
+		Month:     c.Query("month"),
+		PartyType: models.InvoicePartyType(c.Query("party_type")),
+		Status:    models.InvoiceStatus(c.Query("status")),
+		Limit:     limit,
+		Offset:    offset,
+	}
+	if filter.PartyType != "" && !filter.PartyType.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid party type",
+			Message: "Party type must be one of corporate_account or fleet",
+		})
+		return
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid status",
+			Message: "Status must be one of pending, generated or failed",
+		})
+		return
+	}
+
+	invoices, total, err := h.invoiceService.List(c.Request.Context(), filter)
+	if err != nil {
+		h.writeError(c, err, "Failed to list invoices")
+		return
+	}
+	if invoices == nil {
+		invoices = []*models.Invoice{}
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:    invoices,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: offset+len(invoices) < int(total),
+	})
+}
+
+// GetInvoice handles retrieving an invoice
+// @Summary Get an invoice
+// @Description Get an invoice with its generation status and signed download URLs of its CSV and PDF files
+// @Tags admin
+// @Produce json
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} models.Invoice
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/invoices/{id} [get]
+func (h *InvoiceHandler) GetInvoice(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id", "Invalid invoice ID", "Invoice ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	invoice, err := h.invoiceService.Get(c.Request.Context(), id.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to get invoice")
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+// RetryInvoice handles generating an invoice again
+// @Summary Retry an invoice
+// @Description Generate an invoice again whatever its status, such as a failed invoice whose attempts are exhausted or one whose trips changed since, replacing its files. A failure is recorded on the invoice rather than returned as an error.
+// @Tags admin
+// @Produce json
+// @Param id path string true "Invoice ID"
+// @Success 200 {object} models.Invoice
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/invoices/{id}/retry [post]
+func (h *InvoiceHandler) RetryInvoice(c *gin.Context) {
+	id, ok := parseUUIDParam(c, "id", "Invalid invoice ID", "Invoice ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	invoice, err := h.invoiceService.Retry(c.Request.Context(), id.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to retry invoice")
+		return
+	}
+
+	c.JSON(http.StatusOK, invoice)
+}
+
+// writeError maps invoice errors to HTTP responses
+func (h *InvoiceHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
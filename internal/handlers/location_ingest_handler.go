@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LocationIngestHandler handles the admin endpoints of the driver location buffer
+type LocationIngestHandler struct {
+	locations *service.LocationIngester
+}
+
+// NewLocationIngestHandler creates a new LocationIngestHandler instance. A nil ingester, when
+// locations are written as they come, reports the buffer as unavailable.
+func NewLocationIngestHandler(locations *service.LocationIngester) *LocationIngestHandler {
+	return &LocationIngestHandler{
+		locations: locations,
+	}
+}
+
+// GetLocationIngestStats handles the driver location buffer statistics
+// @Summary Get driver location ingestion statistics
+// @Description Get how many drivers have a location not yet written to the database and how stale the oldest is, and how many updates were received, replaced by a newer one before being written, and written since the instance started. How far behind the database was when locations were written is in the driver_location_write_lag_seconds histogram, and the age of the locations matching used in driver_location_age_seconds.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} service.LocationIngestStats
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/locations/stats [get]
+func (h *LocationIngestHandler) GetLocationIngestStats(c *gin.Context) {
+	if h.locations == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Location buffer not available",
+			Message: "Driver locations are written to the database as they come",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.locations.Stats())
+}
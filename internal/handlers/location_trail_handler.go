@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LocationTrailHandler handles the location trails of drivers
+type LocationTrailHandler struct {
+	trailService *service.LocationTrailService
+}
+
+// NewLocationTrailHandler creates a new LocationTrailHandler instance
+func NewLocationTrailHandler(trailService *service.LocationTrailService) *LocationTrailHandler {
+	return &LocationTrailHandler{
+		trailService: trailService,
+	}
+}
+
+// GetDriverLocationTrail handles the location trail of a driver
+// @Summary Get a driver's location trail
+// @Description Get the locations a driver reported over a time window, oldest first, for support and fraud investigations. Pings older than a day are kept at one per minute and older than a week at one per ten minutes by default; a downsampled ping is the last of its interval and counts the pings it stands for. Requires the operator role.
+// @Tags admin
+// @Produce json
+// @Param X-Operator-Key header string true "Operator key"
+// @Param id path string true "Driver ID"
+// @Param start query string false "Start of the window (RFC3339); defaults to 24 hours before end"
+// @Param end query string false "End of the window (RFC3339); defaults to now"
+// @Success 200 {object} models.DriverLocationTrail
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/drivers/{id}/location-trail [get]
+func (h *LocationTrailHandler) GetDriverLocationTrail(c *gin.Context) {
+	end := time.Now()
+	if v := c.Query("end"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid end time",
+				Message: "End time must be in RFC3339 format",
+			})
+			return
+		}
+		end = t
+	}
+	start := end.Add(-24 * time.Hour)
+	if v := c.Query("start"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid start time",
+				Message: "Start time must be in RFC3339 format",
+			})
+			return
+		}
+		start = t
+	}
+
+	trail, err := h.trailService.GetTrail(c.Request.Context(), c.Param("id"), start, end)
+	if err != nil {
+		h.writeError(c, err, "Failed to get driver location trail")
+		return
+	}
+
+	c.JSON(http.StatusOK, trail)
+}
+
+// writeError maps a location trail error to its HTTP response
+func (h *LocationTrailHandler) writeError(c *gin.Context, err error, message string) {
+	var validation *models.ValidationError
+	var notFound *models.NotFoundError
+
+	switch {
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
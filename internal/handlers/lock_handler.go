@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"actor-model-observability/internal/lock"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LockHandler handles the admin view of the distributed locks
+type LockHandler struct {
+	locker *lock.Locker
+}
+
+// NewLockHandler creates a new LockHandler instance. A nil locker, when Redis is disabled,
+// reports locks as unavailable.
+func NewLockHandler(locker *lock.Locker) *LockHandler {
+	return &LockHandler{
+		locker: locker,
+	}
+}
+
+// LockListResponse lists the held locks
+type LockListResponse struct {
+	Owner string          `json:"owner"` // this instance
+	Locks []lock.LockInfo `json:"locks"`
+}
+
+// ListLocks handles listing the held locks
+// @Summary List held locks
+// @Description List the distributed locks currently held by any instance, with their owner and expiry. Locks held by the instance serving the request are marked local and carry when they were acquired.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} LockListResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/locks [get]
+func (h *LockHandler) ListLocks(c *gin.Context) {
+	if h.locker == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Locks not available",
+			Message: "Distributed locks require Redis",
+		})
+		return
+	}
+
+	locks, err := h.locker.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to list locks",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, LockListResponse{
+		Owner: h.locker.Owner(),
+		Locks: locks,
+	})
+}
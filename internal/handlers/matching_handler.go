@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MatchingHandler handles the admin endpoints of the traditional matching workers
+type MatchingHandler struct {
+	matcher *service.TraditionalMatcher
+}
+
+// NewMatchingHandler creates a new MatchingHandler instance. A nil matcher, when requests are
+// matched synchronously, reports the workers as unavailable.
+func NewMatchingHandler(matcher *service.TraditionalMatcher) *MatchingHandler {
+	return &MatchingHandler{
+		matcher: matcher,
+	}
+}
+
+// GetMatchingStats handles the traditional matching worker pool statistics
+// @Summary Get traditional matching statistics
+// @Description Get the matching workers of the traditional mode, how many requests are waiting for one, and how many were matched, rejected on a full queue or timed out waiting since the instance started. How long requests waited is in the matching_queue_wait_seconds histogram.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} service.MatchingStats
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/matching/stats [get]
+func (h *MatchingHandler) GetMatchingStats(c *gin.Context) {
+	if h.matcher == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Matching workers not available",
+			Message: "Ride requests are matched synchronously",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.matcher.Stats())
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"net/http"
+
+	"actor-model-observability/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCatalogResponse lists the error codes of the API
+type ErrorCatalogResponse struct {
+	Codes []models.ErrorCodeInfo `json:"codes"`
+}
+
+// MetaHandler handles the endpoints describing the API itself
+type MetaHandler struct{}
+
+// NewMetaHandler creates a new MetaHandler instance
+func NewMetaHandler() *MetaHandler {
+	return &MetaHandler{}
+}
+
+// GetErrorCatalog handles listing the error codes
+// @Summary List error codes
+// @Description List the stable machine-readable codes sent in the code field of every error response, with the HTTP status each is sent with. Clients should branch on the code rather than the error text; an error without a more specific code carries the generic code of its status, such as BAD_REQUEST or INTERNAL_ERROR.
+// @Tags meta
+// @Produce json
+// @Success 200 {object} ErrorCatalogResponse
+// @Router /api/v1/meta/errors [get]
+func (h *MetaHandler) GetErrorCatalog(c *gin.Context) {
+	c.JSON(http.StatusOK, ErrorCatalogResponse{Codes: models.ErrorCatalog()})
+}
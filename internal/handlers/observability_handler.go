@@ -2,19 +2,30 @@ package handlers
 
 import (
 	"fmt"
+	"io"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
+	"actor-model-observability/internal/filter"
 	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/internal/redaction"
 	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/timerange"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
-// ErrorResponse represents an error response
+// ErrorResponse represents an error response. Responses without a Code get the generic code of
+// their status from middleware.ErrorCodeMiddleware; GET /api/v1/meta/errors lists every code.
 type ErrorResponse struct {
 	Error   string `json:"error"`
 	Message string `json:"message,omitempty"`
+	Code    string `json:"code,omitempty"` // stable machine-readable code, see models.ErrorCatalog
 }
 
 // PaginatedResponse represents a paginated response
@@ -26,31 +37,124 @@ type PaginatedResponse struct {
 	HasMore bool        `json:"has_more"`
 }
 
+// MessageStatsResponse is the aggregate actor message statistics over a window
+type MessageStatsResponse struct {
+	Window string                 `json:"window"`
+	Stats  []*models.MessageStats `json:"stats"`
+}
+
+// SlowQueriesResponse lists the slowest repository calls
+type SlowQueriesResponse struct {
+	Threshold string              `json:"threshold"` // calls at least this slow are recorded
+	Queries   []*models.SlowQuery `json:"queries"`   // slowest first
+}
+
+// ActorLifecyclesResponse is the lifecycle timelines of actors over a window, for Gantt-style rendering
+type ActorLifecyclesResponse struct {
+	Start  time.Time                `json:"start"`
+	End    time.Time                `json:"end"`
+	Bucket string                   `json:"bucket"`
+	Actors []*models.ActorLifecycle `json:"actors"` // most recently spawned first
+}
+
+// Actor lifecycle windows
+const (
+	defaultActorLifecycleWindow = time.Hour
+	defaultActorLifecycleBucket = time.Minute
+	maxActorLifecycleBuckets    = 1000
+	maxActorLifecycleActors     = 100
+)
+
+// Message statistics windows
+const (
+	defaultMessageStatsWindow = time.Hour
+	maxMessageStatsWindow     = 7 * 24 * time.Hour
+)
+
+// eventStreamKeepAlive is how often an idle event stream sends a comment to keep proxies from
+// closing the connection
+const eventStreamKeepAlive = 15 * time.Second
+
 // ObservabilityHandler handles observability-related HTTP requests
 type ObservabilityHandler struct {
 	obsRepo         repository.ObservabilityRepository
 	traditionalRepo repository.TraditionalRepository
+	sloTracker      *observability.SLOTracker
+	eventStream     *observability.EventStream
+	redactor        *redaction.Redactor
+	slowQueries     *observability.SlowQueryLog
+	collector       *observability.MetricsCollector
+	timeRanges      timerange.Parser
 }
 
 // NewObservabilityHandler creates a new ObservabilityHandler instance
 func NewObservabilityHandler(
 	obsRepo repository.ObservabilityRepository,
 	traditionalRepo repository.TraditionalRepository,
+	sloTracker *observability.SLOTracker,
+	eventStream *observability.EventStream,
 ) *ObservabilityHandler {
 	return &ObservabilityHandler{
 		obsRepo:         obsRepo,
 		traditionalRepo: traditionalRepo,
+		sloTracker:      sloTracker,
+		eventStream:     eventStream,
 	}
 }
 
+// SetRedactor sets the redactor applied to message payloads and event data for callers
+// without the operator role. Without one, data is returned unredacted.
+func (h *ObservabilityHandler) SetRedactor(redactor *redaction.Redactor) {
+	h.redactor = redactor
+}
+
+// SetSlowQueryLog sets the log the slow repository calls are read from
+func (h *ObservabilityHandler) SetSlowQueryLog(log *observability.SlowQueryLog) {
+	h.slowQueries = log
+}
+
+// SetMetricsCollector sets the collector whose own counters are reported
+func (h *ObservabilityHandler) SetMetricsCollector(collector *observability.MetricsCollector) {
+	h.collector = collector
+}
+
+// SetMaxTimeRange sets the longest range the time-range queries accept. Without one, ranges
+// may be of any length.
+func (h *ObservabilityHandler) SetMaxTimeRange(max time.Duration) {
+	h.timeRanges.MaxWindow = max
+}
+
+// GetSLOReport handles SLO compliance reporting
+// @Summary Get SLO compliance
+// @Description Get latency and availability compliance and error budget burn for every endpoint with an SLO, over the configured rolling window
+// @Tags observability
+// @Produce json
+// @Success 200 {object} observability.SLOReport
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/observability/slo [get]
+func (h *ObservabilityHandler) GetSLOReport(c *gin.Context) {
+	if h.sloTracker == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "SLO tracking unavailable",
+			Message: "SLO tracking is not configured",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.sloTracker.Report())
+}
+
 // GetActorInstances handles actor instances listing
 // @Summary List actor instances
 // @Description Get a paginated list of actor instances
 // @Tags observability
 // @Produce json
 // @Param actor_type query string false "Filter by actor type"
+// @Param entity_type query string false "Filter by the type of business entity the actor represents, with entity_id" Enums(trip, driver, passenger)
+// @Param entity_id query string false "Filter by the ID of the business entity the actor represents, with entity_type"
 // @Param limit query int false "Number of items per page" default(20)
 // @Param offset query int false "Number of items to skip" default(0)
+// @Param fields query string false "Comma-separated list of fields to return"
 // @Success 200 {object} PaginatedResponse{data=[]models.ActorInstance}
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -79,8 +183,33 @@ func (h *ObservabilityHandler) GetActorInstances(c *gin.Context) {
 		return
 	}
 
+	entityType, entityID, ok := parseEntityFilter(c)
+	if !ok {
+		return
+	}
+
+	fields, ok := parseFields(c, models.ActorInstance{})
+	if !ok {
+		return
+	}
+	ctx := repository.WithColumns(c.Request.Context(), fields)
+
 	// Get actor instances from repository
-	actors, err := h.obsRepo.ListActorInstances(c.Request.Context(), actorType, limit, offset)
+	var actors []*models.ActorInstance
+	if entityType != "" {
+		actors, err = h.obsRepo.ListActorInstancesByEntity(ctx, entityType, entityID, limit, offset)
+	} else {
+		actors, err = h.obsRepo.ListActorInstances(ctx, actorType, limit, offset)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to list actor instances",
+		})
+		return
+	}
+
+	data, err := selectFields(actors, fields)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Internal server error",
@@ -91,24 +220,104 @@ func (h *ObservabilityHandler) GetActorInstances(c *gin.Context) {
 
 	// Return paginated response
 	c.JSON(http.StatusOK, PaginatedResponse{
-		Data:   actors,
+		Data:   data,
 		Limit:  limit,
 		Offset: offset,
 		Total:  int64(len(actors)),
 	})
 }
 
+// GetActorLifecycles handles actor lifecycle timelines
+// @Summary Get actor lifecycle timelines
+// @Description Get per-actor timelines of the window split into buckets, for Gantt-style rendering. Each segment is a run of buckets in which the actor was spawned, processing messages, idle, failed or stopped; buckets in which the actor was not alive have no segment.
+// @Tags observability
+// @Produce json
+// @Param start query string false "Start time: RFC3339, now, or an offset from now such as -15m; defaults to an hour before end"
+// @Param end query string false "End time: RFC3339, now, or an offset from now; defaults to now"
+// @Param bucket query string false "Bucket length as a Go duration; the window may span at most 1000 buckets" default(1m)
+// @Param actor_type query string false "Filter by actor type"
+// @Param limit query int false "Number of actors, the most recently spawned first, at most 100" default(20)
+// @Success 200 {object} ActorLifecyclesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/observability/actors/lifecycle [get]
+func (h *ObservabilityHandler) GetActorLifecycles(c *gin.Context) {
+	window, ok := parseTimeRange(c, h.timeRanges, "start", "end", defaultActorLifecycleWindow)
+	if !ok {
+		return
+	}
+	start, end := window.Start, window.End
+
+	bucket := defaultActorLifecycleBucket
+	if raw := c.Query("bucket"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed < time.Second {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid bucket",
+				Message: "Bucket must be a duration of at least 1s, such as 30s or 5m",
+			})
+			return
+		}
+		bucket = parsed
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > maxActorLifecycleActors {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit parameter",
+			Message: fmt.Sprintf("Limit must be between 1 and %d", maxActorLifecycleActors),
+		})
+		return
+	}
+
+	lf := models.ActorLifecycleFilter{
+		Start:     start,
+		End:       end,
+		Bucket:    bucket,
+		ActorType: c.Query("actor_type"),
+		Limit:     limit,
+	}
+	if lf.Buckets() > maxActorLifecycleBuckets {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Too many buckets",
+			Message: fmt.Sprintf("The window spans %d buckets of %s; use a longer bucket or a shorter window of at most %d buckets", lf.Buckets(), bucket, maxActorLifecycleBuckets),
+		})
+		return
+	}
+
+	activity, err := h.obsRepo.GetActorLifecycleActivity(c.Request.Context(), lf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get actor lifecycles",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ActorLifecyclesResponse{
+		Start:  start,
+		End:    end,
+		Bucket: bucket.String(),
+		Actors: observability.BuildActorLifecycles(lf, activity),
+	})
+}
+
 // GetActorMessages handles actor messages listing
 // @Summary List actor messages
-// @Description Get a paginated list of actor messages with optional filtering
+// @Description Get a paginated list of actor messages with optional filtering. Personal data in message payloads is redacted unless the request carries an operator key.
 // @Tags observability
 // @Produce json
 // @Param from_actor query string false "Filter by sender actor ID"
 // @Param to_actor query string false "Filter by receiver actor ID"
-// @Param start_time query string false "Start time (RFC3339 format)"
-// @Param end_time query string false "End time (RFC3339 format)"
+// @Param entity_type query string false "Filter by the type of business entity the message is about, with entity_id" Enums(trip, driver, passenger)
+// @Param entity_id query string false "Filter by the ID of the business entity the message is about, with entity_type"
+// @Param start_time query string false "Start time: RFC3339, now, or an offset from now such as -15m or now-2d; defaults to an hour before end_time"
+// @Param end_time query string false "End time: RFC3339, now, or an offset from now; defaults to now when start_time is given"
+// @Param filter query string false "Filter expression, such as status=failed AND processing_duration_ms>500; replaces the other filters"
 // @Param limit query int false "Number of items per page" default(20)
 // @Param offset query int false "Number of items to skip" default(0)
+// @Param fields query string false "Comma-separated list of fields to return"
+// @Param X-Operator-Key header string false "Operator key; returns unredacted data"
 // @Success 200 {object} PaginatedResponse{data=[]models.ActorMessage}
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -119,8 +328,6 @@ func (h *ObservabilityHandler) GetActorMessages(c *gin.Context) {
 	offsetStr := c.DefaultQuery("offset", "0")
 	fromActor := c.Query("from_actor")
 	toActor := c.Query("to_actor")
-	startTime := c.Query("start_time")
-	endTime := c.Query("end_time")
 
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 || limit > 100 {
@@ -140,14 +347,48 @@ func (h *ObservabilityHandler) GetActorMessages(c *gin.Context) {
 		return
 	}
 
+	timeRange, ok := parseOptionalTimeRange(c, h.timeRanges, "start_time", "end_time")
+	if !ok {
+		return
+	}
+
+	entityType, entityID, ok := parseEntityFilter(c)
+	if !ok {
+		return
+	}
+
+	expression, ok := parseFilter(c, filter.ActorMessageFields)
+	if !ok {
+		return
+	}
+
+	fields, ok := parseFields(c, models.ActorMessage{})
+	if !ok {
+		return
+	}
+	ctx := repository.WithColumns(c.Request.Context(), fields)
+
 	// Get messages from repository
 	var messages []*models.ActorMessage
-	if startTime != "" && endTime != "" {
-		messages, err = h.obsRepo.GetMessagesByTimeRange(c.Request.Context(), startTime, endTime, limit, offset)
+	if expression != nil {
+		messages, err = h.obsRepo.FilterActorMessages(ctx, expression, limit, offset)
+	} else if entityType != "" {
+		messages, err = h.obsRepo.ListActorMessagesByEntity(ctx, entityType, entityID, limit, offset)
+	} else if timeRange != nil {
+		messages, err = h.obsRepo.GetMessagesByTimeRange(ctx, formatRangeTime(timeRange.Start), formatRangeTime(timeRange.End), limit, offset)
 	} else {
-		messages, err = h.obsRepo.ListActorMessages(c.Request.Context(), fromActor, toActor, limit, offset)
+		messages, err = h.obsRepo.ListActorMessages(ctx, fromActor, toActor, limit, offset)
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to list actor messages",
+		})
+		return
 	}
 
+	data, err := selectFields(h.redactor.ActorMessages(ctx, messages), fields)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Internal server error",
@@ -158,23 +399,127 @@ func (h *ObservabilityHandler) GetActorMessages(c *gin.Context) {
 
 	// Return paginated response
 	c.JSON(http.StatusOK, PaginatedResponse{
-		Data:   messages,
+		Data:   data,
 		Limit:  limit,
 		Offset: offset,
 		Total:  0, // Would need separate count query in real implementation
 	})
 }
 
+// GetMessageStats handles aggregate actor message statistics
+// @Summary Get actor message statistics
+// @Description Get message counts, failure ratios and p50/p95/p99 processing durations grouped by message type and receiving actor type, over the messages created within the window
+// @Tags observability
+// @Produce json
+// @Param window query string false "Window as a Go duration, at most 168h" default(1h)
+// @Success 200 {object} MessageStatsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/observability/messages/stats [get]
+func (h *ObservabilityHandler) GetMessageStats(c *gin.Context) {
+	window := defaultMessageStatsWindow
+	if raw := c.Query("window"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 || parsed > maxMessageStatsWindow {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid window",
+				Message: fmt.Sprintf("Window must be a positive duration of at most %s, such as 15m or 24h", maxMessageStatsWindow),
+			})
+			return
+		}
+		window = parsed
+	}
+
+	stats, err := h.obsRepo.GetMessageStats(c.Request.Context(), window)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get message statistics",
+		})
+		return
+	}
+	if stats == nil {
+		stats = []*models.MessageStats{}
+	}
+
+	c.JSON(http.StatusOK, MessageStatsResponse{Window: window.String(), Stats: stats})
+}
+
+// GetSlowQueries handles slow repository call listing
+// @Summary List slow queries
+// @Description Get the slowest repository calls at or above the slow query threshold, slowest first, with their sanitized parameters
+// @Tags observability
+// @Produce json
+// @Param limit query int false "Number of calls to return, at most the configured top N" default(20)
+// @Success 200 {object} SlowQueriesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/observability/slow-queries [get]
+func (h *ObservabilityHandler) GetSlowQueries(c *gin.Context) {
+	if h.slowQueries == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Slow query log unavailable",
+			Message: "Slow query logging is disabled",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer",
+		})
+		return
+	}
+
+	queries, err := h.slowQueries.Top(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get slow queries",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SlowQueriesResponse{
+		Threshold: h.slowQueries.Threshold().String(),
+		Queries:   queries,
+	})
+}
+
+// GetCollectorStatus handles the metrics collector status
+// @Summary Get metrics collector status
+// @Description Get the metrics collector's own counters: records buffered for the next flush, flush count and durations, failed batches and dropped records by kind, and failed Redis calls. falling_behind is set when a flush took the flush interval or longer, or none finished within two.
+// @Tags observability
+// @Produce json
+// @Success 200 {object} observability.CollectorStats
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/observability/collector/status [get]
+func (h *ObservabilityHandler) GetCollectorStatus(c *gin.Context) {
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Metrics collector unavailable",
+			Message: "The metrics collector is not running",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.collector.Stats())
+}
+
 // GetSystemMetrics handles system metrics listing
 // @Summary List system metrics
 // @Description Get a paginated list of system metrics with optional filtering
 // @Tags observability
 // @Produce json
 // @Param metric_type query string false "Filter by metric type"
-// @Param start_time query string false "Start time (RFC3339 format)"
-// @Param end_time query string false "End time (RFC3339 format)"
+// @Param start_time query string false "Start time: RFC3339, now, or an offset from now such as -15m or now-2d; defaults to an hour before end_time"
+// @Param end_time query string false "End time: RFC3339, now, or an offset from now; defaults to now when start_time is given"
 // @Param limit query int false "Number of items per page" default(20)
 // @Param offset query int false "Number of items to skip" default(0)
+// @Param fields query string false "Comma-separated list of fields to return"
 // @Success 200 {object} PaginatedResponse{data=[]models.SystemMetric}
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -184,8 +529,6 @@ func (h *ObservabilityHandler) GetSystemMetrics(c *gin.Context) {
 	limitStr := c.DefaultQuery("limit", "20")
 	offsetStr := c.DefaultQuery("offset", "0")
 	metricType := c.Query("metric_type")
-	startTime := c.Query("start_time")
-	endTime := c.Query("end_time")
 
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 || limit > 100 {
@@ -205,14 +548,34 @@ func (h *ObservabilityHandler) GetSystemMetrics(c *gin.Context) {
 		return
 	}
 
+	timeRange, ok := parseOptionalTimeRange(c, h.timeRanges, "start_time", "end_time")
+	if !ok {
+		return
+	}
+
+	fields, ok := parseFields(c, models.SystemMetric{})
+	if !ok {
+		return
+	}
+	ctx := repository.WithColumns(c.Request.Context(), fields)
+
 	// Get system metrics from repository
 	var metrics []*models.SystemMetric
-	if startTime != "" && endTime != "" {
-		metrics, err = h.obsRepo.GetMetricsByTimeRange(c.Request.Context(), startTime, endTime, limit, offset)
+	if timeRange != nil {
+		metrics, err = h.obsRepo.GetMetricsByTimeRange(ctx, formatRangeTime(timeRange.Start), formatRangeTime(timeRange.End), limit, offset)
 	} else {
-		metrics, err = h.obsRepo.ListSystemMetrics(c.Request.Context(), metricType, limit, offset)
+		metrics, err = h.obsRepo.ListSystemMetrics(ctx, metricType, limit, offset)
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to list system metrics",
+		})
+		return
 	}
 
+	data, err := selectFields(metrics, fields)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Internal server error",
@@ -223,7 +586,7 @@ func (h *ObservabilityHandler) GetSystemMetrics(c *gin.Context) {
 
 	// Return paginated response
 	c.JSON(http.StatusOK, PaginatedResponse{
-		Data:   metrics,
+		Data:   data,
 		Limit:  limit,
 		Offset: offset,
 		Total:  0, // Would need separate count query in real implementation
@@ -237,8 +600,10 @@ func (h *ObservabilityHandler) GetSystemMetrics(c *gin.Context) {
 // @Produce json
 // @Param operation query string false "Filter by operation name"
 // @Param trace_id query string false "Get all spans for a specific trace ID"
+// @Param filter query string false "Filter expression, such as status=error AND duration_ms>=1000; replaces operation"
 // @Param limit query int false "Number of items per page" default(20)
 // @Param offset query int false "Number of items to skip" default(0)
+// @Param fields query string false "Comma-separated list of fields to return"
 // @Success 200 {object} PaginatedResponse{data=[]models.DistributedTrace}
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -268,14 +633,36 @@ func (h *ObservabilityHandler) GetDistributedTraces(c *gin.Context) {
 		return
 	}
 
+	expression, ok := parseFilter(c, filter.DistributedTraceFields)
+	if !ok {
+		return
+	}
+
+	fields, ok := parseFields(c, models.DistributedTrace{})
+	if !ok {
+		return
+	}
+	ctx := repository.WithColumns(c.Request.Context(), fields)
+
 	// Get distributed traces from repository
 	var traces []*models.DistributedTrace
 	if traceID != "" {
-		traces, err = h.obsRepo.GetTracesByTraceID(c.Request.Context(), traceID)
+		traces, err = h.obsRepo.GetTracesByTraceID(ctx, traceID)
+	} else if expression != nil {
+		traces, err = h.obsRepo.FilterDistributedTraces(ctx, expression, limit, offset)
 	} else {
-		traces, err = h.obsRepo.ListDistributedTraces(c.Request.Context(), operation, limit, offset)
+		traces, err = h.obsRepo.ListDistributedTraces(ctx, operation, limit, offset)
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to list distributed traces",
+		})
+		return
 	}
 
+	data, err := selectFields(traces, fields)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Internal server error",
@@ -286,7 +673,7 @@ func (h *ObservabilityHandler) GetDistributedTraces(c *gin.Context) {
 
 	// Return paginated response
 	c.JSON(http.StatusOK, PaginatedResponse{
-		Data:   traces,
+		Data:   data,
 		Limit:  limit,
 		Offset: offset,
 		Total:  0, // Would need separate count query in real implementation
@@ -295,15 +682,20 @@ func (h *ObservabilityHandler) GetDistributedTraces(c *gin.Context) {
 
 // GetEventLogs handles event logs listing
 // @Summary List event logs
-// @Description Get a paginated list of event logs with optional filtering
+// @Description Get a paginated list of event logs with optional filtering. Personal data in event data is redacted unless the request carries an operator key.
 // @Tags observability
 // @Produce json
 // @Param event_type query string false "Filter by event type"
 // @Param source query string false "Filter by source (actor ID)"
-// @Param start_time query string false "Start time (RFC3339 format)"
-// @Param end_time query string false "End time (RFC3339 format)"
+// @Param entity_type query string false "Filter by the type of business entity the event is about, with entity_id" Enums(trip, driver, passenger)
+// @Param entity_id query string false "Filter by the ID of the business entity the event is about, with entity_type"
+// @Param start_time query string false "Start time: RFC3339, now, or an offset from now such as -15m or now-2d; defaults to an hour before end_time"
+// @Param end_time query string false "End time: RFC3339, now, or an offset from now; defaults to now when start_time is given"
+// @Param filter query string false "Filter expression, such as severity>=warn AND actor_type=trip AND message~timeout; replaces the other filters"
 // @Param limit query int false "Number of items per page" default(20)
 // @Param offset query int false "Number of items to skip" default(0)
+// @Param fields query string false "Comma-separated list of fields to return"
+// @Param X-Operator-Key header string false "Operator key; returns unredacted data"
 // @Success 200 {object} PaginatedResponse{data=[]models.EventLog}
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -314,8 +706,6 @@ func (h *ObservabilityHandler) GetEventLogs(c *gin.Context) {
 	offsetStr := c.DefaultQuery("offset", "0")
 	eventType := c.Query("event_type")
 	source := c.Query("source")
-	startTime := c.Query("start_time")
-	endTime := c.Query("end_time")
 
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 || limit > 100 {
@@ -335,14 +725,48 @@ func (h *ObservabilityHandler) GetEventLogs(c *gin.Context) {
 		return
 	}
 
+	timeRange, ok := parseOptionalTimeRange(c, h.timeRanges, "start_time", "end_time")
+	if !ok {
+		return
+	}
+
+	entityType, entityID, ok := parseEntityFilter(c)
+	if !ok {
+		return
+	}
+
+	expression, ok := parseFilter(c, filter.EventLogFields)
+	if !ok {
+		return
+	}
+
+	fields, ok := parseFields(c, models.EventLog{})
+	if !ok {
+		return
+	}
+	ctx := repository.WithColumns(c.Request.Context(), fields)
+
 	// Get event logs from repository
 	var logs []*models.EventLog
-	if startTime != "" && endTime != "" {
-		logs, err = h.obsRepo.GetEventLogsByTimeRange(c.Request.Context(), startTime, endTime, limit, offset)
+	if expression != nil {
+		logs, err = h.obsRepo.FilterEventLogs(ctx, expression, limit, offset)
+	} else if entityType != "" {
+		logs, err = h.obsRepo.ListEventLogsByEntity(ctx, entityType, entityID, limit, offset)
+	} else if timeRange != nil {
+		logs, err = h.obsRepo.GetEventLogsByTimeRange(ctx, formatRangeTime(timeRange.Start), formatRangeTime(timeRange.End), limit, offset)
 	} else {
-		logs, err = h.obsRepo.ListEventLogs(c.Request.Context(), eventType, source, limit, offset)
+		logs, err = h.obsRepo.ListEventLogs(ctx, eventType, source, limit, offset)
+	}
+
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to list event logs",
+		})
+		return
 	}
 
+	data, err := selectFields(h.redactor.EventLogs(ctx, logs), fields)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Internal server error",
@@ -353,13 +777,91 @@ func (h *ObservabilityHandler) GetEventLogs(c *gin.Context) {
 
 	// Return paginated response
 	c.JSON(http.StatusOK, PaginatedResponse{
-		Data:   logs,
+		Data:   data,
 		Limit:  limit,
 		Offset: offset,
 		Total:  0, // Would need separate count query in real implementation
 	})
 }
 
+// StreamEventLogs handles live event log streaming
+// @Summary Stream event logs
+// @Description Stream new event logs as Server-Sent Events while they are recorded. Each event_log event carries one EventLog as JSON; a keep-alive comment is sent every 15 seconds. Events are not replayed, and a client too slow to keep up misses events. Personal data in event data is redacted unless the request carries an operator key.
+// @Tags observability
+// @Produce text/event-stream
+// @Param severity query string false "Filter by severity" Enums(debug, info, warn, error, fatal)
+// @Param category query string false "Filter by event category" Enums(business, system, error, performance, security)
+// @Param actor_type query string false "Filter by actor type"
+// @Param X-Operator-Key header string false "Operator key; returns unredacted data"
+// @Success 200 {object} models.EventLog
+// @Failure 400 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/observability/events/stream [get]
+func (h *ObservabilityHandler) StreamEventLogs(c *gin.Context) {
+	if h.eventStream == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Event stream unavailable",
+			Message: "Live event streaming is not enabled",
+		})
+		return
+	}
+
+	filter := observability.EventFilter{
+		Severity:  models.EventSeverity(c.Query("severity")),
+		Category:  models.EventCategory(c.Query("category")),
+		ActorType: models.ActorType(c.Query("actor_type")),
+	}
+
+	switch filter.Severity {
+	case "", models.EventSeverityDebug, models.EventSeverityInfo, models.EventSeverityWarn, models.EventSeverityError, models.EventSeverityFatal:
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid severity",
+			Message: "Severity must be one of debug, info, warn, error, fatal",
+		})
+		return
+	}
+
+	switch filter.Category {
+	case "", models.EventCategoryBusiness, models.EventCategorySystem, models.EventCategoryError, models.EventCategoryPerformance, models.EventCategorySecurity:
+	default:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid category",
+			Message: "Category must be one of business, system, error, performance, security",
+		})
+		return
+	}
+
+	events, unsubscribe := h.eventStream.Subscribe(filter)
+	defer unsubscribe()
+
+	// The stream outlives the server write timeout
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event := <-events:
+			c.SSEvent("event_log", h.redactor.EventLog(c.Request.Context(), event))
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		}
+	})
+}
+
 // GetTraditionalPrometheusMetrics handles traditional metrics in Prometheus format
 // @Summary Get traditional metrics in Prometheus format
 // @Description Get traditional monitoring metrics in Prometheus format for scraping
@@ -455,8 +957,8 @@ func (h *ObservabilityHandler) GetTraditionalPrometheusMetrics(c *gin.Context) {
 // @Produce json
 // @Param metric_type query string false "Filter by metric type"
 // @Param service_name query string false "Filter by service name"
-// @Param start_time query string false "Start time (RFC3339 format)"
-// @Param end_time query string false "End time (RFC3339 format)"
+// @Param start_time query string false "Start time: RFC3339, now, or an offset from now such as -15m or now-2d; defaults to an hour before end_time"
+// @Param end_time query string false "End time: RFC3339, now, or an offset from now; defaults to now when start_time is given"
 // @Param limit query int false "Number of items per page" default(20)
 // @Param offset query int false "Number of items to skip" default(0)
 // @Success 200 {object} PaginatedResponse{data=[]models.TraditionalMetric}
@@ -469,8 +971,6 @@ func (h *ObservabilityHandler) GetTraditionalMetrics(c *gin.Context) {
 	offsetStr := c.DefaultQuery("offset", "0")
 	metricType := c.Query("metric_type")
 	serviceName := c.Query("service_name")
-	startTime := c.Query("start_time")
-	endTime := c.Query("end_time")
 
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 || limit > 100 {
@@ -490,10 +990,15 @@ func (h *ObservabilityHandler) GetTraditionalMetrics(c *gin.Context) {
 		return
 	}
 
+	timeRange, ok := parseOptionalTimeRange(c, h.timeRanges, "start_time", "end_time")
+	if !ok {
+		return
+	}
+
 	// Get traditional metrics from repository
 	var metrics []*models.TraditionalMetric
-	if startTime != "" && endTime != "" {
-		metrics, err = h.traditionalRepo.GetTraditionalMetricsByTimeRange(c.Request.Context(), startTime, endTime, limit, offset)
+	if timeRange != nil {
+		metrics, err = h.traditionalRepo.GetTraditionalMetricsByTimeRange(c.Request.Context(), formatRangeTime(timeRange.Start), formatRangeTime(timeRange.End), limit, offset)
 	} else {
 		metrics, err = h.traditionalRepo.ListTraditionalMetrics(c.Request.Context(), metricType, serviceName, limit, offset)
 	}
@@ -522,8 +1027,8 @@ func (h *ObservabilityHandler) GetTraditionalMetrics(c *gin.Context) {
 // @Produce json
 // @Param level query string false "Filter by log level"
 // @Param service_name query string false "Filter by service name"
-// @Param start_time query string false "Start time (RFC3339 format)"
-// @Param end_time query string false "End time (RFC3339 format)"
+// @Param start_time query string false "Start time: RFC3339, now, or an offset from now such as -15m or now-2d; defaults to an hour before end_time"
+// @Param end_time query string false "End time: RFC3339, now, or an offset from now; defaults to now when start_time is given"
 // @Param limit query int false "Number of items per page" default(20)
 // @Param offset query int false "Number of items to skip" default(0)
 // @Success 200 {object} PaginatedResponse{data=[]models.TraditionalLog}
@@ -536,8 +1041,6 @@ func (h *ObservabilityHandler) GetTraditionalLogs(c *gin.Context) {
 	offsetStr := c.DefaultQuery("offset", "0")
 	level := c.Query("level")
 	serviceName := c.Query("service_name")
-	startTime := c.Query("start_time")
-	endTime := c.Query("end_time")
 
 	limit, err := strconv.Atoi(limitStr)
 	if err != nil || limit <= 0 || limit > 100 {
@@ -557,10 +1060,15 @@ func (h *ObservabilityHandler) GetTraditionalLogs(c *gin.Context) {
 		return
 	}
 
+	timeRange, ok := parseOptionalTimeRange(c, h.timeRanges, "start_time", "end_time")
+	if !ok {
+		return
+	}
+
 	// Get traditional logs from repository
 	var logs []*models.TraditionalLog
-	if startTime != "" && endTime != "" {
-		logs, err = h.traditionalRepo.GetTraditionalLogsByTimeRange(c.Request.Context(), startTime, endTime, limit, offset)
+	if timeRange != nil {
+		logs, err = h.traditionalRepo.GetTraditionalLogsByTimeRange(c.Request.Context(), formatRangeTime(timeRange.Start), formatRangeTime(timeRange.End), limit, offset)
 	} else {
 		logs, err = h.traditionalRepo.ListTraditionalLogs(c.Request.Context(), level, serviceName, limit, offset)
 	}
@@ -871,6 +1379,11 @@ func (h *ObservabilityHandler) GetPrometheusMetrics(c *gin.Context) {
 		prometheusMetrics += "actor_message_processing_duration_ms_count 1235\n"
 	}
 
+	// Add the metrics collector's own counters
+	if h.collector != nil {
+		prometheusMetrics += collectorPrometheusMetrics(h.collector.Stats())
+	}
+
 	// Add some basic application metrics
 	prometheusMetrics += "# HELP actor_system_up Application up status\n"
 	prometheusMetrics += "# TYPE actor_system_up gauge\n"
@@ -879,3 +1392,86 @@ func (h *ObservabilityHandler) GetPrometheusMetrics(c *gin.Context) {
 	c.Header("Content-Type", "text/plain; charset=utf-8")
 	c.String(http.StatusOK, prometheusMetrics)
 }
+
+// collectorPrometheusMetrics formats the metrics collector's own counters in Prometheus format
+func collectorPrometheusMetrics(stats *observability.CollectorStats) string {
+	kinds := make([]string, 0, len(stats.Buffered))
+	for kind := range stats.Buffered {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	var b strings.Builder
+	byKind := func(name, help, metricType string, value func(kind string) int64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+		for _, kind := range kinds {
+			fmt.Fprintf(&b, "%s{kind=\"%s\"} %d\n", name, kind, value(kind))
+		}
+	}
+	single := func(name, help, metricType string, value interface{}) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, metricType, name, value)
+	}
+
+	byKind("observability_collector_buffered_records", "Records waiting for the next flush of the metrics collector", "gauge",
+		func(kind string) int64 { return int64(stats.Buffered[kind]) })
+	byKind("observability_collector_failed_flushes_total", "Batches the metrics collector failed to insert", "counter",
+		func(kind string) int64 { return stats.FailedFlushes[kind] })
+	byKind("observability_collector_dropped_records_total", "Records dropped with the batches the metrics collector failed to insert", "counter",
+		func(kind string) int64 { return stats.DroppedRecords[kind] })
+	single("observability_collector_flushes_total", "Flushes of the metrics collector", "counter", stats.Flushes)
+	single("observability_collector_last_flush_duration_seconds", "Duration of the last flush of the metrics collector", "gauge", stats.LastFlushDurationMs/1000)
+	single("observability_collector_redis_errors_total", "Failed Redis calls of the metrics collector for real-time data", "counter", stats.RedisErrors)
+	fallingBehind := 0
+	if stats.FallingBehind {
+		fallingBehind = 1
+	}
+	single("observability_collector_falling_behind", "Whether the metrics collector falls behind its flush interval", "gauge", fallingBehind)
+	return b.String()
+}
+
+// parseEntityFilter reads the entity_type and entity_id query parameters, which filter records
+// by the business entity they are linked to. Both are empty when no entity filter was given; when
+// only one is given or the ID isn't a UUID it writes a 400 response and returns false.
+func parseEntityFilter(c *gin.Context) (string, string, bool) {
+	entityType := c.Query("entity_type")
+	entityID := c.Query("entity_id")
+	if entityType == "" && entityID == "" {
+		return "", "", true
+	}
+
+	if entityType == "" || entityID == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid entity filter",
+			Message: "entity_type and entity_id must be given together",
+		})
+		return "", "", false
+	}
+	if _, err := uuid.Parse(entityID); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid entity filter",
+			Message: "entity_id must be a valid UUID",
+		})
+		return "", "", false
+	}
+	return entityType, entityID, true
+}
+
+// parseFilter reads the filter query parameter, an expression over the fields of schema. It
+// returns nil when no filter was given; when the expression is invalid it writes a 400
+// response and returns false.
+func parseFilter(c *gin.Context, schema filter.Schema) (*filter.Filter, bool) {
+	expression := c.Query("filter")
+	if expression == "" {
+		return nil, true
+	}
+
+	f, err := filter.Parse(expression, schema)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid filter",
+			Message: err.Error(),
+		})
+		return nil, false
+	}
+	return f, true
+}
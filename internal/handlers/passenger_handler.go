@@ -0,0 +1,397 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PassengerHandler handles passenger saved locations, recent destinations and ride preferences
+type PassengerHandler struct {
+	passengerRepo     repository.PassengerRepository
+	savedLocationRepo repository.SavedLocationRepository
+	tripRepo          repository.TripRepository
+}
+
+// NewPassengerHandler creates a new PassengerHandler instance
+func NewPassengerHandler(
+	passengerRepo repository.PassengerRepository,
+	savedLocationRepo repository.SavedLocationRepository,
+	tripRepo repository.TripRepository,
+) *PassengerHandler {
+	return &PassengerHandler{
+		passengerRepo:     passengerRepo,
+		savedLocationRepo: savedLocationRepo,
+		tripRepo:          tripRepo,
+	}
+}
+
+// SavedLocationRequest represents the request payload for creating or replacing a saved location
+type SavedLocationRequest struct {
+	Label     string  `json:"label" binding:"required,oneof=home work custom"`
+	Name      string  `json:"name" binding:"required"`
+	Latitude  float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	Address   *string `json:"address,omitempty"`
+}
+
+// ListSavedLocations handles listing a passenger's saved locations
+// @Summary List saved locations
+// @Description Get a passenger's saved locations, home and work first
+// @Tags passengers
+// @Produce json
+// @Param id path string true "Passenger ID"
+// @Success 200 {array} models.SavedLocation
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/passengers/{id}/locations [get]
+func (h *PassengerHandler) ListSavedLocations(c *gin.Context) {
+	passengerID, ok := h.passengerID(c)
+	if !ok {
+		return
+	}
+
+	locations, err := h.savedLocationRepo.ListByPassengerID(c.Request.Context(), passengerID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to list saved locations",
+		})
+		return
+	}
+	if locations == nil {
+		locations = []*models.SavedLocation{}
+	}
+
+	c.JSON(http.StatusOK, locations)
+}
+
+// CreateSavedLocation handles saving a new location for a passenger
+// @Summary Create a saved location
+// @Description Save a home, work or custom location for a passenger. A passenger has at most one home and one work location.
+// @Tags passengers
+// @Accept json
+// @Produce json
+// @Param id path string true "Passenger ID"
+// @Param request body SavedLocationRequest true "Saved location details"
+// @Success 201 {object} models.SavedLocation
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/passengers/{id}/locations [post]
+func (h *PassengerHandler) CreateSavedLocation(c *gin.Context) {
+	passengerID, ok := h.passengerID(c)
+	if !ok {
+		return
+	}
+
+	var req SavedLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	now := time.Now()
+	location := &models.SavedLocation{
+		ID:          uuid.New(),
+		PassengerID: passengerID,
+		CreatedAt:   now,
+	}
+	req.apply(location, now)
+
+	if err := location.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.savedLocationRepo.Create(c.Request.Context(), location); err != nil {
+		h.writeRepositoryError(c, err, "Failed to create saved location")
+		return
+	}
+
+	c.JSON(http.StatusCreated, location)
+}
+
+// UpdateSavedLocation handles replacing a passenger's saved location
+// @Summary Update a saved location
+// @Description Replace the label, name and coordinates of a saved location
+// @Tags passengers
+// @Accept json
+// @Produce json
+// @Param id path string true "Passenger ID"
+// @Param location_id path string true "Saved location ID"
+// @Param request body SavedLocationRequest true "Saved location details"
+// @Success 200 {object} models.SavedLocation
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/passengers/{id}/locations/{location_id} [put]
+func (h *PassengerHandler) UpdateSavedLocation(c *gin.Context) {
+	location, ok := h.ownedLocation(c)
+	if !ok {
+		return
+	}
+
+	var req SavedLocationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+	req.apply(location, time.Now())
+
+	if err := location.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.savedLocationRepo.Update(c.Request.Context(), location); err != nil {
+		h.writeRepositoryError(c, err, "Failed to update saved location")
+		return
+	}
+
+	c.JSON(http.StatusOK, location)
+}
+
+// DeleteSavedLocation handles removing a passenger's saved location
+// @Summary Delete a saved location
+// @Tags passengers
+// @Param id path string true "Passenger ID"
+// @Param location_id path string true "Saved location ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/passengers/{id}/locations/{location_id} [delete]
+func (h *PassengerHandler) DeleteSavedLocation(c *gin.Context) {
+	location, ok := h.ownedLocation(c)
+	if !ok {
+		return
+	}
+
+	if err := h.savedLocationRepo.Delete(c.Request.Context(), location.ID.String()); err != nil {
+		h.writeRepositoryError(c, err, "Failed to delete saved location")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetRecentDestinations handles listing the places a passenger travelled to recently
+// @Summary Get recent destinations
+// @Description Get a passenger's distinct trip destinations, most recently visited first
+// @Tags passengers
+// @Produce json
+// @Param id path string true "Passenger ID"
+// @Param limit query int false "Maximum number of destinations" default(10)
+// @Success 200 {array} models.RecentDestination
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/passengers/{id}/recent-destinations [get]
+func (h *PassengerHandler) GetRecentDestinations(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 || limit > 50 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer between 1 and 50",
+		})
+		return
+	}
+
+	passengerID, ok := h.passengerID(c)
+	if !ok {
+		return
+	}
+
+	destinations, err := h.tripRepo.GetRecentDestinations(c.Request.Context(), passengerID.String(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get recent destinations",
+		})
+		return
+	}
+	if destinations == nil {
+		destinations = []*models.RecentDestination{}
+	}
+
+	c.JSON(http.StatusOK, destinations)
+}
+
+// GetRidePreferences handles retrieving a passenger's ride preferences
+// @Summary Get ride preferences
+// @Description Get the preferences applied when matching a driver with the passenger's rides, unless a ride request overrides them
+// @Tags passengers
+// @Produce json
+// @Param id path string true "Passenger ID"
+// @Success 200 {object} models.RidePreferences
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/passengers/{id}/ride-preferences [get]
+func (h *PassengerHandler) GetRidePreferences(c *gin.Context) {
+	passenger, ok := h.passenger(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, passenger.RidePreferences)
+}
+
+// UpdateRidePreferences handles replacing a passenger's ride preferences
+// @Summary Update ride preferences
+// @Description Replace the preferences applied when matching a driver with the passenger's rides. Accessible vehicle and pet friendly are required of the matched driver; the minimum driver rating and quiet ride are relaxed when no nearby driver meets them.
+// @Tags passengers
+// @Accept json
+// @Produce json
+// @Param id path string true "Passenger ID"
+// @Param request body models.RidePreferences true "Ride preferences"
+// @Success 200 {object} models.RidePreferences
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/passengers/{id}/ride-preferences [put]
+func (h *PassengerHandler) UpdateRidePreferences(c *gin.Context) {
+	var req models.RidePreferences
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	passenger, ok := h.passenger(c)
+	if !ok {
+		return
+	}
+	passenger.RidePreferences = req
+	passenger.UpdatedAt = time.Now()
+
+	if err := h.passengerRepo.Update(c.Request.Context(), passenger); err != nil {
+		h.writeRepositoryError(c, err, "Failed to update ride preferences")
+		return
+	}
+
+	c.JSON(http.StatusOK, passenger.RidePreferences)
+}
+
+// apply copies the request fields onto location
+func (req *SavedLocationRequest) apply(location *models.SavedLocation, now time.Time) {
+	location.Label = models.SavedLocationLabel(req.Label)
+	location.Name = req.Name
+	location.Latitude = req.Latitude
+	location.Longitude = req.Longitude
+	location.Address = req.Address
+	location.UpdatedAt = now
+}
+
+// passengerID parses the passenger ID path parameter and checks the passenger exists,
+// writing the error response and returning false otherwise
+func (h *PassengerHandler) passengerID(c *gin.Context) (uuid.UUID, bool) {
+	passenger, ok := h.passenger(c)
+	if !ok {
+		return uuid.Nil, false
+	}
+	return passenger.ID, true
+}
+
+// passenger loads the passenger in the path, writing the error response and returning false
+// when the ID is invalid or the passenger does not exist
+func (h *PassengerHandler) passenger(c *gin.Context) (*models.Passenger, bool) {
+	passengerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid passenger ID",
+			Message: "Passenger ID must be a valid UUID",
+		})
+		return nil, false
+	}
+
+	passenger, err := h.passengerRepo.GetByID(c.Request.Context(), passengerID.String())
+	if err != nil {
+		h.writeRepositoryError(c, err, "Failed to get passenger")
+		return nil, false
+	}
+
+	return passenger, true
+}
+
+// ownedLocation loads the saved location in the path, answering 404 when it belongs to another passenger
+func (h *PassengerHandler) ownedLocation(c *gin.Context) (*models.SavedLocation, bool) {
+	passengerID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid passenger ID",
+			Message: "Passenger ID must be a valid UUID",
+		})
+		return nil, false
+	}
+
+	locationID, err := uuid.Parse(c.Param("location_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid location ID",
+			Message: "Location ID must be a valid UUID",
+		})
+		return nil, false
+	}
+
+	location, err := h.savedLocationRepo.GetByID(c.Request.Context(), locationID.String())
+	if err == nil && location.PassengerID != passengerID {
+		err = &models.NotFoundError{Resource: "saved location", ID: locationID.String()}
+	}
+	if err != nil {
+		h.writeRepositoryError(c, err, "Failed to get saved location")
+		return nil, false
+	}
+
+	return location, true
+}
+
+// writeRepositoryError maps repository errors to HTTP responses
+func (h *PassengerHandler) writeRepositoryError(c *gin.Context, err error, message string) {
+	switch err.(type) {
+	case *models.ValidationError:
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	case *models.NotFoundError:
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
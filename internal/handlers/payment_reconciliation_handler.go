@@ -0,0 +1,321 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PaymentReconciliationHandler handles the trip payments ledger and the resolution of payment
+// discrepancies by admins
+type PaymentReconciliationHandler struct {
+	paymentService *service.PaymentReconciliationService
+}
+
+// NewPaymentReconciliationHandler creates a new PaymentReconciliationHandler instance
+func NewPaymentReconciliationHandler(paymentService *service.PaymentReconciliationService) *PaymentReconciliationHandler {
+	return &PaymentReconciliationHandler{
+		paymentService: paymentService,
+	}
+}
+
+// RecordTripPaymentRequest represents the request payload for recording a trip payment
+type RecordTripPaymentRequest struct {
+	Kind       models.PaymentKind   `json:"kind" binding:"required" example:"charge"` // charge or refund
+	Method     models.PaymentMethod `json:"method" binding:"required" example:"card"` // card, cash, wallet or corporate
+	Amount     float64              `json:"amount" binding:"required,gt=0" example:"25.50"`
+	Reference  string               `json:"reference" binding:"max=255"` // payment provider's reference
+	RecordedBy string               `json:"recorded_by" binding:"max=255"`
+}
+
+// ResolvePaymentDiscrepancyRequest represents the request payload for resolving a payment
+// discrepancy
+type ResolvePaymentDiscrepancyRequest struct {
+	Resolution models.PaymentResolution `json:"resolution" binding:"required" example:"record_payment"` // record_payment, refund, write_off or dismiss
+	Method     models.PaymentMethod     `json:"method" example:"card"`                                  // of the charge or refund; required for record_payment and refund
+	Reference  string                   `json:"reference" binding:"max=255"`
+	ResolvedBy string                   `json:"resolved_by" binding:"required,max=255"`
+	Note       string                   `json:"note" binding:"max=1000"`
+}
+
+// ListTripPayments handles retrieving a trip's payments ledger
+// @Summary List trip payments
+// @Description Get the charges and refunds recorded for a trip, oldest first
+// @Tags admin
+// @Produce json
+// @Param id path string true "Trip ID"
+// @Success 200 {array} models.TripPayment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/trips/{id}/payments [get]
+func (h *PaymentReconciliationHandler) ListTripPayments(c *gin.Context) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	payments, err := h.paymentService.ListPayments(c.Request.Context(), tripID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to list trip payments")
+		return
+	}
+	if payments == nil {
+		payments = []*models.TripPayment{}
+	}
+
+	c.JSON(http.StatusOK, payments)
+}
+
+// RecordTripPayment handles recording a charge or refund of a completed trip
+// @Summary Record a trip payment
+// @Description Append a charge or refund to a completed trip's payments ledger. Open discrepancies of the trip are settled once its payments balance its fare, and a missing payment discrepancy once it has any payment.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Trip ID"
+// @Param request body RecordTripPaymentRequest true "Payment"
+// @Success 201 {object} models.TripPayment
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/trips/{id}/payments [post]
+func (h *PaymentReconciliationHandler) RecordTripPayment(c *gin.Context) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req RecordTripPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	payment := &models.TripPayment{
+		TripID: tripID,
+		Kind:   req.Kind,
+		Method: req.Method,
+		Amount: req.Amount,
+	}
+	if req.Reference != "" {
+		payment.Reference = &req.Reference
+	}
+	if req.RecordedBy != "" {
+		payment.RecordedBy = &req.RecordedBy
+	}
+
+	payment, err := h.paymentService.RecordPayment(c.Request.Context(), payment)
+	if err != nil {
+		h.writeError(c, err, "Failed to record trip payment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, payment)
+}
+
+// ListPaymentDiscrepancies handles the admin queue of payment discrepancies
+// @Summary List payment discrepancies
+// @Description Get the completed trips reconciliation found unpaid or paid the wrong amount, newest first, optionally filtered by type, status and trip
+// @Tags admin
+// @Produce json
+// @Param type query string false "missing_payment or amount_mismatch"
+// @Param status query string false "open, resolved or dismissed"
+// @Param trip_id query string false "Filter by trip ID"
+// @Param limit query int false "Number of items per page" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} PaginatedResponse{data=[]models.PaymentDiscrepancy}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/payments/discrepancies [get]
+func (h *PaymentReconciliationHandler) ListPaymentDiscrepancies(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer between 1 and 100",
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid offset",
+			Message: "Offset must be a non-negative integer",
+		})
+		return
+	}
+
+	filter := models.PaymentDiscrepancyFilter{
+		Type:   models.PaymentDiscrepancyType(c.Query("type")),
+		Status: models.PaymentDiscrepancyStatus(c.Query("status")),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if filter.Type != "" && !filter.Type.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid type",
+			Message: "Type must be one of missing_payment or amount_mismatch",
+		})
+		return
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid status",
+			Message: "Status must be one of open, resolved or dismissed",
+		})
+		return
+	}
+	if tripID := c.Query("trip_id"); tripID != "" {
+		id, err := uuid.Parse(tripID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid trip ID",
+				Message: "trip_id must be a valid UUID",
+			})
+			return
+		}
+		filter.TripID = &id
+	}
+
+	discrepancies, total, err := h.paymentService.ListDiscrepancies(c.Request.Context(), filter)
+	if err != nil {
+		h.writeError(c, err, "Failed to list payment discrepancies")
+		return
+	}
+	if discrepancies == nil {
+		discrepancies = []*models.PaymentDiscrepancy{}
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:    discrepancies,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: offset+len(discrepancies) < int(total),
+	})
+}
+
+// GetPaymentDiscrepancy handles retrieving a payment discrepancy
+// @Summary Get a payment discrepancy
+// @Description Get a payment discrepancy by ID, with the fare and the amount paid when it was found
+// @Tags admin
+// @Produce json
+// @Param id path string true "Payment discrepancy ID"
+// @Success 200 {object} models.PaymentDiscrepancy
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/payments/discrepancies/{id} [get]
+func (h *PaymentReconciliationHandler) GetPaymentDiscrepancy(c *gin.Context) {
+	discrepancyID, ok := parseUUIDParam(c, "id", "Invalid payment discrepancy ID", "Payment discrepancy ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	discrepancy, err := h.paymentService.GetDiscrepancy(c.Request.Context(), discrepancyID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to get payment discrepancy")
+		return
+	}
+
+	c.JSON(http.StatusOK, discrepancy)
+}
+
+// ResolvePaymentDiscrepancy handles an admin resolving a payment discrepancy
+// @Summary Resolve a payment discrepancy
+// @Description Resolve an open payment discrepancy: record_payment charges what the trip is short of its fare and refund refunds what it was paid over it, both with the given method; write_off accepts the difference and dismiss closes a discrepancy that is not one. Resolutions are final and counted by type and action.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Payment discrepancy ID"
+// @Param request body ResolvePaymentDiscrepancyRequest true "Resolution"
+// @Success 200 {object} models.PaymentDiscrepancy
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "The operator role is required"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/payments/discrepancies/{id}/resolve [post]
+func (h *PaymentReconciliationHandler) ResolvePaymentDiscrepancy(c *gin.Context) {
+	discrepancyID, ok := parseUUIDParam(c, "id", "Invalid payment discrepancy ID", "Payment discrepancy ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req ResolvePaymentDiscrepancyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	discrepancy, err := h.paymentService.Resolve(c.Request.Context(), discrepancyID.String(), req.Resolution, req.Method, req.Reference, req.ResolvedBy, req.Note)
+	if err != nil {
+		h.writeError(c, err, "Failed to resolve payment discrepancy")
+		return
+	}
+
+	c.JSON(http.StatusOK, discrepancy)
+}
+
+// ReconcilePayments handles an admin running the payment reconciliation now
+// @Summary Reconcile trip payments
+// @Description Check the trips completed within the lookback, and longer than the grace period ago, against their payments now, instead of waiting for the next run. Trips found unpaid or paid the wrong amount open a discrepancy unless one of the same type is still open.
+// @Tags admin
+// @Produce json
+// @Success 200 {object} models.PaymentReconciliationRun
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/payments/reconcile [post]
+func (h *PaymentReconciliationHandler) ReconcilePayments(c *gin.Context) {
+	run, err := h.paymentService.Reconcile(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err, "Failed to reconcile trip payments")
+		return
+	}
+
+	c.JSON(http.StatusOK, run)
+}
+
+// writeError maps a payment error to its HTTP response
+func (h *PaymentReconciliationHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	case errors.Is(err, models.ErrInvalidStatusTransition),
+		errors.Is(err, models.ErrPaymentDiscrepancyStatusConflict):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
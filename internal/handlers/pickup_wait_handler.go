@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// PickupWaitHandler handles drivers waiting for their passengers at the pickup
+type PickupWaitHandler struct {
+	waitService service.PickupWaitServiceInterface
+}
+
+// NewPickupWaitHandler creates a new PickupWaitHandler instance
+func NewPickupWaitHandler(waitService service.PickupWaitServiceInterface) *PickupWaitHandler {
+	return &PickupWaitHandler{
+		waitService: waitService,
+	}
+}
+
+// ArriveAtPickup handles a trip's driver arriving at the pickup
+// @Summary Arrive at a ride's pickup
+// @Description Record the trip's driver arriving at the pickup and start waiting for the passenger. Waiting is free for a while, then charged per started minute with the fare; after the no-show window the driver may report a no-show.
+// @Tags rides
+// @Produce json
+// @Security bearer
+// @Param id path string true "Trip ID"
+// @Success 200 {object} models.PickupWait
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/arrived [post]
+func (h *PickupWaitHandler) ArriveAtPickup(c *gin.Context) {
+	h.driverAction(c, h.waitService.Arrive, "Failed to record arrival at pickup")
+}
+
+// PickUpPassenger handles a trip's driver picking up the passenger
+// @Summary Pick up a ride's passenger
+// @Description End the wait at the pickup with the passenger on board and start the trip. The fee for waiting past the free period is returned and added to the final fare.
+// @Tags rides
+// @Produce json
+// @Security bearer
+// @Param id path string true "Trip ID"
+// @Success 200 {object} models.PickupWait
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/pickup [post]
+func (h *PickupWaitHandler) PickUpPassenger(c *gin.Context) {
+	h.driverAction(c, h.waitService.PickUp, "Failed to pick up passenger")
+}
+
+// ReportNoShow handles a trip's driver reporting the passenger as a no-show
+// @Summary Report a ride's passenger as a no-show
+// @Description End the wait at the pickup without the passenger once the no-show window has passed. The trip is cancelled with the no-show fee as its fare and the driver goes back online.
+// @Tags rides
+// @Produce json
+// @Security bearer
+// @Param id path string true "Trip ID"
+// @Success 200 {object} models.PickupWait
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/no-show [post]
+func (h *PickupWaitHandler) ReportNoShow(c *gin.Context) {
+	h.driverAction(c, h.waitService.ReportNoShow, "Failed to report no-show")
+}
+
+// GetPickupWait handles retrieving the driver's wait at a ride's pickup
+// @Summary Get a ride's pickup wait
+// @Description Get when the trip's driver arrived at the pickup, when a no-show may be reported and how the wait ended
+// @Tags rides
+// @Produce json
+// @Param id path string true "Trip ID"
+// @Success 200 {object} models.PickupWait
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/pickup-wait [get]
+func (h *PickupWaitHandler) GetPickupWait(c *gin.Context) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	wait, err := h.waitService.GetWait(c.Request.Context(), tripID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to get pickup wait")
+		return
+	}
+
+	c.JSON(http.StatusOK, wait)
+}
+
+// GetPickupWaitZoneStats handles the wait-time metrics by pickup zone
+// @Summary Get pickup wait metrics by zone
+// @Description Get the arrivals, pickups, no-shows, wait times and fees of the drivers arriving at pickups in [start, end), by pickup geohash zone, busiest first. Average wait times are over the ended waits.
+// @Tags admin
+// @Produce json
+// @Param start query string false "Start time (RFC3339); defaults to 24 hours before end"
+// @Param end query string false "End time (RFC3339); defaults to now"
+// @Success 200 {array} models.PickupWaitZoneStats
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/pickup-waits/zones [get]
+func (h *PickupWaitHandler) GetPickupWaitZoneStats(c *gin.Context) {
+	end := time.Now()
+	if v := c.Query("end"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid end time",
+				Message: "End time must be in RFC3339 format",
+			})
+			return
+		}
+		end = t
+	}
+	start := end.Add(-24 * time.Hour)
+	if v := c.Query("start"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid start time",
+				Message: "Start time must be in RFC3339 format",
+			})
+			return
+		}
+		start = t
+	}
+
+	stats, err := h.waitService.GetZoneStats(c.Request.Context(), start, end)
+	if err != nil {
+		h.writeError(c, err, "Failed to get pickup wait metrics")
+		return
+	}
+	if stats == nil {
+		stats = []*models.PickupWaitZoneStats{}
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// driverAction runs an action of the trip's driver on the wait at the pickup
+func (h *PickupWaitHandler) driverAction(c *gin.Context, action func(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType) (*models.PickupWait, error), message string) {
+	session, ok := middleware.SessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "A session access token is required",
+		})
+		return
+	}
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	wait, err := action(c.Request.Context(), tripID.String(), session.UserID, session.UserType)
+	if err != nil {
+		h.writeError(c, err, message)
+		return
+	}
+
+	c.JSON(http.StatusOK, wait)
+}
+
+// writeError maps a pickup wait error to its HTTP response
+func (h *PickupWaitHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	case errors.Is(err, models.ErrUnauthorizedOperation):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Only the trip's driver can wait for its passenger",
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	case errors.Is(err, models.ErrTripNotAwaitingPickup),
+		errors.Is(err, models.ErrDriverNotArrived),
+		errors.Is(err, models.ErrNoShowTooEarly),
+		errors.Is(err, models.ErrTripStatusConflict):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
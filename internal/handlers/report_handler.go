@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler handles the reports aggregated by day
+type ReportHandler struct {
+	reportService *service.ReportService
+}
+
+// NewReportHandler creates a new ReportHandler instance
+func NewReportHandler(reportService *service.ReportService) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+	}
+}
+
+// GetDailySummary handles the daily trip summary
+// @Summary Get the daily trip summary
+// @Description Get the trips requested, completed and cancelled and the fares of the completed trips on each local date from from through to. Dates are calendar days in tz, so the days the clocks change are 23 or 25 hours long; each day has its start and end and its length in hours. Trips are counted on the day they were requested, completed or cancelled respectively.
+// @Tags admin
+// @Produce json
+// @Param from query string false "First date (YYYY-MM-DD); defaults to six days before to"
+// @Param to query string false "Last date (YYYY-MM-DD); defaults to today in tz"
+// @Param tz query string false "IANA timezone, e.g. America/New_York; defaults to REPORT_TIMEZONE"
+// @Success 200 {object} models.DailySummaryReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/reports/daily-summary [get]
+func (h *ReportHandler) GetDailySummary(c *gin.Context) {
+	report, err := h.reportService.DailySummary(c.Request.Context(), c.Query("from"), c.Query("to"), c.Query("tz"))
+	if err != nil {
+		var validation *models.ValidationError
+		if errors.As(err, &validation) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation error",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get the daily summary",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
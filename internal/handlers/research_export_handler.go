@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SuppressedTripsHeader is set on research exports to the number of trips left out for k-anonymity
+const SuppressedTripsHeader = "X-Suppressed-Trips"
+
+// ResearchExportHandler handles the anonymized trips dataset exported for research
+type ResearchExportHandler struct {
+	exportService *service.ResearchExportService
+}
+
+// NewResearchExportHandler creates a new ResearchExportHandler instance
+func NewResearchExportHandler(exportService *service.ResearchExportService) *ResearchExportHandler {
+	return &ResearchExportHandler{
+		exportService: exportService,
+	}
+}
+
+// ExportTrips handles exporting the anonymized trips dataset
+// @Summary Export anonymized trips for research
+// @Description Download the trips requested in [from, to) as an anonymized CSV dataset for research, anonymized as declared by the RESEARCH_EXPORT_* settings: the exported columns and their order, IDs replaced with keyed hashes, pickups and dropoffs generalized to geohash cells, timestamps shifted by a per-trip offset or truncated to the hour, and fares and distances kept or rounded. Trips whose pickup and dropoff cells are shared by fewer than the configured k trips are left out and counted in the X-Suppressed-Trips header. Rows are ordered by hashed trip ID. Empty cells are unknown values.
+// @Tags admin
+// @Produce text/csv
+// @Param from query string false "Start time (RFC3339); defaults to 30 days before to"
+// @Param to query string false "End time (RFC3339); defaults to now"
+// @Success 200 {string} string "CSV file"
+// @Header 200 {integer} X-Suppressed-Trips "Trips left out for k-anonymity"
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "The operator role is required"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/research/trips/export [get]
+func (h *ResearchExportHandler) ExportTrips(c *gin.Context) {
+	from, to, ok := parseExportPeriod(c)
+	if !ok {
+		return
+	}
+
+	export, err := h.exportService.ExportTrips(c.Request.Context(), from, to)
+	if err != nil {
+		var validation *models.ValidationError
+		if errors.As(err, &validation) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation error",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to export trips",
+		})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", `attachment; filename="research-trips.csv"`)
+	c.Header(SuppressedTripsHeader, strconv.Itoa(export.Suppressed))
+	c.Status(http.StatusOK)
+	if err := export.Write(c.Writer); err != nil {
+		// The status is already sent, so the truncated download is all the client sees
+		c.Error(err)
+	}
+}
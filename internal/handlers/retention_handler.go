@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"actor-model-observability/internal/retention"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RetentionHandler handles operators running the retention of the partitioned tables on demand
+type RetentionHandler struct {
+	partitions *retention.PartitionManager
+}
+
+// NewRetentionHandler creates a new RetentionHandler instance. A nil partition manager, when the
+// database is not PostgreSQL, reports retention as unavailable.
+func NewRetentionHandler(partitions *retention.PartitionManager) *RetentionHandler {
+	return &RetentionHandler{
+		partitions: partitions,
+	}
+}
+
+// RetentionRunResponse reports a retention run
+type RetentionRunResponse struct {
+	StartedAt  time.Time `json:"started_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// RunRetention handles an operator running the partition retention now
+// @Summary Run partition retention
+// @Description Create the upcoming partitions of the event, actor message, metric and driver location ping tables and drop the partitions past their retention now, instead of waiting for the next maintenance run
+// @Tags admin
+// @Produce json
+// @Success 200 {object} RetentionRunResponse
+// @Failure 403 {object} ErrorResponse "The operator role is required"
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/retention/run [post]
+func (h *RetentionHandler) RunRetention(c *gin.Context) {
+	if h.partitions == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Retention not available",
+			Message: "Partition retention needs the postgres database driver",
+		})
+		return
+	}
+
+	started := time.Now()
+	if err := h.partitions.RunMaintenance(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to run partition retention",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, RetentionRunResponse{
+		StartedAt:  started,
+		DurationMs: time.Since(started).Milliseconds(),
+	})
+}
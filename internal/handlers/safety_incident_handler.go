@@ -0,0 +1,322 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// SafetyIncidentHandler handles SOS reports from a trip's participants and their review by admins
+type SafetyIncidentHandler struct {
+	incidentService service.SafetyIncidentServiceInterface
+}
+
+// NewSafetyIncidentHandler creates a new SafetyIncidentHandler instance
+func NewSafetyIncidentHandler(incidentService service.SafetyIncidentServiceInterface) *SafetyIncidentHandler {
+	return &SafetyIncidentHandler{
+		incidentService: incidentService,
+	}
+}
+
+// ReportSOSRequest represents the request payload for raising an SOS. The location is the
+// reporter's device location; both coordinates or neither are sent.
+type ReportSOSRequest struct {
+	Latitude  *float64 `json:"latitude,omitempty"`
+	Longitude *float64 `json:"longitude,omitempty"`
+	Note      string   `json:"note" binding:"max=1000"`
+}
+
+// ReviewSafetyIncidentRequest represents the request payload for starting an incident review
+type ReviewSafetyIncidentRequest struct {
+	ReviewedBy string `json:"reviewed_by" binding:"required,max=255"`
+}
+
+// CloseSafetyIncidentRequest represents the request payload for closing an incident
+type CloseSafetyIncidentRequest struct {
+	ReviewedBy string `json:"reviewed_by" binding:"required,max=255"`
+	Note       string `json:"note" binding:"max=1000"`
+}
+
+// ReportSOS handles a trip's passenger or driver raising an SOS
+// @Summary Raise an SOS
+// @Description Record a safety incident on a trip with the reporter's location, or the driver's last known location when none is sent. A fatal security event is logged and safety_incident.reported webhook subscribers are alerted immediately. The trip cannot be deleted and its chat is not purged until admins close the incident.
+// @Tags safety
+// @Accept json
+// @Produce json
+// @Security bearer
+// @Param id path string true "Trip ID"
+// @Param request body ReportSOSRequest false "Location and note"
+// @Success 201 {object} models.SafetyIncident
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/sos [post]
+func (h *SafetyIncidentHandler) ReportSOS(c *gin.Context) {
+	session, ok := middleware.SessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "A session access token is required",
+		})
+		return
+	}
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	// An SOS may be raised without a body
+	var req ReportSOSRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request payload",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	if (req.Latitude == nil) != (req.Longitude == nil) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid location",
+			Message: "Latitude and longitude must be sent together",
+		})
+		return
+	}
+
+	var location *models.Location
+	if req.Latitude != nil {
+		location = &models.Location{Latitude: *req.Latitude, Longitude: *req.Longitude}
+	}
+
+	incident, err := h.incidentService.Report(c.Request.Context(), tripID.String(), session.UserID, session.UserType, location, req.Note)
+	if err != nil {
+		h.writeError(c, err, "Failed to report safety incident")
+		return
+	}
+
+	c.JSON(http.StatusCreated, incident)
+}
+
+// ListSafetyIncidents handles the admin review queue
+// @Summary List safety incidents
+// @Description Get safety incidents, oldest first, optionally filtered by status and trip
+// @Tags admin
+// @Produce json
+// @Param status query string false "open, under_review or closed"
+// @Param trip_id query string false "Filter by trip ID"
+// @Param limit query int false "Number of items per page" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} PaginatedResponse{data=[]models.SafetyIncident}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/safety-incidents [get]
+func (h *SafetyIncidentHandler) ListSafetyIncidents(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer between 1 and 100",
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid offset",
+			Message: "Offset must be a non-negative integer",
+		})
+		return
+	}
+
+	filter := models.SafetyIncidentFilter{
+		Status: models.SafetyIncidentStatus(c.Query("status")),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid status",
+			Message: "Status must be one of open, under_review or closed",
+		})
+		return
+	}
+	if tripID := c.Query("trip_id"); tripID != "" {
+		id, err := uuid.Parse(tripID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid trip ID",
+				Message: "trip_id must be a valid UUID",
+			})
+			return
+		}
+		filter.TripID = &id
+	}
+
+	incidents, total, err := h.incidentService.ListIncidents(c.Request.Context(), filter)
+	if err != nil {
+		h.writeError(c, err, "Failed to list safety incidents")
+		return
+	}
+	if incidents == nil {
+		incidents = []*models.SafetyIncident{}
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:    incidents,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: offset+len(incidents) < int(total),
+	})
+}
+
+// GetSafetyIncident handles retrieving an incident
+// @Summary Get a safety incident
+// @Description Get a safety incident by ID, including the snapshot of the trip and its timeline taken when the SOS was raised
+// @Tags admin
+// @Produce json
+// @Param id path string true "Incident ID"
+// @Success 200 {object} models.SafetyIncident
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/safety-incidents/{id} [get]
+func (h *SafetyIncidentHandler) GetSafetyIncident(c *gin.Context) {
+	incidentID, ok := parseUUIDParam(c, "id", "Invalid incident ID", "Incident ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	incident, err := h.incidentService.GetIncident(c.Request.Context(), incidentID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to get safety incident")
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// StartSafetyIncidentReview handles an admin taking an open incident under review
+// @Summary Review a safety incident
+// @Description Move an open safety incident under review
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Incident ID"
+// @Param request body ReviewSafetyIncidentRequest true "Reviewer"
+// @Success 200 {object} models.SafetyIncident
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/safety-incidents/{id}/review [post]
+func (h *SafetyIncidentHandler) StartSafetyIncidentReview(c *gin.Context) {
+	incidentID, ok := parseUUIDParam(c, "id", "Invalid incident ID", "Incident ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req ReviewSafetyIncidentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	incident, err := h.incidentService.StartReview(c.Request.Context(), incidentID.String(), req.ReviewedBy)
+	if err != nil {
+		h.writeError(c, err, "Failed to start safety incident review")
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// CloseSafetyIncident handles an admin closing an incident
+// @Summary Close a safety incident
+// @Description Close an open or under review safety incident. The trip's data is released for deletion and chat retention once none of its incidents awaits review.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Incident ID"
+// @Param request body CloseSafetyIncidentRequest true "Decision"
+// @Success 200 {object} models.SafetyIncident
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/safety-incidents/{id}/close [post]
+func (h *SafetyIncidentHandler) CloseSafetyIncident(c *gin.Context) {
+	incidentID, ok := parseUUIDParam(c, "id", "Invalid incident ID", "Incident ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req CloseSafetyIncidentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	incident, err := h.incidentService.Close(c.Request.Context(), incidentID.String(), req.ReviewedBy, req.Note)
+	if err != nil {
+		h.writeError(c, err, "Failed to close safety incident")
+		return
+	}
+
+	c.JSON(http.StatusOK, incident)
+}
+
+// writeError maps a safety incident error to its HTTP response
+func (h *SafetyIncidentHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	case errors.Is(err, models.ErrUnauthorizedOperation):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Only the trip's passenger and driver can raise an SOS on it",
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	case errors.Is(err, models.ErrInvalidStatusTransition),
+		errors.Is(err, models.ErrIncidentStatusConflict):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScalingSignalHandler handles the autoscaling signal
+type ScalingSignalHandler struct {
+	signalService *service.ScalingSignalService
+}
+
+// NewScalingSignalHandler creates a new ScalingSignalHandler instance
+func NewScalingSignalHandler(signalService *service.ScalingSignalService) *ScalingSignalHandler {
+	return &ScalingSignalHandler{
+		signalService: signalService,
+	}
+}
+
+// GetScalingSignal handles the autoscaling signal
+// @Summary Get the autoscaling signal
+// @Description Get the load autoscalers scale on, as a Kubernetes external metrics API ExternalMetricValueList: actor_mailbox_backlog and actor_mailbox_backlog_max, the messages waiting in every actor mailbox and the fullest one of the instance serving the request; pending_ride_requests, the trips waiting for a driver; latency_headroom, the smallest fraction of a latency error budget left to the endpoints with an SLO, and latency_burn_rate, the fastest burn of one. Values are Kubernetes quantities. With metric, only that metric is returned, as the first item, for KEDA's metrics-api scaler to read with valueLocation items.0.value.
+// @Tags observability
+// @Produce json
+// @Param metric query string false "Metric to return" Enums(actor_mailbox_backlog, actor_mailbox_backlog_max, pending_ride_requests, latency_headroom, latency_burn_rate)
+// @Success 200 {object} models.ExternalMetricValueList
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/observability/scaling-signal [get]
+func (h *ScalingSignalHandler) GetScalingSignal(c *gin.Context) {
+	signal, err := h.signalService.Signal(c.Request.Context(), c.Query("metric"))
+	if err != nil {
+		var validation *models.ValidationError
+		if errors.As(err, &validation) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation error",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get the scaling signal",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, signal)
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+
+	"actor-model-observability/internal/database"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SchemaHandler handles the admin view of schema drift
+type SchemaHandler struct {
+	checker *database.SchemaDriftChecker
+}
+
+// NewSchemaHandler creates a new SchemaHandler instance. A nil checker, when the database is not
+// PostgreSQL, reports drift detection as unavailable.
+func NewSchemaHandler(checker *database.SchemaDriftChecker) *SchemaHandler {
+	return &SchemaHandler{
+		checker: checker,
+	}
+}
+
+// GetSchemaDrift handles getting the last schema drift report
+// @Summary Get schema drift
+// @Description Get the last comparison of the live schema with the columns mapped by the models and the indexes created by the migrations, checking now if the schema was not checked yet
+// @Tags admin
+// @Produce json
+// @Success 200 {object} database.SchemaDriftReport
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/schema/drift [get]
+func (h *SchemaHandler) GetSchemaDrift(c *gin.Context) {
+	if h.checker == nil {
+		h.unavailable(c)
+		return
+	}
+
+	if report := h.checker.Last(); report != nil {
+		c.JSON(http.StatusOK, report)
+		return
+	}
+	h.check(c)
+}
+
+// CheckSchemaDrift handles checking the schema for drift now
+// @Summary Check schema drift
+// @Description Compare the live schema with the columns mapped by the models and the indexes created by the migrations, logging each mismatch as a warning
+// @Tags admin
+// @Produce json
+// @Success 200 {object} database.SchemaDriftReport
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/admin/schema/drift [post]
+func (h *SchemaHandler) CheckSchemaDrift(c *gin.Context) {
+	if h.checker == nil {
+		h.unavailable(c)
+		return
+	}
+	h.check(c)
+}
+
+func (h *SchemaHandler) check(c *gin.Context) {
+	report, err := h.checker.Check(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to check schema drift",
+		})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *SchemaHandler) unavailable(c *gin.Context) {
+	c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+		Error:   "Schema drift detection not available",
+		Message: "Schema drift detection requires PostgreSQL",
+	})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/timerange"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultStorageCostWindow is the period of the storage cost report given only one of its bounds
+const defaultStorageCostWindow = 24 * time.Hour
+
+// StorageCostHandler handles the storage cost comparison of the observability pipelines
+type StorageCostHandler struct {
+	storageCostService *service.StorageCostService
+	timeRanges         timerange.Parser
+}
+
+// NewStorageCostHandler creates a new StorageCostHandler instance
+func NewStorageCostHandler(storageCostService *service.StorageCostService) *StorageCostHandler {
+	return &StorageCostHandler{
+		storageCostService: storageCostService,
+	}
+}
+
+// SetMaxTimeRange sets the longest period storage is measured over. Without one, periods may be
+// of any length.
+func (h *StorageCostHandler) SetMaxTimeRange(max time.Duration) {
+	h.timeRanges.MaxWindow = max
+}
+
+// GetStorageCost handles the storage cost report
+// @Summary Get observability storage cost per ride request
+// @Description Measure the rows and bytes the actor pipeline (actor messages, events, traces) and the traditional pipeline (metrics, logs) stored in the period, in total, per table and per ride request, over time buckets and per processing mode (actor_model or traditional). Both pipelines record every ride, so each mode is charged both. Buckets with requests of more than one mode (mixed) or without requests are listed but left out of the modes. Bytes are the stored row sizes, without indexes.
+// @Tags observability
+// @Produce json
+// @Param start query string false "Start of the period: RFC3339, now, or an offset from now such as -7d; defaults to 24 hours before end"
+// @Param end query string false "End of the period: RFC3339, now, or an offset from now; defaults to now"
+// @Param bucket query string false "Time bucket length as a Go duration of at least 1m; the period may span at most 1000 buckets" default(1h)
+// @Success 200 {object} models.StorageCostReport
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/observability/storage-cost [get]
+func (h *StorageCostHandler) GetStorageCost(c *gin.Context) {
+	period, ok := parseTimeRange(c, h.timeRanges, "start", "end", defaultStorageCostWindow)
+	if !ok {
+		return
+	}
+
+	bucket := service.DefaultStorageCostBucket
+	if raw := c.Query("bucket"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid bucket",
+				Message: "Bucket must be a duration such as 15m or 1h",
+			})
+			return
+		}
+		bucket = parsed
+	}
+
+	report, err := h.storageCostService.Report(c.Request.Context(), period.Start, period.End, bucket)
+	if err != nil {
+		var validation *models.ValidationError
+		if errors.As(err, &validation) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation error",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get storage cost",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"actor-model-observability/internal/timerange"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultListTimeWindow is the range of the list endpoints given only one of its bounds
+const defaultListTimeWindow = time.Hour
+
+// parseTimeRange reads the range of the startParam and endParam query parameters, defaulting to
+// defaultWindow up to now. When the range is invalid it writes a 400 response and returns false.
+func parseTimeRange(c *gin.Context, parser timerange.Parser, startParam, endParam string, defaultWindow time.Duration) (timerange.Range, bool) {
+	r, err := parser.Parse(c.Query(startParam), c.Query(endParam), defaultWindow)
+	if err != nil {
+		writeTimeRangeError(c, err)
+		return timerange.Range{}, false
+	}
+	return r, true
+}
+
+// parseOptionalTimeRange reads the range of list endpoints, which only filter by time when a
+// bound is given. It returns nil without bounds.
+func parseOptionalTimeRange(c *gin.Context, parser timerange.Parser, startParam, endParam string) (*timerange.Range, bool) {
+	if c.Query(startParam) == "" && c.Query(endParam) == "" {
+		return nil, true
+	}
+	r, ok := parseTimeRange(c, parser, startParam, endParam, defaultListTimeWindow)
+	if !ok {
+		return nil, false
+	}
+	return &r, true
+}
+
+// parseTimeParam reads a single time bound, for endpoints whose services default and limit
+// their ranges. It returns the zero time when the parameter is not given.
+func parseTimeParam(c *gin.Context, parser timerange.Parser, param string) (time.Time, bool) {
+	value := c.Query(param)
+	if value == "" {
+		return time.Time{}, true
+	}
+	t, err := parser.ParseTime(param, value)
+	if err != nil {
+		writeTimeRangeError(c, err)
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// formatRangeTime formats a bound of a range for the repositories, which take RFC3339 times
+func formatRangeTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+func writeTimeRangeError(c *gin.Context, err error) {
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:   "Invalid time range",
+		Message: err.Error(),
+	})
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TimelineHandler handles trip timelines
+type TimelineHandler struct {
+	timelineService *service.TimelineService
+}
+
+// NewTimelineHandler creates a new TimelineHandler instance
+func NewTimelineHandler(timelineService *service.TimelineService) *TimelineHandler {
+	return &TimelineHandler{
+		timelineService: timelineService,
+	}
+}
+
+// GetTripTimeline handles the timeline of a ride
+// @Summary Get ride timeline
+// @Description Get everything recorded about a ride in chronological order: its lifecycle, the event logs of the trip, actor messages whose payload references it, the spans of their traces and traditional logs whose fields reference it. Each entry is labelled with its source; sources listed under truncated had more records in the ride's time window than were scanned.
+// @Tags rides
+// @Produce json
+// @Param id path string true "Trip ID"
+// @Success 200 {object} models.TripTimeline
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/timeline [get]
+func (h *TimelineHandler) GetTripTimeline(c *gin.Context) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	timeline, err := h.timelineService.GetTripTimeline(c.Request.Context(), tripID.String())
+	if err != nil {
+		var notFound *models.NotFoundError
+		if errors.As(err, &notFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Trip not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get trip timeline",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, timeline)
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultStuckTripAge is how long a trip must have gone without an update to be listed as stuck
+	defaultStuckTripAge = 15 * time.Minute
+	// defaultStuckTripLimit is how many stuck trips are listed by default
+	defaultStuckTripLimit = 50
+	// maxStuckTripLimit bounds how many stuck trips are listed at once
+	maxStuckTripLimit = 500
+)
+
+// TripAdminHandler handles operators finding and clearing trips that stopped progressing
+type TripAdminHandler struct {
+	tripRepo    repository.TripRepository
+	rideService service.RideServiceInterface
+}
+
+// NewTripAdminHandler creates a new TripAdminHandler instance
+func NewTripAdminHandler(tripRepo repository.TripRepository, rideService service.RideServiceInterface) *TripAdminHandler {
+	return &TripAdminHandler{
+		tripRepo:    tripRepo,
+		rideService: rideService,
+	}
+}
+
+// StuckTripsResponse lists the trips that stopped progressing
+type StuckTripsResponse struct {
+	UpdatedBefore time.Time      `json:"updated_before"`
+	Trips         []*models.Trip `json:"trips"`
+}
+
+// ForceCancelTripRequest is why an operator cancels a trip
+type ForceCancelTripRequest struct {
+	Reason models.CancellationReason `json:"reason,omitempty"` // one of models.CancellationReasons, other by default
+	Note   string                    `json:"note,omitempty"`   // optional details, at most 500 characters
+}
+
+// ListStuckTrips handles listing the trips that stopped progressing
+// @Summary List stuck trips
+// @Description List the trips neither completed nor cancelled that were not updated for longer than older_than, least recently updated first, e.g. requests never matched or trips whose driver stopped reporting
+// @Tags admin
+// @Produce json
+// @Param older_than query string false "How long a trip must have gone without an update, e.g. 30m" default(15m)
+// @Param limit query int false "Maximum number of trips, at most 500" default(50)
+// @Success 200 {object} StuckTripsResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/trips/stuck [get]
+func (h *TripAdminHandler) ListStuckTrips(c *gin.Context) {
+	age := defaultStuckTripAge
+	if value := c.Query("older_than"); value != "" {
+		var err error
+		age, err = time.ParseDuration(value)
+		if err != nil || age <= 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid older_than",
+				Message: "older_than must be a positive duration, e.g. 30m",
+			})
+			return
+		}
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultStuckTripLimit)))
+	if err != nil || limit <= 0 || limit > maxStuckTripLimit {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer between 1 and 500",
+		})
+		return
+	}
+
+	updatedBefore := time.Now().Add(-age)
+	trips, err := h.tripRepo.ListStuck(c.Request.Context(), updatedBefore, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to list stuck trips",
+		})
+		return
+	}
+	if trips == nil {
+		trips = []*models.Trip{}
+	}
+
+	c.JSON(http.StatusOK, StuckTripsResponse{
+		UpdatedBefore: updatedBefore,
+		Trips:         trips,
+	})
+}
+
+// ForceCancelTrip handles an operator cancelling a trip on behalf of its passenger and driver
+// @Summary Force-cancel a trip
+// @Description Cancel a trip that is neither completed nor cancelled, releasing its driver as a passenger cancellation would, e.g. to clear a stuck trip. The reason defaults to other.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param id path string true "Trip ID"
+// @Param request body ForceCancelTripRequest false "Why the trip is cancelled"
+// @Success 200 {object} models.Trip
+// @Failure 400 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "The operator role is required"
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "The trip already ended; code is TRIP_NOT_CANCELLABLE"
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/trips/{id}/cancel [post]
+func (h *TripAdminHandler) ForceCancelTrip(c *gin.Context) {
+	tripID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid trip ID",
+			Message: "Trip ID must be a valid UUID",
+		})
+		return
+	}
+
+	var req ForceCancelTripRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request payload",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+	cancellation := models.TripCancellation{Reason: req.Reason, Note: req.Note}
+	if cancellation.Reason == "" {
+		cancellation.Reason = models.CancellationReasonOther
+	}
+
+	ctx := c.Request.Context()
+	trip, err := h.tripRepo.GetByID(ctx, tripID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to get trip")
+		return
+	}
+	if !trip.IsActive() {
+		h.writeError(c, models.ErrTripNotCancellable, "")
+		return
+	}
+
+	if err := h.rideService.CancelRide(ctx, tripID.String(), cancellation); err != nil {
+		h.writeError(c, err, "Failed to cancel trip")
+		return
+	}
+
+	trip, err = h.tripRepo.GetByID(ctx, tripID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to get trip")
+		return
+	}
+
+	c.JSON(http.StatusOK, trip)
+}
+
+// writeError maps a trip admin error to its HTTP response
+func (h *TripAdminHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	case errors.Is(err, models.ErrTripNotCancellable),
+		errors.Is(err, models.ErrTripStatusConflict):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
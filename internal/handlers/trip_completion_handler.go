@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TripCompletionHandler handles drivers completing their trips and the review of flagged completions
+type TripCompletionHandler struct {
+	completionService service.TripCompletionServiceInterface
+}
+
+// NewTripCompletionHandler creates a new TripCompletionHandler instance
+func NewTripCompletionHandler(completionService service.TripCompletionServiceInterface) *TripCompletionHandler {
+	return &TripCompletionHandler{
+		completionService: completionService,
+	}
+}
+
+// CompleteRideRequest represents the request payload for a driver completing a trip
+type CompleteRideRequest struct {
+	DropoffLat         *float64 `json:"dropoff_lat" binding:"required,min=-90,max=90"`
+	DropoffLng         *float64 `json:"dropoff_lng" binding:"required,min=-180,max=180"`
+	OdometerDistanceKm *float64 `json:"odometer_distance_km" binding:"required,min=0"`
+	TollsAmount        float64  `json:"tolls_amount" binding:"min=0"`
+	ExtrasAmount       float64  `json:"extras_amount" binding:"min=0"`
+}
+
+// CompleteRide handles a trip's driver completing it
+// @Summary Complete a ride
+// @Description Complete an active trip with the actual dropoff, odometer distance, tolls and extras entered by its driver. The odometer distance is billed, but never less than the straight line from pickup to dropoff, and the fee for waiting at the pickup past the free period is added to the fare. Completions whose distance, dropoff or fare deviate too much from the requested route are flagged for review.
+// @Tags rides
+// @Accept json
+// @Produce json
+// @Security bearer
+// @Param id path string true "Trip ID"
+// @Param request body CompleteRideRequest true "Completion details"
+// @Success 200 {object} models.TripCompletion
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/complete [post]
+func (h *TripCompletionHandler) CompleteRide(c *gin.Context) {
+	session, ok := middleware.SessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "A session access token is required",
+		})
+		return
+	}
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req CompleteRideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	completion, err := h.completionService.Complete(c.Request.Context(), tripID.String(), session.UserID, session.UserType, models.TripCompletionDetails{
+		Dropoff:            models.Location{Latitude: *req.DropoffLat, Longitude: *req.DropoffLng},
+		OdometerDistanceKm: *req.OdometerDistanceKm,
+		TollsAmount:        req.TollsAmount,
+		ExtrasAmount:       req.ExtrasAmount,
+	})
+	if err != nil {
+		h.writeError(c, err, "Failed to complete ride")
+		return
+	}
+
+	c.JSON(http.StatusOK, completion)
+}
+
+// GetTripCompletion handles retrieving the completion details of a trip
+// @Summary Get a trip completion
+// @Description Get the details entered by the driver when completing a trip and how they reconciled against the requested route
+// @Tags admin
+// @Produce json
+// @Param id path string true "Trip ID"
+// @Success 200 {object} models.TripCompletion
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/trip-completions/{id} [get]
+func (h *TripCompletionHandler) GetTripCompletion(c *gin.Context) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	completion, err := h.completionService.GetCompletion(c.Request.Context(), tripID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to get trip completion")
+		return
+	}
+
+	c.JSON(http.StatusOK, completion)
+}
+
+// ListTripCompletions handles the admin review queue of trip completions
+// @Summary List trip completions
+// @Description Get trip completions, oldest first, optionally only those flagged for review or not
+// @Tags admin
+// @Produce json
+// @Param flagged query bool false "Filter by whether the completion was flagged for review"
+// @Param limit query int false "Number of items per page" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} PaginatedResponse{data=[]models.TripCompletion}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/trip-completions [get]
+func (h *TripCompletionHandler) ListTripCompletions(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer between 1 and 100",
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid offset",
+			Message: "Offset must be a non-negative integer",
+		})
+		return
+	}
+
+	filter := models.TripCompletionFilter{
+		Limit:  limit,
+		Offset: offset,
+	}
+	if flagged := c.Query("flagged"); flagged != "" {
+		value, err := strconv.ParseBool(flagged)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid flagged",
+				Message: "flagged must be true or false",
+			})
+			return
+		}
+		filter.Flagged = &value
+	}
+
+	completions, total, err := h.completionService.ListCompletions(c.Request.Context(), filter)
+	if err != nil {
+		h.writeError(c, err, "Failed to list trip completions")
+		return
+	}
+	if completions == nil {
+		completions = []*models.TripCompletion{}
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:    completions,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: offset+len(completions) < int(total),
+	})
+}
+
+// writeError maps a trip completion error to its HTTP response
+func (h *TripCompletionHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	case errors.Is(err, models.ErrUnauthorizedOperation):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Only the trip's driver can complete it",
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	case errors.Is(err, models.ErrTripNotCompletable),
+		errors.Is(err, models.ErrTripStatusConflict):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
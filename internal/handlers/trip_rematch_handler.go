@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TripRematchHandler handles drivers cancelling trips before pickup and the trips being matched
+// again
+type TripRematchHandler struct {
+	rematchService service.TripRematchServiceInterface
+}
+
+// NewTripRematchHandler creates a new TripRematchHandler instance
+func NewTripRematchHandler(rematchService service.TripRematchServiceInterface) *TripRematchHandler {
+	return &TripRematchHandler{
+		rematchService: rematchService,
+	}
+}
+
+// DriverCancelRequest represents the request payload for a driver cancelling a trip
+type DriverCancelRequest struct {
+	Note string `json:"note" example:"Flat tyre"` // optional, at most 500 characters
+}
+
+// DriverCancelRide handles a trip's driver cancelling it before pickup
+// @Summary Cancel a ride as its driver
+// @Description Cancel a matched or accepted trip as its driver before pickup. The driver goes back online and the trip is matched again with another driver, never one who cancelled it; it is cancelled instead when no other driver is available or it was already matched again as often as configured. The passenger follows the trip through its status events. In actor mode matching runs in the background and the returned re-match is still matching.
+// @Tags rides
+// @Accept json
+// @Produce json
+// @Security bearer
+// @Param id path string true "Trip ID"
+// @Param request body DriverCancelRequest false "Cancellation note"
+// @Success 200 {object} models.TripRematch
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/driver-cancel [post]
+func (h *TripRematchHandler) DriverCancelRide(c *gin.Context) {
+	session, ok := middleware.SessionFromContext(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Unauthorized",
+			Message: "A session access token is required",
+		})
+		return
+	}
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	var req DriverCancelRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid request payload",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+
+	rematch, err := h.rematchService.CancelByDriver(c.Request.Context(), tripID.String(), session.UserID, session.UserType, req.Note)
+	if err != nil {
+		h.writeError(c, err, "Failed to cancel ride")
+		return
+	}
+
+	c.JSON(http.StatusOK, rematch)
+}
+
+// ListTripRematches handles retrieving the times a ride's driver cancelled it
+// @Summary List a ride's re-matches
+// @Description List the times the trip's driver cancelled before pickup, first first, with how matching the trip again ended and the wait it added
+// @Tags rides
+// @Produce json
+// @Param id path string true "Trip ID"
+// @Success 200 {array} models.TripRematch
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/rides/{id}/rematches [get]
+func (h *TripRematchHandler) ListTripRematches(c *gin.Context) {
+	tripID, ok := parseUUIDParam(c, "id", "Invalid trip ID", "Trip ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	rematches, err := h.rematchService.ListRematches(c.Request.Context(), tripID.String())
+	if err != nil {
+		h.writeError(c, err, "Failed to list ride re-matches")
+		return
+	}
+	if rematches == nil {
+		rematches = []*models.TripRematch{}
+	}
+
+	c.JSON(http.StatusOK, rematches)
+}
+
+// writeError maps a re-match error to its HTTP response
+func (h *TripRematchHandler) writeError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Resource not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	case errors.Is(err, models.ErrUnauthorizedOperation):
+		c.JSON(http.StatusForbidden, ErrorResponse{
+			Error:   "Forbidden",
+			Message: "Only the trip's driver can cancel it",
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	case errors.Is(err, models.ErrTripNotDriverCancellable),
+		errors.Is(err, models.ErrTripStatusConflict):
+		c.JSON(http.StatusConflict, ErrorResponse{
+			Error:   "Conflict",
+			Message: err.Error(),
+			Code:    string(models.ErrorCodeOf(err)),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
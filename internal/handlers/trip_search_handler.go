@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// defaultTripSearchWindow is how far either side of the at time trips are matched by default
+	defaultTripSearchWindow = 30 * time.Minute
+	// maxTripSearchWindow bounds the window so an approximate time stays a narrow lookup
+	maxTripSearchWindow = 24 * time.Hour
+	// defaultTripSearchRadiusKm is how far from lat/lng pickups are matched by default
+	defaultTripSearchRadiusKm = 1.0
+	// maxTripSearchRadiusKm bounds the radius so an approximate location stays a narrow lookup
+	maxTripSearchRadiusKm = 50.0
+)
+
+// tripIDPrefixPattern matches the leading characters of a trip ID, long enough to be selective
+var tripIDPrefixPattern = regexp.MustCompile(`^[0-9a-fA-F-]{4,36}$`)
+
+// TripSearchHandler handles support agents looking up customers' trips
+type TripSearchHandler struct {
+	tripRepo repository.TripRepository
+}
+
+// NewTripSearchHandler creates a new TripSearchHandler instance
+func NewTripSearchHandler(tripRepo repository.TripRepository) *TripSearchHandler {
+	return &TripSearchHandler{
+		tripRepo: tripRepo,
+	}
+}
+
+// SearchTrips handles the support trip search
+// @Summary Search trips
+// @Description Look up trips for customer support by passenger phone or email, driver plate, approximate request time, approximate pickup location and the leading characters of the trip ID. Every criterion given must match and at least one is required. Results carry the passenger's and driver's contact details, most recently requested first.
+// @Tags admin
+// @Produce json
+// @Param phone query string false "Passenger phone number"
+// @Param email query string false "Passenger email, case-insensitive"
+// @Param plate query string false "Driver vehicle plate, ignoring case and spaces"
+// @Param trip_id query string false "Leading characters of the trip ID, at least 4"
+// @Param at query string false "Approximate request time (RFC3339 format)"
+// @Param window query string false "How far either side of at to match, e.g. 15m, at most 24h" default(30m)
+// @Param lat query number false "Approximate pickup latitude, requires lng"
+// @Param lng query number false "Approximate pickup longitude, requires lat"
+// @Param radius_km query number false "How far from lat/lng to match pickups, at most 50" default(1)
+// @Param limit query int false "Number of items per page" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} PaginatedResponse{data=[]models.TripSearchResult}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/trips/search [get]
+func (h *TripSearchHandler) SearchTrips(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer between 1 and 100",
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid offset",
+			Message: "Offset must be a non-negative integer",
+		})
+		return
+	}
+
+	filter := models.TripSearchFilter{
+		PassengerPhone: strings.NewReplacer(" ", "", "-", "").Replace(c.Query("phone")),
+		PassengerEmail: strings.TrimSpace(c.Query("email")),
+		DriverPlate:    strings.TrimSpace(c.Query("plate")),
+		Limit:          limit,
+		Offset:         offset,
+	}
+
+	if tripID := c.Query("trip_id"); tripID != "" {
+		if !tripIDPrefixPattern.MatchString(tripID) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid trip ID",
+				Message: "trip_id must be at least 4 leading characters of a trip ID",
+			})
+			return
+		}
+		filter.TripIDPrefix = tripID
+	}
+
+	if !h.parseTimeWindow(c, &filter) || !h.parsePickupArea(c, &filter) {
+		return
+	}
+
+	if filter.IsEmpty() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Missing search criteria",
+			Message: "At least one of phone, email, plate, trip_id, at or lat/lng is required",
+		})
+		return
+	}
+
+	results, total, err := h.tripRepo.Search(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to search trips",
+		})
+		return
+	}
+	if results == nil {
+		results = []*models.TripSearchResult{}
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:    results,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: offset+len(results) < int(total),
+	})
+}
+
+// parseTimeWindow sets the request time range from the at and window query parameters, writing
+// the error response when they are invalid
+func (h *TripSearchHandler) parseTimeWindow(c *gin.Context, filter *models.TripSearchFilter) bool {
+	at := c.Query("at")
+	if at == "" {
+		return true
+	}
+
+	center, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid time",
+			Message: "at must be in RFC3339 format",
+		})
+		return false
+	}
+
+	window := defaultTripSearchWindow
+	if value := c.Query("window"); value != "" {
+		window, err = time.ParseDuration(value)
+		if err != nil || window <= 0 || window > maxTripSearchWindow {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid window",
+				Message: "window must be a positive duration of at most 24h, e.g. 15m",
+			})
+			return false
+		}
+	}
+
+	from, to := center.Add(-window), center.Add(window)
+	filter.RequestedFrom, filter.RequestedTo = &from, &to
+	return true
+}
+
+// parsePickupArea sets the pickup bounds from the lat, lng and radius_km query parameters,
+// writing the error response when they are invalid
+func (h *TripSearchHandler) parsePickupArea(c *gin.Context, filter *models.TripSearchFilter) bool {
+	lat, lng := c.Query("lat"), c.Query("lng")
+	if lat == "" && lng == "" {
+		return true
+	}
+
+	var center models.Location
+	var latErr, lngErr error
+	center.Latitude, latErr = strconv.ParseFloat(lat, 64)
+	center.Longitude, lngErr = strconv.ParseFloat(lng, 64)
+	if latErr != nil || lngErr != nil || !center.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid location",
+			Message: "lat and lng must both be given as valid coordinates",
+		})
+		return false
+	}
+
+	radius := defaultTripSearchRadiusKm
+	if value := c.Query("radius_km"); value != "" {
+		var err error
+		radius, err = strconv.ParseFloat(value, 64)
+		if err != nil || radius <= 0 || radius > maxTripSearchRadiusKm {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid radius",
+				Message: "radius_km must be a positive number of at most 50",
+			})
+			return false
+		}
+	}
+
+	bounds := models.GeoBoundsAround(center, radius)
+	filter.PickupBounds = &bounds
+	return true
+}
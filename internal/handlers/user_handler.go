@@ -1,11 +1,18 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
+	"actor-model-observability/internal/bus"
 	"actor-model-observability/internal/models"
 	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,6 +23,11 @@ type UserHandler struct {
 	userRepo      repository.UserRepository
 	driverRepo    repository.DriverRepository
 	passengerRepo repository.PassengerRepository
+	events        bus.Publisher
+	fatigue       *service.DriverFatigueService
+	avatars       *service.AvatarService
+	onboarding    *service.DriverOnboardingService
+	locations     *service.LocationIngester
 }
 
 // NewUserHandler creates a new UserHandler instance
@@ -31,6 +43,37 @@ func NewUserHandler(
 	}
 }
 
+// SetEventPublisher sets the event bus driver locations are published on, for the ETAs of the
+// live trip status streams
+func (h *UserHandler) SetEventPublisher(events bus.Publisher) {
+	h.events = events
+}
+
+// SetFatigueService sets the service whose hours are added to driver profiles and which keeps
+// resting drivers from going back online. Without one, fatigue limits are not checked.
+func (h *UserHandler) SetFatigueService(fatigue *service.DriverFatigueService) {
+	h.fatigue = fatigue
+}
+
+// SetAvatarService sets the service storing profile pictures, whose signed URLs are added to user
+// payloads. Without one, profile pictures are not available.
+func (h *UserHandler) SetAvatarService(avatars *service.AvatarService) {
+	h.avatars = avatars
+}
+
+// SetOnboardingService sets the service new drivers start onboarding with and which, when
+// activation is required, keeps drivers who are not activated from going online. Without one,
+// drivers are not onboarded.
+func (h *UserHandler) SetOnboardingService(onboarding *service.DriverOnboardingService) {
+	h.onboarding = onboarding
+}
+
+// SetLocationIngester sets the buffer driver locations are reported to instead of being written to
+// the database one by one
+func (h *UserHandler) SetLocationIngester(locations *service.LocationIngester) {
+	h.locations = locations
+}
+
 // CreateUserRequest represents the request payload for user creation
 type CreateUserRequest struct {
 	Email    string `json:"email" binding:"required,email"`
@@ -42,9 +85,16 @@ type CreateUserRequest struct {
 // CreateDriverRequest represents additional driver-specific fields
 type CreateDriverRequest struct {
 	CreateUserRequest
-	LicenseNumber string `json:"license_number" binding:"required"`
-	VehicleType   string `json:"vehicle_type" binding:"required"`
-	VehiclePlate  string `json:"vehicle_plate" binding:"required"`
+	LicenseNumber string     `json:"license_number" binding:"required"`
+	VehicleType   string     `json:"vehicle_type" binding:"required"`
+	VehiclePlate  string     `json:"vehicle_plate" binding:"required"`
+	FleetID       *uuid.UUID `json:"fleet_id,omitempty"` // fleet partner the driver drives for
+
+	// Ride preferences the driver can meet and the vehicle's special-assistance capabilities
+	PetFriendly          bool `json:"pet_friendly"`
+	WheelchairAccessible bool `json:"wheelchair_accessible"`
+	QuietRides           bool `json:"quiet_rides"`
+	ChildSeat            bool `json:"child_seat"`
 }
 
 // UpdateUserRequest represents the request payload for user updates
@@ -238,6 +288,12 @@ func (h *UserHandler) CreateDriver(c *gin.Context) {
 		VehiclePlate:  req.VehiclePlate,
 		Status:        models.DriverStatusOffline,
 		Rating:        5.0, // Default rating
+		FleetID:       req.FleetID,
+
+		PetFriendly:          req.PetFriendly,
+		WheelchairAccessible: req.WheelchairAccessible,
+		QuietRides:           req.QuietRides,
+		ChildSeat:            req.ChildSeat,
 	}
 
 	if err := driver.Validate(); err != nil {
@@ -248,7 +304,21 @@ func (h *UserHandler) CreateDriver(c *gin.Context) {
 		return
 	}
 
-	if err := h.driverRepo.Create(c.Request.Context(), driver); err != nil {
+	// With onboarding, the driver and its onboarding are created together
+	if h.onboarding != nil {
+		_, err = h.onboarding.CreateDriver(c.Request.Context(), driver)
+	} else {
+		err = h.driverRepo.Create(c.Request.Context(), driver)
+	}
+	if err != nil {
+		var validationErr *models.ValidationError
+		if errors.As(err, &validationErr) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation error",
+				Message: validationErr.Error(),
+			})
+			return
+		}
 		// If driver creation fails, we should ideally rollback the user creation
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Internal server error",
@@ -298,6 +368,9 @@ func (h *UserHandler) GetUser(c *gin.Context) {
 		}
 		return
 	}
+	if !h.attachAvatarURLs(c, user) {
+		return
+	}
 
 	c.JSON(http.StatusOK, user)
 }
@@ -403,17 +476,29 @@ func (h *UserHandler) UpdateUser(c *gin.Context) {
 		}
 		return
 	}
+	if !h.attachAvatarURLs(c, user) {
+		return
+	}
 
 	c.JSON(http.StatusOK, user)
 }
 
+// DriverProfileResponse represents a driver with the rollup of their completed trips and their
+// time online and driving against the fatigue limits
+type DriverProfileResponse struct {
+	*models.Driver
+	AvatarURL string                  `json:"avatar_url,omitempty"` // signed URL of the driver's profile picture
+	TripStats *models.DriverTripStats `json:"trip_stats"`
+	Fatigue   *models.DriverFatigue   `json:"fatigue,omitempty"`
+}
+
 // GetDriver handles driver retrieval by user ID
 // @Summary Get driver by user ID
-// @Description Retrieve driver information by user ID
+// @Description Retrieve driver information by user ID, with the driver's trip totals, rating and earnings over the last 30 days, and the driver's time online and driving over the rolling fatigue window
 // @Tags users
 // @Produce json
 // @Param user_id path string true "User ID"
-// @Success 200 {object} models.Driver
+// @Success 200 {object} DriverProfileResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -446,12 +531,87 @@ func (h *UserHandler) GetDriver(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, driver)
+	response := DriverProfileResponse{Driver: driver}
+	response.TripStats, err = h.driverRepo.GetTripStats(c.Request.Context(), driver.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get driver trip stats",
+		})
+		return
+	}
+	if h.avatars != nil {
+		user, err := h.userRepo.GetByID(c.Request.Context(), userID.String())
+		if err == nil {
+			response.AvatarURL, err = h.avatars.URL(c.Request.Context(), user)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Internal server error",
+				Message: "Failed to get driver profile picture",
+			})
+			return
+		}
+	}
+	if h.fatigue != nil {
+		response.Fatigue, err = h.fatigue.GetFatigue(c.Request.Context(), driver.ID.String())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Internal server error",
+				Message: "Failed to get driver hours",
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetPassenger handles passenger retrieval by user ID
+// @Summary Get passenger by user ID
+// @Description Retrieve passenger information by user ID
+// @Tags users
+// @Produce json
+// @Param user_id path string true "User ID"
+// @Success 200 {object} models.Passenger
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/users/{id}/passenger [get]
+func (h *UserHandler) GetPassenger(c *gin.Context) {
+	userIDStr := c.Param("id")
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid user ID",
+			Message: "User ID must be a valid UUID",
+		})
+		return
+	}
+
+	passenger, err := h.passengerRepo.GetByUserID(c.Request.Context(), userID.String())
+	if err != nil {
+		switch err.(type) {
+		case *models.NotFoundError:
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Passenger not found",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Internal server error",
+				Message: "Failed to get passenger",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, passenger)
 }
 
 // UpdateDriverLocation handles driver location updates
 // @Summary Update driver location
-// @Description Update the current location of a driver
+// @Description Update the current location of a driver. Locations are buffered and written to the database every few seconds, keeping only each driver's latest; matching uses them at once.
 // @Tags users
 // @Accept json
 // @Produce json
@@ -482,9 +642,26 @@ func (h *UserHandler) UpdateDriverLocation(c *gin.Context) {
 		return
 	}
 
-	// Update location directly using driver ID
-	err = h.driverRepo.UpdateLocation(c.Request.Context(), driverID.String(), req.Latitude, req.Longitude)
+	update := &models.DriverLocationUpdate{
+		DriverID:  driverID,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		At:        time.Now(),
+	}
+	if h.locations != nil {
+		err = h.locations.Ingest(c.Request.Context(), update)
+	} else {
+		err = h.driverRepo.UpdateLocation(c.Request.Context(), driverID.String(), req.Latitude, req.Longitude)
+	}
 	if err != nil {
+		var notFound *models.NotFoundError
+		if errors.As(err, &notFound) {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Driver not found",
+				Message: err.Error(),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Internal server error",
 			Message: "Failed to update driver location",
@@ -492,6 +669,10 @@ func (h *UserHandler) UpdateDriverLocation(c *gin.Context) {
 		return
 	}
 
+	if h.events != nil {
+		h.events.Publish(bus.TopicDriverLocation, update)
+	}
+
 	c.JSON(http.StatusOK, gin.H{"message": "Driver location updated successfully"})
 }
 
@@ -506,6 +687,7 @@ func (h *UserHandler) UpdateDriverLocation(c *gin.Context) {
 // @Success 200 {object} models.Driver
 // @Failure 400 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse "Driver is not activated, or is resting after reaching a fatigue limit"
 // @Failure 500 {object} ErrorResponse
 // @Router /api/v1/users/{user_id}/driver/status [put]
 func (h *UserHandler) UpdateDriverStatus(c *gin.Context) {
@@ -530,6 +712,41 @@ func (h *UserHandler) UpdateDriverStatus(c *gin.Context) {
 
 	// Update status directly using driver ID
 	newStatus := models.DriverStatus(req.Status)
+	if newStatus == models.DriverStatusOnline && h.onboarding != nil {
+		if err := h.onboarding.CheckActivated(c.Request.Context(), driverID.String()); err != nil {
+			if errors.Is(err, models.ErrDriverNotActivated) {
+				c.JSON(http.StatusConflict, ErrorResponse{
+					Error:   "Driver is not activated",
+					Message: err.Error(),
+					Code:    string(models.ErrorCodeOf(err)),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Internal server error",
+				Message: "Failed to check driver onboarding",
+			})
+			return
+		}
+	}
+	if newStatus == models.DriverStatusOnline && h.fatigue != nil {
+		if err := h.fatigue.CheckOnline(c.Request.Context(), driverID.String()); err != nil {
+			if errors.Is(err, models.ErrDriverResting) {
+				c.JSON(http.StatusConflict, ErrorResponse{
+					Error:   "Driver is resting",
+					Message: err.Error(),
+					Code:    string(models.ErrorCodeOf(err)),
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Internal server error",
+				Message: "Failed to check driver rest",
+			})
+			return
+		}
+	}
+
 	err = h.driverRepo.UpdateStatus(c.Request.Context(), driverID.String(), newStatus)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
@@ -542,6 +759,208 @@ func (h *UserHandler) UpdateDriverStatus(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Driver status updated successfully"})
 }
 
+// SetDriverDestinationRequest represents the request for turning on destination mode
+type SetDriverDestinationRequest struct {
+	Latitude        float64 `json:"latitude" binding:"required,min=-90,max=90"`
+	Longitude       float64 `json:"longitude" binding:"required,min=-180,max=180"`
+	Address         *string `json:"address,omitempty"`
+	DurationMinutes int     `json:"duration_minutes,omitempty" binding:"min=0"`
+}
+
+// DriverDestinationResponse represents an active destination and the activations left today
+type DriverDestinationResponse struct {
+	*models.DriverDestination
+	RemainingToday int `json:"remaining_today"`
+}
+
+// SetDriverDestination handles turning on destination mode
+// @Summary Set driver destination
+// @Description Turn on destination mode so the driver is only matched with trips heading toward the destination. Replaces an active destination and counts toward the daily limit.
+// @Tags drivers
+// @Accept json
+// @Produce json
+// @Param id path string true "Driver ID"
+// @Param request body SetDriverDestinationRequest true "Destination details"
+// @Success 200 {object} DriverDestinationResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/destination [put]
+func (h *UserHandler) SetDriverDestination(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid driver ID",
+			Message: "Driver ID must be a valid UUID",
+		})
+		return
+	}
+
+	var req SetDriverDestinationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	duration := models.DriverDestinationDefaultDuration
+	if req.DurationMinutes > 0 {
+		duration = time.Duration(req.DurationMinutes) * time.Minute
+	}
+	if duration > models.DriverDestinationMaxDuration {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid duration",
+			Message: fmt.Sprintf("Destination mode can stay on for at most %d minutes", int(models.DriverDestinationMaxDuration.Minutes())),
+		})
+		return
+	}
+
+	ctx := c.Request.Context()
+	if _, err := h.driverRepo.GetByID(ctx, driverID.String()); err != nil {
+		if _, ok := err.(*models.NotFoundError); ok {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Driver not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get driver",
+		})
+		return
+	}
+
+	now := time.Now()
+	startOfDay := now.UTC().Truncate(24 * time.Hour)
+	used, err := h.driverRepo.CountDestinationsSince(ctx, driverID.String(), startOfDay)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to check destination usage",
+		})
+		return
+	}
+	if used >= models.DriverDestinationDailyLimit {
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error:   "Daily limit reached",
+			Message: fmt.Sprintf("Destination mode can be turned on %d times per day", models.DriverDestinationDailyLimit),
+		})
+		return
+	}
+
+	destination := &models.DriverDestination{
+		ID:        uuid.New(),
+		DriverID:  driverID,
+		Latitude:  req.Latitude,
+		Longitude: req.Longitude,
+		Address:   req.Address,
+		ExpiresAt: now.Add(duration),
+		CreatedAt: now,
+	}
+	if err := destination.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.driverRepo.SetDestination(ctx, destination); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to set driver destination",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, DriverDestinationResponse{
+		DriverDestination: destination,
+		RemainingToday:    models.DriverDestinationDailyLimit - used - 1,
+	})
+}
+
+// GetDriverDestination handles retrieving the active destination
+// @Summary Get driver destination
+// @Description Get the driver's active destination
+// @Tags drivers
+// @Produce json
+// @Param id path string true "Driver ID"
+// @Success 200 {object} models.DriverDestination
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/destination [get]
+func (h *UserHandler) GetDriverDestination(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid driver ID",
+			Message: "Driver ID must be a valid UUID",
+		})
+		return
+	}
+
+	destination, err := h.driverRepo.GetActiveDestination(c.Request.Context(), driverID.String())
+	if err != nil {
+		if _, ok := err.(*models.NotFoundError); ok {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Destination not found",
+				Message: "Destination mode is not active for this driver",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to get driver destination",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, destination)
+}
+
+// ClearDriverDestination handles turning off destination mode
+// @Summary Clear driver destination
+// @Description Turn off destination mode. The activation still counts toward the daily limit.
+// @Tags drivers
+// @Param id path string true "Driver ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/destination [delete]
+func (h *UserHandler) ClearDriverDestination(c *gin.Context) {
+	driverID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid driver ID",
+			Message: "Driver ID must be a valid UUID",
+		})
+		return
+	}
+
+	if err := h.driverRepo.ClearDestination(c.Request.Context(), driverID.String()); err != nil {
+		if _, ok := err.(*models.NotFoundError); ok {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Destination not found",
+				Message: "Destination mode is not active for this driver",
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to clear driver destination",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // ListUsers handles user listing with pagination
 // @Summary List users
 // @Description Get a paginated list of users
@@ -550,6 +969,7 @@ func (h *UserHandler) UpdateDriverStatus(c *gin.Context) {
 // @Param user_type query string false "Filter by user type" Enums(passenger, driver)
 // @Param limit query int false "Number of items per page" default(20)
 // @Param offset query int false "Number of items to skip" default(0)
+// @Param fields query string false "Comma-separated list of fields to return"
 // @Success 200 {object} PaginatedResponse{data=[]models.User}
 // @Failure 400 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
@@ -587,9 +1007,32 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 		return
 	}
 
+	fields, ok := parseFields(c, models.User{})
+	if !ok {
+		return
+	}
+	columns := fields
+	if containsField(fields, "avatar_url") && !containsField(fields, "avatar_key") {
+		// The URL is signed from the key
+		columns = append(append([]string(nil), fields...), "avatar_key")
+	}
+	ctx := repository.WithColumns(c.Request.Context(), columns)
+
 	// Get users from repository
 	// TODO: Filter by userType when implementing user filtering
-	users, err := h.userRepo.List(c.Request.Context(), limit, offset)
+	users, err := h.userRepo.List(ctx, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to list users",
+		})
+		return
+	}
+	if !h.attachAvatarURLs(c, users...) {
+		return
+	}
+
+	data, err := selectFields(users, fields)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{
 			Error:   "Internal server error",
@@ -600,7 +1043,7 @@ func (h *UserHandler) ListUsers(c *gin.Context) {
 
 	// Return paginated response
 	c.JSON(http.StatusOK, PaginatedResponse{
-		Data:   users,
+		Data:   data,
 		Limit:  limit,
 		Offset: offset,
 		Total:  int64(len(users)), // Convert to int64
@@ -628,9 +1071,143 @@ func (h *UserHandler) GetOnlineDrivers(c *gin.Context) {
 	c.JSON(http.StatusOK, drivers)
 }
 
+// driverStatsPeriods maps the period query parameter to how far back it reaches
+var driverStatsPeriods = map[string]time.Duration{
+	"day":   24 * time.Hour,
+	"week":  7 * 24 * time.Hour,
+	"month": 30 * 24 * time.Hour,
+}
+
+// GetDriverStats handles the driver leaderboard
+// @Summary Get driver statistics
+// @Description Aggregate trips, acceptance rate, rating, earnings and online hours per driver over a period
+// @Tags admin
+// @Produce json
+// @Param period query string false "Period ending now: day, week or month" default(week)
+// @Param start_time query string false "Start time (RFC3339 format), overrides period"
+// @Param end_time query string false "End time (RFC3339 format), defaults to now"
+// @Param sort_by query string false "trips_completed, acceptance_rate, average_rating, earnings or online_hours" default(trips_completed)
+// @Param order query string false "asc or desc" default(desc)
+// @Param limit query int false "Number of items per page" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} PaginatedResponse{data=[]models.DriverStats}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/drivers/stats [get]
+func (h *UserHandler) GetDriverStats(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer between 1 and 100",
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid offset",
+			Message: "Offset must be a non-negative integer",
+		})
+		return
+	}
+
+	sortBy := models.DriverStatsSort(c.DefaultQuery("sort_by", string(models.DriverStatsSortTripsCompleted)))
+	if !sortBy.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid sort",
+			Message: "sort_by must be one of trips_completed, acceptance_rate, average_rating, earnings or online_hours",
+		})
+		return
+	}
+
+	order := c.DefaultQuery("order", "desc")
+	if order != "asc" && order != "desc" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid order",
+			Message: "Order must be either 'asc' or 'desc'",
+		})
+		return
+	}
+
+	to := time.Now()
+	if endTime := c.Query("end_time"); endTime != "" {
+		if to, err = time.Parse(time.RFC3339, endTime); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid end time",
+				Message: "end_time must be in RFC3339 format",
+			})
+			return
+		}
+	}
+
+	var from time.Time
+	if startTime := c.Query("start_time"); startTime != "" {
+		if from, err = time.Parse(time.RFC3339, startTime); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid start time",
+				Message: "start_time must be in RFC3339 format",
+			})
+			return
+		}
+	} else {
+		period, ok := driverStatsPeriods[c.DefaultQuery("period", "week")]
+		if !ok {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid period",
+				Message: "Period must be one of day, week or month",
+			})
+			return
+		}
+		from = to.Add(-period)
+	}
+
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid time range",
+			Message: "start_time must be before end_time",
+		})
+		return
+	}
+
+	stats, total, err := h.driverRepo.GetDriverStats(c.Request.Context(), models.DriverStatsFilter{
+		From:       from,
+		To:         to,
+		SortBy:     sortBy,
+		Descending: order == "desc",
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		switch err.(type) {
+		case *models.ValidationError:
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation error",
+				Message: err.Error(),
+			})
+		default:
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Internal server error",
+				Message: "Failed to get driver statistics",
+			})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:    stats,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: int64(offset+len(stats)) < total,
+	})
+}
+
 // CreatePassengerRequest represents the request payload for passenger creation
 type CreatePassengerRequest struct {
 	CreateUserRequest
+	RidePreferences *models.RidePreferences `json:"ride_preferences,omitempty"`
 }
 
 // CreatePassenger handles passenger creation
@@ -672,6 +1249,15 @@ func (h *UserHandler) CreatePassenger(c *gin.Context) {
 		})
 		return
 	}
+	if req.RidePreferences != nil {
+		if err := req.RidePreferences.Validate(); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Validation failed",
+				Message: err.Error(),
+			})
+			return
+		}
+	}
 
 	// Create user in database
 	if err := h.userRepo.Create(c.Request.Context(), user); err != nil {
@@ -699,6 +1285,9 @@ func (h *UserHandler) CreatePassenger(c *gin.Context) {
 		CreatedAt:  user.CreatedAt,
 		UpdatedAt:  user.UpdatedAt,
 	}
+	if req.RidePreferences != nil {
+		passenger.RidePreferences = *req.RidePreferences
+	}
 
 	if err := h.passengerRepo.Create(c.Request.Context(), passenger); err != nil {
 		switch err.(type) {
@@ -718,3 +1307,201 @@ func (h *UserHandler) CreatePassenger(c *gin.Context) {
 
 	c.JSON(http.StatusCreated, user)
 }
+
+// avatarFormOverhead is the room left for the multipart framing of an uploaded picture
+const avatarFormOverhead = 64 << 10
+
+// UploadAvatar handles uploading a user's profile picture
+// @Summary Upload profile picture
+// @Description Set the user's profile picture from a JPEG, PNG or WebP image, sent as the request body or as the avatar field of a multipart form. The type is detected from the content, and the picture must not exceed the configured size (5 MiB by default). It replaces the previous picture and is returned as avatar_url, a signed URL that expires after the configured time; user payloads carry a fresh one.
+// @Tags users
+// @Accept image/jpeg
+// @Accept image/png
+// @Accept image/webp
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "User ID"
+// @Param avatar formData file false "Picture"
+// @Success 200 {object} models.User
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/users/{id}/avatar [put]
+func (h *UserHandler) UploadAvatar(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "id", "Invalid user ID", "User ID must be a valid UUID")
+	if !ok {
+		return
+	}
+	h.uploadAvatar(c, userID.String())
+}
+
+// UploadDriverAvatar handles uploading a driver's profile picture
+// @Summary Upload driver profile picture
+// @Description Set the profile picture of the driver's user, as for users
+// @Tags drivers
+// @Accept image/jpeg
+// @Accept image/png
+// @Accept image/webp
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Driver ID"
+// @Param avatar formData file false "Picture"
+// @Success 200 {object} models.User
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/drivers/{id}/avatar [put]
+func (h *UserHandler) UploadDriverAvatar(c *gin.Context) {
+	driverID, ok := parseUUIDParam(c, "id", "Invalid driver ID", "Driver ID must be a valid UUID")
+	if !ok {
+		return
+	}
+
+	driver, err := h.driverRepo.GetByID(c.Request.Context(), driverID.String())
+	if err != nil {
+		h.writeAvatarError(c, err, "Failed to get driver")
+		return
+	}
+	h.uploadAvatar(c, driver.UserID.String())
+}
+
+// DeleteAvatar handles removing a user's profile picture
+// @Summary Remove profile picture
+// @Description Remove the user's profile picture, deleting the stored file
+// @Tags users
+// @Produce json
+// @Param id path string true "User ID"
+// @Success 200 {object} models.User
+// @Failure 400 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 503 {object} ErrorResponse
+// @Router /api/v1/users/{id}/avatar [delete]
+func (h *UserHandler) DeleteAvatar(c *gin.Context) {
+	userID, ok := parseUUIDParam(c, "id", "Invalid user ID", "User ID must be a valid UUID")
+	if !ok || !h.avatarsAvailable(c) {
+		return
+	}
+
+	user, err := h.avatars.Remove(c.Request.Context(), userID.String())
+	if err != nil {
+		h.writeAvatarError(c, err, "Failed to remove profile picture")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// uploadAvatar reads the picture of the request and stores it as the user's profile picture
+func (h *UserHandler) uploadAvatar(c *gin.Context, userID string) {
+	if !h.avatarsAvailable(c) {
+		return
+	}
+
+	maxSize := int64(h.avatars.MaxSize())
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+avatarFormOverhead)
+	var file io.Reader = c.Request.Body
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		header, err := c.FormFile("avatar")
+		if err != nil {
+			h.writeUploadError(c, err, "The multipart form must have an avatar field")
+			return
+		}
+		upload, err := header.Open()
+		if err != nil {
+			h.writeUploadError(c, err, err.Error())
+			return
+		}
+		defer upload.Close()
+		file = upload
+	}
+
+	// One byte more than allowed is enough to reject the picture as too large
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		h.writeUploadError(c, err, err.Error())
+		return
+	}
+
+	user, err := h.avatars.Upload(c.Request.Context(), userID, data)
+	if err != nil {
+		h.writeAvatarError(c, err, "Failed to upload profile picture")
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// avatarsAvailable responds with 503 when profile pictures are not available
+func (h *UserHandler) avatarsAvailable(c *gin.Context) bool {
+	if h.avatars == nil {
+		c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Error:   "Profile pictures not available",
+			Message: "No file storage is configured",
+		})
+		return false
+	}
+	return true
+}
+
+// attachAvatarURLs sets the avatar URLs of the users, responding with 500 when signing fails
+func (h *UserHandler) attachAvatarURLs(c *gin.Context, users ...*models.User) bool {
+	if h.avatars == nil {
+		return true
+	}
+	if err := h.avatars.AttachURLs(c.Request.Context(), users...); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to sign profile picture URLs",
+		})
+		return false
+	}
+	return true
+}
+
+// writeUploadError responds to a picture that could not be read
+func (h *UserHandler) writeUploadError(c *gin.Context, err error, message string) {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		message = fmt.Sprintf("The picture must not exceed %d bytes", h.avatars.MaxSize())
+	}
+	c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:   "Invalid request payload",
+		Message: message,
+	})
+}
+
+// writeAvatarError maps an avatar service error to a response
+func (h *UserHandler) writeAvatarError(c *gin.Context, err error, message string) {
+	var notFound *models.NotFoundError
+	var validation *models.ValidationError
+	switch {
+	case errors.As(err, &notFound):
+		c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "Not found",
+			Message: err.Error(),
+		})
+	case errors.As(err, &validation):
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: message,
+		})
+	}
+}
+
+// containsField reports whether a field was requested
+func containsField(fields []string, field string) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// APIKeyHeader identifies the API consumer that owns a webhook subscription
+const APIKeyHeader = "KEY"
+
+// WebhookHandler handles webhook subscriptions and the admin delivery listing
+type WebhookHandler struct {
+	webhookRepo repository.WebhookRepository
+}
+
+// NewWebhookHandler creates a new WebhookHandler instance
+func NewWebhookHandler(webhookRepo repository.WebhookRepository) *WebhookHandler {
+	return &WebhookHandler{
+		webhookRepo: webhookRepo,
+	}
+}
+
+// RegisterWebhookRequest represents the request payload for registering a webhook
+type RegisterWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Events []string `json:"events,omitempty"`                            // empty subscribes to every event
+	Secret string   `json:"secret,omitempty" binding:"omitempty,min=16"` // generated when omitted
+}
+
+// RegisterWebhook handles registering a webhook for the calling API key
+// @Summary Register a webhook
+// @Description Register a URL that is called on trip lifecycle transitions, fare dispute decisions and safety incidents (trip.requested, trip.matched, trip.completed, trip.cancelled, fare_dispute.resolved, fare_dispute.rejected, safety_incident.reported). Requests are signed with HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>" in the X-Webhook-Signature header; the secret is only returned here.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Security api_key
+// @Param request body RegisterWebhookRequest true "Webhook details"
+// @Success 201 {object} models.WebhookSubscription
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/webhooks [post]
+func (h *WebhookHandler) RegisterWebhook(c *gin.Context) {
+	owner, ok := h.apiKey(c)
+	if !ok {
+		return
+	}
+
+	var req RegisterWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid request payload",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = generateWebhookSecret(); err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "Internal server error",
+				Message: "Failed to generate webhook secret",
+			})
+			return
+		}
+	}
+
+	now := time.Now()
+	subscription := &models.WebhookSubscription{
+		ID:        uuid.New(),
+		Owner:     owner,
+		URL:       req.URL,
+		Secret:    secret,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for _, event := range req.Events {
+		subscription.Events = append(subscription.Events, models.WebhookEvent(event))
+	}
+
+	if err := subscription.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Validation error",
+			Message: err.Error(),
+		})
+		return
+	}
+
+	if err := h.webhookRepo.CreateSubscription(c.Request.Context(), subscription); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to register webhook",
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, subscription)
+}
+
+// ListWebhooks handles listing the webhooks of the calling API key
+// @Summary List webhooks
+// @Description Get the webhooks registered by the calling API key. Secrets are not included.
+// @Tags webhooks
+// @Produce json
+// @Security api_key
+// @Success 200 {array} models.WebhookSubscription
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(c *gin.Context) {
+	owner, ok := h.apiKey(c)
+	if !ok {
+		return
+	}
+
+	subscriptions, err := h.webhookRepo.ListSubscriptionsByOwner(c.Request.Context(), owner)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to list webhooks",
+		})
+		return
+	}
+	if subscriptions == nil {
+		subscriptions = []*models.WebhookSubscription{}
+	}
+	for _, s := range subscriptions {
+		s.Secret = ""
+	}
+
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+// DeleteWebhook handles removing a webhook of the calling API key
+// @Summary Delete a webhook
+// @Description Remove a webhook and its delivery history
+// @Tags webhooks
+// @Security api_key
+// @Param id path string true "Webhook ID"
+// @Success 204
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	owner, ok := h.apiKey(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid webhook ID",
+			Message: "Webhook ID must be a valid UUID",
+		})
+		return
+	}
+
+	// Webhooks of other API keys are reported as missing
+	subscription, err := h.webhookRepo.GetSubscription(c.Request.Context(), id.String())
+	if err == nil && subscription.Owner != owner {
+		err = &models.NotFoundError{Resource: "webhook subscription", ID: id.String()}
+	}
+	if err == nil {
+		err = h.webhookRepo.DeleteSubscription(c.Request.Context(), id.String())
+	}
+	if err != nil {
+		if _, notFound := err.(*models.NotFoundError); notFound {
+			c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "Resource not found",
+				Message: err.Error(),
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to delete webhook",
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListWebhookDeliveries handles listing webhook deliveries for operators
+// @Summary List webhook deliveries
+// @Description Get webhook deliveries with their status, attempts and last error, newest first
+// @Tags admin
+// @Produce json
+// @Param subscription_id query string false "Filter by webhook ID"
+// @Param trip_id query string false "Filter by trip ID"
+// @Param status query string false "pending, succeeded or failed"
+// @Param limit query int false "Number of items per page" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} PaginatedResponse{data=[]models.WebhookDelivery}
+// @Failure 400 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /api/v1/admin/webhooks/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid limit",
+			Message: "Limit must be a positive integer between 1 and 100",
+		})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid offset",
+			Message: "Offset must be a non-negative integer",
+		})
+		return
+	}
+
+	filter := models.WebhookDeliveryFilter{
+		Status: models.WebhookDeliveryStatus(c.Query("status")),
+		Limit:  limit,
+		Offset: offset,
+	}
+	if filter.Status != "" && !filter.Status.IsValid() {
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "Invalid status",
+			Message: "Status must be one of pending, succeeded or failed",
+		})
+		return
+	}
+	if subscriptionID := c.Query("subscription_id"); subscriptionID != "" {
+		id, err := uuid.Parse(subscriptionID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid webhook ID",
+				Message: "subscription_id must be a valid UUID",
+			})
+			return
+		}
+		filter.SubscriptionID = &id
+	}
+	if tripID := c.Query("trip_id"); tripID != "" {
+		id, err := uuid.Parse(tripID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "Invalid trip ID",
+				Message: "trip_id must be a valid UUID",
+			})
+			return
+		}
+		filter.TripID = &id
+	}
+
+	deliveries, total, err := h.webhookRepo.ListDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "Internal server error",
+			Message: "Failed to list webhook deliveries",
+		})
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*models.WebhookDelivery{}
+	}
+
+	c.JSON(http.StatusOK, PaginatedResponse{
+		Data:    deliveries,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: offset+len(deliveries) < int(total),
+	})
+}
+
+// apiKey returns the caller's API key, writing a 401 response and returning false when it is missing
+func (h *WebhookHandler) apiKey(c *gin.Context) (string, bool) {
+	key := c.GetHeader(APIKeyHeader)
+	if key == "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{
+			Error:   "Missing API key",
+			Message: "The " + APIKeyHeader + " header is required",
+		})
+		return "", false
+	}
+	return key, true
+}
+
+// generateWebhookSecret returns a random secret for signing deliveries
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
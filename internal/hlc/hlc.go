@@ -0,0 +1,250 @@
+// Package hlc provides hybrid logical clocks, which order the events and actor messages recorded
+// by components whose wall clocks disagree. A hybrid timestamp is the wall time of its source,
+// never going backwards, and a logical counter ordering the timestamps issued within the same
+// wall time; receiving a timestamp moves the clock past it, so causally related records stay in
+// order even when the receiver's wall clock is behind the sender's.
+package hlc
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Timestamp is a hybrid logical timestamp issued by the clock of Source
+type Timestamp struct {
+	WallTime int64  `json:"wall_time"` // Unix nanoseconds
+	Logical  uint32 `json:"logical"`
+	Source   string `json:"source"`
+}
+
+// IsZero reports whether t was never issued
+func (t Timestamp) IsZero() bool {
+	return t.WallTime == 0 && t.Logical == 0
+}
+
+// Time returns the wall time of t
+func (t Timestamp) Time() time.Time {
+	return time.Unix(0, t.WallTime).UTC()
+}
+
+// Compare returns -1 if t orders before u, 1 if after and 0 if they are equal. Timestamps of
+// different sources with the same wall time and counter are ordered by source, so that the
+// order is total.
+func (t Timestamp) Compare(u Timestamp) int {
+	switch {
+	case t.WallTime != u.WallTime:
+		if t.WallTime < u.WallTime {
+			return -1
+		}
+		return 1
+	case t.Logical != u.Logical:
+		if t.Logical < u.Logical {
+			return -1
+		}
+		return 1
+	default:
+		return strings.Compare(t.Source, u.Source)
+	}
+}
+
+// Before reports whether t orders before u
+func (t Timestamp) Before(u Timestamp) bool {
+	return t.Compare(u) < 0
+}
+
+// String encodes t as the wall time and counter in fixed-width decimal and the source, e.g.
+// 1760608800123456789.0000000002@api-1, so that encoded timestamps sort like Compare
+func (t Timestamp) String() string {
+	return fmt.Sprintf("%019d.%010d@%s", t.WallTime, t.Logical, t.Source)
+}
+
+// Parse decodes a timestamp encoded by String
+func Parse(s string) (Timestamp, error) {
+	stamp, source, ok := strings.Cut(s, "@")
+	wall, logical, ok2 := strings.Cut(stamp, ".")
+	if !ok || !ok2 {
+		return Timestamp{}, fmt.Errorf("invalid hybrid timestamp %q", s)
+	}
+	wallTime, err := strconv.ParseInt(wall, 10, 64)
+	if err != nil {
+		return Timestamp{}, fmt.Errorf("invalid hybrid timestamp %q: %w", s, err)
+	}
+	counter, err := strconv.ParseUint(logical, 10, 32)
+	if err != nil {
+		return Timestamp{}, fmt.Errorf("invalid hybrid timestamp %q: %w", s, err)
+	}
+	return Timestamp{WallTime: wallTime, Logical: uint32(counter), Source: source}, nil
+}
+
+// Value stores t in its String encoding
+func (t Timestamp) Value() (driver.Value, error) {
+	return t.String(), nil
+}
+
+// Scan reads a timestamp stored in its String encoding
+func (t *Timestamp) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into a hybrid timestamp", src)
+	}
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// Skew is a clock found ahead of the local wall clock by more than the tolerated skew
+type Skew struct {
+	Source string        // the clock ahead, as named by the timestamp or record
+	Offset time.Duration // how far ahead it is
+}
+
+// Clock issues the hybrid timestamps of one source. It is safe for concurrent use.
+type Clock struct {
+	source  string
+	maxSkew time.Duration
+	now     func() time.Time
+
+	mu     sync.Mutex
+	last   Timestamp
+	onSkew func(Skew)
+	skews  int64
+}
+
+// NewClock creates the clock of source, tolerating clocks up to maxSkew ahead of the local wall
+// clock. Timestamps further ahead are reported as skew and the clock only moves up to maxSkew
+// past the local wall clock for them, so one bad clock cannot drag every later timestamp into the
+// future. A maxSkew of 0 disables the check.
+func NewClock(source string, maxSkew time.Duration) *Clock {
+	return &Clock{source: source, maxSkew: maxSkew, now: time.Now}
+}
+
+// SetSkewHandler sets the function called, outside the clock's lock, for every skew found
+func (c *Clock) SetSkewHandler(handler func(Skew)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onSkew = handler
+}
+
+// SetNow replaces the local wall clock, for tests
+func (c *Clock) SetNow(now func() time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
+
+// Source returns the source of the clock's timestamps
+func (c *Clock) Source() string {
+	return c.source
+}
+
+// Skews returns the number of skews found
+func (c *Clock) Skews() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.skews
+}
+
+// Now issues a timestamp for a local event, after every timestamp issued or received before
+func (c *Clock) Now() Timestamp {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.advance(c.now().UnixNano(), 0, 0)
+}
+
+// Update receives the timestamp of a remote event, such as a message sent by another source,
+// and issues the timestamp of receiving it, after remote unless remote is too far ahead
+func (c *Clock) Update(remote Timestamp) Timestamp {
+	c.mu.Lock()
+	physical := c.now().UnixNano()
+	skew, skewed := c.checkSkew(remote.Source, remote.WallTime, physical)
+	wall, logical := remote.WallTime, remote.Logical
+	if skewed {
+		wall, logical = physical+int64(c.maxSkew), 0
+	}
+	ts := c.advance(physical, wall, logical)
+	handler := c.onSkew
+	c.mu.Unlock()
+
+	if skewed && handler != nil {
+		handler(skew)
+	}
+	return ts
+}
+
+// Observe checks the wall time a source recorded for an event against the local wall clock,
+// reporting the source's clock as skewed if it is too far ahead
+func (c *Clock) Observe(source string, wallTime time.Time) {
+	c.mu.Lock()
+	skew, skewed := c.checkSkew(source, wallTime.UnixNano(), c.now().UnixNano())
+	handler := c.onSkew
+	c.mu.Unlock()
+
+	if skewed && handler != nil {
+		handler(skew)
+	}
+}
+
+// checkSkew reports whether wall is more than maxSkew ahead of physical; callers hold the lock
+func (c *Clock) checkSkew(source string, wall, physical int64) (Skew, bool) {
+	offset := time.Duration(wall - physical)
+	if c.maxSkew <= 0 || offset <= c.maxSkew {
+		return Skew{}, false
+	}
+	c.skews++
+	return Skew{Source: source, Offset: offset}, true
+}
+
+// advance issues the next timestamp at or after the physical time and the received wall time
+// and counter; callers hold the lock
+func (c *Clock) advance(physical, wall int64, logical uint32) Timestamp {
+	next := Timestamp{WallTime: physical, Source: c.source}
+	switch {
+	case c.last.WallTime >= physical && c.last.WallTime >= wall:
+		next.WallTime = c.last.WallTime
+		next.Logical = c.last.Logical + 1
+		if c.last.WallTime == wall && logical >= c.last.Logical {
+			next.Logical = logical + 1
+		}
+	case wall >= physical:
+		next.WallTime = wall
+		next.Logical = logical + 1
+	}
+	c.last = next
+	return next
+}
+
+// Event is a record ordered by a hybrid timestamp, stamped by Stamp when it is published
+type Event interface {
+	// HybridTimestamp returns the record's timestamp, nil until stamped
+	HybridTimestamp() *Timestamp
+	SetHybridTimestamp(ts Timestamp)
+	// SourceTime returns when the record's source, named by source, says it happened by its own
+	// wall clock
+	SourceTime() (at time.Time, source string)
+}
+
+// Stamp gives an event the timestamp of publishing it, checking the wall time its source
+// recorded for skew. Events stamped already, such as those published by another source, move
+// the clock past their timestamp instead and keep it.
+func (c *Clock) Stamp(event Event) {
+	if ts := event.HybridTimestamp(); ts != nil {
+		c.Update(*ts)
+		return
+	}
+	if at, source := event.SourceTime(); !at.IsZero() {
+		c.Observe(source, at)
+	}
+	event.SetHybridTimestamp(c.Now())
+}
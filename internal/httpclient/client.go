@@ -0,0 +1,309 @@
+// Package httpclient is the outbound HTTP client shared by the integrations calling other
+// services, such as webhook delivery. Every request runs in a client span whose context is
+// propagated to the destination, failed idempotent requests are retried within a per-destination
+// retry budget, and a per-destination circuit breaker fails requests fast while a destination
+// keeps failing.
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// IdempotencyKeyHeader marks a request that is safe to retry whatever its method, the
+// destination dropping repeated keys
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// ErrCircuitOpen is returned without calling the destination while its circuit is open
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Instrumentation holds the tracer and instruments the clients report to
+type Instrumentation struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	duration   metric.Float64Histogram
+	retries    metric.Int64Counter
+	rejected   metric.Int64Counter
+	trips      metric.Int64Counter
+}
+
+// NewInstrumentation creates the instrumentation of the clients. A nil tracer or meter disables
+// spans or metrics respectively.
+func NewInstrumentation(tracer trace.Tracer, meter metric.Meter) (*Instrumentation, error) {
+	if tracer == nil {
+		tracer = tracenoop.NewTracerProvider().Tracer("")
+	}
+	if meter == nil {
+		meter = metricnoop.NewMeterProvider().Meter("")
+	}
+
+	duration, err := meter.Float64Histogram(
+		"http_client_request_duration_seconds",
+		metric.WithDescription("Duration of outbound HTTP request attempts"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	retries, err := meter.Int64Counter(
+		"http_client_retries_total",
+		metric.WithDescription("Outbound HTTP requests retried, or not retried because the retry budget was spent"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	rejected, err := meter.Int64Counter(
+		"http_client_circuit_rejections_total",
+		metric.WithDescription("Outbound HTTP requests failed fast by an open circuit"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	trips, err := meter.Int64Counter(
+		"http_client_circuit_transitions_total",
+		metric.WithDescription("Circuit breaker state changes by destination"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Instrumentation{
+		tracer:     tracer,
+		propagator: propagation.TraceContext{},
+		duration:   duration,
+		retries:    retries,
+		rejected:   rejected,
+		trips:      trips,
+	}, nil
+}
+
+// Client sends the requests of one integration, named in its spans and metrics
+type Client struct {
+	name   string
+	cfg    config.HTTPClientConfig
+	http   *http.Client
+	inst   *Instrumentation
+	logger *logging.Logger
+	now    func() time.Time
+
+	mu           sync.Mutex
+	destinations map[string]*destination
+}
+
+// New creates a client for the named integration, e.g. "webhook". A nil instrumentation reports
+// nowhere. The zero configuration apart from a timeout is a plain client: no retries and no
+// circuit breaker.
+func New(name string, cfg config.HTTPClientConfig, inst *Instrumentation, logger *logging.Logger) *Client {
+	if inst == nil {
+		inst, _ = NewInstrumentation(nil, nil)
+	}
+	return &Client{
+		name:         name,
+		cfg:          cfg,
+		http:         &http.Client{Timeout: cfg.Timeout},
+		inst:         inst,
+		logger:       logger.WithComponent("http_client").WithField("client", name),
+		now:          time.Now,
+		destinations: make(map[string]*destination),
+	}
+}
+
+// Do sends the request, retrying it while it fails and is idempotent, the attempts and the
+// destination's retry budget allow. A request fails on a transport error or a 429 or 5xx
+// response; the last response is returned as is, and must be closed by the caller.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	host := req.URL.Host
+	dest := c.destination(host)
+	dest.budget.deposit(c.cfg.RetryBudgetRatio)
+
+	ctx, span := c.inst.tracer.Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", req.URL.Redacted()),
+			attribute.String("server.address", host),
+			attribute.String("http.client", c.name),
+		),
+	)
+	defer span.End()
+
+	for attempt := 1; ; attempt++ {
+		resp, err := c.attempt(ctx, req, dest, attempt)
+		if !failed(resp, err) || errors.Is(err, ErrCircuitOpen) {
+			return c.finish(span, resp, err, attempt)
+		}
+
+		if attempt > c.cfg.MaxRetries || !retryable(req) {
+			return c.finish(span, resp, err, attempt)
+		}
+		if !dest.budget.withdraw() {
+			c.inst.retries.Add(ctx, 1, metric.WithAttributes(c.attributes(host, attribute.String("outcome", "budget_exhausted"))...))
+			return c.finish(span, resp, err, attempt)
+		}
+		c.inst.retries.Add(ctx, 1, metric.WithAttributes(c.attributes(host, attribute.String("outcome", "retried"))...))
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		if err := sleep(ctx, c.backoff(attempt)); err != nil {
+			return c.finish(span, nil, err, attempt)
+		}
+	}
+}
+
+// attempt sends the request once, unless the destination's circuit is open
+func (c *Client) attempt(ctx context.Context, req *http.Request, dest *destination, attempt int) (*http.Response, error) {
+	out := req.Clone(ctx)
+	if attempt > 1 && req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to rewind request body: %w", err)
+		}
+		out.Body = body
+	}
+	c.inst.propagator.Inject(ctx, propagation.HeaderCarrier(out.Header))
+
+	if from, to, ok := dest.breaker.allow(c.now(), c.cfg); !ok {
+		c.inst.rejected.Add(ctx, 1, metric.WithAttributes(c.attributes(dest.host)...))
+		return nil, fmt.Errorf("%w for %s", ErrCircuitOpen, dest.host)
+	} else if from != to {
+		c.transitioned(ctx, dest.host, from, to)
+	}
+
+	start := c.now()
+	resp, err := c.http.Do(out)
+	duration := c.now().Sub(start)
+
+	status := "error"
+	if resp != nil {
+		status = strconv.Itoa(resp.StatusCode)
+	}
+	c.inst.duration.Record(ctx, duration.Seconds(), metric.WithAttributes(
+		c.attributes(dest.host, attribute.String("method", req.Method), attribute.String("status", status))...,
+	))
+
+	if from, to := dest.breaker.record(!failed(resp, err), c.now(), c.cfg); from != to {
+		c.transitioned(ctx, dest.host, from, to)
+	}
+	return resp, err
+}
+
+// finish ends the request's span with its outcome
+func (c *Client) finish(span trace.Span, resp *http.Response, err error, attempts int) (*http.Response, error) {
+	span.SetAttributes(attribute.Int("http.attempts", attempts))
+	if resp != nil {
+		span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	} else if failed(resp, nil) {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, err
+}
+
+// transitioned reports a change of a destination's circuit
+func (c *Client) transitioned(ctx context.Context, host string, from, to BreakerState) {
+	c.inst.trips.Add(ctx, 1, metric.WithAttributes(c.attributes(host, attribute.String("state", string(to)))...))
+	entry := c.logger.WithFields(logging.Fields{"destination": host, "from": from, "to": to})
+	if to == BreakerOpen {
+		entry.Warn("Circuit opened, failing requests fast")
+	} else {
+		entry.Info("Circuit state changed")
+	}
+}
+
+// attributes are the metric attributes of a destination
+func (c *Client) attributes(host string, extra ...attribute.KeyValue) []attribute.KeyValue {
+	return append([]attribute.KeyValue{
+		attribute.String("client", c.name),
+		attribute.String("destination", host),
+	}, extra...)
+}
+
+// backoff returns the delay before the retry following attempt, doubling from the initial
+// backoff up to the maximum
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := float64(c.cfg.InitialBackoff) * math.Pow(2, float64(attempt-1))
+	if c.cfg.MaxBackoff > 0 && delay > float64(c.cfg.MaxBackoff) {
+		return c.cfg.MaxBackoff
+	}
+	return time.Duration(delay)
+}
+
+// Breakers returns the circuit state of every destination called so far, by host
+func (c *Client) Breakers() map[string]BreakerState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	states := make(map[string]BreakerState, len(c.destinations))
+	for host, dest := range c.destinations {
+		states[host] = dest.breaker.snapshot()
+	}
+	return states
+}
+
+// destination returns the state kept for a host, creating it on first use
+func (c *Client) destination(host string) *destination {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dest, ok := c.destinations[host]
+	if !ok {
+		dest = &destination{host: host, budget: retryBudget{tokens: float64(c.cfg.RetryBudgetBurst), max: float64(c.cfg.RetryBudgetBurst)}}
+		c.destinations[host] = dest
+	}
+	return dest
+}
+
+// failed reports whether an attempt failed: a transport error, or a response asking to back off
+// or reporting a server error
+func failed(resp *http.Response, err error) bool {
+	return err != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+// retryable reports whether sending the request again is safe: its method is idempotent or it
+// carries an idempotency key, and its body can be replayed
+func retryable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(IdempotencyKeyHeader) != ""
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,123 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/config"
+)
+
+// BreakerState is the state of a destination's circuit breaker
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"    // requests are sent
+	BreakerOpen     BreakerState = "open"      // requests fail fast until the cooldown is over
+	BreakerHalfOpen BreakerState = "half_open" // one trial request decides whether to close again
+)
+
+// destination is the state kept per destination host
+type destination struct {
+	host    string
+	breaker breaker
+	budget  retryBudget
+}
+
+// breaker is a consecutive failures circuit breaker
+type breaker struct {
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	trial    bool // a half-open trial request is in flight
+}
+
+// allow reports whether a request may be sent now, and the state change it caused: an open
+// circuit whose cooldown is over lets one trial request through
+func (b *breaker) allow(now time.Time, cfg config.HTTPClientConfig) (from, to BreakerState, ok bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	from = b.current()
+	switch {
+	case cfg.BreakerFailures == 0 || from == BreakerClosed:
+		return from, from, true
+	case from == BreakerOpen && now.Sub(b.openedAt) >= cfg.BreakerCooldown:
+		b.state, b.trial = BreakerHalfOpen, true
+		return from, BreakerHalfOpen, true
+	case from == BreakerHalfOpen && !b.trial:
+		b.trial = true
+		return from, from, true
+	default:
+		return from, from, false
+	}
+}
+
+// record counts the outcome of a request and returns the state change it caused
+func (b *breaker) record(success bool, now time.Time, cfg config.HTTPClientConfig) (from, to BreakerState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	from = b.current()
+	if cfg.BreakerFailures == 0 {
+		return from, from
+	}
+
+	b.trial = false
+	if success {
+		b.state, b.failures = BreakerClosed, 0
+		return from, b.state
+	}
+
+	b.failures++
+	if from == BreakerHalfOpen || b.failures >= cfg.BreakerFailures {
+		b.state, b.openedAt = BreakerOpen, now
+	}
+	return from, b.current()
+}
+
+// snapshot returns the state
+func (b *breaker) snapshot() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.current()
+}
+
+// current returns the state; the zero breaker is closed
+func (b *breaker) current() BreakerState {
+	if b.state == "" {
+		return BreakerClosed
+	}
+	return b.state
+}
+
+// retryBudget limits the retries to a destination to a share of its requests, so that retries
+// don't multiply the load on a destination that is already failing
+type retryBudget struct {
+	mu     sync.Mutex
+	tokens float64
+	max    float64
+}
+
+// deposit credits a request's share of a retry
+func (b *retryBudget) deposit(ratio float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens += ratio
+	if b.tokens > b.max {
+		b.tokens = b.max
+	}
+}
+
+// withdraw spends a retry, reporting whether one was left
+func (b *retryBudget) withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
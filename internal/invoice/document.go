@@ -0,0 +1,243 @@
+// Package invoice renders invoices as CSV, for spreadsheets and accounting imports, and as PDF,
+// for the parties invoiced. The PDF is written by hand in a fixed-width font, enough for an
+// invoice's header and line items without a PDF library.
+package invoice
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// PDF page layout, in points: A4 portrait with the body in 9pt Courier, whose characters are
+// 0.6em wide
+const (
+	pageWidth    = 595
+	pageHeight   = 842
+	margin       = 40
+	fontSize     = 9
+	titleSize    = 14
+	lineHeight   = 12
+	maxLineChars = (pageWidth - 2*margin) * 5 / (fontSize * 3)
+	linesPerPage = (pageHeight-2*margin)/lineHeight - 2 // the footer takes two lines
+	columnGap    = 2
+)
+
+// Field is a labelled value of an invoice's header or summary
+type Field struct {
+	Label string
+	Value string
+}
+
+// Document is an invoice: a title, header fields such as the party and period, a table of line
+// items and summary fields such as the total. The CSV export is the table alone.
+type Document struct {
+	Title   string
+	Header  []Field
+	Columns []string
+	Rows    [][]string
+	Summary []Field
+}
+
+// WriteCSV writes the line items as CSV, with the columns as header
+func (d *Document) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(d.Columns); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(d.Rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// line is a line of the PDF body, bold for the title and table header
+type line struct {
+	text  string
+	bold  bool
+	title bool
+}
+
+// WritePDF writes the invoice as a PDF, breaking the line items over as many pages as needed
+func (d *Document) WritePDF(w io.Writer) error {
+	pages := paginate(d.lines())
+
+	// Objects 1 to 4 are the catalog, page tree and fonts; each page then has a page object
+	// followed by its content stream
+	var objects [][]byte
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 5+2*i)
+	}
+	objects = append(objects,
+		[]byte("<< /Type /Catalog /Pages 2 0 R >>"),
+		[]byte(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))),
+		[]byte("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding /WinAnsiEncoding >>"),
+		[]byte("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding /WinAnsiEncoding >>"),
+	)
+	for i, page := range pages {
+		content := pageContent(page, i+1, len(pages))
+		objects = append(objects,
+			[]byte(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+				pageWidth, pageHeight, 6+2*i)),
+			[]byte(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content)),
+		)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// lines lays the invoice out as lines of text, the table in columns as wide as their widest cell
+func (d *Document) lines() []line {
+	lines := []line{{text: d.Title, bold: true, title: true}, {}}
+	lines = append(lines, fieldLines(d.Header)...)
+	lines = append(lines, line{})
+
+	widths := make([]int, len(d.Columns))
+	for i, column := range d.Columns {
+		widths[i] = utf8.RuneCountInString(column)
+	}
+	for _, row := range d.Rows {
+		for i, cell := range row {
+			if i < len(widths) && utf8.RuneCountInString(cell) > widths[i] {
+				widths[i] = utf8.RuneCountInString(cell)
+			}
+		}
+	}
+	lines = append(lines, line{text: tableRow(d.Columns, widths), bold: true})
+	for _, row := range d.Rows {
+		lines = append(lines, line{text: tableRow(row, widths)})
+	}
+	if len(d.Rows) == 0 {
+		lines = append(lines, line{text: "No line items"})
+	}
+
+	if len(d.Summary) > 0 {
+		lines = append(lines, line{})
+		lines = append(lines, fieldLines(d.Summary)...)
+	}
+	return lines
+}
+
+// fieldLines lays fields out one per line, their values aligned
+func fieldLines(fields []Field) []line {
+	width := 0
+	for _, field := range fields {
+		if n := utf8.RuneCountInString(field.Label); n > width {
+			width = n
+		}
+	}
+
+	lines := make([]line, len(fields))
+	for i, field := range fields {
+		lines[i] = line{text: pad(field.Label+":", width+1+columnGap) + field.Value}
+	}
+	return lines
+}
+
+// tableRow lays the cells of a row out in columns of the given widths
+func tableRow(cells []string, widths []int) string {
+	var b strings.Builder
+	for i, cell := range cells {
+		if i == len(cells)-1 || i >= len(widths) {
+			b.WriteString(cell)
+			break
+		}
+		b.WriteString(pad(cell, widths[i]+columnGap))
+	}
+	return b.String()
+}
+
+// pad right-pads text with spaces to width characters
+func pad(text string, width int) string {
+	if n := utf8.RuneCountInString(text); n < width {
+		return text + strings.Repeat(" ", width-n)
+	}
+	return text
+}
+
+// paginate breaks lines into pages. A document always has a page, even if empty.
+func paginate(lines []line) [][]line {
+	pages := [][]line{nil}
+	for _, l := range lines {
+		current := pages[len(pages)-1]
+		if len(current) == linesPerPage {
+			pages = append(pages, nil)
+		}
+		pages[len(pages)-1] = append(pages[len(pages)-1], l)
+	}
+	return pages
+}
+
+// pageContent returns the content stream drawing the lines of page number of total
+func pageContent(lines []line, number, total int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "BT\n%d TL\n%d %d Td\n", lineHeight, margin, pageHeight-margin)
+	font := ""
+	for _, l := range lines {
+		want := fmt.Sprintf("/F1 %d Tf", fontSize)
+		switch {
+		case l.title:
+			want = fmt.Sprintf("/F2 %d Tf", titleSize)
+		case l.bold:
+			want = fmt.Sprintf("/F2 %d Tf", fontSize)
+		}
+		if want != font {
+			font = want
+			b.WriteString(font + "\n")
+		}
+		fmt.Fprintf(&b, "(%s) Tj T*\n", escape(truncate(l.text)))
+	}
+	b.WriteString("ET\n")
+
+	footer := fmt.Sprintf("Page %d of %d", number, total)
+	fmt.Fprintf(&b, "BT\n/F1 %d Tf\n%d %d Td\n(%s) Tj\nET", fontSize, margin, margin-lineHeight, footer)
+	return b.String()
+}
+
+// truncate cuts a line that would run past the right margin
+func truncate(text string) string {
+	if utf8.RuneCountInString(text) <= maxLineChars {
+		return text
+	}
+	return string([]rune(text)[:maxLineChars])
+}
+
+// escape encodes text as the body of a PDF string in WinAnsiEncoding. Characters the encoding
+// shares with Latin-1 are kept; others are replaced by a question mark.
+func escape(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,162 @@
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/lock"
+)
+
+// RedisBackend takes leadership as a distributed lock, renewed while held
+type RedisBackend struct {
+	locker *lock.Locker
+}
+
+// NewRedisBackend creates a backend taking leadership locks with the locker
+func NewRedisBackend(locker *lock.Locker) *RedisBackend {
+	return &RedisBackend{locker: locker}
+}
+
+// TryAcquire takes the lock "leader:<name>"
+func (b *RedisBackend) TryAcquire(ctx context.Context, name string) (Lease, error) {
+	held, err := b.locker.TryAcquire(ctx, "leader:"+name)
+	if errors.Is(err, lock.ErrNotAcquired) {
+		return nil, ErrHeld
+	}
+	if err != nil {
+		return nil, err
+	}
+	return redisLease{held}, nil
+}
+
+// redisLease is a held leadership lock
+type redisLease struct {
+	lock *lock.Lock
+}
+
+func (l redisLease) Done() <-chan struct{} {
+	return l.lock.Context().Done()
+}
+
+func (l redisLease) Release(ctx context.Context) error {
+	return l.lock.Release(ctx)
+}
+
+// PostgresBackend takes leadership as a session-level Postgres advisory lock, held on a
+// connection of its own for as long as that connection lives
+type PostgresBackend struct {
+	db            *sql.DB
+	checkInterval time.Duration
+}
+
+// NewPostgresBackend creates a backend taking advisory locks on the database, checking the
+// connection holding a lock every check interval
+func NewPostgresBackend(db *sql.DB, checkInterval time.Duration) *PostgresBackend {
+	return &PostgresBackend{db: db, checkInterval: checkInterval}
+}
+
+// TryAcquire takes the advisory lock keyed by the hash of the name
+func (b *PostgresBackend) TryAcquire(ctx context.Context, name string) (Lease, error) {
+	conn, err := b.db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leader election connection: %w", err)
+	}
+
+	key := advisoryKey(name)
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to take advisory lock: %w", err)
+	}
+	if !acquired {
+		conn.Close()
+		return nil, ErrHeld
+	}
+
+	lease := &postgresLease{conn: conn, key: key, done: make(chan struct{}), stop: make(chan struct{})}
+	lease.watching.Add(1)
+	go lease.watch(b.checkInterval)
+	return lease, nil
+}
+
+// postgresLease is a held advisory lock. The lock is lost when its connection fails, as Postgres
+// releases the session's locks.
+type postgresLease struct {
+	conn *sql.Conn
+	key  int64
+
+	done     chan struct{} // closed when the lock is lost
+	stop     chan struct{} // closed on release
+	stopOnce sync.Once
+	watching sync.WaitGroup
+}
+
+func (l *postgresLease) Done() <-chan struct{} {
+	return l.done
+}
+
+func (l *postgresLease) Release(ctx context.Context) error {
+	l.stopOnce.Do(func() { close(l.stop) })
+	l.watching.Wait()
+	defer l.conn.Close()
+
+	if _, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		return fmt.Errorf("failed to release advisory lock: %w", err)
+	}
+	return nil
+}
+
+// watch checks the lock's connection every interval until released, closing done when it fails
+func (l *postgresLease) watch(interval time.Duration) {
+	defer l.watching.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		err := l.conn.PingContext(ctx)
+		cancel()
+		if err != nil {
+			close(l.done)
+			return
+		}
+	}
+}
+
+// advisoryKey returns the advisory lock key of an election
+func advisoryKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("leader:" + name))
+	return int64(h.Sum64())
+}
+
+// LocalBackend grants leadership to every instance, for deployments of a single instance
+type LocalBackend struct{}
+
+// TryAcquire always succeeds
+func (LocalBackend) TryAcquire(ctx context.Context, name string) (Lease, error) {
+	return localLease{}, nil
+}
+
+// localLease is never lost
+type localLease struct{}
+
+func (localLease) Done() <-chan struct{} {
+	return nil
+}
+
+func (localLease) Release(ctx context.Context) error {
+	return nil
+}
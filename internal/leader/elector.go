@@ -0,0 +1,221 @@
+// Package leader elects the one instance among the replicas that runs the singleton background
+// workers, such as partition maintenance and the dashboard refresh. Leadership is a lock taken on
+// a backend, Redis or a Postgres advisory lock; the other instances keep trying to take it over,
+// and a leader losing the lock stops its workers.
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/logging"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+)
+
+// ErrHeld is returned by a backend when another instance leads
+var ErrHeld = errors.New("leadership held by another instance")
+
+// Backend takes the leadership lock of an election
+type Backend interface {
+	// TryAcquire takes the named lock if it is free, returning ErrHeld when another instance
+	// holds it
+	TryAcquire(ctx context.Context, name string) (Lease, error)
+}
+
+// Lease is a held leadership lock
+type Lease interface {
+	// Done is closed when the lock is lost
+	Done() <-chan struct{}
+	// Release gives the lock up. It is called once the lock is lost too, to free its resources.
+	Release(ctx context.Context) error
+}
+
+// Worker is a background worker run by the leader only
+type Worker interface {
+	Start(ctx context.Context) error
+	Stop() error
+}
+
+// namedWorker is a worker with the name it is logged by
+type namedWorker struct {
+	name   string
+	worker Worker
+}
+
+// Elector campaigns for the leadership of an election and runs its workers while leading
+type Elector struct {
+	name          string
+	backend       Backend
+	retryInterval time.Duration
+	logger        *logging.Logger
+	transitions   metric.Int64Counter
+
+	workers []namedWorker
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+
+	mu      sync.Mutex
+	leading bool
+	since   time.Time
+}
+
+// NewElector creates an elector for the named election, trying to take over leadership every
+// retry interval while another instance leads. A nil meter disables metrics.
+func NewElector(name string, backend Backend, retryInterval time.Duration, meter metric.Meter, logger *logging.Logger) (*Elector, error) {
+	if meter == nil {
+		meter = metricnoop.NewMeterProvider().Meter("")
+	}
+
+	e := &Elector{
+		name:          name,
+		backend:       backend,
+		retryInterval: retryInterval,
+		logger:        logger.WithComponent("leader").WithField("election", name),
+	}
+
+	var err error
+	e.transitions, err = meter.Int64Counter(
+		"leader_election_transitions_total",
+		metric.WithDescription("Leadership changes of this instance, by the role taken"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	_, err = meter.Int64ObservableGauge(
+		"leader_election_is_leader",
+		metric.WithDescription("Whether this instance leads the election, 1 or 0"),
+		metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+			var value int64
+			if e.IsLeader() {
+				value = 1
+			}
+			o.Observe(value, metric.WithAttributes(attribute.String("election", e.name)))
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Add registers a worker run while leading. Workers start in the order added and stop in reverse.
+func (e *Elector) Add(name string, worker Worker) {
+	e.workers = append(e.workers, namedWorker{name: name, worker: worker})
+}
+
+// Start campaigns for leadership in the background until stopped
+func (e *Elector) Start(ctx context.Context) error {
+	ctx, e.cancel = context.WithCancel(ctx)
+
+	e.wg.Add(1)
+	go e.campaign(ctx)
+
+	e.logger.WithField("workers", len(e.workers)).Info("Leader election started")
+	return nil
+}
+
+// Stop stops campaigning, stopping the workers and giving leadership up if leading
+func (e *Elector) Stop() error {
+	if e.cancel != nil {
+		e.cancel()
+	}
+	e.wg.Wait()
+
+	e.logger.Info("Leader election stopped")
+	return nil
+}
+
+// IsLeader reports whether this instance leads
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leading
+}
+
+// campaign tries to take leadership every retry interval, leading whenever it does
+func (e *Elector) campaign(ctx context.Context) {
+	defer e.wg.Done()
+
+	for {
+		lease, err := e.backend.TryAcquire(ctx, e.name)
+		switch {
+		case err == nil:
+			e.lead(ctx, lease)
+		case !errors.Is(err, ErrHeld) && ctx.Err() == nil:
+			e.logger.WithError(err).Warn("Failed to campaign for leadership")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.retryInterval):
+		}
+	}
+}
+
+// lead runs the workers until leadership is lost or the campaign stops
+func (e *Elector) lead(ctx context.Context, lease Lease) {
+	e.setLeading(ctx, true)
+
+	leaderCtx, cancel := context.WithCancel(ctx)
+	started := make([]namedWorker, 0, len(e.workers))
+	for _, w := range e.workers {
+		if err := w.worker.Start(leaderCtx); err != nil {
+			e.logger.WithError(err).WithField("worker", w.name).Error("Failed to start worker")
+			continue
+		}
+		started = append(started, w)
+	}
+
+	lost := false
+	select {
+	case <-lease.Done():
+		lost = true
+		e.logger.Warn("Leadership lost, stopping workers")
+	case <-ctx.Done():
+	}
+
+	cancel()
+	for i := len(started) - 1; i >= 0; i-- {
+		if err := started[i].worker.Stop(); err != nil {
+			e.logger.WithError(err).WithField("worker", started[i].name).Error("Failed to stop worker")
+		}
+	}
+
+	releaseCtx, releaseCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	if err := lease.Release(releaseCtx); err != nil && !lost {
+		e.logger.WithError(err).Warn("Failed to give leadership up")
+	}
+	releaseCancel()
+	e.setLeading(ctx, false)
+}
+
+// setLeading records and reports a leadership change
+func (e *Elector) setLeading(ctx context.Context, leading bool) {
+	e.mu.Lock()
+	since := e.since
+	e.leading = leading
+	e.since = time.Now()
+	e.mu.Unlock()
+
+	role := "follower"
+	if leading {
+		role = "leader"
+	}
+	e.transitions.Add(context.WithoutCancel(ctx), 1, metric.WithAttributes(
+		attribute.String("election", e.name),
+		attribute.String("role", role),
+	))
+
+	if leading {
+		e.logger.Info("Became leader, starting workers")
+	} else {
+		e.logger.WithField("led_for", time.Since(since).String()).Info("Stepped down as leader")
+	}
+}
@@ -0,0 +1,151 @@
+package loadtest
+
+import (
+	"context"
+	"time"
+)
+
+// Arrivals schedules requests at a constant arrival rate, independent of how long earlier
+// requests take. Each arrival carries the time it was intended to start, so latency measured
+// from it includes any time the request waited for the load generator: the coordinated
+// omission correction.
+type Arrivals struct {
+	phases  []Phase
+	missed  int64
+	overdue *Histogram
+}
+
+// Phase is a part of a run with its own arrival rate in requests per second. Phases run back
+// to back; the last one continues until the run ends.
+type Phase struct {
+	Duration time.Duration `json:"duration"`
+	Rate     float64       `json:"rate_rps"`
+}
+
+// interval returns the time between two arrivals of the phase, 0 when it has none
+func (p Phase) interval() time.Duration {
+	if p.Rate <= 0 {
+		return 0
+	}
+	interval := time.Duration(float64(time.Second) / p.Rate)
+	if interval <= 0 {
+		interval = 1
+	}
+	return interval
+}
+
+// NewArrivals creates a schedule of rate requests per second
+func NewArrivals(rate float64) *Arrivals {
+	return NewPhasedArrivals([]Phase{{Rate: rate}})
+}
+
+// NewPhasedArrivals creates a schedule whose rate changes from phase to phase, e.g. to ramp up
+// to a peak. A phase without a rate sends nothing for its duration.
+func NewPhasedArrivals(phases []Phase) *Arrivals {
+	return &Arrivals{phases: phases, overdue: NewHistogram()}
+}
+
+// Interval returns the time between two arrivals of the first phase
+func (a *Arrivals) Interval() time.Duration {
+	return a.phases[0].interval()
+}
+
+// intended returns the offset from the start of the run at which the n-th arrival is due and
+// the interval of its phase. It reports false when no arrival is ever due from the n-th on.
+func (a *Arrivals) intended(n int64) (time.Duration, time.Duration, bool) {
+	var offset time.Duration
+	for i, phase := range a.phases {
+		interval := phase.interval()
+		last := i == len(a.phases)-1
+		if last {
+			if interval == 0 {
+				return 0, 0, false
+			}
+			return offset + time.Duration(n)*interval, interval, true
+		}
+
+		var count int64
+		if interval > 0 {
+			// Arrivals due before the phase ends, the first at its start
+			count = int64((phase.Duration + interval - 1) / interval)
+		}
+		if n < count {
+			return offset + time.Duration(n)*interval, interval, true
+		}
+		n -= count
+		offset += phase.Duration
+	}
+	return 0, 0, false
+}
+
+// Missed returns how many arrivals were handed out more than one interval after their
+// intended time, because the consumer or the scheduler itself fell behind
+func (a *Arrivals) Missed() int64 {
+	return a.missed
+}
+
+// Overdue returns, for the arrivals that were due but never sent because the run ended while
+// the consumer was behind, how long each had waited
+func (a *Arrivals) Overdue() *Histogram {
+	return a.overdue
+}
+
+// Run sends the intended start time of every arrival to out until ctx is done, then closes
+// out. Intended times are derived from the start rather than the previous tick, so ticks
+// missed while out was full are sent as soon as possible with their original times instead
+// of being dropped or shifting the schedule. Run returns the number of arrivals sent.
+func (a *Arrivals) Run(ctx context.Context, out chan<- time.Time) int64 {
+	defer close(out)
+
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	var sent int64
+	for {
+		offset, interval, ok := a.intended(sent)
+		if !ok {
+			// Nothing is due for the rest of the run
+			<-ctx.Done()
+			return sent
+		}
+		intended := start.Add(offset)
+		if wait := time.Until(intended); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-ctx.Done():
+				return sent
+			case <-timer.C:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			a.recordOverdue(start, sent)
+			return sent
+		case out <- intended:
+		}
+		if time.Since(intended) > interval {
+			a.missed++
+		}
+		sent++
+	}
+}
+
+// recordOverdue records the wait of every arrival from the next-th on whose intended time has passed
+func (a *Arrivals) recordOverdue(start time.Time, next int64) {
+	now := time.Now()
+	for {
+		offset, _, ok := a.intended(next)
+		if !ok {
+			return
+		}
+		intended := start.Add(offset)
+		if intended.After(now) {
+			return
+		}
+		a.overdue.Record(now.Sub(intended))
+		next++
+	}
+}
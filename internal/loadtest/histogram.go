@@ -0,0 +1,180 @@
+package loadtest
+
+import (
+	"math"
+	"math/bits"
+	"time"
+)
+
+// subBucketBits sets the histogram precision: every power of two of microseconds is split
+// into 2^subBucketBits linear sub-buckets, bounding the relative error of a recorded value
+// to 1/128 (under 1%) like an HDR histogram with two significant digits
+const subBucketBits = 7
+
+const subBucketCount = 1 << subBucketBits
+
+// Histogram records latencies in log-linear buckets of microseconds. Values below 128µs are
+// recorded exactly. It is not safe for concurrent use; record per worker and Merge.
+type Histogram struct {
+	counts []int64
+	total  int64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+// Bucket is a non-empty histogram bucket: Count values between From and To inclusive
+type Bucket struct {
+	From  time.Duration `json:"from"`
+	To    time.Duration `json:"to"`
+	Count int64         `json:"count"`
+}
+
+// NewHistogram creates an empty histogram
+func NewHistogram() *Histogram {
+	return &Histogram{}
+}
+
+// Record adds a latency. Negative values are recorded as zero.
+func (h *Histogram) Record(d time.Duration) {
+	h.RecordN(d, 1)
+}
+
+// RecordN adds n occurrences of a latency
+func (h *Histogram) RecordN(d time.Duration, n int64) {
+	if n <= 0 {
+		return
+	}
+	if d < 0 {
+		d = 0
+	}
+
+	i := bucketIndex(d.Microseconds())
+	if i >= len(h.counts) {
+		grown := make([]int64, i+subBucketCount)
+		copy(grown, h.counts)
+		h.counts = grown
+	}
+	h.counts[i] += n
+
+	if h.total == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.total += n
+	h.sum += d * time.Duration(n)
+}
+
+// Merge adds every value recorded in other
+func (h *Histogram) Merge(other *Histogram) {
+	if other.total == 0 {
+		return
+	}
+	if len(other.counts) > len(h.counts) {
+		grown := make([]int64, len(other.counts))
+		copy(grown, h.counts)
+		h.counts = grown
+	}
+	for i, c := range other.counts {
+		h.counts[i] += c
+	}
+
+	if h.total == 0 || other.min < h.min {
+		h.min = other.min
+	}
+	if other.max > h.max {
+		h.max = other.max
+	}
+	h.total += other.total
+	h.sum += other.sum
+}
+
+// Count returns the number of recorded values
+func (h *Histogram) Count() int64 {
+	return h.total
+}
+
+// Min returns the smallest recorded value
+func (h *Histogram) Min() time.Duration {
+	return h.min
+}
+
+// Max returns the largest recorded value
+func (h *Histogram) Max() time.Duration {
+	return h.max
+}
+
+// Mean returns the average of the recorded values
+func (h *Histogram) Mean() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.total)
+}
+
+// Quantile returns the value below which the fraction q of the recorded values fall, as the
+// upper bound of its bucket capped at the largest recorded value
+func (h *Histogram) Quantile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return h.min
+	}
+
+	rank := int64(math.Ceil(q * float64(h.total)))
+	if rank > h.total {
+		rank = h.total
+	}
+
+	var seen int64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= rank {
+			_, to := bucketBounds(i)
+			if to > h.max {
+				return h.max
+			}
+			return to
+		}
+	}
+	return h.max
+}
+
+// Buckets returns the non-empty buckets, lowest first
+func (h *Histogram) Buckets() []Bucket {
+	var buckets []Bucket
+	for i, c := range h.counts {
+		if c == 0 {
+			continue
+		}
+		from, to := bucketBounds(i)
+		buckets = append(buckets, Bucket{From: from, To: to, Count: c})
+	}
+	return buckets
+}
+
+// bucketIndex returns the bucket of a value in microseconds
+func bucketIndex(us int64) int {
+	if us < subBucketCount {
+		return int(us)
+	}
+	shift := bits.Len64(uint64(us)) - subBucketBits - 1
+	mantissa := int(us >> uint(shift)) // in [subBucketCount, 2*subBucketCount)
+	return (shift+1)*subBucketCount + mantissa - subBucketCount
+}
+
+// bucketBounds returns the smallest and largest value of a bucket
+func bucketBounds(i int) (time.Duration, time.Duration) {
+	if i < subBucketCount {
+		d := time.Duration(i) * time.Microsecond
+		return d, d
+	}
+	shift := i/subBucketCount - 1
+	mantissa := int64(i%subBucketCount + subBucketCount)
+	from := mantissa << uint(shift)
+	to := (mantissa+1)<<uint(shift) - 1
+	return time.Duration(from) * time.Microsecond, time.Duration(to) * time.Microsecond
+}
@@ -0,0 +1,242 @@
+package loadtest
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RecordedRequest is one request of a traffic recording. Offset is when the request arrived,
+// relative to the start of the recording, so replays keep the recorded pacing.
+type RecordedRequest struct {
+	OffsetMs    int64           `json:"offset_ms"`
+	Method      string          `json:"method"`
+	Path        string          `json:"path"` // including the query string
+	Route       string          `json:"route,omitempty"`
+	ContentType string          `json:"content_type,omitempty"`
+	Body        json.RawMessage `json:"body,omitempty"`
+	Status      int             `json:"status"`
+	DurationMs  float64         `json:"duration_ms"`
+}
+
+// Offset returns when the request arrived relative to the start of the recording
+func (r *RecordedRequest) Offset() time.Duration {
+	return time.Duration(r.OffsetMs) * time.Millisecond
+}
+
+// RecordingWriter appends recorded requests to a recording file as JSON lines. It is safe for
+// concurrent use.
+type RecordingWriter struct {
+	mu      sync.Mutex
+	file    *os.File
+	buf     *bufio.Writer
+	started time.Time
+	written int64
+}
+
+// CreateRecording creates, or truncates, the recording file at path. Offsets are measured from
+// now.
+func CreateRecording(path string) (*RecordingWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording %s: %w", path, err)
+	}
+	return &RecordingWriter{file: file, buf: bufio.NewWriter(file), started: time.Now()}, nil
+}
+
+// Write records a request that arrived at arrived, setting its offset
+func (w *RecordingWriter) Write(arrived time.Time, req *RecordedRequest) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.file == nil {
+		return os.ErrClosed
+	}
+	req.OffsetMs = arrived.Sub(w.started).Milliseconds()
+	line, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	if _, err := w.buf.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	w.written++
+	return nil
+}
+
+// Written returns the number of requests recorded
+func (w *RecordingWriter) Written() int64 {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.written
+}
+
+// Flush writes the buffered requests to the file
+func (w *RecordingWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	return w.buf.Flush()
+}
+
+// Close flushes and closes the recording file
+func (w *RecordingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.buf.Flush()
+	if closeErr := w.file.Close(); err == nil {
+		err = closeErr
+	}
+	w.file = nil
+	return err
+}
+
+// ReadRecording reads a recording of JSON lines, ordered by offset. Blank lines are skipped.
+func ReadRecording(r io.Reader) ([]*RecordedRequest, error) {
+	var requests []*RecordedRequest
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var req RecordedRequest
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if req.Method == "" || req.Path == "" || req.Path[0] != '/' {
+			return nil, fmt.Errorf("line %d: a recorded request needs a method and a path starting with /", line)
+		}
+		requests = append(requests, &req)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(requests, func(i, j int) bool {
+		return requests[i].OffsetMs < requests[j].OffsetMs
+	})
+	return requests, nil
+}
+
+// ReadRecordingFile reads the recording file at path
+func ReadRecordingFile(path string) ([]*RecordedRequest, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	requests, err := ReadRecording(file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recording %s: %w", path, err)
+	}
+	return requests, nil
+}
+
+// ReplayArrival is a recorded request due for replay, with the time it was intended to start
+type ReplayArrival struct {
+	Intended time.Time
+	Request  *RecordedRequest
+}
+
+// Replay schedules the requests of a recording at their recorded offsets divided by a speed
+// multiplier, so 2 replays twice as fast. Like Arrivals, each arrival carries its intended start
+// for coordinated omission correction.
+type Replay struct {
+	requests []*RecordedRequest
+	speed    float64
+	missed   int64
+	overdue  *Histogram
+}
+
+// NewReplay creates a replay of requests, ordered by offset, at speed times the recorded pace
+func NewReplay(requests []*RecordedRequest, speed float64) *Replay {
+	return &Replay{requests: requests, speed: speed, overdue: NewHistogram()}
+}
+
+// Length returns how long replaying the whole recording takes
+func (p *Replay) Length() time.Duration {
+	if len(p.requests) == 0 {
+		return 0
+	}
+	return p.at(len(p.requests) - 1)
+}
+
+// Missed returns how many arrivals were handed out after the intended time of the next later
+// one, because the consumer or the scheduler fell behind
+func (p *Replay) Missed() int64 {
+	return p.missed
+}
+
+// Overdue returns how long each arrival that was due but never sent, because the run ended while
+// the consumer was behind, had waited
+func (p *Replay) Overdue() *Histogram {
+	return p.overdue
+}
+
+// at returns the replayed offset of the i-th request
+func (p *Replay) at(i int) time.Duration {
+	return time.Duration(float64(p.requests[i].Offset()) / p.speed)
+}
+
+// Run sends every request to out at its replayed offset until the recording ends or ctx is done,
+// then closes out. Run returns the number of arrivals sent.
+func (p *Replay) Run(ctx context.Context, out chan<- ReplayArrival) int64 {
+	defer close(out)
+
+	start := time.Now()
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	var sent int64
+	for i, req := range p.requests {
+		intended := start.Add(p.at(i))
+		if wait := time.Until(intended); wait > 0 {
+			timer.Reset(wait)
+			select {
+			case <-ctx.Done():
+				return sent
+			case <-timer.C:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			p.recordOverdue(start, i)
+			return sent
+		case out <- ReplayArrival{Intended: intended, Request: req}:
+		}
+		if i+1 < len(p.requests) && p.at(i+1) > p.at(i) && time.Now().After(start.Add(p.at(i+1))) {
+			p.missed++
+		}
+		sent++
+	}
+	return sent
+}
+
+// recordOverdue records the wait of every request from the next-th on whose intended time has
+// passed
+func (p *Replay) recordOverdue(start time.Time, next int) {
+	now := time.Now()
+	for ; next < len(p.requests); next++ {
+		intended := start.Add(p.at(next))
+		if intended.After(now) {
+			return
+		}
+		p.overdue.Record(now.Sub(intended))
+	}
+}
@@ -0,0 +1,81 @@
+package loadtest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SLA holds the limits a load test run is asserted against; zero values are not checked
+type SLA struct {
+	P50       time.Duration
+	P95       time.Duration
+	P99       time.Duration
+	ErrorRate float64 // fraction of failed requests, e.g. 0.01 for 1%
+}
+
+// Violation is an SLA limit a run exceeded
+type Violation struct {
+	Metric string `json:"metric"`
+	Limit  string `json:"limit"`
+	Actual string `json:"actual"`
+}
+
+// String describes the violation
+func (v Violation) String() string {
+	return fmt.Sprintf("%s %s exceeds %s", v.Metric, v.Actual, v.Limit)
+}
+
+// Check returns the limits the latencies and error rate exceed
+func (s SLA) Check(latencies *Histogram, errorRate float64) []Violation {
+	var violations []Violation
+	for _, limit := range []struct {
+		metric   string
+		quantile float64
+		max      time.Duration
+	}{
+		{"p50", 0.50, s.P50},
+		{"p95", 0.95, s.P95},
+		{"p99", 0.99, s.P99},
+	} {
+		if limit.max <= 0 {
+			continue
+		}
+		if actual := latencies.Quantile(limit.quantile); actual > limit.max {
+			violations = append(violations, Violation{Metric: limit.metric, Limit: limit.max.String(), Actual: actual.String()})
+		}
+	}
+
+	if s.ErrorRate > 0 && errorRate > s.ErrorRate {
+		violations = append(violations, Violation{
+			Metric: "error rate",
+			Limit:  FormatPercent(s.ErrorRate),
+			Actual: FormatPercent(errorRate),
+		})
+	}
+	return violations
+}
+
+// ParsePercent parses a percentage such as "1%" or "0.5%" into a fraction. A value without
+// the percent sign is read as a fraction, so "0.01" is also 1%.
+func ParsePercent(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	percent := strings.HasSuffix(value, "%")
+	f, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage %q", value)
+	}
+	if percent {
+		f /= 100
+	}
+	if f < 0 || f > 1 {
+		return 0, fmt.Errorf("percentage %q must be between 0%% and 100%%", value)
+	}
+	return f, nil
+}
+
+// FormatPercent formats a fraction as a percentage
+func FormatPercent(f float64) string {
+	return fmt.Sprintf("%.2f%%", f*100)
+}
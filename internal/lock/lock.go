@@ -0,0 +1,134 @@
+package lock
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Lock is a lock held by this instance. It is renewed every third of the TTL until released. A
+// failed renewal is retried with backoff; only once the lock expired without being renewed, or
+// another owner took it over, is it lost and its context cancelled.
+type Lock struct {
+	name       string
+	key        string
+	token      string
+	locker     *Locker
+	acquiredAt time.Time
+
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	done   chan struct{} // closed when renewal stops
+
+	mu        sync.Mutex
+	expiresAt time.Time
+}
+
+// Name returns the lock's name
+func (lk *Lock) Name() string {
+	return lk.name
+}
+
+// Context returns a context done once the lock is released or lost. Its cause is ErrLockLost when
+// the lock was lost.
+func (lk *Lock) Context() context.Context {
+	return lk.ctx
+}
+
+// Release stops renewing the lock and deletes it, returning ErrLockLost if it had been lost
+func (lk *Lock) Release(ctx context.Context) error {
+	lk.cancel(context.Canceled)
+	<-lk.done
+	if context.Cause(lk.ctx) == ErrLockLost {
+		return ErrLockLost
+	}
+
+	lk.locker.forget(lk)
+	return lk.locker.release(ctx, lk.key, lk.token)
+}
+
+// renew extends the lock until it is released, or is lost when it expires before an extension
+// succeeds
+func (lk *Lock) renew() {
+	defer close(lk.done)
+
+	l := lk.locker
+	interval := l.cfg.TTL / 3
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	backoff := l.cfg.RetryInterval
+	for {
+		select {
+		case <-lk.ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		ctx, cancel := context.WithTimeout(lk.ctx, interval)
+		renewed, gone := l.extend(ctx, lk)
+		cancel()
+		if lk.ctx.Err() != nil {
+			return
+		}
+
+		if renewed {
+			l.renewals.Add(lk.ctx, 1, metric.WithAttributes(l.attributes(lk.name, attribute.String("outcome", "renewed"))...))
+			backoff = l.cfg.RetryInterval
+			timer.Reset(interval)
+			continue
+		}
+
+		// Unreachable nodes are tried again, ever less often, while the lock is still valid
+		remaining := lk.expiry().Sub(l.now())
+		if !gone && remaining > 0 {
+			l.renewals.Add(lk.ctx, 1, metric.WithAttributes(l.attributes(lk.name, attribute.String("outcome", "retried"))...))
+			l.logger.WithField("lock", lk.name).Debug("Failed to renew lock, retrying")
+			timer.Reset(min(backoff, remaining))
+			backoff = min(2*backoff, interval)
+			continue
+		}
+
+		l.renewals.Add(lk.ctx, 1, metric.WithAttributes(l.attributes(lk.name, attribute.String("outcome", "lost"))...))
+		if gone {
+			l.logger.WithField("lock", lk.name).Warn("Lock lost, it is no longer held on a majority of nodes")
+		} else {
+			l.logger.WithField("lock", lk.name).Warn("Lock lost, failed to renew it before it expired")
+		}
+		lk.cancel(ErrLockLost)
+		l.forget(lk)
+		return
+	}
+}
+
+// expiry returns when the lock expires unless renewed again
+func (lk *Lock) expiry() time.Time {
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+	return lk.expiresAt
+}
+
+// setExpiresAt records when the lock expires unless renewed again
+func (lk *Lock) setExpiresAt(expiresAt time.Time) {
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+	lk.expiresAt = expiresAt
+}
+
+// info describes the lock
+func (lk *Lock) info() LockInfo {
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+
+	acquiredAt := lk.acquiredAt
+	return LockInfo{
+		Name:       lk.name,
+		Owner:      lk.locker.owner,
+		ExpiresAt:  lk.expiresAt,
+		AcquiredAt: &acquiredAt,
+		Local:      true,
+	}
+}
@@ -0,0 +1,467 @@
+// Package lock coordinates work across instances, such as reservations, schedulers and retention
+// jobs, with Redis locks. A lock is taken Redlock-style on a majority of independent Redis nodes
+// (a single node works too), expires unless its holder keeps renewing it, and is released only by
+// its holder. Acquisition latency, contention and renewals are reported as metrics.
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	mathrand "math/rand"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+)
+
+// ErrNotAcquired is returned when the lock is held by another owner
+var ErrNotAcquired = errors.New("lock held by another owner")
+
+// ErrLockLost is the cause of a lock's context when the lock could not be renewed before it
+// expired, so another owner may hold it now
+var ErrLockLost = errors.New("lock lost")
+
+// releaseScript deletes the key only while it holds the caller's token
+const releaseScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("del", KEYS[1]) else return 0 end`
+
+// extendScript resets the key's expiry only while it holds the caller's token
+const extendScript = `if redis.call("get", KEYS[1]) == ARGV[1] then return redis.call("pexpire", KEYS[1], ARGV[2]) else return 0 end`
+
+// clockDriftFactor is the share of the TTL the clocks of the nodes are assumed to drift by
+const clockDriftFactor = 0.01
+
+// LockInfo describes a held lock
+type LockInfo struct {
+	Name       string     `json:"name"`
+	Owner      string     `json:"owner"`                 // instance holding the lock
+	ExpiresAt  time.Time  `json:"expires_at"`            // unless renewed
+	AcquiredAt *time.Time `json:"acquired_at,omitempty"` // known for locks held by this instance only
+	Local      bool       `json:"local"`                 // held by this instance
+}
+
+// Locker acquires locks on behalf of this instance
+type Locker struct {
+	nodes  []redis.Cmdable
+	cfg    config.LockConfig
+	owner  string
+	logger *logging.Logger
+	now    func() time.Time
+
+	acquireDuration metric.Float64Histogram
+	contention      metric.Int64Counter
+	renewals        metric.Int64Counter
+	held            metric.Int64UpDownCounter
+
+	mu    sync.Mutex
+	locks map[string]*Lock
+}
+
+// NewLocker creates a locker taking locks on the nodes, which should be independent Redis
+// servers, not replicas of each other. A nil meter disables metrics.
+func NewLocker(nodes []redis.Cmdable, cfg config.LockConfig, meter metric.Meter, logger *logging.Logger) (*Locker, error) {
+	if len(nodes) == 0 {
+		return nil, fmt.Errorf("locks need at least one Redis node")
+	}
+	if meter == nil {
+		meter = metricnoop.NewMeterProvider().Meter("")
+	}
+
+	acquireDuration, err := meter.Float64Histogram(
+		"lock_acquire_duration_seconds",
+		metric.WithDescription("Time taken to acquire a lock, or to give up on it"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	contention, err := meter.Int64Counter(
+		"lock_contention_total",
+		metric.WithDescription("Attempts to acquire a lock finding it held by another owner"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	renewals, err := meter.Int64Counter(
+		"lock_renewals_total",
+		metric.WithDescription("Renewals of held locks, by outcome"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	held, err := meter.Int64UpDownCounter(
+		"locks_held",
+		metric.WithDescription("Locks held by this instance"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	hostname, _ := os.Hostname()
+	return &Locker{
+		nodes:           nodes,
+		cfg:             cfg,
+		owner:           fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+		logger:          logger.WithComponent("lock"),
+		now:             time.Now,
+		acquireDuration: acquireDuration,
+		contention:      contention,
+		renewals:        renewals,
+		held:            held,
+		locks:           make(map[string]*Lock),
+	}, nil
+}
+
+// Owner returns the name this instance holds locks under
+func (l *Locker) Owner() string {
+	return l.owner
+}
+
+// TryAcquire takes the named lock if it is free, returning ErrNotAcquired when another owner
+// holds it. Lock names are "kind" or "kind:id", e.g. "retention" or "driver:42"; metrics are
+// reported by kind.
+func (l *Locker) TryAcquire(ctx context.Context, name string) (*Lock, error) {
+	start := l.now()
+	lock, err := l.try(ctx, name)
+	l.acquired(ctx, name, start, err)
+	return lock, err
+}
+
+// Acquire takes the named lock, waiting for it while another owner holds it until the acquire
+// timeout passes, which returns ErrNotAcquired, or ctx is done
+func (l *Locker) Acquire(ctx context.Context, name string) (*Lock, error) {
+	start := l.now()
+	waitCtx := ctx
+	if l.cfg.AcquireTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, l.cfg.AcquireTimeout)
+		defer cancel()
+	}
+
+	for {
+		lock, err := l.try(waitCtx, name)
+		if !errors.Is(err, ErrNotAcquired) {
+			if err != nil && ctx.Err() == nil && waitCtx.Err() != nil {
+				err = ErrNotAcquired
+			}
+			l.acquired(ctx, name, start, err)
+			return lock, err
+		}
+
+		// Retry after a random part of the interval so that waiting owners do not retry in step
+		delay := l.cfg.RetryInterval/2 + time.Duration(mathrand.Int63n(int64(l.cfg.RetryInterval/2)+1))
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-waitCtx.Done():
+			timer.Stop()
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			l.acquired(ctx, name, start, err)
+			return nil, err
+		}
+	}
+}
+
+// WithLock runs fn holding the named lock, acquired as by Acquire. The context passed to fn is
+// cancelled if the lock is lost, whose cause is then ErrLockLost.
+func (l *Locker) WithLock(ctx context.Context, name string, fn func(ctx context.Context) error) error {
+	lock, err := l.Acquire(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer lock.Release(context.WithoutCancel(ctx))
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stop := context.AfterFunc(lock.Context(), func() {
+		cancel(context.Cause(lock.Context()))
+	})
+	defer stop()
+
+	return fn(runCtx)
+}
+
+// Held returns the locks this instance holds, by name
+func (l *Locker) Held() []LockInfo {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	infos := make([]LockInfo, 0, len(l.locks))
+	for _, lock := range l.locks {
+		infos = append(infos, lock.info())
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// List returns the locks held by any instance, by name. A lock counts as held when a majority of
+// the nodes have it.
+func (l *Locker) List(ctx context.Context) ([]LockInfo, error) {
+	type entry struct {
+		nodes     int
+		expiresAt time.Time
+	}
+	entries := make(map[[2]string]*entry) // by key and token
+
+	now := l.now()
+	for _, node := range l.nodes {
+		keys, err := scanKeys(ctx, node, l.cfg.KeyPrefix+"*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list locks: %w", err)
+		}
+		for _, key := range keys {
+			token, err := node.Get(ctx, key).Result()
+			if err != nil {
+				continue // released or expired since the scan
+			}
+			ttl, err := node.PTTL(ctx, key).Result()
+			if err != nil || ttl <= 0 {
+				continue
+			}
+
+			id := [2]string{key, token}
+			e, ok := entries[id]
+			if !ok {
+				e = &entry{expiresAt: now.Add(ttl)}
+				entries[id] = e
+			}
+			e.nodes++
+			if expiresAt := now.Add(ttl); expiresAt.Before(e.expiresAt) {
+				e.expiresAt = expiresAt
+			}
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	infos := make([]LockInfo, 0, len(entries))
+	for id, e := range entries {
+		if e.nodes < l.quorum() {
+			continue
+		}
+		name, token := strings.TrimPrefix(id[0], l.cfg.KeyPrefix), id[1]
+		info := LockInfo{Name: name, Owner: ownerOf(token), ExpiresAt: e.expiresAt}
+		if lock, ok := l.locks[name]; ok && lock.token == token {
+			info = lock.info()
+		}
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// Close releases every lock this instance holds
+func (l *Locker) Close(ctx context.Context) error {
+	l.mu.Lock()
+	locks := make([]*Lock, 0, len(l.locks))
+	for _, lock := range l.locks {
+		locks = append(locks, lock)
+	}
+	l.mu.Unlock()
+
+	var errs []error
+	for _, lock := range locks {
+		if err := lock.Release(ctx); err != nil && !errors.Is(err, ErrLockLost) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// try sets the lock's key on every node, and holds the lock when a majority of the nodes were set
+// well within the TTL. Otherwise the keys set are deleted again.
+func (l *Locker) try(ctx context.Context, name string) (*Lock, error) {
+	key := l.cfg.KeyPrefix + name
+	token, err := l.newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	start := l.now()
+	set, failed := 0, 0
+	var lastErr error
+	for _, node := range l.nodes {
+		ok, err := node.SetNX(ctx, key, token, l.cfg.TTL).Result()
+		switch {
+		case err != nil:
+			failed++
+			lastErr = err
+		case ok:
+			set++
+		}
+	}
+
+	validity := l.validity(start)
+	if set >= l.quorum() && validity > 0 {
+		return l.hold(name, key, token, start.Add(validity)), nil
+	}
+
+	l.release(context.WithoutCancel(ctx), key, token)
+	if failed > len(l.nodes)-l.quorum() {
+		return nil, fmt.Errorf("failed to reach a majority of lock nodes: %w", lastErr)
+	}
+	return nil, ErrNotAcquired
+}
+
+// extend renews the lock's key on every node, returning whether a majority were renewed well
+// within the TTL, and whether the lock is gone for good because too few nodes still hold its
+// token to ever renew a majority again. Unreachable nodes may answer the next time.
+func (l *Locker) extend(ctx context.Context, lock *Lock) (extended bool, gone bool) {
+	start := l.now()
+	renewed, missing := 0, 0
+	for _, node := range l.nodes {
+		n, err := node.Eval(ctx, extendScript, []string{lock.key}, lock.token, l.cfg.TTL.Milliseconds()).Int64()
+		switch {
+		case err != nil:
+		case n == 1:
+			renewed++
+		default:
+			missing++
+		}
+	}
+	if len(l.nodes)-missing < l.quorum() {
+		return false, true
+	}
+
+	validity := l.validity(start)
+	if renewed < l.quorum() || validity <= 0 {
+		return false, false
+	}
+	lock.setExpiresAt(start.Add(validity))
+	return true, false
+}
+
+// release deletes the lock's key from every node it still holds the token on, returning an error
+// only when no node could be reached
+func (l *Locker) release(ctx context.Context, key, token string) error {
+	var errs []error
+	for _, node := range l.nodes {
+		if err := node.Eval(ctx, releaseScript, []string{key}, token).Err(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == len(l.nodes) {
+		return fmt.Errorf("failed to release lock %s: %w", key, errors.Join(errs...))
+	}
+	return nil
+}
+
+// hold registers an acquired lock and starts renewing it
+func (l *Locker) hold(name, key, token string, expiresAt time.Time) *Lock {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	lock := &Lock{
+		name:       name,
+		key:        key,
+		token:      token,
+		locker:     l,
+		acquiredAt: l.now(),
+		expiresAt:  expiresAt,
+		ctx:        ctx,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+
+	l.mu.Lock()
+	l.locks[name] = lock
+	l.mu.Unlock()
+	l.held.Add(ctx, 1, metric.WithAttributes(l.attributes(name)...))
+
+	go lock.renew()
+	return lock
+}
+
+// forget unregisters a lock that was released or lost
+func (l *Locker) forget(lock *Lock) {
+	l.mu.Lock()
+	current, ok := l.locks[lock.name]
+	if ok && current == lock {
+		delete(l.locks, lock.name)
+	}
+	l.mu.Unlock()
+
+	if ok && current == lock {
+		l.held.Add(context.Background(), -1, metric.WithAttributes(l.attributes(lock.name)...))
+	}
+}
+
+// acquired reports the outcome of acquiring a lock
+func (l *Locker) acquired(ctx context.Context, name string, start time.Time, err error) {
+	outcome := "acquired"
+	switch {
+	case errors.Is(err, ErrNotAcquired):
+		outcome = "contended"
+		l.contention.Add(ctx, 1, metric.WithAttributes(l.attributes(name)...))
+	case err != nil:
+		outcome = "error"
+		l.logger.WithError(err).WithField("lock", name).Warn("Failed to acquire lock")
+	}
+	l.acquireDuration.Record(ctx, l.now().Sub(start).Seconds(), metric.WithAttributes(
+		l.attributes(name, attribute.String("outcome", outcome))...,
+	))
+}
+
+// attributes are the metric attributes of a lock, named by its kind to keep their cardinality low
+func (l *Locker) attributes(name string, extra ...attribute.KeyValue) []attribute.KeyValue {
+	kind, _, _ := strings.Cut(name, ":")
+	return append([]attribute.KeyValue{attribute.String("lock", kind)}, extra...)
+}
+
+// quorum is the number of nodes a lock must be held on
+func (l *Locker) quorum() int {
+	return len(l.nodes)/2 + 1
+}
+
+// validity returns how long a lock set on the nodes since start is safe to rely on, allowing for
+// the time setting it took and the drift of the nodes' clocks
+func (l *Locker) validity(start time.Time) time.Duration {
+	drift := time.Duration(float64(l.cfg.TTL)*clockDriftFactor) + 2*time.Millisecond
+	return l.cfg.TTL - l.now().Sub(start) - drift
+}
+
+// newToken returns a token unique to one acquisition, telling its owner
+func (l *Locker) newToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	return l.owner + "/" + hex.EncodeToString(b), nil
+}
+
+// ownerOf returns the owner a token was generated by
+func ownerOf(token string) string {
+	if i := strings.LastIndex(token, "/"); i >= 0 {
+		return token[:i]
+	}
+	return token
+}
+
+// scanKeys returns the keys of a node matching the pattern
+func scanKeys(ctx context.Context, node redis.Cmdable, pattern string) ([]string, error) {
+	var keys []string
+	var cursor uint64
+	for {
+		batch, next, err := node.Scan(ctx, cursor, pattern, 100).Result()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, batch...)
+		if next == 0 {
+			return keys, nil
+		}
+		cursor = next
+	}
+}
@@ -15,6 +15,7 @@ import (
 type Logger struct {
 	*slog.Logger
 	config *config.LoggingConfig
+	level  *slog.LevelVar // shared by all derived loggers so level changes apply everywhere
 }
 
 // Fields type for structured logging
@@ -48,8 +49,10 @@ func NewLogger(cfg *config.LoggingConfig) (*Logger, error) {
 
 	// Configure handler options
 	var handler slog.Handler
+	level := new(slog.LevelVar)
+	level.Set(parseLevel(cfg.Level))
 	handlerOpts := &slog.HandlerOptions{
-		Level:     parseLevel(cfg.Level),
+		Level:     level,
 		AddSource: cfg.Level == "debug", // Only add source info for debug level
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
 			// Customize attribute names to match previous format
@@ -80,9 +83,17 @@ func NewLogger(cfg *config.LoggingConfig) (*Logger, error) {
 	return &Logger{
 		Logger: logger,
 		config: cfg,
+		level:  level,
 	}, nil
 }
 
+// SetLevel changes the minimum log level at runtime for this logger and all loggers derived from it
+func (l *Logger) SetLevel(level string) {
+	if l.level != nil {
+		l.level.Set(parseLevel(level))
+	}
+}
+
 // parseLevel converts string level to slog.Level
 func parseLevel(level string) slog.Level {
 	switch level {
@@ -122,19 +133,19 @@ func (l *Logger) WithFields(fields Fields) *Logger {
 		args[i] = attr
 	}
 	logger := l.Logger.With(args...)
-	return &Logger{Logger: logger, config: l.config}
+	return &Logger{Logger: logger, config: l.config, level: l.level}
 }
 
 // WithField creates a new logger with a single field
 func (l *Logger) WithField(key string, value interface{}) *Logger {
 	logger := l.Logger.With(slog.Any(key, value))
-	return &Logger{Logger: logger, config: l.config}
+	return &Logger{Logger: logger, config: l.config, level: l.level}
 }
 
 // WithError creates a new logger with an error field
 func (l *Logger) WithError(err error) *Logger {
 	logger := l.Logger.With(slog.Any("error", err))
-	return &Logger{Logger: logger, config: l.config}
+	return &Logger{Logger: logger, config: l.config, level: l.level}
 }
 
 // WithComponent creates a new logger with a component field
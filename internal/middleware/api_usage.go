@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"actor-model-observability/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Quota headers, set on the responses to requests made with a key that has a daily quota
+const (
+	QuotaLimitHeader     = "X-Quota-Limit"
+	QuotaRemainingHeader = "X-Quota-Remaining"
+	QuotaResetHeader     = "X-Quota-Reset" // Unix time the quota resets at
+)
+
+// APIUsageTracker records the requests made with API keys and enforces their daily quotas;
+// service.APIUsageService satisfies it
+type APIUsageTracker interface {
+	// CheckQuota returns how much of its daily quota a key has used before a request is made
+	CheckQuota(ctx context.Context, key models.APIKeyRef) models.APIQuotaStatus
+	// Record counts a request answered with status after latency, rejected for the quota or not
+	Record(key models.APIKeyRef, status int, latency time.Duration, rejected bool)
+}
+
+// APIKeyResolver returns the API key a request was authenticated with, false when it was not
+type APIKeyResolver func(c *gin.Context) (models.APIKeyRef, bool)
+
+// APIUsageMiddleware records the requests made with an API key, with their status and latency,
+// and rejects them with 429 once the key's daily quota is used up. It must run after the
+// middleware authenticating the key, which resolve reads; other requests pass through.
+func APIUsageMiddleware(tracker APIUsageTracker, resolve APIKeyResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key, ok := resolve(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		quota := tracker.CheckQuota(c.Request.Context(), key)
+		if quota.Limit > 0 {
+			c.Header(QuotaLimitHeader, strconv.FormatInt(quota.Limit, 10))
+			c.Header(QuotaResetHeader, strconv.FormatInt(quota.ResetAt.Unix(), 10))
+		}
+
+		if quota.Exceeded() {
+			c.Header(QuotaRemainingHeader, "0")
+			c.Header("Retry-After", strconv.Itoa(int(time.Until(quota.ResetAt).Seconds())+1))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{
+				"error":   "Quota exceeded",
+				"message": "The daily request quota of the API key is used up",
+				"code":    models.ErrorCodeQuotaExceeded,
+			})
+			tracker.Record(key, http.StatusTooManyRequests, time.Since(start), true)
+			return
+		}
+
+		if quota.Limit > 0 {
+			// Remaining counts this request as made
+			c.Header(QuotaRemainingHeader, strconv.FormatInt(quota.Remaining()-1, 10))
+		}
+		c.Next()
+		tracker.Record(key, c.Writer.Status(), time.Since(start), false)
+	}
+}
+
+// FleetAPIKey resolves the fleet API key authenticated by FleetAuthMiddleware
+func FleetAPIKey(c *gin.Context) (models.APIKeyRef, bool) {
+	fleet, ok := FleetFromContext(c)
+	if !ok {
+		return models.APIKeyRef{}, false
+	}
+	return fleet.APIKey(), true
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+
+	"actor-model-observability/internal/appversion"
+	"actor-model-observability/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// appClientContextKey is the gin context key holding the app a request was made with
+const appClientContextKey = "app_client"
+
+// AppVersionMiddleware gates the driver and passenger apps by the version they report in the
+// X-App-Platform and X-App-Version headers. Versions below their platform's minimum are rejected
+// with 426 Upgrade Required and the minimum in the X-App-Min-Version header; invalid versions are
+// rejected with 400. Requests that report no version, such as those of other API clients, pass.
+// The app is stored in the context and its request counted in the gate's usage.
+func AppVersionMiddleware(gate *appversion.Gate) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client, ok, err := appversion.ParseClient(c.GetHeader(appversion.PlatformHeader), c.GetHeader(appversion.VersionHeader))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid app version",
+				"message": err.Error(),
+				"code":    models.ErrorCodeValidationFailed,
+			})
+			return
+		}
+		if !ok {
+			c.Next()
+			return
+		}
+
+		if gate.UpgradeRequired(client) {
+			gate.Record(c.Request.Context(), client, true)
+			min, _ := gate.MinVersion(client.Platform)
+			c.Header(appversion.MinVersionHeader, min.String())
+			c.AbortWithStatusJSON(http.StatusUpgradeRequired, gin.H{
+				"error":       "Upgrade required",
+				"message":     models.ErrAppUpgradeRequired.Error() + "; update the app to " + min.String() + " or later",
+				"code":        models.ErrorCodeAppUpgradeRequired,
+				"platform":    client.Platform,
+				"version":     client.Version.String(),
+				"min_version": min.String(),
+			})
+			return
+		}
+
+		c.Set(appClientContextKey, client)
+		gate.Record(c.Request.Context(), client, false)
+		c.Next()
+	}
+}
+
+// AppClientFromContext returns the app stored by AppVersionMiddleware; ok is false when the
+// request reported no version
+func AppClientFromContext(c *gin.Context) (appversion.Client, bool) {
+	value, ok := c.Get(appClientContextKey)
+	if !ok {
+		return appversion.Client{}, false
+	}
+	client, ok := value.(appversion.Client)
+	return client, ok
+}
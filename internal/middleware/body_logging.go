@@ -0,0 +1,285 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"mime"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/redaction"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Reasons a captured body is left out of its body log
+const (
+	bodyOmittedTooLarge = "too_large"
+	bodyOmittedNotJSON  = "not_json"
+)
+
+// BodyLogRecorder stores the body logs; repository.TraditionalRepository satisfies it
+type BodyLogRecorder interface {
+	CreateTraditionalLog(ctx context.Context, log *models.TraditionalLog) error
+}
+
+// BodyLogRoute is a route switched on or off for body logging at runtime
+type BodyLogRoute struct {
+	Route   string `json:"route"`
+	Enabled bool   `json:"enabled"`
+}
+
+// BodyLoggingSettings are the body logging settings in effect
+type BodyLoggingSettings struct {
+	Enabled     bool           `json:"enabled"`
+	SampleRate  float64        `json:"sample_rate"`
+	MaxBodySize int            `json:"max_body_size"`
+	Routes      []string       `json:"routes"`    // configured routes, empty for every route
+	Overrides   []BodyLogRoute `json:"overrides"` // routes switched at runtime, taking precedence over the configuration
+}
+
+// BodyLogger captures the request and response bodies of a sample of the requests to the logged
+// routes and stores them, redacted, as traditional logs tagged with the request ID. Only JSON
+// bodies up to the configured size are kept. Routes are switched on or off at runtime with
+// SetRoute, which survives configuration reloads.
+type BodyLogger struct {
+	mu        sync.RWMutex
+	cfg       config.BodyLoggingConfig
+	overrides map[string]bool
+
+	recorder BodyLogRecorder
+	redactor *redaction.Redactor
+	service  string
+	instance string
+	logger   *logging.Logger
+	sample   func() float64
+}
+
+// NewBodyLogger creates a body logger storing to recorder. Bodies are redacted by redactor,
+// which should not be nil outside of tests.
+func NewBodyLogger(cfg config.BodyLoggingConfig, recorder BodyLogRecorder, redactor *redaction.Redactor, serviceName string, logger *logging.Logger) *BodyLogger {
+	instance, _ := os.Hostname()
+	return &BodyLogger{
+		cfg:       cfg,
+		overrides: make(map[string]bool),
+		recorder:  recorder,
+		redactor:  redactor,
+		service:   serviceName,
+		instance:  instance,
+		logger:    logger.WithComponent("body_logger"),
+		sample:    rand.Float64,
+	}
+}
+
+// SetConfig replaces the configured settings, e.g. after a configuration reload. Routes switched
+// at runtime keep their override.
+func (l *BodyLogger) SetConfig(cfg config.BodyLoggingConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.cfg = cfg
+}
+
+// SetRoute switches body logging on or off for a route template, e.g. /api/v1/rides/:id,
+// regardless of the configuration
+func (l *BodyLogger) SetRoute(route string, enabled bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.overrides[route] = enabled
+}
+
+// ResetRoute drops the runtime override of a route, so the configuration applies to it again
+func (l *BodyLogger) ResetRoute(route string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.overrides, route)
+}
+
+// Settings returns the settings in effect, overrides ordered by route
+func (l *BodyLogger) Settings() BodyLoggingSettings {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	settings := BodyLoggingSettings{
+		Enabled:     l.cfg.Enabled,
+		SampleRate:  l.cfg.SampleRate,
+		MaxBodySize: l.cfg.MaxBodySize,
+		Routes:      append([]string{}, l.cfg.Routes...),
+		Overrides:   make([]BodyLogRoute, 0, len(l.overrides)),
+	}
+	for route, enabled := range l.overrides {
+		settings.Overrides = append(settings.Overrides, BodyLogRoute{Route: route, Enabled: enabled})
+	}
+	sort.Slice(settings.Overrides, func(i, j int) bool {
+		return settings.Overrides[i].Route < settings.Overrides[j].Route
+	})
+	return settings
+}
+
+// sampled decides whether to capture the bodies of a request to route and returns the largest
+// body size kept
+func (l *BodyLogger) sampled(route string) (bool, int) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	enabled, overridden := l.overrides[route]
+	if !overridden {
+		enabled = l.cfg.Enabled && (len(l.cfg.Routes) == 0 || containsString(l.cfg.Routes, route))
+	}
+	return enabled && l.sample() < l.cfg.SampleRate, l.cfg.MaxBodySize
+}
+
+// Middleware returns the gin handler capturing the bodies. It should run after response
+// compression so that it sees the uncompressed response.
+func (l *BodyLogger) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		capture, maxSize := l.sampled(route)
+		if route == "" || !capture {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		requestBody := captureRequestBody(c.Request, maxSize)
+		writer := &bodyCaptureWriter{ResponseWriter: c.Writer, limit: maxSize}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		requestSize := len(requestBody)
+		if c.Request.ContentLength > int64(requestSize) {
+			requestSize = int(c.Request.ContentLength)
+		}
+		responseSize := writer.Size()
+		if responseSize < 0 {
+			responseSize = 0
+		}
+
+		fields, _ := json.Marshal(map[string]interface{}{
+			"request_id":  c.GetString("request_id"),
+			"method":      c.Request.Method,
+			"route":       route,
+			"path":        c.Request.URL.Path,
+			"status":      writer.Status(),
+			"duration_ms": time.Since(start).Milliseconds(),
+			"request":     l.loggedBody(c.Request.Header.Get("Content-Type"), requestBody, requestSize, maxSize),
+			"response":    l.loggedBody(writer.Header().Get("Content-Type"), writer.body.Bytes(), responseSize, maxSize),
+		})
+		log := &models.TraditionalLog{
+			ID:          uuid.New(),
+			Level:       models.LogLevelInfo,
+			Message:     fmt.Sprintf("HTTP body sample: %s %s %d", c.Request.Method, route, writer.Status()),
+			ServiceName: l.service,
+			InstanceID:  l.instance,
+			Fields:      fields,
+			Timestamp:   start,
+			CreatedAt:   time.Now(),
+		}
+		if err := l.recorder.CreateTraditionalLog(context.WithoutCancel(c.Request.Context()), log); err != nil {
+			l.logger.WithError(err).WithField("route", route).Warn("Failed to store body log")
+		}
+	}
+}
+
+// loggedBody is a captured body as stored in a body log
+type loggedBody struct {
+	Size    int             `json:"size"`
+	Body    json.RawMessage `json:"body,omitempty"`
+	Omitted string          `json:"omitted,omitempty"` // why the body is left out
+}
+
+// loggedBody redacts a captured body of size bytes, leaving it out unless it is JSON of at most
+// maxSize bytes
+func (l *BodyLogger) loggedBody(contentType string, captured []byte, size, maxSize int) loggedBody {
+	logged := loggedBody{Size: size}
+	switch {
+	case size == 0:
+	case size > maxSize:
+		logged.Omitted = bodyOmittedTooLarge
+	case !isJSONContentType(contentType) || !json.Valid(captured):
+		logged.Omitted = bodyOmittedNotJSON
+	default:
+		logged.Body = l.redactor.JSON(captured)
+	}
+	return logged
+}
+
+// captureRequestBody reads up to limit+1 bytes of the request body, the extra byte telling a
+// body of exactly limit bytes from a larger one, and puts them back for the handlers
+func captureRequestBody(r *http.Request, limit int) []byte {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil
+	}
+
+	captured, err := io.ReadAll(io.LimitReader(r.Body, int64(limit)+1))
+	r.Body = &replayedBody{Reader: io.MultiReader(bytes.NewReader(captured), r.Body), Closer: r.Body}
+	if err != nil {
+		return nil
+	}
+	return captured
+}
+
+// replayedBody is a request body whose start was read ahead
+type replayedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyCaptureWriter keeps up to limit+1 bytes of the response body while writing it through
+type bodyCaptureWriter struct {
+	gin.ResponseWriter
+	limit int
+	body  bytes.Buffer
+}
+
+func (w *bodyCaptureWriter) Write(data []byte) (int, error) {
+	w.capture(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyCaptureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyCaptureWriter) capture(data []byte) {
+	if room := w.limit + 1 - w.body.Len(); room > 0 {
+		if len(data) > room {
+			data = data[:room]
+		}
+		w.body.Write(data)
+	}
+}
+
+// isJSONContentType reports whether a Content-Type header is JSON, e.g. application/json or
+// application/problem+json
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
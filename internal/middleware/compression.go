@@ -0,0 +1,202 @@
+package middleware
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CompressionMiddleware creates a middleware that gzips responses for clients accepting it.
+// Responses smaller than minSize bytes, already encoded responses and requests whose path
+// contains one of excludedPaths (e.g. Prometheus scrape endpoints) are sent uncompressed.
+func CompressionMiddleware(level, minSize int, excludedPaths []string) gin.HandlerFunc {
+	pool := &sync.Pool{
+		New: func() interface{} {
+			w, _ := gzip.NewWriterLevel(nil, level)
+			return w
+		},
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+		for _, path := range excludedPaths {
+			if strings.Contains(c.Request.URL.Path, path) {
+				c.Next()
+				return
+			}
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: c.Writer, pool: pool, minSize: minSize}
+		c.Writer = writer
+		defer func() {
+			writer.close()
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Header("Vary", "Accept-Encoding")
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows a gzip response
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+
+		q := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// gzipResponseWriter buffers the start of a response until minSize bytes are written, then
+// either switches to gzip or passes the body through unchanged
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	pool    *sync.Pool
+	minSize int
+
+	buf     bytes.Buffer
+	decided bool
+	gz      *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		w.buf.Write(data)
+		if w.buf.Len() < w.minSize {
+			return len(data), nil
+		}
+		if err := w.decide(); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+
+	if w.gz != nil {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends what has been written so far, deciding on compression first if needed
+func (w *gzipResponseWriter) Flush() {
+	if !w.decided {
+		if err := w.decide(); err != nil {
+			return
+		}
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide picks the encoding from the buffered body and response headers and writes out the buffer
+func (w *gzipResponseWriter) decide() error {
+	w.decided = true
+
+	if w.shouldCompress() {
+		header := w.Header()
+		header.Set("Content-Encoding", "gzip")
+		header.Del("Content-Length")
+
+		w.gz = w.pool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+		_, err := w.gz.Write(w.buf.Bytes())
+		w.buf.Reset()
+		return err
+	}
+
+	if w.buf.Len() == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+	return err
+}
+
+func (w *gzipResponseWriter) shouldCompress() bool {
+	if w.buf.Len() == 0 || w.buf.Len() < w.minSize {
+		return false
+	}
+
+	status := w.Status()
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(w.buf.Bytes())
+	}
+	return isCompressible(contentType)
+}
+
+// isCompressible reports whether a content type is worth compressing; media and archives are
+// already compressed
+func isCompressible(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	switch {
+	case strings.HasPrefix(mediaType, "image/") && mediaType != "image/svg+xml",
+		strings.HasPrefix(mediaType, "video/"),
+		strings.HasPrefix(mediaType, "audio/"):
+		return false
+	}
+
+	switch mediaType {
+	case "application/zip", "application/gzip", "application/x-gzip", "application/octet-stream", "application/pdf":
+		return false
+	}
+	return true
+}
+
+// close writes out a response that never reached minSize and finishes the gzip stream
+func (w *gzipResponseWriter) close() {
+	if !w.decided {
+		w.decide()
+	}
+	if w.gz != nil {
+		w.gz.Close()
+		w.gz.Reset(nil)
+		w.pool.Put(w.gz)
+		w.gz = nil
+	}
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (w *gzipResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
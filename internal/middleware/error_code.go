@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"actor-model-observability/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorCodeMiddleware gives every JSON error response a code field, so clients can branch on
+// codes instead of error texts. Responses whose handler set no code get the generic code of their
+// status, see models.ErrorCodeForStatus; responses that are not JSON objects are left alone.
+func ErrorCodeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := &errorCodeResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+	}
+}
+
+// errorCodeResponseWriter adds the generic code of their status to JSON error bodies without one
+type errorCodeResponseWriter struct {
+	gin.ResponseWriter
+}
+
+func (w *errorCodeResponseWriter) Write(data []byte) (int, error) {
+	if w.Status() < http.StatusBadRequest || !isJSONContentType(w.Header().Get("Content-Type")) {
+		return w.ResponseWriter.Write(data)
+	}
+	if _, err := w.ResponseWriter.Write(withErrorCode(data, models.ErrorCodeForStatus(w.Status()))); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
+
+func (w *errorCodeResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// withErrorCode returns the JSON object body with code set to code unless it has a code already.
+// Other bodies are returned unchanged.
+func withErrorCode(body []byte, code models.ErrorCode) []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil || fields == nil {
+		return body
+	}
+
+	existing, ok := fields["code"]
+	if ok {
+		var value string
+		if json.Unmarshal(existing, &value) == nil && value != "" {
+			return body
+		}
+	}
+
+	encoded, err := json.Marshal(code)
+	if err != nil {
+		return body
+	}
+	if ok {
+		fields["code"] = encoded
+		if rewritten, err := json.Marshal(fields); err == nil {
+			return rewritten
+		}
+		return body
+	}
+
+	// Append the code, keeping the field order of the body
+	trimmed := bytes.TrimRight(body, " \t\r\n")
+	end := len(trimmed) - 1
+	var out bytes.Buffer
+	out.Write(trimmed[:end])
+	if len(fields) > 0 {
+		out.WriteByte(',')
+	}
+	out.WriteString(`"code":`)
+	out.Write(encoded)
+	out.WriteByte('}')
+	out.Write(body[len(trimmed):])
+	return out.Bytes()
+}
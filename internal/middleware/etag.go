@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETagMiddleware creates a middleware for conditional GET requests. Successful GET responses get
+// a weak ETag computed from the body and the given Cache-Control header; requests whose
+// If-None-Match matches the ETag get 304 Not Modified without a body.
+func ETagMiddleware(cacheControl string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		w := c.Writer
+		writer := &etagResponseWriter{ResponseWriter: w}
+		c.Writer = writer
+		defer func() { c.Writer = w }()
+		c.Next()
+
+		if writer.streaming {
+			return
+		}
+
+		if w.Status() != http.StatusOK || writer.buf.Len() == 0 {
+			w.Write(writer.buf.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(writer.buf.Bytes())
+		etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+		header := w.Header()
+		header.Set("ETag", etag)
+		if cacheControl != "" && header.Get("Cache-Control") == "" {
+			header.Set("Cache-Control", cacheControl)
+		}
+
+		if etagMatches(c.GetHeader("If-None-Match"), etag) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			w.WriteHeaderNow()
+			return
+		}
+
+		w.Write(writer.buf.Bytes())
+	}
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, using weak comparison
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	opaque := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == opaque {
+			return true
+		}
+	}
+	return false
+}
+
+// etagResponseWriter holds back the body so the ETag can be computed before anything is sent.
+// A handler that flushes, such as a stream, switches it to writing through.
+type etagResponseWriter struct {
+	gin.ResponseWriter
+	buf       bytes.Buffer
+	streaming bool
+}
+
+func (w *etagResponseWriter) Write(data []byte) (int, error) {
+	if w.streaming {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.buf.Write(data)
+}
+
+func (w *etagResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *etagResponseWriter) Flush() {
+	if !w.streaming {
+		w.streaming = true
+		w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (w *etagResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"actor-model-observability/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FleetKeyHeader carries a fleet operator's API key
+const FleetKeyHeader = "X-Fleet-Key"
+
+// fleetContextKey is the gin context key holding the authenticated fleet
+const fleetContextKey = "fleet"
+
+// FleetAuthenticator resolves the fleet an API key belongs to
+type FleetAuthenticator interface {
+	Authenticate(ctx context.Context, apiKey string) (*models.Fleet, error)
+}
+
+// FleetAuthMiddleware requires a fleet API key in the X-Fleet-Key header and stores the fleet
+// in the context, scoping the request to it
+func FleetAuthMiddleware(authenticator FleetAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(FleetKeyHeader)
+		if key == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"message": "The " + FleetKeyHeader + " header is required",
+			})
+			return
+		}
+
+		fleet, err := authenticator.Authenticate(c.Request.Context(), key)
+		if err != nil {
+			if errors.Is(err, models.ErrInvalidFleetAPIKey) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error":   "Unauthorized",
+					"message": "Invalid fleet API key",
+					"code":    models.ErrorCodeInvalidAPIKey,
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal server error",
+				"message": "Failed to authenticate request",
+			})
+			return
+		}
+
+		c.Set(fleetContextKey, fleet)
+		c.Next()
+	}
+}
+
+// FleetFromContext returns the fleet stored by FleetAuthMiddleware
+func FleetFromContext(c *gin.Context) (*models.Fleet, bool) {
+	value, ok := c.Get(fleetContextKey)
+	if !ok {
+		return nil, false
+	}
+	fleet, ok := value.(*models.Fleet)
+	return fleet, ok
+}
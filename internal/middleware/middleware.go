@@ -79,13 +79,21 @@ func CORSMiddleware() gin.HandlerFunc {
 	}
 }
 
-// RateLimitMiddleware creates a middleware for rate limiting
-func RateLimitMiddleware() gin.HandlerFunc {
-	// Create a map to store rate limiters for each client IP
-	var (
-		mu       sync.RWMutex
-		limiters = make(map[string]*rate.Limiter)
-	)
+// RateLimiter enforces a per-client-IP request rate that can be changed at runtime
+type RateLimiter struct {
+	mu       sync.RWMutex
+	limiters map[string]*rate.Limiter
+	limit    rate.Limit
+	burst    int
+}
+
+// NewRateLimiter creates a rate limiter allowing requestsPerMinute requests per client IP
+func NewRateLimiter(requestsPerMinute, burst int) *RateLimiter {
+	rl := &RateLimiter{
+		limiters: make(map[string]*rate.Limiter),
+		limit:    rate.Every(time.Minute / time.Duration(requestsPerMinute)),
+		burst:    burst,
+	}
 
 	// Clean up old limiters periodically
 	go func() {
@@ -93,28 +101,49 @@ func RateLimitMiddleware() gin.HandlerFunc {
 		defer ticker.Stop()
 
 		for range ticker.C {
-			mu.Lock()
+			rl.mu.Lock()
 			// Simple cleanup - in production, you'd want more sophisticated cleanup
-			if len(limiters) > 1000 {
-				limiters = make(map[string]*rate.Limiter)
+			if len(rl.limiters) > 1000 {
+				rl.limiters = make(map[string]*rate.Limiter)
 			}
-			mu.Unlock()
+			rl.mu.Unlock()
 		}
 	}()
 
+	return rl
+}
+
+// SetLimit changes the allowed rate for new and existing clients
+func (rl *RateLimiter) SetLimit(requestsPerMinute, burst int) {
+	if requestsPerMinute <= 0 || burst <= 0 {
+		return
+	}
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.limit = rate.Every(time.Minute / time.Duration(requestsPerMinute))
+	rl.burst = burst
+	for _, limiter := range rl.limiters {
+		limiter.SetLimit(rl.limit)
+		limiter.SetBurst(rl.burst)
+	}
+}
+
+// Middleware returns the gin handler enforcing the limit
+func (rl *RateLimiter) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		clientIP := c.ClientIP()
 
-		mu.RLock()
-		limiter, exists := limiters[clientIP]
-		mu.RUnlock()
+		rl.mu.RLock()
+		limiter, exists := rl.limiters[clientIP]
+		rl.mu.RUnlock()
 
 		if !exists {
-			// Create new limiter: 100 requests per minute
-			limiter = rate.NewLimiter(rate.Every(time.Minute/100), 10)
-			mu.Lock()
-			limiters[clientIP] = limiter
-			mu.Unlock()
+			rl.mu.Lock()
+			limiter = rate.NewLimiter(rl.limit, rl.burst)
+			rl.limiters[clientIP] = limiter
+			rl.mu.Unlock()
 		}
 
 		if !limiter.Allow() {
@@ -130,6 +159,11 @@ func RateLimitMiddleware() gin.HandlerFunc {
 	}
 }
 
+// RateLimitMiddleware creates a middleware for rate limiting (100 requests per minute per client)
+func RateLimitMiddleware() gin.HandlerFunc {
+	return NewRateLimiter(100, 10).Middleware()
+}
+
 // MetricsMiddleware creates a middleware for collecting HTTP metrics
 func MetricsMiddleware(monitor *traditional.TraditionalMonitor) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -168,28 +202,6 @@ func SecurityMiddleware() gin.HandlerFunc {
 	}
 }
 
-// TimeoutMiddleware creates a middleware for request timeout
-func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		finish := make(chan struct{})
-		go func() {
-			c.Next()
-			finish <- struct{}{}
-		}()
-
-		select {
-		case <-time.After(timeout):
-			c.JSON(http.StatusRequestTimeout, gin.H{
-				"error":   "Request timeout",
-				"message": "The request took too long to process",
-			})
-			c.Abort()
-		case <-finish:
-			// Request completed within timeout
-		}
-	}
-}
-
 // AuthMiddleware creates a middleware for authentication
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -288,20 +300,6 @@ func ValidationMiddleware() gin.HandlerFunc {
 	}
 }
 
-// CompressionMiddleware creates a middleware for response compression
-func CompressionMiddleware() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Simple compression logic
-		// In production, you might want to use a more sophisticated compression middleware
-		acceptEncoding := c.GetHeader("Accept-Encoding")
-		if contains(acceptEncoding, "gzip") {
-			c.Header("Content-Encoding", "gzip")
-		}
-
-		c.Next()
-	}
-}
-
 // CacheMiddleware creates a middleware for response caching
 func CacheMiddleware(maxAge time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -364,31 +362,3 @@ func ErrorHandlingMiddleware(logger *logging.Logger) gin.HandlerFunc {
 		}
 	}
 }
-
-// Helper function
-func contains(s, substr string) bool {
-	return len(s) >= len(substr) && findSubstring(s, substr)
-}
-
-func findSubstring(s, substr string) bool {
-	if len(substr) == 0 {
-		return true
-	}
-	if len(s) < len(substr) {
-		return false
-	}
-
-	for i := 0; i <= len(s)-len(substr); i++ {
-		match := true
-		for j := 0; j < len(substr); j++ {
-			if s[i+j] != substr[j] {
-				match = false
-				break
-			}
-		}
-		if match {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"actor-model-observability/internal/redaction"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OperatorKeyHeader carries the key granting the operator role, which sees unredacted
+// observability data
+const OperatorKeyHeader = "X-Operator-Key"
+
+// OperatorRoleMiddleware grants the operator role to requests presenting one of keys in the
+// X-Operator-Key header. Other requests pass through unchanged and get redacted data.
+func OperatorRoleMiddleware(keys []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if key := c.GetHeader(OperatorKeyHeader); key != "" && isOperatorKey(key, keys) {
+			c.Set("role", "operator")
+			c.Request = c.Request.WithContext(redaction.WithOperator(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// RequireOperatorMiddleware rejects requests without the operator role granted by
+// OperatorRoleMiddleware, for endpoints that change or expose the actors' internal state
+func RequireOperatorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("role") != "operator" {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "Forbidden",
+				"message": "The operator role is required; present an operator key in the " + OperatorKeyHeader + " header",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// isOperatorKey compares key to every configured key in constant time
+func isOperatorKey(key string, keys []string) bool {
+	found := false
+	for _, k := range keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			found = true
+		}
+	}
+	return found
+}
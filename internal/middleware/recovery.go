@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/traditional"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ProblemContentType is the media type of problem responses (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// Problem is an RFC 7807 problem details response
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail"`
+	Instance  string `json:"instance"`
+	RequestID string `json:"request_id,omitempty"`
+	Code      string `json:"code"`
+}
+
+// RecoveryMiddleware recovers from panics in later handlers. The panic is logged with its stack
+// trace, published as a fatal crash event linked to the request ID and route, and counted in
+// panics_total, and the client gets a 500 problem response. monitor and events may be nil.
+func RecoveryMiddleware(logger *logging.Logger, monitor *traditional.TraditionalMonitor, events bus.Publisher) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// http.ErrAbortHandler deliberately aborts the response; let net/http handle it
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			stack := string(debug.Stack())
+			route := c.FullPath()
+			if route == "" {
+				route = "unmatched"
+			}
+			requestID := c.GetString("request_id")
+
+			logger.LogPanic(recovered, "http", "request_handling", logging.Fields{
+				"method":     c.Request.Method,
+				"route":      route,
+				"path":       c.Request.URL.Path,
+				"ip":         c.ClientIP(),
+				"request_id": requestID,
+				"stack":      stack,
+			})
+			if monitor != nil {
+				monitor.RecordPanic(route, c.Request.Method)
+			}
+			if events != nil {
+				events.Publish(bus.TopicEventLog, crashEvent(c, recovered, route, requestID, stack))
+			}
+
+			// The client is gone, there is no one to answer
+			if isBrokenPipe(recovered) {
+				c.Abort()
+				return
+			}
+
+			c.Header("Content-Type", ProblemContentType)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, Problem{
+				Type:      "about:blank",
+				Title:     http.StatusText(http.StatusInternalServerError),
+				Status:    http.StatusInternalServerError,
+				Detail:    "An unexpected error occurred",
+				Instance:  c.Request.URL.Path,
+				RequestID: requestID,
+				Code:      string(models.ErrorCodeInternal),
+			})
+		}()
+
+		c.Next()
+	}
+}
+
+// crashEvent describes a recovered panic as an event log
+func crashEvent(c *gin.Context, recovered interface{}, route, requestID, stack string) *models.EventLog {
+	eventData, _ := json.Marshal(map[string]interface{}{
+		"request_id": requestID,
+		"method":     c.Request.Method,
+		"route":      route,
+		"path":       c.Request.URL.Path,
+		"panic":      fmt.Sprint(recovered),
+		"stack":      stack,
+	})
+
+	now := time.Now()
+	return &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     "panic_recovered",
+		EventCategory: models.EventCategoryError,
+		EventData:     eventData,
+		Severity:      models.EventSeverityFatal,
+		Message:       fmt.Sprintf("panic in %s %s: %v", c.Request.Method, route, recovered),
+		Timestamp:     now,
+		CreatedAt:     now,
+	}
+}
+
+// isBrokenPipe reports whether a panic comes from writing to a connection the client closed
+func isBrokenPipe(recovered interface{}) bool {
+	err, ok := recovered.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var syscallErr *os.SyscallError
+	if !errors.As(opErr, &syscallErr) {
+		return false
+	}
+	message := strings.ToLower(syscallErr.Error())
+	return strings.Contains(message, "broken pipe") || strings.Contains(message, "connection reset by peer")
+}
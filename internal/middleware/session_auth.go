@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"actor-model-observability/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sessionContextKey is the gin context key holding the authenticated session
+const sessionContextKey = "session"
+
+// SessionAuthenticator resolves the device session an access token belongs to
+type SessionAuthenticator interface {
+	Authenticate(ctx context.Context, accessToken string) (*models.Session, error)
+}
+
+// SessionAuthMiddleware requires a valid session access token as "Authorization: Bearer <token>".
+// The session is stored in the context along with user_id and user_type, like AuthMiddleware.
+func SessionAuthMiddleware(authenticator SessionAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authorization := c.GetHeader("Authorization")
+		token, ok := strings.CutPrefix(authorization, "Bearer ")
+		if !ok || token == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"message": "Missing or invalid authorization header. Expected 'Bearer <token>'",
+			})
+			return
+		}
+
+		session, err := authenticator.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			if errors.Is(err, models.ErrInvalidToken) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+					"error":   "Unauthorized",
+					"message": "Invalid or expired authentication token",
+					"code":    models.ErrorCodeInvalidToken,
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal server error",
+				"message": "Failed to authenticate request",
+			})
+			return
+		}
+
+		c.Set(sessionContextKey, session)
+		c.Set("user_id", session.UserID.String())
+		c.Set("user_type", string(session.UserType))
+		c.Set("authenticated", true)
+		c.Next()
+	}
+}
+
+// SessionFromContext returns the session stored by SessionAuthMiddleware
+func SessionFromContext(c *gin.Context) (*models.Session, bool) {
+	value, ok := c.Get(sessionContextKey)
+	if !ok {
+		return nil, false
+	}
+	session, ok := value.(*models.Session)
+	return session, ok
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/deadline"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/traditional"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DeadlineExceededCode is the code of the 504 response sent when a request runs out of its time
+// budget
+const DeadlineExceededCode = string(models.ErrorCodeDeadlineExceeded)
+
+// TimeoutResponse is the 504 response sent when a request runs out of its time budget
+type TimeoutResponse struct {
+	Error     string `json:"error"`
+	Message   string `json:"message"`
+	Code      string `json:"code"`
+	Layer     string `json:"layer"` // layer the budget ran out in: http, service, repository or actor
+	TimeoutMs int64  `json:"timeout_ms"`
+}
+
+// TimeoutMiddleware gives every request the time budget of its endpoint, or cfg.Default, as its
+// context deadline. A request failing once its deadline passed, or not answered at all, gets a 504
+// TimeoutResponse instead, counted in deadline_exceeded_total by route and the layer that ran out
+// of time. streams lists the route patterns of the long-lived WebSocket and event stream
+// endpoints, e.g. /api/v1/rides/:id/status/stream, which never get a budget, whatever headers the
+// client sends; neither do other WebSocket upgrades and event streams. monitor may be nil.
+func TimeoutMiddleware(cfg config.TimeoutConfig, monitor *traditional.TraditionalMonitor, streams ...string) gin.HandlerFunc {
+	budgets := make(map[string]time.Duration, len(cfg.Endpoints))
+	for _, endpoint := range cfg.Endpoints {
+		budgets[endpoint.Method+" "+endpoint.Endpoint] = endpoint.Timeout
+	}
+	longLived := make(map[string]bool, len(streams))
+	for _, route := range streams {
+		longLived[route] = true
+	}
+
+	return func(c *gin.Context) {
+		timeout, ok := budgets[c.Request.Method+" "+c.FullPath()]
+		if !ok {
+			timeout = cfg.Default
+		}
+		if timeout <= 0 || longLived[c.FullPath()] || c.GetHeader("Upgrade") != "" ||
+			strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+			c.Next()
+			return
+		}
+
+		ctx, budget, cancel := deadline.WithBudget(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		writer := &timeoutResponseWriter{ResponseWriter: c.Writer, ctx: ctx, budget: budget}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+
+		if !writer.Written() && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			writer.timeout()
+		}
+		if !writer.timedOut {
+			return
+		}
+		// Handlers aborting with a status alone leave the body to write
+		if err := writer.sendTimeout(); err != nil {
+			c.Error(err)
+		}
+		if monitor != nil {
+			monitor.RecordDeadlineExceeded(c.FullPath(), c.Request.Method, writer.layer())
+		}
+	}
+}
+
+// timeoutResponseWriter replaces the error response of a request whose deadline passed with a 504
+// TimeoutResponse
+type timeoutResponseWriter struct {
+	gin.ResponseWriter
+	ctx    context.Context
+	budget *deadline.Budget
+
+	timedOut bool
+	sent     bool
+}
+
+func (w *timeoutResponseWriter) WriteHeader(code int) {
+	if code >= http.StatusInternalServerError && !w.Written() && errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		w.timeout()
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// timeout turns the response into a 504 TimeoutResponse
+func (w *timeoutResponseWriter) timeout() {
+	w.timedOut = true
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Del("Content-Length")
+	w.ResponseWriter.WriteHeader(http.StatusGatewayTimeout)
+}
+
+func (w *timeoutResponseWriter) Write(data []byte) (int, error) {
+	if w.timedOut {
+		return len(data), w.sendTimeout()
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// sendTimeout writes the 504 body in place of the handler's, once
+func (w *timeoutResponseWriter) sendTimeout() error {
+	if w.sent {
+		return nil
+	}
+	w.sent = true
+
+	body, err := json.Marshal(TimeoutResponse{
+		Error:     "Gateway timeout",
+		Message:   fmt.Sprintf("The request did not complete within its %s budget", w.budget.Timeout),
+		Code:      DeadlineExceededCode,
+		Layer:     w.layer(),
+		TimeoutMs: w.budget.Timeout.Milliseconds(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = w.ResponseWriter.Write(body)
+	return err
+}
+
+// layer returns the layer the request's budget ran out in, the HTTP layer when no layer below
+// the handler reported it
+func (w *timeoutResponseWriter) layer() string {
+	if layer := w.budget.Layer(); layer != "" {
+		return layer
+	}
+	return deadline.LayerHTTP
+}
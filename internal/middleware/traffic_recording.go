@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/loadtest"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/redaction"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TrafficRecorder records a sanitized trace of every request to a recording file, for
+// cmd/load-test -replay to re-issue at the recorded pace. A trace keeps the method, path, route,
+// content type, status and latency of a request and its JSON body redacted with the configured
+// rules; query parameters are redacted the same way and headers, credentials included, are never
+// recorded. Requests that cannot be replayed faithfully, with bodies that are not JSON or larger
+// than the configured size, WebSocket upgrades and event streams, are skipped.
+type TrafficRecorder struct {
+	cfg      config.TrafficRecordingConfig
+	writer   *loadtest.RecordingWriter
+	redactor *redaction.Redactor
+	logger   *logging.Logger
+}
+
+// NewTrafficRecorder creates a recorder truncating and writing to the configured file. Bodies
+// are redacted by redactor, which should not be nil outside of tests.
+func NewTrafficRecorder(cfg config.TrafficRecordingConfig, redactor *redaction.Redactor, logger *logging.Logger) (*TrafficRecorder, error) {
+	writer, err := loadtest.CreateRecording(cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	return &TrafficRecorder{
+		cfg:      cfg,
+		writer:   writer,
+		redactor: redactor,
+		logger:   logger.WithComponent("traffic_recorder"),
+	}, nil
+}
+
+// Recorded returns the number of requests recorded
+func (r *TrafficRecorder) Recorded() int64 {
+	return r.writer.Written()
+}
+
+// Close flushes and closes the recording file; later requests are not recorded
+func (r *TrafficRecorder) Close() error {
+	return r.writer.Close()
+}
+
+// Middleware returns the gin handler recording the requests. It should run after response
+// compression so that it sees the status of the uncompressed response.
+func (r *TrafficRecorder) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !r.recordable(c) {
+			c.Next()
+			return
+		}
+
+		arrived := time.Now()
+		body := captureRequestBody(c.Request, r.cfg.MaxBodySize)
+		contentType := c.Request.Header.Get("Content-Type")
+
+		c.Next()
+
+		recorded := &loadtest.RecordedRequest{
+			Method:     c.Request.Method,
+			Path:       c.Request.URL.Path,
+			Route:      c.FullPath(),
+			Status:     c.Writer.Status(),
+			DurationMs: float64(time.Since(arrived)) / float64(time.Millisecond),
+		}
+		if query := r.query(c.Request.URL.Query()); query != "" {
+			recorded.Path += "?" + query
+		}
+		if len(body) > 0 {
+			if len(body) > r.cfg.MaxBodySize || !isJSONContentType(contentType) || !json.Valid(body) {
+				return
+			}
+			recorded.ContentType = contentType
+			recorded.Body = r.redactor.JSON(body)
+		}
+
+		if err := r.writer.Write(arrived, recorded); err != nil && !errors.Is(err, os.ErrClosed) {
+			r.logger.WithError(err).WithField("path", recorded.Path).Warn("Failed to record request")
+		}
+	}
+}
+
+// recordable reports whether a request is recorded
+func (r *TrafficRecorder) recordable(c *gin.Context) bool {
+	if c.GetHeader("Upgrade") != "" || strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+		return false
+	}
+	for _, path := range r.cfg.ExcludedPaths {
+		if strings.Contains(c.Request.URL.Path, path) {
+			return false
+		}
+	}
+	return r.writer.Written() < r.cfg.MaxRequests
+}
+
+// query returns the encoded query parameters, redacted like a JSON object of the parameters.
+// Parameters the rules drop, or whose values no longer are strings, are left out.
+func (r *TrafficRecorder) query(values url.Values) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	doc := make(map[string]interface{}, len(values))
+	for key, vals := range values {
+		if len(vals) == 1 {
+			doc[key] = vals[0]
+			continue
+		}
+		list := make([]interface{}, len(vals))
+		for i, v := range vals {
+			list[i] = v
+		}
+		doc[key] = list
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		return ""
+	}
+	var redacted map[string]interface{}
+	if err := json.Unmarshal(r.redactor.JSON(raw), &redacted); err != nil {
+		return ""
+	}
+
+	query := url.Values{}
+	for key, value := range redacted {
+		switch v := value.(type) {
+		case string:
+			query.Add(key, v)
+		case []interface{}:
+			for _, element := range v {
+				if s, ok := element.(string); ok {
+					query.Add(key, s)
+				}
+			}
+		}
+	}
+	return query.Encode()
+}
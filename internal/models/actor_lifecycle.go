@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ActorLifecyclePhase is what an actor was doing during a bucket of its lifecycle timeline
+type ActorLifecyclePhase string
+
+const (
+	ActorLifecycleSpawned    ActorLifecyclePhase = "spawned"
+	ActorLifecycleProcessing ActorLifecyclePhase = "processing"
+	ActorLifecycleIdle       ActorLifecyclePhase = "idle"
+	ActorLifecycleFailed     ActorLifecyclePhase = "failed"
+	ActorLifecycleStopped    ActorLifecyclePhase = "stopped"
+)
+
+// ActorLifecycleFilter selects the actors and the window of their lifecycle timelines, split
+// into buckets of equal length from Start
+type ActorLifecycleFilter struct {
+	Start     time.Time
+	End       time.Time
+	Bucket    time.Duration
+	ActorType string // empty for every actor type
+	Limit     int    // most actors, the most recently spawned first
+}
+
+// Buckets returns the number of buckets covering the window, the last one possibly partial
+func (f ActorLifecycleFilter) Buckets() int {
+	if f.Bucket <= 0 || !f.Start.Before(f.End) {
+		return 0
+	}
+	return int((f.End.Sub(f.Start) + f.Bucket - 1) / f.Bucket)
+}
+
+// ActorLifecycleActivity is the activity of an actor within one bucket of the lifecycle window,
+// aggregated from its started, stopped and failed event logs and the messages it processed.
+// Actors without activity in the window have a single row with a nil Bucket.
+type ActorLifecycleActivity struct {
+	ActorID      string      `json:"actor_id" db:"actor_id"`
+	ActorType    ActorType   `json:"actor_type" db:"actor_type"`
+	EntityType   *string     `json:"entity_type" db:"entity_type"`
+	EntityID     *uuid.UUID  `json:"entity_id" db:"entity_id"`
+	Status       ActorStatus `json:"status" db:"status"`
+	SpawnedAt    time.Time   `json:"spawned_at" db:"spawned_at"`
+	AliveAtStart bool        `json:"alive_at_start" db:"alive_at_start"` // spawned before the window and not stopped since
+	Bucket       *int        `json:"bucket" db:"bucket"`                 // index from the window start
+	Started      int64       `json:"started" db:"started"`
+	Stopped      int64       `json:"stopped" db:"stopped"`
+	Failures     int64       `json:"failures" db:"failures"`
+	Messages     int64       `json:"messages" db:"messages"`
+	ProcessingMs int64       `json:"processing_ms" db:"processing_ms"`
+}
+
+// ActorLifecycleSegment is a run of consecutive buckets in which an actor was in the same phase,
+// one bar of a Gantt chart
+type ActorLifecycleSegment struct {
+	Phase        ActorLifecyclePhase `json:"phase"`
+	Start        time.Time           `json:"start"`
+	End          time.Time           `json:"end"`
+	Messages     int64               `json:"messages"`
+	ProcessingMs int64               `json:"processing_ms"`
+	Failures     int64               `json:"failures"`
+}
+
+// ActorLifecycle is the lifecycle timeline of one actor within the window
+type ActorLifecycle struct {
+	ActorID    string                  `json:"actor_id"`
+	ActorType  ActorType               `json:"actor_type"`
+	EntityType *string                 `json:"entity_type,omitempty"`
+	EntityID   *uuid.UUID              `json:"entity_id,omitempty"`
+	Status     ActorStatus             `json:"status"`
+	SpawnedAt  time.Time               `json:"spawned_at"`
+	Segments   []ActorLifecycleSegment `json:"segments"`
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIUsageRollupInterval is the interval the requests made with an API key are rolled up over
+const APIUsageRollupInterval = time.Minute
+
+// apiKeyIDLength is the length of an API key's ID, the start of the key's hash
+const apiKeyIDLength = 12
+
+// API usage bucket sizes
+const (
+	APIUsageBucketMinute = "minute"
+	APIUsageBucketHour   = "hour"
+	APIUsageBucketDay    = "day"
+)
+
+// APIUsageBucketSizes maps the API usage bucket sizes to their duration. Buckets are aligned to
+// UTC.
+var APIUsageBucketSizes = map[string]time.Duration{
+	APIUsageBucketMinute: time.Minute,
+	APIUsageBucketHour:   time.Hour,
+	APIUsageBucketDay:    24 * time.Hour,
+}
+
+// APIKeyID returns the ID usage is recorded under for the API key hashing to hash. The ID
+// tells keys apart in reports without being enough to look a key up.
+func APIKeyID(hash string) string {
+	if len(hash) > apiKeyIDLength {
+		return hash[:apiKeyIDLength]
+	}
+	return hash
+}
+
+// APIKeyRef is the API key a request is made with, and the tenant owning it
+type APIKeyRef struct {
+	KeyID             string
+	TenantID          uuid.UUID
+	DailyRequestQuota *int64 // the default quota when nil
+}
+
+// APIQuotaStatus is how much of its daily request quota an API key has used
+type APIQuotaStatus struct {
+	Limit   int64     // requests allowed per UTC day, 0 for no quota
+	Used    int64     // requests made today, not counting the ones rejected for the quota
+	ResetAt time.Time // start of the next UTC day
+}
+
+// Exceeded returns true if the key has no request left today
+func (s APIQuotaStatus) Exceeded() bool {
+	return s.Limit > 0 && s.Used >= s.Limit
+}
+
+// Remaining returns the requests the key has left today
+func (s APIQuotaStatus) Remaining() int64 {
+	if s.Used >= s.Limit {
+		return 0
+	}
+	return s.Limit - s.Used
+}
+
+// APIUsageRollup is the requests made with an API key over one rollup interval. Requests
+// rejected for the key's quota are counted as client errors as well.
+type APIUsageRollup struct {
+	KeyID           string    `json:"key_id" db:"key_id"`
+	TenantID        uuid.UUID `json:"tenant_id" db:"tenant_id"`
+	BucketStart     time.Time `json:"bucket_start" db:"bucket_start"`
+	Requests        int64     `json:"requests" db:"requests"`
+	ClientErrors    int64     `json:"client_errors" db:"client_errors"`
+	ServerErrors    int64     `json:"server_errors" db:"server_errors"`
+	QuotaRejections int64     `json:"quota_rejections" db:"quota_rejections"`
+	LatencySumMs    float64   `json:"latency_sum_ms" db:"latency_sum_ms"`
+	LatencyMaxMs    float64   `json:"latency_max_ms" db:"latency_max_ms"`
+}
+
+// TableName returns the table name for APIUsageRollup
+func (APIUsageRollup) TableName() string {
+	return "api_usage_rollups"
+}
+
+// Add adds the requests of other to the rollup
+func (r *APIUsageRollup) Add(other *APIUsageRollup) {
+	r.Requests += other.Requests
+	r.ClientErrors += other.ClientErrors
+	r.ServerErrors += other.ServerErrors
+	r.QuotaRejections += other.QuotaRejections
+	r.LatencySumMs += other.LatencySumMs
+	if other.LatencyMaxMs > r.LatencyMaxMs {
+		r.LatencyMaxMs = other.LatencyMaxMs
+	}
+}
+
+// APIUsageFilter selects the rollups of a time window, optionally of one key or tenant
+type APIUsageFilter struct {
+	KeyID    string
+	TenantID *uuid.UUID
+	From     time.Time
+	To       time.Time
+}
+
+// APIUsageStats are the requests made with API keys over a period
+type APIUsageStats struct {
+	Requests        int64   `json:"requests"`
+	ClientErrors    int64   `json:"client_errors"`
+	ServerErrors    int64   `json:"server_errors"`
+	QuotaRejections int64   `json:"quota_rejections"`
+	ErrorRate       float64 `json:"error_rate"` // share of the requests failing with a 4xx or 5xx status
+	AvgLatencyMs    float64 `json:"avg_latency_ms"`
+	MaxLatencyMs    float64 `json:"max_latency_ms"`
+}
+
+// APIUsageBucket is the requests made with an API key over one time bucket
+type APIUsageBucket struct {
+	BucketStart time.Time `json:"bucket_start"`
+	KeyID       string    `json:"key_id"`
+	TenantID    uuid.UUID `json:"tenant_id"`
+	APIUsageStats
+}
+
+// APIUsageReport is the usage of API keys over a time window, by key and time bucket
+type APIUsageReport struct {
+	From    time.Time         `json:"from"`
+	To      time.Time         `json:"to"`
+	Bucket  string            `json:"bucket"`
+	Buckets []*APIUsageBucket `json:"buckets"` // by bucket, then key
+	Total   APIUsageStats     `json:"total"`
+}
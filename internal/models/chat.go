@@ -0,0 +1,65 @@
+package models
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxChatMessageLength caps a chat message body, in characters
+const maxChatMessageLength = 1000
+
+// ChatSenderRole is the trip participant a chat message is from
+type ChatSenderRole string
+
+const (
+	ChatSenderPassenger ChatSenderRole = "passenger"
+	ChatSenderDriver    ChatSenderRole = "driver"
+)
+
+// Recipient returns the other participant of the trip
+func (r ChatSenderRole) Recipient() ChatSenderRole {
+	if r == ChatSenderDriver {
+		return ChatSenderPassenger
+	}
+	return ChatSenderDriver
+}
+
+// TripChatMessage is a message between a trip's passenger and driver. The body is stored after
+// the chat filters ran; ReadAt is set once the recipient has read it.
+type TripChatMessage struct {
+	ID         uuid.UUID      `json:"id" db:"id"`
+	TripID     uuid.UUID      `json:"trip_id" db:"trip_id"`
+	SenderRole ChatSenderRole `json:"sender_role" db:"sender_role"`
+	SenderID   uuid.UUID      `json:"sender_id" db:"sender_id"` // user ID of the sender
+	Body       string         `json:"body" db:"body"`
+	ReadAt     *time.Time     `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt  time.Time      `json:"created_at" db:"created_at"`
+}
+
+// TableName returns the table name for TripChatMessage
+func (TripChatMessage) TableName() string {
+	return "trip_chat_messages"
+}
+
+// Validate validates the chat message data
+func (m *TripChatMessage) Validate() error {
+	if m.TripID == uuid.Nil {
+		return ErrInvalidTripID
+	}
+	if m.SenderRole != ChatSenderPassenger && m.SenderRole != ChatSenderDriver {
+		return ErrInvalidChatSender
+	}
+	if strings.TrimSpace(m.Body) == "" || utf8.RuneCountInString(m.Body) > maxChatMessageLength {
+		return ErrInvalidChatMessage
+	}
+	return nil
+}
+
+// TripChatUnreadCounts is the number of chat messages each participant of a trip has not read
+type TripChatUnreadCounts struct {
+	Passenger int `json:"passenger"`
+	Driver    int `json:"driver"`
+}
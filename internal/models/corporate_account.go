@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxCorporateAccountNameLength caps a corporate account's name
+const maxCorporateAccountNameLength = 255
+
+// CorporateStatementMonthLayout is the layout of the month a corporate statement covers
+const CorporateStatementMonthLayout = "2006-01"
+
+// CorporateAccount is a company paying for the rides its passengers bill to it. Without a
+// monthly spending limit, billing is unlimited.
+type CorporateAccount struct {
+	ID                   uuid.UUID `json:"id" db:"id"`
+	Name                 string    `json:"name" db:"name"`
+	MonthlySpendingLimit *float64  `json:"monthly_spending_limit" db:"monthly_spending_limit"`
+	CreatedAt            time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt            time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TableName returns the table name for CorporateAccount
+func (CorporateAccount) TableName() string {
+	return "corporate_accounts"
+}
+
+// Validate validates the corporate account data
+func (a *CorporateAccount) Validate() error {
+	if a.Name == "" || len(a.Name) > maxCorporateAccountNameLength {
+		return ErrInvalidCorporateAccountName
+	}
+	if a.MonthlySpendingLimit != nil && *a.MonthlySpendingLimit < 0 {
+		return ErrInvalidSpendingLimit
+	}
+	return nil
+}
+
+// CorporateTripCharge is a trip billed to a corporate account. The fare estimated when the ride
+// was requested stands in for the final fare until the trip completes. TripStatus and
+// FareAmount come from the trip when charges are listed.
+type CorporateTripCharge struct {
+	TripID        uuid.UUID  `json:"trip_id" db:"trip_id"`
+	AccountID     uuid.UUID  `json:"account_id" db:"account_id"`
+	PassengerID   uuid.UUID  `json:"passenger_id" db:"passenger_id"`
+	EstimatedFare float64    `json:"estimated_fare" db:"estimated_fare"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	TripStatus    TripStatus `json:"trip_status" db:"trip_status"`
+	FareAmount    *float64   `json:"fare_amount" db:"fare_amount"`
+}
+
+// Amount returns what the charge counts towards the account's spend: nothing for a cancelled
+// trip, the final fare of a completed one and the estimated fare otherwise
+func (c *CorporateTripCharge) Amount() float64 {
+	switch {
+	case c.TripStatus == TripStatusCancelled:
+		return 0
+	case c.TripStatus == TripStatusCompleted && c.FareAmount != nil:
+		return *c.FareAmount
+	default:
+		return c.EstimatedFare
+	}
+}
+
+// CorporateStatement is a corporate account's billed trips and spend over a calendar month (UTC)
+type CorporateStatement struct {
+	AccountID      uuid.UUID                `json:"account_id"`
+	AccountName    string                   `json:"account_name"`
+	Month          string                   `json:"month"`
+	PeriodStart    time.Time                `json:"period_start"`
+	PeriodEnd      time.Time                `json:"period_end"`
+	SpendingLimit  *float64                 `json:"spending_limit"`
+	Trips          int                      `json:"trips"`
+	CompletedTrips int                      `json:"completed_trips"`
+	CancelledTrips int                      `json:"cancelled_trips"`
+	TotalSpend     float64                  `json:"total_spend"`
+	RemainingLimit *float64                 `json:"remaining_limit"`
+	Passengers     []CorporateStatementLine `json:"passengers,omitempty"`
+}
+
+// CorporateStatementLine is one passenger's billed trips and spend within a statement
+type CorporateStatementLine struct {
+	PassengerID uuid.UUID `json:"passenger_id"`
+	Trips       int       `json:"trips"`
+	TotalSpend  float64   `json:"total_spend"`
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// Dashboard summary views; each is precomputed into its own summary table by the refresher
+const (
+	DashboardViewHourlyTrips   = "hourly_trips"
+	DashboardViewMatchingTimes = "matching_times"
+	DashboardViewZoneSupply    = "zone_supply"
+)
+
+// DashboardViews lists every dashboard summary view
+var DashboardViews = []string{DashboardViewHourlyTrips, DashboardViewMatchingTimes, DashboardViewZoneSupply}
+
+// HourlyTripSummary counts the trips requested in the hour starting at Hour by how far they got
+type HourlyTripSummary struct {
+	Hour      time.Time `json:"hour" db:"hour"`
+	Requested int       `json:"requested" db:"requested"`
+	Matched   int       `json:"matched" db:"matched"`
+	Completed int       `json:"completed" db:"completed"`
+	Cancelled int       `json:"cancelled" db:"cancelled"`
+}
+
+// MatchingTimeSummary describes how long the trips requested in the hour starting at Hour
+// waited to be matched with a driver
+type MatchingTimeSummary struct {
+	Hour       time.Time `json:"hour" db:"hour"`
+	Samples    int       `json:"samples" db:"samples"`
+	AvgSeconds float64   `json:"avg_seconds" db:"avg_seconds"`
+	P50Seconds float64   `json:"p50_seconds" db:"p50_seconds"`
+	P90Seconds float64   `json:"p90_seconds" db:"p90_seconds"`
+	P99Seconds float64   `json:"p99_seconds" db:"p99_seconds"`
+}
+
+// ZoneSupplySummary counts the drivers online in a geohash zone
+type ZoneSupplySummary struct {
+	Zone             string  `json:"zone" db:"zone"`
+	CenterLat        float64 `json:"center_lat" db:"-"`
+	CenterLng        float64 `json:"center_lng" db:"-"`
+	AvailableDrivers int     `json:"available_drivers" db:"available_drivers"`
+	BusyDrivers      int     `json:"busy_drivers" db:"busy_drivers"`
+}
+
+// DashboardRefresh records the last refresh of a dashboard summary view
+type DashboardRefresh struct {
+	View        string    `json:"view" db:"view_name"`
+	RefreshedAt time.Time `json:"refreshed_at" db:"refreshed_at"`
+	DurationMs  int64     `json:"duration_ms" db:"duration_ms"`
+	Rows        int       `json:"rows" db:"row_count"`
+}
+
+// DashboardFreshness tells how up to date a dashboard summary is. Summaries that were never
+// refreshed, or not within twice their refresh interval, are stale.
+type DashboardFreshness struct {
+	View            string     `json:"view"`
+	RefreshedAt     *time.Time `json:"refreshed_at"`
+	AgeSeconds      *float64   `json:"age_seconds"`
+	RefreshInterval string     `json:"refresh_interval"`
+	Stale           bool       `json:"stale"`
+}
+
+// DashboardSummary is a dashboard summary view with its freshness
+type DashboardSummary struct {
+	DashboardFreshness
+	Data interface{} `json:"data"`
+}
+
+// TripTiming holds the lifecycle timestamps of a trip used by the dashboard summaries
+type TripTiming struct {
+	RequestedAt time.Time  `db:"requested_at"`
+	MatchedAt   *time.Time `db:"matched_at"`
+	CompletedAt *time.Time `db:"completed_at"`
+	CancelledAt *time.Time `db:"cancelled_at"`
+}
+
+// DriverPosition is the status and last known location of a driver
+type DriverPosition struct {
+	Status    DriverStatus `db:"status"`
+	Latitude  float64      `db:"current_latitude"`
+	Longitude float64      `db:"current_longitude"`
+}
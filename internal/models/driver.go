@@ -28,8 +28,50 @@ type Driver struct {
 	CurrentLongitude *float64     `json:"current_longitude" db:"current_longitude" gorm:"type:decimal(11,8)"`
 	Rating           float64      `json:"rating" db:"rating" gorm:"type:decimal(3,2);default:5.00"`
 	TotalTrips       int          `json:"total_trips" db:"total_trips" gorm:"default:0"`
-	CreatedAt        time.Time    `json:"created_at" db:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
-	UpdatedAt        time.Time    `json:"updated_at" db:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+	FleetID          *uuid.UUID   `json:"fleet_id,omitempty" db:"fleet_id" gorm:"type:uuid;index"` // fleet operator the driver drives for
+
+	// Ride preferences the driver can meet, matched against passengers' RidePreferences, and the
+	// vehicle's special-assistance capabilities
+	PetFriendly          bool `json:"pet_friendly" db:"pet_friendly" gorm:"default:false"`
+	WheelchairAccessible bool `json:"wheelchair_accessible" db:"wheelchair_accessible" gorm:"default:false"`
+	QuietRides           bool `json:"quiet_rides" db:"quiet_rides" gorm:"default:false"` // offers rides without conversation
+	ChildSeat            bool `json:"child_seat" db:"child_seat" gorm:"default:false"`
+
+	CreatedAt time.Time `json:"created_at" db:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// VehicleCapability is special-assistance equipment a ride can require of the driver's vehicle
+type VehicleCapability string
+
+const (
+	VehicleCapabilityWheelchairAccessible VehicleCapability = "wheelchair_accessible"
+	VehicleCapabilityChildSeat            VehicleCapability = "child_seat"
+)
+
+// VehicleCapabilities are the vehicle capabilities a ride can require
+var VehicleCapabilities = []VehicleCapability{VehicleCapabilityWheelchairAccessible, VehicleCapabilityChildSeat}
+
+// IsValid returns true if the capability is known
+func (c VehicleCapability) IsValid() bool {
+	for _, capability := range VehicleCapabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCapability returns true if the driver's vehicle has the capability
+func (d *Driver) HasCapability(capability VehicleCapability) bool {
+	switch capability {
+	case VehicleCapabilityWheelchairAccessible:
+		return d.WheelchairAccessible
+	case VehicleCapabilityChildSeat:
+		return d.ChildSeat
+	default:
+		return false
+	}
 }
 
 // TableName returns the table name for Driver
@@ -128,3 +170,79 @@ func (d *Driver) Validate() error {
 	}
 	return nil
 }
+
+// DriverStatsSort is a column the driver statistics can be sorted by
+type DriverStatsSort string
+
+const (
+	DriverStatsSortTripsCompleted DriverStatsSort = "trips_completed"
+	DriverStatsSortAcceptanceRate DriverStatsSort = "acceptance_rate"
+	DriverStatsSortAverageRating  DriverStatsSort = "average_rating"
+	DriverStatsSortEarnings       DriverStatsSort = "earnings"
+	DriverStatsSortOnlineHours    DriverStatsSort = "online_hours"
+)
+
+// IsValid returns true if the sort column is supported
+func (s DriverStatsSort) IsValid() bool {
+	switch s {
+	case DriverStatsSortTripsCompleted, DriverStatsSortAcceptanceRate, DriverStatsSortAverageRating,
+		DriverStatsSortEarnings, DriverStatsSortOnlineHours:
+		return true
+	}
+	return false
+}
+
+// DriverStatsFilter selects the period and ordering of driver statistics.
+// Trips are attributed to the period by their request time.
+type DriverStatsFilter struct {
+	From       time.Time
+	To         time.Time
+	SortBy     DriverStatsSort
+	Descending bool
+	Limit      int
+	Offset     int
+}
+
+// DriverStats holds a driver's aggregated activity over a period
+type DriverStats struct {
+	DriverID       uuid.UUID    `json:"driver_id" db:"driver_id"`
+	UserID         uuid.UUID    `json:"user_id" db:"user_id"`
+	Name           string       `json:"name" db:"name"`
+	VehiclePlate   string       `json:"vehicle_plate" db:"vehicle_plate"`
+	Status         DriverStatus `json:"status" db:"status"`
+	AverageRating  float64      `json:"average_rating" db:"average_rating"`
+	TripsAssigned  int          `json:"trips_assigned" db:"trips_assigned"`
+	TripsAccepted  int          `json:"trips_accepted" db:"trips_accepted"`
+	TripsCompleted int          `json:"trips_completed" db:"trips_completed"`
+	TripsCancelled int          `json:"trips_cancelled" db:"trips_cancelled"`
+	AcceptanceRate float64      `json:"acceptance_rate" db:"acceptance_rate"` // accepted / assigned, 0 when nothing was assigned
+	Earnings       float64      `json:"earnings" db:"earnings"`               // fares of completed trips
+	OnlineHours    float64      `json:"online_hours" db:"online_hours"`       // time spent online or busy
+}
+
+// DriverEarningsWindowDays is the number of days, today included, DriverTripStats.RecentEarnings
+// covers
+const DriverEarningsWindowDays = 30
+
+// DriverTripStats is the rollup of a driver's completed trips, updated with each trip completion
+// so that driver profiles need not aggregate the driver's trips. Earnings are rolled up per day
+// (UTC) to sum the last DriverEarningsWindowDays days.
+type DriverTripStats struct {
+	DriverID        uuid.UUID  `json:"driver_id" db:"driver_id"`
+	TotalTrips      int        `json:"total_trips" db:"total_trips"`
+	TotalDistanceKm float64    `json:"total_distance_km" db:"total_distance_km"` // billed distance
+	LastTripAt      *time.Time `json:"last_trip_at" db:"last_trip_at"`
+	AverageRating   float64    `json:"average_rating" db:"-"`       // the driver's running rating
+	RecentEarnings  float64    `json:"last_30_day_earnings" db:"-"` // final fares of the trips completed in the window
+}
+
+// TableName returns the table name for DriverTripStats
+func (DriverTripStats) TableName() string {
+	return "driver_trip_stats"
+}
+
+// DriverEarningsWindowStart returns the first day, as YYYY-MM-DD in UTC, of the earnings window
+// ending on the day of now
+func DriverEarningsWindowStart(now time.Time) string {
+	return now.UTC().AddDate(0, 0, -(DriverEarningsWindowDays - 1)).Format(time.DateOnly)
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Destination mode limits
+const (
+	// DriverDestinationDailyLimit is how many times a driver can turn on destination mode per day (UTC)
+	DriverDestinationDailyLimit = 2
+	// DriverDestinationDefaultDuration is how long destination mode stays on when no duration is given
+	DriverDestinationDefaultDuration = time.Hour
+	// DriverDestinationMaxDuration is the longest destination mode can stay on
+	DriverDestinationMaxDuration = 4 * time.Hour
+
+	// driverDestinationMinDetourKm is the detour always allowed, so short trips near the route still match
+	driverDestinationMinDetourKm = 2.0
+	// driverDestinationDetourRatio is the allowed detour as a share of the remaining distance home
+	driverDestinationDetourRatio = 0.3
+)
+
+// DriverDestination is a destination filter set by a driver ("heading home"). While it is
+// active, matching only offers the driver trips that take them roughly toward it.
+type DriverDestination struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	DriverID  uuid.UUID  `json:"driver_id" db:"driver_id"`
+	Latitude  float64    `json:"latitude" db:"latitude"`
+	Longitude float64    `json:"longitude" db:"longitude"`
+	Address   *string    `json:"address" db:"address"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	ClearedAt *time.Time `json:"cleared_at,omitempty" db:"cleared_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// TableName returns the table name for DriverDestination
+func (DriverDestination) TableName() string {
+	return "driver_destinations"
+}
+
+// Location returns the destination coordinates
+func (d *DriverDestination) Location() Location {
+	return Location{Latitude: d.Latitude, Longitude: d.Longitude}
+}
+
+// IsActive reports whether the filter applies at the given time
+func (d *DriverDestination) IsActive(at time.Time) bool {
+	return d.ClearedAt == nil && at.Before(d.ExpiresAt)
+}
+
+// AllowsTrip reports whether a trip from pickup to dropoff takes a driver at current roughly
+// toward the destination: the dropoff must be closer to the destination than the driver is now,
+// and driving the trip must not add much distance compared to heading there directly.
+func (d *DriverDestination) AllowsTrip(current, pickup, dropoff Location) bool {
+	dest := d.Location()
+	direct := current.DistanceTo(dest)
+	if dropoff.DistanceTo(dest) >= direct {
+		return false
+	}
+
+	viaTrip := current.DistanceTo(pickup) + pickup.DistanceTo(dropoff) + dropoff.DistanceTo(dest)
+	return viaTrip-direct <= math.Max(driverDestinationMinDetourKm, direct*driverDestinationDetourRatio)
+}
+
+// Validate validates the destination data
+func (d *DriverDestination) Validate() error {
+	if d.DriverID == uuid.Nil {
+		return ErrInvalidDriverID
+	}
+	loc := d.Location()
+	if !loc.IsValid() {
+		return ErrInvalidLocation
+	}
+	if d.ExpiresAt.IsZero() || d.ExpiresAt.Sub(d.CreatedAt) > DriverDestinationMaxDuration {
+		return ErrInvalidDuration
+	}
+	return nil
+}
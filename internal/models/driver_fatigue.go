@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DriverFatigueLimit is the limit that sent a driver to rest
+type DriverFatigueLimit string
+
+const (
+	DriverFatigueLimitOnline  DriverFatigueLimit = "online_time"
+	DriverFatigueLimitDriving DriverFatigueLimit = "driving_time"
+)
+
+// DriverHours is a driver's time online and driving within a window, usually the rolling fatigue
+// window ending now
+type DriverHours struct {
+	DriverID       uuid.UUID `json:"driver_id" db:"driver_id"`
+	WindowStart    time.Time `json:"window_start" db:"window_start"`
+	WindowEnd      time.Time `json:"window_end" db:"window_end"`
+	OnlineSeconds  int64     `json:"online_seconds" db:"online_seconds"`   // online or busy
+	DrivingSeconds int64     `json:"driving_seconds" db:"driving_seconds"` // busy with a trip
+}
+
+// DriverRest is the rest a driver was sent on after reaching a fatigue limit. The driver cannot
+// go back online before RestUntil.
+type DriverRest struct {
+	ID             uuid.UUID          `json:"id" db:"id"`
+	DriverID       uuid.UUID          `json:"driver_id" db:"driver_id"`
+	Limit          DriverFatigueLimit `json:"limit" db:"limit_reached"`
+	OnlineSeconds  int64              `json:"online_seconds" db:"online_seconds"` // over the window when the limit was reached
+	DrivingSeconds int64              `json:"driving_seconds" db:"driving_seconds"`
+	StartedAt      time.Time          `json:"started_at" db:"started_at"`
+	RestUntil      time.Time          `json:"rest_until" db:"rest_until"`
+}
+
+// TableName returns the table name for DriverRest
+func (DriverRest) TableName() string {
+	return "driver_rests"
+}
+
+// DriverFatigue is a driver's time online and driving over the rolling fatigue window, measured
+// against the fatigue limits, and the rest the driver is on, if any
+type DriverFatigue struct {
+	DriverHours
+	OnlineLimitSeconds  int64       `json:"online_limit_seconds"`
+	DrivingLimitSeconds int64       `json:"driving_limit_seconds"`
+	Rest                *DriverRest `json:"rest,omitempty"`
+}
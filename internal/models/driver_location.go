@@ -0,0 +1,29 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DriverLocationPing is a GPS position reported by a driver, kept in the driver's location trail.
+// Pings are downsampled as they age: a ping standing for every ping of a driver over an interval
+// has the interval as its resolution and counts them as its samples.
+type DriverLocationPing struct {
+	DriverID          uuid.UUID `json:"driver_id" db:"driver_id"`
+	Latitude          float64   `json:"latitude" db:"latitude"`
+	Longitude         float64   `json:"longitude" db:"longitude"`
+	RecordedAt        time.Time `json:"recorded_at" db:"recorded_at"`
+	ResolutionSeconds int       `json:"resolution_seconds" db:"resolution_seconds"` // 0 for a ping as reported
+	Samples           int       `json:"samples" db:"samples"`                       // pings reported over the resolution
+}
+
+// DriverLocationTrail is the path a driver reported over a time window, for support and fraud
+// investigations
+type DriverLocationTrail struct {
+	DriverID  uuid.UUID             `json:"driver_id"`
+	From      time.Time             `json:"from"`
+	To        time.Time             `json:"to"`
+	Pings     []*DriverLocationPing `json:"pings"`     // oldest first
+	Truncated bool                  `json:"truncated"` // more pings were recorded in the window than returned
+}
@@ -0,0 +1,361 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OnboardingStage is a stage of a driver's onboarding. Drivers move through the stages in order
+// and can only go online once activated.
+type OnboardingStage string
+
+const (
+	OnboardingRegistered         OnboardingStage = "registered"
+	OnboardingDocumentsSubmitted OnboardingStage = "documents_submitted"
+	OnboardingVerified           OnboardingStage = "verified"
+	OnboardingTrainingCompleted  OnboardingStage = "training_completed"
+	OnboardingActivated          OnboardingStage = "activated"
+)
+
+// OnboardingStages are the onboarding stages in the order drivers move through them
+var OnboardingStages = []OnboardingStage{
+	OnboardingRegistered,
+	OnboardingDocumentsSubmitted,
+	OnboardingVerified,
+	OnboardingTrainingCompleted,
+	OnboardingActivated,
+}
+
+// IsValid returns true if the stage is supported
+func (s OnboardingStage) IsValid() bool {
+	return s.index() >= 0
+}
+
+// Next returns the stage following this one, or false for the last stage
+func (s OnboardingStage) Next() (OnboardingStage, bool) {
+	i := s.index()
+	if i < 0 || i == len(OnboardingStages)-1 {
+		return "", false
+	}
+	return OnboardingStages[i+1], true
+}
+
+// CanTransitionTo checks if a driver at this stage can move to the given stage. Stages cannot
+// be skipped; activated is terminal.
+func (s OnboardingStage) CanTransitionTo(next OnboardingStage) bool {
+	following, ok := s.Next()
+	return ok && following == next
+}
+
+// Reached returns true if a driver at this stage has reached stage
+func (s OnboardingStage) Reached(stage OnboardingStage) bool {
+	return s.index() >= stage.index() && stage.IsValid()
+}
+
+func (s OnboardingStage) index() int {
+	for i, stage := range OnboardingStages {
+		if s == stage {
+			return i
+		}
+	}
+	return -1
+}
+
+// OnboardingDocument is a document drivers submit to be verified
+type OnboardingDocument string
+
+const (
+	OnboardingDocumentDriverLicense       OnboardingDocument = "driver_license"
+	OnboardingDocumentVehicleRegistration OnboardingDocument = "vehicle_registration"
+	OnboardingDocumentInsurance           OnboardingDocument = "insurance"
+)
+
+// RequiredOnboardingDocuments are the documents a driver submits before being verified
+var RequiredOnboardingDocuments = []OnboardingDocument{
+	OnboardingDocumentDriverLicense,
+	OnboardingDocumentVehicleRegistration,
+	OnboardingDocumentInsurance,
+}
+
+// IsValid returns true if the document is one of the required documents
+func (d OnboardingDocument) IsValid() bool {
+	for _, document := range RequiredOnboardingDocuments {
+		if d == document {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateOnboardingDocuments checks that at least one document is submitted and that each is
+// a required document
+func ValidateOnboardingDocuments(documents []OnboardingDocument) error {
+	if len(documents) == 0 {
+		return fmt.Errorf("%w: no documents submitted", ErrInvalidOnboardingDocument)
+	}
+	for _, document := range documents {
+		if !document.IsValid() {
+			return fmt.Errorf("%w: %q is not one of %s", ErrInvalidOnboardingDocument, document, OnboardingDocumentList(RequiredOnboardingDocuments))
+		}
+	}
+	return nil
+}
+
+// OnboardingDocumentList is the set of documents a driver submitted, stored as a
+// comma-separated column
+type OnboardingDocumentList []OnboardingDocument
+
+// Contains reports whether the list includes the document
+func (l OnboardingDocumentList) Contains(document OnboardingDocument) bool {
+	for _, d := range l {
+		if d == document {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the documents separated by commas
+func (l OnboardingDocumentList) String() string {
+	documents := make([]string, len(l))
+	for i, d := range l {
+		documents[i] = string(d)
+	}
+	return strings.Join(documents, ", ")
+}
+
+// Value implements driver.Valuer
+func (l OnboardingDocumentList) Value() (driver.Value, error) {
+	documents := make([]string, len(l))
+	for i, d := range l {
+		documents[i] = string(d)
+	}
+	return strings.Join(documents, ","), nil
+}
+
+// Scan implements sql.Scanner
+func (l *OnboardingDocumentList) Scan(src interface{}) error {
+	var value string
+	switch v := src.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into OnboardingDocumentList", src)
+	}
+
+	*l = nil
+	for _, d := range strings.Split(value, ",") {
+		if d != "" {
+			*l = append(*l, OnboardingDocument(d))
+		}
+	}
+	return nil
+}
+
+// DriverOnboarding is a driver's progress through onboarding, with the time each stage was
+// reached. Drivers submit their documents and complete training; admins verify the documents
+// and activate drivers.
+type DriverOnboarding struct {
+	DriverID             uuid.UUID              `json:"driver_id" db:"driver_id"`
+	Stage                OnboardingStage        `json:"stage" db:"stage"`
+	Documents            OnboardingDocumentList `json:"documents" db:"documents" swaggertype:"array,string"`
+	RegisteredAt         time.Time              `json:"registered_at" db:"registered_at"`
+	DocumentsSubmittedAt *time.Time             `json:"documents_submitted_at,omitempty" db:"documents_submitted_at"`
+	VerifiedAt           *time.Time             `json:"verified_at,omitempty" db:"verified_at"`
+	VerifiedBy           *string                `json:"verified_by,omitempty" db:"verified_by"`
+	TrainingCompletedAt  *time.Time             `json:"training_completed_at,omitempty" db:"training_completed_at"`
+	ActivatedAt          *time.Time             `json:"activated_at,omitempty" db:"activated_at"`
+	ActivatedBy          *string                `json:"activated_by,omitempty" db:"activated_by"`
+	UpdatedAt            time.Time              `json:"updated_at" db:"updated_at"`
+}
+
+// TableName returns the table name for DriverOnboarding
+func (DriverOnboarding) TableName() string {
+	return "driver_onboardings"
+}
+
+// NewDriverOnboarding starts the onboarding of a driver registered at now
+func NewDriverOnboarding(driverID uuid.UUID, now time.Time) *DriverOnboarding {
+	return &DriverOnboarding{
+		DriverID:     driverID,
+		Stage:        OnboardingRegistered,
+		RegisteredAt: now,
+		UpdatedAt:    now,
+	}
+}
+
+// ReachedAt returns when the driver reached stage, or nil if not yet
+func (o *DriverOnboarding) ReachedAt(stage OnboardingStage) *time.Time {
+	switch stage {
+	case OnboardingRegistered:
+		return &o.RegisteredAt
+	case OnboardingDocumentsSubmitted:
+		return o.DocumentsSubmittedAt
+	case OnboardingVerified:
+		return o.VerifiedAt
+	case OnboardingTrainingCompleted:
+		return o.TrainingCompletedAt
+	case OnboardingActivated:
+		return o.ActivatedAt
+	}
+	return nil
+}
+
+// MissingDocuments returns the required documents the driver has not submitted
+func (o *DriverOnboarding) MissingDocuments() []OnboardingDocument {
+	var missing []OnboardingDocument
+	for _, document := range RequiredOnboardingDocuments {
+		if !o.Documents.Contains(document) {
+			missing = append(missing, document)
+		}
+	}
+	return missing
+}
+
+// SubmitDocuments adds documents to those submitted, moving the driver to documents_submitted
+// once every required document is in
+func (o *DriverOnboarding) SubmitDocuments(documents []OnboardingDocument, now time.Time) {
+	for _, document := range documents {
+		if !o.Documents.Contains(document) {
+			o.Documents = append(o.Documents, document)
+		}
+	}
+	if o.Stage == OnboardingRegistered && len(o.MissingDocuments()) == 0 {
+		o.Stage = OnboardingDocumentsSubmitted
+		o.DocumentsSubmittedAt = &now
+	}
+	o.UpdatedAt = now
+}
+
+// Verify records that reviewer verified the driver's documents
+func (o *DriverOnboarding) Verify(reviewer string, now time.Time) {
+	o.Stage = OnboardingVerified
+	o.VerifiedAt = &now
+	o.VerifiedBy = &reviewer
+	o.UpdatedAt = now
+}
+
+// CompleteTraining records that the driver completed training
+func (o *DriverOnboarding) CompleteTraining(now time.Time) {
+	o.Stage = OnboardingTrainingCompleted
+	o.TrainingCompletedAt = &now
+	o.UpdatedAt = now
+}
+
+// Activate records that reviewer activated the driver, who may go online from now on
+func (o *DriverOnboarding) Activate(reviewer string, now time.Time) {
+	o.Stage = OnboardingActivated
+	o.ActivatedAt = &now
+	o.ActivatedBy = &reviewer
+	o.UpdatedAt = now
+}
+
+// Requirements of the onboarding stages, as reported to drivers
+const (
+	OnboardingRequirementDocument = "document"            // submit a document, named by the requirement
+	OnboardingRequirementReview   = "document_review"     // wait for an admin to verify the documents
+	OnboardingRequirementTraining = "training"            // complete the driver training
+	OnboardingRequirementApproval = "activation_approval" // wait for an admin to activate the driver
+)
+
+// OnboardingRequirement is something outstanding before a driver reaches the next stage
+type OnboardingRequirement struct {
+	Type        string              `json:"type"`
+	Document    *OnboardingDocument `json:"document,omitempty"`
+	Description string              `json:"description"`
+	ByDriver    bool                `json:"by_driver"` // false when the driver is waiting on an admin
+}
+
+// OutstandingRequirements returns what is outstanding before the driver reaches the next stage;
+// none once activated
+func (o *DriverOnboarding) OutstandingRequirements() []OnboardingRequirement {
+	switch o.Stage {
+	case OnboardingRegistered:
+		var requirements []OnboardingRequirement
+		for _, document := range o.MissingDocuments() {
+			document := document
+			requirements = append(requirements, OnboardingRequirement{
+				Type:        OnboardingRequirementDocument,
+				Document:    &document,
+				Description: fmt.Sprintf("Submit your %s", strings.ReplaceAll(string(document), "_", " ")),
+				ByDriver:    true,
+			})
+		}
+		return requirements
+	case OnboardingDocumentsSubmitted:
+		return []OnboardingRequirement{{Type: OnboardingRequirementReview, Description: "Your documents are being verified"}}
+	case OnboardingVerified:
+		return []OnboardingRequirement{{Type: OnboardingRequirementTraining, Description: "Complete the driver training", ByDriver: true}}
+	case OnboardingTrainingCompleted:
+		return []OnboardingRequirement{{Type: OnboardingRequirementApproval, Description: "Your account is awaiting activation"}}
+	}
+	return []OnboardingRequirement{}
+}
+
+// OnboardingStageProgress is a stage of a driver's onboarding and when it was reached
+type OnboardingStageProgress struct {
+	Stage     OnboardingStage `json:"stage"`
+	Completed bool            `json:"completed"`
+	ReachedAt *time.Time      `json:"reached_at,omitempty"`
+}
+
+// DriverOnboardingProgress is a driver's current onboarding stage, every stage with when it was
+// reached, and what is outstanding before the next one
+type DriverOnboardingProgress struct {
+	*DriverOnboarding
+	NextStage   *OnboardingStage          `json:"next_stage,omitempty"`
+	Stages      []OnboardingStageProgress `json:"stages"`
+	Outstanding []OnboardingRequirement   `json:"outstanding"`
+}
+
+// Progress returns the driver's onboarding progress
+func (o *DriverOnboarding) Progress() *DriverOnboardingProgress {
+	progress := &DriverOnboardingProgress{
+		DriverOnboarding: o,
+		Stages:           make([]OnboardingStageProgress, len(OnboardingStages)),
+		Outstanding:      o.OutstandingRequirements(),
+	}
+	if next, ok := o.Stage.Next(); ok {
+		progress.NextStage = &next
+	}
+	for i, stage := range OnboardingStages {
+		progress.Stages[i] = OnboardingStageProgress{
+			Stage:     stage,
+			Completed: o.Stage.Reached(stage),
+			ReachedAt: o.ReachedAt(stage),
+		}
+	}
+	return progress
+}
+
+// OnboardingStageCount is the number of drivers currently at an onboarding stage
+type OnboardingStageCount struct {
+	Stage OnboardingStage `db:"stage"`
+	Count int64           `db:"count"`
+}
+
+// OnboardingFunnelStage is the drivers of a cohort who reached a stage of onboarding. Conversion
+// is the share of the drivers who reached the previous stage that also reached this one, and
+// OverallConversion the share of the cohort.
+type OnboardingFunnelStage struct {
+	Stage             OnboardingStage `json:"stage"`
+	Reached           int64           `json:"reached"`
+	Current           int64           `json:"current"` // drivers still at the stage
+	Conversion        float64         `json:"conversion"`
+	OverallConversion float64         `json:"overall_conversion"`
+}
+
+// OnboardingFunnel is the onboarding funnel of the drivers registered in [Start, End)
+type OnboardingFunnel struct {
+	Start  time.Time               `json:"start"`
+	End    time.Time               `json:"end"`
+	Total  int64                   `json:"total"`
+	Stages []OnboardingFunnelStage `json:"stages"`
+}
@@ -0,0 +1,214 @@
+package models
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorCode is the stable, machine-readable code of an API error response. Clients branch on the
+// code; the error and message texts are for people and may change.
+type ErrorCode string
+
+// Generic error codes, the code of any error response without a more specific one
+const (
+	ErrorCodeBadRequest           ErrorCode = "BAD_REQUEST"
+	ErrorCodeValidationFailed     ErrorCode = "VALIDATION_FAILED"
+	ErrorCodeUnauthorized         ErrorCode = "UNAUTHORIZED"
+	ErrorCodeForbidden            ErrorCode = "FORBIDDEN"
+	ErrorCodeNotFound             ErrorCode = "NOT_FOUND"
+	ErrorCodeMethodNotAllowed     ErrorCode = "METHOD_NOT_ALLOWED"
+	ErrorCodeConflict             ErrorCode = "CONFLICT"
+	ErrorCodePayloadTooLarge      ErrorCode = "PAYLOAD_TOO_LARGE"
+	ErrorCodeUnsupportedMediaType ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	ErrorCodeUnprocessable        ErrorCode = "UNPROCESSABLE"
+	ErrorCodeRateLimited          ErrorCode = "RATE_LIMITED"
+	ErrorCodeInternal             ErrorCode = "INTERNAL_ERROR"
+	ErrorCodeNotImplemented       ErrorCode = "NOT_IMPLEMENTED"
+	ErrorCodeServiceUnavailable   ErrorCode = "SERVICE_UNAVAILABLE"
+	ErrorCodeDeadlineExceeded     ErrorCode = "DEADLINE_EXCEEDED"
+)
+
+// Domain error codes
+const (
+	ErrorCodeInvalidCredentials          ErrorCode = "AUTH_INVALID_CREDENTIALS"
+	ErrorCodeInvalidToken                ErrorCode = "AUTH_INVALID_TOKEN"
+	ErrorCodeInvalidAPIKey               ErrorCode = "AUTH_INVALID_API_KEY"
+	ErrorCodeAppUpgradeRequired          ErrorCode = "APP_UPGRADE_REQUIRED"
+	ErrorCodeQuotaExceeded               ErrorCode = "QUOTA_EXCEEDED"
+	ErrorCodeNotPermitted                ErrorCode = "OPERATION_NOT_PERMITTED"
+	ErrorCodeInvalidStatusTransition     ErrorCode = "INVALID_STATUS_TRANSITION"
+	ErrorCodeStatusConflict              ErrorCode = "STATUS_CONFLICT"
+	ErrorCodeRideNoDrivers               ErrorCode = "RIDE_NO_DRIVERS"
+	ErrorCodeRidePickupOutsideArea       ErrorCode = "RIDE_PICKUP_OUTSIDE_AREA"
+	ErrorCodeRideDestinationOutsideArea  ErrorCode = "RIDE_DESTINATION_OUTSIDE_AREA"
+	ErrorCodeRideOutsideHours            ErrorCode = "RIDE_OUTSIDE_HOURS"
+	ErrorCodeRideMatchingUnavailable     ErrorCode = "RIDE_MATCHING_UNAVAILABLE"
+	ErrorCodeSpendingLimitExceeded       ErrorCode = "CORPORATE_SPENDING_LIMIT_EXCEEDED"
+	ErrorCodeDriverNotVerified           ErrorCode = "DRIVER_NOT_VERIFIED"
+	ErrorCodeDriverResting               ErrorCode = "DRIVER_RESTING"
+	ErrorCodeDriverNotArrived            ErrorCode = "DRIVER_NOT_ARRIVED"
+	ErrorCodeOnboardingRequirementsOpen  ErrorCode = "ONBOARDING_REQUIREMENTS_OPEN"
+	ErrorCodeTripNotCompletable          ErrorCode = "TRIP_NOT_COMPLETABLE"
+	ErrorCodeTripNotAwaitingPickup       ErrorCode = "TRIP_NOT_AWAITING_PICKUP"
+	ErrorCodeTripNotDriverCancellable    ErrorCode = "TRIP_NOT_DRIVER_CANCELLABLE"
+	ErrorCodeTripNotCancellable          ErrorCode = "TRIP_NOT_CANCELLABLE"
+	ErrorCodeNoShowTooEarly              ErrorCode = "TRIP_NO_SHOW_TOO_EARLY"
+	ErrorCodeTripNotDisputable           ErrorCode = "TRIP_NOT_DISPUTABLE"
+	ErrorCodeDisputeAlreadyOpen          ErrorCode = "DISPUTE_ALREADY_OPEN"
+	ErrorCodeTripChatClosed              ErrorCode = "TRIP_CHAT_CLOSED"
+	ErrorCodeForecastInsufficientHistory ErrorCode = "FORECAST_INSUFFICIENT_HISTORY"
+)
+
+// ErrorCodeInfo describes an error code of the catalog
+type ErrorCodeInfo struct {
+	Code        ErrorCode `json:"code"`
+	Status      int       `json:"status"` // HTTP status the code is sent with
+	Description string    `json:"description"`
+}
+
+var errorCatalog = []ErrorCodeInfo{
+	{ErrorCodeBadRequest, http.StatusBadRequest, "The request is malformed or has invalid parameters"},
+	{ErrorCodeValidationFailed, http.StatusBadRequest, "A field of the request failed validation"},
+	{ErrorCodeUnauthorized, http.StatusUnauthorized, "The request is not authenticated"},
+	{ErrorCodeForbidden, http.StatusForbidden, "The caller is not allowed to perform the request"},
+	{ErrorCodeNotFound, http.StatusNotFound, "The resource does not exist"},
+	{ErrorCodeMethodNotAllowed, http.StatusMethodNotAllowed, "The endpoint does not support the method"},
+	{ErrorCodeConflict, http.StatusConflict, "The request conflicts with the current state of the resource"},
+	{ErrorCodePayloadTooLarge, http.StatusRequestEntityTooLarge, "The request body is too large"},
+	{ErrorCodeUnsupportedMediaType, http.StatusUnsupportedMediaType, "The request body has an unsupported content type"},
+	{ErrorCodeUnprocessable, http.StatusUnprocessableEntity, "The request is well formed but cannot be processed"},
+	{ErrorCodeRateLimited, http.StatusTooManyRequests, "Too many requests; retry later"},
+	{ErrorCodeInternal, http.StatusInternalServerError, "An unexpected server error occurred"},
+	{ErrorCodeNotImplemented, http.StatusNotImplemented, "The endpoint is not implemented"},
+	{ErrorCodeServiceUnavailable, http.StatusServiceUnavailable, "The service or one of its dependencies is unavailable"},
+	{ErrorCodeDeadlineExceeded, http.StatusGatewayTimeout, "The request did not complete within its time budget"},
+	{ErrorCodeInvalidCredentials, http.StatusUnauthorized, "The email or password is wrong"},
+	{ErrorCodeInvalidToken, http.StatusUnauthorized, "The session or refresh token is invalid or expired"},
+	{ErrorCodeInvalidAPIKey, http.StatusUnauthorized, "The fleet API key is invalid"},
+	{ErrorCodeAppUpgradeRequired, http.StatusUpgradeRequired, "The app version is below the minimum supported on its platform; update the app"},
+	{ErrorCodeQuotaExceeded, http.StatusTooManyRequests, "The daily request quota of the API key is used up"},
+	{ErrorCodeNotPermitted, http.StatusForbidden, "The caller is not a party allowed to act on the resource"},
+	{ErrorCodeInvalidStatusTransition, http.StatusConflict, "The resource cannot move from its current status to the requested one"},
+	{ErrorCodeStatusConflict, http.StatusConflict, "The resource status changed concurrently; reload and retry"},
+	{ErrorCodeRideNoDrivers, http.StatusUnprocessableEntity, "No driver is available for the ride"},
+	{ErrorCodeRidePickupOutsideArea, http.StatusUnprocessableEntity, "The pickup is outside the service area"},
+	{ErrorCodeRideDestinationOutsideArea, http.StatusUnprocessableEntity, "The destination is outside the service area"},
+	{ErrorCodeRideOutsideHours, http.StatusUnprocessableEntity, "Rides cannot be requested outside operating hours"},
+	{ErrorCodeRideMatchingUnavailable, http.StatusServiceUnavailable, "Ride matching is saturated; retry after the Retry-After delay"},
+	{ErrorCodeSpendingLimitExceeded, http.StatusForbidden, "The corporate account's monthly spending limit is exceeded"},
+	{ErrorCodeDriverNotVerified, http.StatusConflict, "The driver must complete onboarding before going online"},
+	{ErrorCodeDriverResting, http.StatusConflict, "The driver must rest after reaching a fatigue limit"},
+	{ErrorCodeDriverNotArrived, http.StatusConflict, "The driver has not arrived at the pickup"},
+	{ErrorCodeOnboardingRequirementsOpen, http.StatusConflict, "The onboarding stage has outstanding requirements"},
+	{ErrorCodeTripNotCompletable, http.StatusConflict, "Only active trips with a driver can be completed"},
+	{ErrorCodeTripNotAwaitingPickup, http.StatusConflict, "Only matched or accepted trips can await their passenger"},
+	{ErrorCodeTripNotDriverCancellable, http.StatusConflict, "Drivers can only cancel matched or accepted trips"},
+	{ErrorCodeTripNotCancellable, http.StatusConflict, "The trip is already completed or cancelled"},
+	{ErrorCodeNoShowTooEarly, http.StatusConflict, "A no-show can only be reported after the wait window"},
+	{ErrorCodeTripNotDisputable, http.StatusConflict, "Only completed trips with a fare can be disputed"},
+	{ErrorCodeDisputeAlreadyOpen, http.StatusConflict, "The trip already has an open fare dispute"},
+	{ErrorCodeTripChatClosed, http.StatusConflict, "Chat is only open while the trip is in progress"},
+	{ErrorCodeForecastInsufficientHistory, http.StatusUnprocessableEntity, "There is not enough trip history to forecast demand"},
+}
+
+// ErrorCatalog returns every error code the API sends
+func ErrorCatalog() []ErrorCodeInfo {
+	catalog := make([]ErrorCodeInfo, len(errorCatalog))
+	copy(catalog, errorCatalog)
+	return catalog
+}
+
+// errorCodes maps the domain errors to their codes, most specific first
+var errorCodes = []struct {
+	err  error
+	code ErrorCode
+}{
+	{ErrInvalidCredentials, ErrorCodeInvalidCredentials},
+	{ErrInvalidToken, ErrorCodeInvalidToken},
+	{ErrInvalidFleetAPIKey, ErrorCodeInvalidAPIKey},
+	{ErrAppUpgradeRequired, ErrorCodeAppUpgradeRequired},
+	{ErrUnauthorizedOperation, ErrorCodeNotPermitted},
+	{ErrNoDriversAvailable, ErrorCodeRideNoDrivers},
+	{ErrCorporateSpendingLimitExceeded, ErrorCodeSpendingLimitExceeded},
+	{ErrDriverNotActivated, ErrorCodeDriverNotVerified},
+	{ErrDriverResting, ErrorCodeDriverResting},
+	{ErrDriverNotArrived, ErrorCodeDriverNotArrived},
+	{ErrOnboardingRequirementsOpen, ErrorCodeOnboardingRequirementsOpen},
+	{ErrTripNotCompletable, ErrorCodeTripNotCompletable},
+	{ErrTripNotAwaitingPickup, ErrorCodeTripNotAwaitingPickup},
+	{ErrTripNotDriverCancellable, ErrorCodeTripNotDriverCancellable},
+	{ErrTripNotCancellable, ErrorCodeTripNotCancellable},
+	{ErrNoShowTooEarly, ErrorCodeNoShowTooEarly},
+	{ErrTripNotDisputable, ErrorCodeTripNotDisputable},
+	{ErrDisputeAlreadyOpen, ErrorCodeDisputeAlreadyOpen},
+	{ErrTripChatClosed, ErrorCodeTripChatClosed},
+	{ErrInsufficientForecastHistory, ErrorCodeForecastInsufficientHistory},
+	{ErrInvalidStatusTransition, ErrorCodeInvalidStatusTransition},
+	{ErrTripStatusConflict, ErrorCodeStatusConflict},
+	{ErrOnboardingStageConflict, ErrorCodeStatusConflict},
+	{ErrDisputeStatusConflict, ErrorCodeStatusConflict},
+	{ErrIncidentStatusConflict, ErrorCodeStatusConflict},
+	{ErrFraudSignalStatusConflict, ErrorCodeStatusConflict},
+	{ErrPaymentDiscrepancyStatusConflict, ErrorCodeStatusConflict},
+	{ErrDuplicateEntry, ErrorCodeConflict},
+}
+
+// ErrorCodeOf returns the code of a domain error, or "" for errors without one, which are sent
+// with the generic code of their status
+func ErrorCodeOf(err error) ErrorCode {
+	var rejected *RideRejectedError
+	if errors.As(err, &rejected) {
+		return rejected.Reason.Code()
+	}
+	for _, mapping := range errorCodes {
+		if errors.Is(err, mapping.err) {
+			return mapping.code
+		}
+	}
+
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		return ErrorCodeValidationFailed
+	}
+	var notFound *NotFoundError
+	if errors.As(err, &notFound) {
+		return ErrorCodeNotFound
+	}
+	return ""
+}
+
+// ErrorCodeForStatus returns the generic code of an error response with the HTTP status
+func ErrorCodeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrorCodeBadRequest
+	case http.StatusUnauthorized:
+		return ErrorCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrorCodeForbidden
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusMethodNotAllowed:
+		return ErrorCodeMethodNotAllowed
+	case http.StatusConflict:
+		return ErrorCodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return ErrorCodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return ErrorCodeUnsupportedMediaType
+	case http.StatusUnprocessableEntity:
+		return ErrorCodeUnprocessable
+	case http.StatusTooManyRequests:
+		return ErrorCodeRateLimited
+	case http.StatusNotImplemented:
+		return ErrorCodeNotImplemented
+	case http.StatusServiceUnavailable:
+		return ErrorCodeServiceUnavailable
+	case http.StatusGatewayTimeout:
+		return ErrorCodeDeadlineExceeded
+	}
+	if status >= http.StatusInternalServerError {
+		return ErrorCodeInternal
+	}
+	return ErrorCodeBadRequest
+}
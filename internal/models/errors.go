@@ -37,6 +37,149 @@ var (
 	ErrInvalidRideType            = errors.New("invalid ride type")
 )
 
+// Trip completion errors
+var (
+	ErrInvalidDropoffLocation  = errors.New("invalid dropoff location")
+	ErrInvalidCompletionCharge = errors.New("tolls and extras cannot be negative")
+	ErrTripNotCompletable      = errors.New("only active trips with a driver can be completed")
+	ErrTripStatusConflict      = errors.New("trip status changed concurrently")
+)
+
+// Pickup wait errors
+var (
+	ErrTripNotAwaitingPickup = errors.New("only matched or accepted trips can await their passenger")
+	ErrDriverNotArrived      = errors.New("driver has not arrived at the pickup")
+	ErrNoShowTooEarly        = errors.New("a no-show can only be reported after the wait window")
+)
+
+// Trip re-matching errors
+var (
+	ErrTripNotDriverCancellable = errors.New("drivers can only cancel matched or accepted trips")
+)
+
+// Trip cancellation errors
+var (
+	ErrTripNotCancellable = errors.New("only trips neither completed nor cancelled can be cancelled")
+)
+
+// Driver fatigue errors
+var (
+	ErrDriverResting   = errors.New("driver must rest after reaching a fatigue limit")
+	ErrDriverNotOnline = errors.New("driver is not online")
+)
+
+// Driver onboarding errors
+var (
+	ErrInvalidOnboardingDocument  = errors.New("invalid onboarding document")
+	ErrOnboardingRequirementsOpen = errors.New("onboarding stage has outstanding requirements")
+	ErrOnboardingStageConflict    = errors.New("driver onboarding stage changed concurrently")
+	ErrDriverNotActivated         = errors.New("driver must complete onboarding before going online")
+)
+
+// Saved location validation errors
+var (
+	ErrInvalidLocationLabel = errors.New("invalid location label")
+	ErrInvalidLocation      = errors.New("invalid location")
+)
+
+// Webhook validation errors
+var (
+	ErrInvalidWebhookOwner  = errors.New("invalid webhook owner")
+	ErrInvalidWebhookURL    = errors.New("invalid webhook URL")
+	ErrInvalidWebhookSecret = errors.New("invalid webhook secret")
+	ErrInvalidWebhookEvent  = errors.New("invalid webhook event")
+)
+
+// Fare dispute errors
+var (
+	ErrInvalidTripID         = errors.New("invalid trip ID")
+	ErrInvalidDisputeReason  = errors.New("invalid dispute reason")
+	ErrInvalidDisputeNote    = errors.New("invalid dispute resolution note")
+	ErrInvalidDisputeStatus  = errors.New("invalid dispute status")
+	ErrTripNotDisputable     = errors.New("only completed trips with a fare can be disputed")
+	ErrDisputeAlreadyOpen    = errors.New("trip already has an open fare dispute")
+	ErrDisputeStatusConflict = errors.New("fare dispute status changed concurrently")
+)
+
+// Incentive campaign validation errors
+var (
+	ErrInvalidCampaignName   = errors.New("invalid campaign name")
+	ErrInvalidRequiredTrips  = errors.New("required trips must be positive")
+	ErrInvalidBonusAmount    = errors.New("bonus amount must be positive")
+	ErrInvalidCampaignWindow = errors.New("campaign must end after it starts")
+)
+
+// Demand forecast errors
+var (
+	ErrInsufficientForecastHistory = errors.New("not enough trip history to forecast demand")
+)
+
+// Session errors
+var (
+	ErrInvalidDeviceID    = errors.New("invalid device ID")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+)
+
+// App version errors
+var (
+	ErrInvalidAppVersion  = errors.New("app version must be a version such as 2.3.0")
+	ErrAppUpgradeRequired = errors.New("app version is no longer supported")
+)
+
+// Fleet errors
+var (
+	ErrInvalidFleetName         = errors.New("invalid fleet name")
+	ErrInvalidFleetAPIKey       = errors.New("invalid fleet API key")
+	ErrInvalidFleetTimezone     = errors.New("fleet timezone must be an IANA name such as Asia/Jakarta")
+	ErrInvalidFleetRequestQuota = errors.New("fleet daily request quota must be positive")
+)
+
+// Trip chat errors
+var (
+	ErrInvalidChatMessage = errors.New("chat message must be between 1 and 1000 characters")
+	ErrInvalidChatSender  = errors.New("invalid chat sender")
+	ErrTripChatClosed     = errors.New("chat is only open while the trip is in progress")
+)
+
+// Safety incident errors
+var (
+	ErrInvalidIncidentNote    = errors.New("invalid incident note")
+	ErrInvalidIncidentStatus  = errors.New("invalid incident status")
+	ErrIncidentStatusConflict = errors.New("safety incident status changed concurrently")
+	ErrTripFrozen             = errors.New("trip has safety incidents and its data is frozen")
+)
+
+// Fraud signal errors
+var (
+	ErrInvalidFraudRule          = errors.New("invalid fraud rule")
+	ErrInvalidFraudSeverity      = errors.New("invalid fraud severity")
+	ErrInvalidFraudSignalStatus  = errors.New("invalid fraud signal status")
+	ErrInvalidFraudReviewNote    = errors.New("fraud review note must be at most 1000 characters")
+	ErrFraudSignalStatusConflict = errors.New("fraud signal status changed concurrently")
+)
+
+// Payment errors
+var (
+	ErrInvalidPaymentKind               = errors.New("invalid payment kind")
+	ErrInvalidPaymentMethod             = errors.New("invalid payment method")
+	ErrInvalidPaymentAmount             = errors.New("payment amount must be positive")
+	ErrInvalidPaymentReference          = errors.New("payment reference must be at most 255 characters")
+	ErrInvalidPaymentDiscrepancyType    = errors.New("invalid payment discrepancy type")
+	ErrInvalidPaymentDiscrepancyStatus  = errors.New("invalid payment discrepancy status")
+	ErrInvalidPaymentResolution         = errors.New("invalid payment resolution")
+	ErrInvalidPaymentResolutionNote     = errors.New("payment resolution note must be at most 1000 characters")
+	ErrPaymentDiscrepancyStatusConflict = errors.New("payment discrepancy status changed concurrently")
+)
+
+// Corporate account errors
+var (
+	ErrInvalidCorporateAccountName    = errors.New("invalid corporate account name")
+	ErrInvalidSpendingLimit           = errors.New("spending limit must not be negative")
+	ErrCorporateSpendingLimitExceeded = errors.New("corporate account monthly spending limit exceeded")
+	ErrNoCorporateAccount             = errors.New("passenger is not linked to a corporate account")
+)
+
 // Business logic errors
 var (
 	ErrUserNotFound          = errors.New("user not found")
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ETAPrediction is the ETA a trip's passenger was given when a driver was matched, and how long
+// the driver actually took to arrive at the pickup once it did
+type ETAPrediction struct {
+	TripID           uuid.UUID  `json:"trip_id" db:"trip_id"`
+	DriverID         uuid.UUID  `json:"driver_id" db:"driver_id"`
+	Zone             string     `json:"zone" db:"zone"` // geohash of the pickup
+	Hour             int        `json:"hour" db:"hour"` // hour of day of the match, in the report timezone
+	DistanceKm       float64    `json:"distance_km" db:"distance_km"`
+	SpeedKmh         float64    `json:"speed_kmh" db:"speed_kmh"` // average speed the ETA assumed
+	PredictedSeconds int        `json:"predicted_seconds" db:"predicted_seconds"`
+	PredictedAt      time.Time  `json:"predicted_at" db:"predicted_at"`
+	ArrivedAt        *time.Time `json:"arrived_at" db:"arrived_at"`
+	ActualSeconds    *int       `json:"actual_seconds" db:"actual_seconds"`
+	ErrorSeconds     *int       `json:"error_seconds" db:"error_seconds"` // actual minus predicted
+}
+
+// TableName returns the table name for ETAPrediction
+func (ETAPrediction) TableName() string {
+	return "eta_predictions"
+}
+
+// Arrive records the driver arriving at the pickup at the given time
+func (p *ETAPrediction) Arrive(at time.Time) {
+	actual := int(at.Sub(p.PredictedAt).Seconds())
+	if actual < 0 {
+		actual = 0
+	}
+	errorSeconds := actual - p.PredictedSeconds
+	p.ArrivedAt = &at
+	p.ActualSeconds = &actual
+	p.ErrorSeconds = &errorSeconds
+}
+
+// ETAAccuracyStats are the accuracy metrics of the ETAs of arrived drivers. The bias is the mean
+// of the actual minus the predicted seconds: positive when drivers arrive later than predicted.
+type ETAAccuracyStats struct {
+	Samples             int64   `json:"samples" db:"samples"`
+	MAESeconds          float64 `json:"mae_seconds" db:"mae_seconds"`
+	BiasSeconds         float64 `json:"bias_seconds" db:"bias_seconds"`
+	AvgPredictedSeconds float64 `json:"avg_predicted_seconds" db:"avg_predicted_seconds"`
+	AvgActualSeconds    float64 `json:"avg_actual_seconds" db:"avg_actual_seconds"`
+	AvgDistanceKm       float64 `json:"avg_distance_km" db:"avg_distance_km"`
+	ObservedSpeedKmh    float64 `json:"observed_speed_kmh" db:"-"` // total distance over total actual time
+}
+
+// ETAAccuracy are the ETA accuracy metrics of the matches in a pickup zone at an hour of day
+type ETAAccuracy struct {
+	Zone string `json:"zone" db:"zone"`
+	Hour int    `json:"hour" db:"hour"`
+	ETAAccuracyStats
+}
+
+// ETAAccuracyReport is the accuracy of the ETAs predicted in [From, To), overall and by pickup
+// zone and hour of day, with the average speed the ETA model currently assumes
+type ETAAccuracyReport struct {
+	From       time.Time        `json:"from"`
+	To         time.Time        `json:"to"`
+	Timezone   string           `json:"timezone"` // of the hours of day
+	SpeedKmh   float64          `json:"speed_kmh"`
+	Overall    ETAAccuracyStats `json:"overall"`
+	Buckets    []*ETAAccuracy   `json:"buckets"` // most samples first
+	ComputedAt time.Time        `json:"computed_at"`
+}
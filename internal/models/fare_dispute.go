@@ -0,0 +1,152 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FareDisputeStatus represents the status of a fare dispute
+type FareDisputeStatus string
+
+const (
+	FareDisputeOpen        FareDisputeStatus = "open"
+	FareDisputeUnderReview FareDisputeStatus = "under_review"
+	FareDisputeResolved    FareDisputeStatus = "resolved"
+	FareDisputeRejected    FareDisputeStatus = "rejected"
+)
+
+// maxDisputeTextLength caps the passenger's reason and the reviewer's note
+const maxDisputeTextLength = 1000
+
+// IsValid returns true if the status is supported
+func (s FareDisputeStatus) IsValid() bool {
+	switch s {
+	case FareDisputeOpen, FareDisputeUnderReview, FareDisputeResolved, FareDisputeRejected:
+		return true
+	}
+	return false
+}
+
+// IsActive returns true while the dispute awaits a decision. A trip has at most one active dispute.
+func (s FareDisputeStatus) IsActive() bool {
+	return s == FareDisputeOpen || s == FareDisputeUnderReview
+}
+
+// CanTransitionTo checks if a dispute in this status can move to the given status.
+// Disputes are reviewed before they are resolved; they can be rejected at any point until closed.
+func (s FareDisputeStatus) CanTransitionTo(next FareDisputeStatus) bool {
+	switch s {
+	case FareDisputeOpen:
+		return next == FareDisputeUnderReview || next == FareDisputeRejected
+	case FareDisputeUnderReview:
+		return next == FareDisputeResolved || next == FareDisputeRejected
+	case FareDisputeResolved, FareDisputeRejected:
+		return false // Terminal states
+	default:
+		return false
+	}
+}
+
+// FareDispute is a passenger's challenge of the fare charged for a completed trip.
+// Admins review it and either resolve it with an adjusted fare or reject it.
+type FareDispute struct {
+	ID             uuid.UUID         `json:"id" db:"id"`
+	TripID         uuid.UUID         `json:"trip_id" db:"trip_id"`
+	PassengerID    uuid.UUID         `json:"passenger_id" db:"passenger_id"`
+	Reason         string            `json:"reason" db:"reason"`
+	OriginalFare   float64           `json:"original_fare" db:"original_fare"`
+	RequestedFare  *float64          `json:"requested_fare,omitempty" db:"requested_fare"` // the fare the passenger expected, if given
+	Status         FareDisputeStatus `json:"status" db:"status"`
+	AdjustedFare   *float64          `json:"adjusted_fare,omitempty" db:"adjusted_fare"`
+	ReviewedBy     *string           `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ResolutionNote *string           `json:"resolution_note,omitempty" db:"resolution_note"`
+	ReviewedAt     *time.Time        `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ClosedAt       *time.Time        `json:"closed_at,omitempty" db:"closed_at"`
+	CreatedAt      time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// TableName returns the table name for FareDispute
+func (FareDispute) TableName() string {
+	return "fare_disputes"
+}
+
+// StartReview moves the dispute under review by reviewer
+func (d *FareDispute) StartReview(reviewer string, now time.Time) {
+	d.Status = FareDisputeUnderReview
+	d.ReviewedBy = &reviewer
+	d.ReviewedAt = &now
+	d.UpdatedAt = now
+}
+
+// Resolve closes the dispute with the fare the passenger is charged from now on
+func (d *FareDispute) Resolve(reviewer string, adjustedFare float64, note string, now time.Time) {
+	d.close(FareDisputeResolved, reviewer, note, now)
+	d.AdjustedFare = &adjustedFare
+}
+
+// Reject closes the dispute leaving the fare unchanged
+func (d *FareDispute) Reject(reviewer, note string, now time.Time) {
+	d.close(FareDisputeRejected, reviewer, note, now)
+}
+
+func (d *FareDispute) close(status FareDisputeStatus, reviewer, note string, now time.Time) {
+	d.Status = status
+	d.ReviewedBy = &reviewer
+	if note != "" {
+		d.ResolutionNote = &note
+	}
+	d.ClosedAt = &now
+	d.UpdatedAt = now
+}
+
+// Validate validates the dispute data
+func (d *FareDispute) Validate() error {
+	if d.TripID == uuid.Nil {
+		return ErrInvalidTripID
+	}
+	if d.PassengerID == uuid.Nil {
+		return ErrInvalidPassengerID
+	}
+	if d.Reason == "" || len(d.Reason) > maxDisputeTextLength {
+		return ErrInvalidDisputeReason
+	}
+	if d.RequestedFare != nil && *d.RequestedFare < 0 {
+		return ErrInvalidFareAmount
+	}
+	if d.ResolutionNote != nil && len(*d.ResolutionNote) > maxDisputeTextLength {
+		return ErrInvalidDisputeNote
+	}
+	if !d.Status.IsValid() {
+		return ErrInvalidDisputeStatus
+	}
+	return nil
+}
+
+// FareAdjustment is an entry of a trip's fare adjustments ledger. Entries are never changed;
+// the trip's current fare is its original fare plus the sum of its adjustment amounts.
+type FareAdjustment struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	TripID       uuid.UUID `json:"trip_id" db:"trip_id"`
+	DisputeID    uuid.UUID `json:"dispute_id" db:"dispute_id"`
+	PreviousFare float64   `json:"previous_fare" db:"previous_fare"`
+	NewFare      float64   `json:"new_fare" db:"new_fare"`
+	Amount       float64   `json:"amount" db:"amount"` // NewFare - PreviousFare; negative for refunds
+	Reason       string    `json:"reason" db:"reason"`
+	AdjustedBy   string    `json:"adjusted_by" db:"adjusted_by"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+}
+
+// TableName returns the table name for FareAdjustment
+func (FareAdjustment) TableName() string {
+	return "fare_adjustments"
+}
+
+// FareDisputeFilter selects disputes for the admin review queue; zero fields match everything
+type FareDisputeFilter struct {
+	TripID *uuid.UUID
+	Status FareDisputeStatus
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"time"
+
+	"actor-model-observability/internal/reporting"
+
+	"github.com/google/uuid"
+)
+
+// maxFleetNameLength caps a fleet's name
+const maxFleetNameLength = 255
+
+// Fleet is a fleet partner operating a group of drivers. The operator authenticates with an
+// API key, of which only the SHA-256 hash is stored, and only sees its own drivers and trips.
+// Timezone is the IANA timezone its daily reports are grouped in.
+type Fleet struct {
+	ID                uuid.UUID `json:"id" db:"id"`
+	Name              string    `json:"name" db:"name"`
+	APIKeyHash        string    `json:"-" db:"api_key_hash"`
+	Timezone          string    `json:"timezone" db:"timezone"`
+	DailyRequestQuota *int64    `json:"daily_request_quota" db:"daily_request_quota"` // requests per UTC day with the API key; the default quota when nil
+	CreatedAt         time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TableName returns the table name for Fleet
+func (Fleet) TableName() string {
+	return "fleets"
+}
+
+// Validate validates the fleet data
+func (f *Fleet) Validate() error {
+	if f.Name == "" || len(f.Name) > maxFleetNameLength {
+		return ErrInvalidFleetName
+	}
+	if _, err := reporting.LoadLocation(f.Timezone); err != nil {
+		return ErrInvalidFleetTimezone
+	}
+	if f.DailyRequestQuota != nil && *f.DailyRequestQuota <= 0 {
+		return ErrInvalidFleetRequestQuota
+	}
+	return nil
+}
+
+// APIKey returns the fleet's API key as usage is recorded and quotas enforced for it
+func (f *Fleet) APIKey() APIKeyRef {
+	return APIKeyRef{
+		KeyID:             APIKeyID(f.APIKeyHash),
+		TenantID:          f.ID,
+		DailyRequestQuota: f.DailyRequestQuota,
+	}
+}
+
+// FleetStatusImportRow is the outcome of one row of a bulk driver status import. Rows are
+// numbered from 1, not counting the header.
+type FleetStatusImportRow struct {
+	Row      int          `json:"row"`
+	DriverID string       `json:"driver_id"`
+	Status   DriverStatus `json:"status"`
+	Error    string       `json:"error,omitempty"`
+}
+
+// FleetStatusImportResult summarises a bulk driver status import. Valid rows are applied even
+// when others fail.
+type FleetStatusImportResult struct {
+	Updated int                    `json:"updated"`
+	Failed  int                    `json:"failed"`
+	Rows    []FleetStatusImportRow `json:"rows"`
+}
+
+// FleetDriverEarnings is a fleet driver's completed trips and fares over an export period, or
+// over one local date of it when Date is set
+type FleetDriverEarnings struct {
+	Date           string    `json:"date,omitempty"`
+	DriverID       uuid.UUID `json:"driver_id"`
+	VehiclePlate   string    `json:"vehicle_plate"`
+	TripsCompleted int       `json:"trips_completed"`
+	TripsCancelled int       `json:"trips_cancelled"`
+	Earnings       float64   `json:"earnings"`
+}
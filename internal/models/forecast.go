@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// GeoBounds is a latitude/longitude bounding box; points on the south and west edges are inside
+type GeoBounds struct {
+	MinLat float64 `json:"min_lat"`
+	MaxLat float64 `json:"max_lat"`
+	MinLng float64 `json:"min_lng"`
+	MaxLng float64 `json:"max_lng"`
+}
+
+// DemandForecast is the forecast hourly ride demand of a zone
+type DemandForecast struct {
+	Zone         string                  `json:"zone,omitempty"` // geohash; empty for all zones
+	Bounds       *GeoBounds              `json:"bounds,omitempty"`
+	Method       string                  `json:"method"`
+	Timezone     string                  `json:"timezone"`
+	Interval     string                  `json:"interval"`
+	Horizon      int                     `json:"horizon"` // hours forecast
+	HistoryStart time.Time               `json:"history_start"`
+	HistoryEnd   time.Time               `json:"history_end"`
+	HistoryTrips int                     `json:"history_trips"`
+	Points       []DemandForecastPoint   `json:"points"`
+	Daily        []DemandForecastDay     `json:"daily"`
+	Backtest     *DemandForecastBacktest `json:"backtest,omitempty"`
+	GeneratedAt  time.Time               `json:"generated_at"`
+}
+
+// DemandForecastPoint is the forecast number of ride requests in the hour starting at Timestamp
+type DemandForecastPoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Demand    float64   `json:"demand"`
+}
+
+// DemandForecastDay is the forecast demand of the forecast hours falling on a local date; the
+// first and last dates are usually partial, and a full day has 23 or 25 hours when the clocks change
+type DemandForecastDay struct {
+	Date   string  `json:"date"`
+	Demand float64 `json:"demand"`
+	Hours  int     `json:"hours"`
+}
+
+// DemandForecastBacktest reports the accuracy of the method when forecasting the last Horizon
+// hours of history from the hours before them
+type DemandForecastBacktest struct {
+	Horizon int      `json:"horizon"`
+	MAE     float64  `json:"mae"`
+	RMSE    float64  `json:"rmse"`
+	MAPE    *float64 `json:"mape,omitempty"` // percent; omitted when no demand occurred in the held out hours
+}
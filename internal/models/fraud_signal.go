@@ -0,0 +1,143 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// FraudRule identifies the fraud detection rule that raised a signal
+type FraudRule string
+
+const (
+	// FraudRuleImpossibleSpeed flags trips completed faster than a car can drive their distance
+	FraudRuleImpossibleSpeed FraudRule = "impossible_speed"
+	// FraudRuleRepeatedCancellations flags riders and drivers cancelling trips after the match
+	// again and again
+	FraudRuleRepeatedCancellations FraudRule = "repeated_cancellations"
+	// FraudRuleGPSTeleport flags drivers whose reported location jumps further than they could
+	// have driven since their previous ping
+	FraudRuleGPSTeleport FraudRule = "gps_teleport"
+	// FraudRuleSelfReferral flags drivers driving themselves, from the same account or phone, or
+	// the same passenger over and over
+	FraudRuleSelfReferral FraudRule = "self_referral"
+)
+
+// IsValid returns true if the rule is supported
+func (r FraudRule) IsValid() bool {
+	switch r {
+	case FraudRuleImpossibleSpeed, FraudRuleRepeatedCancellations, FraudRuleGPSTeleport, FraudRuleSelfReferral:
+		return true
+	}
+	return false
+}
+
+// FraudSeverity represents how likely a fraud signal is to be actual fraud
+type FraudSeverity string
+
+const (
+	FraudSeverityLow    FraudSeverity = "low"
+	FraudSeverityMedium FraudSeverity = "medium"
+	FraudSeverityHigh   FraudSeverity = "high"
+)
+
+// IsValid returns true if the severity is supported
+func (s FraudSeverity) IsValid() bool {
+	switch s {
+	case FraudSeverityLow, FraudSeverityMedium, FraudSeverityHigh:
+		return true
+	}
+	return false
+}
+
+// FraudSignalStatus represents the review status of a fraud signal
+type FraudSignalStatus string
+
+const (
+	FraudSignalOpen      FraudSignalStatus = "open"
+	FraudSignalConfirmed FraudSignalStatus = "confirmed"
+	FraudSignalDismissed FraudSignalStatus = "dismissed"
+)
+
+// maxFraudReviewNoteLength caps the reviewer's note
+const maxFraudReviewNoteLength = 1000
+
+// IsValid returns true if the status is supported
+func (s FraudSignalStatus) IsValid() bool {
+	switch s {
+	case FraudSignalOpen, FraudSignalConfirmed, FraudSignalDismissed:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo checks if a signal in this status can move to the given status. Open signals
+// are confirmed or dismissed once; both decisions are final.
+func (s FraudSignalStatus) CanTransitionTo(next FraudSignalStatus) bool {
+	return s == FraudSignalOpen && (next == FraudSignalConfirmed || next == FraudSignalDismissed)
+}
+
+// FraudSignal is a suspicion of fraud raised by a detection rule against a driver or passenger,
+// with the evidence the rule found, awaiting review by an admin
+type FraudSignal struct {
+	ID          uuid.UUID         `json:"id" db:"id"`
+	Rule        FraudRule         `json:"rule" db:"rule"`
+	Severity    FraudSeverity     `json:"severity" db:"severity"`
+	SubjectType UserType          `json:"subject_type" db:"subject_type"` // driver or passenger
+	SubjectID   uuid.UUID         `json:"subject_id" db:"subject_id"`     // driver or passenger ID
+	TripID      *uuid.UUID        `json:"trip_id,omitempty" db:"trip_id"`
+	Details     json.RawMessage   `json:"details,omitempty" db:"details" swaggertype:"object"`
+	Status      FraudSignalStatus `json:"status" db:"status"`
+	ReviewedBy  *string           `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewNote  *string           `json:"review_note,omitempty" db:"review_note"`
+	ReviewedAt  *time.Time        `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt   time.Time         `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at" db:"updated_at"`
+}
+
+// TableName returns the table name for FraudSignal
+func (FraudSignal) TableName() string {
+	return "fraud_signals"
+}
+
+// Review records the reviewer's decision on the signal
+func (f *FraudSignal) Review(decision FraudSignalStatus, reviewer, note string, now time.Time) {
+	f.Status = decision
+	f.ReviewedBy = &reviewer
+	if note != "" {
+		f.ReviewNote = &note
+	}
+	f.ReviewedAt = &now
+	f.UpdatedAt = now
+}
+
+// Validate validates the signal data
+func (f *FraudSignal) Validate() error {
+	if !f.Rule.IsValid() {
+		return ErrInvalidFraudRule
+	}
+	if !f.Severity.IsValid() {
+		return ErrInvalidFraudSeverity
+	}
+	if f.SubjectType != UserTypePassenger && f.SubjectType != UserTypeDriver {
+		return ErrInvalidUserType
+	}
+	if !f.Status.IsValid() {
+		return ErrInvalidFraudSignalStatus
+	}
+	if f.ReviewNote != nil && len(*f.ReviewNote) > maxFraudReviewNoteLength {
+		return ErrInvalidFraudReviewNote
+	}
+	return nil
+}
+
+// FraudSignalFilter selects signals for the admin review queue; zero fields match everything
+type FraudSignalFilter struct {
+	Rule      FraudRule
+	Severity  FraudSeverity
+	Status    FraudSignalStatus
+	SubjectID *uuid.UUID
+	Limit     int
+	Offset    int
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Heatmap is the number of trip pickups and dropoffs in each geohash cell over a period
+type Heatmap struct {
+	Bucket      string        `json:"bucket"` // e.g. geohash5
+	Start       time.Time     `json:"start"`
+	End         time.Time     `json:"end"`
+	Pickups     int64         `json:"pickups"`
+	Dropoffs    int64         `json:"dropoffs"`
+	Cells       []HeatmapCell `json:"cells"`
+	GeneratedAt time.Time     `json:"generated_at"`
+}
+
+// HeatmapCell is the number of pickups and dropoffs in one geohash cell
+type HeatmapCell struct {
+	Geohash   string    `json:"geohash"`
+	Bounds    GeoBounds `json:"bounds"`
+	CenterLat float64   `json:"center_lat"`
+	CenterLng float64   `json:"center_lng"`
+	Pickups   int64     `json:"pickups"`
+	Dropoffs  int64     `json:"dropoffs"`
+}
+
+// TripGridCount is the number of pickups and dropoffs in a cell of a regular latitude/longitude
+// grid; Row and Col count cells from the south-west corner of the world (-90, -180)
+type TripGridCount struct {
+	Row      int   `db:"grid_row"`
+	Col      int   `db:"grid_col"`
+	Pickups  int64 `db:"pickups"`
+	Dropoffs int64 `db:"dropoffs"`
+}
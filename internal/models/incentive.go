@@ -0,0 +1,105 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxCampaignNameLength caps an incentive campaign's name
+const maxCampaignNameLength = 255
+
+// IncentiveCampaign is a driver quest: complete RequiredTrips trips between StartsAt and EndsAt
+// to earn BonusAmount. Every driver works towards the quest independently.
+type IncentiveCampaign struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	Name          string    `json:"name" db:"name"`
+	Description   *string   `json:"description,omitempty" db:"description"`
+	RequiredTrips int       `json:"required_trips" db:"required_trips"`
+	BonusAmount   float64   `json:"bonus_amount" db:"bonus_amount"`
+	StartsAt      time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt        time.Time `json:"ends_at" db:"ends_at"`
+	Active        bool      `json:"active" db:"active"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// TableName returns the table name for IncentiveCampaign
+func (IncentiveCampaign) TableName() string {
+	return "incentive_campaigns"
+}
+
+// IsRunning returns true if the campaign is active and trips completed at t count towards it
+func (c *IncentiveCampaign) IsRunning(t time.Time) bool {
+	return c.Active && !t.Before(c.StartsAt) && t.Before(c.EndsAt)
+}
+
+// Validate validates the campaign data
+func (c *IncentiveCampaign) Validate() error {
+	if c.Name == "" || len(c.Name) > maxCampaignNameLength {
+		return ErrInvalidCampaignName
+	}
+	if c.RequiredTrips <= 0 {
+		return ErrInvalidRequiredTrips
+	}
+	if c.BonusAmount <= 0 {
+		return ErrInvalidBonusAmount
+	}
+	if !c.EndsAt.After(c.StartsAt) {
+		return ErrInvalidCampaignWindow
+	}
+	return nil
+}
+
+// IncentiveTripCredit records that a completed trip counted towards a driver's progress on a
+// campaign, so replayed completion events are never counted twice
+type IncentiveTripCredit struct {
+	CampaignID  uuid.UUID `json:"campaign_id" db:"campaign_id"`
+	TripID      uuid.UUID `json:"trip_id" db:"trip_id"`
+	DriverID    uuid.UUID `json:"driver_id" db:"driver_id"`
+	CompletedAt time.Time `json:"completed_at" db:"completed_at"`
+}
+
+// TableName returns the table name for IncentiveTripCredit
+func (IncentiveTripCredit) TableName() string {
+	return "incentive_trip_credits"
+}
+
+// QuestProgress is a driver's progress towards a campaign
+type QuestProgress struct {
+	CampaignID     uuid.UUID  `json:"campaign_id" db:"campaign_id"`
+	DriverID       uuid.UUID  `json:"driver_id" db:"driver_id"`
+	CompletedTrips int        `json:"completed_trips" db:"completed_trips"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty" db:"completed_at"` // when the quest was completed and paid out
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// TableName returns the table name for QuestProgress
+func (QuestProgress) TableName() string {
+	return "incentive_progress"
+}
+
+// IncentivePayout is the bonus owed to a driver for completing a campaign. A driver is paid
+// at most once per campaign.
+type IncentivePayout struct {
+	ID         uuid.UUID `json:"id" db:"id"`
+	CampaignID uuid.UUID `json:"campaign_id" db:"campaign_id"`
+	DriverID   uuid.UUID `json:"driver_id" db:"driver_id"`
+	Amount     float64   `json:"amount" db:"amount"`
+	TripCount  int       `json:"trip_count" db:"trip_count"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// TableName returns the table name for IncentivePayout
+func (IncentivePayout) TableName() string {
+	return "incentive_payouts"
+}
+
+// DriverQuest is a running campaign together with a driver's progress towards it
+type DriverQuest struct {
+	Campaign       *IncentiveCampaign `json:"campaign"`
+	CompletedTrips int                `json:"completed_trips"`
+	RemainingTrips int                `json:"remaining_trips"`
+	Completed      bool               `json:"completed"`
+	Payout         *IncentivePayout   `json:"payout,omitempty"`
+}
@@ -0,0 +1,135 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IntegrityTables are the observability tables whose rows are checksummed per hour
+var IntegrityTables = []string{
+	"event_logs",
+	"actor_messages",
+	"system_metrics",
+	"distributed_traces",
+	"traditional_logs",
+	"traditional_metrics",
+}
+
+// IntegrityRun is a periodic snapshot of the row counts and checksums of the observability
+// tables per hour, over the finished hours of its window
+type IntegrityRun struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	WindowStart time.Time `json:"window_start" db:"window_start"`
+	WindowEnd   time.Time `json:"window_end" db:"window_end"`
+	TotalRows   int64     `json:"total_rows" db:"total_rows"` // rows of every table in the window
+	StartedAt   time.Time `json:"started_at" db:"started_at"`
+	DurationMs  int64     `json:"duration_ms" db:"duration_ms"`
+}
+
+// IntegrityChecksum is the row count and checksum of an observability table's rows in an hour.
+// The checksum is the sum of a hash of every row ID, so it does not depend on the order of the
+// rows and does not change when rows are updated, only when rows are added or removed.
+type IntegrityChecksum struct {
+	RunID     uuid.UUID `json:"-" db:"run_id"`
+	TableName string    `json:"table" db:"table_name"`
+	Hour      time.Time `json:"hour" db:"hour"`
+	RowCount  int64     `json:"row_count" db:"row_count"`
+	Checksum  int64     `json:"checksum" db:"checksum"`
+}
+
+// IntegrityDifferenceKind is how an hour of a table changed between two runs
+type IntegrityDifferenceKind string
+
+const (
+	// IntegrityRowsMissing is an hour with fewer rows than before: rows were lost or deleted
+	IntegrityRowsMissing IntegrityDifferenceKind = "rows_missing"
+	// IntegrityRowsAdded is an hour with more rows than before, written late, e.g. by a flushed buffer
+	IntegrityRowsAdded IntegrityDifferenceKind = "rows_added"
+	// IntegrityRowsReplaced is an hour with as many rows as before but other row IDs
+	IntegrityRowsReplaced IntegrityDifferenceKind = "rows_replaced"
+)
+
+// IntegrityDifference is an hour of a table whose rows changed between two runs
+type IntegrityDifference struct {
+	Table         string                  `json:"table"`
+	Hour          time.Time               `json:"hour"`
+	Kind          IntegrityDifferenceKind `json:"kind"`
+	PreviousCount int64                   `json:"previous_count"`
+	CurrentCount  int64                   `json:"current_count"`
+}
+
+// IntegrityComparison compares a run with the run before it over the hours both windows cover
+type IntegrityComparison struct {
+	Previous      *IntegrityRun          `json:"previous"`
+	Current       *IntegrityRun          `json:"current"`
+	HoursCompared int                    `json:"hours_compared"` // table hours in both windows
+	RowsMissing   int64                  `json:"rows_missing"`   // rows counted before but no longer
+	Differences   []*IntegrityDifference `json:"differences"`    // by table, then hour
+}
+
+// CompareIntegrityChecksums compares the checksums of two runs over the hours both windows
+// cover. Hours without a checksum had no rows.
+func CompareIntegrityChecksums(previous, current *IntegrityRun, previousSums, currentSums []*IntegrityChecksum) *IntegrityComparison {
+	comparison := &IntegrityComparison{
+		Previous:    previous,
+		Current:     current,
+		Differences: []*IntegrityDifference{},
+	}
+
+	start, end := previous.WindowStart, previous.WindowEnd
+	if current.WindowStart.After(start) {
+		start = current.WindowStart
+	}
+	if current.WindowEnd.Before(end) {
+		end = current.WindowEnd
+	}
+
+	type tableHour struct {
+		table string
+		hour  int64
+	}
+	index := func(sums []*IntegrityChecksum) map[tableHour]*IntegrityChecksum {
+		byHour := make(map[tableHour]*IntegrityChecksum, len(sums))
+		for _, sum := range sums {
+			byHour[tableHour{sum.TableName, sum.Hour.Unix()}] = sum
+		}
+		return byHour
+	}
+	before, after := index(previousSums), index(currentSums)
+
+	for _, table := range IntegrityTables {
+		for hour := start; hour.Before(end); hour = hour.Add(time.Hour) {
+			comparison.HoursCompared++
+			var was, is IntegrityChecksum
+			if sum, ok := before[tableHour{table, hour.Unix()}]; ok {
+				was = *sum
+			}
+			if sum, ok := after[tableHour{table, hour.Unix()}]; ok {
+				is = *sum
+			}
+
+			var kind IntegrityDifferenceKind
+			switch {
+			case is.RowCount < was.RowCount:
+				kind = IntegrityRowsMissing
+				comparison.RowsMissing += was.RowCount - is.RowCount
+			case is.RowCount > was.RowCount:
+				kind = IntegrityRowsAdded
+			case is.Checksum != was.Checksum:
+				kind = IntegrityRowsReplaced
+			default:
+				continue
+			}
+			comparison.Differences = append(comparison.Differences, &IntegrityDifference{
+				Table:         table,
+				Hour:          hour.UTC(),
+				Kind:          kind,
+				PreviousCount: was.RowCount,
+				CurrentCount:  is.RowCount,
+			})
+		}
+	}
+
+	return comparison
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// InvoicePartyType is who an invoice is issued to
+type InvoicePartyType string
+
+const (
+	// InvoicePartyCorporateAccount invoices a corporate account for the rides billed to it
+	InvoicePartyCorporateAccount InvoicePartyType = "corporate_account"
+	// InvoicePartyFleet is a fleet partner's statement of its drivers' earnings
+	InvoicePartyFleet InvoicePartyType = "fleet"
+)
+
+// IsValid returns true if the party type is supported
+func (t InvoicePartyType) IsValid() bool {
+	switch t {
+	case InvoicePartyCorporateAccount, InvoicePartyFleet:
+		return true
+	}
+	return false
+}
+
+// InvoiceStatus represents the generation status of an invoice
+type InvoiceStatus string
+
+const (
+	InvoicePending   InvoiceStatus = "pending"
+	InvoiceGenerated InvoiceStatus = "generated"
+	InvoiceFailed    InvoiceStatus = "failed"
+)
+
+// IsValid returns true if the status is supported
+func (s InvoiceStatus) IsValid() bool {
+	switch s {
+	case InvoicePending, InvoiceGenerated, InvoiceFailed:
+		return true
+	}
+	return false
+}
+
+// InvoiceFormat is a file format invoices are exported in
+type InvoiceFormat string
+
+const (
+	InvoiceFormatCSV InvoiceFormat = "csv"
+	InvoiceFormatPDF InvoiceFormat = "pdf"
+)
+
+// Invoice is the monthly invoice of a corporate account or fleet partner, one per party and
+// calendar month (UTC). Its files are stored under CSVKey and PDFKey once generated; a failed
+// invoice is generated again until its attempts are exhausted. CSVURL and PDFURL are signed
+// download URLs added when an invoice is returned.
+type Invoice struct {
+	ID          uuid.UUID        `json:"id" db:"id"`
+	PartyType   InvoicePartyType `json:"party_type" db:"party_type"`
+	PartyID     uuid.UUID        `json:"party_id" db:"party_id"`
+	PartyName   string           `json:"party_name" db:"party_name"`
+	Month       string           `json:"month" db:"month"`
+	Status      InvoiceStatus    `json:"status" db:"status"`
+	Attempts    int              `json:"attempts" db:"attempts"`
+	LastError   *string          `json:"last_error,omitempty" db:"last_error"`
+	Trips       int              `json:"trips" db:"trips"`
+	TotalAmount float64          `json:"total_amount" db:"total_amount"`
+	CSVKey      *string          `json:"-" db:"csv_key"`
+	PDFKey      *string          `json:"-" db:"pdf_key"`
+	CSVURL      string           `json:"csv_url,omitempty" db:"-"`
+	PDFURL      string           `json:"pdf_url,omitempty" db:"-"`
+	GeneratedAt *time.Time       `json:"generated_at,omitempty" db:"generated_at"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// TableName returns the table name for Invoice
+func (Invoice) TableName() string {
+	return "invoices"
+}
+
+// InvoiceFilter selects invoices for the admin listing; zero fields match everything
+type InvoiceFilter struct {
+	Month     string
+	PartyType InvoicePartyType
+	Status    InvoiceStatus
+	Limit     int
+	Offset    int
+}
+
+// InvoiceRun summarises a batch generating the invoices of a month. Invoices already generated,
+// and failed ones whose attempts are exhausted, are skipped.
+type InvoiceRun struct {
+	Month     string     `json:"month"`
+	Generated int        `json:"generated"`
+	Failed    int        `json:"failed"`
+	Skipped   int        `json:"skipped"`
+	Invoices  []*Invoice `json:"invoices"`
+}
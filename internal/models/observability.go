@@ -1,9 +1,13 @@
 package models
 
 import (
+	"database/sql/driver"
 	"encoding/json"
+	"fmt"
 	"time"
 
+	"actor-model-observability/internal/hlc"
+
 	"github.com/google/uuid"
 )
 
@@ -27,11 +31,32 @@ const (
 	ActorStatusError    ActorStatus = "error"
 )
 
+// Business entity types that actor instances, actor messages and event logs are linked to
+const (
+	EntityTypeTrip      = "trip"
+	EntityTypeDriver    = "driver"
+	EntityTypePassenger = "passenger"
+)
+
+// EntityLink converts a business entity reference into the nullable entity columns of
+// observability records. Both are nil when the entity type is empty or the ID isn't a UUID.
+func EntityLink(entityType, entityID string) (*string, *uuid.UUID) {
+	if entityType == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(entityID)
+	if err != nil {
+		return nil, nil
+	}
+	return &entityType, &id
+}
+
 // ActorInstance represents an actor instance in the system
 type ActorInstance struct {
 	ID            uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
 	ActorType     ActorType   `json:"actor_type" gorm:"not null;check:actor_type IN ('passenger', 'driver', 'trip', 'matching', 'observability')"`
 	ActorID       string      `json:"actor_id" gorm:"not null"`
+	EntityType    *string     `json:"entity_type"`
 	EntityID      *uuid.UUID  `json:"entity_id" gorm:"type:uuid"`
 	Status        ActorStatus `json:"status" gorm:"default:'active';check:status IN ('active', 'inactive', 'error')"`
 	LastHeartbeat time.Time   `json:"last_heartbeat" gorm:"default:CURRENT_TIMESTAMP"`
@@ -52,6 +77,8 @@ const (
 	MessageStatusReceived  MessageStatus = "received"
 	MessageStatusProcessed MessageStatus = "processed"
 	MessageStatusFailed    MessageStatus = "failed"
+	// MessageStatusDeadLettered marks a message dropped after its retries were exhausted
+	MessageStatusDeadLettered MessageStatus = "dead_lettered"
 )
 
 // ActorMessage represents a message between actors
@@ -64,22 +91,90 @@ type ActorMessage struct {
 	SenderActorID        string          `json:"sender_actor_id" gorm:"not null"`
 	ReceiverActorType    ActorType       `json:"receiver_actor_type" gorm:"not null"`
 	ReceiverActorID      string          `json:"receiver_actor_id" gorm:"not null"`
+	EntityType           *string         `json:"entity_type"`
+	EntityID             *uuid.UUID      `json:"entity_id" gorm:"type:uuid"`
 	MessageType          string          `json:"message_type" gorm:"not null"`
 	MessagePayload       json.RawMessage `json:"message_payload" gorm:"type:jsonb" swaggertype:"object"`
-	Status               MessageStatus   `json:"status" gorm:"default:'sent';check:status IN ('sent', 'received', 'processed', 'failed')"`
+	MessageVersion       int             `json:"message_version" gorm:"not null;default:1"`
+	Status               MessageStatus   `json:"status" gorm:"default:'sent';check:status IN ('sent', 'received', 'processed', 'failed', 'dead_lettered')"`
 	SentAt               time.Time       `json:"sent_at" gorm:"default:CURRENT_TIMESTAMP"`
 	ReceivedAt           *time.Time      `json:"received_at"`
 	ProcessedAt          *time.Time      `json:"processed_at"`
 	ProcessingDurationMs *int            `json:"processing_duration_ms"`
 	ErrorMessage         *string         `json:"error_message"`
+	RetryCount           int             `json:"retry_count" gorm:"default:0"`
+	Effects              MessageEffects  `json:"effects" gorm:"type:jsonb" swaggertype:"array,object"` // changes made while processing
+	HLC                  *hlc.Timestamp  `json:"hlc,omitempty"`                                        // hybrid timestamp of sending the message
 	CreatedAt            time.Time       `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
 }
 
+// MessageEffect is a change to a business entity made while an actor processed a message,
+// such as a trip status change
+type MessageEffect struct {
+	EntityType string                 `json:"entity_type"`
+	EntityID   string                 `json:"entity_id"`
+	Action     string                 `json:"action"`            // e.g. status_changed
+	Changes    map[string]interface{} `json:"changes,omitempty"` // new values of the changed fields
+}
+
+// MessageEffects is a list of message effects, stored as a JSON array
+type MessageEffects []MessageEffect
+
+// Value implements driver.Valuer; an empty list is stored as NULL
+func (e MessageEffects) Value() (driver.Value, error) {
+	if len(e) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(e)
+}
+
+// Scan implements sql.Scanner
+func (e *MessageEffects) Scan(src interface{}) error {
+	var value []byte
+	switch v := src.(type) {
+	case nil:
+		*e = nil
+		return nil
+	case string:
+		value = []byte(v)
+	case []byte:
+		value = v
+	default:
+		return fmt.Errorf("cannot scan %T into MessageEffects", src)
+	}
+	return json.Unmarshal(value, e)
+}
+
 // TableName returns the table name for ActorMessage
 func (ActorMessage) TableName() string {
 	return "actor_messages"
 }
 
+// MessageStats aggregates the actor messages of one message type received by one actor type
+type MessageStats struct {
+	MessageType  string    `json:"message_type" db:"message_type"`
+	ActorType    ActorType `json:"actor_type" db:"actor_type"` // type of the receiving actor
+	Total        int64     `json:"total" db:"total"`
+	Failed       int64     `json:"failed" db:"failed"`               // failed or dead lettered
+	FailureRatio float64   `json:"failure_ratio" db:"failure_ratio"` // failed / total
+	// Processing duration percentiles in milliseconds, nil when no message recorded a duration
+	P50ProcessingMs *float64 `json:"p50_processing_duration_ms" db:"p50_processing_duration_ms"`
+	P95ProcessingMs *float64 `json:"p95_processing_duration_ms" db:"p95_processing_duration_ms"`
+	P99ProcessingMs *float64 `json:"p99_processing_duration_ms" db:"p99_processing_duration_ms"`
+}
+
+// SlowQuery is a repository call that took at least the slow query threshold
+type SlowQuery struct {
+	Repository string            `json:"repository"`
+	Method     string            `json:"method"`
+	Operation  string            `json:"operation"` // SELECT, INSERT, UPDATE or DELETE
+	Params     map[string]string `json:"params"`    // sanitized call arguments
+	DurationMs float64           `json:"duration_ms"`
+	Error      string            `json:"error,omitempty"`
+	TraceID    string            `json:"trace_id,omitempty"`
+	At         time.Time         `json:"at"`
+}
+
 // MetricType represents the type of metric
 type MetricType string
 
@@ -175,6 +270,7 @@ type EventLog struct {
 	Severity      EventSeverity   `json:"severity" gorm:"default:'info';check:severity IN ('debug', 'info', 'warn', 'error', 'fatal')"`
 	Message       string          `json:"message"`
 	Timestamp     time.Time       `json:"timestamp" gorm:"default:CURRENT_TIMESTAMP;index"`
+	HLC           *hlc.Timestamp  `json:"hlc,omitempty"` // hybrid timestamp of publishing the event
 	CreatedAt     time.Time       `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
 }
 
@@ -183,6 +279,30 @@ func (EventLog) TableName() string {
 	return "event_logs"
 }
 
+// HybridTimestamp returns the hybrid timestamp of publishing the event, nil when not stamped
+func (el *EventLog) HybridTimestamp() *hlc.Timestamp { return el.HLC }
+
+// SetHybridTimestamp stamps the event with the hybrid timestamp of publishing it
+func (el *EventLog) SetHybridTimestamp(ts hlc.Timestamp) { el.HLC = &ts }
+
+// SourceTime returns when the event happened by its source's wall clock, and the source: the
+// actor that recorded it, or its event type when no actor did
+func (el *EventLog) SourceTime() (time.Time, string) {
+	if el.ActorID != nil {
+		return el.Timestamp, *el.ActorID
+	}
+	return el.Timestamp, el.EventType
+}
+
+// HybridTimestamp returns the hybrid timestamp of sending the message, nil when not stamped
+func (am *ActorMessage) HybridTimestamp() *hlc.Timestamp { return am.HLC }
+
+// SetHybridTimestamp stamps the message with the hybrid timestamp of sending it
+func (am *ActorMessage) SetHybridTimestamp(ts hlc.Timestamp) { am.HLC = &ts }
+
+// SourceTime returns when and by which actor the message was sent, by the sender's wall clock
+func (am *ActorMessage) SourceTime() (time.Time, string) { return am.SentAt, am.SenderActorID }
+
 // Helper methods for ActorMessage
 
 // MarkReceived marks the message as received
@@ -201,6 +321,16 @@ func (am *ActorMessage) MarkProcessed(processingDuration time.Duration) {
 	am.ProcessingDurationMs = &durationMs
 }
 
+// Affects reports whether processing the message changed the entity
+func (am *ActorMessage) Affects(entityType, entityID string) bool {
+	for _, effect := range am.Effects {
+		if effect.EntityType == entityType && effect.EntityID == entityID {
+			return true
+		}
+	}
+	return false
+}
+
 // MarkFailed marks the message as failed
 func (am *ActorMessage) MarkFailed(errorMsg string) {
 	am.Status = MessageStatusFailed
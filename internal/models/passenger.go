@@ -8,13 +8,37 @@ import (
 
 // Passenger represents a passenger in the system
 type Passenger struct {
-	ID         uuid.UUID `json:"id" db:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	UserID     uuid.UUID `json:"user_id" db:"user_id" gorm:"type:uuid;not null;index"`
-	User       *User     `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
-	Rating     float64   `json:"rating" db:"rating" gorm:"type:decimal(3,2);default:5.00"`
-	TotalTrips int       `json:"total_trips" db:"total_trips" gorm:"default:0"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+	ID                 uuid.UUID                 `json:"id" db:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID             uuid.UUID                 `json:"user_id" db:"user_id" gorm:"type:uuid;not null;index"`
+	User               *User                     `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Rating             float64                   `json:"rating" db:"rating" gorm:"type:decimal(3,2);default:5.00"`
+	TotalTrips         int                       `json:"total_trips" db:"total_trips" gorm:"default:0"`
+	CorporateAccountID *uuid.UUID                `json:"corporate_account_id,omitempty" db:"corporate_account_id" gorm:"type:uuid;index"`
+	RidePreferences    `json:"ride_preferences"` // applied to the passenger's ride requests unless overridden
+	CreatedAt          time.Time                 `json:"created_at" db:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt          time.Time                 `json:"updated_at" db:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// RidePreferences are a passenger's preferences for the driver matched with a ride. Accessible
+// vehicle and pet friendly are hard constraints: only drivers meeting them are matched. The
+// minimum driver rating and quiet ride are soft constraints, relaxed when no nearby driver meets
+// them.
+type RidePreferences struct {
+	QuietRide         bool     `json:"quiet_ride" db:"quiet_ride" gorm:"default:false"`
+	PetFriendly       bool     `json:"pet_friendly" db:"pet_friendly" gorm:"default:false"`
+	AccessibleVehicle bool     `json:"accessible_vehicle" db:"accessible_vehicle" gorm:"default:false"`
+	MinDriverRating   *float64 `json:"min_driver_rating,omitempty" db:"min_driver_rating" gorm:"type:decimal(3,2)"`
+}
+
+// Validate validates the ride preferences
+func (p RidePreferences) Validate() error {
+	if p.MinDriverRating != nil && (*p.MinDriverRating < 1 || *p.MinDriverRating > 5) {
+		return &ValidationError{
+			Field:   "min_driver_rating",
+			Message: "must be between 1 and 5",
+		}
+	}
+	return nil
 }
 
 // TableName returns the table name for Passenger
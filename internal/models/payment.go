@@ -0,0 +1,264 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PaymentKind represents the direction of a trip payment
+type PaymentKind string
+
+const (
+	PaymentKindCharge PaymentKind = "charge" // the passenger paying for the trip
+	PaymentKindRefund PaymentKind = "refund" // money given back to the passenger
+)
+
+// IsValid returns true if the kind is supported
+func (k PaymentKind) IsValid() bool {
+	return k == PaymentKindCharge || k == PaymentKindRefund
+}
+
+// PaymentMethod represents how a trip payment was made
+type PaymentMethod string
+
+const (
+	PaymentMethodCard      PaymentMethod = "card"
+	PaymentMethodCash      PaymentMethod = "cash"
+	PaymentMethodWallet    PaymentMethod = "wallet"
+	PaymentMethodCorporate PaymentMethod = "corporate"
+)
+
+// IsValid returns true if the method is supported
+func (m PaymentMethod) IsValid() bool {
+	switch m {
+	case PaymentMethodCard, PaymentMethodCash, PaymentMethodWallet, PaymentMethodCorporate:
+		return true
+	}
+	return false
+}
+
+// maxPaymentReferenceLength caps the payment provider's reference
+const maxPaymentReferenceLength = 255
+
+// TripPayment is an entry of a trip's payments ledger: a charge captured from the passenger or a
+// refund given back. Entries are never changed; corrections are new entries.
+type TripPayment struct {
+	ID         uuid.UUID     `json:"id" db:"id"`
+	TripID     uuid.UUID     `json:"trip_id" db:"trip_id"`
+	Kind       PaymentKind   `json:"kind" db:"kind"`
+	Method     PaymentMethod `json:"method" db:"method"`
+	Amount     float64       `json:"amount" db:"amount"`                     // always positive; refunds are subtracted
+	Reference  *string       `json:"reference,omitempty" db:"reference"`     // payment provider's reference
+	RecordedBy *string       `json:"recorded_by,omitempty" db:"recorded_by"` // admin who recorded it by hand
+	CreatedAt  time.Time     `json:"created_at" db:"created_at"`
+}
+
+// TableName returns the table name for TripPayment
+func (TripPayment) TableName() string {
+	return "trip_payments"
+}
+
+// Net returns the amount the payment adds to what was paid for the trip
+func (p *TripPayment) Net() float64 {
+	if p.Kind == PaymentKindRefund {
+		return -p.Amount
+	}
+	return p.Amount
+}
+
+// Validate validates the payment data
+func (p *TripPayment) Validate() error {
+	if p.TripID == uuid.Nil {
+		return ErrInvalidTripID
+	}
+	if !p.Kind.IsValid() {
+		return ErrInvalidPaymentKind
+	}
+	if !p.Method.IsValid() {
+		return ErrInvalidPaymentMethod
+	}
+	if p.Amount <= 0 || math.IsNaN(p.Amount) || math.IsInf(p.Amount, 0) {
+		return ErrInvalidPaymentAmount
+	}
+	if p.Reference != nil && len(*p.Reference) > maxPaymentReferenceLength {
+		return ErrInvalidPaymentReference
+	}
+	return nil
+}
+
+// TripPaymentBalance is a completed trip's fare against the net amount of its payments ledger
+type TripPaymentBalance struct {
+	TripID      uuid.UUID `json:"trip_id" db:"trip_id"`
+	Fare        float64   `json:"fare" db:"fare"`
+	Paid        float64   `json:"paid" db:"paid"` // charges less refunds
+	Payments    int       `json:"payments" db:"payments"`
+	CompletedAt time.Time `json:"completed_at" db:"completed_at"`
+}
+
+// Difference returns what was paid over the fare; negative when the trip is underpaid
+func (b *TripPaymentBalance) Difference() float64 {
+	return math.Round((b.Paid-b.Fare)*100) / 100
+}
+
+// PaymentDiscrepancyType identifies how a completed trip's payments disagree with its fare
+type PaymentDiscrepancyType string
+
+const (
+	// PaymentDiscrepancyMissing is a completed trip without any payment
+	PaymentDiscrepancyMissing PaymentDiscrepancyType = "missing_payment"
+	// PaymentDiscrepancyMismatch is a completed trip whose payments add up to more or less than
+	// its fare
+	PaymentDiscrepancyMismatch PaymentDiscrepancyType = "amount_mismatch"
+)
+
+// IsValid returns true if the type is supported
+func (t PaymentDiscrepancyType) IsValid() bool {
+	return t == PaymentDiscrepancyMissing || t == PaymentDiscrepancyMismatch
+}
+
+// PaymentDiscrepancyStatus represents the resolution status of a payment discrepancy
+type PaymentDiscrepancyStatus string
+
+const (
+	PaymentDiscrepancyOpen      PaymentDiscrepancyStatus = "open"
+	PaymentDiscrepancyResolved  PaymentDiscrepancyStatus = "resolved"
+	PaymentDiscrepancyDismissed PaymentDiscrepancyStatus = "dismissed"
+)
+
+// IsValid returns true if the status is supported
+func (s PaymentDiscrepancyStatus) IsValid() bool {
+	switch s {
+	case PaymentDiscrepancyOpen, PaymentDiscrepancyResolved, PaymentDiscrepancyDismissed:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo checks if a discrepancy in this status can move to the given status. Open
+// discrepancies are resolved or dismissed once; both are final.
+func (s PaymentDiscrepancyStatus) CanTransitionTo(next PaymentDiscrepancyStatus) bool {
+	return s == PaymentDiscrepancyOpen && (next == PaymentDiscrepancyResolved || next == PaymentDiscrepancyDismissed)
+}
+
+// PaymentResolution is the action an admin took on a payment discrepancy
+type PaymentResolution string
+
+const (
+	// PaymentResolutionRecordPayment charges the passenger what the trip is short of its fare
+	PaymentResolutionRecordPayment PaymentResolution = "record_payment"
+	// PaymentResolutionRefund refunds the passenger what they paid over the fare
+	PaymentResolutionRefund PaymentResolution = "refund"
+	// PaymentResolutionWriteOff accepts the difference without a payment
+	PaymentResolutionWriteOff PaymentResolution = "write_off"
+	// PaymentResolutionDismiss closes a discrepancy that is not one, e.g. paid outside the ledger
+	PaymentResolutionDismiss PaymentResolution = "dismiss"
+	// PaymentResolutionSettled is set by the service when payments recorded later balance the trip
+	PaymentResolutionSettled PaymentResolution = "settled"
+)
+
+// IsValid returns true if the resolution is supported
+func (r PaymentResolution) IsValid() bool {
+	switch r {
+	case PaymentResolutionRecordPayment, PaymentResolutionRefund, PaymentResolutionWriteOff,
+		PaymentResolutionDismiss, PaymentResolutionSettled:
+		return true
+	}
+	return false
+}
+
+// Status returns the status a discrepancy resolved with the action ends in
+func (r PaymentResolution) Status() PaymentDiscrepancyStatus {
+	if r == PaymentResolutionDismiss {
+		return PaymentDiscrepancyDismissed
+	}
+	return PaymentDiscrepancyResolved
+}
+
+// maxPaymentResolutionNoteLength caps the resolver's note
+const maxPaymentResolutionNoteLength = 1000
+
+// PaymentDiscrepancy is a completed trip whose payments ledger disagreed with its fare when it
+// was reconciled, awaiting resolution by an admin. A trip has at most one open discrepancy of
+// each type.
+type PaymentDiscrepancy struct {
+	ID             uuid.UUID                `json:"id" db:"id"`
+	TripID         uuid.UUID                `json:"trip_id" db:"trip_id"`
+	Type           PaymentDiscrepancyType   `json:"type" db:"type"`
+	ExpectedAmount float64                  `json:"expected_amount" db:"expected_amount"` // the trip's fare
+	PaidAmount     float64                  `json:"paid_amount" db:"paid_amount"`         // charges less refunds when detected
+	Status         PaymentDiscrepancyStatus `json:"status" db:"status"`
+	Resolution     *PaymentResolution       `json:"resolution,omitempty" db:"resolution"`
+	PaymentID      *uuid.UUID               `json:"payment_id,omitempty" db:"payment_id"` // ledger entry the resolution recorded
+	ResolvedBy     *string                  `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolutionNote *string                  `json:"resolution_note,omitempty" db:"resolution_note"`
+	ResolvedAt     *time.Time               `json:"resolved_at,omitempty" db:"resolved_at"`
+	DetectedAt     time.Time                `json:"detected_at" db:"detected_at"`
+	UpdatedAt      time.Time                `json:"updated_at" db:"updated_at"`
+}
+
+// TableName returns the table name for PaymentDiscrepancy
+func (PaymentDiscrepancy) TableName() string {
+	return "payment_discrepancies"
+}
+
+// Difference returns what was paid over the fare when the discrepancy was detected; negative when
+// the trip was underpaid
+func (d *PaymentDiscrepancy) Difference() float64 {
+	return math.Round((d.PaidAmount-d.ExpectedAmount)*100) / 100
+}
+
+// Resolve records the action taken on the discrepancy
+func (d *PaymentDiscrepancy) Resolve(resolution PaymentResolution, paymentID *uuid.UUID, resolver, note string, now time.Time) {
+	d.Status = resolution.Status()
+	d.Resolution = &resolution
+	d.PaymentID = paymentID
+	d.ResolvedBy = &resolver
+	if note != "" {
+		d.ResolutionNote = &note
+	}
+	d.ResolvedAt = &now
+	d.UpdatedAt = now
+}
+
+// Validate validates the discrepancy data
+func (d *PaymentDiscrepancy) Validate() error {
+	if d.TripID == uuid.Nil {
+		return ErrInvalidTripID
+	}
+	if !d.Type.IsValid() {
+		return ErrInvalidPaymentDiscrepancyType
+	}
+	if !d.Status.IsValid() {
+		return ErrInvalidPaymentDiscrepancyStatus
+	}
+	if d.Resolution != nil && !d.Resolution.IsValid() {
+		return ErrInvalidPaymentResolution
+	}
+	if d.ResolutionNote != nil && len(*d.ResolutionNote) > maxPaymentResolutionNoteLength {
+		return ErrInvalidPaymentResolutionNote
+	}
+	return nil
+}
+
+// PaymentDiscrepancyFilter selects discrepancies for the admin queue; zero fields match everything
+type PaymentDiscrepancyFilter struct {
+	Type   PaymentDiscrepancyType
+	Status PaymentDiscrepancyStatus
+	TripID *uuid.UUID
+	Limit  int
+	Offset int
+}
+
+// PaymentReconciliationRun is the outcome of reconciling the trips completed in a window
+type PaymentReconciliationRun struct {
+	WindowStart   time.Time             `json:"window_start"`
+	WindowEnd     time.Time             `json:"window_end"`
+	Checked       int                   `json:"checked"`       // completed trips in the window
+	Balanced      int                   `json:"balanced"`      // trips whose payments match their fare
+	AlreadyOpen   int                   `json:"already_open"`  // discrepancies found again while still open
+	Discrepancies []*PaymentDiscrepancy `json:"discrepancies"` // discrepancies found for the first time
+	StartedAt     time.Time             `json:"started_at"`
+	DurationMs    int64                 `json:"duration_ms"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PickupWaitOutcome is how a driver's wait at the pickup ended
+type PickupWaitOutcome string
+
+const (
+	PickupWaitWaiting  PickupWaitOutcome = "waiting"
+	PickupWaitPickedUp PickupWaitOutcome = "picked_up"
+	PickupWaitNoShow   PickupWaitOutcome = "no_show"
+)
+
+// PickupWait is a driver's wait for the passenger at the pickup, from the driver's arrival
+// until the passenger is picked up or reported as a no-show
+type PickupWait struct {
+	TripID      uuid.UUID         `json:"trip_id" db:"trip_id"`
+	DriverID    uuid.UUID         `json:"driver_id" db:"driver_id"`
+	Zone        string            `json:"zone" db:"zone"` // geohash of the pickup
+	ArrivedAt   time.Time         `json:"arrived_at" db:"arrived_at"`
+	NoShowAfter time.Time         `json:"no_show_after" db:"no_show_after"` // when the driver may report a no-show
+	Outcome     PickupWaitOutcome `json:"outcome" db:"outcome"`
+	EndedAt     *time.Time        `json:"ended_at" db:"ended_at"`
+	WaitSeconds *int              `json:"wait_seconds" db:"wait_seconds"`
+	WaitFee     float64           `json:"wait_fee" db:"wait_fee"`
+	NoShowFee   float64           `json:"no_show_fee" db:"no_show_fee"`
+}
+
+// TableName returns the table name for PickupWait
+func (PickupWait) TableName() string {
+	return "pickup_waits"
+}
+
+// End ends the wait with outcome at the given time and records how long the driver waited
+func (w *PickupWait) End(outcome PickupWaitOutcome, at time.Time) {
+	waitSeconds := int(at.Sub(w.ArrivedAt).Seconds())
+	if waitSeconds < 0 {
+		waitSeconds = 0
+	}
+	w.Outcome = outcome
+	w.EndedAt = &at
+	w.WaitSeconds = &waitSeconds
+}
+
+// PickupWaitZoneStats are the wait-time metrics of the drivers arriving at pickups in a zone
+type PickupWaitZoneStats struct {
+	Zone           string  `json:"zone" db:"zone"`
+	Arrivals       int64   `json:"arrivals" db:"arrivals"`
+	PickedUp       int64   `json:"picked_up" db:"picked_up"`
+	NoShows        int64   `json:"no_shows" db:"no_shows"`
+	AvgWaitSeconds float64 `json:"avg_wait_seconds" db:"avg_wait_seconds"` // over the ended waits
+	MaxWaitSeconds int     `json:"max_wait_seconds" db:"max_wait_seconds"`
+	WaitFees       float64 `json:"wait_fees" db:"wait_fees"`
+	NoShowFees     float64 `json:"no_show_fees" db:"no_show_fees"`
+}
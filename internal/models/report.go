@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// DailyTripCount is the number of trips requested, completed and cancelled on one day of a
+// report, and the fares of the completed ones. Day indexes the report's days.
+type DailyTripCount struct {
+	Day       int     `db:"day"`
+	Requested int64   `db:"requested"`
+	Completed int64   `db:"completed"`
+	Cancelled int64   `db:"cancelled"`
+	Revenue   float64 `db:"revenue"`
+}
+
+// DailySummaryDay is the trip activity of one local date. Start and End are its local midnights,
+// Hours is 23 or 25 on the days the clocks change.
+type DailySummaryDay struct {
+	Date           string    `json:"date"`
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+	Hours          int       `json:"hours"`
+	TripsRequested int64     `json:"trips_requested"`
+	TripsCompleted int64     `json:"trips_completed"`
+	TripsCancelled int64     `json:"trips_cancelled"`
+	Revenue        float64   `json:"revenue"`
+
+	CancellationsByReason map[string]int64 `json:"cancellations_by_reason,omitempty"` // TripsCancelled by reason
+}
+
+// DailySummaryReport is the daily trip activity between two local dates, inclusive, in Timezone
+type DailySummaryReport struct {
+	Timezone string            `json:"timezone"`
+	From     string            `json:"from"`
+	To       string            `json:"to"`
+	Days     []DailySummaryDay `json:"days"`
+}
@@ -0,0 +1,143 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SafetyIncidentStatus represents the review status of a safety incident
+type SafetyIncidentStatus string
+
+const (
+	SafetyIncidentOpen        SafetyIncidentStatus = "open"
+	SafetyIncidentUnderReview SafetyIncidentStatus = "under_review"
+	SafetyIncidentClosed      SafetyIncidentStatus = "closed"
+)
+
+// maxIncidentTextLength caps the reporter's note and the reviewer's resolution note
+const maxIncidentTextLength = 1000
+
+// IsValid returns true if the status is supported
+func (s SafetyIncidentStatus) IsValid() bool {
+	switch s {
+	case SafetyIncidentOpen, SafetyIncidentUnderReview, SafetyIncidentClosed:
+		return true
+	}
+	return false
+}
+
+// IsActive returns true while the incident awaits review. The trip's data is frozen meanwhile.
+func (s SafetyIncidentStatus) IsActive() bool {
+	return s == SafetyIncidentOpen || s == SafetyIncidentUnderReview
+}
+
+// CanTransitionTo checks if an incident in this status can move to the given status.
+// Incidents can be closed without a separate review step.
+func (s SafetyIncidentStatus) CanTransitionTo(next SafetyIncidentStatus) bool {
+	switch s {
+	case SafetyIncidentOpen:
+		return next == SafetyIncidentUnderReview || next == SafetyIncidentClosed
+	case SafetyIncidentUnderReview:
+		return next == SafetyIncidentClosed
+	default:
+		return false // Terminal state
+	}
+}
+
+// Location sources of a safety incident
+const (
+	IncidentLocationReporter = "reporter" // sent by the reporter's device
+	IncidentLocationDriver   = "driver"   // the driver's last known location
+)
+
+// SafetyIncident is an SOS raised by a trip's passenger or driver. It keeps a snapshot of the
+// trip and its timeline, and the trip's data is exempt from retention until an admin closes it.
+type SafetyIncident struct {
+	ID             uuid.UUID            `json:"id" db:"id"`
+	TripID         uuid.UUID            `json:"trip_id" db:"trip_id"`
+	ReporterType   UserType             `json:"reporter_type" db:"reporter_type"`
+	ReporterID     uuid.UUID            `json:"reporter_id" db:"reporter_id"` // user ID of the reporter
+	Latitude       *float64             `json:"latitude,omitempty" db:"latitude"`
+	Longitude      *float64             `json:"longitude,omitempty" db:"longitude"`
+	LocationSource *string              `json:"location_source,omitempty" db:"location_source"`
+	TripStatus     TripStatus           `json:"trip_status" db:"trip_status"` // the trip's status when the SOS was raised
+	Note           *string              `json:"note,omitempty" db:"note"`
+	Snapshot       json.RawMessage      `json:"snapshot,omitempty" db:"snapshot" swaggertype:"object"`
+	Status         SafetyIncidentStatus `json:"status" db:"status"`
+	ReviewedBy     *string              `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ResolutionNote *string              `json:"resolution_note,omitempty" db:"resolution_note"`
+	ReviewedAt     *time.Time           `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	ClosedAt       *time.Time           `json:"closed_at,omitempty" db:"closed_at"`
+	CreatedAt      time.Time            `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time            `json:"updated_at" db:"updated_at"`
+}
+
+// TableName returns the table name for SafetyIncident
+func (SafetyIncident) TableName() string {
+	return "safety_incidents"
+}
+
+// StartReview moves the incident under review by reviewer
+func (i *SafetyIncident) StartReview(reviewer string, now time.Time) {
+	i.Status = SafetyIncidentUnderReview
+	i.ReviewedBy = &reviewer
+	i.ReviewedAt = &now
+	i.UpdatedAt = now
+}
+
+// Close closes the incident, lifting the freeze of the trip's data
+func (i *SafetyIncident) Close(reviewer, note string, now time.Time) {
+	i.Status = SafetyIncidentClosed
+	i.ReviewedBy = &reviewer
+	if i.ReviewedAt == nil {
+		i.ReviewedAt = &now
+	}
+	if note != "" {
+		i.ResolutionNote = &note
+	}
+	i.ClosedAt = &now
+	i.UpdatedAt = now
+}
+
+// Validate validates the incident data
+func (i *SafetyIncident) Validate() error {
+	if i.TripID == uuid.Nil {
+		return ErrInvalidTripID
+	}
+	if i.ReporterType != UserTypePassenger && i.ReporterType != UserTypeDriver {
+		return ErrInvalidUserType
+	}
+	if (i.Latitude == nil) != (i.Longitude == nil) {
+		return ErrInvalidLocation
+	}
+	if i.Latitude != nil && (*i.Latitude < -90 || *i.Latitude > 90 || *i.Longitude < -180 || *i.Longitude > 180) {
+		return ErrInvalidLocation
+	}
+	if i.Note != nil && len(*i.Note) > maxIncidentTextLength {
+		return ErrInvalidIncidentNote
+	}
+	if i.ResolutionNote != nil && len(*i.ResolutionNote) > maxIncidentTextLength {
+		return ErrInvalidIncidentNote
+	}
+	if !i.Status.IsValid() {
+		return ErrInvalidIncidentStatus
+	}
+	return nil
+}
+
+// SafetyIncidentSnapshot is the trip data frozen when an SOS is raised, kept with the incident
+// because observability partitions are dropped wholesale when they expire
+type SafetyIncidentSnapshot struct {
+	Trip     *Trip         `json:"trip"`
+	Timeline *TripTimeline `json:"timeline,omitempty"`
+}
+
+// SafetyIncidentFilter selects incidents for the admin review queue; zero fields match everything
+type SafetyIncidentFilter struct {
+	TripID *uuid.UUID
+	Status SafetyIncidentStatus
+	Limit  int
+	Offset int
+}
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedLocationLabel classifies a passenger's saved location
+type SavedLocationLabel string
+
+const (
+	SavedLocationHome   SavedLocationLabel = "home"
+	SavedLocationWork   SavedLocationLabel = "work"
+	SavedLocationCustom SavedLocationLabel = "custom"
+)
+
+// IsValid returns true if the label is supported
+func (l SavedLocationLabel) IsValid() bool {
+	switch l {
+	case SavedLocationHome, SavedLocationWork, SavedLocationCustom:
+		return true
+	}
+	return false
+}
+
+// IsUnique reports whether a passenger may have at most one location with this label
+func (l SavedLocationLabel) IsUnique() bool {
+	return l == SavedLocationHome || l == SavedLocationWork
+}
+
+// SavedLocation is a place a passenger saved for quick ride requests
+type SavedLocation struct {
+	ID          uuid.UUID          `json:"id" db:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PassengerID uuid.UUID          `json:"passenger_id" db:"passenger_id" gorm:"type:uuid;not null;index"`
+	Label       SavedLocationLabel `json:"label" db:"label" gorm:"not null;check:label IN ('home', 'work', 'custom')"`
+	Name        string             `json:"name" db:"name" gorm:"not null"`
+	Latitude    float64            `json:"latitude" db:"latitude" gorm:"type:decimal(10,8);not null"`
+	Longitude   float64            `json:"longitude" db:"longitude" gorm:"type:decimal(11,8);not null"`
+	Address     *string            `json:"address" db:"address"`
+	CreatedAt   time.Time          `json:"created_at" db:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time          `json:"updated_at" db:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
+// TableName returns the table name for SavedLocation
+func (SavedLocation) TableName() string {
+	return "saved_locations"
+}
+
+// Location returns the saved coordinates
+func (l *SavedLocation) Location() Location {
+	return Location{Latitude: l.Latitude, Longitude: l.Longitude}
+}
+
+// Validate validates the saved location data
+func (l *SavedLocation) Validate() error {
+	if l.PassengerID == uuid.Nil {
+		return ErrInvalidPassengerID
+	}
+	if !l.Label.IsValid() {
+		return ErrInvalidLocationLabel
+	}
+	if l.Name == "" {
+		return ErrInvalidName
+	}
+	loc := l.Location()
+	if !loc.IsValid() {
+		return ErrInvalidLocation
+	}
+	return nil
+}
+
+// RecentDestination is a destination a passenger travelled to, aggregated over their trips
+type RecentDestination struct {
+	Latitude      float64   `json:"latitude" db:"latitude"`
+	Longitude     float64   `json:"longitude" db:"longitude"`
+	Address       *string   `json:"address" db:"address"`
+	TripCount     int       `json:"trip_count" db:"trip_count"`
+	LastVisitedAt time.Time `json:"last_visited_at" db:"last_visited_at"`
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"math"
+	"strconv"
+	"time"
+)
+
+// Autoscaling signal metric names
+const (
+	ScalingMetricMailboxBacklog    = "actor_mailbox_backlog"     // messages waiting in every actor mailbox
+	ScalingMetricMailboxBacklogMax = "actor_mailbox_backlog_max" // messages waiting in the fullest mailbox
+	ScalingMetricPendingRides      = "pending_ride_requests"     // trips requested and not matched yet
+	ScalingMetricLatencyHeadroom   = "latency_headroom"          // smallest fraction of a latency error budget left
+	ScalingMetricLatencyBurnRate   = "latency_burn_rate"         // fastest latency error budget burn rate
+)
+
+// Kind and API version of the autoscaling signal, those of the Kubernetes external metrics API
+const (
+	ExternalMetricValueListKind = "ExternalMetricValueList"
+	ExternalMetricsAPIVersion   = "external.metrics.k8s.io/v1beta1"
+)
+
+// ExternalMetricValueList is the autoscaling signal in the format of the Kubernetes external
+// metrics API, read by KEDA's metrics-api scaler and by HPA external metrics adapters
+type ExternalMetricValueList struct {
+	Kind       string                `json:"kind"`
+	APIVersion string                `json:"apiVersion"`
+	Metadata   map[string]string     `json:"metadata"`
+	Items      []ExternalMetricValue `json:"items"`
+}
+
+// ExternalMetricValue is one metric of the autoscaling signal
+type ExternalMetricValue struct {
+	MetricName   string            `json:"metricName"`
+	MetricLabels map[string]string `json:"metricLabels"`
+	Timestamp    time.Time         `json:"timestamp"`
+	Value        string            `json:"value"` // Kubernetes quantity, e.g. "12" or "750m"
+}
+
+// NewExternalMetricValueList creates an empty autoscaling signal
+func NewExternalMetricValueList() *ExternalMetricValueList {
+	return &ExternalMetricValueList{
+		Kind:       ExternalMetricValueListKind,
+		APIVersion: ExternalMetricsAPIVersion,
+		Metadata:   map[string]string{},
+		Items:      []ExternalMetricValue{},
+	}
+}
+
+// Add appends a metric measured at a time to the signal
+func (l *ExternalMetricValueList) Add(name string, value float64, at time.Time) {
+	l.Items = append(l.Items, ExternalMetricValue{
+		MetricName:   name,
+		MetricLabels: map[string]string{},
+		Timestamp:    at,
+		Value:        FormatQuantity(value),
+	})
+}
+
+// Filter keeps only the metric with a name, so that scalers can read it as the first item
+func (l *ExternalMetricValueList) Filter(name string) {
+	items := []ExternalMetricValue{}
+	for _, item := range l.Items {
+		if item.MetricName == name {
+			items = append(items, item)
+		}
+	}
+	l.Items = items
+}
+
+// FormatQuantity formats a value as a Kubernetes quantity: whole values as integers, others in
+// thousandths with the m suffix
+func FormatQuantity(value float64) string {
+	if value == math.Trunc(value) {
+		return strconv.FormatInt(int64(value), 10)
+	}
+	return strconv.FormatInt(int64(math.Round(value*1000)), 10) + "m"
+}
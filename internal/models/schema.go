@@ -0,0 +1,43 @@
+package models
+
+// Table is a model stored in a table of its own
+type Table interface {
+	TableName() string
+}
+
+// MappedTables returns the models whose db tags map the columns of their table, which the
+// schema is validated against after migrating. Models without db tags are left out, as they are
+// read and written with explicit column lists.
+func MappedTables() []Table {
+	return []Table{
+		User{},
+		Driver{},
+		Passenger{},
+		Fleet{},
+		CorporateAccount{},
+		Session{},
+		SavedLocation{},
+		DriverDestination{},
+		DriverRest{},
+		DriverOnboarding{},
+		DriverTripStats{},
+		PickupWait{},
+		TripRematch{},
+		ETAPrediction{},
+		TripCompletion{},
+		TripChatMessage{},
+		SafetyIncident{},
+		FareDispute{},
+		FareAdjustment{},
+		WebhookSubscription{},
+		WebhookDelivery{},
+		IncentiveCampaign{},
+		IncentiveTripCredit{},
+		QuestProgress{},
+		IncentivePayout{},
+		Invoice{},
+		TripPayment{},
+		PaymentDiscrepancy{},
+		APIUsageRollup{},
+	}
+}
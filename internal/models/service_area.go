@@ -0,0 +1,45 @@
+package models
+
+import "fmt"
+
+// RideRejectionReason is why a ride request was refused before any trip was created. It is the
+// reason label of the rejection metrics; Code gives the error code of the response.
+type RideRejectionReason string
+
+const (
+	RideRejectionPickupOutsideArea      RideRejectionReason = "pickup_outside_service_area"
+	RideRejectionDestinationOutsideArea RideRejectionReason = "destination_outside_service_area"
+	RideRejectionOutsideHours           RideRejectionReason = "outside_operating_hours"
+)
+
+// Code returns the error code of a ride request rejected for the reason
+func (r RideRejectionReason) Code() ErrorCode {
+	switch r {
+	case RideRejectionPickupOutsideArea:
+		return ErrorCodeRidePickupOutsideArea
+	case RideRejectionDestinationOutsideArea:
+		return ErrorCodeRideDestinationOutsideArea
+	case RideRejectionOutsideHours:
+		return ErrorCodeRideOutsideHours
+	default:
+		return ErrorCodeUnprocessable
+	}
+}
+
+// RideRejectedError is returned for ride requests outside the service area or operating hours
+type RideRejectedError struct {
+	Reason RideRejectionReason
+}
+
+func (e *RideRejectedError) Error() string {
+	switch e.Reason {
+	case RideRejectionPickupOutsideArea:
+		return "pickup is outside the service area"
+	case RideRejectionDestinationOutsideArea:
+		return "destination is outside the service area"
+	case RideRejectionOutsideHours:
+		return "rides cannot be requested outside operating hours"
+	default:
+		return fmt.Sprintf("ride request rejected: %s", e.Reason)
+	}
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a device login of a driver or passenger. The device presents a short-lived access
+// token on API calls and exchanges its refresh token for a new token pair when it expires.
+// Only SHA-256 hashes of the tokens are stored.
+type Session struct {
+	ID               uuid.UUID  `json:"id" db:"id"`
+	UserID           uuid.UUID  `json:"user_id" db:"user_id"`
+	UserType         UserType   `json:"user_type" db:"user_type"`
+	DeviceID         string     `json:"device_id" db:"device_id"`
+	DeviceName       *string    `json:"device_name,omitempty" db:"device_name"`
+	AppPlatform      *string    `json:"app_platform,omitempty" db:"app_platform"` // as last reported by the app, e.g. ios
+	AppVersion       *string    `json:"app_version,omitempty" db:"app_version"`
+	AccessTokenHash  string     `json:"-" db:"access_token_hash"`
+	RefreshTokenHash string     `json:"-" db:"refresh_token_hash"`
+	AccessExpiresAt  time.Time  `json:"access_expires_at" db:"access_expires_at"`
+	ExpiresAt        time.Time  `json:"expires_at" db:"expires_at"` // when the refresh token expires
+	LastUsedAt       time.Time  `json:"last_used_at" db:"last_used_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty" db:"revoked_at"`
+	RevokedReason    *string    `json:"revoked_reason,omitempty" db:"revoked_reason"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at" db:"updated_at"`
+}
+
+// TableName returns the table name for Session
+func (Session) TableName() string {
+	return "sessions"
+}
+
+// IsActive returns true if the session is neither revoked nor expired at now
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// Revoke marks the session as revoked for reason
+func (s *Session) Revoke(reason string, now time.Time) {
+	s.RevokedAt = &now
+	s.RevokedReason = &reason
+	s.UpdatedAt = now
+}
+
+// Validate validates the session data
+func (s *Session) Validate() error {
+	if s.UserID == uuid.Nil {
+		return ErrInvalidUserID
+	}
+	if s.UserType != UserTypePassenger && s.UserType != UserTypeDriver {
+		return ErrInvalidUserType
+	}
+	if s.DeviceID == "" || len(s.DeviceID) > 255 {
+		return ErrInvalidDeviceID
+	}
+	if s.AccessTokenHash == "" || s.RefreshTokenHash == "" {
+		return ErrInvalidToken
+	}
+	return nil
+}
+
+// Session revocation reasons
+const (
+	SessionRevokedByUser   = "revoked"
+	SessionRevokedReplaced = "replaced"      // the device logged in again
+	SessionRevokedLimit    = "session_limit" // evicted to stay within the concurrent session limit
+)
+
+// AppVersionSessions counts the active sessions of an app platform and version. Platform and
+// version are empty for the sessions of apps that did not report them.
+type AppVersionSessions struct {
+	Platform string `json:"platform" db:"app_platform"`
+	Version  string `json:"version" db:"app_version"`
+	Sessions int64  `json:"sessions" db:"sessions"`
+}
+
+// SessionTokens is the token pair issued on login and refresh. The tokens are only returned here.
+type SessionTokens struct {
+	AccessToken  string   `json:"access_token"`
+	RefreshToken string   `json:"refresh_token"`
+	TokenType    string   `json:"token_type"`
+	ExpiresIn    int      `json:"expires_in"` // seconds until the access token expires
+	Session      *Session `json:"session"`
+}
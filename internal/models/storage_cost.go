@@ -0,0 +1,217 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Observability pipelines whose storage is compared
+const (
+	StoragePipelineActor       = "actor"
+	StoragePipelineTraditional = "traditional"
+)
+
+// StorageModeMixed is the mode of a bucket with ride requests made in more than one mode, whose
+// rows cannot be attributed to either
+const StorageModeMixed = "mixed"
+
+// StorageCostTables are the observability tables whose storage is compared, by the pipeline
+// writing them: the actor pipeline's messages, events and traces against the traditional
+// pipeline's metrics and logs
+var StorageCostTables = map[string][]string{
+	StoragePipelineActor:       {"actor_messages", "event_logs", "distributed_traces"},
+	StoragePipelineTraditional: {"traditional_metrics", "traditional_logs"},
+}
+
+// StoragePipelines are the pipelines of StorageCostTables in report order
+var StoragePipelines = []string{StoragePipelineActor, StoragePipelineTraditional}
+
+// StorageUsage is the rows of an observability table written in one bucket of a storage cost
+// report and the bytes they take up. Bucket indexes the report's buckets.
+type StorageUsage struct {
+	TableName string `db:"table_name"`
+	Bucket    int    `db:"bucket"`
+	Rows      int64  `db:"row_count"`
+	Bytes     int64  `db:"bytes"`
+}
+
+// StorageRequests is the ride requests made in one mode in one bucket of a storage cost report
+type StorageRequests struct {
+	Bucket   int    `db:"bucket"`
+	Mode     string `db:"mode"`
+	Requests int64  `db:"requests"`
+}
+
+// StorageTableCost is the rows of one observability table and the bytes they take up, per ride
+// request when there were requests
+type StorageTableCost struct {
+	Table           string  `json:"table"`
+	Rows            int64   `json:"rows"`
+	Bytes           int64   `json:"bytes"`
+	RowsPerRequest  float64 `json:"rows_per_request"`
+	BytesPerRequest float64 `json:"bytes_per_request"`
+}
+
+// StoragePipelineCost is the rows and bytes written by one observability pipeline, in total and
+// by table
+type StoragePipelineCost struct {
+	Pipeline        string              `json:"pipeline"`
+	Rows            int64               `json:"rows"`
+	Bytes           int64               `json:"bytes"`
+	RowsPerRequest  float64             `json:"rows_per_request"`
+	BytesPerRequest float64             `json:"bytes_per_request"`
+	Tables          []*StorageTableCost `json:"tables"`
+}
+
+// StorageCostBucket is the observability storage written in one bucket of a storage cost report.
+// Mode is the mode of the bucket's ride requests, StorageModeMixed when they were made in more
+// than one and empty without requests.
+type StorageCostBucket struct {
+	Start     time.Time              `json:"start"`
+	End       time.Time              `json:"end"`
+	Mode      string                 `json:"mode"`
+	Requests  map[string]int64       `json:"requests"` // by mode
+	Pipelines []*StoragePipelineCost `json:"pipelines"`
+}
+
+// StorageCostMode is the observability storage written while ride requests were made in one
+// mode, over the buckets attributed to it
+type StorageCostMode struct {
+	Mode      string                 `json:"mode"`
+	Buckets   int                    `json:"buckets"`
+	Requests  int64                  `json:"requests"`
+	Pipelines []*StoragePipelineCost `json:"pipelines"`
+}
+
+// StorageCostReport compares the rows and bytes the observability pipelines store per ride
+// request, by the mode (actor_model or traditional) requests were made in. Both pipelines record
+// every ride whichever the mode, so each mode is charged the storage of both. Every bucket is
+// attributed to the mode of its requests; buckets with requests of more than one mode or without
+// requests are listed but left out of the modes.
+type StorageCostReport struct {
+	Start   time.Time            `json:"start"`
+	End     time.Time            `json:"end"`
+	Bucket  string               `json:"bucket"`
+	Modes   []*StorageCostMode   `json:"modes"`
+	Buckets []*StorageCostBucket `json:"buckets"`
+}
+
+// BuildStorageCostReport builds the storage cost report of [start, end) over buckets of the given
+// length starting at start, from the usage of every table of StorageCostTables and the ride
+// requests per bucket and mode
+func BuildStorageCostReport(start, end time.Time, bucket time.Duration, usage []*StorageUsage, requests []*StorageRequests) *StorageCostReport {
+	count := int((end.Sub(start) + bucket - 1) / bucket)
+	report := &StorageCostReport{
+		Start:   start,
+		End:     end,
+		Bucket:  bucket.String(),
+		Modes:   []*StorageCostMode{},
+		Buckets: make([]*StorageCostBucket, count),
+	}
+
+	// Rows and bytes by bucket, then table
+	tables := make([]map[string]*StorageTableCost, count)
+	for i := range report.Buckets {
+		bucketEnd := start.Add(time.Duration(i+1) * bucket)
+		if bucketEnd.After(end) {
+			bucketEnd = end
+		}
+		report.Buckets[i] = &StorageCostBucket{
+			Start:    start.Add(time.Duration(i) * bucket),
+			End:      bucketEnd,
+			Requests: map[string]int64{},
+		}
+		tables[i] = make(map[string]*StorageTableCost)
+	}
+	for _, u := range usage {
+		if u.Bucket < 0 || u.Bucket >= count {
+			continue
+		}
+		cost, ok := tables[u.Bucket][u.TableName]
+		if !ok {
+			cost = &StorageTableCost{Table: u.TableName}
+			tables[u.Bucket][u.TableName] = cost
+		}
+		cost.Rows += u.Rows
+		cost.Bytes += u.Bytes
+	}
+	for _, r := range requests {
+		if r.Bucket >= 0 && r.Bucket < count && r.Requests > 0 {
+			report.Buckets[r.Bucket].Requests[r.Mode] += r.Requests
+		}
+	}
+
+	// Each mode sums the tables of the buckets attributed to it
+	modes := make(map[string]*StorageCostMode)
+	modeTables := make(map[string]map[string]*StorageTableCost)
+	for i, b := range report.Buckets {
+		var requestTotal int64
+		for mode, n := range b.Requests {
+			requestTotal += n
+			if b.Mode == "" {
+				b.Mode = mode
+			} else if b.Mode != mode {
+				b.Mode = StorageModeMixed
+			}
+		}
+		b.Pipelines = storagePipelineCosts(tables[i], requestTotal)
+
+		if b.Mode == "" || b.Mode == StorageModeMixed {
+			continue
+		}
+		mode, ok := modes[b.Mode]
+		if !ok {
+			mode = &StorageCostMode{Mode: b.Mode}
+			modes[b.Mode] = mode
+			modeTables[b.Mode] = make(map[string]*StorageTableCost)
+		}
+		mode.Buckets++
+		mode.Requests += requestTotal
+		for table, cost := range tables[i] {
+			total, ok := modeTables[b.Mode][table]
+			if !ok {
+				total = &StorageTableCost{Table: table}
+				modeTables[b.Mode][table] = total
+			}
+			total.Rows += cost.Rows
+			total.Bytes += cost.Bytes
+		}
+	}
+
+	for name, mode := range modes {
+		mode.Pipelines = storagePipelineCosts(modeTables[name], mode.Requests)
+		report.Modes = append(report.Modes, mode)
+	}
+	sort.Slice(report.Modes, func(i, j int) bool { return report.Modes[i].Mode < report.Modes[j].Mode })
+	return report
+}
+
+// storagePipelineCosts groups the rows and bytes of the tables by pipeline and divides them by
+// the ride requests. Every table is listed, those without rows included.
+func storagePipelineCosts(tables map[string]*StorageTableCost, requests int64) []*StoragePipelineCost {
+	pipelines := make([]*StoragePipelineCost, 0, len(StoragePipelines))
+	for _, name := range StoragePipelines {
+		pipeline := &StoragePipelineCost{Pipeline: name}
+		for _, table := range StorageCostTables[name] {
+			cost := StorageTableCost{Table: table}
+			if t, ok := tables[table]; ok {
+				cost.Rows, cost.Bytes = t.Rows, t.Bytes
+			}
+			cost.RowsPerRequest, cost.BytesPerRequest = perRequest(cost.Rows, cost.Bytes, requests)
+			pipeline.Tables = append(pipeline.Tables, &cost)
+			pipeline.Rows += cost.Rows
+			pipeline.Bytes += cost.Bytes
+		}
+		pipeline.RowsPerRequest, pipeline.BytesPerRequest = perRequest(pipeline.Rows, pipeline.Bytes, requests)
+		pipelines = append(pipelines, pipeline)
+	}
+	return pipelines
+}
+
+// perRequest divides rows and bytes by the ride requests, returning zero without requests
+func perRequest(rows, bytes, requests int64) (float64, float64) {
+	if requests <= 0 {
+		return 0, 0
+	}
+	return float64(rows) / float64(requests), float64(bytes) / float64(requests)
+}
@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"actor-model-observability/internal/hlc"
+
+	"github.com/google/uuid"
+)
+
+// Trip timeline entry sources
+const (
+	TimelineSourceTrip    = "trip"    // lifecycle timestamps of the trip itself
+	TimelineSourceEvent   = "event"   // event logs recorded for the trip
+	TimelineSourceMessage = "message" // actor messages whose payload references the trip
+	TimelineSourceTrace   = "trace"   // spans of the traces of those messages and events
+	TimelineSourceLog     = "log"     // traditional logs whose fields reference the trip
+)
+
+// TripTimeline merges everything recorded about a trip into one chronological view
+type TripTimeline struct {
+	TripID  uuid.UUID           `json:"trip_id"`
+	Status  TripStatus          `json:"status"`
+	Start   time.Time           `json:"start"`
+	End     time.Time           `json:"end"`
+	Entries []TripTimelineEntry `json:"entries"`
+	// Truncated lists the sources with more records in the trip's time window than were
+	// scanned, whose entries may be incomplete
+	Truncated []string `json:"truncated,omitempty"`
+}
+
+// TripTimelineEntry is one record of a trip timeline. Data is the record itself: an EventLog,
+// ActorMessage, DistributedTrace or TraditionalLog; trip lifecycle entries have none. Events and
+// actor messages with a hybrid timestamp are ordered by it rather than by Timestamp, which is
+// read from the clock of the instance that recorded them.
+type TripTimelineEntry struct {
+	Timestamp time.Time      `json:"timestamp"`
+	HLC       *hlc.Timestamp `json:"hlc,omitempty"`
+	Source    string         `json:"source"`
+	Type      string         `json:"type"`
+	Summary   string         `json:"summary"`
+	TraceID   *uuid.UUID     `json:"trace_id,omitempty"`
+	Data      interface{}    `json:"data,omitempty"`
+}
+
+// OrderKey returns the wall time and counter the entry is ordered by: those of its hybrid
+// timestamp if it has one, else its timestamp
+func (e *TripTimelineEntry) OrderKey() (int64, uint32) {
+	if e.HLC != nil {
+		return e.HLC.WallTime, e.HLC.Logical
+	}
+	return e.Timestamp.UnixNano(), 0
+}
@@ -21,29 +21,32 @@ const (
 
 // Trip represents a trip in the system
 type Trip struct {
-	ID                   uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	PassengerID          uuid.UUID  `json:"passenger_id" gorm:"type:uuid;not null;index"`
-	Passenger            *Passenger `json:"passenger,omitempty" gorm:"foreignKey:PassengerID"`
-	DriverID             *uuid.UUID `json:"driver_id" gorm:"type:uuid;index"`
-	Driver               *Driver    `json:"driver,omitempty" gorm:"foreignKey:DriverID"`
-	PickupLatitude       float64    `json:"pickup_latitude" gorm:"type:decimal(10,8);not null"`
-	PickupLongitude      float64    `json:"pickup_longitude" gorm:"type:decimal(11,8);not null"`
-	PickupAddress        *string    `json:"pickup_address"`
-	DestinationLatitude  float64    `json:"destination_latitude" gorm:"type:decimal(10,8);not null"`
-	DestinationLongitude float64    `json:"destination_longitude" gorm:"type:decimal(11,8);not null"`
-	DestinationAddress   *string    `json:"destination_address"`
-	Status               TripStatus `json:"status" gorm:"default:'requested';check:status IN ('requested', 'matched', 'accepted', 'driver_arrived', 'in_progress', 'completed', 'cancelled')"`
-	FareAmount           *float64   `json:"fare_amount" gorm:"type:decimal(10,2)"`
-	DistanceKm           *float64   `json:"distance_km" gorm:"type:decimal(8,2)"`
-	DurationMinutes      *int       `json:"duration_minutes"`
-	RequestedAt          time.Time  `json:"requested_at" gorm:"default:CURRENT_TIMESTAMP"`
-	MatchedAt            *time.Time `json:"matched_at"`
-	AcceptedAt           *time.Time `json:"accepted_at"`
-	PickupAt             *time.Time `json:"pickup_at"`
-	CompletedAt          *time.Time `json:"completed_at"`
-	CancelledAt          *time.Time `json:"cancelled_at"`
-	CreatedAt            time.Time  `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
-	UpdatedAt            time.Time  `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+	ID                   uuid.UUID           `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	PassengerID          uuid.UUID           `json:"passenger_id" gorm:"type:uuid;not null;index"`
+	Passenger            *Passenger          `json:"passenger,omitempty" gorm:"foreignKey:PassengerID"`
+	DriverID             *uuid.UUID          `json:"driver_id" gorm:"type:uuid;index"`
+	Driver               *Driver             `json:"driver,omitempty" gorm:"foreignKey:DriverID"`
+	PickupLatitude       float64             `json:"pickup_latitude" gorm:"type:decimal(10,8);not null"`
+	PickupLongitude      float64             `json:"pickup_longitude" gorm:"type:decimal(11,8);not null"`
+	PickupAddress        *string             `json:"pickup_address"`
+	DestinationLatitude  float64             `json:"destination_latitude" gorm:"type:decimal(10,8);not null"`
+	DestinationLongitude float64             `json:"destination_longitude" gorm:"type:decimal(11,8);not null"`
+	DestinationAddress   *string             `json:"destination_address"`
+	Status               TripStatus          `json:"status" gorm:"default:'requested';check:status IN ('requested', 'matched', 'accepted', 'driver_arrived', 'in_progress', 'completed', 'cancelled')"`
+	FareAmount           *float64            `json:"fare_amount" gorm:"type:decimal(10,2)"`
+	DistanceKm           *float64            `json:"distance_km" gorm:"type:decimal(8,2)"`
+	DurationMinutes      *int                `json:"duration_minutes"`
+	RequestedAt          time.Time           `json:"requested_at" gorm:"default:CURRENT_TIMESTAMP"`
+	MatchedAt            *time.Time          `json:"matched_at"`
+	AcceptedAt           *time.Time          `json:"accepted_at"`
+	PickupAt             *time.Time          `json:"pickup_at"`
+	CompletedAt          *time.Time          `json:"completed_at"`
+	CancelledAt          *time.Time          `json:"cancelled_at"`
+	CancellationReason   *CancellationReason `json:"cancellation_reason"`
+	CancellationNote     *string             `json:"cancellation_note"`
+	CancellationMode     *string             `json:"cancellation_mode"` // actor_model or traditional, the pipeline that cancelled the trip
+	CreatedAt            time.Time           `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt            time.Time           `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
 }
 
 // TableName returns the table name for Trip
@@ -137,6 +140,19 @@ func (t *Trip) Cancel() {
 	t.SetStatus(TripStatusCancelled)
 }
 
+// RecordCancellation records why the trip was cancelled and the mode of the pipeline that
+// cancelled it
+func (t *Trip) RecordCancellation(cancellation TripCancellation, mode string) {
+	reason := cancellation.Reason
+	t.CancellationReason = &reason
+	t.CancellationNote = nil
+	if cancellation.Note != "" {
+		note := cancellation.Note
+		t.CancellationNote = &note
+	}
+	t.CancellationMode = &mode
+}
+
 // GetDuration returns the trip duration if completed
 func (t *Trip) GetDuration() time.Duration {
 	if t.CompletedAt != nil && t.PickupAt != nil {
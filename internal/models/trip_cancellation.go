@@ -0,0 +1,154 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CancellationReason is why a trip was cancelled, from a fixed taxonomy so cancellations can
+// be broken down; details go in the cancellation note
+type CancellationReason string
+
+const (
+	CancellationReasonChangedPlans    CancellationReason = "changed_plans"     // the passenger no longer needs the ride
+	CancellationReasonWaitTooLong     CancellationReason = "wait_too_long"     // matching or the driver's ETA took too long
+	CancellationReasonDriverNotMoving CancellationReason = "driver_not_moving" // the matched driver was not heading to the pickup
+	CancellationReasonWrongPickup     CancellationReason = "wrong_pickup"      // the pickup location was wrong
+	CancellationReasonFareTooHigh     CancellationReason = "fare_too_high"
+	CancellationReasonSafetyConcern   CancellationReason = "safety_concern"
+	CancellationReasonPassengerNoShow CancellationReason = "passenger_no_show" // reported by the driver waiting at the pickup
+	CancellationReasonDriverCancelled CancellationReason = "driver_cancelled"  // the matched driver cancelled and the trip could not be matched again
+	CancellationReasonOther           CancellationReason = "other"
+)
+
+// CancellationReasons are the reasons of the taxonomy, in the order they are reported
+var CancellationReasons = []CancellationReason{
+	CancellationReasonChangedPlans,
+	CancellationReasonWaitTooLong,
+	CancellationReasonDriverNotMoving,
+	CancellationReasonWrongPickup,
+	CancellationReasonFareTooHigh,
+	CancellationReasonSafetyConcern,
+	CancellationReasonPassengerNoShow,
+	CancellationReasonDriverCancelled,
+	CancellationReasonOther,
+}
+
+// MaxCancellationNoteLength is the longest cancellation note accepted, in characters
+const MaxCancellationNoteLength = 500
+
+// CancellationUnknown is the key under which cancellations are counted when their reason, mode
+// or zone is unknown, such as trips cancelled before reasons were recorded
+const CancellationUnknown = "unknown"
+
+// IsValid reports whether the reason is part of the taxonomy
+func (r CancellationReason) IsValid() bool {
+	for _, reason := range CancellationReasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// TripCancellation is why a trip is cancelled: a reason of the taxonomy and an optional
+// free-text note
+type TripCancellation struct {
+	Reason CancellationReason `json:"reason"`
+	Note   string             `json:"note,omitempty"`
+}
+
+// Validate checks that the reason is part of the taxonomy and the note is not too long
+func (c TripCancellation) Validate() error {
+	if !c.Reason.IsValid() {
+		reasons := make([]string, len(CancellationReasons))
+		for i, reason := range CancellationReasons {
+			reasons[i] = string(reason)
+		}
+		return &ValidationError{
+			Field:   "reason",
+			Message: fmt.Sprintf("reason must be one of %s", strings.Join(reasons, ", ")),
+		}
+	}
+	if len([]rune(c.Note)) > MaxCancellationNoteLength {
+		return &ValidationError{
+			Field:   "note",
+			Message: fmt.Sprintf("note must be at most %d characters", MaxCancellationNoteLength),
+		}
+	}
+	return nil
+}
+
+// DailyCancellationCount is the number of trips cancelled for a reason on one day of a report.
+// Day indexes the report's days; trips cancelled without a reason have an empty one.
+type DailyCancellationCount struct {
+	Day    int    `db:"day"`
+	Reason string `db:"reason"`
+	Count  int64  `db:"count"`
+}
+
+// CancellationCount is the number of cancellations sharing a value, such as a reason, and their
+// share of all the cancellations of the period
+type CancellationCount struct {
+	Key   string  `json:"key"`
+	Count int64   `json:"count"`
+	Share float64 `json:"share"`
+}
+
+// CancellationTimeBucket is the cancellations of a bucket of the period, by reason
+type CancellationTimeBucket struct {
+	Start    time.Time        `json:"start"`
+	Count    int64            `json:"count"`
+	ByReason map[string]int64 `json:"by_reason,omitempty"`
+}
+
+// CancellationAnalytics breaks down the trips cancelled in [Start, End) by reason, by the mode
+// of the pipeline that cancelled them, by pickup zone and over time. Breakdowns are sorted by
+// count, largest first; trips cancelled without a reason or mode are counted as CancellationUnknown.
+type CancellationAnalytics struct {
+	Start    time.Time                `json:"start"`
+	End      time.Time                `json:"end"`
+	Bucket   string                   `json:"bucket"`
+	Total    int64                    `json:"total"`
+	ByReason []CancellationCount      `json:"by_reason"`
+	ByMode   []CancellationCount      `json:"by_mode"`
+	ByZone   []CancellationCount      `json:"by_zone"` // pickup geohashes, the busiest first
+	ByTime   []CancellationTimeBucket `json:"by_time"`
+}
+
+// CancellationRule is the rule of the cancellation policy a quote was made under
+type CancellationRule string
+
+const (
+	CancellationRuleTripEnded   CancellationRule = "trip_ended"   // completed or already cancelled trips cannot be cancelled
+	CancellationRuleFreeReason  CancellationRule = "free_reason"  // the reason is never charged
+	CancellationRuleNoDriver    CancellationRule = "no_driver"    // no driver is matched yet
+	CancellationRuleGracePeriod CancellationRule = "grace_period" // a driver was matched within the grace period
+	CancellationRuleLate        CancellationRule = "late"         // a driver was matched before the grace period
+	CancellationRuleArrived     CancellationRule = "arrived"      // the driver has arrived at the pickup
+)
+
+// CancellationQuote tells a passenger whether cancelling a trip now is free and what it costs
+// before they confirm
+type CancellationQuote struct {
+	TripID      uuid.UUID        `json:"trip_id"`
+	Status      TripStatus       `json:"status"`
+	Cancellable bool             `json:"cancellable"`
+	Free        bool             `json:"free"`
+	Fee         float64          `json:"fee"`
+	Rule        CancellationRule `json:"rule"`
+	GraceEndsAt *time.Time       `json:"grace_ends_at,omitempty"` // while cancelling is free within the grace period
+	QuotedAt    time.Time        `json:"quoted_at"`
+}
+
+// EventFields returns the quote's details for the cancellation events
+func (q *CancellationQuote) EventFields() map[string]interface{} {
+	return map[string]interface{}{
+		"cancellation_fee":  q.Fee,
+		"cancellation_free": q.Free,
+		"cancellation_rule": string(q.Rule),
+	}
+}
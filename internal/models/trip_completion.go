@@ -0,0 +1,72 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TripCompletion is what the driver entered when completing a trip, reconciled against the
+// trip's recorded route: the requested pickup and destination. Completions with large
+// discrepancies are flagged for review.
+type TripCompletion struct {
+	TripID             uuid.UUID `json:"trip_id" db:"trip_id"`
+	DriverID           uuid.UUID `json:"driver_id" db:"driver_id"`
+	DropoffLatitude    float64   `json:"dropoff_latitude" db:"dropoff_latitude"`
+	DropoffLongitude   float64   `json:"dropoff_longitude" db:"dropoff_longitude"`
+	OdometerDistanceKm float64   `json:"odometer_distance_km" db:"odometer_distance_km"`
+	TollsAmount        float64   `json:"tolls_amount" db:"tolls_amount"`
+	ExtrasAmount       float64   `json:"extras_amount" db:"extras_amount"`
+	WaitFee            float64   `json:"wait_fee" db:"wait_fee"`                         // for making the driver wait at the pickup past the free period
+	RouteDistanceKm    float64   `json:"route_distance_km" db:"route_distance_km"`       // straight line from the pickup to the actual dropoff
+	DropoffDeviationKm float64   `json:"dropoff_deviation_km" db:"dropoff_deviation_km"` // actual dropoff to the requested destination
+	BilledDistanceKm   float64   `json:"billed_distance_km" db:"billed_distance_km"`
+	EstimatedFare      float64   `json:"estimated_fare" db:"estimated_fare"`
+	FinalFare          float64   `json:"final_fare" db:"final_fare"`
+	Flagged            bool      `json:"flagged" db:"flagged"`
+	FlagReason         *string   `json:"flag_reason" db:"flag_reason"`
+	CreatedAt          time.Time `json:"created_at" db:"created_at"`
+}
+
+// TableName returns the table name for TripCompletion
+func (TripCompletion) TableName() string {
+	return "trip_completions"
+}
+
+// Validate validates the details entered by the driver
+func (c *TripCompletion) Validate() error {
+	if c.TripID == uuid.Nil {
+		return ErrInvalidTripID
+	}
+	if c.DriverID == uuid.Nil {
+		return ErrInvalidDriverID
+	}
+	if c.DropoffLatitude < -90 || c.DropoffLatitude > 90 || c.DropoffLongitude < -180 || c.DropoffLongitude > 180 {
+		return ErrInvalidDropoffLocation
+	}
+	if c.OdometerDistanceKm < 0 || math.IsNaN(c.OdometerDistanceKm) || math.IsInf(c.OdometerDistanceKm, 0) {
+		return ErrInvalidDistance
+	}
+	for _, amount := range []float64{c.TollsAmount, c.ExtrasAmount} {
+		if amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+			return ErrInvalidCompletionCharge
+		}
+	}
+	return nil
+}
+
+// TripCompletionDetails are what a driver enters when completing a trip
+type TripCompletionDetails struct {
+	Dropoff            Location
+	OdometerDistanceKm float64
+	TollsAmount        float64
+	ExtrasAmount       float64
+}
+
+// TripCompletionFilter selects completions for the admin review queue; zero fields match everything
+type TripCompletionFilter struct {
+	Flagged *bool
+	Limit   int
+	Offset  int
+}
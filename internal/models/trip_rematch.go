@@ -0,0 +1,60 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TripRematchOutcome is how matching a trip again after its driver cancelled ended
+type TripRematchOutcome string
+
+const (
+	TripRematchMatching     TripRematchOutcome = "matching"      // the trip is being matched again
+	TripRematchMatched      TripRematchOutcome = "matched"       // another driver was matched
+	TripRematchUnmatched    TripRematchOutcome = "unmatched"     // the trip was cancelled before another driver was matched
+	TripRematchLimitReached TripRematchOutcome = "limit_reached" // the trip was re-matched too often and was cancelled
+)
+
+// TripRematch is a matched driver cancelling a trip before pickup and the trip being matched
+// again with another driver. Every driver who cancelled is left out of the trip's later matches.
+type TripRematch struct {
+	ID                uuid.UUID          `json:"id" db:"id"`
+	TripID            uuid.UUID          `json:"trip_id" db:"trip_id"`
+	Attempt           int                `json:"attempt" db:"attempt"` // 1 for the trip's first driver cancelling
+	CancelledDriverID uuid.UUID          `json:"cancelled_driver_id" db:"cancelled_driver_id"`
+	CancelledAt       time.Time          `json:"cancelled_at" db:"cancelled_at"`
+	Note              *string            `json:"note" db:"note"`
+	Mode              string             `json:"mode" db:"mode"` // actor_model or traditional, the pipeline matching the trip again
+	Outcome           TripRematchOutcome `json:"outcome" db:"outcome"`
+	MatchedDriverID   *uuid.UUID         `json:"matched_driver_id" db:"matched_driver_id"`
+	EndedAt           *time.Time         `json:"ended_at" db:"ended_at"`
+	AddedWaitSeconds  *int               `json:"added_wait_seconds" db:"added_wait_seconds"` // from the cancellation until the re-match ended
+}
+
+// TableName returns the table name for TripRematch
+func (TripRematch) TableName() string {
+	return "trip_rematches"
+}
+
+// End ends the re-match with outcome at the given time, recording the wait it added to the trip
+func (r *TripRematch) End(outcome TripRematchOutcome, matchedDriverID *uuid.UUID, at time.Time) {
+	addedWait := int(at.Sub(r.CancelledAt).Seconds())
+	if addedWait < 0 {
+		addedWait = 0
+	}
+	r.Outcome = outcome
+	r.MatchedDriverID = matchedDriverID
+	r.EndedAt = &at
+	r.AddedWaitSeconds = &addedWait
+}
+
+// ExcludedDrivers returns the drivers who cancelled the trip of the re-matches, to be left out
+// when it is matched again
+func ExcludedDrivers(rematches []*TripRematch) map[uuid.UUID]bool {
+	excluded := make(map[uuid.UUID]bool, len(rematches))
+	for _, rematch := range rematches {
+		excluded[rematch.CancelledDriverID] = true
+	}
+	return excluded
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// kmPerDegreeLatitude is the length of a degree of latitude
+const kmPerDegreeLatitude = 111.32
+
+// TripSearchFilter selects the trips support agents look up for a customer. Every criterion set
+// must match; at least one must be set.
+type TripSearchFilter struct {
+	PassengerPhone string     // exact match
+	PassengerEmail string     // case-insensitive match
+	DriverPlate    string     // matched ignoring case and spaces, see NormalizePlate
+	TripIDPrefix   string     // leading characters of the trip ID, lowercase with dashes
+	RequestedFrom  *time.Time // requested at or after
+	RequestedTo    *time.Time // requested before
+	PickupBounds   *GeoBounds // picked up within
+	Limit          int
+	Offset         int
+}
+
+// IsEmpty reports whether no criterion is set, which would match every trip
+func (f TripSearchFilter) IsEmpty() bool {
+	return f.PassengerPhone == "" && f.PassengerEmail == "" && f.DriverPlate == "" && f.TripIDPrefix == "" &&
+		f.RequestedFrom == nil && f.RequestedTo == nil && f.PickupBounds == nil
+}
+
+// TripSearchResult is a matching trip with the contact details support agents identify its
+// passenger and driver by. The driver fields are nil for trips without a driver.
+type TripSearchResult struct {
+	*Trip
+	PassengerName  string  `json:"passenger_name"`
+	PassengerPhone string  `json:"passenger_phone"`
+	PassengerEmail string  `json:"passenger_email"`
+	DriverName     *string `json:"driver_name"`
+	DriverPlate    *string `json:"driver_plate"`
+}
+
+// NormalizePlate returns a vehicle plate in the form plates are searched by, uppercase without
+// spaces, so that "b 1234 xy" finds "B 1234 XY"
+func NormalizePlate(plate string) string {
+	return strings.ToUpper(strings.ReplaceAll(plate, " ", ""))
+}
+
+// GeoBoundsAround returns the bounding box of the circle of radiusKm around center
+func GeoBoundsAround(center Location, radiusKm float64) GeoBounds {
+	latDelta := radiusKm / kmPerDegreeLatitude
+	lngDelta := 180.0
+	if cos := math.Cos(center.Latitude * math.Pi / 180); cos > 1e-9 {
+		lngDelta = math.Min(radiusKm/(kmPerDegreeLatitude*cos), 180)
+	}
+	return GeoBounds{
+		MinLat: center.Latitude - latDelta,
+		MaxLat: center.Latitude + latDelta,
+		MinLng: center.Longitude - lngDelta,
+		MaxLng: center.Longitude + lngDelta,
+	}
+}
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TripUpdateType is the kind of a live trip update
+type TripUpdateType string
+
+const (
+	// TripUpdateStatus reports that the trip entered Status
+	TripUpdateStatus TripUpdateType = "status"
+	// TripUpdateETA reports where the trip's driver is and when it is expected at the pickup,
+	// or at the destination once the passenger is on board
+	TripUpdateETA TripUpdateType = "eta"
+)
+
+// TripUpdate is a change of a trip pushed to its live subscribers
+type TripUpdate struct {
+	Type            TripUpdateType `json:"type"`
+	TripID          uuid.UUID      `json:"trip_id"`
+	Status          TripStatus     `json:"status"`
+	DriverID        *uuid.UUID     `json:"driver_id,omitempty"`
+	DriverLatitude  *float64       `json:"driver_latitude,omitempty"`
+	DriverLongitude *float64       `json:"driver_longitude,omitempty"`
+	DistanceKm      *float64       `json:"distance_km,omitempty"` // remaining, in a straight line
+	ETASeconds      *int           `json:"eta_seconds,omitempty"` // to the pickup, or to the destination when in progress
+	At              time.Time      `json:"at"`
+}
+
+// DriverLocationUpdate is a driver's reported location
+type DriverLocationUpdate struct {
+	DriverID  uuid.UUID `json:"driver_id"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	At        time.Time `json:"at"`
+}
@@ -21,6 +21,8 @@ type User struct {
 	Phone     string    `json:"phone" db:"phone" gorm:"uniqueIndex;not null"`
 	Name      string    `json:"name" db:"name" gorm:"not null"`
 	UserType  UserType  `json:"user_type" db:"user_type" gorm:"not null;check:user_type IN ('passenger', 'driver')"`
+	AvatarKey *string   `json:"avatar_key,omitempty" db:"avatar_key"` // storage key of the profile picture
+	AvatarURL string    `json:"avatar_url,omitempty" db:"-"`          // signed URL of the profile picture, set in responses
 	CreatedAt time.Time `json:"created_at" db:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
 }
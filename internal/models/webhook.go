@@ -0,0 +1,197 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvent is a trip lifecycle transition, fare dispute decision or safety incident API
+// consumers can subscribe to
+type WebhookEvent string
+
+const (
+	WebhookEventTripRequested       WebhookEvent = "trip.requested"
+	WebhookEventTripMatched         WebhookEvent = "trip.matched"
+	WebhookEventTripCompleted       WebhookEvent = "trip.completed"
+	WebhookEventTripCancelled       WebhookEvent = "trip.cancelled"
+	WebhookEventFareDisputeResolved WebhookEvent = "fare_dispute.resolved"
+	WebhookEventFareDisputeRejected WebhookEvent = "fare_dispute.rejected"
+	WebhookEventSafetyIncident      WebhookEvent = "safety_incident.reported"
+)
+
+// IsValid returns true if the event is supported
+func (e WebhookEvent) IsValid() bool {
+	switch e {
+	case WebhookEventTripRequested, WebhookEventTripMatched, WebhookEventTripCompleted, WebhookEventTripCancelled,
+		WebhookEventFareDisputeResolved, WebhookEventFareDisputeRejected, WebhookEventSafetyIncident:
+		return true
+	}
+	return false
+}
+
+// WebhookEventList is a set of subscribed events, stored as a comma-separated column
+type WebhookEventList []WebhookEvent
+
+// Contains reports whether the list includes the event. An empty list subscribes to every event.
+func (l WebhookEventList) Contains(event WebhookEvent) bool {
+	if len(l) == 0 {
+		return true
+	}
+	for _, e := range l {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Value implements driver.Valuer
+func (l WebhookEventList) Value() (driver.Value, error) {
+	events := make([]string, len(l))
+	for i, e := range l {
+		events[i] = string(e)
+	}
+	return strings.Join(events, ","), nil
+}
+
+// Scan implements sql.Scanner
+func (l *WebhookEventList) Scan(src interface{}) error {
+	var value string
+	switch v := src.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into WebhookEventList", src)
+	}
+
+	*l = nil
+	for _, e := range strings.Split(value, ",") {
+		if e != "" {
+			*l = append(*l, WebhookEvent(e))
+		}
+	}
+	return nil
+}
+
+// WebhookSubscription is a URL an API consumer registered to be called on trip lifecycle transitions.
+// Owner is the API key that registered it; consumers only see their own subscriptions.
+type WebhookSubscription struct {
+	ID        uuid.UUID        `json:"id" db:"id"`
+	Owner     string           `json:"-" db:"owner"`
+	URL       string           `json:"url" db:"url"`
+	Secret    string           `json:"secret,omitempty" db:"secret"`
+	Events    WebhookEventList `json:"events" db:"events"`
+	Active    bool             `json:"active" db:"active"`
+	CreatedAt time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// TableName returns the table name for WebhookSubscription
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// Validate validates the subscription data
+func (s *WebhookSubscription) Validate() error {
+	if s.Owner == "" {
+		return ErrInvalidWebhookOwner
+	}
+	u, err := url.Parse(s.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+	if s.Secret == "" {
+		return ErrInvalidWebhookSecret
+	}
+	for _, e := range s.Events {
+		if !e.IsValid() {
+			return ErrInvalidWebhookEvent
+		}
+	}
+	return nil
+}
+
+// WebhookDeliveryStatus represents the status of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// IsValid returns true if the status is supported
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliverySucceeded, WebhookDeliveryFailed:
+		return true
+	}
+	return false
+}
+
+// WebhookDelivery tracks one event sent to one subscription. A pending delivery is attempted
+// again at NextAttemptAt; it fails for good once its retries are exhausted.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `json:"id" db:"id"`
+	SubscriptionID uuid.UUID             `json:"subscription_id" db:"subscription_id"`
+	Event          WebhookEvent          `json:"event" db:"event"`
+	TripID         uuid.UUID             `json:"trip_id" db:"trip_id"`
+	Payload        json.RawMessage       `json:"payload" db:"payload" swaggertype:"object"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	ResponseStatus *int                  `json:"response_status,omitempty" db:"response_status"`
+	LastError      *string               `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt  *time.Time            `json:"next_attempt_at,omitempty" db:"next_attempt_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// TableName returns the table name for WebhookDelivery
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// WebhookDeliveryFilter selects deliveries for the admin delivery listing; zero fields match everything
+type WebhookDeliveryFilter struct {
+	SubscriptionID *uuid.UUID
+	TripID         *uuid.UUID
+	Status         WebhookDeliveryStatus
+	Limit          int
+	Offset         int
+}
+
+// WebhookPayload is the JSON body posted to a subscription. Dispute is set for fare dispute events
+// and Incident for safety incidents.
+type WebhookPayload struct {
+	ID         uuid.UUID       `json:"id"`
+	Event      WebhookEvent    `json:"event"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Trip       *Trip           `json:"trip"`
+	Dispute    *FareDispute    `json:"dispute,omitempty"`
+	Incident   *SafetyIncident `json:"incident,omitempty"`
+}
+
+// WebhookEventForStatus returns the event published when a trip enters the status, if any
+func WebhookEventForStatus(status TripStatus) (WebhookEvent, bool) {
+	switch status {
+	case TripStatusRequested:
+		return WebhookEventTripRequested, true
+	case TripStatusMatched:
+		return WebhookEventTripMatched, true
+	case TripStatusCompleted:
+		return WebhookEventTripCompleted, true
+	case TripStatusCancelled:
+		return WebhookEventTripCancelled, true
+	}
+	return "", false
+}
@@ -0,0 +1,168 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/config"
+)
+
+// Sender sends rendered emails
+type Sender interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// SMTPSender sends emails through an SMTP server, upgrading the connection with STARTTLS when
+// the server offers it and authenticating with AUTH PLAIN when a username is configured
+type SMTPSender struct {
+	addr     string
+	host     string
+	from     *mail.Address
+	username string
+	password string
+	timeout  time.Duration
+}
+
+// NewSMTPSender creates a sender for the SMTP server of the email configuration
+func NewSMTPSender(cfg config.EmailConfig) (*SMTPSender, error) {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address: %w", err)
+	}
+	return &SMTPSender{
+		addr:     net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)),
+		host:     cfg.SMTPHost,
+		from:     from,
+		username: cfg.Username,
+		password: cfg.Password,
+		timeout:  cfg.Timeout,
+	}, nil
+}
+
+// Send delivers a message in one SMTP session
+func (s *SMTPSender) Send(ctx context.Context, msg *Message) error {
+	to, err := mail.ParseAddress(msg.To)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+	body, err := BuildMIME(s.from, to, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("SMTP STARTTLS failed: %w", err)
+		}
+	}
+	if s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(s.from.Address); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM rejected: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("SMTP RCPT TO rejected: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA rejected: %w", err)
+	}
+	if _, err := w.Write(body); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write SMTP message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP message rejected: %w", err)
+	}
+	return client.Quit()
+}
+
+// BuildMIME encodes a message as a multipart/alternative email with a quoted-printable text and
+// HTML body, sent at date
+func BuildMIME(from, to *mail.Address, msg *Message, date time.Time) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+
+	header := func(name, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", name, value)
+	}
+	header("From", from.String())
+	header("To", to.String())
+	header("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	header("Date", date.Format(time.RFC1123Z))
+	header("Message-ID", messageID(from))
+	header("MIME-Version", "1.0")
+	header("Content-Type", mime.FormatMediaType("multipart/alternative", map[string]string{"boundary": writer.Boundary()}))
+	buf.WriteString("\r\n")
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		w, err := writer.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode email: %w", err)
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, fmt.Errorf("failed to encode email: %w", err)
+		}
+		if err := qp.Close(); err != nil {
+			return nil, fmt.Errorf("failed to encode email: %w", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode email: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// messageID returns a unique Message-ID in the domain of the sender
+func messageID(from *mail.Address) string {
+	domain := "localhost"
+	if at := strings.LastIndexByte(from.Address, '@'); at >= 0 {
+		domain = from.Address[at+1:]
+	}
+	random := make([]byte, 16)
+	rand.Read(random)
+	return "<" + hex.EncodeToString(random) + "@" + domain + ">"
+}
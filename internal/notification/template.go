@@ -0,0 +1,136 @@
+// Package notification renders the emails sent to passengers and drivers from templates and
+// sends them over SMTP. Every template has a plain text and an HTML body, sent together as a
+// multipart/alternative message.
+package notification
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates
+var templateFiles embed.FS
+
+// Template names an email template
+type Template string
+
+const (
+	TemplateReceipt        Template = "receipt"         // a passenger's trip receipt, data ReceiptData
+	TemplatePasswordReset  Template = "password_reset"  // a password reset link, data PasswordResetData
+	TemplateWeeklyEarnings Template = "weekly_earnings" // a driver's weekly summary, data WeeklyEarningsData
+)
+
+// Templates are every email template
+var Templates = []Template{TemplateReceipt, TemplatePasswordReset, TemplateWeeklyEarnings}
+
+// ReceiptData is the data of a trip receipt
+type ReceiptData struct {
+	Name            string
+	TripID          string
+	CompletedAt     time.Time
+	Pickup          string
+	Destination     string
+	DistanceKm      float64
+	DurationMinutes int
+	Fare            float64
+}
+
+// PasswordResetData is the data of a password reset email
+type PasswordResetData struct {
+	Name      string
+	ResetURL  string
+	ExpiresAt time.Time
+}
+
+// WeeklyEarningsData is the data of a driver's weekly earnings summary. WeekEnd is the last day
+// of the week.
+type WeeklyEarningsData struct {
+	Name           string
+	WeekStart      time.Time
+	WeekEnd        time.Time
+	Earnings       float64
+	TripsCompleted int
+	TripsCancelled int
+	OnlineHours    float64
+	Rating         float64
+}
+
+// Message is an email rendered from a template
+type Message struct {
+	To       string
+	Template Template
+	Subject  string
+	Text     string
+	HTML     string
+}
+
+// templateFuncs are the functions available to the templates
+var templateFuncs = map[string]interface{}{
+	"money": func(amount float64) string { return fmt.Sprintf("%.2f", amount) },
+	"date":  func(t time.Time) string { return t.UTC().Format("2 Jan 2006 15:04 UTC") },
+	"day":   func(t time.Time) string { return t.UTC().Format("2 Jan 2006") },
+}
+
+// parsedTemplate is a template's subject and text body, and its HTML body
+type parsedTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// Renderer renders the email templates
+type Renderer struct {
+	templates map[Template]parsedTemplate
+}
+
+// NewRenderer parses the email templates
+func NewRenderer() (*Renderer, error) {
+	r := &Renderer{templates: make(map[Template]parsedTemplate, len(Templates))}
+	for _, name := range Templates {
+		text, err := texttemplate.New(string(name)+".txt").Funcs(templateFuncs).ParseFS(templateFiles, "templates/"+string(name)+".txt")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s text template: %w", name, err)
+		}
+		if text.Lookup("subject") == nil {
+			return nil, fmt.Errorf("%s text template does not define a subject", name)
+		}
+		html, err := htmltemplate.New(string(name)+".html").Funcs(templateFuncs).ParseFS(templateFiles, "templates/"+string(name)+".html")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s HTML template: %w", name, err)
+		}
+		r.templates[name] = parsedTemplate{text: text, html: html}
+	}
+	return r, nil
+}
+
+// Render renders a template with its data into a message to the address to
+func (r *Renderer) Render(name Template, to string, data interface{}) (*Message, error) {
+	t, ok := r.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := t.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, fmt.Errorf("failed to render %s subject: %w", name, err)
+	}
+	if err := t.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s text: %w", name, err)
+	}
+	if err := t.html.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s HTML: %w", name, err)
+	}
+
+	return &Message{
+		To:       to,
+		Template: name,
+		// Header values cannot span lines
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
@@ -0,0 +1,95 @@
+package observability
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// AlertEvaluator raises an alert when error and fatal events reach a threshold within a
+// rolling window. Alerts are logged and published back on the bus as alert_triggered events;
+// after firing it stays quiet for one window.
+type AlertEvaluator struct {
+	threshold int
+	window    time.Duration
+	events    bus.Publisher
+	logger    *logging.Logger
+
+	errors    []time.Time // timestamps of error events within the window
+	lastAlert time.Time
+	mu        sync.Mutex
+}
+
+// NewAlertEvaluator creates an evaluator alerting on threshold error events within window
+func NewAlertEvaluator(threshold int, window time.Duration, events bus.Publisher, logger *logging.Logger) *AlertEvaluator {
+	return &AlertEvaluator{
+		threshold: threshold,
+		window:    window,
+		events:    events,
+		logger:    logger.WithComponent("alert_evaluator"),
+	}
+}
+
+// HandleMessage counts error events and raises an alert once the threshold is reached
+func (a *AlertEvaluator) HandleMessage(msg bus.Message) {
+	event, ok := msg.Payload.(*models.EventLog)
+	if !ok || (event.Severity != models.EventSeverityError && event.Severity != models.EventSeverityFatal) {
+		return
+	}
+
+	a.mu.Lock()
+	now := msg.PublishedAt
+	cutoff := now.Add(-a.window)
+	kept := a.errors[:0]
+	for _, t := range a.errors {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	a.errors = append(kept, now)
+
+	count := len(a.errors)
+	fire := count >= a.threshold && now.Sub(a.lastAlert) >= a.window
+	if fire {
+		a.lastAlert = now
+	}
+	a.mu.Unlock()
+
+	if fire {
+		a.raise(count, event)
+	}
+}
+
+// raise logs the alert and publishes it as an event
+func (a *AlertEvaluator) raise(count int, latest *models.EventLog) {
+	a.logger.WithFields(logging.Fields{
+		"error_events":      count,
+		"window":            a.window.String(),
+		"latest_event_type": latest.EventType,
+	}).Warn("Error event threshold reached")
+
+	eventData, _ := json.Marshal(map[string]interface{}{
+		"error_events":      count,
+		"threshold":         a.threshold,
+		"window":            a.window.String(),
+		"latest_event_id":   latest.ID,
+		"latest_event_type": latest.EventType,
+	})
+	a.events.Publish(bus.TopicEventLog, &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     "alert_triggered",
+		EventCategory: models.EventCategorySystem,
+		EventData:     eventData,
+		Severity:      models.EventSeverityWarn,
+		Message:       fmt.Sprintf("%d error events in the last %s", count, a.window),
+		Timestamp:     time.Now(),
+		CreatedAt:     time.Now(),
+	})
+}
@@ -4,14 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"sync"
 	"time"
 
 	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/bus"
 	"actor-model-observability/internal/config"
 	"actor-model-observability/internal/database"
+	"actor-model-observability/internal/experiment"
+	"actor-model-observability/internal/hlc"
 	"actor-model-observability/internal/logging"
 	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/payload"
 
 	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
@@ -36,34 +41,111 @@ type MetricsCollector struct {
 	metricsLock    sync.RWMutex
 
 	// Collection intervals
-	collectionInterval time.Duration
-	flushInterval      time.Duration
+	collectionInterval   time.Duration
+	flushInterval        time.Duration
+	collectionIntervalCh chan time.Duration
+	flushIntervalCh      chan time.Duration
 
 	// Batch processing
 	batchSize int
+
+	// Bus recorded events are published to instead of being batched here
+	events bus.Publisher
+
+	// Guard bounding the size of batched payloads; nil stores them verbatim
+	guard *payload.Guard
+
+	// Streams real-time messages and metrics are published to; nil writes keys and lists
+	streams *RealtimeStreams
+
+	// Experiment schema batches are written to; nil writes them to the default schema
+	target *experiment.Target
+
+	// The collector's own counters
+	counters *collectorCounters
 }
 
 // NewMetricsCollector creates a new metrics collector
 func NewMetricsCollector(db *database.PostgresDB, redis *redis.Client, cfg *config.Config, logger *logging.Logger) *MetricsCollector {
 	return &MetricsCollector{
-		db:                 db,
-		redis:              redis,
-		logger:             logger.WithComponent("metrics_collector"),
-		config:             cfg,
-		actorMetrics:       make(map[string]*models.ActorInstance),
-		messageMetrics:     make([]*models.ActorMessage, 0),
-		systemMetrics:      make([]*models.SystemMetric, 0),
-		traces:             make([]*models.DistributedTrace, 0),
-		eventLogs:          make([]*models.EventLog, 0),
-		collectionInterval: cfg.Observability.MetricsInterval,
-		flushInterval:      cfg.Observability.MetricsInterval, // use same interval for flushing
-		batchSize:          100,                               // default batch size
+		db:                   db,
+		redis:                redis,
+		logger:               logger.WithComponent("metrics_collector"),
+		config:               cfg,
+		actorMetrics:         make(map[string]*models.ActorInstance),
+		messageMetrics:       make([]*models.ActorMessage, 0),
+		systemMetrics:        make([]*models.SystemMetric, 0),
+		traces:               make([]*models.DistributedTrace, 0),
+		eventLogs:            make([]*models.EventLog, 0),
+		collectionInterval:   cfg.Observability.MetricsInterval,
+		flushInterval:        cfg.Observability.MetricsInterval, // use same interval for flushing
+		batchSize:            100,                               // default batch size
+		collectionIntervalCh: make(chan time.Duration, 1),
+		flushIntervalCh:      make(chan time.Duration, 1),
+		counters:             newCollectorCounters(cfg.Observability.MetricsInterval),
+	}
+}
+
+// SetIntervals changes the collection and flush intervals of a running collector.
+// Non-positive values leave the corresponding interval unchanged.
+func (mc *MetricsCollector) SetIntervals(collection, flush time.Duration) {
+	if collection > 0 {
+		sendLatestInterval(mc.collectionIntervalCh, collection)
+	}
+	if flush > 0 {
+		sendLatestInterval(mc.flushIntervalCh, flush)
+	}
+
+	mc.logger.WithFields(logging.Fields{
+		"collection_interval": collection,
+		"flush_interval":      flush,
+	}).Info("Metrics collector intervals updated")
+}
+
+// SetEventBus publishes recorded events to the event bus, which persists and streams them,
+// instead of batching them for the next flush
+func (mc *MetricsCollector) SetEventBus(events bus.Publisher) {
+	mc.metricsLock.Lock()
+	defer mc.metricsLock.Unlock()
+	mc.events = events
+}
+
+// SetExperimentTarget writes batches to the schema of the active experiment
+func (mc *MetricsCollector) SetExperimentTarget(target *experiment.Target) {
+	mc.metricsLock.Lock()
+	defer mc.metricsLock.Unlock()
+	mc.target = target
+}
+
+// SetPayloadGuard compresses or truncates oversized message payloads and event data batched
+// by the collector. Events published to the event bus are guarded by the persister instead.
+func (mc *MetricsCollector) SetPayloadGuard(guard *payload.Guard) {
+	mc.metricsLock.Lock()
+	defer mc.metricsLock.Unlock()
+	mc.guard = guard
+}
+
+// SetRealtimeStreams publishes real-time messages and system metrics to Redis Streams instead
+// of keys and lists, and records the lag of the streams' consumer groups as system metrics
+func (mc *MetricsCollector) SetRealtimeStreams(streams *RealtimeStreams) {
+	mc.metricsLock.Lock()
+	defer mc.metricsLock.Unlock()
+	mc.streams = streams
+}
+
+// sendLatestInterval replaces any pending interval update with the given one
+func sendLatestInterval(ch chan time.Duration, interval time.Duration) {
+	select {
+	case <-ch:
+	default:
 	}
+	ch <- interval
 }
 
 // Start begins the metrics collection process
 func (mc *MetricsCollector) Start(ctx context.Context) error {
 	mc.ctx, mc.cancel = context.WithCancel(ctx)
+	mc.counters.started(time.Now())
 
 	// Start collection goroutines
 	mc.wg.Add(2)
@@ -94,12 +176,16 @@ func (mc *MetricsCollector) Stop() error {
 
 // CollectActorMetrics collects metrics from an actor system
 func (mc *MetricsCollector) CollectActorMetrics(system *actor.ActorSystem) {
+	// The stream stats take round trips to Redis, which recording shouldn't wait on
+	streamStats := mc.collectStreamStats()
+
 	mc.metricsLock.Lock()
 	defer mc.metricsLock.Unlock()
 
 	// Collect system-level metrics
 	systemMetrics := system.GetMetrics()
 	mc.recordSystemMetrics(systemMetrics)
+	mc.recordStreamMetrics(streamStats)
 
 	// Collect individual actor metrics
 	actors := system.ListActors()
@@ -110,6 +196,23 @@ func (mc *MetricsCollector) CollectActorMetrics(system *actor.ActorSystem) {
 
 // RecordMessage records a message exchange between actors
 func (mc *MetricsCollector) RecordMessage(from, to, messageType string, payload interface{}, timestamp time.Time) {
+	mc.recordMessage(from, to, messageType, payload, timestamp, "", "", actor.InitialMessageVersion, nil)
+}
+
+// RecordActorMessage records a message sent to an actor, linked to the business entity
+// carried by its envelope
+func (mc *MetricsCollector) RecordActorMessage(to string, message actor.Message) {
+	entityType, entityID := message.GetEntity()
+	version := message.GetVersion()
+	if version == 0 {
+		version = actor.InitialMessageVersion
+	}
+	mc.recordMessage(message.GetSender(), to, message.GetType(), message.GetPayload(), message.GetTimestamp(), entityType, entityID, version, message.GetHLC())
+}
+
+// recordMessage buffers an actor message record, with the hybrid timestamp of sending the
+// message if it has one
+func (mc *MetricsCollector) recordMessage(from, to, messageType string, payload interface{}, timestamp time.Time, entityType, entityID string, version int, sent *hlc.Timestamp) {
 	mc.metricsLock.Lock()
 	defer mc.metricsLock.Unlock()
 
@@ -125,12 +228,15 @@ func (mc *MetricsCollector) RecordMessage(from, to, messageType string, payload
 		ReceiverActorID:   to,
 		MessageType:       messageType,
 		MessagePayload:    payloadJSON,
+		MessageVersion:    version,
 		Status:            models.MessageStatusSent,
 		SentAt:            timestamp,
+		HLC:               sent,
 		CreatedAt:         time.Now(),
 	}
+	message.EntityType, message.EntityID = models.EntityLink(entityType, entityID)
 
-	mc.messageMetrics = append(mc.messageMetrics, message)
+	mc.messageMetrics = append(mc.messageMetrics, mc.guard.ActorMessage(message))
 
 	// Also store in Redis for real-time access
 	mc.storeMessageInRedis(message)
@@ -186,7 +292,20 @@ func (mc *MetricsCollector) RecordEvent(eventType, source, description string, m
 		CreatedAt:     time.Now(),
 	}
 
-	mc.eventLogs = append(mc.eventLogs, event)
+	// Link trip events to the trip so they can be looked up from it
+	if tripID, ok := metadata["trip_id"].(string); ok {
+		if id, err := uuid.Parse(tripID); err == nil {
+			entityType := "trip"
+			event.EntityType = &entityType
+			event.EntityID = &id
+		}
+	}
+
+	if mc.events != nil {
+		mc.events.Publish(bus.TopicEventLog, event)
+	} else {
+		mc.eventLogs = append(mc.eventLogs, mc.guard.EventLog(event))
+	}
 
 	// Log critical events
 	if eventType == "error" || eventType == "critical" {
@@ -233,6 +352,7 @@ func (mc *MetricsCollector) recordActorMetrics(actorRef *actor.ActorRef) {
 		CreatedAt:     time.Now(),
 		UpdatedAt:     time.Now(),
 	}
+	actorInstance.EntityType, actorInstance.EntityID = models.EntityLink(actorRef.EntityType, actorRef.EntityID)
 
 	mc.actorMetrics[actorRef.ID] = actorInstance
 
@@ -251,10 +371,182 @@ func (mc *MetricsCollector) recordSystemMetrics(metrics actor.SystemMetrics) {
 		CreatedAt:   time.Now(),
 	}
 
-	mc.systemMetrics = append(mc.systemMetrics, systemMetric)
+	duplicateMetric := &models.SystemMetric{
+		ID:          uuid.New(),
+		MetricName:  "actor_messages_duplicated",
+		MetricType:  models.MetricTypeCounter,
+		MetricValue: float64(metrics.DuplicateMessages),
+		Timestamp:   time.Now(),
+		CreatedAt:   time.Now(),
+	}
+
+	mc.systemMetrics = append(mc.systemMetrics, systemMetric, duplicateMetric)
 
 	// Store in Redis for real-time dashboards
 	mc.storeSystemMetricsInRedis(systemMetric)
+	mc.recordPoolMetrics(metrics)
+	mc.recordPassivationMetrics(metrics.Passivation)
+	mc.recordGoroutineMetrics(metrics.Goroutines)
+	mc.recordConversionMetrics(metrics.MessageConversions)
+}
+
+// recordPoolMetrics records the spawn latency of each actor type and the utilization of the
+// warm pools, which tell how much of the ride latency went into spawning actors
+func (mc *MetricsCollector) recordPoolMetrics(metrics actor.SystemMetrics) {
+	now := time.Now()
+	gauge := func(name string, value float64, actorType string) {
+		labelsJSON, _ := json.Marshal(map[string]string{"actor_type": actorType})
+		mc.systemMetrics = append(mc.systemMetrics, &models.SystemMetric{
+			ID:          uuid.New(),
+			MetricName:  name,
+			MetricType:  models.MetricTypeGauge,
+			MetricValue: value,
+			Labels:      labelsJSON,
+			Timestamp:   now,
+			CreatedAt:   now,
+		})
+	}
+
+	for actorType, spawns := range metrics.Spawns {
+		gauge("actor_spawn_latency_avg_ms", float64(spawns.AverageLatency)/float64(time.Millisecond), actorType)
+		gauge("actor_spawn_latency_max_ms", float64(spawns.MaxLatency)/float64(time.Millisecond), actorType)
+		gauge("actor_spawn_rejected", float64(spawns.Rejected), actorType)
+	}
+	for actorType, pool := range metrics.Pools {
+		gauge("actor_pool_utilization", pool.Utilization, actorType)
+		gauge("actor_pool_misses", float64(pool.Misses), actorType)
+	}
+}
+
+// recordPassivationMetrics records the idle actors passivated and revived by actor type, which
+// tell how much memory passivation saves and how often it costs a revival
+func (mc *MetricsCollector) recordPassivationMetrics(passivation map[string]actor.PassivationMetrics) {
+	now := time.Now()
+	record := func(name string, metricType models.MetricType, value float64, actorType string) {
+		labelsJSON, _ := json.Marshal(map[string]string{"actor_type": actorType})
+		mc.systemMetrics = append(mc.systemMetrics, &models.SystemMetric{
+			ID:          uuid.New(),
+			MetricName:  name,
+			MetricType:  metricType,
+			MetricValue: value,
+			Labels:      labelsJSON,
+			Timestamp:   now,
+			CreatedAt:   now,
+		})
+	}
+
+	for actorType, metrics := range passivation {
+		record("actor_passivated", models.MetricTypeGauge, float64(metrics.Passivated), actorType)
+		record("actor_passivations_total", models.MetricTypeCounter, float64(metrics.Passivations), actorType)
+		record("actor_revivals_total", models.MetricTypeCounter, float64(metrics.Revivals), actorType)
+	}
+}
+
+// recordGoroutineMetrics records the goroutine counts of the last leak audit, which tell whether
+// the goroutines of stopped actors return to baseline
+func (mc *MetricsCollector) recordGoroutineMetrics(goroutines actor.GoroutineMetrics) {
+	now := time.Now()
+	gauge := func(name string, value int, labels map[string]string) {
+		var labelsJSON json.RawMessage
+		if labels != nil {
+			labelsJSON, _ = json.Marshal(labels)
+		}
+		mc.systemMetrics = append(mc.systemMetrics, &models.SystemMetric{
+			ID:          uuid.New(),
+			MetricName:  name,
+			MetricType:  models.MetricTypeGauge,
+			MetricValue: float64(value),
+			Labels:      labelsJSON,
+			Timestamp:   now,
+			CreatedAt:   now,
+		})
+	}
+
+	gauge("goroutines_total", goroutines.Total, nil)
+	gauge("actor_goroutines_tracked", goroutines.Tracked, nil)
+	for actorType, leaked := range goroutines.Leaked {
+		gauge("actor_goroutines_leaked", leaked, map[string]string{"actor_type": actorType})
+	}
+}
+
+// recordConversionMetrics records how many persisted payloads each schema up-converter upgraded,
+// which tells when old message versions are no longer seen and their converters can go
+func (mc *MetricsCollector) recordConversionMetrics(conversions []actor.MessageConversion) {
+	now := time.Now()
+	for _, conversion := range conversions {
+		labelsJSON, _ := json.Marshal(map[string]string{
+			"message_type": conversion.MessageType,
+			"from_version": strconv.Itoa(conversion.FromVersion),
+			"to_version":   strconv.Itoa(conversion.ToVersion),
+		})
+		mc.systemMetrics = append(mc.systemMetrics, &models.SystemMetric{
+			ID:          uuid.New(),
+			MetricName:  "actor_message_conversions",
+			MetricType:  models.MetricTypeCounter,
+			MetricValue: float64(conversion.Count),
+			Labels:      labelsJSON,
+			Timestamp:   now,
+			CreatedAt:   now,
+		})
+	}
+}
+
+// realtimeStreams returns the Redis Streams publisher, nil when real-time data goes to keys
+// and lists
+func (mc *MetricsCollector) realtimeStreams() *RealtimeStreams {
+	mc.metricsLock.RLock()
+	defer mc.metricsLock.RUnlock()
+	return mc.streams
+}
+
+// collectStreamStats reads the length of the real-time streams and the lag of their consumer
+// groups, skipping the streams whose stats can't be read
+func (mc *MetricsCollector) collectStreamStats() []*StreamStats {
+	streams := mc.realtimeStreams()
+	if streams == nil {
+		return nil
+	}
+
+	var result []*StreamStats
+	for _, stream := range []string{MessagesStream, MetricsStream} {
+		stats, err := streams.Stats(mc.ctx, stream)
+		if err != nil {
+			mc.counters.redisFailed()
+			mc.logger.WithError(err).Warn("Failed to get real-time stream stats")
+			continue
+		}
+		result = append(result, stats)
+	}
+	return result
+}
+
+// recordStreamMetrics records the length of the real-time streams and the lag of their
+// consumer groups. Groups whose lag Redis can't tell get no lag gauge.
+func (mc *MetricsCollector) recordStreamMetrics(streamStats []*StreamStats) {
+	now := time.Now()
+	gauge := func(name string, value int64, labels map[string]string) {
+		labelsJSON, _ := json.Marshal(labels)
+		mc.systemMetrics = append(mc.systemMetrics, &models.SystemMetric{
+			ID:          uuid.New(),
+			MetricName:  name,
+			MetricType:  models.MetricTypeGauge,
+			MetricValue: float64(value),
+			Labels:      labelsJSON,
+			Timestamp:   now,
+			CreatedAt:   now,
+		})
+	}
+
+	for _, stats := range streamStats {
+		gauge("realtime_stream_length", stats.Length, map[string]string{"stream": stats.Stream})
+		for _, group := range stats.Groups {
+			labels := map[string]string{"stream": stats.Stream, "group": group.Group}
+			if group.Lag >= 0 {
+				gauge("realtime_stream_lag", group.Lag, labels)
+			}
+			gauge("realtime_stream_pending", group.Pending, labels)
+		}
+	}
 }
 
 // metricsCollectionLoop runs the periodic metrics collection
@@ -269,6 +561,9 @@ func (mc *MetricsCollector) metricsCollectionLoop() {
 		case <-ticker.C:
 			// Collection is triggered externally via CollectActorMetrics
 			// This loop just maintains the ticker for consistency
+		case interval := <-mc.collectionIntervalCh:
+			mc.collectionInterval = interval
+			ticker.Reset(interval)
 		case <-mc.ctx.Done():
 			return
 		}
@@ -286,6 +581,10 @@ func (mc *MetricsCollector) flushLoop() {
 		select {
 		case <-ticker.C:
 			mc.flushMetrics()
+		case interval := <-mc.flushIntervalCh:
+			mc.flushInterval = interval
+			mc.counters.setFlushInterval(interval)
+			ticker.Reset(interval)
 		case <-mc.ctx.Done():
 			return
 		}
@@ -298,6 +597,7 @@ func (mc *MetricsCollector) flushMetrics() {
 	defer mc.metricsLock.Unlock()
 
 	start := time.Now()
+	mc.counters.flushStarted(mc.buffered())
 
 	// Flush actor instances
 	if len(mc.actorMetrics) > 0 {
@@ -325,6 +625,7 @@ func (mc *MetricsCollector) flushMetrics() {
 	}
 
 	flushDuration := time.Since(start)
+	mc.counters.flushFinished(time.Now(), flushDuration)
 	mc.logger.WithField("flush_duration", flushDuration).Debug("Metrics flushed to database")
 }
 
@@ -337,6 +638,7 @@ func (mc *MetricsCollector) flushActorMetrics() {
 
 	if len(instances) > 0 {
 		if err := mc.insertActorInstancesBatch(instances); err != nil {
+			mc.counters.batchFailed(CollectorActorInstances, len(instances))
 			mc.logger.WithError(err).Error("Failed to flush actor metrics")
 		} else {
 			mc.logger.WithField("count", len(instances)).Debug("Actor metrics flushed")
@@ -351,6 +653,7 @@ func (mc *MetricsCollector) flushActorMetrics() {
 func (mc *MetricsCollector) flushMessageMetrics() {
 	if len(mc.messageMetrics) > 0 {
 		if err := mc.insertMessagesBatch(mc.messageMetrics); err != nil {
+			mc.counters.batchFailed(CollectorMessages, len(mc.messageMetrics))
 			mc.logger.WithError(err).Error("Failed to flush message metrics")
 		} else {
 			mc.logger.WithField("count", len(mc.messageMetrics)).Debug("Message metrics flushed")
@@ -365,6 +668,7 @@ func (mc *MetricsCollector) flushMessageMetrics() {
 func (mc *MetricsCollector) flushSystemMetrics() {
 	if len(mc.systemMetrics) > 0 {
 		if err := mc.insertSystemMetricsBatch(mc.systemMetrics); err != nil {
+			mc.counters.batchFailed(CollectorSystemMetrics, len(mc.systemMetrics))
 			mc.logger.WithError(err).Error("Failed to flush system metrics")
 		} else {
 			mc.logger.WithField("count", len(mc.systemMetrics)).Debug("System metrics flushed")
@@ -379,6 +683,7 @@ func (mc *MetricsCollector) flushSystemMetrics() {
 func (mc *MetricsCollector) flushTraces() {
 	if len(mc.traces) > 0 {
 		if err := mc.insertTracesBatch(mc.traces); err != nil {
+			mc.counters.batchFailed(CollectorTraces, len(mc.traces))
 			mc.logger.WithError(err).Error("Failed to flush traces")
 		} else {
 			mc.logger.WithField("count", len(mc.traces)).Debug("Traces flushed")
@@ -393,6 +698,7 @@ func (mc *MetricsCollector) flushTraces() {
 func (mc *MetricsCollector) flushEventLogs() {
 	if len(mc.eventLogs) > 0 {
 		if err := mc.insertEventLogsBatch(mc.eventLogs); err != nil {
+			mc.counters.batchFailed(CollectorEventLogs, len(mc.eventLogs))
 			mc.logger.WithError(err).Error("Failed to flush event logs")
 		} else {
 			mc.logger.WithField("count", len(mc.eventLogs)).Debug("Event logs flushed")
@@ -403,6 +709,30 @@ func (mc *MetricsCollector) flushEventLogs() {
 	mc.eventLogs = mc.eventLogs[:0]
 }
 
+// Stats returns the collector's own counters. While a flush holds the buffers their sizes are
+// those at the start of the flush.
+func (mc *MetricsCollector) Stats() *CollectorStats {
+	if mc.counters.flushing() {
+		return mc.counters.stats(time.Now(), nil)
+	}
+	mc.metricsLock.RLock()
+	buffered := mc.buffered()
+	mc.metricsLock.RUnlock()
+	return mc.counters.stats(time.Now(), buffered)
+}
+
+// buffered returns the number of records of each kind waiting for the next flush. The caller
+// must hold the metrics lock.
+func (mc *MetricsCollector) buffered() map[string]int {
+	return map[string]int{
+		CollectorActorInstances: len(mc.actorMetrics),
+		CollectorMessages:       len(mc.messageMetrics),
+		CollectorSystemMetrics:  len(mc.systemMetrics),
+		CollectorTraces:         len(mc.traces),
+		CollectorEventLogs:      len(mc.eventLogs),
+	}
+}
+
 // storeActorMetricsInRedis stores actor metrics in Redis for real-time access
 func (mc *MetricsCollector) storeActorMetricsInRedis(instance *models.ActorInstance) {
 	// Skip Redis operations if Redis client is not available (e.g., in tests)
@@ -418,6 +748,7 @@ func (mc *MetricsCollector) storeActorMetricsInRedis(instance *models.ActorInsta
 	}
 
 	if err := mc.redis.Set(mc.ctx, key, data, time.Hour).Err(); err != nil {
+		mc.counters.redisFailed()
 		mc.logger.WithError(err).Error("Failed to store actor metrics in Redis")
 	}
 }
@@ -428,6 +759,13 @@ func (mc *MetricsCollector) storeMessageInRedis(message *models.ActorMessage) {
 	if mc.redis == nil {
 		return
 	}
+	if mc.streams != nil {
+		if _, err := mc.streams.Add(mc.ctx, MessagesStream, message); err != nil {
+			mc.counters.redisFailed()
+			mc.logger.WithError(err).Error("Failed to publish message to Redis stream")
+		}
+		return
+	}
 
 	key := fmt.Sprintf("message:%s", message.ID)
 	data, err := json.Marshal(message)
@@ -437,12 +775,16 @@ func (mc *MetricsCollector) storeMessageInRedis(message *models.ActorMessage) {
 	}
 
 	if err := mc.redis.Set(mc.ctx, key, data, 30*time.Minute).Err(); err != nil {
+		mc.counters.redisFailed()
 		mc.logger.WithError(err).Error("Failed to store message in Redis")
 	}
 
 	// Also add to recent messages list
 	listKey := "messages:recent"
-	mc.redis.LPush(mc.ctx, listKey, message.ID)
+	if err := mc.redis.LPush(mc.ctx, listKey, message.ID).Err(); err != nil {
+		mc.counters.redisFailed()
+		return
+	}
 	mc.redis.LTrim(mc.ctx, listKey, 0, 1000) // Keep only last 1000 messages
 	mc.redis.Expire(mc.ctx, listKey, time.Hour)
 }
@@ -453,6 +795,13 @@ func (mc *MetricsCollector) storeSystemMetricsInRedis(metric *models.SystemMetri
 	if mc.redis == nil {
 		return
 	}
+	if mc.streams != nil {
+		if _, err := mc.streams.Add(mc.ctx, MetricsStream, metric); err != nil {
+			mc.counters.redisFailed()
+			mc.logger.WithError(err).Error("Failed to publish system metrics to Redis stream")
+		}
+		return
+	}
 
 	key := "system:metrics:latest"
 	data, err := json.Marshal(metric)
@@ -462,6 +811,7 @@ func (mc *MetricsCollector) storeSystemMetricsInRedis(metric *models.SystemMetri
 	}
 
 	if err := mc.redis.Set(mc.ctx, key, data, time.Hour).Err(); err != nil {
+		mc.counters.redisFailed()
 		mc.logger.WithError(err).Error("Failed to store system metrics in Redis")
 	}
 }
@@ -474,6 +824,9 @@ func (mc *MetricsCollector) GetRealtimeMetrics() (map[string]interface{}, error)
 	if mc.redis == nil {
 		return result, nil
 	}
+	if streams := mc.realtimeStreams(); streams != nil {
+		return mc.getRealtimeStreamMetrics(streams, result)
+	}
 
 	// Get latest system metrics
 	systemData, err := mc.redis.Get(mc.ctx, "system:metrics:latest").Result()
@@ -499,67 +852,100 @@ func (mc *MetricsCollector) GetRealtimeMetrics() (map[string]interface{}, error)
 	return result, nil
 }
 
+// getRealtimeStreamMetrics returns real-time metrics read from the Redis Streams
+func (mc *MetricsCollector) getRealtimeStreamMetrics(streams *RealtimeStreams, result map[string]interface{}) (map[string]interface{}, error) {
+	latest, err := mc.redis.XRevRangeN(mc.ctx, MetricsStream, "+", "-", 1).Result()
+	if err == nil && len(latest) > 0 {
+		var systemMetrics models.SystemMetric
+		data, _ := latest[0].Values[streamDataField].(string)
+		if err := json.Unmarshal([]byte(data), &systemMetrics); err == nil {
+			result["system"] = systemMetrics
+		}
+	}
+
+	var streamStats []*StreamStats
+	for _, stream := range []string{MessagesStream, MetricsStream} {
+		stats, err := streams.Stats(mc.ctx, stream)
+		if err != nil {
+			return nil, err
+		}
+		if stream == MessagesStream {
+			result["recent_messages_count"] = stats.Length
+		}
+		streamStats = append(streamStats, stats)
+	}
+	result["streams"] = streamStats
+
+	// Get active actors count
+	actorKeys, err := mc.redis.Keys(mc.ctx, "actor:metrics:*").Result()
+	if err == nil {
+		result["active_actors_count"] = len(actorKeys)
+	}
+
+	return result, nil
+}
+
 // insertActorInstancesBatch inserts actor instances in batch using sqlx
 func (mc *MetricsCollector) insertActorInstancesBatch(instances []*models.ActorInstance) error {
-	if len(instances) == 0 {
+	if len(instances) == 0 || mc.db == nil {
 		return nil
 	}
 
-	query := `INSERT INTO actor_instances (id, actor_type, actor_id, entity_id, status, last_heartbeat, created_at, updated_at) 
-			  VALUES (:id, :actor_type, :actor_id, :entity_id, :status, :last_heartbeat, :created_at, :updated_at)`
+	query := `INSERT INTO actor_instances (id, actor_type, actor_id, entity_type, entity_id, status, last_heartbeat, created_at, updated_at) 
+			  VALUES (:id, :actor_type, :actor_id, :entity_type, :entity_id, :status, :last_heartbeat, :created_at, :updated_at)`
 
-	_, err := mc.db.NamedExec(query, instances)
+	_, err := mc.db.NamedExec(mc.target.Qualify(query), instances)
 	return err
 }
 
 // insertEventLogsBatch inserts event logs in batch using sqlx
 func (mc *MetricsCollector) insertEventLogsBatch(logs []*models.EventLog) error {
-	if len(logs) == 0 {
+	if len(logs) == 0 || mc.db == nil {
 		return nil
 	}
 
-	query := `INSERT INTO event_logs (id, trace_id, event_type, event_category, actor_type, actor_id, entity_type, entity_id, event_data, severity, message, timestamp, created_at) 
-			  VALUES (:id, :trace_id, :event_type, :event_category, :actor_type, :actor_id, :entity_type, :entity_id, :event_data, :severity, :message, :timestamp, :created_at)`
+	query := `INSERT INTO event_logs (id, trace_id, event_type, event_category, actor_type, actor_id, entity_type, entity_id, event_data, severity, message, timestamp, hlc, created_at) 
+			  VALUES (:id, :trace_id, :event_type, :event_category, :actor_type, :actor_id, :entity_type, :entity_id, :event_data, :severity, :message, :timestamp, :hlc, :created_at)`
 
-	_, err := mc.db.NamedExec(query, logs)
+	_, err := mc.db.NamedExec(mc.target.Qualify(query), logs)
 	return err
 }
 
 // insertTracesBatch inserts distributed traces in batch using sqlx
 func (mc *MetricsCollector) insertTracesBatch(traces []*models.DistributedTrace) error {
-	if len(traces) == 0 {
+	if len(traces) == 0 || mc.db == nil {
 		return nil
 	}
 
 	query := `INSERT INTO distributed_traces (id, trace_id, span_id, parent_span_id, operation_name, actor_type, actor_id, start_time, end_time, duration_ms, status, tags, logs, created_at) 
 			  VALUES (:id, :trace_id, :span_id, :parent_span_id, :operation_name, :actor_type, :actor_id, :start_time, :end_time, :duration_ms, :status, :tags, :logs, :created_at)`
 
-	_, err := mc.db.NamedExec(query, traces)
+	_, err := mc.db.NamedExec(mc.target.Qualify(query), traces)
 	return err
 }
 
 // insertSystemMetricsBatch inserts system metrics in batch using sqlx
 func (mc *MetricsCollector) insertSystemMetricsBatch(metrics []*models.SystemMetric) error {
-	if len(metrics) == 0 {
+	if len(metrics) == 0 || mc.db == nil {
 		return nil
 	}
 
 	query := `INSERT INTO system_metrics (id, metric_name, metric_type, metric_value, labels, actor_type, actor_id, timestamp, created_at) 
 			  VALUES (:id, :metric_name, :metric_type, :metric_value, :labels, :actor_type, :actor_id, :timestamp, :created_at)`
 
-	_, err := mc.db.NamedExec(query, metrics)
+	_, err := mc.db.NamedExec(mc.target.Qualify(query), metrics)
 	return err
 }
 
 // insertMessagesBatch inserts actor messages in batch using sqlx
 func (mc *MetricsCollector) insertMessagesBatch(messages []*models.ActorMessage) error {
-	if len(messages) == 0 {
+	if len(messages) == 0 || mc.db == nil {
 		return nil
 	}
 
-	query := `INSERT INTO actor_messages (id, trace_id, span_id, parent_span_id, sender_actor_type, sender_actor_id, receiver_actor_type, receiver_actor_id, message_type, message_payload, status, sent_at, received_at, processed_at, processing_duration_ms, error_message, created_at) 
-			  VALUES (:id, :trace_id, :span_id, :parent_span_id, :sender_actor_type, :sender_actor_id, :receiver_actor_type, :receiver_actor_id, :message_type, :message_payload, :status, :sent_at, :received_at, :processed_at, :processing_duration_ms, :error_message, :created_at)`
+	query := `INSERT INTO actor_messages (id, trace_id, span_id, parent_span_id, sender_actor_type, sender_actor_id, receiver_actor_type, receiver_actor_id, entity_type, entity_id, message_type, message_payload, message_version, status, sent_at, received_at, processed_at, processing_duration_ms, error_message, retry_count, effects, hlc, created_at) 
+			  VALUES (:id, :trace_id, :span_id, :parent_span_id, :sender_actor_type, :sender_actor_id, :receiver_actor_type, :receiver_actor_id, :entity_type, :entity_id, :message_type, :message_payload, :message_version, :status, :sent_at, :received_at, :processed_at, :processing_duration_ms, :error_message, :retry_count, :effects, :hlc, :created_at)`
 
-	_, err := mc.db.NamedExec(query, messages)
+	_, err := mc.db.NamedExec(mc.target.Qualify(query), messages)
 	return err
 }
@@ -0,0 +1,157 @@
+package observability
+
+import (
+	"sync"
+	"time"
+)
+
+// Kinds of records the collector buffers between flushes
+const (
+	CollectorActorInstances = "actor_instances"
+	CollectorMessages       = "messages"
+	CollectorSystemMetrics  = "system_metrics"
+	CollectorTraces         = "traces"
+	CollectorEventLogs      = "event_logs"
+)
+
+// CollectorStats are the collector's own counters, which tell when the observability pipeline
+// falls behind: records piling up between flushes, flushes slower than the flush interval, or
+// batches dropped because they failed to insert
+type CollectorStats struct {
+	Buffered            map[string]int   `json:"buffered"`          // records waiting for the next flush, by kind
+	FlushInProgress     bool             `json:"flush_in_progress"` // buffered is then as of the start of the flush
+	FlushInterval       string           `json:"flush_interval"`
+	Flushes             int64            `json:"flushes"`
+	LastFlushAt         *time.Time       `json:"last_flush_at,omitempty"`
+	LastFlushDurationMs float64          `json:"last_flush_duration_ms"`
+	MaxFlushDurationMs  float64          `json:"max_flush_duration_ms"`
+	FailedFlushes       map[string]int64 `json:"failed_flushes"`  // batches that failed to insert, by kind
+	DroppedRecords      map[string]int64 `json:"dropped_records"` // records of the failed batches, by kind
+	RedisErrors         int64            `json:"redis_errors"`    // failed Redis calls for real-time data
+	FallingBehind       bool             `json:"falling_behind"`  // a flush took the flush interval or longer, or none finished within two
+}
+
+// collectorCounters are the counters behind CollectorStats. They have a lock of their own so that
+// they can be read while a flush holds the buffers.
+type collectorCounters struct {
+	mu                sync.Mutex
+	startedAt         time.Time
+	flushStartedAt    time.Time // zero unless a flush is in progress
+	flushInterval     time.Duration
+	flushes           int64
+	lastFlushAt       time.Time
+	lastFlushDuration time.Duration
+	maxFlushDuration  time.Duration
+	failedFlushes     map[string]int64
+	droppedRecords    map[string]int64
+	redisErrors       int64
+	buffered          map[string]int // as of the start of the last flush
+}
+
+func newCollectorCounters(flushInterval time.Duration) *collectorCounters {
+	return &collectorCounters{
+		flushInterval:  flushInterval,
+		failedFlushes:  make(map[string]int64),
+		droppedRecords: make(map[string]int64),
+		buffered:       make(map[string]int),
+	}
+}
+
+// started records when the collector started flushing
+func (c *collectorCounters) started(at time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.startedAt = at
+}
+
+// setFlushInterval records a change of the flush interval
+func (c *collectorCounters) setFlushInterval(interval time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushInterval = interval
+}
+
+// flushStarted records the buffer sizes at the start of a flush
+func (c *collectorCounters) flushStarted(buffered map[string]int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.buffered = buffered
+	c.flushStartedAt = time.Now()
+}
+
+// flushFinished records a completed flush
+func (c *collectorCounters) flushFinished(at time.Time, duration time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.flushes++
+	c.flushStartedAt = time.Time{}
+	c.lastFlushAt = at
+	c.lastFlushDuration = duration
+	if duration > c.maxFlushDuration {
+		c.maxFlushDuration = duration
+	}
+}
+
+// flushing reports whether a flush is in progress
+func (c *collectorCounters) flushing() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return !c.flushStartedAt.IsZero()
+}
+
+// batchFailed records a batch of records of a kind dropped because it failed to insert
+func (c *collectorCounters) batchFailed(kind string, records int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.failedFlushes[kind]++
+	c.droppedRecords[kind] += int64(records)
+}
+
+// redisFailed records a failed write to Redis
+func (c *collectorCounters) redisFailed() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.redisErrors++
+}
+
+// stats returns the counters as of now, with the given buffer sizes, or those at the start of the
+// flush in progress when buffered is nil
+func (c *collectorCounters) stats(now time.Time, buffered map[string]int) *CollectorStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := &CollectorStats{
+		Buffered:            buffered,
+		FlushInProgress:     buffered == nil,
+		FlushInterval:       c.flushInterval.String(),
+		Flushes:             c.flushes,
+		LastFlushDurationMs: float64(c.lastFlushDuration) / float64(time.Millisecond),
+		MaxFlushDurationMs:  float64(c.maxFlushDuration) / float64(time.Millisecond),
+		FailedFlushes:       make(map[string]int64, len(c.failedFlushes)),
+		DroppedRecords:      make(map[string]int64, len(c.droppedRecords)),
+		RedisErrors:         c.redisErrors,
+	}
+	if stats.FlushInProgress {
+		stats.Buffered = make(map[string]int, len(c.buffered))
+		for kind, n := range c.buffered {
+			stats.Buffered[kind] = n
+		}
+	}
+	for kind, n := range c.failedFlushes {
+		stats.FailedFlushes[kind] = n
+	}
+	for kind, n := range c.droppedRecords {
+		stats.DroppedRecords[kind] = n
+	}
+
+	lastFlush := c.startedAt
+	if !c.lastFlushAt.IsZero() {
+		lastFlushAt := c.lastFlushAt
+		stats.LastFlushAt = &lastFlushAt
+		lastFlush = lastFlushAt
+	}
+	stats.FallingBehind = c.flushes > 0 && c.lastFlushDuration >= c.flushInterval ||
+		!c.flushStartedAt.IsZero() && now.Sub(c.flushStartedAt) >= c.flushInterval ||
+		!lastFlush.IsZero() && now.Sub(lastFlush) > 2*c.flushInterval
+	return stats
+}
@@ -0,0 +1,104 @@
+package observability
+
+import (
+	"sync"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/models"
+)
+
+// eventSubscriberBuffer is the number of events queued for a subscriber before newer events
+// are dropped for it, so a slow client never blocks event recording
+const eventSubscriberBuffer = 64
+
+// EventFilter selects the event logs a subscriber receives; empty fields match everything
+type EventFilter struct {
+	Severity  models.EventSeverity
+	Category  models.EventCategory
+	ActorType models.ActorType
+}
+
+// Matches reports whether an event log passes the filter
+func (f EventFilter) Matches(event *models.EventLog) bool {
+	if f.Severity != "" && event.Severity != f.Severity {
+		return false
+	}
+	if f.Category != "" && event.EventCategory != f.Category {
+		return false
+	}
+	if f.ActorType != "" && (event.ActorType == nil || *event.ActorType != f.ActorType) {
+		return false
+	}
+	return true
+}
+
+// EventStream fans out event logs to live subscribers as they are recorded
+type EventStream struct {
+	subscribers map[*eventSubscriber]struct{}
+	mu          sync.RWMutex
+}
+
+type eventSubscriber struct {
+	filter EventFilter
+	events chan *models.EventLog
+}
+
+// NewEventStream creates an event stream without subscribers
+func NewEventStream() *EventStream {
+	return &EventStream{
+		subscribers: make(map[*eventSubscriber]struct{}),
+	}
+}
+
+// Subscribe returns a channel receiving the published events matching filter, and a function
+// that ends the subscription and closes the channel
+func (s *EventStream) Subscribe(filter EventFilter) (<-chan *models.EventLog, func()) {
+	sub := &eventSubscriber{
+		filter: filter,
+		events: make(chan *models.EventLog, eventSubscriberBuffer),
+	}
+
+	s.mu.Lock()
+	s.subscribers[sub] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return sub.events, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(s.subscribers, sub)
+			s.mu.Unlock()
+			close(sub.events)
+		})
+	}
+}
+
+// Publish delivers an event to every subscriber whose filter matches it
+func (s *EventStream) Publish(event *models.EventLog) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for sub := range s.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+// HandleMessage publishes event logs delivered by the event bus to the stream's subscribers
+func (s *EventStream) HandleMessage(msg bus.Message) {
+	if event, ok := msg.Payload.(*models.EventLog); ok {
+		s.Publish(event)
+	}
+}
+
+// Subscribers returns the number of active subscriptions
+func (s *EventStream) Subscribers() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.subscribers)
+}
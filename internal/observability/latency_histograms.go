@@ -0,0 +1,134 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"actor-model-observability/internal/config"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Names of the latency histograms
+const (
+	MatchingDurationMetric  = "ride_matching_duration_seconds"
+	TripDurationMetric      = "trip_duration_seconds"
+	MessageProcessingMetric = "actor_message_processing_duration_seconds"
+	MatchingQueueWaitMetric = "matching_queue_wait_seconds"
+)
+
+// nativeHistogramMaxBuckets caps the native buckets of a series; the resolution is halved
+// when a series would exceed it
+const nativeHistogramMaxBuckets = 160
+
+// latencyHistograms records the latencies compared between the actor model and the traditional
+// approach, with the configured bucket boundaries. The OpenTelemetry Prometheus exporter does not
+// export native histograms, so with native histograms enabled they are Prometheus histograms
+// registered next to it instead, carrying both the classic and the native buckets.
+type latencyHistograms struct {
+	matching          latencyHistogram
+	tripDuration      latencyHistogram
+	messageProcessing latencyHistogram
+	matchingQueueWait latencyHistogram
+}
+
+// latencyHistogram records durations in seconds with the values of its labels, in order
+type latencyHistogram interface {
+	record(ctx context.Context, seconds float64, values ...string)
+}
+
+// otelHistogram is a latency histogram recorded with OpenTelemetry
+type otelHistogram struct {
+	histogram metric.Float64Histogram
+	labels    []string
+}
+
+func (h otelHistogram) record(ctx context.Context, seconds float64, values ...string) {
+	attrs := make([]attribute.KeyValue, len(h.labels))
+	for i, label := range h.labels {
+		attrs[i] = attribute.String(label, values[i])
+	}
+	h.histogram.Record(ctx, seconds, metric.WithAttributes(attrs...))
+}
+
+// nativeHistogram is a latency histogram recorded as a Prometheus native histogram
+type nativeHistogram struct {
+	vec *prom.HistogramVec
+}
+
+func (h nativeHistogram) record(_ context.Context, seconds float64, values ...string) {
+	h.vec.WithLabelValues(values...).Observe(seconds)
+}
+
+// newLatencyHistograms creates the latency histograms on meter or, with native histograms
+// enabled, registers them with registerer. Histograms without buckets get the default ones.
+func newLatencyHistograms(meter metric.Meter, registerer prom.Registerer, cfg config.HistogramConfig) (*latencyHistograms, error) {
+	defaults := config.DefaultHistogramConfig()
+	if cfg.NativeBucketFactor <= 1 {
+		cfg.NativeBucketFactor = defaults.NativeBucketFactor
+	}
+
+	create := func(name, description string, buckets, defaultBuckets []float64, labels ...string) (latencyHistogram, error) {
+		if len(buckets) == 0 {
+			buckets = defaultBuckets
+		}
+
+		if !cfg.NativeHistograms {
+			histogram, err := meter.Float64Histogram(
+				name,
+				metric.WithDescription(description),
+				metric.WithUnit("s"),
+				metric.WithExplicitBucketBoundaries(buckets...),
+			)
+			if err != nil {
+				return nil, err
+			}
+			return otelHistogram{histogram: histogram, labels: labels}, nil
+		}
+
+		vec := prom.NewHistogramVec(prom.HistogramOpts{
+			Name:                            name,
+			Help:                            description,
+			Buckets:                         buckets,
+			NativeHistogramBucketFactor:     cfg.NativeBucketFactor,
+			NativeHistogramMaxBucketNumber:  nativeHistogramMaxBuckets,
+			NativeHistogramMinResetDuration: time.Hour,
+		}, labels)
+		if err := registerer.Register(vec); err != nil {
+			// Monitors created again, e.g. by tests, share the histograms already registered
+			var registered prom.AlreadyRegisteredError
+			if !errors.As(err, &registered) {
+				return nil, err
+			}
+			existing, ok := registered.ExistingCollector.(*prom.HistogramVec)
+			if !ok {
+				return nil, err
+			}
+			vec = existing
+		}
+		return nativeHistogram{vec: vec}, nil
+	}
+
+	var h latencyHistograms
+	var err error
+	if h.matching, err = create(MatchingDurationMetric, "Time from a ride request to its driver match in seconds",
+		cfg.MatchingBuckets, defaults.MatchingBuckets, "mode"); err != nil {
+		return nil, err
+	}
+	if h.tripDuration, err = create(TripDurationMetric, "Time from pickup to completion of completed trips in seconds",
+		cfg.TripDurationBuckets, defaults.TripDurationBuckets); err != nil {
+		return nil, err
+	}
+	if h.messageProcessing, err = create(MessageProcessingMetric, "Time actors took to process a message successfully in seconds",
+		cfg.MessageProcessingBuckets, defaults.MessageProcessingBuckets, "actor_type", "message_type"); err != nil {
+		return nil, err
+	}
+	if h.matchingQueueWait, err = create(MatchingQueueWaitMetric, "Time traditional ride requests waited for a matching worker in seconds",
+		cfg.MatchingQueueWaitBuckets, defaults.MatchingQueueWaitBuckets); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
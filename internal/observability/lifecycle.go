@@ -0,0 +1,107 @@
+package observability
+
+import (
+	"time"
+
+	"actor-model-observability/internal/models"
+)
+
+// BuildActorLifecycles turns the bucketed lifecycle activity of actors into Gantt-style
+// timelines, keeping the order of the actors in activity. Each bucket gets one phase, the first
+// that applies of failed, spawned, stopped, processing and idle; buckets in which the actor was
+// not alive are left out, and consecutive buckets in the same phase are merged into a segment.
+func BuildActorLifecycles(lf models.ActorLifecycleFilter, activity []*models.ActorLifecycleActivity) []*models.ActorLifecycle {
+	lifecycles := []*models.ActorLifecycle{}
+
+	for start := 0; start < len(activity); {
+		end := start + 1
+		for end < len(activity) && activity[end].ActorID == activity[start].ActorID {
+			end++
+		}
+
+		a := activity[start]
+		lifecycle := &models.ActorLifecycle{
+			ActorID:    a.ActorID,
+			ActorType:  a.ActorType,
+			EntityType: a.EntityType,
+			EntityID:   a.EntityID,
+			Status:     a.Status,
+			SpawnedAt:  a.SpawnedAt,
+		}
+		lifecycle.Segments = lifecycleSegments(lf, a.SpawnedAt, a.AliveAtStart, activity[start:end])
+		lifecycles = append(lifecycles, lifecycle)
+
+		start = end
+	}
+
+	return lifecycles
+}
+
+// lifecycleSegments assigns a phase to every bucket of one actor's timeline and merges runs of
+// the same phase
+func lifecycleSegments(lf models.ActorLifecycleFilter, spawnedAt time.Time, alive bool, activity []*models.ActorLifecycleActivity) []models.ActorLifecycleSegment {
+	buckets := lf.Buckets()
+	rows := make(map[int]*models.ActorLifecycleActivity, len(activity))
+	for _, a := range activity {
+		if a.Bucket != nil {
+			rows[*a.Bucket] = a
+		}
+	}
+
+	spawnBucket := -1
+	if !spawnedAt.Before(lf.Start) && spawnedAt.Before(lf.End) {
+		spawnBucket = int(spawnedAt.Sub(lf.Start) / lf.Bucket)
+	}
+
+	segments := []models.ActorLifecycleSegment{}
+	merging := false // whether the previous bucket has a segment to merge into
+	for b := 0; b < buckets; b++ {
+		row, ok := rows[b]
+		if !ok {
+			row = &models.ActorLifecycleActivity{}
+		}
+		started := row.Started > 0 || b == spawnBucket
+
+		var phase models.ActorLifecyclePhase
+		switch {
+		case row.Failures > 0:
+			phase = models.ActorLifecycleFailed
+		case started:
+			phase = models.ActorLifecycleSpawned
+		case row.Stopped > 0:
+			phase = models.ActorLifecycleStopped
+		case row.Messages > 0:
+			phase = models.ActorLifecycleProcessing
+		case alive:
+			phase = models.ActorLifecycleIdle
+		}
+
+		// The order of a start and a stop within one bucket is unknown, so the actor is taken
+		// to be alive after it unless it was stopped at least as often as started
+		if started || row.Stopped > 0 {
+			alive = row.Stopped == 0 || row.Started > row.Stopped
+		}
+
+		if phase == "" {
+			merging = false
+			continue
+		}
+
+		start := lf.Start.Add(time.Duration(b) * lf.Bucket)
+		end := start.Add(lf.Bucket)
+		if end.After(lf.End) {
+			end = lf.End
+		}
+		if !merging || segments[len(segments)-1].Phase != phase {
+			segments = append(segments, models.ActorLifecycleSegment{Phase: phase, Start: start})
+		}
+		segment := &segments[len(segments)-1]
+		segment.End = end
+		segment.Messages += row.Messages
+		segment.ProcessingMs += row.ProcessingMs
+		segment.Failures += row.Failures
+		merging = true
+	}
+
+	return segments
+}
@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
 	"actor-model-observability/internal/config"
 	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/payload"
 
+	prom "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -31,10 +34,13 @@ type OTelMonitor struct {
 	tracerProvider *sdktrace.TracerProvider
 	meter          metric.Meter
 	tracer         trace.Tracer
+	sampler        *dynamicSampler
 
 	// Metrics instruments
 	httpRequestsTotal      metric.Int64Counter
 	httpRequestDuration    metric.Float64Histogram
+	panicsTotal            metric.Int64Counter
+	deadlineExceededTotal  metric.Int64Counter
 	databaseQueriesTotal   metric.Int64Counter
 	databaseQueryDuration  metric.Float64Histogram
 	cacheOperationsTotal   metric.Int64Counter
@@ -43,6 +49,7 @@ type OTelMonitor struct {
 	systemMemoryUsage      metric.Float64Histogram
 	systemDiskUsage        metric.Float64Histogram
 	businessMetrics        map[string]metric.Float64Histogram
+	latency                *latencyHistograms
 }
 
 // NewOTelMonitor creates a new OpenTelemetry monitor
@@ -51,6 +58,7 @@ func NewOTelMonitor(cfg *config.OpenTelemetryConfig, logger *logging.Logger) (*O
 		config:          cfg,
 		logger:          logger.WithComponent("otel_monitor"),
 		businessMetrics: make(map[string]metric.Float64Histogram),
+		sampler:         newDynamicSampler(cfg.SampleRate),
 	}
 
 	if err := monitor.initializeResource(); err != nil {
@@ -155,7 +163,7 @@ func (om *OTelMonitor) initializeTracing() error {
 
 	om.tracerProvider = sdktrace.NewTracerProvider(
 		sdktrace.WithBatcher(exporter),
-		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(om.config.SampleRate)),
+		sdktrace.WithSampler(om.sampler),
 		sdktrace.WithResource(om.resource),
 	)
 
@@ -166,6 +174,46 @@ func (om *OTelMonitor) initializeTracing() error {
 	return nil
 }
 
+// SetSampleRate changes the trace sampling ratio at runtime
+func (om *OTelMonitor) SetSampleRate(rate float64) {
+	om.sampler.setRate(rate)
+	om.logger.WithField("sample_rate", rate).Info("Trace sample rate updated")
+}
+
+// dynamicSampler is a ratio-based sampler whose ratio can be changed after the tracer provider is built
+type dynamicSampler struct {
+	mu       sync.RWMutex
+	delegate sdktrace.Sampler
+}
+
+// newDynamicSampler creates a sampler with the given initial ratio
+func newDynamicSampler(rate float64) *dynamicSampler {
+	s := &dynamicSampler{}
+	s.setRate(rate)
+	return s
+}
+
+// setRate replaces the underlying ratio-based sampler
+func (s *dynamicSampler) setRate(rate float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delegate = sdktrace.TraceIDRatioBased(rate)
+}
+
+// ShouldSample implements sdktrace.Sampler
+func (s *dynamicSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.delegate.ShouldSample(p)
+}
+
+// Description implements sdktrace.Sampler
+func (s *dynamicSampler) Description() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.delegate.Description()
+}
+
 // createMetricInstruments creates all the metric instruments
 func (om *OTelMonitor) createMetricInstruments() error {
 	var err error
@@ -188,6 +236,22 @@ func (om *OTelMonitor) createMetricInstruments() error {
 		return err
 	}
 
+	om.panicsTotal, err = om.meter.Int64Counter(
+		"panics_total",
+		metric.WithDescription("Total number of panics recovered while handling HTTP requests"),
+	)
+	if err != nil {
+		return err
+	}
+
+	om.deadlineExceededTotal, err = om.meter.Int64Counter(
+		"deadline_exceeded_total",
+		metric.WithDescription("Total number of HTTP requests answered with a 504 because they ran out of their time budget"),
+	)
+	if err != nil {
+		return err
+	}
+
 	// Database metrics
 	om.databaseQueriesTotal, err = om.meter.Int64Counter(
 		"database_queries_total",
@@ -252,6 +316,12 @@ func (om *OTelMonitor) createMetricInstruments() error {
 		return err
 	}
 
+	// Latency histograms, with configurable buckets
+	om.latency, err = newLatencyHistograms(om.meter, prom.DefaultRegisterer, om.config.Histograms)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -282,6 +352,27 @@ func (om *OTelMonitor) RecordRequest(ctx context.Context, endpoint, method strin
 	}
 }
 
+// RecordPanic counts a panic recovered while handling a request to route
+func (om *OTelMonitor) RecordPanic(ctx context.Context, route, method string) {
+	if om.config.MetricsEnabled {
+		om.panicsTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("route", route),
+			attribute.String("method", method),
+		))
+	}
+}
+
+// RecordDeadlineExceeded counts a request to route that ran out of its time budget in layer
+func (om *OTelMonitor) RecordDeadlineExceeded(ctx context.Context, route, method, layer string) {
+	if om.config.MetricsEnabled {
+		om.deadlineExceededTotal.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("route", route),
+			attribute.String("method", method),
+			attribute.String("layer", layer),
+		))
+	}
+}
+
 // RecordDatabaseOperation records database operation metrics and spans
 func (om *OTelMonitor) RecordDatabaseOperation(ctx context.Context, operation, table string, duration time.Duration, success bool) {
 	status := "success"
@@ -355,6 +446,40 @@ func (om *OTelMonitor) RecordSystemMetrics(ctx context.Context, cpuUsage, memory
 	om.systemDiskUsage.Record(ctx, diskUsage)
 }
 
+// RecordMatchingDuration records how long a ride request took to be matched with a driver, in
+// the mode it was matched in
+func (om *OTelMonitor) RecordMatchingDuration(ctx context.Context, mode string, duration time.Duration) {
+	if om.latency == nil {
+		return
+	}
+	om.latency.matching.record(ctx, duration.Seconds(), mode)
+}
+
+// RecordTripDuration records how long a completed trip took from pickup to completion
+func (om *OTelMonitor) RecordTripDuration(ctx context.Context, duration time.Duration) {
+	if om.latency == nil {
+		return
+	}
+	om.latency.tripDuration.record(ctx, duration.Seconds())
+}
+
+// RecordMatchingQueueWait records how long a traditional ride request waited for a matching
+// worker
+func (om *OTelMonitor) RecordMatchingQueueWait(ctx context.Context, wait time.Duration) {
+	if om.latency == nil {
+		return
+	}
+	om.latency.matchingQueueWait.record(ctx, wait.Seconds())
+}
+
+// RecordMessageProcessing records how long an actor took to process a message successfully
+func (om *OTelMonitor) RecordMessageProcessing(ctx context.Context, actorType, messageType string, duration time.Duration) {
+	if om.latency == nil {
+		return
+	}
+	om.latency.messageProcessing.record(ctx, duration.Seconds(), actorType, messageType)
+}
+
 // RecordBusinessMetrics records business-specific metrics
 func (om *OTelMonitor) RecordBusinessMetrics(ctx context.Context, metricName string, value float64, tags map[string]string) {
 	if !om.config.MetricsEnabled {
@@ -383,6 +508,55 @@ func (om *OTelMonitor) RecordBusinessMetrics(ctx context.Context, metricName str
 	om.businessMetrics[metricName].Record(ctx, value, metric.WithAttributes(attrs...))
 }
 
+// RegisterSLOGauges exports the compliance, burn rate and remaining error budget of every
+// SLO tracked by tracker as gauges, labelled by endpoint, method and slo (availability or latency)
+func (om *OTelMonitor) RegisterSLOGauges(tracker *SLOTracker) error {
+	if !om.config.MetricsEnabled || tracker == nil {
+		return nil
+	}
+
+	compliance, err := om.meter.Float64ObservableGauge(
+		"slo_compliance_ratio",
+		metric.WithDescription("Ratio of good requests over the SLO window"),
+	)
+	if err != nil {
+		return err
+	}
+
+	burnRate, err := om.meter.Float64ObservableGauge(
+		"slo_error_budget_burn_rate",
+		metric.WithDescription("Error budget burn rate over the SLO window; 1 spends the budget exactly over the window"),
+	)
+	if err != nil {
+		return err
+	}
+
+	remaining, err := om.meter.Float64ObservableGauge(
+		"slo_error_budget_remaining_ratio",
+		metric.WithDescription("Fraction of the error budget left over the SLO window"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = om.meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		for _, status := range tracker.Report().Endpoints {
+			for slo, c := range map[string]SLOCompliance{"availability": status.Availability, "latency": status.Latency} {
+				attrs := metric.WithAttributes(
+					attribute.String("endpoint", status.Endpoint),
+					attribute.String("method", status.Method),
+					attribute.String("slo", slo),
+				)
+				o.ObserveFloat64(compliance, c.Actual, attrs)
+				o.ObserveFloat64(burnRate, c.BurnRate, attrs)
+				o.ObserveFloat64(remaining, c.ErrorBudgetRemaining, attrs)
+			}
+		}
+		return nil
+	}, compliance, burnRate, remaining)
+	return err
+}
+
 // GetPrometheusHandler returns the Prometheus metrics handler
 func (om *OTelMonitor) GetPrometheusHandler() http.Handler {
 	return promhttp.Handler()
@@ -396,6 +570,22 @@ func (om *OTelMonitor) StartSpan(ctx context.Context, name string, opts ...trace
 	return om.tracer.Start(ctx, name, opts...)
 }
 
+// Tracer returns the tracer spans are started with, nil when tracing is disabled
+func (om *OTelMonitor) Tracer() trace.Tracer {
+	if !om.config.TracingEnabled {
+		return nil
+	}
+	return om.tracer
+}
+
+// Meter returns the meter instruments are created with, nil when metrics are disabled
+func (om *OTelMonitor) Meter() metric.Meter {
+	if !om.config.MetricsEnabled {
+		return nil
+	}
+	return om.meter
+}
+
 // Shutdown gracefully shuts down the monitor
 func (om *OTelMonitor) Shutdown(ctx context.Context) error {
 	var errs []error
@@ -419,3 +609,121 @@ func (om *OTelMonitor) Shutdown(ctx context.Context) error {
 	om.logger.Info("OpenTelemetry monitor shutdown complete")
 	return nil
 }
+
+// RegisterPayloadCounters exports the number of persisted payloads the guard compressed or
+// truncated as counters
+func (om *OTelMonitor) RegisterPayloadCounters(guard *payload.Guard) error {
+	if !om.config.MetricsEnabled || guard == nil {
+		return nil
+	}
+
+	compressed, err := om.meter.Int64ObservableCounter(
+		"observability_payloads_compressed_total",
+		metric.WithDescription("Persisted message payloads and event data stored gzip compressed"),
+	)
+	if err != nil {
+		return err
+	}
+
+	truncated, err := om.meter.Int64ObservableCounter(
+		"observability_payloads_truncated_total",
+		metric.WithDescription("Persisted message payloads and event data truncated for exceeding the size limit"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = om.meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		stats := guard.Stats()
+		o.ObserveInt64(compressed, stats.Compressed)
+		o.ObserveInt64(truncated, stats.Truncated)
+		return nil
+	}, compressed, truncated)
+	return err
+}
+
+// RegisterCollectorMetrics exports the metrics collector's own counters, which tell when the
+// observability pipeline falls behind: buffered records and failed batches and dropped records by
+// kind, the flushes and their duration, and failed Redis calls
+func (om *OTelMonitor) RegisterCollectorMetrics(collector *MetricsCollector) error {
+	if !om.config.MetricsEnabled || collector == nil {
+		return nil
+	}
+
+	buffered, err := om.meter.Int64ObservableGauge(
+		"observability_collector_buffered_records",
+		metric.WithDescription("Records waiting for the next flush of the metrics collector, by kind"),
+	)
+	if err != nil {
+		return err
+	}
+
+	flushes, err := om.meter.Int64ObservableCounter(
+		"observability_collector_flushes_total",
+		metric.WithDescription("Flushes of the metrics collector"),
+	)
+	if err != nil {
+		return err
+	}
+
+	lastFlush, err := om.meter.Float64ObservableGauge(
+		"observability_collector_last_flush_duration_seconds",
+		metric.WithDescription("Duration of the last flush of the metrics collector"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return err
+	}
+
+	failed, err := om.meter.Int64ObservableCounter(
+		"observability_collector_failed_flushes_total",
+		metric.WithDescription("Batches the metrics collector failed to insert, by kind"),
+	)
+	if err != nil {
+		return err
+	}
+
+	dropped, err := om.meter.Int64ObservableCounter(
+		"observability_collector_dropped_records_total",
+		metric.WithDescription("Records dropped with the batches the metrics collector failed to insert, by kind"),
+	)
+	if err != nil {
+		return err
+	}
+
+	redisErrors, err := om.meter.Int64ObservableCounter(
+		"observability_collector_redis_errors_total",
+		metric.WithDescription("Failed Redis calls of the metrics collector for real-time data"),
+	)
+	if err != nil {
+		return err
+	}
+
+	behind, err := om.meter.Int64ObservableGauge(
+		"observability_collector_falling_behind",
+		metric.WithDescription("Whether the metrics collector falls behind its flush interval, 1 or 0"),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = om.meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		stats := collector.Stats()
+		for kind, n := range stats.Buffered {
+			kindAttr := metric.WithAttributes(attribute.String("kind", kind))
+			o.ObserveInt64(buffered, int64(n), kindAttr)
+			o.ObserveInt64(failed, stats.FailedFlushes[kind], kindAttr)
+			o.ObserveInt64(dropped, stats.DroppedRecords[kind], kindAttr)
+		}
+		o.ObserveInt64(flushes, stats.Flushes)
+		o.ObserveFloat64(lastFlush, stats.LastFlushDurationMs/1000)
+		o.ObserveInt64(redisErrors, stats.RedisErrors)
+		var fallingBehind int64
+		if stats.FallingBehind {
+			fallingBehind = 1
+		}
+		o.ObserveInt64(behind, fallingBehind)
+		return nil
+	}, buffered, flushes, lastFlush, failed, dropped, redisErrors, behind)
+	return err
+}
@@ -0,0 +1,67 @@
+package observability
+
+import (
+	"context"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/payload"
+	"actor-model-observability/internal/repository"
+)
+
+// Persister stores the observability records published on the event bus
+type Persister struct {
+	repo   repository.ObservabilityRepository
+	logger *logging.Logger
+
+	// Guard bounding the size of stored payloads; nil stores them verbatim
+	guard *payload.Guard
+}
+
+// NewPersister creates a persister writing to repo
+func NewPersister(repo repository.ObservabilityRepository, logger *logging.Logger) *Persister {
+	return &Persister{
+		repo:   repo,
+		logger: logger.WithComponent("event_persister"),
+	}
+}
+
+// SetPayloadGuard compresses or truncates oversized message payloads and event data before
+// they are stored
+func (p *Persister) SetPayloadGuard(guard *payload.Guard) {
+	p.guard = guard
+}
+
+// Topics returns the bus topics the persister stores
+func (p *Persister) Topics() []string {
+	return []string{bus.TopicEventLog, bus.TopicActorInstance, bus.TopicActorMessage}
+}
+
+// HandleMessage stores one published record
+func (p *Persister) HandleMessage(msg bus.Message) {
+	ctx := context.Background()
+
+	switch record := msg.Payload.(type) {
+	case *models.EventLog:
+		// The bus shares records between subscribers, so the guard stores a copy
+		record = p.guard.EventLog(record)
+		if err := p.repo.CreateEventLog(ctx, record); err != nil {
+			p.logger.WithError(err).WithField("event_type", record.EventType).Error("Failed to create event log")
+		}
+	case *models.ActorInstance:
+		if err := p.repo.CreateActorInstance(ctx, record); err != nil {
+			p.logger.WithError(err).WithFields(logging.Fields{
+				"actor_id":   record.ActorID,
+				"actor_type": record.ActorType,
+			}).Error("Failed to create actor instance record")
+		}
+	case *models.ActorMessage:
+		record = p.guard.ActorMessage(record)
+		if err := p.repo.CreateActorMessage(ctx, record); err != nil {
+			p.logger.WithError(err).WithField("actor_id", record.ReceiverActorID).Error("Failed to create actor message record")
+		}
+	default:
+		p.logger.WithField("topic", msg.Topic).Warn("Ignoring unexpected event bus payload")
+	}
+}
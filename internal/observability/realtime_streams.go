@@ -0,0 +1,229 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/logging"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Redis Streams the collector publishes real-time data to. Each entry carries the JSON record in
+// its data field.
+const (
+	MessagesStream = "realtime:messages" // models.ActorMessage
+	MetricsStream  = "realtime:metrics"  // models.SystemMetric
+)
+
+// streamDataField is the entry field holding the JSON record
+const streamDataField = "data"
+
+// RealtimeStreams publishes real-time messages and metrics to Redis Streams. Unlike the keys
+// and lists written otherwise, every consumer group reads a stream at its own pace, so
+// dashboards and alerting can consume the same entries independently.
+type RealtimeStreams struct {
+	client redis.UniversalClient
+	maxLen int64
+}
+
+// NewRealtimeStreams creates a publisher trimming each stream to about maxLen entries
+func NewRealtimeStreams(client redis.UniversalClient, maxLen int) *RealtimeStreams {
+	return &RealtimeStreams{client: client, maxLen: int64(maxLen)}
+}
+
+// Add appends a record to a stream and returns the entry ID. The stream is trimmed
+// approximately, which lets Redis drop whole nodes and keeps XADD cheap.
+func (s *RealtimeStreams) Add(ctx context.Context, stream string, record interface{}) (string, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal stream entry: %w", err)
+	}
+
+	id, err := s.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: s.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{streamDataField: data},
+	}).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to add to stream %s: %w", stream, err)
+	}
+	return id, nil
+}
+
+// StreamGroupLag describes how far a consumer group is behind a stream
+type StreamGroupLag struct {
+	Stream    string `json:"stream"`
+	Group     string `json:"group"`
+	Consumers int64  `json:"consumers"`
+	Pending   int64  `json:"pending"` // delivered but not yet acknowledged
+	Lag       int64  `json:"lag"`     // not yet delivered, -1 when Redis can't tell
+}
+
+// StreamStats describes a stream and its consumer groups
+type StreamStats struct {
+	Stream string           `json:"stream"`
+	Length int64            `json:"length"`
+	Groups []StreamGroupLag `json:"groups"`
+}
+
+// Stats returns the length of a stream and the lag of each of its consumer groups. The lag
+// comes from XINFO GROUPS, which Redis 7 answers from counters without reading the stream;
+// older versions don't report it and neither does Redis 7 after entries were deleted from
+// the middle of the stream, in which case the lag is -1.
+func (s *RealtimeStreams) Stats(ctx context.Context, stream string) (*StreamStats, error) {
+	length, err := s.client.XLen(ctx, stream).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get length of stream %s: %w", stream, err)
+	}
+	stats := &StreamStats{Stream: stream, Length: length}
+	if length == 0 {
+		return stats, nil
+	}
+
+	// XInfoGroups of go-redis v8 rejects the Redis 7 reply, which added entries-read and lag
+	reply, err := s.client.Do(ctx, "XINFO", "GROUPS", stream).Slice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumer groups of stream %s: %w", stream, err)
+	}
+	for _, item := range reply {
+		fields, ok := item.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unexpected consumer group info of stream %s: %v", stream, item)
+		}
+		group := StreamGroupLag{Stream: stream, Lag: -1}
+		for i := 0; i+1 < len(fields); i += 2 {
+			name, _ := fields[i].(string)
+			switch name {
+			case "name":
+				group.Group, _ = fields[i+1].(string)
+			case "consumers":
+				group.Consumers, _ = fields[i+1].(int64)
+			case "pending":
+				group.Pending, _ = fields[i+1].(int64)
+			case "lag":
+				if lag, ok := fields[i+1].(int64); ok {
+					group.Lag = lag
+				}
+			}
+		}
+		stats.Groups = append(stats.Groups, group)
+	}
+	return stats, nil
+}
+
+// StreamEntry is a record read from a stream
+type StreamEntry struct {
+	ID   string
+	Data json.RawMessage
+}
+
+// StreamConsumer reads a stream as one consumer of a consumer group. Consumers of the same
+// group share the entries between them; each group receives every entry.
+type StreamConsumer struct {
+	client   redis.UniversalClient
+	stream   string
+	group    string
+	consumer string
+	batch    int64
+	block    time.Duration
+	logger   *logging.Logger
+}
+
+// NewStreamConsumer creates a consumer named consumer in group reading stream
+func NewStreamConsumer(client redis.UniversalClient, stream, group, consumer string, logger *logging.Logger) *StreamConsumer {
+	return &StreamConsumer{
+		client:   client,
+		stream:   stream,
+		group:    group,
+		consumer: consumer,
+		batch:    100,
+		block:    5 * time.Second,
+		logger: logger.WithComponent("stream_consumer").WithFields(logging.Fields{
+			"stream": stream,
+			"group":  group,
+		}),
+	}
+}
+
+// Run hands every entry of the stream to handle until ctx is done, acknowledging the entries
+// handled without error. The group is created at the end of the stream if it doesn't exist.
+// Entries this consumer received but never acknowledged, for example before a crash, are
+// handled again first; entries whose handling failed stay pending and are retried on restart.
+func (c *StreamConsumer) Run(ctx context.Context, handle func(context.Context, StreamEntry) error) error {
+	err := c.client.XGroupCreateMkStream(ctx, c.stream, c.group, "$").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return fmt.Errorf("failed to create consumer group %s on stream %s: %w", c.group, c.stream, err)
+	}
+
+	// An ID reads this consumer's pending entries after it, ">" entries never delivered to the group
+	start := "0"
+	for ctx.Err() == nil {
+		streams, err := c.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    c.group,
+			Consumer: c.consumer,
+			Streams:  []string{c.stream, start},
+			Count:    c.batch,
+			Block:    c.block,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			c.logger.WithError(err).Warn("Failed to read stream")
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		var last string
+		for _, stream := range streams {
+			for _, message := range stream.Messages {
+				c.handle(ctx, message, handle)
+				last = message.ID
+			}
+		}
+
+		// Pending entries are paged through once, after which only new entries are read
+		if start != ">" {
+			if last == "" {
+				start = ">"
+			} else {
+				start = last
+			}
+		}
+	}
+	return nil
+}
+
+// handle passes one entry to the handler and acknowledges it when handled
+func (c *StreamConsumer) handle(ctx context.Context, message redis.XMessage, handle func(context.Context, StreamEntry) error) {
+	// Pending entries trimmed from the stream come back without values and are dropped
+	data, _ := message.Values[streamDataField].(string)
+	if data == "" {
+		c.ack(ctx, message.ID)
+		return
+	}
+	if err := handle(ctx, StreamEntry{ID: message.ID, Data: json.RawMessage(data)}); err != nil {
+		c.logger.WithError(err).WithField("entry_id", message.ID).Warn("Failed to handle stream entry")
+		return
+	}
+	c.ack(ctx, message.ID)
+}
+
+// ack acknowledges an entry, removing it from the group's pending entries
+func (c *StreamConsumer) ack(ctx context.Context, id string) {
+	if err := c.client.XAck(ctx, c.stream, c.group, id).Err(); err != nil {
+		c.logger.WithError(err).WithField("entry_id", id).Warn("Failed to acknowledge stream entry")
+	}
+}
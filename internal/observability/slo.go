@@ -0,0 +1,183 @@
+package observability
+
+import (
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/config"
+)
+
+// sloBuckets is the number of buckets the SLO window is split into; older buckets
+// are dropped as the window rolls forward
+const sloBuckets = 60
+
+// SLOTracker computes per-endpoint SLO compliance and error budget burn over a rolling
+// window from the HTTP requests recorded by the traditional monitor
+type SLOTracker struct {
+	window     time.Duration
+	bucketSize time.Duration
+	objectives map[string]config.SLOObjective
+	order      []string // objective keys in configuration order
+	series     map[string][]sloBucket
+	mu         sync.Mutex
+}
+
+// sloBucket counts the requests of one endpoint that started within a bucket
+type sloBucket struct {
+	start  time.Time
+	total  int64
+	failed int64 // 5xx responses
+	slow   int64 // slower than the latency target
+}
+
+// SLOReport is the SLO status of every tracked endpoint
+type SLOReport struct {
+	Window      string      `json:"window"`
+	GeneratedAt time.Time   `json:"generated_at"`
+	Endpoints   []SLOStatus `json:"endpoints"`
+}
+
+// SLOStatus is the compliance of one endpoint over the window
+type SLOStatus struct {
+	Method         string        `json:"method"`
+	Endpoint       string        `json:"endpoint"`
+	TotalRequests  int64         `json:"total_requests"`
+	FailedRequests int64         `json:"failed_requests"`
+	SlowRequests   int64         `json:"slow_requests"`
+	Availability   SLOCompliance `json:"availability"`
+	Latency        SLOCompliance `json:"latency"`
+	LatencyTarget  string        `json:"latency_target"`
+}
+
+// SLOCompliance compares the good request ratio with its objective. A burn rate of 1 spends
+// the error budget exactly over the window; above 1 the budget runs out early.
+type SLOCompliance struct {
+	Objective            float64 `json:"objective"`
+	Actual               float64 `json:"actual"`
+	Met                  bool    `json:"met"`
+	BurnRate             float64 `json:"burn_rate"`
+	ErrorBudgetRemaining float64 `json:"error_budget_remaining"` // fraction of the budget left; negative once exhausted
+}
+
+// NewSLOTracker creates a tracker for the configured objectives
+func NewSLOTracker(cfg config.SLOConfig) *SLOTracker {
+	t := &SLOTracker{
+		window:     cfg.Window,
+		bucketSize: cfg.Window / sloBuckets,
+		objectives: make(map[string]config.SLOObjective),
+		series:     make(map[string][]sloBucket),
+	}
+	if t.bucketSize <= 0 {
+		t.bucketSize = time.Second
+	}
+
+	for _, o := range cfg.Objectives {
+		key := sloKey(o.Method, o.Endpoint)
+		if _, exists := t.objectives[key]; !exists {
+			t.order = append(t.order, key)
+		}
+		t.objectives[key] = o
+	}
+	return t
+}
+
+// RecordRequest counts a request against the SLOs of its endpoint. Endpoints without an
+// objective are ignored.
+func (t *SLOTracker) RecordRequest(endpoint, method string, duration time.Duration, statusCode int) {
+	key := sloKey(method, endpoint)
+	objective, ok := t.objectives[key]
+	if !ok {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	buckets := t.expire(t.series[key], now)
+	start := now.Truncate(t.bucketSize)
+	if len(buckets) == 0 || !buckets[len(buckets)-1].start.Equal(start) {
+		buckets = append(buckets, sloBucket{start: start})
+	}
+
+	b := &buckets[len(buckets)-1]
+	b.total++
+	if statusCode >= 500 {
+		b.failed++
+	}
+	if duration > objective.LatencyTarget {
+		b.slow++
+	}
+	t.series[key] = buckets
+}
+
+// Report returns the SLO status of every tracked endpoint, in configuration order
+func (t *SLOTracker) Report() SLOReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	report := SLOReport{
+		Window:      t.window.String(),
+		GeneratedAt: now,
+		Endpoints:   make([]SLOStatus, 0, len(t.order)),
+	}
+
+	for _, key := range t.order {
+		objective := t.objectives[key]
+		buckets := t.expire(t.series[key], now)
+		t.series[key] = buckets
+
+		status := SLOStatus{
+			Method:        objective.Method,
+			Endpoint:      objective.Endpoint,
+			LatencyTarget: objective.LatencyTarget.String(),
+		}
+		for _, b := range buckets {
+			status.TotalRequests += b.total
+			status.FailedRequests += b.failed
+			status.SlowRequests += b.slow
+		}
+		status.Availability = compliance(objective.AvailabilityObjective, status.TotalRequests, status.FailedRequests)
+		status.Latency = compliance(objective.LatencyObjective, status.TotalRequests, status.SlowRequests)
+
+		report.Endpoints = append(report.Endpoints, status)
+	}
+
+	return report
+}
+
+// expire drops buckets that have fallen out of the window
+func (t *SLOTracker) expire(buckets []sloBucket, now time.Time) []sloBucket {
+	cutoff := now.Add(-t.window)
+
+	expired := 0
+	for _, b := range buckets {
+		if b.start.Add(t.bucketSize).After(cutoff) {
+			break
+		}
+		expired++
+	}
+	return buckets[expired:]
+}
+
+// compliance computes the good request ratio and error budget burn for an objective
+func compliance(objective float64, total, bad int64) SLOCompliance {
+	c := SLOCompliance{Objective: objective, Actual: 1, Met: true, ErrorBudgetRemaining: 1}
+	if total == 0 {
+		return c
+	}
+
+	badRatio := float64(bad) / float64(total)
+	c.Actual = 1 - badRatio
+	c.Met = c.Actual >= objective
+	if budget := 1 - objective; budget > 0 {
+		c.BurnRate = badRatio / budget
+		c.ErrorBudgetRemaining = 1 - c.BurnRate
+	}
+	return c
+}
+
+func sloKey(method, endpoint string) string {
+	return method + " " + endpoint
+}
@@ -0,0 +1,138 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// SlowQueriesKey is the Redis sorted set holding the slowest repository calls, scored by
+// duration in milliseconds, so every instance ranks into the same top N
+const SlowQueriesKey = "observability:slow_queries"
+
+// slowQueryRedisTimeout bounds the Redis round trip made for a slow call
+const slowQueryRedisTimeout = time.Second
+
+// SlowQueryLog logs the repository calls slower than a threshold and keeps the slowest of them
+// for triage. The ranking is held in memory, and in Redis when set.
+type SlowQueryLog struct {
+	threshold time.Duration
+	size      int
+	logger    *logging.Logger
+	redis     *redis.Client
+
+	mu      sync.Mutex
+	slowest []*models.SlowQuery // slowest first, at most size entries
+}
+
+// NewSlowQueryLog creates a log of the calls taking at least threshold keeping the size slowest
+func NewSlowQueryLog(threshold time.Duration, size int, logger *logging.Logger) *SlowQueryLog {
+	return &SlowQueryLog{
+		threshold: threshold,
+		size:      size,
+		logger:    logger.WithComponent("slow_query_log"),
+	}
+}
+
+// SetRedis shares the ranking between instances through Redis
+func (l *SlowQueryLog) SetRedis(client *redis.Client) {
+	l.redis = client
+}
+
+// Threshold returns the duration from which a call is slow
+func (l *SlowQueryLog) Threshold() time.Duration {
+	return l.threshold
+}
+
+// Record logs a slow call and ranks it among the slowest
+func (l *SlowQueryLog) Record(ctx context.Context, query *models.SlowQuery) {
+	fields := logging.Fields{
+		"repository":  query.Repository,
+		"method":      query.Method,
+		"operation":   query.Operation,
+		"params":      query.Params,
+		"duration_ms": query.DurationMs,
+	}
+	if query.TraceID != "" {
+		fields["trace_id"] = query.TraceID
+	}
+	if query.Error != "" {
+		fields["error"] = query.Error
+	}
+	l.logger.WithFields(fields).Warn("Slow repository call")
+
+	l.mu.Lock()
+	i := sort.Search(len(l.slowest), func(i int) bool { return l.slowest[i].DurationMs < query.DurationMs })
+	if i < l.size {
+		l.slowest = append(l.slowest, nil)
+		copy(l.slowest[i+1:], l.slowest[i:])
+		l.slowest[i] = query
+		if len(l.slowest) > l.size {
+			l.slowest = l.slowest[:l.size]
+		}
+	}
+	l.mu.Unlock()
+
+	if l.redis != nil {
+		if err := l.store(ctx, query); err != nil {
+			l.logger.WithError(err).Error("Failed to store slow query in Redis")
+		}
+	}
+}
+
+// store adds a slow call to the Redis ranking and trims it to the size slowest. The call's own
+// context may be canceled right after it returns, so the write gets its own deadline.
+func (l *SlowQueryLog) store(ctx context.Context, query *models.SlowQuery) error {
+	data, err := json.Marshal(query)
+	if err != nil {
+		return fmt.Errorf("failed to marshal slow query: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), slowQueryRedisTimeout)
+	defer cancel()
+
+	_, err = l.redis.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, SlowQueriesKey, &redis.Z{Score: query.DurationMs, Member: data})
+		pipe.ZRemRangeByRank(ctx, SlowQueriesKey, 0, int64(-l.size-1))
+		return nil
+	})
+	return err
+}
+
+// Top returns up to limit of the slowest calls, slowest first
+func (l *SlowQueryLog) Top(ctx context.Context, limit int) ([]*models.SlowQuery, error) {
+	if limit <= 0 || limit > l.size {
+		limit = l.size
+	}
+
+	if l.redis != nil {
+		members, err := l.redis.ZRevRange(ctx, SlowQueriesKey, 0, int64(limit-1)).Result()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read slow queries: %w", err)
+		}
+		queries := make([]*models.SlowQuery, 0, len(members))
+		for _, member := range members {
+			var query models.SlowQuery
+			if err := json.Unmarshal([]byte(member), &query); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal slow query: %w", err)
+			}
+			queries = append(queries, &query)
+		}
+		return queries, nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limit > len(l.slowest) {
+		limit = len(l.slowest)
+	}
+	return append([]*models.SlowQuery(nil), l.slowest[:limit]...), nil
+}
@@ -0,0 +1,376 @@
+// Package parquet writes Parquet files, the columnar format pandas, DuckDB and Spark read, so
+// records can be analyzed offline instead of queried from the database. Only what the exports
+// need is supported: flat schemas of string, double, int64 and timestamp columns, each optional
+// or required, PLAIN encoded and gzip compressed, with one data page per column of a row group.
+package parquet
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// Type is the type of a column's values
+type Type int
+
+const (
+	String    Type = iota // UTF-8 string, written from a string
+	Double                // 64-bit float, written from a float64
+	Int64                 // 64-bit integer, written from an int64
+	Timestamp             // instant stored in microseconds since the epoch in UTC, written from a time.Time
+)
+
+// Column is a column of the file schema
+type Column struct {
+	Name     string
+	Type     Type
+	Optional bool // whether the column holds nulls, written from nil
+}
+
+// Parquet format values of the file metadata
+const (
+	physicalInt64     = 2
+	physicalDouble    = 5
+	physicalByteArray = 6
+
+	repetitionRequired = 0
+	repetitionOptional = 1
+
+	convertedUTF8            = 0
+	convertedTimestampMicros = 10
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	codecGzip = 2
+
+	pageTypeData = 0
+
+	formatVersion = 1
+)
+
+// magic starts and ends every Parquet file
+var magic = []byte("PAR1")
+
+// CreatedBy identifies the writer in the metadata of the files written
+const CreatedBy = "actor-model-observability"
+
+// ErrClosed is returned when writing to a closed writer
+var ErrClosed = errors.New("parquet writer is closed")
+
+// Writer writes a Parquet file, one row group per call to WriteRows. The file is only complete
+// once the writer is closed, which writes its metadata.
+type Writer struct {
+	out       io.Writer
+	columns   []Column
+	offset    int64
+	rowGroups []rowGroup
+	closed    bool
+}
+
+// rowGroup is the metadata of a written row group
+type rowGroup struct {
+	chunks []columnChunk
+	rows   int64
+	size   int64 // uncompressed size of its column chunks
+}
+
+// columnChunk is the metadata of a written column chunk, a single data page
+type columnChunk struct {
+	offset           int64 // of the data page
+	compressedSize   int64 // of the page header and compressed page
+	uncompressedSize int64 // of the page header and page before compression
+}
+
+// NewWriter starts a Parquet file of the columns on out
+func NewWriter(out io.Writer, columns []Column) (*Writer, error) {
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("parquet schema has no columns")
+	}
+	names := make(map[string]bool, len(columns))
+	for _, column := range columns {
+		if column.Name == "" || names[column.Name] {
+			return nil, fmt.Errorf("parquet column names must be unique and not empty: %q", column.Name)
+		}
+		names[column.Name] = true
+	}
+
+	w := &Writer{out: out, columns: columns}
+	if err := w.write(magic); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// WriteRows writes the rows as a row group. Each row holds a value of every column, in order.
+func (w *Writer) WriteRows(rows [][]any) error {
+	if w.closed {
+		return ErrClosed
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	for _, row := range rows {
+		if len(row) != len(w.columns) {
+			return fmt.Errorf("parquet row has %d values for %d columns", len(row), len(w.columns))
+		}
+	}
+
+	group := rowGroup{rows: int64(len(rows))}
+	for i, column := range w.columns {
+		page, err := encodePage(rows, i, column)
+		if err != nil {
+			return err
+		}
+		compressed, err := compress(page)
+		if err != nil {
+			return err
+		}
+		header := pageHeader(len(rows), len(page), len(compressed), column.Optional)
+
+		chunk := columnChunk{
+			offset:           w.offset,
+			compressedSize:   int64(len(header) + len(compressed)),
+			uncompressedSize: int64(len(header) + len(page)),
+		}
+		if err := w.write(header); err != nil {
+			return err
+		}
+		if err := w.write(compressed); err != nil {
+			return err
+		}
+		group.chunks = append(group.chunks, chunk)
+		group.size += chunk.uncompressedSize
+	}
+	w.rowGroups = append(w.rowGroups, group)
+	return nil
+}
+
+// Close completes the file with its metadata. It does not close the underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	w.closed = true
+
+	metadata := w.fileMetadata()
+	footer := binary.LittleEndian.AppendUint32(nil, uint32(len(metadata)))
+	if err := w.write(metadata); err != nil {
+		return err
+	}
+	if err := w.write(footer); err != nil {
+		return err
+	}
+	return w.write(magic)
+}
+
+// write writes to the file, keeping track of the offset
+func (w *Writer) write(data []byte) error {
+	n, err := w.out.Write(data)
+	w.offset += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write parquet file: %w", err)
+	}
+	return nil
+}
+
+// encodePage encodes the values of a column as a data page: the definition levels of an
+// optional column, telling nulls apart, followed by the values that are not null
+func encodePage(rows [][]any, i int, column Column) ([]byte, error) {
+	var values []byte
+	defined := make([]bool, len(rows))
+	for r, row := range rows {
+		value := row[i]
+		if value == nil {
+			if !column.Optional {
+				return nil, fmt.Errorf("parquet column %s is required but row %d is null", column.Name, r)
+			}
+			continue
+		}
+		defined[r] = true
+
+		var ok bool
+		switch column.Type {
+		case String:
+			var s string
+			if s, ok = value.(string); ok {
+				values = binary.LittleEndian.AppendUint32(values, uint32(len(s)))
+				values = append(values, s...)
+			}
+		case Double:
+			var f float64
+			if f, ok = value.(float64); ok {
+				values = binary.LittleEndian.AppendUint64(values, math.Float64bits(f))
+			}
+		case Int64:
+			var n int64
+			if n, ok = value.(int64); ok {
+				values = binary.LittleEndian.AppendUint64(values, uint64(n))
+			}
+		case Timestamp:
+			var t time.Time
+			if t, ok = value.(time.Time); ok {
+				values = binary.LittleEndian.AppendUint64(values, uint64(t.UnixMicro()))
+			}
+		}
+		if !ok {
+			return nil, fmt.Errorf("parquet column %s cannot hold %T in row %d", column.Name, value, r)
+		}
+	}
+
+	if !column.Optional {
+		return values, nil
+	}
+	return append(encodeLevels(defined), values...), nil
+}
+
+// encodeLevels encodes definition levels with the RLE hybrid encoding, as runs of one bit
+// values, prefixed with their length
+func encodeLevels(defined []bool) []byte {
+	var runs []byte
+	for start := 0; start < len(defined); {
+		end := start
+		for end < len(defined) && defined[end] == defined[start] {
+			end++
+		}
+		runs = binary.AppendUvarint(runs, uint64(end-start)<<1)
+		if defined[start] {
+			runs = append(runs, 1)
+		} else {
+			runs = append(runs, 0)
+		}
+		start = end
+	}
+	return append(binary.LittleEndian.AppendUint32(nil, uint32(len(runs))), runs...)
+}
+
+// compress gzip compresses a page
+func compress(page []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(page); err != nil {
+		return nil, fmt.Errorf("failed to compress parquet page: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress parquet page: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// pageHeader encodes the PageHeader of a data page of n values
+func pageHeader(n, size, compressedSize int, optional bool) []byte {
+	var t thriftWriter
+	t.structValue(func() {
+		t.i32Field(1, pageTypeData)
+		t.i32Field(2, int32(size))
+		t.i32Field(3, int32(compressedSize))
+		t.structField(5, func() {
+			t.i32Field(1, int32(n))
+			t.i32Field(2, encodingPlain)
+			t.i32Field(3, encodingRLE)
+			t.i32Field(4, encodingRLE)
+		})
+	})
+	return t.buf.Bytes()
+}
+
+// fileMetadata encodes the FileMetaData of the file: its schema and where its row groups are
+func (w *Writer) fileMetadata() []byte {
+	var rows int64
+	for _, group := range w.rowGroups {
+		rows += group.rows
+	}
+
+	var t thriftWriter
+	t.structValue(func() {
+		t.i32Field(1, formatVersion)
+		t.listField(2, compactStruct, len(w.columns)+1, func(i int) {
+			if i == 0 {
+				t.structValue(func() {
+					t.stringField(4, "schema")
+					t.i32Field(5, int32(len(w.columns)))
+				})
+				return
+			}
+			t.structValue(func() { schemaElement(&t, w.columns[i-1]) })
+		})
+		t.i64Field(3, rows)
+		t.listField(4, compactStruct, len(w.rowGroups), func(i int) {
+			group := w.rowGroups[i]
+			t.structValue(func() {
+				t.listField(1, compactStruct, len(group.chunks), func(c int) {
+					t.structValue(func() { columnChunkMetadata(&t, w.columns[c], group.chunks[c], group.rows) })
+				})
+				t.i64Field(2, group.size)
+				t.i64Field(3, group.rows)
+			})
+		})
+		t.stringField(6, CreatedBy)
+	})
+	return t.buf.Bytes()
+}
+
+// schemaElement encodes the SchemaElement fields of a column
+func schemaElement(t *thriftWriter, column Column) {
+	t.i32Field(1, physicalType(column.Type))
+	if column.Optional {
+		t.i32Field(3, repetitionOptional)
+	} else {
+		t.i32Field(3, repetitionRequired)
+	}
+	t.stringField(4, column.Name)
+
+	switch column.Type {
+	case String:
+		t.i32Field(6, convertedUTF8)
+		t.structField(10, func() {
+			t.structField(1, func() {})
+		})
+	case Timestamp:
+		t.i32Field(6, convertedTimestampMicros)
+		t.structField(10, func() {
+			t.structField(8, func() {
+				t.boolField(1, true)
+				t.structField(2, func() {
+					t.structField(2, func() {})
+				})
+			})
+		})
+	}
+}
+
+// columnChunkMetadata encodes the ColumnChunk fields of a column chunk of a row group
+func columnChunkMetadata(t *thriftWriter, column Column, chunk columnChunk, rows int64) {
+	t.i64Field(2, chunk.offset)
+	t.structField(3, func() {
+		t.i32Field(1, physicalType(column.Type))
+		t.listField(2, compactI32, 2, func(i int) {
+			t.varint([]int64{encodingPlain, encodingRLE}[i])
+		})
+		t.listField(3, compactBinary, 1, func(int) {
+			t.binary(column.Name)
+		})
+		t.i32Field(4, codecGzip)
+		t.i64Field(5, rows)
+		t.i64Field(6, chunk.uncompressedSize)
+		t.i64Field(7, chunk.compressedSize)
+		t.i64Field(9, chunk.offset)
+	})
+}
+
+// physicalType returns the Parquet physical type values of a type are stored as
+func physicalType(typ Type) int32 {
+	switch typ {
+	case Double:
+		return physicalDouble
+	case Int64, Timestamp:
+		return physicalInt64
+	default:
+		return physicalByteArray
+	}
+}
@@ -0,0 +1,114 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol types of the values written
+const (
+	compactBoolTrue  = 1
+	compactBoolFalse = 2
+	compactI32       = 5
+	compactI64       = 6
+	compactBinary    = 8
+	compactList      = 9
+	compactStruct    = 12
+)
+
+// thriftWriter encodes the Parquet metadata structs in the Thrift compact protocol. Fields are
+// written by ID, each struct between structBegin and structEnd.
+type thriftWriter struct {
+	buf bytes.Buffer
+	// lastField holds the last field ID written of every open struct, which field headers are
+	// encoded relative to
+	lastField []int16
+}
+
+// structBegin opens a struct
+func (t *thriftWriter) structBegin() {
+	t.lastField = append(t.lastField, 0)
+}
+
+// structEnd closes the innermost open struct
+func (t *thriftWriter) structEnd() {
+	t.buf.WriteByte(0)
+	t.lastField = t.lastField[:len(t.lastField)-1]
+}
+
+// fieldHeader writes the header of a field of the innermost open struct
+func (t *thriftWriter) fieldHeader(id int16, typ byte) {
+	last := &t.lastField[len(t.lastField)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.varint(int64(id))
+	}
+	*last = id
+}
+
+// boolField writes a bool field, whose value is carried by its header
+func (t *thriftWriter) boolField(id int16, v bool) {
+	if v {
+		t.fieldHeader(id, compactBoolTrue)
+	} else {
+		t.fieldHeader(id, compactBoolFalse)
+	}
+}
+
+// i32Field writes an i32 field, such as an enum
+func (t *thriftWriter) i32Field(id int16, v int32) {
+	t.fieldHeader(id, compactI32)
+	t.varint(int64(v))
+}
+
+// i64Field writes an i64 field
+func (t *thriftWriter) i64Field(id int16, v int64) {
+	t.fieldHeader(id, compactI64)
+	t.varint(v)
+}
+
+// stringField writes a string field
+func (t *thriftWriter) stringField(id int16, s string) {
+	t.fieldHeader(id, compactBinary)
+	t.binary(s)
+}
+
+// structField writes a struct field whose fields are written by write
+func (t *thriftWriter) structField(id int16, write func()) {
+	t.fieldHeader(id, compactStruct)
+	t.structValue(write)
+}
+
+// listField writes a list field of n elements of a type, each written by write
+func (t *thriftWriter) listField(id int16, elemType byte, n int, write func(i int)) {
+	t.fieldHeader(id, compactList)
+	if n < 15 {
+		t.buf.WriteByte(byte(n)<<4 | elemType)
+	} else {
+		t.buf.WriteByte(0xf0 | elemType)
+		t.buf.Write(binary.AppendUvarint(nil, uint64(n)))
+	}
+	for i := 0; i < n; i++ {
+		write(i)
+	}
+}
+
+// structValue writes a struct list element or field value
+func (t *thriftWriter) structValue(write func()) {
+	t.structBegin()
+	write()
+	t.structEnd()
+}
+
+// binary writes a string list element or field value
+func (t *thriftWriter) binary(s string) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64(len(s))))
+	t.buf.WriteString(s)
+}
+
+// varint writes an integer list element or field value, zigzag encoded
+func (t *thriftWriter) varint(v int64) {
+	t.buf.Write(binary.AppendUvarint(nil, uint64(v<<1)^uint64(v>>63)))
+}
@@ -0,0 +1,184 @@
+// Package payload bounds the size of the actor message payloads and event data persisted by the
+// observability pipeline. Large documents are stored gzip compressed inside a JSON envelope, and
+// documents still over the size limit are replaced by a truncation marker, so every stored value
+// remains valid JSON for the JSONB columns.
+package payload
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+)
+
+// EncodingGzip identifies payloads stored gzip compressed
+const EncodingGzip = "gzip"
+
+// compressedEnvelope stores a compressed payload; Data is base64 encoded by encoding/json
+type compressedEnvelope struct {
+	Compressed   string `json:"_compressed"`
+	OriginalSize int    `json:"original_size"`
+	Data         []byte `json:"data"`
+}
+
+// TruncatedPayload replaces a payload that exceeds the size limit even when compressed.
+// Preview holds the start of the original document, which is usually not valid JSON on its own.
+type TruncatedPayload struct {
+	Truncated    bool   `json:"_truncated"`
+	OriginalSize int    `json:"original_size"`
+	Preview      string `json:"preview"`
+}
+
+// Stats counts the payloads changed by a guard since it was created
+type Stats struct {
+	Compressed int64 `json:"compressed"`
+	Truncated  int64 `json:"truncated"`
+}
+
+// Guard compresses and truncates payloads before they are persisted. A nil Guard leaves
+// payloads unchanged.
+type Guard struct {
+	cfg        config.PayloadConfig
+	compressed atomic.Int64
+	truncated  atomic.Int64
+}
+
+// NewGuard creates a guard enforcing cfg
+func NewGuard(cfg config.PayloadConfig) *Guard {
+	return &Guard{cfg: cfg}
+}
+
+// JSON returns raw unchanged when it is within the limits, and otherwise its compressed
+// envelope or truncation marker
+func (g *Guard) JSON(raw json.RawMessage) json.RawMessage {
+	if g == nil || len(raw) == 0 {
+		return raw
+	}
+
+	stored := raw
+	if g.cfg.CompressionThreshold > 0 && len(raw) > g.cfg.CompressionThreshold {
+		// Compression only pays off when the envelope is smaller than the original
+		if envelope, err := compress(raw); err == nil && len(envelope) < len(raw) {
+			stored = envelope
+			g.compressed.Add(1)
+		}
+	}
+
+	if len(stored) <= g.cfg.MaxSize {
+		return stored
+	}
+
+	g.truncated.Add(1)
+	marker, _ := json.Marshal(TruncatedPayload{
+		Truncated:    true,
+		OriginalSize: len(raw),
+		Preview:      preview(raw, g.cfg.PreviewSize),
+	})
+	return marker
+}
+
+// ActorMessage returns message with its payload guarded. The message passed in is not
+// modified; it is returned as is when its payload is within the limits.
+func (g *Guard) ActorMessage(message *models.ActorMessage) *models.ActorMessage {
+	guarded := g.JSON(message.MessagePayload)
+	if bytes.Equal(guarded, message.MessagePayload) {
+		return message
+	}
+
+	copied := *message
+	copied.MessagePayload = guarded
+	return &copied
+}
+
+// EventLog returns event with its event data guarded. The event passed in is not modified;
+// it is returned as is when its event data is within the limits.
+func (g *Guard) EventLog(event *models.EventLog) *models.EventLog {
+	guarded := g.JSON(event.EventData)
+	if bytes.Equal(guarded, event.EventData) {
+		return event
+	}
+
+	copied := *event
+	copied.EventData = guarded
+	return &copied
+}
+
+// Stats returns the number of payloads compressed and truncated so far
+func (g *Guard) Stats() Stats {
+	if g == nil {
+		return Stats{}
+	}
+	return Stats{
+		Compressed: g.compressed.Load(),
+		Truncated:  g.truncated.Load(),
+	}
+}
+
+// Decode returns the original document of a compressed payload. Other payloads, including
+// truncation markers, are returned unchanged.
+func Decode(raw json.RawMessage) json.RawMessage {
+	if !bytes.Contains(raw, []byte(`"_compressed"`)) {
+		return raw
+	}
+
+	var envelope compressedEnvelope
+	if err := json.Unmarshal(raw, &envelope); err != nil || envelope.Compressed != EncodingGzip {
+		return raw
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(envelope.Data))
+	if err != nil {
+		return raw
+	}
+	defer reader.Close()
+
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		return raw
+	}
+	return decoded
+}
+
+// DecodeActorMessage decodes the payload of a message read from storage in place
+func DecodeActorMessage(message *models.ActorMessage) {
+	message.MessagePayload = Decode(message.MessagePayload)
+}
+
+// DecodeEventLog decodes the event data of an event log read from storage in place
+func DecodeEventLog(event *models.EventLog) {
+	event.EventData = Decode(event.EventData)
+}
+
+// compress returns the gzip envelope of raw
+func compress(raw json.RawMessage) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(raw); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(compressedEnvelope{
+		Compressed:   EncodingGzip,
+		OriginalSize: len(raw),
+		Data:         buf.Bytes(),
+	})
+}
+
+// preview returns up to size bytes from the start of raw without splitting a UTF-8 character
+func preview(raw json.RawMessage, size int) string {
+	if len(raw) <= size {
+		return string(raw)
+	}
+	for size > 0 && !utf8.RuneStart(raw[size]) {
+		size--
+	}
+	return string(raw[:size])
+}
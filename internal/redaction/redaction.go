@@ -0,0 +1,292 @@
+// Package redaction strips or hashes personal data, such as phone numbers, emails and exact
+// coordinates, from the actor message payloads and event data returned by observability
+// endpoints. Callers with the operator role see the data unredacted.
+package redaction
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+)
+
+// Redaction actions applied to the values of matching fields
+const (
+	ActionRemove = "remove" // drop the field
+	ActionHash   = "hash"   // replace the value with a keyed hash, so equal values stay correlatable
+	ActionRound  = "round"  // round numbers to 2 decimal places, about 1 km for coordinates
+)
+
+// roundPrecision is the number of decimal places kept by ActionRound
+const roundPrecision = 2
+
+// hashLength is the number of hex characters kept from the HMAC of a hashed value
+const hashLength = 16
+
+type operatorKey struct{}
+
+// WithOperator marks the request context as coming from a caller with the operator role
+func WithOperator(ctx context.Context) context.Context {
+	return context.WithValue(ctx, operatorKey{}, true)
+}
+
+// IsOperator reports whether the context belongs to a caller with the operator role
+func IsOperator(ctx context.Context) bool {
+	operator, _ := ctx.Value(operatorKey{}).(bool)
+	return operator
+}
+
+// IsValidAction returns true if the action is supported
+func IsValidAction(action string) bool {
+	switch action {
+	case ActionRemove, ActionHash, ActionRound:
+		return true
+	}
+	return false
+}
+
+// Redactor applies redaction rules to JSON documents. A rule's path is a dot-separated list of
+// object keys matched against the end of a field's key path, so "phone" matches a phone field
+// at any depth and "passenger.phone" only one nested in passenger. Each segment is a
+// path.Match pattern, e.g. "*_lat"; array elements are matched like their parent field.
+// The first matching rule wins. A nil Redactor leaves data unchanged.
+type Redactor struct {
+	rules   []rule
+	hashKey []byte
+}
+
+type rule struct {
+	segments []string
+	action   string
+}
+
+// New creates a redactor for rules. Hashed values are keyed with hashKey; when it is empty a
+// random key is generated, so hashes are only stable for the life of the process.
+func New(rules []config.RedactionRule, hashKey string) (*Redactor, error) {
+	r := &Redactor{hashKey: []byte(hashKey)}
+	if len(r.hashKey) == 0 {
+		r.hashKey = make([]byte, 32)
+		if _, err := rand.Read(r.hashKey); err != nil {
+			return nil, fmt.Errorf("failed to generate redaction hash key: %w", err)
+		}
+	}
+
+	for _, cfgRule := range rules {
+		if !IsValidAction(cfgRule.Action) {
+			return nil, fmt.Errorf("invalid redaction action %q for %q", cfgRule.Action, cfgRule.Path)
+		}
+		segments := strings.Split(cfgRule.Path, ".")
+		for _, segment := range segments {
+			if _, err := path.Match(segment, ""); segment == "" || err != nil {
+				return nil, fmt.Errorf("invalid redaction path %q", cfgRule.Path)
+			}
+		}
+		r.rules = append(r.rules, rule{segments: segments, action: cfgRule.Action})
+	}
+
+	return r, nil
+}
+
+// JSON returns a redacted copy of a JSON document. Documents that aren't valid JSON are
+// dropped entirely rather than returned unredacted.
+func (r *Redactor) JSON(raw json.RawMessage) json.RawMessage {
+	if r == nil || len(raw) == 0 || len(r.rules) == 0 {
+		return raw
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return nil
+	}
+
+	redacted, err := json.Marshal(r.redact(doc, nil))
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+// ActorMessages returns the messages with redacted payloads, unless ctx belongs to an operator.
+// The messages passed in are not modified.
+func (r *Redactor) ActorMessages(ctx context.Context, messages []*models.ActorMessage) []*models.ActorMessage {
+	if r == nil || IsOperator(ctx) {
+		return messages
+	}
+
+	redacted := make([]*models.ActorMessage, len(messages))
+	for i, message := range messages {
+		copied := *message
+		copied.MessagePayload = r.JSON(message.MessagePayload)
+		redacted[i] = &copied
+	}
+	return redacted
+}
+
+// EventLogs returns the event logs with redacted event data, unless ctx belongs to an operator.
+// The event logs passed in are not modified.
+func (r *Redactor) EventLogs(ctx context.Context, events []*models.EventLog) []*models.EventLog {
+	if r == nil || IsOperator(ctx) {
+		return events
+	}
+
+	redacted := make([]*models.EventLog, len(events))
+	for i, event := range events {
+		redacted[i] = r.EventLog(ctx, event)
+	}
+	return redacted
+}
+
+// EventLog returns the event log with redacted event data, unless ctx belongs to an operator
+func (r *Redactor) EventLog(ctx context.Context, event *models.EventLog) *models.EventLog {
+	if r == nil || IsOperator(ctx) {
+		return event
+	}
+
+	copied := *event
+	copied.EventData = r.JSON(event.EventData)
+	return &copied
+}
+
+// TraditionalLogs returns the logs with redacted fields, unless ctx belongs to an operator.
+// The logs passed in are not modified.
+func (r *Redactor) TraditionalLogs(ctx context.Context, logs []*models.TraditionalLog) []*models.TraditionalLog {
+	if r == nil || IsOperator(ctx) {
+		return logs
+	}
+
+	redacted := make([]*models.TraditionalLog, len(logs))
+	for i, log := range logs {
+		copied := *log
+		copied.Fields = r.JSON(log.Fields)
+		redacted[i] = &copied
+	}
+	return redacted
+}
+
+// redact applies the rules to the fields of value, whose key path is keys
+func (r *Redactor) redact(value interface{}, keys []string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, field := range v {
+			fieldKeys := append(keys[:len(keys):len(keys)], key)
+			action, ok := r.match(fieldKeys)
+			if !ok {
+				result[key] = r.redact(field, fieldKeys)
+				continue
+			}
+			switch action {
+			case ActionHash:
+				result[key] = r.hash(field)
+			case ActionRound:
+				if rounded, ok := round(field); ok {
+					result[key] = rounded
+				}
+			}
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, element := range v {
+			result[i] = r.redact(element, keys)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// match returns the action of the first rule matching the end of keys
+func (r *Redactor) match(keys []string) (string, bool) {
+	for _, rule := range r.rules {
+		if len(rule.segments) > len(keys) {
+			continue
+		}
+		tail := keys[len(keys)-len(rule.segments):]
+		matched := true
+		for i, segment := range rule.segments {
+			if ok, _ := path.Match(segment, tail[i]); !ok {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return rule.action, true
+		}
+	}
+	return "", false
+}
+
+// hash returns a keyed hash of a value; nulls stay null
+func (r *Redactor) hash(value interface{}) interface{} {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		data = []byte(v)
+	default:
+		data, _ = json.Marshal(v)
+	}
+
+	mac := hmac.New(sha256.New, r.hashKey)
+	mac.Write(data)
+	return "sha256:" + hex.EncodeToString(mac.Sum(nil))[:hashLength]
+}
+
+// round rounds a number, a numeric string or every number nested in an object or array.
+// Values that can't be rounded are reported as not ok and dropped.
+func round(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, true
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return nil, false
+		}
+		return roundFloat(f), true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return nil, false
+		}
+		return strconv.FormatFloat(roundFloat(f), 'f', -1, 64), true
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, field := range v {
+			if rounded, ok := round(field); ok {
+				result[key] = rounded
+			}
+		}
+		return result, true
+	case []interface{}:
+		result := make([]interface{}, 0, len(v))
+		for _, element := range v {
+			if rounded, ok := round(element); ok {
+				result = append(result, rounded)
+			}
+		}
+		return result, true
+	default:
+		return nil, false
+	}
+}
+
+func roundFloat(f float64) float64 {
+	scale := math.Pow(10, roundPrecision)
+	return math.Round(f*scale) / scale
+}
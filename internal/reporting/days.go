@@ -0,0 +1,101 @@
+// Package reporting computes the calendar days and hours reports are aggregated by in an
+// operator's timezone.
+//
+// Timestamps are stored in UTC, so a local day is the range between two local midnights
+// converted to UTC. Around daylight saving changes such a day lasts 23 or 25 hours; grouping by
+// these boundaries (in SQL as well as in Go) keeps every trip on the local date it happened.
+package reporting
+
+import (
+	"fmt"
+	"time"
+)
+
+// DateLayout is the layout of the local dates reports are keyed by
+const DateLayout = "2006-01-02"
+
+// Day is a calendar day in a timezone, covering [Start, End)
+type Day struct {
+	Date  string
+	Start time.Time
+	End   time.Time
+}
+
+// Hours returns the length of the day, 23 or 25 when the clocks change during it
+func (d Day) Hours() int {
+	return int(d.End.Sub(d.Start) / time.Hour)
+}
+
+// LoadLocation loads an IANA timezone such as Europe/Berlin. The server's Local timezone is
+// rejected since it depends on where the server runs.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("timezone must be an IANA name such as Europe/Berlin")
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown timezone %q", name)
+	}
+	return loc, nil
+}
+
+// StartOfDay returns the local midnight starting the day of t in loc. Where the clocks change at
+// midnight the day starts at the first valid local time instead.
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// StartOfHour returns the start of the local hour of t in loc, which differs from truncating to
+// UTC hours in timezones with a fractional offset such as Asia/Kolkata
+func StartOfHour(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+}
+
+// DateOf returns the local date of t in loc
+func DateOf(t time.Time, loc *time.Location) string {
+	return t.In(loc).Format(DateLayout)
+}
+
+// Days returns the days of loc from the date from through the date to, both formatted as
+// DateLayout and inclusive
+func Days(from, to string, loc *time.Location) ([]Day, error) {
+	first, err := time.ParseInLocation(DateLayout, from, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", from)
+	}
+	last, err := time.ParseInLocation(DateLayout, to, loc)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", to)
+	}
+	if last.Before(first) {
+		return nil, fmt.Errorf("%s is before %s", to, from)
+	}
+
+	var days []Day
+	for date := first; !date.After(last); date = date.AddDate(0, 0, 1) {
+		// AddDate keeps the wall clock, so each start is a local midnight whatever the offset
+		start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+		end := time.Date(date.Year(), date.Month(), date.Day()+1, 0, 0, 0, 0, loc)
+		days = append(days, Day{
+			Date:  start.Format(DateLayout),
+			Start: start,
+			End:   end,
+		})
+	}
+	return days, nil
+}
+
+// Boundaries returns the start of every day followed by the end of the last one, the
+// consecutive UTC ranges aggregate queries group by
+func Boundaries(days []Day) []time.Time {
+	if len(days) == 0 {
+		return nil
+	}
+	boundaries := make([]time.Time, 0, len(days)+1)
+	for _, day := range days {
+		boundaries = append(boundaries, day.Start.UTC())
+	}
+	return append(boundaries, days[len(days)-1].End.UTC())
+}
@@ -0,0 +1,21 @@
+package repository
+
+import "context"
+
+type columnsKey struct{}
+
+// WithColumns returns a context asking list queries to load only the given columns, named
+// like the JSON fields of the listed model. Repositories that cannot narrow their queries,
+// or columns they do not know, are ignored, so callers must still trim the response.
+func WithColumns(ctx context.Context, columns []string) context.Context {
+	if len(columns) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, columnsKey{}, columns)
+}
+
+// ColumnsFromContext returns the columns requested with WithColumns, or nil for all columns
+func ColumnsFromContext(ctx context.Context) []string {
+	columns, _ := ctx.Value(columnsKey{}).([]string)
+	return columns
+}
@@ -0,0 +1,128 @@
+package factory
+
+import (
+	"fmt"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/repository/postgres"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Repositories groups every repository used by the services
+type Repositories struct {
+	Users          repository.UserRepository
+	Drivers        repository.DriverRepository
+	Passengers     repository.PassengerRepository
+	Trips          repository.TripRepository
+	SavedLocations repository.SavedLocationRepository
+	Webhooks       repository.WebhookRepository
+	Sessions       repository.SessionRepository
+	FareDisputes   repository.FareDisputeRepository
+	Incentives     repository.IncentiveRepository
+	Fleets         repository.FleetRepository
+	Corporate      repository.CorporateAccountRepository
+	Invoices       repository.InvoiceRepository
+	ETAPredictions repository.ETAPredictionRepository
+	LocationTrail  repository.DriverLocationRepository
+	Chat           repository.ChatRepository
+	Incidents      repository.SafetyIncidentRepository
+	FraudSignals   repository.FraudSignalRepository
+	Payments       repository.PaymentRepository
+	Onboarding     repository.DriverOnboardingRepository
+	APIUsage       repository.APIUsageRepository
+	Dashboard      repository.DashboardRepository
+	Integrity      repository.IntegrityRepository
+	StorageCost    repository.StorageCostRepository
+	Observability  repository.ObservabilityRepository
+	Traditional    repository.TraditionalRepository
+}
+
+// NewRepositories creates the repositories for the configured database driver.
+// The memory driver ignores db and reader; postgres and sqlite share the SQL implementations.
+// reader may be nil, in which case list queries use db.
+func NewRepositories(cfg *config.DatabaseConfig, db *sqlx.DB, reader postgres.DBReader) (*Repositories, error) {
+	switch cfg.Driver {
+	case "memory":
+		return NewMemoryRepositories(memory.NewStore()), nil
+	case "", "postgres", "sqlite":
+		if db == nil {
+			return nil, fmt.Errorf("database connection is required for the %s driver", cfg.Driver)
+		}
+		return NewPostgresRepositories(db, reader), nil
+	default:
+		return nil, fmt.Errorf("unsupported database driver: %s", cfg.Driver)
+	}
+}
+
+// NewPostgresRepositories creates SQL-backed repositories; reader may be nil
+func NewPostgresRepositories(db *sqlx.DB, reader postgres.DBReader) *Repositories {
+	repos := &Repositories{
+		Users:          postgres.NewUserRepository(db),
+		Drivers:        postgres.NewDriverRepository(db),
+		Passengers:     postgres.NewPassengerRepository(db),
+		Trips:          postgres.NewTripRepository(db),
+		SavedLocations: postgres.NewSavedLocationRepository(db),
+		Webhooks:       postgres.NewWebhookRepository(db),
+		Sessions:       postgres.NewSessionRepository(db),
+		FareDisputes:   postgres.NewFareDisputeRepository(db),
+		Incentives:     postgres.NewIncentiveRepository(db),
+		Fleets:         postgres.NewFleetRepository(db),
+		Corporate:      postgres.NewCorporateAccountRepository(db),
+		Invoices:       postgres.NewInvoiceRepository(db),
+		ETAPredictions: postgres.NewETAPredictionRepository(db),
+		LocationTrail:  postgres.NewDriverLocationRepository(db),
+		Chat:           postgres.NewChatRepository(db),
+		Incidents:      postgres.NewSafetyIncidentRepository(db),
+		FraudSignals:   postgres.NewFraudSignalRepository(db),
+		Payments:       postgres.NewPaymentRepository(db),
+		Onboarding:     postgres.NewDriverOnboardingRepository(db),
+		APIUsage:       postgres.NewAPIUsageRepository(db),
+		Dashboard:      postgres.NewDashboardRepository(db),
+		Integrity:      postgres.NewIntegrityRepository(db),
+		StorageCost:    postgres.NewStorageCostRepository(db),
+	}
+
+	if reader != nil {
+		repos.Observability = postgres.NewObservabilityRepositoryWithReader(db, reader)
+		repos.Traditional = postgres.NewTraditionalRepositoryWithReader(db, reader)
+	} else {
+		repos.Observability = postgres.NewObservabilityRepository(db)
+		repos.Traditional = postgres.NewTraditionalRepository(db)
+	}
+
+	return repos
+}
+
+// NewMemoryRepositories creates in-memory repositories sharing a single store
+func NewMemoryRepositories(store *memory.Store) *Repositories {
+	return &Repositories{
+		Users:          memory.NewUserRepository(store),
+		Drivers:        memory.NewDriverRepository(store),
+		Passengers:     memory.NewPassengerRepository(store),
+		Trips:          memory.NewTripRepository(store),
+		SavedLocations: memory.NewSavedLocationRepository(store),
+		Webhooks:       memory.NewWebhookRepository(store),
+		Sessions:       memory.NewSessionRepository(store),
+		FareDisputes:   memory.NewFareDisputeRepository(store),
+		Incentives:     memory.NewIncentiveRepository(store),
+		Fleets:         memory.NewFleetRepository(store),
+		Corporate:      memory.NewCorporateAccountRepository(store),
+		Invoices:       memory.NewInvoiceRepository(store),
+		ETAPredictions: memory.NewETAPredictionRepository(store),
+		LocationTrail:  memory.NewDriverLocationRepository(store),
+		Chat:           memory.NewChatRepository(store),
+		Incidents:      memory.NewSafetyIncidentRepository(store),
+		FraudSignals:   memory.NewFraudSignalRepository(store),
+		Payments:       memory.NewPaymentRepository(store),
+		Onboarding:     memory.NewDriverOnboardingRepository(store),
+		APIUsage:       memory.NewAPIUsageRepository(store),
+		Dashboard:      memory.NewDashboardRepository(store),
+		Integrity:      memory.NewIntegrityRepository(store),
+		StorageCost:    memory.NewStorageCostRepository(store),
+		Observability:  memory.NewObservabilityRepository(store),
+		Traditional:    memory.NewTraditionalRepository(store),
+	}
+}
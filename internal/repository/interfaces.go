@@ -2,8 +2,12 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"actor-model-observability/internal/filter"
 	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
 )
 
 // UserRepository defines the interface for user data operations
@@ -27,8 +31,40 @@ type DriverRepository interface {
 	GetOnlineDrivers(ctx context.Context) ([]*models.Driver, error)
 	GetDriversInRadius(ctx context.Context, lat, lng, radiusKm float64) ([]*models.Driver, error)
 	UpdateLocation(ctx context.Context, driverID string, lat, lng float64) error
+	// UpdateLocations writes the locations of many drivers in one transaction and returns how
+	// many drivers were updated; drivers that no longer exist are skipped
+	UpdateLocations(ctx context.Context, updates []*models.DriverLocationUpdate) (int64, error)
 	UpdateStatus(ctx context.Context, driverID string, status models.DriverStatus) error
+	// Reserve atomically sets an online driver busy for a trip, failing with ErrDriverNotOnline
+	// when another request reserved the driver first or the driver went offline
+	Reserve(ctx context.Context, driverID string) error
+	// Release sets a reserved driver back online, e.g. when the trip could not be assigned
+	Release(ctx context.Context, driverID string) error
 	List(ctx context.Context, limit, offset int) ([]*models.Driver, error)
+	// ListByFleet returns every driver of a fleet, oldest first
+	ListByFleet(ctx context.Context, fleetID string) ([]*models.Driver, error)
+	// GetDriverStats returns per-driver aggregates for the filter period and the total number of drivers
+	GetDriverStats(ctx context.Context, filter models.DriverStatsFilter) ([]*models.DriverStats, int64, error)
+	// GetTripStats returns the rollup of a driver's completed trips, kept up to date by
+	// TripRepository.Complete
+	GetTripStats(ctx context.Context, driverID string) (*models.DriverTripStats, error)
+
+	// Destination mode
+	SetDestination(ctx context.Context, destination *models.DriverDestination) error
+	GetActiveDestination(ctx context.Context, driverID string) (*models.DriverDestination, error)
+	GetActiveDestinations(ctx context.Context) (map[string]*models.DriverDestination, error)
+	ClearDestination(ctx context.Context, driverID string) error
+	CountDestinationsSince(ctx context.Context, driverID string, since time.Time) (int, error)
+
+	// Fatigue limits; time online and driving is derived from the driver status history
+	GetDriverHours(ctx context.Context, driverID string, from, to time.Time) (*models.DriverHours, error)
+	// ListOnlineDriverHours returns the hours within [from, to) of every driver currently online
+	ListOnlineDriverHours(ctx context.Context, from, to time.Time) ([]*models.DriverHours, error)
+	// StartRest records the rest and sets the driver offline, failing with ErrDriverNotOnline
+	// unless the driver is online
+	StartRest(ctx context.Context, rest *models.DriverRest) error
+	// GetLatestRest returns the driver's rest ending last, which may be over
+	GetLatestRest(ctx context.Context, driverID string) (*models.DriverRest, error)
 }
 
 // PassengerRepository defines the interface for passenger data operations
@@ -51,8 +87,360 @@ type TripRepository interface {
 	GetByDriverID(ctx context.Context, driverID string, limit, offset int) ([]*models.Trip, error)
 	GetActiveTrips(ctx context.Context) ([]*models.Trip, error)
 	GetTripsByStatus(ctx context.Context, status models.TripStatus, limit, offset int) ([]*models.Trip, error)
+	// CountByStatus counts the trips with a status
+	CountByStatus(ctx context.Context, status models.TripStatus) (int64, error)
 	GetTripsByDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*models.Trip, error)
 	List(ctx context.Context, limit, offset int) ([]*models.Trip, error)
+	// GetRecentDestinations returns a passenger's distinct destinations, most recently visited first
+	GetRecentDestinations(ctx context.Context, passengerID string, limit int) ([]*models.RecentDestination, error)
+	// GetRequestTimes returns when the trips requested in [start, end) were requested, oldest
+	// first, only those picked up within bounds when set
+	GetRequestTimes(ctx context.Context, start, end time.Time, bounds *models.GeoBounds) ([]time.Time, error)
+	// GetGridCounts counts the pickups of the trips requested and the dropoffs of the trips
+	// completed in [start, end) per cell of a grid of cellHeight by cellWidth degrees
+	GetGridCounts(ctx context.Context, start, end time.Time, cellHeight, cellWidth float64) ([]*models.TripGridCount, error)
+	// ListByFleet returns the trips of a fleet's drivers requested in [start, end), oldest first
+	ListByFleet(ctx context.Context, fleetID string, start, end time.Time) ([]*models.Trip, error)
+	// ListRequested returns the trips requested in [start, end), oldest first
+	ListRequested(ctx context.Context, start, end time.Time) ([]*models.Trip, error)
+	// GetDailyCounts counts the trips requested, completed and cancelled and sums the completed
+	// fares per day, day i covering [boundaries[i], boundaries[i+1]). Days without trips are omitted.
+	GetDailyCounts(ctx context.Context, boundaries []time.Time) ([]*models.DailyTripCount, error)
+	// ListCancelled returns the trips cancelled in [start, end), oldest first
+	ListCancelled(ctx context.Context, start, end time.Time) ([]*models.Trip, error)
+	// GetDailyCancellations counts the trips cancelled per day and reason, day i covering
+	// [boundaries[i], boundaries[i+1]). Trips cancelled without a reason have an empty one.
+	GetDailyCancellations(ctx context.Context, boundaries []time.Time) ([]*models.DailyCancellationCount, error)
+	// Complete stores the completed trip and the completion details its driver entered, failing
+	// with ErrTripStatusConflict unless the stored trip still has status from
+	Complete(ctx context.Context, trip *models.Trip, from models.TripStatus, completion *models.TripCompletion) error
+	// GetCompletion returns the completion details of a trip
+	GetCompletion(ctx context.Context, tripID string) (*models.TripCompletion, error)
+	// ListCompletions returns the matching completions, oldest first, and the total number of matches
+	ListCompletions(ctx context.Context, filter models.TripCompletionFilter) ([]*models.TripCompletion, int64, error)
+	// StartPickupWait stores the trip its driver arrived at and starts the wait for its passenger,
+	// failing with ErrTripStatusConflict unless the stored trip still has status from
+	StartPickupWait(ctx context.Context, trip *models.Trip, from models.TripStatus, wait *models.PickupWait) error
+	// EndPickupWait stores the trip whose passenger was picked up or did not show and ends its
+	// wait, failing with ErrTripStatusConflict unless the stored trip still has status from
+	EndPickupWait(ctx context.Context, trip *models.Trip, from models.TripStatus, wait *models.PickupWait) error
+	// GetPickupWait returns the driver's wait at the pickup of a trip
+	GetPickupWait(ctx context.Context, tripID string) (*models.PickupWait, error)
+	// GetPickupWaitStats returns the wait-time metrics of the arrivals in [from, to) by pickup zone
+	GetPickupWaitStats(ctx context.Context, from, to time.Time) ([]*models.PickupWaitZoneStats, error)
+	// RecordDriverCancellation stores the trip its matched driver cancelled and records the
+	// re-match, failing with ErrTripStatusConflict unless the stored trip still has status from
+	RecordDriverCancellation(ctx context.Context, trip *models.Trip, from models.TripStatus, rematch *models.TripRematch) error
+	// EndRematch records how a re-match ended, failing with ErrTripStatusConflict unless it is
+	// still matching
+	EndRematch(ctx context.Context, rematch *models.TripRematch) error
+	// ListRematches returns the re-matches of a trip, first first
+	ListRematches(ctx context.Context, tripID string) ([]*models.TripRematch, error)
+	// Search returns the trips matching the support search filter with their passenger and driver
+	// contact details, most recently requested first, and the total number of matches
+	Search(ctx context.Context, filter models.TripSearchFilter) ([]*models.TripSearchResult, int64, error)
+	// ListStuck returns up to limit trips neither completed nor cancelled that were last updated
+	// before updatedBefore, least recently updated first
+	ListStuck(ctx context.Context, updatedBefore time.Time, limit int) ([]*models.Trip, error)
+}
+
+// SavedLocationRepository defines the interface for passenger saved location operations
+type SavedLocationRepository interface {
+	Create(ctx context.Context, location *models.SavedLocation) error
+	GetByID(ctx context.Context, id string) (*models.SavedLocation, error)
+	Update(ctx context.Context, location *models.SavedLocation) error
+	Delete(ctx context.Context, id string) error
+	ListByPassengerID(ctx context.Context, passengerID string) ([]*models.SavedLocation, error)
+}
+
+// WebhookRepository defines the interface for webhook subscription and delivery operations
+type WebhookRepository interface {
+	// Subscriptions
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	ListSubscriptionsByOwner(ctx context.Context, owner string) ([]*models.WebhookSubscription, error)
+	// ListSubscriptionsForEvent returns the active subscriptions that receive the event
+	ListSubscriptionsForEvent(ctx context.Context, event models.WebhookEvent) ([]*models.WebhookSubscription, error)
+
+	// Deliveries
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	// GetDueDeliveries returns pending deliveries whose next attempt is at or before now, oldest first
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error)
+	// ListDeliveries returns the matching deliveries, newest first, and the total number of matches
+	ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int64, error)
+}
+
+// SessionRepository defines the interface for device session operations
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) error
+	GetByID(ctx context.Context, id string) (*models.Session, error)
+	GetByAccessTokenHash(ctx context.Context, hash string) (*models.Session, error)
+	GetByRefreshTokenHash(ctx context.Context, hash string) (*models.Session, error)
+	// Update stores the session's tokens, expiry, last use, app version and revocation
+	Update(ctx context.Context, session *models.Session) error
+	// ListActiveByUser returns the user's sessions not revoked or expired at now, most recently used first
+	ListActiveByUser(ctx context.Context, userID string, now time.Time) ([]*models.Session, error)
+	// CountActiveByAppVersion counts the sessions not revoked or expired at now per app platform
+	// and version, by platform and most sessions first
+	CountActiveByAppVersion(ctx context.Context, now time.Time) ([]*models.AppVersionSessions, error)
+}
+
+// FareDisputeRepository defines the interface for fare dispute and fare adjustment operations
+type FareDisputeRepository interface {
+	// CreateDispute fails with ErrDisputeAlreadyOpen when the trip already has an active dispute
+	CreateDispute(ctx context.Context, dispute *models.FareDispute) error
+	GetDispute(ctx context.Context, id string) (*models.FareDispute, error)
+	// UpdateDispute stores the dispute's status and review fields if its stored status is still
+	// from, and fails with ErrDisputeStatusConflict otherwise
+	UpdateDispute(ctx context.Context, dispute *models.FareDispute, from models.FareDisputeStatus) error
+	// ResolveDispute updates the dispute like UpdateDispute, records the adjustment in the ledger
+	// and sets the trip's fare to the adjusted fare, all in one transaction
+	ResolveDispute(ctx context.Context, dispute *models.FareDispute, from models.FareDisputeStatus, adjustment *models.FareAdjustment) error
+	// ListDisputes returns the matching disputes, oldest first, and the total number of matches
+	ListDisputes(ctx context.Context, filter models.FareDisputeFilter) ([]*models.FareDispute, int64, error)
+	// ListAdjustmentsByTrip returns the trip's fare adjustments ledger, oldest first
+	ListAdjustmentsByTrip(ctx context.Context, tripID string) ([]*models.FareAdjustment, error)
+}
+
+// IncentiveRepository defines the interface for driver incentive campaign operations
+type IncentiveRepository interface {
+	CreateCampaign(ctx context.Context, campaign *models.IncentiveCampaign) error
+	GetCampaign(ctx context.Context, id string) (*models.IncentiveCampaign, error)
+	// ListCampaigns returns the campaigns ordered by start time; when runningAt is set, only the
+	// active campaigns whose window contains it
+	ListCampaigns(ctx context.Context, runningAt *time.Time) ([]*models.IncentiveCampaign, error)
+	SetCampaignActive(ctx context.Context, id string, active bool) error
+	// CreditTrip counts a completed trip towards the driver's progress on the campaign and fails
+	// with ErrDuplicateEntry if the trip was already counted. Once the progress reaches
+	// payout.TripCount the payout is recorded, at most once per driver and campaign, and
+	// CreditTrip reports true. Everything happens in one transaction.
+	CreditTrip(ctx context.Context, credit *models.IncentiveTripCredit, payout *models.IncentivePayout) (*models.QuestProgress, bool, error)
+	ListProgressByDriver(ctx context.Context, driverID string) ([]*models.QuestProgress, error)
+	// ListPayoutsByDriver returns the driver's payouts, newest first
+	ListPayoutsByDriver(ctx context.Context, driverID string) ([]*models.IncentivePayout, error)
+}
+
+// FleetRepository defines the interface for fleet partner operations
+type FleetRepository interface {
+	Create(ctx context.Context, fleet *models.Fleet) error
+	GetByID(ctx context.Context, id string) (*models.Fleet, error)
+	// GetByAPIKeyHash returns the fleet whose API key hashes to hash
+	GetByAPIKeyHash(ctx context.Context, hash string) (*models.Fleet, error)
+	// List returns every fleet, oldest first
+	List(ctx context.Context) ([]*models.Fleet, error)
+	// Update stores the fleet's name, timezone and daily request quota
+	Update(ctx context.Context, fleet *models.Fleet) error
+}
+
+// CorporateAccountRepository defines the interface for corporate accounts and the trips billed to them
+type CorporateAccountRepository interface {
+	Create(ctx context.Context, account *models.CorporateAccount) error
+	GetByID(ctx context.Context, id string) (*models.CorporateAccount, error)
+	// Update stores the account's name and monthly spending limit
+	Update(ctx context.Context, account *models.CorporateAccount) error
+	// SetPassengerAccount links a passenger to an account, or unlinks it when accountID is nil
+	SetPassengerAccount(ctx context.Context, passengerID string, accountID *uuid.UUID) error
+	// ChargeTrip bills a trip to the charge's account unless limit is set and the account's
+	// spend on the charges created in [periodStart, periodEnd) plus the estimated fare would
+	// exceed it, in which case it fails with ErrCorporateSpendingLimitExceeded. The check and
+	// the insert happen in one transaction holding the account's row lock.
+	ChargeTrip(ctx context.Context, charge *models.CorporateTripCharge, limit *float64, periodStart, periodEnd time.Time) error
+	// ListCharges returns the account's charges created in [start, end) with the status and
+	// fare of their trip, oldest first
+	ListCharges(ctx context.Context, accountID string, start, end time.Time) ([]*models.CorporateTripCharge, error)
+	// List returns every corporate account, oldest first
+	List(ctx context.Context) ([]*models.CorporateAccount, error)
+}
+
+// InvoiceRepository defines the interface for the monthly invoices of corporate accounts and fleets
+type InvoiceRepository interface {
+	// Create stores a new invoice, failing with ErrDuplicateEntry when its party already has one
+	// for the month
+	Create(ctx context.Context, invoice *models.Invoice) error
+	GetByID(ctx context.Context, id string) (*models.Invoice, error)
+	// GetByParty returns a party's invoice for a month formatted as 2006-01
+	GetByParty(ctx context.Context, partyType models.InvoicePartyType, partyID, month string) (*models.Invoice, error)
+	// Update records the outcome of a generation attempt
+	Update(ctx context.Context, invoice *models.Invoice) error
+	// List returns the matching invoices, newest first, and the total number of matches
+	List(ctx context.Context, filter models.InvoiceFilter) ([]*models.Invoice, int64, error)
+}
+
+// ETAPredictionRepository defines the interface for the ETAs given at matching time and the
+// arrivals they are measured against
+type ETAPredictionRepository interface {
+	// Create stores a trip's ETA, failing with ErrDuplicateEntry when the trip already has one
+	Create(ctx context.Context, prediction *models.ETAPrediction) error
+	GetByTrip(ctx context.Context, tripID string) (*models.ETAPrediction, error)
+	// RecordArrival stores the arrival of a prediction, failing with a NotFoundError unless its
+	// trip has a prediction without an arrival
+	RecordArrival(ctx context.Context, prediction *models.ETAPrediction) error
+	// GetAccuracy returns the accuracy of the ETAs predicted in [from, to) whose drivers arrived,
+	// by pickup zone and hour of day, most samples first
+	GetAccuracy(ctx context.Context, from, to time.Time) ([]*models.ETAAccuracy, error)
+}
+
+// DriverLocationRepository defines the interface for the trails of the locations drivers reported
+type DriverLocationRepository interface {
+	Record(ctx context.Context, ping *models.DriverLocationPing) error
+	// GetTrail returns up to limit pings of a driver recorded in [from, to), oldest first
+	GetTrail(ctx context.Context, driverID string, from, to time.Time, limit int) ([]*models.DriverLocationPing, error)
+	// Downsample replaces the pings recorded before the cutoff at a finer resolution with one ping
+	// per driver and resolution interval, the last of the interval, and returns how many pings
+	// were removed
+	Downsample(ctx context.Context, before time.Time, resolution time.Duration) (int64, error)
+}
+
+// ChatRepository defines the interface for in-trip chat messages
+type ChatRepository interface {
+	Create(ctx context.Context, msg *models.TripChatMessage) error
+	// ListByTrip returns a trip's messages, oldest first
+	ListByTrip(ctx context.Context, tripID string, limit, offset int) ([]*models.TripChatMessage, error)
+	// MarkRead sets read_at on the unread messages of a trip sent to reader and returns how many
+	MarkRead(ctx context.Context, tripID string, reader models.ChatSenderRole, at time.Time) (int, error)
+	// CountUnread counts the unread messages of a trip per recipient
+	CountUnread(ctx context.Context, tripID string) (*models.TripChatUnreadCounts, error)
+	// DeleteForTripsEndedBefore deletes the messages of the trips completed or cancelled before
+	// cutoff, except trips with a safety incident awaiting review, and returns how many
+	DeleteForTripsEndedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// SafetyIncidentRepository defines the interface for SOS safety incidents
+type SafetyIncidentRepository interface {
+	Create(ctx context.Context, incident *models.SafetyIncident) error
+	GetByID(ctx context.Context, id string) (*models.SafetyIncident, error)
+	// Update stores the incident's status and review fields if its stored status is still from,
+	// and fails with ErrIncidentStatusConflict otherwise
+	Update(ctx context.Context, incident *models.SafetyIncident, from models.SafetyIncidentStatus) error
+	// List returns the matching incidents, oldest first, and the total number of matches
+	List(ctx context.Context, filter models.SafetyIncidentFilter) ([]*models.SafetyIncident, int64, error)
+}
+
+// DriverOnboardingRepository defines the interface for the onboarding stages of drivers
+type DriverOnboardingRepository interface {
+	// Create stores the onboarding of a driver, failing with ErrDuplicateEntry if the driver
+	// already has one
+	Create(ctx context.Context, onboarding *models.DriverOnboarding) error
+	// CreateWithDriver stores a new driver together with its onboarding in one transaction,
+	// failing like DriverRepository.Create if the driver cannot be stored
+	CreateWithDriver(ctx context.Context, driver *models.Driver, onboarding *models.DriverOnboarding) error
+	GetByDriverID(ctx context.Context, driverID string) (*models.DriverOnboarding, error)
+	// Update stores the onboarding's stage, documents and stage timestamps if its stored stage is
+	// still from, and fails with ErrOnboardingStageConflict otherwise
+	Update(ctx context.Context, onboarding *models.DriverOnboarding, from models.OnboardingStage) error
+	// CountByStage counts the drivers registered in [start, end) by their current stage
+	CountByStage(ctx context.Context, start, end time.Time) ([]models.OnboardingStageCount, error)
+}
+
+// FraudSignalRepository defines the interface for the fraud signals raised by detection rules
+type FraudSignalRepository interface {
+	// Create stores a new signal, failing with ErrDuplicateEntry if the rule already flagged the
+	// subject on the signal's trip
+	Create(ctx context.Context, signal *models.FraudSignal) error
+	GetByID(ctx context.Context, id string) (*models.FraudSignal, error)
+	// Update stores the signal's status and review fields if its stored status is still from,
+	// and fails with ErrFraudSignalStatusConflict otherwise
+	Update(ctx context.Context, signal *models.FraudSignal, from models.FraudSignalStatus) error
+	// List returns the matching signals, newest first, and the total number of matches
+	List(ctx context.Context, filter models.FraudSignalFilter) ([]*models.FraudSignal, int64, error)
+}
+
+// PaymentRepository defines the interface for the trip payments ledger and the discrepancies
+// reconciliation finds between it and the fares of completed trips
+type PaymentRepository interface {
+	// CreatePayment appends an entry to a trip's payments ledger
+	CreatePayment(ctx context.Context, payment *models.TripPayment) error
+	// ListPaymentsByTrip returns the trip's payments ledger, oldest first
+	ListPaymentsByTrip(ctx context.Context, tripID string) ([]*models.TripPayment, error)
+	// ListBalances returns the fare and net payments of the trips completed in [start, end),
+	// oldest first
+	ListBalances(ctx context.Context, start, end time.Time) ([]*models.TripPaymentBalance, error)
+	// CreateDiscrepancy stores a new discrepancy, failing with ErrDuplicateEntry if the trip has an
+	// open one of the same type
+	CreateDiscrepancy(ctx context.Context, discrepancy *models.PaymentDiscrepancy) error
+	GetDiscrepancy(ctx context.Context, id string) (*models.PaymentDiscrepancy, error)
+	// UpdateDiscrepancy stores the discrepancy's status and resolution fields if its stored status
+	// is still from, and fails with ErrPaymentDiscrepancyStatusConflict otherwise
+	UpdateDiscrepancy(ctx context.Context, discrepancy *models.PaymentDiscrepancy, from models.PaymentDiscrepancyStatus) error
+	// ListDiscrepancies returns the matching discrepancies, newest first, and the total number of
+	// matches
+	ListDiscrepancies(ctx context.Context, filter models.PaymentDiscrepancyFilter) ([]*models.PaymentDiscrepancy, int64, error)
+}
+
+// IntegrityRepository defines the interface for the row counts and checksums of the
+// observability tables
+type IntegrityRepository interface {
+	// ChecksumHours returns the row count and checksum of an observability table's rows per hour
+	// in [start, end), oldest first; hours without rows are left out. The table must be one of
+	// models.IntegrityTables.
+	ChecksumHours(ctx context.Context, table string, start, end time.Time) ([]*models.IntegrityChecksum, error)
+	// CreateRun stores a run with its checksums
+	CreateRun(ctx context.Context, run *models.IntegrityRun, checksums []*models.IntegrityChecksum) error
+	GetRun(ctx context.Context, id string) (*models.IntegrityRun, error)
+	// GetPreviousRun returns the run started last before the run, or nil when there is none
+	GetPreviousRun(ctx context.Context, run *models.IntegrityRun) (*models.IntegrityRun, error)
+	// ListRuns returns the latest runs, newest first
+	ListRuns(ctx context.Context, limit int) ([]*models.IntegrityRun, error)
+	// ListChecksums returns the checksums of a run by table, then hour
+	ListChecksums(ctx context.Context, runID string) ([]*models.IntegrityChecksum, error)
+	// DeleteRunsBefore removes the runs started before the cutoff with their checksums and returns
+	// how many were removed
+	DeleteRunsBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// StorageCostRepository defines the interface for the storage taken up by the observability
+// tables and the ride requests it is compared against. Buckets index the intervals of the given
+// length starting at start.
+type StorageCostRepository interface {
+	// TableUsage returns the rows of an observability table in [start, end) and the bytes they
+	// take up, per bucket; buckets without rows are left out. The table must be one of
+	// models.StorageCostTables.
+	TableUsage(ctx context.Context, table string, start, end time.Time, bucket time.Duration) ([]*models.StorageUsage, error)
+	// RideRequests returns the ride requests made in [start, end) per bucket and mode, counted
+	// from the events of eventType changing a trip from status "none"
+	RideRequests(ctx context.Context, eventType string, start, end time.Time, bucket time.Duration) ([]*models.StorageRequests, error)
+}
+
+// APIUsageRepository defines the interface for the rolled-up usage of API keys
+type APIUsageRepository interface {
+	// Upsert adds the requests of the rollups to the ones stored for the same key and interval
+	Upsert(ctx context.Context, rollups []*models.APIUsageRollup) error
+	// List returns the matching rollups, oldest first, then by key
+	List(ctx context.Context, filter models.APIUsageFilter) ([]*models.APIUsageRollup, error)
+	// CountRequests returns the requests made with a key in [from, to) that were not rejected for
+	// its quota
+	CountRequests(ctx context.Context, keyID string, from, to time.Time) (int64, error)
+	// DeleteBefore removes the rollups of the intervals starting before the cutoff and returns how
+	// many were removed
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// DashboardRepository defines the interface for the precomputed dashboard summaries and the
+// source data they are computed from
+type DashboardRepository interface {
+	// GetTripTimings returns the lifecycle timestamps of the trips requested at or after since
+	GetTripTimings(ctx context.Context, since time.Time) ([]*models.TripTiming, error)
+	// GetDriverPositions returns the status and location of every driver with a known location
+	GetDriverPositions(ctx context.Context) ([]*models.DriverPosition, error)
+	// ReplaceHourlyTrips replaces the hourly trip summaries from since onwards with rows and
+	// records the refresh, in one transaction
+	ReplaceHourlyTrips(ctx context.Context, since time.Time, rows []*models.HourlyTripSummary, refresh *models.DashboardRefresh) error
+	// ReplaceMatchingTimes replaces the matching time summaries from since onwards with rows and
+	// records the refresh, in one transaction
+	ReplaceMatchingTimes(ctx context.Context, since time.Time, rows []*models.MatchingTimeSummary, refresh *models.DashboardRefresh) error
+	// ReplaceZoneSupply replaces every zone supply summary with rows and records the refresh, in
+	// one transaction
+	ReplaceZoneSupply(ctx context.Context, rows []*models.ZoneSupplySummary, refresh *models.DashboardRefresh) error
+	// ListHourlyTrips returns the hourly trip summaries from since onwards, oldest first
+	ListHourlyTrips(ctx context.Context, since time.Time) ([]*models.HourlyTripSummary, error)
+	// ListMatchingTimes returns the matching time summaries from since onwards, oldest first
+	ListMatchingTimes(ctx context.Context, since time.Time) ([]*models.MatchingTimeSummary, error)
+	// ListZoneSupply returns the zone supply summaries, most available drivers first
+	ListZoneSupply(ctx context.Context) ([]*models.ZoneSupplySummary, error)
+	// GetRefresh returns the last refresh of a view, or a NotFoundError if it was never refreshed
+	GetRefresh(ctx context.Context, view string) (*models.DashboardRefresh, error)
 }
 
 // ObservabilityRepository defines the interface for observability data operations
@@ -62,12 +450,24 @@ type ObservabilityRepository interface {
 	GetActorInstance(ctx context.Context, id string) (*models.ActorInstance, error)
 	UpdateActorInstance(ctx context.Context, instance *models.ActorInstance) error
 	ListActorInstances(ctx context.Context, actorType string, limit, offset int) ([]*models.ActorInstance, error)
+	ListActorInstancesByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.ActorInstance, error)
+	// GetActorLifecycleActivity aggregates the lifecycle event logs and processed messages of the
+	// actors alive during the window of lf by actor and bucket, the most recently spawned actors
+	// first and buckets in order. Actors without activity in the window have one row with a nil bucket.
+	GetActorLifecycleActivity(ctx context.Context, lf models.ActorLifecycleFilter) ([]*models.ActorLifecycleActivity, error)
 
 	// Actor Messages
 	CreateActorMessage(ctx context.Context, message *models.ActorMessage) error
 	GetActorMessage(ctx context.Context, id string) (*models.ActorMessage, error)
 	ListActorMessages(ctx context.Context, fromActor, toActor string, limit, offset int) ([]*models.ActorMessage, error)
 	GetMessagesByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.ActorMessage, error)
+	GetMessagesByTraceID(ctx context.Context, traceID string) ([]*models.ActorMessage, error)
+	ListActorMessagesByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.ActorMessage, error)
+	// FilterActorMessages retrieves the messages matching a filter of filter.ActorMessageFields, newest first
+	FilterActorMessages(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.ActorMessage, error)
+	// GetMessageStats aggregates the messages created within the last window by message type and
+	// receiving actor type, ordered by message type then actor type
+	GetMessageStats(ctx context.Context, window time.Duration) ([]*models.MessageStats, error)
 
 	// System Metrics
 	CreateSystemMetric(ctx context.Context, metric *models.SystemMetric) error
@@ -80,12 +480,17 @@ type ObservabilityRepository interface {
 	GetDistributedTrace(ctx context.Context, id string) (*models.DistributedTrace, error)
 	GetTracesByTraceID(ctx context.Context, traceID string) ([]*models.DistributedTrace, error)
 	ListDistributedTraces(ctx context.Context, operation string, limit, offset int) ([]*models.DistributedTrace, error)
+	// FilterDistributedTraces retrieves the spans matching a filter of filter.DistributedTraceFields, latest first
+	FilterDistributedTraces(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.DistributedTrace, error)
 
 	// Event Logs
 	CreateEventLog(ctx context.Context, log *models.EventLog) error
 	GetEventLog(ctx context.Context, id string) (*models.EventLog, error)
 	ListEventLogs(ctx context.Context, eventType, source string, limit, offset int) ([]*models.EventLog, error)
 	GetEventLogsByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.EventLog, error)
+	ListEventLogsByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.EventLog, error)
+	// FilterEventLogs retrieves the event logs matching a filter of filter.EventLogFields, newest first
+	FilterEventLogs(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.EventLog, error)
 }
 
 // TraditionalRepository defines the interface for traditional monitoring data operations
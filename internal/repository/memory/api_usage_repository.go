@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// APIUsageRepositoryImpl implements the APIUsageRepository interface in memory
+type APIUsageRepositoryImpl struct {
+	store *Store
+}
+
+// NewAPIUsageRepository creates a new instance of APIUsageRepositoryImpl
+func NewAPIUsageRepository(store *Store) repository.APIUsageRepository {
+	return &APIUsageRepositoryImpl{store: store}
+}
+
+// Upsert adds the requests of the rollups to the ones stored for the same key and interval
+func (r *APIUsageRepositoryImpl) Upsert(ctx context.Context, rollups []*models.APIUsageRollup) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, rollup := range rollups {
+		key := rollup.KeyID + "|" + rollup.BucketStart.UTC().Format(time.RFC3339)
+		if stored, exists := r.store.apiUsage[key]; exists {
+			stored.Add(rollup)
+			continue
+		}
+		copied := *rollup
+		r.store.apiUsage[key] = &copied
+	}
+	return nil
+}
+
+// List retrieves the matching rollups, oldest first, then by key
+func (r *APIUsageRepositoryImpl) List(ctx context.Context, filter models.APIUsageFilter) ([]*models.APIUsageRollup, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.apiUsage, func(rollup *models.APIUsageRollup) bool {
+		if filter.KeyID != "" && rollup.KeyID != filter.KeyID {
+			return false
+		}
+		if filter.TenantID != nil && rollup.TenantID != *filter.TenantID {
+			return false
+		}
+		return !rollup.BucketStart.Before(filter.From) && rollup.BucketStart.Before(filter.To)
+	}, func(a, b *models.APIUsageRollup) bool {
+		if !a.BucketStart.Equal(b.BucketStart) {
+			return a.BucketStart.Before(b.BucketStart)
+		}
+		return a.KeyID < b.KeyID
+	}, noLimit, 0), nil
+}
+
+// CountRequests returns the requests made with a key in [from, to) that were not rejected for
+// its quota
+func (r *APIUsageRepositoryImpl) CountRequests(ctx context.Context, keyID string, from, to time.Time) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, rollup := range r.store.apiUsage {
+		if rollup.KeyID == keyID && !rollup.BucketStart.Before(from) && rollup.BucketStart.Before(to) {
+			count += rollup.Requests - rollup.QuotaRejections
+		}
+	}
+	return count, nil
+}
+
+// DeleteBefore removes the rollups of the intervals starting before the cutoff
+func (r *APIUsageRepositoryImpl) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var deleted int64
+	for key, rollup := range r.store.apiUsage {
+		if rollup.BucketStart.Before(before) {
+			delete(r.store.apiUsage, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
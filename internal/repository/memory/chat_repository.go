@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// ChatRepositoryImpl implements the ChatRepository interface in memory
+type ChatRepositoryImpl struct {
+	store *Store
+}
+
+// NewChatRepository creates a new instance of ChatRepositoryImpl
+func NewChatRepository(store *Store) repository.ChatRepository {
+	return &ChatRepositoryImpl{store: store}
+}
+
+// Create creates a new chat message
+func (r *ChatRepositoryImpl) Create(ctx context.Context, msg *models.TripChatMessage) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.chatMessages[msg.ID.String()]; exists {
+		return fmt.Errorf("failed to create chat message: %w", models.ErrDuplicateEntry)
+	}
+	if _, ok := r.store.trips[msg.TripID.String()]; !ok {
+		return &models.NotFoundError{
+			Resource: "trip",
+			ID:       msg.TripID.String(),
+		}
+	}
+
+	copied := *msg
+	r.store.chatMessages[msg.ID.String()] = &copied
+	return nil
+}
+
+// ListByTrip retrieves a trip's chat messages, oldest first
+func (r *ChatRepositoryImpl) ListByTrip(ctx context.Context, tripID string, limit, offset int) ([]*models.TripChatMessage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.chatMessages, func(m *models.TripChatMessage) bool {
+		return m.TripID.String() == tripID
+	}, func(a, b *models.TripChatMessage) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, limit, offset), nil
+}
+
+// MarkRead marks the unread messages of a trip sent to reader as read
+func (r *ChatRepositoryImpl) MarkRead(ctx context.Context, tripID string, reader models.ChatSenderRole, at time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	marked := 0
+	for _, m := range r.store.chatMessages {
+		if m.TripID.String() == tripID && m.SenderRole == reader.Recipient() && m.ReadAt == nil {
+			readAt := at
+			m.ReadAt = &readAt
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+// CountUnread counts the unread messages of a trip per recipient
+func (r *ChatRepositoryImpl) CountUnread(ctx context.Context, tripID string) (*models.TripChatUnreadCounts, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := &models.TripChatUnreadCounts{}
+	for _, m := range r.store.chatMessages {
+		if m.TripID.String() != tripID || m.ReadAt != nil {
+			continue
+		}
+		if m.SenderRole == models.ChatSenderDriver {
+			counts.Passenger++
+		} else {
+			counts.Driver++
+		}
+	}
+	return counts, nil
+}
+
+// DeleteForTripsEndedBefore deletes the messages of the trips completed or cancelled before cutoff,
+// except trips frozen by a safety incident awaiting review
+func (r *ChatRepositoryImpl) DeleteForTripsEndedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var deleted int64
+	for id, m := range r.store.chatMessages {
+		trip, ok := r.store.trips[m.TripID.String()]
+		if !ok {
+			continue
+		}
+		ended := trip.CompletedAt
+		if ended == nil {
+			ended = trip.CancelledAt
+		}
+		if ended != nil && ended.Before(cutoff) && !r.store.tripFrozen(trip.ID.String()) {
+			delete(r.store.chatMessages, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// CorporateAccountRepositoryImpl implements the CorporateAccountRepository interface in memory
+type CorporateAccountRepositoryImpl struct {
+	store *Store
+}
+
+// NewCorporateAccountRepository creates a new instance of CorporateAccountRepositoryImpl
+func NewCorporateAccountRepository(store *Store) repository.CorporateAccountRepository {
+	return &CorporateAccountRepositoryImpl{store: store}
+}
+
+// Create creates a new corporate account
+func (r *CorporateAccountRepositoryImpl) Create(ctx context.Context, account *models.CorporateAccount) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.corporateAccounts[account.ID.String()]; exists {
+		return fmt.Errorf("failed to create corporate account: %w", models.ErrDuplicateEntry)
+	}
+
+	copied := *account
+	r.store.corporateAccounts[account.ID.String()] = &copied
+	return nil
+}
+
+// GetByID retrieves a corporate account by ID
+func (r *CorporateAccountRepositoryImpl) GetByID(ctx context.Context, id string) (*models.CorporateAccount, error) {
+	return getByID(r.store, r.store.corporateAccounts, "corporate account", id)
+}
+
+// Update updates the name and spending limit of a corporate account
+func (r *CorporateAccountRepositoryImpl) Update(ctx context.Context, account *models.CorporateAccount) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.corporateAccounts[account.ID.String()]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "corporate account",
+			ID:       account.ID.String(),
+		}
+	}
+
+	existing.Name = account.Name
+	existing.MonthlySpendingLimit = account.MonthlySpendingLimit
+	existing.UpdatedAt = account.UpdatedAt
+	return nil
+}
+
+// SetPassengerAccount links a passenger to a corporate account, or unlinks it
+func (r *CorporateAccountRepositoryImpl) SetPassengerAccount(ctx context.Context, passengerID string, accountID *uuid.UUID) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	passenger, ok := r.store.passengers[passengerID]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "passenger",
+			ID:       passengerID,
+		}
+	}
+	if accountID != nil {
+		if _, ok := r.store.corporateAccounts[accountID.String()]; !ok {
+			return &models.NotFoundError{
+				Resource: "corporate account",
+				ID:       accountID.String(),
+			}
+		}
+		id := *accountID
+		accountID = &id
+	}
+
+	passenger.CorporateAccountID = accountID
+	passenger.UpdatedAt = time.Now()
+	return nil
+}
+
+// ChargeTrip bills a trip to a corporate account within its spending limit. Holding the store
+// lock makes the check and the insert atomic.
+func (r *CorporateAccountRepositoryImpl) ChargeTrip(ctx context.Context, charge *models.CorporateTripCharge, limit *float64, periodStart, periodEnd time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	accountID := charge.AccountID.String()
+	if _, ok := r.store.corporateAccounts[accountID]; !ok {
+		return &models.NotFoundError{
+			Resource: "corporate account",
+			ID:       accountID,
+		}
+	}
+	if _, ok := r.store.trips[charge.TripID.String()]; !ok {
+		return &models.NotFoundError{
+			Resource: "trip",
+			ID:       charge.TripID.String(),
+		}
+	}
+	if _, exists := r.store.corporateCharges[charge.TripID.String()]; exists {
+		return fmt.Errorf("trip %s already charged: %w", charge.TripID, models.ErrDuplicateEntry)
+	}
+
+	if limit != nil {
+		var spend float64
+		for _, existing := range r.chargesLocked(accountID, periodStart, periodEnd) {
+			spend += existing.Amount()
+		}
+		if spend+charge.EstimatedFare > *limit {
+			return models.ErrCorporateSpendingLimitExceeded
+		}
+	}
+
+	copied := *charge
+	copied.TripStatus, copied.FareAmount = "", nil
+	r.store.corporateCharges[charge.TripID.String()] = &copied
+	return nil
+}
+
+// ListCharges retrieves the charges of a corporate account created in [start, end)
+func (r *CorporateAccountRepositoryImpl) ListCharges(ctx context.Context, accountID string, start, end time.Time) ([]*models.CorporateTripCharge, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return r.chargesLocked(accountID, start, end), nil
+}
+
+// List retrieves every corporate account, oldest first
+func (r *CorporateAccountRepositoryImpl) List(ctx context.Context) ([]*models.CorporateAccount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.corporateAccounts, nil, func(a, b *models.CorporateAccount) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, noLimit, 0), nil
+}
+
+// chargesLocked returns copies of the account's charges created in [start, end) joined with
+// their trip, oldest first; callers must hold the lock
+func (r *CorporateAccountRepositoryImpl) chargesLocked(accountID string, start, end time.Time) []*models.CorporateTripCharge {
+	var charges []*models.CorporateTripCharge
+	for tripID, charge := range r.store.corporateCharges {
+		if charge.AccountID.String() != accountID || charge.CreatedAt.Before(start) || !charge.CreatedAt.Before(end) {
+			continue
+		}
+		trip, ok := r.store.trips[tripID]
+		if !ok {
+			continue
+		}
+		copied := *charge
+		copied.TripStatus = trip.Status
+		if trip.FareAmount != nil {
+			fare := *trip.FareAmount
+			copied.FareAmount = &fare
+		}
+		charges = append(charges, &copied)
+	}
+
+	sort.Slice(charges, func(i, j int) bool {
+		if !charges[i].CreatedAt.Equal(charges[j].CreatedAt) {
+			return charges[i].CreatedAt.Before(charges[j].CreatedAt)
+		}
+		return charges[i].TripID.String() < charges[j].TripID.String()
+	})
+	return charges
+}
@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// DashboardRepositoryImpl implements the DashboardRepository interface in memory
+type DashboardRepositoryImpl struct {
+	store *Store
+}
+
+// NewDashboardRepository creates a new instance of DashboardRepositoryImpl
+func NewDashboardRepository(store *Store) repository.DashboardRepository {
+	return &DashboardRepositoryImpl{store: store}
+}
+
+// hourKey keys the hourly summary tables
+func hourKey(hour time.Time) string {
+	return hour.UTC().Format(time.RFC3339)
+}
+
+// GetTripTimings retrieves the lifecycle timestamps of the trips requested at or after since
+func (r *DashboardRepositoryImpl) GetTripTimings(ctx context.Context, since time.Time) ([]*models.TripTiming, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var timings []*models.TripTiming
+	for _, t := range r.store.trips {
+		if t.RequestedAt.Before(since) {
+			continue
+		}
+		timings = append(timings, &models.TripTiming{
+			RequestedAt: t.RequestedAt,
+			MatchedAt:   t.MatchedAt,
+			CompletedAt: t.CompletedAt,
+			CancelledAt: t.CancelledAt,
+		})
+	}
+	return timings, nil
+}
+
+// GetDriverPositions retrieves the status and location of every driver with a known location
+func (r *DashboardRepositoryImpl) GetDriverPositions(ctx context.Context) ([]*models.DriverPosition, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var positions []*models.DriverPosition
+	for _, d := range r.store.drivers {
+		lat, lng, ok := d.GetLocation()
+		if !ok {
+			continue
+		}
+		positions = append(positions, &models.DriverPosition{Status: d.Status, Latitude: lat, Longitude: lng})
+	}
+	return positions, nil
+}
+
+// ReplaceHourlyTrips replaces the hourly trip summaries from since onwards and records the refresh
+func (r *DashboardRepositoryImpl) ReplaceHourlyTrips(ctx context.Context, since time.Time, rows []*models.HourlyTripSummary, refresh *models.DashboardRefresh) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for key, row := range r.store.dashboardHourlyTrips {
+		if !row.Hour.Before(since) {
+			delete(r.store.dashboardHourlyTrips, key)
+		}
+	}
+	for _, row := range rows {
+		copied := *row
+		r.store.dashboardHourlyTrips[hourKey(row.Hour)] = &copied
+	}
+	r.recordRefresh(refresh)
+	return nil
+}
+
+// ReplaceMatchingTimes replaces the matching time summaries from since onwards and records the refresh
+func (r *DashboardRepositoryImpl) ReplaceMatchingTimes(ctx context.Context, since time.Time, rows []*models.MatchingTimeSummary, refresh *models.DashboardRefresh) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for key, row := range r.store.dashboardMatchingTimes {
+		if !row.Hour.Before(since) {
+			delete(r.store.dashboardMatchingTimes, key)
+		}
+	}
+	for _, row := range rows {
+		copied := *row
+		r.store.dashboardMatchingTimes[hourKey(row.Hour)] = &copied
+	}
+	r.recordRefresh(refresh)
+	return nil
+}
+
+// ReplaceZoneSupply replaces every zone supply summary and records the refresh
+func (r *DashboardRepositoryImpl) ReplaceZoneSupply(ctx context.Context, rows []*models.ZoneSupplySummary, refresh *models.DashboardRefresh) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.dashboardZoneSupply = make(map[string]*models.ZoneSupplySummary, len(rows))
+	for _, row := range rows {
+		copied := *row
+		r.store.dashboardZoneSupply[row.Zone] = &copied
+	}
+	r.recordRefresh(refresh)
+	return nil
+}
+
+// ListHourlyTrips retrieves the hourly trip summaries from since onwards, oldest first
+func (r *DashboardRepositoryImpl) ListHourlyTrips(ctx context.Context, since time.Time) ([]*models.HourlyTripSummary, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.dashboardHourlyTrips, func(row *models.HourlyTripSummary) bool {
+		return !row.Hour.Before(since)
+	}, func(a, b *models.HourlyTripSummary) bool {
+		return a.Hour.Before(b.Hour)
+	}, noLimit, 0), nil
+}
+
+// ListMatchingTimes retrieves the matching time summaries from since onwards, oldest first
+func (r *DashboardRepositoryImpl) ListMatchingTimes(ctx context.Context, since time.Time) ([]*models.MatchingTimeSummary, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.dashboardMatchingTimes, func(row *models.MatchingTimeSummary) bool {
+		return !row.Hour.Before(since)
+	}, func(a, b *models.MatchingTimeSummary) bool {
+		return a.Hour.Before(b.Hour)
+	}, noLimit, 0), nil
+}
+
+// ListZoneSupply retrieves the zone supply summaries, most available drivers first
+func (r *DashboardRepositoryImpl) ListZoneSupply(ctx context.Context) ([]*models.ZoneSupplySummary, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.dashboardZoneSupply, nil, func(a, b *models.ZoneSupplySummary) bool {
+		if a.AvailableDrivers != b.AvailableDrivers {
+			return a.AvailableDrivers > b.AvailableDrivers
+		}
+		if a.BusyDrivers != b.BusyDrivers {
+			return a.BusyDrivers > b.BusyDrivers
+		}
+		return a.Zone < b.Zone
+	}, noLimit, 0), nil
+}
+
+// GetRefresh retrieves the last refresh of a dashboard view
+func (r *DashboardRepositoryImpl) GetRefresh(ctx context.Context, view string) (*models.DashboardRefresh, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	refresh, ok := r.store.dashboardRefreshes[view]
+	if !ok {
+		return nil, &models.NotFoundError{Resource: "dashboard refresh", ID: view}
+	}
+	copied := *refresh
+	return &copied, nil
+}
+
+// recordRefresh stores the last refresh of a view; callers must hold the write lock
+func (r *DashboardRepositoryImpl) recordRefresh(refresh *models.DashboardRefresh) {
+	copied := *refresh
+	r.store.dashboardRefreshes[refresh.View] = &copied
+}
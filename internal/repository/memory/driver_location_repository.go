@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// DriverLocationRepositoryImpl implements the DriverLocationRepository interface in memory
+type DriverLocationRepositoryImpl struct {
+	store *Store
+}
+
+// NewDriverLocationRepository creates a new instance of DriverLocationRepositoryImpl
+func NewDriverLocationRepository(store *Store) repository.DriverLocationRepository {
+	return &DriverLocationRepositoryImpl{store: store}
+}
+
+// Record stores a location ping
+func (r *DriverLocationRepositoryImpl) Record(ctx context.Context, ping *models.DriverLocationPing) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	copied := *ping
+	r.store.driverLocations = append(r.store.driverLocations, &copied)
+	return nil
+}
+
+// GetTrail retrieves up to limit pings of a driver recorded in [from, to), oldest first
+func (r *DriverLocationRepositoryImpl) GetTrail(ctx context.Context, driverID string, from, to time.Time, limit int) ([]*models.DriverLocationPing, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var pings []*models.DriverLocationPing
+	for _, ping := range r.store.driverLocations {
+		if ping.DriverID.String() != driverID || ping.RecordedAt.Before(from) || !ping.RecordedAt.Before(to) {
+			continue
+		}
+		copied := *ping
+		pings = append(pings, &copied)
+	}
+
+	sort.SliceStable(pings, func(i, j int) bool { return pings[i].RecordedAt.Before(pings[j].RecordedAt) })
+	if len(pings) > limit {
+		pings = pings[:limit]
+	}
+	return pings, nil
+}
+
+// Downsample replaces the pings recorded before the cutoff at a finer resolution with the last
+// ping of their driver in every resolution interval, counting the pings it replaced
+func (r *DriverLocationRepositoryImpl) Downsample(ctx context.Context, before time.Time, resolution time.Duration) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	type bucket struct {
+		driverID uuid.UUID
+		start    time.Time
+	}
+	seconds := int(resolution.Seconds())
+	kept := make(map[bucket]*models.DriverLocationPing)
+	var pings []*models.DriverLocationPing
+	var removed int64
+	for _, ping := range r.store.driverLocations {
+		if !ping.RecordedAt.Before(before) || ping.ResolutionSeconds >= seconds {
+			pings = append(pings, ping)
+			continue
+		}
+
+		key := bucket{driverID: ping.DriverID, start: ping.RecordedAt.Truncate(resolution)}
+		last, ok := kept[key]
+		if !ok {
+			copied := *ping
+			copied.ResolutionSeconds = seconds
+			kept[key] = &copied
+			pings = append(pings, &copied)
+			continue
+		}
+
+		removed++
+		last.Samples += ping.Samples
+		if ping.RecordedAt.After(last.RecordedAt) {
+			last.Latitude, last.Longitude, last.RecordedAt = ping.Latitude, ping.Longitude, ping.RecordedAt
+		}
+	}
+
+	r.store.driverLocations = pings
+	return removed, nil
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// DriverOnboardingRepositoryImpl implements the DriverOnboardingRepository interface in memory
+type DriverOnboardingRepositoryImpl struct {
+	store *Store
+}
+
+// NewDriverOnboardingRepository creates a new instance of DriverOnboardingRepositoryImpl
+func NewDriverOnboardingRepository(store *Store) repository.DriverOnboardingRepository {
+	return &DriverOnboardingRepositoryImpl{store: store}
+}
+
+// Create creates the onboarding of a driver
+func (r *DriverOnboardingRepositoryImpl) Create(ctx context.Context, onboarding *models.DriverOnboarding) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.insert(onboarding)
+}
+
+// CreateWithDriver creates a driver and its onboarding, storing neither if either fails
+func (r *DriverOnboardingRepositoryImpl) CreateWithDriver(ctx context.Context, driver *models.Driver, onboarding *models.DriverOnboarding) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.driverOnboardings[onboarding.DriverID.String()]; exists {
+		return fmt.Errorf("failed to create driver onboarding: %w", models.ErrDuplicateEntry)
+	}
+	if err := (&DriverRepositoryImpl{store: r.store}).insert(driver); err != nil {
+		return err
+	}
+	return r.insert(onboarding)
+}
+
+// insert stores the onboarding of a driver; the store must be locked
+func (r *DriverOnboardingRepositoryImpl) insert(onboarding *models.DriverOnboarding) error {
+	id := onboarding.DriverID.String()
+	if _, exists := r.store.driverOnboardings[id]; exists {
+		return fmt.Errorf("failed to create driver onboarding: %w", models.ErrDuplicateEntry)
+	}
+	if _, ok := r.store.drivers[id]; !ok {
+		return &models.NotFoundError{
+			Resource: "driver",
+			ID:       id,
+		}
+	}
+
+	copied := *onboarding
+	copied.Documents = append(models.OnboardingDocumentList(nil), onboarding.Documents...)
+	r.store.driverOnboardings[id] = &copied
+	return nil
+}
+
+// GetByDriverID retrieves the onboarding of a driver
+func (r *DriverOnboardingRepositoryImpl) GetByDriverID(ctx context.Context, driverID string) (*models.DriverOnboarding, error) {
+	onboarding, err := getByID(r.store, r.store.driverOnboardings, "driver_onboarding", driverID)
+	if err != nil {
+		return nil, err
+	}
+	onboarding.Documents = append(models.OnboardingDocumentList(nil), onboarding.Documents...)
+	return onboarding, nil
+}
+
+// Update stores the onboarding's stage, documents and stage timestamps if its stored stage is still from
+func (r *DriverOnboardingRepositoryImpl) Update(ctx context.Context, onboarding *models.DriverOnboarding, from models.OnboardingStage) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.driverOnboardings[onboarding.DriverID.String()]
+	if !ok || existing.Stage != from {
+		return models.ErrOnboardingStageConflict
+	}
+
+	existing.Stage = onboarding.Stage
+	existing.Documents = append(models.OnboardingDocumentList(nil), onboarding.Documents...)
+	existing.DocumentsSubmittedAt = onboarding.DocumentsSubmittedAt
+	existing.VerifiedAt = onboarding.VerifiedAt
+	existing.VerifiedBy = onboarding.VerifiedBy
+	existing.TrainingCompletedAt = onboarding.TrainingCompletedAt
+	existing.ActivatedAt = onboarding.ActivatedAt
+	existing.ActivatedBy = onboarding.ActivatedBy
+	existing.UpdatedAt = onboarding.UpdatedAt
+	return nil
+}
+
+// CountByStage counts the drivers registered in [start, end) by their current stage
+func (r *DriverOnboardingRepositoryImpl) CountByStage(ctx context.Context, start, end time.Time) ([]models.OnboardingStageCount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byStage := make(map[models.OnboardingStage]int64)
+	for _, o := range r.store.driverOnboardings {
+		if !o.RegisteredAt.Before(start) && o.RegisteredAt.Before(end) {
+			byStage[o.Stage]++
+		}
+	}
+
+	var counts []models.OnboardingStageCount
+	for _, stage := range models.OnboardingStages {
+		if count, ok := byStage[stage]; ok {
+			counts = append(counts, models.OnboardingStageCount{Stage: stage, Count: count})
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,786 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// DriverRepositoryImpl implements the DriverRepository interface in memory
+type DriverRepositoryImpl struct {
+	store *Store
+}
+
+// NewDriverRepository creates a new instance of DriverRepositoryImpl
+func NewDriverRepository(store *Store) repository.DriverRepository {
+	return &DriverRepositoryImpl{store: store}
+}
+
+// Create creates a new driver
+func (r *DriverRepositoryImpl) Create(ctx context.Context, driver *models.Driver) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.insert(driver)
+}
+
+// insert stores a new driver; the store must be locked
+func (r *DriverRepositoryImpl) insert(driver *models.Driver) error {
+	if _, exists := r.store.drivers[driver.ID.String()]; exists {
+		return fmt.Errorf("failed to create driver: %w", models.ErrDuplicateEntry)
+	}
+	if err := r.checkUnique(driver); err != nil {
+		return err
+	}
+	if _, ok := r.store.users[driver.UserID.String()]; !ok {
+		return &models.ValidationError{
+			Field:   "user_id",
+			Message: "user does not exist",
+		}
+	}
+	if err := r.checkFleet(driver); err != nil {
+		return err
+	}
+
+	copied := *driver
+	copied.User = nil
+	copied.CurrentLatitude = copyFloat(driver.CurrentLatitude)
+	copied.CurrentLongitude = copyFloat(driver.CurrentLongitude)
+	r.store.drivers[driver.ID.String()] = &copied
+	r.store.recordDriverStatus(driver.ID.String(), driver.Status, time.Now())
+	return nil
+}
+
+// GetByID retrieves a driver by ID
+func (r *DriverRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Driver, error) {
+	return r.find(id, func(d *models.Driver) bool { return d.ID.String() == id })
+}
+
+// GetByUserID retrieves a driver by user ID
+func (r *DriverRepositoryImpl) GetByUserID(ctx context.Context, userID string) (*models.Driver, error) {
+	return r.find(userID, func(d *models.Driver) bool { return d.UserID.String() == userID })
+}
+
+// Update updates an existing driver
+func (r *DriverRepositoryImpl) Update(ctx context.Context, driver *models.Driver) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.drivers[driver.ID.String()]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "driver",
+			ID:       driver.ID.String(),
+		}
+	}
+
+	if err := r.checkUnique(driver); err != nil {
+		return err
+	}
+	if err := r.checkFleet(driver); err != nil {
+		return err
+	}
+
+	existing.LicenseNumber = driver.LicenseNumber
+	existing.VehicleType = driver.VehicleType
+	existing.VehiclePlate = driver.VehiclePlate
+	if existing.Status != driver.Status {
+		r.store.recordDriverStatus(existing.ID.String(), driver.Status, time.Now())
+	}
+	existing.Status = driver.Status
+	existing.CurrentLatitude = copyFloat(driver.CurrentLatitude)
+	existing.CurrentLongitude = copyFloat(driver.CurrentLongitude)
+	existing.Rating = driver.Rating
+	existing.TotalTrips = driver.TotalTrips
+	existing.FleetID = driver.FleetID
+	existing.PetFriendly = driver.PetFriendly
+	existing.WheelchairAccessible = driver.WheelchairAccessible
+	existing.QuietRides = driver.QuietRides
+	existing.ChildSeat = driver.ChildSeat
+	existing.UpdatedAt = driver.UpdatedAt
+	return nil
+}
+
+// Delete deletes a driver by ID
+func (r *DriverRepositoryImpl) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.drivers[id]; !ok {
+		return &models.NotFoundError{
+			Resource: "driver",
+			ID:       id,
+		}
+	}
+
+	delete(r.store.drivers, id)
+
+	// The status history cascades with the driver like the foreign key in PostgreSQL
+	history := r.store.driverStatusHistory[:0]
+	for _, change := range r.store.driverStatusHistory {
+		if change.driverID != id {
+			history = append(history, change)
+		}
+	}
+	r.store.driverStatusHistory = history
+
+	destinations := r.store.driverDestinations[:0]
+	for _, d := range r.store.driverDestinations {
+		if d.DriverID.String() != id {
+			destinations = append(destinations, d)
+		}
+	}
+	r.store.driverDestinations = destinations
+
+	rests := r.store.driverRests[:0]
+	for _, rest := range r.store.driverRests {
+		if rest.DriverID.String() != id {
+			rests = append(rests, rest)
+		}
+	}
+	r.store.driverRests = rests
+
+	for tripID, completion := range r.store.tripCompletions {
+		if completion.DriverID.String() == id {
+			delete(r.store.tripCompletions, tripID)
+		}
+	}
+	for tripID, wait := range r.store.pickupWaits {
+		if wait.DriverID.String() == id {
+			delete(r.store.pickupWaits, tripID)
+		}
+	}
+	// Re-matches the driver cancelled cascade and the ones the driver was matched in keep no driver
+	for tripID, rematches := range r.store.tripRematches {
+		kept := rematches[:0]
+		for _, rematch := range rematches {
+			if rematch.CancelledDriverID.String() == id {
+				continue
+			}
+			if rematch.MatchedDriverID != nil && rematch.MatchedDriverID.String() == id {
+				rematch.MatchedDriverID = nil
+			}
+			kept = append(kept, rematch)
+		}
+		r.store.tripRematches[tripID] = kept
+	}
+	delete(r.store.driverOnboardings, id)
+	return nil
+}
+
+// GetOnlineDrivers retrieves all online drivers, best rated first
+func (r *DriverRepositoryImpl) GetOnlineDrivers(ctx context.Context) ([]*models.Driver, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.drivers, func(d *models.Driver) bool {
+		return d.Status == models.DriverStatusOnline
+	}, byRating, noLimit, 0), nil
+}
+
+// GetDriversInRadius retrieves online drivers within radiusKm of the given point, nearest first
+func (r *DriverRepositoryImpl) GetDriversInRadius(ctx context.Context, lat, lng, radiusKm float64) ([]*models.Driver, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	center := models.Location{Latitude: lat, Longitude: lng}
+	distanceTo := func(d *models.Driver) float64 {
+		return center.DistanceTo(models.Location{Latitude: *d.CurrentLatitude, Longitude: *d.CurrentLongitude})
+	}
+
+	return selectRows(r.store.drivers, func(d *models.Driver) bool {
+		return d.Status == models.DriverStatusOnline && d.HasLocation() && distanceTo(d) <= radiusKm
+	}, func(a, b *models.Driver) bool {
+		if da, db := distanceTo(a), distanceTo(b); da != db {
+			return da < db
+		}
+		return a.Rating > b.Rating
+	}, noLimit, 0), nil
+}
+
+// UpdateLocation updates a driver's current location
+func (r *DriverRepositoryImpl) UpdateLocation(ctx context.Context, driverID string, lat, lng float64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	driver, ok := r.store.drivers[driverID]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "driver",
+			ID:       driverID,
+		}
+	}
+
+	driver.CurrentLatitude = &lat
+	driver.CurrentLongitude = &lng
+	driver.UpdatedAt = time.Now()
+	return nil
+}
+
+// UpdateLocations writes the locations of many drivers at once
+func (r *DriverRepositoryImpl) UpdateLocations(ctx context.Context, updates []*models.DriverLocationUpdate) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var updated int64
+	now := time.Now()
+	for _, update := range updates {
+		driver, ok := r.store.drivers[update.DriverID.String()]
+		if !ok {
+			continue
+		}
+		lat, lng := update.Latitude, update.Longitude
+		driver.CurrentLatitude = &lat
+		driver.CurrentLongitude = &lng
+		driver.UpdatedAt = now
+		updated++
+	}
+	return updated, nil
+}
+
+// UpdateStatus updates a driver's status
+func (r *DriverRepositoryImpl) UpdateStatus(ctx context.Context, driverID string, status models.DriverStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	driver, ok := r.store.drivers[driverID]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "driver",
+			ID:       driverID,
+		}
+	}
+
+	if driver.Status != status {
+		r.store.recordDriverStatus(driverID, status, time.Now())
+	}
+	driver.Status = status
+	driver.UpdatedAt = time.Now()
+	return nil
+}
+
+// Reserve sets an online driver busy
+func (r *DriverRepositoryImpl) Reserve(ctx context.Context, driverID string) error {
+	updated, err := r.updateStatusFrom(driverID, models.DriverStatusOnline, models.DriverStatusBusy)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return models.ErrDriverNotOnline
+	}
+	return nil
+}
+
+// Release sets a reserved driver back online; a driver no longer busy is left as is
+func (r *DriverRepositoryImpl) Release(ctx context.Context, driverID string) error {
+	_, err := r.updateStatusFrom(driverID, models.DriverStatusBusy, models.DriverStatusOnline)
+	return err
+}
+
+// updateStatusFrom changes the driver's status to status if it is still from, reporting whether
+// it was
+func (r *DriverRepositoryImpl) updateStatusFrom(driverID string, from, status models.DriverStatus) (bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	driver, ok := r.store.drivers[driverID]
+	if !ok {
+		return false, &models.NotFoundError{
+			Resource: "driver",
+			ID:       driverID,
+		}
+	}
+	if driver.Status != from {
+		return false, nil
+	}
+
+	now := time.Now()
+	r.store.recordDriverStatus(driverID, status, now)
+	driver.Status = status
+	driver.UpdatedAt = now
+	return true, nil
+}
+
+// List retrieves a list of drivers with pagination
+func (r *DriverRepositoryImpl) List(ctx context.Context, limit, offset int) ([]*models.Driver, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.drivers, nil, func(a, b *models.Driver) bool {
+		return newestFirst(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}, limit, offset), nil
+}
+
+// ListByFleet retrieves every driver of a fleet, oldest first
+func (r *DriverRepositoryImpl) ListByFleet(ctx context.Context, fleetID string) ([]*models.Driver, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.drivers, func(d *models.Driver) bool {
+		return d.FleetID != nil && d.FleetID.String() == fleetID
+	}, func(a, b *models.Driver) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, noLimit, 0), nil
+}
+
+// GetDriverStats aggregates trips and online time per driver over the filter period
+func (r *DriverRepositoryImpl) GetDriverStats(ctx context.Context, filter models.DriverStatsFilter) ([]*models.DriverStats, int64, error) {
+	if !filter.SortBy.IsValid() {
+		return nil, 0, &models.ValidationError{
+			Field:   "sort_by",
+			Message: fmt.Sprintf("unsupported sort column: %s", filter.SortBy),
+		}
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stats := make(map[string]*models.DriverStats, len(r.store.drivers))
+	for id, d := range r.store.drivers {
+		s := &models.DriverStats{
+			DriverID:      d.ID,
+			UserID:        d.UserID,
+			VehiclePlate:  d.VehiclePlate,
+			Status:        d.Status,
+			AverageRating: d.Rating,
+		}
+		if u, ok := r.store.users[d.UserID.String()]; ok {
+			s.Name = u.Name
+		}
+		stats[id] = s
+	}
+
+	for _, t := range r.store.trips {
+		if t.DriverID == nil || t.RequestedAt.Before(filter.From) || !t.RequestedAt.Before(filter.To) {
+			continue
+		}
+		s, ok := stats[t.DriverID.String()]
+		if !ok {
+			continue
+		}
+
+		s.TripsAssigned++
+		switch t.Status {
+		case models.TripStatusAccepted, models.TripStatusDriverArrived, models.TripStatusInProgress, models.TripStatusCompleted:
+			s.TripsAccepted++
+		default:
+			if t.AcceptedAt != nil {
+				s.TripsAccepted++
+			}
+		}
+		switch t.Status {
+		case models.TripStatusCompleted:
+			s.TripsCompleted++
+			if t.FareAmount != nil {
+				s.Earnings += *t.FareAmount
+			}
+		case models.TripStatusCancelled:
+			s.TripsCancelled++
+		}
+	}
+
+	r.addOnlineHours(stats, filter.From, filter.To)
+
+	for _, s := range stats {
+		if s.TripsAssigned > 0 {
+			s.AcceptanceRate = float64(s.TripsAccepted) / float64(s.TripsAssigned)
+		}
+	}
+
+	value := driverStatsValue(filter.SortBy)
+	return selectRows(stats, nil, func(a, b *models.DriverStats) bool {
+		if va, vb := value(a), value(b); va != vb {
+			if filter.Descending {
+				return va > vb
+			}
+			return va < vb
+		}
+		return a.DriverID.String() < b.DriverID.String()
+	}, filter.Limit, filter.Offset), int64(len(r.store.drivers)), nil
+}
+
+// GetTripStats retrieves the trip stats rollup of a driver, summing the earnings of the days in
+// the rolling window. Drivers who never completed a trip have zero stats.
+func (r *DriverRepositoryImpl) GetTripStats(ctx context.Context, driverID string) (*models.DriverTripStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	driver, ok := r.store.drivers[driverID]
+	if !ok {
+		return nil, &models.NotFoundError{
+			Resource: "driver",
+			ID:       driverID,
+		}
+	}
+
+	stats := &models.DriverTripStats{DriverID: driver.ID}
+	if stored, ok := r.store.driverTripStats[driverID]; ok {
+		copied := *stored
+		stats = &copied
+	}
+	stats.AverageRating = driver.Rating
+
+	windowStart := models.DriverEarningsWindowStart(time.Now())
+	for key, earnings := range r.store.driverDailyEarnings {
+		id, day, _ := strings.Cut(key, "|")
+		if id == driverID && day >= windowStart {
+			stats.RecentEarnings += earnings
+		}
+	}
+	return stats, nil
+}
+
+// addOnlineHours adds the time each driver spent online or busy within [from, to),
+// treating each status as lasting until the driver's next change or now
+func (r *DriverRepositoryImpl) addOnlineHours(stats map[string]*models.DriverStats, from, to time.Time) {
+	history := make(map[string][]driverStatusChange)
+	for _, change := range r.store.driverStatusHistory {
+		history[change.driverID] = append(history[change.driverID], change)
+	}
+
+	now := time.Now()
+	for driverID, changes := range history {
+		s, ok := stats[driverID]
+		if !ok {
+			continue
+		}
+
+		sort.SliceStable(changes, func(i, j int) bool { return changes[i].changedAt.Before(changes[j].changedAt) })
+		for i, change := range changes {
+			if change.status != models.DriverStatusOnline && change.status != models.DriverStatusBusy {
+				continue
+			}
+
+			start, end := change.changedAt, now
+			if i+1 < len(changes) {
+				end = changes[i+1].changedAt
+			}
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if end.After(start) {
+				s.OnlineHours += end.Sub(start).Hours()
+			}
+		}
+	}
+}
+
+// driverStatsValue returns the value a sort column orders driver statistics by
+func driverStatsValue(sortBy models.DriverStatsSort) func(*models.DriverStats) float64 {
+	switch sortBy {
+	case models.DriverStatsSortAcceptanceRate:
+		return func(s *models.DriverStats) float64 { return s.AcceptanceRate }
+	case models.DriverStatsSortAverageRating:
+		return func(s *models.DriverStats) float64 { return s.AverageRating }
+	case models.DriverStatsSortEarnings:
+		return func(s *models.DriverStats) float64 { return s.Earnings }
+	case models.DriverStatsSortOnlineHours:
+		return func(s *models.DriverStats) float64 { return s.OnlineHours }
+	default:
+		return func(s *models.DriverStats) float64 { return float64(s.TripsCompleted) }
+	}
+}
+
+// SetDestination turns on destination mode, replacing any destination that is still active
+func (r *DriverRepositoryImpl) SetDestination(ctx context.Context, destination *models.DriverDestination) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.drivers[destination.DriverID.String()]; !ok {
+		return &models.NotFoundError{
+			Resource: "driver",
+			ID:       destination.DriverID.String(),
+		}
+	}
+
+	r.clearActiveDestinations(destination.DriverID.String(), destination.CreatedAt)
+
+	copied := *destination
+	copied.ClearedAt = nil
+	r.store.driverDestinations = append(r.store.driverDestinations, &copied)
+	return nil
+}
+
+// GetActiveDestination retrieves the driver's active destination
+func (r *DriverRepositoryImpl) GetActiveDestination(ctx context.Context, driverID string) (*models.DriverDestination, error) {
+	destinations, err := r.GetActiveDestinations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, ok := destinations[driverID]
+	if !ok {
+		return nil, &models.NotFoundError{
+			Resource: "driver destination",
+			ID:       driverID,
+		}
+	}
+	return destination, nil
+}
+
+// GetActiveDestinations retrieves every active destination keyed by driver ID
+func (r *DriverRepositoryImpl) GetActiveDestinations(ctx context.Context) (map[string]*models.DriverDestination, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	now := time.Now()
+	destinations := make(map[string]*models.DriverDestination)
+	for _, d := range r.store.driverDestinations {
+		if d.IsActive(now) {
+			copied := *d
+			destinations[d.DriverID.String()] = &copied
+		}
+	}
+	return destinations, nil
+}
+
+// ClearDestination turns off destination mode for the driver
+func (r *DriverRepositoryImpl) ClearDestination(ctx context.Context, driverID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if r.clearActiveDestinations(driverID, time.Now()) == 0 {
+		return &models.NotFoundError{
+			Resource: "driver destination",
+			ID:       driverID,
+		}
+	}
+	return nil
+}
+
+// CountDestinationsSince counts how often the driver turned on destination mode since the given time
+func (r *DriverRepositoryImpl) CountDestinationsSince(ctx context.Context, driverID string, since time.Time) (int, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	count := 0
+	for _, d := range r.store.driverDestinations {
+		if d.DriverID.String() == driverID && !d.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetDriverHours sums the driver's time online and driving within [from, to)
+func (r *DriverRepositoryImpl) GetDriverHours(ctx context.Context, driverID string, from, to time.Time) (*models.DriverHours, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	driver, ok := r.store.drivers[driverID]
+	if !ok {
+		return nil, &models.NotFoundError{
+			Resource: "driver",
+			ID:       driverID,
+		}
+	}
+	return r.driverHours(driver, from, to), nil
+}
+
+// ListOnlineDriverHours sums the time online and driving within [from, to) of every driver
+// currently online
+func (r *DriverRepositoryImpl) ListOnlineDriverHours(ctx context.Context, from, to time.Time) ([]*models.DriverHours, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	online := selectRows(r.store.drivers, func(d *models.Driver) bool {
+		return d.Status == models.DriverStatusOnline
+	}, func(a, b *models.Driver) bool {
+		return a.ID.String() < b.ID.String()
+	}, noLimit, 0)
+
+	hours := make([]*models.DriverHours, 0, len(online))
+	for _, d := range online {
+		hours = append(hours, r.driverHours(d, from, to))
+	}
+	return hours, nil
+}
+
+// StartRest records the rest and sets the driver offline, failing unless the driver is online
+func (r *DriverRepositoryImpl) StartRest(ctx context.Context, rest *models.DriverRest) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	driverID := rest.DriverID.String()
+	driver, ok := r.store.drivers[driverID]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "driver",
+			ID:       driverID,
+		}
+	}
+	if driver.Status != models.DriverStatusOnline {
+		return models.ErrDriverNotOnline
+	}
+
+	driver.Status = models.DriverStatusOffline
+	driver.UpdatedAt = rest.StartedAt
+	r.store.recordDriverStatus(driverID, models.DriverStatusOffline, rest.StartedAt)
+
+	copied := *rest
+	r.store.driverRests = append(r.store.driverRests, &copied)
+	return nil
+}
+
+// GetLatestRest retrieves the driver's rest ending last, which may be over
+func (r *DriverRepositoryImpl) GetLatestRest(ctx context.Context, driverID string) (*models.DriverRest, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var latest *models.DriverRest
+	for _, rest := range r.store.driverRests {
+		if rest.DriverID.String() == driverID && (latest == nil || rest.RestUntil.After(latest.RestUntil)) {
+			latest = rest
+		}
+	}
+
+	if latest == nil {
+		return nil, &models.NotFoundError{
+			Resource: "driver rest",
+			ID:       driverID,
+		}
+	}
+	copied := *latest
+	return &copied, nil
+}
+
+// driverHours sums a driver's time online and driving within [from, to), treating each status as
+// lasting until the driver's next change; callers must hold the lock
+func (r *DriverRepositoryImpl) driverHours(driver *models.Driver, from, to time.Time) *models.DriverHours {
+	var changes []driverStatusChange
+	for _, change := range r.store.driverStatusHistory {
+		if change.driverID == driver.ID.String() && change.changedAt.Before(to) {
+			changes = append(changes, change)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].changedAt.Before(changes[j].changedAt) })
+
+	var online, driving time.Duration
+	for i, change := range changes {
+		start, end := change.changedAt, to
+		if i+1 < len(changes) {
+			end = changes[i+1].changedAt
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if !end.After(start) {
+			continue
+		}
+
+		switch change.status {
+		case models.DriverStatusOnline:
+			online += end.Sub(start)
+		case models.DriverStatusBusy:
+			online += end.Sub(start)
+			driving += end.Sub(start)
+		}
+	}
+
+	return &models.DriverHours{
+		DriverID:       driver.ID,
+		WindowStart:    from,
+		WindowEnd:      to,
+		OnlineSeconds:  int64(online.Seconds()),
+		DrivingSeconds: int64(driving.Seconds()),
+	}
+}
+
+// clearActiveDestinations marks the driver's active destinations cleared at the given time and
+// returns how many there were; callers must hold the write lock
+func (r *DriverRepositoryImpl) clearActiveDestinations(driverID string, at time.Time) int {
+	cleared := 0
+	for _, d := range r.store.driverDestinations {
+		if d.DriverID.String() == driverID && d.IsActive(at) {
+			clearedAt := at
+			d.ClearedAt = &clearedAt
+			cleared++
+		}
+	}
+	return cleared
+}
+
+// find returns a copy of the first driver matching match
+func (r *DriverRepositoryImpl) find(key string, match func(*models.Driver) bool) (*models.Driver, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, driver := range r.store.drivers {
+		if match(driver) {
+			copied := *driver
+			return &copied, nil
+		}
+	}
+
+	return nil, &models.NotFoundError{
+		Resource: "driver",
+		ID:       key,
+	}
+}
+
+// checkUnique enforces the unique license number and vehicle plate constraints
+func (r *DriverRepositoryImpl) checkUnique(driver *models.Driver) error {
+	for _, other := range r.store.drivers {
+		if other.ID == driver.ID {
+			continue
+		}
+		if other.LicenseNumber == driver.LicenseNumber {
+			return &models.ValidationError{
+				Field:   "license_number",
+				Message: "license number already exists",
+			}
+		}
+		if other.VehiclePlate == driver.VehiclePlate {
+			return &models.ValidationError{
+				Field:   "vehicle_plate",
+				Message: "vehicle plate already exists",
+			}
+		}
+	}
+	return nil
+}
+
+// checkFleet rejects drivers of unknown fleets like the fleet_id foreign key
+func (r *DriverRepositoryImpl) checkFleet(driver *models.Driver) error {
+	if driver.FleetID == nil {
+		return nil
+	}
+	if _, ok := r.store.fleets[driver.FleetID.String()]; !ok {
+		return &models.ValidationError{
+			Field:   "fleet_id",
+			Message: "fleet does not exist",
+		}
+	}
+	return nil
+}
+
+// byRating orders drivers by rating and then total trips, both descending
+func byRating(a, b *models.Driver) bool {
+	if a.Rating != b.Rating {
+		return a.Rating > b.Rating
+	}
+	if a.TotalTrips != b.TotalTrips {
+		return a.TotalTrips > b.TotalTrips
+	}
+	return a.ID.String() < b.ID.String()
+}
+
+// copyFloat returns a copy of f so stored rows don't alias caller memory
+func copyFloat(f *float64) *float64 {
+	if f == nil {
+		return nil
+	}
+	v := *f
+	return &v
+}
@@ -0,0 +1,112 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// ETAPredictionRepositoryImpl implements the ETAPredictionRepository interface in memory
+type ETAPredictionRepositoryImpl struct {
+	store *Store
+}
+
+// NewETAPredictionRepository creates a new instance of ETAPredictionRepositoryImpl
+func NewETAPredictionRepository(store *Store) repository.ETAPredictionRepository {
+	return &ETAPredictionRepositoryImpl{store: store}
+}
+
+// Create creates a new ETA prediction
+func (r *ETAPredictionRepositoryImpl) Create(ctx context.Context, prediction *models.ETAPrediction) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.etaPredictions[prediction.TripID.String()]; exists {
+		return fmt.Errorf("failed to create ETA prediction: %w", models.ErrDuplicateEntry)
+	}
+
+	copied := *prediction
+	r.store.etaPredictions[prediction.TripID.String()] = &copied
+	return nil
+}
+
+// GetByTrip retrieves the ETA prediction of a trip
+func (r *ETAPredictionRepositoryImpl) GetByTrip(ctx context.Context, tripID string) (*models.ETAPrediction, error) {
+	return getByID(r.store, r.store.etaPredictions, "ETA prediction", tripID)
+}
+
+// RecordArrival stores the arrival of a prediction that has none yet
+func (r *ETAPredictionRepositoryImpl) RecordArrival(ctx context.Context, prediction *models.ETAPrediction) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.etaPredictions[prediction.TripID.String()]
+	if !ok || existing.ArrivedAt != nil {
+		return &models.NotFoundError{
+			Resource: "pending ETA prediction",
+			ID:       prediction.TripID.String(),
+		}
+	}
+
+	existing.ArrivedAt = prediction.ArrivedAt
+	existing.ActualSeconds = prediction.ActualSeconds
+	existing.ErrorSeconds = prediction.ErrorSeconds
+	return nil
+}
+
+// GetAccuracy retrieves the accuracy of the ETAs predicted in [from, to) whose drivers arrived, by
+// pickup zone and hour of day, most samples first
+func (r *ETAPredictionRepositoryImpl) GetAccuracy(ctx context.Context, from, to time.Time) ([]*models.ETAAccuracy, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	type bucket struct {
+		zone string
+		hour int
+	}
+	buckets := make(map[bucket]*models.ETAAccuracy)
+	for _, p := range r.store.etaPredictions {
+		if p.ArrivedAt == nil || p.PredictedAt.Before(from) || !p.PredictedAt.Before(to) {
+			continue
+		}
+		key := bucket{zone: p.Zone, hour: p.Hour}
+		accuracy, ok := buckets[key]
+		if !ok {
+			accuracy = &models.ETAAccuracy{Zone: p.Zone, Hour: p.Hour}
+			buckets[key] = accuracy
+		}
+		// Sums until every prediction is counted
+		accuracy.Samples++
+		accuracy.MAESeconds += math.Abs(float64(*p.ErrorSeconds))
+		accuracy.BiasSeconds += float64(*p.ErrorSeconds)
+		accuracy.AvgPredictedSeconds += float64(p.PredictedSeconds)
+		accuracy.AvgActualSeconds += float64(*p.ActualSeconds)
+		accuracy.AvgDistanceKm += p.DistanceKm
+	}
+
+	result := make([]*models.ETAAccuracy, 0, len(buckets))
+	for _, accuracy := range buckets {
+		n := float64(accuracy.Samples)
+		accuracy.MAESeconds /= n
+		accuracy.BiasSeconds /= n
+		accuracy.AvgPredictedSeconds /= n
+		accuracy.AvgActualSeconds /= n
+		accuracy.AvgDistanceKm /= n
+		result = append(result, accuracy)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Samples != result[j].Samples {
+			return result[i].Samples > result[j].Samples
+		}
+		if result[i].Zone != result[j].Zone {
+			return result[i].Zone < result[j].Zone
+		}
+		return result[i].Hour < result[j].Hour
+	})
+	return result, nil
+}
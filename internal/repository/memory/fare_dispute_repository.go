@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// FareDisputeRepositoryImpl implements the FareDisputeRepository interface in memory
+type FareDisputeRepositoryImpl struct {
+	store *Store
+}
+
+// NewFareDisputeRepository creates a new instance of FareDisputeRepositoryImpl
+func NewFareDisputeRepository(store *Store) repository.FareDisputeRepository {
+	return &FareDisputeRepositoryImpl{store: store}
+}
+
+// CreateDispute creates a new fare dispute
+func (r *FareDisputeRepositoryImpl) CreateDispute(ctx context.Context, dispute *models.FareDispute) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.fareDisputes[dispute.ID.String()]; exists {
+		return fmt.Errorf("failed to create fare dispute: %w", models.ErrDuplicateEntry)
+	}
+	if _, ok := r.store.trips[dispute.TripID.String()]; !ok {
+		return &models.NotFoundError{
+			Resource: "trip",
+			ID:       dispute.TripID.String(),
+		}
+	}
+	if _, ok := r.store.passengers[dispute.PassengerID.String()]; !ok {
+		return &models.NotFoundError{
+			Resource: "passenger",
+			ID:       dispute.PassengerID.String(),
+		}
+	}
+	for _, other := range r.store.fareDisputes {
+		if other.TripID == dispute.TripID && other.Status.IsActive() && dispute.Status.IsActive() {
+			return models.ErrDisputeAlreadyOpen
+		}
+	}
+
+	copied := *dispute
+	r.store.fareDisputes[dispute.ID.String()] = &copied
+	return nil
+}
+
+// GetDispute retrieves a fare dispute by ID
+func (r *FareDisputeRepositoryImpl) GetDispute(ctx context.Context, id string) (*models.FareDispute, error) {
+	return getByID(r.store, r.store.fareDisputes, "fare_dispute", id)
+}
+
+// UpdateDispute stores the dispute's status and review fields if its stored status is still from
+func (r *FareDisputeRepositoryImpl) UpdateDispute(ctx context.Context, dispute *models.FareDispute, from models.FareDisputeStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return r.updateDispute(dispute, from)
+}
+
+// ResolveDispute updates the dispute, records the adjustment and applies the new fare to the trip
+func (r *FareDisputeRepositoryImpl) ResolveDispute(ctx context.Context, dispute *models.FareDispute, from models.FareDisputeStatus, adjustment *models.FareAdjustment) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	// Check everything before changing anything so a failure leaves the store untouched
+	trip, ok := r.store.trips[adjustment.TripID.String()]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "trip",
+			ID:       adjustment.TripID.String(),
+		}
+	}
+	if existing, ok := r.store.fareDisputes[dispute.ID.String()]; !ok || existing.Status != from {
+		return models.ErrDisputeStatusConflict
+	}
+
+	if err := r.updateDispute(dispute, from); err != nil {
+		return err
+	}
+
+	copied := *adjustment
+	r.store.fareAdjustments = append(r.store.fareAdjustments, &copied)
+
+	newFare := adjustment.NewFare
+	trip.FareAmount = &newFare
+	trip.UpdatedAt = adjustment.CreatedAt
+	return nil
+}
+
+// updateDispute stores the dispute's status and review fields; callers must hold the write lock
+func (r *FareDisputeRepositoryImpl) updateDispute(dispute *models.FareDispute, from models.FareDisputeStatus) error {
+	existing, ok := r.store.fareDisputes[dispute.ID.String()]
+	if !ok || existing.Status != from {
+		return models.ErrDisputeStatusConflict
+	}
+
+	existing.Status = dispute.Status
+	existing.AdjustedFare = dispute.AdjustedFare
+	existing.ReviewedBy = dispute.ReviewedBy
+	existing.ResolutionNote = dispute.ResolutionNote
+	existing.ReviewedAt = dispute.ReviewedAt
+	existing.ClosedAt = dispute.ClosedAt
+	existing.UpdatedAt = dispute.UpdatedAt
+	return nil
+}
+
+// ListDisputes retrieves the matching disputes, oldest first, and the total number of matches
+func (r *FareDisputeRepositoryImpl) ListDisputes(ctx context.Context, filter models.FareDisputeFilter) ([]*models.FareDispute, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	keep := func(d *models.FareDispute) bool {
+		if filter.TripID != nil && d.TripID != *filter.TripID {
+			return false
+		}
+		return filter.Status == "" || d.Status == filter.Status
+	}
+
+	var total int64
+	for _, d := range r.store.fareDisputes {
+		if keep(d) {
+			total++
+		}
+	}
+
+	return selectRows(r.store.fareDisputes, keep, func(a, b *models.FareDispute) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, filter.Limit, filter.Offset), total, nil
+}
+
+// ListAdjustmentsByTrip retrieves the trip's fare adjustments ledger, oldest first
+func (r *FareDisputeRepositoryImpl) ListAdjustmentsByTrip(ctx context.Context, tripID string) ([]*models.FareAdjustment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var adjustments []*models.FareAdjustment
+	for _, adjustment := range r.store.fareAdjustments {
+		if adjustment.TripID.String() == tripID {
+			copied := *adjustment
+			adjustments = append(adjustments, &copied)
+		}
+	}
+	return adjustments, nil
+}
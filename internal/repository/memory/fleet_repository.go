@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// FleetRepositoryImpl implements the FleetRepository interface in memory
+type FleetRepositoryImpl struct {
+	store *Store
+}
+
+// NewFleetRepository creates a new instance of FleetRepositoryImpl
+func NewFleetRepository(store *Store) repository.FleetRepository {
+	return &FleetRepositoryImpl{store: store}
+}
+
+// Create creates a new fleet
+func (r *FleetRepositoryImpl) Create(ctx context.Context, fleet *models.Fleet) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.fleets[fleet.ID.String()]; exists {
+		return fmt.Errorf("failed to create fleet: %w", models.ErrDuplicateEntry)
+	}
+	for _, other := range r.store.fleets {
+		if other.APIKeyHash == fleet.APIKeyHash {
+			return fmt.Errorf("failed to create fleet: %w", models.ErrDuplicateEntry)
+		}
+	}
+
+	copied := *fleet
+	r.store.fleets[fleet.ID.String()] = &copied
+	return nil
+}
+
+// GetByID retrieves a fleet by ID
+func (r *FleetRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Fleet, error) {
+	return getByID(r.store, r.store.fleets, "fleet", id)
+}
+
+// GetByAPIKeyHash retrieves the fleet owning an API key
+func (r *FleetRepositoryImpl) GetByAPIKeyHash(ctx context.Context, hash string) (*models.Fleet, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, fleet := range r.store.fleets {
+		if fleet.APIKeyHash == hash {
+			copied := *fleet
+			return &copied, nil
+		}
+	}
+
+	return nil, &models.NotFoundError{
+		Resource: "fleet",
+		ID:       "<api key>",
+	}
+}
+
+// List retrieves every fleet, oldest first
+func (r *FleetRepositoryImpl) List(ctx context.Context) ([]*models.Fleet, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.fleets, nil, func(a, b *models.Fleet) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, noLimit, 0), nil
+}
+
+// Update updates a fleet's name, timezone and daily request quota
+func (r *FleetRepositoryImpl) Update(ctx context.Context, fleet *models.Fleet) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, exists := r.store.fleets[fleet.ID.String()]
+	if !exists {
+		return &models.NotFoundError{
+			Resource: "fleet",
+			ID:       fleet.ID.String(),
+		}
+	}
+
+	stored.Name = fleet.Name
+	stored.Timezone = fleet.Timezone
+	stored.DailyRequestQuota = fleet.DailyRequestQuota
+	stored.UpdatedAt = fleet.UpdatedAt
+	return nil
+}
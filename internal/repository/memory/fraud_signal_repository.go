@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// FraudSignalRepositoryImpl implements the FraudSignalRepository interface in memory
+type FraudSignalRepositoryImpl struct {
+	store *Store
+}
+
+// NewFraudSignalRepository creates a new instance of FraudSignalRepositoryImpl
+func NewFraudSignalRepository(store *Store) repository.FraudSignalRepository {
+	return &FraudSignalRepositoryImpl{store: store}
+}
+
+// Create creates a new fraud signal
+func (r *FraudSignalRepositoryImpl) Create(ctx context.Context, signal *models.FraudSignal) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.fraudSignals[signal.ID.String()]; exists {
+		return fmt.Errorf("failed to create fraud signal: %w", models.ErrDuplicateEntry)
+	}
+	if signal.TripID != nil {
+		if _, ok := r.store.trips[signal.TripID.String()]; !ok {
+			return &models.NotFoundError{
+				Resource: "trip",
+				ID:       signal.TripID.String(),
+			}
+		}
+		for _, existing := range r.store.fraudSignals {
+			if existing.Rule == signal.Rule && existing.SubjectID == signal.SubjectID &&
+				existing.TripID != nil && *existing.TripID == *signal.TripID {
+				return fmt.Errorf("failed to create fraud signal: %w", models.ErrDuplicateEntry)
+			}
+		}
+	}
+
+	copied := *signal
+	r.store.fraudSignals[signal.ID.String()] = &copied
+	return nil
+}
+
+// GetByID retrieves a fraud signal by ID
+func (r *FraudSignalRepositoryImpl) GetByID(ctx context.Context, id string) (*models.FraudSignal, error) {
+	return getByID(r.store, r.store.fraudSignals, "fraud_signal", id)
+}
+
+// Update stores the signal's status and review fields if its stored status is still from
+func (r *FraudSignalRepositoryImpl) Update(ctx context.Context, signal *models.FraudSignal, from models.FraudSignalStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.fraudSignals[signal.ID.String()]
+	if !ok || existing.Status != from {
+		return models.ErrFraudSignalStatusConflict
+	}
+
+	existing.Status = signal.Status
+	existing.ReviewedBy = signal.ReviewedBy
+	existing.ReviewNote = signal.ReviewNote
+	existing.ReviewedAt = signal.ReviewedAt
+	existing.UpdatedAt = signal.UpdatedAt
+	return nil
+}
+
+// List retrieves the matching fraud signals, newest first, and the total number of matches
+func (r *FraudSignalRepositoryImpl) List(ctx context.Context, filter models.FraudSignalFilter) ([]*models.FraudSignal, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	keep := func(s *models.FraudSignal) bool {
+		if filter.Rule != "" && s.Rule != filter.Rule {
+			return false
+		}
+		if filter.Severity != "" && s.Severity != filter.Severity {
+			return false
+		}
+		if filter.SubjectID != nil && s.SubjectID != *filter.SubjectID {
+			return false
+		}
+		return filter.Status == "" || s.Status == filter.Status
+	}
+
+	var total int64
+	for _, s := range r.store.fraudSignals {
+		if keep(s) {
+			total++
+		}
+	}
+
+	return selectRows(r.store.fraudSignals, keep, func(a, b *models.FraudSignal) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, filter.Limit, filter.Offset), total, nil
+}
@@ -0,0 +1,159 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// IncentiveRepositoryImpl implements the IncentiveRepository interface in memory
+type IncentiveRepositoryImpl struct {
+	store *Store
+}
+
+// NewIncentiveRepository creates a new instance of IncentiveRepositoryImpl
+func NewIncentiveRepository(store *Store) repository.IncentiveRepository {
+	return &IncentiveRepositoryImpl{store: store}
+}
+
+// incentiveKey keys the credit, progress and payout tables by campaign and trip or driver
+func incentiveKey(campaignID, otherID fmt.Stringer) string {
+	return campaignID.String() + "/" + otherID.String()
+}
+
+// CreateCampaign creates a new incentive campaign
+func (r *IncentiveRepositoryImpl) CreateCampaign(ctx context.Context, campaign *models.IncentiveCampaign) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.incentiveCampaigns[campaign.ID.String()]; exists {
+		return fmt.Errorf("failed to create incentive campaign: %w", models.ErrDuplicateEntry)
+	}
+
+	copied := *campaign
+	r.store.incentiveCampaigns[campaign.ID.String()] = &copied
+	return nil
+}
+
+// GetCampaign retrieves an incentive campaign by ID
+func (r *IncentiveRepositoryImpl) GetCampaign(ctx context.Context, id string) (*models.IncentiveCampaign, error) {
+	return getByID(r.store, r.store.incentiveCampaigns, "incentive_campaign", id)
+}
+
+// ListCampaigns retrieves the campaigns ordered by start time, only the running ones when runningAt is set
+func (r *IncentiveRepositoryImpl) ListCampaigns(ctx context.Context, runningAt *time.Time) ([]*models.IncentiveCampaign, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var keep func(*models.IncentiveCampaign) bool
+	if runningAt != nil {
+		keep = func(c *models.IncentiveCampaign) bool { return c.IsRunning(*runningAt) }
+	}
+
+	return selectRows(r.store.incentiveCampaigns, keep, func(a, b *models.IncentiveCampaign) bool {
+		if !a.StartsAt.Equal(b.StartsAt) {
+			return a.StartsAt.Before(b.StartsAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, noLimit, 0), nil
+}
+
+// SetCampaignActive activates or deactivates a campaign
+func (r *IncentiveRepositoryImpl) SetCampaignActive(ctx context.Context, id string, active bool) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	campaign, ok := r.store.incentiveCampaigns[id]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "incentive_campaign",
+			ID:       id,
+		}
+	}
+
+	campaign.Active = active
+	campaign.UpdatedAt = time.Now()
+	return nil
+}
+
+// CreditTrip counts the trip towards the driver's progress and records the payout once the quest is complete
+func (r *IncentiveRepositoryImpl) CreditTrip(ctx context.Context, credit *models.IncentiveTripCredit, payout *models.IncentivePayout) (*models.QuestProgress, bool, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.incentiveCampaigns[credit.CampaignID.String()]; !ok {
+		return nil, false, &models.NotFoundError{
+			Resource: "incentive_campaign",
+			ID:       credit.CampaignID.String(),
+		}
+	}
+	if _, ok := r.store.trips[credit.TripID.String()]; !ok {
+		return nil, false, &models.NotFoundError{
+			Resource: "trip",
+			ID:       credit.TripID.String(),
+		}
+	}
+	if _, ok := r.store.drivers[credit.DriverID.String()]; !ok {
+		return nil, false, &models.NotFoundError{
+			Resource: "driver",
+			ID:       credit.DriverID.String(),
+		}
+	}
+
+	creditKey := incentiveKey(credit.CampaignID, credit.TripID)
+	if _, exists := r.store.incentiveCredits[creditKey]; exists {
+		return nil, false, fmt.Errorf("trip %s already credited: %w", credit.TripID, models.ErrDuplicateEntry)
+	}
+	copiedCredit := *credit
+	r.store.incentiveCredits[creditKey] = &copiedCredit
+
+	driverKey := incentiveKey(credit.CampaignID, credit.DriverID)
+	progress, ok := r.store.incentiveProgress[driverKey]
+	if !ok {
+		progress = &models.QuestProgress{CampaignID: credit.CampaignID, DriverID: credit.DriverID}
+		r.store.incentiveProgress[driverKey] = progress
+	}
+	progress.CompletedTrips++
+	progress.UpdatedAt = time.Now()
+
+	paid := false
+	if progress.CompletedAt == nil && progress.CompletedTrips >= payout.TripCount {
+		if _, exists := r.store.incentivePayouts[driverKey]; !exists {
+			copiedPayout := *payout
+			r.store.incentivePayouts[driverKey] = &copiedPayout
+			paid = true
+		}
+		completedAt := payout.CreatedAt
+		progress.CompletedAt = &completedAt
+	}
+
+	copied := *progress
+	return &copied, paid, nil
+}
+
+// ListProgressByDriver retrieves the driver's progress on every campaign they have credited trips for
+func (r *IncentiveRepositoryImpl) ListProgressByDriver(ctx context.Context, driverID string) ([]*models.QuestProgress, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.incentiveProgress, func(p *models.QuestProgress) bool {
+		return p.DriverID.String() == driverID
+	}, func(a, b *models.QuestProgress) bool {
+		return a.CampaignID.String() < b.CampaignID.String()
+	}, noLimit, 0), nil
+}
+
+// ListPayoutsByDriver retrieves the driver's payouts, newest first
+func (r *IncentiveRepositoryImpl) ListPayoutsByDriver(ctx context.Context, driverID string) ([]*models.IncentivePayout, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.incentivePayouts, func(p *models.IncentivePayout) bool {
+		return p.DriverID.String() == driverID
+	}, func(a, b *models.IncentivePayout) bool {
+		return newestFirst(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}, noLimit, 0), nil
+}
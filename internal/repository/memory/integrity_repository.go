@@ -0,0 +1,203 @@
+package memory
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// IntegrityRepositoryImpl implements the IntegrityRepository interface in memory
+type IntegrityRepositoryImpl struct {
+	store *Store
+}
+
+// NewIntegrityRepository creates a new instance of IntegrityRepositoryImpl
+func NewIntegrityRepository(store *Store) repository.IntegrityRepository {
+	return &IntegrityRepositoryImpl{store: store}
+}
+
+// integrityRow is the ID and time of a row of an observability table
+type integrityRow struct {
+	id uuid.UUID
+	at time.Time
+}
+
+// integrityRows returns the ID and time of every row of an observability table; callers must
+// hold the lock
+func (r *IntegrityRepositoryImpl) integrityRows(table string) ([]integrityRow, error) {
+	var rows []integrityRow
+	switch table {
+	case "event_logs":
+		for _, row := range r.store.eventLogs {
+			rows = append(rows, integrityRow{row.ID, row.Timestamp})
+		}
+	case "actor_messages":
+		for _, row := range r.store.actorMessages {
+			rows = append(rows, integrityRow{row.ID, row.SentAt})
+		}
+	case "system_metrics":
+		for _, row := range r.store.systemMetrics {
+			rows = append(rows, integrityRow{row.ID, row.Timestamp})
+		}
+	case "distributed_traces":
+		for _, row := range r.store.traces {
+			rows = append(rows, integrityRow{row.ID, row.StartTime})
+		}
+	case "traditional_logs":
+		for _, row := range r.store.traditionalLogs {
+			rows = append(rows, integrityRow{row.ID, row.Timestamp})
+		}
+	case "traditional_metrics":
+		for _, row := range r.store.traditionalMetrics {
+			rows = append(rows, integrityRow{row.ID, row.Timestamp})
+		}
+	default:
+		return nil, fmt.Errorf("table %s is not checksummed", table)
+	}
+	return rows, nil
+}
+
+// ChecksumHours returns the row count and checksum of an observability table's rows per hour in
+// [start, end), oldest first. The checksum sums the first 32 bits of the MD5 of every row ID, as
+// the SQL implementation does.
+func (r *IntegrityRepositoryImpl) ChecksumHours(ctx context.Context, table string, start, end time.Time) ([]*models.IntegrityChecksum, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rows, err := r.integrityRows(table)
+	if err != nil {
+		return nil, err
+	}
+
+	byHour := make(map[int64]*models.IntegrityChecksum)
+	for _, row := range rows {
+		if row.at.Before(start) || !row.at.Before(end) {
+			continue
+		}
+		hour := row.at.UTC().Truncate(time.Hour)
+		checksum, ok := byHour[hour.Unix()]
+		if !ok {
+			checksum = &models.IntegrityChecksum{TableName: table, Hour: hour}
+			byHour[hour.Unix()] = checksum
+		}
+		sum := md5.Sum([]byte(row.id.String()))
+		checksum.RowCount++
+		checksum.Checksum += int64(binary.BigEndian.Uint32(sum[:4]))
+	}
+
+	checksums := make([]*models.IntegrityChecksum, 0, len(byHour))
+	for _, checksum := range byHour {
+		checksums = append(checksums, checksum)
+	}
+	sort.Slice(checksums, func(i, j int) bool {
+		return checksums[i].Hour.Before(checksums[j].Hour)
+	})
+	return checksums, nil
+}
+
+// CreateRun stores a run with its checksums
+func (r *IntegrityRepositoryImpl) CreateRun(ctx context.Context, run *models.IntegrityRun, checksums []*models.IntegrityChecksum) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.integrityRuns[run.ID.String()]; exists {
+		return fmt.Errorf("failed to create integrity run: %w", models.ErrDuplicateEntry)
+	}
+
+	copied := *run
+	r.store.integrityRuns[run.ID.String()] = &copied
+	stored := make([]*models.IntegrityChecksum, len(checksums))
+	for i, checksum := range checksums {
+		c := *checksum
+		c.RunID = run.ID
+		stored[i] = &c
+	}
+	sort.Slice(stored, func(i, j int) bool {
+		if stored[i].TableName != stored[j].TableName {
+			return stored[i].TableName < stored[j].TableName
+		}
+		return stored[i].Hour.Before(stored[j].Hour)
+	})
+	r.store.integrityChecksums[run.ID.String()] = stored
+	return nil
+}
+
+// GetRun retrieves a run by ID
+func (r *IntegrityRepositoryImpl) GetRun(ctx context.Context, id string) (*models.IntegrityRun, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	run, ok := r.store.integrityRuns[id]
+	if !ok {
+		return nil, &models.NotFoundError{
+			Resource: "integrity run",
+			ID:       id,
+		}
+	}
+	copied := *run
+	return &copied, nil
+}
+
+// GetPreviousRun retrieves the run started last before the run, or nil when there is none
+func (r *IntegrityRepositoryImpl) GetPreviousRun(ctx context.Context, run *models.IntegrityRun) (*models.IntegrityRun, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	previous := selectRows(r.store.integrityRuns, func(other *models.IntegrityRun) bool {
+		return other.StartedAt.Before(run.StartedAt) && other.ID != run.ID
+	}, func(a, b *models.IntegrityRun) bool {
+		return newestFirst(a.StartedAt, b.StartedAt, a.ID, b.ID)
+	}, 1, 0)
+	if len(previous) == 0 {
+		return nil, nil
+	}
+	return previous[0], nil
+}
+
+// ListRuns retrieves the latest runs, newest first
+func (r *IntegrityRepositoryImpl) ListRuns(ctx context.Context, limit int) ([]*models.IntegrityRun, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.integrityRuns, func(*models.IntegrityRun) bool { return true }, func(a, b *models.IntegrityRun) bool {
+		return newestFirst(a.StartedAt, b.StartedAt, a.ID, b.ID)
+	}, limit, 0), nil
+}
+
+// ListChecksums retrieves the checksums of a run by table, then hour
+func (r *IntegrityRepositoryImpl) ListChecksums(ctx context.Context, runID string) ([]*models.IntegrityChecksum, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	stored := r.store.integrityChecksums[runID]
+	checksums := make([]*models.IntegrityChecksum, len(stored))
+	for i, checksum := range stored {
+		copied := *checksum
+		checksums[i] = &copied
+	}
+	return checksums, nil
+}
+
+// DeleteRunsBefore removes the runs started before the cutoff with their checksums
+func (r *IntegrityRepositoryImpl) DeleteRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var deleted int64
+	for id, run := range r.store.integrityRuns {
+		if run.StartedAt.Before(before) {
+			delete(r.store.integrityRuns, id)
+			delete(r.store.integrityChecksums, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
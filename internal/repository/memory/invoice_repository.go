@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// InvoiceRepositoryImpl implements the InvoiceRepository interface in memory
+type InvoiceRepositoryImpl struct {
+	store *Store
+}
+
+// NewInvoiceRepository creates a new instance of InvoiceRepositoryImpl
+func NewInvoiceRepository(store *Store) repository.InvoiceRepository {
+	return &InvoiceRepositoryImpl{store: store}
+}
+
+// Create creates a new invoice
+func (r *InvoiceRepositoryImpl) Create(ctx context.Context, invoice *models.Invoice) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.invoices[invoice.ID.String()]; exists {
+		return fmt.Errorf("failed to create invoice: %w", models.ErrDuplicateEntry)
+	}
+	for _, other := range r.store.invoices {
+		if other.PartyType == invoice.PartyType && other.PartyID == invoice.PartyID && other.Month == invoice.Month {
+			return fmt.Errorf("failed to create invoice: %w", models.ErrDuplicateEntry)
+		}
+	}
+
+	copied := *invoice
+	r.store.invoices[invoice.ID.String()] = &copied
+	return nil
+}
+
+// GetByID retrieves an invoice by ID
+func (r *InvoiceRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Invoice, error) {
+	return getByID(r.store, r.store.invoices, "invoice", id)
+}
+
+// GetByParty retrieves a party's invoice for a month
+func (r *InvoiceRepositoryImpl) GetByParty(ctx context.Context, partyType models.InvoicePartyType, partyID, month string) (*models.Invoice, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, invoice := range r.store.invoices {
+		if invoice.PartyType == partyType && invoice.PartyID.String() == partyID && invoice.Month == month {
+			copied := *invoice
+			return &copied, nil
+		}
+	}
+
+	return nil, &models.NotFoundError{
+		Resource: "invoice",
+		ID:       fmt.Sprintf("%s %s %s", partyType, partyID, month),
+	}
+}
+
+// Update records the outcome of a generation attempt
+func (r *InvoiceRepositoryImpl) Update(ctx context.Context, invoice *models.Invoice) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.invoices[invoice.ID.String()]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "invoice",
+			ID:       invoice.ID.String(),
+		}
+	}
+
+	existing.PartyName = invoice.PartyName
+	existing.Status = invoice.Status
+	existing.Attempts = invoice.Attempts
+	existing.LastError = invoice.LastError
+	existing.Trips = invoice.Trips
+	existing.TotalAmount = invoice.TotalAmount
+	existing.CSVKey = invoice.CSVKey
+	existing.PDFKey = invoice.PDFKey
+	existing.GeneratedAt = invoice.GeneratedAt
+	existing.UpdatedAt = invoice.UpdatedAt
+	return nil
+}
+
+// List retrieves the invoices matching the filter, newest month first, with the total number of matches
+func (r *InvoiceRepositoryImpl) List(ctx context.Context, filter models.InvoiceFilter) ([]*models.Invoice, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	keep := func(i *models.Invoice) bool {
+		if filter.Month != "" && i.Month != filter.Month {
+			return false
+		}
+		if filter.PartyType != "" && i.PartyType != filter.PartyType {
+			return false
+		}
+		return filter.Status == "" || i.Status == filter.Status
+	}
+
+	var total int64
+	for _, i := range r.store.invoices {
+		if keep(i) {
+			total++
+		}
+	}
+
+	return selectRows(r.store.invoices, keep, func(a, b *models.Invoice) bool {
+		if a.Month != b.Month {
+			return a.Month > b.Month
+		}
+		if a.PartyType != b.PartyType {
+			return a.PartyType < b.PartyType
+		}
+		if a.PartyName != b.PartyName {
+			return a.PartyName < b.PartyName
+		}
+		return a.ID.String() < b.ID.String()
+	}, filter.Limit, filter.Offset), total, nil
+}
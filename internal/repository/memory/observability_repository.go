@@ -0,0 +1,565 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"actor-model-observability/internal/filter"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/payload"
+	"actor-model-observability/internal/repository"
+)
+
+// ObservabilityRepositoryImpl implements the ObservabilityRepository interface in memory
+type ObservabilityRepositoryImpl struct {
+	store *Store
+}
+
+// NewObservabilityRepository creates a new instance of ObservabilityRepositoryImpl
+func NewObservabilityRepository(store *Store) repository.ObservabilityRepository {
+	return &ObservabilityRepositoryImpl{store: store}
+}
+
+// Actor Instance methods
+
+// CreateActorInstance creates a new actor instance
+func (r *ObservabilityRepositoryImpl) CreateActorInstance(ctx context.Context, instance *models.ActorInstance) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.actorInstances[instance.ID.String()]; exists {
+		return fmt.Errorf("failed to create actor instance: %w", models.ErrDuplicateEntry)
+	}
+
+	copied := *instance
+	r.store.actorInstances[instance.ID.String()] = &copied
+	return nil
+}
+
+// GetActorInstance retrieves an actor instance by ID
+func (r *ObservabilityRepositoryImpl) GetActorInstance(ctx context.Context, id string) (*models.ActorInstance, error) {
+	return getByID(r.store, r.store.actorInstances, "actor_instance", id)
+}
+
+// UpdateActorInstance updates an existing actor instance
+func (r *ObservabilityRepositoryImpl) UpdateActorInstance(ctx context.Context, instance *models.ActorInstance) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.actorInstances[instance.ID.String()]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "actor_instance",
+			ID:       instance.ID.String(),
+		}
+	}
+
+	copied := *instance
+	copied.CreatedAt = existing.CreatedAt
+	r.store.actorInstances[instance.ID.String()] = &copied
+	return nil
+}
+
+// ListActorInstances retrieves actor instances, optionally filtered by actor type
+func (r *ObservabilityRepositoryImpl) ListActorInstances(ctx context.Context, actorType string, limit, offset int) ([]*models.ActorInstance, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.actorInstances, func(i *models.ActorInstance) bool {
+		return actorType == "" || string(i.ActorType) == actorType
+	}, func(a, b *models.ActorInstance) bool {
+		return newestFirst(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}, limit, offset), nil
+}
+
+// ListActorInstancesByEntity retrieves the actor instances representing a business entity, such as a driver
+func (r *ObservabilityRepositoryImpl) ListActorInstancesByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.ActorInstance, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.actorInstances, func(i *models.ActorInstance) bool {
+		return i.EntityType != nil && *i.EntityType == entityType &&
+			i.EntityID != nil && i.EntityID.String() == entityID
+	}, func(a, b *models.ActorInstance) bool {
+		return newestFirst(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}, limit, offset), nil
+}
+
+// GetActorLifecycleActivity aggregates the lifecycle event logs and processed messages of the
+// actors alive during the window by actor and bucket, like the PostgreSQL query
+func (r *ObservabilityRepositoryImpl) GetActorLifecycleActivity(ctx context.Context, lf models.ActorLifecycleFilter) ([]*models.ActorLifecycleActivity, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	type lastEvent struct {
+		eventType string
+		at        time.Time
+	}
+	lastBeforeStart := make(map[string]lastEvent)
+	buckets := make(map[string]map[int]*models.ActorLifecycleActivity)
+	bucketOf := func(actorID string, at time.Time) *models.ActorLifecycleActivity {
+		index := int(at.Sub(lf.Start) / lf.Bucket)
+		if buckets[actorID] == nil {
+			buckets[actorID] = make(map[int]*models.ActorLifecycleActivity)
+		}
+		a, ok := buckets[actorID][index]
+		if !ok {
+			a = &models.ActorLifecycleActivity{Bucket: &index}
+			buckets[actorID][index] = a
+		}
+		return a
+	}
+
+	for _, e := range r.store.eventLogs {
+		if e.ActorID == nil {
+			continue
+		}
+		switch e.EventType {
+		case "actor_started", "actor_stopped", "actor_failed":
+		default:
+			continue
+		}
+		if e.Timestamp.Before(lf.Start) {
+			if last, ok := lastBeforeStart[*e.ActorID]; e.EventType != "actor_failed" && (!ok || e.Timestamp.After(last.at)) {
+				lastBeforeStart[*e.ActorID] = lastEvent{eventType: e.EventType, at: e.Timestamp}
+			}
+			continue
+		}
+		if !e.Timestamp.Before(lf.End) {
+			continue
+		}
+		a := bucketOf(*e.ActorID, e.Timestamp)
+		switch e.EventType {
+		case "actor_started":
+			a.Started++
+		case "actor_stopped":
+			a.Stopped++
+		default:
+			a.Failures++
+		}
+	}
+	for _, m := range r.store.actorMessages {
+		if m.Status != models.MessageStatusProcessed || m.ProcessedAt == nil ||
+			m.ProcessedAt.Before(lf.Start) || !m.ProcessedAt.Before(lf.End) {
+			continue
+		}
+		a := bucketOf(m.ReceiverActorID, *m.ProcessedAt)
+		a.Messages++
+		if m.ProcessingDurationMs != nil {
+			a.ProcessingMs += int64(*m.ProcessingDurationMs)
+		}
+	}
+
+	actors := selectRows(r.store.actorInstances, func(i *models.ActorInstance) bool {
+		if !i.CreatedAt.Before(lf.End) || (lf.ActorType != "" && string(i.ActorType) != lf.ActorType) {
+			return false
+		}
+		return !i.CreatedAt.Before(lf.Start) || len(buckets[i.ActorID]) > 0 ||
+			lastBeforeStart[i.ActorID].eventType != "actor_stopped"
+	}, func(a, b *models.ActorInstance) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return a.ActorID < b.ActorID
+	}, lf.Limit, 0)
+
+	var activity []*models.ActorLifecycleActivity
+	for _, i := range actors {
+		row := models.ActorLifecycleActivity{
+			ActorID:      i.ActorID,
+			ActorType:    i.ActorType,
+			EntityType:   i.EntityType,
+			EntityID:     i.EntityID,
+			Status:       i.Status,
+			SpawnedAt:    i.CreatedAt,
+			AliveAtStart: i.CreatedAt.Before(lf.Start) && lastBeforeStart[i.ActorID].eventType != "actor_stopped",
+		}
+		indexes := make([]int, 0, len(buckets[i.ActorID]))
+		for index := range buckets[i.ActorID] {
+			indexes = append(indexes, index)
+		}
+		sort.Ints(indexes)
+		if len(indexes) == 0 {
+			copied := row
+			activity = append(activity, &copied)
+		}
+		for _, index := range indexes {
+			a := buckets[i.ActorID][index]
+			copied := row
+			copied.Bucket = a.Bucket
+			copied.Started, copied.Stopped, copied.Failures = a.Started, a.Stopped, a.Failures
+			copied.Messages, copied.ProcessingMs = a.Messages, a.ProcessingMs
+			activity = append(activity, &copied)
+		}
+	}
+	return activity, nil
+}
+
+// Actor Message methods
+
+// CreateActorMessage creates a new actor message
+func (r *ObservabilityRepositoryImpl) CreateActorMessage(ctx context.Context, message *models.ActorMessage) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.actorMessages[message.ID.String()]; exists {
+		return fmt.Errorf("failed to create actor message: %w", models.ErrDuplicateEntry)
+	}
+
+	copied := *message
+	r.store.actorMessages[message.ID.String()] = &copied
+	return nil
+}
+
+// GetActorMessage retrieves an actor message by ID
+func (r *ObservabilityRepositoryImpl) GetActorMessage(ctx context.Context, id string) (*models.ActorMessage, error) {
+	message, err := getByID(r.store, r.store.actorMessages, "actor_message", id)
+	if err != nil {
+		return nil, err
+	}
+	payload.DecodeActorMessage(message)
+	return message, nil
+}
+
+// ListActorMessages retrieves actor messages, optionally filtered by sender and receiver actor IDs
+func (r *ObservabilityRepositoryImpl) ListActorMessages(ctx context.Context, fromActor, toActor string, limit, offset int) ([]*models.ActorMessage, error) {
+	return r.selectMessages(func(m *models.ActorMessage) bool {
+		return (fromActor == "" || m.SenderActorID == fromActor) &&
+			(toActor == "" || m.ReceiverActorID == toActor)
+	}, limit, offset), nil
+}
+
+// GetMessagesByTimeRange retrieves messages created within a time range
+func (r *ObservabilityRepositoryImpl) GetMessagesByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.ActorMessage, error) {
+	start, end, err := parseTimeRange(startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.selectMessages(func(m *models.ActorMessage) bool {
+		return inRange(m.CreatedAt, start, end)
+	}, limit, offset), nil
+}
+
+// GetMessagesByTraceID retrieves all messages of a trace ordered by hybrid timestamp, then by
+// send time for messages stored without one, which sort last like in PostgreSQL
+func (r *ObservabilityRepositoryImpl) GetMessagesByTraceID(ctx context.Context, traceID string) ([]*models.ActorMessage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	messages := selectRows(r.store.actorMessages, func(m *models.ActorMessage) bool {
+		return m.TraceID.String() == traceID
+	}, func(a, b *models.ActorMessage) bool {
+		if (a.HLC == nil) != (b.HLC == nil) {
+			return a.HLC != nil
+		}
+		if a.HLC != nil && *a.HLC != *b.HLC {
+			return a.HLC.Before(*b.HLC)
+		}
+		if !a.SentAt.Equal(b.SentAt) {
+			return a.SentAt.Before(b.SentAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, noLimit, 0)
+	for _, message := range messages {
+		payload.DecodeActorMessage(message)
+	}
+	return messages, nil
+}
+
+// ListActorMessagesByEntity retrieves the actor messages about a business entity, such as a trip
+func (r *ObservabilityRepositoryImpl) ListActorMessagesByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.ActorMessage, error) {
+	return r.selectMessages(func(m *models.ActorMessage) bool {
+		return m.EntityType != nil && *m.EntityType == entityType &&
+			m.EntityID != nil && m.EntityID.String() == entityID
+	}, limit, offset), nil
+}
+
+// FilterActorMessages retrieves the actor messages matching a filter expression
+func (r *ObservabilityRepositoryImpl) FilterActorMessages(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.ActorMessage, error) {
+	return r.selectMessages(func(m *models.ActorMessage) bool {
+		return f.Match(jsonFields(m))
+	}, limit, offset), nil
+}
+
+// GetMessageStats aggregates the messages created within the last window by message type and
+// receiving actor type, interpolating percentiles like PostgreSQL's percentile_cont
+func (r *ObservabilityRepositoryImpl) GetMessageStats(ctx context.Context, window time.Duration) ([]*models.MessageStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	type group struct {
+		messageType string
+		actorType   models.ActorType
+	}
+	since := time.Now().Add(-window)
+	groups := make(map[group]*models.MessageStats)
+	durations := make(map[group][]float64)
+	for _, m := range r.store.actorMessages {
+		if m.CreatedAt.Before(since) {
+			continue
+		}
+		key := group{messageType: m.MessageType, actorType: m.ReceiverActorType}
+		s, ok := groups[key]
+		if !ok {
+			s = &models.MessageStats{MessageType: m.MessageType, ActorType: m.ReceiverActorType}
+			groups[key] = s
+		}
+		s.Total++
+		if m.Status == models.MessageStatusFailed || m.Status == models.MessageStatusDeadLettered {
+			s.Failed++
+		}
+		if m.ProcessingDurationMs != nil {
+			durations[key] = append(durations[key], float64(*m.ProcessingDurationMs))
+		}
+	}
+
+	stats := make([]*models.MessageStats, 0, len(groups))
+	for key, s := range groups {
+		s.FailureRatio = float64(s.Failed) / float64(s.Total)
+		if d := durations[key]; len(d) > 0 {
+			sort.Float64s(d)
+			s.P50ProcessingMs = percentileCont(d, 0.50)
+			s.P95ProcessingMs = percentileCont(d, 0.95)
+			s.P99ProcessingMs = percentileCont(d, 0.99)
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].MessageType != stats[j].MessageType {
+			return stats[i].MessageType < stats[j].MessageType
+		}
+		return stats[i].ActorType < stats[j].ActorType
+	})
+	return stats, nil
+}
+
+// percentileCont returns the q quantile of sorted values, interpolating linearly between the
+// two nearest values
+func percentileCont(sorted []float64, q float64) *float64 {
+	pos := q * float64(len(sorted)-1)
+	lower := int(math.Floor(pos))
+	value := sorted[lower]
+	if lower+1 < len(sorted) {
+		value += (pos - float64(lower)) * (sorted[lower+1] - sorted[lower])
+	}
+	return &value
+}
+
+// System Metric methods
+
+// CreateSystemMetric creates a new system metric
+func (r *ObservabilityRepositoryImpl) CreateSystemMetric(ctx context.Context, metric *models.SystemMetric) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.systemMetrics[metric.ID.String()]; exists {
+		return fmt.Errorf("failed to create system metric: %w", models.ErrDuplicateEntry)
+	}
+
+	copied := *metric
+	r.store.systemMetrics[metric.ID.String()] = &copied
+	return nil
+}
+
+// GetSystemMetric retrieves a system metric by ID
+func (r *ObservabilityRepositoryImpl) GetSystemMetric(ctx context.Context, id string) (*models.SystemMetric, error) {
+	return getByID(r.store, r.store.systemMetrics, "system_metric", id)
+}
+
+// ListSystemMetrics retrieves system metrics, optionally filtered by metric type
+func (r *ObservabilityRepositoryImpl) ListSystemMetrics(ctx context.Context, metricType string, limit, offset int) ([]*models.SystemMetric, error) {
+	return r.selectMetrics(func(m *models.SystemMetric) bool {
+		return metricType == "" || string(m.MetricType) == metricType
+	}, limit, offset), nil
+}
+
+// GetMetricsByTimeRange retrieves metrics recorded within a time range
+func (r *ObservabilityRepositoryImpl) GetMetricsByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.SystemMetric, error) {
+	start, end, err := parseTimeRange(startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.selectMetrics(func(m *models.SystemMetric) bool {
+		return inRange(m.Timestamp, start, end)
+	}, limit, offset), nil
+}
+
+// Distributed Trace methods
+
+// CreateDistributedTrace creates a new distributed trace span
+func (r *ObservabilityRepositoryImpl) CreateDistributedTrace(ctx context.Context, trace *models.DistributedTrace) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.traces[trace.ID.String()]; exists {
+		return fmt.Errorf("failed to create distributed trace: %w", models.ErrDuplicateEntry)
+	}
+
+	copied := *trace
+	r.store.traces[trace.ID.String()] = &copied
+	return nil
+}
+
+// GetDistributedTrace retrieves a distributed trace span by ID
+func (r *ObservabilityRepositoryImpl) GetDistributedTrace(ctx context.Context, id string) (*models.DistributedTrace, error) {
+	return getByID(r.store, r.store.traces, "distributed_trace", id)
+}
+
+// GetTracesByTraceID retrieves all spans of a trace ordered by start time
+func (r *ObservabilityRepositoryImpl) GetTracesByTraceID(ctx context.Context, traceID string) ([]*models.DistributedTrace, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.traces, func(t *models.DistributedTrace) bool {
+		return t.TraceID.String() == traceID
+	}, func(a, b *models.DistributedTrace) bool {
+		if !a.StartTime.Equal(b.StartTime) {
+			return a.StartTime.Before(b.StartTime)
+		}
+		return a.ID.String() < b.ID.String()
+	}, noLimit, 0), nil
+}
+
+// ListDistributedTraces retrieves distributed traces, optionally filtered by operation name
+func (r *ObservabilityRepositoryImpl) ListDistributedTraces(ctx context.Context, operation string, limit, offset int) ([]*models.DistributedTrace, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.traces, func(t *models.DistributedTrace) bool {
+		return operation == "" || t.OperationName == operation
+	}, func(a, b *models.DistributedTrace) bool {
+		return newestFirst(a.StartTime, b.StartTime, a.ID, b.ID)
+	}, limit, offset), nil
+}
+
+// FilterDistributedTraces retrieves the distributed trace spans matching a filter expression
+func (r *ObservabilityRepositoryImpl) FilterDistributedTraces(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.DistributedTrace, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.traces, func(t *models.DistributedTrace) bool {
+		return f.Match(jsonFields(t))
+	}, func(a, b *models.DistributedTrace) bool {
+		return newestFirst(a.StartTime, b.StartTime, a.ID, b.ID)
+	}, limit, offset), nil
+}
+
+// Event Log methods
+
+// CreateEventLog creates a new event log
+func (r *ObservabilityRepositoryImpl) CreateEventLog(ctx context.Context, log *models.EventLog) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.eventLogs[log.ID.String()]; exists {
+		return fmt.Errorf("failed to create event log: %w", models.ErrDuplicateEntry)
+	}
+
+	copied := *log
+	r.store.eventLogs[log.ID.String()] = &copied
+	return nil
+}
+
+// GetEventLog retrieves an event log by ID
+func (r *ObservabilityRepositoryImpl) GetEventLog(ctx context.Context, id string) (*models.EventLog, error) {
+	log, err := getByID(r.store, r.store.eventLogs, "event_log", id)
+	if err != nil {
+		return nil, err
+	}
+	payload.DecodeEventLog(log)
+	return log, nil
+}
+
+// ListEventLogs retrieves event logs, optionally filtered by event type and source actor ID
+func (r *ObservabilityRepositoryImpl) ListEventLogs(ctx context.Context, eventType, source string, limit, offset int) ([]*models.EventLog, error) {
+	return r.selectEventLogs(func(l *models.EventLog) bool {
+		return (eventType == "" || l.EventType == eventType) &&
+			(source == "" || (l.ActorID != nil && *l.ActorID == source))
+	}, limit, offset), nil
+}
+
+// GetEventLogsByTimeRange retrieves event logs recorded within a time range
+func (r *ObservabilityRepositoryImpl) GetEventLogsByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.EventLog, error) {
+	start, end, err := parseTimeRange(startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.selectEventLogs(func(l *models.EventLog) bool {
+		return inRange(l.Timestamp, start, end)
+	}, limit, offset), nil
+}
+
+// ListEventLogsByEntity retrieves the event logs recorded for a business entity, such as a trip
+func (r *ObservabilityRepositoryImpl) ListEventLogsByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.EventLog, error) {
+	return r.selectEventLogs(func(l *models.EventLog) bool {
+		return l.EntityType != nil && *l.EntityType == entityType &&
+			l.EntityID != nil && l.EntityID.String() == entityID
+	}, limit, offset), nil
+}
+
+// FilterEventLogs retrieves the event logs matching a filter expression
+func (r *ObservabilityRepositoryImpl) FilterEventLogs(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.EventLog, error) {
+	return r.selectEventLogs(func(l *models.EventLog) bool {
+		return f.Match(jsonFields(l))
+	}, limit, offset), nil
+}
+
+// Helper methods
+
+func (r *ObservabilityRepositoryImpl) selectMessages(keep func(*models.ActorMessage) bool, limit, offset int) []*models.ActorMessage {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	messages := selectRows(r.store.actorMessages, keep, func(a, b *models.ActorMessage) bool {
+		return newestFirst(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}, limit, offset)
+	for _, message := range messages {
+		payload.DecodeActorMessage(message)
+	}
+	return messages
+}
+
+func (r *ObservabilityRepositoryImpl) selectMetrics(keep func(*models.SystemMetric) bool, limit, offset int) []*models.SystemMetric {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.systemMetrics, keep, func(a, b *models.SystemMetric) bool {
+		return newestFirst(a.Timestamp, b.Timestamp, a.ID, b.ID)
+	}, limit, offset)
+}
+
+func (r *ObservabilityRepositoryImpl) selectEventLogs(keep func(*models.EventLog) bool, limit, offset int) []*models.EventLog {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	logs := selectRows(r.store.eventLogs, keep, func(a, b *models.EventLog) bool {
+		return newestFirst(a.Timestamp, b.Timestamp, a.ID, b.ID)
+	}, limit, offset)
+	for _, log := range logs {
+		payload.DecodeEventLog(log)
+	}
+	return logs
+}
+
+// getByID returns a copy of the row stored under id or a NotFoundError for resource
+func getByID[T any](store *Store, table map[string]*T, resource, id string) (*T, error) {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	row, ok := table[id]
+	if !ok {
+		return nil, &models.NotFoundError{
+			Resource: resource,
+			ID:       id,
+		}
+	}
+
+	copied := *row
+	return &copied, nil
+}
@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// PassengerRepositoryImpl implements the PassengerRepository interface in memory
+type PassengerRepositoryImpl struct {
+	store *Store
+}
+
+// NewPassengerRepository creates a new instance of PassengerRepositoryImpl
+func NewPassengerRepository(store *Store) repository.PassengerRepository {
+	return &PassengerRepositoryImpl{store: store}
+}
+
+// Create creates a new passenger
+func (r *PassengerRepositoryImpl) Create(ctx context.Context, passenger *models.Passenger) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.passengers[passenger.ID.String()]; exists {
+		return fmt.Errorf("failed to create passenger: %w", models.ErrDuplicateEntry)
+	}
+	for _, other := range r.store.passengers {
+		if other.UserID == passenger.UserID {
+			return &models.ValidationError{
+				Field:   "user_id",
+				Message: "passenger already exists for this user",
+			}
+		}
+	}
+	if _, ok := r.store.users[passenger.UserID.String()]; !ok {
+		return &models.ValidationError{
+			Field:   "user_id",
+			Message: "user does not exist",
+		}
+	}
+
+	if passenger.CorporateAccountID != nil {
+		if _, ok := r.store.corporateAccounts[passenger.CorporateAccountID.String()]; !ok {
+			return &models.NotFoundError{
+				Resource: "corporate account",
+				ID:       passenger.CorporateAccountID.String(),
+			}
+		}
+	}
+
+	copied := *passenger
+	copied.User = nil
+	copied.MinDriverRating = copyFloat(passenger.MinDriverRating)
+	r.store.passengers[passenger.ID.String()] = &copied
+	return nil
+}
+
+// GetByID retrieves a passenger by ID
+func (r *PassengerRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Passenger, error) {
+	return r.find(id, func(p *models.Passenger) bool { return p.ID.String() == id })
+}
+
+// GetByUserID retrieves a passenger by user ID
+func (r *PassengerRepositoryImpl) GetByUserID(ctx context.Context, userID string) (*models.Passenger, error) {
+	return r.find(userID, func(p *models.Passenger) bool { return p.UserID.String() == userID })
+}
+
+// Update updates an existing passenger
+func (r *PassengerRepositoryImpl) Update(ctx context.Context, passenger *models.Passenger) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.passengers[passenger.ID.String()]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "passenger",
+			ID:       passenger.ID.String(),
+		}
+	}
+
+	existing.Rating = passenger.Rating
+	existing.TotalTrips = passenger.TotalTrips
+	existing.RidePreferences = passenger.RidePreferences
+	existing.MinDriverRating = copyFloat(passenger.MinDriverRating)
+	existing.UpdatedAt = passenger.UpdatedAt
+	return nil
+}
+
+// Delete deletes a passenger by ID
+func (r *PassengerRepositoryImpl) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.passengers[id]; !ok {
+		return &models.NotFoundError{
+			Resource: "passenger",
+			ID:       id,
+		}
+	}
+
+	delete(r.store.passengers, id)
+
+	// Saved locations cascade with the passenger like the foreign key in PostgreSQL
+	for locationID, location := range r.store.savedLocations {
+		if location.PassengerID.String() == id {
+			delete(r.store.savedLocations, locationID)
+		}
+	}
+	for tripID, charge := range r.store.corporateCharges {
+		if charge.PassengerID.String() == id {
+			delete(r.store.corporateCharges, tripID)
+		}
+	}
+	return nil
+}
+
+// List retrieves a list of passengers with pagination
+func (r *PassengerRepositoryImpl) List(ctx context.Context, limit, offset int) ([]*models.Passenger, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.passengers, nil, func(a, b *models.Passenger) bool {
+		return newestFirst(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}, limit, offset), nil
+}
+
+// find returns a copy of the first passenger matching match
+func (r *PassengerRepositoryImpl) find(key string, match func(*models.Passenger) bool) (*models.Passenger, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, passenger := range r.store.passengers {
+		if match(passenger) {
+			copied := *passenger
+			return &copied, nil
+		}
+	}
+
+	return nil, &models.NotFoundError{
+		Resource: "passenger",
+		ID:       key,
+	}
+}
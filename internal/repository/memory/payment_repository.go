@@ -0,0 +1,174 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// PaymentRepositoryImpl implements the PaymentRepository interface in memory
+type PaymentRepositoryImpl struct {
+	store *Store
+}
+
+// NewPaymentRepository creates a new instance of PaymentRepositoryImpl
+func NewPaymentRepository(store *Store) repository.PaymentRepository {
+	return &PaymentRepositoryImpl{store: store}
+}
+
+// CreatePayment appends an entry to a trip's payments ledger
+func (r *PaymentRepositoryImpl) CreatePayment(ctx context.Context, payment *models.TripPayment) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.trips[payment.TripID.String()]; !ok {
+		return &models.NotFoundError{
+			Resource: "trip",
+			ID:       payment.TripID.String(),
+		}
+	}
+
+	copied := *payment
+	r.store.tripPayments = append(r.store.tripPayments, &copied)
+	return nil
+}
+
+// ListPaymentsByTrip retrieves the trip's payments ledger, oldest first
+func (r *PaymentRepositoryImpl) ListPaymentsByTrip(ctx context.Context, tripID string) ([]*models.TripPayment, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var payments []*models.TripPayment
+	for _, payment := range r.store.tripPayments {
+		if payment.TripID.String() == tripID {
+			copied := *payment
+			payments = append(payments, &copied)
+		}
+	}
+	return payments, nil
+}
+
+// ListBalances retrieves the fare and net payments of the trips completed in [start, end), oldest
+// first
+func (r *PaymentRepositoryImpl) ListBalances(ctx context.Context, start, end time.Time) ([]*models.TripPaymentBalance, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byTrip := make(map[string]*models.TripPaymentBalance)
+	var balances []*models.TripPaymentBalance
+	for id, trip := range r.store.trips {
+		if trip.Status != models.TripStatusCompleted || trip.CompletedAt == nil ||
+			trip.CompletedAt.Before(start) || !trip.CompletedAt.Before(end) {
+			continue
+		}
+		balance := &models.TripPaymentBalance{TripID: trip.ID, CompletedAt: *trip.CompletedAt}
+		if trip.FareAmount != nil {
+			balance.Fare = *trip.FareAmount
+		}
+		byTrip[id] = balance
+		balances = append(balances, balance)
+	}
+	for _, payment := range r.store.tripPayments {
+		if balance, ok := byTrip[payment.TripID.String()]; ok {
+			balance.Paid += payment.Net()
+			balance.Payments++
+		}
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		if !balances[i].CompletedAt.Equal(balances[j].CompletedAt) {
+			return balances[i].CompletedAt.Before(balances[j].CompletedAt)
+		}
+		return balances[i].TripID.String() < balances[j].TripID.String()
+	})
+	return balances, nil
+}
+
+// CreateDiscrepancy creates a new payment discrepancy
+func (r *PaymentRepositoryImpl) CreateDiscrepancy(ctx context.Context, discrepancy *models.PaymentDiscrepancy) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.paymentDiscrepancies[discrepancy.ID.String()]; exists {
+		return fmt.Errorf("failed to create payment discrepancy: %w", models.ErrDuplicateEntry)
+	}
+	if _, ok := r.store.trips[discrepancy.TripID.String()]; !ok {
+		return &models.NotFoundError{
+			Resource: "trip",
+			ID:       discrepancy.TripID.String(),
+		}
+	}
+	if discrepancy.Status == models.PaymentDiscrepancyOpen {
+		for _, existing := range r.store.paymentDiscrepancies {
+			if existing.Status == models.PaymentDiscrepancyOpen && existing.TripID == discrepancy.TripID &&
+				existing.Type == discrepancy.Type {
+				return fmt.Errorf("failed to create payment discrepancy: %w", models.ErrDuplicateEntry)
+			}
+		}
+	}
+
+	copied := *discrepancy
+	r.store.paymentDiscrepancies[discrepancy.ID.String()] = &copied
+	return nil
+}
+
+// GetDiscrepancy retrieves a payment discrepancy by ID
+func (r *PaymentRepositoryImpl) GetDiscrepancy(ctx context.Context, id string) (*models.PaymentDiscrepancy, error) {
+	return getByID(r.store, r.store.paymentDiscrepancies, "payment_discrepancy", id)
+}
+
+// UpdateDiscrepancy stores the discrepancy's status and resolution fields if its stored status is
+// still from
+func (r *PaymentRepositoryImpl) UpdateDiscrepancy(ctx context.Context, discrepancy *models.PaymentDiscrepancy, from models.PaymentDiscrepancyStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.paymentDiscrepancies[discrepancy.ID.String()]
+	if !ok || existing.Status != from {
+		return models.ErrPaymentDiscrepancyStatusConflict
+	}
+
+	existing.Status = discrepancy.Status
+	existing.Resolution = discrepancy.Resolution
+	existing.PaymentID = discrepancy.PaymentID
+	existing.ResolvedBy = discrepancy.ResolvedBy
+	existing.ResolutionNote = discrepancy.ResolutionNote
+	existing.ResolvedAt = discrepancy.ResolvedAt
+	existing.UpdatedAt = discrepancy.UpdatedAt
+	return nil
+}
+
+// ListDiscrepancies retrieves the matching payment discrepancies, newest first, and the total
+// number of matches
+func (r *PaymentRepositoryImpl) ListDiscrepancies(ctx context.Context, filter models.PaymentDiscrepancyFilter) ([]*models.PaymentDiscrepancy, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	keep := func(d *models.PaymentDiscrepancy) bool {
+		if filter.Type != "" && d.Type != filter.Type {
+			return false
+		}
+		if filter.TripID != nil && d.TripID != *filter.TripID {
+			return false
+		}
+		return filter.Status == "" || d.Status == filter.Status
+	}
+
+	var total int64
+	for _, d := range r.store.paymentDiscrepancies {
+		if keep(d) {
+			total++
+		}
+	}
+
+	return selectRows(r.store.paymentDiscrepancies, keep, func(a, b *models.PaymentDiscrepancy) bool {
+		if !a.DetectedAt.Equal(b.DetectedAt) {
+			return a.DetectedAt.After(b.DetectedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, filter.Limit, filter.Offset), total, nil
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// SafetyIncidentRepositoryImpl implements the SafetyIncidentRepository interface in memory
+type SafetyIncidentRepositoryImpl struct {
+	store *Store
+}
+
+// NewSafetyIncidentRepository creates a new instance of SafetyIncidentRepositoryImpl
+func NewSafetyIncidentRepository(store *Store) repository.SafetyIncidentRepository {
+	return &SafetyIncidentRepositoryImpl{store: store}
+}
+
+// Create creates a new safety incident
+func (r *SafetyIncidentRepositoryImpl) Create(ctx context.Context, incident *models.SafetyIncident) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.safetyIncidents[incident.ID.String()]; exists {
+		return fmt.Errorf("failed to create safety incident: %w", models.ErrDuplicateEntry)
+	}
+	if _, ok := r.store.trips[incident.TripID.String()]; !ok {
+		return &models.NotFoundError{
+			Resource: "trip",
+			ID:       incident.TripID.String(),
+		}
+	}
+
+	copied := *incident
+	r.store.safetyIncidents[incident.ID.String()] = &copied
+	return nil
+}
+
+// GetByID retrieves a safety incident by ID
+func (r *SafetyIncidentRepositoryImpl) GetByID(ctx context.Context, id string) (*models.SafetyIncident, error) {
+	return getByID(r.store, r.store.safetyIncidents, "safety_incident", id)
+}
+
+// Update stores the incident's status and review fields if its stored status is still from
+func (r *SafetyIncidentRepositoryImpl) Update(ctx context.Context, incident *models.SafetyIncident, from models.SafetyIncidentStatus) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.safetyIncidents[incident.ID.String()]
+	if !ok || existing.Status != from {
+		return models.ErrIncidentStatusConflict
+	}
+
+	existing.Status = incident.Status
+	existing.ReviewedBy = incident.ReviewedBy
+	existing.ResolutionNote = incident.ResolutionNote
+	existing.ReviewedAt = incident.ReviewedAt
+	existing.ClosedAt = incident.ClosedAt
+	existing.UpdatedAt = incident.UpdatedAt
+	return nil
+}
+
+// List retrieves the matching safety incidents, oldest first, and the total number of matches
+func (r *SafetyIncidentRepositoryImpl) List(ctx context.Context, filter models.SafetyIncidentFilter) ([]*models.SafetyIncident, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	keep := func(i *models.SafetyIncident) bool {
+		if filter.TripID != nil && i.TripID != *filter.TripID {
+			return false
+		}
+		return filter.Status == "" || i.Status == filter.Status
+	}
+
+	var total int64
+	for _, i := range r.store.safetyIncidents {
+		if keep(i) {
+			total++
+		}
+	}
+
+	return selectRows(r.store.safetyIncidents, keep, func(a, b *models.SafetyIncident) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, filter.Limit, filter.Offset), total, nil
+}
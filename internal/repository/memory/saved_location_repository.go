@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// savedLocationLabelOrder lists home and work before custom locations, like the PostgreSQL query
+var savedLocationLabelOrder = map[models.SavedLocationLabel]int{
+	models.SavedLocationHome:   0,
+	models.SavedLocationWork:   1,
+	models.SavedLocationCustom: 2,
+}
+
+// SavedLocationRepositoryImpl implements the SavedLocationRepository interface in memory
+type SavedLocationRepositoryImpl struct {
+	store *Store
+}
+
+// NewSavedLocationRepository creates a new instance of SavedLocationRepositoryImpl
+func NewSavedLocationRepository(store *Store) repository.SavedLocationRepository {
+	return &SavedLocationRepositoryImpl{store: store}
+}
+
+// Create creates a new saved location
+func (r *SavedLocationRepositoryImpl) Create(ctx context.Context, location *models.SavedLocation) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.savedLocations[location.ID.String()]; exists {
+		return fmt.Errorf("failed to create saved location: %w", models.ErrDuplicateEntry)
+	}
+	if _, ok := r.store.passengers[location.PassengerID.String()]; !ok {
+		return &models.ValidationError{
+			Field:   "passenger_id",
+			Message: "passenger does not exist",
+		}
+	}
+	if err := r.checkUnique(location); err != nil {
+		return err
+	}
+
+	copied := *location
+	r.store.savedLocations[location.ID.String()] = &copied
+	return nil
+}
+
+// GetByID retrieves a saved location by ID
+func (r *SavedLocationRepositoryImpl) GetByID(ctx context.Context, id string) (*models.SavedLocation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	location, ok := r.store.savedLocations[id]
+	if !ok {
+		return nil, &models.NotFoundError{
+			Resource: "saved location",
+			ID:       id,
+		}
+	}
+
+	copied := *location
+	return &copied, nil
+}
+
+// Update updates an existing saved location
+func (r *SavedLocationRepositoryImpl) Update(ctx context.Context, location *models.SavedLocation) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.savedLocations[location.ID.String()]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "saved location",
+			ID:       location.ID.String(),
+		}
+	}
+
+	updated := *location
+	updated.PassengerID = existing.PassengerID
+	if err := r.checkUnique(&updated); err != nil {
+		return err
+	}
+
+	existing.Label = location.Label
+	existing.Name = location.Name
+	existing.Latitude = location.Latitude
+	existing.Longitude = location.Longitude
+	existing.Address = location.Address
+	existing.UpdatedAt = location.UpdatedAt
+	return nil
+}
+
+// Delete deletes a saved location by ID
+func (r *SavedLocationRepositoryImpl) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.savedLocations[id]; !ok {
+		return &models.NotFoundError{
+			Resource: "saved location",
+			ID:       id,
+		}
+	}
+
+	delete(r.store.savedLocations, id)
+	return nil
+}
+
+// ListByPassengerID retrieves a passenger's saved locations, home and work first
+func (r *SavedLocationRepositoryImpl) ListByPassengerID(ctx context.Context, passengerID string) ([]*models.SavedLocation, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.savedLocations, func(l *models.SavedLocation) bool {
+		return l.PassengerID.String() == passengerID
+	}, func(a, b *models.SavedLocation) bool {
+		if oa, ob := savedLocationLabelOrder[a.Label], savedLocationLabelOrder[b.Label]; oa != ob {
+			return oa < ob
+		}
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, noLimit, 0), nil
+}
+
+// checkUnique enforces one home and one work location per passenger
+func (r *SavedLocationRepositoryImpl) checkUnique(location *models.SavedLocation) error {
+	if !location.Label.IsUnique() {
+		return nil
+	}
+
+	for _, other := range r.store.savedLocations {
+		if other.ID != location.ID && other.PassengerID == location.PassengerID && other.Label == location.Label {
+			return &models.ValidationError{
+				Field:   "label",
+				Message: "passenger already has a location with this label",
+			}
+		}
+	}
+	return nil
+}
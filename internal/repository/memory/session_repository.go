@@ -0,0 +1,160 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// SessionRepositoryImpl implements the SessionRepository interface in memory
+type SessionRepositoryImpl struct {
+	store *Store
+}
+
+// NewSessionRepository creates a new instance of SessionRepositoryImpl
+func NewSessionRepository(store *Store) repository.SessionRepository {
+	return &SessionRepositoryImpl{store: store}
+}
+
+// Create creates a new session
+func (r *SessionRepositoryImpl) Create(ctx context.Context, session *models.Session) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.sessions[session.ID.String()]; exists {
+		return fmt.Errorf("failed to create session: %w", models.ErrDuplicateEntry)
+	}
+	for _, other := range r.store.sessions {
+		if other.AccessTokenHash == session.AccessTokenHash || other.RefreshTokenHash == session.RefreshTokenHash {
+			return fmt.Errorf("failed to create session: %w", models.ErrDuplicateEntry)
+		}
+	}
+	if _, ok := r.store.users[session.UserID.String()]; !ok {
+		return &models.ValidationError{
+			Field:   "user_id",
+			Message: "user does not exist",
+		}
+	}
+
+	copied := *session
+	r.store.sessions[session.ID.String()] = &copied
+	return nil
+}
+
+// GetByID retrieves a session by ID
+func (r *SessionRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Session, error) {
+	return r.find(id, func(s *models.Session) bool { return s.ID.String() == id })
+}
+
+// GetByAccessTokenHash retrieves the session holding an access token
+func (r *SessionRepositoryImpl) GetByAccessTokenHash(ctx context.Context, hash string) (*models.Session, error) {
+	return r.find("<token>", func(s *models.Session) bool { return s.AccessTokenHash == hash })
+}
+
+// GetByRefreshTokenHash retrieves the session holding a refresh token
+func (r *SessionRepositoryImpl) GetByRefreshTokenHash(ctx context.Context, hash string) (*models.Session, error) {
+	return r.find("<token>", func(s *models.Session) bool { return s.RefreshTokenHash == hash })
+}
+
+// find returns a copy of the first session matching match; id is reported when none does
+func (r *SessionRepositoryImpl) find(id string, match func(*models.Session) bool) (*models.Session, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, session := range r.store.sessions {
+		if match(session) {
+			copied := *session
+			return &copied, nil
+		}
+	}
+
+	return nil, &models.NotFoundError{
+		Resource: "session",
+		ID:       id,
+	}
+}
+
+// Update stores the session's tokens, expiry, last use, app version and revocation
+func (r *SessionRepositoryImpl) Update(ctx context.Context, session *models.Session) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.sessions[session.ID.String()]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "session",
+			ID:       session.ID.String(),
+		}
+	}
+
+	existing.AccessTokenHash = session.AccessTokenHash
+	existing.RefreshTokenHash = session.RefreshTokenHash
+	existing.AccessExpiresAt = session.AccessExpiresAt
+	existing.ExpiresAt = session.ExpiresAt
+	existing.LastUsedAt = session.LastUsedAt
+	existing.RevokedAt = session.RevokedAt
+	existing.RevokedReason = session.RevokedReason
+	existing.UpdatedAt = session.UpdatedAt
+	existing.AppPlatform = session.AppPlatform
+	existing.AppVersion = session.AppVersion
+	return nil
+}
+
+// ListActiveByUser retrieves the user's sessions not revoked or expired at now, most recently used first
+func (r *SessionRepositoryImpl) ListActiveByUser(ctx context.Context, userID string, now time.Time) ([]*models.Session, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.sessions, func(s *models.Session) bool {
+		return s.UserID.String() == userID && s.IsActive(now)
+	}, func(a, b *models.Session) bool {
+		return newestFirst(a.LastUsedAt, b.LastUsedAt, a.ID, b.ID)
+	}, noLimit, 0), nil
+}
+
+// CountActiveByAppVersion counts the sessions not revoked or expired at now per app platform and
+// version, by platform and most sessions first
+func (r *SessionRepositoryImpl) CountActiveByAppVersion(ctx context.Context, now time.Time) ([]*models.AppVersionSessions, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	byVersion := make(map[[2]string]*models.AppVersionSessions)
+	for _, session := range r.store.sessions {
+		if !session.IsActive(now) {
+			continue
+		}
+		var key [2]string
+		if session.AppPlatform != nil {
+			key[0] = *session.AppPlatform
+		}
+		if session.AppVersion != nil {
+			key[1] = *session.AppVersion
+		}
+		count, ok := byVersion[key]
+		if !ok {
+			count = &models.AppVersionSessions{Platform: key[0], Version: key[1]}
+			byVersion[key] = count
+		}
+		count.Sessions++
+	}
+
+	counts := make([]*models.AppVersionSessions, 0, len(byVersion))
+	for _, count := range byVersion {
+		counts = append(counts, count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		a, b := counts[i], counts[j]
+		if a.Platform != b.Platform {
+			return a.Platform < b.Platform
+		}
+		if a.Sessions != b.Sessions {
+			return a.Sessions > b.Sessions
+		}
+		return a.Version < b.Version
+	})
+	return counts, nil
+}
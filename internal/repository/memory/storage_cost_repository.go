@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// StorageCostRepositoryImpl implements the StorageCostRepository interface in memory
+type StorageCostRepositoryImpl struct {
+	store *Store
+}
+
+// NewStorageCostRepository creates a new instance of StorageCostRepositoryImpl
+func NewStorageCostRepository(store *Store) repository.StorageCostRepository {
+	return &StorageCostRepositoryImpl{store: store}
+}
+
+// storageRow is the time of a row of an observability table and the row itself
+type storageRow struct {
+	at  time.Time
+	row any
+}
+
+// storageRows returns every row of an observability table with its time; callers must hold the
+// lock
+func (r *StorageCostRepositoryImpl) storageRows(table string) ([]storageRow, error) {
+	var rows []storageRow
+	switch table {
+	case "event_logs":
+		for _, row := range r.store.eventLogs {
+			rows = append(rows, storageRow{row.Timestamp, row})
+		}
+	case "actor_messages":
+		for _, row := range r.store.actorMessages {
+			rows = append(rows, storageRow{row.SentAt, row})
+		}
+	case "distributed_traces":
+		for _, row := range r.store.traces {
+			rows = append(rows, storageRow{row.StartTime, row})
+		}
+	case "traditional_logs":
+		for _, row := range r.store.traditionalLogs {
+			rows = append(rows, storageRow{row.Timestamp, row})
+		}
+	case "traditional_metrics":
+		for _, row := range r.store.traditionalMetrics {
+			rows = append(rows, storageRow{row.Timestamp, row})
+		}
+	default:
+		return nil, fmt.Errorf("table %s is not a storage cost table", table)
+	}
+	return rows, nil
+}
+
+// storageBucket returns the bucket of a time in [start, end), false outside of it
+func storageBucket(at, start, end time.Time, bucket time.Duration) (int, bool) {
+	if at.Before(start) || !at.Before(end) {
+		return 0, false
+	}
+	return int(at.Sub(start) / bucket), true
+}
+
+// TableUsage returns the rows of an observability table in [start, end) and the bytes they take
+// up per bucket. The bytes are the size of the rows encoded as JSON.
+func (r *StorageCostRepositoryImpl) TableUsage(ctx context.Context, table string, start, end time.Time, bucket time.Duration) ([]*models.StorageUsage, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rows, err := r.storageRows(table)
+	if err != nil {
+		return nil, err
+	}
+
+	byBucket := make(map[int]*models.StorageUsage)
+	for _, row := range rows {
+		index, ok := storageBucket(row.at, start, end, bucket)
+		if !ok {
+			continue
+		}
+		encoded, err := json.Marshal(row.row)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get storage usage of %s: %w", table, err)
+		}
+		usage, ok := byBucket[index]
+		if !ok {
+			usage = &models.StorageUsage{TableName: table, Bucket: index}
+			byBucket[index] = usage
+		}
+		usage.Rows++
+		usage.Bytes += int64(len(encoded))
+	}
+
+	usage := make([]*models.StorageUsage, 0, len(byBucket))
+	for _, u := range byBucket {
+		usage = append(usage, u)
+	}
+	sort.Slice(usage, func(i, j int) bool { return usage[i].Bucket < usage[j].Bucket })
+	return usage, nil
+}
+
+// RideRequests returns the ride requests made in [start, end) per bucket and mode
+func (r *StorageCostRepositoryImpl) RideRequests(ctx context.Context, eventType string, start, end time.Time, bucket time.Duration) ([]*models.StorageRequests, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	type key struct {
+		bucket int
+		mode   string
+	}
+	counts := make(map[key]int64)
+	for _, event := range r.store.eventLogs {
+		if event.EventType != eventType {
+			continue
+		}
+		index, ok := storageBucket(event.Timestamp, start, end, bucket)
+		if !ok {
+			continue
+		}
+		var data struct {
+			FromStatus string `json:"from_status"`
+			Mode       string `json:"mode"`
+		}
+		if json.Unmarshal(event.EventData, &data) != nil || data.FromStatus != "none" {
+			continue
+		}
+		counts[key{index, data.Mode}]++
+	}
+
+	requests := make([]*models.StorageRequests, 0, len(counts))
+	for k, n := range counts {
+		requests = append(requests, &models.StorageRequests{Bucket: k.bucket, Mode: k.mode, Requests: n})
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		if requests[i].Bucket != requests[j].Bucket {
+			return requests[i].Bucket < requests[j].Bucket
+		}
+		return requests[i].Mode < requests[j].Mode
+	})
+	return requests, nil
+}
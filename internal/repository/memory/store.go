@@ -0,0 +1,284 @@
+package memory
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/models"
+)
+
+// Store holds all in-memory tables. Repositories created from the same store share
+// data, so foreign-key style checks (e.g. a driver's user must exist) behave like PostgreSQL.
+type Store struct {
+	mu sync.RWMutex
+
+	users      map[string]*models.User
+	drivers    map[string]*models.Driver
+	passengers map[string]*models.Passenger
+	trips      map[string]*models.Trip
+	// tripCompletions is keyed by trip ID
+	tripCompletions map[string]*models.TripCompletion
+	// pickupWaits is keyed by trip ID
+	pickupWaits map[string]*models.PickupWait
+	// tripRematches is keyed by trip ID, first re-match first
+	tripRematches map[string][]*models.TripRematch
+
+	savedLocations map[string]*models.SavedLocation
+
+	webhookSubscriptions map[string]*models.WebhookSubscription
+	webhookDeliveries    map[string]*models.WebhookDelivery
+
+	sessions map[string]*models.Session
+
+	fareDisputes map[string]*models.FareDispute
+	// fareAdjustments is the append-only fare adjustments ledger, oldest first
+	fareAdjustments []*models.FareAdjustment
+
+	incentiveCampaigns map[string]*models.IncentiveCampaign
+	// incentiveCredits, incentiveProgress and incentivePayouts are keyed by campaign and trip or driver ID
+	incentiveCredits  map[string]*models.IncentiveTripCredit
+	incentiveProgress map[string]*models.QuestProgress
+	incentivePayouts  map[string]*models.IncentivePayout
+
+	fleets map[string]*models.Fleet
+
+	corporateAccounts map[string]*models.CorporateAccount
+	// corporateCharges is keyed by trip ID; TripStatus and FareAmount are filled in when listed
+	corporateCharges map[string]*models.CorporateTripCharge
+
+	invoices map[string]*models.Invoice
+
+	etaPredictions map[string]*models.ETAPrediction // by trip ID
+
+	chatMessages    map[string]*models.TripChatMessage
+	safetyIncidents map[string]*models.SafetyIncident
+	fraudSignals    map[string]*models.FraudSignal
+
+	// tripPayments is the append-only trip payments ledger, oldest first
+	tripPayments         []*models.TripPayment
+	paymentDiscrepancies map[string]*models.PaymentDiscrepancy
+
+	driverOnboardings map[string]*models.DriverOnboarding // by driver ID
+	driverTripStats   map[string]*models.DriverTripStats  // by driver ID
+	// driverDailyEarnings is keyed by driver ID and day (YYYY-MM-DD)
+	driverDailyEarnings map[string]float64
+
+	// apiUsage is keyed by key ID and interval start (RFC3339)
+	apiUsage map[string]*models.APIUsageRollup
+
+	// dashboardHourlyTrips and dashboardMatchingTimes are keyed by hour (RFC3339), dashboardZoneSupply by zone
+	dashboardHourlyTrips   map[string]*models.HourlyTripSummary
+	dashboardMatchingTimes map[string]*models.MatchingTimeSummary
+	dashboardZoneSupply    map[string]*models.ZoneSupplySummary
+	dashboardRefreshes     map[string]*models.DashboardRefresh
+
+	// driverStatusHistory mirrors the driver_status_history table kept by a trigger in PostgreSQL
+	driverStatusHistory []driverStatusChange
+	// driverDestinations keeps every destination mode activation, oldest first
+	driverDestinations []*models.DriverDestination
+	// driverRests keeps every rest drivers were sent on after reaching a fatigue limit
+	driverRests []*models.DriverRest
+	// driverLocations keeps the location trail of every driver in the order pings were recorded
+	driverLocations []*models.DriverLocationPing
+
+	actorInstances map[string]*models.ActorInstance
+	actorMessages  map[string]*models.ActorMessage
+	systemMetrics  map[string]*models.SystemMetric
+	traces         map[string]*models.DistributedTrace
+	eventLogs      map[string]*models.EventLog
+	integrityRuns  map[string]*models.IntegrityRun
+	// integrityChecksums keeps the checksums of each integrity run by run ID
+	integrityChecksums map[string][]*models.IntegrityChecksum
+
+	traditionalMetrics map[string]*models.TraditionalMetric
+	traditionalLogs    map[string]*models.TraditionalLog
+	serviceHealth      map[string]*models.ServiceHealth
+}
+
+// NewStore creates an empty in-memory store
+func NewStore() *Store {
+	s := &Store{}
+	s.Reset()
+	return s
+}
+
+// Reset removes all data from the store
+func (s *Store) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users = make(map[string]*models.User)
+	s.drivers = make(map[string]*models.Driver)
+	s.passengers = make(map[string]*models.Passenger)
+	s.trips = make(map[string]*models.Trip)
+	s.tripCompletions = make(map[string]*models.TripCompletion)
+	s.pickupWaits = make(map[string]*models.PickupWait)
+	s.tripRematches = make(map[string][]*models.TripRematch)
+	s.savedLocations = make(map[string]*models.SavedLocation)
+	s.webhookSubscriptions = make(map[string]*models.WebhookSubscription)
+	s.webhookDeliveries = make(map[string]*models.WebhookDelivery)
+	s.sessions = make(map[string]*models.Session)
+	s.fareDisputes = make(map[string]*models.FareDispute)
+	s.fareAdjustments = nil
+	s.incentiveCampaigns = make(map[string]*models.IncentiveCampaign)
+	s.incentiveCredits = make(map[string]*models.IncentiveTripCredit)
+	s.incentiveProgress = make(map[string]*models.QuestProgress)
+	s.incentivePayouts = make(map[string]*models.IncentivePayout)
+	s.fleets = make(map[string]*models.Fleet)
+	s.corporateAccounts = make(map[string]*models.CorporateAccount)
+	s.corporateCharges = make(map[string]*models.CorporateTripCharge)
+	s.invoices = make(map[string]*models.Invoice)
+	s.etaPredictions = make(map[string]*models.ETAPrediction)
+	s.chatMessages = make(map[string]*models.TripChatMessage)
+	s.safetyIncidents = make(map[string]*models.SafetyIncident)
+	s.fraudSignals = make(map[string]*models.FraudSignal)
+	s.tripPayments = nil
+	s.paymentDiscrepancies = make(map[string]*models.PaymentDiscrepancy)
+	s.driverOnboardings = make(map[string]*models.DriverOnboarding)
+	s.driverTripStats = make(map[string]*models.DriverTripStats)
+	s.driverDailyEarnings = make(map[string]float64)
+	s.apiUsage = make(map[string]*models.APIUsageRollup)
+	s.dashboardHourlyTrips = make(map[string]*models.HourlyTripSummary)
+	s.dashboardMatchingTimes = make(map[string]*models.MatchingTimeSummary)
+	s.dashboardZoneSupply = make(map[string]*models.ZoneSupplySummary)
+	s.dashboardRefreshes = make(map[string]*models.DashboardRefresh)
+	s.driverStatusHistory = nil
+	s.driverDestinations = nil
+	s.driverRests = nil
+	s.driverLocations = nil
+
+	s.actorInstances = make(map[string]*models.ActorInstance)
+	s.actorMessages = make(map[string]*models.ActorMessage)
+	s.systemMetrics = make(map[string]*models.SystemMetric)
+	s.traces = make(map[string]*models.DistributedTrace)
+	s.eventLogs = make(map[string]*models.EventLog)
+	s.integrityRuns = make(map[string]*models.IntegrityRun)
+	s.integrityChecksums = make(map[string][]*models.IntegrityChecksum)
+
+	s.traditionalMetrics = make(map[string]*models.TraditionalMetric)
+	s.traditionalLogs = make(map[string]*models.TraditionalLog)
+	s.serviceHealth = make(map[string]*models.ServiceHealth)
+}
+
+// driverStatusChange is a row of the driver status history
+type driverStatusChange struct {
+	driverID  string
+	status    models.DriverStatus
+	changedAt time.Time
+}
+
+// recordDriverStatus appends a status change; callers must hold the write lock
+func (s *Store) recordDriverStatus(driverID string, status models.DriverStatus, changedAt time.Time) {
+	s.driverStatusHistory = append(s.driverStatusHistory, driverStatusChange{
+		driverID:  driverID,
+		status:    status,
+		changedAt: changedAt,
+	})
+}
+
+// tripFrozen reports whether a trip has a safety incident awaiting review; callers must hold the lock
+func (s *Store) tripFrozen(tripID string) bool {
+	for _, incident := range s.safetyIncidents {
+		if incident.TripID.String() == tripID && incident.Status.IsActive() {
+			return true
+		}
+	}
+	return false
+}
+
+// rollUpDriverTrip adds a completed trip to the trip stats and daily earnings of its driver;
+// callers must hold the write lock
+func (s *Store) rollUpDriverTrip(completion *models.TripCompletion) {
+	driverID := completion.DriverID.String()
+	stats, ok := s.driverTripStats[driverID]
+	if !ok {
+		stats = &models.DriverTripStats{DriverID: completion.DriverID}
+		s.driverTripStats[driverID] = stats
+	}
+	stats.TotalTrips++
+	stats.TotalDistanceKm += completion.BilledDistanceKm
+	if stats.LastTripAt == nil || completion.CreatedAt.After(*stats.LastTripAt) {
+		completedAt := completion.CreatedAt
+		stats.LastTripAt = &completedAt
+	}
+
+	s.driverDailyEarnings[driverID+"|"+completion.CreatedAt.UTC().Format(time.DateOnly)] += completion.FinalFare
+}
+
+// selectRows copies the rows matching keep, sorts them with less and applies LIMIT/OFFSET
+// semantics. A nil slice is returned when nothing matches, like the PostgreSQL repositories.
+func selectRows[T any](table map[string]*T, keep func(*T) bool, less func(a, b *T) bool, limit, offset int) []*T {
+	var rows []*T
+	for _, row := range table {
+		if keep == nil || keep(row) {
+			copied := *row
+			rows = append(rows, &copied)
+		}
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool { return less(rows[i], rows[j]) })
+
+	if offset > 0 {
+		if offset >= len(rows) {
+			return nil
+		}
+		rows = rows[offset:]
+	}
+	if limit >= 0 && limit < len(rows) {
+		rows = rows[:limit]
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return rows
+}
+
+// jsonFields returns a lookup of the fields of record, a pointer to a model, by JSON name. The
+// PostgreSQL repositories name columns like the JSON fields, so filters match the same fields.
+func jsonFields(record interface{}) func(string) interface{} {
+	v := reflect.ValueOf(record).Elem()
+	t := v.Type()
+	return func(name string) interface{} {
+		for i := 0; i < t.NumField(); i++ {
+			if tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); tag == name {
+				return v.Field(i).Interface()
+			}
+		}
+		return nil
+	}
+}
+
+// noLimit disables pagination in selectRows for queries without LIMIT
+const noLimit = -1
+
+// newestFirst orders rows by timestamp descending, breaking ties by ID for stable results
+func newestFirst(a, b time.Time, idA, idB fmt.Stringer) bool {
+	if !a.Equal(b) {
+		return a.After(b)
+	}
+	return idA.String() < idB.String()
+}
+
+// parseTimeRange parses RFC3339 range bounds the same way the PostgreSQL repositories do
+func parseTimeRange(startTime, endTime string) (time.Time, time.Time, error) {
+	start, err := time.Parse(time.RFC3339, startTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid start time format: %w", err)
+	}
+
+	end, err := time.Parse(time.RFC3339, endTime)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid end time format: %w", err)
+	}
+
+	return start, end, nil
+}
+
+// inRange reports whether t lies within [start, end]
+func inRange(t, start, end time.Time) bool {
+	return !t.Before(start) && !t.After(end)
+}
@@ -0,0 +1,186 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// TraditionalRepositoryImpl implements the TraditionalRepository interface in memory
+type TraditionalRepositoryImpl struct {
+	store *Store
+}
+
+// NewTraditionalRepository creates a new instance of TraditionalRepositoryImpl
+func NewTraditionalRepository(store *Store) repository.TraditionalRepository {
+	return &TraditionalRepositoryImpl{store: store}
+}
+
+// Traditional Metrics methods
+
+// CreateTraditionalMetric creates a new traditional metric record
+func (r *TraditionalRepositoryImpl) CreateTraditionalMetric(ctx context.Context, metric *models.TraditionalMetric) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.traditionalMetrics[metric.ID.String()]; exists {
+		return fmt.Errorf("failed to create traditional metric: %w", models.ErrDuplicateEntry)
+	}
+
+	copied := *metric
+	r.store.traditionalMetrics[metric.ID.String()] = &copied
+	return nil
+}
+
+// GetTraditionalMetric retrieves a traditional metric by ID
+func (r *TraditionalRepositoryImpl) GetTraditionalMetric(ctx context.Context, id string) (*models.TraditionalMetric, error) {
+	return getByID(r.store, r.store.traditionalMetrics, "traditional_metric", id)
+}
+
+// ListTraditionalMetrics retrieves traditional metrics, optionally filtered by metric type and service name
+func (r *TraditionalRepositoryImpl) ListTraditionalMetrics(ctx context.Context, metricType, serviceName string, limit, offset int) ([]*models.TraditionalMetric, error) {
+	return r.selectMetrics(func(m *models.TraditionalMetric) bool {
+		return (metricType == "" || string(m.MetricType) == metricType) &&
+			(serviceName == "" || m.ServiceName == serviceName)
+	}, limit, offset), nil
+}
+
+// GetTraditionalMetricsByTimeRange retrieves traditional metrics within a time range
+func (r *TraditionalRepositoryImpl) GetTraditionalMetricsByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.TraditionalMetric, error) {
+	start, end, err := parseTimeRange(startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.selectMetrics(func(m *models.TraditionalMetric) bool {
+		return inRange(m.Timestamp, start, end)
+	}, limit, offset), nil
+}
+
+// Traditional Logs methods
+
+// CreateTraditionalLog creates a new traditional log record
+func (r *TraditionalRepositoryImpl) CreateTraditionalLog(ctx context.Context, log *models.TraditionalLog) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.traditionalLogs[log.ID.String()]; exists {
+		return fmt.Errorf("failed to create traditional log: %w", models.ErrDuplicateEntry)
+	}
+
+	copied := *log
+	r.store.traditionalLogs[log.ID.String()] = &copied
+	return nil
+}
+
+// GetTraditionalLog retrieves a traditional log by ID
+func (r *TraditionalRepositoryImpl) GetTraditionalLog(ctx context.Context, id string) (*models.TraditionalLog, error) {
+	return getByID(r.store, r.store.traditionalLogs, "traditional_log", id)
+}
+
+// ListTraditionalLogs retrieves traditional logs, optionally filtered by level and service name
+func (r *TraditionalRepositoryImpl) ListTraditionalLogs(ctx context.Context, level, serviceName string, limit, offset int) ([]*models.TraditionalLog, error) {
+	return r.selectLogs(func(l *models.TraditionalLog) bool {
+		return (level == "" || string(l.Level) == level) &&
+			(serviceName == "" || l.ServiceName == serviceName)
+	}, limit, offset), nil
+}
+
+// GetTraditionalLogsByTimeRange retrieves traditional logs within a time range
+func (r *TraditionalRepositoryImpl) GetTraditionalLogsByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.TraditionalLog, error) {
+	start, end, err := parseTimeRange(startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.selectLogs(func(l *models.TraditionalLog) bool {
+		return inRange(l.Timestamp, start, end)
+	}, limit, offset), nil
+}
+
+// Service Health methods
+
+// CreateServiceHealth creates a new service health record
+func (r *TraditionalRepositoryImpl) CreateServiceHealth(ctx context.Context, health *models.ServiceHealth) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.serviceHealth[health.ID.String()]; exists {
+		return fmt.Errorf("failed to create service health: %w", models.ErrDuplicateEntry)
+	}
+
+	copied := *health
+	r.store.serviceHealth[health.ID.String()] = &copied
+	return nil
+}
+
+// GetServiceHealth retrieves a service health record by ID
+func (r *TraditionalRepositoryImpl) GetServiceHealth(ctx context.Context, id string) (*models.ServiceHealth, error) {
+	return getByID(r.store, r.store.serviceHealth, "service_health", id)
+}
+
+// GetLatestServiceHealth retrieves the latest health status for a service
+func (r *TraditionalRepositoryImpl) GetLatestServiceHealth(ctx context.Context, serviceName string) (*models.ServiceHealth, error) {
+	records := r.selectHealth(func(h *models.ServiceHealth) bool {
+		return h.ServiceName == serviceName
+	}, 1, 0)
+
+	if len(records) == 0 {
+		return nil, &models.NotFoundError{
+			Resource: "service_health",
+			ID:       serviceName,
+		}
+	}
+
+	return records[0], nil
+}
+
+// ListServiceHealth retrieves service health records, optionally filtered by service name
+func (r *TraditionalRepositoryImpl) ListServiceHealth(ctx context.Context, serviceName string, limit, offset int) ([]*models.ServiceHealth, error) {
+	return r.selectHealth(func(h *models.ServiceHealth) bool {
+		return serviceName == "" || h.ServiceName == serviceName
+	}, limit, offset), nil
+}
+
+// GetServiceHealthByTimeRange retrieves service health records within a time range
+func (r *TraditionalRepositoryImpl) GetServiceHealthByTimeRange(ctx context.Context, serviceName, startTime, endTime string, limit, offset int) ([]*models.ServiceHealth, error) {
+	start, end, err := parseTimeRange(startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.selectHealth(func(h *models.ServiceHealth) bool {
+		return (serviceName == "" || h.ServiceName == serviceName) && inRange(h.Timestamp, start, end)
+	}, limit, offset), nil
+}
+
+// Helper methods
+
+func (r *TraditionalRepositoryImpl) selectMetrics(keep func(*models.TraditionalMetric) bool, limit, offset int) []*models.TraditionalMetric {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.traditionalMetrics, keep, func(a, b *models.TraditionalMetric) bool {
+		return newestFirst(a.Timestamp, b.Timestamp, a.ID, b.ID)
+	}, limit, offset)
+}
+
+func (r *TraditionalRepositoryImpl) selectLogs(keep func(*models.TraditionalLog) bool, limit, offset int) []*models.TraditionalLog {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.traditionalLogs, keep, func(a, b *models.TraditionalLog) bool {
+		return newestFirst(a.Timestamp, b.Timestamp, a.ID, b.ID)
+	}, limit, offset)
+}
+
+func (r *TraditionalRepositoryImpl) selectHealth(keep func(*models.ServiceHealth) bool, limit, offset int) []*models.ServiceHealth {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.serviceHealth, keep, func(a, b *models.ServiceHealth) bool {
+		return newestFirst(a.Timestamp, b.Timestamp, a.ID, b.ID)
+	}, limit, offset)
+}
@@ -0,0 +1,808 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// activeTripStatuses matches the statuses GetActiveTrips selects in the PostgreSQL repository
+var activeTripStatuses = map[models.TripStatus]bool{
+	models.TripStatusRequested: true,
+	models.TripStatusMatched:   true,
+	"started":                  true,
+}
+
+// TripRepositoryImpl implements the TripRepository interface in memory
+type TripRepositoryImpl struct {
+	store *Store
+}
+
+// NewTripRepository creates a new instance of TripRepositoryImpl
+func NewTripRepository(store *Store) repository.TripRepository {
+	return &TripRepositoryImpl{store: store}
+}
+
+// Create creates a new trip
+func (r *TripRepositoryImpl) Create(ctx context.Context, trip *models.Trip) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.trips[trip.ID.String()]; exists {
+		return fmt.Errorf("failed to create trip: %w", models.ErrDuplicateEntry)
+	}
+	if err := r.checkReferences(trip); err != nil {
+		return err
+	}
+
+	copied := *trip
+	copied.Passenger = nil
+	copied.Driver = nil
+	r.store.trips[trip.ID.String()] = &copied
+	return nil
+}
+
+// GetByID retrieves a trip by ID
+func (r *TripRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Trip, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	trip, ok := r.store.trips[id]
+	if !ok {
+		return nil, &models.NotFoundError{
+			Resource: "trip",
+			ID:       id,
+		}
+	}
+
+	copied := *trip
+	return &copied, nil
+}
+
+// Update updates an existing trip
+func (r *TripRepositoryImpl) Update(ctx context.Context, trip *models.Trip) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.trips[trip.ID.String()]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "trip",
+			ID:       trip.ID.String(),
+		}
+	}
+	if err := r.checkReferences(trip); err != nil {
+		return err
+	}
+
+	copied := *trip
+	copied.Passenger = nil
+	copied.Driver = nil
+	copied.CreatedAt = existing.CreatedAt
+	r.store.trips[trip.ID.String()] = &copied
+	return nil
+}
+
+// Delete deletes a trip by ID
+func (r *TripRepositoryImpl) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.trips[id]; !ok {
+		return &models.NotFoundError{
+			Resource: "trip",
+			ID:       id,
+		}
+	}
+	// Safety incidents restrict deleting their trip like the foreign key in PostgreSQL
+	for _, incident := range r.store.safetyIncidents {
+		if incident.TripID.String() == id {
+			return models.ErrTripFrozen
+		}
+	}
+
+	delete(r.store.trips, id)
+	// Chat messages cascade with the trip like the foreign key in PostgreSQL
+	for msgID, m := range r.store.chatMessages {
+		if m.TripID.String() == id {
+			delete(r.store.chatMessages, msgID)
+		}
+	}
+	delete(r.store.corporateCharges, id)
+	delete(r.store.tripCompletions, id)
+	delete(r.store.pickupWaits, id)
+	delete(r.store.tripRematches, id)
+	return nil
+}
+
+// GetByPassengerID retrieves trips by passenger ID with pagination
+func (r *TripRepositoryImpl) GetByPassengerID(ctx context.Context, passengerID string, limit, offset int) ([]*models.Trip, error) {
+	return r.selectTrips(func(t *models.Trip) bool {
+		return t.PassengerID.String() == passengerID
+	}, limit, offset), nil
+}
+
+// GetByDriverID retrieves trips by driver ID with pagination
+func (r *TripRepositoryImpl) GetByDriverID(ctx context.Context, driverID string, limit, offset int) ([]*models.Trip, error) {
+	return r.selectTrips(func(t *models.Trip) bool {
+		return t.DriverID != nil && t.DriverID.String() == driverID
+	}, limit, offset), nil
+}
+
+// GetActiveTrips retrieves all active trips
+func (r *TripRepositoryImpl) GetActiveTrips(ctx context.Context) ([]*models.Trip, error) {
+	return r.selectTrips(func(t *models.Trip) bool {
+		return activeTripStatuses[t.Status]
+	}, noLimit, 0), nil
+}
+
+// GetTripsByStatus retrieves trips by status with pagination
+func (r *TripRepositoryImpl) GetTripsByStatus(ctx context.Context, status models.TripStatus, limit, offset int) ([]*models.Trip, error) {
+	return r.selectTrips(func(t *models.Trip) bool {
+		return t.Status == status
+	}, limit, offset), nil
+}
+
+// CountByStatus counts the trips with a status
+func (r *TripRepositoryImpl) CountByStatus(ctx context.Context, status models.TripStatus) (int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var count int64
+	for _, t := range r.store.trips {
+		if t.Status == status {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// GetTripsByDateRange retrieves trips created within a date range (YYYY-MM-DD, end date inclusive)
+func (r *TripRepositoryImpl) GetTripsByDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*models.Trip, error) {
+	startTime, err := time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date format: %w", err)
+	}
+
+	endTime, err := time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date format: %w", err)
+	}
+
+	// Add 24 hours to end date to include the entire day
+	endTime = endTime.Add(24 * time.Hour)
+
+	return r.selectTrips(func(t *models.Trip) bool {
+		return !t.CreatedAt.Before(startTime) && t.CreatedAt.Before(endTime)
+	}, limit, offset), nil
+}
+
+// List retrieves a list of trips with pagination
+func (r *TripRepositoryImpl) List(ctx context.Context, limit, offset int) ([]*models.Trip, error) {
+	return r.selectTrips(nil, limit, offset), nil
+}
+
+// GetRecentDestinations retrieves a passenger's distinct destinations, most recently visited first.
+// Cancelled trips are ignored.
+func (r *TripRepositoryImpl) GetRecentDestinations(ctx context.Context, passengerID string, limit int) ([]*models.RecentDestination, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	destinations := make(map[string]*models.RecentDestination)
+	for _, t := range r.store.trips {
+		if t.PassengerID.String() != passengerID || t.Status == models.TripStatusCancelled {
+			continue
+		}
+
+		key := fmt.Sprintf("%v,%v", t.DestinationLatitude, t.DestinationLongitude)
+		d, ok := destinations[key]
+		if !ok {
+			d = &models.RecentDestination{
+				Latitude:  t.DestinationLatitude,
+				Longitude: t.DestinationLongitude,
+			}
+			destinations[key] = d
+		}
+
+		d.TripCount++
+		if !ok || t.RequestedAt.After(d.LastVisitedAt) {
+			d.Address = t.DestinationAddress
+			d.LastVisitedAt = t.RequestedAt
+		}
+	}
+
+	return selectRows(destinations, nil, func(a, b *models.RecentDestination) bool {
+		return a.LastVisitedAt.After(b.LastVisitedAt)
+	}, limit, 0), nil
+}
+
+// GetRequestTimes retrieves the request times of the trips requested in [start, end), oldest first,
+// optionally only those picked up within bounds
+func (r *TripRepositoryImpl) GetRequestTimes(ctx context.Context, start, end time.Time, bounds *models.GeoBounds) ([]time.Time, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var times []time.Time
+	for _, t := range r.store.trips {
+		if t.RequestedAt.Before(start) || !t.RequestedAt.Before(end) {
+			continue
+		}
+		if bounds != nil && (t.PickupLatitude < bounds.MinLat || t.PickupLatitude >= bounds.MaxLat ||
+			t.PickupLongitude < bounds.MinLng || t.PickupLongitude >= bounds.MaxLng) {
+			continue
+		}
+		times = append(times, t.RequestedAt)
+	}
+
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	return times, nil
+}
+
+// GetGridCounts counts the pickups of the trips requested and the dropoffs of the trips completed
+// in [start, end) per cell of a grid of cellHeight by cellWidth degrees
+func (r *TripRepositoryImpl) GetGridCounts(ctx context.Context, start, end time.Time, cellHeight, cellWidth float64) ([]*models.TripGridCount, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	type cell struct{ row, col int }
+	counts := make(map[cell]*models.TripGridCount)
+	count := func(lat, lng float64) *models.TripGridCount {
+		c := cell{int(math.Floor((lat + 90) / cellHeight)), int(math.Floor((lng + 180) / cellWidth))}
+		if counts[c] == nil {
+			counts[c] = &models.TripGridCount{Row: c.row, Col: c.col}
+		}
+		return counts[c]
+	}
+	inRange := func(t time.Time) bool { return !t.Before(start) && t.Before(end) }
+
+	for _, t := range r.store.trips {
+		if inRange(t.RequestedAt) {
+			count(t.PickupLatitude, t.PickupLongitude).Pickups++
+		}
+		if t.CompletedAt != nil && inRange(*t.CompletedAt) {
+			count(t.DestinationLatitude, t.DestinationLongitude).Dropoffs++
+		}
+	}
+
+	result := make([]*models.TripGridCount, 0, len(counts))
+	for _, c := range counts {
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// GetDailyCounts counts the trips requested, completed and cancelled and sums the completed fares
+// per day, day i covering [boundaries[i], boundaries[i+1])
+func (r *TripRepositoryImpl) GetDailyCounts(ctx context.Context, boundaries []time.Time) ([]*models.DailyTripCount, error) {
+	if len(boundaries) < 2 {
+		return nil, nil
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	counts := make(map[int]*models.DailyTripCount)
+	count := func(t time.Time) *models.DailyTripCount {
+		if t.Before(boundaries[0]) || !t.Before(boundaries[len(boundaries)-1]) {
+			return nil
+		}
+		day := sort.Search(len(boundaries), func(i int) bool { return boundaries[i].After(t) }) - 1
+		if counts[day] == nil {
+			counts[day] = &models.DailyTripCount{Day: day}
+		}
+		return counts[day]
+	}
+
+	for _, t := range r.store.trips {
+		if c := count(t.RequestedAt); c != nil {
+			c.Requested++
+		}
+		if t.Status == models.TripStatusCompleted && t.CompletedAt != nil {
+			if c := count(*t.CompletedAt); c != nil {
+				c.Completed++
+				if t.FareAmount != nil {
+					c.Revenue += *t.FareAmount
+				}
+			}
+		}
+		if t.CancelledAt != nil {
+			if c := count(*t.CancelledAt); c != nil {
+				c.Cancelled++
+			}
+		}
+	}
+
+	result := make([]*models.DailyTripCount, 0, len(counts))
+	for _, c := range counts {
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Day < result[j].Day })
+	return result, nil
+}
+
+// ListByFleet retrieves the trips of a fleet's drivers requested in [start, end), oldest first
+func (r *TripRepositoryImpl) ListByFleet(ctx context.Context, fleetID string, start, end time.Time) ([]*models.Trip, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.trips, func(t *models.Trip) bool {
+		if t.DriverID == nil || t.RequestedAt.Before(start) || !t.RequestedAt.Before(end) {
+			return false
+		}
+		driver, ok := r.store.drivers[t.DriverID.String()]
+		return ok && driver.FleetID != nil && driver.FleetID.String() == fleetID
+	}, func(a, b *models.Trip) bool {
+		if !a.RequestedAt.Equal(b.RequestedAt) {
+			return a.RequestedAt.Before(b.RequestedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, noLimit, 0), nil
+}
+
+// ListRequested retrieves the trips requested in [start, end), oldest first
+func (r *TripRepositoryImpl) ListRequested(ctx context.Context, start, end time.Time) ([]*models.Trip, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.trips, func(t *models.Trip) bool {
+		return !t.RequestedAt.Before(start) && t.RequestedAt.Before(end)
+	}, func(a, b *models.Trip) bool {
+		if !a.RequestedAt.Equal(b.RequestedAt) {
+			return a.RequestedAt.Before(b.RequestedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, noLimit, 0), nil
+}
+
+// ListCancelled retrieves the trips cancelled in [start, end), oldest first
+func (r *TripRepositoryImpl) ListCancelled(ctx context.Context, start, end time.Time) ([]*models.Trip, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.trips, func(t *models.Trip) bool {
+		return t.CancelledAt != nil && !t.CancelledAt.Before(start) && t.CancelledAt.Before(end)
+	}, func(a, b *models.Trip) bool {
+		if !a.CancelledAt.Equal(*b.CancelledAt) {
+			return a.CancelledAt.Before(*b.CancelledAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, noLimit, 0), nil
+}
+
+// GetDailyCancellations counts the trips cancelled per day and reason, day i covering
+// [boundaries[i], boundaries[i+1])
+func (r *TripRepositoryImpl) GetDailyCancellations(ctx context.Context, boundaries []time.Time) ([]*models.DailyCancellationCount, error) {
+	if len(boundaries) < 2 {
+		return nil, nil
+	}
+
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	type key struct {
+		day    int
+		reason string
+	}
+	counts := make(map[key]*models.DailyCancellationCount)
+	for _, t := range r.store.trips {
+		if t.CancelledAt == nil || t.CancelledAt.Before(boundaries[0]) || !t.CancelledAt.Before(boundaries[len(boundaries)-1]) {
+			continue
+		}
+		k := key{day: sort.Search(len(boundaries), func(i int) bool { return boundaries[i].After(*t.CancelledAt) }) - 1}
+		if t.CancellationReason != nil {
+			k.reason = string(*t.CancellationReason)
+		}
+		if counts[k] == nil {
+			counts[k] = &models.DailyCancellationCount{Day: k.day, Reason: k.reason}
+		}
+		counts[k].Count++
+	}
+
+	result := make([]*models.DailyCancellationCount, 0, len(counts))
+	for _, c := range counts {
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Day != result[j].Day {
+			return result[i].Day < result[j].Day
+		}
+		return result[i].Reason < result[j].Reason
+	})
+	return result, nil
+}
+
+// Complete stores the completed trip and its completion details if the stored trip still has
+// status from
+func (r *TripRepositoryImpl) Complete(ctx context.Context, trip *models.Trip, from models.TripStatus, completion *models.TripCompletion) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.trips[trip.ID.String()]
+	if !ok || existing.Status != from {
+		return models.ErrTripStatusConflict
+	}
+	if _, ok := r.store.tripCompletions[trip.ID.String()]; ok {
+		return models.ErrTripStatusConflict
+	}
+	if _, ok := r.store.drivers[completion.DriverID.String()]; !ok {
+		return &models.ValidationError{
+			Field:   "driver_id",
+			Message: "driver does not exist",
+		}
+	}
+
+	updated := *existing
+	updated.Status = trip.Status
+	updated.FareAmount = trip.FareAmount
+	updated.DistanceKm = trip.DistanceKm
+	updated.DurationMinutes = trip.DurationMinutes
+	updated.CompletedAt = trip.CompletedAt
+	updated.UpdatedAt = trip.UpdatedAt
+	r.store.trips[trip.ID.String()] = &updated
+
+	copied := *completion
+	r.store.tripCompletions[trip.ID.String()] = &copied
+	r.store.rollUpDriverTrip(completion)
+	return nil
+}
+
+// GetCompletion retrieves the completion details of a trip
+func (r *TripRepositoryImpl) GetCompletion(ctx context.Context, tripID string) (*models.TripCompletion, error) {
+	return getByID(r.store, r.store.tripCompletions, "trip_completion", tripID)
+}
+
+// ListCompletions retrieves the matching completions, oldest first, and the total number of matches
+func (r *TripRepositoryImpl) ListCompletions(ctx context.Context, filter models.TripCompletionFilter) ([]*models.TripCompletion, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	keep := func(c *models.TripCompletion) bool {
+		return filter.Flagged == nil || c.Flagged == *filter.Flagged
+	}
+
+	var total int64
+	for _, c := range r.store.tripCompletions {
+		if keep(c) {
+			total++
+		}
+	}
+
+	return selectRows(r.store.tripCompletions, keep, func(a, b *models.TripCompletion) bool {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return a.TripID.String() < b.TripID.String()
+	}, filter.Limit, filter.Offset), total, nil
+}
+
+// StartPickupWait stores the trip its driver arrived at and starts the wait for its passenger if
+// the stored trip still has status from
+func (r *TripRepositoryImpl) StartPickupWait(ctx context.Context, trip *models.Trip, from models.TripStatus, wait *models.PickupWait) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.trips[trip.ID.String()]
+	if !ok || existing.Status != from {
+		return models.ErrTripStatusConflict
+	}
+	if _, ok := r.store.pickupWaits[trip.ID.String()]; ok {
+		return models.ErrTripStatusConflict
+	}
+	if _, ok := r.store.drivers[wait.DriverID.String()]; !ok {
+		return &models.ValidationError{
+			Field:   "driver_id",
+			Message: "driver does not exist",
+		}
+	}
+
+	updated := *existing
+	updated.Status = trip.Status
+	updated.UpdatedAt = trip.UpdatedAt
+	r.store.trips[trip.ID.String()] = &updated
+
+	copied := *wait
+	r.store.pickupWaits[trip.ID.String()] = &copied
+	return nil
+}
+
+// EndPickupWait stores the trip whose passenger was picked up or did not show and ends its wait
+// if the stored trip still has status from
+func (r *TripRepositoryImpl) EndPickupWait(ctx context.Context, trip *models.Trip, from models.TripStatus, wait *models.PickupWait) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.trips[trip.ID.String()]
+	if !ok || existing.Status != from {
+		return models.ErrTripStatusConflict
+	}
+	stored, ok := r.store.pickupWaits[trip.ID.String()]
+	if !ok || stored.Outcome != models.PickupWaitWaiting {
+		return models.ErrTripStatusConflict
+	}
+
+	updated := *existing
+	updated.Status = trip.Status
+	updated.FareAmount = trip.FareAmount
+	updated.PickupAt = trip.PickupAt
+	updated.CancelledAt = trip.CancelledAt
+	updated.CancellationReason = trip.CancellationReason
+	updated.CancellationNote = trip.CancellationNote
+	updated.CancellationMode = trip.CancellationMode
+	updated.UpdatedAt = trip.UpdatedAt
+	r.store.trips[trip.ID.String()] = &updated
+
+	ended := *stored
+	ended.Outcome = wait.Outcome
+	ended.EndedAt = wait.EndedAt
+	ended.WaitSeconds = wait.WaitSeconds
+	ended.WaitFee = wait.WaitFee
+	ended.NoShowFee = wait.NoShowFee
+	r.store.pickupWaits[trip.ID.String()] = &ended
+	return nil
+}
+
+// RecordDriverCancellation stores the trip its matched driver cancelled, back to requested or
+// cancelled, and records the re-match if the stored trip still has status from
+func (r *TripRepositoryImpl) RecordDriverCancellation(ctx context.Context, trip *models.Trip, from models.TripStatus, rematch *models.TripRematch) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.trips[trip.ID.String()]
+	if !ok || existing.Status != from {
+		return models.ErrTripStatusConflict
+	}
+	for _, recorded := range r.store.tripRematches[trip.ID.String()] {
+		if recorded.Attempt == rematch.Attempt {
+			return models.ErrTripStatusConflict
+		}
+	}
+	if _, ok := r.store.drivers[rematch.CancelledDriverID.String()]; !ok {
+		return &models.ValidationError{
+			Field:   "cancelled_driver_id",
+			Message: "driver does not exist",
+		}
+	}
+
+	updated := *existing
+	updated.Status = trip.Status
+	updated.DriverID = trip.DriverID
+	updated.MatchedAt = trip.MatchedAt
+	updated.AcceptedAt = trip.AcceptedAt
+	updated.CancelledAt = trip.CancelledAt
+	updated.CancellationReason = trip.CancellationReason
+	updated.CancellationNote = trip.CancellationNote
+	updated.CancellationMode = trip.CancellationMode
+	updated.UpdatedAt = trip.UpdatedAt
+	r.store.trips[trip.ID.String()] = &updated
+
+	copied := *rematch
+	r.store.tripRematches[trip.ID.String()] = append(r.store.tripRematches[trip.ID.String()], &copied)
+	return nil
+}
+
+// EndRematch records how a re-match still matching ended
+func (r *TripRepositoryImpl) EndRematch(ctx context.Context, rematch *models.TripRematch) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	rematches := r.store.tripRematches[rematch.TripID.String()]
+	for i, stored := range rematches {
+		if stored.ID != rematch.ID {
+			continue
+		}
+		if stored.Outcome != models.TripRematchMatching {
+			return models.ErrTripStatusConflict
+		}
+		ended := *stored
+		ended.Outcome = rematch.Outcome
+		ended.MatchedDriverID = rematch.MatchedDriverID
+		ended.EndedAt = rematch.EndedAt
+		ended.AddedWaitSeconds = rematch.AddedWaitSeconds
+		rematches[i] = &ended
+		return nil
+	}
+	return models.ErrTripStatusConflict
+}
+
+// ListRematches retrieves the re-matches of a trip, first first
+func (r *TripRepositoryImpl) ListRematches(ctx context.Context, tripID string) ([]*models.TripRematch, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	rematches := make([]*models.TripRematch, 0, len(r.store.tripRematches[tripID]))
+	for _, rematch := range r.store.tripRematches[tripID] {
+		copied := *rematch
+		rematches = append(rematches, &copied)
+	}
+	return rematches, nil
+}
+
+// GetPickupWait retrieves the driver's wait at the pickup of a trip
+func (r *TripRepositoryImpl) GetPickupWait(ctx context.Context, tripID string) (*models.PickupWait, error) {
+	return getByID(r.store, r.store.pickupWaits, "pickup_wait", tripID)
+}
+
+// GetPickupWaitStats retrieves the wait-time metrics of the arrivals in [from, to) by pickup
+// zone, busiest first
+func (r *TripRepositoryImpl) GetPickupWaitStats(ctx context.Context, from, to time.Time) ([]*models.PickupWaitZoneStats, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	zones := make(map[string]*models.PickupWaitZoneStats)
+	ended := make(map[string]int)
+	for _, w := range r.store.pickupWaits {
+		if w.ArrivedAt.Before(from) || !w.ArrivedAt.Before(to) {
+			continue
+		}
+		stats, ok := zones[w.Zone]
+		if !ok {
+			stats = &models.PickupWaitZoneStats{Zone: w.Zone}
+			zones[w.Zone] = stats
+		}
+		stats.Arrivals++
+		switch w.Outcome {
+		case models.PickupWaitPickedUp:
+			stats.PickedUp++
+		case models.PickupWaitNoShow:
+			stats.NoShows++
+		}
+		if w.WaitSeconds != nil {
+			stats.AvgWaitSeconds += float64(*w.WaitSeconds)
+			ended[w.Zone]++
+			if *w.WaitSeconds > stats.MaxWaitSeconds {
+				stats.MaxWaitSeconds = *w.WaitSeconds
+			}
+		}
+		stats.WaitFees += w.WaitFee
+		stats.NoShowFees += w.NoShowFee
+	}
+
+	result := make([]*models.PickupWaitZoneStats, 0, len(zones))
+	for zone, stats := range zones {
+		if n := ended[zone]; n > 0 {
+			stats.AvgWaitSeconds /= float64(n)
+		}
+		result = append(result, stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Arrivals != result[j].Arrivals {
+			return result[i].Arrivals > result[j].Arrivals
+		}
+		return result[i].Zone < result[j].Zone
+	})
+	return result, nil
+}
+
+// Search retrieves the trips matching the support search filter with their passenger and driver
+// contact details, most recently requested first, and the total number of matches
+func (r *TripRepositoryImpl) Search(ctx context.Context, filter models.TripSearchFilter) ([]*models.TripSearchResult, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	var results []*models.TripSearchResult
+	for _, t := range r.store.trips {
+		passenger, ok := r.store.passengers[t.PassengerID.String()]
+		if !ok {
+			continue
+		}
+		passengerUser, ok := r.store.users[passenger.UserID.String()]
+		if !ok {
+			continue
+		}
+		result := &models.TripSearchResult{
+			PassengerName:  passengerUser.Name,
+			PassengerPhone: passengerUser.Phone,
+			PassengerEmail: passengerUser.Email,
+		}
+		if t.DriverID != nil {
+			if driver, ok := r.store.drivers[t.DriverID.String()]; ok {
+				plate := driver.VehiclePlate
+				result.DriverPlate = &plate
+				if driverUser, ok := r.store.users[driver.UserID.String()]; ok {
+					name := driverUser.Name
+					result.DriverName = &name
+				}
+			}
+		}
+
+		if !matchesTripSearch(filter, t, result) {
+			continue
+		}
+		trip := *t
+		result.Trip = &trip
+		results = append(results, result)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return newestFirst(results[i].RequestedAt, results[j].RequestedAt, results[i].ID, results[j].ID)
+	})
+	total := int64(len(results))
+	if filter.Offset >= len(results) {
+		return nil, total, nil
+	}
+	results = results[filter.Offset:]
+	if filter.Limit >= 0 && filter.Limit < len(results) {
+		results = results[:filter.Limit]
+	}
+	return results, total, nil
+}
+
+// ListStuck retrieves up to limit trips neither completed nor cancelled that were last updated
+// before updatedBefore, least recently updated first
+func (r *TripRepositoryImpl) ListStuck(ctx context.Context, updatedBefore time.Time, limit int) ([]*models.Trip, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.trips, func(t *models.Trip) bool {
+		return t.IsActive() && t.UpdatedAt.Before(updatedBefore)
+	}, func(a, b *models.Trip) bool {
+		if !a.UpdatedAt.Equal(b.UpdatedAt) {
+			return a.UpdatedAt.Before(b.UpdatedAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, limit, 0), nil
+}
+
+// matchesTripSearch reports whether a trip, with its passenger and driver contact details in
+// result, matches every criterion of the filter like the PostgreSQL search does
+func matchesTripSearch(filter models.TripSearchFilter, t *models.Trip, result *models.TripSearchResult) bool {
+	if filter.PassengerPhone != "" && result.PassengerPhone != filter.PassengerPhone {
+		return false
+	}
+	if filter.PassengerEmail != "" && !strings.EqualFold(result.PassengerEmail, filter.PassengerEmail) {
+		return false
+	}
+	if filter.DriverPlate != "" && (result.DriverPlate == nil ||
+		models.NormalizePlate(*result.DriverPlate) != models.NormalizePlate(filter.DriverPlate)) {
+		return false
+	}
+	if filter.TripIDPrefix != "" && !strings.HasPrefix(t.ID.String(), strings.ToLower(filter.TripIDPrefix)) {
+		return false
+	}
+	if filter.RequestedFrom != nil && t.RequestedAt.Before(*filter.RequestedFrom) {
+		return false
+	}
+	if filter.RequestedTo != nil && !t.RequestedAt.Before(*filter.RequestedTo) {
+		return false
+	}
+	if b := filter.PickupBounds; b != nil && (t.PickupLatitude < b.MinLat || t.PickupLatitude >= b.MaxLat ||
+		t.PickupLongitude < b.MinLng || t.PickupLongitude >= b.MaxLng) {
+		return false
+	}
+	return true
+}
+
+// selectTrips returns matching trips ordered by creation time, newest first
+func (r *TripRepositoryImpl) selectTrips(keep func(*models.Trip) bool, limit, offset int) []*models.Trip {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.trips, keep, func(a, b *models.Trip) bool {
+		return newestFirst(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}, limit, offset)
+}
+
+// checkReferences enforces the passenger and driver foreign keys
+func (r *TripRepositoryImpl) checkReferences(trip *models.Trip) error {
+	if _, ok := r.store.passengers[trip.PassengerID.String()]; !ok {
+		return &models.ValidationError{
+			Field:   "passenger_id",
+			Message: "passenger does not exist",
+		}
+	}
+	if trip.DriverID != nil {
+		if _, ok := r.store.drivers[trip.DriverID.String()]; !ok {
+			return &models.ValidationError{
+				Field:   "driver_id",
+				Message: "driver does not exist",
+			}
+		}
+	}
+	return nil
+}
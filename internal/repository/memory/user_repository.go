@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// UserRepositoryImpl implements the UserRepository interface in memory
+type UserRepositoryImpl struct {
+	store *Store
+}
+
+// NewUserRepository creates a new instance of UserRepositoryImpl
+func NewUserRepository(store *Store) repository.UserRepository {
+	return &UserRepositoryImpl{store: store}
+}
+
+// Create creates a new user
+func (r *UserRepositoryImpl) Create(ctx context.Context, user *models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.users[user.ID.String()]; exists {
+		return fmt.Errorf("failed to create user: %w", models.ErrDuplicateEntry)
+	}
+	if err := r.checkUnique(user); err != nil {
+		return err
+	}
+
+	copied := *user
+	r.store.users[user.ID.String()] = &copied
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepositoryImpl) GetByID(ctx context.Context, id string) (*models.User, error) {
+	return r.find(id, func(u *models.User) bool { return u.ID.String() == id })
+}
+
+// GetByEmail retrieves a user by email
+func (r *UserRepositoryImpl) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return r.find(email, func(u *models.User) bool { return u.Email == email })
+}
+
+// GetByPhone retrieves a user by phone number
+func (r *UserRepositoryImpl) GetByPhone(ctx context.Context, phone string) (*models.User, error) {
+	return r.find(phone, func(u *models.User) bool { return u.Phone == phone })
+}
+
+// Update updates an existing user
+func (r *UserRepositoryImpl) Update(ctx context.Context, user *models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.users[user.ID.String()]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "user",
+			ID:       user.ID.String(),
+		}
+	}
+
+	if err := r.checkUnique(user); err != nil {
+		return err
+	}
+
+	existing.Email = user.Email
+	existing.Phone = user.Phone
+	existing.Name = user.Name
+	existing.UserType = user.UserType
+	existing.AvatarKey = user.AvatarKey
+	existing.UpdatedAt = user.UpdatedAt
+	return nil
+}
+
+// Delete deletes a user by ID, cascading to the user's driver and passenger profiles
+func (r *UserRepositoryImpl) Delete(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[id]; !ok {
+		return &models.NotFoundError{
+			Resource: "user",
+			ID:       id,
+		}
+	}
+
+	delete(r.store.users, id)
+	for driverID, driver := range r.store.drivers {
+		if driver.UserID.String() == id {
+			delete(r.store.drivers, driverID)
+		}
+	}
+	for passengerID, passenger := range r.store.passengers {
+		if passenger.UserID.String() == id {
+			delete(r.store.passengers, passengerID)
+		}
+	}
+	for sessionID, session := range r.store.sessions {
+		if session.UserID.String() == id {
+			delete(r.store.sessions, sessionID)
+		}
+	}
+	return nil
+}
+
+// List retrieves a list of users with pagination
+func (r *UserRepositoryImpl) List(ctx context.Context, limit, offset int) ([]*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.users, nil, func(a, b *models.User) bool {
+		return newestFirst(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}, limit, offset), nil
+}
+
+// find returns a copy of the first user matching match
+func (r *UserRepositoryImpl) find(key string, match func(*models.User) bool) (*models.User, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	for _, user := range r.store.users {
+		if match(user) {
+			copied := *user
+			return &copied, nil
+		}
+	}
+
+	return nil, &models.NotFoundError{
+		Resource: "user",
+		ID:       key,
+	}
+}
+
+// checkUnique enforces the unique email and phone constraints
+func (r *UserRepositoryImpl) checkUnique(user *models.User) error {
+	for _, other := range r.store.users {
+		if other.ID == user.ID {
+			continue
+		}
+		if other.Email == user.Email {
+			return &models.ValidationError{
+				Field:   "email",
+				Message: "email already exists",
+			}
+		}
+		if other.Phone == user.Phone {
+			return &models.ValidationError{
+				Field:   "phone",
+				Message: "phone number already exists",
+			}
+		}
+	}
+	return nil
+}
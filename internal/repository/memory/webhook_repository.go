@@ -0,0 +1,185 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// WebhookRepositoryImpl implements the WebhookRepository interface in memory
+type WebhookRepositoryImpl struct {
+	store *Store
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepositoryImpl
+func NewWebhookRepository(store *Store) repository.WebhookRepository {
+	return &WebhookRepositoryImpl{store: store}
+}
+
+// CreateSubscription creates a new webhook subscription
+func (r *WebhookRepositoryImpl) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.webhookSubscriptions[subscription.ID.String()]; exists {
+		return fmt.Errorf("failed to create webhook subscription: %w", models.ErrDuplicateEntry)
+	}
+
+	copied := *subscription
+	r.store.webhookSubscriptions[subscription.ID.String()] = &copied
+	return nil
+}
+
+// GetSubscription retrieves a webhook subscription by ID
+func (r *WebhookRepositoryImpl) GetSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	subscription, ok := r.store.webhookSubscriptions[id]
+	if !ok {
+		return nil, &models.NotFoundError{
+			Resource: "webhook subscription",
+			ID:       id,
+		}
+	}
+
+	copied := *subscription
+	return &copied, nil
+}
+
+// DeleteSubscription deletes a webhook subscription and its deliveries, like ON DELETE CASCADE
+func (r *WebhookRepositoryImpl) DeleteSubscription(ctx context.Context, id string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.webhookSubscriptions[id]; !ok {
+		return &models.NotFoundError{
+			Resource: "webhook subscription",
+			ID:       id,
+		}
+	}
+
+	delete(r.store.webhookSubscriptions, id)
+	for deliveryID, d := range r.store.webhookDeliveries {
+		if d.SubscriptionID.String() == id {
+			delete(r.store.webhookDeliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+// ListSubscriptionsByOwner retrieves the subscriptions registered by an API key, oldest first
+func (r *WebhookRepositoryImpl) ListSubscriptionsByOwner(ctx context.Context, owner string) ([]*models.WebhookSubscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.webhookSubscriptions, func(s *models.WebhookSubscription) bool {
+		return s.Owner == owner
+	}, oldestSubscriptionFirst, noLimit, 0), nil
+}
+
+// ListSubscriptionsForEvent retrieves the active subscriptions that receive the event
+func (r *WebhookRepositoryImpl) ListSubscriptionsForEvent(ctx context.Context, event models.WebhookEvent) ([]*models.WebhookSubscription, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.webhookSubscriptions, func(s *models.WebhookSubscription) bool {
+		return s.Active && s.Events.Contains(event)
+	}, oldestSubscriptionFirst, noLimit, 0), nil
+}
+
+// CreateDelivery creates a new webhook delivery
+func (r *WebhookRepositoryImpl) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, exists := r.store.webhookDeliveries[delivery.ID.String()]; exists {
+		return fmt.Errorf("failed to create webhook delivery: %w", models.ErrDuplicateEntry)
+	}
+	if _, ok := r.store.webhookSubscriptions[delivery.SubscriptionID.String()]; !ok {
+		return &models.ValidationError{
+			Field:   "subscription_id",
+			Message: "webhook subscription does not exist",
+		}
+	}
+
+	copied := *delivery
+	r.store.webhookDeliveries[delivery.ID.String()] = &copied
+	return nil
+}
+
+// UpdateDelivery records the outcome of a delivery attempt
+func (r *WebhookRepositoryImpl) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	existing, ok := r.store.webhookDeliveries[delivery.ID.String()]
+	if !ok {
+		return &models.NotFoundError{
+			Resource: "webhook delivery",
+			ID:       delivery.ID.String(),
+		}
+	}
+
+	existing.Status = delivery.Status
+	existing.Attempts = delivery.Attempts
+	existing.ResponseStatus = delivery.ResponseStatus
+	existing.LastError = delivery.LastError
+	existing.NextAttemptAt = delivery.NextAttemptAt
+	existing.DeliveredAt = delivery.DeliveredAt
+	existing.UpdatedAt = delivery.UpdatedAt
+	return nil
+}
+
+// GetDueDeliveries retrieves pending deliveries whose next attempt is due, oldest first
+func (r *WebhookRepositoryImpl) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	return selectRows(r.store.webhookDeliveries, func(d *models.WebhookDelivery) bool {
+		return d.Status == models.WebhookDeliveryPending && d.NextAttemptAt != nil && !d.NextAttemptAt.After(now)
+	}, func(a, b *models.WebhookDelivery) bool {
+		if !a.NextAttemptAt.Equal(*b.NextAttemptAt) {
+			return a.NextAttemptAt.Before(*b.NextAttemptAt)
+		}
+		return a.ID.String() < b.ID.String()
+	}, limit, 0), nil
+}
+
+// ListDeliveries retrieves the deliveries matching the filter, newest first, with the total number of matches
+func (r *WebhookRepositoryImpl) ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int64, error) {
+	r.store.mu.RLock()
+	defer r.store.mu.RUnlock()
+
+	keep := func(d *models.WebhookDelivery) bool {
+		if filter.SubscriptionID != nil && d.SubscriptionID != *filter.SubscriptionID {
+			return false
+		}
+		if filter.TripID != nil && d.TripID != *filter.TripID {
+			return false
+		}
+		return filter.Status == "" || d.Status == filter.Status
+	}
+
+	var total int64
+	for _, d := range r.store.webhookDeliveries {
+		if keep(d) {
+			total++
+		}
+	}
+
+	return selectRows(r.store.webhookDeliveries, keep, func(a, b *models.WebhookDelivery) bool {
+		return newestFirst(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	}, filter.Limit, filter.Offset), total, nil
+}
+
+// oldestSubscriptionFirst orders subscriptions by creation time, breaking ties by ID
+func oldestSubscriptionFirst(a, b *models.WebhookSubscription) bool {
+	if !a.CreatedAt.Equal(b.CreatedAt) {
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID.String() < b.ID.String()
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const apiUsageRollupColumns = `key_id, tenant_id, bucket_start, requests, client_errors, server_errors, quota_rejections, latency_sum_ms, latency_max_ms`
+
+// APIUsageRepositoryImpl implements the APIUsageRepository interface using PostgreSQL
+type APIUsageRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewAPIUsageRepository creates a new instance of APIUsageRepositoryImpl
+func NewAPIUsageRepository(db *sqlx.DB) repository.APIUsageRepository {
+	return &APIUsageRepositoryImpl{db: db}
+}
+
+// Upsert adds the requests of the rollups to the ones stored for the same key and interval, in
+// one transaction
+func (r *APIUsageRepositoryImpl) Upsert(ctx context.Context, rollups []*models.APIUsageRollup) error {
+	if len(rollups) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		INSERT INTO api_usage_rollups (` + apiUsageRollupColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (key_id, bucket_start) DO UPDATE SET
+			requests = api_usage_rollups.requests + excluded.requests,
+			client_errors = api_usage_rollups.client_errors + excluded.client_errors,
+			server_errors = api_usage_rollups.server_errors + excluded.server_errors,
+			quota_rejections = api_usage_rollups.quota_rejections + excluded.quota_rejections,
+			latency_sum_ms = api_usage_rollups.latency_sum_ms + excluded.latency_sum_ms,
+			latency_max_ms = CASE WHEN excluded.latency_max_ms > api_usage_rollups.latency_max_ms
+				THEN excluded.latency_max_ms ELSE api_usage_rollups.latency_max_ms END
+	`
+	for _, rollup := range rollups {
+		_, err := tx.ExecContext(ctx, query,
+			rollup.KeyID,
+			rollup.TenantID,
+			rollup.BucketStart,
+			rollup.Requests,
+			rollup.ClientErrors,
+			rollup.ServerErrors,
+			rollup.QuotaRejections,
+			rollup.LatencySumMs,
+			rollup.LatencyMaxMs,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to upsert API usage rollup: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// List retrieves the matching rollups, oldest first, then by key
+func (r *APIUsageRepositoryImpl) List(ctx context.Context, filter models.APIUsageFilter) ([]*models.APIUsageRollup, error) {
+	args := []interface{}{filter.From, filter.To}
+	conditions := []string{"bucket_start >= $1", "bucket_start < $2"}
+	if filter.KeyID != "" {
+		args = append(args, filter.KeyID)
+		conditions = append(conditions, fmt.Sprintf("key_id = $%d", len(args)))
+	}
+	if filter.TenantID != nil {
+		args = append(args, *filter.TenantID)
+		conditions = append(conditions, fmt.Sprintf("tenant_id = $%d", len(args)))
+	}
+
+	query := `
+		SELECT ` + apiUsageRollupColumns + `
+		FROM api_usage_rollups
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY bucket_start, key_id
+	`
+
+	var rollups []*models.APIUsageRollup
+	if err := r.db.SelectContext(ctx, &rollups, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list API usage rollups: %w", err)
+	}
+
+	return rollups, nil
+}
+
+// CountRequests returns the requests made with a key in [from, to) that were not rejected for
+// its quota
+func (r *APIUsageRepositoryImpl) CountRequests(ctx context.Context, keyID string, from, to time.Time) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(requests - quota_rejections), 0)
+		FROM api_usage_rollups
+		WHERE key_id = $1 AND bucket_start >= $2 AND bucket_start < $3
+	`
+
+	var count int64
+	if err := r.db.GetContext(ctx, &count, query, keyID, from, to); err != nil {
+		return 0, fmt.Errorf("failed to count API requests: %w", err)
+	}
+
+	return count, nil
+}
+
+// DeleteBefore removes the rollups of the intervals starting before the cutoff
+func (r *APIUsageRepositoryImpl) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM api_usage_rollups WHERE bucket_start < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete API usage rollups: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return deleted, nil
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const chatMessageColumns = `id, trip_id, sender_role, sender_id, body, read_at, created_at`
+
+// ChatRepositoryImpl implements the ChatRepository interface using PostgreSQL
+type ChatRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewChatRepository creates a new instance of ChatRepositoryImpl
+func NewChatRepository(db *sqlx.DB) repository.ChatRepository {
+	return &ChatRepositoryImpl{db: db}
+}
+
+// Create creates a new chat message in the database
+func (r *ChatRepositoryImpl) Create(ctx context.Context, msg *models.TripChatMessage) error {
+	query := `
+		INSERT INTO trip_chat_messages (` + chatMessageColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		msg.ID,
+		msg.TripID,
+		msg.SenderRole,
+		msg.SenderID,
+		msg.Body,
+		msg.ReadAt,
+		msg.CreatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" { // foreign_key_violation
+			return &models.NotFoundError{
+				Resource: "trip",
+				ID:       msg.TripID.String(),
+			}
+		}
+		return fmt.Errorf("failed to create chat message: %w", err)
+	}
+
+	return nil
+}
+
+// ListByTrip retrieves a trip's chat messages, oldest first
+func (r *ChatRepositoryImpl) ListByTrip(ctx context.Context, tripID string, limit, offset int) ([]*models.TripChatMessage, error) {
+	query := `
+		SELECT ` + chatMessageColumns + `
+		FROM trip_chat_messages
+		WHERE trip_id = $1
+		ORDER BY created_at, id
+		LIMIT $2 OFFSET $3
+	`
+
+	var messages []*models.TripChatMessage
+	if err := r.db.SelectContext(ctx, &messages, query, tripID, limit, offset); err != nil {
+		return nil, fmt.Errorf("failed to list chat messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// MarkRead marks the unread messages of a trip sent to reader as read
+func (r *ChatRepositoryImpl) MarkRead(ctx context.Context, tripID string, reader models.ChatSenderRole, at time.Time) (int, error) {
+	query := `
+		UPDATE trip_chat_messages
+		SET read_at = $1
+		WHERE trip_id = $2 AND sender_role = $3 AND read_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query, at, tripID, reader.Recipient())
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark chat messages read: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// CountUnread counts the unread messages of a trip per recipient
+func (r *ChatRepositoryImpl) CountUnread(ctx context.Context, tripID string) (*models.TripChatUnreadCounts, error) {
+	query := `
+		SELECT sender_role, COUNT(*)
+		FROM trip_chat_messages
+		WHERE trip_id = $1 AND read_at IS NULL
+		GROUP BY sender_role
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread chat messages: %w", err)
+	}
+	defer rows.Close()
+
+	counts := &models.TripChatUnreadCounts{}
+	for rows.Next() {
+		var sender models.ChatSenderRole
+		var count int
+		if err := rows.Scan(&sender, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan unread chat messages: %w", err)
+		}
+		// Messages count as unread for their recipient
+		if sender == models.ChatSenderDriver {
+			counts.Passenger = count
+		} else {
+			counts.Driver = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to count unread chat messages: %w", err)
+	}
+
+	return counts, nil
+}
+
+// DeleteForTripsEndedBefore deletes the messages of the trips completed or cancelled before cutoff,
+// except trips frozen by a safety incident awaiting review
+func (r *ChatRepositoryImpl) DeleteForTripsEndedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		DELETE FROM trip_chat_messages
+		WHERE trip_id IN (
+			SELECT id FROM trips
+			WHERE COALESCE(completed_at, cancelled_at) < $1
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM safety_incidents i
+			WHERE i.trip_id = trip_chat_messages.trip_id AND i.status IN ('open', 'under_review')
+		)
+	`
+
+	result, err := r.db.ExecContext(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired chat messages: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected, nil
+}
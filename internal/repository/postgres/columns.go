@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"actor-model-observability/internal/repository"
+)
+
+// selectColumns returns the columns a list query loads: those requested with
+// repository.WithColumns that exist in all, in table order, or all of them
+func selectColumns(ctx context.Context, all []string) []string {
+	requested := repository.ColumnsFromContext(ctx)
+	if len(requested) == 0 {
+		return all
+	}
+
+	wanted := make(map[string]bool, len(requested))
+	for _, c := range requested {
+		wanted[c] = true
+	}
+
+	var columns []string
+	for _, c := range all {
+		if wanted[c] {
+			columns = append(columns, c)
+		}
+	}
+	if len(columns) == 0 {
+		return all
+	}
+	return columns
+}
+
+// selectFrom prefixes the rest of a query with a SELECT of the columns
+func selectFrom(columns []string, rest string) string {
+	return "SELECT " + strings.Join(columns, ", ") + "\n" + rest
+}
+
+// columnTargets returns scan destinations for the columns in dest, a pointer to a model
+// whose JSON field names match its column names
+func columnTargets(dest interface{}, columns []string) []interface{} {
+	v := reflect.ValueOf(dest).Elem()
+	t := v.Type()
+
+	fields := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		fields[name] = i
+	}
+
+	targets := make([]interface{}, len(columns))
+	for i, c := range columns {
+		targets[i] = v.Field(fields[c]).Addr().Interface()
+	}
+	return targets
+}
@@ -0,0 +1,226 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const corporateAccountColumns = `id, name, monthly_spending_limit, created_at, updated_at`
+
+// corporateChargeAmount is what a charge counts towards its account's spend, matching
+// CorporateTripCharge.Amount
+const corporateChargeAmount = `
+	CASE
+		WHEN t.status = 'cancelled' THEN 0
+		WHEN t.status = 'completed' AND t.fare_amount IS NOT NULL THEN t.fare_amount
+		ELSE c.estimated_fare
+	END`
+
+// CorporateAccountRepositoryImpl implements the CorporateAccountRepository interface using PostgreSQL
+type CorporateAccountRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewCorporateAccountRepository creates a new instance of CorporateAccountRepositoryImpl
+func NewCorporateAccountRepository(db *sqlx.DB) repository.CorporateAccountRepository {
+	return &CorporateAccountRepositoryImpl{db: db}
+}
+
+// Create creates a new corporate account in the database
+func (r *CorporateAccountRepositoryImpl) Create(ctx context.Context, account *models.CorporateAccount) error {
+	query := `
+		INSERT INTO corporate_accounts (` + corporateAccountColumns + `)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		account.ID,
+		account.Name,
+		account.MonthlySpendingLimit,
+		account.CreatedAt,
+		account.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return fmt.Errorf("failed to create corporate account: %w", models.ErrDuplicateEntry)
+		}
+		return fmt.Errorf("failed to create corporate account: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a corporate account by ID
+func (r *CorporateAccountRepositoryImpl) GetByID(ctx context.Context, id string) (*models.CorporateAccount, error) {
+	query := `SELECT ` + corporateAccountColumns + ` FROM corporate_accounts WHERE id = $1`
+
+	account := &models.CorporateAccount{}
+	err := r.db.GetContext(ctx, account, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "corporate account",
+				ID:       id,
+			}
+		}
+		return nil, fmt.Errorf("failed to get corporate account by ID: %w", err)
+	}
+
+	return account, nil
+}
+
+// Update updates the name and spending limit of a corporate account
+func (r *CorporateAccountRepositoryImpl) Update(ctx context.Context, account *models.CorporateAccount) error {
+	query := `
+		UPDATE corporate_accounts
+		SET name = $2, monthly_spending_limit = $3, updated_at = $4
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		account.ID,
+		account.Name,
+		account.MonthlySpendingLimit,
+		account.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update corporate account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "corporate account",
+			ID:       account.ID.String(),
+		}
+	}
+
+	return nil
+}
+
+// SetPassengerAccount links a passenger to a corporate account, or unlinks it
+func (r *CorporateAccountRepositoryImpl) SetPassengerAccount(ctx context.Context, passengerID string, accountID *uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE passengers SET corporate_account_id = $2, updated_at = $3 WHERE id = $1`,
+		passengerID, accountID, time.Now())
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" { // foreign_key_violation
+			return &models.NotFoundError{
+				Resource: "corporate account",
+				ID:       accountID.String(),
+			}
+		}
+		return fmt.Errorf("failed to set passenger corporate account: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "passenger",
+			ID:       passengerID,
+		}
+	}
+
+	return nil
+}
+
+// ChargeTrip bills a trip to a corporate account within its spending limit
+func (r *CorporateAccountRepositoryImpl) ChargeTrip(ctx context.Context, charge *models.CorporateTripCharge, limit *float64, periodStart, periodEnd time.Time) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Touching the account row locks it, so concurrent charges are checked one at a time
+	result, err := tx.ExecContext(ctx, `UPDATE corporate_accounts SET updated_at = updated_at WHERE id = $1`, charge.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to lock corporate account: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "corporate account",
+			ID:       charge.AccountID.String(),
+		}
+	}
+
+	if limit != nil {
+		var spend float64
+		err := tx.GetContext(ctx, &spend, `
+			SELECT COALESCE(SUM(`+corporateChargeAmount+`), 0)
+			FROM corporate_trip_charges c
+			JOIN trips t ON t.id = c.trip_id
+			WHERE c.account_id = $1 AND c.created_at >= $2 AND c.created_at < $3
+		`, charge.AccountID, periodStart, periodEnd)
+		if err != nil {
+			return fmt.Errorf("failed to get corporate account spend: %w", err)
+		}
+		if spend+charge.EstimatedFare > *limit {
+			return models.ErrCorporateSpendingLimitExceeded
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO corporate_trip_charges (trip_id, account_id, passenger_id, estimated_fare, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`, charge.TripID, charge.AccountID, charge.PassengerID, charge.EstimatedFare, charge.CreatedAt)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return fmt.Errorf("trip %s already charged: %w", charge.TripID, models.ErrDuplicateEntry)
+		}
+		return fmt.Errorf("failed to charge trip: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit trip charge: %w", err)
+	}
+
+	return nil
+}
+
+// ListCharges retrieves the charges of a corporate account created in [start, end)
+func (r *CorporateAccountRepositoryImpl) ListCharges(ctx context.Context, accountID string, start, end time.Time) ([]*models.CorporateTripCharge, error) {
+	query := `
+		SELECT c.trip_id, c.account_id, c.passenger_id, c.estimated_fare, c.created_at,
+			t.status AS trip_status, t.fare_amount
+		FROM corporate_trip_charges c
+		JOIN trips t ON t.id = c.trip_id
+		WHERE c.account_id = $1 AND c.created_at >= $2 AND c.created_at < $3
+		ORDER BY c.created_at ASC, c.trip_id ASC
+	`
+
+	var charges []*models.CorporateTripCharge
+	if err := r.db.SelectContext(ctx, &charges, query, accountID, start, end); err != nil {
+		return nil, fmt.Errorf("failed to list corporate trip charges: %w", err)
+	}
+
+	return charges, nil
+}
+
+// List retrieves every corporate account, oldest first
+func (r *CorporateAccountRepositoryImpl) List(ctx context.Context) ([]*models.CorporateAccount, error) {
+	query := `SELECT ` + corporateAccountColumns + ` FROM corporate_accounts ORDER BY created_at ASC, id ASC`
+
+	var accounts []*models.CorporateAccount
+	if err := r.db.SelectContext(ctx, &accounts, query); err != nil {
+		return nil, fmt.Errorf("failed to list corporate accounts: %w", err)
+	}
+
+	return accounts, nil
+}
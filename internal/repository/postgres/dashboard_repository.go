@@ -0,0 +1,210 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DashboardRepositoryImpl implements the DashboardRepository interface using PostgreSQL
+type DashboardRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewDashboardRepository creates a new instance of DashboardRepositoryImpl
+func NewDashboardRepository(db *sqlx.DB) repository.DashboardRepository {
+	return &DashboardRepositoryImpl{db: db}
+}
+
+// GetTripTimings retrieves the lifecycle timestamps of the trips requested at or after since
+func (r *DashboardRepositoryImpl) GetTripTimings(ctx context.Context, since time.Time) ([]*models.TripTiming, error) {
+	query := `
+		SELECT requested_at, matched_at, completed_at, cancelled_at
+		FROM trips
+		WHERE requested_at >= $1
+	`
+
+	var timings []*models.TripTiming
+	if err := r.db.SelectContext(ctx, &timings, query, since); err != nil {
+		return nil, fmt.Errorf("failed to get trip timings: %w", err)
+	}
+
+	return timings, nil
+}
+
+// GetDriverPositions retrieves the status and location of every driver with a known location
+func (r *DashboardRepositoryImpl) GetDriverPositions(ctx context.Context) ([]*models.DriverPosition, error) {
+	query := `
+		SELECT status, current_latitude, current_longitude
+		FROM drivers
+		WHERE current_latitude IS NOT NULL AND current_longitude IS NOT NULL
+	`
+
+	var positions []*models.DriverPosition
+	if err := r.db.SelectContext(ctx, &positions, query); err != nil {
+		return nil, fmt.Errorf("failed to get driver positions: %w", err)
+	}
+
+	return positions, nil
+}
+
+// ReplaceHourlyTrips replaces the hourly trip summaries from since onwards and records the refresh
+func (r *DashboardRepositoryImpl) ReplaceHourlyTrips(ctx context.Context, since time.Time, rows []*models.HourlyTripSummary, refresh *models.DashboardRefresh) error {
+	return r.replace(ctx, refresh, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM dashboard_hourly_trips WHERE hour >= $1`, since); err != nil {
+			return fmt.Errorf("failed to clear hourly trip summaries: %w", err)
+		}
+		for _, row := range rows {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO dashboard_hourly_trips (hour, requested, matched, completed, cancelled)
+				VALUES ($1, $2, $3, $4, $5)
+			`, row.Hour, row.Requested, row.Matched, row.Completed, row.Cancelled)
+			if err != nil {
+				return fmt.Errorf("failed to insert hourly trip summary: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ReplaceMatchingTimes replaces the matching time summaries from since onwards and records the refresh
+func (r *DashboardRepositoryImpl) ReplaceMatchingTimes(ctx context.Context, since time.Time, rows []*models.MatchingTimeSummary, refresh *models.DashboardRefresh) error {
+	return r.replace(ctx, refresh, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM dashboard_matching_times WHERE hour >= $1`, since); err != nil {
+			return fmt.Errorf("failed to clear matching time summaries: %w", err)
+		}
+		for _, row := range rows {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO dashboard_matching_times (hour, samples, avg_seconds, p50_seconds, p90_seconds, p99_seconds)
+				VALUES ($1, $2, $3, $4, $5, $6)
+			`, row.Hour, row.Samples, row.AvgSeconds, row.P50Seconds, row.P90Seconds, row.P99Seconds)
+			if err != nil {
+				return fmt.Errorf("failed to insert matching time summary: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ReplaceZoneSupply replaces every zone supply summary and records the refresh
+func (r *DashboardRepositoryImpl) ReplaceZoneSupply(ctx context.Context, rows []*models.ZoneSupplySummary, refresh *models.DashboardRefresh) error {
+	return r.replace(ctx, refresh, func(tx *sqlx.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM dashboard_zone_supply`); err != nil {
+			return fmt.Errorf("failed to clear zone supply summaries: %w", err)
+		}
+		for _, row := range rows {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO dashboard_zone_supply (zone, available_drivers, busy_drivers)
+				VALUES ($1, $2, $3)
+			`, row.Zone, row.AvailableDrivers, row.BusyDrivers)
+			if err != nil {
+				return fmt.Errorf("failed to insert zone supply summary: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// ListHourlyTrips retrieves the hourly trip summaries from since onwards, oldest first
+func (r *DashboardRepositoryImpl) ListHourlyTrips(ctx context.Context, since time.Time) ([]*models.HourlyTripSummary, error) {
+	query := `
+		SELECT hour, requested, matched, completed, cancelled
+		FROM dashboard_hourly_trips
+		WHERE hour >= $1
+		ORDER BY hour
+	`
+
+	var rows []*models.HourlyTripSummary
+	if err := r.db.SelectContext(ctx, &rows, query, since); err != nil {
+		return nil, fmt.Errorf("failed to list hourly trip summaries: %w", err)
+	}
+
+	return rows, nil
+}
+
+// ListMatchingTimes retrieves the matching time summaries from since onwards, oldest first
+func (r *DashboardRepositoryImpl) ListMatchingTimes(ctx context.Context, since time.Time) ([]*models.MatchingTimeSummary, error) {
+	query := `
+		SELECT hour, samples, avg_seconds, p50_seconds, p90_seconds, p99_seconds
+		FROM dashboard_matching_times
+		WHERE hour >= $1
+		ORDER BY hour
+	`
+
+	var rows []*models.MatchingTimeSummary
+	if err := r.db.SelectContext(ctx, &rows, query, since); err != nil {
+		return nil, fmt.Errorf("failed to list matching time summaries: %w", err)
+	}
+
+	return rows, nil
+}
+
+// ListZoneSupply retrieves the zone supply summaries, most available drivers first
+func (r *DashboardRepositoryImpl) ListZoneSupply(ctx context.Context) ([]*models.ZoneSupplySummary, error) {
+	query := `
+		SELECT zone, available_drivers, busy_drivers
+		FROM dashboard_zone_supply
+		ORDER BY available_drivers DESC, busy_drivers DESC, zone
+	`
+
+	var rows []*models.ZoneSupplySummary
+	if err := r.db.SelectContext(ctx, &rows, query); err != nil {
+		return nil, fmt.Errorf("failed to list zone supply summaries: %w", err)
+	}
+
+	return rows, nil
+}
+
+// GetRefresh retrieves the last refresh of a dashboard view
+func (r *DashboardRepositoryImpl) GetRefresh(ctx context.Context, view string) (*models.DashboardRefresh, error) {
+	query := `
+		SELECT view_name, refreshed_at, duration_ms, row_count
+		FROM dashboard_refreshes
+		WHERE view_name = $1
+	`
+
+	refresh := &models.DashboardRefresh{}
+	if err := r.db.GetContext(ctx, refresh, query, view); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{Resource: "dashboard refresh", ID: view}
+		}
+		return nil, fmt.Errorf("failed to get dashboard refresh: %w", err)
+	}
+
+	return refresh, nil
+}
+
+// replace runs fn and records the refresh in one transaction
+func (r *DashboardRepositoryImpl) replace(ctx context.Context, refresh *models.DashboardRefresh, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO dashboard_refreshes (view_name, refreshed_at, duration_ms, row_count)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (view_name)
+		DO UPDATE SET refreshed_at = excluded.refreshed_at, duration_ms = excluded.duration_ms, row_count = excluded.row_count
+	`, refresh.View, refresh.RefreshedAt, refresh.DurationMs, refresh.Rows)
+	if err != nil {
+		return fmt.Errorf("failed to record dashboard refresh: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,13 @@
+package postgres
+
+import (
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// isSQLite reports whether db is the SQLite development database, which runs the PostgreSQL
+// queries with their placeholders rewritten except for the few written for it separately
+func isSQLite(db *sqlx.DB) bool {
+	return strings.HasPrefix(db.DriverName(), "sqlite")
+}
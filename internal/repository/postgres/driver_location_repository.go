@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const driverLocationPingColumns = `driver_id, latitude, longitude, recorded_at, resolution_seconds, samples`
+
+// DriverLocationRepositoryImpl implements the DriverLocationRepository interface using PostgreSQL
+type DriverLocationRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewDriverLocationRepository creates a new instance of DriverLocationRepositoryImpl
+func NewDriverLocationRepository(db *sqlx.DB) repository.DriverLocationRepository {
+	return &DriverLocationRepositoryImpl{db: db}
+}
+
+// Record stores a location ping in the database
+func (r *DriverLocationRepositoryImpl) Record(ctx context.Context, ping *models.DriverLocationPing) error {
+	query := `
+		INSERT INTO driver_location_pings (` + driverLocationPingColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		ping.DriverID,
+		ping.Latitude,
+		ping.Longitude,
+		ping.RecordedAt,
+		ping.ResolutionSeconds,
+		ping.Samples,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record driver location: %w", err)
+	}
+
+	return nil
+}
+
+// GetTrail retrieves up to limit pings of a driver recorded in [from, to), oldest first
+func (r *DriverLocationRepositoryImpl) GetTrail(ctx context.Context, driverID string, from, to time.Time, limit int) ([]*models.DriverLocationPing, error) {
+	query := `
+		SELECT ` + driverLocationPingColumns + `
+		FROM driver_location_pings
+		WHERE driver_id = $1 AND recorded_at >= $2 AND recorded_at < $3
+		ORDER BY recorded_at
+		LIMIT $4
+	`
+
+	var pings []*models.DriverLocationPing
+	if err := r.db.SelectContext(ctx, &pings, query, driverID, from, to, limit); err != nil {
+		return nil, fmt.Errorf("failed to get driver location trail: %w", err)
+	}
+
+	return pings, nil
+}
+
+// Downsample replaces the pings recorded before the cutoff at a finer resolution with the last
+// ping of their driver in every resolution interval, counting the pings it replaced. Intervals
+// are aligned to the Unix epoch, so a cutoff aligned to the resolution never splits one.
+func (r *DriverLocationRepositoryImpl) Downsample(ctx context.Context, before time.Time, resolution time.Duration) (int64, error) {
+	query := `
+		WITH removed AS (
+			DELETE FROM driver_location_pings
+			WHERE recorded_at < $1 AND resolution_seconds < $2
+			RETURNING ` + driverLocationPingColumns + `,
+				FLOOR(EXTRACT(EPOCH FROM recorded_at) / $2) AS bucket
+		), kept AS (
+			SELECT DISTINCT ON (driver_id, bucket)
+				driver_id, latitude, longitude, recorded_at,
+				SUM(samples) OVER (PARTITION BY driver_id, bucket) AS samples
+			FROM removed
+			ORDER BY driver_id, bucket, recorded_at DESC
+		), inserted AS (
+			INSERT INTO driver_location_pings (` + driverLocationPingColumns + `)
+			SELECT driver_id, latitude, longitude, recorded_at, $2, samples FROM kept
+			RETURNING 1
+		)
+		SELECT (SELECT COUNT(*) FROM removed) - (SELECT COUNT(*) FROM inserted)
+	`
+
+	var removed int64
+	if err := r.db.GetContext(ctx, &removed, query, before, int(resolution.Seconds())); err != nil {
+		return 0, fmt.Errorf("failed to downsample driver locations: %w", err)
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const driverOnboardingColumns = `driver_id, stage, documents, registered_at, documents_submitted_at, verified_at, verified_by,
+	training_completed_at, activated_at, activated_by, updated_at`
+
+// DriverOnboardingRepositoryImpl implements the DriverOnboardingRepository interface using PostgreSQL
+type DriverOnboardingRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewDriverOnboardingRepository creates a new instance of DriverOnboardingRepositoryImpl
+func NewDriverOnboardingRepository(db *sqlx.DB) repository.DriverOnboardingRepository {
+	return &DriverOnboardingRepositoryImpl{db: db}
+}
+
+// Create creates the onboarding of a driver in the database
+func (r *DriverOnboardingRepositoryImpl) Create(ctx context.Context, onboarding *models.DriverOnboarding) error {
+	return createDriverOnboarding(ctx, r.db, onboarding)
+}
+
+// CreateWithDriver creates a driver and its onboarding in one transaction
+func (r *DriverOnboardingRepositoryImpl) CreateWithDriver(ctx context.Context, driver *models.Driver, onboarding *models.DriverOnboarding) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := createDriver(ctx, tx, driver); err != nil {
+		return err
+	}
+	if err := createDriverOnboarding(ctx, tx, onboarding); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit driver onboarding: %w", err)
+	}
+
+	return nil
+}
+
+// createDriverOnboarding inserts the onboarding of a driver through db, which may be a transaction
+func createDriverOnboarding(ctx context.Context, db sqlx.ExecerContext, onboarding *models.DriverOnboarding) error {
+	query := `
+		INSERT INTO driver_onboardings (` + driverOnboardingColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := db.ExecContext(ctx, query,
+		onboarding.DriverID,
+		onboarding.Stage,
+		onboarding.Documents,
+		onboarding.RegisteredAt,
+		onboarding.DocumentsSubmittedAt,
+		onboarding.VerifiedAt,
+		onboarding.VerifiedBy,
+		onboarding.TrainingCompletedAt,
+		onboarding.ActivatedAt,
+		onboarding.ActivatedBy,
+		onboarding.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505": // unique_violation
+				return fmt.Errorf("failed to create driver onboarding: %w", models.ErrDuplicateEntry)
+			case "23503": // foreign_key_violation
+				return &models.NotFoundError{
+					Resource: "driver",
+					ID:       onboarding.DriverID.String(),
+				}
+			}
+		}
+		return fmt.Errorf("failed to create driver onboarding: %w", err)
+	}
+
+	return nil
+}
+
+// GetByDriverID retrieves the onboarding of a driver
+func (r *DriverOnboardingRepositoryImpl) GetByDriverID(ctx context.Context, driverID string) (*models.DriverOnboarding, error) {
+	query := `SELECT ` + driverOnboardingColumns + ` FROM driver_onboardings WHERE driver_id = $1`
+
+	onboarding := &models.DriverOnboarding{}
+	err := r.db.GetContext(ctx, onboarding, query, driverID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "driver_onboarding",
+				ID:       driverID,
+			}
+		}
+		return nil, fmt.Errorf("failed to get driver onboarding: %w", err)
+	}
+
+	return onboarding, nil
+}
+
+// Update stores the onboarding's stage, documents and stage timestamps if its stored stage is still from
+func (r *DriverOnboardingRepositoryImpl) Update(ctx context.Context, onboarding *models.DriverOnboarding, from models.OnboardingStage) error {
+	query := `
+		UPDATE driver_onboardings
+		SET stage = $1, documents = $2, documents_submitted_at = $3, verified_at = $4, verified_by = $5,
+			training_completed_at = $6, activated_at = $7, activated_by = $8, updated_at = $9
+		WHERE driver_id = $10 AND stage = $11
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		onboarding.Stage,
+		onboarding.Documents,
+		onboarding.DocumentsSubmittedAt,
+		onboarding.VerifiedAt,
+		onboarding.VerifiedBy,
+		onboarding.TrainingCompletedAt,
+		onboarding.ActivatedAt,
+		onboarding.ActivatedBy,
+		onboarding.UpdatedAt,
+		onboarding.DriverID,
+		from,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update driver onboarding: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// The driver was moved on by another request
+	if rowsAffected == 0 {
+		return models.ErrOnboardingStageConflict
+	}
+
+	return nil
+}
+
+// CountByStage counts the drivers registered in [start, end) by their current stage
+func (r *DriverOnboardingRepositoryImpl) CountByStage(ctx context.Context, start, end time.Time) ([]models.OnboardingStageCount, error) {
+	query := `
+		SELECT stage, COUNT(*) AS count
+		FROM driver_onboardings
+		WHERE registered_at >= $1 AND registered_at < $2
+		GROUP BY stage
+	`
+
+	var counts []models.OnboardingStageCount
+	if err := r.db.SelectContext(ctx, &counts, query, start, end); err != nil {
+		return nil, fmt.Errorf("failed to count driver onboardings: %w", err)
+	}
+
+	return counts, nil
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"math"
+	"time"
 
 	"actor-model-observability/internal/models"
 	"actor-model-observability/internal/repository"
@@ -25,14 +25,21 @@ func NewDriverRepository(db *sqlx.DB) repository.DriverRepository {
 
 // Create creates a new driver in the database
 func (r *DriverRepositoryImpl) Create(ctx context.Context, driver *models.Driver) error {
+	return createDriver(ctx, r.db, driver)
+}
+
+// createDriver inserts a driver through db, which may be a transaction
+func createDriver(ctx context.Context, db sqlx.ExtContext, driver *models.Driver) error {
 	query := `
 		INSERT INTO drivers (id, user_id, license_number, vehicle_type, vehicle_plate, 
-			status, current_latitude, current_longitude, rating, total_trips, created_at, updated_at)
+			status, current_latitude, current_longitude, rating, total_trips, fleet_id,
+			pet_friendly, wheelchair_accessible, quiet_rides, child_seat, created_at, updated_at)
 		VALUES (:id, :user_id, :license_number, :vehicle_type, :vehicle_plate, 
-			:status, :current_latitude, :current_longitude, :rating, :total_trips, :created_at, :updated_at)
+			:status, :current_latitude, :current_longitude, :rating, :total_trips, :fleet_id,
+			:pet_friendly, :wheelchair_accessible, :quiet_rides, :child_seat, :created_at, :updated_at)
 	`
 
-	_, err := r.db.NamedExecContext(ctx, query, driver)
+	_, err := sqlx.NamedExecContext(ctx, db, query, driver)
 
 	if err != nil {
 		if pqErr, ok := err.(*pq.Error); ok {
@@ -57,6 +64,12 @@ func (r *DriverRepositoryImpl) Create(ctx context.Context, driver *models.Driver
 						Message: "user does not exist",
 					}
 				}
+				if pqErr.Constraint == "drivers_fleet_id_fkey" {
+					return &models.ValidationError{
+						Field:   "fleet_id",
+						Message: "fleet does not exist",
+					}
+				}
 			}
 		}
 		return fmt.Errorf("failed to create driver: %w", err)
@@ -69,7 +82,8 @@ func (r *DriverRepositoryImpl) Create(ctx context.Context, driver *models.Driver
 func (r *DriverRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Driver, error) {
 	query := `
 		SELECT id, user_id, license_number, vehicle_type, vehicle_plate, status, 
-			current_latitude, current_longitude, rating, total_trips, created_at, updated_at
+			current_latitude, current_longitude, rating, total_trips, fleet_id,
+			pet_friendly, wheelchair_accessible, quiet_rides, child_seat, created_at, updated_at
 		FROM drivers
 		WHERE id = $1
 	`
@@ -86,6 +100,11 @@ func (r *DriverRepositoryImpl) GetByID(ctx context.Context, id string) (*models.
 		&driver.CurrentLongitude,
 		&driver.Rating,
 		&driver.TotalTrips,
+		&driver.FleetID,
+		&driver.PetFriendly,
+		&driver.WheelchairAccessible,
+		&driver.QuietRides,
+		&driver.ChildSeat,
 		&driver.CreatedAt,
 		&driver.UpdatedAt,
 	)
@@ -107,7 +126,8 @@ func (r *DriverRepositoryImpl) GetByID(ctx context.Context, id string) (*models.
 func (r *DriverRepositoryImpl) GetByUserID(ctx context.Context, userID string) (*models.Driver, error) {
 	query := `
 		SELECT id, user_id, license_number, vehicle_type, vehicle_plate, status, 
-			current_latitude, current_longitude, rating, total_trips, created_at, updated_at
+			current_latitude, current_longitude, rating, total_trips, fleet_id,
+			pet_friendly, wheelchair_accessible, quiet_rides, child_seat, created_at, updated_at
 		FROM drivers
 		WHERE user_id = $1
 	`
@@ -124,6 +144,11 @@ func (r *DriverRepositoryImpl) GetByUserID(ctx context.Context, userID string) (
 		&driver.CurrentLongitude,
 		&driver.Rating,
 		&driver.TotalTrips,
+		&driver.FleetID,
+		&driver.PetFriendly,
+		&driver.WheelchairAccessible,
+		&driver.QuietRides,
+		&driver.ChildSeat,
 		&driver.CreatedAt,
 		&driver.UpdatedAt,
 	)
@@ -146,7 +171,8 @@ func (r *DriverRepositoryImpl) Update(ctx context.Context, driver *models.Driver
 	query := `
 		UPDATE drivers
 		SET license_number = $2, vehicle_type = $3, vehicle_plate = $4, status = $5, 
-			current_latitude = $6, current_longitude = $7, rating = $8, total_trips = $9, updated_at = $10
+			current_latitude = $6, current_longitude = $7, rating = $8, total_trips = $9, fleet_id = $10,
+			pet_friendly = $11, wheelchair_accessible = $12, quiet_rides = $13, child_seat = $14, updated_at = $15
 		WHERE id = $1
 	`
 
@@ -160,6 +186,11 @@ func (r *DriverRepositoryImpl) Update(ctx context.Context, driver *models.Driver
 		driver.CurrentLongitude,
 		driver.Rating,
 		driver.TotalTrips,
+		driver.FleetID,
+		driver.PetFriendly,
+		driver.WheelchairAccessible,
+		driver.QuietRides,
+		driver.ChildSeat,
 		driver.UpdatedAt,
 	)
 
@@ -227,7 +258,8 @@ func (r *DriverRepositoryImpl) Delete(ctx context.Context, id string) error {
 func (r *DriverRepositoryImpl) GetOnlineDrivers(ctx context.Context) ([]*models.Driver, error) {
 	query := `
 		SELECT id, user_id, license_number, vehicle_type, vehicle_plate, status, 
-			current_latitude, current_longitude, rating, total_trips, created_at, updated_at
+			current_latitude, current_longitude, rating, total_trips, fleet_id,
+			pet_friendly, wheelchair_accessible, quiet_rides, child_seat, created_at, updated_at
 		FROM drivers
 		WHERE status = 'online'
 		ORDER BY rating DESC, total_trips DESC
@@ -253,6 +285,11 @@ func (r *DriverRepositoryImpl) GetOnlineDrivers(ctx context.Context) ([]*models.
 			&driver.CurrentLongitude,
 			&driver.Rating,
 			&driver.TotalTrips,
+			&driver.FleetID,
+			&driver.PetFriendly,
+			&driver.WheelchairAccessible,
+			&driver.QuietRides,
+			&driver.ChildSeat,
 			&driver.CreatedAt,
 			&driver.UpdatedAt,
 		)
@@ -273,20 +310,23 @@ func (r *DriverRepositoryImpl) GetOnlineDrivers(ctx context.Context) ([]*models.
 func (r *DriverRepositoryImpl) GetDriversInRadius(ctx context.Context, lat, lng, radiusKm float64) ([]*models.Driver, error) {
 	// Using Haversine formula to calculate distance
 	query := `
-		SELECT id, user_id, license_number, vehicle_type, vehicle_plate, status, 
-			current_latitude, current_longitude, rating, total_trips, created_at, updated_at,
-			(
-				6371 * acos(
-					cos(radians($1)) * cos(radians(current_latitude)) *
-					cos(radians(current_longitude) - radians($2)) +
-					sin(radians($1)) * sin(radians(current_latitude))
-				)
-			) AS distance
-		FROM drivers
-		WHERE status = 'online'
-			AND current_latitude IS NOT NULL
-			AND current_longitude IS NOT NULL
-		HAVING distance <= $3
+		SELECT * FROM (
+			SELECT id, user_id, license_number, vehicle_type, vehicle_plate, status,
+				current_latitude, current_longitude, rating, total_trips, fleet_id,
+				pet_friendly, wheelchair_accessible, quiet_rides, child_seat, created_at, updated_at,
+				(
+					6371 * acos(
+						cos(radians($1)) * cos(radians(current_latitude)) *
+						cos(radians(current_longitude) - radians($2)) +
+						sin(radians($1)) * sin(radians(current_latitude))
+					)
+				) AS distance
+			FROM drivers
+			WHERE status = 'online'
+				AND current_latitude IS NOT NULL
+				AND current_longitude IS NOT NULL
+		) nearby
+		WHERE distance <= $3
 		ORDER BY distance ASC, rating DESC
 	`
 
@@ -311,6 +351,11 @@ func (r *DriverRepositoryImpl) GetDriversInRadius(ctx context.Context, lat, lng,
 			&driver.CurrentLongitude,
 			&driver.Rating,
 			&driver.TotalTrips,
+			&driver.FleetID,
+			&driver.PetFriendly,
+			&driver.WheelchairAccessible,
+			&driver.QuietRides,
+			&driver.ChildSeat,
 			&driver.CreatedAt,
 			&driver.UpdatedAt,
 			&distance,
@@ -356,6 +401,44 @@ func (r *DriverRepositoryImpl) UpdateLocation(ctx context.Context, driverID stri
 	return nil
 }
 
+// UpdateLocations writes the locations of many drivers in one transaction
+func (r *DriverRepositoryImpl) UpdateLocations(ctx context.Context, updates []*models.DriverLocationUpdate) (int64, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PreparexContext(ctx, `
+		UPDATE drivers
+		SET current_latitude = $2, current_longitude = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+	`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prepare driver location update: %w", err)
+	}
+	defer stmt.Close()
+
+	var updated int64
+	for _, update := range updates {
+		result, err := stmt.ExecContext(ctx, update.DriverID, update.Latitude, update.Longitude)
+		if err != nil {
+			return 0, fmt.Errorf("failed to update driver location: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		updated += rowsAffected
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit driver locations: %w", err)
+	}
+
+	return updated, nil
+}
+
 // UpdateStatus updates a driver's status
 func (r *DriverRepositoryImpl) UpdateStatus(ctx context.Context, driverID string, status models.DriverStatus) error {
 	query := `
@@ -384,11 +467,67 @@ func (r *DriverRepositoryImpl) UpdateStatus(ctx context.Context, driverID string
 	return nil
 }
 
+// Reserve sets an online driver busy. The conditional update is atomic, so of two concurrent
+// reservations of a driver only one succeeds.
+func (r *DriverRepositoryImpl) Reserve(ctx context.Context, driverID string) error {
+	updated, err := r.updateStatusFrom(ctx, driverID, models.DriverStatusOnline, models.DriverStatusBusy)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		return models.ErrDriverNotOnline
+	}
+	return nil
+}
+
+// Release sets a reserved driver back online; a driver no longer busy is left as is
+func (r *DriverRepositoryImpl) Release(ctx context.Context, driverID string) error {
+	_, err := r.updateStatusFrom(ctx, driverID, models.DriverStatusBusy, models.DriverStatusOnline)
+	return err
+}
+
+// updateStatusFrom changes the driver's status to status if it is still from, reporting whether
+// it was
+func (r *DriverRepositoryImpl) updateStatusFrom(ctx context.Context, driverID string, from, status models.DriverStatus) (bool, error) {
+	query := `
+		UPDATE drivers
+		SET status = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, driverID, from, status)
+	if err != nil {
+		return false, fmt.Errorf("failed to update driver status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		var exists bool
+		if err := r.db.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM drivers WHERE id = $1)`, driverID); err != nil {
+			return false, fmt.Errorf("failed to check driver: %w", err)
+		}
+		if !exists {
+			return false, &models.NotFoundError{
+				Resource: "driver",
+				ID:       driverID,
+			}
+		}
+		return false, nil
+	}
+
+	return true, nil
+}
+
 // List retrieves a list of drivers with pagination
 func (r *DriverRepositoryImpl) List(ctx context.Context, limit, offset int) ([]*models.Driver, error) {
 	query := `
 		SELECT id, user_id, license_number, vehicle_type, vehicle_plate, status, 
-			current_latitude, current_longitude, rating, total_trips, created_at, updated_at
+			current_latitude, current_longitude, rating, total_trips, fleet_id,
+			pet_friendly, wheelchair_accessible, quiet_rides, child_seat, created_at, updated_at
 		FROM drivers
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
@@ -414,6 +553,11 @@ func (r *DriverRepositoryImpl) List(ctx context.Context, limit, offset int) ([]*
 			&driver.CurrentLongitude,
 			&driver.Rating,
 			&driver.TotalTrips,
+			&driver.FleetID,
+			&driver.PetFriendly,
+			&driver.WheelchairAccessible,
+			&driver.QuietRides,
+			&driver.ChildSeat,
 			&driver.CreatedAt,
 			&driver.UpdatedAt,
 		)
@@ -430,22 +574,518 @@ func (r *DriverRepositoryImpl) List(ctx context.Context, limit, offset int) ([]*
 	return drivers, nil
 }
 
-// calculateDistance calculates the distance between two points using Haversine formula
-func calculateDistance(lat1, lng1, lat2, lng2 float64) float64 {
-	const earthRadius = 6371 // Earth's radius in kilometers
+// ListByFleet retrieves every driver of a fleet, oldest first
+func (r *DriverRepositoryImpl) ListByFleet(ctx context.Context, fleetID string) ([]*models.Driver, error) {
+	query := `
+		SELECT id, user_id, license_number, vehicle_type, vehicle_plate, status, 
+			current_latitude, current_longitude, rating, total_trips, fleet_id,
+			pet_friendly, wheelchair_accessible, quiet_rides, child_seat, created_at, updated_at
+		FROM drivers
+		WHERE fleet_id = $1
+		ORDER BY created_at, id
+	`
 
-	// Convert degrees to radians
-	lat1Rad := lat1 * math.Pi / 180
-	lng1Rad := lng1 * math.Pi / 180
-	lat2Rad := lat2 * math.Pi / 180
-	lng2Rad := lng2 * math.Pi / 180
+	rows, err := r.db.QueryContext(ctx, query, fleetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fleet drivers: %w", err)
+	}
+	defer rows.Close()
 
-	// Haversine formula
-	dlat := lat2Rad - lat1Rad
-	dlng := lng2Rad - lng1Rad
+	var drivers []*models.Driver
+	for rows.Next() {
+		driver := &models.Driver{}
+		err := rows.Scan(
+			&driver.ID,
+			&driver.UserID,
+			&driver.LicenseNumber,
+			&driver.VehicleType,
+			&driver.VehiclePlate,
+			&driver.Status,
+			&driver.CurrentLatitude,
+			&driver.CurrentLongitude,
+			&driver.Rating,
+			&driver.TotalTrips,
+			&driver.FleetID,
+			&driver.PetFriendly,
+			&driver.WheelchairAccessible,
+			&driver.QuietRides,
+			&driver.ChildSeat,
+			&driver.CreatedAt,
+			&driver.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan driver: %w", err)
+		}
+		drivers = append(drivers, driver)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating drivers: %w", err)
+	}
+
+	return drivers, nil
+}
+
+// driverStatsOrderColumns maps the supported sort options to result columns
+var driverStatsOrderColumns = map[models.DriverStatsSort]string{
+	models.DriverStatsSortTripsCompleted: "trips_completed",
+	models.DriverStatsSortAcceptanceRate: "acceptance_rate",
+	models.DriverStatsSortAverageRating:  "average_rating",
+	models.DriverStatsSortEarnings:       "earnings",
+	models.DriverStatsSortOnlineHours:    "online_hours",
+}
+
+// GetDriverStats aggregates trips and online time per driver over the filter period.
+// Online hours are derived from driver_status_history, clipping sessions to the period.
+func (r *DriverRepositoryImpl) GetDriverStats(ctx context.Context, filter models.DriverStatsFilter) ([]*models.DriverStats, int64, error) {
+	orderColumn, ok := driverStatsOrderColumns[filter.SortBy]
+	if !ok {
+		return nil, 0, &models.ValidationError{
+			Field:   "sort_by",
+			Message: fmt.Sprintf("unsupported sort column: %s", filter.SortBy),
+		}
+	}
+	direction := "ASC"
+	if filter.Descending {
+		direction = "DESC"
+	}
+
+	query := fmt.Sprintf(`
+		WITH trip_stats AS (
+			SELECT driver_id,
+				COUNT(*) AS trips_assigned,
+				COUNT(*) FILTER (WHERE accepted_at IS NOT NULL
+					OR status IN ('accepted', 'driver_arrived', 'in_progress', 'completed')) AS trips_accepted,
+				COUNT(*) FILTER (WHERE status = 'completed') AS trips_completed,
+				COUNT(*) FILTER (WHERE status = 'cancelled') AS trips_cancelled,
+				COALESCE(SUM(fare_amount) FILTER (WHERE status = 'completed'), 0) AS earnings
+			FROM trips
+			WHERE driver_id IS NOT NULL AND requested_at >= $1 AND requested_at < $2
+			GROUP BY driver_id
+		),
+		status_periods AS (
+			SELECT driver_id, status, changed_at AS started_at,
+				LEAD(changed_at, 1, NOW()) OVER (PARTITION BY driver_id ORDER BY changed_at) AS ended_at
+			FROM driver_status_history
+			WHERE changed_at < $2
+		),
+		online_stats AS (
+			SELECT driver_id,
+				SUM(EXTRACT(EPOCH FROM (LEAST(ended_at, $2) - GREATEST(started_at, $1)))) / 3600 AS online_hours
+			FROM status_periods
+			WHERE status IN ('online', 'busy') AND ended_at > $1
+			GROUP BY driver_id
+		)
+		SELECT d.id, d.user_id, u.name, d.vehicle_plate, d.status, d.rating AS average_rating,
+			COALESCE(t.trips_assigned, 0) AS trips_assigned,
+			COALESCE(t.trips_accepted, 0) AS trips_accepted,
+			COALESCE(t.trips_completed, 0) AS trips_completed,
+			COALESCE(t.trips_cancelled, 0) AS trips_cancelled,
+			CASE WHEN COALESCE(t.trips_assigned, 0) > 0
+				THEN t.trips_accepted::float / t.trips_assigned ELSE 0 END AS acceptance_rate,
+			COALESCE(t.earnings, 0) AS earnings,
+			COALESCE(o.online_hours, 0) AS online_hours
+		FROM drivers d
+		JOIN users u ON u.id = d.user_id
+		LEFT JOIN trip_stats t ON t.driver_id = d.id
+		LEFT JOIN online_stats o ON o.driver_id = d.id
+		ORDER BY %s %s, d.id
+		LIMIT $3 OFFSET $4
+	`, orderColumn, direction)
+
+	rows, err := r.db.QueryContext(ctx, query, filter.From, filter.To, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get driver stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*models.DriverStats
+	for rows.Next() {
+		s := &models.DriverStats{}
+		err := rows.Scan(
+			&s.DriverID,
+			&s.UserID,
+			&s.Name,
+			&s.VehiclePlate,
+			&s.Status,
+			&s.AverageRating,
+			&s.TripsAssigned,
+			&s.TripsAccepted,
+			&s.TripsCompleted,
+			&s.TripsCancelled,
+			&s.AcceptanceRate,
+			&s.Earnings,
+			&s.OnlineHours,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan driver stats: %w", err)
+		}
+		stats = append(stats, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating driver stats: %w", err)
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM drivers`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count drivers: %w", err)
+	}
+
+	return stats, total, nil
+}
+
+// GetTripStats retrieves the trip stats rollup of a driver, summing the earnings of the days in
+// the rolling window. Drivers who never completed a trip have zero stats.
+func (r *DriverRepositoryImpl) GetTripStats(ctx context.Context, driverID string) (*models.DriverTripStats, error) {
+	query := `
+		SELECT d.id, COALESCE(s.total_trips, 0), COALESCE(s.total_distance_km, 0), s.last_trip_at, d.rating,
+			COALESCE((
+				SELECT SUM(e.earnings) FROM driver_daily_earnings e WHERE e.driver_id = d.id AND e.day >= $2
+			), 0)
+		FROM drivers d
+		LEFT JOIN driver_trip_stats s ON s.driver_id = d.id
+		WHERE d.id = $1
+	`
+
+	stats := &models.DriverTripStats{}
+	err := r.db.QueryRowContext(ctx, query, driverID, models.DriverEarningsWindowStart(time.Now())).Scan(
+		&stats.DriverID,
+		&stats.TotalTrips,
+		&stats.TotalDistanceKm,
+		&stats.LastTripAt,
+		&stats.AverageRating,
+		&stats.RecentEarnings,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "driver",
+				ID:       driverID,
+			}
+		}
+		return nil, fmt.Errorf("failed to get driver trip stats: %w", err)
+	}
 
-	a := math.Sin(dlat/2)*math.Sin(dlat/2) + math.Cos(lat1Rad)*math.Cos(lat2Rad)*math.Sin(dlng/2)*math.Sin(dlng/2)
-	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+	return stats, nil
+}
+
+// SetDestination turns on destination mode, replacing any destination that is still active
+func (r *DriverRepositoryImpl) SetDestination(ctx context.Context, destination *models.DriverDestination) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	clearQuery := `
+		UPDATE driver_destinations
+		SET cleared_at = $2
+		WHERE driver_id = $1 AND cleared_at IS NULL AND expires_at > $2
+	`
+	if _, err := tx.ExecContext(ctx, clearQuery, destination.DriverID, destination.CreatedAt); err != nil {
+		return fmt.Errorf("failed to clear previous destination: %w", err)
+	}
+
+	insertQuery := `
+		INSERT INTO driver_destinations (id, driver_id, latitude, longitude, address, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err = tx.ExecContext(ctx, insertQuery,
+		destination.ID,
+		destination.DriverID,
+		destination.Latitude,
+		destination.Longitude,
+		destination.Address,
+		destination.ExpiresAt,
+		destination.CreatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" { // foreign_key_violation
+			return &models.NotFoundError{
+				Resource: "driver",
+				ID:       destination.DriverID.String(),
+			}
+		}
+		return fmt.Errorf("failed to set destination: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit destination: %w", err)
+	}
+
+	return nil
+}
+
+// GetActiveDestination retrieves the driver's active destination
+func (r *DriverRepositoryImpl) GetActiveDestination(ctx context.Context, driverID string) (*models.DriverDestination, error) {
+	query := `
+		SELECT id, driver_id, latitude, longitude, address, expires_at, cleared_at, created_at
+		FROM driver_destinations
+		WHERE driver_id = $1 AND cleared_at IS NULL AND expires_at > $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	destination := &models.DriverDestination{}
+	err := r.db.GetContext(ctx, destination, query, driverID, time.Now())
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "driver destination",
+				ID:       driverID,
+			}
+		}
+		return nil, fmt.Errorf("failed to get active destination: %w", err)
+	}
+
+	return destination, nil
+}
+
+// GetActiveDestinations retrieves every active destination keyed by driver ID
+func (r *DriverRepositoryImpl) GetActiveDestinations(ctx context.Context) (map[string]*models.DriverDestination, error) {
+	query := `
+		SELECT id, driver_id, latitude, longitude, address, expires_at, cleared_at, created_at
+		FROM driver_destinations
+		WHERE cleared_at IS NULL AND expires_at > $1
+		ORDER BY created_at
+	`
+
+	var rows []*models.DriverDestination
+	if err := r.db.SelectContext(ctx, &rows, query, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to get active destinations: %w", err)
+	}
+
+	// Later rows win, so a driver's newest destination is kept
+	destinations := make(map[string]*models.DriverDestination, len(rows))
+	for _, d := range rows {
+		destinations[d.DriverID.String()] = d
+	}
+
+	return destinations, nil
+}
+
+// ClearDestination turns off destination mode for the driver
+func (r *DriverRepositoryImpl) ClearDestination(ctx context.Context, driverID string) error {
+	now := time.Now()
+	query := `
+		UPDATE driver_destinations
+		SET cleared_at = $2
+		WHERE driver_id = $1 AND cleared_at IS NULL AND expires_at > $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, driverID, now)
+	if err != nil {
+		return fmt.Errorf("failed to clear destination: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "driver destination",
+			ID:       driverID,
+		}
+	}
+
+	return nil
+}
+
+// CountDestinationsSince counts how often the driver turned on destination mode since the given time
+func (r *DriverRepositoryImpl) CountDestinationsSince(ctx context.Context, driverID string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM driver_destinations WHERE driver_id = $1 AND created_at >= $2`
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, driverID, since).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count destinations: %w", err)
+	}
+
+	return count, nil
+}
+
+// driverHoursQuery sums the time each driver spent online or busy, and busy alone, within
+// [$1, $2), treating each status as lasting until the driver's next change. The %[1]s
+// placeholder is a condition on the drivers d to include.
+const driverHoursQuery = `
+	WITH status_periods AS (
+		SELECT driver_id, status,
+			GREATEST(changed_at, $1) AS started_at,
+			LEAST(LEAD(changed_at, 1, $2) OVER (PARTITION BY driver_id ORDER BY changed_at), $2) AS ended_at
+		FROM driver_status_history
+		WHERE changed_at < $2 AND driver_id IN (SELECT d.id FROM drivers d WHERE %[1]s)
+	),
+	hours AS (
+		SELECT driver_id,
+			SUM(EXTRACT(EPOCH FROM (ended_at - started_at))) FILTER (WHERE status IN ('online', 'busy')) AS online_seconds,
+			SUM(EXTRACT(EPOCH FROM (ended_at - started_at))) FILTER (WHERE status = 'busy') AS driving_seconds
+		FROM status_periods
+		WHERE ended_at > started_at
+		GROUP BY driver_id
+	)
+	SELECT d.id AS driver_id,
+		COALESCE(h.online_seconds, 0)::BIGINT AS online_seconds,
+		COALESCE(h.driving_seconds, 0)::BIGINT AS driving_seconds
+	FROM drivers d
+	LEFT JOIN hours h ON h.driver_id = d.id
+	WHERE %[1]s
+	ORDER BY d.id
+`
+
+// sqliteDriverHoursQuery is driverHoursQuery for SQLite, which has no EXTRACT or GREATEST and
+// stores timestamps as text: periods are measured in Julian days
+const sqliteDriverHoursQuery = `
+	WITH status_periods AS (
+		SELECT driver_id, status,
+			MAX(julianday(changed_at), julianday($1)) AS started_at,
+			MIN(julianday(LEAD(changed_at, 1, $2) OVER (PARTITION BY driver_id ORDER BY julianday(changed_at))), julianday($2)) AS ended_at
+		FROM driver_status_history
+		WHERE julianday(changed_at) < julianday($2) AND driver_id IN (SELECT d.id FROM drivers d WHERE %[1]s)
+	),
+	hours AS (
+		SELECT driver_id,
+			SUM((ended_at - started_at) * 86400) FILTER (WHERE status IN ('online', 'busy')) AS online_seconds,
+			SUM((ended_at - started_at) * 86400) FILTER (WHERE status = 'busy') AS driving_seconds
+		FROM status_periods
+		WHERE ended_at > started_at
+		GROUP BY driver_id
+	)
+	SELECT d.id AS driver_id,
+		CAST(COALESCE(h.online_seconds, 0) AS INTEGER) AS online_seconds,
+		CAST(COALESCE(h.driving_seconds, 0) AS INTEGER) AS driving_seconds
+	FROM drivers d
+	LEFT JOIN hours h ON h.driver_id = d.id
+	WHERE %[1]s
+	ORDER BY d.id
+`
+
+// driverHours returns the driver hours query for the database, selecting the drivers matching
+// the condition
+func (r *DriverRepositoryImpl) driverHours(condition string) string {
+	if isSQLite(r.db) {
+		return fmt.Sprintf(sqliteDriverHoursQuery, condition)
+	}
+	return fmt.Sprintf(driverHoursQuery, condition)
+}
+
+// GetDriverHours sums the driver's time online and driving within [from, to)
+func (r *DriverRepositoryImpl) GetDriverHours(ctx context.Context, driverID string, from, to time.Time) (*models.DriverHours, error) {
+	hours := &models.DriverHours{}
+	err := r.db.GetContext(ctx, hours, r.driverHours("d.id = $3"), from, to, driverID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "driver",
+				ID:       driverID,
+			}
+		}
+		return nil, fmt.Errorf("failed to get driver hours: %w", err)
+	}
+
+	hours.WindowStart = from
+	hours.WindowEnd = to
+	return hours, nil
+}
+
+// ListOnlineDriverHours sums the time online and driving within [from, to) of every driver
+// currently online
+func (r *DriverRepositoryImpl) ListOnlineDriverHours(ctx context.Context, from, to time.Time) ([]*models.DriverHours, error) {
+	var hours []*models.DriverHours
+	if err := r.db.SelectContext(ctx, &hours, r.driverHours("d.status = 'online'"), from, to); err != nil {
+		return nil, fmt.Errorf("failed to list online driver hours: %w", err)
+	}
+
+	for _, h := range hours {
+		h.WindowStart = from
+		h.WindowEnd = to
+	}
+	return hours, nil
+}
+
+// StartRest records the rest and sets the driver offline in one transaction, failing unless the
+// driver is still online
+func (r *DriverRepositoryImpl) StartRest(ctx context.Context, rest *models.DriverRest) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE drivers
+		SET status = 'offline', updated_at = $2
+		WHERE id = $1 AND status = 'online'
+	`, rest.DriverID, rest.StartedAt)
+	if err != nil {
+		return fmt.Errorf("failed to set driver offline: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		var exists bool
+		if err := tx.GetContext(ctx, &exists, `SELECT EXISTS (SELECT 1 FROM drivers WHERE id = $1)`, rest.DriverID); err != nil {
+			return fmt.Errorf("failed to check driver: %w", err)
+		}
+		if !exists {
+			return &models.NotFoundError{
+				Resource: "driver",
+				ID:       rest.DriverID.String(),
+			}
+		}
+		return models.ErrDriverNotOnline
+	}
+
+	insertQuery := `
+		INSERT INTO driver_rests (id, driver_id, limit_reached, online_seconds, driving_seconds, started_at, rest_until)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err = tx.ExecContext(ctx, insertQuery,
+		rest.ID,
+		rest.DriverID,
+		rest.Limit,
+		rest.OnlineSeconds,
+		rest.DrivingSeconds,
+		rest.StartedAt,
+		rest.RestUntil,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create driver rest: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit driver rest: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestRest retrieves the driver's rest ending last, which may be over
+func (r *DriverRepositoryImpl) GetLatestRest(ctx context.Context, driverID string) (*models.DriverRest, error) {
+	query := `
+		SELECT id, driver_id, limit_reached, online_seconds, driving_seconds, started_at, rest_until
+		FROM driver_rests
+		WHERE driver_id = $1
+		ORDER BY rest_until DESC
+		LIMIT 1
+	`
+
+	rest := &models.DriverRest{}
+	err := r.db.GetContext(ctx, rest, query, driverID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "driver rest",
+				ID:       driverID,
+			}
+		}
+		return nil, fmt.Errorf("failed to get latest rest: %w", err)
+	}
 
-	return earthRadius * c
+	return rest, nil
 }
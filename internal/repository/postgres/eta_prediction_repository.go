@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const etaPredictionColumns = `trip_id, driver_id, zone, hour, distance_km, speed_kmh, predicted_seconds, predicted_at,
+	arrived_at, actual_seconds, error_seconds`
+
+// ETAPredictionRepositoryImpl implements the ETAPredictionRepository interface using PostgreSQL
+type ETAPredictionRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewETAPredictionRepository creates a new instance of ETAPredictionRepositoryImpl
+func NewETAPredictionRepository(db *sqlx.DB) repository.ETAPredictionRepository {
+	return &ETAPredictionRepositoryImpl{db: db}
+}
+
+// Create creates a new ETA prediction in the database
+func (r *ETAPredictionRepositoryImpl) Create(ctx context.Context, prediction *models.ETAPrediction) error {
+	query := `
+		INSERT INTO eta_predictions (` + etaPredictionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		prediction.TripID,
+		prediction.DriverID,
+		prediction.Zone,
+		prediction.Hour,
+		prediction.DistanceKm,
+		prediction.SpeedKmh,
+		prediction.PredictedSeconds,
+		prediction.PredictedAt,
+		prediction.ArrivedAt,
+		prediction.ActualSeconds,
+		prediction.ErrorSeconds,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return fmt.Errorf("failed to create ETA prediction: %w", models.ErrDuplicateEntry)
+		}
+		return fmt.Errorf("failed to create ETA prediction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByTrip retrieves the ETA prediction of a trip
+func (r *ETAPredictionRepositoryImpl) GetByTrip(ctx context.Context, tripID string) (*models.ETAPrediction, error) {
+	query := `SELECT ` + etaPredictionColumns + ` FROM eta_predictions WHERE trip_id = $1`
+
+	prediction := &models.ETAPrediction{}
+	err := r.db.GetContext(ctx, prediction, query, tripID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "ETA prediction",
+				ID:       tripID,
+			}
+		}
+		return nil, fmt.Errorf("failed to get ETA prediction: %w", err)
+	}
+
+	return prediction, nil
+}
+
+// RecordArrival stores the arrival of a prediction that has none yet
+func (r *ETAPredictionRepositoryImpl) RecordArrival(ctx context.Context, prediction *models.ETAPrediction) error {
+	query := `
+		UPDATE eta_predictions
+		SET arrived_at = $2, actual_seconds = $3, error_seconds = $4
+		WHERE trip_id = $1 AND arrived_at IS NULL
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		prediction.TripID,
+		prediction.ArrivedAt,
+		prediction.ActualSeconds,
+		prediction.ErrorSeconds,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record ETA arrival: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "pending ETA prediction",
+			ID:       prediction.TripID.String(),
+		}
+	}
+
+	return nil
+}
+
+// GetAccuracy retrieves the accuracy of the ETAs predicted in [from, to) whose drivers arrived, by
+// pickup zone and hour of day, most samples first
+func (r *ETAPredictionRepositoryImpl) GetAccuracy(ctx context.Context, from, to time.Time) ([]*models.ETAAccuracy, error) {
+	query := `
+		SELECT
+			zone,
+			hour,
+			COUNT(*) AS samples,
+			AVG(ABS(error_seconds)) AS mae_seconds,
+			AVG(error_seconds) AS bias_seconds,
+			AVG(predicted_seconds) AS avg_predicted_seconds,
+			AVG(actual_seconds) AS avg_actual_seconds,
+			AVG(distance_km) AS avg_distance_km
+		FROM eta_predictions
+		WHERE arrived_at IS NOT NULL AND predicted_at >= $1 AND predicted_at < $2
+		GROUP BY zone, hour
+		ORDER BY samples DESC, zone, hour
+	`
+
+	var accuracy []*models.ETAAccuracy
+	if err := r.db.SelectContext(ctx, &accuracy, query, from, to); err != nil {
+		return nil, fmt.Errorf("failed to get ETA accuracy: %w", err)
+	}
+
+	return accuracy, nil
+}
@@ -0,0 +1,247 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const fareDisputeColumns = `id, trip_id, passenger_id, reason, original_fare, requested_fare, status, adjusted_fare,
+	reviewed_by, resolution_note, reviewed_at, closed_at, created_at, updated_at`
+
+const fareAdjustmentColumns = `id, trip_id, dispute_id, previous_fare, new_fare, amount, reason, adjusted_by, created_at`
+
+// FareDisputeRepositoryImpl implements the FareDisputeRepository interface using PostgreSQL
+type FareDisputeRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewFareDisputeRepository creates a new instance of FareDisputeRepositoryImpl
+func NewFareDisputeRepository(db *sqlx.DB) repository.FareDisputeRepository {
+	return &FareDisputeRepositoryImpl{db: db}
+}
+
+// CreateDispute creates a new fare dispute in the database
+func (r *FareDisputeRepositoryImpl) CreateDispute(ctx context.Context, dispute *models.FareDispute) error {
+	query := `
+		INSERT INTO fare_disputes (` + fareDisputeColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		dispute.ID,
+		dispute.TripID,
+		dispute.PassengerID,
+		dispute.Reason,
+		dispute.OriginalFare,
+		dispute.RequestedFare,
+		dispute.Status,
+		dispute.AdjustedFare,
+		dispute.ReviewedBy,
+		dispute.ResolutionNote,
+		dispute.ReviewedAt,
+		dispute.ClosedAt,
+		dispute.CreatedAt,
+		dispute.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505": // unique_violation
+				if pqErr.Constraint == "idx_fare_disputes_active_trip" {
+					return models.ErrDisputeAlreadyOpen
+				}
+			case "23503": // foreign_key_violation
+				if pqErr.Constraint == "fare_disputes_passenger_id_fkey" {
+					return &models.NotFoundError{
+						Resource: "passenger",
+						ID:       dispute.PassengerID.String(),
+					}
+				}
+				return &models.NotFoundError{
+					Resource: "trip",
+					ID:       dispute.TripID.String(),
+				}
+			}
+		}
+		return fmt.Errorf("failed to create fare dispute: %w", err)
+	}
+
+	return nil
+}
+
+// GetDispute retrieves a fare dispute by ID
+func (r *FareDisputeRepositoryImpl) GetDispute(ctx context.Context, id string) (*models.FareDispute, error) {
+	query := `SELECT ` + fareDisputeColumns + ` FROM fare_disputes WHERE id = $1`
+
+	dispute := &models.FareDispute{}
+	err := r.db.GetContext(ctx, dispute, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "fare_dispute",
+				ID:       id,
+			}
+		}
+		return nil, fmt.Errorf("failed to get fare dispute: %w", err)
+	}
+
+	return dispute, nil
+}
+
+// UpdateDispute stores the dispute's status and review fields if its stored status is still from
+func (r *FareDisputeRepositoryImpl) UpdateDispute(ctx context.Context, dispute *models.FareDispute, from models.FareDisputeStatus) error {
+	return updateDispute(ctx, r.db, dispute, from)
+}
+
+// ResolveDispute updates the dispute, records the adjustment and applies the new fare to the trip
+func (r *FareDisputeRepositoryImpl) ResolveDispute(ctx context.Context, dispute *models.FareDispute, from models.FareDisputeStatus, adjustment *models.FareAdjustment) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateDispute(ctx, tx, dispute, from); err != nil {
+		return err
+	}
+
+	insertQuery := `
+		INSERT INTO fare_adjustments (` + fareAdjustmentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+	_, err = tx.ExecContext(ctx, insertQuery,
+		adjustment.ID,
+		adjustment.TripID,
+		adjustment.DisputeID,
+		adjustment.PreviousFare,
+		adjustment.NewFare,
+		adjustment.Amount,
+		adjustment.Reason,
+		adjustment.AdjustedBy,
+		adjustment.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record fare adjustment: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `UPDATE trips SET fare_amount = $2, updated_at = $3 WHERE id = $1`,
+		adjustment.TripID, adjustment.NewFare, adjustment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update trip fare: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "trip",
+			ID:       adjustment.TripID.String(),
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit fare dispute resolution: %w", err)
+	}
+
+	return nil
+}
+
+// updateDispute stores the dispute's status and review fields using db, which may be a transaction
+func updateDispute(ctx context.Context, db sqlx.ExecerContext, dispute *models.FareDispute, from models.FareDisputeStatus) error {
+	query := `
+		UPDATE fare_disputes
+		SET status = $3, adjusted_fare = $4, reviewed_by = $5, resolution_note = $6, reviewed_at = $7,
+			closed_at = $8, updated_at = $9
+		WHERE id = $1 AND status = $2
+	`
+
+	result, err := db.ExecContext(ctx, query,
+		dispute.ID,
+		from,
+		dispute.Status,
+		dispute.AdjustedFare,
+		dispute.ReviewedBy,
+		dispute.ResolutionNote,
+		dispute.ReviewedAt,
+		dispute.ClosedAt,
+		dispute.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update fare dispute: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// The dispute was either deleted or moved on by another reviewer
+	if rowsAffected == 0 {
+		return models.ErrDisputeStatusConflict
+	}
+
+	return nil
+}
+
+// ListDisputes retrieves the matching disputes, oldest first, and the total number of matches
+func (r *FareDisputeRepositoryImpl) ListDisputes(ctx context.Context, filter models.FareDisputeFilter) ([]*models.FareDispute, int64, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.TripID != nil {
+		args = append(args, *filter.TripID)
+		conditions = append(conditions, fmt.Sprintf("trip_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM fare_disputes `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count fare disputes: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM fare_disputes
+		%s
+		ORDER BY created_at, id
+		LIMIT $%d OFFSET $%d
+	`, fareDisputeColumns, where, len(args)+1, len(args)+2)
+
+	var disputes []*models.FareDispute
+	if err := r.db.SelectContext(ctx, &disputes, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list fare disputes: %w", err)
+	}
+
+	return disputes, total, nil
+}
+
+// ListAdjustmentsByTrip retrieves the trip's fare adjustments ledger, oldest first
+func (r *FareDisputeRepositoryImpl) ListAdjustmentsByTrip(ctx context.Context, tripID string) ([]*models.FareAdjustment, error) {
+	query := `
+		SELECT ` + fareAdjustmentColumns + `
+		FROM fare_adjustments
+		WHERE trip_id = $1
+		ORDER BY created_at, id
+	`
+
+	var adjustments []*models.FareAdjustment
+	if err := r.db.SelectContext(ctx, &adjustments, query, tripID); err != nil {
+		return nil, fmt.Errorf("failed to list fare adjustments: %w", err)
+	}
+
+	return adjustments, nil
+}
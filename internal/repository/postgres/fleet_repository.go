@@ -0,0 +1,130 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const fleetColumns = `id, name, api_key_hash, timezone, daily_request_quota, created_at, updated_at`
+
+// FleetRepositoryImpl implements the FleetRepository interface using PostgreSQL
+type FleetRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewFleetRepository creates a new instance of FleetRepositoryImpl
+func NewFleetRepository(db *sqlx.DB) repository.FleetRepository {
+	return &FleetRepositoryImpl{db: db}
+}
+
+// Create creates a new fleet in the database
+func (r *FleetRepositoryImpl) Create(ctx context.Context, fleet *models.Fleet) error {
+	query := `
+		INSERT INTO fleets (` + fleetColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		fleet.ID,
+		fleet.Name,
+		fleet.APIKeyHash,
+		fleet.Timezone,
+		fleet.DailyRequestQuota,
+		fleet.CreatedAt,
+		fleet.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return fmt.Errorf("failed to create fleet: %w", models.ErrDuplicateEntry)
+		}
+		return fmt.Errorf("failed to create fleet: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a fleet by ID
+func (r *FleetRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Fleet, error) {
+	return r.getBy(ctx, "id", id)
+}
+
+// GetByAPIKeyHash retrieves the fleet owning an API key
+func (r *FleetRepositoryImpl) GetByAPIKeyHash(ctx context.Context, hash string) (*models.Fleet, error) {
+	return r.getBy(ctx, "api_key_hash", hash)
+}
+
+// getBy retrieves the fleet whose column equals value
+func (r *FleetRepositoryImpl) getBy(ctx context.Context, column, value string) (*models.Fleet, error) {
+	query := `SELECT ` + fleetColumns + ` FROM fleets WHERE ` + column + ` = $1`
+
+	fleet := &models.Fleet{}
+	err := r.db.GetContext(ctx, fleet, query, value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// API key hashes are not echoed back in errors
+			id := value
+			if column != "id" {
+				id = "<api key>"
+			}
+			return nil, &models.NotFoundError{
+				Resource: "fleet",
+				ID:       id,
+			}
+		}
+		return nil, fmt.Errorf("failed to get fleet by %s: %w", column, err)
+	}
+
+	return fleet, nil
+}
+
+// List retrieves every fleet, oldest first
+func (r *FleetRepositoryImpl) List(ctx context.Context) ([]*models.Fleet, error) {
+	query := `SELECT ` + fleetColumns + ` FROM fleets ORDER BY created_at ASC, id ASC`
+
+	var fleets []*models.Fleet
+	if err := r.db.SelectContext(ctx, &fleets, query); err != nil {
+		return nil, fmt.Errorf("failed to list fleets: %w", err)
+	}
+
+	return fleets, nil
+}
+
+// Update updates a fleet's name, timezone and daily request quota
+func (r *FleetRepositoryImpl) Update(ctx context.Context, fleet *models.Fleet) error {
+	query := `
+		UPDATE fleets
+		SET name = $2, timezone = $3, daily_request_quota = $4, updated_at = $5
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		fleet.ID,
+		fleet.Name,
+		fleet.Timezone,
+		fleet.DailyRequestQuota,
+		fleet.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update fleet: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "fleet",
+			ID:       fleet.ID.String(),
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const fraudSignalColumns = `id, rule, severity, subject_type, subject_id, trip_id, details, status, reviewed_by,
+	review_note, reviewed_at, created_at, updated_at`
+
+// FraudSignalRepositoryImpl implements the FraudSignalRepository interface using PostgreSQL
+type FraudSignalRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewFraudSignalRepository creates a new instance of FraudSignalRepositoryImpl
+func NewFraudSignalRepository(db *sqlx.DB) repository.FraudSignalRepository {
+	return &FraudSignalRepositoryImpl{db: db}
+}
+
+// Create creates a new fraud signal in the database
+func (r *FraudSignalRepositoryImpl) Create(ctx context.Context, signal *models.FraudSignal) error {
+	query := `
+		INSERT INTO fraud_signals (` + fraudSignalColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		signal.ID,
+		signal.Rule,
+		signal.Severity,
+		signal.SubjectType,
+		signal.SubjectID,
+		signal.TripID,
+		signal.Details,
+		signal.Status,
+		signal.ReviewedBy,
+		signal.ReviewNote,
+		signal.ReviewedAt,
+		signal.CreatedAt,
+		signal.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505": // unique_violation
+				return fmt.Errorf("failed to create fraud signal: %w", models.ErrDuplicateEntry)
+			case "23503": // foreign_key_violation
+				return &models.NotFoundError{
+					Resource: "trip",
+					ID:       signal.TripID.String(),
+				}
+			}
+		}
+		return fmt.Errorf("failed to create fraud signal: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a fraud signal by ID
+func (r *FraudSignalRepositoryImpl) GetByID(ctx context.Context, id string) (*models.FraudSignal, error) {
+	query := `SELECT ` + fraudSignalColumns + ` FROM fraud_signals WHERE id = $1`
+
+	signal := &models.FraudSignal{}
+	err := r.db.GetContext(ctx, signal, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "fraud_signal",
+				ID:       id,
+			}
+		}
+		return nil, fmt.Errorf("failed to get fraud signal: %w", err)
+	}
+
+	return signal, nil
+}
+
+// Update stores the signal's status and review fields if its stored status is still from
+func (r *FraudSignalRepositoryImpl) Update(ctx context.Context, signal *models.FraudSignal, from models.FraudSignalStatus) error {
+	query := `
+		UPDATE fraud_signals
+		SET status = $1, reviewed_by = $2, review_note = $3, reviewed_at = $4, updated_at = $5
+		WHERE id = $6 AND status = $7
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		signal.Status,
+		signal.ReviewedBy,
+		signal.ReviewNote,
+		signal.ReviewedAt,
+		signal.UpdatedAt,
+		signal.ID,
+		from,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update fraud signal: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// The signal was reviewed by another reviewer
+	if rowsAffected == 0 {
+		return models.ErrFraudSignalStatusConflict
+	}
+
+	return nil
+}
+
+// List retrieves the matching fraud signals, newest first, and the total number of matches
+func (r *FraudSignalRepositoryImpl) List(ctx context.Context, filter models.FraudSignalFilter) ([]*models.FraudSignal, int64, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Rule != "" {
+		args = append(args, filter.Rule)
+		conditions = append(conditions, fmt.Sprintf("rule = $%d", len(args)))
+	}
+	if filter.Severity != "" {
+		args = append(args, filter.Severity)
+		conditions = append(conditions, fmt.Sprintf("severity = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.SubjectID != nil {
+		args = append(args, *filter.SubjectID)
+		conditions = append(conditions, fmt.Sprintf("subject_id = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM fraud_signals `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count fraud signals: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM fraud_signals
+		%s
+		ORDER BY created_at DESC, id
+		LIMIT $%d OFFSET $%d
+	`, fraudSignalColumns, where, len(args)+1, len(args)+2)
+
+	var signals []*models.FraudSignal
+	if err := r.db.SelectContext(ctx, &signals, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list fraud signals: %w", err)
+	}
+
+	return signals, total, nil
+}
@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const incentiveCampaignColumns = `id, name, description, required_trips, bonus_amount, starts_at, ends_at, active,
+	created_at, updated_at`
+
+const incentivePayoutColumns = `id, campaign_id, driver_id, amount, trip_count, created_at`
+
+// IncentiveRepositoryImpl implements the IncentiveRepository interface using PostgreSQL
+type IncentiveRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewIncentiveRepository creates a new instance of IncentiveRepositoryImpl
+func NewIncentiveRepository(db *sqlx.DB) repository.IncentiveRepository {
+	return &IncentiveRepositoryImpl{db: db}
+}
+
+// CreateCampaign creates a new incentive campaign in the database
+func (r *IncentiveRepositoryImpl) CreateCampaign(ctx context.Context, campaign *models.IncentiveCampaign) error {
+	query := `
+		INSERT INTO incentive_campaigns (` + incentiveCampaignColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		campaign.ID,
+		campaign.Name,
+		campaign.Description,
+		campaign.RequiredTrips,
+		campaign.BonusAmount,
+		campaign.StartsAt,
+		campaign.EndsAt,
+		campaign.Active,
+		campaign.CreatedAt,
+		campaign.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create incentive campaign: %w", err)
+	}
+
+	return nil
+}
+
+// GetCampaign retrieves an incentive campaign by ID
+func (r *IncentiveRepositoryImpl) GetCampaign(ctx context.Context, id string) (*models.IncentiveCampaign, error) {
+	query := `SELECT ` + incentiveCampaignColumns + ` FROM incentive_campaigns WHERE id = $1`
+
+	campaign := &models.IncentiveCampaign{}
+	err := r.db.GetContext(ctx, campaign, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "incentive_campaign",
+				ID:       id,
+			}
+		}
+		return nil, fmt.Errorf("failed to get incentive campaign: %w", err)
+	}
+
+	return campaign, nil
+}
+
+// ListCampaigns retrieves the campaigns ordered by start time, only the running ones when runningAt is set
+func (r *IncentiveRepositoryImpl) ListCampaigns(ctx context.Context, runningAt *time.Time) ([]*models.IncentiveCampaign, error) {
+	query := `SELECT ` + incentiveCampaignColumns + ` FROM incentive_campaigns`
+	var args []interface{}
+	if runningAt != nil {
+		query += ` WHERE active AND starts_at <= $1 AND ends_at > $1`
+		args = append(args, *runningAt)
+	}
+	query += ` ORDER BY starts_at, id`
+
+	var campaigns []*models.IncentiveCampaign
+	if err := r.db.SelectContext(ctx, &campaigns, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to list incentive campaigns: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// SetCampaignActive activates or deactivates a campaign
+func (r *IncentiveRepositoryImpl) SetCampaignActive(ctx context.Context, id string, active bool) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE incentive_campaigns SET active = $2, updated_at = $3 WHERE id = $1`,
+		id, active, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to update incentive campaign: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "incentive_campaign",
+			ID:       id,
+		}
+	}
+
+	return nil
+}
+
+// CreditTrip counts the trip towards the driver's progress and records the payout once the quest is complete
+func (r *IncentiveRepositoryImpl) CreditTrip(ctx context.Context, credit *models.IncentiveTripCredit, payout *models.IncentivePayout) (*models.QuestProgress, bool, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		INSERT INTO incentive_trip_credits (campaign_id, trip_id, driver_id, completed_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (campaign_id, trip_id) DO NOTHING
+	`, credit.CampaignID, credit.TripID, credit.DriverID, credit.CompletedAt)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to credit trip: %w", err)
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return nil, false, fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rowsAffected == 0 {
+		return nil, false, fmt.Errorf("trip %s already credited: %w", credit.TripID, models.ErrDuplicateEntry)
+	}
+
+	now := time.Now()
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO incentive_progress (campaign_id, driver_id, completed_trips, updated_at)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (campaign_id, driver_id)
+		DO UPDATE SET completed_trips = incentive_progress.completed_trips + 1, updated_at = excluded.updated_at
+	`, credit.CampaignID, credit.DriverID, now)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to update quest progress: %w", err)
+	}
+
+	progress := &models.QuestProgress{}
+	err = tx.GetContext(ctx, progress, `
+		SELECT campaign_id, driver_id, completed_trips, completed_at, updated_at
+		FROM incentive_progress
+		WHERE campaign_id = $1 AND driver_id = $2
+	`, credit.CampaignID, credit.DriverID)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get quest progress: %w", err)
+	}
+
+	paid := false
+	if progress.CompletedAt == nil && progress.CompletedTrips >= payout.TripCount {
+		result, err := tx.ExecContext(ctx, `
+			INSERT INTO incentive_payouts (`+incentivePayoutColumns+`)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (campaign_id, driver_id) DO NOTHING
+		`, payout.ID, payout.CampaignID, payout.DriverID, payout.Amount, payout.TripCount, payout.CreatedAt)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to record incentive payout: %w", err)
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		paid = rowsAffected > 0
+
+		_, err = tx.ExecContext(ctx, `UPDATE incentive_progress SET completed_at = $3 WHERE campaign_id = $1 AND driver_id = $2`,
+			credit.CampaignID, credit.DriverID, payout.CreatedAt)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to complete quest progress: %w", err)
+		}
+		progress.CompletedAt = &payout.CreatedAt
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit trip credit: %w", err)
+	}
+
+	return progress, paid, nil
+}
+
+// ListProgressByDriver retrieves the driver's progress on every campaign they have credited trips for
+func (r *IncentiveRepositoryImpl) ListProgressByDriver(ctx context.Context, driverID string) ([]*models.QuestProgress, error) {
+	query := `
+		SELECT campaign_id, driver_id, completed_trips, completed_at, updated_at
+		FROM incentive_progress
+		WHERE driver_id = $1
+	`
+
+	var progress []*models.QuestProgress
+	if err := r.db.SelectContext(ctx, &progress, query, driverID); err != nil {
+		return nil, fmt.Errorf("failed to list quest progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+// ListPayoutsByDriver retrieves the driver's payouts, newest first
+func (r *IncentiveRepositoryImpl) ListPayoutsByDriver(ctx context.Context, driverID string) ([]*models.IncentivePayout, error) {
+	query := `
+		SELECT ` + incentivePayoutColumns + `
+		FROM incentive_payouts
+		WHERE driver_id = $1
+		ORDER BY created_at DESC, id
+	`
+
+	var payouts []*models.IncentivePayout
+	if err := r.db.SelectContext(ctx, &payouts, query, driverID); err != nil {
+		return nil, fmt.Errorf("failed to list incentive payouts: %w", err)
+	}
+
+	return payouts, nil
+}
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const integrityRunColumns = `id, window_start, window_end, total_rows, started_at, duration_ms`
+
+// observabilityTimeColumns are the columns the rows of each observability table are bucketed on
+var observabilityTimeColumns = map[string]string{
+	"event_logs":          "timestamp",
+	"actor_messages":      "sent_at",
+	"system_metrics":      "timestamp",
+	"distributed_traces":  "start_time",
+	"traditional_logs":    "timestamp",
+	"traditional_metrics": "timestamp",
+}
+
+// IntegrityRepositoryImpl implements the IntegrityRepository interface using PostgreSQL
+type IntegrityRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewIntegrityRepository creates a new instance of IntegrityRepositoryImpl
+func NewIntegrityRepository(db *sqlx.DB) repository.IntegrityRepository {
+	return &IntegrityRepositoryImpl{db: db}
+}
+
+// ChecksumHours returns the row count and checksum of an observability table's rows per hour in
+// [start, end), oldest first. The checksum sums the first 32 bits of the MD5 of every row ID.
+func (r *IntegrityRepositoryImpl) ChecksumHours(ctx context.Context, table string, start, end time.Time) ([]*models.IntegrityChecksum, error) {
+	column, ok := observabilityTimeColumns[table]
+	if !ok {
+		return nil, fmt.Errorf("table %s is not checksummed", table)
+	}
+
+	query := `
+		SELECT $3::text AS table_name, date_trunc('hour', ` + column + `) AS hour, COUNT(*) AS row_count,
+			COALESCE(SUM(('x' || substr(md5(id::text), 1, 8))::bit(32)::bigint), 0) AS checksum
+		FROM ` + table + `
+		WHERE ` + column + ` >= $1 AND ` + column + ` < $2
+		GROUP BY 2
+		ORDER BY 2
+	`
+
+	var checksums []*models.IntegrityChecksum
+	if err := r.db.SelectContext(ctx, &checksums, query, start, end, table); err != nil {
+		return nil, fmt.Errorf("failed to checksum %s: %w", table, err)
+	}
+
+	return checksums, nil
+}
+
+// CreateRun stores a run with its checksums in one transaction
+func (r *IntegrityRepositoryImpl) CreateRun(ctx context.Context, run *models.IntegrityRun, checksums []*models.IntegrityChecksum) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO integrity_runs (`+integrityRunColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, run.ID, run.WindowStart, run.WindowEnd, run.TotalRows, run.StartedAt, run.DurationMs)
+	if err != nil {
+		return fmt.Errorf("failed to create integrity run: %w", err)
+	}
+
+	for _, checksum := range checksums {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO integrity_checksums (run_id, table_name, hour, row_count, checksum)
+			VALUES ($1, $2, $3, $4, $5)
+		`, run.ID, checksum.TableName, checksum.Hour, checksum.RowCount, checksum.Checksum)
+		if err != nil {
+			return fmt.Errorf("failed to create integrity checksum: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit integrity run: %w", err)
+	}
+	return nil
+}
+
+// GetRun retrieves a run by ID
+func (r *IntegrityRepositoryImpl) GetRun(ctx context.Context, id string) (*models.IntegrityRun, error) {
+	run := &models.IntegrityRun{}
+	err := r.db.GetContext(ctx, run, `SELECT `+integrityRunColumns+` FROM integrity_runs WHERE id = $1`, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "integrity run",
+				ID:       id,
+			}
+		}
+		return nil, fmt.Errorf("failed to get integrity run: %w", err)
+	}
+
+	return run, nil
+}
+
+// GetPreviousRun retrieves the run started last before the run, or nil when there is none
+func (r *IntegrityRepositoryImpl) GetPreviousRun(ctx context.Context, run *models.IntegrityRun) (*models.IntegrityRun, error) {
+	query := `
+		SELECT ` + integrityRunColumns + `
+		FROM integrity_runs
+		WHERE started_at < $1 AND id <> $2
+		ORDER BY started_at DESC, id
+		LIMIT 1
+	`
+
+	previous := &models.IntegrityRun{}
+	if err := r.db.GetContext(ctx, previous, query, run.StartedAt, run.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get previous integrity run: %w", err)
+	}
+
+	return previous, nil
+}
+
+// ListRuns retrieves the latest runs, newest first
+func (r *IntegrityRepositoryImpl) ListRuns(ctx context.Context, limit int) ([]*models.IntegrityRun, error) {
+	query := `
+		SELECT ` + integrityRunColumns + `
+		FROM integrity_runs
+		ORDER BY started_at DESC, id
+		LIMIT $1
+	`
+
+	var runs []*models.IntegrityRun
+	if err := r.db.SelectContext(ctx, &runs, query, limit); err != nil {
+		return nil, fmt.Errorf("failed to list integrity runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// ListChecksums retrieves the checksums of a run by table, then hour
+func (r *IntegrityRepositoryImpl) ListChecksums(ctx context.Context, runID string) ([]*models.IntegrityChecksum, error) {
+	query := `
+		SELECT run_id, table_name, hour, row_count, checksum
+		FROM integrity_checksums
+		WHERE run_id = $1
+		ORDER BY table_name, hour
+	`
+
+	var checksums []*models.IntegrityChecksum
+	if err := r.db.SelectContext(ctx, &checksums, query, runID); err != nil {
+		return nil, fmt.Errorf("failed to list integrity checksums: %w", err)
+	}
+
+	return checksums, nil
+}
+
+// DeleteRunsBefore removes the runs started before the cutoff; their checksums are removed by
+// the foreign key cascade
+func (r *IntegrityRepositoryImpl) DeleteRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM integrity_runs WHERE started_at < $1`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete integrity runs: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,183 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const invoiceColumns = `id, party_type, party_id, party_name, month, status, attempts, last_error, trips,
+	total_amount, csv_key, pdf_key, generated_at, created_at, updated_at`
+
+// InvoiceRepositoryImpl implements the InvoiceRepository interface using PostgreSQL
+type InvoiceRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewInvoiceRepository creates a new instance of InvoiceRepositoryImpl
+func NewInvoiceRepository(db *sqlx.DB) repository.InvoiceRepository {
+	return &InvoiceRepositoryImpl{db: db}
+}
+
+// Create creates a new invoice in the database
+func (r *InvoiceRepositoryImpl) Create(ctx context.Context, invoice *models.Invoice) error {
+	query := `
+		INSERT INTO invoices (` + invoiceColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		invoice.ID,
+		invoice.PartyType,
+		invoice.PartyID,
+		invoice.PartyName,
+		invoice.Month,
+		invoice.Status,
+		invoice.Attempts,
+		invoice.LastError,
+		invoice.Trips,
+		invoice.TotalAmount,
+		invoice.CSVKey,
+		invoice.PDFKey,
+		invoice.GeneratedAt,
+		invoice.CreatedAt,
+		invoice.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return fmt.Errorf("failed to create invoice: %w", models.ErrDuplicateEntry)
+		}
+		return fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an invoice by ID
+func (r *InvoiceRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + ` FROM invoices WHERE id = $1`
+
+	invoice := &models.Invoice{}
+	err := r.db.GetContext(ctx, invoice, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "invoice",
+				ID:       id,
+			}
+		}
+		return nil, fmt.Errorf("failed to get invoice by ID: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// GetByParty retrieves a party's invoice for a month
+func (r *InvoiceRepositoryImpl) GetByParty(ctx context.Context, partyType models.InvoicePartyType, partyID, month string) (*models.Invoice, error) {
+	query := `SELECT ` + invoiceColumns + ` FROM invoices WHERE party_type = $1 AND party_id = $2 AND month = $3`
+
+	invoice := &models.Invoice{}
+	err := r.db.GetContext(ctx, invoice, query, partyType, partyID, month)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "invoice",
+				ID:       fmt.Sprintf("%s %s %s", partyType, partyID, month),
+			}
+		}
+		return nil, fmt.Errorf("failed to get invoice by party: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// Update records the outcome of a generation attempt
+func (r *InvoiceRepositoryImpl) Update(ctx context.Context, invoice *models.Invoice) error {
+	query := `
+		UPDATE invoices
+		SET party_name = $2, status = $3, attempts = $4, last_error = $5, trips = $6, total_amount = $7,
+			csv_key = $8, pdf_key = $9, generated_at = $10, updated_at = $11
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		invoice.ID,
+		invoice.PartyName,
+		invoice.Status,
+		invoice.Attempts,
+		invoice.LastError,
+		invoice.Trips,
+		invoice.TotalAmount,
+		invoice.CSVKey,
+		invoice.PDFKey,
+		invoice.GeneratedAt,
+		invoice.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update invoice: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "invoice",
+			ID:       invoice.ID.String(),
+		}
+	}
+
+	return nil
+}
+
+// List retrieves the invoices matching the filter, newest month first, with the total number of matches
+func (r *InvoiceRepositoryImpl) List(ctx context.Context, filter models.InvoiceFilter) ([]*models.Invoice, int64, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Month != "" {
+		args = append(args, filter.Month)
+		conditions = append(conditions, fmt.Sprintf("month = $%d", len(args)))
+	}
+	if filter.PartyType != "" {
+		args = append(args, filter.PartyType)
+		conditions = append(conditions, fmt.Sprintf("party_type = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM invoices `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count invoices: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM invoices
+		%s
+		ORDER BY month DESC, party_type, party_name, id
+		LIMIT $%d OFFSET $%d
+	`, invoiceColumns, where, len(args)+1, len(args)+2)
+
+	var invoices []*models.Invoice
+	if err := r.db.SelectContext(ctx, &invoices, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	return invoices, total, nil
+}
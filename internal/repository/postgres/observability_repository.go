@@ -6,15 +6,40 @@ import (
 	"fmt"
 	"time"
 
+	"actor-model-observability/internal/experiment"
+	"actor-model-observability/internal/filter"
 	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/payload"
 	"actor-model-observability/internal/repository"
 
 	"github.com/jmoiron/sqlx"
 )
 
+// Observability table columns, in the order list queries select them
+var (
+	actorInstanceColumns = []string{"id", "actor_type", "actor_id", "entity_type", "entity_id", "status", "last_heartbeat", "created_at", "updated_at"}
+	actorMessageColumns  = []string{
+		"id", "trace_id", "span_id", "parent_span_id", "sender_actor_type", "sender_actor_id",
+		"receiver_actor_type", "receiver_actor_id", "entity_type", "entity_id", "message_type", "message_payload",
+		"message_version", "status", "sent_at", "received_at", "processed_at", "processing_duration_ms", "error_message", "retry_count",
+		"effects", "hlc", "created_at",
+	}
+	systemMetricColumns     = []string{"id", "metric_name", "metric_type", "metric_value", "labels", "actor_type", "actor_id", "timestamp", "created_at"}
+	distributedTraceColumns = []string{
+		"id", "trace_id", "span_id", "parent_span_id", "operation_name", "actor_type", "actor_id",
+		"start_time", "end_time", "duration_ms", "status", "tags", "logs", "created_at",
+	}
+	eventLogColumns = []string{
+		"id", "trace_id", "event_type", "event_category", "actor_type", "actor_id", "entity_type",
+		"entity_id", "event_data", "severity", "message", "timestamp", "hlc", "created_at",
+	}
+)
+
 // ObservabilityRepositoryImpl implements the ObservabilityRepository interface using PostgreSQL
 type ObservabilityRepositoryImpl struct {
-	db *sqlx.DB
+	db     *sqlx.DB
+	reader DBReader           // optional; routes list queries to a read replica
+	target *experiment.Target // optional; the experiment schema queries go to
 }
 
 // NewObservabilityRepository creates a new instance of ObservabilityRepositoryImpl
@@ -22,19 +47,44 @@ func NewObservabilityRepository(db *sqlx.DB) repository.ObservabilityRepository
 	return &ObservabilityRepositoryImpl{db: db}
 }
 
+// NewObservabilityRepositoryWithReader creates a ObservabilityRepositoryImpl that writes to db and
+// serves list queries from the connection returned by reader
+func NewObservabilityRepositoryWithReader(db *sqlx.DB, reader DBReader) repository.ObservabilityRepository {
+	return &ObservabilityRepositoryImpl{db: db, reader: reader}
+}
+
+// readDB returns the connection used for read-heavy list queries
+func (r *ObservabilityRepositoryImpl) readDB() *sqlx.DB {
+	if r.reader != nil {
+		return r.reader.Reader()
+	}
+	return r.db
+}
+
+// SetTarget routes queries to the schema of the active experiment
+func (r *ObservabilityRepositoryImpl) SetTarget(target *experiment.Target) {
+	r.target = target
+}
+
+// sql qualifies the tables of query with the schema of the active experiment
+func (r *ObservabilityRepositoryImpl) sql(query string) string {
+	return r.target.Qualify(query)
+}
+
 // Actor Instances methods
 
 // CreateActorInstance creates a new actor instance record
 func (r *ObservabilityRepositoryImpl) CreateActorInstance(ctx context.Context, instance *models.ActorInstance) error {
 	query := `
-		INSERT INTO actor_instances (id, actor_type, actor_id, entity_id, status, last_heartbeat, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO actor_instances (id, actor_type, actor_id, entity_type, entity_id, status, last_heartbeat, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := r.db.ExecContext(ctx, r.sql(query),
 		instance.ID,
 		instance.ActorType,
 		instance.ActorID,
+		instance.EntityType,
 		instance.EntityID,
 		instance.Status,
 		instance.LastHeartbeat,
@@ -52,16 +102,17 @@ func (r *ObservabilityRepositoryImpl) CreateActorInstance(ctx context.Context, i
 // GetActorInstance retrieves an actor instance by ID
 func (r *ObservabilityRepositoryImpl) GetActorInstance(ctx context.Context, id string) (*models.ActorInstance, error) {
 	query := `
-		SELECT id, actor_type, actor_id, entity_id, status, last_heartbeat, created_at, updated_at
+		SELECT id, actor_type, actor_id, entity_type, entity_id, status, last_heartbeat, created_at, updated_at
 		FROM actor_instances
 		WHERE id = $1
 	`
 
 	instance := &models.ActorInstance{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, r.sql(query), id).Scan(
 		&instance.ID,
 		&instance.ActorType,
 		&instance.ActorID,
+		&instance.EntityType,
 		&instance.EntityID,
 		&instance.Status,
 		&instance.LastHeartbeat,
@@ -86,15 +137,16 @@ func (r *ObservabilityRepositoryImpl) GetActorInstance(ctx context.Context, id s
 func (r *ObservabilityRepositoryImpl) UpdateActorInstance(ctx context.Context, instance *models.ActorInstance) error {
 	query := `
 		UPDATE actor_instances
-		SET actor_type = $2, actor_id = $3, entity_id = $4, status = $5, 
-			last_heartbeat = $6, updated_at = $7
+		SET actor_type = $2, actor_id = $3, entity_type = $4, entity_id = $5, status = $6, 
+			last_heartbeat = $7, updated_at = $8
 		WHERE id = $1
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := r.db.ExecContext(ctx, r.sql(query),
 		instance.ID,
 		instance.ActorType,
 		instance.ActorID,
+		instance.EntityType,
 		instance.EntityID,
 		instance.Status,
 		instance.LastHeartbeat,
@@ -122,29 +174,29 @@ func (r *ObservabilityRepositoryImpl) UpdateActorInstance(ctx context.Context, i
 
 // ListActorInstances retrieves actor instances by type with pagination
 func (r *ObservabilityRepositoryImpl) ListActorInstances(ctx context.Context, actorType string, limit, offset int) ([]*models.ActorInstance, error) {
+	columns := selectColumns(ctx, actorInstanceColumns)
+
 	var query string
 	var args []interface{}
 
 	if actorType != "" {
-		query = `
-			SELECT id, actor_type, actor_id, entity_id, status, last_heartbeat, created_at, updated_at
+		query = selectFrom(columns, `
 			FROM actor_instances
 			WHERE actor_type = $1
 			ORDER BY created_at DESC
 			LIMIT $2 OFFSET $3
-		`
+		`)
 		args = []interface{}{actorType, limit, offset}
 	} else {
-		query = `
-			SELECT id, actor_type, actor_id, entity_id, status, last_heartbeat, created_at, updated_at
+		query = selectFrom(columns, `
 			FROM actor_instances
 			ORDER BY created_at DESC
 			LIMIT $1 OFFSET $2
-		`
+		`)
 		args = []interface{}{limit, offset}
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.readDB().QueryContext(ctx, r.sql(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list actor instances: %w", err)
 	}
@@ -153,17 +205,7 @@ func (r *ObservabilityRepositoryImpl) ListActorInstances(ctx context.Context, ac
 	var instances []*models.ActorInstance
 	for rows.Next() {
 		instance := &models.ActorInstance{}
-		err := rows.Scan(
-			&instance.ID,
-			&instance.ActorType,
-			&instance.ActorID,
-			&instance.EntityID,
-			&instance.Status,
-			&instance.LastHeartbeat,
-			&instance.CreatedAt,
-			&instance.UpdatedAt,
-		)
-		if err != nil {
+		if err := rows.Scan(columnTargets(instance, columns)...); err != nil {
 			return nil, fmt.Errorf("failed to scan actor instance: %w", err)
 		}
 		instances = append(instances, instance)
@@ -176,18 +218,146 @@ func (r *ObservabilityRepositoryImpl) ListActorInstances(ctx context.Context, ac
 	return instances, nil
 }
 
+// ListActorInstancesByEntity retrieves the actor instances representing a business entity, such as a driver
+func (r *ObservabilityRepositoryImpl) ListActorInstancesByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.ActorInstance, error) {
+	columns := selectColumns(ctx, actorInstanceColumns)
+
+	query := selectFrom(columns, `
+		FROM actor_instances
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`)
+
+	rows, err := r.readDB().QueryContext(ctx, r.sql(query), entityType, entityID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list actor instances by entity: %w", err)
+	}
+	defer rows.Close()
+
+	var instances []*models.ActorInstance
+	for rows.Next() {
+		instance := &models.ActorInstance{}
+		if err := rows.Scan(columnTargets(instance, columns)...); err != nil {
+			return nil, fmt.Errorf("failed to scan actor instance: %w", err)
+		}
+		instances = append(instances, instance)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating actor instances: %w", err)
+	}
+
+	return instances, nil
+}
+
+// GetActorLifecycleActivity aggregates the lifecycle event logs and processed messages of the
+// actors alive during the window by actor and bucket. An actor spawned before the window is alive
+// at its start unless its last started or stopped event before the window is a stop; actors
+// neither alive at the start nor active in the window are left out.
+func (r *ObservabilityRepositoryImpl) GetActorLifecycleActivity(ctx context.Context, lf models.ActorLifecycleFilter) ([]*models.ActorLifecycleActivity, error) {
+	query := `
+		WITH actors AS (
+			SELECT i.actor_id, i.actor_type, i.entity_type, i.entity_id, i.status, i.created_at AS spawned_at,
+				i.created_at < $1 AND COALESCE((
+					SELECT e.event_type
+					FROM event_logs e
+					WHERE e.actor_id = i.actor_id
+						AND e.event_type IN ('actor_started', 'actor_stopped')
+						AND e.timestamp < $1
+					ORDER BY e.timestamp DESC
+					LIMIT 1
+				), 'actor_started') = 'actor_started' AS alive_at_start
+			FROM actor_instances i
+			WHERE i.created_at < $2 AND ($4 = '' OR i.actor_type = $4)
+		),
+		activity AS (
+			SELECT e.actor_id, e.timestamp AS at,
+				CASE WHEN e.event_type = 'actor_started' THEN 1 ELSE 0 END AS started,
+				CASE WHEN e.event_type = 'actor_stopped' THEN 1 ELSE 0 END AS stopped,
+				CASE WHEN e.event_type = 'actor_failed' THEN 1 ELSE 0 END AS failures,
+				0 AS messages, 0 AS processing_ms
+			FROM event_logs e
+			WHERE e.event_type IN ('actor_started', 'actor_stopped', 'actor_failed')
+				AND e.timestamp >= $1 AND e.timestamp < $2
+			UNION ALL
+			SELECT m.receiver_actor_id, m.processed_at, 0, 0, 0, 1, COALESCE(m.processing_duration_ms, 0)
+			FROM actor_messages m
+			WHERE m.status = 'processed' AND m.processed_at >= $1 AND m.processed_at < $2
+		),
+		buckets AS (
+			SELECT actor_id, FLOOR(EXTRACT(EPOCH FROM (at - $1)) / $3)::int AS bucket,
+				SUM(started) AS started, SUM(stopped) AS stopped, SUM(failures) AS failures,
+				SUM(messages) AS messages, SUM(processing_ms) AS processing_ms
+			FROM activity
+			WHERE actor_id IN (SELECT actor_id FROM actors)
+			GROUP BY 1, 2
+		),
+		selected AS (
+			SELECT a.*
+			FROM actors a
+			WHERE a.alive_at_start OR a.spawned_at >= $1
+				OR EXISTS (SELECT 1 FROM buckets b WHERE b.actor_id = a.actor_id)
+			ORDER BY a.spawned_at DESC, a.actor_id
+			LIMIT $5
+		)
+		SELECT s.actor_id, s.actor_type, s.entity_type, s.entity_id, s.status, s.spawned_at, s.alive_at_start,
+			b.bucket, COALESCE(b.started, 0), COALESCE(b.stopped, 0), COALESCE(b.failures, 0),
+			COALESCE(b.messages, 0), COALESCE(b.processing_ms, 0)
+		FROM selected s
+		LEFT JOIN buckets b ON b.actor_id = s.actor_id
+		ORDER BY s.spawned_at DESC, s.actor_id, b.bucket
+	`
+
+	rows, err := r.readDB().QueryContext(ctx, r.sql(query), lf.Start, lf.End, lf.Bucket.Seconds(), lf.ActorType, lf.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get actor lifecycle activity: %w", err)
+	}
+	defer rows.Close()
+
+	var activity []*models.ActorLifecycleActivity
+	for rows.Next() {
+		a := &models.ActorLifecycleActivity{}
+		err := rows.Scan(
+			&a.ActorID,
+			&a.ActorType,
+			&a.EntityType,
+			&a.EntityID,
+			&a.Status,
+			&a.SpawnedAt,
+			&a.AliveAtStart,
+			&a.Bucket,
+			&a.Started,
+			&a.Stopped,
+			&a.Failures,
+			&a.Messages,
+			&a.ProcessingMs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan actor lifecycle activity: %w", err)
+		}
+		activity = append(activity, a)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating actor lifecycle activity: %w", err)
+	}
+
+	return activity, nil
+}
+
 // Actor Messages methods
 
 // CreateActorMessage creates a new actor message record
 func (r *ObservabilityRepositoryImpl) CreateActorMessage(ctx context.Context, message *models.ActorMessage) error {
 	query := `
 		INSERT INTO actor_messages (id, trace_id, span_id, parent_span_id, sender_actor_type, 
-			sender_actor_id, receiver_actor_type, receiver_actor_id, message_type, message_payload, 
-			status, sent_at, received_at, processed_at, processing_duration_ms, error_message, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+			sender_actor_id, receiver_actor_type, receiver_actor_id, entity_type, entity_id, message_type, message_payload, 
+			message_version, status, sent_at, received_at, processed_at, processing_duration_ms, error_message, retry_count, effects, hlc, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := r.db.ExecContext(ctx, r.sql(query),
 		message.ID,
 		message.TraceID,
 		message.SpanID,
@@ -196,14 +366,20 @@ func (r *ObservabilityRepositoryImpl) CreateActorMessage(ctx context.Context, me
 		message.SenderActorID,
 		message.ReceiverActorType,
 		message.ReceiverActorID,
+		message.EntityType,
+		message.EntityID,
 		message.MessageType,
 		message.MessagePayload,
+		message.MessageVersion,
 		message.Status,
 		message.SentAt,
 		message.ReceivedAt,
 		message.ProcessedAt,
 		message.ProcessingDurationMs,
 		message.ErrorMessage,
+		message.RetryCount,
+		message.Effects,
+		message.HLC,
 		message.CreatedAt,
 	)
 
@@ -218,14 +394,14 @@ func (r *ObservabilityRepositoryImpl) CreateActorMessage(ctx context.Context, me
 func (r *ObservabilityRepositoryImpl) GetActorMessage(ctx context.Context, id string) (*models.ActorMessage, error) {
 	query := `
 		SELECT id, trace_id, span_id, parent_span_id, sender_actor_type, sender_actor_id, 
-			receiver_actor_type, receiver_actor_id, message_type, message_payload, status, 
-			sent_at, received_at, processed_at, processing_duration_ms, error_message, created_at
+			receiver_actor_type, receiver_actor_id, entity_type, entity_id, message_type, message_payload, message_version, 
+			status, sent_at, received_at, processed_at, processing_duration_ms, error_message, retry_count, effects, hlc, created_at
 		FROM actor_messages
 		WHERE id = $1
 	`
 
 	message := &models.ActorMessage{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, r.sql(query), id).Scan(
 		&message.ID,
 		&message.TraceID,
 		&message.SpanID,
@@ -234,14 +410,20 @@ func (r *ObservabilityRepositoryImpl) GetActorMessage(ctx context.Context, id st
 		&message.SenderActorID,
 		&message.ReceiverActorType,
 		&message.ReceiverActorID,
+		&message.EntityType,
+		&message.EntityID,
 		&message.MessageType,
 		&message.MessagePayload,
+		&message.MessageVersion,
 		&message.Status,
 		&message.SentAt,
 		&message.ReceivedAt,
 		&message.ProcessedAt,
 		&message.ProcessingDurationMs,
 		&message.ErrorMessage,
+		&message.RetryCount,
+		&message.Effects,
+		&message.HLC,
 		&message.CreatedAt,
 	)
 
@@ -255,64 +437,57 @@ func (r *ObservabilityRepositoryImpl) GetActorMessage(ctx context.Context, id st
 		return nil, fmt.Errorf("failed to get actor message: %w", err)
 	}
 
+	payload.DecodeActorMessage(message)
 	return message, nil
 }
 
 // ListActorMessages retrieves actor messages with optional filtering
 func (r *ObservabilityRepositoryImpl) ListActorMessages(ctx context.Context, fromActor, toActor string, limit, offset int) ([]*models.ActorMessage, error) {
+	columns := selectColumns(ctx, actorMessageColumns)
+
 	var query string
 	var args []interface{}
 
 	if fromActor != "" && toActor != "" {
-		query = `
-			SELECT id, trace_id, span_id, parent_span_id, sender_actor_type, sender_actor_id, 
-				receiver_actor_type, receiver_actor_id, message_type, message_payload, status, 
-				sent_at, received_at, processed_at, processing_duration_ms, error_message, created_at
+		query = selectFrom(columns, `
 			FROM actor_messages
 			WHERE sender_actor_id = $1 AND receiver_actor_id = $2
 			ORDER BY created_at DESC
 			LIMIT $3 OFFSET $4
-		`
+		`)
 		args = []interface{}{fromActor, toActor, limit, offset}
 	} else if fromActor != "" {
-		query = `
-			SELECT id, trace_id, span_id, parent_span_id, sender_actor_type, sender_actor_id, 
-				receiver_actor_type, receiver_actor_id, message_type, message_payload, status, 
-				sent_at, received_at, processed_at, processing_duration_ms, error_message, created_at
+		query = selectFrom(columns, `
 			FROM actor_messages
 			WHERE sender_actor_id = $1
 			ORDER BY created_at DESC
 			LIMIT $2 OFFSET $3
-		`
+		`)
 		args = []interface{}{fromActor, limit, offset}
 	} else if toActor != "" {
-		query = `
-			SELECT id, trace_id, span_id, parent_span_id, sender_actor_type, sender_actor_id, 
-				receiver_actor_type, receiver_actor_id, message_type, message_payload, status, 
-				sent_at, received_at, processed_at, processing_duration_ms, error_message, created_at
+		query = selectFrom(columns, `
 			FROM actor_messages
 			WHERE receiver_actor_id = $1
 			ORDER BY created_at DESC
 			LIMIT $2 OFFSET $3
-		`
+		`)
 		args = []interface{}{toActor, limit, offset}
 	} else {
-		query = `
-			SELECT id, trace_id, span_id, parent_span_id, sender_actor_type, sender_actor_id, 
-				receiver_actor_type, receiver_actor_id, message_type, message_payload, status, 
-				sent_at, received_at, processed_at, processing_duration_ms, error_message, created_at
+		query = selectFrom(columns, `
 			FROM actor_messages
 			ORDER BY created_at DESC
 			LIMIT $1 OFFSET $2
-		`
+		`)
 		args = []interface{}{limit, offset}
 	}
 
-	return r.scanActorMessages(ctx, query, args...)
+	return r.scanActorMessages(ctx, columns, query, args...)
 }
 
 // GetMessagesByTimeRange retrieves messages within a time range
 func (r *ObservabilityRepositoryImpl) GetMessagesByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.ActorMessage, error) {
+	columns := selectColumns(ctx, actorMessageColumns)
+
 	startTimeParsed, err := time.Parse(time.RFC3339, startTime)
 	if err != nil {
 		return nil, fmt.Errorf("invalid start time format: %w", err)
@@ -323,17 +498,108 @@ func (r *ObservabilityRepositoryImpl) GetMessagesByTimeRange(ctx context.Context
 		return nil, fmt.Errorf("invalid end time format: %w", err)
 	}
 
-	query := `
-		SELECT id, trace_id, span_id, parent_span_id, sender_actor_type, sender_actor_id, 
-			receiver_actor_type, receiver_actor_id, message_type, message_payload, status, 
-			sent_at, received_at, processed_at, processing_duration_ms, error_message, created_at
+	query := selectFrom(columns, `
 		FROM actor_messages
 		WHERE created_at >= $1 AND created_at <= $2
 		ORDER BY created_at DESC
 		LIMIT $3 OFFSET $4
+	`)
+
+	return r.scanActorMessages(ctx, columns, query, startTimeParsed, endTimeParsed, limit, offset)
+}
+
+// GetMessagesByTraceID retrieves all messages of a trace ordered by hybrid timestamp, which
+// sorts in timestamp order as stored, then by send time for messages stored without one
+func (r *ObservabilityRepositoryImpl) GetMessagesByTraceID(ctx context.Context, traceID string) ([]*models.ActorMessage, error) {
+	columns := selectColumns(ctx, actorMessageColumns)
+
+	query := selectFrom(columns, `
+		FROM actor_messages
+		WHERE trace_id = $1
+		ORDER BY hlc ASC, sent_at ASC
+	`)
+
+	return r.scanActorMessages(ctx, columns, query, traceID)
+}
+
+// ListActorMessagesByEntity retrieves the actor messages about a business entity, such as a trip
+func (r *ObservabilityRepositoryImpl) ListActorMessagesByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.ActorMessage, error) {
+	columns := selectColumns(ctx, actorMessageColumns)
+
+	query := selectFrom(columns, `
+		FROM actor_messages
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`)
+
+	return r.scanActorMessages(ctx, columns, query, entityType, entityID, limit, offset)
+}
+
+// FilterActorMessages retrieves the actor messages matching a filter expression
+func (r *ObservabilityRepositoryImpl) FilterActorMessages(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.ActorMessage, error) {
+	columns := selectColumns(ctx, actorMessageColumns)
+
+	where, args := f.SQL(1)
+	query := selectFrom(columns, fmt.Sprintf(`
+		FROM actor_messages
+		WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2))
+
+	return r.scanActorMessages(ctx, columns, query, append(args, limit, offset)...)
+}
+
+// GetMessageStats aggregates the messages created within the last window by message type and
+// receiving actor type. Percentiles are interpolated by percentile_cont, which skips messages
+// without a processing duration.
+func (r *ObservabilityRepositoryImpl) GetMessageStats(ctx context.Context, window time.Duration) ([]*models.MessageStats, error) {
+	query := `
+		SELECT message_type, receiver_actor_type AS actor_type,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE status IN ('failed', 'dead_lettered')) AS failed,
+			percentile_cont(0.50) WITHIN GROUP (ORDER BY processing_duration_ms) AS p50_processing_duration_ms,
+			percentile_cont(0.95) WITHIN GROUP (ORDER BY processing_duration_ms) AS p95_processing_duration_ms,
+			percentile_cont(0.99) WITHIN GROUP (ORDER BY processing_duration_ms) AS p99_processing_duration_ms
+		FROM actor_messages
+		WHERE created_at >= $1
+		GROUP BY message_type, receiver_actor_type
+		ORDER BY message_type, receiver_actor_type
 	`
 
-	return r.scanActorMessages(ctx, query, startTimeParsed, endTimeParsed, limit, offset)
+	rows, err := r.readDB().QueryContext(ctx, r.sql(query), time.Now().Add(-window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get message stats: %w", err)
+	}
+	defer rows.Close()
+
+	var stats []*models.MessageStats
+	for rows.Next() {
+		s := &models.MessageStats{}
+		err := rows.Scan(
+			&s.MessageType,
+			&s.ActorType,
+			&s.Total,
+			&s.Failed,
+			&s.P50ProcessingMs,
+			&s.P95ProcessingMs,
+			&s.P99ProcessingMs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan message stats: %w", err)
+		}
+		if s.Total > 0 {
+			s.FailureRatio = float64(s.Failed) / float64(s.Total)
+		}
+		stats = append(stats, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating message stats: %w", err)
+	}
+
+	return stats, nil
 }
 
 // System Metrics methods
@@ -346,7 +612,7 @@ func (r *ObservabilityRepositoryImpl) CreateSystemMetric(ctx context.Context, me
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := r.db.ExecContext(ctx, r.sql(query),
 		metric.ID,
 		metric.MetricName,
 		metric.MetricType,
@@ -374,7 +640,7 @@ func (r *ObservabilityRepositoryImpl) GetSystemMetric(ctx context.Context, id st
 	`
 
 	metric := &models.SystemMetric{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, r.sql(query), id).Scan(
 		&metric.ID,
 		&metric.MetricName,
 		&metric.MetricType,
@@ -401,33 +667,35 @@ func (r *ObservabilityRepositoryImpl) GetSystemMetric(ctx context.Context, id st
 
 // ListSystemMetrics retrieves system metrics with optional filtering
 func (r *ObservabilityRepositoryImpl) ListSystemMetrics(ctx context.Context, metricType string, limit, offset int) ([]*models.SystemMetric, error) {
+	columns := selectColumns(ctx, systemMetricColumns)
+
 	var query string
 	var args []interface{}
 
 	if metricType != "" {
-		query = `
-			SELECT id, metric_name, metric_type, metric_value, labels, actor_type, actor_id, timestamp, created_at
+		query = selectFrom(columns, `
 			FROM system_metrics
 			WHERE metric_type = $1
 			ORDER BY timestamp DESC
 			LIMIT $2 OFFSET $3
-		`
+		`)
 		args = []interface{}{metricType, limit, offset}
 	} else {
-		query = `
-			SELECT id, metric_name, metric_type, metric_value, labels, actor_type, actor_id, timestamp, created_at
+		query = selectFrom(columns, `
 			FROM system_metrics
 			ORDER BY timestamp DESC
 			LIMIT $1 OFFSET $2
-		`
+		`)
 		args = []interface{}{limit, offset}
 	}
 
-	return r.scanSystemMetrics(ctx, query, args...)
+	return r.scanSystemMetrics(ctx, columns, query, args...)
 }
 
 // GetMetricsByTimeRange retrieves metrics within a time range
 func (r *ObservabilityRepositoryImpl) GetMetricsByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.SystemMetric, error) {
+	columns := selectColumns(ctx, systemMetricColumns)
+
 	startTimeParsed, err := time.Parse(time.RFC3339, startTime)
 	if err != nil {
 		return nil, fmt.Errorf("invalid start time format: %w", err)
@@ -438,15 +706,14 @@ func (r *ObservabilityRepositoryImpl) GetMetricsByTimeRange(ctx context.Context,
 		return nil, fmt.Errorf("invalid end time format: %w", err)
 	}
 
-	query := `
-		SELECT id, metric_name, metric_type, metric_value, labels, actor_type, actor_id, timestamp, created_at
+	query := selectFrom(columns, `
 		FROM system_metrics
 		WHERE timestamp >= $1 AND timestamp <= $2
 		ORDER BY timestamp DESC
 		LIMIT $3 OFFSET $4
-	`
+	`)
 
-	return r.scanSystemMetrics(ctx, query, startTimeParsed, endTimeParsed, limit, offset)
+	return r.scanSystemMetrics(ctx, columns, query, startTimeParsed, endTimeParsed, limit, offset)
 }
 
 // Distributed Traces methods
@@ -459,7 +726,7 @@ func (r *ObservabilityRepositoryImpl) CreateDistributedTrace(ctx context.Context
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := r.db.ExecContext(ctx, r.sql(query),
 		trace.ID,
 		trace.TraceID,
 		trace.SpanID,
@@ -493,7 +760,7 @@ func (r *ObservabilityRepositoryImpl) GetDistributedTrace(ctx context.Context, i
 	`
 
 	trace := &models.DistributedTrace{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, r.sql(query), id).Scan(
 		&trace.ID,
 		&trace.TraceID,
 		&trace.SpanID,
@@ -525,44 +792,57 @@ func (r *ObservabilityRepositoryImpl) GetDistributedTrace(ctx context.Context, i
 
 // GetTracesByTraceID retrieves all spans for a specific trace ID
 func (r *ObservabilityRepositoryImpl) GetTracesByTraceID(ctx context.Context, traceID string) ([]*models.DistributedTrace, error) {
-	query := `
-		SELECT id, trace_id, span_id, parent_span_id, operation_name, actor_type, actor_id, 
-			start_time, end_time, duration_ms, status, tags, logs, created_at
+	columns := selectColumns(ctx, distributedTraceColumns)
+
+	query := selectFrom(columns, `
 		FROM distributed_traces
 		WHERE trace_id = $1
 		ORDER BY start_time ASC
-	`
+	`)
 
-	return r.scanDistributedTraces(ctx, query, traceID)
+	return r.scanDistributedTraces(ctx, columns, query, traceID)
 }
 
 // ListDistributedTraces retrieves distributed traces with optional filtering
 func (r *ObservabilityRepositoryImpl) ListDistributedTraces(ctx context.Context, operation string, limit, offset int) ([]*models.DistributedTrace, error) {
+	columns := selectColumns(ctx, distributedTraceColumns)
+
 	var query string
 	var args []interface{}
 
 	if operation != "" {
-		query = `
-			SELECT id, trace_id, span_id, parent_span_id, operation_name, actor_type, actor_id, 
-				start_time, end_time, duration_ms, status, tags, logs, created_at
+		query = selectFrom(columns, `
 			FROM distributed_traces
 			WHERE operation_name = $1
 			ORDER BY start_time DESC
 			LIMIT $2 OFFSET $3
-		`
+		`)
 		args = []interface{}{operation, limit, offset}
 	} else {
-		query = `
-			SELECT id, trace_id, span_id, parent_span_id, operation_name, actor_type, actor_id, 
-				start_time, end_time, duration_ms, status, tags, logs, created_at
+		query = selectFrom(columns, `
 			FROM distributed_traces
 			ORDER BY start_time DESC
 			LIMIT $1 OFFSET $2
-		`
+		`)
 		args = []interface{}{limit, offset}
 	}
 
-	return r.scanDistributedTraces(ctx, query, args...)
+	return r.scanDistributedTraces(ctx, columns, query, args...)
+}
+
+// FilterDistributedTraces retrieves the distributed trace spans matching a filter expression
+func (r *ObservabilityRepositoryImpl) FilterDistributedTraces(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.DistributedTrace, error) {
+	columns := selectColumns(ctx, distributedTraceColumns)
+
+	where, args := f.SQL(1)
+	query := selectFrom(columns, fmt.Sprintf(`
+		FROM distributed_traces
+		WHERE %s
+		ORDER BY start_time DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2))
+
+	return r.scanDistributedTraces(ctx, columns, query, append(args, limit, offset)...)
 }
 
 // Event Logs methods
@@ -571,11 +851,11 @@ func (r *ObservabilityRepositoryImpl) ListDistributedTraces(ctx context.Context,
 func (r *ObservabilityRepositoryImpl) CreateEventLog(ctx context.Context, log *models.EventLog) error {
 	query := `
 		INSERT INTO event_logs (id, trace_id, event_type, event_category, actor_type, actor_id, 
-			entity_type, entity_id, event_data, severity, message, timestamp, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+			entity_type, entity_id, event_data, severity, message, timestamp, hlc, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
 
-	_, err := r.db.ExecContext(ctx, query,
+	_, err := r.db.ExecContext(ctx, r.sql(query),
 		log.ID,
 		log.TraceID,
 		log.EventType,
@@ -588,6 +868,7 @@ func (r *ObservabilityRepositoryImpl) CreateEventLog(ctx context.Context, log *m
 		log.Severity,
 		log.Message,
 		log.Timestamp,
+		log.HLC,
 		log.CreatedAt,
 	)
 
@@ -602,13 +883,13 @@ func (r *ObservabilityRepositoryImpl) CreateEventLog(ctx context.Context, log *m
 func (r *ObservabilityRepositoryImpl) GetEventLog(ctx context.Context, id string) (*models.EventLog, error) {
 	query := `
 		SELECT id, trace_id, event_type, event_category, actor_type, actor_id, entity_type, 
-			entity_id, event_data, severity, message, timestamp, created_at
+			entity_id, event_data, severity, message, timestamp, hlc, created_at
 		FROM event_logs
 		WHERE id = $1
 	`
 
 	log := &models.EventLog{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := r.db.QueryRowContext(ctx, r.sql(query), id).Scan(
 		&log.ID,
 		&log.TraceID,
 		&log.EventType,
@@ -621,6 +902,7 @@ func (r *ObservabilityRepositoryImpl) GetEventLog(ctx context.Context, id string
 		&log.Severity,
 		&log.Message,
 		&log.Timestamp,
+		&log.HLC,
 		&log.CreatedAt,
 	)
 
@@ -634,60 +916,57 @@ func (r *ObservabilityRepositoryImpl) GetEventLog(ctx context.Context, id string
 		return nil, fmt.Errorf("failed to get event log: %w", err)
 	}
 
+	payload.DecodeEventLog(log)
 	return log, nil
 }
 
 // ListEventLogs retrieves event logs with optional filtering
 func (r *ObservabilityRepositoryImpl) ListEventLogs(ctx context.Context, eventType, source string, limit, offset int) ([]*models.EventLog, error) {
+	columns := selectColumns(ctx, eventLogColumns)
+
 	var query string
 	var args []interface{}
 
 	if eventType != "" && source != "" {
-		query = `
-			SELECT id, trace_id, event_type, event_category, actor_type, actor_id, entity_type, 
-				entity_id, event_data, severity, message, timestamp, created_at
+		query = selectFrom(columns, `
 			FROM event_logs
 			WHERE event_type = $1 AND actor_id = $2
 			ORDER BY timestamp DESC
 			LIMIT $3 OFFSET $4
-		`
+		`)
 		args = []interface{}{eventType, source, limit, offset}
 	} else if eventType != "" {
-		query = `
-			SELECT id, trace_id, event_type, event_category, actor_type, actor_id, entity_type, 
-				entity_id, event_data, severity, message, timestamp, created_at
+		query = selectFrom(columns, `
 			FROM event_logs
 			WHERE event_type = $1
 			ORDER BY timestamp DESC
 			LIMIT $2 OFFSET $3
-		`
+		`)
 		args = []interface{}{eventType, limit, offset}
 	} else if source != "" {
-		query = `
-			SELECT id, trace_id, event_type, event_category, actor_type, actor_id, entity_type, 
-				entity_id, event_data, severity, message, timestamp, created_at
+		query = selectFrom(columns, `
 			FROM event_logs
 			WHERE actor_id = $1
 			ORDER BY timestamp DESC
 			LIMIT $2 OFFSET $3
-		`
+		`)
 		args = []interface{}{source, limit, offset}
 	} else {
-		query = `
-			SELECT id, trace_id, event_type, event_category, actor_type, actor_id, entity_type, 
-				entity_id, event_data, severity, message, timestamp, created_at
+		query = selectFrom(columns, `
 			FROM event_logs
 			ORDER BY timestamp DESC
 			LIMIT $1 OFFSET $2
-		`
+		`)
 		args = []interface{}{limit, offset}
 	}
 
-	return r.scanEventLogs(ctx, query, args...)
+	return r.scanEventLogs(ctx, columns, query, args...)
 }
 
 // GetEventLogsByTimeRange retrieves event logs within a time range
 func (r *ObservabilityRepositoryImpl) GetEventLogsByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.EventLog, error) {
+	columns := selectColumns(ctx, eventLogColumns)
+
 	startTimeParsed, err := time.Parse(time.RFC3339, startTime)
 	if err != nil {
 		return nil, fmt.Errorf("invalid start time format: %w", err)
@@ -698,22 +977,49 @@ func (r *ObservabilityRepositoryImpl) GetEventLogsByTimeRange(ctx context.Contex
 		return nil, fmt.Errorf("invalid end time format: %w", err)
 	}
 
-	query := `
-		SELECT id, trace_id, event_type, event_category, actor_type, actor_id, entity_type, 
-			entity_id, event_data, severity, message, timestamp, created_at
+	query := selectFrom(columns, `
 		FROM event_logs
 		WHERE timestamp >= $1 AND timestamp <= $2
 		ORDER BY timestamp DESC
 		LIMIT $3 OFFSET $4
-	`
+	`)
+
+	return r.scanEventLogs(ctx, columns, query, startTimeParsed, endTimeParsed, limit, offset)
+}
+
+// ListEventLogsByEntity retrieves the event logs recorded for a business entity, such as a trip
+func (r *ObservabilityRepositoryImpl) ListEventLogsByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.EventLog, error) {
+	columns := selectColumns(ctx, eventLogColumns)
+
+	query := selectFrom(columns, `
+		FROM event_logs
+		WHERE entity_type = $1 AND entity_id = $2
+		ORDER BY timestamp DESC
+		LIMIT $3 OFFSET $4
+	`)
+
+	return r.scanEventLogs(ctx, columns, query, entityType, entityID, limit, offset)
+}
+
+// FilterEventLogs retrieves the event logs matching a filter expression
+func (r *ObservabilityRepositoryImpl) FilterEventLogs(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.EventLog, error) {
+	columns := selectColumns(ctx, eventLogColumns)
+
+	where, args := f.SQL(1)
+	query := selectFrom(columns, fmt.Sprintf(`
+		FROM event_logs
+		WHERE %s
+		ORDER BY timestamp DESC
+		LIMIT $%d OFFSET $%d
+	`, where, len(args)+1, len(args)+2))
 
-	return r.scanEventLogs(ctx, query, startTimeParsed, endTimeParsed, limit, offset)
+	return r.scanEventLogs(ctx, columns, query, append(args, limit, offset)...)
 }
 
 // Helper methods for scanning results
 
-func (r *ObservabilityRepositoryImpl) scanActorMessages(ctx context.Context, query string, args ...interface{}) ([]*models.ActorMessage, error) {
-	rows, err := r.db.QueryContext(ctx, query, args...)
+func (r *ObservabilityRepositoryImpl) scanActorMessages(ctx context.Context, columns []string, query string, args ...interface{}) ([]*models.ActorMessage, error) {
+	rows, err := r.readDB().QueryContext(ctx, r.sql(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -722,28 +1028,10 @@ func (r *ObservabilityRepositoryImpl) scanActorMessages(ctx context.Context, que
 	var messages []*models.ActorMessage
 	for rows.Next() {
 		message := &models.ActorMessage{}
-		err := rows.Scan(
-			&message.ID,
-			&message.TraceID,
-			&message.SpanID,
-			&message.ParentSpanID,
-			&message.SenderActorType,
-			&message.SenderActorID,
-			&message.ReceiverActorType,
-			&message.ReceiverActorID,
-			&message.MessageType,
-			&message.MessagePayload,
-			&message.Status,
-			&message.SentAt,
-			&message.ReceivedAt,
-			&message.ProcessedAt,
-			&message.ProcessingDurationMs,
-			&message.ErrorMessage,
-			&message.CreatedAt,
-		)
-		if err != nil {
+		if err := rows.Scan(columnTargets(message, columns)...); err != nil {
 			return nil, fmt.Errorf("failed to scan actor message: %w", err)
 		}
+		payload.DecodeActorMessage(message)
 		messages = append(messages, message)
 	}
 
@@ -754,8 +1042,8 @@ func (r *ObservabilityRepositoryImpl) scanActorMessages(ctx context.Context, que
 	return messages, nil
 }
 
-func (r *ObservabilityRepositoryImpl) scanSystemMetrics(ctx context.Context, query string, args ...interface{}) ([]*models.SystemMetric, error) {
-	rows, err := r.db.QueryContext(ctx, query, args...)
+func (r *ObservabilityRepositoryImpl) scanSystemMetrics(ctx context.Context, columns []string, query string, args ...interface{}) ([]*models.SystemMetric, error) {
+	rows, err := r.readDB().QueryContext(ctx, r.sql(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -764,18 +1052,7 @@ func (r *ObservabilityRepositoryImpl) scanSystemMetrics(ctx context.Context, que
 	var metrics []*models.SystemMetric
 	for rows.Next() {
 		metric := &models.SystemMetric{}
-		err := rows.Scan(
-			&metric.ID,
-			&metric.MetricName,
-			&metric.MetricType,
-			&metric.MetricValue,
-			&metric.Labels,
-			&metric.ActorType,
-			&metric.ActorID,
-			&metric.Timestamp,
-			&metric.CreatedAt,
-		)
-		if err != nil {
+		if err := rows.Scan(columnTargets(metric, columns)...); err != nil {
 			return nil, fmt.Errorf("failed to scan system metric: %w", err)
 		}
 		metrics = append(metrics, metric)
@@ -788,8 +1065,8 @@ func (r *ObservabilityRepositoryImpl) scanSystemMetrics(ctx context.Context, que
 	return metrics, nil
 }
 
-func (r *ObservabilityRepositoryImpl) scanDistributedTraces(ctx context.Context, query string, args ...interface{}) ([]*models.DistributedTrace, error) {
-	rows, err := r.db.QueryContext(ctx, query, args...)
+func (r *ObservabilityRepositoryImpl) scanDistributedTraces(ctx context.Context, columns []string, query string, args ...interface{}) ([]*models.DistributedTrace, error) {
+	rows, err := r.readDB().QueryContext(ctx, r.sql(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -798,23 +1075,7 @@ func (r *ObservabilityRepositoryImpl) scanDistributedTraces(ctx context.Context,
 	var traces []*models.DistributedTrace
 	for rows.Next() {
 		trace := &models.DistributedTrace{}
-		err := rows.Scan(
-			&trace.ID,
-			&trace.TraceID,
-			&trace.SpanID,
-			&trace.ParentSpanID,
-			&trace.OperationName,
-			&trace.ActorType,
-			&trace.ActorID,
-			&trace.StartTime,
-			&trace.EndTime,
-			&trace.DurationMs,
-			&trace.Status,
-			&trace.Tags,
-			&trace.Logs,
-			&trace.CreatedAt,
-		)
-		if err != nil {
+		if err := rows.Scan(columnTargets(trace, columns)...); err != nil {
 			return nil, fmt.Errorf("failed to scan distributed trace: %w", err)
 		}
 		traces = append(traces, trace)
@@ -827,8 +1088,8 @@ func (r *ObservabilityRepositoryImpl) scanDistributedTraces(ctx context.Context,
 	return traces, nil
 }
 
-func (r *ObservabilityRepositoryImpl) scanEventLogs(ctx context.Context, query string, args ...interface{}) ([]*models.EventLog, error) {
-	rows, err := r.db.QueryContext(ctx, query, args...)
+func (r *ObservabilityRepositoryImpl) scanEventLogs(ctx context.Context, columns []string, query string, args ...interface{}) ([]*models.EventLog, error) {
+	rows, err := r.readDB().QueryContext(ctx, r.sql(query), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -837,24 +1098,10 @@ func (r *ObservabilityRepositoryImpl) scanEventLogs(ctx context.Context, query s
 	var logs []*models.EventLog
 	for rows.Next() {
 		log := &models.EventLog{}
-		err := rows.Scan(
-			&log.ID,
-			&log.TraceID,
-			&log.EventType,
-			&log.EventCategory,
-			&log.ActorType,
-			&log.ActorID,
-			&log.EntityType,
-			&log.EntityID,
-			&log.EventData,
-			&log.Severity,
-			&log.Message,
-			&log.Timestamp,
-			&log.CreatedAt,
-		)
-		if err != nil {
+		if err := rows.Scan(columnTargets(log, columns)...); err != nil {
 			return nil, fmt.Errorf("failed to scan event log: %w", err)
 		}
+		payload.DecodeEventLog(log)
 		logs = append(logs, log)
 	}
 
@@ -25,8 +25,10 @@ func NewPassengerRepository(db *sqlx.DB) repository.PassengerRepository {
 // Create creates a new passenger in the database
 func (r *PassengerRepositoryImpl) Create(ctx context.Context, passenger *models.Passenger) error {
 	query := `
-		INSERT INTO passengers (id, user_id, rating, total_trips, created_at, updated_at)
-		VALUES (:id, :user_id, :rating, :total_trips, :created_at, :updated_at)
+		INSERT INTO passengers (id, user_id, rating, total_trips, corporate_account_id,
+			quiet_ride, pet_friendly, accessible_vehicle, min_driver_rating, created_at, updated_at)
+		VALUES (:id, :user_id, :rating, :total_trips, :corporate_account_id,
+			:quiet_ride, :pet_friendly, :accessible_vehicle, :min_driver_rating, :created_at, :updated_at)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -34,6 +36,11 @@ func (r *PassengerRepositoryImpl) Create(ctx context.Context, passenger *models.
 		passenger.UserID,
 		passenger.Rating,
 		passenger.TotalTrips,
+		passenger.CorporateAccountID,
+		passenger.QuietRide,
+		passenger.PetFriendly,
+		passenger.AccessibleVehicle,
+		passenger.MinDriverRating,
 		passenger.CreatedAt,
 		passenger.UpdatedAt,
 	)
@@ -66,7 +73,8 @@ func (r *PassengerRepositoryImpl) Create(ctx context.Context, passenger *models.
 // GetByID retrieves a passenger by ID
 func (r *PassengerRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Passenger, error) {
 	query := `
-		SELECT id, user_id, rating, total_trips, created_at, updated_at
+		SELECT id, user_id, rating, total_trips, corporate_account_id,
+			quiet_ride, pet_friendly, accessible_vehicle, min_driver_rating, created_at, updated_at
 		FROM passengers
 		WHERE id = $1
 	`
@@ -77,6 +85,11 @@ func (r *PassengerRepositoryImpl) GetByID(ctx context.Context, id string) (*mode
 		&passenger.UserID,
 		&passenger.Rating,
 		&passenger.TotalTrips,
+		&passenger.CorporateAccountID,
+		&passenger.QuietRide,
+		&passenger.PetFriendly,
+		&passenger.AccessibleVehicle,
+		&passenger.MinDriverRating,
 		&passenger.CreatedAt,
 		&passenger.UpdatedAt,
 	)
@@ -97,7 +110,8 @@ func (r *PassengerRepositoryImpl) GetByID(ctx context.Context, id string) (*mode
 // GetByUserID retrieves a passenger by user ID
 func (r *PassengerRepositoryImpl) GetByUserID(ctx context.Context, userID string) (*models.Passenger, error) {
 	query := `
-		SELECT id, user_id, rating, total_trips, created_at, updated_at
+		SELECT id, user_id, rating, total_trips, corporate_account_id,
+			quiet_ride, pet_friendly, accessible_vehicle, min_driver_rating, created_at, updated_at
 		FROM passengers
 		WHERE user_id = $1
 	`
@@ -108,6 +122,11 @@ func (r *PassengerRepositoryImpl) GetByUserID(ctx context.Context, userID string
 		&passenger.UserID,
 		&passenger.Rating,
 		&passenger.TotalTrips,
+		&passenger.CorporateAccountID,
+		&passenger.QuietRide,
+		&passenger.PetFriendly,
+		&passenger.AccessibleVehicle,
+		&passenger.MinDriverRating,
 		&passenger.CreatedAt,
 		&passenger.UpdatedAt,
 	)
@@ -129,7 +148,8 @@ func (r *PassengerRepositoryImpl) GetByUserID(ctx context.Context, userID string
 func (r *PassengerRepositoryImpl) Update(ctx context.Context, passenger *models.Passenger) error {
 	query := `
 		UPDATE passengers
-		SET rating = $2, total_trips = $3, updated_at = $4
+		SET rating = $2, total_trips = $3, quiet_ride = $4, pet_friendly = $5, accessible_vehicle = $6,
+			min_driver_rating = $7, updated_at = $8
 		WHERE id = $1
 	`
 
@@ -137,6 +157,10 @@ func (r *PassengerRepositoryImpl) Update(ctx context.Context, passenger *models.
 		passenger.ID,
 		passenger.Rating,
 		passenger.TotalTrips,
+		passenger.QuietRide,
+		passenger.PetFriendly,
+		passenger.AccessibleVehicle,
+		passenger.MinDriverRating,
 		passenger.UpdatedAt,
 	)
 
@@ -186,7 +210,8 @@ func (r *PassengerRepositoryImpl) Delete(ctx context.Context, id string) error {
 // List retrieves a list of passengers with pagination
 func (r *PassengerRepositoryImpl) List(ctx context.Context, limit, offset int) ([]*models.Passenger, error) {
 	query := `
-		SELECT id, user_id, rating, total_trips, created_at, updated_at
+		SELECT id, user_id, rating, total_trips, corporate_account_id,
+			quiet_ride, pet_friendly, accessible_vehicle, min_driver_rating, created_at, updated_at
 		FROM passengers
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
@@ -206,6 +231,11 @@ func (r *PassengerRepositoryImpl) List(ctx context.Context, limit, offset int) (
 			&passenger.UserID,
 			&passenger.Rating,
 			&passenger.TotalTrips,
+			&passenger.CorporateAccountID,
+			&passenger.QuietRide,
+			&passenger.PetFriendly,
+			&passenger.AccessibleVehicle,
+			&passenger.MinDriverRating,
 			&passenger.CreatedAt,
 			&passenger.UpdatedAt,
 		)
@@ -0,0 +1,242 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const tripPaymentColumns = `id, trip_id, kind, method, amount, reference, recorded_by, created_at`
+
+const paymentDiscrepancyColumns = `id, trip_id, type, expected_amount, paid_amount, status, resolution, payment_id,
+	resolved_by, resolution_note, resolved_at, detected_at, updated_at`
+
+// PaymentRepositoryImpl implements the PaymentRepository interface using PostgreSQL
+type PaymentRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewPaymentRepository creates a new instance of PaymentRepositoryImpl
+func NewPaymentRepository(db *sqlx.DB) repository.PaymentRepository {
+	return &PaymentRepositoryImpl{db: db}
+}
+
+// CreatePayment appends an entry to a trip's payments ledger
+func (r *PaymentRepositoryImpl) CreatePayment(ctx context.Context, payment *models.TripPayment) error {
+	query := `
+		INSERT INTO trip_payments (` + tripPaymentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		payment.ID,
+		payment.TripID,
+		payment.Kind,
+		payment.Method,
+		payment.Amount,
+		payment.Reference,
+		payment.RecordedBy,
+		payment.CreatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" { // foreign_key_violation
+			return &models.NotFoundError{
+				Resource: "trip",
+				ID:       payment.TripID.String(),
+			}
+		}
+		return fmt.Errorf("failed to create trip payment: %w", err)
+	}
+
+	return nil
+}
+
+// ListPaymentsByTrip retrieves the trip's payments ledger, oldest first
+func (r *PaymentRepositoryImpl) ListPaymentsByTrip(ctx context.Context, tripID string) ([]*models.TripPayment, error) {
+	query := `
+		SELECT ` + tripPaymentColumns + `
+		FROM trip_payments
+		WHERE trip_id = $1
+		ORDER BY created_at, id
+	`
+
+	var payments []*models.TripPayment
+	if err := r.db.SelectContext(ctx, &payments, query, tripID); err != nil {
+		return nil, fmt.Errorf("failed to list trip payments: %w", err)
+	}
+
+	return payments, nil
+}
+
+// ListBalances retrieves the fare and net payments of the trips completed in [start, end), oldest
+// first
+func (r *PaymentRepositoryImpl) ListBalances(ctx context.Context, start, end time.Time) ([]*models.TripPaymentBalance, error) {
+	query := `
+		SELECT t.id AS trip_id,
+			COALESCE(t.fare_amount, 0) AS fare,
+			COALESCE(SUM(CASE WHEN p.kind = 'refund' THEN -p.amount ELSE p.amount END), 0) AS paid,
+			COUNT(p.id) AS payments,
+			t.completed_at
+		FROM trips t
+		LEFT JOIN trip_payments p ON p.trip_id = t.id
+		WHERE t.status = 'completed' AND t.completed_at >= $1 AND t.completed_at < $2
+		GROUP BY t.id, t.fare_amount, t.completed_at
+		ORDER BY t.completed_at, t.id
+	`
+
+	var balances []*models.TripPaymentBalance
+	if err := r.db.SelectContext(ctx, &balances, query, start, end); err != nil {
+		return nil, fmt.Errorf("failed to list trip payment balances: %w", err)
+	}
+
+	return balances, nil
+}
+
+// CreateDiscrepancy creates a new payment discrepancy in the database
+func (r *PaymentRepositoryImpl) CreateDiscrepancy(ctx context.Context, discrepancy *models.PaymentDiscrepancy) error {
+	query := `
+		INSERT INTO payment_discrepancies (` + paymentDiscrepancyColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		discrepancy.ID,
+		discrepancy.TripID,
+		discrepancy.Type,
+		discrepancy.ExpectedAmount,
+		discrepancy.PaidAmount,
+		discrepancy.Status,
+		discrepancy.Resolution,
+		discrepancy.PaymentID,
+		discrepancy.ResolvedBy,
+		discrepancy.ResolutionNote,
+		discrepancy.ResolvedAt,
+		discrepancy.DetectedAt,
+		discrepancy.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok {
+			switch pqErr.Code {
+			case "23505": // unique_violation
+				return fmt.Errorf("failed to create payment discrepancy: %w", models.ErrDuplicateEntry)
+			case "23503": // foreign_key_violation
+				return &models.NotFoundError{
+					Resource: "trip",
+					ID:       discrepancy.TripID.String(),
+				}
+			}
+		}
+		return fmt.Errorf("failed to create payment discrepancy: %w", err)
+	}
+
+	return nil
+}
+
+// GetDiscrepancy retrieves a payment discrepancy by ID
+func (r *PaymentRepositoryImpl) GetDiscrepancy(ctx context.Context, id string) (*models.PaymentDiscrepancy, error) {
+	query := `SELECT ` + paymentDiscrepancyColumns + ` FROM payment_discrepancies WHERE id = $1`
+
+	discrepancy := &models.PaymentDiscrepancy{}
+	err := r.db.GetContext(ctx, discrepancy, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "payment_discrepancy",
+				ID:       id,
+			}
+		}
+		return nil, fmt.Errorf("failed to get payment discrepancy: %w", err)
+	}
+
+	return discrepancy, nil
+}
+
+// UpdateDiscrepancy stores the discrepancy's status and resolution fields if its stored status is
+// still from
+func (r *PaymentRepositoryImpl) UpdateDiscrepancy(ctx context.Context, discrepancy *models.PaymentDiscrepancy, from models.PaymentDiscrepancyStatus) error {
+	query := `
+		UPDATE payment_discrepancies
+		SET status = $1, resolution = $2, payment_id = $3, resolved_by = $4, resolution_note = $5,
+			resolved_at = $6, updated_at = $7
+		WHERE id = $8 AND status = $9
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		discrepancy.Status,
+		discrepancy.Resolution,
+		discrepancy.PaymentID,
+		discrepancy.ResolvedBy,
+		discrepancy.ResolutionNote,
+		discrepancy.ResolvedAt,
+		discrepancy.UpdatedAt,
+		discrepancy.ID,
+		from,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update payment discrepancy: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// The discrepancy was resolved by another admin, or by a payment settling the trip
+	if rowsAffected == 0 {
+		return models.ErrPaymentDiscrepancyStatusConflict
+	}
+
+	return nil
+}
+
+// ListDiscrepancies retrieves the matching payment discrepancies, newest first, and the total
+// number of matches
+func (r *PaymentRepositoryImpl) ListDiscrepancies(ctx context.Context, filter models.PaymentDiscrepancyFilter) ([]*models.PaymentDiscrepancy, int64, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		conditions = append(conditions, fmt.Sprintf("type = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.TripID != nil {
+		args = append(args, *filter.TripID)
+		conditions = append(conditions, fmt.Sprintf("trip_id = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM payment_discrepancies `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count payment discrepancies: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM payment_discrepancies
+		%s
+		ORDER BY detected_at DESC, id
+		LIMIT $%d OFFSET $%d
+	`, paymentDiscrepancyColumns, where, len(args)+1, len(args)+2)
+
+	var discrepancies []*models.PaymentDiscrepancy
+	if err := r.db.SelectContext(ctx, &discrepancies, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list payment discrepancies: %w", err)
+	}
+
+	return discrepancies, total, nil
+}
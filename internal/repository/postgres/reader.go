@@ -0,0 +1,9 @@
+package postgres
+
+import "github.com/jmoiron/sqlx"
+
+// DBReader provides the connection to use for read-only queries, such as a
+// read replica that falls back to the primary when unhealthy
+type DBReader interface {
+	Reader() *sqlx.DB
+}
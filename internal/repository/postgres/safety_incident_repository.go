@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const safetyIncidentColumns = `id, trip_id, reporter_type, reporter_id, latitude, longitude, location_source, trip_status,
+	note, snapshot, status, reviewed_by, resolution_note, reviewed_at, closed_at, created_at, updated_at`
+
+// SafetyIncidentRepositoryImpl implements the SafetyIncidentRepository interface using PostgreSQL
+type SafetyIncidentRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewSafetyIncidentRepository creates a new instance of SafetyIncidentRepositoryImpl
+func NewSafetyIncidentRepository(db *sqlx.DB) repository.SafetyIncidentRepository {
+	return &SafetyIncidentRepositoryImpl{db: db}
+}
+
+// Create creates a new safety incident in the database
+func (r *SafetyIncidentRepositoryImpl) Create(ctx context.Context, incident *models.SafetyIncident) error {
+	query := `
+		INSERT INTO safety_incidents (` + safetyIncidentColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		incident.ID,
+		incident.TripID,
+		incident.ReporterType,
+		incident.ReporterID,
+		incident.Latitude,
+		incident.Longitude,
+		incident.LocationSource,
+		incident.TripStatus,
+		incident.Note,
+		incident.Snapshot,
+		incident.Status,
+		incident.ReviewedBy,
+		incident.ResolutionNote,
+		incident.ReviewedAt,
+		incident.ClosedAt,
+		incident.CreatedAt,
+		incident.UpdatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23503" { // foreign_key_violation
+			return &models.NotFoundError{
+				Resource: "trip",
+				ID:       incident.TripID.String(),
+			}
+		}
+		return fmt.Errorf("failed to create safety incident: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a safety incident by ID
+func (r *SafetyIncidentRepositoryImpl) GetByID(ctx context.Context, id string) (*models.SafetyIncident, error) {
+	query := `SELECT ` + safetyIncidentColumns + ` FROM safety_incidents WHERE id = $1`
+
+	incident := &models.SafetyIncident{}
+	err := r.db.GetContext(ctx, incident, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "safety_incident",
+				ID:       id,
+			}
+		}
+		return nil, fmt.Errorf("failed to get safety incident: %w", err)
+	}
+
+	return incident, nil
+}
+
+// Update stores the incident's status and review fields if its stored status is still from
+func (r *SafetyIncidentRepositoryImpl) Update(ctx context.Context, incident *models.SafetyIncident, from models.SafetyIncidentStatus) error {
+	query := `
+		UPDATE safety_incidents
+		SET status = $1, reviewed_by = $2, resolution_note = $3, reviewed_at = $4, closed_at = $5, updated_at = $6
+		WHERE id = $7 AND status = $8
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		incident.Status,
+		incident.ReviewedBy,
+		incident.ResolutionNote,
+		incident.ReviewedAt,
+		incident.ClosedAt,
+		incident.UpdatedAt,
+		incident.ID,
+		from,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update safety incident: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	// The incident was moved on by another reviewer
+	if rowsAffected == 0 {
+		return models.ErrIncidentStatusConflict
+	}
+
+	return nil
+}
+
+// List retrieves the matching safety incidents, oldest first, and the total number of matches
+func (r *SafetyIncidentRepositoryImpl) List(ctx context.Context, filter models.SafetyIncidentFilter) ([]*models.SafetyIncident, int64, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.TripID != nil {
+		args = append(args, *filter.TripID)
+		conditions = append(conditions, fmt.Sprintf("trip_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM safety_incidents `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count safety incidents: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM safety_incidents
+		%s
+		ORDER BY created_at, id
+		LIMIT $%d OFFSET $%d
+	`, safetyIncidentColumns, where, len(args)+1, len(args)+2)
+
+	var incidents []*models.SafetyIncident
+	if err := r.db.SelectContext(ctx, &incidents, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list safety incidents: %w", err)
+	}
+
+	return incidents, total, nil
+}
@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// SavedLocationRepositoryImpl implements the SavedLocationRepository interface using PostgreSQL
+type SavedLocationRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewSavedLocationRepository creates a new instance of SavedLocationRepositoryImpl
+func NewSavedLocationRepository(db *sqlx.DB) repository.SavedLocationRepository {
+	return &SavedLocationRepositoryImpl{db: db}
+}
+
+// Create creates a new saved location in the database
+func (r *SavedLocationRepositoryImpl) Create(ctx context.Context, location *models.SavedLocation) error {
+	query := `
+		INSERT INTO saved_locations (id, passenger_id, label, name, latitude, longitude, address, created_at, updated_at)
+		VALUES (:id, :passenger_id, :label, :name, :latitude, :longitude, :address, :created_at, :updated_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, location)
+	if err != nil {
+		if validationErr := savedLocationConstraintError(err); validationErr != nil {
+			return validationErr
+		}
+		return fmt.Errorf("failed to create saved location: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a saved location by ID
+func (r *SavedLocationRepositoryImpl) GetByID(ctx context.Context, id string) (*models.SavedLocation, error) {
+	query := `
+		SELECT id, passenger_id, label, name, latitude, longitude, address, created_at, updated_at
+		FROM saved_locations
+		WHERE id = $1
+	`
+
+	location := &models.SavedLocation{}
+	err := r.db.GetContext(ctx, location, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "saved location",
+				ID:       id,
+			}
+		}
+		return nil, fmt.Errorf("failed to get saved location by ID: %w", err)
+	}
+
+	return location, nil
+}
+
+// Update updates an existing saved location
+func (r *SavedLocationRepositoryImpl) Update(ctx context.Context, location *models.SavedLocation) error {
+	query := `
+		UPDATE saved_locations
+		SET label = :label, name = :name, latitude = :latitude, longitude = :longitude,
+			address = :address, updated_at = :updated_at
+		WHERE id = :id
+	`
+
+	result, err := r.db.NamedExecContext(ctx, query, location)
+	if err != nil {
+		if validationErr := savedLocationConstraintError(err); validationErr != nil {
+			return validationErr
+		}
+		return fmt.Errorf("failed to update saved location: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "saved location",
+			ID:       location.ID.String(),
+		}
+	}
+
+	return nil
+}
+
+// Delete deletes a saved location by ID
+func (r *SavedLocationRepositoryImpl) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM saved_locations WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved location: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "saved location",
+			ID:       id,
+		}
+	}
+
+	return nil
+}
+
+// ListByPassengerID retrieves a passenger's saved locations, home and work first
+func (r *SavedLocationRepositoryImpl) ListByPassengerID(ctx context.Context, passengerID string) ([]*models.SavedLocation, error) {
+	query := `
+		SELECT id, passenger_id, label, name, latitude, longitude, address, created_at, updated_at
+		FROM saved_locations
+		WHERE passenger_id = $1
+		ORDER BY CASE label WHEN 'home' THEN 0 WHEN 'work' THEN 1 ELSE 2 END, created_at
+	`
+
+	var locations []*models.SavedLocation
+	if err := r.db.SelectContext(ctx, &locations, query, passengerID); err != nil {
+		return nil, fmt.Errorf("failed to list saved locations: %w", err)
+	}
+
+	return locations, nil
+}
+
+// savedLocationConstraintError maps constraint violations to validation errors, or returns nil
+func savedLocationConstraintError(err error) error {
+	pqErr, ok := err.(*pq.Error)
+	if !ok {
+		return nil
+	}
+
+	switch pqErr.Code {
+	case "23505": // unique_violation
+		if pqErr.Constraint == "idx_saved_locations_passenger_label" {
+			return &models.ValidationError{
+				Field:   "label",
+				Message: "passenger already has a location with this label",
+			}
+		}
+	case "23503": // foreign_key_violation
+		if pqErr.Constraint == "saved_locations_passenger_id_fkey" {
+			return &models.ValidationError{
+				Field:   "passenger_id",
+				Message: "passenger does not exist",
+			}
+		}
+	}
+	return nil
+}
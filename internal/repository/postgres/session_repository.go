@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const sessionColumns = `id, user_id, user_type, device_id, device_name, app_platform, app_version, access_token_hash,
+	refresh_token_hash, access_expires_at, expires_at, last_used_at, revoked_at, revoked_reason, created_at, updated_at`
+
+// SessionRepositoryImpl implements the SessionRepository interface using PostgreSQL
+type SessionRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewSessionRepository creates a new instance of SessionRepositoryImpl
+func NewSessionRepository(db *sqlx.DB) repository.SessionRepository {
+	return &SessionRepositoryImpl{db: db}
+}
+
+// Create creates a new session in the database
+func (r *SessionRepositoryImpl) Create(ctx context.Context, session *models.Session) error {
+	query := `
+		INSERT INTO sessions (` + sessionColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		session.ID,
+		session.UserID,
+		session.UserType,
+		session.DeviceID,
+		session.DeviceName,
+		session.AppPlatform,
+		session.AppVersion,
+		session.AccessTokenHash,
+		session.RefreshTokenHash,
+		session.AccessExpiresAt,
+		session.ExpiresAt,
+		session.LastUsedAt,
+		session.RevokedAt,
+		session.RevokedReason,
+		session.CreatedAt,
+		session.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a session by ID
+func (r *SessionRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Session, error) {
+	return r.getBy(ctx, "id", id)
+}
+
+// GetByAccessTokenHash retrieves the session holding an access token
+func (r *SessionRepositoryImpl) GetByAccessTokenHash(ctx context.Context, hash string) (*models.Session, error) {
+	return r.getBy(ctx, "access_token_hash", hash)
+}
+
+// GetByRefreshTokenHash retrieves the session holding a refresh token
+func (r *SessionRepositoryImpl) GetByRefreshTokenHash(ctx context.Context, hash string) (*models.Session, error) {
+	return r.getBy(ctx, "refresh_token_hash", hash)
+}
+
+// getBy retrieves the session whose column equals value
+func (r *SessionRepositoryImpl) getBy(ctx context.Context, column, value string) (*models.Session, error) {
+	query := `SELECT ` + sessionColumns + ` FROM sessions WHERE ` + column + ` = $1`
+
+	session := &models.Session{}
+	err := r.db.GetContext(ctx, session, query, value)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// Token hashes are not echoed back in errors
+			id := value
+			if column != "id" {
+				id = "<token>"
+			}
+			return nil, &models.NotFoundError{
+				Resource: "session",
+				ID:       id,
+			}
+		}
+		return nil, fmt.Errorf("failed to get session by %s: %w", column, err)
+	}
+
+	return session, nil
+}
+
+// Update stores the session's tokens, expiry, last use, app version and revocation
+func (r *SessionRepositoryImpl) Update(ctx context.Context, session *models.Session) error {
+	query := `
+		UPDATE sessions
+		SET access_token_hash = $2, refresh_token_hash = $3, access_expires_at = $4, expires_at = $5,
+			last_used_at = $6, revoked_at = $7, revoked_reason = $8, updated_at = $9,
+			app_platform = $10, app_version = $11
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		session.ID,
+		session.AccessTokenHash,
+		session.RefreshTokenHash,
+		session.AccessExpiresAt,
+		session.ExpiresAt,
+		session.LastUsedAt,
+		session.RevokedAt,
+		session.RevokedReason,
+		session.UpdatedAt,
+		session.AppPlatform,
+		session.AppVersion,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "session",
+			ID:       session.ID.String(),
+		}
+	}
+
+	return nil
+}
+
+// ListActiveByUser retrieves the user's sessions not revoked or expired at now, most recently used first
+func (r *SessionRepositoryImpl) ListActiveByUser(ctx context.Context, userID string, now time.Time) ([]*models.Session, error) {
+	query := `
+		SELECT ` + sessionColumns + `
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_used_at DESC, id
+	`
+
+	var sessions []*models.Session
+	if err := r.db.SelectContext(ctx, &sessions, query, userID, now); err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// CountActiveByAppVersion counts the sessions not revoked or expired at now per app platform and
+// version, by platform and most sessions first
+func (r *SessionRepositoryImpl) CountActiveByAppVersion(ctx context.Context, now time.Time) ([]*models.AppVersionSessions, error) {
+	query := `
+		SELECT COALESCE(app_platform, '') AS app_platform, COALESCE(app_version, '') AS app_version,
+			COUNT(*) AS sessions
+		FROM sessions
+		WHERE revoked_at IS NULL AND expires_at > $1
+		GROUP BY COALESCE(app_platform, ''), COALESCE(app_version, '')
+		ORDER BY app_platform, sessions DESC, app_version
+	`
+
+	var counts []*models.AppVersionSessions
+	if err := r.db.SelectContext(ctx, &counts, query, now); err != nil {
+		return nil, fmt.Errorf("failed to count sessions by app version: %w", err)
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// StorageCostRepositoryImpl implements the StorageCostRepository interface using PostgreSQL
+type StorageCostRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewStorageCostRepository creates a new instance of StorageCostRepositoryImpl
+func NewStorageCostRepository(db *sqlx.DB) repository.StorageCostRepository {
+	return &StorageCostRepositoryImpl{db: db}
+}
+
+// TableUsage returns the rows of an observability table in [start, end) and the bytes they take
+// up per bucket. The bytes are the stored size of the rows, leaving out indexes and page overhead.
+func (r *StorageCostRepositoryImpl) TableUsage(ctx context.Context, table string, start, end time.Time, bucket time.Duration) ([]*models.StorageUsage, error) {
+	column, ok := observabilityTimeColumns[table]
+	if !ok || !isStorageCostTable(table) {
+		return nil, fmt.Errorf("table %s is not a storage cost table", table)
+	}
+
+	query := `
+		SELECT $4::text AS table_name, FLOOR(EXTRACT(EPOCH FROM (t.` + column + ` - $1)) / $3)::int AS bucket,
+			COUNT(*) AS row_count, COALESCE(SUM(pg_column_size(t.*)), 0) AS bytes
+		FROM ` + table + ` t
+		WHERE t.` + column + ` >= $1 AND t.` + column + ` < $2
+		GROUP BY 2
+		ORDER BY 2
+	`
+
+	var usage []*models.StorageUsage
+	if err := r.db.SelectContext(ctx, &usage, query, start, end, bucket.Seconds(), table); err != nil {
+		return nil, fmt.Errorf("failed to get storage usage of %s: %w", table, err)
+	}
+
+	return usage, nil
+}
+
+// RideRequests returns the ride requests made in [start, end) per bucket and mode
+func (r *StorageCostRepositoryImpl) RideRequests(ctx context.Context, eventType string, start, end time.Time, bucket time.Duration) ([]*models.StorageRequests, error) {
+	query := `
+		SELECT FLOOR(EXTRACT(EPOCH FROM (timestamp - $1)) / $3)::int AS bucket,
+			COALESCE(event_data->>'mode', '') AS mode, COUNT(*) AS requests
+		FROM event_logs
+		WHERE event_type = $4 AND event_data->>'from_status' = 'none'
+			AND timestamp >= $1 AND timestamp < $2
+		GROUP BY 1, 2
+		ORDER BY 1, 2
+	`
+
+	var requests []*models.StorageRequests
+	if err := r.db.SelectContext(ctx, &requests, query, start, end, bucket.Seconds(), eventType); err != nil {
+		return nil, fmt.Errorf("failed to count ride requests: %w", err)
+	}
+
+	return requests, nil
+}
+
+// isStorageCostTable reports whether a table is one of models.StorageCostTables
+func isStorageCostTable(table string) bool {
+	for _, tables := range models.StorageCostTables {
+		for _, t := range tables {
+			if t == table {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -14,7 +14,8 @@ import (
 
 // TraditionalRepositoryImpl implements the TraditionalRepository interface using PostgreSQL
 type TraditionalRepositoryImpl struct {
-	db *sqlx.DB
+	db     *sqlx.DB
+	reader DBReader // optional; routes list queries to a read replica
 }
 
 // NewTraditionalRepository creates a new instance of TraditionalRepositoryImpl
@@ -22,6 +23,20 @@ func NewTraditionalRepository(db *sqlx.DB) repository.TraditionalRepository {
 	return &TraditionalRepositoryImpl{db: db}
 }
 
+// NewTraditionalRepositoryWithReader creates a TraditionalRepositoryImpl that writes to db and
+// serves list queries from the connection returned by reader
+func NewTraditionalRepositoryWithReader(db *sqlx.DB, reader DBReader) repository.TraditionalRepository {
+	return &TraditionalRepositoryImpl{db: db, reader: reader}
+}
+
+// readDB returns the connection used for read-heavy list queries
+func (r *TraditionalRepositoryImpl) readDB() *sqlx.DB {
+	if r.reader != nil {
+		return r.reader.Reader()
+	}
+	return r.db
+}
+
 // Traditional Metrics methods
 
 // CreateTraditionalMetric creates a new traditional metric record
@@ -483,7 +498,7 @@ func (r *TraditionalRepositoryImpl) GetServiceHealthByTimeRange(ctx context.Cont
 // Helper methods for scanning results
 
 func (r *TraditionalRepositoryImpl) scanTraditionalMetrics(ctx context.Context, query string, args ...interface{}) ([]*models.TraditionalMetric, error) {
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.readDB().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -517,7 +532,7 @@ func (r *TraditionalRepositoryImpl) scanTraditionalMetrics(ctx context.Context,
 }
 
 func (r *TraditionalRepositoryImpl) scanTraditionalLogs(ctx context.Context, query string, args ...interface{}) ([]*models.TraditionalLog, error) {
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.readDB().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
@@ -550,7 +565,7 @@ func (r *TraditionalRepositoryImpl) scanTraditionalLogs(ctx context.Context, que
 }
 
 func (r *TraditionalRepositoryImpl) scanServiceHealth(ctx context.Context, query string, args ...interface{}) ([]*models.ServiceHealth, error) {
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := r.readDB().QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute query: %w", err)
 	}
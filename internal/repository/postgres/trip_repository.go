@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 	"time"
 
 	"actor-model-observability/internal/models"
@@ -13,6 +14,24 @@ import (
 	"github.com/lib/pq"
 )
 
+// tripColumns are the trips columns, in the order list queries select them
+var tripColumns = []string{
+	"id", "passenger_id", "driver_id", "status", "pickup_latitude", "pickup_longitude",
+	"destination_latitude", "destination_longitude", "pickup_address", "destination_address", "fare_amount", "distance_km",
+	"duration_minutes", "requested_at", "matched_at", "accepted_at", "pickup_at", "completed_at", "cancelled_at",
+	"cancellation_reason", "cancellation_note", "cancellation_mode", "created_at", "updated_at",
+}
+
+const tripCompletionColumns = `trip_id, driver_id, dropoff_latitude, dropoff_longitude, odometer_distance_km, tolls_amount,
+	extras_amount, wait_fee, route_distance_km, dropoff_deviation_km, billed_distance_km, estimated_fare, final_fare,
+	flagged, flag_reason, created_at`
+
+const pickupWaitColumns = `trip_id, driver_id, zone, arrived_at, no_show_after, outcome, ended_at, wait_seconds, wait_fee,
+	no_show_fee`
+
+const tripRematchColumns = `id, trip_id, attempt, cancelled_driver_id, cancelled_at, note, mode, outcome, matched_driver_id,
+	ended_at, added_wait_seconds`
+
 // TripRepositoryImpl implements the TripRepository interface using PostgreSQL
 type TripRepositoryImpl struct {
 	db *sqlx.DB
@@ -29,8 +48,8 @@ func (r *TripRepositoryImpl) Create(ctx context.Context, trip *models.Trip) erro
 		INSERT INTO trips (id, passenger_id, driver_id, status, pickup_latitude, pickup_longitude, 
 			destination_latitude, destination_longitude, pickup_address, destination_address, fare_amount, distance_km, 
 			duration_minutes, requested_at, matched_at, pickup_at, completed_at, cancelled_at, 
-			created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+			cancellation_reason, cancellation_note, cancellation_mode, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -52,6 +71,9 @@ func (r *TripRepositoryImpl) Create(ctx context.Context, trip *models.Trip) erro
 		trip.PickupAt,
 		trip.CompletedAt,
 		trip.CancelledAt,
+		trip.CancellationReason,
+		trip.CancellationNote,
+		trip.CancellationMode,
 		trip.CreatedAt,
 		trip.UpdatedAt,
 	)
@@ -86,7 +108,7 @@ func (r *TripRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Tr
 		SELECT id, passenger_id, driver_id, status, pickup_latitude, pickup_longitude, 
 			destination_latitude, destination_longitude, pickup_address, destination_address, fare_amount, distance_km, 
 			duration_minutes, requested_at, matched_at, accepted_at, pickup_at, completed_at, cancelled_at, 
-			created_at, updated_at
+			cancellation_reason, cancellation_note, cancellation_mode, created_at, updated_at
 		FROM trips
 		WHERE id = $1
 	`
@@ -112,6 +134,9 @@ func (r *TripRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Tr
 		&trip.PickupAt,
 		&trip.CompletedAt,
 		&trip.CancelledAt,
+		&trip.CancellationReason,
+		&trip.CancellationNote,
+		&trip.CancellationMode,
 		&trip.CreatedAt,
 		&trip.UpdatedAt,
 	)
@@ -137,7 +162,7 @@ func (r *TripRepositoryImpl) Update(ctx context.Context, trip *models.Trip) erro
 			destination_latitude = $7, destination_longitude = $8, pickup_address = $9, destination_address = $10,
 			fare_amount = $11, distance_km = $12, duration_minutes = $13, requested_at = $14, matched_at = $15,
 			accepted_at = $16, pickup_at = $17, completed_at = $18, cancelled_at = $19,
-			updated_at = $20
+			cancellation_reason = $20, cancellation_note = $21, cancellation_mode = $22, updated_at = $23
 		WHERE id = $1
 	`
 
@@ -161,6 +186,9 @@ func (r *TripRepositoryImpl) Update(ctx context.Context, trip *models.Trip) erro
 		trip.PickupAt,
 		trip.CompletedAt,
 		trip.CancelledAt,
+		trip.CancellationReason,
+		trip.CancellationNote,
+		trip.CancellationMode,
 		trip.UpdatedAt,
 	)
 
@@ -206,6 +234,9 @@ func (r *TripRepositoryImpl) Delete(ctx context.Context, id string) error {
 
 	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Constraint == "safety_incidents_trip_id_fkey" {
+			return models.ErrTripFrozen
+		}
 		return fmt.Errorf("failed to delete trip: %w", err)
 	}
 
@@ -230,7 +261,7 @@ func (r *TripRepositoryImpl) GetByPassengerID(ctx context.Context, passengerID s
 		SELECT id, passenger_id, driver_id, status, pickup_latitude, pickup_longitude, 
 			destination_latitude, destination_longitude, pickup_address, destination_address, fare_amount, distance_km, 
 			duration_minutes, requested_at, matched_at, accepted_at, pickup_at, completed_at, cancelled_at, 
-			created_at, updated_at
+			cancellation_reason, cancellation_note, cancellation_mode, created_at, updated_at
 		FROM trips
 		WHERE passenger_id = $1
 		ORDER BY created_at DESC
@@ -246,7 +277,7 @@ func (r *TripRepositoryImpl) GetByDriverID(ctx context.Context, driverID string,
 		SELECT id, passenger_id, driver_id, status, pickup_latitude, pickup_longitude, 
 			destination_latitude, destination_longitude, pickup_address, destination_address, fare_amount, distance_km, 
 			duration_minutes, requested_at, matched_at, accepted_at, pickup_at, completed_at, cancelled_at, 
-			created_at, updated_at
+			cancellation_reason, cancellation_note, cancellation_mode, created_at, updated_at
 		FROM trips
 		WHERE driver_id = $1
 		ORDER BY created_at DESC
@@ -262,7 +293,7 @@ func (r *TripRepositoryImpl) GetActiveTrips(ctx context.Context) ([]*models.Trip
 		SELECT id, passenger_id, driver_id, status, pickup_latitude, pickup_longitude, 
 			destination_latitude, destination_longitude, pickup_address, destination_address, fare_amount, distance_km, 
 			duration_minutes, requested_at, matched_at, accepted_at, pickup_at, completed_at, cancelled_at, 
-			created_at, updated_at
+			cancellation_reason, cancellation_note, cancellation_mode, created_at, updated_at
 		FROM trips
 		WHERE status IN ('requested', 'matched', 'started')
 		ORDER BY created_at DESC
@@ -277,7 +308,7 @@ func (r *TripRepositoryImpl) GetTripsByStatus(ctx context.Context, status models
 		SELECT id, passenger_id, driver_id, status, pickup_latitude, pickup_longitude, 
 			destination_latitude, destination_longitude, pickup_address, destination_address, fare_amount, distance_km, 
 			duration_minutes, requested_at, matched_at, accepted_at, pickup_at, completed_at, cancelled_at, 
-			created_at, updated_at
+			cancellation_reason, cancellation_note, cancellation_mode, created_at, updated_at
 		FROM trips
 		WHERE status = $1
 		ORDER BY created_at DESC
@@ -287,6 +318,16 @@ func (r *TripRepositoryImpl) GetTripsByStatus(ctx context.Context, status models
 	return r.scanTrips(ctx, query, status, limit, offset)
 }
 
+// CountByStatus counts the trips with a status
+func (r *TripRepositoryImpl) CountByStatus(ctx context.Context, status models.TripStatus) (int64, error) {
+	var count int64
+	if err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM trips WHERE status = $1`, status); err != nil {
+		return 0, fmt.Errorf("failed to count trips by status: %w", err)
+	}
+
+	return count, nil
+}
+
 // GetTripsByDateRange retrieves trips within a date range with pagination
 func (r *TripRepositoryImpl) GetTripsByDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*models.Trip, error) {
 	// Parse dates
@@ -307,7 +348,7 @@ func (r *TripRepositoryImpl) GetTripsByDateRange(ctx context.Context, startDate,
 		SELECT id, passenger_id, driver_id, status, pickup_latitude, pickup_longitude, 
 			destination_latitude, destination_longitude, pickup_address, destination_address, fare_amount, distance_km, 
 			duration_minutes, requested_at, matched_at, accepted_at, pickup_at, completed_at, cancelled_at, 
-			created_at, updated_at
+			cancellation_reason, cancellation_note, cancellation_mode, created_at, updated_at
 		FROM trips
 		WHERE created_at >= $1 AND created_at < $2
 		ORDER BY created_at DESC
@@ -319,17 +360,766 @@ func (r *TripRepositoryImpl) GetTripsByDateRange(ctx context.Context, startDate,
 
 // List retrieves a list of trips with pagination
 func (r *TripRepositoryImpl) List(ctx context.Context, limit, offset int) ([]*models.Trip, error) {
-	query := `
-		SELECT id, passenger_id, driver_id, status, pickup_latitude, pickup_longitude, 
-			destination_latitude, destination_longitude, pickup_address, destination_address, fare_amount, distance_km, 
-			duration_minutes, requested_at, matched_at, accepted_at, pickup_at, completed_at, cancelled_at, 
-			created_at, updated_at
+	columns := selectColumns(ctx, tripColumns)
+	query := selectFrom(columns, `
 		FROM trips
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
+	`)
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var trips []*models.Trip
+	for rows.Next() {
+		trip := &models.Trip{}
+		if err := rows.Scan(columnTargets(trip, columns)...); err != nil {
+			return nil, fmt.Errorf("failed to scan trip: %w", err)
+		}
+		trips = append(trips, trip)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating trips: %w", err)
+	}
+
+	return trips, nil
+}
+
+// GetRecentDestinations retrieves a passenger's distinct destinations, most recently visited first.
+// Cancelled trips are ignored.
+func (r *TripRepositoryImpl) GetRecentDestinations(ctx context.Context, passengerID string, limit int) ([]*models.RecentDestination, error) {
+	query := `
+		SELECT destination_latitude, destination_longitude, destination_address, trip_count, requested_at
+		FROM (
+			SELECT destination_latitude, destination_longitude, destination_address, requested_at,
+				COUNT(*) OVER (PARTITION BY destination_latitude, destination_longitude) AS trip_count,
+				ROW_NUMBER() OVER (PARTITION BY destination_latitude, destination_longitude ORDER BY requested_at DESC) AS visit
+			FROM trips
+			WHERE passenger_id = $1 AND status != 'cancelled'
+		) destinations
+		WHERE visit = 1
+		ORDER BY requested_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, passengerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent destinations: %w", err)
+	}
+	defer rows.Close()
+
+	var destinations []*models.RecentDestination
+	for rows.Next() {
+		d := &models.RecentDestination{}
+		err := rows.Scan(
+			&d.Latitude,
+			&d.Longitude,
+			&d.Address,
+			&d.TripCount,
+			&d.LastVisitedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recent destination: %w", err)
+		}
+		destinations = append(destinations, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating recent destinations: %w", err)
+	}
+
+	return destinations, nil
+}
+
+// GetRequestTimes retrieves the request times of the trips requested in [start, end), oldest first,
+// optionally only those picked up within bounds
+func (r *TripRepositoryImpl) GetRequestTimes(ctx context.Context, start, end time.Time, bounds *models.GeoBounds) ([]time.Time, error) {
+	query := `SELECT requested_at FROM trips WHERE requested_at >= $1 AND requested_at < $2`
+	args := []interface{}{start, end}
+	if bounds != nil {
+		query += ` AND pickup_latitude >= $3 AND pickup_latitude < $4 AND pickup_longitude >= $5 AND pickup_longitude < $6`
+		args = append(args, bounds.MinLat, bounds.MaxLat, bounds.MinLng, bounds.MaxLng)
+	}
+	query += ` ORDER BY requested_at`
+
+	var times []time.Time
+	if err := r.db.SelectContext(ctx, &times, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get trip request times: %w", err)
+	}
+
+	return times, nil
+}
+
+// GetGridCounts counts the pickups of the trips requested and the dropoffs of the trips completed
+// in [start, end) per cell of a grid of cellHeight by cellWidth degrees, in a single aggregate query
+func (r *TripRepositoryImpl) GetGridCounts(ctx context.Context, start, end time.Time, cellHeight, cellWidth float64) ([]*models.TripGridCount, error) {
+	query := `
+		SELECT grid_row, grid_col, SUM(pickup) AS pickups, SUM(dropoff) AS dropoffs
+		FROM (
+			SELECT CAST(FLOOR((pickup_latitude + 90) / $1) AS INTEGER) AS grid_row,
+				CAST(FLOOR((pickup_longitude + 180) / $2) AS INTEGER) AS grid_col,
+				1 AS pickup, 0 AS dropoff
+			FROM trips
+			WHERE requested_at >= $3 AND requested_at < $4
+			UNION ALL
+			SELECT CAST(FLOOR((destination_latitude + 90) / $1) AS INTEGER),
+				CAST(FLOOR((destination_longitude + 180) / $2) AS INTEGER),
+				0, 1
+			FROM trips
+			WHERE completed_at >= $3 AND completed_at < $4
+		) cells
+		GROUP BY grid_row, grid_col`
+
+	var counts []*models.TripGridCount
+	if err := r.db.SelectContext(ctx, &counts, query, cellHeight, cellWidth, start, end); err != nil {
+		return nil, fmt.Errorf("failed to get trip grid counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// ListByFleet retrieves the trips of a fleet's drivers requested in [start, end), oldest first
+func (r *TripRepositoryImpl) ListByFleet(ctx context.Context, fleetID string, start, end time.Time) ([]*models.Trip, error) {
+	query := `
+		SELECT t.id, t.passenger_id, t.driver_id, t.status, t.pickup_latitude, t.pickup_longitude,
+			t.destination_latitude, t.destination_longitude, t.pickup_address, t.destination_address, t.fare_amount, t.distance_km,
+			t.duration_minutes, t.requested_at, t.matched_at, t.accepted_at, t.pickup_at, t.completed_at, t.cancelled_at,
+			t.cancellation_reason, t.cancellation_note, t.cancellation_mode, t.created_at, t.updated_at
+		FROM trips t
+		JOIN drivers d ON d.id = t.driver_id
+		WHERE d.fleet_id = $1 AND t.requested_at >= $2 AND t.requested_at < $3
+		ORDER BY t.requested_at, t.id
+	`
+
+	return r.scanTrips(ctx, query, fleetID, start, end)
+}
+
+// ListRequested retrieves the trips requested in [start, end), oldest first
+func (r *TripRepositoryImpl) ListRequested(ctx context.Context, start, end time.Time) ([]*models.Trip, error) {
+	query := `
+		SELECT id, passenger_id, driver_id, status, pickup_latitude, pickup_longitude,
+			destination_latitude, destination_longitude, pickup_address, destination_address, fare_amount, distance_km,
+			duration_minutes, requested_at, matched_at, accepted_at, pickup_at, completed_at, cancelled_at,
+			cancellation_reason, cancellation_note, cancellation_mode, created_at, updated_at
+		FROM trips
+		WHERE requested_at >= $1 AND requested_at < $2
+		ORDER BY requested_at, id
+	`
+
+	return r.scanTrips(ctx, query, start, end)
+}
+
+// GetDailyCounts counts the trips requested, completed and cancelled and sums the completed fares
+// per day, day i covering [boundaries[i], boundaries[i+1]). The boundaries are local midnights
+// computed by the caller, so days are grouped in any timezone, 23 and 25 hour days included,
+// without relying on the database's timezone data.
+func (r *TripRepositoryImpl) GetDailyCounts(ctx context.Context, boundaries []time.Time) ([]*models.DailyTripCount, error) {
+	if len(boundaries) < 2 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, len(boundaries))
+	for i, b := range boundaries {
+		args[i] = b
+	}
+	first, last := "$1", fmt.Sprintf("$%d", len(boundaries))
+	day := func(column string) string { return dayIndex(column, len(boundaries)) }
+
+	query := `
+		SELECT day, SUM(requested) AS requested, SUM(completed) AS completed,
+			SUM(cancelled) AS cancelled, SUM(revenue) AS revenue
+		FROM (
+			SELECT ` + day("requested_at") + ` AS day, 1 AS requested, 0 AS completed, 0 AS cancelled, 0 AS revenue
+			FROM trips
+			WHERE requested_at >= ` + first + ` AND requested_at < ` + last + `
+			UNION ALL
+			SELECT ` + day("completed_at") + `, 0, 1, 0, COALESCE(fare_amount, 0)
+			FROM trips
+			WHERE status = 'completed' AND completed_at >= ` + first + ` AND completed_at < ` + last + `
+			UNION ALL
+			SELECT ` + day("cancelled_at") + `, 0, 0, 1, 0
+			FROM trips
+			WHERE cancelled_at >= ` + first + ` AND cancelled_at < ` + last + `
+		) days
+		GROUP BY day
+		ORDER BY day`
+
+	var counts []*models.DailyTripCount
+	if err := r.db.SelectContext(ctx, &counts, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get daily trip counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// ListCancelled retrieves the trips cancelled in [start, end), oldest first
+func (r *TripRepositoryImpl) ListCancelled(ctx context.Context, start, end time.Time) ([]*models.Trip, error) {
+	query := `
+		SELECT id, passenger_id, driver_id, status, pickup_latitude, pickup_longitude,
+			destination_latitude, destination_longitude, pickup_address, destination_address, fare_amount, distance_km,
+			duration_minutes, requested_at, matched_at, accepted_at, pickup_at, completed_at, cancelled_at,
+			cancellation_reason, cancellation_note, cancellation_mode, created_at, updated_at
+		FROM trips
+		WHERE cancelled_at >= $1 AND cancelled_at < $2
+		ORDER BY cancelled_at, id
 	`
 
-	return r.scanTrips(ctx, query, limit, offset)
+	return r.scanTrips(ctx, query, start, end)
+}
+
+// GetDailyCancellations counts the trips cancelled per day and reason, day i covering
+// [boundaries[i], boundaries[i+1]) as in GetDailyCounts
+func (r *TripRepositoryImpl) GetDailyCancellations(ctx context.Context, boundaries []time.Time) ([]*models.DailyCancellationCount, error) {
+	if len(boundaries) < 2 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, len(boundaries))
+	for i, b := range boundaries {
+		args[i] = b
+	}
+
+	query := `
+		SELECT ` + dayIndex("cancelled_at", len(boundaries)) + ` AS day, COALESCE(cancellation_reason, '') AS reason,
+			COUNT(*) AS count
+		FROM trips
+		WHERE cancelled_at >= $1 AND cancelled_at < ` + fmt.Sprintf("$%d", len(boundaries)) + `
+		GROUP BY 1, 2
+		ORDER BY 1, 2`
+
+	var counts []*models.DailyCancellationCount
+	if err := r.db.SelectContext(ctx, &counts, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get daily cancellation counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+// dayIndex returns the SQL expression of the day of column among the days delimited by the
+// numBoundaries boundaries bound as $1 to $numBoundaries
+func dayIndex(column string, numBoundaries int) string {
+	if numBoundaries == 2 {
+		return "0"
+	}
+	var sb strings.Builder
+	sb.WriteString("CASE")
+	for i := 1; i < numBoundaries-1; i++ {
+		fmt.Fprintf(&sb, " WHEN %s < $%d THEN %d", column, i+1, i-1)
+	}
+	fmt.Fprintf(&sb, " ELSE %d END", numBoundaries-2)
+	return sb.String()
+}
+
+// Complete stores the completed trip and its completion details if the stored trip still has
+// status from
+func (r *TripRepositoryImpl) Complete(ctx context.Context, trip *models.Trip, from models.TripStatus, completion *models.TripCompletion) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE trips
+		SET status = $3, fare_amount = $4, distance_km = $5, duration_minutes = $6, completed_at = $7, updated_at = $8
+		WHERE id = $1 AND status = $2
+	`,
+		trip.ID,
+		from,
+		trip.Status,
+		trip.FareAmount,
+		trip.DistanceKm,
+		trip.DurationMinutes,
+		trip.CompletedAt,
+		trip.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to complete trip: %w", err)
+	}
+	if err := requireStatusMatch(result); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO trip_completions (`+tripCompletionColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
+	`,
+		completion.TripID,
+		completion.DriverID,
+		completion.DropoffLatitude,
+		completion.DropoffLongitude,
+		completion.OdometerDistanceKm,
+		completion.TollsAmount,
+		completion.ExtrasAmount,
+		completion.WaitFee,
+		completion.RouteDistanceKm,
+		completion.DropoffDeviationKm,
+		completion.BilledDistanceKm,
+		completion.EstimatedFare,
+		completion.FinalFare,
+		completion.Flagged,
+		completion.FlagReason,
+		completion.CreatedAt,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return models.ErrTripStatusConflict
+		}
+		return fmt.Errorf("failed to record trip completion: %w", err)
+	}
+
+	if err := rollUpDriverTrip(ctx, tx, completion); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit trip completion: %w", err)
+	}
+
+	return nil
+}
+
+// rollUpDriverTrip adds a completed trip to the trip stats and daily earnings of its driver
+func rollUpDriverTrip(ctx context.Context, tx *sqlx.Tx, completion *models.TripCompletion) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO driver_trip_stats (driver_id, total_trips, total_distance_km, last_trip_at)
+		VALUES ($1, 1, $2, $3)
+		ON CONFLICT (driver_id) DO UPDATE SET
+			total_trips = driver_trip_stats.total_trips + 1,
+			total_distance_km = driver_trip_stats.total_distance_km + EXCLUDED.total_distance_km,
+			last_trip_at = GREATEST(driver_trip_stats.last_trip_at, EXCLUDED.last_trip_at)
+	`, completion.DriverID, completion.BilledDistanceKm, completion.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update driver trip stats: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO driver_daily_earnings (driver_id, day, trips, earnings)
+		VALUES ($1, $2, 1, $3)
+		ON CONFLICT (driver_id, day) DO UPDATE SET
+			trips = driver_daily_earnings.trips + 1,
+			earnings = driver_daily_earnings.earnings + EXCLUDED.earnings
+	`, completion.DriverID, completion.CreatedAt.UTC().Format(time.DateOnly), completion.FinalFare)
+	if err != nil {
+		return fmt.Errorf("failed to update driver daily earnings: %w", err)
+	}
+	return nil
+}
+
+// GetCompletion retrieves the completion details of a trip
+func (r *TripRepositoryImpl) GetCompletion(ctx context.Context, tripID string) (*models.TripCompletion, error) {
+	query := `SELECT ` + tripCompletionColumns + ` FROM trip_completions WHERE trip_id = $1`
+
+	completion := &models.TripCompletion{}
+	err := r.db.GetContext(ctx, completion, query, tripID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "trip_completion",
+				ID:       tripID,
+			}
+		}
+		return nil, fmt.Errorf("failed to get trip completion: %w", err)
+	}
+
+	return completion, nil
+}
+
+// ListCompletions retrieves the matching completions, oldest first, and the total number of matches
+func (r *TripRepositoryImpl) ListCompletions(ctx context.Context, filter models.TripCompletionFilter) ([]*models.TripCompletion, int64, error) {
+	var args []interface{}
+	where := ""
+	if filter.Flagged != nil {
+		args = append(args, *filter.Flagged)
+		where = "WHERE flagged = $1"
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM trip_completions `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count trip completions: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM trip_completions
+		%s
+		ORDER BY created_at, trip_id
+		LIMIT $%d OFFSET $%d
+	`, tripCompletionColumns, where, len(args)+1, len(args)+2)
+
+	var completions []*models.TripCompletion
+	if err := r.db.SelectContext(ctx, &completions, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list trip completions: %w", err)
+	}
+
+	return completions, total, nil
+}
+
+// StartPickupWait stores the trip its driver arrived at and starts the wait for its passenger if
+// the stored trip still has status from
+func (r *TripRepositoryImpl) StartPickupWait(ctx context.Context, trip *models.Trip, from models.TripStatus, wait *models.PickupWait) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE trips
+		SET status = $3, updated_at = $4
+		WHERE id = $1 AND status = $2
+	`, trip.ID, from, trip.Status, trip.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to update trip status: %w", err)
+	}
+	if err := requireStatusMatch(result); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO pickup_waits (`+pickupWaitColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`,
+		wait.TripID,
+		wait.DriverID,
+		wait.Zone,
+		wait.ArrivedAt,
+		wait.NoShowAfter,
+		wait.Outcome,
+		wait.EndedAt,
+		wait.WaitSeconds,
+		wait.WaitFee,
+		wait.NoShowFee,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return models.ErrTripStatusConflict
+		}
+		return fmt.Errorf("failed to record pickup wait: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit pickup wait: %w", err)
+	}
+
+	return nil
+}
+
+// EndPickupWait stores the trip whose passenger was picked up or did not show and ends its wait
+// if the stored trip still has status from
+func (r *TripRepositoryImpl) EndPickupWait(ctx context.Context, trip *models.Trip, from models.TripStatus, wait *models.PickupWait) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE trips
+		SET status = $3, fare_amount = $4, pickup_at = $5, cancelled_at = $6, cancellation_reason = $7,
+			cancellation_note = $8, cancellation_mode = $9, updated_at = $10
+		WHERE id = $1 AND status = $2
+	`,
+		trip.ID,
+		from,
+		trip.Status,
+		trip.FareAmount,
+		trip.PickupAt,
+		trip.CancelledAt,
+		trip.CancellationReason,
+		trip.CancellationNote,
+		trip.CancellationMode,
+		trip.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update trip status: %w", err)
+	}
+	if err := requireStatusMatch(result); err != nil {
+		return err
+	}
+
+	result, err = tx.ExecContext(ctx, `
+		UPDATE pickup_waits
+		SET outcome = $3, ended_at = $4, wait_seconds = $5, wait_fee = $6, no_show_fee = $7
+		WHERE trip_id = $1 AND outcome = $2
+	`,
+		wait.TripID,
+		models.PickupWaitWaiting,
+		wait.Outcome,
+		wait.EndedAt,
+		wait.WaitSeconds,
+		wait.WaitFee,
+		wait.NoShowFee,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to end pickup wait: %w", err)
+	}
+	if err := requireStatusMatch(result); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit pickup wait: %w", err)
+	}
+
+	return nil
+}
+
+// GetPickupWait retrieves the driver's wait at the pickup of a trip
+func (r *TripRepositoryImpl) GetPickupWait(ctx context.Context, tripID string) (*models.PickupWait, error) {
+	query := `SELECT ` + pickupWaitColumns + ` FROM pickup_waits WHERE trip_id = $1`
+
+	wait := &models.PickupWait{}
+	err := r.db.GetContext(ctx, wait, query, tripID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "pickup_wait",
+				ID:       tripID,
+			}
+		}
+		return nil, fmt.Errorf("failed to get pickup wait: %w", err)
+	}
+
+	return wait, nil
+}
+
+// GetPickupWaitStats retrieves the wait-time metrics of the arrivals in [from, to) by pickup
+// zone, busiest first
+func (r *TripRepositoryImpl) GetPickupWaitStats(ctx context.Context, from, to time.Time) ([]*models.PickupWaitZoneStats, error) {
+	query := `
+		SELECT
+			zone,
+			COUNT(*) AS arrivals,
+			COALESCE(SUM(CASE WHEN outcome = $3 THEN 1 ELSE 0 END), 0) AS picked_up,
+			COALESCE(SUM(CASE WHEN outcome = $4 THEN 1 ELSE 0 END), 0) AS no_shows,
+			COALESCE(AVG(wait_seconds), 0) AS avg_wait_seconds,
+			COALESCE(MAX(wait_seconds), 0) AS max_wait_seconds,
+			COALESCE(SUM(wait_fee), 0) AS wait_fees,
+			COALESCE(SUM(no_show_fee), 0) AS no_show_fees
+		FROM pickup_waits
+		WHERE arrived_at >= $1 AND arrived_at < $2
+		GROUP BY zone
+		ORDER BY arrivals DESC, zone
+	`
+
+	var stats []*models.PickupWaitZoneStats
+	if err := r.db.SelectContext(ctx, &stats, query, from, to, models.PickupWaitPickedUp, models.PickupWaitNoShow); err != nil {
+		return nil, fmt.Errorf("failed to get pickup wait stats: %w", err)
+	}
+
+	return stats, nil
+}
+
+// RecordDriverCancellation stores the trip its matched driver cancelled, back to requested or
+// cancelled, and records the re-match if the stored trip still has status from
+func (r *TripRepositoryImpl) RecordDriverCancellation(ctx context.Context, trip *models.Trip, from models.TripStatus, rematch *models.TripRematch) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE trips
+		SET status = $3, driver_id = $4, matched_at = $5, accepted_at = $6, cancelled_at = $7,
+			cancellation_reason = $8, cancellation_note = $9, cancellation_mode = $10, updated_at = $11
+		WHERE id = $1 AND status = $2
+	`,
+		trip.ID,
+		from,
+		trip.Status,
+		trip.DriverID,
+		trip.MatchedAt,
+		trip.AcceptedAt,
+		trip.CancelledAt,
+		trip.CancellationReason,
+		trip.CancellationNote,
+		trip.CancellationMode,
+		trip.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update trip status: %w", err)
+	}
+	if err := requireStatusMatch(result); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO trip_rematches (`+tripRematchColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`,
+		rematch.ID,
+		rematch.TripID,
+		rematch.Attempt,
+		rematch.CancelledDriverID,
+		rematch.CancelledAt,
+		rematch.Note,
+		rematch.Mode,
+		rematch.Outcome,
+		rematch.MatchedDriverID,
+		rematch.EndedAt,
+		rematch.AddedWaitSeconds,
+	)
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == "23505" { // unique_violation
+			return models.ErrTripStatusConflict
+		}
+		return fmt.Errorf("failed to record trip rematch: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit trip rematch: %w", err)
+	}
+
+	return nil
+}
+
+// EndRematch records how a re-match still matching ended
+func (r *TripRepositoryImpl) EndRematch(ctx context.Context, rematch *models.TripRematch) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE trip_rematches
+		SET outcome = $3, matched_driver_id = $4, ended_at = $5, added_wait_seconds = $6
+		WHERE id = $1 AND outcome = $2
+	`,
+		rematch.ID,
+		models.TripRematchMatching,
+		rematch.Outcome,
+		rematch.MatchedDriverID,
+		rematch.EndedAt,
+		rematch.AddedWaitSeconds,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to end trip rematch: %w", err)
+	}
+	return requireStatusMatch(result)
+}
+
+// ListRematches retrieves the re-matches of a trip, first first
+func (r *TripRepositoryImpl) ListRematches(ctx context.Context, tripID string) ([]*models.TripRematch, error) {
+	query := `SELECT ` + tripRematchColumns + ` FROM trip_rematches WHERE trip_id = $1 ORDER BY attempt`
+
+	rematches := []*models.TripRematch{}
+	if err := r.db.SelectContext(ctx, &rematches, query, tripID); err != nil {
+		return nil, fmt.Errorf("failed to list trip rematches: %w", err)
+	}
+
+	return rematches, nil
+}
+
+// Search retrieves the trips matching the support search filter with their passenger and driver
+// contact details, most recently requested first, and the total number of matches. Emails and
+// plates are matched on the normalized expressions indexed by the trip search migration.
+func (r *TripRepositoryImpl) Search(ctx context.Context, filter models.TripSearchFilter) ([]*models.TripSearchResult, int64, error) {
+	var conditions []string
+	var args []interface{}
+	match := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.PassengerPhone != "" {
+		match("pu.phone = $%d", filter.PassengerPhone)
+	}
+	if filter.PassengerEmail != "" {
+		match("LOWER(pu.email) = $%d", strings.ToLower(filter.PassengerEmail))
+	}
+	if filter.DriverPlate != "" {
+		match("UPPER(REPLACE(d.vehicle_plate, ' ', '')) = $%d", models.NormalizePlate(filter.DriverPlate))
+	}
+	if filter.TripIDPrefix != "" {
+		match("CAST(t.id AS TEXT) LIKE $%d", strings.ToLower(filter.TripIDPrefix)+"%")
+	}
+	if filter.RequestedFrom != nil {
+		match("t.requested_at >= $%d", *filter.RequestedFrom)
+	}
+	if filter.RequestedTo != nil {
+		match("t.requested_at < $%d", *filter.RequestedTo)
+	}
+	if b := filter.PickupBounds; b != nil {
+		match("t.pickup_latitude >= $%d", b.MinLat)
+		match("t.pickup_latitude < $%d", b.MaxLat)
+		match("t.pickup_longitude >= $%d", b.MinLng)
+		match("t.pickup_longitude < $%d", b.MaxLng)
+	}
+
+	from := `
+		FROM trips t
+		JOIN passengers p ON p.id = t.passenger_id
+		JOIN users pu ON pu.id = p.user_id
+		LEFT JOIN drivers d ON d.id = t.driver_id
+		LEFT JOIN users du ON du.id = d.user_id`
+	if len(conditions) > 0 {
+		from += "\n\t\tWHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*)"+from, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count trip search results: %w", err)
+	}
+
+	columns := make([]string, len(tripColumns))
+	for i, c := range tripColumns {
+		columns[i] = "t." + c
+	}
+	query := fmt.Sprintf(`
+		SELECT %s, pu.name, pu.phone, pu.email, du.name, d.vehicle_plate%s
+		ORDER BY t.requested_at DESC, t.id
+		LIMIT $%d OFFSET $%d
+	`, strings.Join(columns, ", "), from, len(args)+1, len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, filter.Limit, filter.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search trips: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*models.TripSearchResult
+	for rows.Next() {
+		result := &models.TripSearchResult{Trip: &models.Trip{}}
+		targets := append(columnTargets(result.Trip, tripColumns),
+			&result.PassengerName, &result.PassengerPhone, &result.PassengerEmail, &result.DriverName, &result.DriverPlate)
+		if err := rows.Scan(targets...); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan trip search result: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating trip search results: %w", err)
+	}
+
+	return results, total, nil
+}
+
+// ListStuck retrieves up to limit trips neither completed nor cancelled that were last updated
+// before updatedBefore, least recently updated first
+func (r *TripRepositoryImpl) ListStuck(ctx context.Context, updatedBefore time.Time, limit int) ([]*models.Trip, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM trips
+		WHERE status NOT IN ('completed', 'cancelled') AND updated_at < $1
+		ORDER BY updated_at, id
+		LIMIT $2
+	`, strings.Join(tripColumns, ", "))
+
+	return r.scanTrips(ctx, query, updatedBefore, limit)
+}
+
+// requireStatusMatch fails with ErrTripStatusConflict when a conditional status update matched
+// no row, i.e. the row was deleted or moved on since it was read
+func requireStatusMatch(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return models.ErrTripStatusConflict
+	}
+	return nil
 }
 
 // scanTrips is a helper method to scan trip results with parameters
@@ -379,6 +1169,9 @@ func (r *TripRepositoryImpl) scanTripRows(rows *sql.Rows) ([]*models.Trip, error
 			&trip.PickupAt,
 			&trip.CompletedAt,
 			&trip.CancelledAt,
+			&trip.CancellationReason,
+			&trip.CancellationNote,
+			&trip.CancellationMode,
 			&trip.CreatedAt,
 			&trip.UpdatedAt,
 		)
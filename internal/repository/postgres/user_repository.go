@@ -12,6 +12,9 @@ import (
 	"github.com/lib/pq"
 )
 
+// userColumns are the users columns, in the order list queries select them
+var userColumns = []string{"id", "email", "phone", "name", "user_type", "avatar_key", "created_at", "updated_at"}
+
 // UserRepositoryImpl implements the UserRepository interface using PostgreSQL
 type UserRepositoryImpl struct {
 	db *sqlx.DB
@@ -25,8 +28,8 @@ func NewUserRepository(db *sqlx.DB) repository.UserRepository {
 // Create creates a new user in the database
 func (r *UserRepositoryImpl) Create(ctx context.Context, user *models.User) error {
 	query := `
-		INSERT INTO users (id, email, phone, name, user_type, created_at, updated_at)
-		VALUES (:id, :email, :phone, :name, :user_type, :created_at, :updated_at)
+		INSERT INTO users (id, email, phone, name, user_type, avatar_key, created_at, updated_at)
+		VALUES (:id, :email, :phone, :name, :user_type, :avatar_key, :created_at, :updated_at)
 	`
 
 	_, err := r.db.NamedExecContext(ctx, query, user)
@@ -58,7 +61,7 @@ func (r *UserRepositoryImpl) Create(ctx context.Context, user *models.User) erro
 // GetByID retrieves a user by ID
 func (r *UserRepositoryImpl) GetByID(ctx context.Context, id string) (*models.User, error) {
 	query := `
-		SELECT id, email, phone, name, user_type, created_at, updated_at
+		SELECT id, email, phone, name, user_type, avatar_key, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
@@ -82,7 +85,7 @@ func (r *UserRepositoryImpl) GetByID(ctx context.Context, id string) (*models.Us
 // GetByEmail retrieves a user by email
 func (r *UserRepositoryImpl) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
-		SELECT id, email, phone, name, user_type, created_at, updated_at
+		SELECT id, email, phone, name, user_type, avatar_key, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
@@ -106,7 +109,7 @@ func (r *UserRepositoryImpl) GetByEmail(ctx context.Context, email string) (*mod
 // GetByPhone retrieves a user by phone number
 func (r *UserRepositoryImpl) GetByPhone(ctx context.Context, phone string) (*models.User, error) {
 	query := `
-		SELECT id, email, phone, name, user_type, created_at, updated_at
+		SELECT id, email, phone, name, user_type, avatar_key, created_at, updated_at
 		FROM users
 		WHERE phone = $1
 	`
@@ -131,7 +134,7 @@ func (r *UserRepositoryImpl) GetByPhone(ctx context.Context, phone string) (*mod
 func (r *UserRepositoryImpl) Update(ctx context.Context, user *models.User) error {
 	query := `
 		UPDATE users
-		SET email = :email, phone = :phone, name = :name, user_type = :user_type, updated_at = :updated_at
+		SET email = :email, phone = :phone, name = :name, user_type = :user_type, avatar_key = :avatar_key, updated_at = :updated_at
 		WHERE id = :id
 	`
 
@@ -199,12 +202,12 @@ func (r *UserRepositoryImpl) Delete(ctx context.Context, id string) error {
 
 // List retrieves a list of users with pagination
 func (r *UserRepositoryImpl) List(ctx context.Context, limit, offset int) ([]*models.User, error) {
-	query := `
-		SELECT id, email, phone, name, user_type, created_at, updated_at
+	columns := selectColumns(ctx, userColumns)
+	query := selectFrom(columns, `
 		FROM users
 		ORDER BY created_at DESC
 		LIMIT $1 OFFSET $2
-	`
+	`)
 
 	rows, err := r.db.QueryContext(ctx, query, limit, offset)
 	if err != nil {
@@ -215,16 +218,7 @@ func (r *UserRepositoryImpl) List(ctx context.Context, limit, offset int) ([]*mo
 	var users []*models.User
 	for rows.Next() {
 		user := &models.User{}
-		err := rows.Scan(
-			&user.ID,
-			&user.Email,
-			&user.Phone,
-			&user.Name,
-			&user.UserType,
-			&user.CreatedAt,
-			&user.UpdatedAt,
-		)
-		if err != nil {
+		if err := rows.Scan(columnTargets(user, columns)...); err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
 		users = append(users, user)
@@ -0,0 +1,267 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	webhookSubscriptionColumns = `id, owner, url, secret, events, active, created_at, updated_at`
+	webhookDeliveryColumns     = `id, subscription_id, event, trip_id, payload, status, attempts, response_status,
+		last_error, next_attempt_at, delivered_at, created_at, updated_at`
+)
+
+// WebhookRepositoryImpl implements the WebhookRepository interface using PostgreSQL
+type WebhookRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepositoryImpl
+func NewWebhookRepository(db *sqlx.DB) repository.WebhookRepository {
+	return &WebhookRepositoryImpl{db: db}
+}
+
+// CreateSubscription creates a new webhook subscription in the database
+func (r *WebhookRepositoryImpl) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (id, owner, url, secret, events, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		subscription.ID,
+		subscription.Owner,
+		subscription.URL,
+		subscription.Secret,
+		subscription.Events,
+		subscription.Active,
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetSubscription retrieves a webhook subscription by ID
+func (r *WebhookRepositoryImpl) GetSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	query := `SELECT ` + webhookSubscriptionColumns + ` FROM webhook_subscriptions WHERE id = $1`
+
+	subscription := &models.WebhookSubscription{}
+	err := r.db.GetContext(ctx, subscription, query, id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, &models.NotFoundError{
+				Resource: "webhook subscription",
+				ID:       id,
+			}
+		}
+		return nil, fmt.Errorf("failed to get webhook subscription by ID: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// DeleteSubscription deletes a webhook subscription and its deliveries
+func (r *WebhookRepositoryImpl) DeleteSubscription(ctx context.Context, id string) error {
+	query := `DELETE FROM webhook_subscriptions WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook subscription: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "webhook subscription",
+			ID:       id,
+		}
+	}
+
+	return nil
+}
+
+// ListSubscriptionsByOwner retrieves the subscriptions registered by an API key, oldest first
+func (r *WebhookRepositoryImpl) ListSubscriptionsByOwner(ctx context.Context, owner string) ([]*models.WebhookSubscription, error) {
+	query := `
+		SELECT ` + webhookSubscriptionColumns + `
+		FROM webhook_subscriptions
+		WHERE owner = $1
+		ORDER BY created_at, id
+	`
+
+	var subscriptions []*models.WebhookSubscription
+	if err := r.db.SelectContext(ctx, &subscriptions, query, owner); err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// ListSubscriptionsForEvent retrieves the active subscriptions that receive the event
+func (r *WebhookRepositoryImpl) ListSubscriptionsForEvent(ctx context.Context, event models.WebhookEvent) ([]*models.WebhookSubscription, error) {
+	query := `
+		SELECT ` + webhookSubscriptionColumns + `
+		FROM webhook_subscriptions
+		WHERE active
+		ORDER BY created_at, id
+	`
+
+	var active []*models.WebhookSubscription
+	if err := r.db.SelectContext(ctx, &active, query); err != nil {
+		return nil, fmt.Errorf("failed to list webhook subscriptions for event: %w", err)
+	}
+
+	// Events are stored as a comma-separated list, so matching is done here
+	var subscriptions []*models.WebhookSubscription
+	for _, s := range active {
+		if s.Events.Contains(event) {
+			subscriptions = append(subscriptions, s)
+		}
+	}
+
+	return subscriptions, nil
+}
+
+// CreateDelivery creates a new webhook delivery in the database
+func (r *WebhookRepositoryImpl) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (` + webhookDeliveryColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		delivery.ID,
+		delivery.SubscriptionID,
+		delivery.Event,
+		delivery.TripID,
+		delivery.Payload,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseStatus,
+		delivery.LastError,
+		delivery.NextAttemptAt,
+		delivery.DeliveredAt,
+		delivery.CreatedAt,
+		delivery.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateDelivery records the outcome of a delivery attempt
+func (r *WebhookRepositoryImpl) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, response_status = $4, last_error = $5,
+			next_attempt_at = $6, delivered_at = $7, updated_at = $8
+		WHERE id = $1
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		delivery.ID,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.ResponseStatus,
+		delivery.LastError,
+		delivery.NextAttemptAt,
+		delivery.DeliveredAt,
+		delivery.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return &models.NotFoundError{
+			Resource: "webhook delivery",
+			ID:       delivery.ID.String(),
+		}
+	}
+
+	return nil
+}
+
+// GetDueDeliveries retrieves pending deliveries whose next attempt is due, oldest first
+func (r *WebhookRepositoryImpl) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries
+		WHERE status = 'pending' AND next_attempt_at <= $1
+		ORDER BY next_attempt_at, id
+		LIMIT $2
+	`
+
+	var deliveries []*models.WebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, now, limit); err != nil {
+		return nil, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// ListDeliveries retrieves the deliveries matching the filter, newest first, with the total number of matches
+func (r *WebhookRepositoryImpl) ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int64, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.SubscriptionID != nil {
+		args = append(args, *filter.SubscriptionID)
+		conditions = append(conditions, fmt.Sprintf("subscription_id = $%d", len(args)))
+	}
+	if filter.TripID != nil {
+		args = append(args, *filter.TripID)
+		conditions = append(conditions, fmt.Sprintf("trip_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	var total int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM webhook_deliveries `+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM webhook_deliveries
+		%s
+		ORDER BY created_at DESC, id
+		LIMIT $%d OFFSET $%d
+	`, webhookDeliveryColumns, where, len(args)+1, len(args)+2)
+
+	var deliveries []*models.WebhookDelivery
+	if err := r.db.SelectContext(ctx, &deliveries, query, append(args, filter.Limit, filter.Offset)...); err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	return deliveries, total, nil
+}
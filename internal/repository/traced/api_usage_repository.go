@@ -0,0 +1,39 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// apiUsageRepository traces a repository.APIUsageRepository
+type apiUsageRepository struct {
+	next repository.APIUsageRepository
+	inst *Instrumentation
+}
+
+func (r *apiUsageRepository) Upsert(ctx context.Context, rollups []*models.APIUsageRollup) error {
+	return exec(ctx, r.inst, "APIUsageRepository", "Upsert", []any{"rollups", rollups}, func(ctx context.Context) error {
+		return r.next.Upsert(ctx, rollups)
+	})
+}
+
+func (r *apiUsageRepository) List(ctx context.Context, filter models.APIUsageFilter) ([]*models.APIUsageRollup, error) {
+	return query(ctx, r.inst, "APIUsageRepository", "List", []any{"filter", filter}, func(ctx context.Context) ([]*models.APIUsageRollup, error) {
+		return r.next.List(ctx, filter)
+	})
+}
+
+func (r *apiUsageRepository) CountRequests(ctx context.Context, keyID string, from, to time.Time) (int64, error) {
+	return query(ctx, r.inst, "APIUsageRepository", "CountRequests", []any{"key_id", keyID, "from", from, "to", to}, func(ctx context.Context) (int64, error) {
+		return r.next.CountRequests(ctx, keyID, from, to)
+	})
+}
+
+func (r *apiUsageRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	return query(ctx, r.inst, "APIUsageRepository", "DeleteBefore", []any{"before", before}, func(ctx context.Context) (int64, error) {
+		return r.next.DeleteBefore(ctx, before)
+	})
+}
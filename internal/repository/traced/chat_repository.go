@@ -0,0 +1,45 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// chatRepository traces a repository.ChatRepository
+type chatRepository struct {
+	next repository.ChatRepository
+	inst *Instrumentation
+}
+
+func (r *chatRepository) Create(ctx context.Context, msg *models.TripChatMessage) error {
+	return exec(ctx, r.inst, "ChatRepository", "Create", []any{"msg", msg}, func(ctx context.Context) error {
+		return r.next.Create(ctx, msg)
+	})
+}
+
+func (r *chatRepository) ListByTrip(ctx context.Context, tripID string, limit, offset int) ([]*models.TripChatMessage, error) {
+	return query(ctx, r.inst, "ChatRepository", "ListByTrip", []any{"tripID", tripID, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.TripChatMessage, error) {
+		return r.next.ListByTrip(ctx, tripID, limit, offset)
+	})
+}
+
+func (r *chatRepository) MarkRead(ctx context.Context, tripID string, reader models.ChatSenderRole, at time.Time) (int, error) {
+	return query(ctx, r.inst, "ChatRepository", "MarkRead", []any{"tripID", tripID, "reader", reader, "at", at}, func(ctx context.Context) (int, error) {
+		return r.next.MarkRead(ctx, tripID, reader, at)
+	})
+}
+
+func (r *chatRepository) CountUnread(ctx context.Context, tripID string) (*models.TripChatUnreadCounts, error) {
+	return query(ctx, r.inst, "ChatRepository", "CountUnread", []any{"tripID", tripID}, func(ctx context.Context) (*models.TripChatUnreadCounts, error) {
+		return r.next.CountUnread(ctx, tripID)
+	})
+}
+
+func (r *chatRepository) DeleteForTripsEndedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return query(ctx, r.inst, "ChatRepository", "DeleteForTripsEndedBefore", []any{"cutoff", cutoff}, func(ctx context.Context) (int64, error) {
+		return r.next.DeleteForTripsEndedBefore(ctx, cutoff)
+	})
+}
@@ -0,0 +1,59 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// corporateAccountRepository traces a repository.CorporateAccountRepository
+type corporateAccountRepository struct {
+	next repository.CorporateAccountRepository
+	inst *Instrumentation
+}
+
+func (r *corporateAccountRepository) Create(ctx context.Context, account *models.CorporateAccount) error {
+	return exec(ctx, r.inst, "CorporateAccountRepository", "Create", []any{"account", account}, func(ctx context.Context) error {
+		return r.next.Create(ctx, account)
+	})
+}
+
+func (r *corporateAccountRepository) GetByID(ctx context.Context, id string) (*models.CorporateAccount, error) {
+	return query(ctx, r.inst, "CorporateAccountRepository", "GetByID", []any{"id", id}, func(ctx context.Context) (*models.CorporateAccount, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+func (r *corporateAccountRepository) Update(ctx context.Context, account *models.CorporateAccount) error {
+	return exec(ctx, r.inst, "CorporateAccountRepository", "Update", []any{"account", account}, func(ctx context.Context) error {
+		return r.next.Update(ctx, account)
+	})
+}
+
+func (r *corporateAccountRepository) SetPassengerAccount(ctx context.Context, passengerID string, accountID *uuid.UUID) error {
+	return exec(ctx, r.inst, "CorporateAccountRepository", "SetPassengerAccount", []any{"passengerID", passengerID, "accountID", accountID}, func(ctx context.Context) error {
+		return r.next.SetPassengerAccount(ctx, passengerID, accountID)
+	})
+}
+
+func (r *corporateAccountRepository) ChargeTrip(ctx context.Context, charge *models.CorporateTripCharge, limit *float64, periodStart, periodEnd time.Time) error {
+	return exec(ctx, r.inst, "CorporateAccountRepository", "ChargeTrip", []any{"charge", charge, "limit", limit, "periodStart", periodStart, "periodEnd", periodEnd}, func(ctx context.Context) error {
+		return r.next.ChargeTrip(ctx, charge, limit, periodStart, periodEnd)
+	})
+}
+
+func (r *corporateAccountRepository) ListCharges(ctx context.Context, accountID string, start, end time.Time) ([]*models.CorporateTripCharge, error) {
+	return query(ctx, r.inst, "CorporateAccountRepository", "ListCharges", []any{"accountID", accountID, "start", start, "end", end}, func(ctx context.Context) ([]*models.CorporateTripCharge, error) {
+		return r.next.ListCharges(ctx, accountID, start, end)
+	})
+}
+
+func (r *corporateAccountRepository) List(ctx context.Context) ([]*models.CorporateAccount, error) {
+	return query(ctx, r.inst, "CorporateAccountRepository", "List", nil, func(ctx context.Context) ([]*models.CorporateAccount, error) {
+		return r.next.List(ctx)
+	})
+}
@@ -0,0 +1,69 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// dashboardRepository traces a repository.DashboardRepository
+type dashboardRepository struct {
+	next repository.DashboardRepository
+	inst *Instrumentation
+}
+
+func (r *dashboardRepository) GetTripTimings(ctx context.Context, since time.Time) ([]*models.TripTiming, error) {
+	return query(ctx, r.inst, "DashboardRepository", "GetTripTimings", []any{"since", since}, func(ctx context.Context) ([]*models.TripTiming, error) {
+		return r.next.GetTripTimings(ctx, since)
+	})
+}
+
+func (r *dashboardRepository) GetDriverPositions(ctx context.Context) ([]*models.DriverPosition, error) {
+	return query(ctx, r.inst, "DashboardRepository", "GetDriverPositions", nil, func(ctx context.Context) ([]*models.DriverPosition, error) {
+		return r.next.GetDriverPositions(ctx)
+	})
+}
+
+func (r *dashboardRepository) ReplaceHourlyTrips(ctx context.Context, since time.Time, rows []*models.HourlyTripSummary, refresh *models.DashboardRefresh) error {
+	return exec(ctx, r.inst, "DashboardRepository", "ReplaceHourlyTrips", []any{"since", since, "rows", rows, "refresh", refresh}, func(ctx context.Context) error {
+		return r.next.ReplaceHourlyTrips(ctx, since, rows, refresh)
+	})
+}
+
+func (r *dashboardRepository) ReplaceMatchingTimes(ctx context.Context, since time.Time, rows []*models.MatchingTimeSummary, refresh *models.DashboardRefresh) error {
+	return exec(ctx, r.inst, "DashboardRepository", "ReplaceMatchingTimes", []any{"since", since, "rows", rows, "refresh", refresh}, func(ctx context.Context) error {
+		return r.next.ReplaceMatchingTimes(ctx, since, rows, refresh)
+	})
+}
+
+func (r *dashboardRepository) ReplaceZoneSupply(ctx context.Context, rows []*models.ZoneSupplySummary, refresh *models.DashboardRefresh) error {
+	return exec(ctx, r.inst, "DashboardRepository", "ReplaceZoneSupply", []any{"rows", rows, "refresh", refresh}, func(ctx context.Context) error {
+		return r.next.ReplaceZoneSupply(ctx, rows, refresh)
+	})
+}
+
+func (r *dashboardRepository) ListHourlyTrips(ctx context.Context, since time.Time) ([]*models.HourlyTripSummary, error) {
+	return query(ctx, r.inst, "DashboardRepository", "ListHourlyTrips", []any{"since", since}, func(ctx context.Context) ([]*models.HourlyTripSummary, error) {
+		return r.next.ListHourlyTrips(ctx, since)
+	})
+}
+
+func (r *dashboardRepository) ListMatchingTimes(ctx context.Context, since time.Time) ([]*models.MatchingTimeSummary, error) {
+	return query(ctx, r.inst, "DashboardRepository", "ListMatchingTimes", []any{"since", since}, func(ctx context.Context) ([]*models.MatchingTimeSummary, error) {
+		return r.next.ListMatchingTimes(ctx, since)
+	})
+}
+
+func (r *dashboardRepository) ListZoneSupply(ctx context.Context) ([]*models.ZoneSupplySummary, error) {
+	return query(ctx, r.inst, "DashboardRepository", "ListZoneSupply", nil, func(ctx context.Context) ([]*models.ZoneSupplySummary, error) {
+		return r.next.ListZoneSupply(ctx)
+	})
+}
+
+func (r *dashboardRepository) GetRefresh(ctx context.Context, view string) (*models.DashboardRefresh, error) {
+	return query(ctx, r.inst, "DashboardRepository", "GetRefresh", []any{"view", view}, func(ctx context.Context) (*models.DashboardRefresh, error) {
+		return r.next.GetRefresh(ctx, view)
+	})
+}
@@ -0,0 +1,33 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// driverLocationRepository traces a repository.DriverLocationRepository
+type driverLocationRepository struct {
+	next repository.DriverLocationRepository
+	inst *Instrumentation
+}
+
+func (r *driverLocationRepository) Record(ctx context.Context, ping *models.DriverLocationPing) error {
+	return exec(ctx, r.inst, "DriverLocationRepository", "Record", []any{"ping", ping}, func(ctx context.Context) error {
+		return r.next.Record(ctx, ping)
+	})
+}
+
+func (r *driverLocationRepository) GetTrail(ctx context.Context, driverID string, from, to time.Time, limit int) ([]*models.DriverLocationPing, error) {
+	return query(ctx, r.inst, "DriverLocationRepository", "GetTrail", []any{"driverID", driverID, "from", from, "to", to, "limit", limit}, func(ctx context.Context) ([]*models.DriverLocationPing, error) {
+		return r.next.GetTrail(ctx, driverID, from, to, limit)
+	})
+}
+
+func (r *driverLocationRepository) Downsample(ctx context.Context, before time.Time, resolution time.Duration) (int64, error) {
+	return query(ctx, r.inst, "DriverLocationRepository", "Downsample", []any{"before", before, "resolution", resolution}, func(ctx context.Context) (int64, error) {
+		return r.next.Downsample(ctx, before, resolution)
+	})
+}
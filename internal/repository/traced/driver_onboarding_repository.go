@@ -0,0 +1,45 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// driverOnboardingRepository traces a repository.DriverOnboardingRepository
+type driverOnboardingRepository struct {
+	next repository.DriverOnboardingRepository
+	inst *Instrumentation
+}
+
+func (r *driverOnboardingRepository) Create(ctx context.Context, onboarding *models.DriverOnboarding) error {
+	return exec(ctx, r.inst, "DriverOnboardingRepository", "Create", []any{"onboarding", onboarding}, func(ctx context.Context) error {
+		return r.next.Create(ctx, onboarding)
+	})
+}
+
+func (r *driverOnboardingRepository) CreateWithDriver(ctx context.Context, driver *models.Driver, onboarding *models.DriverOnboarding) error {
+	return exec(ctx, r.inst, "DriverOnboardingRepository", "CreateWithDriver", []any{"driver", driver, "onboarding", onboarding}, func(ctx context.Context) error {
+		return r.next.CreateWithDriver(ctx, driver, onboarding)
+	})
+}
+
+func (r *driverOnboardingRepository) GetByDriverID(ctx context.Context, driverID string) (*models.DriverOnboarding, error) {
+	return query(ctx, r.inst, "DriverOnboardingRepository", "GetByDriverID", []any{"driverID", driverID}, func(ctx context.Context) (*models.DriverOnboarding, error) {
+		return r.next.GetByDriverID(ctx, driverID)
+	})
+}
+
+func (r *driverOnboardingRepository) Update(ctx context.Context, onboarding *models.DriverOnboarding, from models.OnboardingStage) error {
+	return exec(ctx, r.inst, "DriverOnboardingRepository", "Update", []any{"onboarding", onboarding, "from", from}, func(ctx context.Context) error {
+		return r.next.Update(ctx, onboarding, from)
+	})
+}
+
+func (r *driverOnboardingRepository) CountByStage(ctx context.Context, start, end time.Time) ([]models.OnboardingStageCount, error) {
+	return query(ctx, r.inst, "DriverOnboardingRepository", "CountByStage", []any{"start", start, "end", end}, func(ctx context.Context) ([]models.OnboardingStageCount, error) {
+		return r.next.CountByStage(ctx, start, end)
+	})
+}
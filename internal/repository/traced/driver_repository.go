@@ -0,0 +1,165 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// driverRepository traces a repository.DriverRepository
+type driverRepository struct {
+	next repository.DriverRepository
+	inst *Instrumentation
+}
+
+func (r *driverRepository) Create(ctx context.Context, driver *models.Driver) error {
+	return exec(ctx, r.inst, "DriverRepository", "Create", []any{"driver", driver}, func(ctx context.Context) error {
+		return r.next.Create(ctx, driver)
+	})
+}
+
+func (r *driverRepository) GetByID(ctx context.Context, id string) (*models.Driver, error) {
+	return query(ctx, r.inst, "DriverRepository", "GetByID", []any{"id", id}, func(ctx context.Context) (*models.Driver, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+func (r *driverRepository) GetByUserID(ctx context.Context, userID string) (*models.Driver, error) {
+	return query(ctx, r.inst, "DriverRepository", "GetByUserID", []any{"userID", userID}, func(ctx context.Context) (*models.Driver, error) {
+		return r.next.GetByUserID(ctx, userID)
+	})
+}
+
+func (r *driverRepository) Update(ctx context.Context, driver *models.Driver) error {
+	return exec(ctx, r.inst, "DriverRepository", "Update", []any{"driver", driver}, func(ctx context.Context) error {
+		return r.next.Update(ctx, driver)
+	})
+}
+
+func (r *driverRepository) Delete(ctx context.Context, id string) error {
+	return exec(ctx, r.inst, "DriverRepository", "Delete", []any{"id", id}, func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func (r *driverRepository) GetOnlineDrivers(ctx context.Context) ([]*models.Driver, error) {
+	return query(ctx, r.inst, "DriverRepository", "GetOnlineDrivers", nil, func(ctx context.Context) ([]*models.Driver, error) {
+		return r.next.GetOnlineDrivers(ctx)
+	})
+}
+
+func (r *driverRepository) GetDriversInRadius(ctx context.Context, lat, lng, radiusKm float64) ([]*models.Driver, error) {
+	return query(ctx, r.inst, "DriverRepository", "GetDriversInRadius", []any{"lat", lat, "lng", lng, "radiusKm", radiusKm}, func(ctx context.Context) ([]*models.Driver, error) {
+		return r.next.GetDriversInRadius(ctx, lat, lng, radiusKm)
+	})
+}
+
+func (r *driverRepository) UpdateLocation(ctx context.Context, driverID string, lat, lng float64) error {
+	return exec(ctx, r.inst, "DriverRepository", "UpdateLocation", []any{"driverID", driverID, "lat", lat, "lng", lng}, func(ctx context.Context) error {
+		return r.next.UpdateLocation(ctx, driverID, lat, lng)
+	})
+}
+
+func (r *driverRepository) UpdateLocations(ctx context.Context, updates []*models.DriverLocationUpdate) (int64, error) {
+	return query(ctx, r.inst, "DriverRepository", "UpdateLocations", []any{"updates", len(updates)}, func(ctx context.Context) (int64, error) {
+		return r.next.UpdateLocations(ctx, updates)
+	})
+}
+
+func (r *driverRepository) UpdateStatus(ctx context.Context, driverID string, status models.DriverStatus) error {
+	return exec(ctx, r.inst, "DriverRepository", "UpdateStatus", []any{"driverID", driverID, "status", status}, func(ctx context.Context) error {
+		return r.next.UpdateStatus(ctx, driverID, status)
+	})
+}
+
+func (r *driverRepository) Reserve(ctx context.Context, driverID string) error {
+	return exec(ctx, r.inst, "DriverRepository", "Reserve", []any{"driverID", driverID}, func(ctx context.Context) error {
+		return r.next.Reserve(ctx, driverID)
+	})
+}
+
+func (r *driverRepository) Release(ctx context.Context, driverID string) error {
+	return exec(ctx, r.inst, "DriverRepository", "Release", []any{"driverID", driverID}, func(ctx context.Context) error {
+		return r.next.Release(ctx, driverID)
+	})
+}
+
+func (r *driverRepository) List(ctx context.Context, limit, offset int) ([]*models.Driver, error) {
+	return query(ctx, r.inst, "DriverRepository", "List", []any{"limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.Driver, error) {
+		return r.next.List(ctx, limit, offset)
+	})
+}
+
+func (r *driverRepository) ListByFleet(ctx context.Context, fleetID string) ([]*models.Driver, error) {
+	return query(ctx, r.inst, "DriverRepository", "ListByFleet", []any{"fleetID", fleetID}, func(ctx context.Context) ([]*models.Driver, error) {
+		return r.next.ListByFleet(ctx, fleetID)
+	})
+}
+
+func (r *driverRepository) GetDriverStats(ctx context.Context, filter models.DriverStatsFilter) ([]*models.DriverStats, int64, error) {
+	return query2(ctx, r.inst, "DriverRepository", "GetDriverStats", []any{"filter", filter}, func(ctx context.Context) ([]*models.DriverStats, int64, error) {
+		return r.next.GetDriverStats(ctx, filter)
+	})
+}
+
+func (r *driverRepository) GetTripStats(ctx context.Context, driverID string) (*models.DriverTripStats, error) {
+	return query(ctx, r.inst, "DriverRepository", "GetTripStats", []any{"driverID", driverID}, func(ctx context.Context) (*models.DriverTripStats, error) {
+		return r.next.GetTripStats(ctx, driverID)
+	})
+}
+
+func (r *driverRepository) SetDestination(ctx context.Context, destination *models.DriverDestination) error {
+	return exec(ctx, r.inst, "DriverRepository", "SetDestination", []any{"destination", destination}, func(ctx context.Context) error {
+		return r.next.SetDestination(ctx, destination)
+	})
+}
+
+func (r *driverRepository) GetActiveDestination(ctx context.Context, driverID string) (*models.DriverDestination, error) {
+	return query(ctx, r.inst, "DriverRepository", "GetActiveDestination", []any{"driverID", driverID}, func(ctx context.Context) (*models.DriverDestination, error) {
+		return r.next.GetActiveDestination(ctx, driverID)
+	})
+}
+
+func (r *driverRepository) GetActiveDestinations(ctx context.Context) (map[string]*models.DriverDestination, error) {
+	return query(ctx, r.inst, "DriverRepository", "GetActiveDestinations", nil, func(ctx context.Context) (map[string]*models.DriverDestination, error) {
+		return r.next.GetActiveDestinations(ctx)
+	})
+}
+
+func (r *driverRepository) ClearDestination(ctx context.Context, driverID string) error {
+	return exec(ctx, r.inst, "DriverRepository", "ClearDestination", []any{"driverID", driverID}, func(ctx context.Context) error {
+		return r.next.ClearDestination(ctx, driverID)
+	})
+}
+
+func (r *driverRepository) CountDestinationsSince(ctx context.Context, driverID string, since time.Time) (int, error) {
+	return query(ctx, r.inst, "DriverRepository", "CountDestinationsSince", []any{"driverID", driverID, "since", since}, func(ctx context.Context) (int, error) {
+		return r.next.CountDestinationsSince(ctx, driverID, since)
+	})
+}
+
+func (r *driverRepository) GetDriverHours(ctx context.Context, driverID string, from, to time.Time) (*models.DriverHours, error) {
+	return query(ctx, r.inst, "DriverRepository", "GetDriverHours", []any{"driverID", driverID, "from", from, "to", to}, func(ctx context.Context) (*models.DriverHours, error) {
+		return r.next.GetDriverHours(ctx, driverID, from, to)
+	})
+}
+
+func (r *driverRepository) ListOnlineDriverHours(ctx context.Context, from, to time.Time) ([]*models.DriverHours, error) {
+	return query(ctx, r.inst, "DriverRepository", "ListOnlineDriverHours", []any{"from", from, "to", to}, func(ctx context.Context) ([]*models.DriverHours, error) {
+		return r.next.ListOnlineDriverHours(ctx, from, to)
+	})
+}
+
+func (r *driverRepository) StartRest(ctx context.Context, rest *models.DriverRest) error {
+	return exec(ctx, r.inst, "DriverRepository", "StartRest", []any{"rest", rest}, func(ctx context.Context) error {
+		return r.next.StartRest(ctx, rest)
+	})
+}
+
+func (r *driverRepository) GetLatestRest(ctx context.Context, driverID string) (*models.DriverRest, error) {
+	return query(ctx, r.inst, "DriverRepository", "GetLatestRest", []any{"driverID", driverID}, func(ctx context.Context) (*models.DriverRest, error) {
+		return r.next.GetLatestRest(ctx, driverID)
+	})
+}
@@ -0,0 +1,39 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// etaPredictionRepository traces a repository.ETAPredictionRepository
+type etaPredictionRepository struct {
+	next repository.ETAPredictionRepository
+	inst *Instrumentation
+}
+
+func (r *etaPredictionRepository) Create(ctx context.Context, prediction *models.ETAPrediction) error {
+	return exec(ctx, r.inst, "ETAPredictionRepository", "Create", []any{"prediction", prediction}, func(ctx context.Context) error {
+		return r.next.Create(ctx, prediction)
+	})
+}
+
+func (r *etaPredictionRepository) GetByTrip(ctx context.Context, tripID string) (*models.ETAPrediction, error) {
+	return query(ctx, r.inst, "ETAPredictionRepository", "GetByTrip", []any{"tripID", tripID}, func(ctx context.Context) (*models.ETAPrediction, error) {
+		return r.next.GetByTrip(ctx, tripID)
+	})
+}
+
+func (r *etaPredictionRepository) RecordArrival(ctx context.Context, prediction *models.ETAPrediction) error {
+	return exec(ctx, r.inst, "ETAPredictionRepository", "RecordArrival", []any{"prediction", prediction}, func(ctx context.Context) error {
+		return r.next.RecordArrival(ctx, prediction)
+	})
+}
+
+func (r *etaPredictionRepository) GetAccuracy(ctx context.Context, from, to time.Time) ([]*models.ETAAccuracy, error) {
+	return query(ctx, r.inst, "ETAPredictionRepository", "GetAccuracy", []any{"from", from, "to", to}, func(ctx context.Context) ([]*models.ETAAccuracy, error) {
+		return r.next.GetAccuracy(ctx, from, to)
+	})
+}
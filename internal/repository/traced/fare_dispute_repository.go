@@ -0,0 +1,50 @@
+package traced
+
+import (
+	"context"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// fareDisputeRepository traces a repository.FareDisputeRepository
+type fareDisputeRepository struct {
+	next repository.FareDisputeRepository
+	inst *Instrumentation
+}
+
+func (r *fareDisputeRepository) CreateDispute(ctx context.Context, dispute *models.FareDispute) error {
+	return exec(ctx, r.inst, "FareDisputeRepository", "CreateDispute", []any{"dispute", dispute}, func(ctx context.Context) error {
+		return r.next.CreateDispute(ctx, dispute)
+	})
+}
+
+func (r *fareDisputeRepository) GetDispute(ctx context.Context, id string) (*models.FareDispute, error) {
+	return query(ctx, r.inst, "FareDisputeRepository", "GetDispute", []any{"id", id}, func(ctx context.Context) (*models.FareDispute, error) {
+		return r.next.GetDispute(ctx, id)
+	})
+}
+
+func (r *fareDisputeRepository) UpdateDispute(ctx context.Context, dispute *models.FareDispute, from models.FareDisputeStatus) error {
+	return exec(ctx, r.inst, "FareDisputeRepository", "UpdateDispute", []any{"dispute", dispute, "from", from}, func(ctx context.Context) error {
+		return r.next.UpdateDispute(ctx, dispute, from)
+	})
+}
+
+func (r *fareDisputeRepository) ResolveDispute(ctx context.Context, dispute *models.FareDispute, from models.FareDisputeStatus, adjustment *models.FareAdjustment) error {
+	return exec(ctx, r.inst, "FareDisputeRepository", "ResolveDispute", []any{"dispute", dispute, "from", from, "adjustment", adjustment}, func(ctx context.Context) error {
+		return r.next.ResolveDispute(ctx, dispute, from, adjustment)
+	})
+}
+
+func (r *fareDisputeRepository) ListDisputes(ctx context.Context, filter models.FareDisputeFilter) ([]*models.FareDispute, int64, error) {
+	return query2(ctx, r.inst, "FareDisputeRepository", "ListDisputes", []any{"filter", filter}, func(ctx context.Context) ([]*models.FareDispute, int64, error) {
+		return r.next.ListDisputes(ctx, filter)
+	})
+}
+
+func (r *fareDisputeRepository) ListAdjustmentsByTrip(ctx context.Context, tripID string) ([]*models.FareAdjustment, error) {
+	return query(ctx, r.inst, "FareDisputeRepository", "ListAdjustmentsByTrip", []any{"tripID", tripID}, func(ctx context.Context) ([]*models.FareAdjustment, error) {
+		return r.next.ListAdjustmentsByTrip(ctx, tripID)
+	})
+}
@@ -0,0 +1,44 @@
+package traced
+
+import (
+	"context"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// fleetRepository traces a repository.FleetRepository
+type fleetRepository struct {
+	next repository.FleetRepository
+	inst *Instrumentation
+}
+
+func (r *fleetRepository) Create(ctx context.Context, fleet *models.Fleet) error {
+	return exec(ctx, r.inst, "FleetRepository", "Create", []any{"fleet", fleet}, func(ctx context.Context) error {
+		return r.next.Create(ctx, fleet)
+	})
+}
+
+func (r *fleetRepository) GetByID(ctx context.Context, id string) (*models.Fleet, error) {
+	return query(ctx, r.inst, "FleetRepository", "GetByID", []any{"id", id}, func(ctx context.Context) (*models.Fleet, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+func (r *fleetRepository) GetByAPIKeyHash(ctx context.Context, hash string) (*models.Fleet, error) {
+	return query(ctx, r.inst, "FleetRepository", "GetByAPIKeyHash", []any{"hash", hash}, func(ctx context.Context) (*models.Fleet, error) {
+		return r.next.GetByAPIKeyHash(ctx, hash)
+	})
+}
+
+func (r *fleetRepository) List(ctx context.Context) ([]*models.Fleet, error) {
+	return query(ctx, r.inst, "FleetRepository", "List", nil, func(ctx context.Context) ([]*models.Fleet, error) {
+		return r.next.List(ctx)
+	})
+}
+
+func (r *fleetRepository) Update(ctx context.Context, fleet *models.Fleet) error {
+	return exec(ctx, r.inst, "FleetRepository", "Update", []any{"fleet", fleet}, func(ctx context.Context) error {
+		return r.next.Update(ctx, fleet)
+	})
+}
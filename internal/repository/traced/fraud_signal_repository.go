@@ -0,0 +1,38 @@
+package traced
+
+import (
+	"context"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// fraudSignalRepository traces a repository.FraudSignalRepository
+type fraudSignalRepository struct {
+	next repository.FraudSignalRepository
+	inst *Instrumentation
+}
+
+func (r *fraudSignalRepository) Create(ctx context.Context, signal *models.FraudSignal) error {
+	return exec(ctx, r.inst, "FraudSignalRepository", "Create", []any{"signal", signal}, func(ctx context.Context) error {
+		return r.next.Create(ctx, signal)
+	})
+}
+
+func (r *fraudSignalRepository) GetByID(ctx context.Context, id string) (*models.FraudSignal, error) {
+	return query(ctx, r.inst, "FraudSignalRepository", "GetByID", []any{"id", id}, func(ctx context.Context) (*models.FraudSignal, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+func (r *fraudSignalRepository) Update(ctx context.Context, signal *models.FraudSignal, from models.FraudSignalStatus) error {
+	return exec(ctx, r.inst, "FraudSignalRepository", "Update", []any{"signal", signal, "from", from}, func(ctx context.Context) error {
+		return r.next.Update(ctx, signal, from)
+	})
+}
+
+func (r *fraudSignalRepository) List(ctx context.Context, filter models.FraudSignalFilter) ([]*models.FraudSignal, int64, error) {
+	return query2(ctx, r.inst, "FraudSignalRepository", "List", []any{"filter", filter}, func(ctx context.Context) ([]*models.FraudSignal, int64, error) {
+		return r.next.List(ctx, filter)
+	})
+}
@@ -0,0 +1,57 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// incentiveRepository traces a repository.IncentiveRepository
+type incentiveRepository struct {
+	next repository.IncentiveRepository
+	inst *Instrumentation
+}
+
+func (r *incentiveRepository) CreateCampaign(ctx context.Context, campaign *models.IncentiveCampaign) error {
+	return exec(ctx, r.inst, "IncentiveRepository", "CreateCampaign", []any{"campaign", campaign}, func(ctx context.Context) error {
+		return r.next.CreateCampaign(ctx, campaign)
+	})
+}
+
+func (r *incentiveRepository) GetCampaign(ctx context.Context, id string) (*models.IncentiveCampaign, error) {
+	return query(ctx, r.inst, "IncentiveRepository", "GetCampaign", []any{"id", id}, func(ctx context.Context) (*models.IncentiveCampaign, error) {
+		return r.next.GetCampaign(ctx, id)
+	})
+}
+
+func (r *incentiveRepository) ListCampaigns(ctx context.Context, runningAt *time.Time) ([]*models.IncentiveCampaign, error) {
+	return query(ctx, r.inst, "IncentiveRepository", "ListCampaigns", []any{"runningAt", runningAt}, func(ctx context.Context) ([]*models.IncentiveCampaign, error) {
+		return r.next.ListCampaigns(ctx, runningAt)
+	})
+}
+
+func (r *incentiveRepository) SetCampaignActive(ctx context.Context, id string, active bool) error {
+	return exec(ctx, r.inst, "IncentiveRepository", "SetCampaignActive", []any{"id", id, "active", active}, func(ctx context.Context) error {
+		return r.next.SetCampaignActive(ctx, id, active)
+	})
+}
+
+func (r *incentiveRepository) CreditTrip(ctx context.Context, credit *models.IncentiveTripCredit, payout *models.IncentivePayout) (*models.QuestProgress, bool, error) {
+	return query2(ctx, r.inst, "IncentiveRepository", "CreditTrip", []any{"credit", credit, "payout", payout}, func(ctx context.Context) (*models.QuestProgress, bool, error) {
+		return r.next.CreditTrip(ctx, credit, payout)
+	})
+}
+
+func (r *incentiveRepository) ListProgressByDriver(ctx context.Context, driverID string) ([]*models.QuestProgress, error) {
+	return query(ctx, r.inst, "IncentiveRepository", "ListProgressByDriver", []any{"driverID", driverID}, func(ctx context.Context) ([]*models.QuestProgress, error) {
+		return r.next.ListProgressByDriver(ctx, driverID)
+	})
+}
+
+func (r *incentiveRepository) ListPayoutsByDriver(ctx context.Context, driverID string) ([]*models.IncentivePayout, error) {
+	return query(ctx, r.inst, "IncentiveRepository", "ListPayoutsByDriver", []any{"driverID", driverID}, func(ctx context.Context) ([]*models.IncentivePayout, error) {
+		return r.next.ListPayoutsByDriver(ctx, driverID)
+	})
+}
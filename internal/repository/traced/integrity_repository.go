@@ -0,0 +1,57 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// integrityRepository traces a repository.IntegrityRepository
+type integrityRepository struct {
+	next repository.IntegrityRepository
+	inst *Instrumentation
+}
+
+func (r *integrityRepository) ChecksumHours(ctx context.Context, table string, start, end time.Time) ([]*models.IntegrityChecksum, error) {
+	return query(ctx, r.inst, "IntegrityRepository", "ChecksumHours", []any{"table", table, "start", start, "end", end}, func(ctx context.Context) ([]*models.IntegrityChecksum, error) {
+		return r.next.ChecksumHours(ctx, table, start, end)
+	})
+}
+
+func (r *integrityRepository) CreateRun(ctx context.Context, run *models.IntegrityRun, checksums []*models.IntegrityChecksum) error {
+	return exec(ctx, r.inst, "IntegrityRepository", "CreateRun", []any{"run", run, "checksums", checksums}, func(ctx context.Context) error {
+		return r.next.CreateRun(ctx, run, checksums)
+	})
+}
+
+func (r *integrityRepository) GetRun(ctx context.Context, id string) (*models.IntegrityRun, error) {
+	return query(ctx, r.inst, "IntegrityRepository", "GetRun", []any{"id", id}, func(ctx context.Context) (*models.IntegrityRun, error) {
+		return r.next.GetRun(ctx, id)
+	})
+}
+
+func (r *integrityRepository) GetPreviousRun(ctx context.Context, run *models.IntegrityRun) (*models.IntegrityRun, error) {
+	return query(ctx, r.inst, "IntegrityRepository", "GetPreviousRun", []any{"run", run}, func(ctx context.Context) (*models.IntegrityRun, error) {
+		return r.next.GetPreviousRun(ctx, run)
+	})
+}
+
+func (r *integrityRepository) ListRuns(ctx context.Context, limit int) ([]*models.IntegrityRun, error) {
+	return query(ctx, r.inst, "IntegrityRepository", "ListRuns", []any{"limit", limit}, func(ctx context.Context) ([]*models.IntegrityRun, error) {
+		return r.next.ListRuns(ctx, limit)
+	})
+}
+
+func (r *integrityRepository) ListChecksums(ctx context.Context, runID string) ([]*models.IntegrityChecksum, error) {
+	return query(ctx, r.inst, "IntegrityRepository", "ListChecksums", []any{"run_id", runID}, func(ctx context.Context) ([]*models.IntegrityChecksum, error) {
+		return r.next.ListChecksums(ctx, runID)
+	})
+}
+
+func (r *integrityRepository) DeleteRunsBefore(ctx context.Context, before time.Time) (int64, error) {
+	return query(ctx, r.inst, "IntegrityRepository", "DeleteRunsBefore", []any{"before", before}, func(ctx context.Context) (int64, error) {
+		return r.next.DeleteRunsBefore(ctx, before)
+	})
+}
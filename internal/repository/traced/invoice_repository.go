@@ -0,0 +1,44 @@
+package traced
+
+import (
+	"context"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// invoiceRepository traces a repository.InvoiceRepository
+type invoiceRepository struct {
+	next repository.InvoiceRepository
+	inst *Instrumentation
+}
+
+func (r *invoiceRepository) Create(ctx context.Context, invoice *models.Invoice) error {
+	return exec(ctx, r.inst, "InvoiceRepository", "Create", []any{"invoice", invoice}, func(ctx context.Context) error {
+		return r.next.Create(ctx, invoice)
+	})
+}
+
+func (r *invoiceRepository) GetByID(ctx context.Context, id string) (*models.Invoice, error) {
+	return query(ctx, r.inst, "InvoiceRepository", "GetByID", []any{"id", id}, func(ctx context.Context) (*models.Invoice, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+func (r *invoiceRepository) GetByParty(ctx context.Context, partyType models.InvoicePartyType, partyID, month string) (*models.Invoice, error) {
+	return query(ctx, r.inst, "InvoiceRepository", "GetByParty", []any{"partyType", partyType, "partyID", partyID, "month", month}, func(ctx context.Context) (*models.Invoice, error) {
+		return r.next.GetByParty(ctx, partyType, partyID, month)
+	})
+}
+
+func (r *invoiceRepository) Update(ctx context.Context, invoice *models.Invoice) error {
+	return exec(ctx, r.inst, "InvoiceRepository", "Update", []any{"invoice", invoice}, func(ctx context.Context) error {
+		return r.next.Update(ctx, invoice)
+	})
+}
+
+func (r *invoiceRepository) List(ctx context.Context, filter models.InvoiceFilter) ([]*models.Invoice, int64, error) {
+	return query2(ctx, r.inst, "InvoiceRepository", "List", []any{"filter", filter}, func(ctx context.Context) ([]*models.Invoice, int64, error) {
+		return r.next.List(ctx, filter)
+	})
+}
@@ -0,0 +1,190 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/filter"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// observabilityRepository traces a repository.ObservabilityRepository
+type observabilityRepository struct {
+	next repository.ObservabilityRepository
+	inst *Instrumentation
+}
+
+func (r *observabilityRepository) CreateActorInstance(ctx context.Context, instance *models.ActorInstance) error {
+	return exec(ctx, r.inst, "ObservabilityRepository", "CreateActorInstance", []any{"instance", instance}, func(ctx context.Context) error {
+		return r.next.CreateActorInstance(ctx, instance)
+	})
+}
+
+func (r *observabilityRepository) GetActorInstance(ctx context.Context, id string) (*models.ActorInstance, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "GetActorInstance", []any{"id", id}, func(ctx context.Context) (*models.ActorInstance, error) {
+		return r.next.GetActorInstance(ctx, id)
+	})
+}
+
+func (r *observabilityRepository) UpdateActorInstance(ctx context.Context, instance *models.ActorInstance) error {
+	return exec(ctx, r.inst, "ObservabilityRepository", "UpdateActorInstance", []any{"instance", instance}, func(ctx context.Context) error {
+		return r.next.UpdateActorInstance(ctx, instance)
+	})
+}
+
+func (r *observabilityRepository) ListActorInstances(ctx context.Context, actorType string, limit, offset int) ([]*models.ActorInstance, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "ListActorInstances", []any{"actorType", actorType, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.ActorInstance, error) {
+		return r.next.ListActorInstances(ctx, actorType, limit, offset)
+	})
+}
+
+func (r *observabilityRepository) ListActorInstancesByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.ActorInstance, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "ListActorInstancesByEntity", []any{"entityType", entityType, "entityID", entityID, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.ActorInstance, error) {
+		return r.next.ListActorInstancesByEntity(ctx, entityType, entityID, limit, offset)
+	})
+}
+
+func (r *observabilityRepository) GetActorLifecycleActivity(ctx context.Context, lf models.ActorLifecycleFilter) ([]*models.ActorLifecycleActivity, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "GetActorLifecycleActivity", []any{"lf", lf}, func(ctx context.Context) ([]*models.ActorLifecycleActivity, error) {
+		return r.next.GetActorLifecycleActivity(ctx, lf)
+	})
+}
+
+func (r *observabilityRepository) CreateActorMessage(ctx context.Context, message *models.ActorMessage) error {
+	return exec(ctx, r.inst, "ObservabilityRepository", "CreateActorMessage", []any{"message", message}, func(ctx context.Context) error {
+		return r.next.CreateActorMessage(ctx, message)
+	})
+}
+
+func (r *observabilityRepository) GetActorMessage(ctx context.Context, id string) (*models.ActorMessage, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "GetActorMessage", []any{"id", id}, func(ctx context.Context) (*models.ActorMessage, error) {
+		return r.next.GetActorMessage(ctx, id)
+	})
+}
+
+func (r *observabilityRepository) ListActorMessages(ctx context.Context, fromActor, toActor string, limit, offset int) ([]*models.ActorMessage, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "ListActorMessages", []any{"fromActor", fromActor, "toActor", toActor, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.ActorMessage, error) {
+		return r.next.ListActorMessages(ctx, fromActor, toActor, limit, offset)
+	})
+}
+
+func (r *observabilityRepository) GetMessagesByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.ActorMessage, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "GetMessagesByTimeRange", []any{"startTime", startTime, "endTime", endTime, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.ActorMessage, error) {
+		return r.next.GetMessagesByTimeRange(ctx, startTime, endTime, limit, offset)
+	})
+}
+
+func (r *observabilityRepository) GetMessagesByTraceID(ctx context.Context, traceID string) ([]*models.ActorMessage, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "GetMessagesByTraceID", []any{"traceID", traceID}, func(ctx context.Context) ([]*models.ActorMessage, error) {
+		return r.next.GetMessagesByTraceID(ctx, traceID)
+	})
+}
+
+func (r *observabilityRepository) ListActorMessagesByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.ActorMessage, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "ListActorMessagesByEntity", []any{"entityType", entityType, "entityID", entityID, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.ActorMessage, error) {
+		return r.next.ListActorMessagesByEntity(ctx, entityType, entityID, limit, offset)
+	})
+}
+
+func (r *observabilityRepository) FilterActorMessages(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.ActorMessage, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "FilterActorMessages", []any{"f", f, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.ActorMessage, error) {
+		return r.next.FilterActorMessages(ctx, f, limit, offset)
+	})
+}
+
+func (r *observabilityRepository) GetMessageStats(ctx context.Context, window time.Duration) ([]*models.MessageStats, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "GetMessageStats", []any{"window", window}, func(ctx context.Context) ([]*models.MessageStats, error) {
+		return r.next.GetMessageStats(ctx, window)
+	})
+}
+
+func (r *observabilityRepository) CreateSystemMetric(ctx context.Context, metric *models.SystemMetric) error {
+	return exec(ctx, r.inst, "ObservabilityRepository", "CreateSystemMetric", []any{"metric", metric}, func(ctx context.Context) error {
+		return r.next.CreateSystemMetric(ctx, metric)
+	})
+}
+
+func (r *observabilityRepository) GetSystemMetric(ctx context.Context, id string) (*models.SystemMetric, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "GetSystemMetric", []any{"id", id}, func(ctx context.Context) (*models.SystemMetric, error) {
+		return r.next.GetSystemMetric(ctx, id)
+	})
+}
+
+func (r *observabilityRepository) ListSystemMetrics(ctx context.Context, metricType string, limit, offset int) ([]*models.SystemMetric, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "ListSystemMetrics", []any{"metricType", metricType, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.SystemMetric, error) {
+		return r.next.ListSystemMetrics(ctx, metricType, limit, offset)
+	})
+}
+
+func (r *observabilityRepository) GetMetricsByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.SystemMetric, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "GetMetricsByTimeRange", []any{"startTime", startTime, "endTime", endTime, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.SystemMetric, error) {
+		return r.next.GetMetricsByTimeRange(ctx, startTime, endTime, limit, offset)
+	})
+}
+
+func (r *observabilityRepository) CreateDistributedTrace(ctx context.Context, trace *models.DistributedTrace) error {
+	return exec(ctx, r.inst, "ObservabilityRepository", "CreateDistributedTrace", []any{"trace", trace}, func(ctx context.Context) error {
+		return r.next.CreateDistributedTrace(ctx, trace)
+	})
+}
+
+func (r *observabilityRepository) GetDistributedTrace(ctx context.Context, id string) (*models.DistributedTrace, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "GetDistributedTrace", []any{"id", id}, func(ctx context.Context) (*models.DistributedTrace, error) {
+		return r.next.GetDistributedTrace(ctx, id)
+	})
+}
+
+func (r *observabilityRepository) GetTracesByTraceID(ctx context.Context, traceID string) ([]*models.DistributedTrace, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "GetTracesByTraceID", []any{"traceID", traceID}, func(ctx context.Context) ([]*models.DistributedTrace, error) {
+		return r.next.GetTracesByTraceID(ctx, traceID)
+	})
+}
+
+func (r *observabilityRepository) ListDistributedTraces(ctx context.Context, operation string, limit, offset int) ([]*models.DistributedTrace, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "ListDistributedTraces", []any{"operation", operation, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.DistributedTrace, error) {
+		return r.next.ListDistributedTraces(ctx, operation, limit, offset)
+	})
+}
+
+func (r *observabilityRepository) FilterDistributedTraces(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.DistributedTrace, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "FilterDistributedTraces", []any{"f", f, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.DistributedTrace, error) {
+		return r.next.FilterDistributedTraces(ctx, f, limit, offset)
+	})
+}
+
+func (r *observabilityRepository) CreateEventLog(ctx context.Context, log *models.EventLog) error {
+	return exec(ctx, r.inst, "ObservabilityRepository", "CreateEventLog", []any{"log", log}, func(ctx context.Context) error {
+		return r.next.CreateEventLog(ctx, log)
+	})
+}
+
+func (r *observabilityRepository) GetEventLog(ctx context.Context, id string) (*models.EventLog, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "GetEventLog", []any{"id", id}, func(ctx context.Context) (*models.EventLog, error) {
+		return r.next.GetEventLog(ctx, id)
+	})
+}
+
+func (r *observabilityRepository) ListEventLogs(ctx context.Context, eventType, source string, limit, offset int) ([]*models.EventLog, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "ListEventLogs", []any{"eventType", eventType, "source", source, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.EventLog, error) {
+		return r.next.ListEventLogs(ctx, eventType, source, limit, offset)
+	})
+}
+
+func (r *observabilityRepository) GetEventLogsByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.EventLog, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "GetEventLogsByTimeRange", []any{"startTime", startTime, "endTime", endTime, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.EventLog, error) {
+		return r.next.GetEventLogsByTimeRange(ctx, startTime, endTime, limit, offset)
+	})
+}
+
+func (r *observabilityRepository) ListEventLogsByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.EventLog, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "ListEventLogsByEntity", []any{"entityType", entityType, "entityID", entityID, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.EventLog, error) {
+		return r.next.ListEventLogsByEntity(ctx, entityType, entityID, limit, offset)
+	})
+}
+
+func (r *observabilityRepository) FilterEventLogs(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.EventLog, error) {
+	return query(ctx, r.inst, "ObservabilityRepository", "FilterEventLogs", []any{"f", f, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.EventLog, error) {
+		return r.next.FilterEventLogs(ctx, f, limit, offset)
+	})
+}
@@ -0,0 +1,83 @@
+package traced
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// maxParamLength caps the length of a sanitized string argument
+const maxParamLength = 64
+
+// redactedParam replaces the value of a secret argument
+const redactedParam = "[REDACTED]"
+
+// secretParams are the argument name fragments whose values are never reported
+var secretParams = []string{"hash", "token", "password", "secret", "key"}
+
+// sanitizeParams renders the name, value pairs of a call's arguments for the slow query log.
+// Secret arguments are redacted, long strings truncated, and entities reduced to their type and
+// ID so personal data never leaves the repository layer.
+func sanitizeParams(params []any) map[string]string {
+	sanitized := make(map[string]string, len(params)/2)
+	for i := 0; i+1 < len(params); i += 2 {
+		name, _ := params[i].(string)
+		sanitized[name] = sanitizeParam(name, params[i+1])
+	}
+	return sanitized
+}
+
+func sanitizeParam(name string, value any) string {
+	lower := strings.ToLower(name)
+	for _, secret := range secretParams {
+		if strings.Contains(lower, secret) {
+			return redactedParam
+		}
+	}
+	return sanitizeValue(reflect.ValueOf(value))
+}
+
+func sanitizeValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "null"
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.UTC().Format(time.RFC3339Nano)
+	case time.Duration:
+		return value.String()
+	case uuid.UUID:
+		return value.String()
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return "null"
+		}
+		return sanitizeValue(v.Elem())
+	case reflect.String:
+		s := v.String()
+		if utf8.RuneCountInString(s) > maxParamLength {
+			s = string([]rune(s)[:maxParamLength]) + "…"
+		}
+		return s
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return fmt.Sprint(v.Interface())
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fmt.Sprintf("%s(len=%d)", v.Type(), v.Len())
+	case reflect.Struct:
+		if id := v.FieldByName("ID"); id.IsValid() {
+			return fmt.Sprintf("%s{ID: %s}", v.Type(), sanitizeValue(id))
+		}
+		return v.Type().String()
+	}
+	return v.Type().String()
+}
@@ -0,0 +1,50 @@
+package traced
+
+import (
+	"context"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// passengerRepository traces a repository.PassengerRepository
+type passengerRepository struct {
+	next repository.PassengerRepository
+	inst *Instrumentation
+}
+
+func (r *passengerRepository) Create(ctx context.Context, passenger *models.Passenger) error {
+	return exec(ctx, r.inst, "PassengerRepository", "Create", []any{"passenger", passenger}, func(ctx context.Context) error {
+		return r.next.Create(ctx, passenger)
+	})
+}
+
+func (r *passengerRepository) GetByID(ctx context.Context, id string) (*models.Passenger, error) {
+	return query(ctx, r.inst, "PassengerRepository", "GetByID", []any{"id", id}, func(ctx context.Context) (*models.Passenger, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+func (r *passengerRepository) GetByUserID(ctx context.Context, userID string) (*models.Passenger, error) {
+	return query(ctx, r.inst, "PassengerRepository", "GetByUserID", []any{"userID", userID}, func(ctx context.Context) (*models.Passenger, error) {
+		return r.next.GetByUserID(ctx, userID)
+	})
+}
+
+func (r *passengerRepository) Update(ctx context.Context, passenger *models.Passenger) error {
+	return exec(ctx, r.inst, "PassengerRepository", "Update", []any{"passenger", passenger}, func(ctx context.Context) error {
+		return r.next.Update(ctx, passenger)
+	})
+}
+
+func (r *passengerRepository) Delete(ctx context.Context, id string) error {
+	return exec(ctx, r.inst, "PassengerRepository", "Delete", []any{"id", id}, func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func (r *passengerRepository) List(ctx context.Context, limit, offset int) ([]*models.Passenger, error) {
+	return query(ctx, r.inst, "PassengerRepository", "List", []any{"limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.Passenger, error) {
+		return r.next.List(ctx, limit, offset)
+	})
+}
@@ -0,0 +1,57 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// paymentRepository traces a repository.PaymentRepository
+type paymentRepository struct {
+	next repository.PaymentRepository
+	inst *Instrumentation
+}
+
+func (r *paymentRepository) CreatePayment(ctx context.Context, payment *models.TripPayment) error {
+	return exec(ctx, r.inst, "PaymentRepository", "CreatePayment", []any{"payment", payment}, func(ctx context.Context) error {
+		return r.next.CreatePayment(ctx, payment)
+	})
+}
+
+func (r *paymentRepository) ListPaymentsByTrip(ctx context.Context, tripID string) ([]*models.TripPayment, error) {
+	return query(ctx, r.inst, "PaymentRepository", "ListPaymentsByTrip", []any{"tripID", tripID}, func(ctx context.Context) ([]*models.TripPayment, error) {
+		return r.next.ListPaymentsByTrip(ctx, tripID)
+	})
+}
+
+func (r *paymentRepository) ListBalances(ctx context.Context, start, end time.Time) ([]*models.TripPaymentBalance, error) {
+	return query(ctx, r.inst, "PaymentRepository", "ListBalances", []any{"start", start, "end", end}, func(ctx context.Context) ([]*models.TripPaymentBalance, error) {
+		return r.next.ListBalances(ctx, start, end)
+	})
+}
+
+func (r *paymentRepository) CreateDiscrepancy(ctx context.Context, discrepancy *models.PaymentDiscrepancy) error {
+	return exec(ctx, r.inst, "PaymentRepository", "CreateDiscrepancy", []any{"discrepancy", discrepancy}, func(ctx context.Context) error {
+		return r.next.CreateDiscrepancy(ctx, discrepancy)
+	})
+}
+
+func (r *paymentRepository) GetDiscrepancy(ctx context.Context, id string) (*models.PaymentDiscrepancy, error) {
+	return query(ctx, r.inst, "PaymentRepository", "GetDiscrepancy", []any{"id", id}, func(ctx context.Context) (*models.PaymentDiscrepancy, error) {
+		return r.next.GetDiscrepancy(ctx, id)
+	})
+}
+
+func (r *paymentRepository) UpdateDiscrepancy(ctx context.Context, discrepancy *models.PaymentDiscrepancy, from models.PaymentDiscrepancyStatus) error {
+	return exec(ctx, r.inst, "PaymentRepository", "UpdateDiscrepancy", []any{"discrepancy", discrepancy, "from", from}, func(ctx context.Context) error {
+		return r.next.UpdateDiscrepancy(ctx, discrepancy, from)
+	})
+}
+
+func (r *paymentRepository) ListDiscrepancies(ctx context.Context, filter models.PaymentDiscrepancyFilter) ([]*models.PaymentDiscrepancy, int64, error) {
+	return query2(ctx, r.inst, "PaymentRepository", "ListDiscrepancies", []any{"filter", filter}, func(ctx context.Context) ([]*models.PaymentDiscrepancy, int64, error) {
+		return r.next.ListDiscrepancies(ctx, filter)
+	})
+}
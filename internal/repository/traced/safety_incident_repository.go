@@ -0,0 +1,38 @@
+package traced
+
+import (
+	"context"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// safetyIncidentRepository traces a repository.SafetyIncidentRepository
+type safetyIncidentRepository struct {
+	next repository.SafetyIncidentRepository
+	inst *Instrumentation
+}
+
+func (r *safetyIncidentRepository) Create(ctx context.Context, incident *models.SafetyIncident) error {
+	return exec(ctx, r.inst, "SafetyIncidentRepository", "Create", []any{"incident", incident}, func(ctx context.Context) error {
+		return r.next.Create(ctx, incident)
+	})
+}
+
+func (r *safetyIncidentRepository) GetByID(ctx context.Context, id string) (*models.SafetyIncident, error) {
+	return query(ctx, r.inst, "SafetyIncidentRepository", "GetByID", []any{"id", id}, func(ctx context.Context) (*models.SafetyIncident, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+func (r *safetyIncidentRepository) Update(ctx context.Context, incident *models.SafetyIncident, from models.SafetyIncidentStatus) error {
+	return exec(ctx, r.inst, "SafetyIncidentRepository", "Update", []any{"incident", incident, "from", from}, func(ctx context.Context) error {
+		return r.next.Update(ctx, incident, from)
+	})
+}
+
+func (r *safetyIncidentRepository) List(ctx context.Context, filter models.SafetyIncidentFilter) ([]*models.SafetyIncident, int64, error) {
+	return query2(ctx, r.inst, "SafetyIncidentRepository", "List", []any{"filter", filter}, func(ctx context.Context) ([]*models.SafetyIncident, int64, error) {
+		return r.next.List(ctx, filter)
+	})
+}
@@ -0,0 +1,44 @@
+package traced
+
+import (
+	"context"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// savedLocationRepository traces a repository.SavedLocationRepository
+type savedLocationRepository struct {
+	next repository.SavedLocationRepository
+	inst *Instrumentation
+}
+
+func (r *savedLocationRepository) Create(ctx context.Context, location *models.SavedLocation) error {
+	return exec(ctx, r.inst, "SavedLocationRepository", "Create", []any{"location", location}, func(ctx context.Context) error {
+		return r.next.Create(ctx, location)
+	})
+}
+
+func (r *savedLocationRepository) GetByID(ctx context.Context, id string) (*models.SavedLocation, error) {
+	return query(ctx, r.inst, "SavedLocationRepository", "GetByID", []any{"id", id}, func(ctx context.Context) (*models.SavedLocation, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+func (r *savedLocationRepository) Update(ctx context.Context, location *models.SavedLocation) error {
+	return exec(ctx, r.inst, "SavedLocationRepository", "Update", []any{"location", location}, func(ctx context.Context) error {
+		return r.next.Update(ctx, location)
+	})
+}
+
+func (r *savedLocationRepository) Delete(ctx context.Context, id string) error {
+	return exec(ctx, r.inst, "SavedLocationRepository", "Delete", []any{"id", id}, func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func (r *savedLocationRepository) ListByPassengerID(ctx context.Context, passengerID string) ([]*models.SavedLocation, error) {
+	return query(ctx, r.inst, "SavedLocationRepository", "ListByPassengerID", []any{"passengerID", passengerID}, func(ctx context.Context) ([]*models.SavedLocation, error) {
+		return r.next.ListByPassengerID(ctx, passengerID)
+	})
+}
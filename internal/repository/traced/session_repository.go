@@ -0,0 +1,57 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// sessionRepository traces a repository.SessionRepository
+type sessionRepository struct {
+	next repository.SessionRepository
+	inst *Instrumentation
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *models.Session) error {
+	return exec(ctx, r.inst, "SessionRepository", "Create", []any{"session", session}, func(ctx context.Context) error {
+		return r.next.Create(ctx, session)
+	})
+}
+
+func (r *sessionRepository) GetByID(ctx context.Context, id string) (*models.Session, error) {
+	return query(ctx, r.inst, "SessionRepository", "GetByID", []any{"id", id}, func(ctx context.Context) (*models.Session, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+func (r *sessionRepository) GetByAccessTokenHash(ctx context.Context, hash string) (*models.Session, error) {
+	return query(ctx, r.inst, "SessionRepository", "GetByAccessTokenHash", []any{"hash", hash}, func(ctx context.Context) (*models.Session, error) {
+		return r.next.GetByAccessTokenHash(ctx, hash)
+	})
+}
+
+func (r *sessionRepository) GetByRefreshTokenHash(ctx context.Context, hash string) (*models.Session, error) {
+	return query(ctx, r.inst, "SessionRepository", "GetByRefreshTokenHash", []any{"hash", hash}, func(ctx context.Context) (*models.Session, error) {
+		return r.next.GetByRefreshTokenHash(ctx, hash)
+	})
+}
+
+func (r *sessionRepository) Update(ctx context.Context, session *models.Session) error {
+	return exec(ctx, r.inst, "SessionRepository", "Update", []any{"session", session}, func(ctx context.Context) error {
+		return r.next.Update(ctx, session)
+	})
+}
+
+func (r *sessionRepository) ListActiveByUser(ctx context.Context, userID string, now time.Time) ([]*models.Session, error) {
+	return query(ctx, r.inst, "SessionRepository", "ListActiveByUser", []any{"userID", userID, "now", now}, func(ctx context.Context) ([]*models.Session, error) {
+		return r.next.ListActiveByUser(ctx, userID, now)
+	})
+}
+
+func (r *sessionRepository) CountActiveByAppVersion(ctx context.Context, now time.Time) ([]*models.AppVersionSessions, error) {
+	return query(ctx, r.inst, "SessionRepository", "CountActiveByAppVersion", []any{"now", now}, func(ctx context.Context) ([]*models.AppVersionSessions, error) {
+		return r.next.CountActiveByAppVersion(ctx, now)
+	})
+}
@@ -0,0 +1,27 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// storageCostRepository traces a repository.StorageCostRepository
+type storageCostRepository struct {
+	next repository.StorageCostRepository
+	inst *Instrumentation
+}
+
+func (r *storageCostRepository) TableUsage(ctx context.Context, table string, start, end time.Time, bucket time.Duration) ([]*models.StorageUsage, error) {
+	return query(ctx, r.inst, "StorageCostRepository", "TableUsage", []any{"table", table, "start", start, "end", end, "bucket", bucket}, func(ctx context.Context) ([]*models.StorageUsage, error) {
+		return r.next.TableUsage(ctx, table, start, end, bucket)
+	})
+}
+
+func (r *storageCostRepository) RideRequests(ctx context.Context, eventType string, start, end time.Time, bucket time.Duration) ([]*models.StorageRequests, error) {
+	return query(ctx, r.inst, "StorageCostRepository", "RideRequests", []any{"event_type", eventType, "start", start, "end", end, "bucket", bucket}, func(ctx context.Context) ([]*models.StorageRequests, error) {
+		return r.next.RideRequests(ctx, eventType, start, end, bucket)
+	})
+}
@@ -0,0 +1,249 @@
+// Package traced decorates the repositories with OpenTelemetry instrumentation: every call runs
+// in a span carrying the SQL operation, the rows returned or affected and the duration, and is
+// recorded in a per-repository latency histogram. Calls failing because the request's deadline
+// passed are reported as the repository layer running out of its time budget.
+package traced
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/deadline"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository/factory"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// SlowQueryRecorder receives the repository calls that took at least its threshold
+type SlowQueryRecorder interface {
+	Threshold() time.Duration
+	Record(ctx context.Context, query *models.SlowQuery)
+}
+
+// Instrumentation holds the tracer and latency histogram the decorated repositories report to
+type Instrumentation struct {
+	tracer    trace.Tracer
+	duration  metric.Float64Histogram
+	system    string
+	slowQuery SlowQueryRecorder
+}
+
+// New creates the instrumentation for repositories backed by the given database driver.
+// A nil tracer or meter disables spans or the histogram respectively.
+func New(tracer trace.Tracer, meter metric.Meter, system string) (*Instrumentation, error) {
+	if tracer == nil {
+		tracer = tracenoop.NewTracerProvider().Tracer("")
+	}
+	if meter == nil {
+		meter = metricnoop.NewMeterProvider().Meter("")
+	}
+
+	duration, err := meter.Float64Histogram(
+		"repository_operation_duration_seconds",
+		metric.WithDescription("Duration of repository calls"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Instrumentation{tracer: tracer, duration: duration, system: system}, nil
+}
+
+// SetSlowQueryRecorder reports the calls at least as slow as the recorder's threshold to it
+func (i *Instrumentation) SetSlowQueryRecorder(recorder SlowQueryRecorder) {
+	i.slowQuery = recorder
+}
+
+// Wrap returns a copy of repos with every repository decorated
+func Wrap(repos *factory.Repositories, inst *Instrumentation) *factory.Repositories {
+	return &factory.Repositories{
+		Users:          &userRepository{next: repos.Users, inst: inst},
+		Drivers:        &driverRepository{next: repos.Drivers, inst: inst},
+		Passengers:     &passengerRepository{next: repos.Passengers, inst: inst},
+		Trips:          &tripRepository{next: repos.Trips, inst: inst},
+		SavedLocations: &savedLocationRepository{next: repos.SavedLocations, inst: inst},
+		Webhooks:       &webhookRepository{next: repos.Webhooks, inst: inst},
+		Sessions:       &sessionRepository{next: repos.Sessions, inst: inst},
+		FareDisputes:   &fareDisputeRepository{next: repos.FareDisputes, inst: inst},
+		Incentives:     &incentiveRepository{next: repos.Incentives, inst: inst},
+		Fleets:         &fleetRepository{next: repos.Fleets, inst: inst},
+		Corporate:      &corporateAccountRepository{next: repos.Corporate, inst: inst},
+		Invoices:       &invoiceRepository{next: repos.Invoices, inst: inst},
+		ETAPredictions: &etaPredictionRepository{next: repos.ETAPredictions, inst: inst},
+		LocationTrail:  &driverLocationRepository{next: repos.LocationTrail, inst: inst},
+		Chat:           &chatRepository{next: repos.Chat, inst: inst},
+		Incidents:      &safetyIncidentRepository{next: repos.Incidents, inst: inst},
+		FraudSignals:   &fraudSignalRepository{next: repos.FraudSignals, inst: inst},
+		Payments:       &paymentRepository{next: repos.Payments, inst: inst},
+		Onboarding:     &driverOnboardingRepository{next: repos.Onboarding, inst: inst},
+		APIUsage:       &apiUsageRepository{next: repos.APIUsage, inst: inst},
+		Dashboard:      &dashboardRepository{next: repos.Dashboard, inst: inst},
+		Integrity:      &integrityRepository{next: repos.Integrity, inst: inst},
+		StorageCost:    &storageCostRepository{next: repos.StorageCost, inst: inst},
+		Observability:  &observabilityRepository{next: repos.Observability, inst: inst},
+		Traditional:    &traditionalRepository{next: repos.Traditional, inst: inst},
+	}
+}
+
+// call is an in-flight repository call
+type call struct {
+	inst       *Instrumentation
+	ctx        context.Context
+	span       trace.Span
+	repository string
+	method     string
+	operation  string
+	params     []any
+	start      time.Time
+}
+
+// begin starts the span of a call to repository.method with the given name, value pairs of
+// arguments
+func (i *Instrumentation) begin(ctx context.Context, repository, method string, params []any) (context.Context, *call) {
+	operation := Operation(method)
+	ctx, span := i.tracer.Start(ctx, repository+"."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", i.system),
+			attribute.String("db.operation", operation),
+			attribute.String("db.repository", repository),
+			attribute.String("code.function", method),
+		),
+	)
+	return ctx, &call{
+		inst:       i,
+		ctx:        ctx,
+		span:       span,
+		repository: repository,
+		method:     method,
+		operation:  operation,
+		params:     params,
+		start:      time.Now(),
+	}
+}
+
+// end records the outcome of the call; rows is negative when the call doesn't report a count
+func (c *call) end(rows int64, err error) {
+	duration := time.Since(c.start)
+
+	status := "success"
+	if err != nil {
+		status = "error"
+		deadline.Exceeded(c.ctx, deadline.LayerRepository, err)
+		c.span.RecordError(err)
+		c.span.SetStatus(codes.Error, err.Error())
+	}
+	if rows >= 0 {
+		c.span.SetAttributes(attribute.Int64("db.rows_affected", rows))
+	}
+	c.span.SetAttributes(attribute.Float64("db.duration", duration.Seconds()))
+	c.span.End()
+
+	c.inst.duration.Record(c.ctx, duration.Seconds(), metric.WithAttributes(
+		attribute.String("repository", c.repository),
+		attribute.String("method", c.method),
+		attribute.String("operation", c.operation),
+		attribute.String("status", status),
+	))
+
+	if c.inst.slowQuery != nil && duration >= c.inst.slowQuery.Threshold() {
+		c.reportSlow(duration, err)
+	}
+}
+
+// reportSlow hands the call to the slow query recorder
+func (c *call) reportSlow(duration time.Duration, err error) {
+	query := &models.SlowQuery{
+		Repository: c.repository,
+		Method:     c.method,
+		Operation:  c.operation,
+		Params:     sanitizeParams(c.params),
+		DurationMs: float64(duration) / float64(time.Millisecond),
+		At:         c.start,
+	}
+	if err != nil {
+		query.Error = err.Error()
+	}
+	if sc := c.span.SpanContext(); sc.HasTraceID() {
+		query.TraceID = sc.TraceID().String()
+	}
+	c.inst.slowQuery.Record(c.ctx, query)
+}
+
+// exec instruments a call that only returns an error
+func exec(ctx context.Context, i *Instrumentation, repository, method string, params []any, fn func(context.Context) error) error {
+	ctx, c := i.begin(ctx, repository, method, params)
+	err := fn(ctx)
+	c.end(-1, err)
+	return err
+}
+
+// query instruments a call returning a result, counted as the rows it returned or affected
+func query[T any](ctx context.Context, i *Instrumentation, repository, method string, params []any, fn func(context.Context) (T, error)) (T, error) {
+	ctx, c := i.begin(ctx, repository, method, params)
+	result, err := fn(ctx)
+	c.end(rowCount(c.operation, result, err), err)
+	return result, err
+}
+
+// query2 instruments a call returning two results, counted by the first
+func query2[T, U any](ctx context.Context, i *Instrumentation, repository, method string, params []any, fn func(context.Context) (T, U, error)) (T, U, error) {
+	ctx, c := i.begin(ctx, repository, method, params)
+	first, second, err := fn(ctx)
+	c.end(rowCount(c.operation, first, err), err)
+	return first, second, err
+}
+
+// Operation maps a repository method to the SQL operation it performs, by its verb
+func Operation(method string) string {
+	for _, verb := range []struct {
+		prefixes  []string
+		operation string
+	}{
+		{[]string{"Get", "List", "Count", "Filter", "Search"}, "SELECT"},
+		{[]string{"Create", "Credit", "Charge", "Upsert"}, "INSERT"},
+		{[]string{"Update", "Set", "Mark", "Resolve", "Replace", "Complete", "Start", "End", "Reserve", "Release"}, "UPDATE"},
+		{[]string{"Delete", "Clear"}, "DELETE"},
+	} {
+		for _, prefix := range verb.prefixes {
+			if strings.HasPrefix(method, prefix) {
+				return verb.operation
+			}
+		}
+	}
+	return "CALL"
+}
+
+// rowCount counts the rows behind a result: the length of a slice or map, the value of a count
+// returned by a write, otherwise one for a present value; -1 when the call failed
+func rowCount(operation string, result any, err error) int64 {
+	if err != nil {
+		return -1
+	}
+
+	v := reflect.ValueOf(result)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return int64(v.Len())
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+	case reflect.Int, reflect.Int64:
+		if operation != "SELECT" {
+			return v.Int()
+		}
+	case reflect.Invalid:
+		return 0
+	}
+	return 1
+}
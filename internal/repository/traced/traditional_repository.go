@@ -0,0 +1,62 @@
+package traced
+
+import (
+	"context"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// traditionalRepository traces a repository.TraditionalRepository
+type traditionalRepository struct {
+	next repository.TraditionalRepository
+	inst *Instrumentation
+}
+
+func (r *traditionalRepository) CreateTraditionalMetric(ctx context.Context, metric *models.TraditionalMetric) error {
+	return exec(ctx, r.inst, "TraditionalRepository", "CreateTraditionalMetric", []any{"metric", metric}, func(ctx context.Context) error {
+		return r.next.CreateTraditionalMetric(ctx, metric)
+	})
+}
+
+func (r *traditionalRepository) GetTraditionalMetric(ctx context.Context, id string) (*models.TraditionalMetric, error) {
+	return query(ctx, r.inst, "TraditionalRepository", "GetTraditionalMetric", []any{"id", id}, func(ctx context.Context) (*models.TraditionalMetric, error) {
+		return r.next.GetTraditionalMetric(ctx, id)
+	})
+}
+
+func (r *traditionalRepository) ListTraditionalMetrics(ctx context.Context, name, metricType string, limit, offset int) ([]*models.TraditionalMetric, error) {
+	return query(ctx, r.inst, "TraditionalRepository", "ListTraditionalMetrics", []any{"name", name, "metricType", metricType, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.TraditionalMetric, error) {
+		return r.next.ListTraditionalMetrics(ctx, name, metricType, limit, offset)
+	})
+}
+
+func (r *traditionalRepository) GetTraditionalMetricsByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.TraditionalMetric, error) {
+	return query(ctx, r.inst, "TraditionalRepository", "GetTraditionalMetricsByTimeRange", []any{"startTime", startTime, "endTime", endTime, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.TraditionalMetric, error) {
+		return r.next.GetTraditionalMetricsByTimeRange(ctx, startTime, endTime, limit, offset)
+	})
+}
+
+func (r *traditionalRepository) CreateTraditionalLog(ctx context.Context, log *models.TraditionalLog) error {
+	return exec(ctx, r.inst, "TraditionalRepository", "CreateTraditionalLog", []any{"log", log}, func(ctx context.Context) error {
+		return r.next.CreateTraditionalLog(ctx, log)
+	})
+}
+
+func (r *traditionalRepository) GetTraditionalLog(ctx context.Context, id string) (*models.TraditionalLog, error) {
+	return query(ctx, r.inst, "TraditionalRepository", "GetTraditionalLog", []any{"id", id}, func(ctx context.Context) (*models.TraditionalLog, error) {
+		return r.next.GetTraditionalLog(ctx, id)
+	})
+}
+
+func (r *traditionalRepository) ListTraditionalLogs(ctx context.Context, level, source string, limit, offset int) ([]*models.TraditionalLog, error) {
+	return query(ctx, r.inst, "TraditionalRepository", "ListTraditionalLogs", []any{"level", level, "source", source, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.TraditionalLog, error) {
+		return r.next.ListTraditionalLogs(ctx, level, source, limit, offset)
+	})
+}
+
+func (r *traditionalRepository) GetTraditionalLogsByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.TraditionalLog, error) {
+	return query(ctx, r.inst, "TraditionalRepository", "GetTraditionalLogsByTimeRange", []any{"startTime", startTime, "endTime", endTime, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.TraditionalLog, error) {
+		return r.next.GetTraditionalLogsByTimeRange(ctx, startTime, endTime, limit, offset)
+	})
+}
@@ -0,0 +1,201 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// tripRepository traces a repository.TripRepository
+type tripRepository struct {
+	next repository.TripRepository
+	inst *Instrumentation
+}
+
+func (r *tripRepository) Create(ctx context.Context, trip *models.Trip) error {
+	return exec(ctx, r.inst, "TripRepository", "Create", []any{"trip", trip}, func(ctx context.Context) error {
+		return r.next.Create(ctx, trip)
+	})
+}
+
+func (r *tripRepository) GetByID(ctx context.Context, id string) (*models.Trip, error) {
+	return query(ctx, r.inst, "TripRepository", "GetByID", []any{"id", id}, func(ctx context.Context) (*models.Trip, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+func (r *tripRepository) Update(ctx context.Context, trip *models.Trip) error {
+	return exec(ctx, r.inst, "TripRepository", "Update", []any{"trip", trip}, func(ctx context.Context) error {
+		return r.next.Update(ctx, trip)
+	})
+}
+
+func (r *tripRepository) Delete(ctx context.Context, id string) error {
+	return exec(ctx, r.inst, "TripRepository", "Delete", []any{"id", id}, func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func (r *tripRepository) GetByPassengerID(ctx context.Context, passengerID string, limit, offset int) ([]*models.Trip, error) {
+	return query(ctx, r.inst, "TripRepository", "GetByPassengerID", []any{"passengerID", passengerID, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.Trip, error) {
+		return r.next.GetByPassengerID(ctx, passengerID, limit, offset)
+	})
+}
+
+func (r *tripRepository) GetByDriverID(ctx context.Context, driverID string, limit, offset int) ([]*models.Trip, error) {
+	return query(ctx, r.inst, "TripRepository", "GetByDriverID", []any{"driverID", driverID, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.Trip, error) {
+		return r.next.GetByDriverID(ctx, driverID, limit, offset)
+	})
+}
+
+func (r *tripRepository) GetActiveTrips(ctx context.Context) ([]*models.Trip, error) {
+	return query(ctx, r.inst, "TripRepository", "GetActiveTrips", nil, func(ctx context.Context) ([]*models.Trip, error) {
+		return r.next.GetActiveTrips(ctx)
+	})
+}
+
+func (r *tripRepository) GetTripsByStatus(ctx context.Context, status models.TripStatus, limit, offset int) ([]*models.Trip, error) {
+	return query(ctx, r.inst, "TripRepository", "GetTripsByStatus", []any{"status", status, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.Trip, error) {
+		return r.next.GetTripsByStatus(ctx, status, limit, offset)
+	})
+}
+
+func (r *tripRepository) CountByStatus(ctx context.Context, status models.TripStatus) (int64, error) {
+	return query(ctx, r.inst, "TripRepository", "CountByStatus", []any{"status", status}, func(ctx context.Context) (int64, error) {
+		return r.next.CountByStatus(ctx, status)
+	})
+}
+
+func (r *tripRepository) GetTripsByDateRange(ctx context.Context, startDate, endDate string, limit, offset int) ([]*models.Trip, error) {
+	return query(ctx, r.inst, "TripRepository", "GetTripsByDateRange", []any{"startDate", startDate, "endDate", endDate, "limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.Trip, error) {
+		return r.next.GetTripsByDateRange(ctx, startDate, endDate, limit, offset)
+	})
+}
+
+func (r *tripRepository) List(ctx context.Context, limit, offset int) ([]*models.Trip, error) {
+	return query(ctx, r.inst, "TripRepository", "List", []any{"limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.Trip, error) {
+		return r.next.List(ctx, limit, offset)
+	})
+}
+
+func (r *tripRepository) GetRecentDestinations(ctx context.Context, passengerID string, limit int) ([]*models.RecentDestination, error) {
+	return query(ctx, r.inst, "TripRepository", "GetRecentDestinations", []any{"passengerID", passengerID, "limit", limit}, func(ctx context.Context) ([]*models.RecentDestination, error) {
+		return r.next.GetRecentDestinations(ctx, passengerID, limit)
+	})
+}
+
+func (r *tripRepository) GetRequestTimes(ctx context.Context, start, end time.Time, bounds *models.GeoBounds) ([]time.Time, error) {
+	return query(ctx, r.inst, "TripRepository", "GetRequestTimes", []any{"start", start, "end", end, "bounds", bounds}, func(ctx context.Context) ([]time.Time, error) {
+		return r.next.GetRequestTimes(ctx, start, end, bounds)
+	})
+}
+
+func (r *tripRepository) GetGridCounts(ctx context.Context, start, end time.Time, cellHeight, cellWidth float64) ([]*models.TripGridCount, error) {
+	return query(ctx, r.inst, "TripRepository", "GetGridCounts", []any{"start", start, "end", end, "cellHeight", cellHeight, "cellWidth", cellWidth}, func(ctx context.Context) ([]*models.TripGridCount, error) {
+		return r.next.GetGridCounts(ctx, start, end, cellHeight, cellWidth)
+	})
+}
+
+func (r *tripRepository) ListByFleet(ctx context.Context, fleetID string, start, end time.Time) ([]*models.Trip, error) {
+	return query(ctx, r.inst, "TripRepository", "ListByFleet", []any{"fleetID", fleetID, "start", start, "end", end}, func(ctx context.Context) ([]*models.Trip, error) {
+		return r.next.ListByFleet(ctx, fleetID, start, end)
+	})
+}
+
+func (r *tripRepository) ListRequested(ctx context.Context, start, end time.Time) ([]*models.Trip, error) {
+	return query(ctx, r.inst, "TripRepository", "ListRequested", []any{"start", start, "end", end}, func(ctx context.Context) ([]*models.Trip, error) {
+		return r.next.ListRequested(ctx, start, end)
+	})
+}
+
+func (r *tripRepository) GetDailyCounts(ctx context.Context, boundaries []time.Time) ([]*models.DailyTripCount, error) {
+	return query(ctx, r.inst, "TripRepository", "GetDailyCounts", []any{"boundaries", boundaries}, func(ctx context.Context) ([]*models.DailyTripCount, error) {
+		return r.next.GetDailyCounts(ctx, boundaries)
+	})
+}
+
+func (r *tripRepository) ListCancelled(ctx context.Context, start, end time.Time) ([]*models.Trip, error) {
+	return query(ctx, r.inst, "TripRepository", "ListCancelled", []any{"start", start, "end", end}, func(ctx context.Context) ([]*models.Trip, error) {
+		return r.next.ListCancelled(ctx, start, end)
+	})
+}
+
+func (r *tripRepository) GetDailyCancellations(ctx context.Context, boundaries []time.Time) ([]*models.DailyCancellationCount, error) {
+	return query(ctx, r.inst, "TripRepository", "GetDailyCancellations", []any{"boundaries", boundaries}, func(ctx context.Context) ([]*models.DailyCancellationCount, error) {
+		return r.next.GetDailyCancellations(ctx, boundaries)
+	})
+}
+
+func (r *tripRepository) Complete(ctx context.Context, trip *models.Trip, from models.TripStatus, completion *models.TripCompletion) error {
+	return exec(ctx, r.inst, "TripRepository", "Complete", []any{"trip", trip, "from", from, "completion", completion}, func(ctx context.Context) error {
+		return r.next.Complete(ctx, trip, from, completion)
+	})
+}
+
+func (r *tripRepository) GetCompletion(ctx context.Context, tripID string) (*models.TripCompletion, error) {
+	return query(ctx, r.inst, "TripRepository", "GetCompletion", []any{"tripID", tripID}, func(ctx context.Context) (*models.TripCompletion, error) {
+		return r.next.GetCompletion(ctx, tripID)
+	})
+}
+
+func (r *tripRepository) ListCompletions(ctx context.Context, filter models.TripCompletionFilter) ([]*models.TripCompletion, int64, error) {
+	return query2(ctx, r.inst, "TripRepository", "ListCompletions", []any{"filter", filter}, func(ctx context.Context) ([]*models.TripCompletion, int64, error) {
+		return r.next.ListCompletions(ctx, filter)
+	})
+}
+
+func (r *tripRepository) StartPickupWait(ctx context.Context, trip *models.Trip, from models.TripStatus, wait *models.PickupWait) error {
+	return exec(ctx, r.inst, "TripRepository", "StartPickupWait", []any{"trip", trip, "from", from, "wait", wait}, func(ctx context.Context) error {
+		return r.next.StartPickupWait(ctx, trip, from, wait)
+	})
+}
+
+func (r *tripRepository) EndPickupWait(ctx context.Context, trip *models.Trip, from models.TripStatus, wait *models.PickupWait) error {
+	return exec(ctx, r.inst, "TripRepository", "EndPickupWait", []any{"trip", trip, "from", from, "wait", wait}, func(ctx context.Context) error {
+		return r.next.EndPickupWait(ctx, trip, from, wait)
+	})
+}
+
+func (r *tripRepository) GetPickupWait(ctx context.Context, tripID string) (*models.PickupWait, error) {
+	return query(ctx, r.inst, "TripRepository", "GetPickupWait", []any{"tripID", tripID}, func(ctx context.Context) (*models.PickupWait, error) {
+		return r.next.GetPickupWait(ctx, tripID)
+	})
+}
+
+func (r *tripRepository) GetPickupWaitStats(ctx context.Context, from, to time.Time) ([]*models.PickupWaitZoneStats, error) {
+	return query(ctx, r.inst, "TripRepository", "GetPickupWaitStats", []any{"from", from, "to", to}, func(ctx context.Context) ([]*models.PickupWaitZoneStats, error) {
+		return r.next.GetPickupWaitStats(ctx, from, to)
+	})
+}
+
+func (r *tripRepository) RecordDriverCancellation(ctx context.Context, trip *models.Trip, from models.TripStatus, rematch *models.TripRematch) error {
+	return exec(ctx, r.inst, "TripRepository", "RecordDriverCancellation", []any{"trip", trip, "from", from, "rematch", rematch}, func(ctx context.Context) error {
+		return r.next.RecordDriverCancellation(ctx, trip, from, rematch)
+	})
+}
+
+func (r *tripRepository) EndRematch(ctx context.Context, rematch *models.TripRematch) error {
+	return exec(ctx, r.inst, "TripRepository", "EndRematch", []any{"rematch", rematch}, func(ctx context.Context) error {
+		return r.next.EndRematch(ctx, rematch)
+	})
+}
+
+func (r *tripRepository) ListRematches(ctx context.Context, tripID string) ([]*models.TripRematch, error) {
+	return query(ctx, r.inst, "TripRepository", "ListRematches", []any{"tripID", tripID}, func(ctx context.Context) ([]*models.TripRematch, error) {
+		return r.next.ListRematches(ctx, tripID)
+	})
+}
+
+func (r *tripRepository) Search(ctx context.Context, filter models.TripSearchFilter) ([]*models.TripSearchResult, int64, error) {
+	return query2(ctx, r.inst, "TripRepository", "Search", []any{"filter", filter}, func(ctx context.Context) ([]*models.TripSearchResult, int64, error) {
+		return r.next.Search(ctx, filter)
+	})
+}
+
+func (r *tripRepository) ListStuck(ctx context.Context, updatedBefore time.Time, limit int) ([]*models.Trip, error) {
+	return query(ctx, r.inst, "TripRepository", "ListStuck", []any{"updatedBefore", updatedBefore, "limit", limit}, func(ctx context.Context) ([]*models.Trip, error) {
+		return r.next.ListStuck(ctx, updatedBefore, limit)
+	})
+}
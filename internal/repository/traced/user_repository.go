@@ -0,0 +1,56 @@
+package traced
+
+import (
+	"context"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// userRepository traces a repository.UserRepository
+type userRepository struct {
+	next repository.UserRepository
+	inst *Instrumentation
+}
+
+func (r *userRepository) Create(ctx context.Context, user *models.User) error {
+	return exec(ctx, r.inst, "UserRepository", "Create", []any{"user", user}, func(ctx context.Context) error {
+		return r.next.Create(ctx, user)
+	})
+}
+
+func (r *userRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	return query(ctx, r.inst, "UserRepository", "GetByID", []any{"id", id}, func(ctx context.Context) (*models.User, error) {
+		return r.next.GetByID(ctx, id)
+	})
+}
+
+func (r *userRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return query(ctx, r.inst, "UserRepository", "GetByEmail", []any{"email", email}, func(ctx context.Context) (*models.User, error) {
+		return r.next.GetByEmail(ctx, email)
+	})
+}
+
+func (r *userRepository) GetByPhone(ctx context.Context, phone string) (*models.User, error) {
+	return query(ctx, r.inst, "UserRepository", "GetByPhone", []any{"phone", phone}, func(ctx context.Context) (*models.User, error) {
+		return r.next.GetByPhone(ctx, phone)
+	})
+}
+
+func (r *userRepository) Update(ctx context.Context, user *models.User) error {
+	return exec(ctx, r.inst, "UserRepository", "Update", []any{"user", user}, func(ctx context.Context) error {
+		return r.next.Update(ctx, user)
+	})
+}
+
+func (r *userRepository) Delete(ctx context.Context, id string) error {
+	return exec(ctx, r.inst, "UserRepository", "Delete", []any{"id", id}, func(ctx context.Context) error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func (r *userRepository) List(ctx context.Context, limit, offset int) ([]*models.User, error) {
+	return query(ctx, r.inst, "UserRepository", "List", []any{"limit", limit, "offset", offset}, func(ctx context.Context) ([]*models.User, error) {
+		return r.next.List(ctx, limit, offset)
+	})
+}
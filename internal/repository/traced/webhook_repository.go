@@ -0,0 +1,69 @@
+package traced
+
+import (
+	"context"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// webhookRepository traces a repository.WebhookRepository
+type webhookRepository struct {
+	next repository.WebhookRepository
+	inst *Instrumentation
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	return exec(ctx, r.inst, "WebhookRepository", "CreateSubscription", []any{"subscription", subscription}, func(ctx context.Context) error {
+		return r.next.CreateSubscription(ctx, subscription)
+	})
+}
+
+func (r *webhookRepository) GetSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	return query(ctx, r.inst, "WebhookRepository", "GetSubscription", []any{"id", id}, func(ctx context.Context) (*models.WebhookSubscription, error) {
+		return r.next.GetSubscription(ctx, id)
+	})
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	return exec(ctx, r.inst, "WebhookRepository", "DeleteSubscription", []any{"id", id}, func(ctx context.Context) error {
+		return r.next.DeleteSubscription(ctx, id)
+	})
+}
+
+func (r *webhookRepository) ListSubscriptionsByOwner(ctx context.Context, owner string) ([]*models.WebhookSubscription, error) {
+	return query(ctx, r.inst, "WebhookRepository", "ListSubscriptionsByOwner", []any{"owner", owner}, func(ctx context.Context) ([]*models.WebhookSubscription, error) {
+		return r.next.ListSubscriptionsByOwner(ctx, owner)
+	})
+}
+
+func (r *webhookRepository) ListSubscriptionsForEvent(ctx context.Context, event models.WebhookEvent) ([]*models.WebhookSubscription, error) {
+	return query(ctx, r.inst, "WebhookRepository", "ListSubscriptionsForEvent", []any{"event", event}, func(ctx context.Context) ([]*models.WebhookSubscription, error) {
+		return r.next.ListSubscriptionsForEvent(ctx, event)
+	})
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return exec(ctx, r.inst, "WebhookRepository", "CreateDelivery", []any{"delivery", delivery}, func(ctx context.Context) error {
+		return r.next.CreateDelivery(ctx, delivery)
+	})
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return exec(ctx, r.inst, "WebhookRepository", "UpdateDelivery", []any{"delivery", delivery}, func(ctx context.Context) error {
+		return r.next.UpdateDelivery(ctx, delivery)
+	})
+}
+
+func (r *webhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	return query(ctx, r.inst, "WebhookRepository", "GetDueDeliveries", []any{"now", now, "limit", limit}, func(ctx context.Context) ([]*models.WebhookDelivery, error) {
+		return r.next.GetDueDeliveries(ctx, now, limit)
+	})
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int64, error) {
+	return query2(ctx, r.inst, "WebhookRepository", "ListDeliveries", []any{"filter", filter}, func(ctx context.Context) ([]*models.WebhookDelivery, int64, error) {
+		return r.next.ListDeliveries(ctx, filter)
+	})
+}
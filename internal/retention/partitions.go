@@ -0,0 +1,227 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+
+	// partitionDateLayout is the suffix format of partition names, e.g. actor_messages_p20240131
+	partitionDateLayout = "20060102"
+)
+
+// PartitionSpec describes a table partitioned by time range
+type PartitionSpec struct {
+	Table     string
+	Interval  time.Duration // day or week
+	Retention time.Duration // overrides the manager's retention period when set
+}
+
+// ObservabilityPartitions lists the time-partitioned observability tables
+var ObservabilityPartitions = []PartitionSpec{
+	{Table: "actor_messages", Interval: day},
+	{Table: "event_logs", Interval: day},
+	{Table: "system_metrics", Interval: week},
+}
+
+// Expired reports whether a partition of the table lies entirely before now minus its retention
+// period, which is the spec's own or else retention. Names that are not partitions of the table,
+// such as its default partition, never expire.
+func (s PartitionSpec) Expired(partition string, retention time.Duration, now time.Time) bool {
+	from, ok := PartitionStart(s.Table, partition)
+	if !ok {
+		return false
+	}
+	if s.Retention > 0 {
+		retention = s.Retention
+	}
+	return !from.Add(s.Interval).After(now.Add(-retention))
+}
+
+// PartitionManager creates upcoming partitions ahead of time and drops partitions
+// that fall entirely outside the retention period
+type PartitionManager struct {
+	db        *sqlx.DB
+	specs     []PartitionSpec
+	retention time.Duration
+	premake   int
+	interval  time.Duration
+	logger    *logging.Logger
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewPartitionManager creates a new partition manager for the observability tables and the driver
+// location trail. Each table is kept for its own retention period when one is configured and for
+// the metrics retention period otherwise.
+func NewPartitionManager(db *sqlx.DB, cfg *config.Config, logger *logging.Logger) *PartitionManager {
+	retention := map[string]time.Duration{
+		"actor_messages": cfg.Retention.ActorMessagesPeriod,
+		"event_logs":     cfg.Retention.EventLogsPeriod,
+		"system_metrics": cfg.Retention.SystemMetricsPeriod,
+	}
+	var specs []PartitionSpec
+	for _, spec := range ObservabilityPartitions {
+		spec.Retention = retention[spec.Table]
+		specs = append(specs, spec)
+	}
+	specs = append(specs, PartitionSpec{
+		Table:     "driver_location_pings",
+		Interval:  day,
+		Retention: cfg.LocationTrail.RetentionPeriod,
+	})
+
+	return &PartitionManager{
+		db:        db,
+		specs:     specs,
+		retention: cfg.Metrics.RetentionPeriod,
+		premake:   cfg.Retention.PartitionPremakeDays,
+		interval:  cfg.Retention.MaintenanceInterval,
+		logger:    logger.WithComponent("partition_manager"),
+	}
+}
+
+// Start runs maintenance immediately and then on every maintenance interval
+func (pm *PartitionManager) Start(ctx context.Context) error {
+	pm.ctx, pm.cancel = context.WithCancel(ctx)
+
+	if err := pm.RunMaintenance(pm.ctx); err != nil {
+		pm.logger.WithError(err).Error("Initial partition maintenance failed")
+	}
+
+	pm.wg.Add(1)
+	go pm.maintenanceLoop()
+
+	pm.logger.Info("Partition manager started")
+	return nil
+}
+
+// Stop stops the maintenance loop
+func (pm *PartitionManager) Stop() error {
+	if pm.cancel != nil {
+		pm.cancel()
+	}
+	pm.wg.Wait()
+
+	pm.logger.Info("Partition manager stopped")
+	return nil
+}
+
+// maintenanceLoop periodically runs partition maintenance
+func (pm *PartitionManager) maintenanceLoop() {
+	defer pm.wg.Done()
+
+	ticker := time.NewTicker(pm.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := pm.RunMaintenance(pm.ctx); err != nil {
+				pm.logger.WithError(err).Error("Partition maintenance failed")
+			}
+		case <-pm.ctx.Done():
+			return
+		}
+	}
+}
+
+// RunMaintenance creates missing upcoming partitions and drops expired ones for every table
+func (pm *PartitionManager) RunMaintenance(ctx context.Context) error {
+	now := time.Now().UTC()
+
+	for _, spec := range pm.specs {
+		if err := pm.ensurePartitions(ctx, spec, now); err != nil {
+			return err
+		}
+		if err := pm.dropExpiredPartitions(ctx, spec, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ensurePartitions creates partitions from the current period up to the premake horizon
+func (pm *PartitionManager) ensurePartitions(ctx context.Context, spec PartitionSpec, now time.Time) error {
+	start := PeriodStart(now, spec.Interval)
+	horizon := now.Add(time.Duration(pm.premake) * day)
+
+	for from := start; !from.After(horizon); from = from.Add(spec.Interval) {
+		to := from.Add(spec.Interval)
+		if _, err := pm.db.ExecContext(ctx, `SELECT create_time_partition($1, $2, $3)`, spec.Table, from, to); err != nil {
+			return fmt.Errorf("failed to create partition of %s for %s: %w", spec.Table, from.Format(partitionDateLayout), err)
+		}
+	}
+
+	return nil
+}
+
+// dropExpiredPartitions drops partitions whose whole range is older than the retention period
+func (pm *PartitionManager) dropExpiredPartitions(ctx context.Context, spec PartitionSpec, now time.Time) error {
+	query := `
+		SELECT child.relname
+		FROM pg_inherits
+		JOIN pg_class parent ON parent.oid = pg_inherits.inhparent
+		JOIN pg_class child ON child.oid = pg_inherits.inhrelid
+		WHERE parent.relname = $1
+	`
+
+	var partitions []string
+	if err := pm.db.SelectContext(ctx, &partitions, query, spec.Table); err != nil {
+		return fmt.Errorf("failed to list partitions of %s: %w", spec.Table, err)
+	}
+
+	for _, partition := range partitions {
+		if !spec.Expired(partition, pm.retention, now) {
+			continue
+		}
+
+		if _, err := pm.db.ExecContext(ctx, "DROP TABLE IF EXISTS "+pq.QuoteIdentifier(partition)); err != nil {
+			return fmt.Errorf("failed to drop partition %s: %w", partition, err)
+		}
+
+		pm.logger.WithFields(logging.Fields{
+			"table":     spec.Table,
+			"partition": partition,
+		}).Info("Dropped expired partition")
+	}
+
+	return nil
+}
+
+// PeriodStart truncates t to the start of its day or ISO week (Monday), matching Postgres date_trunc
+func PeriodStart(t time.Time, interval time.Duration) time.Time {
+	start := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == week {
+		offset := (int(start.Weekday()) + 6) % 7
+		start = start.AddDate(0, 0, -offset)
+	}
+	return start
+}
+
+// PartitionStart parses the lower bound encoded in the name of a partition of table
+func PartitionStart(table, partition string) (time.Time, bool) {
+	suffix, found := strings.CutPrefix(partition, table+"_p")
+	if !found {
+		return time.Time{}, false
+	}
+	from, err := time.Parse(partitionDateLayout, suffix)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return from, true
+}
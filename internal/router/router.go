@@ -3,15 +3,25 @@ package router
 import (
 	"context"
 	"net/http"
+	"strings"
 	"time"
 
 	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/appversion"
+	"actor-model-observability/internal/bus"
 	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/database"
+	"actor-model-observability/internal/experiment"
 	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/lock"
 	"actor-model-observability/internal/logging"
 	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/internal/redaction"
 	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/retention"
 	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/storage"
 	"actor-model-observability/internal/traditional"
 
 	_ "actor-model-observability/docs" // Import generated docs
@@ -24,17 +34,68 @@ import (
 
 // RouterConfig holds dependencies for router setup
 type RouterConfig struct {
-	Config             *config.Config
-	Logger             *logging.Logger
-	UserRepo           repository.UserRepository
-	DriverRepo         repository.DriverRepository
-	PassengerRepo      repository.PassengerRepository
-	TripRepo           repository.TripRepository
-	ObservabilityRepo  repository.ObservabilityRepository
-	TraditionalRepo    repository.TraditionalRepository
-	ActorSystem        *actor.ActorSystem
-	TraditionalMonitor *traditional.TraditionalMonitor
-	RideService        *service.RideService
+	Config               *config.Config
+	Logger               *logging.Logger
+	UserRepo             repository.UserRepository
+	DriverRepo           repository.DriverRepository
+	PassengerRepo        repository.PassengerRepository
+	TripRepo             repository.TripRepository
+	SavedLocationRepo    repository.SavedLocationRepository
+	WebhookRepo          repository.WebhookRepository
+	ObservabilityRepo    repository.ObservabilityRepository
+	TraditionalRepo      repository.TraditionalRepository
+	ActorSystem          *actor.ActorSystem
+	TraditionalMonitor   *traditional.TraditionalMonitor
+	SLOTracker           *observability.SLOTracker
+	EventStream          *observability.EventStream
+	SlowQueryLog         *observability.SlowQueryLog
+	MetricsCollector     *observability.MetricsCollector
+	EventBus             bus.Publisher
+	TripStatusStream     *service.TripStatusStream
+	Redactor             *redaction.Redactor
+	RideService          *service.RideService
+	SessionService       *service.SessionService
+	FareDisputeService   *service.FareDisputeService
+	IncentiveService     *service.IncentiveService
+	ForecastService      *service.ForecastService
+	ReportService        *service.ReportService
+	CancellationService  *service.CancellationService
+	HeatmapService       *service.HeatmapService
+	ScalingSignalService *service.ScalingSignalService
+	StorageCostService   *service.StorageCostService
+	ResearchService      *service.ResearchExportService
+	DashboardService     *service.DashboardService
+	TimelineService      *service.TimelineService
+	FleetService         *service.FleetService
+	CorporateService     *service.CorporateAccountService
+	ChatService          *service.ChatService
+	IncidentService      *service.SafetyIncidentService
+	CompletionService    *service.TripCompletionService
+	PickupWaitService    *service.PickupWaitService
+	ETAAccuracyService   *service.ETAAccuracyService
+	LocationTrailService *service.LocationTrailService
+	FraudService         *service.FraudService
+	PaymentService       *service.PaymentReconciliationService
+	IntegrityService     *service.IntegrityService // nil unless integrity checks are enabled
+	APIUsageService      *service.APIUsageService
+	FatigueService       *service.DriverFatigueService
+	OnboardingService    *service.DriverOnboardingService
+	EmailNotifier        *service.EmailNotifier
+	TraditionalMatcher   *service.TraditionalMatcher
+	LocationIngester     *service.LocationIngester
+	ConfigReloader       *service.ConfigReloader
+	Locker               *lock.Locker
+	SchemaChecker        *database.SchemaDriftChecker
+	ChangeListener       *database.ChangeListener
+	PartitionManager     *retention.PartitionManager // nil unless the database is PostgreSQL
+	Experiments          *experiment.Manager
+	FileStore            storage.Store
+	AvatarService        *service.AvatarService
+	InvoiceService       *service.InvoiceService
+	RateLimiter          *middleware.RateLimiter
+	BodyLogger           *middleware.BodyLogger
+	TrafficRecorder      *middleware.TrafficRecorder // nil unless traffic recording is enabled
+	AppVersionGate       *appversion.Gate
 }
 
 // SetupRouter configures and returns the Gin router with all routes and middleware
@@ -56,18 +117,8 @@ func SetupRouter(cfg *RouterConfig) *gin.Engine {
 
 // setupMiddleware configures all middleware
 func setupMiddleware(router *gin.Engine, cfg *RouterConfig) {
-	// Recovery middleware
-	router.Use(gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
-		cfg.Logger.LogPanic(recovered, "http", "request_handling", logging.Fields{
-			"method": c.Request.Method,
-			"path":   c.Request.URL.Path,
-			"ip":     c.ClientIP(),
-		})
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"error":   "Internal server error",
-			"message": "An unexpected error occurred",
-		})
-	}))
+	// Recovery middleware; panics become crash events and a 500 problem response
+	router.Use(middleware.RecoveryMiddleware(cfg.Logger, cfg.TraditionalMonitor, cfg.EventBus))
 
 	// Request ID middleware
 	router.Use(func(c *gin.Context) {
@@ -83,16 +134,65 @@ func setupMiddleware(router *gin.Engine, cfg *RouterConfig) {
 	// Logging middleware
 	router.Use(middleware.LoggingMiddleware(cfg.Logger, cfg.Config.Logging.SkipPaths, cfg.Config.Logging.SkipUserAgents))
 
+	// Operator role middleware; operators see unredacted observability data
+	router.Use(middleware.OperatorRoleMiddleware(cfg.Config.Redaction.OperatorKeys))
+
 	// CORS middleware
 	router.Use(middleware.CORSMiddleware())
 
+	// Response compression middleware
+	if cfg.Config.Compression.Enabled {
+		compression := cfg.Config.Compression
+		router.Use(middleware.CompressionMiddleware(compression.Level, compression.MinSize, compression.ExcludedPaths))
+	}
+
+	// Sampled request/response body logging; after compression so it sees uncompressed responses
+	if cfg.BodyLogger != nil {
+		router.Use(cfg.BodyLogger.Middleware())
+	}
+
+	// Replayable traffic recording; after compression so it records the uncompressed status
+	if cfg.TrafficRecorder != nil {
+		router.Use(cfg.TrafficRecorder.Middleware())
+	}
+
+	// Error codes for error responses without one; before rate limiting so 429s get theirs
+	router.Use(middleware.ErrorCodeMiddleware())
+
 	// Rate limiting middleware (if enabled)
 	if cfg.Config.Server.Mode == "release" {
-		router.Use(middleware.RateLimitMiddleware())
+		if cfg.RateLimiter != nil {
+			router.Use(cfg.RateLimiter.Middleware())
+		} else {
+			router.Use(middleware.RateLimitMiddleware())
+		}
 	}
 
 	// Metrics middleware
 	router.Use(middleware.MetricsMiddleware(cfg.TraditionalMonitor))
+
+	// App version gating; after the metrics middleware so it records the 426s
+	if cfg.AppVersionGate != nil {
+		router.Use(middleware.AppVersionMiddleware(cfg.AppVersionGate))
+	}
+
+	// Request time budgets; after the metrics middleware so it records the 504s
+	router.Use(middleware.TimeoutMiddleware(cfg.Config.Timeout, cfg.TraditionalMonitor, streamRoutes...))
+}
+
+// streamRoutes are the long-lived WebSocket and event stream routes, which have no time budget
+var streamRoutes = []string{
+	"/api/v1/rides/:id/status/stream",
+	"/api/v1/rides/:id/chat/ws",
+	"/api/v1/observability/events/stream",
+}
+
+// httpCache returns the ETag middleware for a route group, or nothing when HTTP caching is disabled
+func httpCache(cfg *RouterConfig, cacheControl string) []gin.HandlerFunc {
+	if !cfg.Config.HTTPCache.Enabled {
+		return nil
+	}
+	return []gin.HandlerFunc{middleware.ETagMiddleware(cacheControl)}
 }
 
 // setupRoutes configures all API routes
@@ -103,15 +203,103 @@ func setupRoutes(router *gin.Engine, cfg *RouterConfig) {
 		cfg.DriverRepo,
 		cfg.PassengerRepo,
 	)
+	if cfg.EventBus != nil {
+		userHandler.SetEventPublisher(cfg.EventBus)
+	}
+	if cfg.FatigueService != nil {
+		userHandler.SetFatigueService(cfg.FatigueService)
+	}
+	if cfg.AvatarService != nil {
+		userHandler.SetAvatarService(cfg.AvatarService)
+	}
+	if cfg.OnboardingService != nil {
+		userHandler.SetOnboardingService(cfg.OnboardingService)
+	}
+	if cfg.LocationIngester != nil {
+		userHandler.SetLocationIngester(cfg.LocationIngester)
+	}
 
 	rideHandler := handlers.NewRideHandler(
 		cfg.RideService,
+		cfg.SavedLocationRepo,
+	)
+	rideHandler.SetChatService(cfg.ChatService)
+	rideHandler.SetStatusStream(cfg.TripStatusStream)
+
+	passengerHandler := handlers.NewPassengerHandler(
+		cfg.PassengerRepo,
+		cfg.SavedLocationRepo,
+		cfg.TripRepo,
 	)
 
 	observabilityHandler := handlers.NewObservabilityHandler(
 		cfg.ObservabilityRepo,
 		cfg.TraditionalRepo,
+		cfg.SLOTracker,
+		cfg.EventStream,
 	)
+	observabilityHandler.SetRedactor(cfg.Redactor)
+	observabilityHandler.SetSlowQueryLog(cfg.SlowQueryLog)
+	observabilityHandler.SetMetricsCollector(cfg.MetricsCollector)
+	observabilityHandler.SetMaxTimeRange(cfg.Config.Observability.MaxTimeRange)
+
+	webhookHandler := handlers.NewWebhookHandler(cfg.WebhookRepo)
+
+	fareDisputeHandler := handlers.NewFareDisputeHandler(cfg.FareDisputeService, cfg.ObservabilityRepo)
+
+	incentiveHandler := handlers.NewIncentiveHandler(cfg.IncentiveService)
+
+	forecastHandler := handlers.NewForecastHandler(cfg.ForecastService)
+	reportHandler := handlers.NewReportHandler(cfg.ReportService)
+	researchExportHandler := handlers.NewResearchExportHandler(cfg.ResearchService)
+
+	heatmapHandler := handlers.NewHeatmapHandler(cfg.HeatmapService)
+	scalingSignalHandler := handlers.NewScalingSignalHandler(cfg.ScalingSignalService)
+	storageCostHandler := handlers.NewStorageCostHandler(cfg.StorageCostService)
+	storageCostHandler.SetMaxTimeRange(cfg.Config.Observability.MaxTimeRange)
+
+	dashboardHandler := handlers.NewDashboardHandler(cfg.DashboardService)
+
+	timelineHandler := handlers.NewTimelineHandler(cfg.TimelineService)
+	fleetHandler := handlers.NewFleetHandler(cfg.FleetService)
+	apiUsageHandler := handlers.NewAPIUsageHandler(cfg.APIUsageService)
+	corporateHandler := handlers.NewCorporateAccountHandler(cfg.CorporateService)
+	invoiceHandler := handlers.NewInvoiceHandler(cfg.InvoiceService)
+	chatHandler := handlers.NewChatHandler(cfg.ChatService)
+	incidentHandler := handlers.NewSafetyIncidentHandler(cfg.IncidentService)
+	completionHandler := handlers.NewTripCompletionHandler(cfg.CompletionService)
+	tripSearchHandler := handlers.NewTripSearchHandler(cfg.TripRepo)
+	tripAdminHandler := handlers.NewTripAdminHandler(cfg.TripRepo, cfg.RideService)
+	retentionHandler := handlers.NewRetentionHandler(cfg.PartitionManager)
+	lockHandler := handlers.NewLockHandler(cfg.Locker)
+	schemaHandler := handlers.NewSchemaHandler(cfg.SchemaChecker)
+	changeListenerHandler := handlers.NewChangeListenerHandler(cfg.ChangeListener)
+	experimentHandler := handlers.NewExperimentHandler(cfg.Experiments)
+	fileHandler := handlers.NewFileHandler(cfg.FileStore)
+	pickupWaitHandler := handlers.NewPickupWaitHandler(cfg.PickupWaitService)
+	rematchHandler := handlers.NewTripRematchHandler(cfg.RideService)
+	etaHandler := handlers.NewETAHandler(cfg.ETAAccuracyService)
+	etaHandler.SetMaxTimeRange(cfg.Config.Observability.MaxTimeRange)
+	cancellationHandler := handlers.NewCancellationHandler(cfg.CancellationService)
+	cancellationHandler.SetMaxTimeRange(cfg.Config.Observability.MaxTimeRange)
+	onboardingHandler := handlers.NewDriverOnboardingHandler(cfg.OnboardingService)
+	onboardingHandler.SetMaxTimeRange(cfg.Config.Observability.MaxTimeRange)
+	emailHandler := handlers.NewEmailHandler(cfg.EmailNotifier)
+	matchingHandler := handlers.NewMatchingHandler(cfg.TraditionalMatcher)
+	locationIngestHandler := handlers.NewLocationIngestHandler(cfg.LocationIngester)
+	locationTrailHandler := handlers.NewLocationTrailHandler(cfg.LocationTrailService)
+	fraudHandler := handlers.NewFraudSignalHandler(cfg.FraudService)
+	paymentHandler := handlers.NewPaymentReconciliationHandler(cfg.PaymentService)
+	integrityHandler := handlers.NewIntegrityHandler(cfg.IntegrityService)
+	metaHandler := handlers.NewMetaHandler()
+	appVersionHandler := handlers.NewAppVersionHandler(cfg.AppVersionGate, cfg.SessionService)
+	mailboxHandler := handlers.NewActorMailboxHandler(cfg.ActorSystem, cfg.EventBus, cfg.Logger)
+	requireOperator := middleware.RequireOperatorMiddleware()
+
+	authHandler := handlers.NewAuthHandler(cfg.SessionService)
+	sessionAuth := middleware.SessionAuthMiddleware(cfg.SessionService)
+
+	graphqlHandler := handlers.NewGraphQLHandler(cfg.ObservabilityRepo, cfg.TripRepo, cfg.Redactor)
 
 	// Health check endpoints
 	setupHealthRoutes(router, cfg)
@@ -119,51 +307,156 @@ func setupRoutes(router *gin.Engine, cfg *RouterConfig) {
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
+		// API description routes
+		v1.GET("/meta/errors", metaHandler.GetErrorCatalog)
+		v1.GET("/meta/capabilities", appVersionHandler.GetCapabilities)
+
 		// User management routes
-		userRoutes := v1.Group("/users")
+		userRoutes := v1.Group("/users", httpCache(cfg, cfg.Config.HTTPCache.UsersCacheControl)...)
 		{
 			userRoutes.POST("", userHandler.CreateUser)
 			userRoutes.GET("", userHandler.ListUsers)
 			userRoutes.GET("/:id", userHandler.GetUser)
 			userRoutes.PUT("/:id", userHandler.UpdateUser)
 			userRoutes.GET("/:id/driver", userHandler.GetDriver)
+			userRoutes.GET("/:id/passenger", userHandler.GetPassenger)
+			userRoutes.PUT("/:id/avatar", userHandler.UploadAvatar)
+			userRoutes.DELETE("/:id/avatar", userHandler.DeleteAvatar)
 		}
 
 		// Driver-specific routes
-		driverRoutes := v1.Group("/drivers")
+		driverRoutes := v1.Group("/drivers", httpCache(cfg, cfg.Config.HTTPCache.DriversCacheControl)...)
 		{
 			driverRoutes.POST("", userHandler.CreateDriver)
 			driverRoutes.PUT("/:id/location", userHandler.UpdateDriverLocation)
 			driverRoutes.PUT("/:id/status", userHandler.UpdateDriverStatus)
 			driverRoutes.GET("/online", userHandler.GetOnlineDrivers)
+			driverRoutes.PUT("/:id/destination", userHandler.SetDriverDestination)
+			driverRoutes.GET("/:id/destination", userHandler.GetDriverDestination)
+			driverRoutes.DELETE("/:id/destination", userHandler.ClearDriverDestination)
+			driverRoutes.PUT("/:id/avatar", userHandler.UploadDriverAvatar)
+			driverRoutes.GET("/:id/quests", incentiveHandler.GetDriverQuests)
+			driverRoutes.GET("/:id/incentive-payouts", incentiveHandler.ListDriverIncentivePayouts)
+			driverRoutes.GET("/:id/onboarding", onboardingHandler.GetOnboardingProgress)
+			driverRoutes.POST("/:id/onboarding/documents", onboardingHandler.SubmitOnboardingDocuments)
+			driverRoutes.POST("/:id/onboarding/training", onboardingHandler.CompleteOnboardingTraining)
 		}
 
+		// Stored files, retrieved by signed URLs
+		v1.GET("/files/*key", fileHandler.GetFile)
+
 		// Passenger-specific routes
 		passengerRoutes := v1.Group("/passengers")
 		{
 			passengerRoutes.POST("", userHandler.CreatePassenger)
+			passengerRoutes.GET("/:id/locations", passengerHandler.ListSavedLocations)
+			passengerRoutes.POST("/:id/locations", passengerHandler.CreateSavedLocation)
+			passengerRoutes.PUT("/:id/locations/:location_id", passengerHandler.UpdateSavedLocation)
+			passengerRoutes.DELETE("/:id/locations/:location_id", passengerHandler.DeleteSavedLocation)
+			passengerRoutes.GET("/:id/recent-destinations", passengerHandler.GetRecentDestinations)
+			passengerRoutes.GET("/:id/ride-preferences", passengerHandler.GetRidePreferences)
+			passengerRoutes.PUT("/:id/ride-preferences", passengerHandler.UpdateRidePreferences)
 		}
 
 		// Ride management routes
-		rideRoutes := v1.Group("/rides")
+		rideRoutes := v1.Group("/rides", httpCache(cfg, cfg.Config.HTTPCache.RidesCacheControl)...)
 		{
 			rideRoutes.POST("/request", rideHandler.RequestRide)
 			rideRoutes.POST("/:id/cancel", rideHandler.CancelRide)
+			rideRoutes.GET("/:id/cancel-quote", rideHandler.GetCancelQuote)
 			rideRoutes.GET("/:id/status", rideHandler.GetRideStatus)
+			rideRoutes.GET("/:id/status/stream", rideHandler.StreamRideStatus)
 			rideRoutes.GET("", rideHandler.ListRides)
 		}
 
+		// Fare dispute routes for a ride's passenger
+		rideDisputeRoutes := v1.Group("/rides")
+		{
+			rideDisputeRoutes.POST("/:id/disputes", fareDisputeHandler.OpenFareDispute)
+			rideDisputeRoutes.GET("/:id/disputes", fareDisputeHandler.ListTripFareDisputes)
+			rideDisputeRoutes.GET("/:id/fare-adjustments", fareDisputeHandler.ListFareAdjustments)
+		}
+
+		// Trip timeline routes merging every observability source of a ride, its pickup wait and re-matches
+		rideTimelineRoutes := v1.Group("/rides")
+		{
+			rideTimelineRoutes.GET("/:id/timeline", timelineHandler.GetTripTimeline)
+			rideTimelineRoutes.GET("/:id/pickup-wait", pickupWaitHandler.GetPickupWait)
+			rideTimelineRoutes.GET("/:id/rematches", rematchHandler.ListTripRematches)
+		}
+
+		// In-trip chat routes for a ride's passenger and driver, authenticated with a session access token
+		rideChatRoutes := v1.Group("/rides", sessionAuth)
+		{
+			rideChatRoutes.GET("/:id/chat/messages", chatHandler.ListChatMessages)
+			rideChatRoutes.POST("/:id/chat/messages", chatHandler.SendChatMessage)
+			rideChatRoutes.POST("/:id/chat/read", chatHandler.MarkChatRead)
+			rideChatRoutes.GET("/:id/chat/ws", chatHandler.StreamChat)
+		}
+
+		// SOS route for a ride's passenger and driver, authenticated with a session access token
+		rideSafetyRoutes := v1.Group("/rides", sessionAuth)
+		{
+			rideSafetyRoutes.POST("/:id/sos", incidentHandler.ReportSOS)
+		}
+
+		// Pickup, cancellation and completion routes for a ride's driver, authenticated with a session access token
+		rideDriverRoutes := v1.Group("/rides", sessionAuth)
+		{
+			rideDriverRoutes.POST("/:id/driver-cancel", rematchHandler.DriverCancelRide)
+			rideDriverRoutes.POST("/:id/arrived", pickupWaitHandler.ArriveAtPickup)
+			rideDriverRoutes.POST("/:id/pickup", pickupWaitHandler.PickUpPassenger)
+			rideDriverRoutes.POST("/:id/no-show", pickupWaitHandler.ReportNoShow)
+			rideDriverRoutes.POST("/:id/complete", completionHandler.CompleteRide)
+		}
+
+		// Device session routes; listing and revoking require a session access token
+		authRoutes := v1.Group("/auth")
+		{
+			authRoutes.POST("/sessions", authHandler.Login)
+			authRoutes.POST("/sessions/refresh", authHandler.RefreshSession)
+			authRoutes.GET("/sessions", sessionAuth, authHandler.ListSessions)
+			authRoutes.DELETE("/sessions/:id", sessionAuth, authHandler.RevokeSession)
+		}
+
+		// Webhook routes, scoped to the caller's API key
+		webhookRoutes := v1.Group("/webhooks")
+		{
+			webhookRoutes.POST("", webhookHandler.RegisterWebhook)
+			webhookRoutes.GET("", webhookHandler.ListWebhooks)
+			webhookRoutes.DELETE("/:id", webhookHandler.DeleteWebhook)
+		}
+
+		// Fleet partner routes, scoped to the fleet of the caller's API key
+		fleetMiddleware := []gin.HandlerFunc{middleware.FleetAuthMiddleware(cfg.FleetService)}
+		if cfg.APIUsageService != nil {
+			fleetMiddleware = append(fleetMiddleware, middleware.APIUsageMiddleware(cfg.APIUsageService, middleware.FleetAPIKey))
+		}
+		fleetRoutes := v1.Group("/fleet", fleetMiddleware...)
+		{
+			fleetRoutes.POST("/drivers/status", fleetHandler.ImportDriverStatuses)
+			fleetRoutes.GET("/drivers/export", fleetHandler.ExportDrivers)
+			fleetRoutes.GET("/trips/export", fleetHandler.ExportTrips)
+			fleetRoutes.GET("/earnings/export", fleetHandler.ExportEarnings)
+		}
+
+		// GraphQL facade over observability data
+		v1.POST("/graphql", graphqlHandler.Query)
+		v1.GET("/graphql", graphqlHandler.QueryGet)
+
 		// Observability routes (Actor model)
 		observabilityRoutes := v1.Group("/observability")
 		{
 			actorRoutes := observabilityRoutes.Group("/actors")
 			{
 				actorRoutes.GET("", observabilityHandler.GetActorInstances)
+				actorRoutes.GET("/lifecycle", observabilityHandler.GetActorLifecycles)
 			}
 
 			messageRoutes := observabilityRoutes.Group("/messages")
 			{
 				messageRoutes.GET("", observabilityHandler.GetActorMessages)
+				messageRoutes.GET("/stats", observabilityHandler.GetMessageStats)
 			}
 
 			metricsRoutes := observabilityRoutes.Group("/metrics")
@@ -179,9 +472,24 @@ func setupRoutes(router *gin.Engine, cfg *RouterConfig) {
 			eventRoutes := observabilityRoutes.Group("/events")
 			{
 				eventRoutes.GET("", observabilityHandler.GetEventLogs)
+				eventRoutes.GET("/stream", observabilityHandler.StreamEventLogs)
 			}
 
 			observabilityRoutes.GET("/prometheus", observabilityHandler.GetPrometheusMetrics)
+			observabilityRoutes.GET("/slo", observabilityHandler.GetSLOReport)
+			observabilityRoutes.GET("/slow-queries", observabilityHandler.GetSlowQueries)
+			observabilityRoutes.GET("/collector/status", observabilityHandler.GetCollectorStatus)
+			observabilityRoutes.GET("/heatmap", heatmapHandler.GetTripHeatmap)
+			observabilityRoutes.GET("/scaling-signal", scalingSignalHandler.GetScalingSignal)
+			observabilityRoutes.GET("/storage-cost", storageCostHandler.GetStorageCost)
+		}
+
+		// Dashboard summaries, precomputed by the dashboard refresher
+		dashboardRoutes := v1.Group("/dashboard")
+		{
+			dashboardRoutes.GET("/hourly-trips", dashboardHandler.GetHourlyTrips)
+			dashboardRoutes.GET("/matching-times", dashboardHandler.GetMatchingTimes)
+			dashboardRoutes.GET("/zone-supply", dashboardHandler.GetZoneSupply)
 		}
 
 		// Traditional monitoring routes
@@ -213,6 +521,89 @@ func setupRoutes(router *gin.Engine, cfg *RouterConfig) {
 			systemRoutes.GET("/info", getSystemInfo(cfg))
 			systemRoutes.GET("/stats", getSystemStats(cfg))
 		}
+
+		// Runtime administration routes
+		adminRoutes := v1.Group("/admin")
+		{
+			adminRoutes.POST("/config/reload", requireOperator, reloadConfig(cfg))
+			adminRoutes.GET("/config/body-logging", getBodyLogging(cfg))
+			adminRoutes.PUT("/config/body-logging/routes", requireOperator, setBodyLoggingRoute(cfg))
+			adminRoutes.DELETE("/config/body-logging/routes", requireOperator, resetBodyLoggingRoute(cfg))
+			adminRoutes.GET("/drivers/stats", userHandler.GetDriverStats)
+			adminRoutes.GET("/drivers/:id/location-trail", requireOperator, locationTrailHandler.GetDriverLocationTrail)
+			adminRoutes.GET("/forecast", forecastHandler.GetDemandForecast)
+			adminRoutes.GET("/reports/daily-summary", reportHandler.GetDailySummary)
+			adminRoutes.GET("/webhooks/deliveries", webhookHandler.ListWebhookDeliveries)
+			adminRoutes.GET("/fare-disputes", fareDisputeHandler.ListFareDisputes)
+			adminRoutes.GET("/fare-disputes/:id", fareDisputeHandler.GetFareDispute)
+			adminRoutes.GET("/fare-disputes/:id/audit", fareDisputeHandler.GetFareDisputeAuditLog)
+			adminRoutes.POST("/fare-disputes/:id/review", fareDisputeHandler.StartFareDisputeReview)
+			adminRoutes.POST("/fare-disputes/:id/resolve", requireOperator, fareDisputeHandler.ResolveFareDispute)
+			adminRoutes.POST("/fare-disputes/:id/reject", fareDisputeHandler.RejectFareDispute)
+			adminRoutes.GET("/safety-incidents", incidentHandler.ListSafetyIncidents)
+			adminRoutes.GET("/safety-incidents/:id", incidentHandler.GetSafetyIncident)
+			adminRoutes.POST("/safety-incidents/:id/review", incidentHandler.StartSafetyIncidentReview)
+			adminRoutes.POST("/safety-incidents/:id/close", incidentHandler.CloseSafetyIncident)
+			adminRoutes.GET("/fraud-signals", fraudHandler.ListFraudSignals)
+			adminRoutes.GET("/fraud-signals/:id", fraudHandler.GetFraudSignal)
+			adminRoutes.POST("/fraud-signals/:id/review", fraudHandler.ReviewFraudSignal)
+			adminRoutes.GET("/payments/discrepancies", paymentHandler.ListPaymentDiscrepancies)
+			adminRoutes.GET("/payments/discrepancies/:id", paymentHandler.GetPaymentDiscrepancy)
+			adminRoutes.POST("/payments/discrepancies/:id/resolve", requireOperator, paymentHandler.ResolvePaymentDiscrepancy)
+			adminRoutes.POST("/payments/reconcile", paymentHandler.ReconcilePayments)
+			adminRoutes.GET("/trips/:id/payments", paymentHandler.ListTripPayments)
+			adminRoutes.POST("/trips/:id/payments", paymentHandler.RecordTripPayment)
+			adminRoutes.GET("/trips/search", tripSearchHandler.SearchTrips)
+			adminRoutes.GET("/trips/stuck", tripAdminHandler.ListStuckTrips)
+			adminRoutes.POST("/trips/:id/cancel", requireOperator, tripAdminHandler.ForceCancelTrip)
+			adminRoutes.GET("/research/trips/export", requireOperator, researchExportHandler.ExportTrips)
+			adminRoutes.GET("/app-versions", appVersionHandler.GetAppVersions)
+			adminRoutes.GET("/integrity/runs", integrityHandler.ListIntegrityRuns)
+			adminRoutes.POST("/integrity/runs", integrityHandler.RunIntegrityCheck)
+			adminRoutes.GET("/integrity/compare", integrityHandler.CompareIntegrityRuns)
+			adminRoutes.GET("/locks", lockHandler.ListLocks)
+			adminRoutes.GET("/schema/drift", schemaHandler.GetSchemaDrift)
+			adminRoutes.POST("/schema/drift", schemaHandler.CheckSchemaDrift)
+			adminRoutes.GET("/database/change-listener", changeListenerHandler.GetChangeListenerStats)
+			adminRoutes.POST("/retention/run", requireOperator, retentionHandler.RunRetention)
+			adminRoutes.GET("/experiments", experimentHandler.ListExperiments)
+			adminRoutes.POST("/experiments", requireOperator, experimentHandler.CreateExperiment)
+			adminRoutes.PUT("/experiments/active", requireOperator, experimentHandler.SetActiveExperiment)
+			adminRoutes.DELETE("/experiments/:name", requireOperator, experimentHandler.DropExperiment)
+			adminRoutes.GET("/trip-completions", completionHandler.ListTripCompletions)
+			adminRoutes.GET("/trip-completions/:id", completionHandler.GetTripCompletion)
+			adminRoutes.GET("/pickup-waits/zones", pickupWaitHandler.GetPickupWaitZoneStats)
+			adminRoutes.GET("/eta/accuracy", etaHandler.GetETAAccuracy)
+			adminRoutes.GET("/cancellations/analytics", cancellationHandler.GetCancellationAnalytics)
+			adminRoutes.GET("/driver-onboarding/funnel", onboardingHandler.GetOnboardingFunnel)
+			adminRoutes.POST("/driver-onboarding/:id/verify", onboardingHandler.VerifyDriverOnboarding)
+			adminRoutes.POST("/driver-onboarding/:id/activate", onboardingHandler.ActivateDriver)
+			adminRoutes.GET("/notifications/email/stats", emailHandler.GetEmailStats)
+			adminRoutes.POST("/notifications/weekly-earnings", emailHandler.SendWeeklyEarnings)
+			adminRoutes.GET("/matching/stats", matchingHandler.GetMatchingStats)
+			adminRoutes.GET("/locations/stats", locationIngestHandler.GetLocationIngestStats)
+			adminRoutes.GET("/actors/:id/mailbox", requireOperator, mailboxHandler.PeekActorMailbox)
+			adminRoutes.POST("/actors/:id/messages", requireOperator, mailboxHandler.InjectActorMessage)
+			adminRoutes.POST("/incentive-campaigns", incentiveHandler.CreateIncentiveCampaign)
+			adminRoutes.GET("/incentive-campaigns", incentiveHandler.ListIncentiveCampaigns)
+			adminRoutes.POST("/incentive-campaigns/:id/activate", incentiveHandler.ActivateIncentiveCampaign)
+			adminRoutes.POST("/incentive-campaigns/:id/deactivate", incentiveHandler.DeactivateIncentiveCampaign)
+			adminRoutes.POST("/dashboard/refresh", dashboardHandler.RefreshDashboard)
+			adminRoutes.POST("/fleets", fleetHandler.CreateFleet)
+			adminRoutes.PUT("/fleets/:id/request-quota", fleetHandler.SetRequestQuota)
+			adminRoutes.GET("/usage", apiUsageHandler.GetAPIUsage)
+			adminRoutes.POST("/corporate-accounts", corporateHandler.CreateAccount)
+			adminRoutes.GET("/corporate-accounts/:id", corporateHandler.GetAccount)
+			adminRoutes.PUT("/corporate-accounts/:id/spending-limit", corporateHandler.SetSpendingLimit)
+			adminRoutes.PUT("/corporate-accounts/:id/passengers/:passenger_id", corporateHandler.LinkPassenger)
+			adminRoutes.DELETE("/corporate-accounts/:id/passengers/:passenger_id", corporateHandler.UnlinkPassenger)
+			adminRoutes.GET("/corporate-accounts/:id/statements", corporateHandler.ListStatements)
+			adminRoutes.GET("/corporate-accounts/:id/statements/:month", corporateHandler.GetStatement)
+			adminRoutes.POST("/invoices/runs", invoiceHandler.RunInvoices)
+			adminRoutes.GET("/invoices", invoiceHandler.ListInvoices)
+			adminRoutes.GET("/invoices/:id", invoiceHandler.GetInvoice)
+			adminRoutes.POST("/invoices/:id/retry", invoiceHandler.RetryInvoice)
+		}
 	}
 
 	// Admin routes (if needed)
@@ -336,6 +727,18 @@ func setupAdminRoutes(router *gin.Engine, cfg *RouterConfig) {
 				stats := cfg.ActorSystem.GetMetrics()
 				c.JSON(http.StatusOK, stats)
 			})
+
+			// Goroutines of stopped actors that are still running
+			actorAdmin.GET("/goroutines", func(c *gin.Context) {
+				if cfg.ActorSystem == nil {
+					c.JSON(http.StatusServiceUnavailable, gin.H{
+						"error": "Actor system not available",
+					})
+					return
+				}
+
+				c.JSON(http.StatusOK, cfg.ActorSystem.AuditGoroutines())
+			})
 		}
 
 		// Traditional monitor management
@@ -400,6 +803,106 @@ func getSystemInfo(cfg *RouterConfig) gin.HandlerFunc {
 	}
 }
 
+// reloadConfig re-reads configuration and applies runtime-changeable settings
+func reloadConfig(cfg *RouterConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.ConfigReloader == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Configuration reload not available",
+			})
+			return
+		}
+
+		changes, err := cfg.ConfigReloader.Reload(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Failed to reload configuration",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "reloaded",
+			"changes": changes,
+		})
+	}
+}
+
+// BodyLoggingRouteRequest switches body logging on or off for a route at runtime
+type BodyLoggingRouteRequest struct {
+	Route   string `json:"route" binding:"required"` // route template, e.g. /api/v1/rides/:id
+	Enabled *bool  `json:"enabled" binding:"required"`
+}
+
+// getBodyLogging returns the request/response body logging settings in effect
+func getBodyLogging(cfg *RouterConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.BodyLogger == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Body logging not available",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, cfg.BodyLogger.Settings())
+	}
+}
+
+// setBodyLoggingRoute switches body logging on or off for a route, overriding the configuration
+// until the override is reset
+func setBodyLoggingRoute(cfg *RouterConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.BodyLogger == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Body logging not available",
+			})
+			return
+		}
+
+		var req BodyLoggingRouteRequest
+		if err := c.ShouldBindJSON(&req); err != nil || !strings.HasPrefix(req.Route, "/") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid request body",
+				"message": "A route template starting with / and enabled are required",
+			})
+			return
+		}
+
+		cfg.BodyLogger.SetRoute(req.Route, *req.Enabled)
+		cfg.Logger.WithFields(logging.Fields{
+			"route":   req.Route,
+			"enabled": *req.Enabled,
+		}).Info("Body logging switched for route")
+
+		c.JSON(http.StatusOK, cfg.BodyLogger.Settings())
+	}
+}
+
+// resetBodyLoggingRoute drops the runtime override of the route in the route query parameter
+func resetBodyLoggingRoute(cfg *RouterConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.BodyLogger == nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{
+				"error": "Body logging not available",
+			})
+			return
+		}
+
+		route := c.Query("route")
+		if !strings.HasPrefix(route, "/") {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid route",
+				"message": "A route template starting with / is required",
+			})
+			return
+		}
+
+		cfg.BodyLogger.ResetRoute(route)
+		c.JSON(http.StatusOK, cfg.BodyLogger.Settings())
+	}
+}
+
 // getSystemStats returns system statistics
 func getSystemStats(cfg *RouterConfig) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,233 @@
+package scenario
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"time"
+
+	"actor-model-observability/internal/models"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario describes a reproducible traffic experiment for the simulator and the load tester
+type Scenario struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	Seed        int64             `yaml:"seed"` // 0 picks a random seed per run
+	Duration    time.Duration     `yaml:"duration"`
+	Tick        time.Duration     `yaml:"tick"`
+	Area        Area              `yaml:"area"`
+	Hotspots    []Hotspot         `yaml:"hotspots"`
+	Drivers     DriverSettings    `yaml:"drivers"`
+	Passengers  PassengerSettings `yaml:"passengers"`
+	Phases      []Phase           `yaml:"phases"`
+}
+
+// Area is the circular region drivers cruise in and trips start and end in
+type Area struct {
+	Latitude  float64 `yaml:"latitude"`
+	Longitude float64 `yaml:"longitude"`
+	RadiusKm  float64 `yaml:"radius_km"`
+}
+
+// Hotspot is a weighted sub-area where pickups and dropoffs concentrate
+type Hotspot struct {
+	Name      string  `yaml:"name"`
+	Latitude  float64 `yaml:"latitude"`
+	Longitude float64 `yaml:"longitude"`
+	RadiusKm  float64 `yaml:"radius_km"`
+	Weight    float64 `yaml:"weight"`
+}
+
+// DriverSettings controls the synthetic driver fleet
+type DriverSettings struct {
+	Count    int     `yaml:"count"`
+	SpeedKmh float64 `yaml:"speed_kmh"`
+	Churn    Churn   `yaml:"churn"`
+}
+
+// Churn makes idle drivers go offline and come back during the run
+type Churn struct {
+	Interval           time.Duration `yaml:"interval"` // 0 disables churn
+	OfflineProbability float64       `yaml:"offline_probability"`
+	OnlineProbability  float64       `yaml:"online_probability"`
+}
+
+// PassengerSettings controls the synthetic passengers and their ride requests
+type PassengerSettings struct {
+	Count int `yaml:"count"`
+	// ArrivalRate is the number of ride requests per minute across all passengers,
+	// used when no phases are defined
+	ArrivalRate             float64       `yaml:"arrival_rate"`
+	CancellationProbability float64       `yaml:"cancellation_probability"`
+	MatchTimeout            time.Duration `yaml:"match_timeout"`
+}
+
+// Phase overrides the arrival rate for part of the run. Phases run back to back;
+// the last phase continues until the scenario ends.
+type Phase struct {
+	Name        string        `yaml:"name"`
+	Duration    time.Duration `yaml:"duration"`
+	ArrivalRate float64       `yaml:"arrival_rate"`
+}
+
+// Default returns the scenario used when no scenario file is given
+func Default() *Scenario {
+	return &Scenario{
+		Name:     "default",
+		Duration: 5 * time.Minute,
+		Tick:     2 * time.Second,
+		Area: Area{
+			Latitude:  37.7749,
+			Longitude: -122.4194,
+			RadiusKm:  4,
+		},
+		Drivers: DriverSettings{
+			Count:    20,
+			SpeedKmh: 40,
+		},
+		Passengers: PassengerSettings{
+			Count:        50,
+			ArrivalRate:  60,
+			MatchTimeout: 30 * time.Second,
+		},
+	}
+}
+
+// Load reads and validates a scenario file. Fields missing from the file keep their defaults.
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario %s: %w", path, err)
+	}
+
+	s, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Parse decodes and validates a YAML scenario
+func Parse(data []byte) (*Scenario, error) {
+	s := Default()
+	if err := yaml.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario: %w", err)
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate checks the scenario for values the simulator cannot run with
+func (s *Scenario) Validate() error {
+	if s.Duration <= 0 || s.Tick <= 0 {
+		return fmt.Errorf("duration and tick must be positive")
+	}
+
+	center := models.Location{Latitude: s.Area.Latitude, Longitude: s.Area.Longitude}
+	if !center.IsValid() || s.Area.RadiusKm <= 0 {
+		return fmt.Errorf("area must have valid coordinates and a positive radius")
+	}
+
+	for i, h := range s.Hotspots {
+		loc := models.Location{Latitude: h.Latitude, Longitude: h.Longitude}
+		if !loc.IsValid() || h.RadiusKm <= 0 || h.Weight <= 0 {
+			return fmt.Errorf("hotspot %d (%s) must have valid coordinates, a positive radius and a positive weight", i, h.Name)
+		}
+	}
+
+	if s.Drivers.Count < 0 || s.Passengers.Count < 0 {
+		return fmt.Errorf("driver and passenger counts must not be negative")
+	}
+	if s.Drivers.SpeedKmh <= 0 {
+		return fmt.Errorf("drivers.speed_kmh must be positive")
+	}
+
+	churn := s.Drivers.Churn
+	if churn.Interval < 0 || !isProbability(churn.OfflineProbability) || !isProbability(churn.OnlineProbability) {
+		return fmt.Errorf("drivers.churn must have a non-negative interval and probabilities between 0 and 1")
+	}
+
+	if s.Passengers.ArrivalRate < 0 || s.Passengers.MatchTimeout <= 0 {
+		return fmt.Errorf("passengers.arrival_rate must not be negative and passengers.match_timeout must be positive")
+	}
+	if !isProbability(s.Passengers.CancellationProbability) {
+		return fmt.Errorf("passengers.cancellation_probability must be between 0 and 1")
+	}
+
+	for i, p := range s.Phases {
+		if p.Duration <= 0 || p.ArrivalRate < 0 {
+			return fmt.Errorf("phase %d (%s) must have a positive duration and a non-negative arrival rate", i, p.Name)
+		}
+	}
+
+	return nil
+}
+
+// ArrivalRate returns the ride requests per minute at elapsed time into the run
+func (s *Scenario) ArrivalRate(elapsed time.Duration) float64 {
+	if len(s.Phases) == 0 {
+		return s.Passengers.ArrivalRate
+	}
+
+	for _, p := range s.Phases {
+		if elapsed < p.Duration {
+			return p.ArrivalRate
+		}
+		elapsed -= p.Duration
+	}
+	return s.Phases[len(s.Phases)-1].ArrivalRate
+}
+
+// Center returns the center of the scenario area
+func (s *Scenario) Center() models.Location {
+	return models.Location{Latitude: s.Area.Latitude, Longitude: s.Area.Longitude}
+}
+
+// RandomLocation returns a pickup or dropoff location, drawn from a hotspot chosen by
+// weight when hotspots are defined and from the whole area otherwise
+func (s *Scenario) RandomLocation(rng *rand.Rand) models.Location {
+	if len(s.Hotspots) == 0 {
+		return RandomPoint(rng, s.Center(), s.Area.RadiusKm)
+	}
+
+	total := 0.0
+	for _, h := range s.Hotspots {
+		total += h.Weight
+	}
+
+	pick := rng.Float64() * total
+	hotspot := s.Hotspots[len(s.Hotspots)-1]
+	for _, h := range s.Hotspots {
+		if pick < h.Weight {
+			hotspot = h
+			break
+		}
+		pick -= h.Weight
+	}
+
+	return RandomPoint(rng, models.Location{Latitude: hotspot.Latitude, Longitude: hotspot.Longitude}, hotspot.RadiusKm)
+}
+
+// RandomPoint returns a uniformly distributed point within radiusKm of center
+func RandomPoint(rng *rand.Rand, center models.Location, radiusKm float64) models.Location {
+	const kmPerDegree = 111.32
+
+	distance := radiusKm * math.Sqrt(rng.Float64())
+	bearing := rng.Float64() * 2 * math.Pi
+
+	return models.Location{
+		Latitude:  center.Latitude + distance*math.Cos(bearing)/kmPerDegree,
+		Longitude: center.Longitude + distance*math.Sin(bearing)/(kmPerDegree*math.Cos(center.Latitude*math.Pi/180)),
+	}
+}
+
+func isProbability(p float64) bool {
+	return p >= 0 && p <= 1
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+const (
+	// apiUsageMaxBuckets caps the time buckets of a usage report
+	apiUsageMaxBuckets = 1440
+	// apiUsagePruneInterval is how often the rollups past the retention period are removed
+	apiUsagePruneInterval = time.Hour
+	// apiQuotaDay is the period of a daily request quota, aligned to UTC
+	apiQuotaDay = 24 * time.Hour
+)
+
+// apiUsageKey identifies a rollup by API key and interval start (Unix seconds)
+type apiUsageKey struct {
+	keyID  string
+	bucket int64
+}
+
+// dailyAPIUsage is the requests an API key made on a UTC day that count against its quota
+type dailyAPIUsage struct {
+	day         time.Time
+	stored      int64     // requests stored by every instance when last counted, plus the ones flushed since
+	pending     int64     // requests of this instance not flushed yet
+	refreshedAt time.Time // when stored was last counted
+}
+
+// APIUsageService records the requests made with fleet API keys and enforces their daily request
+// quotas. Every instance rolls the requests it serves up per key and minute in memory, with their
+// errors and latency, and adds them to the stored rollups every flush interval. Quotas count the
+// stored requests of the day, recounted every flush interval, and the instance's own requests
+// not flushed yet, so other instances' requests count with a delay of up to two intervals.
+type APIUsageService struct {
+	usage   repository.APIUsageRepository
+	cfg     config.APIUsageConfig
+	metrics BusinessMetricsRecorder
+	logger  *logging.Logger
+	now     func() time.Time
+
+	mu      sync.Mutex
+	pending map[apiUsageKey]*models.APIUsageRollup
+	daily   map[string]*dailyAPIUsage // by key ID
+	flushes int64                     // flushes completed, telling recounts racing a flush apart
+
+	// flushMu serializes flushes
+	flushMu sync.Mutex
+	pruned  time.Time // owned by the flush loop
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewAPIUsageService creates a new API usage service. Quota rejections are counted in metrics,
+// which may be nil.
+func NewAPIUsageService(
+	usage repository.APIUsageRepository,
+	cfg config.APIUsageConfig,
+	metrics BusinessMetricsRecorder,
+	logger *logging.Logger,
+) *APIUsageService {
+	return &APIUsageService{
+		usage:   usage,
+		cfg:     cfg,
+		metrics: metrics,
+		logger:  logger.WithComponent("api_usage_service"),
+		now:     time.Now,
+		pending: make(map[apiUsageKey]*models.APIUsageRollup),
+		daily:   make(map[string]*dailyAPIUsage),
+	}
+}
+
+// CheckQuota returns how much of its daily request quota a key has used before a request is made
+// with it. The quota of the key is the one of its fleet, or the default quota. When the stored
+// requests cannot be counted the request is allowed, as if the key had no quota.
+func (s *APIUsageService) CheckQuota(ctx context.Context, key models.APIKeyRef) models.APIQuotaStatus {
+	now := s.now().UTC()
+	day := now.Truncate(apiQuotaDay)
+	status := models.APIQuotaStatus{
+		Limit:   s.cfg.DefaultDailyQuota,
+		ResetAt: day.Add(apiQuotaDay),
+	}
+	if key.DailyRequestQuota != nil {
+		status.Limit = *key.DailyRequestQuota
+	}
+	if status.Limit <= 0 {
+		return status
+	}
+
+	s.mu.Lock()
+	usage := s.dailyUsage(key.KeyID, day)
+	stale := usage.refreshedAt.IsZero() || now.Sub(usage.refreshedAt) >= s.cfg.FlushInterval
+	flushes := s.flushes
+	s.mu.Unlock()
+
+	if stale {
+		stored, err := s.usage.CountRequests(ctx, key.KeyID, day, day.Add(apiQuotaDay))
+		if err != nil {
+			s.logger.WithError(err).WithField("tenant_id", key.TenantID.String()).Warn("Failed to count API key requests, quota not enforced")
+			return models.APIQuotaStatus{ResetAt: status.ResetAt}
+		}
+
+		s.mu.Lock()
+		// A flush completed during the count may or may not be in it; the count is left for the
+		// next check then, the flushed requests being moved to stored already
+		if usage = s.dailyUsage(key.KeyID, day); s.flushes == flushes {
+			usage.stored = stored
+			usage.refreshedAt = now
+		}
+		s.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	status.Used = usage.stored + usage.pending
+	s.mu.Unlock()
+
+	if status.Exceeded() && s.metrics != nil {
+		s.metrics.RecordBusinessMetrics("api_quota_rejections_total", 1, map[string]string{
+			"tenant_id": key.TenantID.String(),
+		})
+	}
+	return status
+}
+
+// Record counts a request made with a key that was answered with status after latency.
+// Requests rejected for the key's quota do not count against it.
+func (s *APIUsageService) Record(key models.APIKeyRef, status int, latency time.Duration, rejected bool) {
+	now := s.now().UTC()
+	latencyMs := float64(latency.Microseconds()) / 1000
+	request := &models.APIUsageRollup{
+		Requests:     1,
+		LatencySumMs: latencyMs,
+		LatencyMaxMs: latencyMs,
+	}
+	switch {
+	case status >= 500:
+		request.ServerErrors = 1
+	case status >= 400:
+		request.ClientErrors = 1
+	}
+	if rejected {
+		request.QuotaRejections = 1
+	}
+
+	bucket := now.Truncate(models.APIUsageRollupInterval)
+	id := apiUsageKey{keyID: key.KeyID, bucket: bucket.Unix()}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rollup, ok := s.pending[id]
+	if !ok {
+		rollup = &models.APIUsageRollup{KeyID: key.KeyID, TenantID: key.TenantID, BucketStart: bucket}
+		s.pending[id] = rollup
+	}
+	rollup.Add(request)
+	if !rejected {
+		s.dailyUsage(key.KeyID, now.Truncate(apiQuotaDay)).pending++
+	}
+}
+
+// dailyUsage returns the usage of a key on day, starting it afresh on a new day. s.mu must be
+// held.
+func (s *APIUsageService) dailyUsage(keyID string, day time.Time) *dailyAPIUsage {
+	usage, ok := s.daily[keyID]
+	if !ok || usage.day.Before(day) {
+		usage = &dailyAPIUsage{day: day}
+		s.daily[keyID] = usage
+	}
+	return usage
+}
+
+// Flush adds the requests rolled up since the last flush to the stored rollups. Requests that
+// fail to be stored are kept for the next flush.
+func (s *APIUsageService) Flush(ctx context.Context) error {
+	s.flushMu.Lock()
+	defer s.flushMu.Unlock()
+
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[apiUsageKey]*models.APIUsageRollup)
+	s.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// Instances upsert in the same order, so their transactions never deadlock
+	rollups := make([]*models.APIUsageRollup, 0, len(pending))
+	for _, rollup := range pending {
+		rollups = append(rollups, rollup)
+	}
+	sort.Slice(rollups, func(i, j int) bool {
+		if !rollups[i].BucketStart.Equal(rollups[j].BucketStart) {
+			return rollups[i].BucketStart.Before(rollups[j].BucketStart)
+		}
+		return rollups[i].KeyID < rollups[j].KeyID
+	})
+
+	if err := s.usage.Upsert(ctx, rollups); err != nil {
+		s.mu.Lock()
+		for id, rollup := range pending {
+			if current, ok := s.pending[id]; ok {
+				rollup.Add(current)
+			}
+			s.pending[id] = rollup
+		}
+		s.mu.Unlock()
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	today := s.now().UTC().Truncate(apiQuotaDay)
+	for _, rollup := range rollups {
+		usage, ok := s.daily[rollup.KeyID]
+		if !ok || !usage.day.Equal(rollup.BucketStart.Truncate(apiQuotaDay)) {
+			continue
+		}
+		flushed := rollup.Requests - rollup.QuotaRejections
+		usage.pending -= flushed
+		usage.stored += flushed
+	}
+	for keyID, usage := range s.daily {
+		if usage.day.Before(today) {
+			delete(s.daily, keyID)
+		}
+	}
+	s.flushes++
+	return nil
+}
+
+// Prune removes the rollups past the retention period
+func (s *APIUsageService) Prune(ctx context.Context) (int64, error) {
+	return s.usage.DeleteBefore(ctx, s.now().Add(-s.cfg.RetentionPeriod))
+}
+
+// Report returns the usage of API keys over [from, to), by key and time bucket of the given size,
+// optionally of one key or tenant. The window is widened to start on a bucket boundary, in UTC.
+// Requests served since the last flush of each instance are not reported yet.
+func (s *APIUsageService) Report(ctx context.Context, filter models.APIUsageFilter, bucket string) (*models.APIUsageReport, error) {
+	size, ok := models.APIUsageBucketSizes[bucket]
+	if !ok {
+		return nil, &models.ValidationError{
+			Field:   "bucket",
+			Message: "must be minute, hour, or day",
+		}
+	}
+	if !filter.From.Before(filter.To) {
+		return nil, &models.ValidationError{
+			Field:   "from",
+			Message: "must be before to",
+		}
+	}
+
+	filter.From = filter.From.UTC().Truncate(size)
+	filter.To = filter.To.UTC()
+	if buckets := int64(math.Ceil(float64(filter.To.Sub(filter.From)) / float64(size))); buckets > apiUsageMaxBuckets {
+		return nil, &models.ValidationError{
+			Field:   "bucket",
+			Message: "the window spans more than 1440 buckets; use larger buckets or a shorter window",
+		}
+	}
+
+	rollups, err := s.usage.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	type bucketKey struct {
+		start int64
+		keyID string
+	}
+	sums := make(map[bucketKey]*models.APIUsageRollup)
+	var order []bucketKey
+	var total models.APIUsageRollup
+	for _, rollup := range rollups {
+		start := rollup.BucketStart.UTC().Truncate(size)
+		id := bucketKey{start: start.Unix(), keyID: rollup.KeyID}
+		sum, ok := sums[id]
+		if !ok {
+			sum = &models.APIUsageRollup{KeyID: rollup.KeyID, TenantID: rollup.TenantID, BucketStart: start}
+			sums[id] = sum
+			order = append(order, id)
+		}
+		sum.Add(rollup)
+		total.Add(rollup)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		if order[i].start != order[j].start {
+			return order[i].start < order[j].start
+		}
+		return order[i].keyID < order[j].keyID
+	})
+
+	buckets := make([]*models.APIUsageBucket, 0, len(order))
+	for _, id := range order {
+		sum := sums[id]
+		buckets = append(buckets, &models.APIUsageBucket{
+			BucketStart:   sum.BucketStart,
+			KeyID:         sum.KeyID,
+			TenantID:      sum.TenantID,
+			APIUsageStats: apiUsageStats(sum),
+		})
+	}
+
+	return &models.APIUsageReport{
+		From:    filter.From,
+		To:      filter.To,
+		Bucket:  bucket,
+		Buckets: buckets,
+		Total:   apiUsageStats(&total),
+	}, nil
+}
+
+// apiUsageStats returns the request counts, error rate and latency of a sum of rollups
+func apiUsageStats(sum *models.APIUsageRollup) models.APIUsageStats {
+	stats := models.APIUsageStats{
+		Requests:        sum.Requests,
+		ClientErrors:    sum.ClientErrors,
+		ServerErrors:    sum.ServerErrors,
+		QuotaRejections: sum.QuotaRejections,
+		MaxLatencyMs:    math.Round(sum.LatencyMaxMs*100) / 100,
+	}
+	if sum.Requests > 0 {
+		n := float64(sum.Requests)
+		stats.ErrorRate = math.Round(float64(sum.ClientErrors+sum.ServerErrors)/n*10000) / 10000
+		stats.AvgLatencyMs = math.Round(sum.LatencySumMs/n*100) / 100
+	}
+	return stats
+}
+
+// Start starts flushing the rolled up usage every interval
+func (s *APIUsageService) Start(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go s.flushLoop()
+
+	s.logger.Info("API usage service started")
+	return nil
+}
+
+// Stop stops the flush loop and flushes the usage rolled up since the last flush
+func (s *APIUsageService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	if err := s.Flush(context.Background()); err != nil {
+		s.logger.WithError(err).Error("Failed to flush API usage")
+	}
+
+	s.logger.Info("API usage service stopped")
+	return nil
+}
+
+// flushLoop flushes the usage on every interval and prunes old rollups every prune interval
+func (s *APIUsageService) flushLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-s.ctx.Done():
+			return
+		}
+
+		if err := s.Flush(s.ctx); err != nil && s.ctx.Err() == nil {
+			s.logger.WithError(err).Error("Failed to flush API usage")
+		}
+
+		if now := s.now(); now.Sub(s.pruned) >= apiUsagePruneInterval {
+			s.pruned = now
+			deleted, err := s.Prune(s.ctx)
+			if err != nil {
+				if s.ctx.Err() == nil {
+					s.logger.WithError(err).Error("Failed to prune API usage rollups")
+				}
+				continue
+			}
+			if deleted > 0 {
+				s.logger.WithField("deleted", deleted).Info("Old API usage rollups pruned")
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/storage"
+)
+
+// avatarExtensions are the accepted profile picture types, by the extension they are stored under
+var avatarExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// AvatarService stores user profile pictures. A picture is stored under a new key on every upload,
+// so signed URLs handed out earlier never show a newer picture, and the previous picture is
+// deleted once the user points at the new one.
+type AvatarService struct {
+	users  repository.UserRepository
+	store  storage.Store
+	cfg    config.StorageConfig
+	logger *logging.Logger
+}
+
+// NewAvatarService creates a new avatar service
+func NewAvatarService(users repository.UserRepository, store storage.Store, cfg config.StorageConfig, logger *logging.Logger) *AvatarService {
+	return &AvatarService{
+		users:  users,
+		store:  store,
+		cfg:    cfg,
+		logger: logger.WithComponent("avatar_service"),
+	}
+}
+
+// MaxSize returns the largest picture accepted, in bytes
+func (s *AvatarService) MaxSize() int {
+	return s.cfg.MaxAvatarSize
+}
+
+// Upload stores a JPEG, PNG or WebP picture as the user's profile picture, replacing the previous
+// one. The type is detected from the content, whatever the uploader claimed.
+func (s *AvatarService) Upload(ctx context.Context, userID string, data []byte) (*models.User, error) {
+	if len(data) == 0 {
+		return nil, &models.ValidationError{Field: "avatar", Message: "picture is empty"}
+	}
+	if len(data) > s.cfg.MaxAvatarSize {
+		return nil, &models.ValidationError{
+			Field:   "avatar",
+			Message: fmt.Sprintf("picture must not exceed %d bytes", s.cfg.MaxAvatarSize),
+		}
+	}
+	contentType := http.DetectContentType(data)
+	extension, ok := avatarExtensions[contentType]
+	if !ok {
+		return nil, &models.ValidationError{Field: "avatar", Message: "picture must be a JPEG, PNG or WebP image"}
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, fmt.Errorf("failed to generate avatar key: %w", err)
+	}
+	key := fmt.Sprintf("avatars/%s/%s%s", user.ID, hex.EncodeToString(suffix), extension)
+	if err := s.store.Put(ctx, key, data, contentType); err != nil {
+		return nil, err
+	}
+
+	previous := user.AvatarKey
+	user.AvatarKey = &key
+	user.UpdatedAt = time.Now()
+	if err := s.users.Update(ctx, user); err != nil {
+		s.deleteFile(ctx, key)
+		return nil, err
+	}
+	if previous != nil {
+		s.deleteFile(ctx, *previous)
+	}
+
+	s.logger.WithField("user_id", user.ID.String()).WithField("size", len(data)).Info("Avatar uploaded")
+	return user, s.AttachURLs(ctx, user)
+}
+
+// Remove deletes the user's profile picture
+func (s *AvatarService) Remove(ctx context.Context, userID string) (*models.User, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.AvatarKey == nil {
+		return user, nil
+	}
+
+	previous := *user.AvatarKey
+	user.AvatarKey = nil
+	user.UpdatedAt = time.Now()
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	s.deleteFile(ctx, previous)
+	return user, nil
+}
+
+// URL returns a signed URL of the user's profile picture, empty when the user has none
+func (s *AvatarService) URL(ctx context.Context, user *models.User) (string, error) {
+	if user.AvatarKey == nil {
+		return "", nil
+	}
+	return s.store.SignedURL(ctx, *user.AvatarKey, s.cfg.URLTTL)
+}
+
+// AttachURLs sets the avatar URL of the users with a profile picture
+func (s *AvatarService) AttachURLs(ctx context.Context, users ...*models.User) error {
+	for _, user := range users {
+		url, err := s.URL(ctx, user)
+		if err != nil {
+			return fmt.Errorf("failed to sign avatar URL: %w", err)
+		}
+		user.AvatarURL = url
+	}
+	return nil
+}
+
+// deleteFile deletes a picture no user points at, logging failures as the picture is then only
+// left behind
+func (s *AvatarService) deleteFile(ctx context.Context, key string) {
+	if err := s.store.Delete(ctx, key); err != nil {
+		s.logger.WithError(err).WithField("key", key).Warn("Failed to delete avatar")
+	}
+}
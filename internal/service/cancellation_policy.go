@@ -0,0 +1,68 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+)
+
+// CancellationPolicy decides what a passenger is charged for cancelling a trip: nothing before a
+// driver is matched, within the grace period after or for one of the free reasons, the fee once
+// the grace period is over and the arrived fee once the driver is at the pickup
+type CancellationPolicy struct {
+	cfg         config.CancellationPolicyConfig
+	freeReasons map[models.CancellationReason]bool
+}
+
+// NewCancellationPolicy creates a cancellation policy, failing for free reasons outside the
+// cancellation reason taxonomy
+func NewCancellationPolicy(cfg config.CancellationPolicyConfig) (*CancellationPolicy, error) {
+	freeReasons := make(map[models.CancellationReason]bool, len(cfg.FreeReasons))
+	for _, raw := range cfg.FreeReasons {
+		reason := models.CancellationReason(strings.TrimSpace(raw))
+		if !reason.IsValid() {
+			return nil, fmt.Errorf("invalid free cancellation reason: %s", raw)
+		}
+		freeReasons[reason] = true
+	}
+	return &CancellationPolicy{cfg: cfg, freeReasons: freeReasons}, nil
+}
+
+// Quote returns what cancelling the trip at now for the reason costs. The reason may be empty
+// when the passenger has not picked one yet.
+func (p *CancellationPolicy) Quote(trip *models.Trip, reason models.CancellationReason, now time.Time) *models.CancellationQuote {
+	quote := &models.CancellationQuote{
+		TripID:      trip.ID,
+		Status:      trip.Status,
+		Cancellable: trip.IsActive(),
+		QuotedAt:    now,
+	}
+
+	matchedAt := trip.MatchedAt
+	if matchedAt == nil {
+		matchedAt = trip.AcceptedAt
+	}
+	switch {
+	case !quote.Cancellable:
+		quote.Rule = models.CancellationRuleTripEnded
+	case p.freeReasons[reason]:
+		quote.Rule = models.CancellationRuleFreeReason
+	case trip.Status == models.TripStatusDriverArrived || trip.Status == models.TripStatusInProgress:
+		quote.Rule = models.CancellationRuleArrived
+		quote.Fee = p.cfg.ArrivedFee
+	case trip.DriverID == nil || matchedAt == nil:
+		quote.Rule = models.CancellationRuleNoDriver
+	case now.Before(matchedAt.Add(p.cfg.GracePeriod)):
+		quote.Rule = models.CancellationRuleGracePeriod
+		graceEndsAt := matchedAt.Add(p.cfg.GracePeriod)
+		quote.GraceEndsAt = &graceEndsAt
+	default:
+		quote.Rule = models.CancellationRuleLate
+		quote.Fee = p.cfg.Fee
+	}
+	quote.Free = quote.Cancellable && quote.Fee == 0
+	return quote
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"actor-model-observability/internal/geohash"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// Defaults and limits of the cancellation analytics
+const (
+	DefaultCancellationBucket        = time.Hour
+	DefaultCancellationZonePrecision = 5
+	MinCancellationBucket            = time.Minute
+	MaxCancellationBuckets           = 1000
+)
+
+// CancellationService breaks down why, where and when trips are cancelled
+type CancellationService struct {
+	trips repository.TripRepository
+}
+
+// NewCancellationService creates a new cancellation service
+func NewCancellationService(trips repository.TripRepository) *CancellationService {
+	return &CancellationService{trips: trips}
+}
+
+// Analytics breaks down the trips cancelled in [start, end) by reason, by mode, by pickup
+// geohash zone of zonePrecision characters and over buckets of the period starting at start.
+// Every reason of the taxonomy and every bucket is listed, those without cancellations included.
+func (s *CancellationService) Analytics(ctx context.Context, start, end time.Time, bucket time.Duration, zonePrecision int) (*models.CancellationAnalytics, error) {
+	if bucket < MinCancellationBucket {
+		return nil, &models.ValidationError{
+			Field:   "bucket",
+			Message: fmt.Sprintf("bucket must be at least %s", MinCancellationBucket),
+		}
+	}
+	buckets := int((end.Sub(start) + bucket - 1) / bucket)
+	if buckets > MaxCancellationBuckets {
+		return nil, &models.ValidationError{
+			Field:   "bucket",
+			Message: fmt.Sprintf("the period spans %d buckets of %s; use a longer bucket or a shorter period of at most %d buckets", buckets, bucket, MaxCancellationBuckets),
+		}
+	}
+	grid, err := geohash.NewGrid(zonePrecision)
+	if err != nil {
+		return nil, &models.ValidationError{
+			Field:   "zone_precision",
+			Message: err.Error(),
+		}
+	}
+
+	trips, err := s.trips.ListCancelled(ctx, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cancelled trips: %w", err)
+	}
+
+	byReason := make(map[string]int64, len(models.CancellationReasons))
+	for _, reason := range models.CancellationReasons {
+		byReason[string(reason)] = 0
+	}
+	byMode := make(map[string]int64)
+	byZone := make(map[string]int64)
+	byTime := make([]models.CancellationTimeBucket, buckets)
+	for i := range byTime {
+		byTime[i].Start = start.Add(time.Duration(i) * bucket)
+	}
+
+	for _, trip := range trips {
+		reason, mode := models.CancellationUnknown, models.CancellationUnknown
+		if trip.CancellationReason != nil {
+			reason = string(*trip.CancellationReason)
+		}
+		if trip.CancellationMode != nil {
+			mode = *trip.CancellationMode
+		}
+		byReason[reason]++
+		byMode[mode]++
+		byZone[grid.Geohash(grid.Locate(trip.PickupLatitude, trip.PickupLongitude))]++
+
+		b := &byTime[int(trip.CancelledAt.Sub(start)/bucket)]
+		b.Count++
+		if b.ByReason == nil {
+			b.ByReason = make(map[string]int64)
+		}
+		b.ByReason[reason]++
+	}
+
+	total := int64(len(trips))
+	return &models.CancellationAnalytics{
+		Start:    start,
+		End:      end,
+		Bucket:   bucket.String(),
+		Total:    total,
+		ByReason: cancellationCounts(byReason, total),
+		ByMode:   cancellationCounts(byMode, total),
+		ByZone:   cancellationCounts(byZone, total),
+		ByTime:   byTime,
+	}, nil
+}
+
+// cancellationCounts lists counts with their share of total, the largest first
+func cancellationCounts(counts map[string]int64, total int64) []models.CancellationCount {
+	result := make([]models.CancellationCount, 0, len(counts))
+	for key, count := range counts {
+		c := models.CancellationCount{Key: key, Count: count}
+		if total > 0 {
+			c.Share = math.Round(float64(count)/float64(total)*10000) / 10000
+		}
+		result = append(result, c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
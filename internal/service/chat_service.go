@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/chat"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// ChatService runs the in-trip chat between a trip's passenger and driver. Participants send
+// messages while the trip has a driver and is not over; message bodies pass through the chat
+// filters before they are stored and delivered to live subscribers. Participants can read the
+// conversation until it is purged, a retention period after the trip completed or was cancelled.
+type ChatService struct {
+	messages     repository.ChatRepository
+	participants tripParticipants
+	filter       chat.Filter
+	hub          *chat.Hub
+	cfg          config.ChatConfig
+	logger       *logging.Logger
+	now          func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewChatService creates a new chat service. filter may be nil to store bodies as sent.
+func NewChatService(
+	messages repository.ChatRepository,
+	trips repository.TripRepository,
+	drivers repository.DriverRepository,
+	passengers repository.PassengerRepository,
+	filter chat.Filter,
+	cfg config.ChatConfig,
+	logger *logging.Logger,
+) *ChatService {
+	return &ChatService{
+		messages:     messages,
+		participants: tripParticipants{trips: trips, drivers: drivers, passengers: passengers},
+		filter:       filter,
+		hub:          chat.NewHub(),
+		cfg:          cfg,
+		logger:       logger.WithComponent("chat_service"),
+		now:          time.Now,
+	}
+}
+
+// Start purges expired messages immediately and then on every purge interval
+func (s *ChatService) Start(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	if _, err := s.PurgeExpired(s.ctx); err != nil {
+		s.logger.WithError(err).Error("Initial chat purge failed")
+	}
+
+	s.wg.Add(1)
+	go s.purgeLoop()
+
+	s.logger.Info("Chat purger started")
+	return nil
+}
+
+// Stop stops the purge loop
+func (s *ChatService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Chat purger stopped")
+	return nil
+}
+
+// purgeLoop purges expired messages on every purge interval
+func (s *ChatService) purgeLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.PurgeExpired(s.ctx); err != nil {
+				s.logger.WithError(err).Error("Chat purge failed")
+			}
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// PurgeExpired deletes the messages of the trips that ended more than the retention period ago
+func (s *ChatService) PurgeExpired(ctx context.Context) (int64, error) {
+	deleted, err := s.messages.DeleteForTripsEndedBefore(ctx, s.now().Add(-s.cfg.RetentionPeriod))
+	if err != nil {
+		return 0, err
+	}
+	if deleted > 0 {
+		s.logger.WithField("messages", deleted).Info("Purged expired chat messages")
+	}
+	return deleted, nil
+}
+
+// SendMessage sends a message from a participant of an active trip to the other participant
+func (s *ChatService) SendMessage(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType, body string) (*models.TripChatMessage, error) {
+	trip, err := s.participants.authorize(ctx, tripID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	if trip.DriverID == nil || !trip.IsActive() {
+		return nil, models.ErrTripChatClosed
+	}
+
+	msg := &models.TripChatMessage{
+		ID:         uuid.New(),
+		TripID:     trip.ID,
+		SenderRole: models.ChatSenderRole(userType),
+		SenderID:   userID,
+		Body:       body,
+		CreatedAt:  s.now(),
+	}
+	// The body is validated as sent; a filter replacing contact details may lengthen it
+	if err := msg.Validate(); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "body",
+			Message: err.Error(),
+		}
+	}
+	if s.filter != nil {
+		msg.Body = s.filter.Apply(msg.Body)
+	}
+
+	if err := s.messages.Create(ctx, msg); err != nil {
+		return nil, err
+	}
+
+	s.hub.Publish(msg)
+	return msg, nil
+}
+
+// ListMessages returns a trip's messages, oldest first, to one of its participants
+func (s *ChatService) ListMessages(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType, limit, offset int) ([]*models.TripChatMessage, error) {
+	if _, err := s.participants.authorize(ctx, tripID, userID, userType); err != nil {
+		return nil, err
+	}
+	return s.messages.ListByTrip(ctx, tripID, limit, offset)
+}
+
+// MarkRead marks the messages a participant received on a trip as read and returns how many
+// were unread
+func (s *ChatService) MarkRead(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType) (int, error) {
+	if _, err := s.participants.authorize(ctx, tripID, userID, userType); err != nil {
+		return 0, err
+	}
+	return s.messages.MarkRead(ctx, tripID, models.ChatSenderRole(userType), s.now())
+}
+
+// UnreadCounts returns the number of messages each participant of a trip has not read
+func (s *ChatService) UnreadCounts(ctx context.Context, tripID string) (*models.TripChatUnreadCounts, error) {
+	return s.messages.CountUnread(ctx, tripID)
+}
+
+// Subscribe returns a channel receiving the messages sent on a trip from now on, for one of its
+// participants, and a function that ends the subscription
+func (s *ChatService) Subscribe(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType) (<-chan *models.TripChatMessage, func(), error) {
+	if _, err := s.participants.authorize(ctx, tripID, userID, userType); err != nil {
+		return nil, nil, err
+	}
+	messages, unsubscribe := s.hub.Subscribe(tripID)
+	return messages, unsubscribe, nil
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// ConfigReloader re-reads configuration and applies runtime-changeable settings
+type ConfigReloader struct {
+	mu       sync.Mutex
+	current  *config.Config
+	load     func() (*config.Config, error)
+	appliers []func(cfg *config.Config)
+	events   bus.Publisher
+	logger   *logging.Logger
+}
+
+// NewConfigReloader creates a new configuration reloader for the given live configuration.
+// Reload events are published to events.
+func NewConfigReloader(cfg *config.Config, events bus.Publisher, logger *logging.Logger) *ConfigReloader {
+	return &ConfigReloader{
+		current: cfg,
+		load:    config.Load,
+		events:  events,
+		logger:  logger.WithComponent("config_reloader"),
+	}
+}
+
+// OnReload registers a function that is called with the updated configuration
+// whenever a reload changes at least one runtime-changeable setting
+func (r *ConfigReloader) OnReload(fn func(cfg *config.Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.appliers = append(r.appliers, fn)
+}
+
+// Reload re-reads the configuration, applies the settings that can change at runtime
+// and returns every detected change. Changes that require a restart are reported but not applied.
+// The process environment cannot change, so new values come from the env file named by
+// CONFIG_ENV_FILE, which is read again on every reload and overrides the process environment.
+func (r *ConfigReloader) Reload(ctx context.Context) ([]config.Change, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.load()
+	if err != nil {
+		r.logger.WithError(err).Error("Failed to reload configuration")
+		return nil, fmt.Errorf("failed to reload configuration: %w", err)
+	}
+
+	changes := r.current.Diff(next)
+
+	applied := 0
+	for _, change := range changes {
+		if change.Applied {
+			applied++
+		}
+	}
+
+	if applied > 0 {
+		r.current.ApplyReloadable(next)
+		for _, apply := range r.appliers {
+			apply(r.current)
+		}
+	}
+
+	r.logger.WithFields(logging.Fields{
+		"changes":          len(changes),
+		"applied":          applied,
+		"requires_restart": len(changes) - applied,
+	}).Info("Configuration reloaded")
+
+	r.recordReloadEvent(changes, applied)
+
+	return changes, nil
+}
+
+// recordReloadEvent publishes an event log entry describing what changed
+func (r *ConfigReloader) recordReloadEvent(changes []config.Change, applied int) {
+	if r.events == nil {
+		return
+	}
+
+	eventData, _ := json.Marshal(map[string]interface{}{
+		"changes": changes,
+	})
+	eventLog := &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     "config_reloaded",
+		EventCategory: models.EventCategorySystem,
+		EventData:     eventData,
+		Severity:      models.EventSeverityInfo,
+		Message: fmt.Sprintf("Configuration reloaded: %d setting(s) applied, %d require restart",
+			applied, len(changes)-applied),
+		Timestamp: time.Now(),
+		CreatedAt: time.Now(),
+	}
+	r.events.Publish(bus.TopicEventLog, eventLog)
+}
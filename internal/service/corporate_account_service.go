@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// defaultCorporateStatementMonths is how many monthly statements are listed by default
+	defaultCorporateStatementMonths = 12
+	// maxCorporateStatementMonths caps the monthly statements listed at once
+	maxCorporateStatementMonths = 24
+)
+
+// CorporateAccountService manages corporate accounts, the passengers linked to them and the
+// trips billed to them. Spending limits and statements cover calendar months in UTC.
+type CorporateAccountService struct {
+	accounts   repository.CorporateAccountRepository
+	passengers repository.PassengerRepository
+	logger     *logging.Logger
+	now        func() time.Time
+}
+
+// NewCorporateAccountService creates a new corporate account service
+func NewCorporateAccountService(accounts repository.CorporateAccountRepository, passengers repository.PassengerRepository, logger *logging.Logger) *CorporateAccountService {
+	return &CorporateAccountService{
+		accounts:   accounts,
+		passengers: passengers,
+		logger:     logger.WithComponent("corporate_account_service"),
+		now:        time.Now,
+	}
+}
+
+// CreateAccount stores a new corporate account; a nil limit leaves its spending unlimited
+func (s *CorporateAccountService) CreateAccount(ctx context.Context, name string, monthlyLimit *float64) (*models.CorporateAccount, error) {
+	now := s.now()
+	account := &models.CorporateAccount{
+		ID:                   uuid.New(),
+		Name:                 strings.TrimSpace(name),
+		MonthlySpendingLimit: monthlyLimit,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	if err := validateCorporateAccount(account); err != nil {
+		return nil, err
+	}
+
+	if err := s.accounts.Create(ctx, account); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithField("account_id", account.ID.String()).Info("Corporate account created")
+	return account, nil
+}
+
+// GetAccount retrieves a corporate account
+func (s *CorporateAccountService) GetAccount(ctx context.Context, id string) (*models.CorporateAccount, error) {
+	return s.accounts.GetByID(ctx, id)
+}
+
+// SetSpendingLimit changes the monthly spending limit of an account; nil removes it. Trips
+// already billed are kept even if the account is now over its limit.
+func (s *CorporateAccountService) SetSpendingLimit(ctx context.Context, id string, monthlyLimit *float64) (*models.CorporateAccount, error) {
+	account, err := s.accounts.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	account.MonthlySpendingLimit = monthlyLimit
+	account.UpdatedAt = s.now()
+	if err := validateCorporateAccount(account); err != nil {
+		return nil, err
+	}
+	if err := s.accounts.Update(ctx, account); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithField("account_id", id).Info("Corporate account spending limit updated")
+	return account, nil
+}
+
+// LinkPassenger links a passenger to an account, replacing any account it was linked to
+func (s *CorporateAccountService) LinkPassenger(ctx context.Context, accountID, passengerID string) error {
+	account, err := s.accounts.GetByID(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	if err := s.accounts.SetPassengerAccount(ctx, passengerID, &account.ID); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(logging.Fields{
+		"account_id":   accountID,
+		"passenger_id": passengerID,
+	}).Info("Passenger linked to corporate account")
+	return nil
+}
+
+// UnlinkPassenger removes a passenger from an account. Trips it already billed stay on the
+// account's statements.
+func (s *CorporateAccountService) UnlinkPassenger(ctx context.Context, accountID, passengerID string) error {
+	passenger, err := s.passengers.GetByID(ctx, passengerID)
+	if err != nil {
+		return err
+	}
+	if passenger.CorporateAccountID == nil || passenger.CorporateAccountID.String() != accountID {
+		return &models.NotFoundError{
+			Resource: "corporate account passenger",
+			ID:       passengerID,
+		}
+	}
+	if err := s.accounts.SetPassengerAccount(ctx, passengerID, nil); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(logging.Fields{
+		"account_id":   accountID,
+		"passenger_id": passengerID,
+	}).Info("Passenger unlinked from corporate account")
+	return nil
+}
+
+// ChargeTrip bills a trip to the corporate account of its passenger within the account's
+// spending limit for the current month
+func (s *CorporateAccountService) ChargeTrip(ctx context.Context, passenger *models.Passenger, trip *models.Trip, estimatedFare float64) error {
+	if passenger.CorporateAccountID == nil {
+		return models.ErrNoCorporateAccount
+	}
+
+	account, err := s.accounts.GetByID(ctx, passenger.CorporateAccountID.String())
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	periodStart, periodEnd := statementPeriod(now)
+	charge := &models.CorporateTripCharge{
+		TripID:        trip.ID,
+		AccountID:     account.ID,
+		PassengerID:   passenger.ID,
+		EstimatedFare: roundFare(estimatedFare),
+		CreatedAt:     now,
+	}
+	if err := s.accounts.ChargeTrip(ctx, charge, account.MonthlySpendingLimit, periodStart, periodEnd); err != nil {
+		return err
+	}
+
+	s.logger.WithFields(logging.Fields{
+		"account_id":     account.ID.String(),
+		"trip_id":        trip.ID.String(),
+		"estimated_fare": charge.EstimatedFare,
+	}).Info("Trip billed to corporate account")
+	return nil
+}
+
+// GetStatement returns the statement of an account for a month formatted as 2006-01, with the
+// spend of each passenger
+func (s *CorporateAccountService) GetStatement(ctx context.Context, accountID, month string) (*models.CorporateStatement, error) {
+	monthStart, err := time.Parse(models.CorporateStatementMonthLayout, month)
+	if err != nil {
+		return nil, &models.ValidationError{Field: "month", Message: "month must be formatted as YYYY-MM"}
+	}
+
+	account, err := s.accounts.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return s.statement(ctx, account, monthStart, true)
+}
+
+// ListStatements returns the statements of an account for the last months, the current month
+// included, newest first. Passenger breakdowns are left out.
+func (s *CorporateAccountService) ListStatements(ctx context.Context, accountID string, months int) ([]*models.CorporateStatement, error) {
+	if months <= 0 {
+		months = defaultCorporateStatementMonths
+	}
+	if months > maxCorporateStatementMonths {
+		return nil, &models.ValidationError{
+			Field:   "months",
+			Message: fmt.Sprintf("must be at most %d", maxCorporateStatementMonths),
+		}
+	}
+
+	account, err := s.accounts.GetByID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, _ := statementPeriod(s.now())
+	statements := make([]*models.CorporateStatement, 0, months)
+	for i := 0; i < months; i++ {
+		statement, err := s.statement(ctx, account, current.AddDate(0, -i, 0), false)
+		if err != nil {
+			return nil, err
+		}
+		statements = append(statements, statement)
+	}
+	return statements, nil
+}
+
+// statement aggregates the charges of an account over the month starting at monthStart
+func (s *CorporateAccountService) statement(ctx context.Context, account *models.CorporateAccount, monthStart time.Time, byPassenger bool) (*models.CorporateStatement, error) {
+	start, end := statementPeriod(monthStart)
+	charges, err := s.accounts.ListCharges(ctx, account.ID.String(), start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list corporate trip charges: %w", err)
+	}
+
+	statement := &models.CorporateStatement{
+		AccountID:     account.ID,
+		AccountName:   account.Name,
+		Month:         start.Format(models.CorporateStatementMonthLayout),
+		PeriodStart:   start,
+		PeriodEnd:     end,
+		SpendingLimit: account.MonthlySpendingLimit,
+	}
+
+	lines := make(map[uuid.UUID]*models.CorporateStatementLine)
+	for _, charge := range charges {
+		amount := charge.Amount()
+		statement.Trips++
+		statement.TotalSpend += amount
+		switch charge.TripStatus {
+		case models.TripStatusCompleted:
+			statement.CompletedTrips++
+		case models.TripStatusCancelled:
+			statement.CancelledTrips++
+		}
+
+		line, ok := lines[charge.PassengerID]
+		if !ok {
+			line = &models.CorporateStatementLine{PassengerID: charge.PassengerID}
+			lines[charge.PassengerID] = line
+		}
+		line.Trips++
+		line.TotalSpend += amount
+	}
+	statement.TotalSpend = roundFare(statement.TotalSpend)
+
+	if account.MonthlySpendingLimit != nil {
+		remaining := roundFare(*account.MonthlySpendingLimit - statement.TotalSpend)
+		if remaining < 0 {
+			remaining = 0
+		}
+		statement.RemainingLimit = &remaining
+	}
+
+	if byPassenger {
+		statement.Passengers = make([]models.CorporateStatementLine, 0, len(lines))
+		for _, line := range lines {
+			line.TotalSpend = roundFare(line.TotalSpend)
+			statement.Passengers = append(statement.Passengers, *line)
+		}
+		sort.Slice(statement.Passengers, func(i, j int) bool {
+			a, b := statement.Passengers[i], statement.Passengers[j]
+			if a.TotalSpend != b.TotalSpend {
+				return a.TotalSpend > b.TotalSpend
+			}
+			return a.PassengerID.String() < b.PassengerID.String()
+		})
+	}
+
+	return statement, nil
+}
+
+// statementPeriod returns the calendar month in UTC containing t
+func statementPeriod(t time.Time) (time.Time, time.Time) {
+	t = t.UTC()
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 1, 0)
+}
+
+// validateCorporateAccount validates an account, reporting the offending field
+func validateCorporateAccount(account *models.CorporateAccount) error {
+	err := account.Validate()
+	if err == nil {
+		return nil
+	}
+
+	field := "name"
+	if err == models.ErrInvalidSpendingLimit {
+		field = "monthly_spending_limit"
+	}
+	return &models.ValidationError{
+		Field:   field,
+		Message: err.Error(),
+	}
+}
@@ -0,0 +1,391 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/geohash"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// DashboardService serves the dashboard summaries from their summary tables and keeps those
+// tables fresh. The hourly trip and matching time summaries are recomputed for the configured
+// refresh window on every trip refresh interval, and the zone supply summary on every supply
+// refresh interval, so dashboards never aggregate the raw trips and drivers per request.
+type DashboardService struct {
+	repo   repository.DashboardRepository
+	cfg    config.DashboardConfig
+	grid   geohash.Grid
+	logger *logging.Logger
+	now    func() time.Time
+
+	refreshMu sync.Mutex // serializes scheduled and on-demand refreshes
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
+}
+
+// NewDashboardService creates a new dashboard service
+func NewDashboardService(repo repository.DashboardRepository, cfg config.DashboardConfig, logger *logging.Logger) *DashboardService {
+	// The zone precision is checked by config validation
+	grid, _ := geohash.NewGrid(cfg.ZonePrecision)
+	return &DashboardService{
+		repo:   repo,
+		cfg:    cfg,
+		grid:   grid,
+		logger: logger.WithComponent("dashboard_service"),
+		now:    time.Now,
+	}
+}
+
+// Start refreshes every summary immediately and then on their refresh intervals
+func (s *DashboardService) Start(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	if _, err := s.RefreshAll(s.ctx); err != nil {
+		s.logger.WithError(err).Error("Initial dashboard refresh failed")
+	}
+
+	s.wg.Add(2)
+	go s.refreshLoop(s.cfg.TripRefreshInterval, models.DashboardViewHourlyTrips, models.DashboardViewMatchingTimes)
+	go s.refreshLoop(s.cfg.SupplyRefreshInterval, models.DashboardViewZoneSupply)
+
+	s.logger.Info("Dashboard refresher started")
+	return nil
+}
+
+// Stop stops the refresh loops
+func (s *DashboardService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Dashboard refresher stopped")
+	return nil
+}
+
+// refreshLoop refreshes views on every interval
+func (s *DashboardService) refreshLoop(interval time.Duration, views ...string) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			for _, view := range views {
+				if _, err := s.Refresh(s.ctx, view); err != nil {
+					s.logger.WithError(err).WithField("view", view).Error("Dashboard refresh failed")
+				}
+			}
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// RefreshAll refreshes every summary view and returns their freshness
+func (s *DashboardService) RefreshAll(ctx context.Context) ([]*models.DashboardFreshness, error) {
+	var freshness []*models.DashboardFreshness
+	var errs []error
+	for _, view := range models.DashboardViews {
+		f, err := s.Refresh(ctx, view)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		freshness = append(freshness, f)
+	}
+	return freshness, errors.Join(errs...)
+}
+
+// Refresh recomputes a summary view from the trips and drivers and returns its new freshness
+func (s *DashboardService) Refresh(ctx context.Context, view string) (*models.DashboardFreshness, error) {
+	if _, err := s.refreshInterval(view); err != nil {
+		return nil, err
+	}
+
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	started := s.now()
+	var rows int
+	var err error
+	switch view {
+	case models.DashboardViewHourlyTrips:
+		rows, err = s.refreshHourlyTrips(ctx, started)
+	case models.DashboardViewMatchingTimes:
+		rows, err = s.refreshMatchingTimes(ctx, started)
+	case models.DashboardViewZoneSupply:
+		rows, err = s.refreshZoneSupply(ctx, started)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh %s: %w", view, err)
+	}
+
+	s.logger.WithFields(logging.Fields{
+		"view":        view,
+		"rows":        rows,
+		"duration_ms": s.now().Sub(started).Milliseconds(),
+	}).Debug("Dashboard summary refreshed")
+
+	return s.Freshness(ctx, view)
+}
+
+// refreshStart returns the first hour recomputed by a refresh at now
+func (s *DashboardService) refreshStart(now time.Time) time.Time {
+	return now.UTC().Truncate(time.Hour).Add(-s.cfg.RefreshWindow).Truncate(time.Hour)
+}
+
+// newRefresh returns the refresh record of a view refreshed from started until now
+func (s *DashboardService) newRefresh(view string, started time.Time, rows int) *models.DashboardRefresh {
+	return &models.DashboardRefresh{
+		View:        view,
+		RefreshedAt: started.UTC(),
+		DurationMs:  s.now().Sub(started).Milliseconds(),
+		Rows:        rows,
+	}
+}
+
+// refreshHourlyTrips recomputes the trip counts of every hour in the refresh window, including
+// hours without trips
+func (s *DashboardService) refreshHourlyTrips(ctx context.Context, now time.Time) (int, error) {
+	since := s.refreshStart(now)
+	timings, err := s.repo.GetTripTimings(ctx, since)
+	if err != nil {
+		return 0, err
+	}
+
+	hours := int(now.UTC().Sub(since)/time.Hour) + 1
+	rows := make([]*models.HourlyTripSummary, hours)
+	for i := range rows {
+		rows[i] = &models.HourlyTripSummary{Hour: since.Add(time.Duration(i) * time.Hour)}
+	}
+	for _, t := range timings {
+		i := int(t.RequestedAt.UTC().Sub(since) / time.Hour)
+		if i < 0 || i >= hours {
+			continue // requested in the future, e.g. clock skew
+		}
+		rows[i].Requested++
+		if t.MatchedAt != nil {
+			rows[i].Matched++
+		}
+		if t.CompletedAt != nil {
+			rows[i].Completed++
+		}
+		if t.CancelledAt != nil {
+			rows[i].Cancelled++
+		}
+	}
+
+	return len(rows), s.repo.ReplaceHourlyTrips(ctx, since, rows, s.newRefresh(models.DashboardViewHourlyTrips, now, len(rows)))
+}
+
+// refreshMatchingTimes recomputes the matching time percentiles of every hour in the refresh
+// window in which trips were matched
+func (s *DashboardService) refreshMatchingTimes(ctx context.Context, now time.Time) (int, error) {
+	since := s.refreshStart(now)
+	timings, err := s.repo.GetTripTimings(ctx, since)
+	if err != nil {
+		return 0, err
+	}
+
+	waits := make(map[time.Time][]float64)
+	for _, t := range timings {
+		if t.MatchedAt == nil || t.MatchedAt.Before(t.RequestedAt) {
+			continue
+		}
+		hour := t.RequestedAt.UTC().Truncate(time.Hour)
+		waits[hour] = append(waits[hour], t.MatchedAt.Sub(t.RequestedAt).Seconds())
+	}
+
+	rows := make([]*models.MatchingTimeSummary, 0, len(waits))
+	for hour, seconds := range waits {
+		sort.Float64s(seconds)
+		var total float64
+		for _, v := range seconds {
+			total += v
+		}
+		rows = append(rows, &models.MatchingTimeSummary{
+			Hour:       hour,
+			Samples:    len(seconds),
+			AvgSeconds: roundSeconds(total / float64(len(seconds))),
+			P50Seconds: roundSeconds(percentile(seconds, 50)),
+			P90Seconds: roundSeconds(percentile(seconds, 90)),
+			P99Seconds: roundSeconds(percentile(seconds, 99)),
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Hour.Before(rows[j].Hour) })
+
+	return len(rows), s.repo.ReplaceMatchingTimes(ctx, since, rows, s.newRefresh(models.DashboardViewMatchingTimes, now, len(rows)))
+}
+
+// refreshZoneSupply recounts the online drivers in every zone
+func (s *DashboardService) refreshZoneSupply(ctx context.Context, now time.Time) (int, error) {
+	positions, err := s.repo.GetDriverPositions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	zones := make(map[string]*models.ZoneSupplySummary)
+	for _, p := range positions {
+		if p.Status != models.DriverStatusOnline && p.Status != models.DriverStatusBusy {
+			continue
+		}
+		zone := s.grid.Geohash(s.grid.Locate(p.Latitude, p.Longitude))
+		if zones[zone] == nil {
+			zones[zone] = &models.ZoneSupplySummary{Zone: zone}
+		}
+		if p.Status == models.DriverStatusOnline {
+			zones[zone].AvailableDrivers++
+		} else {
+			zones[zone].BusyDrivers++
+		}
+	}
+
+	rows := make([]*models.ZoneSupplySummary, 0, len(zones))
+	for _, row := range zones {
+		rows = append(rows, row)
+	}
+
+	return len(rows), s.repo.ReplaceZoneSupply(ctx, rows, s.newRefresh(models.DashboardViewZoneSupply, now, len(rows)))
+}
+
+// HourlyTrips returns the hourly trip counts of the last hours hours, including the current one
+func (s *DashboardService) HourlyTrips(ctx context.Context, hours int) (*models.DashboardSummary, error) {
+	since, err := s.summarySince(hours)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.ListHourlyTrips(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		rows = []*models.HourlyTripSummary{}
+	}
+	return s.summary(ctx, models.DashboardViewHourlyTrips, rows)
+}
+
+// MatchingTimes returns the matching time percentiles of the last hours hours, including the
+// current one. Hours without matched trips are omitted.
+func (s *DashboardService) MatchingTimes(ctx context.Context, hours int) (*models.DashboardSummary, error) {
+	since, err := s.summarySince(hours)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.repo.ListMatchingTimes(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		rows = []*models.MatchingTimeSummary{}
+	}
+	return s.summary(ctx, models.DashboardViewMatchingTimes, rows)
+}
+
+// ZoneSupply returns the online drivers per zone, most available drivers first
+func (s *DashboardService) ZoneSupply(ctx context.Context) (*models.DashboardSummary, error) {
+	rows, err := s.repo.ListZoneSupply(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rows == nil {
+		rows = []*models.ZoneSupplySummary{}
+	}
+	for _, row := range rows {
+		if box, err := geohash.Decode(row.Zone); err == nil {
+			row.CenterLat = (box.MinLat + box.MaxLat) / 2
+			row.CenterLng = (box.MinLng + box.MaxLng) / 2
+		}
+	}
+	return s.summary(ctx, models.DashboardViewZoneSupply, rows)
+}
+
+// Freshness returns when a view was last refreshed and whether it is stale
+func (s *DashboardService) Freshness(ctx context.Context, view string) (*models.DashboardFreshness, error) {
+	interval, err := s.refreshInterval(view)
+	if err != nil {
+		return nil, err
+	}
+
+	freshness := &models.DashboardFreshness{
+		View:            view,
+		RefreshInterval: interval.String(),
+		Stale:           true,
+	}
+
+	refresh, err := s.repo.GetRefresh(ctx, view)
+	var notFound *models.NotFoundError
+	if errors.As(err, &notFound) {
+		return freshness, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	age := math.Max(s.now().Sub(refresh.RefreshedAt).Seconds(), 0)
+	age = roundSeconds(age)
+	freshness.RefreshedAt = &refresh.RefreshedAt
+	freshness.AgeSeconds = &age
+	freshness.Stale = age > (2 * interval).Seconds()
+	return freshness, nil
+}
+
+// summary wraps the rows of a view with its freshness
+func (s *DashboardService) summary(ctx context.Context, view string, rows interface{}) (*models.DashboardSummary, error) {
+	freshness, err := s.Freshness(ctx, view)
+	if err != nil {
+		return nil, err
+	}
+	return &models.DashboardSummary{DashboardFreshness: *freshness, Data: rows}, nil
+}
+
+// summarySince validates hours and returns the start of the first of the last hours hours
+func (s *DashboardService) summarySince(hours int) (time.Time, error) {
+	if hours <= 0 || hours > s.cfg.MaxHours {
+		return time.Time{}, &models.ValidationError{
+			Field:   "hours",
+			Message: fmt.Sprintf("hours must be between 1 and %d", s.cfg.MaxHours),
+		}
+	}
+	return s.now().UTC().Truncate(time.Hour).Add(-time.Duration(hours-1) * time.Hour), nil
+}
+
+// refreshInterval returns the refresh interval of a view
+func (s *DashboardService) refreshInterval(view string) (time.Duration, error) {
+	switch view {
+	case models.DashboardViewHourlyTrips, models.DashboardViewMatchingTimes:
+		return s.cfg.TripRefreshInterval, nil
+	case models.DashboardViewZoneSupply:
+		return s.cfg.SupplyRefreshInterval, nil
+	default:
+		return 0, &models.ValidationError{
+			Field:   "view",
+			Message: fmt.Sprintf("view must be one of %v", models.DashboardViews),
+		}
+	}
+}
+
+// percentile returns the nearest-rank percentile p of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank-1, 0)]
+}
+
+// roundSeconds rounds a duration in seconds to milliseconds
+func roundSeconds(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// DriverFatigueService enforces the limits on how long drivers may be online and driving. Time
+// online (including busy) and driving (busy) is summed over a rolling window; an online driver
+// reaching either limit is set offline and cannot go back online until the rest period is over.
+// A driver's window restarts when their last rest ends.
+type DriverFatigueService struct {
+	drivers repository.DriverRepository
+	cfg     config.FatigueConfig
+	events  bus.Publisher
+	metrics BusinessMetricsRecorder
+	logger  *logging.Logger
+	now     func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDriverFatigueService creates a new driver fatigue service. Rests are published on events and
+// counted in metrics; both may be nil.
+func NewDriverFatigueService(
+	drivers repository.DriverRepository,
+	cfg config.FatigueConfig,
+	events bus.Publisher,
+	metrics BusinessMetricsRecorder,
+	logger *logging.Logger,
+) *DriverFatigueService {
+	return &DriverFatigueService{
+		drivers: drivers,
+		cfg:     cfg,
+		events:  events,
+		metrics: metrics,
+		logger:  logger.WithComponent("driver_fatigue_service"),
+		now:     time.Now,
+	}
+}
+
+// Start checks the online drivers against the limits on every check interval; it does nothing
+// when fatigue limits are disabled
+func (s *DriverFatigueService) Start(ctx context.Context) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go s.enforceLoop()
+
+	s.logger.Info("Driver fatigue enforcer started")
+	return nil
+}
+
+// Stop stops the enforce loop
+func (s *DriverFatigueService) Stop() error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	s.wg.Wait()
+
+	s.logger.Info("Driver fatigue enforcer stopped")
+	return nil
+}
+
+// enforceLoop enforces the limits on every check interval
+func (s *DriverFatigueService) enforceLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := s.Enforce(s.ctx); err != nil {
+				s.logger.WithError(err).Error("Driver fatigue check failed")
+			}
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// GetFatigue returns the driver's time online and driving over their current window, the
+// limits, and the rest the driver is on, if any
+func (s *DriverFatigueService) GetFatigue(ctx context.Context, driverID string) (*models.DriverFatigue, error) {
+	now := s.now()
+	rest, err := s.latestRest(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	hours, err := s.drivers.GetDriverHours(ctx, driverID, s.windowStart(rest, now), now)
+	if err != nil {
+		return nil, err
+	}
+
+	fatigue := &models.DriverFatigue{
+		DriverHours:         *hours,
+		OnlineLimitSeconds:  int64(s.cfg.MaxOnline.Seconds()),
+		DrivingLimitSeconds: int64(s.cfg.MaxDriving.Seconds()),
+	}
+	if rest != nil && rest.RestUntil.After(now) {
+		fatigue.Rest = rest
+	}
+	return fatigue, nil
+}
+
+// CheckOnline fails with ErrDriverResting while the driver is on a rest
+func (s *DriverFatigueService) CheckOnline(ctx context.Context, driverID string) error {
+	if !s.cfg.Enabled {
+		return nil
+	}
+
+	rest, err := s.latestRest(ctx, driverID)
+	if err != nil {
+		return err
+	}
+	if rest != nil && rest.RestUntil.After(s.now()) {
+		return fmt.Errorf("%w until %s", models.ErrDriverResting, rest.RestUntil.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Enforce sends every online driver who reached a limit on a rest and returns the rests started
+func (s *DriverFatigueService) Enforce(ctx context.Context) ([]*models.DriverRest, error) {
+	now := s.now()
+	hours, err := s.drivers.ListOnlineDriverHours(ctx, now.Add(-s.cfg.Window), now)
+	if err != nil {
+		return nil, err
+	}
+
+	var rests []*models.DriverRest
+	for _, h := range hours {
+		if s.limitReached(h) == "" {
+			continue
+		}
+
+		// The time before the driver's last rest ended does not count
+		driverID := h.DriverID.String()
+		rest, err := s.latestRest(ctx, driverID)
+		if err != nil {
+			return rests, err
+		}
+		if start := s.windowStart(rest, now); start.After(h.WindowStart) {
+			if h, err = s.drivers.GetDriverHours(ctx, driverID, start, now); err != nil {
+				return rests, err
+			}
+		}
+
+		limit := s.limitReached(h)
+		if limit == "" {
+			continue
+		}
+
+		rest = &models.DriverRest{
+			ID:             uuid.New(),
+			DriverID:       h.DriverID,
+			Limit:          limit,
+			OnlineSeconds:  h.OnlineSeconds,
+			DrivingSeconds: h.DrivingSeconds,
+			StartedAt:      now,
+			RestUntil:      now.Add(s.cfg.RestPeriod),
+		}
+		if err := s.drivers.StartRest(ctx, rest); err != nil {
+			// The driver took a trip or went offline since the hours were summed
+			if errors.Is(err, models.ErrDriverNotOnline) {
+				continue
+			}
+			return rests, err
+		}
+
+		s.logger.WithFields(logging.Fields{
+			"driver_id":  driverID,
+			"limit":      limit,
+			"rest_until": rest.RestUntil,
+		}).Info("Driver set offline to rest")
+		s.publishRest(rest)
+		rests = append(rests, rest)
+	}
+
+	return rests, nil
+}
+
+// limitReached returns the limit the hours reach, online time first, or "" if none
+func (s *DriverFatigueService) limitReached(h *models.DriverHours) models.DriverFatigueLimit {
+	switch {
+	case h.OnlineSeconds >= int64(s.cfg.MaxOnline.Seconds()):
+		return models.DriverFatigueLimitOnline
+	case h.DrivingSeconds >= int64(s.cfg.MaxDriving.Seconds()):
+		return models.DriverFatigueLimitDriving
+	default:
+		return ""
+	}
+}
+
+// latestRest returns the driver's rest ending last, or nil if the driver never rested
+func (s *DriverFatigueService) latestRest(ctx context.Context, driverID string) (*models.DriverRest, error) {
+	rest, err := s.drivers.GetLatestRest(ctx, driverID)
+	if err != nil {
+		var notFound *models.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return rest, nil
+}
+
+// windowStart returns the start of the driver's window ending now: the rolling window, cut short
+// by the end of the driver's last rest
+func (s *DriverFatigueService) windowStart(rest *models.DriverRest, now time.Time) time.Time {
+	start := now.Add(-s.cfg.Window)
+	if rest != nil && rest.RestUntil.After(start) {
+		start = rest.RestUntil
+	}
+	if start.After(now) {
+		start = now
+	}
+	return start
+}
+
+// publishRest publishes the rest as an event log and counts it
+func (s *DriverFatigueService) publishRest(rest *models.DriverRest) {
+	if s.metrics != nil {
+		s.metrics.RecordBusinessMetrics("driver_fatigue_rests_total", 1, map[string]string{
+			"limit": string(rest.Limit),
+		})
+	}
+	if s.events == nil {
+		return
+	}
+
+	entityType, entityID := models.EntityTypeDriver, rest.DriverID
+	eventLog := &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     "driver_fatigue_rest_started",
+		EventCategory: models.EventCategoryBusiness,
+		EntityType:    &entityType,
+		EntityID:      &entityID,
+		Severity:      models.EventSeverityWarn,
+		Message:       fmt.Sprintf("Driver set offline after reaching the %s limit", rest.Limit),
+		Timestamp:     rest.StartedAt,
+		CreatedAt:     rest.StartedAt,
+	}
+	eventLog.EventData, _ = json.Marshal(rest)
+	s.events.Publish(bus.TopicEventLog, eventLog)
+}
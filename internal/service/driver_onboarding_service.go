@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// driverOnboardingEntityType is the entity type of the event logs recorded for an onboarding
+const driverOnboardingEntityType = "driver_onboarding"
+
+// DriverOnboardingService moves drivers through onboarding: registered, documents submitted,
+// verified, training completed and activated. Drivers submit their documents and complete
+// training; admins verify the documents and activate drivers. Each stage reached is published
+// as an event log, and the funnel reports how many drivers of a cohort reach each stage.
+type DriverOnboardingService struct {
+	onboardings repository.DriverOnboardingRepository
+	cfg         config.OnboardingConfig
+	events      bus.Publisher
+	logger      *logging.Logger
+	now         func() time.Time
+}
+
+// NewDriverOnboardingService creates a new driver onboarding service. Events are published to
+// events, which may be nil.
+func NewDriverOnboardingService(onboardings repository.DriverOnboardingRepository, cfg config.OnboardingConfig, events bus.Publisher, logger *logging.Logger) *DriverOnboardingService {
+	return &DriverOnboardingService{
+		onboardings: onboardings,
+		cfg:         cfg,
+		events:      events,
+		logger:      logger.WithComponent("driver_onboarding_service"),
+		now:         time.Now,
+	}
+}
+
+// CreateDriver creates a new driver and starts its onboarding in one transaction, so a driver is
+// never stored without an onboarding
+func (s *DriverOnboardingService) CreateDriver(ctx context.Context, driver *models.Driver) (*models.DriverOnboarding, error) {
+	onboarding := models.NewDriverOnboarding(driver.ID, s.now())
+	if err := s.onboardings.CreateWithDriver(ctx, driver, onboarding); err != nil {
+		return nil, err
+	}
+
+	s.publish(onboarding, "", "Driver onboarding started")
+	return onboarding, nil
+}
+
+// Register starts the onboarding of an existing driver
+func (s *DriverOnboardingService) Register(ctx context.Context, driverID uuid.UUID) (*models.DriverOnboarding, error) {
+	onboarding := models.NewDriverOnboarding(driverID, s.now())
+	if err := s.onboardings.Create(ctx, onboarding); err != nil {
+		return nil, err
+	}
+
+	s.publish(onboarding, "", "Driver onboarding started")
+	return onboarding, nil
+}
+
+// Progress returns a driver's current stage, when each stage was reached and what is
+// outstanding before the next one
+func (s *DriverOnboardingService) Progress(ctx context.Context, driverID string) (*models.DriverOnboardingProgress, error) {
+	onboarding, err := s.onboardings.GetByDriverID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	return onboarding.Progress(), nil
+}
+
+// SubmitDocuments records documents submitted by a driver. The driver moves to
+// documents_submitted once every required document is in; documents may be submitted one at a
+// time.
+func (s *DriverOnboardingService) SubmitDocuments(ctx context.Context, driverID string, documents []models.OnboardingDocument) (*models.DriverOnboardingProgress, error) {
+	if err := models.ValidateOnboardingDocuments(documents); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "documents",
+			Message: err.Error(),
+		}
+	}
+
+	onboarding, err := s.onboardings.GetByDriverID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	if onboarding.Stage != models.OnboardingRegistered {
+		return nil, fmt.Errorf("%w: driver onboarding is %s and documents can no longer be submitted", models.ErrInvalidStatusTransition, onboarding.Stage)
+	}
+
+	onboarding.SubmitDocuments(documents, s.now())
+	if err := s.onboardings.Update(ctx, onboarding, models.OnboardingRegistered); err != nil {
+		return nil, err
+	}
+
+	if onboarding.Stage == models.OnboardingDocumentsSubmitted {
+		s.publish(onboarding, "", "Driver onboarding documents submitted")
+	}
+	return onboarding.Progress(), nil
+}
+
+// Verify records that reviewer verified the documents of a driver
+func (s *DriverOnboardingService) Verify(ctx context.Context, driverID, reviewer string) (*models.DriverOnboardingProgress, error) {
+	onboarding, err := s.transition(ctx, driverID, models.OnboardingVerified)
+	if err != nil {
+		return nil, err
+	}
+
+	onboarding.Verify(reviewer, s.now())
+	if err := s.onboardings.Update(ctx, onboarding, models.OnboardingDocumentsSubmitted); err != nil {
+		return nil, err
+	}
+
+	s.publish(onboarding, reviewer, "Driver onboarding documents verified")
+	return onboarding.Progress(), nil
+}
+
+// CompleteTraining records that a verified driver completed training
+func (s *DriverOnboardingService) CompleteTraining(ctx context.Context, driverID string) (*models.DriverOnboardingProgress, error) {
+	onboarding, err := s.transition(ctx, driverID, models.OnboardingTrainingCompleted)
+	if err != nil {
+		return nil, err
+	}
+
+	onboarding.CompleteTraining(s.now())
+	if err := s.onboardings.Update(ctx, onboarding, models.OnboardingVerified); err != nil {
+		return nil, err
+	}
+
+	s.publish(onboarding, "", "Driver onboarding training completed")
+	return onboarding.Progress(), nil
+}
+
+// Activate records that reviewer activated a driver who completed training, allowing the
+// driver to go online
+func (s *DriverOnboardingService) Activate(ctx context.Context, driverID, reviewer string) (*models.DriverOnboardingProgress, error) {
+	onboarding, err := s.transition(ctx, driverID, models.OnboardingActivated)
+	if err != nil {
+		return nil, err
+	}
+
+	onboarding.Activate(reviewer, s.now())
+	if err := s.onboardings.Update(ctx, onboarding, models.OnboardingTrainingCompleted); err != nil {
+		return nil, err
+	}
+
+	s.publish(onboarding, reviewer, "Driver activated")
+	return onboarding.Progress(), nil
+}
+
+// CheckActivated returns ErrDriverNotActivated unless the driver completed onboarding or
+// activation is not required. Drivers without an onboarding, created before onboarding existed
+// or directly in the repository, are not held back.
+func (s *DriverOnboardingService) CheckActivated(ctx context.Context, driverID string) error {
+	if !s.cfg.RequireActivation {
+		return nil
+	}
+
+	onboarding, err := s.onboardings.GetByDriverID(ctx, driverID)
+	if err != nil {
+		var notFound *models.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get driver onboarding: %w", err)
+	}
+	if onboarding.Stage != models.OnboardingActivated {
+		return fmt.Errorf("%w: onboarding is at stage %s", models.ErrDriverNotActivated, onboarding.Stage)
+	}
+	return nil
+}
+
+// Funnel reports how many of the drivers registered in [start, end) reached each stage, and
+// the conversion from each stage to the next
+func (s *DriverOnboardingService) Funnel(ctx context.Context, start, end time.Time) (*models.OnboardingFunnel, error) {
+	counts, err := s.onboardings.CountByStage(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[models.OnboardingStage]int64, len(counts))
+	for _, c := range counts {
+		current[c.Stage] += c.Count
+	}
+
+	funnel := &models.OnboardingFunnel{
+		Start:  start,
+		End:    end,
+		Stages: make([]models.OnboardingFunnelStage, len(models.OnboardingStages)),
+	}
+	// Drivers at a stage have reached every stage before it
+	var reached int64
+	for i := len(models.OnboardingStages) - 1; i >= 0; i-- {
+		stage := models.OnboardingStages[i]
+		reached += current[stage]
+		funnel.Stages[i] = models.OnboardingFunnelStage{
+			Stage:   stage,
+			Reached: reached,
+			Current: current[stage],
+		}
+	}
+	funnel.Total = reached
+
+	for i := range funnel.Stages {
+		previous := funnel.Total
+		if i > 0 {
+			previous = funnel.Stages[i-1].Reached
+		}
+		funnel.Stages[i].Conversion = conversionRate(funnel.Stages[i].Reached, previous)
+		funnel.Stages[i].OverallConversion = conversionRate(funnel.Stages[i].Reached, funnel.Total)
+	}
+	return funnel, nil
+}
+
+// conversionRate returns reached as a share of of, rounded to four decimals; zero when of is
+func conversionRate(reached, of int64) float64 {
+	if of == 0 {
+		return 0
+	}
+	return math.Round(float64(reached)/float64(of)*10000) / 10000
+}
+
+// transition loads the onboarding of a driver and checks that it can move to stage. A driver
+// not yet at the stage before is still missing requirements; one past it is a conflict.
+func (s *DriverOnboardingService) transition(ctx context.Context, driverID string, stage models.OnboardingStage) (*models.DriverOnboarding, error) {
+	onboarding, err := s.onboardings.GetByDriverID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	if onboarding.Stage.CanTransitionTo(stage) {
+		return onboarding, nil
+	}
+	if onboarding.Stage.Reached(stage) {
+		return nil, fmt.Errorf("%w: driver onboarding is %s and cannot become %s", models.ErrInvalidStatusTransition, onboarding.Stage, stage)
+	}
+
+	outstanding := onboarding.OutstandingRequirements()
+	descriptions := make([]string, len(outstanding))
+	for i, requirement := range outstanding {
+		descriptions[i] = requirement.Description
+	}
+	return nil, fmt.Errorf("%w: driver onboarding is %s and cannot become %s until: %s",
+		models.ErrOnboardingRequirementsOpen, onboarding.Stage, stage, strings.Join(descriptions, "; "))
+}
+
+// publish logs the stage a driver reached and publishes it as a business event log on the driver
+func (s *DriverOnboardingService) publish(onboarding *models.DriverOnboarding, actor, message string) {
+	eventType := "driver_onboarding_" + string(onboarding.Stage)
+	data := map[string]interface{}{
+		"driver_id": onboarding.DriverID,
+		"stage":     onboarding.Stage,
+		"documents": onboarding.Documents,
+	}
+	if actor != "" {
+		data["reviewed_by"] = actor
+	}
+
+	s.logger.WithFields(logging.Fields{
+		"event_type": eventType,
+		"driver_id":  onboarding.DriverID.String(),
+		"stage":      string(onboarding.Stage),
+	}).Info(message)
+
+	if s.events == nil {
+		return
+	}
+
+	entityType, entityID := driverOnboardingEntityType, onboarding.DriverID
+	eventLog := &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     eventType,
+		EventCategory: models.EventCategoryBusiness,
+		EntityType:    &entityType,
+		EntityID:      &entityID,
+		Severity:      models.EventSeverityInfo,
+		Message:       message,
+		Timestamp:     time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	eventLog.EventData, _ = json.Marshal(data)
+	s.events.Publish(bus.TopicEventLog, eventLog)
+}
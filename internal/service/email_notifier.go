@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/notification"
+	"actor-model-observability/internal/repository"
+)
+
+// ErrEmailQueueFull is returned when an email is dropped because the send queue is full
+var ErrEmailQueueFull = errors.New("email queue is full")
+
+// weeklyEarningsPageSize is how many drivers' statistics are read at a time for the weekly
+// earnings summaries
+const weeklyEarningsPageSize = 200
+
+// EmailStats counts the emails the notifier has handled since it started
+type EmailStats struct {
+	Queued     int64 `json:"queued"`
+	Sent       int64 `json:"sent"`
+	Retried    int64 `json:"retried"`     // failed attempts that were retried
+	Failed     int64 `json:"failed"`      // emails given up on after every attempt failed
+	Dropped    int64 `json:"dropped"`     // emails dropped because the queue was full
+	QueueDepth int   `json:"queue_depth"` // emails waiting to be sent
+}
+
+// emailJob is a rendered email waiting to be sent and the attempts made so far
+type emailJob struct {
+	message  *notification.Message
+	attempts int
+}
+
+// EmailNotifier emails passengers and drivers: a receipt when a passenger's trip completes,
+// password reset links and drivers' weekly earnings summaries. Emails are rendered when
+// queued and sent by a pool of workers, failed sends being retried with exponential backoff.
+// The queue is held in memory, so emails still queued or waiting on a retry when the notifier
+// stops are lost.
+type EmailNotifier struct {
+	renderer   *notification.Renderer
+	sender     notification.Sender
+	users      repository.UserRepository
+	passengers repository.PassengerRepository
+	drivers    repository.DriverRepository
+	metrics    BusinessMetricsRecorder
+	policy     actor.RetryPolicy
+	receipts   bool
+	workers    int
+	queue      chan *emailJob
+	rng        *rand.Rand
+	rngMutex   sync.Mutex
+	queued     atomic.Int64
+	sent       atomic.Int64
+	retried    atomic.Int64
+	failed     atomic.Int64
+	dropped    atomic.Int64
+	logger     *logging.Logger
+	ctx        context.Context
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
+}
+
+// NewEmailNotifier creates a new email notifier sending through sender. metrics may be nil.
+func NewEmailNotifier(
+	sender notification.Sender,
+	users repository.UserRepository,
+	passengers repository.PassengerRepository,
+	drivers repository.DriverRepository,
+	metrics BusinessMetricsRecorder,
+	cfg config.EmailConfig,
+	logger *logging.Logger,
+) (*EmailNotifier, error) {
+	renderer, err := notification.NewRenderer()
+	if err != nil {
+		return nil, err
+	}
+	return &EmailNotifier{
+		renderer:   renderer,
+		sender:     sender,
+		users:      users,
+		passengers: passengers,
+		drivers:    drivers,
+		metrics:    metrics,
+		policy: actor.RetryPolicy{
+			MaxAttempts:    cfg.MaxAttempts,
+			InitialBackoff: cfg.InitialBackoff,
+			MaxBackoff:     cfg.MaxBackoff,
+			Multiplier:     2,
+			Jitter:         0.1,
+		},
+		receipts: cfg.Receipts,
+		workers:  cfg.Workers,
+		queue:    make(chan *emailJob, cfg.QueueSize),
+		rng:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		logger:   logger.WithComponent("email_notifier"),
+	}, nil
+}
+
+// Start starts the workers sending queued emails
+func (n *EmailNotifier) Start(ctx context.Context) error {
+	n.ctx, n.cancel = context.WithCancel(ctx)
+
+	for i := 0; i < n.workers; i++ {
+		n.wg.Add(1)
+		go n.worker()
+	}
+
+	n.logger.WithField("workers", n.workers).Info("Email notifier started")
+	return nil
+}
+
+// Stop stops the workers once the emails being sent are done
+func (n *EmailNotifier) Stop() error {
+	if n.cancel != nil {
+		n.cancel()
+	}
+	n.wg.Wait()
+
+	n.logger.WithField("unsent", len(n.queue)).Info("Email notifier stopped")
+	return nil
+}
+
+// Stats returns the emails handled since the notifier started
+func (n *EmailNotifier) Stats() EmailStats {
+	return EmailStats{
+		Queued:     n.queued.Load(),
+		Sent:       n.sent.Load(),
+		Retried:    n.retried.Load(),
+		Failed:     n.failed.Load(),
+		Dropped:    n.dropped.Load(),
+		QueueDepth: len(n.queue),
+	}
+}
+
+// PublishTripEvent emails the passenger a receipt when a trip completes, if receipts are
+// enabled. Other events are ignored.
+func (n *EmailNotifier) PublishTripEvent(ctx context.Context, event models.WebhookEvent, trip *models.Trip) error {
+	if !n.receipts || event != models.WebhookEventTripCompleted {
+		return nil
+	}
+
+	passenger, err := n.passengers.GetByID(ctx, trip.PassengerID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get passenger for receipt: %w", err)
+	}
+	user, err := n.users.GetByID(ctx, passenger.UserID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get passenger user for receipt: %w", err)
+	}
+
+	data := notification.ReceiptData{
+		Name:        user.Name,
+		TripID:      trip.ID.String(),
+		CompletedAt: trip.UpdatedAt,
+		Pickup:      fmt.Sprintf("%.5f, %.5f", trip.PickupLatitude, trip.PickupLongitude),
+		Destination: fmt.Sprintf("%.5f, %.5f", trip.DestinationLatitude, trip.DestinationLongitude),
+	}
+	if trip.CompletedAt != nil {
+		data.CompletedAt = *trip.CompletedAt
+	}
+	if trip.PickupAddress != nil && *trip.PickupAddress != "" {
+		data.Pickup = *trip.PickupAddress
+	}
+	if trip.DestinationAddress != nil && *trip.DestinationAddress != "" {
+		data.Destination = *trip.DestinationAddress
+	}
+	if trip.DistanceKm != nil {
+		data.DistanceKm = *trip.DistanceKm
+	}
+	if trip.DurationMinutes != nil {
+		data.DurationMinutes = *trip.DurationMinutes
+	}
+	if trip.FareAmount != nil {
+		data.Fare = *trip.FareAmount
+	}
+
+	return n.enqueue(notification.TemplateReceipt, user.Email, data)
+}
+
+// SendPasswordReset emails a user the link resetting their password, valid until expiresAt
+func (n *EmailNotifier) SendPasswordReset(user *models.User, resetURL string, expiresAt time.Time) error {
+	return n.enqueue(notification.TemplatePasswordReset, user.Email, notification.PasswordResetData{
+		Name:      user.Name,
+		ResetURL:  resetURL,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// SendWeeklyEarnings emails every driver who completed a trip in the week starting at
+// weekStart a summary of it, and returns how many summaries were queued. Drivers whose
+// summary could not be queued are logged and skipped.
+func (n *EmailNotifier) SendWeeklyEarnings(ctx context.Context, weekStart time.Time) (int, error) {
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	filter := models.DriverStatsFilter{
+		From:       weekStart,
+		To:         weekEnd,
+		SortBy:     models.DriverStatsSortEarnings,
+		Descending: true,
+		Limit:      weeklyEarningsPageSize,
+	}
+
+	queued := 0
+	for {
+		stats, total, err := n.drivers.GetDriverStats(ctx, filter)
+		if err != nil {
+			return queued, fmt.Errorf("failed to get driver statistics: %w", err)
+		}
+
+		for _, driver := range stats {
+			if driver.TripsCompleted == 0 {
+				continue
+			}
+			logger := n.logger.WithField("driver_id", driver.DriverID.String())
+
+			user, err := n.users.GetByID(ctx, driver.UserID.String())
+			if err != nil {
+				logger.WithError(err).Warn("Failed to get driver user for weekly earnings")
+				continue
+			}
+			err = n.enqueue(notification.TemplateWeeklyEarnings, user.Email, notification.WeeklyEarningsData{
+				Name:           driver.Name,
+				WeekStart:      weekStart,
+				WeekEnd:        weekEnd.AddDate(0, 0, -1),
+				Earnings:       driver.Earnings,
+				TripsCompleted: driver.TripsCompleted,
+				TripsCancelled: driver.TripsCancelled,
+				OnlineHours:    driver.OnlineHours,
+				Rating:         driver.AverageRating,
+			})
+			if err != nil {
+				logger.WithError(err).Warn("Failed to queue weekly earnings")
+				continue
+			}
+			queued++
+		}
+
+		filter.Offset += len(stats)
+		if len(stats) == 0 || int64(filter.Offset) >= total {
+			break
+		}
+	}
+
+	n.logger.WithFields(logging.Fields{
+		"week_start": weekStart.Format("2006-01-02"),
+		"queued":     queued,
+	}).Info("Weekly earnings summaries queued")
+	return queued, nil
+}
+
+// enqueue renders an email and queues it to be sent. Nothing blocks on a full queue: the email
+// is dropped and ErrEmailQueueFull returned.
+func (n *EmailNotifier) enqueue(tmpl notification.Template, to string, data interface{}) error {
+	message, err := n.renderer.Render(tmpl, to, data)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case n.queue <- &emailJob{message: message}:
+		n.queued.Add(1)
+		return nil
+	default:
+		n.dropped.Add(1)
+		n.recordMessage(tmpl, "dropped")
+		return ErrEmailQueueFull
+	}
+}
+
+// worker sends queued emails until the notifier is stopped
+func (n *EmailNotifier) worker() {
+	defer n.wg.Done()
+
+	for {
+		select {
+		case job := <-n.queue:
+			n.attempt(job)
+		case <-n.ctx.Done():
+			return
+		}
+	}
+}
+
+// attempt sends an email once, scheduling a retry while attempts remain
+func (n *EmailNotifier) attempt(job *emailJob) {
+	tmpl := job.message.Template
+	logger := n.logger.WithFields(logging.Fields{
+		"template": string(tmpl),
+		"to":       job.message.To,
+	})
+
+	job.attempts++
+	start := time.Now()
+	err := n.sender.Send(n.ctx, job.message)
+	if n.metrics != nil {
+		result := "success"
+		if err != nil {
+			result = "error"
+		}
+		tags := map[string]string{"template": string(tmpl), "result": result}
+		n.metrics.RecordBusinessMetrics("email_send_attempts_total", 1, tags)
+		n.metrics.RecordBusinessMetrics("email_send_duration_seconds", time.Since(start).Seconds(), tags)
+	}
+
+	switch {
+	case err == nil:
+		n.sent.Add(1)
+		n.recordMessage(tmpl, "sent")
+	case job.attempts >= n.policy.MaxAttempts:
+		n.failed.Add(1)
+		n.recordMessage(tmpl, "failed")
+		logger.WithError(err).WithField("attempts", job.attempts).Warn("Email send failed, giving up")
+	default:
+		n.retried.Add(1)
+		n.rngMutex.Lock()
+		backoff := n.policy.Backoff(job.attempts, n.rng)
+		n.rngMutex.Unlock()
+		logger.WithError(err).WithFields(logging.Fields{
+			"attempts": job.attempts,
+			"backoff":  backoff,
+		}).Debug("Email send failed, retrying")
+
+		time.AfterFunc(backoff, func() {
+			if n.ctx.Err() != nil {
+				return
+			}
+			select {
+			case n.queue <- job:
+			default:
+				n.dropped.Add(1)
+				n.recordMessage(tmpl, "dropped")
+				logger.Warn("Email queue is full, dropping retry")
+			}
+		})
+	}
+}
+
+// recordMessage counts an email that was sent, given up on or dropped
+func (n *EmailNotifier) recordMessage(tmpl notification.Template, outcome string) {
+	if n.metrics == nil {
+		return
+	}
+	n.metrics.RecordBusinessMetrics("email_messages_total", 1, map[string]string{
+		"template": string(tmpl),
+		"outcome":  outcome,
+	})
+}
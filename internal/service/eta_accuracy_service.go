@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/geohash"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/reporting"
+	"actor-model-observability/internal/repository"
+)
+
+// ETAAccuracyService closes the loop on the ETA model. From the trip_status topic of the event
+// bus it records the ETA to the pickup when a driver is matched and the driver's arrival, then
+// recomputes the accuracy of the recent ETAs every interval, by pickup zone and hour of day.
+// With auto-adjustment enabled, every recompute moves the model's average speed toward the speed
+// drivers actually drove at. Each instance records the trips it matches and adjusts its own model
+// from the accuracy of every instance's ETAs.
+type ETAAccuracyService struct {
+	predictions repository.ETAPredictionRepository
+	drivers     repository.DriverRepository
+	model       *ETAModel
+	cfg         config.ETAConfig
+	grid        geohash.Grid
+	timezone    string
+	location    *time.Location
+	logger      *logging.Logger
+	now         func() time.Time
+
+	latest *models.ETAAccuracyReport
+	mu     sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewETAAccuracyService creates a new ETA accuracy service adjusting model. Hours of day are in
+// the report timezone.
+func NewETAAccuracyService(
+	predictions repository.ETAPredictionRepository,
+	drivers repository.DriverRepository,
+	model *ETAModel,
+	cfg config.ETAConfig,
+	reportingCfg config.ReportingConfig,
+	logger *logging.Logger,
+) *ETAAccuracyService {
+	// The precision and timezone are validated with the config
+	grid, _ := geohash.NewGrid(cfg.ZonePrecision)
+	location, err := reporting.LoadLocation(reportingCfg.Timezone)
+	timezone := reportingCfg.Timezone
+	if err != nil {
+		location, timezone = time.UTC, "UTC"
+	}
+	return &ETAAccuracyService{
+		predictions: predictions,
+		drivers:     drivers,
+		model:       model,
+		cfg:         cfg,
+		grid:        grid,
+		timezone:    timezone,
+		location:    location,
+		logger:      logger.WithComponent("eta_accuracy_service"),
+		now:         time.Now,
+	}
+}
+
+// Topics returns the event bus topics the service handles
+func (s *ETAAccuracyService) Topics() []string {
+	return []string{bus.TopicTripStatus}
+}
+
+// HandleMessage records the ETA of a trip matched with a driver, and the driver's arrival at the
+// pickup. Trips started without reporting the arrival are taken to have arrived at pickup.
+func (s *ETAAccuracyService) HandleMessage(msg bus.Message) {
+	trip, ok := msg.Payload.(*models.Trip)
+	if !ok || trip.DriverID == nil {
+		return
+	}
+	ctx := context.Background()
+
+	switch trip.Status {
+	case models.TripStatusMatched:
+		at := msg.PublishedAt
+		if trip.MatchedAt != nil {
+			at = *trip.MatchedAt
+		}
+		s.predict(ctx, trip, at)
+	case models.TripStatusDriverArrived:
+		s.arrive(ctx, trip, msg.PublishedAt)
+	case models.TripStatusInProgress:
+		at := msg.PublishedAt
+		if trip.PickupAt != nil {
+			at = *trip.PickupAt
+		}
+		s.arrive(ctx, trip, at)
+	}
+}
+
+// predict records the ETA of the trip's driver to the pickup from where the driver is
+func (s *ETAAccuracyService) predict(ctx context.Context, trip *models.Trip, at time.Time) {
+	driver, err := s.drivers.GetByID(ctx, trip.DriverID.String())
+	if err != nil {
+		s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Warn("Failed to get matched driver for ETA prediction")
+		return
+	}
+	if driver.CurrentLatitude == nil || driver.CurrentLongitude == nil {
+		return
+	}
+
+	driverLocation := models.Location{Latitude: *driver.CurrentLatitude, Longitude: *driver.CurrentLongitude}
+	distance := driverLocation.DistanceTo(models.Location{Latitude: trip.PickupLatitude, Longitude: trip.PickupLongitude})
+	prediction := &models.ETAPrediction{
+		TripID:           trip.ID,
+		DriverID:         driver.ID,
+		Zone:             s.grid.Geohash(s.grid.Locate(trip.PickupLatitude, trip.PickupLongitude)),
+		Hour:             at.In(s.location).Hour(),
+		DistanceKm:       math.Round(distance*1000) / 1000,
+		SpeedKmh:         s.model.SpeedKmh(),
+		PredictedSeconds: s.model.Seconds(distance),
+		PredictedAt:      at,
+	}
+	// A trip published as matched again keeps its first ETA
+	if err := s.predictions.Create(ctx, prediction); err != nil && !errors.Is(err, models.ErrDuplicateEntry) {
+		s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Error("Failed to record ETA prediction")
+	}
+}
+
+// arrive records the arrival of the trip's driver at the pickup, unless it is recorded already or
+// the trip has no ETA
+func (s *ETAAccuracyService) arrive(ctx context.Context, trip *models.Trip, at time.Time) {
+	prediction, err := s.predictions.GetByTrip(ctx, trip.ID.String())
+	var notFound *models.NotFoundError
+	if errors.As(err, &notFound) || (err == nil && prediction.ArrivedAt != nil) {
+		return
+	}
+	if err != nil {
+		s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Error("Failed to get ETA prediction")
+		return
+	}
+
+	prediction.Arrive(at)
+	if err := s.predictions.RecordArrival(ctx, prediction); err != nil && !errors.As(err, &notFound) {
+		s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Error("Failed to record ETA arrival")
+	}
+}
+
+// Compute returns the accuracy of the ETAs predicted in [from, to) whose drivers arrived
+func (s *ETAAccuracyService) Compute(ctx context.Context, from, to time.Time) (*models.ETAAccuracyReport, error) {
+	if !from.Before(to) {
+		return nil, &models.ValidationError{
+			Field:   "from",
+			Message: "must be before to",
+		}
+	}
+
+	buckets, err := s.predictions.GetAccuracy(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	if buckets == nil {
+		buckets = []*models.ETAAccuracy{}
+	}
+
+	// The overall metrics are the means of the buckets' weighted by their samples
+	var overall models.ETAAccuracyStats
+	for _, bucket := range buckets {
+		bucket.ObservedSpeedKmh = observedSpeed(bucket.AvgDistanceKm, bucket.AvgActualSeconds)
+
+		n := float64(bucket.Samples)
+		overall.Samples += bucket.Samples
+		overall.MAESeconds += bucket.MAESeconds * n
+		overall.BiasSeconds += bucket.BiasSeconds * n
+		overall.AvgPredictedSeconds += bucket.AvgPredictedSeconds * n
+		overall.AvgActualSeconds += bucket.AvgActualSeconds * n
+		overall.AvgDistanceKm += bucket.AvgDistanceKm * n
+	}
+	if overall.Samples > 0 {
+		n := float64(overall.Samples)
+		overall.MAESeconds /= n
+		overall.BiasSeconds /= n
+		overall.AvgPredictedSeconds /= n
+		overall.AvgActualSeconds /= n
+		overall.AvgDistanceKm /= n
+		overall.ObservedSpeedKmh = observedSpeed(overall.AvgDistanceKm, overall.AvgActualSeconds)
+	}
+
+	return &models.ETAAccuracyReport{
+		From:       from,
+		To:         to,
+		Timezone:   s.timezone,
+		SpeedKmh:   s.model.SpeedKmh(),
+		Overall:    overall,
+		Buckets:    buckets,
+		ComputedAt: s.now(),
+	}, nil
+}
+
+// Latest returns the accuracy last computed over the configured window, nil before the first
+// recompute
+func (s *ETAAccuracyService) Latest() *models.ETAAccuracyReport {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest
+}
+
+// Refresh recomputes the accuracy over the configured window and, with auto-adjustment enabled,
+// adjusts the model's average speed from it
+func (s *ETAAccuracyService) Refresh(ctx context.Context) (*models.ETAAccuracyReport, error) {
+	now := s.now()
+	report, err := s.Compute(ctx, now.Add(-s.cfg.AccuracyWindow), now)
+	if err != nil {
+		return nil, err
+	}
+	if s.cfg.AutoAdjust {
+		s.adjust(report.Overall)
+		report.SpeedKmh = s.model.SpeedKmh()
+	}
+
+	s.mu.Lock()
+	s.latest = report
+	s.mu.Unlock()
+	return report, nil
+}
+
+// adjust moves the model's average speed part of the way toward the observed speed, once there
+// are enough samples
+func (s *ETAAccuracyService) adjust(overall models.ETAAccuracyStats) {
+	if overall.Samples < int64(s.cfg.MinSamples) || overall.ObservedSpeedKmh <= 0 {
+		return
+	}
+
+	current := s.model.SpeedKmh()
+	next := current + s.cfg.AdjustRate*(overall.ObservedSpeedKmh-current)
+	next = math.Max(s.cfg.MinSpeedKmh, math.Min(s.cfg.MaxSpeedKmh, next))
+	next = math.Round(next*100) / 100
+	if next == current {
+		return
+	}
+
+	s.model.SetSpeedKmh(next)
+	s.logger.WithFields(logging.Fields{
+		"previous_speed_kmh": current,
+		"speed_kmh":          next,
+		"observed_speed_kmh": overall.ObservedSpeedKmh,
+		"samples":            overall.Samples,
+	}).Info("ETA model average speed adjusted")
+}
+
+// observedSpeed returns the speed in km/h of covering distanceKm in seconds, 0 without time
+func observedSpeed(distanceKm, seconds float64) float64 {
+	if seconds <= 0 {
+		return 0
+	}
+	return math.Round(distanceKm/seconds*3600*100) / 100
+}
+
+// Start starts recomputing the accuracy every interval
+func (s *ETAAccuracyService) Start(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go s.refreshLoop()
+
+	s.logger.Info("ETA accuracy service started")
+	return nil
+}
+
+// Stop stops the refresh loop
+func (s *ETAAccuracyService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("ETA accuracy service stopped")
+	return nil
+}
+
+// refreshLoop recomputes the accuracy now and on every interval
+func (s *ETAAccuracyService) refreshLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.AccuracyInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Refresh(s.ctx); err != nil && s.ctx.Err() == nil {
+			s.logger.WithError(err).Error("Failed to compute ETA accuracy")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"math"
+	"sync/atomic"
+)
+
+// ETAModel estimates travel times from the straight-line distance left at an average speed, which
+// the ETA accuracy feedback loop may adjust at runtime. It is safe for concurrent use.
+type ETAModel struct {
+	speedBits atomic.Uint64 // float64 bits of the speed in km/h
+}
+
+// NewETAModel creates an ETA model assuming speedKmh
+func NewETAModel(speedKmh float64) *ETAModel {
+	m := &ETAModel{}
+	m.SetSpeedKmh(speedKmh)
+	return m
+}
+
+// SpeedKmh returns the average speed the model assumes
+func (m *ETAModel) SpeedKmh() float64 {
+	return math.Float64frombits(m.speedBits.Load())
+}
+
+// SetSpeedKmh changes the average speed the model assumes
+func (m *ETAModel) SetSpeedKmh(speedKmh float64) {
+	m.speedBits.Store(math.Float64bits(speedKmh))
+}
+
+// Seconds returns the seconds it takes to travel distanceKm
+func (m *ETAModel) Seconds(distanceKm float64) int {
+	return int(math.Round(distanceKm / m.SpeedKmh() * 3600))
+}
@@ -0,0 +1,305 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// FareDisputeService runs the fare dispute workflow. Passengers open a dispute on a completed
+// trip; admins move it under review and then resolve it with an adjusted fare, which is added
+// to the trip's fare adjustments ledger, or reject it. Every step is published as a business
+// event log for auditing, and decisions are sent to webhook subscribers.
+type FareDisputeService struct {
+	disputes repository.FareDisputeRepository
+	trips    repository.TripRepository
+	notifier FareDisputeNotifier
+	events   bus.Publisher
+	logger   *logging.Logger
+	now      func() time.Time
+}
+
+// NewFareDisputeService creates a new fare dispute service. Decisions are sent to notifier and
+// audit events are published to events; both may be nil.
+func NewFareDisputeService(disputes repository.FareDisputeRepository, trips repository.TripRepository, notifier FareDisputeNotifier, events bus.Publisher, logger *logging.Logger) *FareDisputeService {
+	return &FareDisputeService{
+		disputes: disputes,
+		trips:    trips,
+		notifier: notifier,
+		events:   events,
+		logger:   logger.WithComponent("fare_dispute_service"),
+		now:      time.Now,
+	}
+}
+
+// OpenDispute opens a dispute of the fare of a completed trip on behalf of its passenger.
+// requestedFare is the fare the passenger expected and may be nil.
+func (s *FareDisputeService) OpenDispute(ctx context.Context, tripID, passengerID, reason string, requestedFare *float64) (*models.FareDispute, error) {
+	trip, err := s.trips.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if trip.PassengerID.String() != passengerID {
+		return nil, models.ErrUnauthorizedOperation
+	}
+	if !trip.IsCompleted() || trip.FareAmount == nil {
+		return nil, models.ErrTripNotDisputable
+	}
+
+	now := s.now()
+	dispute := &models.FareDispute{
+		ID:            uuid.New(),
+		TripID:        trip.ID,
+		PassengerID:   trip.PassengerID,
+		Reason:        reason,
+		OriginalFare:  *trip.FareAmount,
+		RequestedFare: requestedFare,
+		Status:        models.FareDisputeOpen,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := dispute.Validate(); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "dispute",
+			Message: err.Error(),
+		}
+	}
+
+	if err := s.disputes.CreateDispute(ctx, dispute); err != nil {
+		return nil, err
+	}
+
+	s.publish("fare_dispute_opened", dispute, "", map[string]interface{}{
+		"reason":         dispute.Reason,
+		"requested_fare": dispute.RequestedFare,
+	}, "Fare dispute opened")
+	return dispute, nil
+}
+
+// GetDispute returns a fare dispute by ID
+func (s *FareDisputeService) GetDispute(ctx context.Context, id string) (*models.FareDispute, error) {
+	return s.disputes.GetDispute(ctx, id)
+}
+
+// ListDisputes returns the matching disputes, oldest first, and the total number of matches
+func (s *FareDisputeService) ListDisputes(ctx context.Context, filter models.FareDisputeFilter) ([]*models.FareDispute, int64, error) {
+	return s.disputes.ListDisputes(ctx, filter)
+}
+
+// ListAdjustments returns the trip's fare adjustments ledger, oldest first
+func (s *FareDisputeService) ListAdjustments(ctx context.Context, tripID string) ([]*models.FareAdjustment, error) {
+	if _, err := s.trips.GetByID(ctx, tripID); err != nil {
+		return nil, err
+	}
+	return s.disputes.ListAdjustmentsByTrip(ctx, tripID)
+}
+
+// StartReview moves an open dispute under review by reviewer
+func (s *FareDisputeService) StartReview(ctx context.Context, id, reviewer string) (*models.FareDispute, error) {
+	dispute, err := s.transition(ctx, id, models.FareDisputeUnderReview)
+	if err != nil {
+		return nil, err
+	}
+
+	dispute.StartReview(reviewer, s.now())
+	if err := s.disputes.UpdateDispute(ctx, dispute, models.FareDisputeOpen); err != nil {
+		return nil, err
+	}
+
+	s.publish("fare_dispute_review_started", dispute, reviewer, nil, "Fare dispute review started")
+	return dispute, nil
+}
+
+// Resolve closes a dispute under review and charges the passenger adjustedFare from now on.
+// The change from the trip's current fare is recorded in its fare adjustments ledger.
+func (s *FareDisputeService) Resolve(ctx context.Context, id, reviewer string, adjustedFare float64, note string) (*models.FareDispute, error) {
+	dispute, err := s.transition(ctx, id, models.FareDisputeResolved)
+	if err != nil {
+		return nil, err
+	}
+
+	adjustedFare = roundFare(adjustedFare)
+	if adjustedFare < 0 {
+		return nil, &models.ValidationError{
+			Field:   "adjusted_fare",
+			Message: "adjusted fare must not be negative",
+		}
+	}
+
+	trip, err := s.trips.GetByID(ctx, dispute.TripID.String())
+	if err != nil {
+		return nil, err
+	}
+	previousFare := dispute.OriginalFare
+	if trip.FareAmount != nil {
+		previousFare = *trip.FareAmount
+	}
+	if adjustedFare == previousFare {
+		return nil, &models.ValidationError{
+			Field:   "adjusted_fare",
+			Message: "adjusted fare equals the current fare; reject the dispute to keep it",
+		}
+	}
+
+	now := s.now()
+	from := dispute.Status
+	dispute.Resolve(reviewer, adjustedFare, note, now)
+	if err := dispute.Validate(); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "note",
+			Message: err.Error(),
+		}
+	}
+
+	adjustment := &models.FareAdjustment{
+		ID:           uuid.New(),
+		TripID:       dispute.TripID,
+		DisputeID:    dispute.ID,
+		PreviousFare: previousFare,
+		NewFare:      adjustedFare,
+		Amount:       roundFare(adjustedFare - previousFare),
+		Reason:       note,
+		AdjustedBy:   reviewer,
+		CreatedAt:    now,
+	}
+	if adjustment.Reason == "" {
+		adjustment.Reason = dispute.Reason
+	}
+
+	if err := s.disputes.ResolveDispute(ctx, dispute, from, adjustment); err != nil {
+		return nil, err
+	}
+
+	s.publish("fare_dispute_resolved", dispute, reviewer, map[string]interface{}{
+		"adjustment_id": adjustment.ID,
+		"previous_fare": adjustment.PreviousFare,
+		"new_fare":      adjustment.NewFare,
+		"amount":        adjustment.Amount,
+		"note":          note,
+	}, "Fare dispute resolved with a fare adjustment")
+
+	trip.FareAmount = &adjustedFare
+	trip.UpdatedAt = now
+	s.notify(ctx, models.WebhookEventFareDisputeResolved, trip, dispute)
+	return dispute, nil
+}
+
+// Reject closes a dispute leaving the fare unchanged. A note explaining the decision is required.
+func (s *FareDisputeService) Reject(ctx context.Context, id, reviewer, note string) (*models.FareDispute, error) {
+	if note == "" {
+		return nil, &models.ValidationError{
+			Field:   "note",
+			Message: "a note explaining the rejection is required",
+		}
+	}
+
+	dispute, err := s.transition(ctx, id, models.FareDisputeRejected)
+	if err != nil {
+		return nil, err
+	}
+
+	from := dispute.Status
+	dispute.Reject(reviewer, note, s.now())
+	if err := dispute.Validate(); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "note",
+			Message: err.Error(),
+		}
+	}
+	if err := s.disputes.UpdateDispute(ctx, dispute, from); err != nil {
+		return nil, err
+	}
+
+	s.publish("fare_dispute_rejected", dispute, reviewer, map[string]interface{}{
+		"note": note,
+	}, "Fare dispute rejected")
+
+	trip, err := s.trips.GetByID(ctx, dispute.TripID.String())
+	if err != nil {
+		s.logger.WithError(err).WithField("dispute_id", dispute.ID.String()).Error("Failed to load trip for fare dispute notification")
+		return dispute, nil
+	}
+	s.notify(ctx, models.WebhookEventFareDisputeRejected, trip, dispute)
+	return dispute, nil
+}
+
+// transition loads the dispute and checks that it can move to status
+func (s *FareDisputeService) transition(ctx context.Context, id string, status models.FareDisputeStatus) (*models.FareDispute, error) {
+	dispute, err := s.disputes.GetDispute(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !dispute.Status.CanTransitionTo(status) {
+		return nil, fmt.Errorf("%w: fare dispute is %s and cannot become %s", models.ErrInvalidStatusTransition, dispute.Status, status)
+	}
+	return dispute, nil
+}
+
+// notify sends a dispute decision to webhook subscribers. The decision is already stored,
+// so failures are logged rather than returned.
+func (s *FareDisputeService) notify(ctx context.Context, event models.WebhookEvent, trip *models.Trip, dispute *models.FareDispute) {
+	if s.notifier == nil {
+		return
+	}
+	if err := s.notifier.PublishFareDisputeEvent(ctx, event, trip, dispute); err != nil {
+		s.logger.WithError(err).WithFields(logging.Fields{
+			"dispute_id": dispute.ID.String(),
+			"event":      string(event),
+		}).Error("Failed to notify fare dispute decision")
+	}
+}
+
+// publish logs a dispute workflow step and publishes it as a business event log on the dispute
+func (s *FareDisputeService) publish(eventType string, dispute *models.FareDispute, actor string, data map[string]interface{}, message string) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["dispute_id"] = dispute.ID
+	data["trip_id"] = dispute.TripID
+	data["passenger_id"] = dispute.PassengerID
+	data["status"] = dispute.Status
+	data["original_fare"] = dispute.OriginalFare
+	if actor != "" {
+		data["reviewed_by"] = actor
+	}
+
+	s.logger.WithFields(logging.Fields{
+		"event_type": eventType,
+		"dispute_id": dispute.ID.String(),
+		"trip_id":    dispute.TripID.String(),
+		"status":     string(dispute.Status),
+	}).Info(message)
+
+	if s.events == nil {
+		return
+	}
+
+	entityType, entityID := "fare_dispute", dispute.ID
+	eventLog := &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     eventType,
+		EventCategory: models.EventCategoryBusiness,
+		EntityType:    &entityType,
+		EntityID:      &entityID,
+		Severity:      models.EventSeverityInfo,
+		Message:       message,
+		Timestamp:     time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	eventLog.EventData, _ = json.Marshal(data)
+	s.events.Publish(bus.TopicEventLog, eventLog)
+}
+
+// roundFare rounds a fare to cents, the precision fares are stored with
+func roundFare(fare float64) float64 {
+	return math.Round(fare*100) / 100
+}
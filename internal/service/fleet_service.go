@@ -0,0 +1,542 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/reporting"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// fleetAPIKeyBytes is the entropy of a fleet API key
+	fleetAPIKeyBytes = 32
+	// fleetImportMaxRows caps the rows of one bulk driver status import
+	fleetImportMaxRows = 5000
+)
+
+// Column headers of the fleet CSV files. The driver export has the driver_id and status
+// columns the status import reads, so an edited export can be imported back.
+var (
+	fleetDriverColumns = []string{
+		"driver_id", "user_id", "license_number", "vehicle_type", "vehicle_plate", "status", "rating", "total_trips",
+	}
+	fleetTripColumns = []string{
+		"trip_id", "driver_id", "passenger_id", "status", "requested_at", "completed_at", "cancelled_at",
+		"distance_km", "duration_minutes", "fare_amount",
+	}
+	fleetEarningsColumns = []string{
+		"driver_id", "vehicle_plate", "trips_completed", "trips_cancelled", "earnings",
+	}
+)
+
+// FleetService lets fleet partners manage the drivers they operate: bulk status imports and
+// CSV exports of their drivers, trips and earnings. Everything is scoped to the fleet.
+type FleetService struct {
+	fleets  repository.FleetRepository
+	drivers repository.DriverRepository
+	trips   repository.TripRepository
+	logger  *logging.Logger
+	now     func() time.Time
+}
+
+// NewFleetService creates a new fleet service
+func NewFleetService(fleets repository.FleetRepository, drivers repository.DriverRepository, trips repository.TripRepository, logger *logging.Logger) *FleetService {
+	return &FleetService{
+		fleets:  fleets,
+		drivers: drivers,
+		trips:   trips,
+		logger:  logger.WithComponent("fleet_service"),
+		now:     time.Now,
+	}
+}
+
+// CreateFleet stores a new fleet operating in timezone, UTC when empty, and returns it with its
+// API key, which is not stored and cannot be retrieved again
+func (s *FleetService) CreateFleet(ctx context.Context, name, timezone string) (*models.Fleet, string, error) {
+	apiKey, err := generateFleetAPIKey()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if timezone = strings.TrimSpace(timezone); timezone == "" {
+		timezone = "UTC"
+	}
+
+	now := s.now()
+	fleet := &models.Fleet{
+		ID:         uuid.New(),
+		Name:       strings.TrimSpace(name),
+		APIKeyHash: hashFleetAPIKey(apiKey),
+		Timezone:   timezone,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := fleet.Validate(); err != nil {
+		field := "name"
+		if errors.Is(err, models.ErrInvalidFleetTimezone) {
+			field = "timezone"
+		}
+		return nil, "", &models.ValidationError{
+			Field:   field,
+			Message: err.Error(),
+		}
+	}
+
+	if err := s.fleets.Create(ctx, fleet); err != nil {
+		return nil, "", err
+	}
+
+	s.logger.WithField("fleet_id", fleet.ID.String()).Info("Fleet created")
+	return fleet, apiKey, nil
+}
+
+// Authenticate returns the fleet an API key belongs to, or ErrInvalidFleetAPIKey
+func (s *FleetService) Authenticate(ctx context.Context, apiKey string) (*models.Fleet, error) {
+	fleet, err := s.fleets.GetByAPIKeyHash(ctx, hashFleetAPIKey(apiKey))
+	if err != nil {
+		var notFound *models.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, models.ErrInvalidFleetAPIKey
+		}
+		return nil, err
+	}
+	return fleet, nil
+}
+
+// SetDailyRequestQuota changes the number of requests the fleet's API key may make per UTC day;
+// nil falls back to the default quota. Requests made today already count against a new quota.
+func (s *FleetService) SetDailyRequestQuota(ctx context.Context, id string, quota *int64) (*models.Fleet, error) {
+	fleet, err := s.fleets.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fleet.DailyRequestQuota = quota
+	fleet.UpdatedAt = s.now()
+	if err := fleet.Validate(); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "daily_request_quota",
+			Message: err.Error(),
+		}
+	}
+	if err := s.fleets.Update(ctx, fleet); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithField("fleet_id", id).Info("Fleet daily request quota updated")
+	return fleet, nil
+}
+
+// ImportDriverStatuses sets the statuses of the fleet's drivers from a CSV file with a header
+// row naming at least the driver_id and status columns. Drivers can be set online or offline;
+// drivers of other fleets and drivers on a trip are rejected row by row while the other rows
+// are applied. A malformed file is rejected as a whole before anything is changed.
+func (s *FleetService) ImportDriverStatuses(ctx context.Context, fleet *models.Fleet, file io.Reader) (*models.FleetStatusImportResult, error) {
+	rows, err := readStatusImport(file)
+	if err != nil {
+		return nil, err
+	}
+
+	drivers, err := s.drivers.ListByFleet(ctx, fleet.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fleet drivers: %w", err)
+	}
+	fleetDrivers := make(map[string]*models.Driver, len(drivers))
+	for _, d := range drivers {
+		fleetDrivers[d.ID.String()] = d
+	}
+
+	result := &models.FleetStatusImportResult{Rows: rows}
+	for i := range result.Rows {
+		row := &result.Rows[i]
+		if row.Error == "" {
+			row.Error = s.applyStatus(ctx, fleetDrivers, row)
+		}
+		if row.Error != "" {
+			result.Failed++
+		} else {
+			result.Updated++
+		}
+	}
+
+	s.logger.WithFields(logging.Fields{
+		"fleet_id": fleet.ID.String(),
+		"updated":  result.Updated,
+		"failed":   result.Failed,
+	}).Info("Fleet driver statuses imported")
+	return result, nil
+}
+
+// applyStatus sets one imported row's status, returning the row's error message if it fails
+func (s *FleetService) applyStatus(ctx context.Context, fleetDrivers map[string]*models.Driver, row *models.FleetStatusImportRow) string {
+	driver, ok := fleetDrivers[row.DriverID]
+	if !ok {
+		return "driver not found in fleet"
+	}
+	if driver.IsBusy() {
+		return "driver is on a trip"
+	}
+	if err := s.drivers.UpdateStatus(ctx, row.DriverID, row.Status); err != nil {
+		s.logger.WithError(err).WithField("driver_id", row.DriverID).Error("Failed to import driver status")
+		return "failed to update driver status"
+	}
+	driver.Status = row.Status
+	return ""
+}
+
+// readStatusImport parses a status import file into rows, marking invalid values on the row
+func readStatusImport(file io.Reader) ([]models.FleetStatusImportRow, error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, &models.ValidationError{Field: "file", Message: "file is empty"}
+		}
+		return nil, &models.ValidationError{Field: "file", Message: err.Error()}
+	}
+	driverCol, statusCol := -1, -1
+	for i, name := range header {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "driver_id":
+			driverCol = i
+		case "status":
+			statusCol = i
+		}
+	}
+	if driverCol < 0 || statusCol < 0 {
+		return nil, &models.ValidationError{Field: "file", Message: "header must name the driver_id and status columns"}
+	}
+
+	var rows []models.FleetStatusImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, &models.ValidationError{Field: "file", Message: err.Error()}
+		}
+		if len(rows) == fleetImportMaxRows {
+			return nil, &models.ValidationError{
+				Field:   "file",
+				Message: fmt.Sprintf("at most %d rows can be imported at once", fleetImportMaxRows),
+			}
+		}
+
+		row := models.FleetStatusImportRow{Row: len(rows) + 1}
+		if driverCol >= len(record) || statusCol >= len(record) {
+			row.Error = "missing driver_id or status"
+			rows = append(rows, row)
+			continue
+		}
+		row.DriverID = strings.TrimSpace(record[driverCol])
+		row.Status = models.DriverStatus(strings.ToLower(strings.TrimSpace(record[statusCol])))
+		if id, err := uuid.Parse(row.DriverID); err != nil {
+			row.Error = "driver_id must be a valid UUID"
+		} else {
+			row.DriverID = id.String()
+		}
+		if row.Error == "" && row.Status != models.DriverStatusOnline && row.Status != models.DriverStatusOffline {
+			row.Error = "status must be online or offline"
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, &models.ValidationError{Field: "file", Message: "file has no rows"}
+	}
+	return rows, nil
+}
+
+// ExportDrivers loads the fleet's drivers and returns a function writing them as CSV, oldest
+// first. Loading errors are returned before anything is written.
+func (s *FleetService) ExportDrivers(ctx context.Context, fleet *models.Fleet) (func(io.Writer) error, error) {
+	drivers, err := s.drivers.ListByFleet(ctx, fleet.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fleet drivers: %w", err)
+	}
+
+	records := make([][]string, 0, len(drivers))
+	for _, d := range drivers {
+		records = append(records, []string{
+			d.ID.String(),
+			d.UserID.String(),
+			d.LicenseNumber,
+			d.VehicleType,
+			d.VehiclePlate,
+			string(d.Status),
+			formatCSVFloat(d.Rating),
+			strconv.Itoa(d.TotalTrips),
+		})
+	}
+	return func(w io.Writer) error { return writeCSV(w, fleetDriverColumns, records) }, nil
+}
+
+// ExportTrips loads the trips of the fleet's drivers requested in [from, to) and returns a
+// function writing them as CSV, oldest first
+func (s *FleetService) ExportTrips(ctx context.Context, fleet *models.Fleet, from, to time.Time) (func(io.Writer) error, error) {
+	trips, err := s.fleetTrips(ctx, fleet, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([][]string, 0, len(trips))
+	for _, t := range trips {
+		records = append(records, []string{
+			t.ID.String(),
+			t.DriverID.String(),
+			t.PassengerID.String(),
+			string(t.Status),
+			t.RequestedAt.UTC().Format(time.RFC3339),
+			formatCSVTime(t.CompletedAt),
+			formatCSVTime(t.CancelledAt),
+			formatCSVOptionalFloat(t.DistanceKm),
+			formatCSVOptionalInt(t.DurationMinutes),
+			formatCSVOptionalFloat(t.FareAmount),
+		})
+	}
+	return func(w io.Writer) error { return writeCSV(w, fleetTripColumns, records) }, nil
+}
+
+// GetEarnings totals the completed trips and fares of every fleet driver over the trips
+// requested in [from, to), in the order of ListByFleet
+func (s *FleetService) GetEarnings(ctx context.Context, fleet *models.Fleet, from, to time.Time) ([]*models.FleetDriverEarnings, error) {
+	drivers, err := s.drivers.ListByFleet(ctx, fleet.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fleet drivers: %w", err)
+	}
+	trips, err := s.fleetTrips(ctx, fleet, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	earnings := make([]*models.FleetDriverEarnings, 0, len(drivers))
+	byDriver := make(map[uuid.UUID]*models.FleetDriverEarnings, len(drivers))
+	for _, d := range drivers {
+		e := &models.FleetDriverEarnings{DriverID: d.ID, VehiclePlate: d.VehiclePlate}
+		earnings = append(earnings, e)
+		byDriver[d.ID] = e
+	}
+
+	for _, t := range trips {
+		if e, ok := byDriver[*t.DriverID]; ok {
+			addFleetTrip(e, t)
+		}
+	}
+	for _, e := range earnings {
+		e.Earnings = roundFare(e.Earnings)
+	}
+
+	return earnings, nil
+}
+
+// GetDailyEarnings totals the completed trips and fares of the fleet drivers per local date of
+// the trips requested in [from, to), in timezone or the fleet's own when empty. Only the days a
+// driver had trips are listed, by date and then in the order of ListByFleet.
+func (s *FleetService) GetDailyEarnings(ctx context.Context, fleet *models.Fleet, from, to time.Time, timezone string) ([]*models.FleetDriverEarnings, error) {
+	if timezone == "" {
+		timezone = fleet.Timezone
+	}
+	loc, err := reporting.LoadLocation(timezone)
+	if err != nil {
+		return nil, &models.ValidationError{
+			Field:   "tz",
+			Message: err.Error(),
+		}
+	}
+
+	drivers, err := s.drivers.ListByFleet(ctx, fleet.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fleet drivers: %w", err)
+	}
+	trips, err := s.fleetTrips(ctx, fleet, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	type driverDay struct {
+		date     string
+		driverID uuid.UUID
+	}
+	plates := make(map[uuid.UUID]string, len(drivers))
+	order := make(map[uuid.UUID]int, len(drivers))
+	for i, d := range drivers {
+		plates[d.ID] = d.VehiclePlate
+		order[d.ID] = i
+	}
+
+	var earnings []*models.FleetDriverEarnings
+	byDay := make(map[driverDay]*models.FleetDriverEarnings)
+	for _, t := range trips {
+		plate, ok := plates[*t.DriverID]
+		if !ok {
+			continue
+		}
+		key := driverDay{date: reporting.DateOf(t.RequestedAt, loc), driverID: *t.DriverID}
+		e, ok := byDay[key]
+		if !ok {
+			e = &models.FleetDriverEarnings{Date: key.date, DriverID: key.driverID, VehiclePlate: plate}
+			byDay[key] = e
+			earnings = append(earnings, e)
+		}
+		addFleetTrip(e, t)
+	}
+	for _, e := range earnings {
+		e.Earnings = roundFare(e.Earnings)
+	}
+
+	sort.SliceStable(earnings, func(i, j int) bool {
+		if earnings[i].Date != earnings[j].Date {
+			return earnings[i].Date < earnings[j].Date
+		}
+		return order[earnings[i].DriverID] < order[earnings[j].DriverID]
+	})
+	return earnings, nil
+}
+
+// addFleetTrip adds a trip to a driver's earnings
+func addFleetTrip(e *models.FleetDriverEarnings, t *models.Trip) {
+	switch t.Status {
+	case models.TripStatusCompleted:
+		e.TripsCompleted++
+		if t.FareAmount != nil {
+			e.Earnings += *t.FareAmount
+		}
+	case models.TripStatusCancelled:
+		e.TripsCancelled++
+	}
+}
+
+// ExportEarnings loads GetEarnings and returns a function writing it as CSV
+func (s *FleetService) ExportEarnings(ctx context.Context, fleet *models.Fleet, from, to time.Time) (func(io.Writer) error, error) {
+	earnings, err := s.GetEarnings(ctx, fleet, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([][]string, 0, len(earnings))
+	for _, e := range earnings {
+		records = append(records, []string{
+			e.DriverID.String(),
+			e.VehiclePlate,
+			strconv.Itoa(e.TripsCompleted),
+			strconv.Itoa(e.TripsCancelled),
+			formatCSVFloat(e.Earnings),
+		})
+	}
+	return func(w io.Writer) error { return writeCSV(w, fleetEarningsColumns, records) }, nil
+}
+
+// ExportDailyEarnings loads GetDailyEarnings and returns a function writing it as CSV, with the
+// local date before the GetEarnings columns
+func (s *FleetService) ExportDailyEarnings(ctx context.Context, fleet *models.Fleet, from, to time.Time, timezone string) (func(io.Writer) error, error) {
+	earnings, err := s.GetDailyEarnings(ctx, fleet, from, to, timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([][]string, 0, len(earnings))
+	for _, e := range earnings {
+		records = append(records, []string{
+			e.Date,
+			e.DriverID.String(),
+			e.VehiclePlate,
+			strconv.Itoa(e.TripsCompleted),
+			strconv.Itoa(e.TripsCancelled),
+			formatCSVFloat(e.Earnings),
+		})
+	}
+	header := append([]string{"date"}, fleetEarningsColumns...)
+	return func(w io.Writer) error { return writeCSV(w, header, records) }, nil
+}
+
+// fleetTrips validates an export period and loads the fleet's trips requested within it
+func (s *FleetService) fleetTrips(ctx context.Context, fleet *models.Fleet, from, to time.Time) ([]*models.Trip, error) {
+	if !to.After(from) {
+		return nil, &models.ValidationError{
+			Field:   "to",
+			Message: "to must be after from",
+		}
+	}
+
+	trips, err := s.trips.ListByFleet(ctx, fleet.ID.String(), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fleet trips: %w", err)
+	}
+	return trips, nil
+}
+
+// writeCSV writes a header and records as CSV
+func writeCSV(w io.Writer, header []string, records [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(records); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// formatCSVFloat formats a number without trailing zeros
+func formatCSVFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// formatCSVOptionalFloat formats a nullable number, empty when null
+func formatCSVOptionalFloat(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return formatCSVFloat(*v)
+}
+
+// formatCSVOptionalInt formats a nullable integer, empty when null
+func formatCSVOptionalInt(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+// formatCSVTime formats a nullable timestamp as RFC 3339 in UTC, empty when null
+func formatCSVTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// generateFleetAPIKey returns a random URL-safe API key
+func generateFleetAPIKey() (string, error) {
+	b := make([]byte, fleetAPIKeyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate fleet API key: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashFleetAPIKey returns the hex SHA-256 of an API key, as stored on the fleet
+func hashFleetAPIKey(apiKey string) string {
+	sum := sha256.Sum256([]byte(apiKey))
+	return hex.EncodeToString(sum[:])
+}
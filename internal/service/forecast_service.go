@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/forecast"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/reporting"
+	"actor-model-observability/internal/repository"
+)
+
+// ForecastService forecasts hourly ride demand per zone from the trips requested in the
+// configured history window. Zones are geohash cells of the trips' pickup locations. Hours are
+// local hours of the requested timezone, which also groups the forecast into local dates.
+type ForecastService struct {
+	trips     repository.TripRepository
+	cfg       config.ForecastConfig
+	reporting config.ReportingConfig
+	now       func() time.Time
+}
+
+// NewForecastService creates a new demand forecast service
+func NewForecastService(trips repository.TripRepository, cfg config.ForecastConfig, reportingCfg config.ReportingConfig) *ForecastService {
+	return &ForecastService{
+		trips:     trips,
+		cfg:       cfg,
+		reporting: reportingCfg,
+		now:       time.Now,
+	}
+}
+
+// Forecast predicts the ride requests in each of the next horizon hours in zone, or across all
+// zones when zone is empty. method is moving_average or holt_winters; when empty, Holt-Winters is
+// used if there are two days of history. The forecast includes the accuracy of the method when
+// backtested on the last horizon hours of history, when there is enough history to do so.
+// Timestamps are in timezone, the configured report timezone when empty.
+func (s *ForecastService) Forecast(ctx context.Context, zone string, horizon int, method, timezone string) (*models.DemandForecast, error) {
+	if horizon <= 0 || horizon > s.cfg.MaxHorizon {
+		return nil, &models.ValidationError{
+			Field:   "horizon",
+			Message: fmt.Sprintf("horizon must be between 1 and %d hours", s.cfg.MaxHorizon),
+		}
+	}
+	if method != "" && method != forecast.MethodMovingAverage && method != forecast.MethodHoltWinters {
+		return nil, &models.ValidationError{
+			Field:   "method",
+			Message: "method must be moving_average or holt_winters",
+		}
+	}
+	if timezone == "" {
+		timezone = s.reporting.Timezone
+	}
+	loc, err := reporting.LoadLocation(timezone)
+	if err != nil {
+		return nil, &models.ValidationError{
+			Field:   "tz",
+			Message: err.Error(),
+		}
+	}
+
+	var bounds *models.GeoBounds
+	if zone != "" {
+		z, err := forecast.ParseZone(zone)
+		if err != nil {
+			return nil, &models.ValidationError{
+				Field:   "zone",
+				Message: err.Error(),
+			}
+		}
+		zone = z.Geohash
+		bounds = &models.GeoBounds{MinLat: z.MinLat, MaxLat: z.MaxLat, MinLng: z.MinLng, MaxLng: z.MaxLng}
+	}
+
+	// Only complete hours are used, starting from the first request in the window so a new
+	// zone's history isn't padded with hours before it had any trips. Hours are aligned to local
+	// hours, which differ from UTC hours in timezones with a fractional offset.
+	now := s.now().In(loc)
+	end := reporting.StartOfHour(now, loc)
+	start := reporting.StartOfHour(end.Add(-s.cfg.HistoryWindow), loc)
+	times, err := s.trips.GetRequestTimes(ctx, start, end, bounds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trip history: %w", err)
+	}
+	if len(times) > 0 {
+		start = reporting.StartOfHour(times[0], loc)
+	}
+	series := forecast.HourlyCounts(times, start, int(end.Sub(start)/time.Hour))
+	// Without any requests the series only holds the empty window, so there are no hours of history
+	history := len(series)
+	if len(times) == 0 {
+		history = 0
+	}
+
+	params := forecast.Params{
+		MovingAverageWindow: s.cfg.MovingAverageWindow,
+		Alpha:               s.cfg.Alpha,
+		Beta:                s.cfg.Beta,
+		Gamma:               s.cfg.Gamma,
+	}
+	if method == "" {
+		method = forecast.MethodMovingAverage
+		if history >= forecast.MinHistory(forecast.MethodHoltWinters, params) {
+			method = forecast.MethodHoltWinters
+		}
+	}
+	if history < forecast.MinHistory(method, params) {
+		return nil, fmt.Errorf("%w: %s needs %d hours, found %d", models.ErrInsufficientForecastHistory,
+			method, forecast.MinHistory(method, params), history)
+	}
+
+	predicted, err := forecast.Predict(method, series, horizon, params)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.DemandForecast{
+		Zone:         zone,
+		Bounds:       bounds,
+		Method:       method,
+		Timezone:     timezone,
+		Interval:     "1h",
+		Horizon:      horizon,
+		HistoryStart: start,
+		HistoryEnd:   end,
+		HistoryTrips: len(times),
+		Points:       make([]models.DemandForecastPoint, horizon),
+		GeneratedAt:  now,
+	}
+	var day *models.DemandForecastDay
+	for i, demand := range predicted {
+		timestamp := end.Add(time.Duration(i) * time.Hour).In(loc)
+		result.Points[i] = models.DemandForecastPoint{
+			Timestamp: timestamp,
+			Demand:    roundDemand(demand),
+		}
+
+		if date := reporting.DateOf(timestamp, loc); day == nil || day.Date != date {
+			result.Daily = append(result.Daily, models.DemandForecastDay{Date: date})
+			day = &result.Daily[len(result.Daily)-1]
+		}
+		day.Demand += demand
+		day.Hours++
+	}
+	for i := range result.Daily {
+		result.Daily[i].Demand = roundDemand(result.Daily[i].Demand)
+	}
+
+	if len(series)-horizon >= forecast.MinHistory(method, params) {
+		accuracy, err := forecast.Backtest(method, series, horizon, params)
+		if err != nil {
+			return nil, err
+		}
+		result.Backtest = &models.DemandForecastBacktest{
+			Horizon: accuracy.Points,
+			MAE:     roundDemand(accuracy.MAE),
+			RMSE:    roundDemand(accuracy.RMSE),
+		}
+		if !math.IsNaN(accuracy.MAPE) {
+			mape := roundDemand(accuracy.MAPE)
+			result.Backtest.MAPE = &mape
+		}
+	}
+
+	return result, nil
+}
+
+// roundDemand rounds a forecast value to two decimals for display
+func roundDemand(v float64) float64 {
+	return math.Round(v*100) / 100
+}
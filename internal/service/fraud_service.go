@@ -0,0 +1,471 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// fraudSignalEntityType is the entity type of the event logs recorded for a fraud signal
+const fraudSignalEntityType = "fraud_signal"
+
+// fraudHistoryLimit caps the recent trips of a rider or driver the history rules look through
+const fraudHistoryLimit = 100
+
+// Reasons a driver is suspected of self-referral
+const (
+	selfReferralSameAccount  = "same_account"
+	selfReferralSharedDevice = "shared_device"
+	selfReferralRepeatedPair = "repeated_pair"
+)
+
+// FraudService runs rules-based fraud detection on the trip_status and driver_location topics of
+// the event bus. Completed trips are checked for impossible average speeds and for drivers
+// driving themselves, from the passenger's account or device, or the same passenger over and
+// over; cancellations after the match are counted per passenger and driver; and every driver
+// location is checked against the driver's previous one for GPS teleporting. A rule firing raises
+// a fraud signal with the evidence for admins to confirm or dismiss, publishes a security event
+// log and counts the hit per rule. A rule flags a subject once per trip, and rules without a trip
+// hold back while the subject has a signal of the rule awaiting review.
+type FraudService struct {
+	signals    repository.FraudSignalRepository
+	trips      repository.TripRepository
+	drivers    repository.DriverRepository
+	passengers repository.PassengerRepository
+	sessions   repository.SessionRepository
+	cfg        config.FraudConfig
+	events     bus.Publisher
+	metrics    BusinessMetricsRecorder
+	logger     *logging.Logger
+	now        func() time.Time
+
+	// lastLocations is the latest location reported by each driver to this instance
+	lastLocations map[uuid.UUID]models.DriverLocationUpdate
+	mu            sync.Mutex
+}
+
+// NewFraudService creates a new fraud detection service. Signals are published on events and
+// rule hits counted in metrics; both may be nil.
+func NewFraudService(
+	signals repository.FraudSignalRepository,
+	trips repository.TripRepository,
+	drivers repository.DriverRepository,
+	passengers repository.PassengerRepository,
+	sessions repository.SessionRepository,
+	cfg config.FraudConfig,
+	events bus.Publisher,
+	metrics BusinessMetricsRecorder,
+	logger *logging.Logger,
+) *FraudService {
+	return &FraudService{
+		signals:       signals,
+		trips:         trips,
+		drivers:       drivers,
+		passengers:    passengers,
+		sessions:      sessions,
+		cfg:           cfg,
+		events:        events,
+		metrics:       metrics,
+		logger:        logger.WithComponent("fraud_service"),
+		now:           time.Now,
+		lastLocations: make(map[uuid.UUID]models.DriverLocationUpdate),
+	}
+}
+
+// Topics returns the event bus topics the service handles
+func (s *FraudService) Topics() []string {
+	return []string{bus.TopicTripStatus, bus.TopicDriverLocation}
+}
+
+// HandleMessage runs the rules applying to a trip status change or a driver location
+func (s *FraudService) HandleMessage(msg bus.Message) {
+	ctx := context.Background()
+
+	switch payload := msg.Payload.(type) {
+	case *models.Trip:
+		switch payload.Status {
+		case models.TripStatusCompleted:
+			s.checkImpossibleSpeed(ctx, payload)
+			s.checkSelfReferral(ctx, payload)
+		case models.TripStatusCancelled:
+			s.checkCancellations(ctx, payload)
+		}
+	case *models.DriverLocationUpdate:
+		s.checkTeleport(ctx, payload, msg.PublishedAt)
+	}
+}
+
+// checkImpossibleSpeed flags the driver of a completed trip whose distance could not have been
+// driven between the pickup and the dropoff
+func (s *FraudService) checkImpossibleSpeed(ctx context.Context, trip *models.Trip) {
+	if trip.DriverID == nil || trip.DistanceKm == nil || trip.PickupAt == nil || trip.CompletedAt == nil {
+		return
+	}
+
+	// A trip completed the moment it started took no less than a second
+	seconds := math.Max(trip.CompletedAt.Sub(*trip.PickupAt).Seconds(), 1)
+	speed := *trip.DistanceKm / seconds * 3600
+	if speed <= s.cfg.MaxTripSpeedKmh {
+		return
+	}
+
+	severity := models.FraudSeverityMedium
+	if speed >= 2*s.cfg.MaxTripSpeedKmh {
+		severity = models.FraudSeverityHigh
+	}
+	s.raise(ctx, models.FraudRuleImpossibleSpeed, severity, models.UserTypeDriver, *trip.DriverID, &trip.ID, map[string]interface{}{
+		"distance_km":      *trip.DistanceKm,
+		"duration_seconds": math.Round(seconds),
+		"speed_kmh":        math.Round(speed*100) / 100,
+		"max_speed_kmh":    s.cfg.MaxTripSpeedKmh,
+	})
+}
+
+// checkSelfReferral flags the driver of a completed trip who is the passenger: on the same
+// account, from a device both are signed in on, or as the passenger's driver on most of their
+// recent trips
+func (s *FraudService) checkSelfReferral(ctx context.Context, trip *models.Trip) {
+	if trip.DriverID == nil {
+		return
+	}
+
+	driver, err := s.drivers.GetByID(ctx, trip.DriverID.String())
+	if err != nil {
+		s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Warn("Failed to get driver for fraud detection")
+		return
+	}
+	passenger, err := s.passengers.GetByID(ctx, trip.PassengerID.String())
+	if err != nil {
+		s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Warn("Failed to get passenger for fraud detection")
+		return
+	}
+
+	details := map[string]interface{}{
+		"passenger_id": passenger.ID,
+	}
+	if driver.UserID == passenger.UserID {
+		details["reason"] = selfReferralSameAccount
+		s.raise(ctx, models.FraudRuleSelfReferral, models.FraudSeverityHigh, models.UserTypeDriver, driver.ID, &trip.ID, details)
+		return
+	}
+	if device := s.sharedDevice(ctx, driver.UserID, passenger.UserID); device != "" {
+		details["reason"] = selfReferralSharedDevice
+		details["device_id"] = device
+		s.raise(ctx, models.FraudRuleSelfReferral, models.FraudSeverityHigh, models.UserTypeDriver, driver.ID, &trip.ID, details)
+		return
+	}
+
+	trips, err := s.trips.GetByPassengerID(ctx, passenger.ID.String(), fraudHistoryLimit, 0)
+	if err != nil {
+		s.logger.WithError(err).WithField("passenger_id", passenger.ID.String()).Warn("Failed to get passenger trips for fraud detection")
+		return
+	}
+	since := s.now().Add(-s.cfg.PairWindow)
+	pairs := 0
+	for _, t := range trips {
+		if t.Status == models.TripStatusCompleted && t.DriverID != nil && *t.DriverID == driver.ID &&
+			t.CompletedAt != nil && t.CompletedAt.After(since) {
+			pairs++
+		}
+	}
+	if pairs < s.cfg.PairThreshold {
+		return
+	}
+
+	details["reason"] = selfReferralRepeatedPair
+	details["completed_trips"] = pairs
+	details["window_hours"] = s.cfg.PairWindow.Hours()
+	s.raise(ctx, models.FraudRuleSelfReferral, models.FraudSeverityMedium, models.UserTypeDriver, driver.ID, &trip.ID, details)
+}
+
+// sharedDevice returns a device the driver and the passenger both have an active session on
+func (s *FraudService) sharedDevice(ctx context.Context, driverUserID, passengerUserID uuid.UUID) string {
+	now := s.now()
+	driverSessions, err := s.sessions.ListActiveByUser(ctx, driverUserID.String(), now)
+	if err != nil || len(driverSessions) == 0 {
+		return ""
+	}
+	passengerSessions, err := s.sessions.ListActiveByUser(ctx, passengerUserID.String(), now)
+	if err != nil {
+		return ""
+	}
+
+	devices := make(map[string]bool, len(driverSessions))
+	for _, session := range driverSessions {
+		devices[session.DeviceID] = true
+	}
+	for _, session := range passengerSessions {
+		if devices[session.DeviceID] {
+			return session.DeviceID
+		}
+	}
+	return ""
+}
+
+// checkCancellations flags the passenger and the driver of a trip cancelled after the match when
+// they reach the threshold of such cancellations within the window
+func (s *FraudService) checkCancellations(ctx context.Context, trip *models.Trip) {
+	if trip.MatchedAt == nil {
+		return
+	}
+	at := s.now()
+	if trip.CancelledAt != nil {
+		at = *trip.CancelledAt
+	}
+
+	trips, err := s.trips.GetByPassengerID(ctx, trip.PassengerID.String(), fraudHistoryLimit, 0)
+	if err != nil {
+		s.logger.WithError(err).WithField("passenger_id", trip.PassengerID.String()).Warn("Failed to get passenger trips for fraud detection")
+	} else {
+		s.flagCancellations(ctx, trip, models.UserTypePassenger, trip.PassengerID, trips, at)
+	}
+
+	if trip.DriverID == nil {
+		return
+	}
+	trips, err = s.trips.GetByDriverID(ctx, trip.DriverID.String(), fraudHistoryLimit, 0)
+	if err != nil {
+		s.logger.WithError(err).WithField("driver_id", trip.DriverID.String()).Warn("Failed to get driver trips for fraud detection")
+		return
+	}
+	s.flagCancellations(ctx, trip, models.UserTypeDriver, *trip.DriverID, trips, at)
+}
+
+// flagCancellations counts the trips cancelled after the match within the window back from at
+// and flags the subject when they reach the threshold
+func (s *FraudService) flagCancellations(ctx context.Context, trip *models.Trip, subjectType models.UserType, subjectID uuid.UUID, trips []*models.Trip, at time.Time) {
+	since := at.Add(-s.cfg.CancellationWindow)
+	cancelled := 0
+	for _, t := range trips {
+		if t.Status == models.TripStatusCancelled && t.MatchedAt != nil && t.CancelledAt != nil &&
+			t.CancelledAt.After(since) && !t.CancelledAt.After(at) {
+			cancelled++
+		}
+	}
+	if cancelled < s.cfg.CancellationThreshold || s.awaitingReview(ctx, models.FraudRuleRepeatedCancellations, subjectID) {
+		return
+	}
+
+	severity := models.FraudSeverityMedium
+	if cancelled >= 2*s.cfg.CancellationThreshold {
+		severity = models.FraudSeverityHigh
+	}
+	s.raise(ctx, models.FraudRuleRepeatedCancellations, severity, subjectType, subjectID, &trip.ID, map[string]interface{}{
+		"cancellations": cancelled,
+		"window_hours":  s.cfg.CancellationWindow.Hours(),
+	})
+}
+
+// checkTeleport flags a driver whose location jumped further than they could have driven since
+// their previous location. Locations older than the latest one are ignored.
+func (s *FraudService) checkTeleport(ctx context.Context, update *models.DriverLocationUpdate, publishedAt time.Time) {
+	current := *update
+	if current.At.IsZero() {
+		current.At = publishedAt
+	}
+
+	s.mu.Lock()
+	previous, ok := s.lastLocations[current.DriverID]
+	if ok && current.At.Before(previous.At) {
+		s.mu.Unlock()
+		return
+	}
+	s.lastLocations[current.DriverID] = current
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	from := models.Location{Latitude: previous.Latitude, Longitude: previous.Longitude}
+	distance := from.DistanceTo(models.Location{Latitude: current.Latitude, Longitude: current.Longitude})
+	if distance < s.cfg.TeleportMinDistanceKm {
+		return
+	}
+	// Pings reported together are a second apart
+	seconds := math.Max(current.At.Sub(previous.At).Seconds(), 1)
+	speed := distance / seconds * 3600
+	if speed <= s.cfg.TeleportSpeedKmh || s.awaitingReview(ctx, models.FraudRuleGPSTeleport, current.DriverID) {
+		return
+	}
+
+	s.raise(ctx, models.FraudRuleGPSTeleport, models.FraudSeverityHigh, models.UserTypeDriver, current.DriverID, nil, map[string]interface{}{
+		"from_latitude":  previous.Latitude,
+		"from_longitude": previous.Longitude,
+		"from_at":        previous.At,
+		"to_latitude":    current.Latitude,
+		"to_longitude":   current.Longitude,
+		"to_at":          current.At,
+		"distance_km":    math.Round(distance*1000) / 1000,
+		"speed_kmh":      math.Round(speed*100) / 100,
+	})
+}
+
+// awaitingReview returns true if the rule flagged the subject in a signal still open
+func (s *FraudService) awaitingReview(ctx context.Context, rule models.FraudRule, subjectID uuid.UUID) bool {
+	_, total, err := s.signals.List(ctx, models.FraudSignalFilter{
+		Rule:      rule,
+		Status:    models.FraudSignalOpen,
+		SubjectID: &subjectID,
+		Limit:     1,
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("subject_id", subjectID.String()).Warn("Failed to check open fraud signals")
+		return false
+	}
+	return total > 0
+}
+
+// raise counts a rule hit and stores the fraud signal it raises, unless the rule already flagged
+// the subject on the trip
+func (s *FraudService) raise(ctx context.Context, rule models.FraudRule, severity models.FraudSeverity, subjectType models.UserType, subjectID uuid.UUID, tripID *uuid.UUID, details map[string]interface{}) {
+	if s.metrics != nil {
+		s.metrics.RecordBusinessMetrics("fraud_rule_hits_total", 1, map[string]string{
+			"rule":     string(rule),
+			"severity": string(severity),
+		})
+	}
+
+	now := s.now()
+	signal := &models.FraudSignal{
+		ID:          uuid.New(),
+		Rule:        rule,
+		Severity:    severity,
+		SubjectType: subjectType,
+		SubjectID:   subjectID,
+		TripID:      tripID,
+		Status:      models.FraudSignalOpen,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	signal.Details, _ = json.Marshal(details)
+
+	if err := s.signals.Create(ctx, signal); err != nil {
+		if !errors.Is(err, models.ErrDuplicateEntry) {
+			s.logger.WithError(err).WithField("rule", string(rule)).Error("Failed to record fraud signal")
+		}
+		return
+	}
+
+	eventSeverity := models.EventSeverityWarn
+	if severity == models.FraudSeverityHigh {
+		eventSeverity = models.EventSeverityError
+	}
+	s.publish("fraud_signal_raised", signal, "", eventSeverity, details, fmt.Sprintf("Fraud rule %s flagged %s", rule, subjectType))
+}
+
+// GetSignal returns a fraud signal by ID
+func (s *FraudService) GetSignal(ctx context.Context, id string) (*models.FraudSignal, error) {
+	return s.signals.GetByID(ctx, id)
+}
+
+// ListSignals returns the matching signals, newest first, and the total number of matches
+func (s *FraudService) ListSignals(ctx context.Context, filter models.FraudSignalFilter) ([]*models.FraudSignal, int64, error) {
+	return s.signals.List(ctx, filter)
+}
+
+// Review confirms or dismisses an open signal. Decisions are counted per rule, so the metrics
+// show how often each rule is right.
+func (s *FraudService) Review(ctx context.Context, id string, decision models.FraudSignalStatus, reviewer, note string) (*models.FraudSignal, error) {
+	if decision != models.FraudSignalConfirmed && decision != models.FraudSignalDismissed {
+		return nil, &models.ValidationError{
+			Field:   "decision",
+			Message: "must be confirmed or dismissed",
+		}
+	}
+
+	signal, err := s.signals.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !signal.Status.CanTransitionTo(decision) {
+		return nil, fmt.Errorf("%w: fraud signal is %s and cannot become %s", models.ErrInvalidStatusTransition, signal.Status, decision)
+	}
+
+	from := signal.Status
+	signal.Review(decision, reviewer, strings.TrimSpace(note), s.now())
+	if err := signal.Validate(); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "note",
+			Message: err.Error(),
+		}
+	}
+	if err := s.signals.Update(ctx, signal, from); err != nil {
+		return nil, err
+	}
+
+	if s.metrics != nil {
+		s.metrics.RecordBusinessMetrics("fraud_signal_reviews_total", 1, map[string]string{
+			"rule":     string(signal.Rule),
+			"decision": string(decision),
+		})
+	}
+	s.publish("fraud_signal_"+string(decision), signal, reviewer, models.EventSeverityInfo, map[string]interface{}{
+		"review_note": signal.ReviewNote,
+	}, "Fraud signal "+string(decision))
+	return signal, nil
+}
+
+// publish logs a fraud signal workflow step and publishes it as a security event log on the signal
+func (s *FraudService) publish(eventType string, signal *models.FraudSignal, reviewer string, severity models.EventSeverity, data map[string]interface{}, message string) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["signal_id"] = signal.ID
+	data["rule"] = signal.Rule
+	data["severity"] = signal.Severity
+	data["subject_type"] = signal.SubjectType
+	data["subject_id"] = signal.SubjectID
+	data["status"] = signal.Status
+	if signal.TripID != nil {
+		data["trip_id"] = *signal.TripID
+	}
+	if reviewer != "" {
+		data["reviewed_by"] = reviewer
+	}
+
+	entry := s.logger.WithFields(logging.Fields{
+		"event_type":   eventType,
+		"signal_id":    signal.ID.String(),
+		"rule":         string(signal.Rule),
+		"severity":     string(signal.Severity),
+		"subject_type": string(signal.SubjectType),
+		"subject_id":   signal.SubjectID.String(),
+	})
+	if severity == models.EventSeverityInfo {
+		entry.Info(message)
+	} else {
+		entry.Warn(message)
+	}
+
+	if s.events == nil {
+		return
+	}
+
+	entityType, entityID := fraudSignalEntityType, signal.ID
+	eventLog := &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     eventType,
+		EventCategory: models.EventCategorySecurity,
+		EntityType:    &entityType,
+		EntityID:      &entityID,
+		Severity:      severity,
+		Message:       message,
+		Timestamp:     time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	eventLog.EventData, _ = json.Marshal(data)
+	s.events.Publish(bus.TopicEventLog, eventLog)
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/geohash"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Heatmap bucket precisions; buckets are named geohash<precision>, e.g. geohash5 (~4.9km cells)
+const (
+	DefaultHeatmapBucket   = "geohash5"
+	MinHeatmapPrecision    = 3
+	MaxHeatmapPrecision    = 7
+	heatmapBucketPrefix    = "geohash"
+	heatmapCacheKeyPattern = "heatmap:%s:%d:%d"
+)
+
+// HeatmapService aggregates trip pickups and dropoffs per geohash cell for map visualizations.
+// Heatmaps are cached in Redis when a client is configured.
+type HeatmapService struct {
+	trips  repository.TripRepository
+	cache  *redis.Client
+	cfg    config.HeatmapConfig
+	logger *logging.Logger
+	now    func() time.Time
+}
+
+// NewHeatmapService creates a new heatmap service. cache may be nil to run without caching.
+func NewHeatmapService(trips repository.TripRepository, cache *redis.Client, cfg config.HeatmapConfig, logger *logging.Logger) *HeatmapService {
+	return &HeatmapService{
+		trips:  trips,
+		cache:  cache,
+		cfg:    cfg,
+		logger: logger.WithComponent("heatmap_service"),
+		now:    time.Now,
+	}
+}
+
+// GetHeatmap counts the pickups of the trips requested and the dropoffs of the trips completed in
+// [start, end) per bucket cell, busiest cells first. A zero end is now and a zero start is the
+// configured default range before end. Both are truncated to the minute so that heatmaps of the
+// same period share a cache entry.
+func (s *HeatmapService) GetHeatmap(ctx context.Context, start, end time.Time, bucket string) (*models.Heatmap, error) {
+	if bucket == "" {
+		bucket = DefaultHeatmapBucket
+	}
+	grid, err := parseHeatmapBucket(bucket)
+	if err != nil {
+		return nil, err
+	}
+
+	if end.IsZero() {
+		end = s.now()
+	}
+	end = end.UTC().Truncate(time.Minute)
+	if start.IsZero() {
+		start = end.Add(-s.cfg.DefaultRange)
+	}
+	start = start.UTC().Truncate(time.Minute)
+	if !start.Before(end) {
+		return nil, &models.ValidationError{
+			Field:   "start",
+			Message: "start must be at least a minute before end",
+		}
+	}
+	if end.Sub(start) > s.cfg.MaxRange {
+		return nil, &models.ValidationError{
+			Field:   "end",
+			Message: fmt.Sprintf("heatmaps cover at most %s", s.cfg.MaxRange),
+		}
+	}
+
+	key := fmt.Sprintf(heatmapCacheKeyPattern, bucket, start.Unix(), end.Unix())
+	if heatmap := s.cached(ctx, key); heatmap != nil {
+		return heatmap, nil
+	}
+
+	counts, err := s.trips.GetGridCounts(ctx, start, end, grid.CellHeight(), grid.CellWidth())
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate trips: %w", err)
+	}
+
+	heatmap := &models.Heatmap{
+		Bucket:      bucket,
+		Start:       start,
+		End:         end,
+		Cells:       make([]models.HeatmapCell, 0, len(counts)),
+		GeneratedAt: s.now().UTC(),
+	}
+	for _, count := range counts {
+		cell := geohash.Cell{Row: count.Row, Col: count.Col}
+		box := grid.Bounds(cell)
+		heatmap.Pickups += count.Pickups
+		heatmap.Dropoffs += count.Dropoffs
+		heatmap.Cells = append(heatmap.Cells, models.HeatmapCell{
+			Geohash:   grid.Geohash(cell),
+			Bounds:    models.GeoBounds{MinLat: box.MinLat, MaxLat: box.MaxLat, MinLng: box.MinLng, MaxLng: box.MaxLng},
+			CenterLat: (box.MinLat + box.MaxLat) / 2,
+			CenterLng: (box.MinLng + box.MaxLng) / 2,
+			Pickups:   count.Pickups,
+			Dropoffs:  count.Dropoffs,
+		})
+	}
+	sort.Slice(heatmap.Cells, func(i, j int) bool {
+		a, b := heatmap.Cells[i], heatmap.Cells[j]
+		if a.Pickups+a.Dropoffs != b.Pickups+b.Dropoffs {
+			return a.Pickups+a.Dropoffs > b.Pickups+b.Dropoffs
+		}
+		return a.Geohash < b.Geohash
+	})
+
+	s.store(ctx, key, heatmap)
+	return heatmap, nil
+}
+
+// cached returns the heatmap cached under key, or nil. Cache failures are logged and treated as
+// misses so that heatmaps keep working while Redis is unavailable.
+func (s *HeatmapService) cached(ctx context.Context, key string) *models.Heatmap {
+	if s.cache == nil || s.cfg.CacheTTL <= 0 {
+		return nil
+	}
+
+	data, err := s.cache.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			s.logger.WithError(err).WithField("key", key).Warn("Failed to read cached heatmap")
+		}
+		return nil
+	}
+
+	var heatmap models.Heatmap
+	if err := json.Unmarshal(data, &heatmap); err != nil {
+		s.logger.WithError(err).WithField("key", key).Warn("Failed to decode cached heatmap")
+		return nil
+	}
+	return &heatmap
+}
+
+// store caches a heatmap under key for the configured TTL
+func (s *HeatmapService) store(ctx context.Context, key string, heatmap *models.Heatmap) {
+	if s.cache == nil || s.cfg.CacheTTL <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(heatmap)
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to encode heatmap for caching")
+		return
+	}
+	if err := s.cache.Set(ctx, key, data, s.cfg.CacheTTL).Err(); err != nil {
+		s.logger.WithError(err).WithField("key", key).Warn("Failed to cache heatmap")
+	}
+}
+
+// parseHeatmapBucket returns the geohash grid of a bucket name such as geohash5
+func parseHeatmapBucket(bucket string) (geohash.Grid, error) {
+	invalid := &models.ValidationError{
+		Field:   "bucket",
+		Message: fmt.Sprintf("bucket must be geohash%d to geohash%d", MinHeatmapPrecision, MaxHeatmapPrecision),
+	}
+
+	precision, err := strconv.Atoi(strings.TrimPrefix(bucket, heatmapBucketPrefix))
+	if err != nil || !strings.HasPrefix(bucket, heatmapBucketPrefix) ||
+		precision < MinHeatmapPrecision || precision > MaxHeatmapPrecision {
+		return geohash.Grid{}, invalid
+	}
+	grid, err := geohash.NewGrid(precision)
+	if err != nil {
+		return geohash.Grid{}, invalid
+	}
+	return grid, nil
+}
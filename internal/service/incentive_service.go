@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// IncentiveService runs driver incentive campaigns. It receives trip lifecycle events and
+// counts every completed trip towards the driver's progress on each campaign running at the
+// time of completion; drivers reaching a campaign's required trips get a payout record.
+type IncentiveService struct {
+	incentives repository.IncentiveRepository
+	drivers    repository.DriverRepository
+	events     bus.Publisher
+	logger     *logging.Logger
+	now        func() time.Time
+}
+
+// NewIncentiveService creates a new incentive service. Completed quests are published to
+// events as business event logs; events may be nil.
+func NewIncentiveService(incentives repository.IncentiveRepository, drivers repository.DriverRepository, events bus.Publisher, logger *logging.Logger) *IncentiveService {
+	return &IncentiveService{
+		incentives: incentives,
+		drivers:    drivers,
+		events:     events,
+		logger:     logger.WithComponent("incentive_service"),
+		now:        time.Now,
+	}
+}
+
+// CreateCampaign validates and stores a new campaign, active from creation
+func (s *IncentiveService) CreateCampaign(ctx context.Context, campaign *models.IncentiveCampaign) (*models.IncentiveCampaign, error) {
+	now := s.now()
+	campaign.ID = uuid.New()
+	campaign.BonusAmount = roundFare(campaign.BonusAmount)
+	campaign.Active = true
+	campaign.CreatedAt = now
+	campaign.UpdatedAt = now
+	if err := campaign.Validate(); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "campaign",
+			Message: err.Error(),
+		}
+	}
+
+	if err := s.incentives.CreateCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(logging.Fields{
+		"campaign_id":    campaign.ID.String(),
+		"required_trips": campaign.RequiredTrips,
+		"bonus_amount":   campaign.BonusAmount,
+	}).Info("Incentive campaign created")
+	return campaign, nil
+}
+
+// ListCampaigns returns every campaign, or only those running now, ordered by start time
+func (s *IncentiveService) ListCampaigns(ctx context.Context, runningOnly bool) ([]*models.IncentiveCampaign, error) {
+	if !runningOnly {
+		return s.incentives.ListCampaigns(ctx, nil)
+	}
+	now := s.now()
+	return s.incentives.ListCampaigns(ctx, &now)
+}
+
+// SetCampaignActive activates or deactivates a campaign. Trips completed while a campaign is
+// inactive never count towards it.
+func (s *IncentiveService) SetCampaignActive(ctx context.Context, id string, active bool) (*models.IncentiveCampaign, error) {
+	if err := s.incentives.SetCampaignActive(ctx, id, active); err != nil {
+		return nil, err
+	}
+	return s.incentives.GetCampaign(ctx, id)
+}
+
+// GetDriverQuests returns the campaigns running now with the driver's progress on each
+func (s *IncentiveService) GetDriverQuests(ctx context.Context, driverID string) ([]*models.DriverQuest, error) {
+	if _, err := s.drivers.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+
+	campaigns, err := s.ListCampaigns(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.incentives.ListProgressByDriver(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	payouts, err := s.incentives.ListPayoutsByDriver(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	progressByCampaign := make(map[uuid.UUID]*models.QuestProgress, len(progress))
+	for _, p := range progress {
+		progressByCampaign[p.CampaignID] = p
+	}
+	payoutByCampaign := make(map[uuid.UUID]*models.IncentivePayout, len(payouts))
+	for _, p := range payouts {
+		payoutByCampaign[p.CampaignID] = p
+	}
+
+	quests := make([]*models.DriverQuest, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		quest := &models.DriverQuest{
+			Campaign:       campaign,
+			RemainingTrips: campaign.RequiredTrips,
+			Payout:         payoutByCampaign[campaign.ID],
+		}
+		if p, ok := progressByCampaign[campaign.ID]; ok {
+			quest.CompletedTrips = p.CompletedTrips
+			quest.RemainingTrips = max(campaign.RequiredTrips-p.CompletedTrips, 0)
+			quest.Completed = p.CompletedAt != nil
+		}
+		quests = append(quests, quest)
+	}
+	return quests, nil
+}
+
+// ListDriverPayouts returns the driver's incentive payouts, newest first
+func (s *IncentiveService) ListDriverPayouts(ctx context.Context, driverID string) ([]*models.IncentivePayout, error) {
+	if _, err := s.drivers.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+	return s.incentives.ListPayoutsByDriver(ctx, driverID)
+}
+
+// PublishTripEvent credits a completed trip to its driver on every campaign running when the
+// trip completed. Other events are ignored, and so are trips that were already credited.
+func (s *IncentiveService) PublishTripEvent(ctx context.Context, event models.WebhookEvent, trip *models.Trip) error {
+	if event != models.WebhookEventTripCompleted || trip.DriverID == nil {
+		return nil
+	}
+
+	completedAt := s.now()
+	if trip.CompletedAt != nil {
+		completedAt = *trip.CompletedAt
+	}
+
+	campaigns, err := s.incentives.ListCampaigns(ctx, &completedAt)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, campaign := range campaigns {
+		credit := &models.IncentiveTripCredit{
+			CampaignID:  campaign.ID,
+			TripID:      trip.ID,
+			DriverID:    *trip.DriverID,
+			CompletedAt: completedAt,
+		}
+		payout := &models.IncentivePayout{
+			ID:         uuid.New(),
+			CampaignID: campaign.ID,
+			DriverID:   *trip.DriverID,
+			Amount:     campaign.BonusAmount,
+			TripCount:  campaign.RequiredTrips,
+			CreatedAt:  s.now(),
+		}
+
+		progress, paid, err := s.incentives.CreditTrip(ctx, credit, payout)
+		if errors.Is(err, models.ErrDuplicateEntry) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		s.logger.WithFields(logging.Fields{
+			"campaign_id":     campaign.ID.String(),
+			"driver_id":       trip.DriverID.String(),
+			"trip_id":         trip.ID.String(),
+			"completed_trips": progress.CompletedTrips,
+		}).Debug("Trip credited to incentive campaign")
+
+		if paid {
+			s.publishQuestCompleted(campaign, payout)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// publishQuestCompleted logs a completed quest and publishes it as a business event log on the driver
+func (s *IncentiveService) publishQuestCompleted(campaign *models.IncentiveCampaign, payout *models.IncentivePayout) {
+	s.logger.WithFields(logging.Fields{
+		"campaign_id": campaign.ID.String(),
+		"driver_id":   payout.DriverID.String(),
+		"payout_id":   payout.ID.String(),
+		"amount":      payout.Amount,
+	}).Info("Driver completed incentive quest")
+
+	if s.events == nil {
+		return
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"campaign_id":   campaign.ID,
+		"campaign_name": campaign.Name,
+		"payout_id":     payout.ID,
+		"amount":        payout.Amount,
+		"trip_count":    payout.TripCount,
+	})
+	entityType, entityID := "driver", payout.DriverID
+	s.events.Publish(bus.TopicEventLog, &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     "incentive_quest_completed",
+		EventCategory: models.EventCategoryBusiness,
+		EntityType:    &entityType,
+		EntityID:      &entityID,
+		EventData:     data,
+		Severity:      models.EventSeverityInfo,
+		Message:       "Driver completed incentive quest " + campaign.Name,
+		Timestamp:     time.Now(),
+		CreatedAt:     time.Now(),
+	})
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// integrityRunEntityType is the entity type of the event logs recorded for an integrity run
+const integrityRunEntityType = "integrity_run"
+
+// IntegrityService checks that the observability tables do not silently lose rows. Every
+// interval it counts and checksums the rows of each table per hour over the finished hours of the
+// lookback and stores them as a run. The hours a run shares with the run before it should only
+// gain rows, written late by buffered writers; an hour with fewer rows than before lost rows, e.g.
+// to a batch dropped after being written or to cleanup removing too much, and is published as a
+// warning event log. Runs are kept for the retention period.
+type IntegrityService struct {
+	integrity repository.IntegrityRepository
+	cfg       config.IntegrityCheckConfig
+	events    bus.Publisher
+	metrics   BusinessMetricsRecorder
+	logger    *logging.Logger
+	now       func() time.Time
+
+	// runMu serializes the runs of this instance, so that each run is compared with the one
+	// before it
+	runMu sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewIntegrityService creates a new observability integrity service. Lost rows are published on
+// events and counted in metrics; both may be nil.
+func NewIntegrityService(
+	integrity repository.IntegrityRepository,
+	cfg config.IntegrityCheckConfig,
+	events bus.Publisher,
+	metrics BusinessMetricsRecorder,
+	logger *logging.Logger,
+) *IntegrityService {
+	return &IntegrityService{
+		integrity: integrity,
+		cfg:       cfg,
+		events:    events,
+		metrics:   metrics,
+		logger:    logger.WithComponent("integrity_service"),
+		now:       time.Now,
+	}
+}
+
+// Start checks the observability tables in the background, immediately and then every interval
+func (s *IntegrityService) Start(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go s.checkLoop()
+
+	s.logger.WithFields(logging.Fields{
+		"interval": s.cfg.Interval.String(),
+		"lookback": s.cfg.Lookback.String(),
+	}).Info("Observability integrity checks started")
+	return nil
+}
+
+// Stop stops the check loop
+func (s *IntegrityService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Observability integrity checks stopped")
+	return nil
+}
+
+// checkLoop checks the observability tables now and on every interval
+func (s *IntegrityService) checkLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Run(s.ctx); err != nil && s.ctx.Err() == nil {
+			s.logger.WithError(err).Error("Observability integrity check failed")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// Run checksums the rows of every observability table per hour over the finished hours of the
+// lookback, stores the run and compares it with the run before it. The comparison has no
+// previous run on the first run.
+func (s *IntegrityService) Run(ctx context.Context) (*models.IntegrityComparison, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	started := s.now()
+	end := started.UTC().Truncate(time.Hour)
+	hours := (s.cfg.Lookback + time.Hour - 1) / time.Hour
+	run := &models.IntegrityRun{
+		ID:          uuid.New(),
+		WindowStart: end.Add(-hours * time.Hour),
+		WindowEnd:   end,
+		StartedAt:   started,
+	}
+
+	var checksums []*models.IntegrityChecksum
+	for _, table := range models.IntegrityTables {
+		tableSums, err := s.integrity.ChecksumHours(ctx, table, run.WindowStart, run.WindowEnd)
+		if err != nil {
+			s.recordRun("error", started)
+			return nil, err
+		}
+		for _, checksum := range tableSums {
+			checksum.Hour = checksum.Hour.UTC()
+			run.TotalRows += checksum.RowCount
+		}
+		checksums = append(checksums, tableSums...)
+	}
+
+	run.DurationMs = s.now().Sub(started).Milliseconds()
+	if err := s.integrity.CreateRun(ctx, run, checksums); err != nil {
+		s.recordRun("error", started)
+		return nil, err
+	}
+
+	comparison, err := s.compare(ctx, run, checksums)
+	if err != nil {
+		s.recordRun("error", started)
+		return nil, err
+	}
+	s.recordRun("success", started)
+
+	if comparison.RowsMissing > 0 {
+		s.reportMissingRows(comparison)
+	}
+
+	if deleted, err := s.integrity.DeleteRunsBefore(ctx, started.Add(-s.cfg.RetentionPeriod)); err != nil {
+		s.logger.WithError(err).Warn("Failed to delete expired integrity runs")
+	} else if deleted > 0 {
+		s.logger.WithField("deleted", deleted).Debug("Expired integrity runs deleted")
+	}
+
+	s.logger.WithFields(logging.Fields{
+		"run_id":       run.ID.String(),
+		"total_rows":   run.TotalRows,
+		"rows_missing": comparison.RowsMissing,
+		"differences":  len(comparison.Differences),
+	}).Info("Observability tables checksummed")
+	return comparison, nil
+}
+
+// ListRuns returns the latest runs, newest first
+func (s *IntegrityService) ListRuns(ctx context.Context, limit int) ([]*models.IntegrityRun, error) {
+	return s.integrity.ListRuns(ctx, limit)
+}
+
+// Compare compares a run with the run before it, or the latest run when runID is empty
+func (s *IntegrityService) Compare(ctx context.Context, runID string) (*models.IntegrityComparison, error) {
+	var run *models.IntegrityRun
+	if runID == "" {
+		runs, err := s.integrity.ListRuns(ctx, 1)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) == 0 {
+			return nil, &models.NotFoundError{Resource: "integrity run", ID: "latest"}
+		}
+		run = runs[0]
+	} else {
+		var err error
+		if run, err = s.integrity.GetRun(ctx, runID); err != nil {
+			return nil, err
+		}
+	}
+
+	checksums, err := s.integrity.ListChecksums(ctx, run.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	return s.compare(ctx, run, checksums)
+}
+
+// compare compares a run's checksums with those of the run before it
+func (s *IntegrityService) compare(ctx context.Context, run *models.IntegrityRun, checksums []*models.IntegrityChecksum) (*models.IntegrityComparison, error) {
+	previous, err := s.integrity.GetPreviousRun(ctx, run)
+	if err != nil {
+		return nil, err
+	}
+	if previous == nil {
+		return &models.IntegrityComparison{
+			Current:     run,
+			Differences: []*models.IntegrityDifference{},
+		}, nil
+	}
+
+	previousSums, err := s.integrity.ListChecksums(ctx, previous.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	return models.CompareIntegrityChecksums(previous, run, previousSums, checksums), nil
+}
+
+// recordRun counts an integrity run by outcome and records its duration
+func (s *IntegrityService) recordRun(outcome string, started time.Time) {
+	if s.metrics == nil {
+		return
+	}
+	s.metrics.RecordBusinessMetrics("integrity_check_runs_total", 1, map[string]string{"outcome": outcome})
+	s.metrics.RecordBusinessMetrics("integrity_check_duration_seconds", s.now().Sub(started).Seconds(), nil)
+}
+
+// reportMissingRows logs, counts and publishes the rows a run found missing since the run before
+func (s *IntegrityService) reportMissingRows(comparison *models.IntegrityComparison) {
+	missing := make(map[string]int64)
+	for _, difference := range comparison.Differences {
+		if difference.Kind == models.IntegrityRowsMissing {
+			missing[difference.Table] += difference.PreviousCount - difference.CurrentCount
+		}
+	}
+
+	message := fmt.Sprintf("Observability tables lost %d rows since the previous integrity run", comparison.RowsMissing)
+	s.logger.WithFields(logging.Fields{
+		"run_id":          comparison.Current.ID.String(),
+		"previous_run_id": comparison.Previous.ID.String(),
+		"rows_missing":    comparison.RowsMissing,
+		"tables":          missing,
+	}).Warn(message)
+
+	if s.metrics != nil {
+		for table, rows := range missing {
+			s.metrics.RecordBusinessMetrics("integrity_rows_missing_total", float64(rows), map[string]string{"table": table})
+		}
+	}
+
+	if s.events == nil {
+		return
+	}
+
+	entityType, entityID := integrityRunEntityType, comparison.Current.ID
+	eventLog := &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     "observability_rows_missing",
+		EventCategory: models.EventCategorySystem,
+		EntityType:    &entityType,
+		EntityID:      &entityID,
+		Severity:      models.EventSeverityWarn,
+		Message:       message,
+		Timestamp:     time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	eventLog.EventData, _ = json.Marshal(map[string]interface{}{
+		"run_id":          comparison.Current.ID,
+		"previous_run_id": comparison.Previous.ID,
+		"rows_missing":    comparison.RowsMissing,
+		"tables":          missing,
+	})
+	s.events.Publish(bus.TopicEventLog, eventLog)
+}
@@ -2,17 +2,156 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
 )
 
 // RideServiceInterface defines the interface for ride service operations
 type RideServiceInterface interface {
-	RequestRide(ctx context.Context, passengerID string, pickup, dropoff models.Location, pickupAddr, dropoffAddr string) (*models.Trip, error)
-	CancelRide(ctx context.Context, tripID, reason string) error
+	RequestRide(ctx context.Context, passengerID string, pickup, dropoff models.Location, pickupAddr, dropoffAddr string, opts ...RideOption) (*models.Trip, error)
+	CancelRide(ctx context.Context, tripID string, cancellation models.TripCancellation) error
+	CancellationQuote(ctx context.Context, tripID string, reason models.CancellationReason) (*models.CancellationQuote, error)
 	GetTripStatus(ctx context.Context, tripID string) (*models.Trip, error)
 	ListRides(ctx context.Context, passengerID, driverID *string, status *string, limit, offset int) ([]*models.Trip, int64, error)
 }
 
+// TripEventPublisher notifies external consumers of trip lifecycle transitions
+type TripEventPublisher interface {
+	PublishTripEvent(ctx context.Context, event models.WebhookEvent, trip *models.Trip) error
+}
+
+// BusinessMetricsRecorder records business metrics; traditional.TraditionalMonitor satisfies it
+type BusinessMetricsRecorder interface {
+	RecordBusinessMetrics(metricName string, value float64, tags map[string]string)
+}
+
+// LatencyRecorder records the latency histograms; traditional.TraditionalMonitor satisfies it
+type LatencyRecorder interface {
+	RecordMatchingDuration(mode string, duration time.Duration)
+	RecordTripDuration(duration time.Duration)
+}
+
+// CorporateBilling bills trips to the corporate account of their passenger
+type CorporateBilling interface {
+	// ChargeTrip charges the trip's estimated fare to the passenger's corporate account, failing
+	// with ErrCorporateSpendingLimitExceeded if it would exceed the account's monthly limit
+	ChargeTrip(ctx context.Context, passenger *models.Passenger, trip *models.Trip, estimatedFare float64) error
+}
+
+// FareDisputeNotifier notifies external consumers when a fare dispute is resolved or rejected
+type FareDisputeNotifier interface {
+	PublishFareDisputeEvent(ctx context.Context, event models.WebhookEvent, trip *models.Trip, dispute *models.FareDispute) error
+}
+
+// SafetyIncidentNotifier alerts external consumers when an SOS is raised
+type SafetyIncidentNotifier interface {
+	PublishSafetyIncident(ctx context.Context, trip *models.Trip, incident *models.SafetyIncident) error
+}
+
+// SessionServiceInterface defines the interface for driver and passenger device sessions
+type SessionServiceInterface interface {
+	Login(ctx context.Context, creds LoginCredentials) (*models.SessionTokens, error)
+	Refresh(ctx context.Context, refreshToken, clientIP string, app ClientApp) (*models.SessionTokens, error)
+	Authenticate(ctx context.Context, accessToken string) (*models.Session, error)
+	ListSessions(ctx context.Context, userID string) ([]*models.Session, error)
+	RevokeSession(ctx context.Context, userID, sessionID, clientIP string) error
+	CountSessionsByAppVersion(ctx context.Context) ([]*models.AppVersionSessions, error)
+}
+
+// FareDisputeServiceInterface defines the interface for the fare dispute workflow
+type FareDisputeServiceInterface interface {
+	OpenDispute(ctx context.Context, tripID, passengerID, reason string, requestedFare *float64) (*models.FareDispute, error)
+	GetDispute(ctx context.Context, id string) (*models.FareDispute, error)
+	ListDisputes(ctx context.Context, filter models.FareDisputeFilter) ([]*models.FareDispute, int64, error)
+	ListAdjustments(ctx context.Context, tripID string) ([]*models.FareAdjustment, error)
+	StartReview(ctx context.Context, id, reviewer string) (*models.FareDispute, error)
+	Resolve(ctx context.Context, id, reviewer string, adjustedFare float64, note string) (*models.FareDispute, error)
+	Reject(ctx context.Context, id, reviewer, note string) (*models.FareDispute, error)
+}
+
+// SafetyIncidentServiceInterface defines the interface for SOS safety incidents and their review
+type SafetyIncidentServiceInterface interface {
+	Report(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType, location *models.Location, note string) (*models.SafetyIncident, error)
+	GetIncident(ctx context.Context, id string) (*models.SafetyIncident, error)
+	ListIncidents(ctx context.Context, filter models.SafetyIncidentFilter) ([]*models.SafetyIncident, int64, error)
+	StartReview(ctx context.Context, id, reviewer string) (*models.SafetyIncident, error)
+	Close(ctx context.Context, id, reviewer, note string) (*models.SafetyIncident, error)
+}
+
+// TripCompletionServiceInterface defines the interface for completing trips with driver-entered details
+type TripCompletionServiceInterface interface {
+	Complete(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType, details models.TripCompletionDetails) (*models.TripCompletion, error)
+	GetCompletion(ctx context.Context, tripID string) (*models.TripCompletion, error)
+	ListCompletions(ctx context.Context, filter models.TripCompletionFilter) ([]*models.TripCompletion, int64, error)
+}
+
+// PickupWaitServiceInterface defines the interface for drivers waiting for passengers at the pickup
+type PickupWaitServiceInterface interface {
+	Arrive(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType) (*models.PickupWait, error)
+	PickUp(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType) (*models.PickupWait, error)
+	ReportNoShow(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType) (*models.PickupWait, error)
+	GetWait(ctx context.Context, tripID string) (*models.PickupWait, error)
+	GetZoneStats(ctx context.Context, from, to time.Time) ([]*models.PickupWaitZoneStats, error)
+}
+
+// TripRematchServiceInterface defines the interface for drivers cancelling trips before pickup
+// and the trips being matched again
+type TripRematchServiceInterface interface {
+	CancelByDriver(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType, note string) (*models.TripRematch, error)
+	ListRematches(ctx context.Context, tripID string) ([]*models.TripRematch, error)
+}
+
+// IncentiveServiceInterface defines the interface for driver incentive campaigns
+type IncentiveServiceInterface interface {
+	CreateCampaign(ctx context.Context, campaign *models.IncentiveCampaign) (*models.IncentiveCampaign, error)
+	ListCampaigns(ctx context.Context, runningOnly bool) ([]*models.IncentiveCampaign, error)
+	SetCampaignActive(ctx context.Context, id string, active bool) (*models.IncentiveCampaign, error)
+	GetDriverQuests(ctx context.Context, driverID string) ([]*models.DriverQuest, error)
+	ListDriverPayouts(ctx context.Context, driverID string) ([]*models.IncentivePayout, error)
+}
+
 // Ensure RideService implements RideServiceInterface
 var _ RideServiceInterface = (*RideService)(nil)
+
+// Ensure WebhookDispatcher implements TripEventPublisher
+var _ TripEventPublisher = (*WebhookDispatcher)(nil)
+
+// Ensure WebhookDispatcher implements FareDisputeNotifier
+var _ FareDisputeNotifier = (*WebhookDispatcher)(nil)
+
+// Ensure WebhookDispatcher implements SafetyIncidentNotifier
+var _ SafetyIncidentNotifier = (*WebhookDispatcher)(nil)
+
+// Ensure CorporateAccountService implements CorporateBilling
+var _ CorporateBilling = (*CorporateAccountService)(nil)
+
+// Ensure SessionService implements SessionServiceInterface
+var _ SessionServiceInterface = (*SessionService)(nil)
+
+// Ensure FareDisputeService implements FareDisputeServiceInterface
+var _ FareDisputeServiceInterface = (*FareDisputeService)(nil)
+
+// Ensure SafetyIncidentService implements SafetyIncidentServiceInterface
+var _ SafetyIncidentServiceInterface = (*SafetyIncidentService)(nil)
+
+// Ensure TripCompletionService implements TripCompletionServiceInterface
+var _ TripCompletionServiceInterface = (*TripCompletionService)(nil)
+
+// Ensure PickupWaitService implements PickupWaitServiceInterface
+var _ PickupWaitServiceInterface = (*PickupWaitService)(nil)
+
+// Ensure RideService implements TripRematchServiceInterface
+var _ TripRematchServiceInterface = (*RideService)(nil)
+
+// Ensure IncentiveService implements IncentiveServiceInterface and receives trip events
+var (
+	_ IncentiveServiceInterface = (*IncentiveService)(nil)
+	_ TripEventPublisher        = (*IncentiveService)(nil)
+	_ TripEventPublisher        = TripEventPublishers(nil)
+)
+
+// Ensure EmailNotifier receives trip events
+var _ TripEventPublisher = (*EmailNotifier)(nil)
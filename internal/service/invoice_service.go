@@ -0,0 +1,551 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/invoice"
+	"actor-model-observability/internal/lock"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+// invoiceRetryBatch caps the failed invoices of earlier months retried per check
+const invoiceRetryBatch = 100
+
+// invoiceContentTypes are the content types invoice files are stored with, by format
+var invoiceContentTypes = map[models.InvoiceFormat]string{
+	models.InvoiceFormatCSV: "text/csv",
+	models.InvoiceFormatPDF: "application/pdf",
+}
+
+// Column headers of the invoice line items
+var (
+	corporateInvoiceColumns = []string{"passenger_id", "trips", "amount"}
+	fleetInvoiceColumns     = []string{"driver_id", "vehicle_plate", "trips_completed", "trips_cancelled", "earnings"}
+)
+
+// InvoiceService generates the monthly invoices of corporate accounts, from their statements,
+// and of fleet partners, from their drivers' earnings, over calendar months in UTC. Each invoice
+// is stored as files in the configured formats and recorded with its generation status, so the
+// batch job retries failed ones and never generates an invoice twice; admins can regenerate any.
+type InvoiceService struct {
+	invoices  repository.InvoiceRepository
+	accounts  repository.CorporateAccountRepository
+	fleets    repository.FleetRepository
+	corporate *CorporateAccountService
+	fleet     *FleetService
+	store     storage.Store
+	cfg       config.InvoiceConfig
+	urlTTL    time.Duration
+	locker    *lock.Locker
+	logger    *logging.Logger
+	now       func() time.Time
+
+	// mu serializes the runs of this instance; the locker serializes those of every instance
+	mu sync.Mutex
+	// completed is the last month the job generated every invoice of
+	completed string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewInvoiceService creates a new invoice service storing files in store, whose signed download
+// URLs stay valid for urlTTL
+func NewInvoiceService(
+	invoices repository.InvoiceRepository,
+	accounts repository.CorporateAccountRepository,
+	fleets repository.FleetRepository,
+	corporate *CorporateAccountService,
+	fleet *FleetService,
+	store storage.Store,
+	cfg config.InvoiceConfig,
+	urlTTL time.Duration,
+	logger *logging.Logger,
+) *InvoiceService {
+	return &InvoiceService{
+		invoices:  invoices,
+		accounts:  accounts,
+		fleets:    fleets,
+		corporate: corporate,
+		fleet:     fleet,
+		store:     store,
+		cfg:       cfg,
+		urlTTL:    urlTTL,
+		logger:    logger.WithComponent("invoice_service"),
+		now:       time.Now,
+	}
+}
+
+// SetLocker sets the locker runs take a lock of their month with, so that the batch job and
+// admin runs on other instances do not generate the same invoices at once
+func (s *InvoiceService) SetLocker(locker *lock.Locker) {
+	s.locker = locker
+}
+
+// Start generates the invoices due in the background, immediately and then on every check
+// interval
+func (s *InvoiceService) Start(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go s.checkLoop()
+
+	s.logger.Info("Invoice generator started")
+	return nil
+}
+
+// Stop stops the check loop
+func (s *InvoiceService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Invoice generator stopped")
+	return nil
+}
+
+// checkLoop generates the invoices due now and on every check interval
+func (s *InvoiceService) checkLoop() {
+	defer s.wg.Done()
+
+	s.runDue(s.ctx)
+
+	ticker := time.NewTicker(s.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.runDue(s.ctx)
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// runDue generates the invoices of the previous month until all of them are, then retries the
+// failed invoices of earlier months
+func (s *InvoiceService) runDue(ctx context.Context) {
+	month := s.previousMonth()
+	if s.completed != month {
+		run, err := s.Generate(ctx, month)
+		if err != nil {
+			s.logger.WithError(err).WithField("month", month).Error("Invoice run failed")
+			return
+		}
+		if run.Failed == 0 {
+			s.completed = month
+		}
+	}
+
+	if err := s.retryFailed(ctx, month); err != nil {
+		s.logger.WithError(err).Error("Invoice retry failed")
+	}
+}
+
+// Generate generates the invoices of every corporate account and fleet partner for a month
+// formatted as 2006-01, the previous month when empty, which must be over. Invoices already generated, and failed ones whose
+// attempts are exhausted, are skipped; the others are attempted and recorded whether they are
+// generated or fail.
+func (s *InvoiceService) Generate(ctx context.Context, month string) (*models.InvoiceRun, error) {
+	if month == "" {
+		month = s.previousMonth()
+	}
+	start, end, err := s.invoicePeriod(month)
+	if err != nil {
+		return nil, err
+	}
+
+	run := &models.InvoiceRun{Month: month, Invoices: []*models.Invoice{}}
+	err = s.withRunLock(ctx, month, func(ctx context.Context) error {
+		invoices, err := s.partyInvoices(ctx, month, end)
+		if err != nil {
+			return err
+		}
+
+		for _, invoice := range invoices {
+			if invoice.Status == models.InvoiceGenerated || (invoice.Status == models.InvoiceFailed && invoice.Attempts >= s.cfg.MaxAttempts) {
+				run.Skipped++
+			} else if err := s.generate(ctx, invoice, start, end); err != nil {
+				return err
+			} else if invoice.Status == models.InvoiceGenerated {
+				run.Generated++
+			} else {
+				run.Failed++
+			}
+			run.Invoices = append(run.Invoices, invoice)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachURLs(ctx, run.Invoices...); err != nil {
+		return nil, err
+	}
+	s.logger.WithFields(logging.Fields{
+		"month":     month,
+		"generated": run.Generated,
+		"failed":    run.Failed,
+		"skipped":   run.Skipped,
+	}).Info("Invoice run finished")
+	return run, nil
+}
+
+// Retry generates an invoice again whatever its status, replacing its files
+func (s *InvoiceService) Retry(ctx context.Context, id string) (*models.Invoice, error) {
+	invoice, err := s.invoices.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	start, end, err := s.invoicePeriod(invoice.Month)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.withRunLock(ctx, invoice.Month, func(ctx context.Context) error {
+		return s.generate(ctx, invoice, start, end)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invoice, s.attachURLs(ctx, invoice)
+}
+
+// Get returns an invoice with the download URLs of its files
+func (s *InvoiceService) Get(ctx context.Context, id string) (*models.Invoice, error) {
+	invoice, err := s.invoices.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return invoice, s.attachURLs(ctx, invoice)
+}
+
+// List returns the matching invoices with the download URLs of their files, newest month
+// first, and the total number of matches
+func (s *InvoiceService) List(ctx context.Context, filter models.InvoiceFilter) ([]*models.Invoice, int64, error) {
+	invoices, total, err := s.invoices.List(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return invoices, total, s.attachURLs(ctx, invoices...)
+}
+
+// retryFailed generates again the failed invoices of months other than skip whose attempts are
+// not exhausted, up to a page of them per check
+func (s *InvoiceService) retryFailed(ctx context.Context, skip string) error {
+	failed, _, err := s.invoices.List(ctx, models.InvoiceFilter{Status: models.InvoiceFailed, Limit: invoiceRetryBatch})
+	if err != nil {
+		return fmt.Errorf("failed to list failed invoices: %w", err)
+	}
+
+	for _, invoice := range failed {
+		if invoice.Month == skip || invoice.Attempts >= s.cfg.MaxAttempts {
+			continue
+		}
+		start, end, err := s.invoicePeriod(invoice.Month)
+		if err != nil {
+			return err
+		}
+		err = s.withRunLock(ctx, invoice.Month, func(ctx context.Context) error {
+			return s.generate(ctx, invoice, start, end)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// partyInvoices returns the invoice of every corporate account and fleet that existed before
+// the month ended, recording pending invoices for those without one
+func (s *InvoiceService) partyInvoices(ctx context.Context, month string, end time.Time) ([]*models.Invoice, error) {
+	accounts, err := s.accounts.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list corporate accounts: %w", err)
+	}
+	fleets, err := s.fleets.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fleets: %w", err)
+	}
+
+	var invoices []*models.Invoice
+	add := func(partyType models.InvoicePartyType, partyID uuid.UUID, name string, createdAt time.Time) error {
+		if !createdAt.Before(end) {
+			return nil
+		}
+		invoice, err := s.invoiceFor(ctx, partyType, partyID, name, month)
+		if err != nil {
+			return err
+		}
+		invoices = append(invoices, invoice)
+		return nil
+	}
+	for _, account := range accounts {
+		if err := add(models.InvoicePartyCorporateAccount, account.ID, account.Name, account.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+	for _, fleet := range fleets {
+		if err := add(models.InvoicePartyFleet, fleet.ID, fleet.Name, fleet.CreatedAt); err != nil {
+			return nil, err
+		}
+	}
+	return invoices, nil
+}
+
+// invoiceFor returns a party's invoice for a month, creating it pending if it has none
+func (s *InvoiceService) invoiceFor(ctx context.Context, partyType models.InvoicePartyType, partyID uuid.UUID, name, month string) (*models.Invoice, error) {
+	invoice, err := s.invoices.GetByParty(ctx, partyType, partyID.String(), month)
+	var notFound *models.NotFoundError
+	if !errors.As(err, &notFound) {
+		return invoice, err
+	}
+
+	now := s.now()
+	invoice = &models.Invoice{
+		ID:        uuid.New(),
+		PartyType: partyType,
+		PartyID:   partyID,
+		PartyName: name,
+		Month:     month,
+		Status:    models.InvoicePending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.invoices.Create(ctx, invoice); err != nil {
+		if errors.Is(err, models.ErrDuplicateEntry) {
+			return s.invoices.GetByParty(ctx, partyType, partyID.String(), month)
+		}
+		return nil, err
+	}
+	return invoice, nil
+}
+
+// generate renders an invoice over [start, end) and stores its files, recording whether it was
+// generated or failed. Only a failure to record the outcome is returned.
+func (s *InvoiceService) generate(ctx context.Context, inv *models.Invoice, start, end time.Time) error {
+	now := s.now()
+	inv.Attempts++
+	inv.UpdatedAt = now
+
+	keys, err := s.render(ctx, inv, start, end)
+	if err != nil {
+		message := err.Error()
+		inv.Status = models.InvoiceFailed
+		inv.LastError = &message
+		s.logger.WithError(err).WithFields(logging.Fields{
+			"invoice_id": inv.ID.String(),
+			"party_type": string(inv.PartyType),
+			"party_id":   inv.PartyID.String(),
+			"month":      inv.Month,
+			"attempts":   inv.Attempts,
+		}).Warn("Invoice generation failed")
+	} else {
+		inv.Status = models.InvoiceGenerated
+		inv.LastError = nil
+		inv.CSVKey = keys[models.InvoiceFormatCSV]
+		inv.PDFKey = keys[models.InvoiceFormatPDF]
+		inv.GeneratedAt = &now
+	}
+
+	if err := s.invoices.Update(ctx, inv); err != nil {
+		return fmt.Errorf("failed to record invoice generation: %w", err)
+	}
+	return nil
+}
+
+// render builds an invoice's document and stores it in every configured format, returning the
+// key of each file
+func (s *InvoiceService) render(ctx context.Context, inv *models.Invoice, start, end time.Time) (map[models.InvoiceFormat]*string, error) {
+	var doc *invoice.Document
+	var err error
+	switch inv.PartyType {
+	case models.InvoicePartyCorporateAccount:
+		doc, err = s.corporateDocument(ctx, inv)
+	case models.InvoicePartyFleet:
+		doc, err = s.fleetDocument(ctx, inv, start, end)
+	default:
+		err = fmt.Errorf("unsupported invoice party type: %s", inv.PartyType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[models.InvoiceFormat]*string, len(s.cfg.Formats))
+	for _, name := range s.cfg.Formats {
+		format := models.InvoiceFormat(name)
+		var buf bytes.Buffer
+		switch format {
+		case models.InvoiceFormatCSV:
+			err = doc.WriteCSV(&buf)
+		case models.InvoiceFormatPDF:
+			err = doc.WritePDF(&buf)
+		default:
+			err = fmt.Errorf("unsupported invoice format: %s", format)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to render %s invoice: %w", format, err)
+		}
+
+		key := fmt.Sprintf("invoices/%s/%s/%s.%s", inv.Month, inv.PartyType, inv.PartyID, format)
+		if err := s.store.Put(ctx, key, buf.Bytes(), invoiceContentTypes[format]); err != nil {
+			return nil, fmt.Errorf("failed to store %s invoice: %w", format, err)
+		}
+		keys[format] = &key
+	}
+	return keys, nil
+}
+
+// corporateDocument builds a corporate account's invoice from its statement of the month, with
+// a line per passenger
+func (s *InvoiceService) corporateDocument(ctx context.Context, inv *models.Invoice) (*invoice.Document, error) {
+	statement, err := s.corporate.GetStatement(ctx, inv.PartyID.String(), inv.Month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get corporate statement: %w", err)
+	}
+	inv.PartyName = statement.AccountName
+	inv.Trips = statement.Trips
+	inv.TotalAmount = statement.TotalSpend
+
+	rows := make([][]string, 0, len(statement.Passengers))
+	for _, line := range statement.Passengers {
+		rows = append(rows, []string{line.PassengerID.String(), strconv.Itoa(line.Trips), formatInvoiceAmount(line.TotalSpend)})
+	}
+	return &invoice.Document{
+		Title:   "Invoice",
+		Header:  s.invoiceHeader(inv, "Corporate account", statement.PeriodStart, statement.PeriodEnd),
+		Columns: corporateInvoiceColumns,
+		Rows:    rows,
+		Summary: []invoice.Field{
+			{Label: "Trips", Value: strconv.Itoa(statement.Trips)},
+			{Label: "Completed trips", Value: strconv.Itoa(statement.CompletedTrips)},
+			{Label: "Cancelled trips", Value: strconv.Itoa(statement.CancelledTrips)},
+			{Label: "Total due", Value: formatInvoiceAmount(statement.TotalSpend)},
+		},
+	}, nil
+}
+
+// fleetDocument builds a fleet partner's statement of its drivers' earnings over [start, end),
+// with a line per driver
+func (s *InvoiceService) fleetDocument(ctx context.Context, inv *models.Invoice, start, end time.Time) (*invoice.Document, error) {
+	fleet, err := s.fleets.GetByID(ctx, inv.PartyID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fleet: %w", err)
+	}
+	earnings, err := s.fleet.GetEarnings(ctx, fleet, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fleet earnings: %w", err)
+	}
+
+	var completed, cancelled int
+	var total float64
+	rows := make([][]string, 0, len(earnings))
+	for _, e := range earnings {
+		completed += e.TripsCompleted
+		cancelled += e.TripsCancelled
+		total += e.Earnings
+		rows = append(rows, []string{
+			e.DriverID.String(),
+			e.VehiclePlate,
+			strconv.Itoa(e.TripsCompleted),
+			strconv.Itoa(e.TripsCancelled),
+			formatInvoiceAmount(e.Earnings),
+		})
+	}
+	inv.PartyName = fleet.Name
+	inv.Trips = completed
+	inv.TotalAmount = roundFare(total)
+
+	return &invoice.Document{
+		Title:   "Earnings statement",
+		Header:  s.invoiceHeader(inv, "Fleet", start, end),
+		Columns: fleetInvoiceColumns,
+		Rows:    rows,
+		Summary: []invoice.Field{
+			{Label: "Completed trips", Value: strconv.Itoa(completed)},
+			{Label: "Cancelled trips", Value: strconv.Itoa(cancelled)},
+			{Label: "Total earnings", Value: formatInvoiceAmount(inv.TotalAmount)},
+		},
+	}, nil
+}
+
+// invoiceHeader returns the header fields of an invoice over [start, end)
+func (s *InvoiceService) invoiceHeader(inv *models.Invoice, party string, start, end time.Time) []invoice.Field {
+	return []invoice.Field{
+		{Label: "Invoice", Value: inv.ID.String()},
+		{Label: party, Value: inv.PartyName},
+		{Label: party + " ID", Value: inv.PartyID.String()},
+		{Label: "Period", Value: start.Format("2006-01-02") + " to " + end.AddDate(0, 0, -1).Format("2006-01-02") + " (UTC)"},
+		{Label: "Issued", Value: s.now().UTC().Format("2006-01-02")},
+	}
+}
+
+// attachURLs sets the signed download URLs of the generated files of the invoices
+func (s *InvoiceService) attachURLs(ctx context.Context, invoices ...*models.Invoice) error {
+	for _, inv := range invoices {
+		var err error
+		if inv.CSVKey != nil {
+			if inv.CSVURL, err = s.store.SignedURL(ctx, *inv.CSVKey, s.urlTTL); err != nil {
+				return fmt.Errorf("failed to sign invoice URL: %w", err)
+			}
+		}
+		if inv.PDFKey != nil {
+			if inv.PDFURL, err = s.store.SignedURL(ctx, *inv.PDFKey, s.urlTTL); err != nil {
+				return fmt.Errorf("failed to sign invoice URL: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// withRunLock runs fn holding this instance's run mutex and, with a locker, the lock of the month
+func (s *InvoiceService) withRunLock(ctx context.Context, month string, fn func(ctx context.Context) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.locker == nil {
+		return fn(ctx)
+	}
+	return s.locker.WithLock(ctx, "invoices:"+month, fn)
+}
+
+// invoicePeriod returns the calendar month in UTC of a month formatted as 2006-01, which must
+// be over
+func (s *InvoiceService) invoicePeriod(month string) (time.Time, time.Time, error) {
+	monthStart, err := time.Parse(models.CorporateStatementMonthLayout, month)
+	if err != nil {
+		return time.Time{}, time.Time{}, &models.ValidationError{Field: "month", Message: "month must be formatted as YYYY-MM"}
+	}
+	start, end := statementPeriod(monthStart)
+	if end.After(s.now()) {
+		return time.Time{}, time.Time{}, &models.ValidationError{Field: "month", Message: "month must be over"}
+	}
+	return start, end, nil
+}
+
+// previousMonth returns the month before the current one, formatted as 2006-01
+func (s *InvoiceService) previousMonth() string {
+	current, _ := statementPeriod(s.now())
+	return current.AddDate(0, -1, 0).Format(models.CorporateStatementMonthLayout)
+}
+
+// formatInvoiceAmount formats an amount with two decimals
+func formatInvoiceAmount(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
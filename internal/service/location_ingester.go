@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/traditional"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// driverLocationKeyPrefix prefixes the Redis keys holding the latest location of each driver
+const driverLocationKeyPrefix = "driver:location:"
+
+// LocationIngestStats is the state of the driver location buffer
+type LocationIngestStats struct {
+	Pending              int        `json:"pending"`                // drivers whose latest location is not in the database yet
+	OldestPendingSeconds float64    `json:"oldest_pending_seconds"` // how far behind the database is at worst
+	Received             int64      `json:"received"`
+	Coalesced            int64      `json:"coalesced"` // updates replaced by a newer one before being written
+	Written              int64      `json:"written"`   // locations written to the database
+	Flushes              int64      `json:"flushes"`
+	FailedFlushes        int64      `json:"failed_flushes"`
+	LastFlushAt          *time.Time `json:"last_flush_at,omitempty"`
+}
+
+// LocationIngester buffers driver location updates. Drivers report their location every few
+// seconds, and writing each report to the database as it comes makes location updates most of the
+// database's writes. Only the latest update of each driver is kept and the buffered locations are
+// written in one transaction on an interval, or sooner once enough drivers are waiting. Redis, when
+// configured, gets every update at once so matching uses where drivers are now rather than where
+// the database last saw them.
+type LocationIngester struct {
+	cfg     config.LocationIngestConfig
+	drivers repository.DriverRepository
+	cache   *redis.Client
+	monitor *traditional.TraditionalMonitor
+	logger  *logging.Logger
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]*models.DriverLocationUpdate
+	known   map[uuid.UUID]bool // drivers known to exist, so their updates skip the lookup
+	stats   LocationIngestStats
+
+	flushMu  sync.Mutex // one write to the database at a time, so an older batch never lands last
+	flushNow chan struct{}
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewLocationIngester creates a new location ingester. cache may be nil, in which case matching
+// uses the locations still buffered on this instance.
+func NewLocationIngester(cfg config.LocationIngestConfig, drivers repository.DriverRepository, cache *redis.Client, monitor *traditional.TraditionalMonitor, logger *logging.Logger) *LocationIngester {
+	return &LocationIngester{
+		cfg:      cfg,
+		drivers:  drivers,
+		cache:    cache,
+		monitor:  monitor,
+		logger:   logger.WithComponent("location_ingester"),
+		pending:  make(map[uuid.UUID]*models.DriverLocationUpdate),
+		known:    make(map[uuid.UUID]bool),
+		flushNow: make(chan struct{}, 1),
+	}
+}
+
+// Start starts writing the buffered locations to the database
+func (li *LocationIngester) Start(ctx context.Context) error {
+	li.ctx, li.cancel = context.WithCancel(ctx)
+
+	li.wg.Add(1)
+	go li.run()
+
+	li.logger.WithFields(logging.Fields{
+		"flush_interval": li.cfg.FlushInterval.String(),
+		"max_pending":    li.cfg.MaxPending,
+		"redis":          li.cache != nil,
+	}).Info("Location ingester started")
+	return nil
+}
+
+// Stop stops the ingester once the buffered locations are written
+func (li *LocationIngester) Stop() error {
+	if li.cancel != nil {
+		li.cancel()
+	}
+	li.wg.Wait()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err := li.Flush(ctx)
+
+	li.logger.Info("Location ingester stopped")
+	return err
+}
+
+// Ingest buffers a driver's location, replacing any location of theirs not yet written, and
+// caches it in Redis. It fails with a NotFoundError for drivers that do not exist. Updates older
+// than the driver's buffered location arrived out of order and are dropped.
+func (li *LocationIngester) Ingest(ctx context.Context, update *models.DriverLocationUpdate) error {
+	li.mu.Lock()
+	known := li.known[update.DriverID]
+	li.mu.Unlock()
+	if !known {
+		if _, err := li.drivers.GetByID(ctx, update.DriverID.String()); err != nil {
+			return err
+		}
+	}
+
+	li.mu.Lock()
+	li.known[update.DriverID] = true
+	li.stats.Received++
+	previous, buffered := li.pending[update.DriverID]
+	if buffered && previous.At.After(update.At) {
+		li.stats.Coalesced++
+		li.mu.Unlock()
+		li.recordCoalesced()
+		return nil
+	}
+	li.pending[update.DriverID] = update
+	if buffered {
+		li.stats.Coalesced++
+	}
+	full := len(li.pending) >= li.cfg.MaxPending
+	li.mu.Unlock()
+
+	if buffered {
+		li.recordCoalesced()
+	}
+	if full {
+		select {
+		case li.flushNow <- struct{}{}:
+		default:
+		}
+	}
+
+	li.store(ctx, update)
+	return nil
+}
+
+// Flush writes the buffered locations to the database. Locations that fail to be written are
+// buffered again unless the driver has reported a newer one since.
+func (li *LocationIngester) Flush(ctx context.Context) error {
+	li.flushMu.Lock()
+	defer li.flushMu.Unlock()
+
+	li.mu.Lock()
+	batch := li.pending
+	li.pending = make(map[uuid.UUID]*models.DriverLocationUpdate, len(batch))
+	li.mu.Unlock()
+	if len(batch) == 0 {
+		return nil
+	}
+
+	// Drivers are updated in ID order so concurrent batches of several instances lock rows in the
+	// same order
+	updates := make([]*models.DriverLocationUpdate, 0, len(batch))
+	for _, update := range batch {
+		updates = append(updates, update)
+	}
+	sort.Slice(updates, func(i, j int) bool {
+		return updates[i].DriverID.String() < updates[j].DriverID.String()
+	})
+
+	start := time.Now()
+	updated, err := li.drivers.UpdateLocations(ctx, updates)
+	now := time.Now()
+	li.monitor.RecordDatabaseOperation("UPDATE", "drivers", now.Sub(start), err == nil)
+
+	li.mu.Lock()
+	li.stats.Flushes++
+	if err != nil {
+		li.stats.FailedFlushes++
+		for id, update := range batch {
+			if _, newer := li.pending[id]; !newer {
+				li.pending[id] = update
+			}
+		}
+		li.mu.Unlock()
+		li.monitor.RecordBusinessMetrics("driver_location_flushes_total", 1, map[string]string{"outcome": "error"})
+		return err
+	}
+	li.stats.Written += int64(len(updates))
+	li.stats.LastFlushAt = &now
+	// Some drivers were deleted since their first update; they are looked up again
+	if updated < int64(len(updates)) {
+		li.known = make(map[uuid.UUID]bool)
+	}
+	li.mu.Unlock()
+
+	li.monitor.RecordBusinessMetrics("driver_location_flushes_total", 1, map[string]string{"outcome": "success"})
+	li.monitor.RecordBusinessMetrics("driver_location_flush_batch_size", float64(len(updates)), nil)
+	for _, update := range updates {
+		li.monitor.RecordBusinessMetrics("driver_location_write_lag_seconds", now.Sub(update.At).Seconds(), nil)
+	}
+	return nil
+}
+
+// LatestLocations returns the latest location of the given drivers that is newer than the
+// database's: from Redis when configured, otherwise the ones still buffered on this instance.
+// Drivers without one are left out, and the age of the locations returned is recorded.
+func (li *LocationIngester) LatestLocations(ctx context.Context, driverIDs []uuid.UUID) map[uuid.UUID]*models.DriverLocationUpdate {
+	locations := make(map[uuid.UUID]*models.DriverLocationUpdate)
+	if len(driverIDs) == 0 {
+		return locations
+	}
+
+	if cached, err := li.cached(ctx, driverIDs); err == nil {
+		locations = cached
+	} else {
+		if !errors.Is(err, errNoLocationCache) {
+			li.logger.WithError(err).Warn("Failed to read cached driver locations, using buffered ones")
+		}
+		li.mu.Lock()
+		for _, id := range driverIDs {
+			if update, ok := li.pending[id]; ok {
+				locations[id] = update
+			}
+		}
+		li.mu.Unlock()
+	}
+
+	now := time.Now()
+	for _, update := range locations {
+		li.monitor.RecordBusinessMetrics("driver_location_age_seconds", now.Sub(update.At).Seconds(), nil)
+	}
+	return locations
+}
+
+// Stats returns the state of the buffer
+func (li *LocationIngester) Stats() LocationIngestStats {
+	li.mu.Lock()
+	defer li.mu.Unlock()
+
+	stats := li.stats
+	stats.Pending = len(li.pending)
+	now := time.Now()
+	for _, update := range li.pending {
+		if age := now.Sub(update.At).Seconds(); age > stats.OldestPendingSeconds {
+			stats.OldestPendingSeconds = age
+		}
+	}
+	return stats
+}
+
+// run writes the buffered locations every interval, and sooner when the buffer is full
+func (li *LocationIngester) run() {
+	defer li.wg.Done()
+
+	ticker := time.NewTicker(li.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-li.flushNow:
+		case <-li.ctx.Done():
+			return
+		}
+		if err := li.Flush(li.ctx); err != nil {
+			li.logger.WithError(err).Error("Failed to write driver locations")
+		}
+	}
+}
+
+// errNoLocationCache is returned by cached when Redis is not configured
+var errNoLocationCache = errors.New("driver location cache not configured")
+
+// store caches a driver's location in Redis. Failures are logged; matching then uses the
+// buffered or stored location.
+func (li *LocationIngester) store(ctx context.Context, update *models.DriverLocationUpdate) {
+	if li.cache == nil {
+		return
+	}
+
+	data, err := json.Marshal(update)
+	if err != nil {
+		li.logger.WithError(err).Warn("Failed to encode driver location for caching")
+		return
+	}
+	if err := li.cache.Set(ctx, driverLocationKeyPrefix+update.DriverID.String(), data, li.cfg.CacheTTL).Err(); err != nil {
+		li.logger.WithError(err).WithField("driver_id", update.DriverID).Warn("Failed to cache driver location")
+	}
+}
+
+// Invalidate removes the cached location of a driver, after the driver was changed elsewhere than
+// through location reports, e.g. taken offline by a manual SQL fix, so that matching goes by the
+// database until the driver reports again
+func (li *LocationIngester) Invalidate(ctx context.Context, driverID uuid.UUID) {
+	if li.cache == nil {
+		return
+	}
+	if err := li.cache.Del(ctx, driverLocationKeyPrefix+driverID.String()).Err(); err != nil {
+		li.logger.WithError(err).WithField("driver_id", driverID).Warn("Failed to invalidate cached driver location")
+	}
+}
+
+// cached returns the locations cached in Redis of the given drivers
+func (li *LocationIngester) cached(ctx context.Context, driverIDs []uuid.UUID) (map[uuid.UUID]*models.DriverLocationUpdate, error) {
+	if li.cache == nil {
+		return nil, errNoLocationCache
+	}
+
+	keys := make([]string, len(driverIDs))
+	for i, id := range driverIDs {
+		keys[i] = driverLocationKeyPrefix + id.String()
+	}
+	values, err := li.cache.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	locations := make(map[uuid.UUID]*models.DriverLocationUpdate, len(values))
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+		var update models.DriverLocationUpdate
+		if err := json.Unmarshal([]byte(data), &update); err != nil {
+			continue
+		}
+		locations[driverIDs[i]] = &update
+	}
+	return locations, nil
+}
+
+// recordCoalesced counts an update that replaced, or lost to, one not yet written
+func (li *LocationIngester) recordCoalesced() {
+	li.monitor.RecordBusinessMetrics("driver_location_updates_coalesced_total", 1, nil)
+}
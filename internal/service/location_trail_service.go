@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// Resolutions location pings are downsampled to as they age
+const (
+	locationTrailMinuteResolution     = time.Minute
+	locationTrailTenMinutesResolution = 10 * time.Minute
+)
+
+// LocationTrailService keeps the trail of the locations drivers report, for support and fraud
+// investigations. Every instance records the driver_location pings of its event bus; one
+// instance downsamples the pings every interval, to one per driver and minute after a day and
+// one per ten minutes after a week by default. Old pings are dropped with their partition by the
+// partition manager.
+type LocationTrailService struct {
+	locations repository.DriverLocationRepository
+	drivers   repository.DriverRepository
+	cfg       config.LocationTrailConfig
+	logger    *logging.Logger
+	now       func() time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewLocationTrailService creates a new location trail service
+func NewLocationTrailService(
+	locations repository.DriverLocationRepository,
+	drivers repository.DriverRepository,
+	cfg config.LocationTrailConfig,
+	logger *logging.Logger,
+) *LocationTrailService {
+	return &LocationTrailService{
+		locations: locations,
+		drivers:   drivers,
+		cfg:       cfg,
+		logger:    logger.WithComponent("location_trail_service"),
+		now:       time.Now,
+	}
+}
+
+// Topics returns the event bus topics the service handles
+func (s *LocationTrailService) Topics() []string {
+	return []string{bus.TopicDriverLocation}
+}
+
+// HandleMessage records a location reported by a driver in the driver's trail
+func (s *LocationTrailService) HandleMessage(msg bus.Message) {
+	update, ok := msg.Payload.(*models.DriverLocationUpdate)
+	if !ok {
+		return
+	}
+
+	at := update.At
+	if at.IsZero() {
+		at = msg.PublishedAt
+	}
+	ping := &models.DriverLocationPing{
+		DriverID:   update.DriverID,
+		Latitude:   update.Latitude,
+		Longitude:  update.Longitude,
+		RecordedAt: at.UTC(),
+		Samples:    1,
+	}
+	if err := s.locations.Record(context.Background(), ping); err != nil {
+		s.logger.WithError(err).WithField("driver_id", update.DriverID.String()).Error("Failed to record driver location")
+	}
+}
+
+// GetTrail returns the trail of a driver over [from, to), oldest first, with at most the
+// configured number of pings
+func (s *LocationTrailService) GetTrail(ctx context.Context, driverID string, from, to time.Time) (*models.DriverLocationTrail, error) {
+	if !from.Before(to) {
+		return nil, &models.ValidationError{
+			Field:   "from",
+			Message: "must be before to",
+		}
+	}
+
+	driver, err := s.drivers.GetByID(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+
+	// One ping past the limit tells whether the trail was cut short
+	pings, err := s.locations.GetTrail(ctx, driver.ID.String(), from.UTC(), to.UTC(), s.cfg.MaxPoints+1)
+	if err != nil {
+		return nil, err
+	}
+	trail := &models.DriverLocationTrail{
+		DriverID: driver.ID,
+		From:     from,
+		To:       to,
+		Pings:    pings,
+	}
+	if len(trail.Pings) > s.cfg.MaxPoints {
+		trail.Pings = trail.Pings[:s.cfg.MaxPoints]
+		trail.Truncated = true
+	}
+	if trail.Pings == nil {
+		trail.Pings = []*models.DriverLocationPing{}
+	}
+	return trail, nil
+}
+
+// Downsample downsamples the pings older than the configured ages, the ten-minute tier first so
+// pings are merged straight into it, and returns how many pings were removed. Cutoffs are
+// aligned to the resolution so an interval is only ever downsampled whole.
+func (s *LocationTrailService) Downsample(ctx context.Context) (int64, error) {
+	now := s.now().UTC()
+
+	var removed int64
+	for _, tier := range []struct {
+		after      time.Duration
+		resolution time.Duration
+	}{
+		{s.cfg.TenMinutesAfter, locationTrailTenMinutesResolution},
+		{s.cfg.MinuteAfter, locationTrailMinuteResolution},
+	} {
+		before := now.Add(-tier.after).Truncate(tier.resolution)
+		n, err := s.locations.Downsample(ctx, before, tier.resolution)
+		if err != nil {
+			return removed, err
+		}
+		removed += n
+	}
+
+	if removed > 0 {
+		s.logger.WithField("removed", removed).Info("Driver location trail downsampled")
+	}
+	return removed, nil
+}
+
+// Start starts downsampling the trails every interval
+func (s *LocationTrailService) Start(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go s.downsampleLoop()
+
+	s.logger.Info("Location trail service started")
+	return nil
+}
+
+// Stop stops the downsample loop
+func (s *LocationTrailService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Location trail service stopped")
+	return nil
+}
+
+// downsampleLoop downsamples the trails now and on every interval
+func (s *LocationTrailService) downsampleLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.DownsampleInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Downsample(s.ctx); err != nil && s.ctx.Err() == nil {
+			s.logger.WithError(err).Error("Failed to downsample driver location trail")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
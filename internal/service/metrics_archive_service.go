@@ -0,0 +1,330 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/parquet"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/storage"
+)
+
+// MetricsArchiveContentType is the content type archived metrics files are stored with
+const MetricsArchiveContentType = "application/vnd.apache.parquet"
+
+// metricsArchiveFile is the name of the file of a table's day in its partition
+const metricsArchiveFile = "metrics.parquet"
+
+// metricsArchiveTable is a metrics table archived one day at a time
+type metricsArchiveTable struct {
+	name    string
+	columns []parquet.Column
+	// page returns a page of the metrics recorded in [start, end], newest first
+	page func(ctx context.Context, start, end string, limit, offset int) ([]archivedMetric, error)
+}
+
+// archivedMetric is a metric with its values for the columns of its table
+type archivedMetric struct {
+	id        string
+	timestamp time.Time
+	values    []any
+}
+
+// MetricsArchiveService archives the system and traditional metrics of every finished UTC day as
+// Parquet files, one per table and day, partitioned as <prefix>/<table>/date=YYYY-MM-DD/ so that
+// DuckDB and pandas read them as hive partitions. Days of the backfill period without a file are
+// archived on every check, so days missed while the archiver was down are caught up.
+type MetricsArchiveService struct {
+	store  storage.Store
+	cfg    config.MetricsArchiveConfig
+	tables []metricsArchiveTable
+	logger *logging.Logger
+	now    func() time.Time
+
+	// mu serializes the runs of this instance
+	mu sync.Mutex
+	// archived holds the keys of the files known to be stored
+	archived map[string]bool
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewMetricsArchiveService creates a new metrics archive service storing files in store
+func NewMetricsArchiveService(
+	observability repository.ObservabilityRepository,
+	traditional repository.TraditionalRepository,
+	store storage.Store,
+	cfg config.MetricsArchiveConfig,
+	logger *logging.Logger,
+) *MetricsArchiveService {
+	return &MetricsArchiveService{
+		store: store,
+		cfg:   cfg,
+		tables: []metricsArchiveTable{
+			systemMetricsArchiveTable(observability),
+			traditionalMetricsArchiveTable(traditional),
+		},
+		logger:   logger.WithComponent("metrics_archive_service"),
+		now:      time.Now,
+		archived: make(map[string]bool),
+	}
+}
+
+// Start archives the days due in the background, immediately and then on every check interval
+func (s *MetricsArchiveService) Start(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go s.checkLoop()
+
+	s.logger.Info("Metrics archiver started")
+	return nil
+}
+
+// Stop stops the check loop
+func (s *MetricsArchiveService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Metrics archiver stopped")
+	return nil
+}
+
+// checkLoop archives the days due now and on every check interval
+func (s *MetricsArchiveService) checkLoop() {
+	defer s.wg.Done()
+
+	s.ArchiveDue(s.ctx)
+
+	ticker := time.NewTicker(s.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.ArchiveDue(s.ctx)
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// ArchiveDue archives the tables of the finished days of the backfill period that have no file
+// yet, oldest first
+func (s *MetricsArchiveService) ArchiveDue(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	today := s.now().UTC().Truncate(24 * time.Hour)
+	for days := s.cfg.BackfillDays; days >= 1; days-- {
+		day := today.AddDate(0, 0, -days)
+		for _, table := range s.tables {
+			key := s.key(table, day)
+			stored, err := s.stored(ctx, key)
+			if err != nil {
+				s.logger.WithError(err).WithField("key", key).Error("Failed to check archived metrics")
+				continue
+			}
+			if stored {
+				continue
+			}
+			if err := s.archive(ctx, table, day); err != nil {
+				s.logger.WithError(err).WithField("key", key).Error("Failed to archive metrics")
+			}
+		}
+	}
+}
+
+// Archive writes the files of every table for a UTC day, replacing existing ones
+func (s *MetricsArchiveService) Archive(ctx context.Context, day time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	day = day.UTC().Truncate(24 * time.Hour)
+	for _, table := range s.tables {
+		if err := s.archive(ctx, table, day); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// archive writes the file of a table's day, a row group per page of metrics read
+func (s *MetricsArchiveService) archive(ctx context.Context, table metricsArchiveTable, day time.Time) error {
+	start, end := day.Format(time.RFC3339), day.AddDate(0, 0, 1)
+
+	var buf bytes.Buffer
+	w, err := parquet.NewWriter(&buf, table.columns)
+	if err != nil {
+		return err
+	}
+
+	// Pages are only ordered by timestamp, so a metric sharing its timestamp with others may be
+	// read twice
+	seen := make(map[string]bool)
+	for offset := 0; ; offset += s.cfg.BatchSize {
+		metrics, err := table.page(ctx, start, end.Format(time.RFC3339), s.cfg.BatchSize, offset)
+		if err != nil {
+			return fmt.Errorf("failed to list %s: %w", table.name, err)
+		}
+
+		rows := make([][]any, 0, len(metrics))
+		for _, metric := range metrics {
+			// The range includes its end, which starts the next day
+			if !metric.timestamp.Before(end) || seen[metric.id] {
+				continue
+			}
+			seen[metric.id] = true
+			rows = append(rows, metric.values)
+		}
+		if err := w.WriteRows(rows); err != nil {
+			return err
+		}
+
+		if len(metrics) < s.cfg.BatchSize {
+			break
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	key := s.key(table, day)
+	if err := s.store.Put(ctx, key, buf.Bytes(), MetricsArchiveContentType); err != nil {
+		return fmt.Errorf("failed to store archived %s: %w", table.name, err)
+	}
+	s.archived[key] = true
+
+	s.logger.WithFields(logging.Fields{
+		"key":     key,
+		"metrics": len(seen),
+		"bytes":   buf.Len(),
+	}).Info("Metrics archived")
+	return nil
+}
+
+// stored reports whether the file of a key is stored
+func (s *MetricsArchiveService) stored(ctx context.Context, key string) (bool, error) {
+	if s.archived[key] {
+		return true, nil
+	}
+
+	object, err := s.store.Get(ctx, key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	object.Body.Close()
+	s.archived[key] = true
+	return true, nil
+}
+
+// key returns the key of the file of a table's day
+func (s *MetricsArchiveService) key(table metricsArchiveTable, day time.Time) string {
+	return path.Join(s.cfg.Prefix, table.name, "date="+day.Format("2006-01-02"), metricsArchiveFile)
+}
+
+// systemMetricsArchiveTable archives the system_metrics table
+func systemMetricsArchiveTable(observability repository.ObservabilityRepository) metricsArchiveTable {
+	return metricsArchiveTable{
+		name: "system_metrics",
+		columns: []parquet.Column{
+			{Name: "id", Type: parquet.String},
+			{Name: "metric_name", Type: parquet.String},
+			{Name: "metric_type", Type: parquet.String},
+			{Name: "metric_value", Type: parquet.Double},
+			{Name: "labels", Type: parquet.String, Optional: true},
+			{Name: "actor_type", Type: parquet.String, Optional: true},
+			{Name: "actor_id", Type: parquet.String, Optional: true},
+			{Name: "timestamp", Type: parquet.Timestamp},
+			{Name: "created_at", Type: parquet.Timestamp},
+		},
+		page: func(ctx context.Context, start, end string, limit, offset int) ([]archivedMetric, error) {
+			metrics, err := observability.GetMetricsByTimeRange(ctx, start, end, limit, offset)
+			if err != nil {
+				return nil, err
+			}
+			archived := make([]archivedMetric, len(metrics))
+			for i, m := range metrics {
+				var actorType any
+				if m.ActorType != nil {
+					actorType = string(*m.ActorType)
+				}
+				archived[i] = archivedMetric{
+					id:        m.ID.String(),
+					timestamp: m.Timestamp,
+					values: []any{
+						m.ID.String(), m.MetricName, string(m.MetricType), m.MetricValue, archivedLabels(m.Labels),
+						actorType, archivedString(m.ActorID), m.Timestamp, m.CreatedAt,
+					},
+				}
+			}
+			return archived, nil
+		},
+	}
+}
+
+// traditionalMetricsArchiveTable archives the traditional_metrics table
+func traditionalMetricsArchiveTable(traditional repository.TraditionalRepository) metricsArchiveTable {
+	return metricsArchiveTable{
+		name: "traditional_metrics",
+		columns: []parquet.Column{
+			{Name: "id", Type: parquet.String},
+			{Name: "metric_name", Type: parquet.String},
+			{Name: "metric_type", Type: parquet.String},
+			{Name: "metric_value", Type: parquet.Double},
+			{Name: "labels", Type: parquet.String, Optional: true},
+			{Name: "service_name", Type: parquet.String},
+			{Name: "instance_id", Type: parquet.String},
+			{Name: "timestamp", Type: parquet.Timestamp},
+			{Name: "created_at", Type: parquet.Timestamp},
+		},
+		page: func(ctx context.Context, start, end string, limit, offset int) ([]archivedMetric, error) {
+			metrics, err := traditional.GetTraditionalMetricsByTimeRange(ctx, start, end, limit, offset)
+			if err != nil {
+				return nil, err
+			}
+			archived := make([]archivedMetric, len(metrics))
+			for i, m := range metrics {
+				archived[i] = archivedMetric{
+					id:        m.ID.String(),
+					timestamp: m.Timestamp,
+					values: []any{
+						m.ID.String(), m.MetricName, string(m.MetricType), m.MetricValue, archivedLabels(m.Labels),
+						m.ServiceName, m.InstanceID, m.Timestamp, m.CreatedAt,
+					},
+				}
+			}
+			return archived, nil
+		},
+	}
+}
+
+// archivedLabels returns the JSON labels of a metric as a string, nil without labels
+func archivedLabels(labels []byte) any {
+	if len(labels) == 0 || string(labels) == "null" {
+		return nil
+	}
+	return string(labels)
+}
+
+// archivedString returns a nullable string column value
+func archivedString(s *string) any {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
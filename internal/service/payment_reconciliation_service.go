@@ -0,0 +1,526 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// paymentDiscrepancyEntityType is the entity type of the event logs recorded for a payment
+// discrepancy
+const paymentDiscrepancyEntityType = "payment_discrepancy"
+
+// paymentSettlementResolver is who resolves the discrepancies of trips that payments recorded
+// later balance
+const paymentSettlementResolver = "reconciliation"
+
+// PaymentReconciliationService keeps the trip payments ledger and reconciles it against the fares
+// of completed trips. Every interval the trips completed within the lookback, and longer than the
+// grace period ago, are checked: a trip without any payment, or whose charges less refunds are
+// off its fare by more than the tolerance, opens a payment discrepancy, published as a business
+// event log and counted by type. A trip has at most one open discrepancy of each type, so trips
+// found again while their discrepancy is open are not reported twice. Admins resolve
+// discrepancies by charging the shortfall, refunding the overpayment, writing the difference off
+// or dismissing them; a payment recorded later that balances the trip settles its discrepancies.
+type PaymentReconciliationService struct {
+	payments repository.PaymentRepository
+	trips    repository.TripRepository
+	cfg      config.PaymentReconciliationConfig
+	events   bus.Publisher
+	metrics  BusinessMetricsRecorder
+	logger   *logging.Logger
+	now      func() time.Time
+
+	// runMu serializes the runs of this instance; the unique open discrepancy per trip and type
+	// keeps runs of several instances from reporting a trip twice
+	runMu  sync.Mutex
+	latest *models.PaymentReconciliationRun
+	mu     sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewPaymentReconciliationService creates a new payment reconciliation service. Discrepancies are
+// published on events and counted in metrics; both may be nil.
+func NewPaymentReconciliationService(
+	payments repository.PaymentRepository,
+	trips repository.TripRepository,
+	cfg config.PaymentReconciliationConfig,
+	events bus.Publisher,
+	metrics BusinessMetricsRecorder,
+	logger *logging.Logger,
+) *PaymentReconciliationService {
+	return &PaymentReconciliationService{
+		payments: payments,
+		trips:    trips,
+		cfg:      cfg,
+		events:   events,
+		metrics:  metrics,
+		logger:   logger.WithComponent("payment_reconciliation_service"),
+		now:      time.Now,
+	}
+}
+
+// Start reconciles the completed trips in the background, immediately and then every interval
+func (s *PaymentReconciliationService) Start(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go s.reconcileLoop()
+
+	s.logger.WithFields(logging.Fields{
+		"interval":     s.cfg.Interval.String(),
+		"grace_period": s.cfg.GracePeriod.String(),
+		"lookback":     s.cfg.Lookback.String(),
+	}).Info("Payment reconciliation started")
+	return nil
+}
+
+// Stop stops the reconcile loop
+func (s *PaymentReconciliationService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Payment reconciliation stopped")
+	return nil
+}
+
+// reconcileLoop reconciles the completed trips now and on every interval
+func (s *PaymentReconciliationService) reconcileLoop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Reconcile(s.ctx); err != nil && s.ctx.Err() == nil {
+			s.logger.WithError(err).Error("Payment reconciliation failed")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-s.ctx.Done():
+			return
+		}
+	}
+}
+
+// Reconcile checks the trips completed within the lookback, and longer than the grace period ago,
+// against their payments and opens a discrepancy for each trip found unpaid or paid the wrong
+// amount
+func (s *PaymentReconciliationService) Reconcile(ctx context.Context) (*models.PaymentReconciliationRun, error) {
+	s.runMu.Lock()
+	defer s.runMu.Unlock()
+
+	started := s.now()
+	run := &models.PaymentReconciliationRun{
+		WindowStart:   started.Add(-s.cfg.Lookback),
+		WindowEnd:     started.Add(-s.cfg.GracePeriod),
+		Discrepancies: []*models.PaymentDiscrepancy{},
+		StartedAt:     started,
+	}
+
+	balances, err := s.payments.ListBalances(ctx, run.WindowStart, run.WindowEnd)
+	if err != nil {
+		s.recordRun("error", started)
+		return nil, err
+	}
+
+	for _, balance := range balances {
+		run.Checked++
+		discrepancyType, ok := s.discrepancyType(balance)
+		if !ok {
+			run.Balanced++
+			continue
+		}
+
+		discrepancy := &models.PaymentDiscrepancy{
+			ID:             uuid.New(),
+			TripID:         balance.TripID,
+			Type:           discrepancyType,
+			ExpectedAmount: balance.Fare,
+			PaidAmount:     math.Round(balance.Paid*100) / 100,
+			Status:         models.PaymentDiscrepancyOpen,
+			DetectedAt:     s.now(),
+			UpdatedAt:      s.now(),
+		}
+		if err := s.payments.CreateDiscrepancy(ctx, discrepancy); err != nil {
+			if errors.Is(err, models.ErrDuplicateEntry) {
+				run.AlreadyOpen++
+				continue
+			}
+			s.recordRun("error", started)
+			return nil, err
+		}
+		run.Discrepancies = append(run.Discrepancies, discrepancy)
+
+		if s.metrics != nil {
+			s.metrics.RecordBusinessMetrics("payment_discrepancies_detected_total", 1, map[string]string{
+				"type": string(discrepancy.Type),
+			})
+		}
+		s.publish("payment_discrepancy_detected", discrepancy, models.EventSeverityWarn, map[string]interface{}{
+			"payments": balance.Payments,
+		}, fmt.Sprintf("Completed trip has a payment discrepancy: %s", discrepancy.Type))
+	}
+
+	run.DurationMs = s.now().Sub(started).Milliseconds()
+	s.recordRun("success", started)
+
+	s.mu.Lock()
+	s.latest = run
+	s.mu.Unlock()
+
+	s.logger.WithFields(logging.Fields{
+		"checked":       run.Checked,
+		"discrepancies": len(run.Discrepancies),
+		"already_open":  run.AlreadyOpen,
+	}).Info("Payments reconciled")
+	return run, nil
+}
+
+// LatestRun returns the outcome of the latest reconciliation run of this instance, nil before the
+// first
+func (s *PaymentReconciliationService) LatestRun() *models.PaymentReconciliationRun {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.latest
+}
+
+// discrepancyType returns how a trip's payments disagree with its fare, if they do. Trips without
+// a fare to pay need no payment.
+func (s *PaymentReconciliationService) discrepancyType(balance *models.TripPaymentBalance) (models.PaymentDiscrepancyType, bool) {
+	if balance.Payments == 0 {
+		return models.PaymentDiscrepancyMissing, balance.Fare > s.cfg.Tolerance
+	}
+	return models.PaymentDiscrepancyMismatch, math.Abs(balance.Difference()) > s.cfg.Tolerance
+}
+
+// RecordPayment appends a charge or refund to a completed trip's payments ledger. Open
+// discrepancies of the trip are settled once its payments balance its fare.
+func (s *PaymentReconciliationService) RecordPayment(ctx context.Context, payment *models.TripPayment) (*models.TripPayment, error) {
+	trip, err := s.trips.GetByID(ctx, payment.TripID.String())
+	if err != nil {
+		return nil, err
+	}
+	if !trip.IsCompleted() {
+		return nil, &models.ValidationError{
+			Field:   "trip_id",
+			Message: "payments are recorded for completed trips",
+		}
+	}
+
+	payment.ID = uuid.New()
+	payment.Amount = math.Round(payment.Amount*100) / 100
+	payment.CreatedAt = s.now()
+	if err := payment.Validate(); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "payment",
+			Message: err.Error(),
+		}
+	}
+	if err := s.payments.CreatePayment(ctx, payment); err != nil {
+		return nil, err
+	}
+
+	if s.metrics != nil {
+		s.metrics.RecordBusinessMetrics("trip_payments_recorded_total", 1, map[string]string{
+			"kind":   string(payment.Kind),
+			"method": string(payment.Method),
+		})
+	}
+	s.settle(ctx, trip)
+	return payment, nil
+}
+
+// ListPayments returns a trip's payments ledger, oldest first
+func (s *PaymentReconciliationService) ListPayments(ctx context.Context, tripID string) ([]*models.TripPayment, error) {
+	if _, err := s.trips.GetByID(ctx, tripID); err != nil {
+		return nil, err
+	}
+	return s.payments.ListPaymentsByTrip(ctx, tripID)
+}
+
+// GetDiscrepancy returns a payment discrepancy by ID
+func (s *PaymentReconciliationService) GetDiscrepancy(ctx context.Context, id string) (*models.PaymentDiscrepancy, error) {
+	return s.payments.GetDiscrepancy(ctx, id)
+}
+
+// ListDiscrepancies returns the matching discrepancies, newest first, and the total number of
+// matches
+func (s *PaymentReconciliationService) ListDiscrepancies(ctx context.Context, filter models.PaymentDiscrepancyFilter) ([]*models.PaymentDiscrepancy, int64, error) {
+	return s.payments.ListDiscrepancies(ctx, filter)
+}
+
+// Resolve takes an action on an open discrepancy. record_payment charges what the trip is short
+// of its fare now and refund refunds what it was paid over it, both with method; write_off and
+// dismiss only close the discrepancy. Resolutions are counted by type and action.
+func (s *PaymentReconciliationService) Resolve(ctx context.Context, id string, resolution models.PaymentResolution, method models.PaymentMethod, reference, resolver, note string) (*models.PaymentDiscrepancy, error) {
+	if !resolution.IsValid() || resolution == models.PaymentResolutionSettled {
+		return nil, &models.ValidationError{
+			Field:   "resolution",
+			Message: "must be record_payment, refund, write_off or dismiss",
+		}
+	}
+
+	discrepancy, err := s.payments.GetDiscrepancy(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !discrepancy.Status.CanTransitionTo(resolution.Status()) {
+		return nil, fmt.Errorf("%w: payment discrepancy is %s", models.ErrInvalidStatusTransition, discrepancy.Status)
+	}
+
+	var payment *models.TripPayment
+	if resolution == models.PaymentResolutionRecordPayment || resolution == models.PaymentResolutionRefund {
+		if payment, err = s.resolutionPayment(ctx, discrepancy, resolution, method, reference, resolver); err != nil {
+			return nil, err
+		}
+	}
+
+	from := discrepancy.Status
+	discrepancy.Resolve(resolution, nil, resolver, strings.TrimSpace(note), s.now())
+	if err := discrepancy.Validate(); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "note",
+			Message: err.Error(),
+		}
+	}
+	// The discrepancy is claimed before the payment is recorded, so admins resolving it at once
+	// never charge or refund the passenger twice
+	if err := s.payments.UpdateDiscrepancy(ctx, discrepancy, from); err != nil {
+		return nil, err
+	}
+	if payment != nil {
+		if err := s.payments.CreatePayment(ctx, payment); err != nil {
+			s.reopen(ctx, discrepancy, from)
+			return nil, err
+		}
+		discrepancy.PaymentID = &payment.ID
+		if err := s.payments.UpdateDiscrepancy(ctx, discrepancy, discrepancy.Status); err != nil {
+			s.logger.WithError(err).WithField("discrepancy_id", discrepancy.ID.String()).Warn("Failed to link payment to resolved discrepancy")
+		}
+	}
+
+	s.recordResolution(discrepancy, resolution)
+	data := map[string]interface{}{
+		"resolved_by":     resolver,
+		"resolution_note": discrepancy.ResolutionNote,
+	}
+	if payment != nil {
+		data["payment_id"] = payment.ID
+		data["payment_amount"] = payment.Amount
+	}
+	s.publish("payment_discrepancy_"+string(discrepancy.Status), discrepancy, models.EventSeverityInfo, data,
+		fmt.Sprintf("Payment discrepancy %s with %s", discrepancy.Status, resolution))
+	return discrepancy, nil
+}
+
+// resolutionPayment returns the charge or refund that brings the trip's payments to its fare
+func (s *PaymentReconciliationService) resolutionPayment(ctx context.Context, discrepancy *models.PaymentDiscrepancy, resolution models.PaymentResolution, method models.PaymentMethod, reference, resolver string) (*models.TripPayment, error) {
+	if !method.IsValid() {
+		return nil, &models.ValidationError{
+			Field:   "method",
+			Message: "must be card, cash, wallet or corporate",
+		}
+	}
+
+	balance, err := s.balance(ctx, discrepancy.TripID.String())
+	if err != nil {
+		return nil, err
+	}
+	difference := balance.Difference()
+
+	payment := &models.TripPayment{
+		ID:         uuid.New(),
+		TripID:     discrepancy.TripID,
+		Kind:       models.PaymentKindCharge,
+		Method:     method,
+		Amount:     -difference,
+		RecordedBy: &resolver,
+		CreatedAt:  s.now(),
+	}
+	if resolution == models.PaymentResolutionRefund {
+		payment.Kind, payment.Amount = models.PaymentKindRefund, difference
+	}
+	if payment.Amount <= s.cfg.Tolerance {
+		owed := "underpaid"
+		if payment.Kind == models.PaymentKindRefund {
+			owed = "overpaid"
+		}
+		return nil, &models.ValidationError{
+			Field:   "resolution",
+			Message: fmt.Sprintf("trip is paid %.2f against a fare of %.2f and is not %s", balance.Paid, balance.Fare, owed),
+		}
+	}
+	if reference = strings.TrimSpace(reference); reference != "" {
+		payment.Reference = &reference
+	}
+	if err := payment.Validate(); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "reference",
+			Message: err.Error(),
+		}
+	}
+	return payment, nil
+}
+
+// reopen undoes the claim of a discrepancy whose payment failed to be recorded
+func (s *PaymentReconciliationService) reopen(ctx context.Context, discrepancy *models.PaymentDiscrepancy, status models.PaymentDiscrepancyStatus) {
+	resolved := discrepancy.Status
+	discrepancy.Status = status
+	discrepancy.Resolution, discrepancy.PaymentID, discrepancy.ResolvedBy = nil, nil, nil
+	discrepancy.ResolutionNote, discrepancy.ResolvedAt = nil, nil
+	discrepancy.UpdatedAt = s.now()
+	if err := s.payments.UpdateDiscrepancy(ctx, discrepancy, resolved); err != nil {
+		s.logger.WithError(err).WithField("discrepancy_id", discrepancy.ID.String()).Error("Failed to reopen payment discrepancy")
+	}
+}
+
+// settle resolves the open discrepancies of a trip that its payments no longer show: a missing
+// payment once the trip has one, any discrepancy once its payments balance its fare. A trip still
+// paid the wrong amount is reported as a mismatch by the next run.
+func (s *PaymentReconciliationService) settle(ctx context.Context, trip *models.Trip) {
+	balance, err := s.balance(ctx, trip.ID.String())
+	if err != nil {
+		s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Warn("Failed to get trip payment balance")
+		return
+	}
+	balanced := math.Abs(balance.Difference()) <= s.cfg.Tolerance
+
+	open, _, err := s.payments.ListDiscrepancies(ctx, models.PaymentDiscrepancyFilter{
+		Status: models.PaymentDiscrepancyOpen,
+		TripID: &trip.ID,
+		Limit:  10, // one of each type at most
+	})
+	if err != nil {
+		s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Warn("Failed to get open payment discrepancies")
+		return
+	}
+	for _, discrepancy := range open {
+		if !balanced && discrepancy.Type != models.PaymentDiscrepancyMissing {
+			continue
+		}
+		discrepancy.Resolve(models.PaymentResolutionSettled, nil, paymentSettlementResolver, "", s.now())
+		if err := s.payments.UpdateDiscrepancy(ctx, discrepancy, models.PaymentDiscrepancyOpen); err != nil {
+			if !errors.Is(err, models.ErrPaymentDiscrepancyStatusConflict) {
+				s.logger.WithError(err).WithField("discrepancy_id", discrepancy.ID.String()).Warn("Failed to settle payment discrepancy")
+			}
+			continue
+		}
+		s.recordResolution(discrepancy, models.PaymentResolutionSettled)
+		s.publish("payment_discrepancy_resolved", discrepancy, models.EventSeverityInfo, map[string]interface{}{
+			"resolved_by": paymentSettlementResolver,
+		}, "Payment discrepancy settled by a later payment")
+	}
+}
+
+// balance returns a trip's fare against the net amount of its payments ledger
+func (s *PaymentReconciliationService) balance(ctx context.Context, tripID string) (*models.TripPaymentBalance, error) {
+	trip, err := s.trips.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	payments, err := s.payments.ListPaymentsByTrip(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	balance := &models.TripPaymentBalance{TripID: trip.ID, Payments: len(payments)}
+	if trip.FareAmount != nil {
+		balance.Fare = *trip.FareAmount
+	}
+	if trip.CompletedAt != nil {
+		balance.CompletedAt = *trip.CompletedAt
+	}
+	for _, payment := range payments {
+		balance.Paid += payment.Net()
+	}
+	return balance, nil
+}
+
+// recordRun counts a reconciliation run by outcome and records its duration
+func (s *PaymentReconciliationService) recordRun(outcome string, started time.Time) {
+	if s.metrics == nil {
+		return
+	}
+	s.metrics.RecordBusinessMetrics("payment_reconciliation_runs_total", 1, map[string]string{"outcome": outcome})
+	s.metrics.RecordBusinessMetrics("payment_reconciliation_duration_seconds", s.now().Sub(started).Seconds(), nil)
+}
+
+// recordResolution counts a resolved discrepancy by type and action
+func (s *PaymentReconciliationService) recordResolution(discrepancy *models.PaymentDiscrepancy, resolution models.PaymentResolution) {
+	if s.metrics == nil {
+		return
+	}
+	s.metrics.RecordBusinessMetrics("payment_discrepancy_resolutions_total", 1, map[string]string{
+		"type":       string(discrepancy.Type),
+		"resolution": string(resolution),
+	})
+}
+
+// publish logs a payment discrepancy workflow step and publishes it as a business event log on
+// the discrepancy
+func (s *PaymentReconciliationService) publish(eventType string, discrepancy *models.PaymentDiscrepancy, severity models.EventSeverity, data map[string]interface{}, message string) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["discrepancy_id"] = discrepancy.ID
+	data["trip_id"] = discrepancy.TripID
+	data["type"] = discrepancy.Type
+	data["status"] = discrepancy.Status
+	data["expected_amount"] = discrepancy.ExpectedAmount
+	data["paid_amount"] = discrepancy.PaidAmount
+	data["difference"] = discrepancy.Difference()
+	if discrepancy.Resolution != nil {
+		data["resolution"] = *discrepancy.Resolution
+	}
+
+	entry := s.logger.WithFields(logging.Fields{
+		"event_type":     eventType,
+		"discrepancy_id": discrepancy.ID.String(),
+		"trip_id":        discrepancy.TripID.String(),
+		"type":           string(discrepancy.Type),
+	})
+	if severity == models.EventSeverityInfo {
+		entry.Info(message)
+	} else {
+		entry.Warn(message)
+	}
+
+	if s.events == nil {
+		return
+	}
+
+	entityType, entityID := paymentDiscrepancyEntityType, discrepancy.ID
+	eventLog := &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     eventType,
+		EventCategory: models.EventCategoryBusiness,
+		EntityType:    &entityType,
+		EntityID:      &entityID,
+		Severity:      severity,
+		Message:       message,
+		Timestamp:     time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	eventLog.EventData, _ = json.Marshal(data)
+	s.events.Publish(bus.TopicEventLog, eventLog)
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/geohash"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// PickupWaitService tracks drivers waiting for their passengers at the pickup. The wait starts
+// when the driver arrives and is free for a while; past that, every started minute adds a fee to
+// the fare. Once the no-show window passes the driver may report the passenger as a no-show,
+// which cancels the trip with a no-show fee.
+type PickupWaitService struct {
+	trips        repository.TripRepository
+	participants tripParticipants
+	cfg          config.PickupWaitConfig
+	grid         geohash.Grid
+	tripEvents   TripEventPublisher
+	events       bus.Publisher
+	logger       *logging.Logger
+	now          func() time.Time
+}
+
+// NewPickupWaitService creates a new pickup wait service. Trip status transitions are published
+// on events and no-show cancellations are also sent to tripEvents, e.g. webhooks; both may be nil.
+func NewPickupWaitService(
+	trips repository.TripRepository,
+	drivers repository.DriverRepository,
+	passengers repository.PassengerRepository,
+	cfg config.PickupWaitConfig,
+	tripEvents TripEventPublisher,
+	events bus.Publisher,
+	logger *logging.Logger,
+) *PickupWaitService {
+	// The precision is validated with the config
+	grid, _ := geohash.NewGrid(cfg.ZonePrecision)
+	return &PickupWaitService{
+		trips:        trips,
+		participants: tripParticipants{trips: trips, drivers: drivers, passengers: passengers},
+		cfg:          cfg,
+		grid:         grid,
+		tripEvents:   tripEvents,
+		events:       events,
+		logger:       logger.WithComponent("pickup_wait_service"),
+		now:          time.Now,
+	}
+}
+
+// Arrive records the trip's driver arriving at the pickup and starts the wait for the passenger
+func (s *PickupWaitService) Arrive(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType) (*models.PickupWait, error) {
+	trip, err := s.authorizeDriver(ctx, tripID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	if trip.Status != models.TripStatusMatched && trip.Status != models.TripStatusAccepted {
+		return nil, models.ErrTripNotAwaitingPickup
+	}
+
+	now := s.now()
+	wait := &models.PickupWait{
+		TripID:      trip.ID,
+		DriverID:    *trip.DriverID,
+		Zone:        s.grid.Geohash(s.grid.Locate(trip.PickupLatitude, trip.PickupLongitude)),
+		ArrivedAt:   now,
+		NoShowAfter: now.Add(s.cfg.NoShowWindow),
+		Outcome:     models.PickupWaitWaiting,
+	}
+
+	from := trip.Status
+	trip.Status = models.TripStatusDriverArrived
+	trip.UpdatedAt = now
+	if err := s.trips.StartPickupWait(ctx, trip, from, wait); err != nil {
+		return nil, err
+	}
+
+	s.publishStatus(trip)
+	return wait, nil
+}
+
+// PickUp ends the wait with the passenger on board, starting the trip. Waiting past the free
+// period is charged per started minute.
+func (s *PickupWaitService) PickUp(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType) (*models.PickupWait, error) {
+	trip, wait, err := s.arrivedTrip(ctx, tripID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	wait.End(models.PickupWaitPickedUp, now)
+	if billable := now.Sub(wait.ArrivedAt) - s.cfg.FreePeriod; billable > 0 {
+		wait.WaitFee = roundFare(math.Ceil(billable.Minutes()) * s.cfg.FeePerMinute)
+	}
+
+	from := trip.Status
+	trip.Status = models.TripStatusInProgress
+	trip.PickupAt = &now
+	trip.UpdatedAt = now
+	if err := s.trips.EndPickupWait(ctx, trip, from, wait); err != nil {
+		return nil, err
+	}
+
+	s.publishStatus(trip)
+	return wait, nil
+}
+
+// ReportNoShow ends the wait without the passenger once the no-show window has passed,
+// cancelling the trip with the no-show fee as its fare and freeing the driver
+func (s *PickupWaitService) ReportNoShow(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType) (*models.PickupWait, error) {
+	trip, wait, err := s.arrivedTrip(ctx, tripID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	if now.Before(wait.NoShowAfter) {
+		return nil, models.ErrNoShowTooEarly
+	}
+	wait.End(models.PickupWaitNoShow, now)
+	wait.NoShowFee = roundFare(s.cfg.NoShowFee)
+
+	from := trip.Status
+	trip.Status = models.TripStatusCancelled
+	trip.FareAmount = &wait.NoShowFee
+	trip.CancelledAt = &now
+	// No-shows are reported straight to the repository, without going through the actors
+	trip.RecordCancellation(models.TripCancellation{Reason: models.CancellationReasonPassengerNoShow}, TripEventModeTraditional)
+	trip.UpdatedAt = now
+	if err := s.trips.EndPickupWait(ctx, trip, from, wait); err != nil {
+		return nil, err
+	}
+
+	// The trip is cancelled either way, so failing to free the driver is only logged
+	if err := s.participants.freeDriver(ctx, trip); err != nil {
+		s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Warn("Failed to free driver after passenger no-show")
+	}
+	s.publishStatus(trip)
+	if s.tripEvents != nil {
+		if err := s.tripEvents.PublishTripEvent(context.WithoutCancel(ctx), models.WebhookEventTripCancelled, trip); err != nil {
+			s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Error("Failed to publish trip cancelled event")
+		}
+	}
+	s.publishNoShow(trip, wait)
+	return wait, nil
+}
+
+// GetWait returns the driver's wait at the pickup of a trip
+func (s *PickupWaitService) GetWait(ctx context.Context, tripID string) (*models.PickupWait, error) {
+	return s.trips.GetPickupWait(ctx, tripID)
+}
+
+// GetZoneStats returns the wait-time metrics of the drivers arriving at pickups in [from, to),
+// by pickup zone, busiest first
+func (s *PickupWaitService) GetZoneStats(ctx context.Context, from, to time.Time) ([]*models.PickupWaitZoneStats, error) {
+	if !from.Before(to) {
+		return nil, &models.ValidationError{
+			Field:   "from",
+			Message: "must be before to",
+		}
+	}
+	return s.trips.GetPickupWaitStats(ctx, from, to)
+}
+
+// authorizeDriver returns the trip, or ErrUnauthorizedOperation unless the user is its driver
+func (s *PickupWaitService) authorizeDriver(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType) (*models.Trip, error) {
+	if userType != models.UserTypeDriver {
+		return nil, models.ErrUnauthorizedOperation
+	}
+	return s.participants.authorize(ctx, tripID, userID, userType)
+}
+
+// arrivedTrip returns the trip whose driver is waiting at the pickup and the wait
+func (s *PickupWaitService) arrivedTrip(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType) (*models.Trip, *models.PickupWait, error) {
+	trip, err := s.authorizeDriver(ctx, tripID, userID, userType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if trip.Status != models.TripStatusDriverArrived {
+		return nil, nil, models.ErrDriverNotArrived
+	}
+	wait, err := s.trips.GetPickupWait(ctx, tripID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return trip, wait, nil
+}
+
+// publishStatus publishes the trip's transition for the live trip status streams
+func (s *PickupWaitService) publishStatus(trip *models.Trip) {
+	if s.events == nil {
+		return
+	}
+	snapshot := *trip
+	s.events.Publish(bus.TopicTripStatus, &snapshot)
+}
+
+// publishNoShow publishes a passenger no-show as an event log
+func (s *PickupWaitService) publishNoShow(trip *models.Trip, wait *models.PickupWait) {
+	if s.events == nil {
+		return
+	}
+
+	entityType, entityID := models.EntityTypeTrip, trip.ID
+	eventLog := &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     "passenger_no_show",
+		EventCategory: models.EventCategoryBusiness,
+		EntityType:    &entityType,
+		EntityID:      &entityID,
+		Severity:      models.EventSeverityInfo,
+		Message:       "Trip cancelled after passenger no-show",
+		Timestamp:     *wait.EndedAt,
+		CreatedAt:     *wait.EndedAt,
+	}
+	eventLog.EventData, _ = json.Marshal(wait)
+	s.events.Publish(bus.TopicEventLog, eventLog)
+}
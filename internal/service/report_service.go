@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/reporting"
+	"actor-model-observability/internal/repository"
+)
+
+// defaultReportDays is the days a daily report covers when no start date is given, today included
+const defaultReportDays = 7
+
+// ReportService aggregates trips by calendar day in an operator's timezone. Timestamps are
+// stored in UTC, so the local days are computed here, DST changes included, and the database
+// groups trips by their UTC boundaries.
+type ReportService struct {
+	trips repository.TripRepository
+	cfg   config.ReportingConfig
+	now   func() time.Time
+}
+
+// NewReportService creates a new report service
+func NewReportService(trips repository.TripRepository, cfg config.ReportingConfig) *ReportService {
+	return &ReportService{
+		trips: trips,
+		cfg:   cfg,
+		now:   time.Now,
+	}
+}
+
+// DailySummary counts the trips requested, completed and cancelled, the cancellations by reason
+// and the completed fares on each local date from from through to in timezone. An empty timezone is the configured one, an
+// empty to is today there and an empty from is six days before to. Every date is listed, days
+// without trips included.
+func (s *ReportService) DailySummary(ctx context.Context, from, to, timezone string) (*models.DailySummaryReport, error) {
+	if timezone == "" {
+		timezone = s.cfg.Timezone
+	}
+	loc, err := reporting.LoadLocation(timezone)
+	if err != nil {
+		return nil, &models.ValidationError{
+			Field:   "tz",
+			Message: err.Error(),
+		}
+	}
+
+	days, err := s.reportDays(from, to, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	boundaries := reporting.Boundaries(days)
+	counts, err := s.trips.GetDailyCounts(ctx, boundaries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count daily trips: %w", err)
+	}
+	cancellations, err := s.trips.GetDailyCancellations(ctx, boundaries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count daily cancellations: %w", err)
+	}
+
+	report := &models.DailySummaryReport{
+		Timezone: timezone,
+		From:     days[0].Date,
+		To:       days[len(days)-1].Date,
+		Days:     make([]models.DailySummaryDay, len(days)),
+	}
+	for i, day := range days {
+		report.Days[i] = models.DailySummaryDay{
+			Date:  day.Date,
+			Start: day.Start,
+			End:   day.End,
+			Hours: day.Hours(),
+		}
+	}
+	for _, c := range counts {
+		if c.Day < 0 || c.Day >= len(report.Days) {
+			continue
+		}
+		d := &report.Days[c.Day]
+		d.TripsRequested = c.Requested
+		d.TripsCompleted = c.Completed
+		d.TripsCancelled = c.Cancelled
+		d.Revenue = roundFare(c.Revenue)
+	}
+	for _, c := range cancellations {
+		if c.Day < 0 || c.Day >= len(report.Days) {
+			continue
+		}
+		d := &report.Days[c.Day]
+		if d.CancellationsByReason == nil {
+			d.CancellationsByReason = make(map[string]int64)
+		}
+		reason := c.Reason
+		if reason == "" {
+			reason = models.CancellationUnknown
+		}
+		d.CancellationsByReason[reason] += c.Count
+	}
+
+	return report, nil
+}
+
+// reportDays resolves the dates of a daily report in loc, capped at the configured maximum
+func (s *ReportService) reportDays(from, to string, loc *time.Location) ([]reporting.Day, error) {
+	if to == "" {
+		to = reporting.DateOf(s.now(), loc)
+	}
+	if from == "" {
+		end, err := time.ParseInLocation(reporting.DateLayout, to, loc)
+		if err != nil {
+			return nil, &models.ValidationError{
+				Field:   "to",
+				Message: "to must be a date in YYYY-MM-DD format",
+			}
+		}
+		from = end.AddDate(0, 0, 1-defaultReportDays).Format(reporting.DateLayout)
+	}
+
+	days, err := reporting.Days(from, to, loc)
+	if err != nil {
+		return nil, &models.ValidationError{
+			Field:   "from",
+			Message: err.Error(),
+		}
+	}
+	if len(days) > s.cfg.MaxDays {
+		return nil, &models.ValidationError{
+			Field:   "from",
+			Message: fmt.Sprintf("a report covers at most %d days", s.cfg.MaxDays),
+		}
+	}
+	return days, nil
+}
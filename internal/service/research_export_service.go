@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/geohash"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// researchHashLength is the number of hex characters kept from the HMAC of a hashed ID
+const researchHashLength = 16
+
+// ResearchExport is an anonymized trips dataset ready to be written
+type ResearchExport struct {
+	Write      func(io.Writer) error // writes the dataset as CSV
+	Exported   int                   // trips in the dataset
+	Suppressed int                   // trips left out for sharing their cells with too few trips
+}
+
+// ResearchExportService exports the trips as an anonymized dataset for research, anonymized as
+// declared by the research export config. IDs are replaced with keyed hashes, so the trips of a
+// passenger or driver stay linkable without identifying them. Jittered timestamps of a trip are
+// all shifted by one offset derived from the trip's keyed hash, keeping its durations and staying
+// the same across exports, so exporting again does not let the jitter be averaged out.
+type ResearchExportService struct {
+	trips   repository.TripRepository
+	cfg     config.ResearchExportConfig
+	grid    geohash.Grid
+	hashKey []byte
+}
+
+// NewResearchExportService creates a new research export service. Without a configured hash key
+// a random one is generated, so hashes and jitter are only stable for the life of the process.
+func NewResearchExportService(trips repository.TripRepository, cfg config.ResearchExportConfig) (*ResearchExportService, error) {
+	grid, err := geohash.NewGrid(cfg.GeohashPrecision)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &ResearchExportService{
+		trips:   trips,
+		cfg:     cfg,
+		grid:    grid,
+		hashKey: []byte(cfg.HashKey),
+	}
+	if len(s.hashKey) == 0 {
+		s.hashKey = make([]byte, 32)
+		if _, err := rand.Read(s.hashKey); err != nil {
+			return nil, fmt.Errorf("failed to generate research export hash key: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// ExportTrips anonymizes the trips requested in [from, to). Trips are listed by hashed ID, an
+// order unrelated to when they were requested.
+func (s *ResearchExportService) ExportTrips(ctx context.Context, from, to time.Time) (*ResearchExport, error) {
+	if !from.Before(to) {
+		return nil, &models.ValidationError{Field: "from", Message: "must be before to"}
+	}
+	if to.Sub(from) > s.cfg.MaxPeriod {
+		return nil, &models.ValidationError{Field: "to", Message: fmt.Sprintf("period must not exceed %s", s.cfg.MaxPeriod)}
+	}
+
+	trips, err := s.trips.ListRequested(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trips: %w", err)
+	}
+
+	type row struct {
+		key    string
+		record []string
+	}
+	groups := make(map[string][]row)
+	for _, t := range trips {
+		hash := s.hash(t.ID.String())
+		record := make([]string, len(s.cfg.Columns))
+		for i, rule := range s.cfg.Columns {
+			record[i] = s.anonymize(t, hash, rule)
+		}
+		quasi := s.quasiIdentifier(t)
+		groups[quasi] = append(groups[quasi], row{key: hash, record: record})
+	}
+
+	var rows []row
+	suppressed := 0
+	for _, group := range groups {
+		if len(group) < s.cfg.KAnonymity {
+			suppressed += len(group)
+			continue
+		}
+		rows = append(rows, group...)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].key < rows[j].key })
+
+	header := make([]string, len(s.cfg.Columns))
+	for i, rule := range s.cfg.Columns {
+		header[i] = rule.Column
+	}
+	records := make([][]string, len(rows))
+	for i, r := range rows {
+		records[i] = r.record
+	}
+
+	return &ResearchExport{
+		Write:      func(w io.Writer) error { return writeCSV(w, header, records) },
+		Exported:   len(records),
+		Suppressed: suppressed,
+	}, nil
+}
+
+// anonymize returns a trip's value of a column anonymized with the rule's action
+func (s *ResearchExportService) anonymize(t *models.Trip, tripHash string, rule config.AnonymizationRule) string {
+	switch rule.Column {
+	case "trip_id":
+		return tripHash
+	case "passenger_id":
+		return s.hash(t.PassengerID.String())
+	case "driver_id":
+		if t.DriverID == nil {
+			return ""
+		}
+		return s.hash(t.DriverID.String())
+	case "status":
+		return string(t.Status)
+	case "pickup":
+		return s.grid.Geohash(s.grid.Locate(t.PickupLatitude, t.PickupLongitude))
+	case "dropoff":
+		return s.grid.Geohash(s.grid.Locate(t.DestinationLatitude, t.DestinationLongitude))
+	case "requested_at":
+		return s.timestamp(&t.RequestedAt, tripHash, rule.Action)
+	case "matched_at":
+		return s.timestamp(t.MatchedAt, tripHash, rule.Action)
+	case "pickup_at":
+		return s.timestamp(t.PickupAt, tripHash, rule.Action)
+	case "completed_at":
+		return s.timestamp(t.CompletedAt, tripHash, rule.Action)
+	case "cancelled_at":
+		return s.timestamp(t.CancelledAt, tripHash, rule.Action)
+	case "distance_km":
+		return s.number(t.DistanceKm, rule.Action)
+	case "duration_minutes":
+		return formatCSVOptionalInt(t.DurationMinutes)
+	case "fare_amount":
+		return s.number(t.FareAmount, rule.Action)
+	}
+	return ""
+}
+
+// quasiIdentifier returns the exported cells of a trip, which at least KAnonymity exported trips
+// share
+func (s *ResearchExportService) quasiIdentifier(t *models.Trip) string {
+	var cells []string
+	for _, rule := range s.cfg.Columns {
+		switch rule.Column {
+		case "pickup", "dropoff":
+			cells = append(cells, s.anonymize(t, "", rule))
+		}
+	}
+	return strings.Join(cells, ",")
+}
+
+// timestamp formats a nullable timestamp shifted by the trip's jitter offset, or truncated to
+// the hour
+func (s *ResearchExportService) timestamp(t *time.Time, tripHash, action string) string {
+	if t == nil {
+		return ""
+	}
+	if action == "hour" {
+		return t.UTC().Truncate(time.Hour).Format(time.RFC3339)
+	}
+	shifted := t.Add(s.jitter(tripHash))
+	return formatCSVTime(&shifted)
+}
+
+// jitter returns the offset of a trip's timestamps, in whole seconds within the configured jitter
+// either way
+func (s *ResearchExportService) jitter(tripHash string) time.Duration {
+	span := int64(s.cfg.TimestampJitter / time.Second)
+	if span == 0 {
+		return 0
+	}
+	mac := hmac.New(sha256.New, s.hashKey)
+	mac.Write([]byte("jitter:" + tripHash))
+	offset := int64(binary.BigEndian.Uint64(mac.Sum(nil)) % uint64(2*span+1))
+	return time.Duration(offset-span) * time.Second
+}
+
+// number formats a nullable number, rounded to a whole number when the action is round
+func (s *ResearchExportService) number(v *float64, action string) string {
+	if v == nil {
+		return ""
+	}
+	if action == "round" {
+		return formatCSVFloat(math.Round(*v))
+	}
+	return formatCSVFloat(*v)
+}
+
+// hash returns the keyed hash of an ID
+func (s *ResearchExportService) hash(id string) string {
+	mac := hmac.New(sha256.New, s.hashKey)
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))[:researchHashLength]
+}
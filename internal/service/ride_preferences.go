@@ -0,0 +1,178 @@
+package service
+
+import (
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+)
+
+// rideRequirements are what the driver matched with a ride must, or should, offer
+type rideRequirements struct {
+	preferences models.RidePreferences
+	// capabilities are required of the vehicle, by the request or the accessible vehicle
+	// preference
+	capabilities []models.VehicleCapability
+}
+
+// newRideRequirements returns the requirements of a ride with prefs requiring capabilities
+func newRideRequirements(prefs models.RidePreferences, capabilities []models.VehicleCapability) rideRequirements {
+	reqs := rideRequirements{preferences: prefs}
+	if prefs.AccessibleVehicle {
+		reqs.capabilities = append(reqs.capabilities, models.VehicleCapabilityWheelchairAccessible)
+	}
+	for _, capability := range capabilities {
+		if !reqs.requires(capability) {
+			reqs.capabilities = append(reqs.capabilities, capability)
+		}
+	}
+	return reqs
+}
+
+// requires returns true if the vehicle must have the capability
+func (r rideRequirements) requires(capability models.VehicleCapability) bool {
+	for _, required := range r.capabilities {
+		if required == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// preferenceConstraint is a ride preference a driver must meet to be matched with the ride
+type preferenceConstraint struct {
+	name   string
+	hard   bool // never relaxed
+	allows func(driver *models.Driver) bool
+}
+
+// constraints returns the constraints of the requirements, the soft ones in the order they are
+// relaxed
+func (r rideRequirements) constraints() []preferenceConstraint {
+	var constraints []preferenceConstraint
+	for _, capability := range r.capabilities {
+		constraints = append(constraints, preferenceConstraint{
+			name:   string(capability),
+			hard:   true,
+			allows: func(driver *models.Driver) bool { return driver.HasCapability(capability) },
+		})
+	}
+	if r.preferences.PetFriendly {
+		constraints = append(constraints, preferenceConstraint{
+			name:   "pet_friendly",
+			hard:   true,
+			allows: func(driver *models.Driver) bool { return driver.PetFriendly },
+		})
+	}
+	if r.preferences.QuietRide {
+		constraints = append(constraints, preferenceConstraint{
+			name:   "quiet_ride",
+			allows: func(driver *models.Driver) bool { return driver.QuietRides },
+		})
+	}
+	if r.preferences.MinDriverRating != nil {
+		minRating := *r.preferences.MinDriverRating
+		constraints = append(constraints, preferenceConstraint{
+			name:   "min_driver_rating",
+			allows: func(driver *models.Driver) bool { return driver.Rating >= minRating },
+		})
+	}
+	return constraints
+}
+
+// applyRequirements keeps the drivers meeting the ride's requirements. Drivers failing a hard
+// constraint, a required vehicle capability or pet friendliness, are always dropped; when the soft
+// constraints leave no driver, they are relaxed one at a time, quiet ride before the minimum
+// rating, until some driver remains. Every relaxation is counted by constraint, so the metrics
+// show how often passengers get less than they asked for.
+func (rs *RideService) applyRequirements(drivers []*models.Driver, pickup models.Location, reqs rideRequirements, method string) []*models.Driver {
+	rs.recordAccessibleDemand(drivers, pickup, reqs, method)
+
+	constraints := reqs.constraints()
+	if len(constraints) == 0 || len(drivers) == 0 {
+		return drivers
+	}
+
+	var hard, soft []preferenceConstraint
+	for _, constraint := range constraints {
+		if constraint.hard {
+			hard = append(hard, constraint)
+		} else {
+			soft = append(soft, constraint)
+		}
+	}
+
+	eligible := filterDrivers(drivers, hard)
+	if len(eligible) == 0 {
+		return nil
+	}
+	for {
+		matching := filterDrivers(eligible, soft)
+		if len(matching) > 0 || len(soft) == 0 {
+			return matching
+		}
+
+		relaxed := soft[0]
+		soft = soft[1:]
+		rs.traditionalMonitor.RecordBusinessMetrics("matching_constraint_relaxations_total", 1, map[string]string{
+			"method":     method,
+			"constraint": relaxed.name,
+		})
+		rs.logger.WithFields(logging.Fields{
+			"constraint": relaxed.name,
+			"method":     method,
+			"drivers":    len(eligible),
+		}).Info("Ride preference relaxed for lack of matching drivers")
+	}
+}
+
+// recordAccessibleDemand counts a ride requiring vehicle capabilities by pickup zone and
+// capability, with the supply of nearby drivers whose vehicle has the capability. Requests met
+// by no nearby vehicle are counted separately, so unserved demand stands out per zone.
+func (rs *RideService) recordAccessibleDemand(drivers []*models.Driver, pickup models.Location, reqs rideRequirements, method string) {
+	if len(reqs.capabilities) == 0 {
+		return
+	}
+
+	zone := rs.accessibilityZones.Geohash(rs.accessibilityZones.Locate(pickup.Latitude, pickup.Longitude))
+	for _, capability := range reqs.capabilities {
+		supply := 0
+		for _, driver := range drivers {
+			if driver.HasCapability(capability) {
+				supply++
+			}
+		}
+
+		labels := map[string]string{
+			"zone":       zone,
+			"capability": string(capability),
+			"method":     method,
+		}
+		rs.traditionalMonitor.RecordBusinessMetrics("accessible_ride_requests_total", 1, labels)
+		rs.traditionalMonitor.RecordBusinessMetrics("accessible_ride_nearby_supply", float64(supply), labels)
+		if supply == 0 {
+			rs.traditionalMonitor.RecordBusinessMetrics("accessible_ride_unserved_total", 1, labels)
+		}
+		rs.logger.WithFields(logging.Fields{
+			"zone":       zone,
+			"capability": capability,
+			"supply":     supply,
+		}).Debug("Accessible ride requested")
+	}
+}
+
+// filterDrivers returns the drivers meeting every constraint
+func filterDrivers(drivers []*models.Driver, constraints []preferenceConstraint) []*models.Driver {
+	var allowed []*models.Driver
+	for _, driver := range drivers {
+		ok := true
+		for _, constraint := range constraints {
+			if !constraint.allows(driver) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			allowed = append(allowed, driver)
+		}
+	}
+	return allowed
+}
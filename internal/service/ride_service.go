@@ -2,16 +2,24 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
+	"sort"
+	"sync"
 	"time"
 
 	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/deadline"
+	"actor-model-observability/internal/geohash"
 	"actor-model-observability/internal/logging"
 	"actor-model-observability/internal/models"
 	"actor-model-observability/internal/observability"
 	"actor-model-observability/internal/repository"
 	"actor-model-observability/internal/traditional"
+	"actor-model-observability/internal/tripcache"
 
 	"github.com/google/uuid"
 )
@@ -25,8 +33,24 @@ type RideService struct {
 	actorSystem        *actor.ActorSystem
 	metricsCollector   *observability.MetricsCollector
 	traditionalMonitor *traditional.TraditionalMonitor
+	eventPublisher     TripEventPublisher
+	statusPublisher    bus.Publisher
+	tripEvents         *TripEventEmitter
+	corporateBilling   CorporateBilling
+	serviceArea        *ServiceAreaPolicy
+	eta                *ETAModel
+	accessibilityZones geohash.Grid
+	rematch            config.RematchConfig
+	cancellationPolicy *CancellationPolicy
+	matcher            *TraditionalMatcher
+	locations          *LocationIngester
+	tripCache          *tripcache.Cache
 	logger             *logging.Logger
 	useActorModel      bool
+
+	// Trip actors of the trips being matched, by trip ID
+	tripActors      map[string]string
+	tripActorsMutex sync.Mutex
 }
 
 // NewRideService creates a new ride service
@@ -41,6 +65,8 @@ func NewRideService(
 	logger *logging.Logger,
 	useActorModel bool,
 ) *RideService {
+	accessibilityZones, _ := geohash.NewGrid(config.DefaultAccessibilityConfig().ZonePrecision)
+	cancellationPolicy, _ := NewCancellationPolicy(config.DefaultCancellationPolicyConfig())
 	return &RideService{
 		userRepo:           userRepo,
 		driverRepo:         driverRepo,
@@ -49,13 +75,147 @@ func NewRideService(
 		actorSystem:        actorSystem,
 		metricsCollector:   metricsCollector,
 		traditionalMonitor: traditionalMonitor,
+		eta:                NewETAModel(etaAverageSpeedKmh),
+		accessibilityZones: accessibilityZones,
+		rematch:            config.DefaultRematchConfig(),
+		cancellationPolicy: cancellationPolicy,
 		logger:             logger.WithComponent("ride_service"),
 		useActorModel:      useActorModel,
+		tripActors:         make(map[string]string),
+	}
+}
+
+// SetEventPublisher sets where trip lifecycle transitions are published, e.g. webhooks
+func (rs *RideService) SetEventPublisher(publisher TripEventPublisher) {
+	rs.eventPublisher = publisher
+}
+
+// SetStatusPublisher sets the event bus every trip status transition is published on, for the
+// live trip status streams
+func (rs *RideService) SetStatusPublisher(publisher bus.Publisher) {
+	rs.statusPublisher = publisher
+}
+
+// SetTripEventEmitter sets the emitter every trip state change is recorded with, in both the
+// actor and traditional pipelines whichever mode the service runs in
+func (rs *RideService) SetTripEventEmitter(emitter *TripEventEmitter) {
+	rs.tripEvents = emitter
+}
+
+// SetCorporateBilling sets how rides requested with BillToCorporateAccount are billed
+func (rs *RideService) SetCorporateBilling(billing CorporateBilling) {
+	rs.corporateBilling = billing
+}
+
+// SetServiceArea sets the policy rejecting ride requests outside the service area or operating
+// hours. Without one, rides may be requested anywhere at any time.
+func (rs *RideService) SetServiceArea(policy *ServiceAreaPolicy) {
+	rs.serviceArea = policy
+}
+
+// SetAccessibilityZones groups the supply and demand metrics of rides requiring vehicle
+// capabilities by pickup geohashes of precision characters
+func (rs *RideService) SetAccessibilityZones(precision int) {
+	// The precision is validated with the config
+	if grid, err := geohash.NewGrid(precision); err == nil {
+		rs.accessibilityZones = grid
 	}
 }
 
+// SetRematch sets how often a trip is matched again after its driver cancels before pickup
+func (rs *RideService) SetRematch(cfg config.RematchConfig) {
+	rs.rematch = cfg
+}
+
+// SetCancellationPolicy sets what passengers are charged for cancelling rides
+func (rs *RideService) SetCancellationPolicy(policy *CancellationPolicy) {
+	rs.cancellationPolicy = policy
+}
+
+// SetTraditionalMatcher sets the worker pool ride requests are matched on in traditional mode.
+// Without one, requests are matched synchronously as they come in.
+func (rs *RideService) SetTraditionalMatcher(matcher *TraditionalMatcher) {
+	rs.matcher = matcher
+}
+
+// SetLocationIngester matches rides with the latest driver locations reported to locations, which
+// may not be in the database yet
+func (rs *RideService) SetLocationIngester(locations *LocationIngester) {
+	rs.locations = locations
+}
+
+// SetTripCache serves trip status lookups from cache, which the trip repository must write
+// through. Without one, every lookup reads the database.
+func (rs *RideService) SetTripCache(cache *tripcache.Cache) {
+	rs.tripCache = cache
+}
+
+// SetETAModel estimates the ETA passengers are given when a driver is matched with model
+func (rs *RideService) SetETAModel(model *ETAModel) {
+	rs.eta = model
+}
+
+// RideOption customises a ride request
+type RideOption func(*rideOptions)
+
+// rideOptions are the settings of a ride request
+type rideOptions struct {
+	billToCorporate bool
+	preferences     *models.RidePreferences
+	capabilities    []models.VehicleCapability
+}
+
+// BillToCorporateAccount bills the ride to the passenger's corporate account instead of the
+// passenger. The request fails if the passenger has no account or the account's monthly
+// spending limit would be exceeded.
+func BillToCorporateAccount() RideOption {
+	return func(o *rideOptions) {
+		o.billToCorporate = true
+	}
+}
+
+// WithRidePreferences matches the ride with a driver meeting prefs instead of the ride preferences
+// of the passenger's profile
+func WithRidePreferences(prefs models.RidePreferences) RideOption {
+	return func(o *rideOptions) {
+		o.preferences = &prefs
+	}
+}
+
+// RequireVehicleCapabilities matches the ride only with drivers whose vehicle has every capability,
+// however few of them are nearby
+func RequireVehicleCapabilities(capabilities ...models.VehicleCapability) RideOption {
+	return func(o *rideOptions) {
+		o.capabilities = append(o.capabilities, capabilities...)
+	}
+}
+
+// TripEventPublishers publishes trip events to several publishers, e.g. webhooks and driver incentives
+type TripEventPublishers []TripEventPublisher
+
+// PublishTripEvent publishes the event to every publisher, returning their joined errors
+func (p TripEventPublishers) PublishTripEvent(ctx context.Context, event models.WebhookEvent, trip *models.Trip) error {
+	var errs []error
+	for _, publisher := range p {
+		if err := publisher.PublishTripEvent(ctx, event, trip); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // RequestRide handles ride requests
-func (rs *RideService) RequestRide(ctx context.Context, passengerID string, pickup, dropoff models.Location, pickupAddr, dropoffAddr string) (*models.Trip, error) {
+func (rs *RideService) RequestRide(ctx context.Context, passengerID string, pickup, dropoff models.Location, pickupAddr, dropoffAddr string, opts ...RideOption) (*models.Trip, error) {
+	var options rideOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if rs.serviceArea != nil {
+		if err := rs.serviceArea.Check(pickup, dropoff, time.Now()); err != nil {
+			return nil, err
+		}
+	}
+
 	start := time.Now()
 	defer func() {
 		duration := time.Since(start)
@@ -78,6 +238,12 @@ func (rs *RideService) RequestRide(ctx context.Context, passengerID string, pick
 	if err != nil {
 		return nil, fmt.Errorf("passenger not found: %w", err)
 	}
+	if options.billToCorporate && passenger.CorporateAccountID == nil {
+		return nil, &models.ValidationError{
+			Field:   "bill_to",
+			Message: models.ErrNoCorporateAccount.Error(),
+		}
+	}
 
 	// Parse passengerID string to UUID
 	passengerUUID, err := uuid.Parse(passengerID)
@@ -104,20 +270,47 @@ func (rs *RideService) RequestRide(ctx context.Context, passengerID string, pick
 	if err := rs.tripRepo.Create(ctx, trip); err != nil {
 		return nil, fmt.Errorf("failed to create trip: %w", err)
 	}
+	if options.billToCorporate {
+		if err := rs.chargeCorporateAccount(ctx, passenger, trip, pickup, dropoff); err != nil {
+			return nil, err
+		}
+	}
+	rs.publishTripEvent(ctx, trip, "")
 
+	prefs := passenger.RidePreferences
+	if options.preferences != nil {
+		prefs = *options.preferences
+	}
+	reqs := newRideRequirements(prefs, options.capabilities)
 	if rs.useActorModel {
-		return rs.requestRideActorModel(ctx, passenger, trip, pickup, dropoff, pickupAddr, dropoffAddr)
+		return rs.requestRideActorModel(ctx, passenger, trip, reqs, pickup, dropoff, pickupAddr, dropoffAddr)
 	} else {
-		return rs.requestRideTraditional(ctx, passenger, trip, pickup, dropoff, pickupAddr, dropoffAddr)
+		return rs.requestRideTraditional(ctx, passenger, trip, reqs, pickup, dropoff, pickupAddr, dropoffAddr)
 	}
 }
 
+// chargeCorporateAccount bills a new trip to the passenger's corporate account, deleting the
+// trip if the charge is refused so a rejected request leaves nothing behind
+func (rs *RideService) chargeCorporateAccount(ctx context.Context, passenger *models.Passenger, trip *models.Trip, pickup, dropoff models.Location) error {
+	err := errors.New("corporate billing is not configured")
+	if rs.corporateBilling != nil {
+		err = rs.corporateBilling.ChargeTrip(ctx, passenger, trip, rs.calculateEstimatedFare(pickup, dropoff))
+	}
+	if err == nil {
+		return nil
+	}
+
+	if deleteErr := rs.tripRepo.Delete(ctx, trip.ID.String()); deleteErr != nil {
+		rs.logger.WithError(deleteErr).WithField("trip_id", trip.ID.String()).Error("Failed to delete trip after corporate charge failure")
+	}
+	return fmt.Errorf("failed to bill corporate account: %w", err)
+}
+
 // requestRideActorModel handles ride request using actor model
-func (rs *RideService) requestRideActorModel(ctx context.Context, passenger *models.Passenger, trip *models.Trip, pickup, dropoff models.Location, pickupAddr, dropoffAddr string) (*models.Trip, error) {
-	// Check if passenger actor already exists
+func (rs *RideService) requestRideActorModel(ctx context.Context, passenger *models.Passenger, trip *models.Trip, reqs rideRequirements, pickup, dropoff models.Location, pickupAddr, dropoffAddr string) (*models.Trip, error) {
+	// Check if passenger actor already exists, passivated ones included
 	passengerActorID := fmt.Sprintf("passenger-%s", passenger.ID.String())
-	_, err := rs.actorSystem.GetActor(passengerActorID)
-	if err != nil {
+	if !rs.actorSystem.HasActor(passengerActorID) {
 		// Create new passenger actor
 		pa, err := actor.NewPassengerActor(passenger, rs.actorSystem)
 		if err != nil {
@@ -135,15 +328,33 @@ func (rs *RideService) requestRideActorModel(ctx context.Context, passenger *mod
 			// or create a public method. For now, let's use a simple approach.
 			return nil // TODO: Implement proper message handling
 		}
-		if _, err := rs.actorSystem.SpawnActor("passenger", passengerActorID, 100, handler, actor.SupervisionRestart); err != nil {
+		// The passenger is snapshotted while the actor is passivated
+		if _, err := rs.actorSystem.SpawnActor("passenger", passengerActorID, 100, handler, actor.SupervisionRestart,
+			actor.ForEntity(models.EntityTypePassenger, passenger.ID.String()), actor.WithPassivator(pa)); err != nil {
 			return nil, fmt.Errorf("failed to spawn passenger actor: %w", err)
 		}
+	}
 
-		// Store the passenger actor reference for later use
-		_ = pa // Suppress unused variable warning
+	// The trip and matching actors come from the warm pools when configured, so the latency of
+	// this request shows whether actors had to be spawned for it. The trip actor's state is
+	// snapshotted while it is passivated.
+	tripState := actor.NewTripActor(trip)
+	tripActor, err := rs.actorSystem.AcquireActor(string(models.ActorTypeTrip), tripState.HandleMessage,
+		actor.ForEntity(models.EntityTypeTrip, trip.ID.String()), actor.WithPassivator(tripState))
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire trip actor: %w", err)
+	}
+	matchingActor, err := rs.actorSystem.AcquireActor(string(models.ActorTypeMatching), ignoreMessage,
+		actor.ForEntity(models.EntityTypeTrip, trip.ID.String()))
+	if err != nil {
+		rs.releaseActor(tripActor.ID)
+		return nil, fmt.Errorf("failed to acquire matching actor: %w", err)
 	}
+	rs.tripActorsMutex.Lock()
+	rs.tripActors[trip.ID.String()] = tripActor.ID
+	rs.tripActorsMutex.Unlock()
 
-	// Send ride request message to trip matching service
+	// Send ride request message to the matching actor
 	payload := actor.RequestRidePayload{
 		PickupLat:   pickup.Latitude,
 		PickupLng:   pickup.Longitude,
@@ -152,14 +363,34 @@ func (rs *RideService) requestRideActorModel(ctx context.Context, passenger *mod
 		PickupAddr:  pickupAddr,
 		DropoffAddr: dropoffAddr,
 		RequestedAt: time.Now(),
+		Preferences: &reqs.preferences,
+
+		RequiredCapabilities: reqs.capabilities,
 	}
 
 	// Record message in observability system
-	rs.metricsCollector.RecordMessage(passengerActorID, "trip-matcher", actor.MsgTypeRequestRide, payload, time.Now())
-
-	// In a real system, this would be sent to a trip matching actor
-	// For demo purposes, we'll simulate the matching process
-	go rs.simulateRideMatching(ctx, trip)
+	message := actor.NewBaseMessage(actor.MsgTypeRequestRide, payload, passengerActorID).
+		WithEntity(models.EntityTypeTrip, trip.ID.String())
+	if err := rs.actorSystem.SendMessageContext(ctx, matchingActor.ID, message); err != nil {
+		// Matching is not started for a request out of time, it would only reserve a driver
+		// for a passenger who already got an error
+		if ctx.Err() != nil {
+			rs.releaseActor(matchingActor.ID)
+			rs.releaseTripActor(trip.ID.String())
+			return nil, err
+		}
+		rs.logger.WithError(err).Warn("Failed to send ride request to matching actor")
+	}
+	rs.metricsCollector.RecordActorMessage(matchingActor.ID, message)
+
+	// For demo purposes, we'll simulate the matching process. It outlives the request, so it
+	// is not bound by the request's deadline.
+	matchCtx := context.WithoutCancel(ctx)
+	go func() {
+		defer rs.releaseActor(matchingActor.ID)
+		rs.simulateRideMatching(matchCtx, trip, reqs)
+		rs.releaseTripActor(trip.ID.String())
+	}()
 
 	rs.logger.WithFields(logging.Fields{
 		"trip_id":      trip.ID,
@@ -171,25 +402,28 @@ func (rs *RideService) requestRideActorModel(ctx context.Context, passenger *mod
 }
 
 // requestRideTraditional handles ride request using traditional approach
-func (rs *RideService) requestRideTraditional(ctx context.Context, passenger *models.Passenger, trip *models.Trip, pickup, dropoff models.Location, pickupAddr, dropoffAddr string) (*models.Trip, error) {
-	// Traditional centralized approach
-	start := time.Now()
-
-	// Find available drivers
-	drivers, err := rs.findNearbyDrivers(ctx, pickup, 5.0) // 5km radius
+func (rs *RideService) requestRideTraditional(ctx context.Context, passenger *models.Passenger, trip *models.Trip, reqs rideRequirements, pickup, dropoff models.Location, pickupAddr, dropoffAddr string) (*models.Trip, error) {
+	// Traditional centralized approach, on the matching workers when configured
+	match := func(ctx context.Context) (*models.Driver, error) {
+		return rs.matchTraditional(ctx, pickup, dropoff, reqs)
+	}
+	var bestDriver *models.Driver
+	var err error
+	if rs.matcher != nil {
+		bestDriver, err = rs.matcher.Match(ctx, match)
+	} else {
+		bestDriver, err = match(ctx)
+	}
 	if err != nil {
-		rs.traditionalMonitor.RecordDatabaseOperation("SELECT", "drivers", time.Since(start), false)
-		return nil, fmt.Errorf("failed to find nearby drivers: %w", err)
+		// Unless a repository call ran out of time, the budget was spent in the service, such
+		// as waiting for a matching worker
+		deadline.Exceeded(ctx, deadline.LayerService, err)
+		return nil, err
 	}
-	rs.traditionalMonitor.RecordDatabaseOperation("SELECT", "drivers", time.Since(start), true)
-
-	if len(drivers) == 0 {
-		return nil, fmt.Errorf("no available drivers found")
+	if bestDriver == nil {
+		return nil, fmt.Errorf("no available drivers found: %w", models.ErrNoDriversAvailable)
 	}
 
-	// Select best driver (closest for simplicity)
-	bestDriver := rs.selectBestDriver(drivers, pickup)
-
 	// Update trip with matched driver
 	trip.DriverID = &bestDriver.ID
 	trip.Status = models.TripStatusMatched
@@ -198,18 +432,12 @@ func (rs *RideService) requestRideTraditional(ctx context.Context, passenger *mo
 	updateStart := time.Now()
 	if err := rs.tripRepo.Update(ctx, trip); err != nil {
 		rs.traditionalMonitor.RecordDatabaseOperation("UPDATE", "trips", time.Since(updateStart), false)
+		rs.releaseDriver(ctx, bestDriver)
 		return nil, fmt.Errorf("failed to update trip: %w", err)
 	}
 	rs.traditionalMonitor.RecordDatabaseOperation("UPDATE", "trips", time.Since(updateStart), true)
-
-	// Update driver status
-	bestDriver.Status = models.DriverStatusBusy
-	driverUpdateStart := time.Now()
-	if err := rs.driverRepo.Update(ctx, bestDriver); err != nil {
-		rs.traditionalMonitor.RecordDatabaseOperation("UPDATE", "drivers", time.Since(driverUpdateStart), false)
-		return nil, fmt.Errorf("failed to update driver status: %w", err)
-	}
-	rs.traditionalMonitor.RecordDatabaseOperation("UPDATE", "drivers", time.Since(driverUpdateStart), true)
+	rs.traditionalMonitor.RecordMatchingDuration(rs.mode(), trip.MatchedAt.Sub(trip.RequestedAt))
+	rs.publishTripEvent(ctx, trip, models.TripStatusRequested)
 
 	rs.logger.WithFields(logging.Fields{
 		"trip_id":      trip.ID,
@@ -221,18 +449,64 @@ func (rs *RideService) requestRideTraditional(ctx context.Context, passenger *mo
 	return trip, nil
 }
 
-// CancelRide handles ride cancellation
-func (rs *RideService) CancelRide(ctx context.Context, tripID, reason string) error {
+// matchTraditional finds the drivers near the pickup meeting the ride's requirements and reserves
+// the best one, returning nil if there is none
+func (rs *RideService) matchTraditional(ctx context.Context, pickup, dropoff models.Location, reqs rideRequirements) (*models.Driver, error) {
+	start := time.Now()
+
+	// Find available drivers
+	drivers, err := rs.findNearbyDrivers(ctx, pickup, 5.0) // 5km radius
+	if err != nil {
+		rs.traditionalMonitor.RecordDatabaseOperation("SELECT", "drivers", time.Since(start), false)
+		return nil, fmt.Errorf("failed to find nearby drivers: %w", err)
+	}
+	rs.traditionalMonitor.RecordDatabaseOperation("SELECT", "drivers", time.Since(start), true)
+
+	destinationStart := time.Now()
+	drivers, err = rs.filterByDestination(ctx, drivers, pickup, dropoff)
+	rs.traditionalMonitor.RecordDatabaseOperation("SELECT", "driver_destinations", time.Since(destinationStart), err == nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply driver destinations: %w", err)
+	}
+	drivers = rs.applyRequirements(drivers, pickup, reqs, "traditional")
+
+	if len(drivers) == 0 {
+		return nil, nil
+	}
+
+	// Reserve the best driver (closest for simplicity) before assigning the trip
+	reserveStart := time.Now()
+	bestDriver, err := rs.reserveBestDriver(ctx, drivers, pickup, "traditional")
+	rs.traditionalMonitor.RecordDatabaseOperation("UPDATE", "drivers", time.Since(reserveStart), err == nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reserve driver: %w", err)
+	}
+	return bestDriver, nil
+}
+
+// CancelRide handles ride cancellation, recording why the trip was cancelled
+func (rs *RideService) CancelRide(ctx context.Context, tripID string, cancellation models.TripCancellation) error {
+	if err := cancellation.Validate(); err != nil {
+		return err
+	}
+
 	start := time.Now()
+	var quote *models.CancellationQuote
 	defer func() {
 		duration := time.Since(start)
 		if rs.useActorModel {
-			rs.metricsCollector.RecordEvent("ride_cancel", "ride_service", "Ride cancelled", map[string]interface{}{
+			fields := map[string]interface{}{
 				"trip_id":     tripID,
-				"reason":      reason,
+				"reason":      string(cancellation.Reason),
 				"duration_ms": duration.Milliseconds(),
 				"method":      "actor_model",
-			})
+			}
+			if quote != nil {
+				for k, v := range quote.EventFields() {
+					fields[k] = v
+				}
+			}
+			rs.metricsCollector.RecordEvent("ride_cancel", "ride_service", "Ride cancelled", fields)
 		} else {
 			rs.traditionalMonitor.RecordRequest("/api/rides/cancel", "POST", duration, 200)
 		}
@@ -243,24 +517,43 @@ func (rs *RideService) CancelRide(ctx context.Context, tripID, reason string) er
 	if err != nil {
 		return fmt.Errorf("trip not found: %w", err)
 	}
+	// Quoted as the cancel-quote endpoint does, for the cancellation events
+	quote = rs.cancellationPolicy.Quote(trip, cancellation.Reason, time.Now())
 
 	if rs.useActorModel {
-		return rs.cancelRideActorModel(ctx, trip, reason)
+		return rs.cancelRideActorModel(ctx, trip, cancellation, quote)
 	} else {
-		return rs.cancelRideTraditional(ctx, trip, reason)
+		return rs.cancelRideTraditional(ctx, trip, cancellation, quote)
+	}
+}
+
+// CancellationQuote returns whether cancelling a trip now for the reason is free and what it
+// costs, before the passenger confirms. The reason may be empty when none is picked yet.
+func (rs *RideService) CancellationQuote(ctx context.Context, tripID string, reason models.CancellationReason) (*models.CancellationQuote, error) {
+	if reason != "" {
+		if err := (models.TripCancellation{Reason: reason}).Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	trip, err := rs.GetTripStatus(ctx, tripID)
+	if err != nil {
+		return nil, err
 	}
+	return rs.cancellationPolicy.Quote(trip, reason, time.Now()), nil
 }
 
 // cancelRideActorModel handles cancellation using actor model
-func (rs *RideService) cancelRideActorModel(ctx context.Context, trip *models.Trip, reason string) error {
+func (rs *RideService) cancelRideActorModel(ctx context.Context, trip *models.Trip, cancellation models.TripCancellation, quote *models.CancellationQuote) error {
 	// Send cancellation message to relevant actors
 	payload := actor.CancelRidePayload{
 		TripID:      trip.ID.String(),
-		Reason:      reason,
+		Reason:      string(cancellation.Reason),
 		CancelledAt: time.Now(),
 	}
 
-	message := actor.NewBaseMessage(actor.MsgTypeCancelRide, payload, "ride-service")
+	message := actor.NewBaseMessage(actor.MsgTypeCancelRide, payload, "ride-service").
+		WithEntity(models.EntityTypeTrip, trip.ID.String())
 
 	// Notify passenger actor
 	passengerActorID := fmt.Sprintf("passenger-%s", trip.PassengerID.String())
@@ -277,33 +570,64 @@ func (rs *RideService) cancelRideActorModel(ctx context.Context, trip *models.Tr
 	}
 
 	// Update trip status
+	from := trip.Status
 	trip.Status = models.TripStatusCancelled
 	trip.CancelledAt = &[]time.Time{time.Now()}[0]
+	trip.RecordCancellation(cancellation, rs.mode())
 
 	if err := rs.tripRepo.Update(ctx, trip); err != nil {
 		return fmt.Errorf("failed to update trip: %w", err)
 	}
+	rs.publishTripChange(ctx, TripStateChange{Trip: trip, From: from, Cancellation: quote})
 
 	// Record message
-	rs.metricsCollector.RecordMessage("ride-service", passengerActorID, actor.MsgTypeCancelRide, payload, time.Now())
+	rs.metricsCollector.RecordActorMessage(passengerActorID, message)
 
+	rs.releaseTripActor(trip.ID.String())
 	return nil
 }
 
+// ignoreMessage handles the messages of matching actors, whose work is simulated
+func ignoreMessage(actor.Message) error {
+	return nil
+}
+
+// releaseTripActor releases the trip actor of a trip, if it still holds one
+func (rs *RideService) releaseTripActor(tripID string) {
+	rs.tripActorsMutex.Lock()
+	actorID, ok := rs.tripActors[tripID]
+	delete(rs.tripActors, tripID)
+	rs.tripActorsMutex.Unlock()
+
+	if ok {
+		rs.releaseActor(actorID)
+	}
+}
+
+// releaseActor returns an acquired actor to its pool or stops it
+func (rs *RideService) releaseActor(actorID string) {
+	if err := rs.actorSystem.ReleaseActor(actorID); err != nil {
+		rs.logger.WithError(err).WithField("actor_id", actorID).Warn("Failed to release actor")
+	}
+}
+
 // cancelRideTraditional handles cancellation using traditional approach
-func (rs *RideService) cancelRideTraditional(ctx context.Context, trip *models.Trip, reason string) error {
+func (rs *RideService) cancelRideTraditional(ctx context.Context, trip *models.Trip, cancellation models.TripCancellation, quote *models.CancellationQuote) error {
 	// Traditional centralized cancellation
 	start := time.Now()
 
 	// Update trip status
+	from := trip.Status
 	trip.Status = models.TripStatusCancelled
 	trip.CancelledAt = &[]time.Time{time.Now()}[0]
+	trip.RecordCancellation(cancellation, rs.mode())
 
 	if err := rs.tripRepo.Update(ctx, trip); err != nil {
 		rs.traditionalMonitor.RecordDatabaseOperation("UPDATE", "trips", time.Since(start), false)
 		return fmt.Errorf("failed to update trip: %w", err)
 	}
 	rs.traditionalMonitor.RecordDatabaseOperation("UPDATE", "trips", time.Since(start), true)
+	rs.publishTripChange(ctx, TripStateChange{Trip: trip, From: from, Cancellation: quote})
 
 	// Free up driver if assigned
 	if trip.DriverID != nil {
@@ -320,50 +644,123 @@ func (rs *RideService) cancelRideTraditional(ctx context.Context, trip *models.T
 }
 
 // simulateRideMatching simulates the ride matching process for actor model
-func (rs *RideService) simulateRideMatching(ctx context.Context, trip *models.Trip) {
+func (rs *RideService) simulateRideMatching(ctx context.Context, trip *models.Trip, reqs rideRequirements) {
 	// Simulate matching delay
 	time.Sleep(2 * time.Second)
 
 	// Find nearby drivers
 	pickup := models.Location{Latitude: trip.PickupLatitude, Longitude: trip.PickupLongitude}
+	dropoff := models.Location{Latitude: trip.DestinationLatitude, Longitude: trip.DestinationLongitude}
 	drivers, err := rs.findNearbyDrivers(ctx, pickup, 5.0)
+	if err == nil {
+		drivers, err = rs.filterByDestination(ctx, drivers, pickup, dropoff)
+	}
+	if err == nil {
+		drivers = rs.applyRequirements(drivers, pickup, reqs, "actor_model")
+	}
 	if err != nil || len(drivers) == 0 {
 		rs.logger.WithField("trip_id", trip.ID).Warn("No drivers found for matching")
 		return
 	}
 
-	// Select best driver
-	bestDriver := rs.selectBestDriver(drivers, pickup)
+	// Reserve the best driver before assigning the trip
+	bestDriver, err := rs.reserveBestDriver(ctx, drivers, pickup, "actor_model")
+	if err != nil {
+		rs.logger.WithError(err).WithField("trip_id", trip.ID).Error("Failed to reserve driver")
+		return
+	}
+	if bestDriver == nil {
+		rs.logger.WithField("trip_id", trip.ID).Warn("Every nearby driver was reserved by another request")
+		return
+	}
 
 	// Update trip
 	trip.DriverID = &bestDriver.ID
 	trip.Status = models.TripStatusMatched
 	trip.MatchedAt = &[]time.Time{time.Now()}[0]
-	rs.tripRepo.Update(ctx, trip)
+	if err := rs.tripRepo.Update(ctx, trip); err != nil {
+		rs.logger.WithError(err).WithField("trip_id", trip.ID).Error("Failed to assign reserved driver")
+		rs.releaseDriver(ctx, bestDriver)
+		return
+	}
+	rs.traditionalMonitor.RecordMatchingDuration(rs.mode(), trip.MatchedAt.Sub(trip.RequestedAt))
+	rs.publishTripEvent(ctx, trip, models.TripStatusRequested)
 
-	// Update driver status
-	bestDriver.Status = models.DriverStatusBusy
-	rs.driverRepo.Update(ctx, bestDriver)
+	rs.notifyPassengerMatched(trip, bestDriver)
+}
 
-	// Send matched notification to passenger actor
+// notifyPassengerMatched tells the passenger actor, in actor mode, the driver the trip was
+// matched with
+func (rs *RideService) notifyPassengerMatched(trip *models.Trip, driver *models.Driver) {
+	if !rs.useActorModel {
+		return
+	}
+	driverLocation := models.Location{Latitude: *driver.CurrentLatitude, Longitude: *driver.CurrentLongitude}
 	payload := actor.RideMatchedPayload{
 		TripID:       trip.ID.String(),
-		DriverID:     bestDriver.ID.String(),
+		DriverID:     driver.ID.String(),
 		DriverName:   "Driver Name", // Would get from user table
 		DriverPhone:  "123-456-7890",
-		VehicleInfo:  bestDriver.VehicleType + " " + bestDriver.VehiclePlate,
-		DriverLat:    *bestDriver.CurrentLatitude,
-		DriverLng:    *bestDriver.CurrentLongitude,
-		EstimatedETA: 5 * time.Minute,
+		VehicleInfo:  driver.VehicleType + " " + driver.VehiclePlate,
+		DriverLat:    *driver.CurrentLatitude,
+		DriverLng:    *driver.CurrentLongitude,
+		EstimatedETA: time.Duration(rs.eta.Seconds(driverLocation.DistanceTo(models.Location{Latitude: trip.PickupLatitude, Longitude: trip.PickupLongitude}))) * time.Second,
 		MatchedAt:    time.Now(),
 	}
 
-	message := actor.NewBaseMessage(actor.MsgTypeRideMatched, payload, "trip-matcher")
+	message := actor.NewBaseMessage(actor.MsgTypeRideMatched, payload, "trip-matcher").
+		WithEntity(models.EntityTypeTrip, trip.ID.String())
 	passengerActorID := fmt.Sprintf("passenger-%s", trip.PassengerID.String())
 	rs.actorSystem.SendMessage(passengerActorID, message)
 
 	// Record the matching event
-	rs.metricsCollector.RecordMessage("trip-matcher", passengerActorID, actor.MsgTypeRideMatched, payload, time.Now())
+	rs.metricsCollector.RecordActorMessage(passengerActorID, message)
+}
+
+// publishTripEvent publishes the transition from a status, empty for a new trip, into the
+// trip's current one. Failures are logged rather than returned so a webhook problem never fails
+// the ride operation.
+func (rs *RideService) publishTripEvent(ctx context.Context, trip *models.Trip, from models.TripStatus) {
+	rs.publishTripChange(ctx, TripStateChange{Trip: trip, From: from})
+}
+
+// publishTripChange publishes a trip state change as publishTripEvent does, stamped with the
+// service's mode and the current time
+func (rs *RideService) publishTripChange(ctx context.Context, change TripStateChange) {
+	trip := change.Trip
+	if rs.tripEvents != nil {
+		change.Mode, change.At = rs.mode(), time.Now()
+		rs.tripEvents.Emit(ctx, change)
+	}
+
+	if rs.statusPublisher != nil {
+		// A copy, since the caller may keep changing the trip
+		snapshot := *trip
+		rs.statusPublisher.Publish(bus.TopicTripStatus, &snapshot)
+	}
+
+	if rs.eventPublisher == nil {
+		return
+	}
+	event, ok := models.WebhookEventForStatus(trip.Status)
+	if !ok {
+		return
+	}
+
+	if err := rs.eventPublisher.PublishTripEvent(context.WithoutCancel(ctx), event, trip); err != nil {
+		rs.logger.WithError(err).WithFields(logging.Fields{
+			"trip_id": trip.ID,
+			"event":   event,
+		}).Error("Failed to publish trip event")
+	}
+}
+
+// mode is the mode the service runs in, as labelled in its trip events
+func (rs *RideService) mode() string {
+	if rs.useActorModel {
+		return TripEventModeActor
+	}
+	return TripEventModeTraditional
 }
 
 // findNearbyDrivers finds drivers within a specified radius
@@ -374,10 +771,14 @@ func (rs *RideService) findNearbyDrivers(ctx context.Context, location models.Lo
 	if err != nil {
 		return nil, err
 	}
+	allDrivers = rs.withLatestLocations(ctx, allDrivers)
 
 	var nearbyDrivers []*models.Driver
 	for _, driver := range allDrivers {
-		distance := rs.calculateDistance(location.Latitude, location.Longitude, *driver.CurrentLatitude, *driver.CurrentLongitude)
+		if driver.CurrentLatitude == nil || driver.CurrentLongitude == nil {
+			continue
+		}
+		distance := location.DistanceTo(models.Location{Latitude: *driver.CurrentLatitude, Longitude: *driver.CurrentLongitude})
 		if distance <= radiusKm {
 			nearbyDrivers = append(nearbyDrivers, driver)
 		}
@@ -386,29 +787,120 @@ func (rs *RideService) findNearbyDrivers(ctx context.Context, location models.Lo
 	return nearbyDrivers, nil
 }
 
-// selectBestDriver selects the best driver based on distance and rating
-func (rs *RideService) selectBestDriver(drivers []*models.Driver, pickup models.Location) *models.Driver {
+// withLatestLocations returns drivers with the locations they reported since their location was
+// last stored, when a location ingester buffers them. The drivers are copied, not updated.
+func (rs *RideService) withLatestLocations(ctx context.Context, drivers []*models.Driver) []*models.Driver {
+	if rs.locations == nil || len(drivers) == 0 {
+		return drivers
+	}
+
+	ids := make([]uuid.UUID, len(drivers))
+	for i, driver := range drivers {
+		ids[i] = driver.ID
+	}
+	latest := rs.locations.LatestLocations(ctx, ids)
+	if len(latest) == 0 {
+		return drivers
+	}
+
+	result := make([]*models.Driver, len(drivers))
+	for i, driver := range drivers {
+		result[i] = driver
+		if update, ok := latest[driver.ID]; ok {
+			moved := *driver
+			moved.CurrentLatitude = &update.Latitude
+			moved.CurrentLongitude = &update.Longitude
+			result[i] = &moved
+		}
+	}
+	return result
+}
+
+// filterByDestination drops drivers in destination mode for whom the trip does not lead
+// toward their destination
+func (rs *RideService) filterByDestination(ctx context.Context, drivers []*models.Driver, pickup, dropoff models.Location) ([]*models.Driver, error) {
 	if len(drivers) == 0 {
-		return nil
+		return drivers, nil
+	}
+
+	destinations, err := rs.driverRepo.GetActiveDestinations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(destinations) == 0 {
+		return drivers, nil
+	}
+
+	var eligible []*models.Driver
+	for _, driver := range drivers {
+		destination, ok := destinations[driver.ID.String()]
+		if ok {
+			current := models.Location{Latitude: *driver.CurrentLatitude, Longitude: *driver.CurrentLongitude}
+			if !destination.AllowsTrip(current, pickup, dropoff) {
+				continue
+			}
+		}
+		eligible = append(eligible, driver)
 	}
 
-	bestDriver := drivers[0]
-	bestScore := rs.calculateDriverScore(bestDriver, pickup)
+	return eligible, nil
+}
 
-	for _, driver := range drivers[1:] {
-		score := rs.calculateDriverScore(driver, pickup)
-		if score > bestScore {
-			bestDriver = driver
-			bestScore = score
+// reserveBestDriver reserves the best of the drivers for a trip, moving on to the next best when
+// another request reserved a driver first. It returns nil if every driver was taken. Every
+// attempt is counted by outcome, so the metrics show how often requests contend for drivers.
+func (rs *RideService) reserveBestDriver(ctx context.Context, drivers []*models.Driver, pickup models.Location, method string) (*models.Driver, error) {
+	for _, driver := range rs.rankDrivers(drivers, pickup) {
+		err := rs.driverRepo.Reserve(ctx, driver.ID.String())
+		if errors.Is(err, models.ErrDriverNotOnline) {
+			rs.recordReservation(method, "conflict")
+			rs.logger.WithField("driver_id", driver.ID).Debug("Driver reserved by another request")
+			continue
 		}
+		if err != nil {
+			return nil, err
+		}
+
+		rs.recordReservation(method, "reserved")
+		driver.Status = models.DriverStatusBusy
+		return driver, nil
 	}
+	return nil, nil
+}
 
-	return bestDriver
+// releaseDriver releases a reserved driver whose trip could not be assigned
+func (rs *RideService) releaseDriver(ctx context.Context, driver *models.Driver) {
+	if err := rs.driverRepo.Release(ctx, driver.ID.String()); err != nil {
+		rs.logger.WithError(err).WithField("driver_id", driver.ID).Error("Failed to release driver")
+		return
+	}
+	driver.Status = models.DriverStatusOnline
+}
+
+// recordReservation counts a driver reservation attempt by outcome: reserved or conflict
+func (rs *RideService) recordReservation(method, outcome string) {
+	rs.traditionalMonitor.RecordBusinessMetrics("driver_reservations_total", 1, map[string]string{
+		"method":  method,
+		"outcome": outcome,
+	})
+}
+
+// rankDrivers orders the drivers best first, by distance and rating
+func (rs *RideService) rankDrivers(drivers []*models.Driver, pickup models.Location) []*models.Driver {
+	ranked := append([]*models.Driver(nil), drivers...)
+	scores := make(map[*models.Driver]float64, len(ranked))
+	for _, driver := range ranked {
+		scores[driver] = rs.calculateDriverScore(driver, pickup)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return scores[ranked[i]] > scores[ranked[j]]
+	})
+	return ranked
 }
 
 // calculateDriverScore calculates a score for driver selection
 func (rs *RideService) calculateDriverScore(driver *models.Driver, pickup models.Location) float64 {
-	distance := rs.calculateDistance(pickup.Latitude, pickup.Longitude, *driver.CurrentLatitude, *driver.CurrentLongitude)
+	distance := pickup.DistanceTo(models.Location{Latitude: *driver.CurrentLatitude, Longitude: *driver.CurrentLongitude})
 
 	// Score based on distance (closer is better) and rating (higher is better)
 	// Normalize distance to 0-1 scale (assuming max 10km)
@@ -421,29 +913,9 @@ func (rs *RideService) calculateDriverScore(driver *models.Driver, pickup models
 	return (distanceScore * 0.7) + (ratingScore * 0.3)
 }
 
-// calculateDistance calculates the distance between two points using Haversine formula
-func (rs *RideService) calculateDistance(lat1, lng1, lat2, lng2 float64) float64 {
-	const earthRadius = 6371 // Earth's radius in kilometers
-
-	// Convert degrees to radians
-	lat1Rad := lat1 * math.Pi / 180
-	lng1Rad := lng1 * math.Pi / 180
-	lat2Rad := lat2 * math.Pi / 180
-	lng2Rad := lng2 * math.Pi / 180
-
-	// Haversine formula
-	dlat := lat2Rad - lat1Rad
-	dlng := lng2Rad - lng1Rad
-	a := math.Sin(dlat/2)*math.Sin(dlat/2) + math.Cos(lat1Rad)*math.Cos(lat2Rad)*math.Sin(dlng/2)*math.Sin(dlng/2)
-	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-	distance := earthRadius * c
-
-	return distance
-}
-
 // calculateEstimatedFare calculates estimated fare based on distance
 func (rs *RideService) calculateEstimatedFare(pickup, dropoff models.Location) float64 {
-	distance := rs.calculateDistance(pickup.Latitude, pickup.Longitude, dropoff.Latitude, dropoff.Longitude)
+	distance := pickup.DistanceTo(dropoff)
 
 	// Simple fare calculation: base fare + distance rate
 	baseFare := 5.0
@@ -462,7 +934,20 @@ func (rs *RideService) GetTripStatus(ctx context.Context, tripID string) (*model
 		}
 	}()
 
-	return rs.tripRepo.GetByID(ctx, tripID)
+	if rs.tripCache != nil {
+		if trip, ok := rs.tripCache.Get(ctx, tripID); ok {
+			return trip, nil
+		}
+	}
+
+	trip, err := rs.tripRepo.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+	if rs.tripCache != nil {
+		rs.tripCache.Put(ctx, trip)
+	}
+	return trip, nil
 }
 
 // ListRides returns a paginated list of rides with optional filtering
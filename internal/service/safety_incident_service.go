@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/redaction"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// safetyIncidentEntityType is the entity type of the event logs recorded for an incident
+const safetyIncidentEntityType = "safety_incident"
+
+// SafetyIncidentService records SOS reports raised by a trip's passenger or driver. Reporting
+// an incident raises a fatal security event log and alerts webhook subscribers immediately, and
+// snapshots the trip and its timeline. The trip cannot be deleted and its chat is not purged
+// while the incident awaits review; admins move it under review and close it.
+type SafetyIncidentService struct {
+	incidents    repository.SafetyIncidentRepository
+	participants tripParticipants
+	timelines    *TimelineService
+	notifier     SafetyIncidentNotifier
+	events       bus.Publisher
+	logger       *logging.Logger
+	now          func() time.Time
+}
+
+// NewSafetyIncidentService creates a new safety incident service. Snapshots include the trip's
+// timeline when timelines is set; alerts are sent to notifier and events are published to
+// events. timelines, notifier and events may be nil.
+func NewSafetyIncidentService(
+	incidents repository.SafetyIncidentRepository,
+	trips repository.TripRepository,
+	drivers repository.DriverRepository,
+	passengers repository.PassengerRepository,
+	timelines *TimelineService,
+	notifier SafetyIncidentNotifier,
+	events bus.Publisher,
+	logger *logging.Logger,
+) *SafetyIncidentService {
+	return &SafetyIncidentService{
+		incidents:    incidents,
+		participants: tripParticipants{trips: trips, drivers: drivers, passengers: passengers},
+		timelines:    timelines,
+		notifier:     notifier,
+		events:       events,
+		logger:       logger.WithComponent("safety_incident_service"),
+		now:          time.Now,
+	}
+}
+
+// Report records an SOS raised by a participant of a trip. location is the reporter's device
+// location and may be nil, in which case the driver's last known location is used if any.
+func (s *SafetyIncidentService) Report(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType, location *models.Location, note string) (*models.SafetyIncident, error) {
+	trip, err := s.participants.authorize(ctx, tripID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.now()
+	incident := &models.SafetyIncident{
+		ID:           uuid.New(),
+		TripID:       trip.ID,
+		ReporterType: userType,
+		ReporterID:   userID,
+		TripStatus:   trip.Status,
+		Status:       models.SafetyIncidentOpen,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if note = strings.TrimSpace(note); note != "" {
+		incident.Note = &note
+	}
+	s.locate(ctx, incident, trip, location)
+
+	if err := incident.Validate(); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "incident",
+			Message: err.Error(),
+		}
+	}
+	incident.Snapshot = s.snapshot(ctx, trip)
+
+	if err := s.incidents.Create(ctx, incident); err != nil {
+		return nil, err
+	}
+
+	s.publish("safety_incident_reported", incident, "", models.EventSeverityFatal, map[string]interface{}{
+		"reporter_type":   incident.ReporterType,
+		"reporter_id":     incident.ReporterID,
+		"trip_status":     incident.TripStatus,
+		"latitude":        incident.Latitude,
+		"longitude":       incident.Longitude,
+		"location_source": incident.LocationSource,
+		"note":            incident.Note,
+	}, "SOS raised on trip")
+
+	if s.notifier != nil {
+		if err := s.notifier.PublishSafetyIncident(ctx, trip, incident); err != nil {
+			s.logger.WithError(err).WithField("incident_id", incident.ID.String()).Error("Failed to send safety incident alert")
+		}
+	}
+	return incident, nil
+}
+
+// GetIncident returns a safety incident by ID
+func (s *SafetyIncidentService) GetIncident(ctx context.Context, id string) (*models.SafetyIncident, error) {
+	return s.incidents.GetByID(ctx, id)
+}
+
+// ListIncidents returns the matching incidents, oldest first, and the total number of matches
+func (s *SafetyIncidentService) ListIncidents(ctx context.Context, filter models.SafetyIncidentFilter) ([]*models.SafetyIncident, int64, error) {
+	return s.incidents.List(ctx, filter)
+}
+
+// StartReview moves an open incident under review by reviewer
+func (s *SafetyIncidentService) StartReview(ctx context.Context, id, reviewer string) (*models.SafetyIncident, error) {
+	incident, err := s.transition(ctx, id, models.SafetyIncidentUnderReview)
+	if err != nil {
+		return nil, err
+	}
+
+	incident.StartReview(reviewer, s.now())
+	if err := s.incidents.Update(ctx, incident, models.SafetyIncidentOpen); err != nil {
+		return nil, err
+	}
+
+	s.publish("safety_incident_review_started", incident, reviewer, models.EventSeverityInfo, nil, "Safety incident review started")
+	return incident, nil
+}
+
+// Close closes an open or under review incident, lifting the freeze of the trip's data once
+// no other incident of the trip awaits review
+func (s *SafetyIncidentService) Close(ctx context.Context, id, reviewer, note string) (*models.SafetyIncident, error) {
+	incident, err := s.transition(ctx, id, models.SafetyIncidentClosed)
+	if err != nil {
+		return nil, err
+	}
+
+	from := incident.Status
+	incident.Close(reviewer, strings.TrimSpace(note), s.now())
+	if err := incident.Validate(); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "note",
+			Message: err.Error(),
+		}
+	}
+	if err := s.incidents.Update(ctx, incident, from); err != nil {
+		return nil, err
+	}
+
+	s.publish("safety_incident_closed", incident, reviewer, models.EventSeverityInfo, map[string]interface{}{
+		"resolution_note": incident.ResolutionNote,
+	}, "Safety incident closed")
+	return incident, nil
+}
+
+// transition loads the incident and checks that it can move to status
+func (s *SafetyIncidentService) transition(ctx context.Context, id string, status models.SafetyIncidentStatus) (*models.SafetyIncident, error) {
+	incident, err := s.incidents.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !incident.Status.CanTransitionTo(status) {
+		return nil, fmt.Errorf("%w: safety incident is %s and cannot become %s", models.ErrInvalidStatusTransition, incident.Status, status)
+	}
+	return incident, nil
+}
+
+// locate sets the incident's location from the reporter's device, falling back to the driver's
+// last known location
+func (s *SafetyIncidentService) locate(ctx context.Context, incident *models.SafetyIncident, trip *models.Trip, location *models.Location) {
+	source := models.IncidentLocationReporter
+	if location == nil {
+		if trip.DriverID == nil {
+			return
+		}
+		driver, err := s.participants.drivers.GetByID(ctx, trip.DriverID.String())
+		if err != nil {
+			s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Warn("Failed to load driver location for safety incident")
+			return
+		}
+		lat, lng, ok := driver.GetLocation()
+		if !ok {
+			return
+		}
+		location = &models.Location{Latitude: lat, Longitude: lng}
+		source = models.IncidentLocationDriver
+	}
+
+	incident.Latitude = &location.Latitude
+	incident.Longitude = &location.Longitude
+	incident.LocationSource = &source
+}
+
+// snapshot encodes the trip and, when available, its timeline as seen by an operator. Failing
+// to build the timeline must not block an SOS, so it is logged and left out.
+func (s *SafetyIncidentService) snapshot(ctx context.Context, trip *models.Trip) json.RawMessage {
+	snapshot := models.SafetyIncidentSnapshot{Trip: trip}
+	if s.timelines != nil {
+		timeline, err := s.timelines.GetTripTimeline(redaction.WithOperator(ctx), trip.ID.String())
+		if err != nil {
+			s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Warn("Failed to snapshot trip timeline for safety incident")
+		} else {
+			snapshot.Timeline = timeline
+		}
+	}
+
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Warn("Failed to encode safety incident snapshot")
+		return nil
+	}
+	return raw
+}
+
+// publish logs an incident workflow step and publishes it as a security event log on the incident
+func (s *SafetyIncidentService) publish(eventType string, incident *models.SafetyIncident, actor string, severity models.EventSeverity, data map[string]interface{}, message string) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["incident_id"] = incident.ID
+	data["trip_id"] = incident.TripID
+	data["status"] = incident.Status
+	if actor != "" {
+		data["reviewed_by"] = actor
+	}
+
+	entry := s.logger.WithFields(logging.Fields{
+		"event_type":  eventType,
+		"incident_id": incident.ID.String(),
+		"trip_id":     incident.TripID.String(),
+		"status":      string(incident.Status),
+	})
+	if severity == models.EventSeverityFatal {
+		entry.Error(message)
+	} else {
+		entry.Info(message)
+	}
+
+	if s.events == nil {
+		return
+	}
+
+	entityType, entityID := safetyIncidentEntityType, incident.ID
+	eventLog := &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     eventType,
+		EventCategory: models.EventCategorySecurity,
+		EntityType:    &entityType,
+		EntityID:      &entityID,
+		Severity:      severity,
+		Message:       message,
+		Timestamp:     time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	eventLog.EventData, _ = json.Marshal(data)
+	s.events.Publish(bus.TopicEventLog, eventLog)
+}
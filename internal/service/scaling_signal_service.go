@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/internal/repository"
+)
+
+// scalingMetrics are the metrics of the autoscaling signal, in the order they are reported
+var scalingMetrics = []string{
+	models.ScalingMetricMailboxBacklog,
+	models.ScalingMetricMailboxBacklogMax,
+	models.ScalingMetricPendingRides,
+	models.ScalingMetricLatencyHeadroom,
+	models.ScalingMetricLatencyBurnRate,
+}
+
+// ScalingSignalService summarizes load into autoscaling signals: the messages waiting in the
+// actor mailboxes of the instance serving it, the ride requests waiting for a driver across
+// instances, and the latency error budget left to the endpoints with an SLO
+type ScalingSignalService struct {
+	system *actor.ActorSystem
+	trips  repository.TripRepository
+	slo    *observability.SLOTracker
+	now    func() time.Time
+}
+
+// NewScalingSignalService creates a new scaling signal service. A nil system reports empty
+// mailboxes and a nil tracker full latency headroom.
+func NewScalingSignalService(system *actor.ActorSystem, trips repository.TripRepository, slo *observability.SLOTracker) *ScalingSignalService {
+	return &ScalingSignalService{
+		system: system,
+		trips:  trips,
+		slo:    slo,
+		now:    time.Now,
+	}
+}
+
+// Signal returns the autoscaling signal, only the named metric when metric is not empty
+func (s *ScalingSignalService) Signal(ctx context.Context, metric string) (*models.ExternalMetricValueList, error) {
+	if metric != "" && !isScalingMetric(metric) {
+		return nil, &models.ValidationError{
+			Field:   "metric",
+			Message: fmt.Sprintf("unknown metric %q", metric),
+		}
+	}
+
+	pending, err := s.trips.CountByStatus(ctx, models.TripStatusRequested)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count pending ride requests: %w", err)
+	}
+
+	now := s.now()
+	backlog, deepest := s.mailboxBacklog()
+	headroom, burnRate := s.latencyHeadroom()
+
+	signal := models.NewExternalMetricValueList()
+	signal.Add(models.ScalingMetricMailboxBacklog, float64(backlog), now)
+	signal.Add(models.ScalingMetricMailboxBacklogMax, float64(deepest), now)
+	signal.Add(models.ScalingMetricPendingRides, float64(pending), now)
+	signal.Add(models.ScalingMetricLatencyHeadroom, headroom, now)
+	signal.Add(models.ScalingMetricLatencyBurnRate, burnRate, now)
+	if metric != "" {
+		signal.Filter(metric)
+	}
+	return signal, nil
+}
+
+// mailboxBacklog returns the messages waiting in every actor mailbox and in the fullest one
+func (s *ScalingSignalService) mailboxBacklog() (total, deepest int) {
+	if s.system == nil {
+		return 0, 0
+	}
+	for _, ref := range s.system.ListActors() {
+		queued := ref.Actor.GetMetrics().CurrentQueueSize
+		total += queued
+		if queued > deepest {
+			deepest = queued
+		}
+	}
+	return total, deepest
+}
+
+// latencyHeadroom returns the smallest fraction of a latency error budget left and the fastest
+// burn rate among the endpoints with requests in the SLO window. Without any, the headroom is
+// full and nothing burns.
+func (s *ScalingSignalService) latencyHeadroom() (headroom, burnRate float64) {
+	headroom = 1
+	if s.slo == nil {
+		return headroom, 0
+	}
+	for _, endpoint := range s.slo.Report().Endpoints {
+		if endpoint.TotalRequests == 0 {
+			continue
+		}
+		if endpoint.Latency.ErrorBudgetRemaining < headroom {
+			headroom = endpoint.Latency.ErrorBudgetRemaining
+		}
+		if endpoint.Latency.BurnRate > burnRate {
+			burnRate = endpoint.Latency.BurnRate
+		}
+	}
+	return headroom, burnRate
+}
+
+// isScalingMetric reports whether name is a metric of the autoscaling signal
+func isScalingMetric(name string) bool {
+	for _, metric := range scalingMetrics {
+		if metric == name {
+			return true
+		}
+	}
+	return false
+}
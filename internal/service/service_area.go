@@ -0,0 +1,127 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+)
+
+// ServiceAreaPolicy decides whether rides may be requested from a pickup to a destination at a
+// time, from the configured service area polygons and operating hours, and counts the rejections by
+// reason
+type ServiceAreaPolicy struct {
+	cfg      config.ServiceAreaConfig
+	location *time.Location
+	metrics  BusinessMetricsRecorder
+}
+
+// NewServiceAreaPolicy creates a service area policy
+func NewServiceAreaPolicy(cfg config.ServiceAreaConfig, metrics BusinessMetricsRecorder) (*ServiceAreaPolicy, error) {
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid service area timezone: %w", err)
+	}
+	return &ServiceAreaPolicy{
+		cfg:      cfg,
+		location: location,
+		metrics:  metrics,
+	}, nil
+}
+
+// Check returns a RideRejectedError when the service is closed at the time or the pickup or
+// destination is outside every service area, and nil when the ride may be requested or the
+// policy is disabled
+func (p *ServiceAreaPolicy) Check(pickup, destination models.Location, at time.Time) error {
+	if !p.cfg.Enabled {
+		return nil
+	}
+
+	var reason models.RideRejectionReason
+	switch {
+	case !p.open(at.In(p.location)):
+		reason = models.RideRejectionOutsideHours
+	case !p.served(pickup):
+		reason = models.RideRejectionPickupOutsideArea
+	case !p.served(destination):
+		reason = models.RideRejectionDestinationOutsideArea
+	default:
+		return nil
+	}
+
+	if p.metrics != nil {
+		p.metrics.RecordBusinessMetrics("ride_request_rejections_total", 1, map[string]string{
+			"reason": string(reason),
+		})
+	}
+	return &models.RideRejectedError{Reason: reason}
+}
+
+// served reports whether a location is inside one of the service areas
+func (p *ServiceAreaPolicy) served(location models.Location) bool {
+	if len(p.cfg.Areas) == 0 {
+		return true
+	}
+	for _, area := range p.cfg.Areas {
+		if polygonContains(area.Polygon, location) {
+			return true
+		}
+	}
+	return false
+}
+
+// open reports whether the local time falls within the operating hours. Hours closing past
+// midnight keep the service open into the next day.
+func (p *ServiceAreaPolicy) open(local time.Time) bool {
+	if len(p.cfg.Hours) == 0 {
+		return true
+	}
+
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, p.location)
+	sinceMidnight := local.Sub(midnight)
+	yesterday := midnight.AddDate(0, 0, -1).Weekday()
+	for _, hours := range p.cfg.Hours {
+		if hours.Close > hours.Open {
+			if onDay(hours, local.Weekday()) && sinceMidnight >= hours.Open && sinceMidnight < hours.Close {
+				return true
+			}
+			continue
+		}
+		// Runs past midnight: open from Open to midnight, and from midnight to Close the next day
+		if onDay(hours, local.Weekday()) && sinceMidnight >= hours.Open {
+			return true
+		}
+		if onDay(hours, yesterday) && sinceMidnight < hours.Close {
+			return true
+		}
+	}
+	return false
+}
+
+// onDay reports whether the hours apply on a weekday
+func onDay(hours config.OperatingHours, day time.Weekday) bool {
+	if len(hours.Days) == 0 {
+		return true
+	}
+	for _, d := range hours.Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// polygonContains reports whether a location is inside a polygon, by counting the polygon edges
+// a ray cast east from it crosses
+func polygonContains(polygon []config.GeoPoint, location models.Location) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Lat > location.Latitude) != (b.Lat > location.Latitude) &&
+			location.Longitude < (b.Lng-a.Lng)*(location.Latitude-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
@@ -0,0 +1,351 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// sessionTokenBytes is the amount of randomness in access and refresh tokens
+const sessionTokenBytes = 32
+
+// LoginCredentials identifies the user and device starting a session. Users have no passwords
+// in this system, so the account's email and phone must both match, which is enough for the
+// demo apps but not a production credential.
+type LoginCredentials struct {
+	Email      string
+	Phone      string
+	DeviceID   string
+	DeviceName *string
+	ClientIP   string
+	App        ClientApp
+}
+
+// ClientApp is the app platform and version a session call was made with; both are empty when
+// the app did not report them
+type ClientApp struct {
+	Platform string
+	Version  string
+}
+
+// apply records the app on the session when it reported its version
+func (a ClientApp) apply(session *models.Session) {
+	if a.Version == "" {
+		return
+	}
+	platform, version := a.Platform, a.Version
+	session.AppPlatform = &platform
+	session.AppVersion = &version
+}
+
+// SessionService issues and revokes driver and passenger device sessions. Access tokens are
+// short-lived; refresh tokens are rotated on every use. Each user keeps at most
+// MaxSessionsPerUser active sessions, and every session change is published as a
+// security event log for auditing.
+type SessionService struct {
+	sessions repository.SessionRepository
+	users    repository.UserRepository
+	cfg      config.AuthConfig
+	events   bus.Publisher
+	logger   *logging.Logger
+	now      func() time.Time
+}
+
+// NewSessionService creates a new session service. Security events are published to events,
+// which may be nil.
+func NewSessionService(sessions repository.SessionRepository, users repository.UserRepository, cfg config.AuthConfig, events bus.Publisher, logger *logging.Logger) *SessionService {
+	return &SessionService{
+		sessions: sessions,
+		users:    users,
+		cfg:      cfg,
+		events:   events,
+		logger:   logger.WithComponent("session_service"),
+		now:      time.Now,
+	}
+}
+
+// Login verifies the credentials and starts a session for the device. A device that is already
+// logged in gets its previous session replaced; beyond the concurrent session limit the user's
+// least recently used sessions are revoked.
+func (s *SessionService) Login(ctx context.Context, creds LoginCredentials) (*models.SessionTokens, error) {
+	user, err := s.users.GetByEmail(ctx, creds.Email)
+	if err != nil {
+		var notFound *models.NotFoundError
+		if !errors.As(err, &notFound) {
+			return nil, fmt.Errorf("failed to look up user: %w", err)
+		}
+		user = nil
+	}
+	if user == nil || user.Phone != creds.Phone {
+		s.publish("login_failed", models.EventSeverityWarn, nil, map[string]interface{}{
+			"email":     creds.Email,
+			"device_id": creds.DeviceID,
+			"client_ip": creds.ClientIP,
+		}, "Login failed: credentials did not match a user")
+		return nil, models.ErrInvalidCredentials
+	}
+
+	now := s.now()
+	active, err := s.sessions.ListActiveByUser(ctx, user.ID.String(), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	// Replace the device's previous session, then evict the least recently used ones so the
+	// new session stays within the limit
+	var kept []*models.Session
+	for _, existing := range active {
+		if existing.DeviceID == creds.DeviceID {
+			if err := s.revoke(ctx, existing, models.SessionRevokedReplaced, creds.ClientIP); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	for len(kept) >= s.cfg.MaxSessionsPerUser {
+		oldest := kept[len(kept)-1]
+		kept = kept[:len(kept)-1]
+		if err := s.revoke(ctx, oldest, models.SessionRevokedLimit, creds.ClientIP); err != nil {
+			return nil, err
+		}
+	}
+
+	session := &models.Session{
+		ID:         uuid.New(),
+		UserID:     user.ID,
+		UserType:   user.UserType,
+		DeviceID:   creds.DeviceID,
+		DeviceName: creds.DeviceName,
+		ExpiresAt:  now.Add(s.cfg.RefreshTokenTTL),
+		LastUsedAt: now,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	creds.App.apply(session)
+	tokens, err := s.issueTokens(session, now)
+	if err != nil {
+		return nil, err
+	}
+	if err := session.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	s.publish("session_created", models.EventSeverityInfo, session, map[string]interface{}{
+		"client_ip": creds.ClientIP,
+	}, fmt.Sprintf("Session started for %s %s", session.UserType, session.UserID))
+
+	return tokens, nil
+}
+
+// Refresh exchanges a refresh token for a new token pair. The presented refresh token stops
+// working; the session's expiry is unchanged. The session records the app version when the
+// app reports one, e.g. after the app was upgraded.
+func (s *SessionService) Refresh(ctx context.Context, refreshToken, clientIP string, app ClientApp) (*models.SessionTokens, error) {
+	session, err := s.sessions.GetByRefreshTokenHash(ctx, hashSessionToken(refreshToken))
+	if err != nil {
+		var notFound *models.NotFoundError
+		if errors.As(err, &notFound) {
+			s.publish("refresh_failed", models.EventSeverityWarn, nil, map[string]interface{}{
+				"client_ip": clientIP,
+			}, "Session refresh failed: unknown refresh token")
+			return nil, models.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to look up session: %w", err)
+	}
+
+	now := s.now()
+	if !session.IsActive(now) {
+		s.publish("refresh_failed", models.EventSeverityWarn, session, map[string]interface{}{
+			"client_ip": clientIP,
+		}, "Session refresh failed: session revoked or expired")
+		return nil, models.ErrInvalidToken
+	}
+
+	tokens, err := s.issueTokens(session, now)
+	if err != nil {
+		return nil, err
+	}
+	session.LastUsedAt = now
+	session.UpdatedAt = now
+	app.apply(session)
+	if err := s.sessions.Update(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+
+	s.publish("session_refreshed", models.EventSeverityInfo, session, map[string]interface{}{
+		"client_ip": clientIP,
+	}, fmt.Sprintf("Session refreshed for %s %s", session.UserType, session.UserID))
+
+	return tokens, nil
+}
+
+// Authenticate returns the active session an access token belongs to
+func (s *SessionService) Authenticate(ctx context.Context, accessToken string) (*models.Session, error) {
+	if accessToken == "" {
+		return nil, models.ErrInvalidToken
+	}
+
+	session, err := s.sessions.GetByAccessTokenHash(ctx, hashSessionToken(accessToken))
+	if err != nil {
+		var notFound *models.NotFoundError
+		if errors.As(err, &notFound) {
+			return nil, models.ErrInvalidToken
+		}
+		return nil, fmt.Errorf("failed to look up session: %w", err)
+	}
+
+	now := s.now()
+	if !session.IsActive(now) || !now.Before(session.AccessExpiresAt) {
+		return nil, models.ErrInvalidToken
+	}
+
+	return session, nil
+}
+
+// CountSessionsByAppVersion returns the number of active sessions per app platform and version
+func (s *SessionService) CountSessionsByAppVersion(ctx context.Context) ([]*models.AppVersionSessions, error) {
+	counts, err := s.sessions.CountActiveByAppVersion(ctx, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to count sessions by app version: %w", err)
+	}
+	return counts, nil
+}
+
+// ListSessions returns the user's active sessions, most recently used first
+func (s *SessionService) ListSessions(ctx context.Context, userID string) ([]*models.Session, error) {
+	sessions, err := s.sessions.ListActiveByUser(ctx, userID, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// RevokeSession revokes one of the user's sessions. Sessions of other users, and sessions
+// that are already revoked, are reported as not found.
+func (s *SessionService) RevokeSession(ctx context.Context, userID, sessionID, clientIP string) error {
+	session, err := s.sessions.GetByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if session.UserID.String() != userID || session.RevokedAt != nil {
+		return &models.NotFoundError{Resource: "session", ID: sessionID}
+	}
+
+	return s.revoke(ctx, session, models.SessionRevokedByUser, clientIP)
+}
+
+// revoke marks a session as revoked and records why
+func (s *SessionService) revoke(ctx context.Context, session *models.Session, reason, clientIP string) error {
+	session.Revoke(reason, s.now())
+	if err := s.sessions.Update(ctx, session); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	eventType := "session_revoked"
+	if reason == models.SessionRevokedLimit {
+		eventType = "session_evicted"
+	}
+	s.publish(eventType, models.EventSeverityInfo, session, map[string]interface{}{
+		"reason":    reason,
+		"client_ip": clientIP,
+	}, fmt.Sprintf("Session revoked for %s %s: %s", session.UserType, session.UserID, reason))
+
+	return nil
+}
+
+// issueTokens generates a new token pair for the session and stores their hashes on it
+func (s *SessionService) issueTokens(session *models.Session, now time.Time) (*models.SessionTokens, error) {
+	accessToken, err := generateSessionToken()
+	if err != nil {
+		return nil, err
+	}
+	refreshToken, err := generateSessionToken()
+	if err != nil {
+		return nil, err
+	}
+
+	session.AccessTokenHash = hashSessionToken(accessToken)
+	session.RefreshTokenHash = hashSessionToken(refreshToken)
+	session.AccessExpiresAt = now.Add(s.cfg.AccessTokenTTL)
+	if session.AccessExpiresAt.After(session.ExpiresAt) {
+		session.AccessExpiresAt = session.ExpiresAt
+	}
+
+	return &models.SessionTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(session.AccessExpiresAt.Sub(now).Seconds()),
+		Session:      session,
+	}, nil
+}
+
+// publish records a security event log for a session change; session may be nil
+func (s *SessionService) publish(eventType string, severity models.EventSeverity, session *models.Session, data map[string]interface{}, message string) {
+	fields := logging.Fields{"event_type": eventType}
+	for k, v := range data {
+		fields[k] = v
+	}
+	if session != nil {
+		fields["session_id"] = session.ID.String()
+		fields["user_id"] = session.UserID.String()
+	}
+	s.logger.WithFields(fields).Info(message)
+
+	if s.events == nil {
+		return
+	}
+
+	eventLog := &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     eventType,
+		EventCategory: models.EventCategorySecurity,
+		Severity:      severity,
+		Message:       message,
+		Timestamp:     time.Now(),
+		CreatedAt:     time.Now(),
+	}
+	if session != nil {
+		entityType, entityID := "session", session.ID
+		eventLog.EntityType = &entityType
+		eventLog.EntityID = &entityID
+		data["user_id"] = session.UserID
+		data["user_type"] = session.UserType
+		data["device_id"] = session.DeviceID
+	}
+	eventLog.EventData, _ = json.Marshal(data)
+	s.events.Publish(bus.TopicEventLog, eventLog)
+}
+
+// generateSessionToken returns a random URL-safe token
+func generateSessionToken() (string, error) {
+	b := make([]byte, sessionTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate session token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// hashSessionToken returns the hex SHA-256 of a token, as stored on the session
+func hashSessionToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+)
+
+// Defaults and limits of the storage cost report
+const (
+	DefaultStorageCostBucket = time.Hour
+	MinStorageCostBucket     = time.Minute
+	MaxStorageCostBuckets    = 1000
+)
+
+// StorageCostService compares the observability storage of the actor and traditional pipelines
+// per ride request
+type StorageCostService struct {
+	storage repository.StorageCostRepository
+}
+
+// NewStorageCostService creates a new storage cost service
+func NewStorageCostService(storage repository.StorageCostRepository) *StorageCostService {
+	return &StorageCostService{storage: storage}
+}
+
+// Report measures the rows and bytes every observability table gained in [start, end) over
+// buckets of the period starting at start, and divides them by the ride requests made in each
+// processing mode
+func (s *StorageCostService) Report(ctx context.Context, start, end time.Time, bucket time.Duration) (*models.StorageCostReport, error) {
+	if bucket < MinStorageCostBucket {
+		return nil, &models.ValidationError{
+			Field:   "bucket",
+			Message: fmt.Sprintf("bucket must be at least %s", MinStorageCostBucket),
+		}
+	}
+	buckets := int((end.Sub(start) + bucket - 1) / bucket)
+	if buckets > MaxStorageCostBuckets {
+		return nil, &models.ValidationError{
+			Field:   "bucket",
+			Message: fmt.Sprintf("the period spans %d buckets of %s; use a longer bucket or a shorter period of at most %d buckets", buckets, bucket, MaxStorageCostBuckets),
+		}
+	}
+
+	var usage []*models.StorageUsage
+	for _, pipeline := range models.StoragePipelines {
+		for _, table := range models.StorageCostTables[pipeline] {
+			tableUsage, err := s.storage.TableUsage(ctx, table, start, end, bucket)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get storage usage: %w", err)
+			}
+			usage = append(usage, tableUsage...)
+		}
+	}
+
+	requests, err := s.storage.RideRequests(ctx, TripStateChangeEvent, start, end, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count ride requests: %w", err)
+	}
+
+	return models.BuildStorageCostReport(start, end, bucket, usage, requests), nil
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/redaction"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// timelineScanLimit caps the records read from each source for one timeline
+	timelineScanLimit = 1000
+	// timelineWindowMargin widens the trip's time window to catch records written just before
+	// the trip was stored or just after it ended
+	timelineWindowMargin = time.Minute
+)
+
+// timelineSourceOrder orders entries with the same timestamp
+var timelineSourceOrder = map[string]int{
+	models.TimelineSourceTrip:    0,
+	models.TimelineSourceEvent:   1,
+	models.TimelineSourceMessage: 2,
+	models.TimelineSourceTrace:   3,
+	models.TimelineSourceLog:     4,
+}
+
+// TimelineService builds trip timelines for end-to-end debugging. Event logs are linked to
+// trips by entity; actor messages and traditional logs by the trip_id in their payload or
+// fields, within the trip's time window; spans by the trace IDs of those messages and events.
+type TimelineService struct {
+	trips         repository.TripRepository
+	observability repository.ObservabilityRepository
+	traditional   repository.TraditionalRepository
+	redactor      *redaction.Redactor
+	now           func() time.Time
+}
+
+// NewTimelineService creates a new trip timeline service. Message payloads, event data and log
+// fields are redacted with redactor for callers without the operator role; redactor may be nil.
+func NewTimelineService(trips repository.TripRepository, observability repository.ObservabilityRepository, traditional repository.TraditionalRepository, redactor *redaction.Redactor) *TimelineService {
+	return &TimelineService{
+		trips:         trips,
+		observability: observability,
+		traditional:   traditional,
+		redactor:      redactor,
+		now:           time.Now,
+	}
+}
+
+// GetTripTimeline returns everything recorded about a trip, oldest first
+func (s *TimelineService) GetTripTimeline(ctx context.Context, tripID string) (*models.TripTimeline, error) {
+	trip, err := s.trips.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	end := s.now()
+	if trip.CompletedAt != nil {
+		end = *trip.CompletedAt
+	} else if trip.CancelledAt != nil {
+		end = *trip.CancelledAt
+	}
+	timeline := &models.TripTimeline{
+		TripID:  trip.ID,
+		Status:  trip.Status,
+		Start:   trip.RequestedAt.Add(-timelineWindowMargin).UTC().Truncate(time.Second),
+		End:     end.Add(timelineWindowMargin).UTC().Truncate(time.Second).Add(time.Second),
+		Entries: tripLifecycleEntries(trip),
+	}
+	start, finish := timeline.Start.Format(time.RFC3339), timeline.End.Format(time.RFC3339)
+	tripKey := trip.ID.String()
+
+	traceIDs := make(map[uuid.UUID]bool)
+
+	events, err := s.observability.ListEventLogsByEntity(ctx, tripEntityType, tripKey, timelineScanLimit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load trip events: %w", err)
+	}
+	markTruncated(timeline, models.TimelineSourceEvent, len(events))
+	for _, event := range s.redactor.EventLogs(ctx, events) {
+		if event.TraceID != nil {
+			traceIDs[*event.TraceID] = true
+		}
+		timeline.Entries = append(timeline.Entries, models.TripTimelineEntry{
+			Timestamp: event.Timestamp,
+			HLC:       event.HLC,
+			Source:    models.TimelineSourceEvent,
+			Type:      event.EventType,
+			Summary:   event.Message,
+			TraceID:   event.TraceID,
+			Data:      event,
+		})
+	}
+
+	messages, err := s.observability.GetMessagesByTimeRange(ctx, start, finish, timelineScanLimit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load actor messages: %w", err)
+	}
+	markTruncated(timeline, models.TimelineSourceMessage, len(messages))
+	var tripMessages []*models.ActorMessage
+	for _, message := range messages {
+		if referencesTrip(message.MessagePayload, tripKey) || message.Affects(models.EntityTypeTrip, tripKey) {
+			tripMessages = append(tripMessages, message)
+		}
+	}
+	for _, message := range s.redactor.ActorMessages(ctx, tripMessages) {
+		traceID := message.TraceID
+		traceIDs[traceID] = true
+		timeline.Entries = append(timeline.Entries, models.TripTimelineEntry{
+			Timestamp: message.SentAt,
+			HLC:       message.HLC,
+			Source:    models.TimelineSourceMessage,
+			Type:      message.MessageType,
+			Summary: fmt.Sprintf("%s %s -> %s %s (%s)", message.SenderActorType, message.SenderActorID,
+				message.ReceiverActorType, message.ReceiverActorID, message.Status),
+			TraceID: &traceID,
+			Data:    message,
+		})
+	}
+
+	for _, traceID := range sortedTraceIDs(traceIDs) {
+		spans, err := s.observability.GetTracesByTraceID(ctx, traceID.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load trace %s: %w", traceID, err)
+		}
+		for _, span := range spans {
+			summary := span.OperationName
+			if span.DurationMs != nil {
+				summary = fmt.Sprintf("%s (%dms, %s)", span.OperationName, *span.DurationMs, span.Status)
+			}
+			spanTraceID := span.TraceID
+			timeline.Entries = append(timeline.Entries, models.TripTimelineEntry{
+				Timestamp: span.StartTime,
+				Source:    models.TimelineSourceTrace,
+				Type:      span.OperationName,
+				Summary:   summary,
+				TraceID:   &spanTraceID,
+				Data:      span,
+			})
+		}
+	}
+
+	logs, err := s.traditional.GetTraditionalLogsByTimeRange(ctx, start, finish, timelineScanLimit, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load traditional logs: %w", err)
+	}
+	markTruncated(timeline, models.TimelineSourceLog, len(logs))
+	var tripLogs []*models.TraditionalLog
+	for _, log := range logs {
+		if referencesTrip(log.Fields, tripKey) {
+			tripLogs = append(tripLogs, log)
+		}
+	}
+	for _, log := range s.redactor.TraditionalLogs(ctx, tripLogs) {
+		timeline.Entries = append(timeline.Entries, models.TripTimelineEntry{
+			Timestamp: log.Timestamp,
+			Source:    models.TimelineSourceLog,
+			Type:      string(log.Level),
+			Summary:   fmt.Sprintf("[%s] %s", log.ServiceName, log.Message),
+			Data:      log,
+		})
+	}
+
+	sort.SliceStable(timeline.Entries, func(i, j int) bool {
+		a, b := &timeline.Entries[i], &timeline.Entries[j]
+		aWall, aLogical := a.OrderKey()
+		bWall, bLogical := b.OrderKey()
+		if aWall != bWall {
+			return aWall < bWall
+		}
+		if aLogical != bLogical {
+			return aLogical < bLogical
+		}
+		return timelineSourceOrder[a.Source] < timelineSourceOrder[b.Source]
+	})
+
+	return timeline, nil
+}
+
+// tripEntityType is the event log entity type of trip events
+const tripEntityType = "trip"
+
+// markTruncated records that a source may be incomplete when its scan returned the maximum rows
+func markTruncated(timeline *models.TripTimeline, source string, scanned int) {
+	if scanned >= timelineScanLimit {
+		timeline.Truncated = append(timeline.Truncated, source)
+	}
+}
+
+// tripLifecycleEntries turns the trip's own timestamps into timeline entries
+func tripLifecycleEntries(trip *models.Trip) []models.TripTimelineEntry {
+	entries := []models.TripTimelineEntry{{
+		Timestamp: trip.RequestedAt,
+		Source:    models.TimelineSourceTrip,
+		Type:      string(models.TripStatusRequested),
+		Summary:   "Trip requested",
+	}}
+	steps := []struct {
+		at      *time.Time
+		status  models.TripStatus
+		summary string
+	}{
+		{trip.MatchedAt, models.TripStatusMatched, "Driver matched"},
+		{trip.AcceptedAt, models.TripStatusAccepted, "Driver accepted"},
+		{trip.PickupAt, models.TripStatusInProgress, "Passenger picked up"},
+		{trip.CompletedAt, models.TripStatusCompleted, "Trip completed"},
+		{trip.CancelledAt, models.TripStatusCancelled, "Trip cancelled"},
+	}
+	for _, step := range steps {
+		if step.at == nil {
+			continue
+		}
+		entries = append(entries, models.TripTimelineEntry{
+			Timestamp: *step.at,
+			Source:    models.TimelineSourceTrip,
+			Type:      string(step.status),
+			Summary:   step.summary,
+		})
+	}
+	return entries
+}
+
+// referencesTrip reports whether a JSON object has the trip's ID as its top-level trip_id
+func referencesTrip(raw json.RawMessage, tripID string) bool {
+	if len(raw) == 0 {
+		return false
+	}
+	var fields struct {
+		TripID string `json:"trip_id"`
+	}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return false
+	}
+	return fields.TripID == tripID
+}
+
+// sortedTraceIDs returns the trace IDs of a set in a stable order
+func sortedTraceIDs(set map[uuid.UUID]bool) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(set))
+	for id := range set {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].String() < ids[j].String()
+	})
+	return ids
+}
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/traditional"
+)
+
+var (
+	// ErrMatchingQueueFull is returned when a ride request is rejected because every matching
+	// worker is busy and the queue is full
+	ErrMatchingQueueFull = errors.New("matching queue is full")
+	// ErrMatchingQueueTimeout is returned when a ride request waited too long for a matching worker
+	ErrMatchingQueueTimeout = errors.New("timed out waiting for a matching worker")
+)
+
+// MatchFunc finds and reserves a driver for a ride request, returning nil if none is available
+type MatchFunc func(ctx context.Context) (*models.Driver, error)
+
+// MatchingStats is the state of the matching worker pool
+type MatchingStats struct {
+	Workers    int   `json:"workers"`
+	QueueDepth int   `json:"queue_depth"` // requests waiting for a worker
+	Matched    int64 `json:"matched"`     // requests a worker ran, with or without a driver found
+	Rejected   int64 `json:"rejected"`    // requests turned away because the queue was full
+	TimedOut   int64 `json:"timed_out"`   // requests given up on while waiting for a worker
+}
+
+// States of a match job; a job only moves out of queued or running once
+const (
+	matchJobQueued int32 = iota
+	matchJobRunning
+	matchJobDone
+	matchJobAbandoned // the requester stopped waiting
+)
+
+// matchJob is a ride request waiting for a matching worker
+type matchJob struct {
+	ctx      context.Context
+	match    MatchFunc
+	queuedAt time.Time
+	result   chan matchResult
+	state    atomic.Int32
+}
+
+type matchResult struct {
+	driver *models.Driver
+	err    error
+}
+
+// TraditionalMatcher matches ride requests in traditional mode on a bounded pool of workers, the
+// way the actor model hands matching to matching actors instead of doing it in the request. A
+// request waits in a bounded queue for a worker; when the queue is full or the wait too long it
+// fails fast, so latency degrades gracefully under load instead of every request scanning the
+// drivers at once. The wait of every request is recorded in the matching queue wait histogram.
+type TraditionalMatcher struct {
+	workers  int
+	timeout  time.Duration
+	queue    chan *matchJob
+	drivers  repository.DriverRepository
+	monitor  *traditional.TraditionalMonitor
+	matched  atomic.Int64
+	rejected atomic.Int64
+	timedOut atomic.Int64
+	logger   *logging.Logger
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+// NewTraditionalMatcher creates a new matcher. Drivers reserved for requests whose requester
+// stopped waiting are released in drivers.
+func NewTraditionalMatcher(cfg config.MatchingConfig, drivers repository.DriverRepository, monitor *traditional.TraditionalMonitor, logger *logging.Logger) *TraditionalMatcher {
+	return &TraditionalMatcher{
+		workers: cfg.Workers,
+		timeout: cfg.QueueTimeout,
+		queue:   make(chan *matchJob, cfg.QueueSize),
+		drivers: drivers,
+		monitor: monitor,
+		logger:  logger.WithComponent("traditional_matcher"),
+	}
+}
+
+// Start starts the matching workers
+func (m *TraditionalMatcher) Start(ctx context.Context) error {
+	m.ctx, m.cancel = context.WithCancel(ctx)
+
+	for i := 0; i < m.workers; i++ {
+		m.wg.Add(1)
+		go m.worker()
+	}
+
+	m.logger.WithFields(logging.Fields{
+		"workers":    m.workers,
+		"queue_size": cap(m.queue),
+	}).Info("Traditional matcher started")
+	return nil
+}
+
+// Stop stops the workers once the requests being matched are done. Requests still queued are
+// left to time out.
+func (m *TraditionalMatcher) Stop() error {
+	if m.cancel != nil {
+		m.cancel()
+	}
+	m.wg.Wait()
+
+	m.logger.WithField("queued", len(m.queue)).Info("Traditional matcher stopped")
+	return nil
+}
+
+// Stats returns the state of the worker pool
+func (m *TraditionalMatcher) Stats() MatchingStats {
+	return MatchingStats{
+		Workers:    m.workers,
+		QueueDepth: len(m.queue),
+		Matched:    m.matched.Load(),
+		Rejected:   m.rejected.Load(),
+		TimedOut:   m.timedOut.Load(),
+	}
+}
+
+// Match runs match on a worker and waits for its driver. It returns ErrMatchingQueueFull at once
+// when the queue is full, and ErrMatchingQueueTimeout when no worker took the request in time;
+// once a worker has it, the request waits for the match or ctx. A driver reserved after the
+// requester stopped waiting is released.
+func (m *TraditionalMatcher) Match(ctx context.Context, match MatchFunc) (*models.Driver, error) {
+	job := &matchJob{
+		ctx:      ctx,
+		match:    match,
+		queuedAt: time.Now(),
+		result:   make(chan matchResult, 1),
+	}
+
+	select {
+	case m.queue <- job:
+	default:
+		m.rejected.Add(1)
+		m.recordOutcome("queue_full")
+		return nil, ErrMatchingQueueFull
+	}
+
+	timer := time.NewTimer(m.timeout)
+	defer timer.Stop()
+	timeout := timer.C
+	for {
+		select {
+		case result := <-job.result:
+			return result.driver, result.err
+		case <-timeout:
+			if job.state.CompareAndSwap(matchJobQueued, matchJobAbandoned) {
+				m.timedOut.Add(1)
+				m.recordOutcome("timeout")
+				return nil, ErrMatchingQueueTimeout
+			}
+			// A worker took the request in time
+			timeout = nil
+		case <-ctx.Done():
+			if job.state.CompareAndSwap(matchJobQueued, matchJobAbandoned) ||
+				job.state.CompareAndSwap(matchJobRunning, matchJobAbandoned) {
+				return nil, ctx.Err()
+			}
+			// The worker just finished, so its driver is returned after all
+			result := <-job.result
+			return result.driver, result.err
+		}
+	}
+}
+
+// worker matches queued requests until the matcher is stopped
+func (m *TraditionalMatcher) worker() {
+	defer m.wg.Done()
+
+	for {
+		select {
+		case job := <-m.queue:
+			m.run(job)
+		case <-m.ctx.Done():
+			return
+		}
+	}
+}
+
+// run matches a request, unless its requester already stopped waiting
+func (m *TraditionalMatcher) run(job *matchJob) {
+	if !job.state.CompareAndSwap(matchJobQueued, matchJobRunning) {
+		return
+	}
+	m.monitor.RecordMatchingQueueWait(time.Since(job.queuedAt))
+
+	driver, err := job.match(job.ctx)
+	m.matched.Add(1)
+	if job.state.CompareAndSwap(matchJobRunning, matchJobDone) {
+		job.result <- matchResult{driver: driver, err: err}
+		outcome := "matched"
+		if err != nil {
+			outcome = "error"
+		} else if driver == nil {
+			outcome = "no_driver"
+		}
+		m.recordOutcome(outcome)
+		return
+	}
+
+	// The requester stopped waiting while the request was matched
+	m.recordOutcome("abandoned")
+	if driver == nil {
+		return
+	}
+	if err := m.drivers.Release(context.WithoutCancel(job.ctx), driver.ID.String()); err != nil {
+		m.logger.WithError(err).WithField("driver_id", driver.ID).Error("Failed to release driver of abandoned request")
+	}
+}
+
+// recordOutcome counts a ride request by how matching it ended
+func (m *TraditionalMatcher) recordOutcome(outcome string) {
+	m.monitor.RecordBusinessMetrics("matching_requests_total", 1, map[string]string{
+		"outcome": outcome,
+	})
+}
@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// TripCompletionService completes trips with the details their driver entered: the actual
+// dropoff, the odometer distance, tolls and extras. The details are reconciled against the
+// requested route to compute the final fare, and completions deviating too much from it are
+// flagged for review with a warning event log.
+type TripCompletionService struct {
+	trips        repository.TripRepository
+	participants tripParticipants
+	cfg          config.FareConfig
+	tripEvents   TripEventPublisher
+	events       bus.Publisher
+	latency      LatencyRecorder
+	logger       *logging.Logger
+	now          func() time.Time
+}
+
+// NewTripCompletionService creates a new trip completion service. Trip status transitions are
+// sent to tripEvents, e.g. webhooks, and published on events with the flagged completions;
+// both may be nil.
+func NewTripCompletionService(
+	trips repository.TripRepository,
+	drivers repository.DriverRepository,
+	passengers repository.PassengerRepository,
+	cfg config.FareConfig,
+	tripEvents TripEventPublisher,
+	events bus.Publisher,
+	logger *logging.Logger,
+) *TripCompletionService {
+	return &TripCompletionService{
+		trips:        trips,
+		participants: tripParticipants{trips: trips, drivers: drivers, passengers: passengers},
+		cfg:          cfg,
+		tripEvents:   tripEvents,
+		events:       events,
+		logger:       logger.WithComponent("trip_completion_service"),
+		now:          time.Now,
+	}
+}
+
+// SetLatencyRecorder sets where the durations of completed trips are recorded
+func (s *TripCompletionService) SetLatencyRecorder(latency LatencyRecorder) {
+	s.latency = latency
+}
+
+// Complete completes an active trip on behalf of its driver and frees the driver
+func (s *TripCompletionService) Complete(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType, details models.TripCompletionDetails) (*models.TripCompletion, error) {
+	if userType != models.UserTypeDriver {
+		return nil, models.ErrUnauthorizedOperation
+	}
+	trip, err := s.participants.authorize(ctx, tripID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	if !trip.IsActive() || !trip.HasDriver() {
+		return nil, models.ErrTripNotCompletable
+	}
+
+	now := s.now()
+	completion := &models.TripCompletion{
+		TripID:             trip.ID,
+		DriverID:           *trip.DriverID,
+		DropoffLatitude:    details.Dropoff.Latitude,
+		DropoffLongitude:   details.Dropoff.Longitude,
+		OdometerDistanceKm: details.OdometerDistanceKm,
+		TollsAmount:        details.TollsAmount,
+		ExtrasAmount:       details.ExtrasAmount,
+		CreatedAt:          now,
+	}
+	if err := completion.Validate(); err != nil {
+		return nil, &models.ValidationError{
+			Field:   "completion",
+			Message: err.Error(),
+		}
+	}
+
+	// Passengers who made the driver wait at the pickup pay for it with the fare
+	var notFound *models.NotFoundError
+	wait, err := s.trips.GetPickupWait(ctx, tripID)
+	switch {
+	case err == nil:
+		completion.WaitFee = wait.WaitFee
+	case !errors.As(err, &notFound):
+		return nil, err
+	}
+	s.reconcile(trip, completion)
+
+	from := trip.Status
+	duration := now.Sub(tripStartedAt(trip))
+	durationMinutes := int(math.Round(duration.Minutes()))
+	trip.Status = models.TripStatusCompleted
+	trip.FareAmount = &completion.FinalFare
+	trip.DistanceKm = &completion.BilledDistanceKm
+	trip.DurationMinutes = &durationMinutes
+	trip.CompletedAt = &now
+	trip.UpdatedAt = now
+
+	if err := s.trips.Complete(ctx, trip, from, completion); err != nil {
+		return nil, err
+	}
+
+	// The trip is completed either way, so failing to free the driver is only logged
+	if err := s.participants.freeDriver(ctx, trip); err != nil {
+		s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Warn("Failed to free driver after trip completion")
+	}
+	if s.latency != nil {
+		s.latency.RecordTripDuration(duration)
+	}
+	s.publishTrip(ctx, trip)
+	if completion.Flagged {
+		s.publishFlagged(trip, completion)
+	}
+	return completion, nil
+}
+
+// GetCompletion returns the completion details of a trip
+func (s *TripCompletionService) GetCompletion(ctx context.Context, tripID string) (*models.TripCompletion, error) {
+	return s.trips.GetCompletion(ctx, tripID)
+}
+
+// ListCompletions returns the matching completions, oldest first, and the total number of matches
+func (s *TripCompletionService) ListCompletions(ctx context.Context, filter models.TripCompletionFilter) ([]*models.TripCompletion, int64, error) {
+	return s.trips.ListCompletions(ctx, filter)
+}
+
+// reconcile compares the completion against the trip's requested route, computes the final
+// fare and flags the deviations above the configured limits
+func (s *TripCompletionService) reconcile(trip *models.Trip, completion *models.TripCompletion) {
+	pickup := models.Location{Latitude: trip.PickupLatitude, Longitude: trip.PickupLongitude}
+	destination := models.Location{Latitude: trip.DestinationLatitude, Longitude: trip.DestinationLongitude}
+	dropoff := models.Location{Latitude: completion.DropoffLatitude, Longitude: completion.DropoffLongitude}
+	plannedKm := pickup.DistanceTo(destination)
+	routeKm := pickup.DistanceTo(dropoff)
+	deviationKm := destination.DistanceTo(dropoff)
+
+	completion.RouteDistanceKm = roundDistance(routeKm)
+	completion.DropoffDeviationKm = roundDistance(deviationKm)
+	completion.EstimatedFare = roundFare(s.cfg.BaseFare + s.cfg.PerKm*plannedKm)
+
+	var reasons []string
+	odometerKm := completion.OdometerDistanceKm
+
+	// No road is shorter than the straight line, so that much is always billed
+	billedKm := math.Max(odometerKm, routeKm)
+	if odometerKm < completion.RouteDistanceKm {
+		reasons = append(reasons, fmt.Sprintf("odometer distance %.2f km is shorter than the %.2f km straight line to the dropoff", odometerKm, completion.RouteDistanceKm))
+	} else if odometerKm > routeKm*s.cfg.MaxDetourRatio {
+		reasons = append(reasons, fmt.Sprintf("odometer distance %.2f km exceeds %.1f times the %.2f km straight line to the dropoff", odometerKm, s.cfg.MaxDetourRatio, completion.RouteDistanceKm))
+	}
+	if deviationKm > s.cfg.MaxDropoffDeviationKm {
+		reasons = append(reasons, fmt.Sprintf("dropoff is %.2f km from the requested destination", completion.DropoffDeviationKm))
+	}
+
+	// The wait fee is charged by the service itself, so only the driver's entries are reviewed
+	completion.BilledDistanceKm = roundDistance(billedKm)
+	fare := roundFare(s.cfg.BaseFare + s.cfg.PerKm*billedKm + completion.TollsAmount + completion.ExtrasAmount)
+	if deviation := math.Abs(fare - completion.EstimatedFare); deviation > completion.EstimatedFare*s.cfg.MaxFareDeviation {
+		reasons = append(reasons, fmt.Sprintf("fare %.2f deviates from the %.2f estimate by %.0f%%", fare, completion.EstimatedFare, 100*deviation/completion.EstimatedFare))
+	}
+	completion.FinalFare = roundFare(fare + completion.WaitFee)
+
+	if len(reasons) > 0 {
+		reason := strings.Join(reasons, "; ")
+		completion.Flagged = true
+		completion.FlagReason = &reason
+	}
+}
+
+// publishTrip publishes the transition into completed for the live trip status streams and
+// the trip event subscribers
+func (s *TripCompletionService) publishTrip(ctx context.Context, trip *models.Trip) {
+	if s.events != nil {
+		snapshot := *trip
+		s.events.Publish(bus.TopicTripStatus, &snapshot)
+	}
+	if s.tripEvents == nil {
+		return
+	}
+	if err := s.tripEvents.PublishTripEvent(context.WithoutCancel(ctx), models.WebhookEventTripCompleted, trip); err != nil {
+		s.logger.WithError(err).WithField("trip_id", trip.ID.String()).Error("Failed to publish trip completed event")
+	}
+}
+
+// publishFlagged logs a completion flagged for review and publishes it as a warning event log
+func (s *TripCompletionService) publishFlagged(trip *models.Trip, completion *models.TripCompletion) {
+	s.logger.WithFields(logging.Fields{
+		"trip_id":     trip.ID.String(),
+		"driver_id":   completion.DriverID.String(),
+		"final_fare":  completion.FinalFare,
+		"flag_reason": *completion.FlagReason,
+	}).Warn("Trip completion flagged for review")
+
+	if s.events == nil {
+		return
+	}
+
+	entityType, entityID := models.EntityTypeTrip, trip.ID
+	eventLog := &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     "trip_completion_flagged",
+		EventCategory: models.EventCategoryBusiness,
+		EntityType:    &entityType,
+		EntityID:      &entityID,
+		Severity:      models.EventSeverityWarn,
+		Message:       "Trip completion flagged for review",
+		Timestamp:     completion.CreatedAt,
+		CreatedAt:     completion.CreatedAt,
+	}
+	eventLog.EventData, _ = json.Marshal(completion)
+	s.events.Publish(bus.TopicEventLog, eventLog)
+}
+
+// tripStartedAt returns when the passenger was picked up, or the latest earlier milestone when
+// the pickup was not recorded
+func tripStartedAt(trip *models.Trip) time.Time {
+	for _, at := range []*time.Time{trip.PickupAt, trip.AcceptedAt, trip.MatchedAt} {
+		if at != nil {
+			return *at
+		}
+	}
+	return trip.RequestedAt
+}
+
+// roundDistance rounds a distance to 10 meters, the precision distances are stored with
+func roundDistance(km float64) float64 {
+	return math.Round(km*100) / 100
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// Modes RideService runs in, the mode label of the trip events it emits
+const (
+	TripEventModeActor       = "actor_model"
+	TripEventModeTraditional = "traditional"
+)
+
+// TripStateChangeEvent is the event type of trip state changes in the event logs
+const TripStateChangeEvent = "trip_state_changed"
+
+// TripStateChange is a trip's transition between two statuses
+type TripStateChange struct {
+	Trip *models.Trip
+	From models.TripStatus // empty when the trip was just requested
+	Mode string
+	At   time.Time
+
+	Cancellation *models.CancellationQuote // what the passenger is charged, for cancellations
+}
+
+// TripEventRecorder records events as event logs; observability.MetricsCollector satisfies it
+type TripEventRecorder interface {
+	RecordEvent(eventType, source, description string, metadata map[string]interface{})
+}
+
+// TripLogRecorder stores traditional logs; repository.TraditionalRepository satisfies it
+type TripLogRecorder interface {
+	CreateTraditionalLog(ctx context.Context, log *models.TraditionalLog) error
+}
+
+// TripEventEmitter emits every trip state change to the actor pipeline's event logs, the
+// traditional pipeline's logs and the trip_state_changes_total metric, labelled by the mode
+// RideService runs in. Both pipelines record the same changes whichever mode is compared, so
+// neither is favoured by writing more. Any recorder may be nil.
+type TripEventEmitter struct {
+	events   TripEventRecorder
+	logs     TripLogRecorder
+	metrics  BusinessMetricsRecorder
+	service  string
+	instance string
+	logger   *logging.Logger
+}
+
+// NewTripEventEmitter creates a new TripEventEmitter instance
+func NewTripEventEmitter(events TripEventRecorder, logs TripLogRecorder, metrics BusinessMetricsRecorder, serviceName string, logger *logging.Logger) *TripEventEmitter {
+	instance, _ := os.Hostname()
+	return &TripEventEmitter{
+		events:   events,
+		logs:     logs,
+		metrics:  metrics,
+		service:  serviceName,
+		instance: instance,
+		logger:   logger.WithComponent("trip_event_emitter"),
+	}
+}
+
+// Emit records a trip state change in every pipeline. Failing to store the traditional log is
+// logged, not returned, so the change itself stands.
+func (e *TripEventEmitter) Emit(ctx context.Context, change TripStateChange) {
+	trip := change.Trip
+	fields := map[string]interface{}{
+		"trip_id":      trip.ID.String(),
+		"passenger_id": trip.PassengerID.String(),
+		"from_status":  fromStatusLabel(change.From),
+		"to_status":    string(trip.Status),
+		"mode":         change.Mode,
+	}
+	if trip.DriverID != nil {
+		fields["driver_id"] = trip.DriverID.String()
+	}
+	if change.Cancellation != nil {
+		for k, v := range change.Cancellation.EventFields() {
+			fields[k] = v
+		}
+	}
+	message := fmt.Sprintf("Trip status changed from %s to %s", fromStatusLabel(change.From), trip.Status)
+
+	if e.events != nil {
+		e.events.RecordEvent(TripStateChangeEvent, "ride_service", message, fields)
+	}
+
+	if e.logs != nil {
+		fieldsJSON, _ := json.Marshal(fields)
+		log := &models.TraditionalLog{
+			ID:          uuid.New(),
+			Level:       models.LogLevelInfo,
+			Message:     message,
+			ServiceName: e.service,
+			InstanceID:  e.instance,
+			Fields:      fieldsJSON,
+			Timestamp:   change.At,
+			CreatedAt:   time.Now(),
+		}
+		if err := e.logs.CreateTraditionalLog(context.WithoutCancel(ctx), log); err != nil {
+			e.logger.WithError(err).WithField("trip_id", trip.ID).Warn("Failed to store trip state change log")
+		}
+	}
+
+	if e.metrics != nil {
+		e.metrics.RecordBusinessMetrics("trip_state_changes_total", 1, map[string]string{
+			"mode":        change.Mode,
+			"from_status": fromStatusLabel(change.From),
+			"to_status":   string(trip.Status),
+		})
+	}
+}
+
+// fromStatusLabel is the status a trip changed from, "none" for a new trip
+func fromStatusLabel(status models.TripStatus) string {
+	if status == "" {
+		return "none"
+	}
+	return string(status)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// tripParticipants resolves whether a user is the passenger or the driver of a trip
+type tripParticipants struct {
+	trips      repository.TripRepository
+	drivers    repository.DriverRepository
+	passengers repository.PassengerRepository
+}
+
+// authorize returns the trip, or ErrUnauthorizedOperation when the user is neither its passenger
+// nor its driver. A participant's role in the trip is their user type.
+func (p tripParticipants) authorize(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType) (*models.Trip, error) {
+	trip, err := p.trips.GetByID(ctx, tripID)
+	if err != nil {
+		return nil, err
+	}
+
+	var notFound *models.NotFoundError
+	switch userType {
+	case models.UserTypePassenger:
+		passenger, err := p.passengers.GetByUserID(ctx, userID.String())
+		if err != nil {
+			if errors.As(err, &notFound) {
+				return nil, models.ErrUnauthorizedOperation
+			}
+			return nil, err
+		}
+		if passenger.ID == trip.PassengerID {
+			return trip, nil
+		}
+	case models.UserTypeDriver:
+		driver, err := p.drivers.GetByUserID(ctx, userID.String())
+		if err != nil {
+			if errors.As(err, &notFound) {
+				return nil, models.ErrUnauthorizedOperation
+			}
+			return nil, err
+		}
+		if trip.DriverID != nil && driver.ID == *trip.DriverID {
+			return trip, nil
+		}
+	}
+
+	return nil, models.ErrUnauthorizedOperation
+}
+
+// freeDriver puts the driver of a trip that ended back online
+func (p tripParticipants) freeDriver(ctx context.Context, trip *models.Trip) error {
+	driver, err := p.drivers.GetByID(ctx, trip.DriverID.String())
+	if err != nil {
+		return err
+	}
+	if driver.Status != models.DriverStatusBusy {
+		return nil
+	}
+	driver.Status = models.DriverStatusOnline
+	return p.drivers.Update(ctx, driver)
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// CancelByDriver cancels a trip on behalf of its matched driver before pickup and matches the
+// trip again with another driver, leaving out every driver who cancelled it. Matching runs
+// synchronously in traditional mode and in the background in actor mode. A trip already matched
+// again as often as configured, or for which no other driver is available, is cancelled instead.
+// The passenger follows the trip going back to requested, and then matched or cancelled, through
+// its status events and, in actor mode, their passenger actor.
+func (rs *RideService) CancelByDriver(ctx context.Context, tripID string, userID uuid.UUID, userType models.UserType, note string) (*models.TripRematch, error) {
+	if userType != models.UserTypeDriver {
+		return nil, models.ErrUnauthorizedOperation
+	}
+	if len([]rune(note)) > models.MaxCancellationNoteLength {
+		return nil, &models.ValidationError{
+			Field:   "note",
+			Message: fmt.Sprintf("note must be at most %d characters", models.MaxCancellationNoteLength),
+		}
+	}
+
+	participants := tripParticipants{trips: rs.tripRepo, drivers: rs.driverRepo, passengers: rs.passengerRepo}
+	trip, err := participants.authorize(ctx, tripID, userID, userType)
+	if err != nil {
+		return nil, err
+	}
+	if trip.Status != models.TripStatusMatched && trip.Status != models.TripStatusAccepted {
+		return nil, models.ErrTripNotDriverCancellable
+	}
+
+	rematches, err := rs.tripRepo.ListRematches(ctx, tripID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trip re-matches: %w", err)
+	}
+
+	now := time.Now()
+	driverID := *trip.DriverID
+	rematch := &models.TripRematch{
+		ID:                uuid.New(),
+		TripID:            trip.ID,
+		Attempt:           len(rematches) + 1,
+		CancelledDriverID: driverID,
+		CancelledAt:       now,
+		Mode:              rs.mode(),
+		Outcome:           models.TripRematchMatching,
+	}
+	if note != "" {
+		rematch.Note = &note
+	}
+
+	from := trip.Status
+	limitReached := len(rematches) >= rs.rematch.MaxRematches
+	if limitReached {
+		rematch.End(models.TripRematchLimitReached, nil, now)
+		trip.Status = models.TripStatusCancelled
+		trip.CancelledAt = &now
+		trip.RecordCancellation(models.TripCancellation{Reason: models.CancellationReasonDriverCancelled, Note: note}, rs.mode())
+	} else {
+		trip.Status = models.TripStatusRequested
+	}
+	trip.DriverID = nil
+	trip.MatchedAt = nil
+	trip.AcceptedAt = nil
+	trip.UpdatedAt = now
+	if err := rs.tripRepo.RecordDriverCancellation(ctx, trip, from, rematch); err != nil {
+		return nil, err
+	}
+
+	if err := rs.driverRepo.Release(ctx, driverID.String()); err != nil {
+		rs.logger.WithError(err).WithField("driver_id", driverID).Error("Failed to free driver who cancelled")
+	}
+	rs.publishTripEvent(ctx, trip, from)
+	rs.notifyPassengerCancelled(trip, now)
+
+	rs.logger.WithFields(logging.Fields{
+		"trip_id":   trip.ID,
+		"driver_id": driverID,
+		"attempt":   rematch.Attempt,
+		"method":    rs.mode(),
+	}).Info("Driver cancelled trip")
+
+	if limitReached {
+		rs.recordRematch(rematch)
+		return rematch, nil
+	}
+
+	excluded := models.ExcludedDrivers(append(rematches, rematch))
+	if rs.useActorModel {
+		snapshot, pending := *trip, *rematch
+		go rs.rematchTrip(context.WithoutCancel(ctx), &snapshot, &pending, excluded)
+		return rematch, nil
+	}
+	rs.rematchTrip(ctx, trip, rematch, excluded)
+	return rematch, nil
+}
+
+// ListRematches retrieves the times a trip's driver cancelled and the trip was matched again
+func (rs *RideService) ListRematches(ctx context.Context, tripID string) ([]*models.TripRematch, error) {
+	if _, err := rs.tripRepo.GetByID(ctx, tripID); err != nil {
+		return nil, err
+	}
+	return rs.tripRepo.ListRematches(ctx, tripID)
+}
+
+// rematchTrip matches a trip its driver cancelled with another driver, never one of excluded,
+// cancelling the trip when none is available. Requirements given with the original request are
+// not stored, so the trip is matched again with the ride preferences of the passenger's profile.
+func (rs *RideService) rematchTrip(ctx context.Context, trip *models.Trip, rematch *models.TripRematch, excluded map[uuid.UUID]bool) {
+	logger := rs.logger.WithFields(logging.Fields{
+		"trip_id": trip.ID,
+		"attempt": rematch.Attempt,
+	})
+
+	start := time.Now()
+	driver, err := rs.reserveRematchDriver(ctx, trip, excluded)
+	if !rs.useActorModel {
+		rs.traditionalMonitor.RecordDatabaseOperation("SELECT", "drivers", time.Since(start), err == nil)
+	}
+	if err != nil {
+		logger.WithError(err).Error("Failed to find a driver to re-match the trip with")
+	}
+
+	// The passenger may have cancelled the trip while it was being matched again
+	current, err := rs.tripRepo.GetByID(ctx, trip.ID.String())
+	if err != nil || current.Status != models.TripStatusRequested {
+		if driver != nil {
+			rs.releaseDriver(ctx, driver)
+		}
+		rs.endRematch(ctx, rematch, models.TripRematchUnmatched, nil)
+		return
+	}
+
+	if driver == nil {
+		if !rs.endRematch(ctx, rematch, models.TripRematchUnmatched, nil) {
+			return
+		}
+		from := trip.Status
+		trip.Status = models.TripStatusCancelled
+		trip.CancelledAt = rematch.EndedAt
+		trip.RecordCancellation(models.TripCancellation{Reason: models.CancellationReasonDriverCancelled}, rs.mode())
+		trip.UpdatedAt = *rematch.EndedAt
+		if err := rs.tripRepo.Update(ctx, trip); err != nil {
+			logger.WithError(err).Error("Failed to cancel trip left without a driver")
+			return
+		}
+		rs.publishTripEvent(ctx, trip, from)
+		rs.notifyPassengerCancelled(trip, *rematch.EndedAt)
+		logger.Warn("No other driver found, trip cancelled")
+		return
+	}
+
+	if !rs.endRematch(ctx, rematch, models.TripRematchMatched, &driver.ID) {
+		rs.releaseDriver(ctx, driver)
+		return
+	}
+	trip.DriverID = &driver.ID
+	trip.Status = models.TripStatusMatched
+	trip.MatchedAt = rematch.EndedAt
+	trip.UpdatedAt = *rematch.EndedAt
+	if err := rs.tripRepo.Update(ctx, trip); err != nil {
+		logger.WithError(err).Error("Failed to assign re-matched driver")
+		rs.releaseDriver(ctx, driver)
+		return
+	}
+	rs.publishTripEvent(ctx, trip, models.TripStatusRequested)
+	rs.notifyPassengerMatched(trip, driver)
+
+	logger.WithFields(logging.Fields{
+		"driver_id":          driver.ID,
+		"added_wait_seconds": *rematch.AddedWaitSeconds,
+	}).Info("Trip matched again")
+}
+
+// reserveRematchDriver reserves the best driver near a trip's pickup who is not excluded and
+// meets the passenger's ride preferences. It returns nil if there is none.
+func (rs *RideService) reserveRematchDriver(ctx context.Context, trip *models.Trip, excluded map[uuid.UUID]bool) (*models.Driver, error) {
+	passenger, err := rs.passengerRepo.GetByID(ctx, trip.PassengerID.String())
+	if err != nil {
+		return nil, fmt.Errorf("passenger not found: %w", err)
+	}
+
+	pickup := models.Location{Latitude: trip.PickupLatitude, Longitude: trip.PickupLongitude}
+	dropoff := models.Location{Latitude: trip.DestinationLatitude, Longitude: trip.DestinationLongitude}
+	drivers, err := rs.findNearbyDrivers(ctx, pickup, 5.0)
+	if err != nil {
+		return nil, err
+	}
+	var eligible []*models.Driver
+	for _, driver := range drivers {
+		if !excluded[driver.ID] {
+			eligible = append(eligible, driver)
+		}
+	}
+	eligible, err = rs.filterByDestination(ctx, eligible, pickup, dropoff)
+	if err != nil {
+		return nil, err
+	}
+	eligible = rs.applyRequirements(eligible, pickup, newRideRequirements(passenger.RidePreferences, nil), rs.mode())
+	if len(eligible) == 0 {
+		return nil, nil
+	}
+	return rs.reserveBestDriver(ctx, eligible, pickup, rs.mode())
+}
+
+// endRematch ends a re-match still matching with outcome and records it, returning false if it
+// had already ended
+func (rs *RideService) endRematch(ctx context.Context, rematch *models.TripRematch, outcome models.TripRematchOutcome, matchedDriverID *uuid.UUID) bool {
+	rematch.End(outcome, matchedDriverID, time.Now())
+	if err := rs.tripRepo.EndRematch(ctx, rematch); err != nil {
+		if !errors.Is(err, models.ErrTripStatusConflict) {
+			rs.logger.WithError(err).WithField("trip_id", rematch.TripID).Error("Failed to end trip re-match")
+		}
+		return false
+	}
+	rs.recordRematch(rematch)
+	return true
+}
+
+// recordRematch counts an ended re-match by mode and outcome, with the wait it added to the trip
+func (rs *RideService) recordRematch(rematch *models.TripRematch) {
+	tags := map[string]string{
+		"mode":    rs.mode(),
+		"outcome": string(rematch.Outcome),
+	}
+	rs.traditionalMonitor.RecordBusinessMetrics("trip_rematches_total", 1, tags)
+	rs.traditionalMonitor.RecordBusinessMetrics("trip_rematch_added_wait_seconds", float64(*rematch.AddedWaitSeconds), tags)
+
+	if rs.useActorModel {
+		rs.metricsCollector.RecordEvent("trip_rematch", "ride_service", "Trip re-match ended", map[string]interface{}{
+			"trip_id":            rematch.TripID.String(),
+			"attempt":            rematch.Attempt,
+			"outcome":            string(rematch.Outcome),
+			"added_wait_seconds": *rematch.AddedWaitSeconds,
+			"method":             "actor_model",
+		})
+	}
+}
+
+// notifyPassengerCancelled tells the passenger actor, in actor mode, that the trip's driver
+// cancelled
+func (rs *RideService) notifyPassengerCancelled(trip *models.Trip, at time.Time) {
+	if !rs.useActorModel {
+		return
+	}
+	payload := actor.RideCancelledPayload{
+		TripID:      trip.ID.String(),
+		Reason:      string(models.CancellationReasonDriverCancelled),
+		CancelledBy: string(models.UserTypeDriver),
+		CancelledAt: at,
+	}
+	message := actor.NewBaseMessage(actor.MsgTypeRideCancelled, payload, "ride-service").
+		WithEntity(models.EntityTypeTrip, trip.ID.String())
+	passengerActorID := fmt.Sprintf("passenger-%s", trip.PassengerID.String())
+	if err := rs.actorSystem.SendMessage(passengerActorID, message); err != nil {
+		rs.logger.WithError(err).Warn("Failed to notify passenger actor of driver cancellation")
+	}
+	rs.metricsCollector.RecordActorMessage(passengerActorID, message)
+}
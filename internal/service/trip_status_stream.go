@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// tripUpdateBuffer is the number of updates queued for a subscriber before newer ones are
+	// dropped for it, so a slow client never blocks the event bus
+	tripUpdateBuffer = 16
+	// etaAverageSpeedKmh is the speed ETAs assume over the straight-line distance left, unless
+	// the stream is given an ETA model
+	etaAverageSpeedKmh = 30.0
+)
+
+// TripStatusStream pushes the status transitions of trips and the ETA of their drivers to live
+// subscribers, from the trip_status and driver_location topics of the event bus. Only trips
+// with subscribers are tracked.
+type TripStatusStream struct {
+	trips   repository.TripRepository
+	drivers repository.DriverRepository
+	eta     *ETAModel
+	now     func() time.Time
+
+	watches map[string]*tripWatch // by trip ID
+	mu      sync.Mutex
+}
+
+// tripWatch is the latest known state of a trip with subscribers
+type tripWatch struct {
+	trip        *models.Trip
+	subscribers map[chan *models.TripUpdate]struct{}
+}
+
+// NewTripStatusStream creates a trip status stream without subscribers
+func NewTripStatusStream(trips repository.TripRepository, drivers repository.DriverRepository) *TripStatusStream {
+	return &TripStatusStream{
+		trips:   trips,
+		drivers: drivers,
+		eta:     NewETAModel(etaAverageSpeedKmh),
+		now:     time.Now,
+		watches: make(map[string]*tripWatch),
+	}
+}
+
+// SetETAModel estimates the ETAs pushed to subscribers with model, shared with the ETA accuracy
+// feedback loop adjusting it
+func (s *TripStatusStream) SetETAModel(model *ETAModel) {
+	s.eta = model
+}
+
+// Topics returns the event bus topics the stream handles
+func (s *TripStatusStream) Topics() []string {
+	return []string{bus.TopicTripStatus, bus.TopicDriverLocation}
+}
+
+// Subscribe returns the current state of a trip, its status and, when its driver is on the way,
+// the driver's ETA, followed by a channel receiving the trip's updates and a function that ends
+// the subscription and closes the channel
+func (s *TripStatusStream) Subscribe(ctx context.Context, tripID string) ([]*models.TripUpdate, <-chan *models.TripUpdate, func(), error) {
+	updates := make(chan *models.TripUpdate, tripUpdateBuffer)
+	s.mu.Lock()
+	watch, ok := s.watches[tripID]
+	if !ok {
+		watch = &tripWatch{subscribers: make(map[chan *models.TripUpdate]struct{})}
+		s.watches[tripID] = watch
+	}
+	watch.subscribers[updates] = struct{}{}
+	s.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			s.mu.Lock()
+			delete(watch.subscribers, updates)
+			if len(watch.subscribers) == 0 {
+				delete(s.watches, tripID)
+			}
+			s.mu.Unlock()
+			close(updates)
+		})
+	}
+
+	// Subscribing before loading the trip means no transition is missed in between; one
+	// published meanwhile is sent again on the channel, which clients tolerate
+	trip, err := s.trips.GetByID(ctx, tripID)
+	if err != nil {
+		unsubscribe()
+		return nil, nil, nil, err
+	}
+	s.mu.Lock()
+	if watch.trip == nil || trip.UpdatedAt.After(watch.trip.UpdatedAt) {
+		watch.trip = trip
+	}
+	trip = watch.trip
+	s.mu.Unlock()
+
+	now := s.now()
+	current := []*models.TripUpdate{statusUpdate(trip, now)}
+	if trip.DriverID != nil {
+		driver, err := s.drivers.GetByID(ctx, trip.DriverID.String())
+		if err == nil && driver.CurrentLatitude != nil && driver.CurrentLongitude != nil {
+			if eta, ok := etaUpdate(trip, *driver.CurrentLatitude, *driver.CurrentLongitude, now, s.eta); ok {
+				current = append(current, eta)
+			}
+		}
+	}
+
+	return current, updates, unsubscribe, nil
+}
+
+// HandleMessage pushes the trip transitions and driver locations delivered by the event bus to
+// the subscribers of the trips concerned
+func (s *TripStatusStream) HandleMessage(msg bus.Message) {
+	switch payload := msg.Payload.(type) {
+	case *models.Trip:
+		s.publishStatus(payload, msg.PublishedAt)
+	case *models.DriverLocationUpdate:
+		s.publishLocation(payload)
+	}
+}
+
+// Refresh reloads a trip with subscribers, sending its status to them if it changed since the
+// last one they got. Changes made through the API reach the stream on the event bus first, so
+// this only sends those made elsewhere, by manual SQL fixes or other services.
+func (s *TripStatusStream) Refresh(ctx context.Context, tripID string) error {
+	s.mu.Lock()
+	_, watched := s.watches[tripID]
+	s.mu.Unlock()
+	if !watched {
+		return nil
+	}
+
+	trip, err := s.trips.GetByID(ctx, tripID)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	watch, ok := s.watches[tripID]
+	if !ok {
+		return nil
+	}
+	previous := watch.trip
+	if previous != nil && trip.UpdatedAt.Before(previous.UpdatedAt) {
+		return nil
+	}
+	watch.trip = trip
+	if previous == nil || trip.Status != previous.Status || !sameDriver(trip.DriverID, previous.DriverID) {
+		watch.send(statusUpdate(trip, s.now()))
+	}
+	return nil
+}
+
+// RefreshAll reloads every trip with subscribers, see Refresh, returning the first error
+func (s *TripStatusStream) RefreshAll(ctx context.Context) error {
+	s.mu.Lock()
+	tripIDs := make([]string, 0, len(s.watches))
+	for tripID := range s.watches {
+		tripIDs = append(tripIDs, tripID)
+	}
+	s.mu.Unlock()
+
+	var firstErr error
+	for _, tripID := range tripIDs {
+		if err := s.Refresh(ctx, tripID); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// sameDriver reports whether two trips are assigned the same driver, or both none
+func sameDriver(a, b *uuid.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// publishStatus sends a trip's new status to its subscribers
+func (s *TripStatusStream) publishStatus(trip *models.Trip, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	watch, ok := s.watches[trip.ID.String()]
+	if !ok {
+		return
+	}
+	watch.trip = trip
+	watch.send(statusUpdate(trip, at))
+}
+
+// publishLocation sends the ETA of a driver to the subscribers of the trips it is driving
+func (s *TripStatusStream) publishLocation(location *models.DriverLocationUpdate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, watch := range s.watches {
+		if watch.trip == nil || watch.trip.DriverID == nil || *watch.trip.DriverID != location.DriverID {
+			continue
+		}
+		if eta, ok := etaUpdate(watch.trip, location.Latitude, location.Longitude, location.At, s.eta); ok {
+			watch.send(eta)
+		}
+	}
+}
+
+// send queues an update for every subscriber, dropping it for those whose queue is full. The
+// caller holds the stream's mutex.
+func (w *tripWatch) send(update *models.TripUpdate) {
+	for updates := range w.subscribers {
+		select {
+		case updates <- update:
+		default:
+		}
+	}
+}
+
+// statusUpdate returns the status update of a trip
+func statusUpdate(trip *models.Trip, at time.Time) *models.TripUpdate {
+	return &models.TripUpdate{
+		Type:     models.TripUpdateStatus,
+		TripID:   trip.ID,
+		Status:   trip.Status,
+		DriverID: trip.DriverID,
+		At:       at,
+	}
+}
+
+// etaUpdate returns the ETA of a trip's driver at a location estimated by model: to the pickup
+// while the driver is on the way, to the destination while the trip is in progress. There is
+// none otherwise.
+func etaUpdate(trip *models.Trip, lat, lng float64, at time.Time, model *ETAModel) (*models.TripUpdate, bool) {
+	var target models.Location
+	switch trip.Status {
+	case models.TripStatusMatched, models.TripStatusAccepted:
+		target = models.Location{Latitude: trip.PickupLatitude, Longitude: trip.PickupLongitude}
+	case models.TripStatusInProgress:
+		target = models.Location{Latitude: trip.DestinationLatitude, Longitude: trip.DestinationLongitude}
+	default:
+		return nil, false
+	}
+
+	current := models.Location{Latitude: lat, Longitude: lng}
+	distance := current.DistanceTo(target)
+	eta := model.Seconds(distance)
+	distance = math.Round(distance*100) / 100
+	return &models.TripUpdate{
+		Type:            models.TripUpdateETA,
+		TripID:          trip.ID,
+		Status:          trip.Status,
+		DriverID:        trip.DriverID,
+		DriverLatitude:  &lat,
+		DriverLongitude: &lng,
+		DistanceKm:      &distance,
+		ETASeconds:      &eta,
+		At:              at,
+	}, true
+}
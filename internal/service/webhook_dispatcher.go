@@ -0,0 +1,307 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/httpclient"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+// Headers sent with every webhook request. The signature is "sha256=" followed by the hex
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the subscription secret.
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// maxWebhookErrorBody caps how much of a failed response body is kept as the delivery error
+const maxWebhookErrorBody = 512
+
+// WebhookDispatcher records a delivery for every subscription interested in a trip event
+// and posts them in the background, retrying failures with exponential backoff
+type WebhookDispatcher struct {
+	repo         repository.WebhookRepository
+	client       *httpclient.Client
+	policy       actor.RetryPolicy
+	pollInterval time.Duration
+	batchSize    int
+	rng          *rand.Rand
+	rngMutex     sync.Mutex
+	wake         chan struct{}
+	logger       *logging.Logger
+	ctx          context.Context
+	cancel       context.CancelFunc
+	wg           sync.WaitGroup
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher
+func NewWebhookDispatcher(repo repository.WebhookRepository, cfg config.WebhookConfig, logger *logging.Logger) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:   repo,
+		client: httpclient.New("webhook", config.HTTPClientConfig{Timeout: cfg.Timeout}, nil, logger),
+		policy: actor.RetryPolicy{
+			MaxAttempts:    cfg.MaxAttempts,
+			InitialBackoff: cfg.InitialBackoff,
+			MaxBackoff:     cfg.MaxBackoff,
+			Multiplier:     2,
+			Jitter:         0.1,
+		},
+		pollInterval: cfg.PollInterval,
+		batchSize:    cfg.BatchSize,
+		rng:          rand.New(rand.NewSource(time.Now().UnixNano())),
+		wake:         make(chan struct{}, 1),
+		logger:       logger.WithComponent("webhook_dispatcher"),
+	}
+}
+
+// SetHTTPClient sets the client the deliveries are posted with, in place of a plain client
+// without retries or circuit breaking
+func (d *WebhookDispatcher) SetHTTPClient(client *httpclient.Client) {
+	d.client = client
+}
+
+// Start delivers due webhooks on every poll interval and whenever an event is published
+func (d *WebhookDispatcher) Start(ctx context.Context) error {
+	d.ctx, d.cancel = context.WithCancel(ctx)
+
+	d.wg.Add(1)
+	go d.deliveryLoop()
+
+	d.logger.Info("Webhook dispatcher started")
+	return nil
+}
+
+// Stop stops the delivery loop. Pending deliveries stay pending and are picked up after a restart.
+func (d *WebhookDispatcher) Stop() error {
+	if d.cancel != nil {
+		d.cancel()
+	}
+	d.wg.Wait()
+
+	d.logger.Info("Webhook dispatcher stopped")
+	return nil
+}
+
+// PublishTripEvent records a pending delivery of the event for every active subscription
+// that receives it. The deliveries are sent by the delivery loop.
+func (d *WebhookDispatcher) PublishTripEvent(ctx context.Context, event models.WebhookEvent, trip *models.Trip) error {
+	return d.publish(ctx, models.WebhookPayload{Event: event, Trip: trip})
+}
+
+// PublishFareDisputeEvent records a pending delivery of a fare dispute decision for every
+// active subscription that receives it
+func (d *WebhookDispatcher) PublishFareDisputeEvent(ctx context.Context, event models.WebhookEvent, trip *models.Trip, dispute *models.FareDispute) error {
+	return d.publish(ctx, models.WebhookPayload{Event: event, Trip: trip, Dispute: dispute})
+}
+
+// PublishSafetyIncident records a pending delivery of a newly reported safety incident for
+// every active subscription that receives it
+func (d *WebhookDispatcher) PublishSafetyIncident(ctx context.Context, trip *models.Trip, incident *models.SafetyIncident) error {
+	return d.publish(ctx, models.WebhookPayload{Event: models.WebhookEventSafetyIncident, Trip: trip, Incident: incident})
+}
+
+// publish records the deliveries of an event. The payload's ID and occurrence time are set per
+// delivery.
+func (d *WebhookDispatcher) publish(ctx context.Context, payload models.WebhookPayload) error {
+	event, trip := payload.Event, payload.Trip
+	subscriptions, err := d.repo.ListSubscriptionsForEvent(ctx, event)
+	if err != nil {
+		return fmt.Errorf("failed to find webhook subscriptions: %w", err)
+	}
+
+	now := time.Now()
+	for _, subscription := range subscriptions {
+		delivery := &models.WebhookDelivery{
+			ID:             uuid.New(),
+			SubscriptionID: subscription.ID,
+			Event:          event,
+			TripID:         trip.ID,
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  &now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+
+		// The delivery ID doubles as the event ID so consumers can drop retried duplicates
+		payload.ID = delivery.ID
+		payload.OccurredAt = now
+		delivery.Payload, err = json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("failed to encode webhook payload: %w", err)
+		}
+
+		if err := d.repo.CreateDelivery(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to record webhook delivery: %w", err)
+		}
+	}
+
+	if len(subscriptions) > 0 {
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// DeliverDue attempts every delivery that is due and returns how many were attempted
+func (d *WebhookDispatcher) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := d.repo.GetDueDeliveries(ctx, time.Now(), d.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, delivery := range deliveries {
+		wg.Add(1)
+		go func(delivery *models.WebhookDelivery) {
+			defer wg.Done()
+			d.attempt(ctx, delivery)
+		}(delivery)
+	}
+	wg.Wait()
+
+	return len(deliveries), nil
+}
+
+// deliveryLoop delivers due webhooks until the dispatcher is stopped
+func (d *WebhookDispatcher) deliveryLoop() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(d.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-d.wake:
+		case <-d.ctx.Done():
+			return
+		}
+
+		// Keep going while full batches come back so a backlog drains without waiting for ticks
+		for {
+			attempted, err := d.DeliverDue(d.ctx)
+			if err != nil {
+				d.logger.WithError(err).Error("Webhook delivery failed")
+			}
+			if err != nil || attempted < d.batchSize || d.ctx.Err() != nil {
+				break
+			}
+		}
+	}
+}
+
+// attempt posts a delivery once and records the outcome, scheduling a retry while attempts remain
+func (d *WebhookDispatcher) attempt(ctx context.Context, delivery *models.WebhookDelivery) {
+	logger := d.logger.WithFields(logging.Fields{
+		"delivery_id":     delivery.ID,
+		"subscription_id": delivery.SubscriptionID,
+		"event":           delivery.Event,
+	})
+
+	subscription, err := d.repo.GetSubscription(ctx, delivery.SubscriptionID.String())
+	if err != nil {
+		logger.WithError(err).Error("Failed to load webhook subscription")
+		return
+	}
+
+	delivery.Attempts++
+	statusCode, err := d.send(ctx, subscription, delivery)
+	now := time.Now()
+	delivery.UpdatedAt = now
+	if statusCode != 0 {
+		delivery.ResponseStatus = &statusCode
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = models.WebhookDeliverySucceeded
+		delivery.LastError = nil
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= d.policy.MaxAttempts:
+		errorMessage := err.Error()
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = &errorMessage
+		delivery.NextAttemptAt = nil
+		logger.WithError(err).WithField("attempts", delivery.Attempts).Warn("Webhook delivery failed, giving up")
+	default:
+		errorMessage := err.Error()
+		d.rngMutex.Lock()
+		next := now.Add(d.policy.Backoff(delivery.Attempts, d.rng))
+		d.rngMutex.Unlock()
+		delivery.LastError = &errorMessage
+		delivery.NextAttemptAt = &next
+		logger.WithError(err).WithFields(logging.Fields{
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": next,
+		}).Debug("Webhook delivery failed, retrying")
+	}
+
+	// Record the outcome even if the dispatcher is stopping, so a sent delivery is not resent
+	if err := d.repo.UpdateDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		logger.WithError(err).Error("Failed to record webhook delivery attempt")
+	}
+}
+
+// send posts the delivery payload to the subscription URL. Any non-2xx response is an error.
+func (d *WebhookDispatcher) send(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, string(delivery.Event))
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID.String())
+	// Consumers drop repeated delivery IDs, so the client may retry a failed attempt
+	req.Header.Set(httpclient.IdempotencyKeyHeader, delivery.ID.String())
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(subscription.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookErrorBody))
+		return resp.StatusCode, fmt.Errorf("webhook endpoint returned %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the signature header value for a payload sent at timestamp (Unix seconds).
+// Consumers recompute it with their secret to verify a request came from this service.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
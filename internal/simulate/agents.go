@@ -0,0 +1,284 @@
+package simulate
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"sync/atomic"
+	"time"
+
+	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// assignment hands a matched trip from the passenger that observed the match to its driver
+type assignment struct {
+	tripID  uuid.UUID
+	pickup  models.Location
+	dropoff models.Location
+	done    chan struct{} // closed by the driver at the dropoff
+}
+
+// simDriver is a synthetic driver that cruises between random waypoints and drives matched trips
+type simDriver struct {
+	sim         *Simulation
+	id          uuid.UUID
+	rng         *rand.Rand
+	position    models.Location
+	target      models.Location
+	online      bool
+	assignments chan *assignment
+	current     *assignment
+	pickedUp    bool
+}
+
+// run moves the driver every tick until ctx is cancelled, then takes it offline
+func (d *simDriver) run(ctx context.Context) {
+	sc := d.sim.cfg.Scenario
+	defer func() {
+		if !d.online {
+			return
+		}
+		// Use a fresh context so the driver goes offline after the run ends
+		offlineCtx, cancel := context.WithTimeout(context.Background(), d.sim.cfg.RequestTimeout)
+		defer cancel()
+		d.sim.check(d.sim.client.UpdateDriverStatus(offlineCtx, d.id, models.DriverStatusOffline))
+	}()
+
+	d.target = d.randomWaypoint()
+	ticker := time.NewTicker(sc.Tick)
+	defer ticker.Stop()
+
+	var churn <-chan time.Time
+	if sc.Drivers.Churn.Interval > 0 {
+		churnTicker := time.NewTicker(sc.Drivers.Churn.Interval)
+		defer churnTicker.Stop()
+		churn = churnTicker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case a := <-d.assignments:
+			if d.current != nil {
+				// Still driving a trip; the server matched us twice, so drop the newer one
+				close(a.done)
+				continue
+			}
+			// Matched just before churning offline; the server already considers us busy
+			d.online = true
+			d.current = a
+			d.pickedUp = false
+			d.target = a.pickup
+		case <-churn:
+			d.churn(ctx)
+		case <-ticker.C:
+			if d.online {
+				d.step(ctx)
+			}
+		}
+	}
+}
+
+// churn takes an idle driver offline or brings an offline driver back, per the scenario probabilities
+func (d *simDriver) churn(ctx context.Context) {
+	if d.current != nil {
+		return
+	}
+
+	churn := d.sim.cfg.Scenario.Drivers.Churn
+	status := models.DriverStatusOffline
+	switch {
+	case d.online && d.rng.Float64() < churn.OfflineProbability:
+	case !d.online && d.rng.Float64() < churn.OnlineProbability:
+		status = models.DriverStatusOnline
+	default:
+		return
+	}
+
+	if d.sim.check(d.sim.client.UpdateDriverStatus(ctx, d.id, status)) {
+		d.online = status == models.DriverStatusOnline
+		atomic.AddInt64(&d.sim.stats.ChurnEvents, 1)
+	}
+}
+
+// step advances the driver toward its target and reports the new location
+func (d *simDriver) step(ctx context.Context) {
+	sc := d.sim.cfg.Scenario
+	if MoveToward(&d.position, d.target, sc.Drivers.SpeedKmh*sc.Tick.Hours()) {
+		d.arrive(ctx)
+	}
+
+	if d.sim.check(d.sim.client.UpdateDriverLocation(ctx, d.id, d.position)) {
+		atomic.AddInt64(&d.sim.stats.LocationUpdates, 1)
+	}
+}
+
+// arrive handles reaching the current target
+func (d *simDriver) arrive(ctx context.Context) {
+	switch {
+	case d.current == nil:
+		d.target = d.randomWaypoint()
+	case !d.pickedUp:
+		d.pickedUp = true
+		d.target = d.current.dropoff
+	default:
+		atomic.AddInt64(&d.sim.stats.TripsDriven, 1)
+		close(d.current.done)
+		d.current = nil
+		d.target = d.randomWaypoint()
+		// The API has no trip completion endpoint yet, so free the driver for the next match
+		d.sim.check(d.sim.client.UpdateDriverStatus(ctx, d.id, models.DriverStatusOnline))
+	}
+}
+
+// randomWaypoint picks the next place to cruise to, favouring hotspots like real drivers do
+func (d *simDriver) randomWaypoint() models.Location {
+	return d.sim.cfg.Scenario.RandomLocation(d.rng)
+}
+
+// simPassenger is a synthetic passenger; the arrival dispatcher hands it one ride at a time
+type simPassenger struct {
+	sim *Simulation
+	id  uuid.UUID
+	rng *rand.Rand
+}
+
+// ride requests a single ride and follows it until dropoff or cancellation
+func (p *simPassenger) ride(ctx context.Context) {
+	sc := p.sim.cfg.Scenario
+	pickup := sc.RandomLocation(p.rng)
+	dropoff := sc.RandomLocation(p.rng)
+
+	// Decide up front whether this passenger gives up before a driver is found
+	cancelAfter := time.Duration(-1)
+	if p.rng.Float64() < sc.Passengers.CancellationProbability {
+		cancelAfter = time.Duration(p.rng.Float64() * float64(sc.Passengers.MatchTimeout))
+	}
+
+	tripID, err := p.sim.client.RequestRide(ctx, p.id, pickup, dropoff)
+	if !p.sim.check(err) {
+		return
+	}
+	atomic.AddInt64(&p.sim.stats.RidesRequested, 1)
+
+	trip, reason, err := p.waitForMatch(ctx, tripID, cancelAfter)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.sim.check(err)
+		}
+		return
+	}
+
+	if trip == nil {
+		if reason == "" {
+			// Already cancelled on the server
+			return
+		}
+		cancelCtx, cancel := context.WithTimeout(context.Background(), p.sim.cfg.RequestTimeout)
+		defer cancel()
+		if p.sim.check(p.sim.client.CancelRide(cancelCtx, tripID, p.id, reason)) {
+			if reason == reasonMatchTimeout {
+				atomic.AddInt64(&p.sim.stats.RidesTimedOut, 1)
+			} else {
+				atomic.AddInt64(&p.sim.stats.RidesCancelled, 1)
+			}
+		}
+		return
+	}
+	atomic.AddInt64(&p.sim.stats.RidesMatched, 1)
+
+	driver, ok := p.sim.drivers[*trip.DriverID]
+	if !ok {
+		// Matched to a driver this run did not create
+		return
+	}
+
+	a := &assignment{tripID: tripID, pickup: pickup, dropoff: dropoff, done: make(chan struct{})}
+	select {
+	case driver.assignments <- a:
+	case <-ctx.Done():
+		return
+	}
+
+	select {
+	case <-a.done:
+	case <-ctx.Done():
+	}
+}
+
+// Cancellation reasons sent to the API
+const (
+	reasonMatchTimeout    = models.CancellationReasonWaitTooLong
+	reasonPassengerCancel = models.CancellationReasonChangedPlans
+)
+
+// waitForMatch polls the trip until a driver is assigned. It returns a nil trip and the
+// cancellation reason when the passenger gives up, or a nil trip and an empty reason when
+// the trip was cancelled elsewhere. A negative cancelAfter never gives up early.
+func (p *simPassenger) waitForMatch(ctx context.Context, tripID uuid.UUID, cancelAfter time.Duration) (*models.Trip, models.CancellationReason, error) {
+	started := time.Now()
+	ticker := time.NewTicker(p.sim.cfg.Scenario.Tick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, "", ctx.Err()
+		case <-ticker.C:
+		}
+
+		trip, err := p.sim.client.GetRideStatus(ctx, tripID)
+		if err != nil {
+			return nil, "", err
+		}
+		if trip.DriverID != nil {
+			return trip, "", nil
+		}
+		if trip.Status == models.TripStatusCancelled {
+			return nil, "", nil
+		}
+
+		waited := time.Since(started)
+		if cancelAfter >= 0 && waited >= cancelAfter {
+			return nil, reasonPassengerCancel, nil
+		}
+		if waited >= p.sim.cfg.Scenario.Passengers.MatchTimeout {
+			return nil, reasonMatchTimeout, nil
+		}
+	}
+}
+
+// check counts and logs API errors; it reports whether err was nil
+func (s *Simulation) check(err error) bool {
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// The run ended mid-request
+		return false
+	}
+
+	atomic.AddInt64(&s.stats.APIErrors, 1)
+	if s.cfg.Verbose {
+		log.Printf("simulate: %v", err)
+	}
+	return false
+}
+
+// MoveToward moves pos up to stepKm toward target and reports whether it arrived
+func MoveToward(pos *models.Location, target models.Location, stepKm float64) bool {
+	remaining := pos.DistanceTo(target)
+	if remaining <= stepKm {
+		*pos = target
+		return true
+	}
+
+	ratio := stepKm / remaining
+	pos.Latitude += (target.Latitude - pos.Latitude) * ratio
+	pos.Longitude += (target.Longitude - pos.Longitude) * ratio
+	return false
+}
@@ -0,0 +1,185 @@
+package simulate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
+)
+
+// apiClient is a thin HTTP client for the ride-hailing API
+type apiClient struct {
+	baseURL  string
+	approach string
+	http     *http.Client
+}
+
+// newAPIClient creates a client for the API served at baseURL
+func newAPIClient(baseURL, approach string, timeout time.Duration) *apiClient {
+	return &apiClient{
+		baseURL:  baseURL,
+		approach: approach,
+		http:     &http.Client{Timeout: timeout},
+	}
+}
+
+// apiError is returned for non-2xx responses
+type apiError struct {
+	StatusCode int
+	Response   handlers.ErrorResponse
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("api returned %d: %s: %s", e.StatusCode, e.Response.Error, e.Response.Message)
+}
+
+// CreateDriver registers a driver with its user account
+func (c *apiClient) CreateDriver(ctx context.Context, req handlers.CreateDriverRequest) (*models.Driver, error) {
+	var driver models.Driver
+	if err := c.do(ctx, http.MethodPost, "/api/v1/drivers", req, &driver); err != nil {
+		return nil, fmt.Errorf("failed to create driver: %w", err)
+	}
+	return &driver, nil
+}
+
+// OnboardDriver takes a newly created driver through onboarding to activation, submitting the
+// required documents, verifying them, completing training and activating the driver, so the
+// driver can go online when the server requires activation
+func (c *apiClient) OnboardDriver(ctx context.Context, driverID uuid.UUID) error {
+	steps := []struct {
+		path string
+		body interface{}
+	}{
+		{fmt.Sprintf("/api/v1/drivers/%s/onboarding/documents", driverID), handlers.SubmitOnboardingDocumentsRequest{Documents: models.RequiredOnboardingDocuments}},
+		{fmt.Sprintf("/api/v1/admin/driver-onboarding/%s/verify", driverID), handlers.ReviewDriverOnboardingRequest{ReviewedBy: "simulator"}},
+		{fmt.Sprintf("/api/v1/drivers/%s/onboarding/training", driverID), nil},
+		{fmt.Sprintf("/api/v1/admin/driver-onboarding/%s/activate", driverID), handlers.ReviewDriverOnboardingRequest{ReviewedBy: "simulator"}},
+	}
+	for _, step := range steps {
+		if err := c.do(ctx, http.MethodPost, step.path, step.body, nil); err != nil {
+			return fmt.Errorf("failed to onboard driver: %w", err)
+		}
+	}
+	return nil
+}
+
+// UpdateDriverStatus sets a driver online, offline or busy
+func (c *apiClient) UpdateDriverStatus(ctx context.Context, driverID uuid.UUID, status models.DriverStatus) error {
+	path := fmt.Sprintf("/api/v1/drivers/%s/status", driverID)
+	if err := c.do(ctx, http.MethodPut, path, handlers.UpdateDriverStatusRequest{Status: string(status)}, nil); err != nil {
+		return fmt.Errorf("failed to update driver status: %w", err)
+	}
+	return nil
+}
+
+// UpdateDriverLocation reports a driver's current position
+func (c *apiClient) UpdateDriverLocation(ctx context.Context, driverID uuid.UUID, loc models.Location) error {
+	path := fmt.Sprintf("/api/v1/drivers/%s/location", driverID)
+	req := handlers.UpdateDriverLocationRequest{Latitude: loc.Latitude, Longitude: loc.Longitude}
+	if err := c.do(ctx, http.MethodPut, path, req, nil); err != nil {
+		return fmt.Errorf("failed to update driver location: %w", err)
+	}
+	return nil
+}
+
+// CreatePassenger registers a passenger and returns its passenger record
+func (c *apiClient) CreatePassenger(ctx context.Context, req handlers.CreatePassengerRequest) (*models.Passenger, error) {
+	var user models.User
+	if err := c.do(ctx, http.MethodPost, "/api/v1/passengers", req, &user); err != nil {
+		return nil, fmt.Errorf("failed to create passenger: %w", err)
+	}
+
+	var passenger models.Passenger
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/users/%s/passenger", user.ID), nil, &passenger); err != nil {
+		return nil, fmt.Errorf("failed to get passenger: %w", err)
+	}
+	return &passenger, nil
+}
+
+// RequestRide requests a ride and returns the new trip ID
+func (c *apiClient) RequestRide(ctx context.Context, passengerID uuid.UUID, pickup, dropoff models.Location) (uuid.UUID, error) {
+	req := handlers.RequestRideRequest{
+		PassengerID:    passengerID,
+		PickupLat:      pickup.Latitude,
+		PickupLng:      pickup.Longitude,
+		DestinationLat: dropoff.Latitude,
+		DestinationLng: dropoff.Longitude,
+		RideType:       "standard",
+	}
+
+	var resp handlers.RequestRideResponse
+	if err := c.do(ctx, http.MethodPost, "/api/v1/rides/request?approach="+url.QueryEscape(c.approach), req, &resp); err != nil {
+		return uuid.Nil, fmt.Errorf("failed to request ride: %w", err)
+	}
+	return resp.TripID, nil
+}
+
+// GetRideStatus returns the current state of a trip
+func (c *apiClient) GetRideStatus(ctx context.Context, tripID uuid.UUID) (*models.Trip, error) {
+	var trip models.Trip
+	if err := c.do(ctx, http.MethodGet, fmt.Sprintf("/api/v1/rides/%s/status", tripID), nil, &trip); err != nil {
+		return nil, fmt.Errorf("failed to get ride status: %w", err)
+	}
+	return &trip, nil
+}
+
+// CancelRide cancels a trip on behalf of its passenger
+func (c *apiClient) CancelRide(ctx context.Context, tripID, passengerID uuid.UUID, reason models.CancellationReason) error {
+	req := handlers.CancelRideRequest{TripID: tripID, PassengerID: passengerID, Reason: reason}
+	path := fmt.Sprintf("/api/v1/rides/%s/cancel?approach=%s", tripID, url.QueryEscape(c.approach))
+	if err := c.do(ctx, http.MethodPost, path, req, nil); err != nil {
+		return fmt.Errorf("failed to cancel ride: %w", err)
+	}
+	return nil
+}
+
+// do sends a JSON request and decodes a JSON response into out when out is non-nil
+func (c *apiClient) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("User-Agent", "actor-model-simulator")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		apiErr := &apiError{StatusCode: resp.StatusCode}
+		_ = json.NewDecoder(resp.Body).Decode(&apiErr.Response)
+		return apiErr
+	}
+
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
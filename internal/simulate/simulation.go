@@ -0,0 +1,253 @@
+package simulate
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/scenario"
+
+	"github.com/google/uuid"
+)
+
+// Config holds the simulator settings
+type Config struct {
+	BaseURL        string
+	Approach       string // actor or traditional
+	RequestTimeout time.Duration
+	Verbose        bool // log every failed API call
+	Scenario       *scenario.Scenario
+}
+
+// Validate checks the settings and the scenario
+func (c Config) Validate() error {
+	if c.Approach != "actor" && c.Approach != "traditional" {
+		return fmt.Errorf("approach must be either 'actor' or 'traditional'")
+	}
+	if c.RequestTimeout <= 0 {
+		return fmt.Errorf("request timeout must be positive")
+	}
+	return c.Scenario.Validate()
+}
+
+// Stats counts what the simulation did; fields are updated atomically
+type Stats struct {
+	DriversRegistered    int64
+	PassengersRegistered int64
+	RidesRequested       int64
+	RidesMatched         int64
+	RidesCancelled       int64
+	RidesTimedOut        int64
+	ArrivalsDropped      int64
+	TripsDriven          int64
+	LocationUpdates      int64
+	ChurnEvents          int64
+	APIErrors            int64
+}
+
+// Simulation is the shared state of a simulator run
+type Simulation struct {
+	cfg     Config
+	client  *apiClient
+	drivers map[uuid.UUID]*simDriver // read-only once the run starts
+	stats   Stats
+}
+
+// New creates a simulation against the API served at cfg.BaseURL
+func New(cfg Config) *Simulation {
+	return &Simulation{
+		cfg:     cfg,
+		client:  newAPIClient(cfg.BaseURL, cfg.Approach, cfg.RequestTimeout),
+		drivers: make(map[uuid.UUID]*simDriver),
+	}
+}
+
+// Stats returns a snapshot of the counters, safe to call while the run is in progress
+func (s *Simulation) Stats() Stats {
+	return Stats{
+		DriversRegistered:    atomic.LoadInt64(&s.stats.DriversRegistered),
+		PassengersRegistered: atomic.LoadInt64(&s.stats.PassengersRegistered),
+		RidesRequested:       atomic.LoadInt64(&s.stats.RidesRequested),
+		RidesMatched:         atomic.LoadInt64(&s.stats.RidesMatched),
+		RidesCancelled:       atomic.LoadInt64(&s.stats.RidesCancelled),
+		RidesTimedOut:        atomic.LoadInt64(&s.stats.RidesTimedOut),
+		ArrivalsDropped:      atomic.LoadInt64(&s.stats.ArrivalsDropped),
+		TripsDriven:          atomic.LoadInt64(&s.stats.TripsDriven),
+		LocationUpdates:      atomic.LoadInt64(&s.stats.LocationUpdates),
+		ChurnEvents:          atomic.LoadInt64(&s.stats.ChurnEvents),
+		APIErrors:            atomic.LoadInt64(&s.stats.APIErrors),
+	}
+}
+
+// Run registers the synthetic users and drives them until the duration elapses or ctx is cancelled
+func (s *Simulation) Run(ctx context.Context) error {
+	sc := s.cfg.Scenario
+	rng := rand.New(rand.NewSource(sc.Seed))
+	// Unique per run so repeated runs don't collide on email and phone
+	runID := time.Now().Unix()
+
+	drivers, err := s.registerDrivers(ctx, rng, runID)
+	if err != nil {
+		return err
+	}
+	passengers, err := s.registerPassengers(ctx, rng, runID)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Running scenario %q with %d drivers and %d passengers for %s (seed %d)\n",
+		sc.Name, len(drivers), len(passengers), sc.Duration, sc.Seed)
+
+	runCtx, cancel := context.WithTimeout(ctx, sc.Duration)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, d := range drivers {
+		wg.Add(1)
+		go func(d *simDriver) {
+			defer wg.Done()
+			d.run(runCtx)
+		}(d)
+	}
+
+	idle := make(chan *simPassenger, len(passengers))
+	for _, p := range passengers {
+		idle <- p
+	}
+	if len(passengers) > 0 {
+		s.dispatchArrivals(runCtx, rand.New(rand.NewSource(rng.Int63())), idle, &wg)
+	}
+
+	wg.Wait()
+	return nil
+}
+
+// dispatchArrivals starts ride requests as a Poisson process at the scenario's current
+// arrival rate, handing each request to an idle passenger
+func (s *Simulation) dispatchArrivals(ctx context.Context, rng *rand.Rand, idle chan *simPassenger, wg *sync.WaitGroup) {
+	sc := s.cfg.Scenario
+	started := time.Now()
+
+	for {
+		rate := sc.ArrivalRate(time.Since(started))
+		// With no arrivals, re-check the rate every tick in case a later phase has some
+		wait := sc.Tick
+		if rate > 0 {
+			wait = time.Duration(rng.ExpFloat64() / rate * float64(time.Minute))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		if rate <= 0 {
+			continue
+		}
+
+		select {
+		case p := <-idle:
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				p.ride(ctx)
+				idle <- p
+			}()
+		default:
+			// Every passenger is already on a ride
+			atomic.AddInt64(&s.stats.ArrivalsDropped, 1)
+		}
+	}
+}
+
+// registerDrivers creates and onboards the drivers, places them in the area and takes them online
+func (s *Simulation) registerDrivers(ctx context.Context, rng *rand.Rand, runID int64) ([]*simDriver, error) {
+	sc := s.cfg.Scenario
+	drivers := make([]*simDriver, 0, sc.Drivers.Count)
+	for i := 0; i < sc.Drivers.Count; i++ {
+		driver, err := s.client.CreateDriver(ctx, handlers.CreateDriverRequest{
+			CreateUserRequest: handlers.CreateUserRequest{
+				Email:    fmt.Sprintf("sim-driver-%d-%d@example.com", runID, i),
+				Phone:    fmt.Sprintf("+1%d%04d", runID, i),
+				Name:     fmt.Sprintf("Simulated Driver %d", i+1),
+				UserType: string(models.UserTypeDriver),
+			},
+			LicenseNumber: fmt.Sprintf("SIM-%d-%04d", runID, i),
+			VehicleType:   "sedan",
+			VehiclePlate:  fmt.Sprintf("SIM%04d", i),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := s.client.OnboardDriver(ctx, driver.ID); err != nil {
+			return nil, err
+		}
+
+		d := &simDriver{
+			sim:         s,
+			id:          driver.ID,
+			rng:         rand.New(rand.NewSource(rng.Int63())),
+			position:    scenario.RandomPoint(rng, sc.Center(), sc.Area.RadiusKm),
+			assignments: make(chan *assignment, 1),
+			online:      true,
+		}
+		if err := s.client.UpdateDriverLocation(ctx, d.id, d.position); err != nil {
+			return nil, err
+		}
+		if err := s.client.UpdateDriverStatus(ctx, d.id, models.DriverStatusOnline); err != nil {
+			return nil, err
+		}
+
+		s.drivers[d.id] = d
+		drivers = append(drivers, d)
+		atomic.AddInt64(&s.stats.DriversRegistered, 1)
+	}
+	return drivers, nil
+}
+
+// registerPassengers creates the passengers
+func (s *Simulation) registerPassengers(ctx context.Context, rng *rand.Rand, runID int64) ([]*simPassenger, error) {
+	passengers := make([]*simPassenger, 0, s.cfg.Scenario.Passengers.Count)
+	for i := 0; i < s.cfg.Scenario.Passengers.Count; i++ {
+		passenger, err := s.client.CreatePassenger(ctx, handlers.CreatePassengerRequest{
+			CreateUserRequest: handlers.CreateUserRequest{
+				Email:    fmt.Sprintf("sim-passenger-%d-%d@example.com", runID, i),
+				Phone:    fmt.Sprintf("+2%d%04d", runID, i),
+				Name:     fmt.Sprintf("Simulated Passenger %d", i+1),
+				UserType: string(models.UserTypePassenger),
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		passengers = append(passengers, &simPassenger{
+			sim: s,
+			id:  passenger.ID,
+			rng: rand.New(rand.NewSource(rng.Int63())),
+		})
+		atomic.AddInt64(&s.stats.PassengersRegistered, 1)
+	}
+	return passengers, nil
+}
+
+// PrintSummary prints what the run produced
+func (s *Simulation) PrintSummary(elapsed time.Duration) {
+	fmt.Printf("Simulation finished after %s\n", elapsed.Round(time.Second))
+	fmt.Printf("  drivers registered:    %d\n", s.stats.DriversRegistered)
+	fmt.Printf("  passengers registered: %d\n", s.stats.PassengersRegistered)
+	fmt.Printf("  rides requested:       %d\n", s.stats.RidesRequested)
+	fmt.Printf("  rides matched:         %d\n", s.stats.RidesMatched)
+	fmt.Printf("  rides cancelled:       %d\n", s.stats.RidesCancelled)
+	fmt.Printf("  rides timed out:       %d\n", s.stats.RidesTimedOut)
+	fmt.Printf("  arrivals dropped:      %d\n", s.stats.ArrivalsDropped)
+	fmt.Printf("  trips driven:          %d\n", s.stats.TripsDriven)
+	fmt.Printf("  location updates:      %d\n", s.stats.LocationUpdates)
+	fmt.Printf("  driver churn events:   %d\n", s.stats.ChurnEvents)
+	fmt.Printf("  api errors:            %d\n", s.stats.APIErrors)
+}
@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalFilesPath is the path the local store's signed URLs are served under, followed by the key
+const LocalFilesPath = "/api/v1/files/"
+
+// LocalStore stores files in a directory, retrieved by URLs signed with an HMAC key
+type LocalStore struct {
+	dir        string
+	signingKey []byte
+	now        func() time.Time
+}
+
+// NewLocalStore creates a store in the directory, creating it if needed. URLs are signed with a
+// random key when the signing key is empty, so they are only valid on this instance until it
+// restarts.
+func NewLocalStore(dir, signingKey string) (*LocalStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	key := []byte(signingKey)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, fmt.Errorf("failed to generate signing key: %w", err)
+		}
+	}
+	return &LocalStore{dir: dir, signingKey: key, now: time.Now}, nil
+}
+
+// Put writes the file, replacing it atomically if it exists. The content type is not stored; it
+// is derived from the key's extension on retrieval.
+func (s *LocalStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return fmt.Errorf("failed to create file directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(file), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), file); err != nil {
+		return fmt.Errorf("failed to store file: %w", err)
+	}
+	return nil
+}
+
+// Get opens the file
+func (s *LocalStore) Get(ctx context.Context, key string) (*Object, error) {
+	file, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to stat file: %w", err)
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &Object{Body: f, ContentType: contentType, Size: info.Size()}, nil
+}
+
+// Delete removes the file. Deleting a missing file succeeds.
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	return nil
+}
+
+// SignedURL returns the relative URL of the file on the files endpoint, signed until the TTL
+// elapses
+func (s *LocalStore) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if _, err := s.path(key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.sign(key, expires))
+	return LocalFilesPath + key + "?" + query.Encode(), nil
+}
+
+// VerifyURL checks the expiry and signature query parameters of a signed URL of the key
+func (s *LocalStore) VerifyURL(key string, query url.Values) error {
+	expires := query.Get("expires")
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.now().Unix() > unix {
+		return ErrInvalidSignature
+	}
+
+	signature, err := hex.DecodeString(query.Get("signature"))
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	expected, _ := hex.DecodeString(s.sign(key, expires))
+	if !hmac.Equal(signature, expected) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// sign returns the signature of a key until the expiry
+func (s *LocalStore) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path returns the file of a key, rejecting keys that would escape the directory
+func (s *LocalStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,253 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/httpclient"
+)
+
+// unsignedPayload is the payload hash of presigned URLs, whose body is not known when signing
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Store stores files in a bucket of an S3-compatible object store, signing requests with AWS
+// Signature Version 4
+type S3Store struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	client    *httpclient.Client
+	now       func() time.Time
+}
+
+// NewS3Store creates a store in the configured bucket, sending requests with the client
+func NewS3Store(cfg config.StorageConfig, client *httpclient.Client) *S3Store {
+	endpoint, err := url.Parse(strings.TrimRight(cfg.S3Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		endpoint = &url.URL{Scheme: "https", Host: cfg.S3Endpoint}
+	}
+	return &S3Store{
+		endpoint:  endpoint,
+		region:    cfg.S3Region,
+		bucket:    cfg.S3Bucket,
+		accessKey: cfg.S3AccessKey,
+		secretKey: cfg.S3SecretKey,
+		pathStyle: cfg.S3PathStyle,
+		client:    client,
+		now:       time.Now,
+	}
+}
+
+// Put uploads the file
+func (s *S3Store) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	resp, err := s.do(ctx, http.MethodPut, key, data, map[string]string{"Content-Type": contentType})
+	if err != nil {
+		return fmt.Errorf("failed to upload file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to upload file: %w", responseError(resp))
+	}
+	return nil
+}
+
+// Get downloads the file
+func (s *S3Store) Get(ctx context.Context, key string) (*Object, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download file: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return &Object{Body: resp.Body, ContentType: resp.Header.Get("Content-Type"), Size: resp.ContentLength}, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, fmt.Errorf("failed to download file: %w", responseError(resp))
+	}
+}
+
+// Delete removes the file. Deleting a missing file succeeds.
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("failed to delete file: %w", responseError(resp))
+	}
+	return nil
+}
+
+// SignedURL returns a presigned URL downloading the file from the object store until the TTL
+// elapses, at most seven days
+func (s *S3Store) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+	return s.PresignedURL(key, ttl, s.now()), nil
+}
+
+// PresignedURL returns the URL downloading the file signed at the given time until the TTL
+// elapses
+func (s *S3Store) PresignedURL(key string, ttl time.Duration, at time.Time) string {
+	u := s.objectURL(key)
+	amzDate, scope := s.scope(at)
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.accessKey+"/"+scope)
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(ttl.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+
+	canonicalQuery := canonicalQueryString(query)
+	request := strings.Join([]string{
+		http.MethodGet,
+		u.EscapedPath(),
+		canonicalQuery,
+		"host:" + u.Host + "\n",
+		"host",
+		unsignedPayload,
+	}, "\n")
+
+	u.RawQuery = canonicalQuery + "&X-Amz-Signature=" + s.signature(request, amzDate, scope, at)
+	return u.String()
+}
+
+// do sends a request for an object, signed in the Authorization header
+func (s *S3Store) do(ctx context.Context, method, key string, body []byte, headers map[string]string) (*http.Response, error) {
+	if key == "" {
+		return nil, ErrInvalidKey
+	}
+
+	u := s.objectURL(key)
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	at := s.now()
+	amzDate, scope := s.scope(at)
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders := "host;x-amz-content-sha256;x-amz-date"
+	request := strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		"",
+		"host:" + u.Host + "\nx-amz-content-sha256:" + payloadHash + "\nx-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, s.signature(request, amzDate, scope, at)))
+	return s.client.Do(req)
+}
+
+// objectURL returns the URL of an object, the bucket in the host name or the path
+func (s *S3Store) objectURL(key string) *url.URL {
+	u := *s.endpoint
+	objectPath := "/" + key
+	if s.pathStyle {
+		objectPath = "/" + s.bucket + objectPath
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+	u.Path = strings.TrimRight(u.Path, "/") + objectPath
+	u.RawPath = uriEncode(u.Path, false)
+	return &u
+}
+
+// scope returns the request date and the credential scope of a signature made at the time
+func (s *S3Store) scope(at time.Time) (amzDate, scope string) {
+	at = at.UTC()
+	return at.Format("20060102T150405Z"), at.Format("20060102") + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature signs a canonical request
+func (s *S3Store) signature(canonicalRequest, amzDate, scope string, at time.Time) string {
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), at.UTC().Format("20060102"))
+	key = hmacSHA256(key, s.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// canonicalQueryString returns the query sorted by name, names and values encoded
+func canonicalQueryString(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range query[name] {
+			pairs = append(pairs, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode percent-encodes all but the unreserved characters, and slashes unless encodeSlash
+func uriEncode(value string, encodeSlash bool) string {
+	var b strings.Builder
+	for _, c := range []byte(value) {
+		switch {
+		case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// responseError describes an unexpected response of the object store
+func responseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("object store returned %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+}
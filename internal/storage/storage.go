@@ -0,0 +1,59 @@
+// Package storage stores uploaded files, such as profile pictures, on the local disk or in an
+// S3-compatible object store. Files are not served publicly: clients retrieve them by signed URLs
+// that expire, pointing at the object store or, for the local backend, at the files endpoint.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/httpclient"
+)
+
+var (
+	// ErrNotFound is returned for a key with no stored file
+	ErrNotFound = errors.New("file not found")
+	// ErrInvalidKey is returned for a key that is empty or escapes the store, e.g. with ".."
+	ErrInvalidKey = errors.New("invalid file key")
+	// ErrInvalidSignature is returned for a signed URL that was tampered with or has expired
+	ErrInvalidSignature = errors.New("invalid or expired signature")
+)
+
+// Object is a stored file. Body must be closed by the caller.
+type Object struct {
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+// Store stores files by key, such as "avatars/<user id>/<name>.png"
+type Store interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) (*Object, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL retrieving the file until the TTL elapses
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// URLVerifier is a store whose signed URLs are served by this service, verifying the signature
+// query parameters of a key
+type URLVerifier interface {
+	VerifyURL(key string, query url.Values) error
+}
+
+// New creates the store selected by the configuration. S3 requests are sent with the client.
+func New(cfg config.StorageConfig, client *httpclient.Client) (Store, error) {
+	switch cfg.Backend {
+	case "", "local":
+		return NewLocalStore(cfg.LocalDir, cfg.SigningKey)
+	case "s3":
+		return NewS3Store(cfg, client), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage backend: %s", cfg.Backend)
+	}
+}
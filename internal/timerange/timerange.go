@@ -0,0 +1,160 @@
+// Package timerange parses the time ranges accepted by the observability, traditional and
+// comparison endpoints. Each bound is an RFC3339 time, now, or an offset from now such as
+//
+//	start=-15m&end=now
+//	start=now-7d&end=now-1d
+//
+// Offsets are Go durations that may also use d for days and w for weeks, such as 1d12h.
+package timerange
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Now is the bound standing for the current time
+const Now = "now"
+
+// Error is returned for an invalid bound or range
+type Error struct {
+	Param   string // the bound at fault, start or end; empty for the range as a whole
+	Message string
+}
+
+func (e *Error) Error() string {
+	if e.Param == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Param, e.Message)
+}
+
+// Range is a time range from Start to End
+type Range struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Duration returns the length of the range
+func (r Range) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// Parser parses ranges relative to the current time, rejecting ranges longer than MaxWindow
+type Parser struct {
+	MaxWindow time.Duration    // longest range accepted; 0 accepts any
+	Now       func() time.Time // current time; time.Now when nil
+}
+
+// Parse parses the start and end bounds of a range. Without an end the range ends now, and
+// without a start it covers defaultWindow before its end.
+func (p Parser) Parse(start, end string, defaultWindow time.Duration) (Range, error) {
+	now := p.now()
+
+	var r Range
+	var err error
+	if end == "" {
+		r.End = now
+	} else if r.End, err = parseBound("end", end, now); err != nil {
+		return Range{}, err
+	}
+	if start == "" {
+		r.Start = r.End.Add(-defaultWindow)
+	} else if r.Start, err = parseBound("start", start, now); err != nil {
+		return Range{}, err
+	}
+
+	if !r.Start.Before(r.End) {
+		return Range{}, &Error{Message: "start must be before end"}
+	}
+	if p.MaxWindow > 0 && r.Duration() > p.MaxWindow {
+		return Range{}, &Error{Message: fmt.Sprintf("the range may span at most %s", FormatDuration(p.MaxWindow))}
+	}
+	return r, nil
+}
+
+// ParseTime parses a single bound, for endpoints that default and limit their ranges themselves
+func (p Parser) ParseTime(param, value string) (time.Time, error) {
+	return parseBound(param, value, p.now())
+}
+
+func (p Parser) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+// parseBound parses an RFC3339 time, now, or an offset from now
+func parseBound(param, value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == Now {
+		return now, nil
+	}
+
+	offset := strings.TrimPrefix(value, Now)
+	if strings.HasPrefix(offset, "-") || strings.HasPrefix(offset, "+") {
+		d, err := ParseDuration(offset)
+		if err != nil {
+			return time.Time{}, &Error{Param: param, Message: fmt.Sprintf("invalid offset %q: %v", value, err)}
+		}
+		return now.Add(d), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, &Error{Param: param, Message: fmt.Sprintf("%q must be an RFC3339 time, now, or an offset from now such as -15m or now-7d", value)}
+	}
+	return t, nil
+}
+
+// ParseDuration parses a Go duration that may also use d for days and w for weeks
+func ParseDuration(value string) (time.Duration, error) {
+	sign := time.Duration(1)
+	rest := value
+	switch {
+	case strings.HasPrefix(rest, "-"):
+		sign, rest = -1, rest[1:]
+	case strings.HasPrefix(rest, "+"):
+		rest = rest[1:]
+	}
+	if rest == "" {
+		return 0, fmt.Errorf("missing duration")
+	}
+
+	// Days and weeks lead the duration, as they are the longest units
+	var total time.Duration
+	for _, unit := range []struct {
+		suffix string
+		length time.Duration
+	}{{"w", 7 * 24 * time.Hour}, {"d", 24 * time.Hour}} {
+		i := strings.Index(rest, unit.suffix)
+		if i < 0 {
+			continue
+		}
+		n, err := strconv.ParseFloat(rest[:i], 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		total += time.Duration(n * float64(unit.length))
+		rest = rest[i+1:]
+	}
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		total += d
+	}
+	return sign * total, nil
+}
+
+// FormatDuration formats a duration in days when it is a whole number of them, such as 7d, and
+// as a Go duration otherwise
+func FormatDuration(d time.Duration) string {
+	if d > 0 && d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%dd", d/(24*time.Hour))
+	}
+	return d.String()
+}
@@ -13,6 +13,7 @@ import (
 // This replaces the previous custom monitoring implementation
 type TraditionalMonitor struct {
 	otelMonitor *observability.OTelMonitor
+	sloTracker  *observability.SLOTracker
 	logger      *logging.Logger
 	ctx         context.Context
 	cancel      context.CancelFunc
@@ -67,11 +68,37 @@ func (tm *TraditionalMonitor) Stop() error {
 	return nil
 }
 
+// SetSLOTracker feeds recorded requests into tracker and exports its SLOs as Prometheus gauges
+func (tm *TraditionalMonitor) SetSLOTracker(tracker *observability.SLOTracker) error {
+	tm.sloTracker = tracker
+	if tm.otelMonitor != nil {
+		return tm.otelMonitor.RegisterSLOGauges(tracker)
+	}
+	return nil
+}
+
 // RecordRequest records a request for monitoring using OpenTelemetry
 func (tm *TraditionalMonitor) RecordRequest(endpoint, method string, duration time.Duration, statusCode int) {
 	if tm.otelMonitor != nil {
 		tm.otelMonitor.RecordRequest(tm.ctx, endpoint, method, duration, statusCode)
 	}
+	if tm.sloTracker != nil {
+		tm.sloTracker.RecordRequest(endpoint, method, duration, statusCode)
+	}
+}
+
+// RecordPanic counts a recovered panic using OpenTelemetry
+func (tm *TraditionalMonitor) RecordPanic(route, method string) {
+	if tm.otelMonitor != nil {
+		tm.otelMonitor.RecordPanic(tm.ctx, route, method)
+	}
+}
+
+// RecordDeadlineExceeded counts a request that ran out of its time budget using OpenTelemetry
+func (tm *TraditionalMonitor) RecordDeadlineExceeded(route, method, layer string) {
+	if tm.otelMonitor != nil {
+		tm.otelMonitor.RecordDeadlineExceeded(tm.ctx, route, method, layer)
+	}
 }
 
 // RecordDatabaseOperation records database operation metrics using OpenTelemetry
@@ -95,6 +122,28 @@ func (tm *TraditionalMonitor) RecordSystemMetrics(cpuUsage, memoryUsage, diskUsa
 	}
 }
 
+// RecordMatchingDuration records how long a ride request took to be matched using OpenTelemetry
+func (tm *TraditionalMonitor) RecordMatchingDuration(mode string, duration time.Duration) {
+	if tm.otelMonitor != nil {
+		tm.otelMonitor.RecordMatchingDuration(tm.ctx, mode, duration)
+	}
+}
+
+// RecordMatchingQueueWait records how long a ride request waited for a matching worker using
+// OpenTelemetry
+func (tm *TraditionalMonitor) RecordMatchingQueueWait(wait time.Duration) {
+	if tm.otelMonitor != nil {
+		tm.otelMonitor.RecordMatchingQueueWait(tm.ctx, wait)
+	}
+}
+
+// RecordTripDuration records how long a completed trip took using OpenTelemetry
+func (tm *TraditionalMonitor) RecordTripDuration(duration time.Duration) {
+	if tm.otelMonitor != nil {
+		tm.otelMonitor.RecordTripDuration(tm.ctx, duration)
+	}
+}
+
 // RecordBusinessMetrics records business-specific metrics using OpenTelemetry
 func (tm *TraditionalMonitor) RecordBusinessMetrics(metricName string, value float64, tags map[string]string) {
 	if tm.otelMonitor != nil {
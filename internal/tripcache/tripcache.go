@@ -0,0 +1,213 @@
+// Package tripcache caches active trips in Redis for the ride status lookups passengers and
+// drivers poll, so that polls do not reach the database. Trips are written through on every
+// change made through the wrapped trip repository and dropped once completed or cancelled, so a
+// terminal status is always read from the database. Trips changed by other means, such as manual
+// fixes, are dropped by the database change listener when enabled and otherwise expire after the
+// TTL. Lookups are counted by result, with the hit ratio since the instance started.
+package tripcache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+)
+
+// keyPrefix prefixes the Redis keys holding the cached trips
+const keyPrefix = "trip_status:"
+
+// Lookup results
+const (
+	resultHit   = "hit"
+	resultMiss  = "miss"
+	resultError = "error"
+)
+
+// Stats are the lookups of the cache since the instance started
+type Stats struct {
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	Errors   int64   `json:"errors"`    // failed Redis reads, served from the database
+	HitRatio float64 `json:"hit_ratio"` // hits over lookups, 0 before the first
+}
+
+// Cache caches active trips in Redis
+type Cache struct {
+	client redis.Cmdable
+	ttl    time.Duration
+	logger *logging.Logger
+
+	hits, misses, errors atomic.Int64
+	lookups              metric.Int64Counter
+}
+
+// New creates a cache of the active trips in client. A nil meter disables metrics.
+func New(client redis.Cmdable, cfg config.TripCacheConfig, meter metric.Meter, logger *logging.Logger) (*Cache, error) {
+	if meter == nil {
+		meter = metricnoop.NewMeterProvider().Meter("")
+	}
+
+	c := &Cache{
+		client: client,
+		ttl:    cfg.TTL,
+		logger: logger.WithComponent("trip_cache"),
+	}
+
+	var err error
+	c.lookups, err = meter.Int64Counter(
+		"trip_status_cache_lookups_total",
+		metric.WithDescription("Trip status lookups, by result: hit, miss or error"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	_, err = meter.Float64ObservableGauge(
+		"trip_status_cache_hit_ratio",
+		metric.WithDescription("Share of the trip status lookups served from the cache since the instance started"),
+		metric.WithFloat64Callback(func(_ context.Context, o metric.Float64Observer) error {
+			o.Observe(c.Stats().HitRatio)
+			return nil
+		}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Get returns the cached trip; ok is false when the trip is not cached or Redis failed, in which
+// case the trip should be read from the database and stored with Put
+func (c *Cache) Get(ctx context.Context, tripID string) (trip *models.Trip, ok bool) {
+	data, err := c.client.Get(ctx, keyPrefix+tripID).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			c.record(ctx, resultMiss)
+		} else {
+			c.record(ctx, resultError)
+			c.logger.WithError(err).WithField("trip_id", tripID).Warn("Failed to read cached trip")
+		}
+		return nil, false
+	}
+
+	trip = &models.Trip{}
+	if err := json.Unmarshal(data, trip); err != nil {
+		c.record(ctx, resultError)
+		c.logger.WithError(err).WithField("trip_id", tripID).Warn("Failed to decode cached trip")
+		return nil, false
+	}
+	c.record(ctx, resultHit)
+	return trip, true
+}
+
+// Put caches an active trip and drops a completed or cancelled one
+func (c *Cache) Put(ctx context.Context, trip *models.Trip) {
+	if !trip.IsActive() {
+		c.Invalidate(ctx, trip.ID.String())
+		return
+	}
+
+	data, err := json.Marshal(trip)
+	if err != nil {
+		c.logger.WithError(err).WithField("trip_id", trip.ID).Warn("Failed to encode trip for caching")
+		return
+	}
+	if err := c.client.Set(ctx, keyPrefix+trip.ID.String(), data, c.ttl).Err(); err != nil {
+		c.logger.WithError(err).WithField("trip_id", trip.ID).Warn("Failed to cache trip")
+		// A cached older version must not outlive the change
+		c.Invalidate(ctx, trip.ID.String())
+	}
+}
+
+// Invalidate drops a cached trip, after the trip was changed elsewhere than through the wrapped
+// repository
+func (c *Cache) Invalidate(ctx context.Context, tripID string) {
+	if err := c.client.Del(ctx, keyPrefix+tripID).Err(); err != nil {
+		c.logger.WithError(err).WithField("trip_id", tripID).Warn("Failed to invalidate cached trip")
+	}
+}
+
+// Stats returns the lookups since the instance started
+func (c *Cache) Stats() Stats {
+	stats := Stats{Hits: c.hits.Load(), Misses: c.misses.Load(), Errors: c.errors.Load()}
+	if total := stats.Hits + stats.Misses + stats.Errors; total > 0 {
+		stats.HitRatio = float64(stats.Hits) / float64(total)
+	}
+	return stats
+}
+
+// record counts a lookup by result
+func (c *Cache) record(ctx context.Context, result string) {
+	switch result {
+	case resultHit:
+		c.hits.Add(1)
+	case resultMiss:
+		c.misses.Add(1)
+	default:
+		c.errors.Add(1)
+	}
+	c.lookups.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+}
+
+// Wrap returns trips writing every trip it changes through the cache: the trip is cached after a
+// successful write while active and dropped once completed or cancelled. A failed write drops the
+// trip too, since conditional writes fail when the stored trip changed meanwhile.
+func (c *Cache) Wrap(trips repository.TripRepository) repository.TripRepository {
+	return &writeThrough{TripRepository: trips, cache: c}
+}
+
+// writeThrough is a trip repository writing the trips it changes through the cache
+type writeThrough struct {
+	repository.TripRepository
+	cache *Cache
+}
+
+// written caches the trip after a successful write, or drops it after a failed one
+func (r *writeThrough) written(ctx context.Context, trip *models.Trip, err error) error {
+	if err != nil {
+		r.cache.Invalidate(ctx, trip.ID.String())
+		return err
+	}
+	r.cache.Put(ctx, trip)
+	return nil
+}
+
+func (r *writeThrough) Create(ctx context.Context, trip *models.Trip) error {
+	return r.written(ctx, trip, r.TripRepository.Create(ctx, trip))
+}
+
+func (r *writeThrough) Update(ctx context.Context, trip *models.Trip) error {
+	return r.written(ctx, trip, r.TripRepository.Update(ctx, trip))
+}
+
+func (r *writeThrough) Delete(ctx context.Context, id string) error {
+	err := r.TripRepository.Delete(ctx, id)
+	r.cache.Invalidate(ctx, id)
+	return err
+}
+
+func (r *writeThrough) Complete(ctx context.Context, trip *models.Trip, from models.TripStatus, completion *models.TripCompletion) error {
+	return r.written(ctx, trip, r.TripRepository.Complete(ctx, trip, from, completion))
+}
+
+func (r *writeThrough) StartPickupWait(ctx context.Context, trip *models.Trip, from models.TripStatus, wait *models.PickupWait) error {
+	return r.written(ctx, trip, r.TripRepository.StartPickupWait(ctx, trip, from, wait))
+}
+
+func (r *writeThrough) EndPickupWait(ctx context.Context, trip *models.Trip, from models.TripStatus, wait *models.PickupWait) error {
+	return r.written(ctx, trip, r.TripRepository.EndPickupWait(ctx, trip, from, wait))
+}
+
+func (r *writeThrough) RecordDriverCancellation(ctx context.Context, trip *models.Trip, from models.TripStatus, rematch *models.TripRematch) error {
+	return r.written(ctx, trip, r.TripRepository.RecordDriverCancellation(ctx, trip, from, rematch))
+}
@@ -0,0 +1,244 @@
+-- +migrate Up
+-- Convert high-volume observability tables to native range partitioning by time.
+-- actor_messages and event_logs are partitioned daily, system_metrics weekly.
+-- Partitions are named <table>_pYYYYMMDD after their lower bound and are created
+-- ahead of time by the partition maintenance job in internal/retention.
+
+-- +migrate StatementBegin
+-- Creates a single range partition of parent_table covering [from_ts, to_ts)
+CREATE OR REPLACE FUNCTION create_time_partition(parent_table TEXT, from_ts TIMESTAMP, to_ts TIMESTAMP)
+RETURNS TEXT AS $$
+DECLARE
+    partition_name TEXT := parent_table || '_p' || to_char(from_ts, 'YYYYMMDD');
+BEGIN
+    EXECUTE format(
+        'CREATE TABLE IF NOT EXISTS %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+        partition_name, parent_table, from_ts, to_ts
+    );
+    RETURN partition_name;
+END;
+$$ language 'plpgsql';
+-- +migrate StatementEnd
+
+ALTER TABLE actor_messages RENAME TO actor_messages_legacy;
+ALTER TABLE system_metrics RENAME TO system_metrics_legacy;
+ALTER TABLE event_logs RENAME TO event_logs_legacy;
+ALTER TABLE actor_messages_legacy RENAME CONSTRAINT actor_messages_pkey TO actor_messages_legacy_pkey;
+ALTER TABLE system_metrics_legacy RENAME CONSTRAINT system_metrics_pkey TO system_metrics_legacy_pkey;
+ALTER TABLE event_logs_legacy RENAME CONSTRAINT event_logs_pkey TO event_logs_legacy_pkey;
+
+-- Index names are global, so drop the legacy ones before recreating them on the partitioned tables
+DROP INDEX IF EXISTS idx_actor_messages_trace;
+DROP INDEX IF EXISTS idx_actor_messages_span;
+DROP INDEX IF EXISTS idx_actor_messages_parent_span;
+DROP INDEX IF EXISTS idx_actor_messages_sender;
+DROP INDEX IF EXISTS idx_actor_messages_receiver;
+DROP INDEX IF EXISTS idx_actor_messages_type;
+DROP INDEX IF EXISTS idx_actor_messages_sent_at;
+DROP INDEX IF EXISTS idx_actor_messages_status;
+DROP INDEX IF EXISTS idx_system_metrics_name;
+DROP INDEX IF EXISTS idx_system_metrics_type;
+DROP INDEX IF EXISTS idx_system_metrics_actor;
+DROP INDEX IF EXISTS idx_system_metrics_timestamp;
+DROP INDEX IF EXISTS idx_event_logs_trace_id;
+DROP INDEX IF EXISTS idx_event_logs_type;
+DROP INDEX IF EXISTS idx_event_logs_category;
+DROP INDEX IF EXISTS idx_event_logs_actor;
+DROP INDEX IF EXISTS idx_event_logs_entity;
+DROP INDEX IF EXISTS idx_event_logs_timestamp;
+DROP INDEX IF EXISTS idx_event_logs_severity;
+
+-- Actor messages, partitioned daily by created_at
+CREATE TABLE actor_messages (
+    id UUID NOT NULL DEFAULT gen_random_uuid(),
+    trace_id UUID NOT NULL,
+    span_id UUID NOT NULL,
+    parent_span_id UUID,
+    sender_actor_type VARCHAR(50) NOT NULL,
+    sender_actor_id VARCHAR(255) NOT NULL,
+    receiver_actor_type VARCHAR(50) NOT NULL,
+    receiver_actor_id VARCHAR(255) NOT NULL,
+    message_type VARCHAR(100) NOT NULL,
+    message_payload JSONB,
+    status VARCHAR(20) DEFAULT 'sent' CHECK (status IN ('sent', 'received', 'processed', 'failed')),
+    sent_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    received_at TIMESTAMP,
+    processed_at TIMESTAMP,
+    processing_duration_ms INTEGER,
+    error_message TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id, created_at)
+) PARTITION BY RANGE (created_at);
+
+-- System metrics, partitioned weekly by timestamp
+CREATE TABLE system_metrics (
+    id UUID NOT NULL DEFAULT gen_random_uuid(),
+    metric_name VARCHAR(100) NOT NULL,
+    metric_type VARCHAR(20) NOT NULL CHECK (metric_type IN ('counter', 'gauge', 'histogram')),
+    metric_value DECIMAL(15, 6) NOT NULL,
+    labels JSONB,
+    actor_type VARCHAR(50),
+    actor_id VARCHAR(255),
+    timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id, timestamp)
+) PARTITION BY RANGE (timestamp);
+
+-- Event logs, partitioned daily by timestamp
+CREATE TABLE event_logs (
+    id UUID NOT NULL DEFAULT gen_random_uuid(),
+    trace_id UUID,
+    event_type VARCHAR(100) NOT NULL,
+    event_category VARCHAR(50) NOT NULL CHECK (event_category IN (
+        'business', 'system', 'error', 'performance', 'security'
+    )),
+    actor_type VARCHAR(50),
+    actor_id VARCHAR(255),
+    entity_type VARCHAR(50),
+    entity_id UUID,
+    event_data JSONB,
+    severity VARCHAR(20) DEFAULT 'info' CHECK (severity IN ('debug', 'info', 'warn', 'error', 'fatal')),
+    message TEXT,
+    timestamp TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (id, timestamp)
+) PARTITION BY RANGE (timestamp);
+
+-- Default partitions catch rows outside the pre-created ranges
+CREATE TABLE actor_messages_default PARTITION OF actor_messages DEFAULT;
+CREATE TABLE system_metrics_default PARTITION OF system_metrics DEFAULT;
+CREATE TABLE event_logs_default PARTITION OF event_logs DEFAULT;
+
+-- +migrate StatementBegin
+-- Create partitions covering existing data and the next seven days
+DO $$
+DECLARE
+    day_start TIMESTAMP;
+    week_start TIMESTAMP;
+BEGIN
+    day_start := date_trunc('day', LEAST(
+        COALESCE((SELECT MIN(created_at) FROM actor_messages_legacy), CURRENT_TIMESTAMP),
+        COALESCE((SELECT MIN(timestamp) FROM event_logs_legacy), CURRENT_TIMESTAMP)
+    ));
+    WHILE day_start < date_trunc('day', CURRENT_TIMESTAMP) + INTERVAL '8 days' LOOP
+        PERFORM create_time_partition('actor_messages', day_start, day_start + INTERVAL '1 day');
+        PERFORM create_time_partition('event_logs', day_start, day_start + INTERVAL '1 day');
+        day_start := day_start + INTERVAL '1 day';
+    END LOOP;
+
+    week_start := date_trunc('week', COALESCE((SELECT MIN(timestamp) FROM system_metrics_legacy), CURRENT_TIMESTAMP));
+    WHILE week_start < date_trunc('week', CURRENT_TIMESTAMP) + INTERVAL '2 weeks' LOOP
+        PERFORM create_time_partition('system_metrics', week_start, week_start + INTERVAL '1 week');
+        week_start := week_start + INTERVAL '1 week';
+    END LOOP;
+END;
+$$;
+-- +migrate StatementEnd
+
+INSERT INTO actor_messages SELECT id, trace_id, span_id, parent_span_id, sender_actor_type, sender_actor_id,
+    receiver_actor_type, receiver_actor_id, message_type, message_payload, status, sent_at, received_at,
+    processed_at, processing_duration_ms, error_message, COALESCE(created_at, CURRENT_TIMESTAMP)
+FROM actor_messages_legacy;
+INSERT INTO system_metrics SELECT id, metric_name, metric_type, metric_value, labels, actor_type, actor_id,
+    COALESCE(timestamp, CURRENT_TIMESTAMP), created_at
+FROM system_metrics_legacy;
+INSERT INTO event_logs SELECT id, trace_id, event_type, event_category, actor_type, actor_id, entity_type,
+    entity_id, event_data, severity, message, COALESCE(timestamp, CURRENT_TIMESTAMP), created_at
+FROM event_logs_legacy;
+
+DROP TABLE actor_messages_legacy;
+DROP TABLE system_metrics_legacy;
+DROP TABLE event_logs_legacy;
+
+-- Indexes on the partitioned parents are created on every partition
+CREATE INDEX idx_actor_messages_trace ON actor_messages(trace_id);
+CREATE INDEX idx_actor_messages_span ON actor_messages(span_id);
+CREATE INDEX idx_actor_messages_parent_span ON actor_messages(parent_span_id);
+CREATE INDEX idx_actor_messages_sender ON actor_messages(sender_actor_type, sender_actor_id);
+CREATE INDEX idx_actor_messages_receiver ON actor_messages(receiver_actor_type, receiver_actor_id);
+CREATE INDEX idx_actor_messages_type ON actor_messages(message_type);
+CREATE INDEX idx_actor_messages_sent_at ON actor_messages(sent_at);
+CREATE INDEX idx_actor_messages_status ON actor_messages(status);
+
+CREATE INDEX idx_system_metrics_name ON system_metrics(metric_name);
+CREATE INDEX idx_system_metrics_type ON system_metrics(metric_type);
+CREATE INDEX idx_system_metrics_actor ON system_metrics(actor_type, actor_id);
+CREATE INDEX idx_system_metrics_timestamp ON system_metrics(timestamp);
+
+CREATE INDEX idx_event_logs_trace_id ON event_logs(trace_id);
+CREATE INDEX idx_event_logs_type ON event_logs(event_type);
+CREATE INDEX idx_event_logs_category ON event_logs(event_category);
+CREATE INDEX idx_event_logs_actor ON event_logs(actor_type, actor_id);
+CREATE INDEX idx_event_logs_entity ON event_logs(entity_type, entity_id);
+CREATE INDEX idx_event_logs_timestamp ON event_logs(timestamp);
+CREATE INDEX idx_event_logs_severity ON event_logs(severity);
+
+-- +migrate Down
+-- Convert the partitioned tables back to plain tables, preserving data
+
+ALTER TABLE actor_messages RENAME TO actor_messages_partitioned;
+ALTER TABLE system_metrics RENAME TO system_metrics_partitioned;
+ALTER TABLE event_logs RENAME TO event_logs_partitioned;
+ALTER TABLE actor_messages_partitioned RENAME CONSTRAINT actor_messages_pkey TO actor_messages_partitioned_pkey;
+ALTER TABLE system_metrics_partitioned RENAME CONSTRAINT system_metrics_pkey TO system_metrics_partitioned_pkey;
+ALTER TABLE event_logs_partitioned RENAME CONSTRAINT event_logs_pkey TO event_logs_partitioned_pkey;
+
+DROP INDEX IF EXISTS idx_actor_messages_trace;
+DROP INDEX IF EXISTS idx_actor_messages_span;
+DROP INDEX IF EXISTS idx_actor_messages_parent_span;
+DROP INDEX IF EXISTS idx_actor_messages_sender;
+DROP INDEX IF EXISTS idx_actor_messages_receiver;
+DROP INDEX IF EXISTS idx_actor_messages_type;
+DROP INDEX IF EXISTS idx_actor_messages_sent_at;
+DROP INDEX IF EXISTS idx_actor_messages_status;
+DROP INDEX IF EXISTS idx_system_metrics_name;
+DROP INDEX IF EXISTS idx_system_metrics_type;
+DROP INDEX IF EXISTS idx_system_metrics_actor;
+DROP INDEX IF EXISTS idx_system_metrics_timestamp;
+DROP INDEX IF EXISTS idx_event_logs_trace_id;
+DROP INDEX IF EXISTS idx_event_logs_type;
+DROP INDEX IF EXISTS idx_event_logs_category;
+DROP INDEX IF EXISTS idx_event_logs_actor;
+DROP INDEX IF EXISTS idx_event_logs_entity;
+DROP INDEX IF EXISTS idx_event_logs_timestamp;
+DROP INDEX IF EXISTS idx_event_logs_severity;
+
+CREATE TABLE actor_messages (LIKE actor_messages_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+CREATE TABLE system_metrics (LIKE system_metrics_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+CREATE TABLE event_logs (LIKE event_logs_partitioned INCLUDING DEFAULTS INCLUDING CONSTRAINTS);
+
+INSERT INTO actor_messages SELECT * FROM actor_messages_partitioned;
+INSERT INTO system_metrics SELECT * FROM system_metrics_partitioned;
+INSERT INTO event_logs SELECT * FROM event_logs_partitioned;
+
+DROP TABLE actor_messages_partitioned;
+DROP TABLE system_metrics_partitioned;
+DROP TABLE event_logs_partitioned;
+
+ALTER TABLE actor_messages ADD PRIMARY KEY (id);
+ALTER TABLE system_metrics ADD PRIMARY KEY (id);
+ALTER TABLE event_logs ADD PRIMARY KEY (id);
+
+CREATE INDEX idx_actor_messages_trace ON actor_messages(trace_id);
+CREATE INDEX idx_actor_messages_span ON actor_messages(span_id);
+CREATE INDEX idx_actor_messages_parent_span ON actor_messages(parent_span_id);
+CREATE INDEX idx_actor_messages_sender ON actor_messages(sender_actor_type, sender_actor_id);
+CREATE INDEX idx_actor_messages_receiver ON actor_messages(receiver_actor_type, receiver_actor_id);
+CREATE INDEX idx_actor_messages_type ON actor_messages(message_type);
+CREATE INDEX idx_actor_messages_sent_at ON actor_messages(sent_at);
+CREATE INDEX idx_actor_messages_status ON actor_messages(status);
+
+CREATE INDEX idx_system_metrics_name ON system_metrics(metric_name);
+CREATE INDEX idx_system_metrics_type ON system_metrics(metric_type);
+CREATE INDEX idx_system_metrics_actor ON system_metrics(actor_type, actor_id);
+CREATE INDEX idx_system_metrics_timestamp ON system_metrics(timestamp);
+
+CREATE INDEX idx_event_logs_trace_id ON event_logs(trace_id);
+CREATE INDEX idx_event_logs_type ON event_logs(event_type);
+CREATE INDEX idx_event_logs_category ON event_logs(event_category);
+CREATE INDEX idx_event_logs_actor ON event_logs(actor_type, actor_id);
+CREATE INDEX idx_event_logs_entity ON event_logs(entity_type, entity_id);
+CREATE INDEX idx_event_logs_timestamp ON event_logs(timestamp);
+CREATE INDEX idx_event_logs_severity ON event_logs(severity);
+
+DROP FUNCTION IF EXISTS create_time_partition(TEXT, TIMESTAMP, TIMESTAMP);
@@ -0,0 +1,43 @@
+-- +migrate Up
+-- Record every driver status change so online hours can be aggregated per period.
+-- Rows are written by a trigger, so status changes made through any code path
+-- (or by hand) are captured.
+
+CREATE TABLE driver_status_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('online', 'offline', 'busy')),
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_driver_status_history_driver ON driver_status_history(driver_id, changed_at);
+CREATE INDEX idx_driver_status_history_changed_at ON driver_status_history(changed_at);
+
+-- Indexes backing the per-driver trip aggregates
+CREATE INDEX idx_trips_driver_requested_at ON trips(driver_id, requested_at);
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION record_driver_status_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR NEW.status IS DISTINCT FROM OLD.status THEN
+        INSERT INTO driver_status_history (driver_id, status, changed_at)
+        VALUES (NEW.id, NEW.status, CURRENT_TIMESTAMP);
+    END IF;
+    RETURN NEW;
+END;
+$$ language 'plpgsql';
+-- +migrate StatementEnd
+
+CREATE TRIGGER record_driver_status_change AFTER INSERT OR UPDATE OF status ON drivers
+    FOR EACH ROW EXECUTE FUNCTION record_driver_status_change();
+
+-- Seed the current status of existing drivers so their current session is counted
+INSERT INTO driver_status_history (driver_id, status, changed_at)
+SELECT id, status, updated_at FROM drivers;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS record_driver_status_change ON drivers;
+DROP FUNCTION IF EXISTS record_driver_status_change();
+DROP INDEX IF EXISTS idx_trips_driver_requested_at;
+DROP TABLE IF EXISTS driver_status_history;
@@ -0,0 +1,30 @@
+-- +migrate Up
+-- Places a passenger saved for quick ride requests. A passenger has at most one
+-- home and one work location but any number of custom ones.
+
+CREATE TABLE saved_locations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    passenger_id UUID NOT NULL REFERENCES passengers(id) ON DELETE CASCADE,
+    label VARCHAR(20) NOT NULL CHECK (label IN ('home', 'work', 'custom')),
+    name VARCHAR(255) NOT NULL,
+    latitude DECIMAL(10, 8) NOT NULL,
+    longitude DECIMAL(11, 8) NOT NULL,
+    address TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_saved_locations_passenger_id ON saved_locations(passenger_id);
+CREATE UNIQUE INDEX idx_saved_locations_passenger_label ON saved_locations(passenger_id, label)
+    WHERE label IN ('home', 'work');
+
+-- Backs the recent destinations lookup
+CREATE INDEX idx_trips_passenger_requested_at ON trips(passenger_id, requested_at);
+
+CREATE TRIGGER update_saved_locations_updated_at BEFORE UPDATE ON saved_locations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS update_saved_locations_updated_at ON saved_locations;
+DROP INDEX IF EXISTS idx_trips_passenger_requested_at;
+DROP TABLE IF EXISTS saved_locations;
@@ -0,0 +1,20 @@
+-- +migrate Up
+-- Destination mode: a driver heading somewhere (usually home) is only offered trips
+-- along the way. Every activation is kept so the daily limit can be enforced.
+
+CREATE TABLE driver_destinations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    latitude DECIMAL(10, 8) NOT NULL,
+    longitude DECIMAL(11, 8) NOT NULL,
+    address TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    cleared_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_driver_destinations_driver_created_at ON driver_destinations(driver_id, created_at);
+CREATE INDEX idx_driver_destinations_active ON driver_destinations(expires_at) WHERE cleared_at IS NULL;
+
+-- +migrate Down
+DROP TABLE IF EXISTS driver_destinations;
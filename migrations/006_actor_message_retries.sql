@@ -0,0 +1,19 @@
+-- +migrate Up
+-- Failed actor messages are retried according to a per message type policy and
+-- dead-lettered once it is exhausted. Each failed attempt records how many retries
+-- preceded it.
+
+ALTER TABLE actor_messages ADD COLUMN retry_count INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE actor_messages DROP CONSTRAINT actor_messages_status_check;
+ALTER TABLE actor_messages ADD CONSTRAINT actor_messages_status_check
+    CHECK (status IN ('sent', 'received', 'processed', 'failed', 'dead_lettered'));
+
+-- +migrate Down
+UPDATE actor_messages SET status = 'failed' WHERE status = 'dead_lettered';
+
+ALTER TABLE actor_messages DROP CONSTRAINT actor_messages_status_check;
+ALTER TABLE actor_messages ADD CONSTRAINT actor_messages_status_check
+    CHECK (status IN ('sent', 'received', 'processed', 'failed'));
+
+ALTER TABLE actor_messages DROP COLUMN IF EXISTS retry_count;
@@ -0,0 +1,29 @@
+-- +migrate Up
+-- Device sessions: drivers and passengers log in from a device and receive an access token
+-- and a refresh token. Only token hashes are stored; sessions stay for auditing once revoked.
+
+CREATE TABLE sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    user_type VARCHAR(20) NOT NULL CHECK (user_type IN ('passenger', 'driver')),
+    device_id VARCHAR(255) NOT NULL,
+    device_name VARCHAR(255),
+    access_token_hash VARCHAR(64) NOT NULL UNIQUE,
+    refresh_token_hash VARCHAR(64) NOT NULL UNIQUE,
+    access_expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_used_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_reason VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id, last_used_at) WHERE revoked_at IS NULL;
+
+CREATE TRIGGER update_sessions_updated_at BEFORE UPDATE ON sessions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS update_sessions_updated_at ON sessions;
+DROP TABLE IF EXISTS sessions;
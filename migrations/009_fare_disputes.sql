@@ -0,0 +1,46 @@
+-- +migrate Up
+-- Fare disputes: passengers dispute the fare of a completed trip and admins resolve or reject
+-- the dispute. Resolved disputes add an entry to the trip's fare adjustments ledger.
+
+CREATE TABLE fare_disputes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    passenger_id UUID NOT NULL REFERENCES passengers(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    original_fare DECIMAL(10,2) NOT NULL,
+    requested_fare DECIMAL(10,2),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'under_review', 'resolved', 'rejected')),
+    adjusted_fare DECIMAL(10,2),
+    reviewed_by VARCHAR(255),
+    resolution_note TEXT,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A trip has at most one dispute awaiting a decision
+CREATE UNIQUE INDEX idx_fare_disputes_active_trip ON fare_disputes(trip_id) WHERE status IN ('open', 'under_review');
+CREATE INDEX idx_fare_disputes_status ON fare_disputes(status, created_at);
+
+CREATE TABLE fare_adjustments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    dispute_id UUID NOT NULL REFERENCES fare_disputes(id) ON DELETE CASCADE,
+    previous_fare DECIMAL(10,2) NOT NULL,
+    new_fare DECIMAL(10,2) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    reason TEXT NOT NULL,
+    adjusted_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_fare_adjustments_trip_id ON fare_adjustments(trip_id, created_at);
+
+CREATE TRIGGER update_fare_disputes_updated_at BEFORE UPDATE ON fare_disputes
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS update_fare_disputes_updated_at ON fare_disputes;
+DROP TABLE IF EXISTS fare_adjustments;
+DROP TABLE IF EXISTS fare_disputes;
@@ -0,0 +1,61 @@
+-- +migrate Up
+-- Driver incentive campaigns: drivers completing the required number of trips within a
+-- campaign's window earn its bonus. Progress is computed from trip completion events.
+
+CREATE TABLE incentive_campaigns (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    required_trips INTEGER NOT NULL CHECK (required_trips > 0),
+    bonus_amount DECIMAL(10,2) NOT NULL CHECK (bonus_amount > 0),
+    starts_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    ends_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_incentive_campaigns_window ON incentive_campaigns(starts_at, ends_at) WHERE active;
+
+-- Each completed trip counts at most once towards a campaign
+CREATE TABLE incentive_trip_credits (
+    campaign_id UUID NOT NULL REFERENCES incentive_campaigns(id) ON DELETE CASCADE,
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    completed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    PRIMARY KEY (campaign_id, trip_id)
+);
+
+CREATE TABLE incentive_progress (
+    campaign_id UUID NOT NULL REFERENCES incentive_campaigns(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    completed_trips INTEGER NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (campaign_id, driver_id)
+);
+
+CREATE INDEX idx_incentive_progress_driver_id ON incentive_progress(driver_id);
+
+CREATE TABLE incentive_payouts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    campaign_id UUID NOT NULL REFERENCES incentive_campaigns(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    amount DECIMAL(10,2) NOT NULL,
+    trip_count INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (campaign_id, driver_id)
+);
+
+CREATE INDEX idx_incentive_payouts_driver_id ON incentive_payouts(driver_id, created_at);
+
+CREATE TRIGGER update_incentive_campaigns_updated_at BEFORE UPDATE ON incentive_campaigns
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS update_incentive_campaigns_updated_at ON incentive_campaigns;
+DROP TABLE IF EXISTS incentive_payouts;
+DROP TABLE IF EXISTS incentive_progress;
+DROP TABLE IF EXISTS incentive_trip_credits;
+DROP TABLE IF EXISTS incentive_campaigns;
@@ -0,0 +1,40 @@
+-- +migrate Up
+-- Summary tables behind the dashboard endpoints. They are recomputed from trips and drivers by
+-- the dashboard refresher on a schedule, so dashboards don't aggregate the raw tables per request.
+
+CREATE TABLE dashboard_hourly_trips (
+    hour TIMESTAMP WITH TIME ZONE PRIMARY KEY,
+    requested INTEGER NOT NULL DEFAULT 0,
+    matched INTEGER NOT NULL DEFAULT 0,
+    completed INTEGER NOT NULL DEFAULT 0,
+    cancelled INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE dashboard_matching_times (
+    hour TIMESTAMP WITH TIME ZONE PRIMARY KEY,
+    samples INTEGER NOT NULL,
+    avg_seconds DOUBLE PRECISION NOT NULL,
+    p50_seconds DOUBLE PRECISION NOT NULL,
+    p90_seconds DOUBLE PRECISION NOT NULL,
+    p99_seconds DOUBLE PRECISION NOT NULL
+);
+
+CREATE TABLE dashboard_zone_supply (
+    zone VARCHAR(12) PRIMARY KEY,
+    available_drivers INTEGER NOT NULL DEFAULT 0,
+    busy_drivers INTEGER NOT NULL DEFAULT 0
+);
+
+-- Freshness of each summary view
+CREATE TABLE dashboard_refreshes (
+    view_name VARCHAR(50) PRIMARY KEY,
+    refreshed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_ms BIGINT NOT NULL,
+    row_count INTEGER NOT NULL
+);
+
+-- +migrate Down
+DROP TABLE IF EXISTS dashboard_refreshes;
+DROP TABLE IF EXISTS dashboard_zone_supply;
+DROP TABLE IF EXISTS dashboard_matching_times;
+DROP TABLE IF EXISTS dashboard_hourly_trips;
@@ -0,0 +1,25 @@
+-- +migrate Up
+-- Actor instances and actor messages are linked to the business entity they represent
+-- or relate to (a trip, driver or passenger), like event logs already are.
+
+ALTER TABLE actor_instances ADD COLUMN entity_type VARCHAR(50);
+
+DROP INDEX IF EXISTS idx_actor_instances_entity;
+CREATE INDEX idx_actor_instances_entity ON actor_instances(entity_type, entity_id);
+
+-- Columns and indexes added to the partitioned parent apply to every partition
+ALTER TABLE actor_messages ADD COLUMN entity_type VARCHAR(50);
+ALTER TABLE actor_messages ADD COLUMN entity_id UUID;
+
+CREATE INDEX idx_actor_messages_entity ON actor_messages(entity_type, entity_id);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_actor_messages_entity;
+
+ALTER TABLE actor_messages DROP COLUMN IF EXISTS entity_id;
+ALTER TABLE actor_messages DROP COLUMN IF EXISTS entity_type;
+
+DROP INDEX IF EXISTS idx_actor_instances_entity;
+CREATE INDEX idx_actor_instances_entity ON actor_instances(entity_id);
+
+ALTER TABLE actor_instances DROP COLUMN IF EXISTS entity_type;
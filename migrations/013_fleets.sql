@@ -0,0 +1,25 @@
+-- +migrate Up
+-- Fleet partners operate groups of drivers. Operators authenticate with an API key, of which
+-- only the SHA-256 hash is stored, and bulk manage their drivers' statuses.
+
+CREATE TABLE fleets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    api_key_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TRIGGER update_fleets_updated_at BEFORE UPDATE ON fleets
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+ALTER TABLE drivers ADD COLUMN fleet_id UUID CONSTRAINT drivers_fleet_id_fkey REFERENCES fleets(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_drivers_fleet_id ON drivers(fleet_id) WHERE fleet_id IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_drivers_fleet_id;
+ALTER TABLE drivers DROP COLUMN IF EXISTS fleet_id;
+
+DROP TRIGGER IF EXISTS update_fleets_updated_at ON fleets;
+DROP TABLE IF EXISTS fleets;
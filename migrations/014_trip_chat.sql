@@ -0,0 +1,22 @@
+-- +migrate Up
+-- In-trip chat between a trip's passenger and driver. Messages are stored after the profanity
+-- and PII filters ran, cascade with their trip and are purged once the trip has been over for
+-- the chat retention period.
+
+CREATE TABLE trip_chat_messages (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    sender_role VARCHAR(20) NOT NULL CHECK (sender_role IN ('passenger', 'driver')),
+    sender_id UUID NOT NULL,
+    body TEXT NOT NULL,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_trip_chat_messages_trip_id ON trip_chat_messages(trip_id, created_at);
+CREATE INDEX idx_trip_chat_messages_unread ON trip_chat_messages(trip_id, sender_role) WHERE read_at IS NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_trip_chat_messages_unread;
+DROP INDEX IF EXISTS idx_trip_chat_messages_trip_id;
+DROP TABLE IF EXISTS trip_chat_messages;
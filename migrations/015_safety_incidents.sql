@@ -0,0 +1,36 @@
+-- +migrate Up
+-- Safety incidents: SOS reports raised by a trip's passenger or driver, with a location and a
+-- snapshot of the trip's data, reviewed by admins. Trips with incidents cannot be deleted, and
+-- their chat is not purged while an incident awaits review.
+
+CREATE TABLE safety_incidents (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL CONSTRAINT safety_incidents_trip_id_fkey REFERENCES trips(id) ON DELETE RESTRICT,
+    reporter_type VARCHAR(20) NOT NULL CHECK (reporter_type IN ('passenger', 'driver')),
+    reporter_id UUID NOT NULL,
+    latitude DECIMAL(10,8),
+    longitude DECIMAL(11,8),
+    location_source VARCHAR(20) CHECK (location_source IN ('reporter', 'driver')),
+    trip_status VARCHAR(20) NOT NULL,
+    note TEXT,
+    snapshot JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'under_review', 'closed')),
+    reviewed_by VARCHAR(255),
+    resolution_note TEXT,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_safety_incidents_trip_id ON safety_incidents(trip_id);
+CREATE INDEX idx_safety_incidents_status ON safety_incidents(status, created_at);
+
+CREATE TRIGGER update_safety_incidents_updated_at BEFORE UPDATE ON safety_incidents
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS update_safety_incidents_updated_at ON safety_incidents;
+DROP INDEX IF EXISTS idx_safety_incidents_status;
+DROP INDEX IF EXISTS idx_safety_incidents_trip_id;
+DROP TABLE IF EXISTS safety_incidents;
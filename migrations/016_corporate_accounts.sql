@@ -0,0 +1,39 @@
+-- +migrate Up
+-- Corporate accounts pay for the rides of their passengers. A passenger belongs to at most one
+-- account and chooses per ride whether to bill it. Each billed trip has a charge row carrying
+-- the fare estimated at request time, which counts towards the account's monthly spending
+-- limit until the trip completes with its final fare; cancelled trips cost nothing.
+-- Charging a trip locks its account row with a no-op update, so corporate_accounts has no
+-- updated_at trigger; the application sets updated_at itself.
+
+CREATE TABLE corporate_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    monthly_spending_limit DECIMAL(12,2) CHECK (monthly_spending_limit >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE passengers ADD COLUMN corporate_account_id UUID
+    CONSTRAINT passengers_corporate_account_id_fkey REFERENCES corporate_accounts(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_passengers_corporate_account_id ON passengers(corporate_account_id) WHERE corporate_account_id IS NOT NULL;
+
+CREATE TABLE corporate_trip_charges (
+    trip_id UUID PRIMARY KEY REFERENCES trips(id) ON DELETE CASCADE,
+    account_id UUID NOT NULL REFERENCES corporate_accounts(id) ON DELETE RESTRICT,
+    passenger_id UUID NOT NULL REFERENCES passengers(id) ON DELETE CASCADE,
+    estimated_fare DECIMAL(10,2) NOT NULL CHECK (estimated_fare >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_corporate_trip_charges_account ON corporate_trip_charges(account_id, created_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_corporate_trip_charges_account;
+DROP TABLE IF EXISTS corporate_trip_charges;
+
+DROP INDEX IF EXISTS idx_passengers_corporate_account_id;
+ALTER TABLE passengers DROP COLUMN IF EXISTS corporate_account_id;
+
+DROP TABLE IF EXISTS corporate_accounts;
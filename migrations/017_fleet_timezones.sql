@@ -0,0 +1,8 @@
+-- +migrate Up
+-- Fleet timezones: the IANA timezone a fleet operates in. Its daily earnings are grouped by
+-- local dates in this timezone unless the export asks for another one.
+
+ALTER TABLE fleets ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+
+-- +migrate Down
+ALTER TABLE fleets DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,9 @@
+-- +migrate Up
+-- Actor message versions: the schema version of each message payload, so messages recorded
+-- before a message struct changed can be upgraded by the registered converters on replay.
+-- Messages recorded before versioning are at version 1.
+
+ALTER TABLE actor_messages ADD COLUMN message_version INTEGER NOT NULL DEFAULT 1;
+
+-- +migrate Down
+ALTER TABLE actor_messages DROP COLUMN IF EXISTS message_version;
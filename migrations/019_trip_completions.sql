@@ -0,0 +1,27 @@
+-- +migrate Up
+-- Trip completions: the actual dropoff, odometer distance, tolls and extras a driver entered
+-- when completing a trip, reconciled against the requested route. Completions whose distance,
+-- dropoff or fare deviate too much from the route are flagged for review.
+
+CREATE TABLE trip_completions (
+    trip_id UUID PRIMARY KEY REFERENCES trips(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    dropoff_latitude DECIMAL(10,8) NOT NULL,
+    dropoff_longitude DECIMAL(11,8) NOT NULL,
+    odometer_distance_km DECIMAL(8,2) NOT NULL,
+    tolls_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    extras_amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    route_distance_km DECIMAL(8,2) NOT NULL,
+    dropoff_deviation_km DECIMAL(8,2) NOT NULL,
+    billed_distance_km DECIMAL(8,2) NOT NULL,
+    estimated_fare DECIMAL(10,2) NOT NULL,
+    final_fare DECIMAL(10,2) NOT NULL,
+    flagged BOOLEAN NOT NULL DEFAULT FALSE,
+    flag_reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_trip_completions_flagged ON trip_completions(created_at) WHERE flagged;
+
+-- +migrate Down
+DROP TABLE IF EXISTS trip_completions;
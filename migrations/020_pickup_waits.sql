@@ -0,0 +1,25 @@
+-- +migrate Up
+-- Pickup waits: when the driver arrived at the pickup and how the wait ended, with the fee for
+-- making the driver wait past the free period or for not showing up. The zone is the geohash of
+-- the pickup the wait-time metrics are grouped by.
+
+CREATE TABLE pickup_waits (
+    trip_id UUID PRIMARY KEY REFERENCES trips(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    zone VARCHAR(12) NOT NULL,
+    arrived_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    no_show_after TIMESTAMP WITH TIME ZONE NOT NULL,
+    outcome VARCHAR(20) NOT NULL DEFAULT 'waiting' CHECK (outcome IN ('waiting', 'picked_up', 'no_show')),
+    ended_at TIMESTAMP WITH TIME ZONE,
+    wait_seconds INTEGER,
+    wait_fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+    no_show_fee DECIMAL(10,2) NOT NULL DEFAULT 0
+);
+
+CREATE INDEX idx_pickup_waits_arrived_at ON pickup_waits(arrived_at);
+
+ALTER TABLE trip_completions ADD COLUMN wait_fee DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+-- +migrate Down
+ALTER TABLE trip_completions DROP COLUMN IF EXISTS wait_fee;
+DROP TABLE IF EXISTS pickup_waits;
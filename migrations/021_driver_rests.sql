@@ -0,0 +1,19 @@
+-- +migrate Up
+-- Driver rests: drivers reaching the online or driving time limit over the rolling fatigue
+-- window are set offline and cannot go back online until rest_until, when their window
+-- restarts. The time online and driving is derived from driver_status_history.
+
+CREATE TABLE driver_rests (
+    id UUID PRIMARY KEY,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    limit_reached VARCHAR(20) NOT NULL CHECK (limit_reached IN ('online_time', 'driving_time')),
+    online_seconds BIGINT NOT NULL,
+    driving_seconds BIGINT NOT NULL,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    rest_until TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_driver_rests_driver_rest_until ON driver_rests(driver_id, rest_until);
+
+-- +migrate Down
+DROP TABLE IF EXISTS driver_rests;
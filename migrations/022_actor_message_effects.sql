@@ -0,0 +1,9 @@
+-- +migrate Up
+-- Actor message effects: the business entities changed while an actor processed a message,
+-- e.g. [{"entity_type": "trip", "entity_id": "...", "action": "status_changed",
+-- "changes": {"status": "matched"}}], so a message record shows what the message did.
+
+ALTER TABLE actor_messages ADD COLUMN effects JSONB;
+
+-- +migrate Down
+ALTER TABLE actor_messages DROP COLUMN IF EXISTS effects;
@@ -0,0 +1,13 @@
+-- +migrate Up
+-- Trip search indexes for support agents looking up a customer's trips by passenger email
+-- (case-insensitive), driver plate (ignoring case and spaces) or the leading characters of a
+-- trip ID. Phone, request time and pickup location lookups use the existing indexes.
+
+CREATE INDEX idx_users_email_lower ON users (LOWER(email));
+CREATE INDEX idx_drivers_plate_search ON drivers (UPPER(REPLACE(vehicle_plate, ' ', '')));
+CREATE INDEX idx_trips_id_prefix ON trips ((CAST(id AS TEXT)) text_pattern_ops);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_trips_id_prefix;
+DROP INDEX IF EXISTS idx_drivers_plate_search;
+DROP INDEX IF EXISTS idx_users_email_lower;
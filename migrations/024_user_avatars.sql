@@ -0,0 +1,8 @@
+-- +migrate Up
+-- User profile pictures: the storage key of the current picture. Pictures are stored under a new
+-- key on every upload and retrieved by signed URLs, so the key is never exposed as a public URL.
+
+ALTER TABLE users ADD COLUMN avatar_key VARCHAR(255);
+
+-- +migrate Down
+ALTER TABLE users DROP COLUMN IF EXISTS avatar_key;
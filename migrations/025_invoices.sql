@@ -0,0 +1,30 @@
+-- +migrate Up
+-- Monthly invoices of corporate accounts and fleet partners, generated by a batch job as CSV
+-- and PDF files kept in the file storage. A row records the generation status of one party's
+-- invoice for one calendar month (UTC), so failed generations are retried and finished ones
+-- are not generated twice.
+
+CREATE TABLE invoices (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    party_type VARCHAR(20) NOT NULL CHECK (party_type IN ('corporate_account', 'fleet')),
+    party_id UUID NOT NULL,
+    party_name VARCHAR(255) NOT NULL,
+    month CHAR(7) NOT NULL,
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'generated', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    trips INTEGER NOT NULL DEFAULT 0,
+    total_amount DECIMAL(12,2) NOT NULL DEFAULT 0,
+    csv_key VARCHAR(255),
+    pdf_key VARCHAR(255),
+    generated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (party_type, party_id, month)
+);
+
+CREATE INDEX idx_invoices_month_status ON invoices(month, status);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_invoices_month_status;
+DROP TABLE IF EXISTS invoices;
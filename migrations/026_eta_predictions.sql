@@ -0,0 +1,25 @@
+-- +migrate Up
+-- ETA predictions: the ETA to the pickup given when a driver is matched, from the distance and
+-- the average speed the ETA model assumed, and how long the driver actually took to arrive. The
+-- zone is the geohash of the pickup and the hour the hour of day of the match, which accuracy
+-- metrics are grouped by.
+
+CREATE TABLE eta_predictions (
+    trip_id UUID PRIMARY KEY REFERENCES trips(id) ON DELETE CASCADE,
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    zone VARCHAR(12) NOT NULL,
+    hour SMALLINT NOT NULL CHECK (hour BETWEEN 0 AND 23),
+    distance_km DECIMAL(10,3) NOT NULL,
+    speed_kmh DECIMAL(6,2) NOT NULL,
+    predicted_seconds INTEGER NOT NULL,
+    predicted_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    arrived_at TIMESTAMP WITH TIME ZONE,
+    actual_seconds INTEGER,
+    error_seconds INTEGER
+);
+
+CREATE INDEX idx_eta_predictions_predicted_at ON eta_predictions(predicted_at) WHERE arrived_at IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_eta_predictions_predicted_at;
+DROP TABLE IF EXISTS eta_predictions;
@@ -0,0 +1,27 @@
+-- +migrate Up
+-- Passenger ride preferences, matched against what drivers can offer. Accessible vehicles and pet
+-- friendly rides are required of the matched driver; the minimum driver rating and quiet rides
+-- are relaxed when no nearby driver meets them.
+
+ALTER TABLE passengers
+    ADD COLUMN quiet_ride BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN pet_friendly BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN accessible_vehicle BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN min_driver_rating DECIMAL(3,2) CHECK (min_driver_rating BETWEEN 1 AND 5);
+
+ALTER TABLE drivers
+    ADD COLUMN pet_friendly BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN wheelchair_accessible BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN quiet_rides BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +migrate Down
+ALTER TABLE drivers
+    DROP COLUMN IF EXISTS quiet_rides,
+    DROP COLUMN IF EXISTS wheelchair_accessible,
+    DROP COLUMN IF EXISTS pet_friendly;
+
+ALTER TABLE passengers
+    DROP COLUMN IF EXISTS min_driver_rating,
+    DROP COLUMN IF EXISTS accessible_vehicle,
+    DROP COLUMN IF EXISTS pet_friendly,
+    DROP COLUMN IF EXISTS quiet_ride;
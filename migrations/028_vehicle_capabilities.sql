@@ -0,0 +1,9 @@
+-- +migrate Up
+-- Child seats, alongside wheelchair access, as special-assistance capabilities ride requests can
+-- require of the driver's vehicle
+ALTER TABLE drivers
+    ADD COLUMN child_seat BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- +migrate Down
+ALTER TABLE drivers
+    DROP COLUMN IF EXISTS child_seat;
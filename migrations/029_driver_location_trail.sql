@@ -0,0 +1,37 @@
+-- +migrate Up
+-- Driver location trail: every GPS ping reported by drivers, for support and fraud
+-- investigations. The table is partitioned daily by recorded_at like the observability tables;
+-- upcoming partitions are created and expired ones dropped by the partition maintenance job in
+-- internal/retention. Pings are downsampled as they age: a downsampled ping is the last ping of
+-- its driver over resolution_seconds and counts the pings it replaced in samples.
+
+CREATE TABLE driver_location_pings (
+    driver_id UUID NOT NULL,
+    latitude DECIMAL(10, 8) NOT NULL,
+    longitude DECIMAL(11, 8) NOT NULL,
+    recorded_at TIMESTAMP NOT NULL,
+    resolution_seconds INTEGER NOT NULL DEFAULT 0,
+    samples INTEGER NOT NULL DEFAULT 1
+) PARTITION BY RANGE (recorded_at);
+
+CREATE TABLE driver_location_pings_default PARTITION OF driver_location_pings DEFAULT;
+
+CREATE INDEX idx_driver_location_pings_driver ON driver_location_pings(driver_id, recorded_at);
+
+-- +migrate StatementBegin
+-- Create partitions for today and the next seven days
+DO $$
+DECLARE
+    day_start TIMESTAMP := date_trunc('day', CURRENT_TIMESTAMP);
+BEGIN
+    WHILE day_start < date_trunc('day', CURRENT_TIMESTAMP) + INTERVAL '8 days' LOOP
+        PERFORM create_time_partition('driver_location_pings', day_start, day_start + INTERVAL '1 day');
+        day_start := day_start + INTERVAL '1 day';
+    END LOOP;
+END;
+$$;
+-- +migrate StatementEnd
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_driver_location_pings_driver;
+DROP TABLE IF EXISTS driver_location_pings;
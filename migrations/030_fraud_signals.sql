@@ -0,0 +1,34 @@
+-- +migrate Up
+-- Fraud signals: suspicions of fraud raised against a driver or passenger by the rules of the
+-- fraud detection worker, with the evidence found, reviewed by admins. A rule flags a subject at
+-- most once per trip.
+
+CREATE TABLE fraud_signals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    rule VARCHAR(50) NOT NULL CHECK (rule IN ('impossible_speed', 'repeated_cancellations', 'gps_teleport', 'self_referral')),
+    severity VARCHAR(20) NOT NULL CHECK (severity IN ('low', 'medium', 'high')),
+    subject_type VARCHAR(20) NOT NULL CHECK (subject_type IN ('passenger', 'driver')),
+    subject_id UUID NOT NULL,
+    trip_id UUID REFERENCES trips(id) ON DELETE SET NULL,
+    details JSONB,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'confirmed', 'dismissed')),
+    reviewed_by VARCHAR(255),
+    review_note TEXT,
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_fraud_signals_trip ON fraud_signals(rule, trip_id, subject_id) WHERE trip_id IS NOT NULL;
+CREATE INDEX idx_fraud_signals_subject ON fraud_signals(subject_id, rule, status);
+CREATE INDEX idx_fraud_signals_status ON fraud_signals(status, created_at);
+
+CREATE TRIGGER update_fraud_signals_updated_at BEFORE UPDATE ON fraud_signals
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS update_fraud_signals_updated_at ON fraud_signals;
+DROP INDEX IF EXISTS idx_fraud_signals_status;
+DROP INDEX IF EXISTS idx_fraud_signals_subject;
+DROP INDEX IF EXISTS idx_fraud_signals_trip;
+DROP TABLE IF EXISTS fraud_signals;
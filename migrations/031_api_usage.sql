@@ -0,0 +1,29 @@
+-- +migrate Up
+-- API usage analytics: the requests made with every fleet API key, rolled up per key and
+-- minute by each instance, with their errors and latency. Fleets get an optional daily request
+-- quota enforced on their API key.
+
+ALTER TABLE fleets ADD COLUMN daily_request_quota BIGINT CHECK (daily_request_quota > 0);
+
+CREATE TABLE api_usage_rollups (
+    key_id VARCHAR(64) NOT NULL,
+    tenant_id UUID NOT NULL,
+    bucket_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    client_errors BIGINT NOT NULL DEFAULT 0,
+    server_errors BIGINT NOT NULL DEFAULT 0,
+    quota_rejections BIGINT NOT NULL DEFAULT 0,
+    latency_sum_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    latency_max_ms DOUBLE PRECISION NOT NULL DEFAULT 0,
+    PRIMARY KEY (key_id, bucket_start)
+);
+
+CREATE INDEX idx_api_usage_rollups_tenant ON api_usage_rollups(tenant_id, bucket_start);
+CREATE INDEX idx_api_usage_rollups_bucket_start ON api_usage_rollups(bucket_start);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_api_usage_rollups_bucket_start;
+DROP INDEX IF EXISTS idx_api_usage_rollups_tenant;
+DROP TABLE IF EXISTS api_usage_rollups;
+
+ALTER TABLE fleets DROP COLUMN IF EXISTS daily_request_quota;
@@ -0,0 +1,22 @@
+-- +migrate Up
+-- Why trips are cancelled: a reason from a fixed taxonomy replacing the free-text reason, an
+-- optional note, and the mode (actor_model or traditional) of the pipeline that cancelled the trip,
+-- broken down by the cancellation analytics and the daily summary.
+
+ALTER TABLE trips
+    ADD COLUMN cancellation_reason VARCHAR(32) CHECK (cancellation_reason IN (
+        'changed_plans', 'wait_too_long', 'driver_not_moving', 'wrong_pickup',
+        'fare_too_high', 'safety_concern', 'passenger_no_show', 'other'
+    )),
+    ADD COLUMN cancellation_note TEXT,
+    ADD COLUMN cancellation_mode VARCHAR(16);
+
+CREATE INDEX idx_trips_cancelled_at ON trips(cancelled_at) WHERE cancelled_at IS NOT NULL;
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_trips_cancelled_at;
+
+ALTER TABLE trips
+    DROP COLUMN IF EXISTS cancellation_mode,
+    DROP COLUMN IF EXISTS cancellation_note,
+    DROP COLUMN IF EXISTS cancellation_reason;
@@ -0,0 +1,39 @@
+-- +migrate Up
+-- Driver onboarding: the stage each driver has reached on the way to being activated
+-- (registered, documents_submitted, verified, training_completed, activated), with the documents
+-- submitted and when each stage was reached. Drivers must be activated before going online;
+-- drivers registered before onboarding existed are backfilled as activated.
+
+CREATE TABLE driver_onboardings (
+    driver_id UUID PRIMARY KEY REFERENCES drivers(id) ON DELETE CASCADE,
+    stage VARCHAR(32) NOT NULL DEFAULT 'registered' CHECK (stage IN (
+        'registered', 'documents_submitted', 'verified', 'training_completed', 'activated'
+    )),
+    documents TEXT NOT NULL DEFAULT '',
+    registered_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    documents_submitted_at TIMESTAMP WITH TIME ZONE,
+    verified_at TIMESTAMP WITH TIME ZONE,
+    verified_by VARCHAR(255),
+    training_completed_at TIMESTAMP WITH TIME ZONE,
+    activated_at TIMESTAMP WITH TIME ZONE,
+    activated_by VARCHAR(255),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_driver_onboardings_registered_at ON driver_onboardings(registered_at, stage);
+
+CREATE TRIGGER update_driver_onboardings_updated_at BEFORE UPDATE ON driver_onboardings
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO driver_onboardings (
+    driver_id, stage, documents, registered_at, documents_submitted_at, verified_at,
+    training_completed_at, activated_at
+)
+SELECT id, 'activated', 'driver_license,vehicle_registration,insurance', registered_at, registered_at,
+    registered_at, registered_at, registered_at
+FROM (SELECT id, COALESCE(created_at, CURRENT_TIMESTAMP) AS registered_at FROM drivers) AS existing;
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS update_driver_onboardings_updated_at ON driver_onboardings;
+DROP INDEX IF EXISTS idx_driver_onboardings_registered_at;
+DROP TABLE IF EXISTS driver_onboardings;
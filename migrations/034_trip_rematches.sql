@@ -0,0 +1,37 @@
+-- +migrate Up
+-- Trip re-matches: a matched driver cancelling a trip before pickup, and how matching the trip
+-- again without that driver ended. Trips re-matched too often or left without a driver are
+-- cancelled with the new driver_cancelled reason.
+
+ALTER TABLE trips DROP CONSTRAINT IF EXISTS trips_cancellation_reason_check;
+ALTER TABLE trips ADD CONSTRAINT trips_cancellation_reason_check CHECK (cancellation_reason IN (
+    'changed_plans', 'wait_too_long', 'driver_not_moving', 'wrong_pickup',
+    'fare_too_high', 'safety_concern', 'passenger_no_show', 'driver_cancelled', 'other'
+));
+
+CREATE TABLE trip_rematches (
+    id UUID PRIMARY KEY,
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    attempt INTEGER NOT NULL CHECK (attempt > 0),
+    cancelled_driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    cancelled_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    note TEXT,
+    mode VARCHAR(16) NOT NULL,
+    outcome VARCHAR(20) NOT NULL DEFAULT 'matching' CHECK (outcome IN ('matching', 'matched', 'unmatched', 'limit_reached')),
+    matched_driver_id UUID REFERENCES drivers(id) ON DELETE SET NULL,
+    ended_at TIMESTAMP WITH TIME ZONE,
+    added_wait_seconds INTEGER,
+    UNIQUE (trip_id, attempt)
+);
+
+CREATE INDEX idx_trip_rematches_cancelled_at ON trip_rematches(cancelled_at);
+
+-- +migrate Down
+DROP TABLE IF EXISTS trip_rematches;
+
+UPDATE trips SET cancellation_reason = 'other' WHERE cancellation_reason = 'driver_cancelled';
+ALTER TABLE trips DROP CONSTRAINT IF EXISTS trips_cancellation_reason_check;
+ALTER TABLE trips ADD CONSTRAINT trips_cancellation_reason_check CHECK (cancellation_reason IN (
+    'changed_plans', 'wait_too_long', 'driver_not_moving', 'wrong_pickup',
+    'fare_too_high', 'safety_concern', 'passenger_no_show', 'other'
+));
@@ -0,0 +1,48 @@
+-- +migrate Up
+-- Trip payments: the ledger of the charges captured from passengers for their trips and the
+-- refunds given back, and the discrepancies the reconciliation job finds between the ledger and
+-- the fares of completed trips, resolved by admins. A trip has at most one open discrepancy of
+-- each type.
+
+CREATE TABLE trip_payments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('charge', 'refund')),
+    method VARCHAR(20) NOT NULL CHECK (method IN ('card', 'cash', 'wallet', 'corporate')),
+    amount DECIMAL(10,2) NOT NULL CHECK (amount > 0),
+    reference VARCHAR(255),
+    recorded_by VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_trip_payments_trip_id ON trip_payments(trip_id, created_at);
+
+CREATE TABLE payment_discrepancies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    trip_id UUID NOT NULL REFERENCES trips(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('missing_payment', 'amount_mismatch')),
+    expected_amount DECIMAL(10,2) NOT NULL,
+    paid_amount DECIMAL(10,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved', 'dismissed')),
+    resolution VARCHAR(20) CHECK (resolution IN ('record_payment', 'refund', 'write_off', 'dismiss', 'settled')),
+    payment_id UUID REFERENCES trip_payments(id) ON DELETE SET NULL,
+    resolved_by VARCHAR(255),
+    resolution_note TEXT,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    detected_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_payment_discrepancies_open_trip ON payment_discrepancies(trip_id, type) WHERE status = 'open';
+CREATE INDEX idx_payment_discrepancies_status ON payment_discrepancies(status, detected_at);
+
+CREATE TRIGGER update_payment_discrepancies_updated_at BEFORE UPDATE ON payment_discrepancies
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS update_payment_discrepancies_updated_at ON payment_discrepancies;
+DROP INDEX IF EXISTS idx_payment_discrepancies_status;
+DROP INDEX IF EXISTS idx_payment_discrepancies_open_trip;
+DROP TABLE IF EXISTS payment_discrepancies;
+DROP INDEX IF EXISTS idx_trip_payments_trip_id;
+DROP TABLE IF EXISTS trip_payments;
@@ -0,0 +1,36 @@
+-- +migrate Up
+-- Driver trip stats: a rollup of each driver's completed trips, updated in the transaction
+-- completing a trip so that driver profiles need not aggregate the trips table. Earnings are
+-- rolled up per day to sum the rolling 30 day window. Trips completed before the rollup existed
+-- are backfilled.
+
+CREATE TABLE driver_trip_stats (
+    driver_id UUID PRIMARY KEY REFERENCES drivers(id) ON DELETE CASCADE,
+    total_trips INTEGER NOT NULL DEFAULT 0,
+    total_distance_km DECIMAL(12,2) NOT NULL DEFAULT 0,
+    last_trip_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE TABLE driver_daily_earnings (
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    trips INTEGER NOT NULL DEFAULT 0,
+    earnings DECIMAL(12,2) NOT NULL DEFAULT 0,
+    PRIMARY KEY (driver_id, day)
+);
+
+INSERT INTO driver_trip_stats (driver_id, total_trips, total_distance_km, last_trip_at)
+SELECT driver_id, COUNT(*), COALESCE(SUM(distance_km), 0), MAX(completed_at)
+FROM trips
+WHERE status = 'completed' AND driver_id IS NOT NULL
+GROUP BY driver_id;
+
+INSERT INTO driver_daily_earnings (driver_id, day, trips, earnings)
+SELECT driver_id, (COALESCE(completed_at, updated_at) AT TIME ZONE 'UTC')::date, COUNT(*), COALESCE(SUM(fare_amount), 0)
+FROM trips
+WHERE status = 'completed' AND driver_id IS NOT NULL
+GROUP BY driver_id, (COALESCE(completed_at, updated_at) AT TIME ZONE 'UTC')::date;
+
+-- +migrate Down
+DROP TABLE IF EXISTS driver_daily_earnings;
+DROP TABLE IF EXISTS driver_trip_stats;
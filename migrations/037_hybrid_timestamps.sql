@@ -0,0 +1,15 @@
+-- +migrate Up
+-- Hybrid logical timestamps: event logs and actor messages carry the hybrid timestamp of
+-- publishing or sending them, encoded so that they sort in timestamp order, which orders them
+-- across instances whose wall clocks disagree. Records stored before have none and are ordered
+-- by their wall clock timestamps.
+
+ALTER TABLE event_logs ADD COLUMN hlc VARCHAR(128);
+ALTER TABLE actor_messages ADD COLUMN hlc VARCHAR(128);
+
+CREATE INDEX idx_actor_messages_trace_hlc ON actor_messages(trace_id, hlc);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_actor_messages_trace_hlc;
+ALTER TABLE actor_messages DROP COLUMN IF EXISTS hlc;
+ALTER TABLE event_logs DROP COLUMN IF EXISTS hlc;
@@ -0,0 +1,42 @@
+-- +migrate Up
+-- Notify the row_changes channel of every change to drivers and trips, so that instances
+-- invalidate what they cache of the rows and update live streams however the rows changed,
+-- including by manual SQL fixes and other services. Payloads are JSON objects with the table,
+-- operation and row ID, e.g. {"table":"trips","op":"UPDATE","id":"..."}. Driver updates that only
+-- move the driver are left out: location reports are written in batches every few seconds, and the
+-- next report replaces a location changed by hand anyway.
+
+-- +migrate StatementBegin
+CREATE OR REPLACE FUNCTION notify_row_change()
+RETURNS TRIGGER AS $$
+DECLARE
+    row_id UUID;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        row_id := OLD.id;
+    ELSE
+        row_id := NEW.id;
+    END IF;
+    PERFORM pg_notify('row_changes', json_build_object('table', TG_TABLE_NAME, 'op', TG_OP, 'id', row_id)::text);
+    RETURN NULL;
+END;
+$$ language 'plpgsql';
+-- +migrate StatementEnd
+
+CREATE TRIGGER notify_drivers_row_change AFTER INSERT OR DELETE ON drivers
+    FOR EACH ROW EXECUTE FUNCTION notify_row_change();
+
+CREATE TRIGGER notify_drivers_row_update AFTER UPDATE ON drivers
+    FOR EACH ROW
+    WHEN ((to_jsonb(OLD) - ARRAY['current_latitude', 'current_longitude', 'updated_at'])
+        IS DISTINCT FROM (to_jsonb(NEW) - ARRAY['current_latitude', 'current_longitude', 'updated_at']))
+    EXECUTE FUNCTION notify_row_change();
+
+CREATE TRIGGER notify_trips_row_change AFTER INSERT OR UPDATE OR DELETE ON trips
+    FOR EACH ROW EXECUTE FUNCTION notify_row_change();
+
+-- +migrate Down
+DROP TRIGGER IF EXISTS notify_trips_row_change ON trips;
+DROP TRIGGER IF EXISTS notify_drivers_row_update ON drivers;
+DROP TRIGGER IF EXISTS notify_drivers_row_change ON drivers;
+DROP FUNCTION IF EXISTS notify_row_change();
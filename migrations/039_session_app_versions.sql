@@ -0,0 +1,11 @@
+-- +migrate Up
+-- App versions per session: the platform and version the app reported when logging in or last
+-- refreshing the session, to see how many active sessions an old version still has before
+-- raising the minimum version. Sessions started before have neither.
+
+ALTER TABLE sessions ADD COLUMN app_platform VARCHAR(20);
+ALTER TABLE sessions ADD COLUMN app_version VARCHAR(20);
+
+-- +migrate Down
+ALTER TABLE sessions DROP COLUMN IF EXISTS app_version;
+ALTER TABLE sessions DROP COLUMN IF EXISTS app_platform;
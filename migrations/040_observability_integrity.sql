@@ -0,0 +1,29 @@
+-- +migrate Up
+-- Observability integrity checks: the row count and checksum of every observability table per
+-- hour, computed periodically over the last finished hours. Comparing the checksums of
+-- consecutive runs shows hours that lost rows, e.g. to dropped batches or over-eager cleanup.
+
+CREATE TABLE integrity_runs (
+    id UUID PRIMARY KEY,
+    window_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    window_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    total_rows BIGINT NOT NULL DEFAULT 0,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    duration_ms BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE integrity_checksums (
+    run_id UUID NOT NULL REFERENCES integrity_runs(id) ON DELETE CASCADE,
+    table_name VARCHAR(64) NOT NULL,
+    hour TIMESTAMP WITH TIME ZONE NOT NULL,
+    row_count BIGINT NOT NULL,
+    checksum BIGINT NOT NULL,
+    PRIMARY KEY (run_id, table_name, hour)
+);
+
+CREATE INDEX idx_integrity_runs_started_at ON integrity_runs(started_at);
+
+-- +migrate Down
+DROP INDEX IF EXISTS idx_integrity_runs_started_at;
+DROP TABLE IF EXISTS integrity_checksums;
+DROP TABLE IF EXISTS integrity_runs;
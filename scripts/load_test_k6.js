@@ -368,6 +368,35 @@ function testSystemMetrics() {
   }
 }
 
+// Takes a driver through the onboarding stages it has not reached yet, so it can go online when
+// the server requires activation. Drivers without an onboarding are never held back.
+function onboardDriver(driverId) {
+  const progress = http.get(`${BASE_URL}/api/v1/drivers/${driverId}/onboarding`);
+  if (progress.status !== 200) {
+    return;
+  }
+
+  const headers = { 'Content-Type': 'application/json' };
+  const review = JSON.stringify({ reviewed_by: 'k6-load-test' });
+  const steps = {
+    registered: () => http.post(`${BASE_URL}/api/v1/drivers/${driverId}/onboarding/documents`,
+      JSON.stringify({ documents: ['driver_license', 'vehicle_registration', 'insurance'] }), { headers }),
+    documents_submitted: () => http.post(`${BASE_URL}/api/v1/admin/driver-onboarding/${driverId}/verify`, review, { headers }),
+    verified: () => http.post(`${BASE_URL}/api/v1/drivers/${driverId}/onboarding/training`),
+    training_completed: () => http.post(`${BASE_URL}/api/v1/admin/driver-onboarding/${driverId}/activate`, review, { headers }),
+  };
+
+  let stage = progress.json('stage');
+  while (steps[stage]) {
+    const response = steps[stage]();
+    if (response.status !== 200) {
+      console.warn(`Failed to onboard driver ${driverId} at stage ${stage}. Status: ${response.status}`);
+      return;
+    }
+    stage = response.json('stage');
+  }
+}
+
 // Setup function (runs once per VU)
 export function setup() {
   console.log('Starting K6 load test for Actor Model Ride Hailing Observability');
@@ -379,6 +408,8 @@ export function setup() {
     throw new Error(`Server not accessible at ${BASE_URL}. Status: ${response.status}`);
   }
   
+  DRIVER_IDS.forEach(onboardDriver);
+
   console.log('Server is accessible, starting load test...');
 }
 
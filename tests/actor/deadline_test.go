@@ -0,0 +1,34 @@
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/deadline"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorSystem_SendMessageContext_StopsAtDeadline(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+	_, err := system.SpawnActor("matching", "matching-1", 10, func(actor.Message) error { return nil }, actor.SupervisionRestart)
+	require.NoError(t, err)
+
+	ctx, budget, cancel := deadline.WithBudget(context.Background(), time.Minute)
+	defer cancel()
+	require.NoError(t, system.SendMessageContext(ctx, "matching-1", actor.NewBaseMessage("ping", nil, "test")))
+	assert.Equal(t, 1, system.RunUntilIdle())
+	assert.Empty(t, budget.Layer())
+
+	// The actor would work for a requester that stopped waiting
+	expired, budget, cancelExpired := deadline.WithBudget(context.Background(), time.Millisecond)
+	defer cancelExpired()
+	<-expired.Done()
+	err = system.SendMessageContext(expired, "matching-1", actor.NewBaseMessage("ping", nil, "test"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, deadline.LayerActor, budget.Layer())
+	assert.Zero(t, system.RunUntilIdle())
+}
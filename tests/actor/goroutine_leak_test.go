@@ -0,0 +1,57 @@
+package actor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/actor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorSystem_AuditGoroutines(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+	system.SetGoroutineLeakGrace(30 * time.Second)
+
+	spawn := func(id string) *actor.BaseActor {
+		ref, err := system.SpawnActor("driver", id, 10, func(actor.Message) error { return nil }, actor.SupervisionRestart)
+		require.NoError(t, err)
+		return ref.Actor.(*actor.BaseActor)
+	}
+
+	// One actor's goroutine honors its context, the other's ignores it
+	wellBehaved := spawn("driver-ok")
+	wellBehaved.Go("location_poller", func(ctx context.Context) { <-ctx.Done() })
+	release := make(chan struct{})
+	leaky := spawn("driver-leaky")
+	leaky.Go("location_poller", func(context.Context) { <-release })
+
+	audit := system.AuditGoroutines()
+	assert.Equal(t, 2, audit.Tracked)
+	assert.Empty(t, audit.Leaks)
+
+	require.NoError(t, system.StopActor("driver-ok"))
+	require.NoError(t, system.StopActor("driver-leaky"))
+	require.Eventually(t, func() bool { return system.AuditGoroutines().Tracked == 1 }, time.Second, 5*time.Millisecond)
+
+	// Within the grace period the goroutine may still be winding down
+	assert.Empty(t, system.AuditGoroutines().Leaks)
+
+	require.NoError(t, system.AdvanceTime(time.Minute))
+	audit = system.AuditGoroutines()
+	require.Len(t, audit.Leaks, 1)
+	assert.Equal(t, "driver-leaky", audit.Leaks[0].ActorID)
+	assert.Equal(t, "driver", audit.Leaks[0].ActorType)
+	require.Len(t, audit.Leaks[0].Goroutines, 1)
+	assert.Equal(t, "location_poller", audit.Leaks[0].Goroutines[0].Name)
+	assert.Equal(t, map[string]int{"driver": 1}, audit.Leaked)
+	assert.Equal(t, map[string]int{"driver": 1}, system.GetMetrics().Goroutines.Leaked)
+	assert.Positive(t, audit.Total)
+
+	close(release)
+	require.Eventually(t, func() bool { return system.AuditGoroutines().Tracked == 0 }, time.Second, 5*time.Millisecond)
+	assert.Empty(t, system.AuditGoroutines().Leaks)
+	assert.Empty(t, system.GetMetrics().Goroutines.Leaked)
+}
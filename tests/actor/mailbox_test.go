@@ -0,0 +1,53 @@
+package actor
+
+import (
+	"errors"
+	"testing"
+
+	"actor-model-observability/internal/actor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActorSystem_PeekMailbox(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+
+	var received []string
+	_, err := system.SpawnActor("trip", "trip-1", 2, func(msg actor.Message) error {
+		received = append(received, msg.GetPayload().(string))
+		return nil
+	}, actor.SupervisionRestart)
+	require.NoError(t, err)
+
+	require.NoError(t, system.SendMessage("trip-1", actor.NewBaseMessage("ping", "first", "test")))
+	require.NoError(t, system.SendMessage("trip-1", actor.NewBaseMessage("ping", "second", "test")))
+	err = system.SendMessage("trip-1", actor.NewBaseMessage("ping", "third", "test"))
+	assert.True(t, errors.Is(err, actor.ErrMailboxFull))
+
+	// Peeking leaves the messages in the mailbox
+	snapshot, err := system.PeekMailbox("trip-1", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "trip", snapshot.ActorType)
+	assert.Equal(t, 2, snapshot.Capacity)
+	assert.Equal(t, 2, snapshot.Pending)
+	require.Len(t, snapshot.Messages, 1)
+	assert.Equal(t, "first", snapshot.Messages[0].GetPayload())
+
+	assert.True(t, system.Step())
+	assert.Equal(t, []string{"first"}, received)
+	snapshot, err = system.PeekMailbox("trip-1", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, snapshot.Pending)
+	require.Len(t, snapshot.Messages, 1)
+	assert.Equal(t, "second", snapshot.Messages[0].GetPayload())
+
+	system.RunUntilIdle()
+	snapshot, err = system.PeekMailbox("trip-1", 0)
+	require.NoError(t, err)
+	assert.Zero(t, snapshot.Pending)
+	assert.Empty(t, snapshot.Messages)
+
+	_, err = system.PeekMailbox("trip-2", 0)
+	assert.True(t, errors.Is(err, actor.ErrActorNotFound))
+}
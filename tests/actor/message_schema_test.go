@@ -0,0 +1,106 @@
+package actor
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"actor-model-observability/internal/actor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// renameField returns a converter moving a payload field to a new name
+func renameField(from, to string) actor.MessageConverter {
+	return func(payload json.RawMessage) (json.RawMessage, error) {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(payload, &fields); err != nil {
+			return nil, err
+		}
+		fields[to] = fields[from]
+		delete(fields, from)
+		return json.Marshal(fields)
+	}
+}
+
+func TestMessageSchemas_Upgrade(t *testing.T) {
+	schemas := actor.NewMessageSchemas()
+	assert.Equal(t, actor.InitialMessageVersion, schemas.Version("ride_request"))
+
+	require.NoError(t, schemas.Register("ride_request", 1, renameField("pickup", "pickup_addr")))
+	require.NoError(t, schemas.Register("ride_request", 2, renameField("fare", "estimated_fare")))
+	assert.Error(t, schemas.Register("ride_request", 1, renameField("a", "b")), "converters must start at the current version")
+	assert.Equal(t, 3, schemas.Version("ride_request"))
+
+	// Envelopes persisted before versioning are at the initial version
+	payload, version, err := schemas.Upgrade("ride_request", 0, json.RawMessage(`{"pickup":"Main St","fare":12.5}`))
+	require.NoError(t, err)
+	assert.Equal(t, 3, version)
+	assert.JSONEq(t, `{"pickup_addr":"Main St","estimated_fare":12.5}`, string(payload))
+
+	payload, version, err = schemas.Upgrade("ride_request", 2, json.RawMessage(`{"pickup_addr":"Main St","fare":8}`))
+	require.NoError(t, err)
+	assert.Equal(t, 3, version)
+	assert.JSONEq(t, `{"pickup_addr":"Main St","estimated_fare":8}`, string(payload))
+
+	_, _, err = schemas.Upgrade("ride_request", 4, json.RawMessage(`{}`))
+	assert.Error(t, err, "versions from the future cannot be processed")
+
+	assert.Equal(t, []actor.MessageConversion{
+		{MessageType: "ride_request", FromVersion: 1, ToVersion: 2, Count: 1},
+		{MessageType: "ride_request", FromVersion: 2, ToVersion: 3, Count: 2},
+	}, schemas.Conversions())
+}
+
+func TestMessageSchemas_Decode(t *testing.T) {
+	schemas := actor.NewMessageSchemas()
+	require.NoError(t, schemas.Register("trip_completed", 1, renameField("amount", "fare")))
+
+	message, err := schemas.Decode([]byte(`{"id":"msg-1","type":"trip_completed","payload":{"amount":20},"sender":"trip-1","entity_type":"trip","entity_id":"trip-1"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "msg-1", message.ID)
+	assert.Equal(t, 2, message.Version)
+	assert.JSONEq(t, `{"fare":20}`, string(message.Payload.(json.RawMessage)))
+	entityType, entityID := message.GetEntity()
+	assert.Equal(t, "trip", entityType)
+	assert.Equal(t, "trip-1", entityID)
+
+	failing := actor.NewMessageSchemas()
+	require.NoError(t, failing.Register("trip_completed", 1, func(json.RawMessage) (json.RawMessage, error) {
+		return nil, errors.New("unsupported payload")
+	}))
+	_, err = failing.Decode([]byte(`{"id":"msg-2","type":"trip_completed","payload":{}}`))
+	assert.Error(t, err)
+}
+
+func TestActorSystem_SendMessageUpgradesPersistedPayloads(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+	require.NoError(t, system.MessageSchemas().Register("ride_request", 1, renameField("pickup", "pickup_addr")))
+
+	var received []actor.Message
+	_, err := system.SpawnActor("driver", "driver-1", 10, func(msg actor.Message) error {
+		received = append(received, msg)
+		return nil
+	}, actor.SupervisionRestart)
+	require.NoError(t, err)
+
+	// A message built in process is stamped with the current version
+	fresh := actor.NewBaseMessage("ride_request", map[string]string{"pickup_addr": "Main St"}, "passenger-1")
+	require.NoError(t, system.SendMessage("driver-1", fresh))
+
+	// A message read back from storage carries raw JSON at an older version
+	replayed := actor.NewBaseMessage("ride_request", json.RawMessage(`{"pickup":"Elm St"}`), "passenger-2")
+	replayed.Version = 1
+	require.NoError(t, system.SendMessage("driver-1", replayed))
+
+	system.RunUntilIdle()
+	require.Len(t, received, 2)
+	assert.Equal(t, 2, received[0].GetVersion())
+	assert.Equal(t, 2, received[1].GetVersion())
+	assert.JSONEq(t, `{"pickup_addr":"Elm St"}`, string(received[1].GetPayload().(json.RawMessage)))
+
+	assert.Equal(t, []actor.MessageConversion{
+		{MessageType: "ride_request", FromVersion: 1, ToVersion: 2, Count: 1},
+	}, system.GetMetrics().MessageConversions)
+}
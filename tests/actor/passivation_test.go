@@ -0,0 +1,196 @@
+package actor
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// counterState is actor state counting messages, released while passivated
+type counterState struct {
+	count     int
+	released  bool
+	snapshots int
+}
+
+func (s *counterState) Snapshot() ([]byte, error) {
+	s.snapshots++
+	snapshot := []byte(strconv.Itoa(s.count))
+	s.count, s.released = 0, true
+	return snapshot, nil
+}
+
+func (s *counterState) Restore(snapshot []byte) error {
+	count, err := strconv.Atoi(string(snapshot))
+	s.count, s.released = count, false
+	return err
+}
+
+func TestSimulatedActorSystem_PassivatesIdleActors(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+	system.SetDefaultIdleTimeout(time.Minute)
+	system.SetDedupWindow(time.Hour)
+
+	state := &counterState{}
+	_, err := system.SpawnActor("passenger", "passenger-a", 10, func(msg actor.Message) error {
+		state.count++
+		return nil
+	}, actor.SupervisionRestart, actor.WithPassivator(state))
+	require.NoError(t, err)
+	_, err = system.SpawnActor("passenger", "passenger-b", 10, func(actor.Message) error { return nil }, actor.SupervisionRestart)
+	require.NoError(t, err)
+
+	ride := actor.NewBaseMessage("request_ride", "trip-1", "test")
+	require.NoError(t, system.SendMessage("passenger-a", ride))
+	require.NoError(t, system.AdvanceTime(40*time.Second))
+	require.NoError(t, system.SendMessage("passenger-b", actor.NewBaseMessage("ping", nil, "test")))
+
+	// passenger-a has been idle for a minute at the tick after it, passenger-b not yet
+	require.NoError(t, system.AdvanceTime(25*time.Second))
+	assert.True(t, system.IsPassivated("passenger-a"))
+	assert.True(t, state.released)
+	assert.False(t, system.IsPassivated("passenger-b"))
+	assert.True(t, system.HasActor("passenger-a"))
+	_, err = system.GetActor("passenger-a")
+	assert.ErrorIs(t, err, actor.ErrActorNotFound)
+
+	metrics := system.GetMetrics()
+	assert.Equal(t, 1, metrics.ActiveActors)
+	assert.Equal(t, actor.PassivationMetrics{IdleTimeout: time.Minute, Passivated: 1, Passivations: 1}, metrics.Passivation["passenger"])
+
+	// The next message revives the actor with its state
+	require.NoError(t, system.SendMessage("passenger-a", actor.NewBaseMessage("cancel_ride", "trip-1", "test")))
+	system.RunUntilIdle()
+	assert.False(t, system.IsPassivated("passenger-a"))
+	assert.False(t, state.released)
+	assert.Equal(t, 2, state.count)
+
+	// and the messages it processed before are still deduplicated
+	require.NoError(t, system.SendMessage("passenger-a", ride))
+	system.RunUntilIdle()
+	assert.Equal(t, 2, state.count)
+
+	metrics = system.GetMetrics()
+	assert.Equal(t, 2, metrics.ActiveActors)
+	assert.Equal(t, int64(1), metrics.Passivation["passenger"].Revivals)
+	assert.Equal(t, 0, metrics.Passivation["passenger"].Passivated)
+
+	// Both are passivated once idle again
+	require.NoError(t, system.AdvanceTime(2*time.Minute))
+	assert.True(t, system.IsPassivated("passenger-a"))
+	assert.True(t, system.IsPassivated("passenger-b"))
+	assert.Equal(t, int64(3), system.GetMetrics().Passivation["passenger"].Passivations)
+	assert.Equal(t, 2, state.snapshots)
+}
+
+func TestSimulatedActorSystem_PassivationByType(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+	system.SetDefaultIdleTimeout(time.Minute)
+	system.SetIdleTimeout("driver", 0)
+	require.NoError(t, system.WarmPool("trip", 1, 10, actor.SupervisionRestart))
+
+	noop := func(actor.Message) error { return nil }
+	_, err := system.SpawnActor("driver", "driver-a", 10, noop, actor.SupervisionRestart)
+	require.NoError(t, err)
+	_, err = system.SpawnActor("passenger", "passenger-a", 10, noop, actor.SupervisionRestart)
+	require.NoError(t, err)
+
+	require.NoError(t, system.AdvanceTime(5*time.Minute))
+	assert.False(t, system.IsPassivated("driver-a"))
+	assert.True(t, system.IsPassivated("passenger-a"))
+	// Pooled actors are never passivated
+	assert.Equal(t, 1, system.GetMetrics().Pools["trip"].Size)
+	assert.Len(t, system.ListActors(), 2)
+
+	// Broadcasts reach passivated actors too
+	received := 0
+	_, err = system.SpawnActor("passenger", "passenger-b", 10, func(actor.Message) error {
+		received++
+		return nil
+	}, actor.SupervisionRestart)
+	require.NoError(t, err)
+	require.NoError(t, system.BroadcastMessage("passenger", actor.NewBaseMessage("surge_update", nil, "test")))
+	system.RunUntilIdle()
+	assert.Equal(t, 1, received)
+	assert.False(t, system.IsPassivated("passenger-a"))
+
+	// Stopping a passivated actor drops it
+	require.NoError(t, system.AdvanceTime(5*time.Minute))
+	require.True(t, system.IsPassivated("passenger-a"))
+	require.NoError(t, system.StopActor("passenger-a"))
+	assert.False(t, system.HasActor("passenger-a"))
+	assert.ErrorIs(t, system.SendMessage("passenger-a", actor.NewBaseMessage("ping", nil, "test")), actor.ErrActorNotFound)
+}
+
+func TestSimulatedActorSystem_PassivationKeepsActorState(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+	system.SetDefaultIdleTimeout(time.Minute)
+	noop := func(actor.Message) error { return nil }
+
+	passenger, err := actor.NewPassengerActor(&models.Passenger{ID: uuid.New(), UserID: uuid.New(), Rating: 4.5, TotalTrips: 3}, system)
+	require.NoError(t, err)
+	_, err = system.SpawnActor("passenger", passenger.GetID(), 10, noop, actor.SupervisionRestart, actor.WithPassivator(passenger))
+	require.NoError(t, err)
+
+	lat, lng := -6.2, 106.8
+	driver, err := actor.NewDriverActor(&models.Driver{ID: uuid.New(), UserID: uuid.New(), LicenseNumber: "LIC-1", Status: models.DriverStatusOnline,
+		CurrentLatitude: &lat, CurrentLongitude: &lng, Rating: 4.8, TotalTrips: 12}, system)
+	require.NoError(t, err)
+	_, err = system.SpawnActor("driver", driver.GetID(), 10, noop, actor.SupervisionRestart, actor.WithPassivator(driver))
+	require.NoError(t, err)
+
+	trip := actor.NewTripActor(&models.Trip{ID: uuid.New(), PassengerID: passenger.GetPassenger().ID, Status: models.TripStatusRequested})
+	_, err = system.SpawnActor("trip", "trip-a", 10, trip.HandleMessage, actor.SupervisionRestart, actor.WithPassivator(trip))
+	require.NoError(t, err)
+	matchedAt := time.Date(2024, 1, 1, 0, 0, 30, 0, time.UTC)
+	require.NoError(t, system.SendMessage("trip-a", actor.NewBaseMessage(actor.MsgTypeRideMatched,
+		actor.RideMatchedPayload{TripID: trip.Trip().ID.String(), DriverID: driver.GetDriver().ID.String(), MatchedAt: matchedAt}, "test")))
+	system.RunUntilIdle()
+
+	wantPassenger, wantDriver := *passenger.GetPassenger(), *driver.GetDriver()
+	require.NoError(t, system.AdvanceTime(2*time.Minute))
+	for _, id := range []string{passenger.GetID(), driver.GetID(), "trip-a"} {
+		require.True(t, system.IsPassivated(id), id)
+	}
+	// The state is released while passivated
+	assert.Nil(t, passenger.GetPassenger())
+	assert.Nil(t, driver.GetDriver())
+	assert.Nil(t, trip.Trip())
+
+	// and restored when a message revives the actors
+	for _, id := range []string{passenger.GetID(), driver.GetID()} {
+		require.NoError(t, system.SendMessage(id, actor.NewBaseMessage("ping", nil, "test")))
+	}
+	require.NoError(t, system.SendMessage("trip-a", actor.NewBaseMessage(actor.MsgTypeRideStarted,
+		actor.RideStartedPayload{StartedAt: matchedAt.Add(5 * time.Minute)}, "test")))
+	system.RunUntilIdle()
+
+	require.NotNil(t, passenger.GetPassenger())
+	assert.Equal(t, wantPassenger.ID, passenger.GetPassenger().ID)
+	assert.Equal(t, 3, passenger.GetPassenger().TotalTrips)
+	assert.Equal(t, 4.5, passenger.GetPassenger().Rating)
+
+	restored := driver.GetDriver()
+	require.NotNil(t, restored)
+	assert.Equal(t, wantDriver.ID, restored.ID)
+	assert.Equal(t, models.DriverStatusOnline, restored.Status)
+	require.NotNil(t, restored.CurrentLatitude)
+	assert.Equal(t, lat, *restored.CurrentLatitude)
+	assert.Equal(t, 12, restored.TotalTrips)
+
+	state := trip.Trip()
+	require.NotNil(t, state)
+	assert.Equal(t, models.TripStatusInProgress, state.Status, "messages after the revival apply to the restored state")
+	require.NotNil(t, state.DriverID)
+	assert.Equal(t, wantDriver.ID, *state.DriverID)
+	require.NotNil(t, state.MatchedAt)
+	assert.True(t, matchedAt.Equal(*state.MatchedAt))
+	assert.Equal(t, int64(0), system.GetMetrics().Passivation["trip"].Failures)
+}
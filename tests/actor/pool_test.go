@@ -0,0 +1,151 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"actor-model-observability/internal/actor"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarmPool_AcquireUsesPooledActorsBeforeSpawning(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+	require.NoError(t, system.WarmPool("trip", 2, 10, actor.SupervisionRestart))
+
+	metrics := system.GetMetrics()
+	assert.Equal(t, actor.PoolMetrics{Size: 2}, metrics.Pools["trip"])
+	assert.Equal(t, int64(2), metrics.Spawns["trip"].Spawned)
+
+	var first, second []string
+	a, err := system.AcquireActor("trip", func(msg actor.Message) error {
+		first = append(first, msg.GetPayload().(string))
+		return nil
+	}, actor.ForEntity("trip", "trip-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "trip-1", a.EntityID)
+
+	_, err = system.AcquireActor("trip", func(actor.Message) error { return nil })
+	require.NoError(t, err)
+
+	// The pool is exhausted, so the third actor is spawned on demand
+	c, err := system.AcquireActor("trip", func(actor.Message) error { return nil })
+	require.NoError(t, err)
+
+	metrics = system.GetMetrics()
+	assert.Equal(t, actor.PoolMetrics{Size: 2, InUse: 2, Utilization: 1, Hits: 2, Misses: 1}, metrics.Pools["trip"])
+	assert.Equal(t, int64(3), metrics.Spawns["trip"].Spawned)
+	assert.Equal(t, 3, metrics.Spawns["trip"].Active)
+
+	require.NoError(t, system.SendMessage(a.ID, actor.NewBaseMessage("ping", "one", "test")))
+	system.RunUntilIdle()
+	assert.Equal(t, []string{"one"}, first)
+
+	// A released pooled actor is idle again and no longer delivers to its previous holder
+	require.NoError(t, system.ReleaseActor(a.ID))
+	assert.Empty(t, a.EntityID)
+	require.NoError(t, system.SendMessage(a.ID, actor.NewBaseMessage("ping", "two", "test")))
+	system.RunUntilIdle()
+	assert.Equal(t, []string{"one"}, first)
+
+	b, err := system.AcquireActor("trip", func(msg actor.Message) error {
+		second = append(second, msg.GetPayload().(string))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, a.ID, b.ID)
+	require.NoError(t, system.SendMessage(b.ID, actor.NewBaseMessage("ping", "three", "test")))
+	system.RunUntilIdle()
+	assert.Equal(t, []string{"three"}, second)
+
+	// Actors spawned on demand are stopped on release
+	require.NoError(t, system.ReleaseActor(c.ID))
+	_, err = system.GetActor(c.ID)
+	assert.Error(t, err)
+	assert.Equal(t, 2, system.GetMetrics().Spawns["trip"].Active)
+
+	assert.Error(t, system.ReleaseActor(a.ID+"-unknown"))
+}
+
+func TestWarmPool_ReleasingIdleActorFails(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+	require.NoError(t, system.WarmPool("matching", 1, 10, actor.SupervisionRestart))
+
+	ref, err := system.AcquireActor("matching", func(actor.Message) error { return nil })
+	require.NoError(t, err)
+	require.NoError(t, system.ReleaseActor(ref.ID))
+	assert.Error(t, system.ReleaseActor(ref.ID))
+}
+
+func TestWarmPool_RequiresStartedSystem(t *testing.T) {
+	system := actor.NewActorSystem("stopped")
+	assert.Error(t, system.WarmPool("trip", 1, 10, actor.SupervisionRestart))
+}
+
+func TestWarmPool_StoppedPooledActorLeavesPool(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+	require.NoError(t, system.WarmPool("trip", 2, 10, actor.SupervisionRestart))
+
+	ref, err := system.AcquireActor("trip", func(actor.Message) error { return nil })
+	require.NoError(t, err)
+	require.NoError(t, system.StopActor(ref.ID))
+
+	assert.Equal(t, actor.PoolMetrics{Size: 1, Hits: 1}, system.GetMetrics().Pools["trip"])
+
+	// Warming again replaces it
+	require.NoError(t, system.WarmPool("trip", 2, 10, actor.SupervisionRestart))
+	assert.Equal(t, 2, system.GetMetrics().Pools["trip"].Size)
+}
+
+func TestActorLimit_RejectsSpawnsBeyondLimit(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+	system.SetActorLimit("matching", 2)
+	require.NoError(t, system.WarmPool("matching", 1, 10, actor.SupervisionRestart))
+
+	_, err := system.AcquireActor("matching", func(actor.Message) error { return nil })
+	require.NoError(t, err)
+	spawned, err := system.AcquireActor("matching", func(actor.Message) error { return nil })
+	require.NoError(t, err)
+
+	_, err = system.AcquireActor("matching", func(actor.Message) error { return nil })
+	assert.True(t, errors.Is(err, actor.ErrActorLimitReached))
+	_, err = system.SpawnActor("matching", "matching-extra", 10, func(actor.Message) error { return nil }, actor.SupervisionRestart)
+	assert.True(t, errors.Is(err, actor.ErrActorLimitReached))
+
+	// Other types are not limited
+	_, err = system.SpawnActor("trip", "trip-extra", 10, func(actor.Message) error { return nil }, actor.SupervisionRestart)
+	assert.NoError(t, err)
+
+	spawns := system.GetMetrics().Spawns["matching"]
+	assert.Equal(t, int64(2), spawns.Spawned)
+	assert.Equal(t, int64(2), spawns.Rejected)
+	assert.Equal(t, 2, spawns.Limit)
+
+	// Releasing an on-demand actor frees a slot
+	require.NoError(t, system.ReleaseActor(spawned.ID))
+	_, err = system.AcquireActor("matching", func(actor.Message) error { return nil })
+	assert.NoError(t, err)
+
+	system.SetActorLimit("matching", 0)
+	_, err = system.SpawnActor("matching", "matching-unlimited", 10, func(actor.Message) error { return nil }, actor.SupervisionRestart)
+	assert.NoError(t, err)
+}
+
+func TestActorSystem_RecordsSpawnLatency(t *testing.T) {
+	system := actor.NewActorSystem("latency")
+	require.NoError(t, system.Start(context.Background()))
+	t.Cleanup(func() { _ = system.Stop() })
+
+	for _, id := range []string{"driver-a", "driver-b"} {
+		_, err := system.SpawnActor("driver", id, 10, func(actor.Message) error { return nil }, actor.SupervisionRestart)
+		require.NoError(t, err)
+	}
+
+	spawns := system.GetMetrics().Spawns["driver"]
+	assert.Equal(t, int64(2), spawns.Spawned)
+	assert.Positive(t, spawns.MaxLatency)
+	assert.Positive(t, spawns.AverageLatency)
+	assert.LessOrEqual(t, spawns.AverageLatency, spawns.MaxLatency)
+}
@@ -0,0 +1,303 @@
+package actor
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSimulatedSystem(t *testing.T) (*actor.ActorSystem, *actor.SimulatedClock) {
+	clock := actor.NewSimulatedClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	system := actor.NewSimulatedActorSystem("test", clock)
+	require.NoError(t, system.Start(context.Background()))
+	t.Cleanup(func() { _ = system.Stop() })
+	return system, clock
+}
+
+func TestSimulatedActorSystem_ProcessesMessagesInSendOrder(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+
+	var received []string
+	record := func(msg actor.Message) error {
+		received = append(received, msg.GetPayload().(string))
+		return nil
+	}
+	_, err := system.SpawnActor("driver", "driver-a", 10, record, actor.SupervisionRestart)
+	require.NoError(t, err)
+	_, err = system.SpawnActor("driver", "driver-b", 10, record, actor.SupervisionRestart)
+	require.NoError(t, err)
+
+	require.NoError(t, system.SendMessage("driver-b", actor.NewBaseMessage("ping", "b1", "test")))
+	require.NoError(t, system.SendMessage("driver-a", actor.NewBaseMessage("ping", "a1", "test")))
+	require.NoError(t, system.SendMessage("driver-b", actor.NewBaseMessage("ping", "b2", "test")))
+
+	// Nothing runs until the scheduler is driven
+	assert.Empty(t, received)
+
+	assert.Equal(t, 3, system.RunUntilIdle())
+	assert.Equal(t, []string{"b1", "a1", "b2"}, received)
+}
+
+func TestSimulatedActorSystem_ScheduledMatchingTimeout(t *testing.T) {
+	system, clock := newSimulatedSystem(t)
+
+	var timeouts []time.Time
+	_, err := system.SpawnActor("ride_manager", "ride-manager", 10, func(msg actor.Message) error {
+		if msg.GetType() == "matching_timeout" {
+			timeouts = append(timeouts, clock.Now())
+		}
+		return nil
+	}, actor.SupervisionRestart)
+	require.NoError(t, err)
+
+	start := clock.Now()
+	system.ScheduleMessage(30*time.Second, "ride-manager", actor.NewBaseMessage("matching_timeout", nil, "test"))
+	cancelled := system.ScheduleMessage(10*time.Second, "ride-manager", actor.NewBaseMessage("matching_timeout", nil, "test"))
+	assert.True(t, cancelled.Stop())
+
+	require.NoError(t, system.AdvanceTime(29*time.Second))
+	assert.Empty(t, timeouts)
+
+	require.NoError(t, system.AdvanceTime(time.Second))
+	require.Len(t, timeouts, 1)
+	assert.Equal(t, start.Add(30*time.Second), timeouts[0])
+}
+
+func TestSimulatedActorSystem_HeartbeatExpiry(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+	system.SetHeartbeatTimeout(10 * time.Second)
+
+	var failed []string
+	system.SetEventHandlers(nil, nil, func(actorID string, err error) {
+		assert.True(t, errors.Is(err, actor.ErrHeartbeatExpired))
+		failed = append(failed, actorID)
+	}, nil)
+
+	noop := func(actor.Message) error { return nil }
+	_, err := system.SpawnActor("driver", "driver-alive", 10, noop, actor.SupervisionRestart)
+	require.NoError(t, err)
+	_, err = system.SpawnActor("driver", "driver-silent", 10, noop, actor.SupervisionRestart)
+	require.NoError(t, err)
+
+	// Heartbeats are checked on the 5s metrics tick
+	require.NoError(t, system.AdvanceTime(5*time.Second))
+	require.NoError(t, system.Heartbeat("driver-alive"))
+	require.NoError(t, system.AdvanceTime(5*time.Second))
+	assert.Equal(t, []string{"driver-silent"}, failed)
+
+	// Expiry is reported once until the actor heartbeats again
+	require.NoError(t, system.AdvanceTime(10*time.Second))
+	assert.Equal(t, []string{"driver-silent", "driver-alive"}, failed)
+
+	assert.Error(t, system.Heartbeat("unknown"))
+}
+
+func TestActorSystem_AdvanceTimeRequiresSimulation(t *testing.T) {
+	system := actor.NewActorSystem("real")
+	assert.Error(t, system.AdvanceTime(time.Second))
+	assert.False(t, system.Step())
+}
+
+func TestSimulatedActorSystem_DropsRedeliveredMessages(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+	system.SetDedupWindow(time.Minute)
+
+	fees := 0
+	ref, err := system.SpawnActor("passenger", "passenger-a", 10, func(msg actor.Message) error {
+		fees++
+		return nil
+	}, actor.SupervisionRestart)
+	require.NoError(t, err)
+
+	cancellation := actor.NewBaseMessage("cancel_ride", "trip-1", "test")
+	require.NoError(t, system.SendMessage("passenger-a", cancellation))
+	require.NoError(t, system.SendMessage("passenger-a", cancellation))
+	system.RunUntilIdle()
+
+	assert.Equal(t, 1, fees)
+	assert.Equal(t, int64(1), ref.Actor.GetMetrics().MessagesDuplicated)
+
+	// Once the window has passed the ID is forgotten
+	require.NoError(t, system.AdvanceTime(2*time.Minute))
+	require.NoError(t, system.SendMessage("passenger-a", cancellation))
+	system.RunUntilIdle()
+	assert.Equal(t, 2, fees)
+	assert.Equal(t, int64(1), system.GetMetrics().DuplicateMessages)
+
+	// The count covers actors no longer running
+	require.NoError(t, system.StopActor("passenger-a"))
+	require.NoError(t, system.AdvanceTime(2*time.Minute))
+	assert.Equal(t, int64(1), system.GetMetrics().DuplicateMessages)
+}
+
+func TestSimulatedActorSystem_RetriesFailedMessages(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+
+	attempts := 0
+	_, err := system.SpawnActor("driver", "driver-a", 10, func(msg actor.Message) error {
+		attempts++
+		if attempts == 1 {
+			return errors.New("transient failure")
+		}
+		return nil
+	}, actor.SupervisionRestart)
+	require.NoError(t, err)
+
+	message := actor.NewBaseMessage("complete_ride", "trip-1", "test")
+	require.NoError(t, system.SendMessage("driver-a", message))
+	require.NoError(t, system.SendMessage("driver-a", message))
+	require.NoError(t, system.SendMessage("driver-a", message))
+	system.RunUntilIdle()
+
+	// The failed first attempt does not count as processed, so the retry is applied once
+	assert.Equal(t, 2, attempts)
+}
+
+func TestActorSystem_LinksActorsAndMessagesToEntities(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+
+	// The started handler can look up the spawned actor and its entity
+	var started *actor.ActorRef
+	system.SetEventHandlers(func(actorID string) {
+		ref, err := system.GetActor(actorID)
+		require.NoError(t, err)
+		started = ref
+	}, nil, nil, nil)
+
+	var failed actor.MessageFailure
+	system.SetMessageFailureHandler(func(failure actor.MessageFailure) {
+		failed = failure
+	})
+
+	_, err := system.SpawnActor("driver", "driver-a", 10, func(msg actor.Message) error {
+		return errors.New("permanent failure")
+	}, actor.SupervisionRestart, actor.ForEntity("driver", "8d1c6a0e-5f43-4b8e-9a57-2f0f4f1c2b10"))
+	require.NoError(t, err)
+	require.NotNil(t, started)
+	assert.Equal(t, "driver", started.EntityType)
+	assert.Equal(t, "8d1c6a0e-5f43-4b8e-9a57-2f0f4f1c2b10", started.EntityID)
+
+	// The entity travels with the message envelope to the failure handler
+	message := actor.NewBaseMessage("complete_ride", nil, "test").WithEntity("trip", "0c3f1d9a-6b2e-4f7a-8c5d-1e2f3a4b5c6d")
+	require.NoError(t, system.SendMessage("driver-a", message))
+	system.RunUntilIdle()
+
+	require.NotNil(t, failed.Message)
+	entityType, entityID := failed.Message.GetEntity()
+	assert.Equal(t, "trip", entityType)
+	assert.Equal(t, "0c3f1d9a-6b2e-4f7a-8c5d-1e2f3a4b5c6d", entityID)
+}
+
+func TestActorSystem_ReportsEffectsOfProcessedMessages(t *testing.T) {
+	system, _ := newSimulatedSystem(t)
+
+	var results []actor.MessageResult
+	system.SetMessageProcessedHandler(func(result actor.MessageResult) {
+		results = append(results, result)
+	})
+	var failed actor.MessageFailure
+	system.SetMessageFailureHandler(func(failure actor.MessageFailure) {
+		failed = failure
+	})
+
+	tripStarted := models.MessageEffect{
+		EntityType: models.EntityTypeTrip,
+		EntityID:   "trip-1",
+		Action:     "status_changed",
+		Changes:    map[string]interface{}{"status": "in_progress"},
+	}
+	_, err := system.SpawnActor("trip", "trip-manager", 10, func(msg actor.Message) error {
+		actor.RecordEffect(msg, tripStarted)
+		if msg.GetType() == "cancel_ride" {
+			return errors.New("trip already started")
+		}
+		// Forwarding the message hands on the message as it was sent
+		return system.SendMessage("passenger-a", msg)
+	}, actor.SupervisionRestart)
+	require.NoError(t, err)
+	_, err = system.SpawnActor("passenger", "passenger-a", 10, func(msg actor.Message) error {
+		return nil
+	}, actor.SupervisionRestart)
+	require.NoError(t, err)
+
+	require.NoError(t, system.SendMessage("trip-manager", actor.NewBaseMessage("start_ride", "trip-1", "driver-a")))
+	system.RunUntilIdle()
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "trip-manager", results[0].ActorID)
+	assert.Equal(t, "trip", results[0].ActorType)
+	assert.Equal(t, "start_ride", results[0].Message.GetType())
+	assert.Equal(t, models.MessageEffects{tripStarted}, results[0].Effects)
+	assert.Equal(t, "passenger-a", results[1].ActorID)
+	assert.Empty(t, results[1].Effects)
+
+	// Changes made before a handler fails are reported with the failure
+	system.SetRetryPolicy("cancel_ride", actor.RetryPolicy{MaxAttempts: 1})
+	require.NoError(t, system.SendMessage("trip-manager", actor.NewBaseMessage("cancel_ride", "trip-1", "passenger-a")))
+	system.RunUntilIdle()
+
+	assert.True(t, failed.DeadLettered)
+	assert.Equal(t, models.MessageEffects{tripStarted}, failed.Effects)
+	assert.Len(t, results, 2)
+}
+
+func TestSimulatedActorSystem_RetriesWithBackoffBeforeDeadLettering(t *testing.T) {
+	system, clock := newSimulatedSystem(t)
+	system.SetRetryPolicy("complete_ride", actor.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		Multiplier:     2,
+	})
+
+	var attempts []time.Time
+	_, err := system.SpawnActor("driver", "driver-a", 10, func(msg actor.Message) error {
+		attempts = append(attempts, clock.Now())
+		return errors.New("trip manager unavailable")
+	}, actor.SupervisionRestart)
+	require.NoError(t, err)
+
+	var failures []actor.MessageFailure
+	system.SetMessageFailureHandler(func(failure actor.MessageFailure) {
+		failures = append(failures, failure)
+	})
+
+	start := clock.Now()
+	require.NoError(t, system.SendMessage("driver-a", actor.NewBaseMessage("complete_ride", "trip-1", "test")))
+	require.NoError(t, system.AdvanceTime(time.Minute))
+
+	assert.Equal(t, []time.Time{start, start.Add(time.Second), start.Add(3 * time.Second)}, attempts)
+	require.Len(t, failures, 3)
+	assert.False(t, failures[0].DeadLettered)
+	assert.Equal(t, time.Second, failures[0].RetryIn)
+	assert.Equal(t, 2*time.Second, failures[1].RetryIn)
+	assert.True(t, failures[2].DeadLettered)
+	assert.Equal(t, 2, failures[2].Retries())
+
+	metrics := system.GetMetrics()
+	assert.Equal(t, int64(2), metrics.RetriedMessages)
+	assert.Equal(t, int64(1), metrics.DeadLetters)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := actor.RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	assert.Equal(t, 100*time.Millisecond, policy.Backoff(1, nil))
+	assert.Equal(t, 400*time.Millisecond, policy.Backoff(3, nil))
+	assert.Equal(t, time.Second, policy.Backoff(10, nil))
+
+	policy.Jitter = 0.5
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		backoff := policy.Backoff(1, rng)
+		assert.GreaterOrEqual(t, backoff, 50*time.Millisecond)
+		assert.LessOrEqual(t, backoff, 150*time.Millisecond)
+	}
+}
@@ -0,0 +1,136 @@
+package appversion
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"actor-model-observability/internal/appversion"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	for input, expected := range map[string]appversion.Version{
+		"2.3.0": {Major: 2, Minor: 3},
+		"v2.3":  {Major: 2, Minor: 3},
+		"10":    {Major: 10},
+		"1.2.3": {Major: 1, Minor: 2, Patch: 3},
+	} {
+		version, err := appversion.ParseVersion(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, expected, version, input)
+	}
+
+	for _, input := range []string{"", "2.3.0.1", "2.x", "2.3.0-beta", " 2.3", "1234567.0"} {
+		_, err := appversion.ParseVersion(input)
+		assert.ErrorIs(t, err, models.ErrInvalidAppVersion, input)
+	}
+
+	v := func(s string) appversion.Version {
+		version, err := appversion.ParseVersion(s)
+		require.NoError(t, err)
+		return version
+	}
+	assert.True(t, v("2.3.9").Less(v("2.10")), "parts compare numerically")
+	assert.Equal(t, 0, v("2.3").Compare(v("2.3.0")))
+	assert.Equal(t, 1, v("3").Compare(v("2.99.99")))
+	assert.Equal(t, "2.3.0", v("v2.3").String())
+}
+
+func TestParseClient(t *testing.T) {
+	client, ok, err := appversion.ParseClient(" iOS ", "2.3")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "ios", client.Platform)
+	assert.Equal(t, "2.3.0", client.Version.String())
+
+	_, ok, err = appversion.ParseClient("", "")
+	require.NoError(t, err)
+	assert.False(t, ok, "requests without a version are not from the apps")
+
+	_, _, err = appversion.ParseClient("", "2.3.0")
+	assert.ErrorIs(t, err, models.ErrInvalidAppVersion, "the platform is required along with the version")
+	_, _, err = appversion.ParseClient("android", "latest")
+	assert.ErrorIs(t, err, models.ErrInvalidAppVersion)
+}
+
+func TestGate_UpgradeRequiredAndFeatures(t *testing.T) {
+	gate, err := appversion.NewGate(config.AppVersionConfig{
+		MinVersions:     map[string]string{"iOS": "2.3.0", "android": "2.1"},
+		FeatureVersions: map[string]string{"offer_websocket": "2.4.0", "split_fare": "2.3.5"},
+	}, nil)
+	require.NoError(t, err)
+
+	client := func(platform, version string) appversion.Client {
+		c, ok, err := appversion.ParseClient(platform, version)
+		require.NoError(t, err)
+		require.True(t, ok)
+		return c
+	}
+
+	assert.True(t, gate.UpgradeRequired(client("ios", "2.2.9")))
+	assert.False(t, gate.UpgradeRequired(client("ios", "2.3.0")), "the minimum itself is supported")
+	assert.True(t, gate.UpgradeRequired(client("android", "2.0.12")))
+	assert.False(t, gate.UpgradeRequired(client("web", "0.1")), "platforms without a minimum are not gated")
+	assert.Equal(t, map[string]string{"ios": "2.3.0", "android": "2.1.0"}, gate.MinVersions())
+
+	assert.Equal(t, []string{}, gate.Features(client("ios", "2.3.0")))
+	assert.Equal(t, []string{"split_fare"}, gate.Features(client("ios", "2.3.5")))
+	assert.Equal(t, []string{"offer_websocket", "split_fare"}, gate.Features(client("android", "3.0")))
+	assert.True(t, gate.Supports(client("ios", "2.4"), "offer_websocket"))
+	assert.False(t, gate.Supports(client("ios", "9.0"), "unknown_feature"))
+
+	// Reloaded versions apply to the next requests
+	require.NoError(t, gate.SetConfig(config.AppVersionConfig{
+		MinVersions:     map[string]string{"ios": "2.4.0"},
+		FeatureVersions: map[string]string{"offer_websocket": "2.3.0"},
+	}))
+	assert.True(t, gate.UpgradeRequired(client("ios", "2.3.0")))
+	assert.False(t, gate.UpgradeRequired(client("android", "1.0")))
+	assert.True(t, gate.Supports(client("ios", "2.3.0"), "offer_websocket"))
+
+	assert.Error(t, gate.SetConfig(config.AppVersionConfig{MinVersions: map[string]string{"ios": "two"}}))
+	assert.True(t, gate.UpgradeRequired(client("ios", "2.3.0")), "an invalid reload keeps the previous versions")
+}
+
+func TestGate_Usage(t *testing.T) {
+	gate, err := appversion.NewGate(config.DefaultAppVersionConfig(), nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	record := func(platform, version string, rejected bool) {
+		client, _, err := appversion.ParseClient(platform, version)
+		require.NoError(t, err)
+		gate.Record(ctx, client, rejected)
+	}
+	record("ios", "2.3", false)
+	record("ios", "2.3.0", false)
+	record("ios", "2.10.0", false)
+	record("android", "2.0", true)
+
+	usage := gate.Usage()
+	require.Len(t, usage, 3)
+	assert.Equal(t, "android", usage[0].Platform)
+	assert.Equal(t, int64(1), usage[0].Rejected)
+	assert.Equal(t, []string{"2.10.0", "2.3.0"}, []string{usage[1].Version, usage[2].Version}, "newest version first")
+	assert.Equal(t, int64(2), usage[2].Requests, "versions are counted normalized")
+	assert.False(t, usage[2].LastSeenAt.IsZero())
+
+	// Versions beyond the tracked ones are counted together
+	for i := 0; i < 600; i++ {
+		record("ios", fmt.Sprintf("1.0.%d", i), false)
+	}
+	usage = gate.Usage()
+	assert.Len(t, usage, 501)
+	var other int64
+	for _, u := range usage {
+		if u.Platform == "other" {
+			other = u.Requests
+		}
+	}
+	assert.Equal(t, int64(103), other)
+}
@@ -0,0 +1,196 @@
+package bus
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/hlc"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/tests/utils"
+)
+
+func newLogger(t *testing.T) *logging.Logger {
+	logger := utils.NewTestLogger(t)
+	return logger
+}
+
+// recorder collects the messages delivered to a subscriber
+type recorder struct {
+	mu       sync.Mutex
+	messages []bus.Message
+}
+
+func (r *recorder) handle(msg bus.Message) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, msg)
+}
+
+func (r *recorder) payloads() []interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	payloads := make([]interface{}, len(r.messages))
+	for i, msg := range r.messages {
+		payloads[i] = msg.Payload
+	}
+	return payloads
+}
+
+func TestBus_DeliversByTopic(t *testing.T) {
+	b := bus.New(10, newLogger(t))
+
+	events := &recorder{}
+	everything := &recorder{}
+	b.Subscribe("events", events.handle, bus.TopicEventLog)
+	b.Subscribe("everything", everything.handle, bus.TopicEventLog, bus.TopicActorMessage)
+
+	b.Publish(bus.TopicEventLog, "event 1")
+	b.Publish(bus.TopicActorMessage, "message 1")
+	b.Publish(bus.TopicActorInstance, "instance 1")
+	b.Publish(bus.TopicEventLog, "event 2")
+	b.Close()
+
+	assert.Equal(t, []interface{}{"event 1", "event 2"}, events.payloads())
+	assert.Equal(t, []interface{}{"event 1", "message 1", "event 2"}, everything.payloads())
+
+	stats := b.Stats()
+	require.Len(t, stats, 2)
+	assert.Equal(t, "events", stats[0].Name)
+	assert.Equal(t, int64(2), stats[0].Delivered)
+	assert.Equal(t, int64(3), stats[1].Delivered)
+
+	// Publishing after Close is ignored
+	b.Publish(bus.TopicEventLog, "late")
+	assert.Len(t, events.payloads(), 2)
+}
+
+func TestBus_SlowSubscriberDropsWithoutBlocking(t *testing.T) {
+	b := bus.New(2, newLogger(t))
+
+	release := make(chan struct{})
+	slow := &recorder{}
+	b.Subscribe("slow", func(msg bus.Message) {
+		<-release
+		slow.handle(msg)
+	}, bus.TopicEventLog)
+	fast := &recorder{}
+	b.Subscribe("fast", fast.handle, bus.TopicEventLog)
+
+	for i := 0; i < 10; i++ {
+		b.Publish(bus.TopicEventLog, i)
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	b.Close()
+
+	// One message in the handler and two queued; the rest were dropped for the slow subscriber only
+	assert.Len(t, slow.payloads(), 3)
+	assert.Len(t, fast.payloads(), 10)
+	assert.Equal(t, int64(7), b.Stats()[0].Dropped)
+	assert.Equal(t, int64(0), b.Stats()[1].Dropped)
+}
+
+func TestBus_RecoversFromHandlerPanics(t *testing.T) {
+	b := bus.New(10, newLogger(t))
+
+	delivered := &recorder{}
+	b.Subscribe("flaky", func(msg bus.Message) {
+		if msg.Payload == "bad" {
+			panic("cannot handle")
+		}
+		delivered.handle(msg)
+	}, bus.TopicEventLog)
+
+	b.Publish(bus.TopicEventLog, "bad")
+	b.Publish(bus.TopicEventLog, "good")
+	b.Close()
+
+	assert.Equal(t, []interface{}{"good"}, delivered.payloads())
+}
+
+func TestPersister_StoresPublishedRecords(t *testing.T) {
+	repo := &utils.MockObservabilityRepository{}
+	event := &models.EventLog{ID: uuid.New(), EventType: "actor_started"}
+	instance := &models.ActorInstance{ID: uuid.New(), ActorID: "driver-1"}
+	message := &models.ActorMessage{ID: uuid.New(), ReceiverActorID: "driver-1"}
+	repo.On("CreateEventLog", mock.Anything, event).Return(nil)
+	repo.On("CreateActorInstance", mock.Anything, instance).Return(nil)
+	repo.On("CreateActorMessage", mock.Anything, message).Return(nil)
+
+	b := bus.New(10, newLogger(t))
+	persister := observability.NewPersister(repo, newLogger(t))
+	b.Subscribe("persistence", persister.HandleMessage, persister.Topics()...)
+
+	b.Publish(bus.TopicEventLog, event)
+	b.Publish(bus.TopicActorInstance, instance)
+	b.Publish(bus.TopicActorMessage, message)
+	b.Close()
+
+	repo.AssertExpectations(t)
+}
+
+func TestAlertEvaluator_RaisesOncePerWindow(t *testing.T) {
+	b := bus.New(100, newLogger(t))
+	alerts := observability.NewAlertEvaluator(3, time.Minute, b, newLogger(t))
+	b.Subscribe("alerts", alerts.HandleMessage, bus.TopicEventLog)
+
+	raised := &recorder{}
+	b.Subscribe("alert_log", func(msg bus.Message) {
+		if event := msg.Payload.(*models.EventLog); event.EventType == "alert_triggered" {
+			raised.handle(msg)
+		}
+	}, bus.TopicEventLog)
+
+	publish := func(severity models.EventSeverity) {
+		b.Publish(bus.TopicEventLog, &models.EventLog{ID: uuid.New(), EventType: "actor_failed", Severity: severity})
+	}
+	publish(models.EventSeverityError)
+	publish(models.EventSeverityInfo)
+	publish(models.EventSeverityWarn)
+	publish(models.EventSeverityError)
+	require.Never(t, func() bool { return len(raised.payloads()) > 0 }, 50*time.Millisecond, 5*time.Millisecond)
+
+	publish(models.EventSeverityFatal)
+	// Further errors within the window don't raise another alert
+	publish(models.EventSeverityError)
+	publish(models.EventSeverityError)
+
+	require.Eventually(t, func() bool { return len(raised.payloads()) == 1 }, time.Second, 5*time.Millisecond)
+	b.Close()
+
+	alert := raised.payloads()[0].(*models.EventLog)
+	assert.Len(t, raised.payloads(), 1)
+	assert.Equal(t, models.EventSeverityWarn, alert.Severity)
+	assert.Equal(t, "3 error events in the last 1m0s", alert.Message)
+}
+
+func TestBus_StampsHybridTimestamps(t *testing.T) {
+	b := bus.New(10, newLogger(t))
+	clock := hlc.NewClock("api-1", time.Second)
+	b.SetClock(clock)
+
+	received := &recorder{}
+	b.Subscribe("events", received.handle, bus.TopicEventLog)
+
+	first := &models.EventLog{ID: uuid.New(), EventType: "trip_requested", Timestamp: time.Now()}
+	second := &models.EventLog{ID: uuid.New(), EventType: "trip_matched", Timestamp: time.Now()}
+	b.Publish(bus.TopicEventLog, first)
+	b.Publish(bus.TopicEventLog, second)
+	b.Publish(bus.TopicEventLog, "not an event")
+	b.Close()
+
+	require.Len(t, received.payloads(), 3)
+	require.NotNil(t, first.HLC)
+	require.NotNil(t, second.HLC)
+	assert.Equal(t, "api-1", first.HLC.Source)
+	assert.True(t, first.HLC.Before(*second.HLC))
+}
@@ -0,0 +1,58 @@
+package chat
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/chat"
+	"actor-model-observability/internal/models"
+)
+
+func TestProfanityFilter_MasksWholeWords(t *testing.T) {
+	f := chat.NewProfanityFilter([]string{"darn", "heck"})
+
+	assert.Equal(t, "Oh **** it, what the ****", f.Apply("Oh darn it, what the HECK"))
+	assert.Equal(t, "darnation stays", f.Apply("darnation stays"))
+	assert.Equal(t, "no words", chat.NewProfanityFilter(nil).Apply("no words"))
+}
+
+func TestPIIFilter_RemovesContactDetails(t *testing.T) {
+	f := chat.NewPIIFilter()
+
+	assert.Equal(t, "Call me at [phone removed] or mail [email removed]",
+		f.Apply("Call me at +62 812-3456-7890 or mail rider@example.com"))
+	assert.Equal(t, "I'm at gate 3, 5 minutes away", f.Apply("I'm at gate 3, 5 minutes away"))
+}
+
+func TestNewFilters(t *testing.T) {
+	filters, err := chat.NewFilters([]string{"profanity", "pii"}, []string{"darn"})
+	require.NoError(t, err)
+	assert.Equal(t, "**** it, [email removed]", filters.Apply("darn it, rider@example.com"))
+
+	_, err = chat.NewFilters([]string{"spam"}, nil)
+	assert.Error(t, err)
+}
+
+func TestHub_DeliversToTripSubscribers(t *testing.T) {
+	hub := chat.NewHub()
+	tripID := uuid.New()
+
+	messages, unsubscribe := hub.Subscribe(tripID.String())
+	other, unsubscribeOther := hub.Subscribe(uuid.NewString())
+	defer unsubscribeOther()
+
+	msg := &models.TripChatMessage{ID: uuid.New(), TripID: tripID, Body: "On my way"}
+	hub.Publish(msg)
+
+	assert.Equal(t, msg, <-messages)
+	assert.Empty(t, other)
+
+	unsubscribe()
+	unsubscribe()
+	assert.Zero(t, hub.Subscribers(tripID.String()))
+	_, open := <-messages
+	assert.False(t, open)
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeEnvFile writes an env file with the given content to a temporary directory and points
+// CONFIG_ENV_FILE at it
+func writeEnvFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "app.env")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	t.Setenv(config.EnvFileKey, path)
+	return path
+}
+
+// changesByField indexes changes by their field name
+func changesByField(changes []config.Change) map[string]config.Change {
+	byField := make(map[string]config.Change, len(changes))
+	for _, change := range changes {
+		byField[change.Field] = change
+	}
+	return byField
+}
+
+func TestDiff(t *testing.T) {
+	current := config.Development()
+	next := config.Development()
+
+	assert.Empty(t, current.Diff(next), "identical configurations")
+
+	next.Logging.Level = "warn"
+	next.RateLimit.Burst = current.RateLimit.Burst + 5
+	next.Server.Port = "9090"
+	next.Database.Password = "rotated"
+
+	changes := changesByField(current.Diff(next))
+	require.Len(t, changes, 4)
+
+	assert.Equal(t, config.Change{Field: "Logging.Level", OldValue: current.Logging.Level, NewValue: "warn", Applied: true}, changes["Logging.Level"])
+	assert.True(t, changes["RateLimit.Burst"].Applied)
+	assert.Equal(t, config.Change{Field: "Server.Port", OldValue: current.Server.Port, NewValue: "9090", Applied: false}, changes["Server.Port"], "requires a restart")
+
+	password := changes["Database.Password"]
+	assert.False(t, password.Applied)
+	assert.NotEqual(t, "rotated", password.NewValue, "secrets are redacted")
+	assert.Equal(t, password.OldValue, password.NewValue)
+}
+
+func TestApplyReloadable(t *testing.T) {
+	current := config.Development()
+	next := config.Development()
+	next.Logging.Level = "warn"
+	next.OpenTelemetry.SampleRate = 0.25
+	next.Observability.MetricsInterval = 42 * time.Second
+	next.RateLimit.RequestsPerMinute = 7
+	next.RateLimit.Burst = 3
+	next.BodyLogging.Enabled = !current.BodyLogging.Enabled
+	next.Server.Port = "9090"
+	next.Database.Host = "replica.example.com"
+
+	port, host := current.Server.Port, current.Database.Host
+	current.ApplyReloadable(next)
+
+	assert.Equal(t, "warn", current.Logging.Level)
+	assert.Equal(t, 0.25, current.OpenTelemetry.SampleRate)
+	assert.Equal(t, 42*time.Second, current.Observability.MetricsInterval)
+	assert.Equal(t, 7, current.RateLimit.RequestsPerMinute)
+	assert.Equal(t, 3, current.RateLimit.Burst)
+	assert.Equal(t, next.BodyLogging, current.BodyLogging)
+	assert.Equal(t, port, current.Server.Port, "settings requiring a restart are kept")
+	assert.Equal(t, host, current.Database.Host)
+	for _, change := range current.Diff(next) {
+		assert.False(t, change.Applied, "only settings requiring a restart still differ: %s", change.Field)
+	}
+}
+
+func TestLoad_EnvFile(t *testing.T) {
+	path := writeEnvFile(t, `
+# Comments and blank lines are skipped
+LOG_LEVEL=debug
+export RATE_LIMIT_BURST=12
+SERVER_PORT="9091"
+SERVER_HOST='127.0.0.1'
+`)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "debug", cfg.Logging.Level)
+	assert.Equal(t, 12, cfg.RateLimit.Burst)
+	assert.Equal(t, "9091", cfg.Server.Port)
+	assert.Equal(t, "127.0.0.1", cfg.Server.Host)
+
+	// The file is read again on every load
+	require.NoError(t, os.WriteFile(path, []byte("LOG_LEVEL=warn\n"), 0o600))
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "warn", cfg.Logging.Level)
+	assert.Equal(t, "8080", cfg.Server.Port, "settings removed from the file fall back to their defaults")
+
+	// The file takes precedence over the process environment, which fills in what it leaves unset
+	t.Setenv("LOG_LEVEL", "error")
+	t.Setenv("SERVER_PORT", "9092")
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "warn", cfg.Logging.Level)
+	assert.Equal(t, "9092", cfg.Server.Port)
+
+	// Editing a setting that is also in the process environment applies on the next load
+	require.NoError(t, os.WriteFile(path, []byte("LOG_LEVEL=debug\n"), 0o600))
+	cfg, err = config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "debug", cfg.Logging.Level)
+}
+
+func TestLoad_InvalidEnvFile(t *testing.T) {
+	writeEnvFile(t, "LOG_LEVEL=debug\nnot a setting\n")
+	_, err := config.Load()
+	assert.ErrorContains(t, err, "line 2")
+
+	t.Setenv(config.EnvFileKey, filepath.Join(t.TempDir(), "missing.env"))
+	_, err = config.Load()
+	assert.Error(t, err)
+}
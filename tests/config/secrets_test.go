@@ -0,0 +1,266 @@
+package config
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	"actor-model-observability/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// secretFieldName matches the names of the configuration fields holding secrets
+var secretFieldName = regexp.MustCompile(`Password|Secret|Token|HashKey|SigningKey|OperatorKeys|DSN`)
+
+// secretFields calls fn with every string or []string field of v, a struct, whose name marks it
+// as a secret, walking nested structs
+func secretFields(prefix string, v reflect.Value, fn func(name string, field reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		field, value := v.Type().Field(i), v.Field(i)
+		name := prefix + field.Name
+		if value.Kind() == reflect.Struct {
+			secretFields(name+".", value, fn)
+			continue
+		}
+		isText := value.Kind() == reflect.String || (value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.String)
+		if isText && secretFieldName.MatchString(field.Name) {
+			fn(name, value)
+		}
+	}
+}
+
+func TestNewSecretProvider(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      config.SecretsConfig
+		wantName string
+		wantErr  string
+	}{
+		{name: "default", cfg: config.SecretsConfig{}, wantName: "env"},
+		{name: "env", cfg: config.SecretsConfig{Provider: "env"}, wantName: "env"},
+		{name: "file", cfg: config.SecretsConfig{Provider: "file", Dir: "/run/secrets"}, wantName: "file"},
+		{name: "vault", cfg: config.SecretsConfig{Provider: "vault", VaultAddr: "http://vault:8200/", VaultPath: "/secret/data/app/"}, wantName: "vault"},
+		{name: "vault without address", cfg: config.SecretsConfig{Provider: "vault", VaultPath: "secret/data/app"}, wantErr: "vault address and secret path are required"},
+		{name: "vault without path", cfg: config.SecretsConfig{Provider: "vault", VaultAddr: "http://vault:8200"}, wantErr: "vault address and secret path are required"},
+		{name: "unsupported", cfg: config.SecretsConfig{Provider: "aws"}, wantErr: "unsupported secrets provider: aws"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider, err := config.NewSecretProvider(&tt.cfg)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, provider.Name())
+		})
+	}
+
+	provider, err := config.NewSecretProvider(&config.SecretsConfig{Provider: "vault", VaultAddr: "http://vault:8200/", VaultPath: "/secret/data/app/"})
+	require.NoError(t, err)
+	vault := provider.(*config.VaultSecretProvider)
+	assert.Equal(t, "http://vault:8200", vault.Address, "trailing slashes are trimmed")
+	assert.Equal(t, "secret/data/app", vault.Path)
+}
+
+func TestEnvSecretProvider(t *testing.T) {
+	dir := t.TempDir()
+	passwordFile := filepath.Join(dir, "db_password")
+	require.NoError(t, os.WriteFile(passwordFile, []byte("from-file\n"), 0o600))
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		wantValue string
+		wantFound bool
+		wantErr   bool
+	}{
+		{name: "unset"},
+		{name: "variable", env: map[string]string{"DB_PASSWORD": "from-env"}, wantValue: "from-env", wantFound: true},
+		{name: "file variable trims the newline", env: map[string]string{"DB_PASSWORD_FILE": passwordFile}, wantValue: "from-file", wantFound: true},
+		{name: "file variable takes precedence", env: map[string]string{"DB_PASSWORD": "from-env", "DB_PASSWORD_FILE": passwordFile}, wantValue: "from-file", wantFound: true},
+		{name: "missing file", env: map[string]string{"DB_PASSWORD_FILE": filepath.Join(dir, "missing")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DB_PASSWORD", "")
+			t.Setenv("DB_PASSWORD_FILE", "")
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			value, found, err := (&config.EnvSecretProvider{}).GetSecret("DB_PASSWORD")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFound, found)
+			assert.Equal(t, tt.wantValue, value)
+		})
+	}
+}
+
+func TestFileSecretProvider(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "db_password"), []byte("s3cret\r\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "redis_password"), []byte(""), 0o600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "smtp_password"), 0o700))
+
+	tests := []struct {
+		name      string
+		key       string
+		wantValue string
+		wantFound bool
+		wantErr   bool
+	}{
+		{name: "file named after the lowercased key", key: "DB_PASSWORD", wantValue: "s3cret", wantFound: true},
+		{name: "empty file", key: "REDIS_PASSWORD", wantValue: "", wantFound: true},
+		{name: "missing file", key: "DB_USER"},
+		{name: "unreadable file", key: "SMTP_PASSWORD", wantErr: true},
+	}
+
+	provider := &config.FileSecretProvider{Dir: dir}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, found, err := provider.GetSecret(tt.key)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFound, found)
+			assert.Equal(t, tt.wantValue, value)
+		})
+	}
+}
+
+func TestVaultSecretProvider(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		key       string
+		wantValue string
+		wantFound bool
+		wantErr   string
+	}{
+		{name: "found", status: http.StatusOK, body: `{"data":{"data":{"DB_PASSWORD":"from-vault"}}}`, key: "DB_PASSWORD", wantValue: "from-vault", wantFound: true},
+		{name: "missing key", status: http.StatusOK, body: `{"data":{"data":{"DB_PASSWORD":"from-vault"}}}`, key: "REDIS_PASSWORD"},
+		{name: "empty secret", status: http.StatusOK, body: `{"data":{}}`, key: "DB_PASSWORD"},
+		{name: "forbidden", status: http.StatusForbidden, body: `{"errors":["permission denied"]}`, key: "DB_PASSWORD", wantErr: "vault returned status 403"},
+		{name: "invalid response", status: http.StatusOK, body: `not json`, key: "DB_PASSWORD", wantErr: "failed to decode vault response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				assert.Equal(t, "/v1/secret/data/app", r.URL.Path)
+				assert.Equal(t, "vault-token", r.Header.Get("X-Vault-Token"))
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			provider := &config.VaultSecretProvider{Address: server.URL, Token: "vault-token", Path: "secret/data/app", Client: server.Client()}
+			value, found, err := provider.GetSecret(tt.key)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantFound, found)
+			assert.Equal(t, tt.wantValue, value)
+
+			// The secret is read from Vault once
+			_, _, err = provider.GetSecret("DB_USER")
+			require.NoError(t, err)
+			assert.Equal(t, 1, requests)
+		})
+	}
+}
+
+func TestLoad_AppliesSecrets(t *testing.T) {
+	dir := t.TempDir()
+	for name, value := range map[string]string{
+		"db_password":              "db-secret",
+		"operator_api_keys":        " key-a, ,key-b ",
+		"research_export_hash_key": "research-secret",
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(value+"\n"), 0o600))
+	}
+	t.Setenv("SECRETS_PROVIDER", "file")
+	t.Setenv("SECRETS_DIR", dir)
+	t.Setenv("DB_PASSWORD", "ignored")
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	assert.Equal(t, "db-secret", cfg.Database.Password, "the provider overrides the environment")
+	assert.Equal(t, []string{"key-a", "key-b"}, cfg.Redaction.OperatorKeys)
+	assert.Equal(t, "research-secret", cfg.Research.HashKey)
+}
+
+func TestRedacted_MasksEverySecretField(t *testing.T) {
+	cfg := config.Development()
+	secrets := 0
+	secretFields("", reflect.ValueOf(cfg).Elem(), func(name string, field reflect.Value) {
+		secrets++
+		if field.Kind() == reflect.String {
+			field.SetString("secret-" + name)
+		} else {
+			field.Set(reflect.ValueOf([]string{"secret-" + name}))
+		}
+	})
+	require.NotZero(t, secrets)
+
+	redacted := cfg.Redacted()
+	secretFields("", reflect.ValueOf(redacted), func(name string, field reflect.Value) {
+		assert.NotContains(t, strings.Join(textValues(field), ","), "secret-", "%s is not masked", name)
+		assert.NotEmpty(t, textValues(field), "%s is masked, not cleared", name)
+	})
+
+	// The configuration itself keeps its secrets
+	assert.Equal(t, "secret-Database.Password", cfg.Database.Password)
+	assert.Equal(t, cfg.Server, redacted.Server, "other settings are kept")
+
+	// Changes to secrets are masked in reload diffs as well
+	for _, change := range config.Development().Diff(cfg) {
+		assert.NotContains(t, fmt.Sprint(change.NewValue), "secret-", "%s is not masked in diffs", change.Field)
+	}
+}
+
+func TestRedacted_ResearchExportHashKey(t *testing.T) {
+	cfg := config.Development()
+	cfg.Research.HashKey = "research-secret"
+
+	redacted := cfg.Redacted()
+	assert.NotContains(t, redacted.Research.HashKey, "research-secret")
+	assert.Equal(t, "research-secret", cfg.Research.HashKey, "the configuration itself is left alone")
+
+	// Without a key there is nothing to mask
+	cfg.Research.HashKey = ""
+	assert.Empty(t, cfg.Redacted().Research.HashKey)
+}
+
+// textValues returns the values of a string or []string field
+func textValues(field reflect.Value) []string {
+	if field.Kind() == reflect.String {
+		if field.String() == "" {
+			return nil
+		}
+		return []string{field.String()}
+	}
+	return field.Interface().([]string)
+}
@@ -0,0 +1,45 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/database"
+	"actor-model-observability/tests/utils"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeListener_DispatchesByTable(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+	listener := database.NewChangeListener(&config.DatabaseConfig{}, logger)
+
+	var trips, drivers []database.RowChange
+	resyncs := 0
+	listener.Handle("trips", func(ctx context.Context, change database.RowChange) { trips = append(trips, change) })
+	listener.Handle("drivers", func(ctx context.Context, change database.RowChange) { drivers = append(drivers, change) })
+	listener.Handle("drivers", func(ctx context.Context, change database.RowChange) { panic("faulty handler") })
+	listener.OnResync(func(ctx context.Context) { resyncs++ })
+
+	notify := func(payload string) {
+		listener.HandleNotification(&pq.Notification{Channel: database.RowChangeChannel, Extra: payload})
+	}
+	notify(`{"table":"trips","op":"UPDATE","id":"a1"}`)
+	notify(`{"table":"drivers","op":"DELETE","id":"b2"}`)
+	notify(`{"table":"passengers","op":"INSERT","id":"c3"}`)
+	notify(`not json`)
+	notify(`{"table":"trips","op":"UPDATE"}`)
+	// Sent by the listener once it reconnected
+	listener.HandleNotification(nil)
+
+	assert.Equal(t, []database.RowChange{{Table: "trips", Operation: database.RowUpdated, ID: "a1"}}, trips)
+	assert.Equal(t, []database.RowChange{{Table: "drivers", Operation: database.RowDeleted, ID: "b2"}}, drivers)
+	assert.Equal(t, 1, resyncs)
+
+	stats := listener.Stats()
+	assert.Equal(t, int64(3), stats.Received)
+	assert.Equal(t, int64(2), stats.Invalid)
+	assert.NotNil(t, stats.LastSyncAt)
+}
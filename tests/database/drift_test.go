@@ -0,0 +1,111 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"actor-model-observability/internal/database"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/tests/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/rubenv/sql-migrate"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpectedIndexes(t *testing.T) {
+	statements := []string{
+		"CREATE INDEX idx_messages_trace ON messages(trace_id);",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_span ON messages (span_id);",
+		"-- Drivers by fleet\nCREATE INDEX idx_drivers_fleet ON drivers(fleet_id) WHERE fleet_id IS NOT NULL;",
+		"CREATE INDEX idx_legacy_status ON legacy(status);",
+		"ALTER TABLE messages RENAME TO messages_legacy;",
+		"DROP INDEX IF EXISTS idx_messages_trace;",
+		"DROP TABLE IF EXISTS legacy, messages_legacy CASCADE;",
+		"ALTER INDEX idx_drivers_fleet RENAME TO idx_drivers_fleet_id;",
+	}
+
+	assert.Equal(t, []database.IndexDefinition{
+		{Name: "idx_drivers_fleet_id", Table: "drivers"},
+	}, database.ExpectedIndexes(statements))
+}
+
+func TestSchemaDriftChecker_Check(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	columns := sqlmock.NewRows([]string{"table_name", "column_name"})
+	for _, column := range database.MappedColumns(models.Fleet{}) {
+		columns.AddRow("fleets", column)
+	}
+	mock.ExpectQuery("FROM information_schema.columns").WillReturnRows(columns)
+
+	// Every index created by the migrations exists except the one on drivers by fleet
+	checker := database.NewSchemaDriftChecker(sqlx.NewDb(db, "postgres"), []models.Table{models.Fleet{}}, "../../migrations", logger)
+	expected := expectedMigrationIndexes(t)
+	indexes := sqlmock.NewRows([]string{"indexname"})
+	for _, index := range expected {
+		if index.Name != "idx_drivers_fleet_id" {
+			indexes.AddRow(index.Name)
+		}
+	}
+	mock.ExpectQuery("FROM pg_indexes").WillReturnRows(indexes)
+
+	assert.Nil(t, checker.Last())
+	report, err := checker.Check(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, report.Drifted)
+	assert.Equal(t, 1, report.Tables)
+	assert.Equal(t, len(expected), report.Indexes)
+	assert.Empty(t, report.IndexesSkipped)
+	assert.Equal(t, []database.SchemaMismatch{{Table: "drivers", Index: "idx_drivers_fleet_id"}}, report.Mismatches)
+	assert.Same(t, report, checker.Last())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSchemaDriftChecker_Check_WithoutMigrations(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectQuery("FROM information_schema.columns").
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "column_name"}).AddRow("fleets", "id"))
+
+	checker := database.NewSchemaDriftChecker(sqlx.NewDb(db, "postgres"), []models.Table{models.Fleet{}}, "", logger)
+	report, err := checker.Check(context.Background())
+	require.NoError(t, err)
+
+	assert.True(t, report.Drifted)
+	assert.NotEmpty(t, report.IndexesSkipped)
+	assert.Zero(t, report.Indexes)
+	assert.Contains(t, report.Mismatches, database.SchemaMismatch{Table: "fleets", Column: "api_key_hash"})
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestExpectedIndexes_Migrations(t *testing.T) {
+	expected := expectedMigrationIndexes(t)
+
+	// Indexes of the partitioned tables replace those of the legacy tables
+	assert.Contains(t, expected, database.IndexDefinition{Name: "idx_actor_messages_trace", Table: "actor_messages"})
+	for _, index := range expected {
+		assert.NotContains(t, index.Table, "_legacy")
+	}
+}
+
+// expectedMigrationIndexes returns the indexes the repository's migrations create
+func expectedMigrationIndexes(t *testing.T) []database.IndexDefinition {
+	migrations, err := migrate.FileMigrationSource{Dir: "../../migrations"}.FindMigrations()
+	require.NoError(t, err)
+
+	var statements []string
+	for _, migration := range migrations {
+		statements = append(statements, migration.Up...)
+	}
+	return database.ExpectedIndexes(statements)
+}
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"testing"
+
+	"actor-model-observability/internal/database"
+	"actor-model-observability/internal/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDestructiveStatements(t *testing.T) {
+	queries := []string{
+		"DROP TRIGGER IF EXISTS update_fleets_updated_at ON fleets;",
+		"DROP INDEX IF EXISTS idx_trips_fleet_id;",
+		"ALTER TABLE drivers DROP CONSTRAINT IF EXISTS drivers_fleet_id_fkey;",
+		"-- Remove the fleets\nDROP TABLE IF EXISTS fleets;",
+		"ALTER TABLE drivers DROP COLUMN IF EXISTS fleet_id;",
+		"ALTER TABLE passengers DROP corporate_account_id;",
+		"DELETE FROM dashboard_refreshes;",
+		"TRUNCATE incentive_progress;",
+		"-- DROP TABLE users;\nSELECT 1;",
+	}
+
+	assert.Equal(t, []string{
+		"DROP TABLE IF EXISTS fleets;",
+		"ALTER TABLE drivers DROP COLUMN IF EXISTS fleet_id;",
+		"ALTER TABLE passengers DROP corporate_account_id;",
+		"DELETE FROM dashboard_refreshes;",
+		"TRUNCATE incentive_progress;",
+	}, database.DestructiveStatements(queries))
+}
+
+func TestMappedColumns(t *testing.T) {
+	assert.Equal(t, []string{"api_key_hash", "created_at", "daily_request_quota", "id", "name", "timezone", "updated_at"},
+		database.MappedColumns(models.Fleet{}))
+
+	for _, table := range models.MappedTables() {
+		assert.NotEmpty(t, database.MappedColumns(table), table.TableName())
+	}
+}
+
+func TestValidateSchema(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"table_name", "column_name"})
+	for _, column := range []string{"id", "name", "timezone", "daily_request_quota", "created_at", "updated_at", "legacy_code"} {
+		rows.AddRow("fleets", column)
+	}
+	mock.ExpectQuery("FROM information_schema.columns").WillReturnRows(rows)
+
+	mismatches, err := database.ValidateSchema(context.Background(), sqlx.NewDb(db, "postgres"),
+		[]models.Table{models.Fleet{}, models.CorporateAccount{}})
+	require.NoError(t, err)
+
+	assert.Equal(t, []database.SchemaMismatch{
+		{Table: "fleets", Column: "api_key_hash"},
+		{Table: "corporate_accounts"},
+	}, mismatches)
+	assert.Equal(t, "column fleets.api_key_hash is missing", mismatches[0].String())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,139 @@
+package database
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/database"
+	"actor-model-observability/tests/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const replicaLagPattern = `SELECT CASE\s+WHEN pg_last_wal_receive_lsn\(\) = pg_last_wal_replay_lsn\(\)`
+
+func newReplicaMock(t *testing.T) (*sqlx.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return sqlx.NewDb(db, "postgres"), mock
+}
+
+func lagRows(seconds float64) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"lag"}).AddRow(seconds)
+}
+
+func TestReplicaRouter_FallsBackToPrimary(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+	cfg := &config.DatabaseConfig{ReplicaHealthCheckInterval: time.Minute, ReplicaMaxLag: 30 * time.Second}
+
+	tests := []struct {
+		name        string
+		expect      func(mock sqlmock.Sqlmock)
+		wantReplica bool
+	}{
+		{
+			name: "healthy replica serves reads",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectPing()
+				mock.ExpectQuery(replicaLagPattern).WillReturnRows(lagRows(2))
+			},
+			wantReplica: true,
+		},
+		{
+			name: "unreachable replica falls back to primary",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+			},
+		},
+		{
+			name: "lagging replica falls back to primary",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectPing()
+				mock.ExpectQuery(replicaLagPattern).WillReturnRows(lagRows(45))
+			},
+		},
+		{
+			name: "failed lag query falls back to primary",
+			expect: func(mock sqlmock.Sqlmock) {
+				mock.ExpectPing()
+				mock.ExpectQuery(replicaLagPattern).WillReturnError(errors.New("recovery is not in progress"))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, _ := newReplicaMock(t)
+			replica, mock := newReplicaMock(t)
+			tt.expect(mock)
+
+			router := database.NewReplicaRouterWithReplica(primary, replica, cfg, logger)
+
+			assert.Equal(t, tt.wantReplica, router.IsReplicaHealthy())
+			if tt.wantReplica {
+				assert.Same(t, replica, router.Reader())
+			} else {
+				assert.Same(t, primary, router.Reader())
+			}
+			assert.Same(t, primary, router.Writer())
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestReplicaRouter_RecoversAfterFallback(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+	cfg := &config.DatabaseConfig{ReplicaHealthCheckInterval: time.Minute, ReplicaMaxLag: 30 * time.Second}
+	primary, _ := newReplicaMock(t)
+	replica, mock := newReplicaMock(t)
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	router := database.NewReplicaRouterWithReplica(primary, replica, cfg, logger)
+	assert.Same(t, primary, router.Reader())
+
+	mock.ExpectPing()
+	mock.ExpectQuery(replicaLagPattern).WillReturnRows(lagRows(60))
+	router.CheckHealth()
+	assert.Same(t, primary, router.Reader(), "reachable but lagging replica must not serve reads")
+
+	mock.ExpectPing()
+	mock.ExpectQuery(replicaLagPattern).WillReturnRows(lagRows(0))
+	router.CheckHealth()
+	assert.Same(t, replica, router.Reader())
+
+	mock.ExpectPing().WillReturnError(errors.New("connection reset"))
+	router.CheckHealth()
+	assert.Same(t, primary, router.Reader())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplicaRouter_IgnoresLagWithoutMaximum(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+	primary, _ := newReplicaMock(t)
+	replica, mock := newReplicaMock(t)
+
+	mock.ExpectPing()
+	router := database.NewReplicaRouterWithReplica(primary, replica, &config.DatabaseConfig{}, logger)
+
+	assert.Same(t, replica, router.Reader())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplicaRouter_WithoutReplicaReadsPrimary(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+	primary, _ := newReplicaMock(t)
+
+	router := database.NewReplicaRouter(primary, &config.DatabaseConfig{}, logger)
+	router.CheckHealth()
+
+	assert.False(t, router.IsReplicaHealthy())
+	assert.Same(t, primary, router.Reader())
+	assert.NoError(t, router.Stop())
+}
@@ -0,0 +1,54 @@
+package database
+
+import (
+	"testing"
+
+	"actor-model-observability/internal/database"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSQLitePlaceholders(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "no placeholders",
+			query: "SELECT * FROM drivers",
+			want:  "SELECT * FROM drivers",
+		},
+		{
+			name:  "out of order placeholders keep their numbers",
+			query: "UPDATE drivers SET status = $2, updated_at = $3 WHERE id = $1",
+			want:  "UPDATE drivers SET status = ?2, updated_at = ?3 WHERE id = ?1",
+		},
+		{
+			name:  "multi digit placeholders",
+			query: "VALUES ($10, $11)",
+			want:  "VALUES (?10, ?11)",
+		},
+		{
+			name:  "quoted literals are left alone",
+			query: `SELECT '$1', "col$2" FROM t WHERE a = $1 AND b = 'it''s $3'`,
+			want:  `SELECT '$1', "col$2" FROM t WHERE a = ?1 AND b = 'it''s $3'`,
+		},
+		{
+			name:  "comments are left alone",
+			query: "SELECT 1 -- costs $5\nWHERE id = $1",
+			want:  "SELECT 1 -- costs $5\nWHERE id = ?1",
+		},
+		{
+			name:  "dollar without a digit is kept",
+			query: "SELECT '$' || name FROM t WHERE price > $",
+			want:  "SELECT '$' || name FROM t WHERE price > $",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, database.SQLitePlaceholders(tt.query))
+		})
+	}
+}
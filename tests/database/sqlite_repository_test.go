@@ -0,0 +1,207 @@
+//go:build sqlite
+
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/database"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository/factory"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSQLiteRepositories opens a fresh SQLite database with the development schema and returns
+// the SQL repositories running against it
+func newSQLiteRepositories(t *testing.T) *factory.Repositories {
+	logger := utils.NewTestLogger(t)
+
+	cfg := &config.DatabaseConfig{Driver: "sqlite", SQLitePath: filepath.Join(t.TempDir(), "observability.db")}
+	db, err := database.NewSQLiteConnection(cfg, logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	repos, err := factory.NewRepositories(cfg, db.DB, nil)
+	require.NoError(t, err)
+	return repos
+}
+
+// createSQLiteDriver creates an offline driver without a location
+func createSQLiteDriver(t *testing.T, repos *factory.Repositories, license string) *models.Driver {
+	ctx := context.Background()
+	user := &models.User{ID: uuid.New(), Email: license + "@example.com", Phone: "+62" + license, Name: "Driver " + license, UserType: models.UserTypeDriver}
+	require.NoError(t, repos.Users.Create(ctx, user))
+	driver := &models.Driver{ID: uuid.New(), UserID: user.ID, LicenseNumber: license, VehicleType: "sedan", VehiclePlate: "B " + license, Status: models.DriverStatusOffline, Rating: 5}
+	require.NoError(t, repos.Drivers.Create(ctx, driver))
+	return driver
+}
+
+func TestSQLite_DriverRepository(t *testing.T) {
+	ctx := context.Background()
+	repos := newSQLiteRepositories(t)
+	driver := createSQLiteDriver(t, repos, "1001")
+	other := createSQLiteDriver(t, repos, "1002")
+
+	// The driver ID is bound after the status, so placeholders must be bound by number
+	require.NoError(t, repos.Drivers.UpdateStatus(ctx, driver.ID.String(), models.DriverStatusOnline))
+	require.NoError(t, repos.Drivers.UpdateLocation(ctx, driver.ID.String(), -6.2, 106.8))
+
+	stored, err := repos.Drivers.GetByID(ctx, driver.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.DriverStatusOnline, stored.Status)
+	require.NotNil(t, stored.CurrentLatitude)
+	assert.InDelta(t, -6.2, *stored.CurrentLatitude, 1e-9)
+
+	updated, err := repos.Drivers.UpdateLocations(ctx, []*models.DriverLocationUpdate{
+		{DriverID: driver.ID, Latitude: -6.21, Longitude: 106.81, At: time.Now()},
+		{DriverID: other.ID, Latitude: -6.3, Longitude: 106.9, At: time.Now()},
+		{DriverID: uuid.New(), Latitude: 0, Longitude: 0, At: time.Now()},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+
+	online, err := repos.Drivers.GetOnlineDrivers(ctx)
+	require.NoError(t, err)
+	require.Len(t, online, 1)
+	assert.Equal(t, driver.ID, online[0].ID)
+	require.NotNil(t, online[0].CurrentLatitude)
+	assert.InDelta(t, -6.21, *online[0].CurrentLatitude, 1e-9)
+
+	nearby, err := repos.Drivers.GetDriversInRadius(ctx, -6.2, 106.8, 5)
+	require.NoError(t, err)
+	require.Len(t, nearby, 1)
+	assert.Equal(t, driver.ID, nearby[0].ID)
+
+	// Reserving sets the driver busy, which counts as driving time
+	require.NoError(t, repos.Drivers.Reserve(ctx, driver.ID.String()))
+	require.ErrorIs(t, repos.Drivers.Reserve(ctx, driver.ID.String()), models.ErrDriverNotOnline)
+	require.NoError(t, repos.Drivers.Release(ctx, driver.ID.String()))
+}
+
+func TestSQLite_DriverHours(t *testing.T) {
+	ctx := context.Background()
+	repos := newSQLiteRepositories(t)
+	driver := createSQLiteDriver(t, repos, "2001")
+	require.NoError(t, repos.Drivers.UpdateStatus(ctx, driver.ID.String(), models.DriverStatusOnline))
+
+	from := time.Now().Add(-time.Hour)
+	to := time.Now().Add(time.Hour)
+	hours, err := repos.Drivers.GetDriverHours(ctx, driver.ID.String(), from, to)
+	require.NoError(t, err)
+	assert.Equal(t, driver.ID, hours.DriverID)
+	// Online from now until the end of the window
+	assert.InDelta(t, time.Hour.Seconds(), float64(hours.OnlineSeconds), 5)
+	assert.Zero(t, hours.DrivingSeconds)
+
+	online, err := repos.Drivers.ListOnlineDriverHours(ctx, from, to)
+	require.NoError(t, err)
+	require.Len(t, online, 1)
+	assert.Equal(t, hours.OnlineSeconds, online[0].OnlineSeconds)
+
+	_, err = repos.Drivers.GetDriverHours(ctx, uuid.NewString(), from, to)
+	var notFound *models.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestSQLite_TripsAndFareDisputes(t *testing.T) {
+	ctx := context.Background()
+	repos := newSQLiteRepositories(t)
+	driver := createSQLiteDriver(t, repos, "3001")
+
+	user := &models.User{ID: uuid.New(), Email: "rider@example.com", Phone: "+6281200003001", Name: "Rider", UserType: models.UserTypePassenger}
+	require.NoError(t, repos.Users.Create(ctx, user))
+	passenger := &models.Passenger{ID: uuid.New(), UserID: user.ID}
+	require.NoError(t, repos.Passengers.Create(ctx, passenger))
+
+	now := time.Now()
+	trip := &models.Trip{
+		ID:                   uuid.New(),
+		PassengerID:          passenger.ID,
+		PickupLatitude:       -6.2,
+		PickupLongitude:      106.82,
+		DestinationLatitude:  -6.3,
+		DestinationLongitude: 106.85,
+		Status:               models.TripStatusRequested,
+		RequestedAt:          now,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	require.NoError(t, repos.Trips.Create(ctx, trip))
+
+	fare := 12.5
+	trip.DriverID = &driver.ID
+	trip.Status = models.TripStatusCompleted
+	trip.FareAmount = &fare
+	trip.CompletedAt = &now
+	require.NoError(t, repos.Trips.Update(ctx, trip))
+
+	stored, err := repos.Trips.GetByID(ctx, trip.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.TripStatusCompleted, stored.Status)
+	require.NotNil(t, stored.DriverID)
+	assert.Equal(t, driver.ID, *stored.DriverID)
+
+	dispute := &models.FareDispute{
+		ID:           uuid.New(),
+		TripID:       trip.ID,
+		PassengerID:  passenger.ID,
+		Reason:       "Overcharged",
+		OriginalFare: fare,
+		Status:       models.FareDisputeOpen,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	require.NoError(t, repos.FareDisputes.CreateDispute(ctx, dispute))
+	reviewer := "ops"
+	dispute.Status = models.FareDisputeUnderReview
+	dispute.ReviewedBy = &reviewer
+	require.NoError(t, repos.FareDisputes.UpdateDispute(ctx, dispute, models.FareDisputeOpen))
+	assert.ErrorIs(t, repos.FareDisputes.UpdateDispute(ctx, dispute, models.FareDisputeOpen), models.ErrDisputeStatusConflict)
+
+	reviewed, err := repos.FareDisputes.GetDispute(ctx, dispute.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.FareDisputeUnderReview, reviewed.Status)
+	require.NotNil(t, reviewed.ReviewedBy)
+	assert.Equal(t, reviewer, *reviewed.ReviewedBy)
+
+	fleet := &models.Fleet{ID: uuid.New(), Name: "Jakarta Cabs", APIKeyHash: "hash", Timezone: "UTC", CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, repos.Fleets.Create(ctx, fleet))
+	fleet.Name = "Jakarta Taxis"
+	require.NoError(t, repos.Fleets.Update(ctx, fleet))
+	storedFleet, err := repos.Fleets.GetByID(ctx, fleet.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Jakarta Taxis", storedFleet.Name)
+}
+
+func TestSQLite_CreateDriverWithOnboarding(t *testing.T) {
+	ctx := context.Background()
+	repos := newSQLiteRepositories(t)
+
+	user := &models.User{ID: uuid.New(), Email: "4001@example.com", Phone: "+624001", Name: "Driver 4001", UserType: models.UserTypeDriver}
+	require.NoError(t, repos.Users.Create(ctx, user))
+	driver := &models.Driver{ID: uuid.New(), UserID: user.ID, LicenseNumber: "4001", VehicleType: "sedan", VehiclePlate: "B 4001", Status: models.DriverStatusOffline, Rating: 5}
+	require.NoError(t, repos.Onboarding.CreateWithDriver(ctx, driver, models.NewDriverOnboarding(driver.ID, time.Now())))
+
+	onboarding, err := repos.Onboarding.GetByDriverID(ctx, driver.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.OnboardingRegistered, onboarding.Stage)
+
+	// The onboarding is rejected after the driver is inserted, which rolls the driver back
+	rejected := &models.Driver{ID: uuid.New(), UserID: user.ID, LicenseNumber: "4002", VehicleType: "sedan", VehiclePlate: "B 4002", Status: models.DriverStatusOffline, Rating: 5}
+	invalid := models.NewDriverOnboarding(rejected.ID, time.Now())
+	invalid.Stage = "unknown"
+	require.Error(t, repos.Onboarding.CreateWithDriver(ctx, rejected, invalid))
+
+	_, err = repos.Drivers.GetByID(ctx, rejected.ID.String())
+	var notFound *models.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
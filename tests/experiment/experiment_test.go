@@ -0,0 +1,131 @@
+package experiment
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+
+	"actor-model-observability/internal/experiment"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/tests/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newManager(t *testing.T) (*experiment.Manager, *experiment.Target, sqlmock.Sqlmock) {
+	logger := utils.NewTestLogger(t)
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	target := experiment.NewTarget()
+	return experiment.NewManager(sqlx.NewDb(db, "postgres"), target, "experiment_", logger), target, mock
+}
+
+func expectExists(mock sqlmock.Sqlmock, schema string, exists bool) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM pg_namespace WHERE nspname = $1)")).
+		WithArgs(schema).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(exists))
+}
+
+func TestTarget_Qualify(t *testing.T) {
+	query := `SELECT m.id FROM actor_messages m JOIN event_logs e ON e.trace_id = m.trace_id
+		WHERE m.message_type = 'actor_messages'`
+
+	var unset *experiment.Target
+	assert.Equal(t, query, unset.Qualify(query))
+	assert.Equal(t, query, experiment.NewTarget().Qualify(query))
+}
+
+func TestManager_CreateAndActivate(t *testing.T) {
+	manager, target, mock := newManager(t)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE SCHEMA "experiment_surge"`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	for _, table := range experiment.Tables {
+		mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE "experiment_surge".` + table + ` (LIKE ` + table + ` INCLUDING`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+	mock.ExpectCommit()
+
+	created, err := manager.Create(ctx, "surge")
+	require.NoError(t, err)
+	assert.Equal(t, &experiment.Experiment{Name: "surge", Schema: "experiment_surge"}, created)
+
+	expectExists(mock, "experiment_surge", true)
+	active, err := manager.Activate(ctx, "surge")
+	require.NoError(t, err)
+	assert.True(t, active.Active)
+	assert.Equal(t, "experiment_surge", target.Schema())
+	assert.Equal(t, active, manager.Active())
+
+	// Queries now go to the experiment's tables; other tables and literals are left alone
+	assert.Equal(t,
+		`SELECT m.id FROM "experiment_surge".actor_messages m JOIN trips t ON t.id = m.entity_id WHERE m.message_type = 'event_logs'`,
+		target.Qualify(`SELECT m.id FROM actor_messages m JOIN trips t ON t.id = m.entity_id WHERE m.message_type = 'event_logs'`))
+	assert.Equal(t, `INSERT INTO "experiment_surge".event_logs (id) VALUES ($1)`, target.Qualify(`INSERT INTO event_logs (id) VALUES ($1)`))
+
+	// The active experiment cannot be dropped
+	assert.ErrorIs(t, manager.Drop(ctx, "surge"), experiment.ErrExperimentActive)
+
+	_, err = manager.Activate(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, target.Schema())
+	assert.Nil(t, manager.Active())
+
+	expectExists(mock, "experiment_surge", true)
+	mock.ExpectExec(regexp.QuoteMeta(`DROP SCHEMA "experiment_surge" CASCADE`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	require.NoError(t, manager.Drop(ctx, "surge"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestManager_CreateExisting(t *testing.T) {
+	manager, _, mock := newManager(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE SCHEMA "experiment_surge"`)).
+		WillReturnError(&pq.Error{Code: "42P06"})
+	mock.ExpectRollback()
+
+	_, err := manager.Create(context.Background(), "surge")
+	assert.ErrorIs(t, err, experiment.ErrExperimentExists)
+}
+
+func TestManager_ValidatesNames(t *testing.T) {
+	manager, target, mock := newManager(t)
+	ctx := context.Background()
+
+	var validation *models.ValidationError
+	for _, name := range []string{"Surge", "1st", "surge-v2", `x"; DROP SCHEMA public; --`} {
+		_, err := manager.Create(ctx, name)
+		assert.True(t, errors.As(err, &validation), name)
+	}
+
+	var notFound *models.NotFoundError
+	expectExists(mock, "experiment_missing", false)
+	_, err := manager.Activate(ctx, "missing")
+	assert.True(t, errors.As(err, &notFound))
+	assert.Empty(t, target.Schema())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestManager_List(t *testing.T) {
+	manager, _, mock := newManager(t)
+
+	mock.ExpectQuery("SELECT nspname FROM pg_namespace").
+		WithArgs("experiment_").
+		WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("experiment_baseline").AddRow("experiment_surge"))
+
+	experiments, err := manager.List(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []*experiment.Experiment{
+		{Name: "baseline", Schema: "experiment_baseline"},
+		{Name: "surge", Schema: "experiment_surge"},
+	}, experiments)
+}
@@ -0,0 +1,93 @@
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/filter"
+	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_SQL(t *testing.T) {
+	f, err := filter.Parse(`severity>=warning AND actor_type=trip AND message~"time_out 50%"`, filter.EventLogFields)
+	require.NoError(t, err)
+
+	where, args := f.SQL(3)
+	assert.Equal(t, `((severity IN ($3, $4, $5) AND actor_type = $6) AND LOWER(message) LIKE $7 ESCAPE '\')`, where)
+	assert.Equal(t, []interface{}{"warn", "error", "fatal", "trip", `%time\_out 50\%%`}, args)
+}
+
+func TestParse_PrecedenceAndNot(t *testing.T) {
+	f, err := filter.Parse(`status=error OR NOT (duration_ms<=100 and operation_name!=match)`, filter.DistributedTraceFields)
+	require.NoError(t, err)
+
+	where, args := f.SQL(1)
+	assert.Equal(t, `(status = $1 OR NOT COALESCE((duration_ms <= $2 AND operation_name <> $3), FALSE))`, where)
+	assert.Equal(t, []interface{}{"error", 100.0, "match"}, args)
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		expression string
+		message    string
+	}{
+		{"", "at position 0: expected a field name, got \"end of expression\""},
+		{"colour=red", "at position 0: unknown field \"colour\""},
+		{"severity=loud", "at position 9: severity must be one of debug, info, warn, error, fatal"},
+		{"event_category>error", "at position 15: event_category does not support >"},
+		{"timestamp>yesterday", "at position 10: timestamp must be compared with an RFC3339 time"},
+		{"message~\"open", "at position 8: unterminated string"},
+		{"(severity=info", "at position 14: expected \")\", got \"end of expression\""},
+		{"severity=info severity=warn", "at position 14: unexpected \"severity\""},
+		{"severity=>info", "at position 9: expected a value after severity=, got \">\""},
+		{"severity!info", "at position 8: unknown operator \"!\""},
+	} {
+		_, err := filter.Parse(tc.expression, filter.EventLogFields)
+		var syntaxErr *filter.SyntaxError
+		if assert.ErrorAs(t, err, &syntaxErr, tc.expression) {
+			assert.Equal(t, tc.message, err.Error(), tc.expression)
+		}
+	}
+}
+
+func TestFilter_Match(t *testing.T) {
+	actorType := models.ActorTypeTrip
+	traceID := uuid.New()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	event := map[string]interface{}{
+		"severity":   models.EventSeverityError,
+		"actor_type": &actorType,
+		"actor_id":   (*string)(nil),
+		"message":    "Matching TIMED OUT",
+		"trace_id":   &traceID,
+		"timestamp":  now,
+	}
+	lookup := func(field string) interface{} { return event[field] }
+
+	for expression, want := range map[string]bool{
+		`severity>=warn AND actor_type=trip AND message~"timed out"`: true,
+		`severity>error`:                                       false,
+		`severity<warn OR message!~timed`:                      false,
+		`actor_id=driver-1`:                                    false,
+		`actor_id!=driver-1`:                                   false, // absent fields never compare
+		`NOT actor_id=driver-1`:                                true,
+		`trace_id=` + traceID.String():                         true,
+		`timestamp>2024-05-01T11:00:00Z`:                       true,
+		`timestamp<=2024-05-01T11:00:00Z`:                      false,
+		`(severity=info OR severity=error) AND NOT message~ok`: true,
+	} {
+		f, err := filter.Parse(expression, filter.EventLogFields)
+		require.NoError(t, err, expression)
+		assert.Equal(t, want, f.Match(lookup), expression)
+	}
+
+	f, err := filter.Parse(`retry_count>=2 AND processing_duration_ms<100`, filter.ActorMessageFields)
+	require.NoError(t, err)
+	duration := 40
+	message := map[string]interface{}{"retry_count": 3, "processing_duration_ms": &duration}
+	assert.True(t, f.Match(func(field string) interface{} { return message[field] }))
+}
@@ -0,0 +1,96 @@
+package forecast
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/forecast"
+)
+
+var testParams = forecast.Params{MovingAverageWindow: 24, Alpha: 0.3, Beta: 0.05, Gamma: 0.2}
+
+// dailyDemand returns days of hourly demand peaking at 08:00 and 18:00
+func dailyDemand(days int) []float64 {
+	series := make([]float64, days*forecast.SeasonLength)
+	for i := range series {
+		hour := i % forecast.SeasonLength
+		series[i] = 10 + 8*math.Exp(-math.Pow(float64(hour-8), 2)/4) + 12*math.Exp(-math.Pow(float64(hour-18), 2)/4)
+	}
+	return series
+}
+
+func TestParseZone(t *testing.T) {
+	// Central Jakarta
+	zone, err := forecast.ParseZone("QQGUW")
+	require.NoError(t, err)
+	assert.Equal(t, "qqguw", zone.Geohash)
+	assert.True(t, zone.Contains(-6.2, 106.82))
+	assert.False(t, zone.Contains(-6.3, 106.82))
+	assert.InDelta(t, 0.0439, zone.MaxLat-zone.MinLat, 0.001)
+	assert.InDelta(t, 0.0439, zone.MaxLng-zone.MinLng, 0.001)
+
+	for _, invalid := range []string{"", "qqguwxyz", "qqgua"} {
+		_, err := forecast.ParseZone(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestHourlyCounts(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	counts := forecast.HourlyCounts([]time.Time{
+		start.Add(-time.Minute),
+		start,
+		start.Add(59 * time.Minute),
+		start.Add(2*time.Hour + time.Second),
+		start.Add(3 * time.Hour),
+	}, start, 3)
+
+	assert.Equal(t, []float64{2, 0, 1}, counts)
+}
+
+func TestMovingAverage(t *testing.T) {
+	predicted, err := forecast.MovingAverage([]float64{100, 1, 2, 3}, 3, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{2, 2}, predicted)
+
+	_, err = forecast.MovingAverage([]float64{1, 2}, 3, 2)
+	assert.ErrorIs(t, err, forecast.ErrInsufficientHistory)
+}
+
+func TestHoltWinters_LearnsDailySeason(t *testing.T) {
+	series := dailyDemand(7)
+
+	predicted, err := forecast.HoltWinters(series, 0.3, 0.05, 0.2, forecast.SeasonLength)
+	require.NoError(t, err)
+	for i, v := range predicted {
+		assert.InDelta(t, series[i], v, 0.5, "hour %d", i)
+	}
+
+	_, err = forecast.HoltWinters(series[:30], 0.3, 0.05, 0.2, 1)
+	assert.ErrorIs(t, err, forecast.ErrInsufficientHistory)
+}
+
+func TestBacktest_HoltWintersBeatsMovingAverageOnSeasonalDemand(t *testing.T) {
+	series := dailyDemand(7)
+
+	hw, err := forecast.Backtest(forecast.MethodHoltWinters, series, 24, testParams)
+	require.NoError(t, err)
+	ma, err := forecast.Backtest(forecast.MethodMovingAverage, series, 24, testParams)
+	require.NoError(t, err)
+
+	assert.Equal(t, 24, hw.Points)
+	assert.Less(t, hw.MAE, ma.MAE)
+	assert.Less(t, hw.RMSE, ma.RMSE)
+	assert.Less(t, hw.MAPE, 5.0)
+}
+
+func TestBacktest_MAPEUndefinedWithoutDemand(t *testing.T) {
+	accuracy, err := forecast.Backtest(forecast.MethodMovingAverage, make([]float64, 48), 24, testParams)
+	require.NoError(t, err)
+	assert.Zero(t, accuracy.MAE)
+	assert.True(t, math.IsNaN(accuracy.MAPE))
+}
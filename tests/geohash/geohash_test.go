@@ -0,0 +1,50 @@
+package geohash
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/geohash"
+)
+
+func TestDecode(t *testing.T) {
+	// Central Jakarta
+	box, err := geohash.Decode("QQGUW")
+	require.NoError(t, err)
+	assert.True(t, box.Contains(-6.2, 106.82))
+	assert.False(t, box.Contains(-6.3, 106.82))
+
+	for _, invalid := range []string{"", "qqguwqqguwqqg", "qqgua"} {
+		_, err := geohash.Decode(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestGrid_LocateMatchesDecode(t *testing.T) {
+	points := [][2]float64{{-6.2, 106.82}, {40.7128, -74.006}, {51.5074, -0.1278}, {-33.8688, 151.2093}, {0, 0}}
+	for precision := 1; precision <= 8; precision++ {
+		grid, err := geohash.NewGrid(precision)
+		require.NoError(t, err)
+
+		for _, p := range points {
+			cell := grid.Locate(p[0], p[1])
+			hash := grid.Geohash(cell)
+			require.Len(t, hash, precision)
+
+			box, err := geohash.Decode(hash)
+			require.NoError(t, err)
+			assert.True(t, box.Contains(p[0], p[1]), "%s should contain %v", hash, p)
+			assert.Equal(t, box, grid.Bounds(cell))
+		}
+	}
+
+	grid, err := geohash.NewGrid(5)
+	require.NoError(t, err)
+	assert.Equal(t, "qqguw", grid.Geohash(grid.Locate(-6.2, 106.82)))
+	assert.Equal(t, "zzzzz", grid.Geohash(grid.Locate(90, 180)), "the poles and antimeridian are clamped")
+
+	_, err = geohash.NewGrid(0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,228 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/graphql"
+)
+
+type node struct {
+	Name     string  `json:"name"`
+	Score    float64 `json:"score"`
+	Children []*node `json:"children"`
+}
+
+// newTestSchema returns a schema over a small tree of nodes
+func newTestSchema() *graphql.Schema {
+	root := &node{Name: "root", Score: 1.5, Children: []*node{
+		{Name: "a", Children: []*node{{Name: "a1"}}},
+		{Name: "b"},
+	}}
+
+	nodeType := &graphql.Object{Name: "Node"}
+	nodeType.Fields = map[string]*graphql.Field{
+		"name":     {Type: graphql.String},
+		"score":    {Type: graphql.Float},
+		"children": {Type: graphql.List{Of: nodeType}},
+		"greeting": {
+			Type: graphql.String,
+			Args: map[string]*graphql.ArgumentDefinition{
+				"prefix": {Type: graphql.String, Default: "hello"},
+				"times":  {Type: graphql.Int, Default: 1},
+			},
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				greeting := ""
+				for i := 0; i < p.Args["times"].(int); i++ {
+					greeting += p.Args["prefix"].(string) + " "
+				}
+				return greeting + p.Source.(*node).Name, nil
+			},
+		},
+	}
+
+	return &graphql.Schema{
+		Query: &graphql.Object{
+			Name: "Query",
+			Fields: map[string]*graphql.Field{
+				"root": {
+					Type:    nodeType,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) { return root, nil },
+				},
+			},
+		},
+		MaxDepth: 4,
+	}
+}
+
+func execute(t *testing.T, params graphql.Params) (*graphql.Result, string) {
+	result := newTestSchema().Execute(context.Background(), params)
+	body, err := json.Marshal(result)
+	require.NoError(t, err)
+	return result, string(body)
+}
+
+func TestExecute_FragmentsAndAliases(t *testing.T) {
+	_, body := execute(t, graphql.Params{Query: `
+		query Tree {
+			root {
+				...NodeFields
+				kids: children {
+					... on Node { name }
+					children { name }
+				}
+			}
+		}
+
+		fragment NodeFields on Node {
+			name
+			score
+		}
+	`})
+
+	assert.Equal(t, `{"data":{"root":{"name":"root","score":1.5,"kids":[{"name":"a","children":[{"name":"a1"}]},{"name":"b","children":[]}]}}}`, body)
+}
+
+func TestExecute_MergesRepeatedFields(t *testing.T) {
+	_, body := execute(t, graphql.Params{Query: `{ root { children { name } children { score } } }`})
+
+	assert.Equal(t, `{"data":{"root":{"children":[{"name":"a","score":0},{"name":"b","score":0}]}}}`, body)
+}
+
+func TestExecute_ArgumentsAndVariables(t *testing.T) {
+	_, body := execute(t, graphql.Params{
+		Query: `query Greet($prefix: String = "hi", $times: Int) {
+			root {
+				defaults: greeting
+				literal: greeting(prefix: "hey", times: 2)
+				variables: greeting(prefix: $prefix, times: $times)
+			}
+		}`,
+		Variables: map[string]interface{}{"times": float64(3)},
+	})
+
+	assert.Equal(t, `{"data":{"root":{"defaults":"hello root","literal":"hey hey root","variables":"hi hi hi root"}}}`, body)
+}
+
+func TestExecute_SkipAndInclude(t *testing.T) {
+	_, body := execute(t, graphql.Params{
+		Query: `query($full: Boolean!) {
+			root {
+				name
+				score @include(if: $full)
+				children @skip(if: true) { name }
+			}
+		}`,
+		Variables: map[string]interface{}{"full": false},
+	})
+
+	assert.Equal(t, `{"data":{"root":{"name":"root"}}}`, body)
+}
+
+func TestExecute_OperationName(t *testing.T) {
+	query := `query One { root { name } } query Two { root { score } }`
+
+	result, _ := execute(t, graphql.Params{Query: query})
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, "Must provide operation name if query contains multiple operations", result.Errors[0].Message)
+
+	_, body := execute(t, graphql.Params{Query: query, OperationName: "Two"})
+	assert.Equal(t, `{"data":{"root":{"score":1.5}}}`, body)
+}
+
+func TestExecute_ValidationErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		message string
+	}{
+		{"unknown field", `{ root { age } }`, `Cannot query field "age" on type "Node"`},
+		{"unknown argument", `{ root { name(upper: true) } }`, `Unknown argument "upper" on "name"`},
+		{"invalid argument", `{ root { greeting(times: "two") } }`, `Argument "times" has invalid value: Int cannot represent two`},
+		{"missing subselection", `{ root }`, `Field "root" of type "Node" must have a selection of subfields`},
+		{"selection on scalar", `{ root { name { length } } }`, `Field "name" must not have a selection since type "String" has no subfields`},
+		{"unknown fragment", `{ root { ...Missing } }`, `Unknown fragment "Missing"`},
+		{"fragment cycle", `{ root { ...A } } fragment A on Node { children { ...A } }`, `Cannot spread fragment "A" within itself`},
+		{"wrong fragment type", `{ root { ... on Query { root { name } } } }`, `Fragment on "Query" cannot be spread on type "Node"`},
+		{"undefined variable", `{ root { greeting(prefix: $p) } }`, `Variable "$p" is not defined`},
+		{"too deep", `{ root { children { children { children { children { name } } } } } }`, `Query exceeds the maximum depth of 4`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := execute(t, graphql.Params{Query: tt.query})
+
+			assert.Nil(t, result.Data)
+			require.NotEmpty(t, result.Errors)
+			assert.Equal(t, tt.message, result.Errors[0].Message)
+		})
+	}
+}
+
+func TestExecute_RequiredVariableMissing(t *testing.T) {
+	result, _ := execute(t, graphql.Params{Query: `query($p: String!) { root { greeting(prefix: $p) } }`})
+
+	assert.Nil(t, result.Data)
+	require.Len(t, result.Errors, 1)
+	assert.Equal(t, `Variable "$p" of required type "String!" was not provided`, result.Errors[0].Message)
+}
+
+func TestParse_SyntaxErrors(t *testing.T) {
+	tests := []struct {
+		query    string
+		message  string
+		location graphql.Location
+	}{
+		{"{\n  root {\n    name(\n  }\n}", `Syntax Error: Unexpected "}"`, graphql.Location{Line: 4, Column: 3}},
+		{`{ root { name } `, "Syntax Error: Unexpected end of document", graphql.Location{Line: 1, Column: 17}},
+		{`{ root { name: "x" } }`, `Syntax Error: Unexpected "x"`, graphql.Location{Line: 1, Column: 16}},
+		{`{ }`, "Syntax Error: Selection set must not be empty", graphql.Location{Line: 1, Column: 4}},
+		{`{ root { greeting(prefix: "open) } }`, "Syntax Error: Unterminated string", graphql.Location{Line: 1, Column: 37}},
+		{`{ root { greeting(times: 01) } }`, "Syntax Error: Invalid number, unexpected digit after 0", graphql.Location{Line: 1, Column: 27}},
+		{`subscription { root { name } }`, "Syntax Error: subscription operations are not supported", graphql.Location{Line: 1, Column: 1}},
+		{`fragment F on Node { name }`, "Document does not contain an operation", graphql.Location{Line: 1, Column: 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := graphql.Parse(tt.query)
+
+			require.Error(t, err)
+			gqlErr, ok := err.(*graphql.Error)
+			require.True(t, ok)
+			assert.Equal(t, tt.message, gqlErr.Message)
+			assert.Equal(t, []graphql.Location{tt.location}, gqlErr.Locations)
+		})
+	}
+}
+
+func TestParse_Literals(t *testing.T) {
+	doc, err := graphql.Parse(`
+		# comments and commas are ignored
+		query Q($n: Int = -12, $f: Float = 1.5e3, $s: String = "tab\there é", $b: String = """
+			block
+			  string
+		""") {
+			root(list: [1, "two", null], object: {key: ENUM_VALUE, on: true},) { name }
+		}
+	`)
+	require.NoError(t, err)
+	require.Len(t, doc.Operations, 1)
+
+	op := doc.Operations[0]
+	assert.Equal(t, "Q", op.Name)
+	require.Len(t, op.Variables, 4)
+	assert.Equal(t, int64(-12), op.Variables[0].Default)
+	assert.Equal(t, 1500.0, op.Variables[1].Default)
+	assert.Equal(t, "tab\there é", op.Variables[2].Default)
+	assert.Equal(t, "block\n  string", op.Variables[3].Default)
+
+	field := op.SelectionSet[0].(*graphql.FieldSelection)
+	require.Len(t, field.Arguments, 2)
+	assert.Equal(t, graphql.ListValue{int64(1), "two", nil}, field.Arguments[0].Value)
+	assert.Equal(t, graphql.ObjectValue{"key": "ENUM_VALUE", "on": true}, field.Arguments[1].Value)
+}
@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/tests/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// auditRecorder keeps the event logs published by a handler
+type auditRecorder struct {
+	mu     sync.Mutex
+	events []*models.EventLog
+}
+
+func (r *auditRecorder) Publish(topic string, payload interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if event, ok := payload.(*models.EventLog); ok && topic == bus.TopicEventLog {
+		r.events = append(r.events, event)
+	}
+}
+
+func setupActorMailboxRouter(t *testing.T) (*gin.Engine, *actor.ActorSystem, *auditRecorder) {
+	logger := utils.NewTestLogger(t)
+
+	system := actor.NewSimulatedActorSystem("test", actor.NewSimulatedClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	require.NoError(t, system.Start(context.Background()))
+	t.Cleanup(func() { _ = system.Stop() })
+	_, err := system.SpawnActor("trip", "trip-1", 10, func(actor.Message) error { return nil }, actor.SupervisionRestart)
+	require.NoError(t, err)
+
+	audits := &auditRecorder{}
+	handler := handlers.NewActorMailboxHandler(system, audits, logger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.OperatorRoleMiddleware([]string{"operator-key"}))
+	requireOperator := middleware.RequireOperatorMiddleware()
+	router.GET("/api/v1/admin/actors/:id/mailbox", requireOperator, handler.PeekActorMailbox)
+	router.POST("/api/v1/admin/actors/:id/messages", requireOperator, handler.InjectActorMessage)
+	return router, system, audits
+}
+
+func TestActorMailboxHandler_RequiresOperator(t *testing.T) {
+	router, system, audits := setupActorMailboxRouter(t)
+
+	req, _ := http.NewRequest("GET", "/api/v1/admin/actors/trip-1/mailbox", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	body := `{"type":"nudge","reason":"stuck"}`
+	req, _ = http.NewRequest("POST", "/api/v1/admin/actors/trip-1/messages", bytes.NewBufferString(body))
+	req.Header.Set(middleware.OperatorKeyHeader, "wrong-key")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	snapshot, err := system.PeekMailbox("trip-1", 0)
+	require.NoError(t, err)
+	assert.Zero(t, snapshot.Pending)
+	assert.Empty(t, audits.events)
+}
+
+func TestActorMailboxHandler_InjectAndPeek(t *testing.T) {
+	router, system, audits := setupActorMailboxRouter(t)
+	tripID := uuid.New().String()
+
+	body := `{"type":"nudge","payload":{"attempt":2},"entity_type":"trip","entity_id":"` + tripID + `","reason":"Trip stuck in matching"}`
+	req, _ := http.NewRequest("POST", "/api/v1/admin/actors/trip-1/messages", bytes.NewBufferString(body))
+	req.Header.Set(middleware.OperatorKeyHeader, "operator-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	var injected struct {
+		ActorID string            `json:"actor_id"`
+		Message actor.BaseMessage `json:"message"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &injected))
+	assert.Equal(t, "trip-1", injected.ActorID)
+	assert.Equal(t, "admin", injected.Message.Sender)
+	assert.Equal(t, actor.InitialMessageVersion, injected.Message.Version)
+
+	req, _ = http.NewRequest("GET", "/api/v1/admin/actors/trip-1/mailbox?limit=10", nil)
+	req.Header.Set(middleware.OperatorKeyHeader, "operator-key")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var snapshot struct {
+		Pending  int                 `json:"pending"`
+		Capacity int                 `json:"capacity"`
+		Messages []actor.BaseMessage `json:"messages"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &snapshot))
+	assert.Equal(t, 1, snapshot.Pending)
+	assert.Equal(t, 10, snapshot.Capacity)
+	require.Len(t, snapshot.Messages, 1)
+	assert.Equal(t, injected.Message.ID, snapshot.Messages[0].ID)
+	assert.Equal(t, "nudge", snapshot.Messages[0].Type)
+	assert.Equal(t, tripID, snapshot.Messages[0].EntityID)
+
+	// Both calls are audited, the injection against the trip
+	require.Len(t, audits.events, 2)
+	injection := audits.events[0]
+	assert.Equal(t, "actor_message_injected", injection.EventType)
+	assert.Equal(t, models.EventCategorySecurity, injection.EventCategory)
+	assert.Equal(t, "trip-1", *injection.ActorID)
+	require.NotNil(t, injection.EntityID)
+	assert.Equal(t, tripID, injection.EntityID.String())
+	var data map[string]interface{}
+	require.NoError(t, json.Unmarshal(injection.EventData, &data))
+	assert.Equal(t, "Trip stuck in matching", data["reason"])
+	assert.Equal(t, injected.Message.ID, data["message_id"])
+	assert.Equal(t, "actor_mailbox_peeked", audits.events[1].EventType)
+
+	assert.Equal(t, 1, system.RunUntilIdle())
+}
+
+func TestActorMailboxHandler_Errors(t *testing.T) {
+	router, _, audits := setupActorMailboxRouter(t)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+	}{
+		{"unknown actor peek", "GET", "/api/v1/admin/actors/trip-2/mailbox", "", http.StatusNotFound},
+		{"invalid limit", "GET", "/api/v1/admin/actors/trip-1/mailbox?limit=0", "", http.StatusBadRequest},
+		{"unknown actor injection", "POST", "/api/v1/admin/actors/trip-2/messages", `{"type":"nudge","reason":"stuck"}`, http.StatusNotFound},
+		{"missing reason", "POST", "/api/v1/admin/actors/trip-1/messages", `{"type":"nudge"}`, http.StatusBadRequest},
+		{"entity without ID", "POST", "/api/v1/admin/actors/trip-1/messages", `{"type":"nudge","reason":"stuck","entity_type":"trip"}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.path, bytes.NewBufferString(tt.body))
+			req.Header.Set(middleware.OperatorKeyHeader, "operator-key")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+		})
+	}
+	assert.Empty(t, audits.events)
+}
+
+func TestActorMailboxHandler_Unavailable(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/admin/actors/:id/mailbox", handlers.NewActorMailboxHandler(nil, nil, logger).PeekActorMailbox)
+
+	req, _ := http.NewRequest("GET", "/api/v1/admin/actors/trip-1/mailbox", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"actor-model-observability/internal/appversion"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/tests/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func setupAppVersionHandler(t *testing.T) (*gin.Engine, *utils.MockSessionService) {
+	gate, err := appversion.NewGate(config.AppVersionConfig{
+		MinVersions:     map[string]string{"android": "2.1.0"},
+		FeatureVersions: map[string]string{"offer_websocket": "2.4.0"},
+	}, nil)
+	require.NoError(t, err)
+
+	router, mockSessionService, _ := utils.SetupAuthHandler()
+	appVersionHandler := handlers.NewAppVersionHandler(gate, mockSessionService)
+	router.Use(middleware.AppVersionMiddleware(gate))
+	router.GET("/api/v1/meta/capabilities", appVersionHandler.GetCapabilities)
+	router.GET("/api/v1/admin/app-versions", appVersionHandler.GetAppVersions)
+	return router, mockSessionService
+}
+
+func TestAppVersionHandler_GetCapabilities(t *testing.T) {
+	router, _ := setupAppVersionHandler(t)
+
+	get := func(version string) handlers.CapabilitiesResponse {
+		req, _ := http.NewRequest("GET", "/api/v1/meta/capabilities", nil)
+		if version != "" {
+			req.Header.Set(appversion.PlatformHeader, "android")
+			req.Header.Set(appversion.VersionHeader, version)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response handlers.CapabilitiesResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	assert.Equal(t, handlers.CapabilitiesResponse{
+		Platform:   "android",
+		Version:    "2.4.1",
+		MinVersion: "2.1.0",
+		Features:   []string{"offer_websocket"},
+	}, get("2.4.1"))
+	assert.Empty(t, get("2.3.0").Features)
+	assert.Equal(t, handlers.CapabilitiesResponse{Features: []string{}}, get(""))
+}
+
+func TestAppVersionHandler_GetAppVersions(t *testing.T) {
+	router, mockSessionService := setupAppVersionHandler(t)
+	mockSessionService.On("CountSessionsByAppVersion", mock.Anything).
+		Return([]*models.AppVersionSessions{{Platform: "android", Version: "2.0.0", Sessions: 4}}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/admin/app-versions", nil)
+	req.Header.Set(appversion.PlatformHeader, "android")
+	req.Header.Set(appversion.VersionHeader, "2.0.0")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUpgradeRequired, w.Code)
+
+	req, _ = http.NewRequest("GET", "/api/v1/admin/app-versions", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response handlers.AppVersionsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, map[string]string{"android": "2.1.0"}, response.MinVersions)
+	require.Len(t, response.Requests, 1)
+	assert.Equal(t, int64(1), response.Requests[0].Rejected)
+	require.Len(t, response.Sessions, 1)
+	assert.Equal(t, int64(4), response.Sessions[0].Sessions)
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+)
+
+func newTestSession() *models.Session {
+	return &models.Session{
+		ID:              uuid.New(),
+		UserID:          uuid.New(),
+		UserType:        models.UserTypeDriver,
+		DeviceID:        "device-1",
+		AccessExpiresAt: time.Now().Add(15 * time.Minute),
+		ExpiresAt:       time.Now().Add(24 * time.Hour),
+		LastUsedAt:      time.Now(),
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+}
+
+func TestAuthHandler_Login_Success(t *testing.T) {
+	router, mockService, authHandler := utils.SetupAuthHandler()
+	router.POST("/api/v1/auth/sessions", authHandler.Login)
+
+	session := newTestSession()
+	mockService.On("Login", mock.Anything, mock.MatchedBy(func(creds service.LoginCredentials) bool {
+		return creds.Email == "driver@example.com" && creds.Phone == "+6281234567890" && creds.DeviceID == "device-1"
+	})).Return(&models.SessionTokens{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		TokenType:    "Bearer",
+		ExpiresIn:    900,
+		Session:      session,
+	}, nil)
+
+	body, _ := json.Marshal(handlers.LoginRequest{
+		Email:    "driver@example.com",
+		Phone:    "+6281234567890",
+		DeviceID: "device-1",
+	})
+	req, _ := http.NewRequest("POST", "/api/v1/auth/sessions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.SessionTokens
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "access", response.AccessToken)
+	assert.Equal(t, "refresh", response.RefreshToken)
+	assert.Equal(t, session.ID, response.Session.ID)
+	assert.NotContains(t, w.Body.String(), "token_hash")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_Login_InvalidCredentials(t *testing.T) {
+	router, mockService, authHandler := utils.SetupAuthHandler()
+	router.POST("/api/v1/auth/sessions", authHandler.Login)
+
+	mockService.On("Login", mock.Anything, mock.Anything).Return(nil, models.ErrInvalidCredentials)
+
+	body, _ := json.Marshal(handlers.LoginRequest{
+		Email:    "driver@example.com",
+		Phone:    "+6200000000000",
+		DeviceID: "device-1",
+	})
+	req, _ := http.NewRequest("POST", "/api/v1/auth/sessions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_Login_MissingDeviceID(t *testing.T) {
+	router, mockService, authHandler := utils.SetupAuthHandler()
+	router.POST("/api/v1/auth/sessions", authHandler.Login)
+
+	body, _ := json.Marshal(handlers.LoginRequest{Email: "driver@example.com", Phone: "+6281234567890"})
+	req, _ := http.NewRequest("POST", "/api/v1/auth/sessions", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "Login", mock.Anything, mock.Anything)
+}
+
+func TestAuthHandler_RefreshSession_InvalidToken(t *testing.T) {
+	router, mockService, authHandler := utils.SetupAuthHandler()
+	router.POST("/api/v1/auth/sessions/refresh", authHandler.RefreshSession)
+
+	mockService.On("Refresh", mock.Anything, "stale", mock.Anything, service.ClientApp{}).Return(nil, models.ErrInvalidToken)
+
+	body, _ := json.Marshal(handlers.RefreshSessionRequest{RefreshToken: "stale"})
+	req, _ := http.NewRequest("POST", "/api/v1/auth/sessions/refresh", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_ListSessions_RequiresToken(t *testing.T) {
+	router, mockService, authHandler := utils.SetupAuthHandler()
+	router.GET("/api/v1/auth/sessions", middleware.SessionAuthMiddleware(mockService), authHandler.ListSessions)
+
+	mockService.On("Authenticate", mock.Anything, "expired").Return(nil, models.ErrInvalidToken)
+
+	for _, authorization := range []string{"", "Basic abc", "Bearer expired"} {
+		req, _ := http.NewRequest("GET", "/api/v1/auth/sessions", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code, authorization)
+	}
+	mockService.AssertNotCalled(t, "ListSessions", mock.Anything, mock.Anything)
+}
+
+func TestAuthHandler_ListSessions_Success(t *testing.T) {
+	router, mockService, authHandler := utils.SetupAuthHandler()
+	router.GET("/api/v1/auth/sessions", middleware.SessionAuthMiddleware(mockService), authHandler.ListSessions)
+
+	session := newTestSession()
+	mockService.On("Authenticate", mock.Anything, "access").Return(session, nil)
+	mockService.On("ListSessions", mock.Anything, session.UserID.String()).Return([]*models.Session{session}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/auth/sessions", nil)
+	req.Header.Set("Authorization", "Bearer access")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []models.Session
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response, 1)
+	assert.Equal(t, session.ID, response[0].ID)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAuthHandler_RevokeSession(t *testing.T) {
+	session := newTestSession()
+	otherID := uuid.New().String()
+
+	tests := []struct {
+		name           string
+		id             string
+		err            error
+		expectedStatus int
+	}{
+		{name: "revoked", id: session.ID.String(), expectedStatus: http.StatusNoContent},
+		{name: "not found", id: otherID, err: &models.NotFoundError{Resource: "session", ID: otherID}, expectedStatus: http.StatusNotFound},
+		{name: "invalid ID", id: "not-a-uuid", expectedStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, mockService, authHandler := utils.SetupAuthHandler()
+			router.DELETE("/api/v1/auth/sessions/:id", middleware.SessionAuthMiddleware(mockService), authHandler.RevokeSession)
+
+			mockService.On("Authenticate", mock.Anything, "access").Return(session, nil)
+			if tt.expectedStatus != http.StatusBadRequest {
+				mockService.On("RevokeSession", mock.Anything, session.UserID.String(), tt.id, mock.Anything).Return(tt.err)
+			}
+
+			req, _ := http.NewRequest("DELETE", "/api/v1/auth/sessions/"+tt.id, nil)
+			req.Header.Set("Authorization", "Bearer access")
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedStatus, w.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/storage"
+	"actor-model-observability/tests/utils"
+)
+
+// setupAvatarRouter serves the avatar and file endpoints over in-memory users and a local store
+func setupAvatarRouter(t *testing.T) (*gin.Engine, repository.UserRepository) {
+	logger := utils.NewTestLogger(t)
+	store, err := storage.NewLocalStore(t.TempDir(), "secret")
+	require.NoError(t, err)
+
+	memStore := memory.NewStore()
+	userRepo := memory.NewUserRepository(memStore)
+	cfg := config.DefaultStorageConfig()
+	cfg.MaxAvatarSize = 1024
+
+	userHandler := handlers.NewUserHandler(userRepo, memory.NewDriverRepository(memStore), memory.NewPassengerRepository(memStore))
+	userHandler.SetAvatarService(service.NewAvatarService(userRepo, store, cfg, logger))
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/users/:id", userHandler.GetUser)
+	router.PUT("/api/v1/users/:id/avatar", userHandler.UploadAvatar)
+	router.DELETE("/api/v1/users/:id/avatar", userHandler.DeleteAvatar)
+	router.GET("/api/v1/files/*key", handlers.NewFileHandler(store).GetFile)
+	return router, userRepo
+}
+
+func createAvatarUser(t *testing.T, userRepo repository.UserRepository) *models.User {
+	user := &models.User{
+		ID:        uuid.New(),
+		Email:     "avatar@example.com",
+		Phone:     "+1234567890",
+		Name:      "Avatar User",
+		UserType:  models.UserTypePassenger,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, userRepo.Create(context.Background(), user))
+	return user
+}
+
+func pngPicture(t *testing.T) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4))))
+	return buf.Bytes()
+}
+
+func TestUserHandler_UploadAvatar(t *testing.T) {
+	router, userRepo := setupAvatarRouter(t)
+	user := createAvatarUser(t, userRepo)
+	picture := pngPicture(t)
+
+	req := httptest.NewRequest("PUT", "/api/v1/users/"+user.ID.String()+"/avatar", bytes.NewReader(picture))
+	req.Header.Set("Content-Type", "image/png")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var uploaded models.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &uploaded))
+	require.NotNil(t, uploaded.AvatarKey)
+	assert.Contains(t, *uploaded.AvatarKey, ".png")
+	assert.Contains(t, uploaded.AvatarURL, storage.LocalFilesPath+*uploaded.AvatarKey)
+
+	// The signed URL serves the picture
+	req = httptest.NewRequest("GET", uploaded.AvatarURL, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, picture, w.Body.Bytes())
+
+	// The user payload carries a URL
+	req = httptest.NewRequest("GET", "/api/v1/users/"+user.ID.String(), nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var fetched models.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &fetched))
+	assert.Contains(t, fetched.AvatarURL, *uploaded.AvatarKey)
+
+	// Removing the picture deletes the file
+	req = httptest.NewRequest("DELETE", "/api/v1/users/"+user.ID.String()+"/avatar", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var removed models.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &removed))
+	assert.Nil(t, removed.AvatarKey)
+	assert.Empty(t, removed.AvatarURL)
+
+	req = httptest.NewRequest("GET", uploaded.AvatarURL, nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestUserHandler_UploadAvatar_Multipart(t *testing.T) {
+	router, userRepo := setupAvatarRouter(t)
+	user := createAvatarUser(t, userRepo)
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("avatar", "me.png")
+	require.NoError(t, err)
+	_, err = part.Write(pngPicture(t))
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	req := httptest.NewRequest("PUT", "/api/v1/users/"+user.ID.String()+"/avatar", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var uploaded models.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &uploaded))
+	assert.NotEmpty(t, uploaded.AvatarURL)
+}
+
+func TestUserHandler_UploadAvatar_Invalid(t *testing.T) {
+	router, userRepo := setupAvatarRouter(t)
+	user := createAvatarUser(t, userRepo)
+
+	tests := []struct {
+		name   string
+		userID string
+		body   []byte
+		status int
+	}{
+		{"not an image", user.ID.String(), []byte("<html>not a picture</html>"), http.StatusBadRequest},
+		{"empty", user.ID.String(), nil, http.StatusBadRequest},
+		{"too large", user.ID.String(), append(pngPicture(t), make([]byte, 2048)...), http.StatusBadRequest},
+		{"unknown user", uuid.New().String(), pngPicture(t), http.StatusNotFound},
+		{"invalid user ID", "not-a-uuid", pngPicture(t), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/api/v1/users/"+tt.userID+"/avatar", bytes.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.status, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestUserHandler_UploadAvatar_Unavailable(t *testing.T) {
+	userHandler := handlers.NewUserHandler(&utils.MockUserRepository{}, &utils.MockDriverRepository{}, &utils.MockPassengerRepository{})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/api/v1/users/:id/avatar", userHandler.UploadAvatar)
+
+	req := httptest.NewRequest("PUT", "/api/v1/users/"+uuid.New().String()+"/avatar", bytes.NewReader(pngPicture(t)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestFileHandler_GetFile_InvalidSignature(t *testing.T) {
+	router, userRepo := setupAvatarRouter(t)
+	user := createAvatarUser(t, userRepo)
+
+	req := httptest.NewRequest("PUT", "/api/v1/users/"+user.ID.String()+"/avatar", bytes.NewReader(pngPicture(t)))
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var uploaded models.User
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &uploaded))
+
+	for _, path := range []string{
+		storage.LocalFilesPath + *uploaded.AvatarKey,
+		storage.LocalFilesPath + *uploaded.AvatarKey + "?expires=9999999999&signature=00",
+	} {
+		req = httptest.NewRequest("GET", path, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code, path)
+	}
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/tests/utils"
+)
+
+type graphqlResponse struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message string        `json:"message"`
+		Path    []interface{} `json:"path"`
+	} `json:"errors"`
+}
+
+func postGraphQL(t *testing.T, router http.Handler, query string, variables map[string]interface{}) (*httptest.ResponseRecorder, graphqlResponse) {
+	body, _ := json.Marshal(handlers.GraphQLRequest{Query: query, Variables: variables})
+	req, _ := http.NewRequest("POST", "/api/v1/graphql", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	var response graphqlResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return w, response
+}
+
+func TestGraphQLHandler_TripNestedResolution(t *testing.T) {
+	router, mockObsRepo, mockTripRepo, graphqlHandler := utils.SetupGraphQLHandler()
+	router.POST("/api/v1/graphql", graphqlHandler.Query)
+
+	tripID := uuid.New()
+	traceID := uuid.New()
+	spanID := uuid.New()
+	childSpanID := uuid.New()
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	trip := &models.Trip{ID: tripID, PassengerID: uuid.New(), Status: models.TripStatusMatched, RequestedAt: now}
+	event := &models.EventLog{ID: uuid.New(), TraceID: &traceID, EventType: "ride_request", Timestamp: now}
+	message := &models.ActorMessage{
+		ID:              uuid.New(),
+		TraceID:         traceID,
+		SpanID:          spanID,
+		SenderActorID:   "passenger-1",
+		ReceiverActorID: "matching-1",
+		MessageType:     "RideRequest",
+		MessagePayload:  json.RawMessage(`{"ride_type":"standard"}`),
+		SentAt:          now,
+	}
+	spans := []*models.DistributedTrace{
+		{ID: uuid.New(), TraceID: traceID, SpanID: spanID, OperationName: "handle_ride_request", StartTime: now},
+		{ID: uuid.New(), TraceID: traceID, SpanID: childSpanID, ParentSpanID: &spanID, OperationName: "find_driver", StartTime: now},
+		{ID: uuid.New(), TraceID: traceID, SpanID: uuid.New(), OperationName: "unrelated", StartTime: now},
+	}
+
+	mockTripRepo.On("GetByID", mock.Anything, tripID.String()).Return(trip, nil)
+	mockObsRepo.On("ListEventLogsByEntity", mock.Anything, "trip", tripID.String(), 5, 0).Return([]*models.EventLog{event}, nil)
+	mockObsRepo.On("GetMessagesByTraceID", mock.Anything, traceID.String()).Return([]*models.ActorMessage{message}, nil)
+	mockObsRepo.On("GetTracesByTraceID", mock.Anything, traceID.String()).Return(spans, nil)
+
+	query := `
+		query TripTimeline($id: ID!) {
+			trip(id: $id) {
+				id
+				status
+				events(limit: 5) {
+					eventType
+					messages {
+						... on ActorMessage { from: senderActorId }
+						messagePayload
+						spans { operationName parentSpanId }
+					}
+				}
+			}
+		}
+	`
+	w, response := postGraphQL(t, router, query, map[string]interface{}{"id": tripID.String()})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, response.Errors)
+	assert.Contains(t, w.Body.String(), `{"data":{"trip":{"id":"`+tripID.String()+`","status":"matched","events"`, "fields keep the selection order")
+
+	tripData := response.Data["trip"].(map[string]interface{})
+	events := tripData["events"].([]interface{})
+	require.Len(t, events, 1)
+	messages := events[0].(map[string]interface{})["messages"].([]interface{})
+	require.Len(t, messages, 1)
+	messageData := messages[0].(map[string]interface{})
+	assert.Equal(t, "passenger-1", messageData["from"])
+	assert.Equal(t, map[string]interface{}{"ride_type": "standard"}, messageData["messagePayload"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"operationName": "handle_ride_request", "parentSpanId": nil},
+		map[string]interface{}{"operationName": "find_driver", "parentSpanId": spanID.String()},
+	}, messageData["spans"])
+
+	mockTripRepo.AssertExpectations(t)
+	mockObsRepo.AssertExpectations(t)
+}
+
+func TestGraphQLHandler_ActorInstancesWithMessages(t *testing.T) {
+	router, mockObsRepo, _, graphqlHandler := utils.SetupGraphQLHandler()
+	router.POST("/api/v1/graphql", graphqlHandler.Query)
+
+	instance := &models.ActorInstance{ID: uuid.New(), ActorType: models.ActorTypeDriver, ActorID: "driver-1"}
+	mockObsRepo.On("ListActorInstances", mock.Anything, "driver", 20, 0).Return([]*models.ActorInstance{instance}, nil)
+	mockObsRepo.On("ListActorMessages", mock.Anything, "driver-1", "", 20, 0).Return(nil, nil)
+	mockObsRepo.On("ListActorMessages", mock.Anything, "", "driver-1", 2, 0).Return([]*models.ActorMessage{{ID: uuid.New(), MessageType: "LocationUpdate"}}, nil)
+
+	w, response := postGraphQL(t, router, `{
+		actorInstances(actorType: "driver") {
+			__typename
+			actorId
+			sentMessages { id }
+			receivedMessages(limit: 2) { messageType }
+		}
+	}`, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, response.Errors)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"__typename":       "ActorInstance",
+		"actorId":          "driver-1",
+		"sentMessages":     []interface{}{},
+		"receivedMessages": []interface{}{map[string]interface{}{"messageType": "LocationUpdate"}},
+	}}, response.Data["actorInstances"])
+
+	mockObsRepo.AssertExpectations(t)
+}
+
+func TestGraphQLHandler_ResolverErrorNullsField(t *testing.T) {
+	router, mockObsRepo, _, graphqlHandler := utils.SetupGraphQLHandler()
+	router.POST("/api/v1/graphql", graphqlHandler.Query)
+
+	mockObsRepo.On("ListDistributedTraces", mock.Anything, "", 20, 0).Return([]*models.DistributedTrace(nil), errors.New("database error"))
+	mockObsRepo.On("ListEventLogs", mock.Anything, "", "", 20, 0).Return([]*models.EventLog{}, nil)
+
+	w, response := postGraphQL(t, router, `{ traces { id } events { id } }`, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, response.Data["traces"])
+	assert.Equal(t, []interface{}{}, response.Data["events"])
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, "database error", response.Errors[0].Message)
+	assert.Equal(t, []interface{}{"traces"}, response.Errors[0].Path)
+}
+
+func TestGraphQLHandler_InvalidLimit(t *testing.T) {
+	router, _, _, graphqlHandler := utils.SetupGraphQLHandler()
+	router.POST("/api/v1/graphql", graphqlHandler.Query)
+
+	w, response := postGraphQL(t, router, `{ trips(limit: 500) { id } }`, nil)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Nil(t, response.Data["trips"])
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, "limit must be between 1 and 100", response.Errors[0].Message)
+}
+
+func TestGraphQLHandler_TripNotFound(t *testing.T) {
+	router, _, mockTripRepo, graphqlHandler := utils.SetupGraphQLHandler()
+	router.POST("/api/v1/graphql", graphqlHandler.Query)
+
+	tripID := uuid.New().String()
+	mockTripRepo.On("GetByID", mock.Anything, tripID).Return((*models.Trip)(nil), &models.NotFoundError{Resource: "trip", ID: tripID})
+
+	w, response := postGraphQL(t, router, `query($id: ID!) { trip(id: $id) { id } }`, map[string]interface{}{"id": tripID})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, response.Errors)
+	assert.Contains(t, response.Data, "trip")
+	assert.Nil(t, response.Data["trip"])
+}
+
+func TestGraphQLHandler_ValidationError(t *testing.T) {
+	router, mockObsRepo, mockTripRepo, graphqlHandler := utils.SetupGraphQLHandler()
+	router.POST("/api/v1/graphql", graphqlHandler.Query)
+
+	w, response := postGraphQL(t, router, `{ trip { id passwordHash } }`, nil)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Nil(t, response.Data)
+	require.Len(t, response.Errors, 2)
+	assert.Equal(t, `Argument "id" of type "ID!" is required on "trip"`, response.Errors[0].Message)
+	assert.Equal(t, `Cannot query field "passwordHash" on type "Trip"`, response.Errors[1].Message)
+
+	mockObsRepo.AssertNotCalled(t, "ListEventLogsByEntity")
+	mockTripRepo.AssertNotCalled(t, "GetByID")
+}
+
+func TestGraphQLHandler_SyntaxError(t *testing.T) {
+	router, _, _, graphqlHandler := utils.SetupGraphQLHandler()
+	router.POST("/api/v1/graphql", graphqlHandler.Query)
+
+	w, response := postGraphQL(t, router, `{ trips { id }`, nil)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, "Syntax Error: Unexpected end of document", response.Errors[0].Message)
+}
+
+func TestGraphQLHandler_MutationRejected(t *testing.T) {
+	router, _, _, graphqlHandler := utils.SetupGraphQLHandler()
+	router.POST("/api/v1/graphql", graphqlHandler.Query)
+
+	w, response := postGraphQL(t, router, `mutation { cancelTrip(id: "1") }`, nil)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.Len(t, response.Errors, 1)
+	assert.Equal(t, "Syntax Error: mutation operations are not supported", response.Errors[0].Message)
+}
+
+func TestGraphQLHandler_MissingQuery(t *testing.T) {
+	router, _, _, graphqlHandler := utils.SetupGraphQLHandler()
+	router.POST("/api/v1/graphql", graphqlHandler.Query)
+
+	req, _ := http.NewRequest("POST", "/api/v1/graphql", bytes.NewBufferString(`{"variables":{}}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGraphQLHandler_QueryGet(t *testing.T) {
+	router, mockObsRepo, _, graphqlHandler := utils.SetupGraphQLHandler()
+	router.GET("/api/v1/graphql", graphqlHandler.QueryGet)
+
+	mockObsRepo.On("ListEventLogs", mock.Anything, "actor_failed", "", 10, 0).Return([]*models.EventLog{{ID: uuid.New(), EventType: "actor_failed"}}, nil)
+
+	params := url.Values{}
+	params.Set("query", `query Failures($type: String, $limit: Int = 10) { events(eventType: $type, limit: $limit) { eventType } }`)
+	params.Set("variables", `{"type":"actor_failed"}`)
+	req, _ := http.NewRequest("GET", "/api/v1/graphql?"+params.Encode(), nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"events":[{"eventType":"actor_failed"}]}}`, w.Body.String())
+
+	mockObsRepo.AssertExpectations(t)
+}
+
+func TestGraphQLHandler_QueryGet_InvalidVariables(t *testing.T) {
+	router, _, _, graphqlHandler := utils.SetupGraphQLHandler()
+	router.GET("/api/v1/graphql", graphqlHandler.QueryGet)
+
+	req, _ := http.NewRequest("GET", "/api/v1/graphql?query=%7Btrips%7Bid%7D%7D&variables=nope", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid variables")
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/lock"
+	"actor-model-observability/tests/utils"
+)
+
+func TestLockHandler_ListLocks(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+	node := utils.NewRedisMock()
+	locker, err := lock.NewLocker([]redis.Cmdable{node}, config.DefaultLockConfig(), nil, logger)
+	require.NoError(t, err)
+
+	held, err := locker.TryAcquire(context.Background(), "retention")
+	require.NoError(t, err)
+	defer held.Release(context.Background())
+	node.Put("lock:scheduler", "worker-2-17/abc", time.Minute)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/admin/locks", handlers.NewLockHandler(locker).ListLocks)
+
+	req, _ := http.NewRequest("GET", "/api/v1/admin/locks", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response handlers.LockListResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, locker.Owner(), response.Owner)
+	require.Len(t, response.Locks, 2)
+	assert.Equal(t, "retention", response.Locks[0].Name)
+	assert.True(t, response.Locks[0].Local)
+	assert.Equal(t, "worker-2-17", response.Locks[1].Owner)
+}
+
+func TestLockHandler_ListLocks_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/admin/locks", handlers.NewLockHandler(nil).ListLocks)
+
+	req, _ := http.NewRequest("GET", "/api/v1/admin/locks", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
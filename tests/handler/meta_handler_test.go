@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/models"
+)
+
+func TestMetaHandler_GetErrorCatalog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/meta/errors", handlers.NewMetaHandler().GetErrorCatalog)
+
+	req, _ := http.NewRequest("GET", "/api/v1/meta/errors", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response handlers.ErrorCatalogResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, models.ErrorCatalog(), response.Codes)
+	assert.Contains(t, response.Codes, models.ErrorCodeInfo{
+		Code:        models.ErrorCodeRideNoDrivers,
+		Status:      http.StatusUnprocessableEntity,
+		Description: "No driver is available for the ride",
+	})
+}
@@ -2,19 +2,30 @@ package handler
 
 import (
 	"actor-model-observability/tests/utils"
+	"bufio"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/database"
 	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/middleware"
 	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/internal/redaction"
 )
 
 // Test GetActorInstances endpoint
@@ -153,6 +164,224 @@ func TestObservabilityHandler_GetActorMessages_WithTimeRange(t *testing.T) {
 	mockObsRepo.AssertExpectations(t)
 }
 
+func TestObservabilityHandler_GetActorMessages_RelativeTimeRange(t *testing.T) {
+	router, mockObsRepo, _, obsHandler := utils.SetupObservabilityHandler()
+	obsHandler.SetMaxTimeRange(24 * time.Hour)
+	router.GET("/api/v1/observability/messages", obsHandler.GetActorMessages)
+
+	var start, end time.Time
+	mockObsRepo.On("GetMessagesByTimeRange", mock.Anything, mock.AnythingOfType("string"), mock.AnythingOfType("string"), 20, 0).
+		Run(func(args mock.Arguments) {
+			start, _ = time.Parse(time.RFC3339, args.String(1))
+			end, _ = time.Parse(time.RFC3339, args.String(2))
+		}).
+		Return([]*models.ActorMessage{}, nil)
+
+	before := time.Now()
+	req, _ := http.NewRequest("GET", "/api/v1/observability/messages?start_time=-15m&end_time=now", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 15*time.Minute, end.Sub(start))
+	assert.WithinDuration(t, before, end, time.Minute)
+
+	// Only a start defaults the end to now
+	req, _ = http.NewRequest("GET", "/api/v1/observability/messages?start_time=now-2h", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 2*time.Hour, end.Sub(start))
+
+	for _, query := range []string{"start_time=-2d", "start_time=now&end_time=-1h", "start_time=15m"} {
+		req, _ = http.NewRequest("GET", "/api/v1/observability/messages?"+query, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockObsRepo.AssertNumberOfCalls(t, "GetMessagesByTimeRange", 2)
+}
+
+func TestObservabilityHandler_GetActorLifecycles(t *testing.T) {
+	router, mockObsRepo, _, obsHandler := utils.SetupObservabilityHandler()
+	router.GET("/api/v1/observability/actors/lifecycle", obsHandler.GetActorLifecycles)
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	lf := models.ActorLifecycleFilter{Start: start, End: start.Add(150 * time.Second), Bucket: time.Minute, ActorType: "trip", Limit: 5}
+	bucket := 1
+	activity := []*models.ActorLifecycleActivity{
+		{ActorID: "trip-1", ActorType: models.ActorTypeTrip, Status: models.ActorStatusActive, SpawnedAt: start.Add(-time.Hour), AliveAtStart: true, Bucket: &bucket, Messages: 3, ProcessingMs: 12},
+	}
+	mockObsRepo.On("GetActorLifecycleActivity", mock.Anything, lf).Return(activity, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/observability/actors/lifecycle?start=2024-01-01T12:00:00Z&end=2024-01-01T12:02:30Z&bucket=1m&actor_type=trip&limit=5", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response handlers.ActorLifecyclesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "1m0s", response.Bucket)
+	require.Len(t, response.Actors, 1)
+	// The last bucket is cut off at the end of the window
+	assert.Equal(t, []models.ActorLifecycleSegment{
+		{Phase: models.ActorLifecycleIdle, Start: start, End: start.Add(time.Minute)},
+		{Phase: models.ActorLifecycleProcessing, Start: start.Add(time.Minute), End: start.Add(2 * time.Minute), Messages: 3, ProcessingMs: 12},
+		{Phase: models.ActorLifecycleIdle, Start: start.Add(2 * time.Minute), End: start.Add(150 * time.Second)},
+	}, response.Actors[0].Segments)
+
+	for _, query := range []string{"bucket=0s", "bucket=1s&start=2024-01-01T00:00:00Z&end=2024-01-02T00:00:00Z", "limit=500", "start=2024-01-02T00:00:00Z&end=2024-01-01T00:00:00Z", "end=yesterday"} {
+		req, _ = http.NewRequest("GET", "/api/v1/observability/actors/lifecycle?"+query, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+	mockObsRepo.AssertExpectations(t)
+}
+
+func TestObservabilityHandler_GetMessageStats(t *testing.T) {
+	router, mockObsRepo, _, obsHandler := utils.SetupObservabilityHandler()
+	router.GET("/api/v1/observability/messages/stats", obsHandler.GetMessageStats)
+
+	p95 := 42.5
+	stats := []*models.MessageStats{
+		{MessageType: "request_ride", ActorType: models.ActorTypeTrip, Total: 10, Failed: 1, FailureRatio: 0.1, P95ProcessingMs: &p95},
+	}
+	mockObsRepo.On("GetMessageStats", mock.Anything, 15*time.Minute).Return(stats, nil)
+	mockObsRepo.On("GetMessageStats", mock.Anything, time.Hour).Return(nil, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/observability/messages/stats?window=15m", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response handlers.MessageStatsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "15m0s", response.Window)
+	require.Len(t, response.Stats, 1)
+	assert.Equal(t, int64(10), response.Stats[0].Total)
+	assert.Equal(t, 42.5, *response.Stats[0].P95ProcessingMs)
+	assert.Nil(t, response.Stats[0].P50ProcessingMs)
+
+	// Without a window the last hour is aggregated, and no messages is an empty list
+	req, _ = http.NewRequest("GET", "/api/v1/observability/messages/stats", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"window":"1h0m0s","stats":[]}`, w.Body.String())
+
+	for _, window := range []string{"soon", "-1h", "200h"} {
+		req, _ = http.NewRequest("GET", "/api/v1/observability/messages/stats?window="+window, nil)
+		w = httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, window)
+	}
+	mockObsRepo.AssertExpectations(t)
+}
+
+func TestObservabilityHandler_GetEventLogs_Filter(t *testing.T) {
+	router, mockObsRepo, _, obsHandler := utils.SetupObservabilityHandler()
+	router.GET("/api/v1/observability/events", obsHandler.GetEventLogs)
+
+	expression := `severity>=warning AND actor_type=trip AND message~"timeout"`
+	logs := []*models.EventLog{{ID: uuid.New(), Severity: models.EventSeverityError, Message: "matching timeout"}}
+	mockObsRepo.On("FilterEventLogs", mock.Anything, expression, 20, 0).Return(logs, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/observability/events?filter="+url.QueryEscape(expression)+"&event_type=ignored", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockObsRepo.AssertExpectations(t)
+}
+
+func TestObservabilityHandler_Filter_Invalid(t *testing.T) {
+	router, _, _, obsHandler := utils.SetupObservabilityHandler()
+	router.GET("/api/v1/observability/events", obsHandler.GetEventLogs)
+	router.GET("/api/v1/observability/messages", obsHandler.GetActorMessages)
+	router.GET("/api/v1/observability/traces", obsHandler.GetDistributedTraces)
+
+	for _, target := range []string{
+		"/api/v1/observability/events?filter=" + url.QueryEscape("severity=loud"),
+		"/api/v1/observability/messages?filter=" + url.QueryEscape("severity=warn"),
+		"/api/v1/observability/traces?filter=" + url.QueryEscape("duration_ms~slow"),
+	} {
+		req, _ := http.NewRequest("GET", target, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, target)
+		var response handlers.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Invalid filter", response.Error)
+	}
+}
+
+func TestObservabilityHandler_GetActorMessages_ByEntity(t *testing.T) {
+	router, mockObsRepo, _, obsHandler := utils.SetupObservabilityHandler()
+
+	// Setup route
+	router.GET("/api/v1/observability/messages", obsHandler.GetActorMessages)
+
+	// Mock data
+	tripID := uuid.New()
+	entityType := models.EntityTypeTrip
+	messages := []*models.ActorMessage{
+		{
+			ID:                uuid.New(),
+			TraceID:           uuid.New(),
+			SpanID:            uuid.New(),
+			SenderActorType:   models.ActorTypePassenger,
+			SenderActorID:     "passenger-123",
+			ReceiverActorType: models.ActorTypeMatching,
+			ReceiverActorID:   "trip-matcher",
+			EntityType:        &entityType,
+			EntityID:          &tripID,
+			MessageType:       "request_ride",
+			Status:            models.MessageStatusSent,
+			SentAt:            time.Now(),
+			CreatedAt:         time.Now(),
+		},
+	}
+
+	// Setup mock expectations
+	mockObsRepo.On("ListActorMessagesByEntity", mock.Anything, "trip", tripID.String(), 20, 0).Return(messages, nil)
+
+	// Create request with entity filter
+	req, _ := http.NewRequest("GET", "/api/v1/observability/messages?entity_type=trip&entity_id="+tripID.String(), nil)
+	w := httptest.NewRecorder()
+
+	// Execute request
+	router.ServeHTTP(w, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"entity_id":"`+tripID.String()+`"`)
+	mockObsRepo.AssertExpectations(t)
+}
+
+func TestObservabilityHandler_EntityFilter_Invalid(t *testing.T) {
+	router, _, _, obsHandler := utils.SetupObservabilityHandler()
+
+	// Setup routes
+	router.GET("/api/v1/observability/actors", obsHandler.GetActorInstances)
+	router.GET("/api/v1/observability/messages", obsHandler.GetActorMessages)
+	router.GET("/api/v1/observability/events", obsHandler.GetEventLogs)
+
+	for _, url := range []string{
+		"/api/v1/observability/actors?entity_type=driver",
+		"/api/v1/observability/messages?entity_id=" + uuid.New().String(),
+		"/api/v1/observability/events?entity_type=trip&entity_id=not-a-uuid",
+	} {
+		req, _ := http.NewRequest("GET", url, nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, url)
+	}
+}
+
 // Test GetSystemMetrics endpoint
 func TestObservabilityHandler_GetSystemMetrics_Success(t *testing.T) {
 	router, mockObsRepo, _, obsHandler := utils.SetupObservabilityHandler()
@@ -652,3 +881,305 @@ func TestObservabilityHandler_GetPrometheusMetrics_RepositoryError(t *testing.T)
 
 	mockObsRepo.AssertExpectations(t)
 }
+
+func TestObservabilityHandler_GetSLOReport(t *testing.T) {
+	tracker := observability.NewSLOTracker(config.SLOConfig{
+		Window: time.Hour,
+		Objectives: []config.SLOObjective{
+			{Method: "POST", Endpoint: "/api/v1/rides/request", LatencyTarget: 500 * time.Millisecond, LatencyObjective: 0.9, AvailabilityObjective: 0.99},
+			{Method: "GET", Endpoint: "/api/v1/rides/:id/status", LatencyTarget: 200 * time.Millisecond, LatencyObjective: 0.99, AvailabilityObjective: 0.999},
+		},
+	})
+	for i := 0; i < 98; i++ {
+		tracker.RecordRequest("/api/v1/rides/request", "POST", 100*time.Millisecond, http.StatusCreated)
+	}
+	tracker.RecordRequest("/api/v1/rides/request", "POST", time.Second, http.StatusCreated)
+	tracker.RecordRequest("/api/v1/rides/request", "POST", 100*time.Millisecond, http.StatusInternalServerError)
+	// Endpoints without an objective are not tracked
+	tracker.RecordRequest("/api/v1/users", "GET", time.Second, http.StatusInternalServerError)
+
+	router, _, _, _ := utils.SetupObservabilityHandler()
+	obsHandler := handlers.NewObservabilityHandler(&utils.MockObservabilityRepository{}, &utils.MockTraditionalRepository{}, tracker, nil)
+	router.GET("/api/v1/observability/slo", obsHandler.GetSLOReport)
+
+	req, _ := http.NewRequest("GET", "/api/v1/observability/slo", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var report observability.SLOReport
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+	assert.Equal(t, "1h0m0s", report.Window)
+	assert.Len(t, report.Endpoints, 2)
+
+	rideRequest := report.Endpoints[0]
+	assert.Equal(t, int64(100), rideRequest.TotalRequests)
+	assert.Equal(t, int64(1), rideRequest.FailedRequests)
+	assert.Equal(t, int64(1), rideRequest.SlowRequests)
+	// 1% failures against a 1% budget spends it exactly
+	assert.InDelta(t, 0.99, rideRequest.Availability.Actual, 1e-9)
+	assert.True(t, rideRequest.Latency.Met)
+	assert.InDelta(t, 1.0, rideRequest.Availability.BurnRate, 1e-9)
+	assert.InDelta(t, 0.0, rideRequest.Availability.ErrorBudgetRemaining, 1e-9)
+	assert.InDelta(t, 0.1, rideRequest.Latency.BurnRate, 1e-9)
+
+	status := report.Endpoints[1]
+	assert.Equal(t, int64(0), status.TotalRequests)
+	assert.True(t, status.Availability.Met)
+	assert.Equal(t, 1.0, status.Availability.ErrorBudgetRemaining)
+}
+
+func TestObservabilityHandler_GetSLOReport_NotConfigured(t *testing.T) {
+	router, _, _, obsHandler := utils.SetupObservabilityHandler()
+	router.GET("/api/v1/observability/slo", obsHandler.GetSLOReport)
+
+	req, _ := http.NewRequest("GET", "/api/v1/observability/slo", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestObservabilityHandler_StreamEventLogs(t *testing.T) {
+	stream := observability.NewEventStream()
+	router, _, _, _ := utils.SetupObservabilityHandler()
+	obsHandler := handlers.NewObservabilityHandler(&utils.MockObservabilityRepository{}, &utils.MockTraditionalRepository{}, nil, stream)
+	router.GET("/api/v1/observability/events/stream", obsHandler.StreamEventLogs)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/api/v1/observability/events/stream?severity=error&actor_type=driver", nil)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	require.Eventually(t, func() bool { return stream.Subscribers() == 1 }, time.Second, 10*time.Millisecond)
+
+	driver := models.ActorTypeDriver
+	passenger := models.ActorTypePassenger
+	stream.Publish(&models.EventLog{ID: uuid.New(), EventType: "ignored", Severity: models.EventSeverityInfo, ActorType: &driver})
+	stream.Publish(&models.EventLog{ID: uuid.New(), EventType: "ignored", Severity: models.EventSeverityError, ActorType: &passenger})
+	expected := &models.EventLog{ID: uuid.New(), EventType: "driver_timeout", Severity: models.EventSeverityError, ActorType: &driver}
+	stream.Publish(expected)
+
+	reader := bufio.NewReader(resp.Body)
+	line, err := reader.ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "event:event_log\n", line)
+	line, err = reader.ReadString('\n')
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(line, "data:"))
+
+	var event models.EventLog
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data:")), &event))
+	assert.Equal(t, expected.ID, event.ID)
+	assert.Equal(t, "driver_timeout", event.EventType)
+
+	// Closing the connection ends the subscription
+	cancel()
+	assert.Eventually(t, func() bool { return stream.Subscribers() == 0 }, time.Second, 10*time.Millisecond)
+}
+
+func TestObservabilityHandler_StreamEventLogs_InvalidFilter(t *testing.T) {
+	router, _, _, _ := utils.SetupObservabilityHandler()
+	obsHandler := handlers.NewObservabilityHandler(&utils.MockObservabilityRepository{}, &utils.MockTraditionalRepository{}, nil, observability.NewEventStream())
+	router.GET("/api/v1/observability/events/stream", obsHandler.StreamEventLogs)
+
+	req, _ := http.NewRequest("GET", "/api/v1/observability/events/stream?severity=critical", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid severity")
+}
+
+func TestObservabilityHandler_StreamEventLogs_NotConfigured(t *testing.T) {
+	router, _, _, obsHandler := utils.SetupObservabilityHandler()
+	router.GET("/api/v1/observability/events/stream", obsHandler.StreamEventLogs)
+
+	req, _ := http.NewRequest("GET", "/api/v1/observability/events/stream", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestObservabilityHandler_GetActorMessages_RedactsForNonOperators(t *testing.T) {
+	router, mockObsRepo, _, obsHandler := utils.SetupObservabilityHandler()
+	redactor, err := redaction.New(config.DefaultRedactionRules(), "test-key")
+	require.NoError(t, err)
+	obsHandler.SetRedactor(redactor)
+
+	router.Use(middleware.OperatorRoleMiddleware([]string{"operator-key"}))
+	router.GET("/api/v1/observability/messages", obsHandler.GetActorMessages)
+
+	payload := `{"passenger_phone":"+6281234567890","pickup_lat":-6.208812,"pickup_address":"Jl. Sudirman 1"}`
+	messages := []*models.ActorMessage{
+		{ID: uuid.New(), MessageType: "ride_request", MessagePayload: json.RawMessage(payload)},
+	}
+	mockObsRepo.On("ListActorMessages", mock.Anything, "", "", 20, 0).Return(messages, nil)
+
+	get := func(operatorKey string) map[string]interface{} {
+		req, _ := http.NewRequest("GET", "/api/v1/observability/messages", nil)
+		if operatorKey != "" {
+			req.Header.Set(middleware.OperatorKeyHeader, operatorKey)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data []struct {
+				MessagePayload map[string]interface{} `json:"message_payload"`
+			} `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.Len(t, response.Data, 1)
+		return response.Data[0].MessagePayload
+	}
+
+	for _, key := range []string{"", "wrong-key"} {
+		redacted := get(key)
+		assert.Regexp(t, `^sha256:[0-9a-f]{16}$`, redacted["passenger_phone"])
+		assert.Equal(t, -6.21, redacted["pickup_lat"])
+		assert.NotContains(t, redacted, "pickup_address")
+	}
+
+	unredacted := get("operator-key")
+	assert.Equal(t, "+6281234567890", unredacted["passenger_phone"])
+	assert.Equal(t, -6.208812, unredacted["pickup_lat"])
+	assert.Equal(t, "Jl. Sudirman 1", unredacted["pickup_address"])
+
+	// The repository's records are left untouched
+	assert.JSONEq(t, payload, string(messages[0].MessagePayload))
+}
+
+func TestObservabilityHandler_GetSlowQueries(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+
+	slowQueries := observability.NewSlowQueryLog(100*time.Millisecond, 2, logger)
+	for _, durationMs := range []float64{150, 400, 250} {
+		slowQueries.Record(context.Background(), &models.SlowQuery{
+			Repository: "TripRepository",
+			Method:     "GetActiveTrips",
+			Operation:  "SELECT",
+			DurationMs: durationMs,
+			At:         time.Now(),
+		})
+	}
+
+	router, _, _, obsHandler := utils.SetupObservabilityHandler()
+	obsHandler.SetSlowQueryLog(slowQueries)
+	router.GET("/api/v1/observability/slow-queries", obsHandler.GetSlowQueries)
+
+	req, _ := http.NewRequest("GET", "/api/v1/observability/slow-queries", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response handlers.SlowQueriesResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "100ms", response.Threshold)
+	// Only the top 2 are kept, slowest first
+	require.Len(t, response.Queries, 2)
+	assert.Equal(t, 400.0, response.Queries[0].DurationMs)
+	assert.Equal(t, 250.0, response.Queries[1].DurationMs)
+
+	req, _ = http.NewRequest("GET", "/api/v1/observability/slow-queries?limit=1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Len(t, response.Queries, 1)
+
+	req, _ = http.NewRequest("GET", "/api/v1/observability/slow-queries?limit=zero", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestObservabilityHandler_GetSlowQueries_Disabled(t *testing.T) {
+	router, _, _, obsHandler := utils.SetupObservabilityHandler()
+	router.GET("/api/v1/observability/slow-queries", obsHandler.GetSlowQueries)
+
+	req, _ := http.NewRequest("GET", "/api/v1/observability/slow-queries", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestObservabilityHandler_GetCollectorStatus(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+
+	// Inserting messages fails and Redis is unreachable
+	sqlxDB, sqlMock := utils.SetupMockDB(t)
+	sqlMock.ExpectExec("INSERT INTO actor_messages").WillReturnError(fmt.Errorf("connection refused"))
+	cache := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	defer cache.Close()
+
+	cfg := &config.Config{Observability: config.ObservabilityConfig{MetricsInterval: time.Hour}}
+	collector := observability.NewMetricsCollector(&database.PostgresDB{DB: sqlxDB}, cache, cfg, logger)
+	require.NoError(t, collector.Start(context.Background()))
+	collector.RecordMessage("driver-1", "trip-1", "location_update", map[string]float64{"lat": -6.2}, time.Now())
+	collector.RecordMessage("driver-1", "trip-1", "location_update", map[string]float64{"lat": -6.3}, time.Now())
+	collector.RecordTrace(uuid.NewString(), uuid.NewString(), "", "match", time.Now(), time.Now(), nil)
+
+	router, mockObsRepo, mockTradRepo, obsHandler := utils.SetupObservabilityHandler()
+	obsHandler.SetMetricsCollector(collector)
+	router.GET("/api/v1/observability/collector/status", obsHandler.GetCollectorStatus)
+	router.GET("/api/v1/observability/prometheus", obsHandler.GetPrometheusMetrics)
+
+	getStatus := func() observability.CollectorStats {
+		req, _ := http.NewRequest("GET", "/api/v1/observability/collector/status", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var stats observability.CollectorStats
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+		return stats
+	}
+
+	stats := getStatus()
+	assert.Equal(t, 2, stats.Buffered[observability.CollectorMessages])
+	assert.Equal(t, 1, stats.Buffered[observability.CollectorTraces])
+	assert.Positive(t, stats.RedisErrors)
+	assert.Zero(t, stats.Flushes)
+	assert.Equal(t, "1h0m0s", stats.FlushInterval)
+	assert.False(t, stats.FallingBehind)
+
+	// Stopping flushes the buffers; the failed batch of messages is dropped
+	require.NoError(t, collector.Stop())
+	stats = getStatus()
+	assert.Zero(t, stats.Buffered[observability.CollectorMessages])
+	assert.Equal(t, int64(1), stats.Flushes)
+	assert.NotNil(t, stats.LastFlushAt)
+	assert.Equal(t, int64(1), stats.FailedFlushes[observability.CollectorMessages])
+	assert.Equal(t, int64(2), stats.DroppedRecords[observability.CollectorMessages])
+
+	mockObsRepo.On("ListSystemMetrics", mock.Anything, "", 100, 0).Return([]*models.SystemMetric{}, nil)
+	mockTradRepo.On("ListTraditionalMetrics", mock.Anything, "", "", 100, 0).Return([]*models.TraditionalMetric{}, nil)
+	req, _ := http.NewRequest("GET", "/api/v1/observability/prometheus", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `observability_collector_dropped_records_total{kind="messages"} 2`)
+	assert.Contains(t, w.Body.String(), fmt.Sprintf("observability_collector_redis_errors_total %d\n", stats.RedisErrors))
+}
+
+func TestObservabilityHandler_GetCollectorStatus_Unavailable(t *testing.T) {
+	router, _, _, obsHandler := utils.SetupObservabilityHandler()
+	router.GET("/api/v1/observability/collector/status", obsHandler.GetCollectorStatus)
+
+	req, _ := http.NewRequest("GET", "/api/v1/observability/collector/status", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
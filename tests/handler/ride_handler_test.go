@@ -12,10 +12,12 @@ import (
 
 	"actor-model-observability/internal/handlers"
 	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // TestRideHandler_RequestRide_Success tests successful ride request
@@ -140,6 +142,77 @@ func TestRideHandler_RequestRide_MissingFields(t *testing.T) {
 	assert.Equal(t, "Invalid request payload", response.Error)
 }
 
+// TestRideHandler_RequestRide_SavedLocation tests requesting a ride to a saved location
+func TestRideHandler_RequestRide_SavedLocation(t *testing.T) {
+	handler, mockService, mockLocations := utils.SetupRideHandlerWithSavedLocations()
+
+	passengerID := uuid.New()
+	address := "1 Market St"
+	saved := &models.SavedLocation{
+		ID:          uuid.New(),
+		PassengerID: passengerID,
+		Label:       models.SavedLocationWork,
+		Name:        "Office",
+		Latitude:    37.7849,
+		Longitude:   -122.4094,
+		Address:     &address,
+	}
+	pickup := models.Location{Latitude: 37.7749, Longitude: -122.4194}
+
+	mockLocations.On("GetByID", mock.Anything, saved.ID.String()).Return(saved, nil)
+	mockService.On("RequestRide", mock.Anything, passengerID.String(), pickup, saved.Location(), "", address).
+		Return(&models.Trip{ID: uuid.New(), PassengerID: passengerID, Status: models.TripStatusRequested}, nil)
+
+	body, _ := json.Marshal(handlers.RequestRideRequest{
+		PassengerID:     passengerID,
+		PickupLat:       pickup.Latitude,
+		PickupLng:       pickup.Longitude,
+		SavedLocationID: &saved.ID,
+		RideType:        "standard",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/rides/request", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	handler.RequestRide(c)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockService.AssertExpectations(t)
+	mockLocations.AssertExpectations(t)
+}
+
+// TestRideHandler_RequestRide_SavedLocationOfOtherPassenger tests that saved locations are private
+func TestRideHandler_RequestRide_SavedLocationOfOtherPassenger(t *testing.T) {
+	handler, mockService, mockLocations := utils.SetupRideHandlerWithSavedLocations()
+
+	saved := &models.SavedLocation{ID: uuid.New(), PassengerID: uuid.New(), Label: models.SavedLocationHome, Name: "Home"}
+	mockLocations.On("GetByID", mock.Anything, saved.ID.String()).Return(saved, nil)
+
+	body, _ := json.Marshal(handlers.RequestRideRequest{
+		PassengerID:     uuid.New(),
+		PickupLat:       37.7749,
+		PickupLng:       -122.4194,
+		SavedLocationID: &saved.ID,
+		RideType:        "standard",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/rides/request", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	handler.RequestRide(c)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockService.AssertNotCalled(t, "RequestRide")
+}
+
 // TestRideHandler_RequestRide_ServiceError tests service error handling
 func TestRideHandler_RequestRide_ServiceError(t *testing.T) {
 	handler, mockService := utils.SetupRideHandler()
@@ -186,6 +259,45 @@ func TestRideHandler_RequestRide_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+// TestRideHandler_RequestRide_OutsideServiceArea tests that rejected requests carry the reason code
+func TestRideHandler_RequestRide_OutsideServiceArea(t *testing.T) {
+	handler, mockService := utils.SetupRideHandler()
+
+	passengerID := uuid.New()
+	pickup := models.Location{Latitude: 37.7749, Longitude: -122.4194}
+	dropoff := models.Location{Latitude: 37.7849, Longitude: -122.4094}
+
+	mockService.On("RequestRide", mock.Anything, passengerID.String(), pickup, dropoff, "", "").
+		Return((*models.Trip)(nil), &models.RideRejectedError{Reason: models.RideRejectionOutsideHours})
+
+	body, _ := json.Marshal(handlers.RequestRideRequest{
+		PassengerID:    passengerID,
+		PickupLat:      pickup.Latitude,
+		PickupLng:      pickup.Longitude,
+		DestinationLat: dropoff.Latitude,
+		DestinationLng: dropoff.Longitude,
+		RideType:       "standard",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/rides/request", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	handler.RequestRide(c)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var response handlers.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "RIDE_OUTSIDE_HOURS", response.Code)
+	assert.Equal(t, "rides cannot be requested outside operating hours", response.Message)
+
+	mockService.AssertExpectations(t)
+}
+
 // TestRideHandler_CancelRide_Success tests successful ride cancellation
 func TestRideHandler_CancelRide_Success(t *testing.T) {
 	handler, mockService := utils.SetupRideHandler()
@@ -193,16 +305,17 @@ func TestRideHandler_CancelRide_Success(t *testing.T) {
 	// Setup test data
 	tripID := uuid.New()
 	passengerID := uuid.New()
-	reason := "Changed my mind"
+	cancellation := models.TripCancellation{Reason: models.CancellationReasonChangedPlans, Note: "Changed my mind"}
 
 	// Setup mock expectations
-	mockService.On("CancelRide", mock.Anything, tripID.String(), reason).Return(nil)
+	mockService.On("CancelRide", mock.Anything, tripID.String(), cancellation).Return(nil)
 
 	// Create request
 	requestBody := handlers.CancelRideRequest{
 		TripID:      tripID,
 		PassengerID: passengerID,
-		Reason:      reason,
+		Reason:      cancellation.Reason,
+		Note:        cancellation.Note,
 	}
 
 	body, _ := json.Marshal(requestBody)
@@ -276,6 +389,47 @@ func TestRideHandler_GetRideStatus_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+// TestRideHandler_GetCancelQuote tests the cancellation quote shown before cancelling
+func TestRideHandler_GetCancelQuote(t *testing.T) {
+	handler, mockService := utils.SetupRideHandler()
+
+	tripID := uuid.New()
+	quote := &models.CancellationQuote{
+		TripID:      tripID,
+		Status:      models.TripStatusAccepted,
+		Cancellable: true,
+		Fee:         3,
+		Rule:        models.CancellationRuleLate,
+		QuotedAt:    time.Now(),
+	}
+	mockService.On("CancellationQuote", mock.Anything, tripID.String(), models.CancellationReasonChangedPlans).Return(quote, nil)
+	mockService.On("CancellationQuote", mock.Anything, tripID.String(), models.CancellationReason("bad")).
+		Return(nil, &models.ValidationError{Field: "reason", Message: "reason must be one of the cancellation reasons"})
+
+	quoteFor := func(reason string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/rides/%s/cancel-quote?reason=%s", tripID, reason), nil)
+		w := httptest.NewRecorder()
+		gin.SetMode(gin.TestMode)
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{{Key: "id", Value: tripID.String()}}
+		handler.GetCancelQuote(c)
+		return w
+	}
+
+	w := quoteFor("changed_plans")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response models.CancellationQuote
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, tripID, response.TripID)
+	assert.False(t, response.Free)
+	assert.Equal(t, 3.0, response.Fee)
+	assert.Equal(t, models.CancellationRuleLate, response.Rule)
+
+	assert.Equal(t, http.StatusBadRequest, quoteFor("bad").Code)
+	mockService.AssertExpectations(t)
+}
+
 // TestRideHandler_GetRideStatus_InvalidUUID tests invalid trip ID
 func TestRideHandler_GetRideStatus_InvalidUUID(t *testing.T) {
 	handler, _ := utils.SetupRideHandler()
@@ -442,3 +596,49 @@ func TestRideHandler_ListRides_InvalidOffset(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, "Invalid offset", response.Error)
 }
+
+// TestRideHandler_StreamRideStatus_EndsWithFinishedTrip tests that the stream of a completed
+// trip sends its status and ends
+func TestRideHandler_StreamRideStatus_EndsWithFinishedTrip(t *testing.T) {
+	handler, _ := utils.SetupRideHandler()
+	trips := new(utils.MockTripRepository)
+	handler.SetStatusStream(service.NewTripStatusStream(trips, nil))
+
+	tripID := uuid.New()
+	trips.On("GetByID", mock.Anything, tripID.String()).Return(&models.Trip{
+		ID:        tripID,
+		Status:    models.TripStatusCompleted,
+		UpdatedAt: time.Now(),
+	}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/rides/%s/status/stream", tripID.String()), nil)
+	w := httptest.NewRecorder()
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: tripID.String()}}
+
+	handler.StreamRideStatus(c)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "event:status\n")
+	assert.Contains(t, w.Body.String(), `"status":"completed"`)
+	trips.AssertExpectations(t)
+}
+
+// TestRideHandler_StreamRideStatus_Unavailable tests streaming without a status stream
+func TestRideHandler_StreamRideStatus_Unavailable(t *testing.T) {
+	handler, _ := utils.SetupRideHandler()
+
+	w := httptest.NewRecorder()
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/rides/x/status/stream", nil)
+	c.Params = gin.Params{{Key: "id", Value: "x"}}
+
+	handler.StreamRideStatus(c)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
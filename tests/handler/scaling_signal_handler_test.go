@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupScalingSignalRouter serves the scaling signal of two actors with three and one messages
+// waiting, two pending ride requests, and an endpoint with a quarter of its requests too slow
+// for a 50% latency objective
+func setupScalingSignalRouter(t *testing.T) *gin.Engine {
+	ctx := context.Background()
+
+	system := actor.NewSimulatedActorSystem("test", actor.NewSimulatedClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+	require.NoError(t, system.Start(ctx))
+	t.Cleanup(func() { _ = system.Stop() })
+	for id, messages := range map[string]int{"trip-1": 3, "trip-2": 1} {
+		_, err := system.SpawnActor("trip", id, 10, func(actor.Message) error { return nil }, actor.SupervisionRestart)
+		require.NoError(t, err)
+		for i := 0; i < messages; i++ {
+			require.NoError(t, system.SendMessage(id, actor.NewBaseMessage("nudge", nil, "test")))
+		}
+	}
+
+	trips := &utils.MockTripRepository{}
+	trips.On("CountByStatus", mock.Anything, models.TripStatusRequested).Return(int64(2), nil)
+
+	slo := observability.NewSLOTracker(config.SLOConfig{
+		Window: time.Hour,
+		Objectives: []config.SLOObjective{
+			{Method: "POST", Endpoint: "/api/v1/rides", LatencyTarget: 100 * time.Millisecond, LatencyObjective: 0.5, AvailabilityObjective: 0.99},
+			{Method: "GET", Endpoint: "/api/v1/rides/:id", LatencyTarget: 100 * time.Millisecond, LatencyObjective: 0.99, AvailabilityObjective: 0.99},
+		},
+	})
+	for _, latency := range []time.Duration{10, 20, 30, 500} {
+		slo.RecordRequest("/api/v1/rides", "POST", latency*time.Millisecond, http.StatusCreated)
+	}
+
+	handler := handlers.NewScalingSignalHandler(service.NewScalingSignalService(system, trips, slo))
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/observability/scaling-signal", handler.GetScalingSignal)
+	return router
+}
+
+func TestScalingSignalHandler_GetScalingSignal(t *testing.T) {
+	router := setupScalingSignalRouter(t)
+
+	req, _ := http.NewRequest("GET", "/api/v1/observability/scaling-signal", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var signal models.ExternalMetricValueList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &signal))
+	assert.Equal(t, "ExternalMetricValueList", signal.Kind)
+	assert.Equal(t, "external.metrics.k8s.io/v1beta1", signal.APIVersion)
+
+	values := make(map[string]string)
+	for _, item := range signal.Items {
+		values[item.MetricName] = item.Value
+		assert.False(t, item.Timestamp.IsZero())
+	}
+	// The endpoint without requests leaves the headroom to the one burning its budget at half speed
+	assert.Equal(t, map[string]string{
+		"actor_mailbox_backlog":     "4",
+		"actor_mailbox_backlog_max": "3",
+		"pending_ride_requests":     "2",
+		"latency_headroom":          "500m",
+		"latency_burn_rate":         "500m",
+	}, values)
+}
+
+func TestScalingSignalHandler_GetScalingSignal_SingleMetric(t *testing.T) {
+	router := setupScalingSignalRouter(t)
+
+	req, _ := http.NewRequest("GET", "/api/v1/observability/scaling-signal?metric=pending_ride_requests", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var signal models.ExternalMetricValueList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &signal))
+	require.Len(t, signal.Items, 1)
+	assert.Equal(t, "pending_ride_requests", signal.Items[0].MetricName)
+	assert.Equal(t, "2", signal.Items[0].Value)
+
+	req, _ = http.NewRequest("GET", "/api/v1/observability/scaling-signal?metric=cpu", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
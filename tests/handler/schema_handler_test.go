@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/database"
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/tests/utils"
+)
+
+func TestSchemaHandler_GetSchemaDrift(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	// Only the first request checks the schema, later ones get its report
+	mock.ExpectQuery("FROM information_schema.columns").WillReturnRows(
+		sqlmock.NewRows([]string{"table_name", "column_name"}).AddRow("fleets", "id").AddRow("fleets", "name"))
+	checker := database.NewSchemaDriftChecker(sqlx.NewDb(db, "postgres"), []models.Table{models.Fleet{}}, "", logger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/admin/schema/drift", handlers.NewSchemaHandler(checker).GetSchemaDrift)
+
+	for i := 0; i < 2; i++ {
+		req, _ := http.NewRequest("GET", "/api/v1/admin/schema/drift", nil)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var report database.SchemaDriftReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		assert.True(t, report.Drifted)
+		assert.Contains(t, report.Mismatches, database.SchemaMismatch{Table: "fleets", Column: "timezone"})
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSchemaHandler_CheckSchemaDrift_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/v1/admin/schema/drift", handlers.NewSchemaHandler(nil).CheckSchemaDrift)
+
+	req, _ := http.NewRequest("POST", "/api/v1/admin/schema/drift", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/tests/utils"
+)
+
+func TestTripAdminHandler_ListStuckTrips(t *testing.T) {
+	router, mockTripRepo, _, tripAdminHandler := utils.SetupTripAdminHandler()
+	router.GET("/api/v1/admin/trips/stuck", tripAdminHandler.ListStuckTrips)
+
+	trip := &models.Trip{ID: uuid.New(), Status: models.TripStatusRequested, UpdatedAt: time.Now().Add(-time.Hour)}
+	before := time.Now()
+	mockTripRepo.On("ListStuck", mock.Anything, mock.MatchedBy(func(updatedBefore time.Time) bool {
+		return !updatedBefore.Before(before.Add(-30*time.Minute)) && updatedBefore.Before(time.Now().Add(-29*time.Minute))
+	}), 10).Return([]*models.Trip{trip}, nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/admin/trips/stuck?older_than=30m&limit=10", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var response handlers.StuckTripsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Trips, 1)
+	assert.Equal(t, trip.ID, response.Trips[0].ID)
+	mockTripRepo.AssertExpectations(t)
+
+	for _, query := range []string{"older_than=soon", "older_than=-5m", "limit=0", "limit=501"} {
+		req, _ := http.NewRequest("GET", "/api/v1/admin/trips/stuck?"+query, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+	}
+}
+
+func TestTripAdminHandler_ForceCancelTrip(t *testing.T) {
+	router, mockTripRepo, mockRideService, tripAdminHandler := utils.SetupTripAdminHandler()
+	router.POST("/api/v1/admin/trips/:id/cancel", tripAdminHandler.ForceCancelTrip)
+
+	tripID := uuid.New()
+	now := time.Now()
+	cancelled := &models.Trip{ID: tripID, Status: models.TripStatusCancelled, CancelledAt: &now}
+	mockTripRepo.On("GetByID", mock.Anything, tripID.String()).
+		Return(&models.Trip{ID: tripID, Status: models.TripStatusMatched}, nil).Once()
+	mockTripRepo.On("GetByID", mock.Anything, tripID.String()).Return(cancelled, nil).Once()
+	mockRideService.On("CancelRide", mock.Anything, tripID.String(), models.TripCancellation{
+		Reason: models.CancellationReasonOther,
+		Note:   "driver app stopped reporting",
+	}).Return(nil)
+
+	body, _ := json.Marshal(handlers.ForceCancelTripRequest{Note: "driver app stopped reporting"})
+	req, _ := http.NewRequest("POST", "/api/v1/admin/trips/"+tripID.String()+"/cancel", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var trip models.Trip
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &trip))
+	assert.Equal(t, models.TripStatusCancelled, trip.Status)
+	mockTripRepo.AssertExpectations(t)
+	mockRideService.AssertExpectations(t)
+}
+
+func TestTripAdminHandler_ForceCancelTrip_Errors(t *testing.T) {
+	router, mockTripRepo, mockRideService, tripAdminHandler := utils.SetupTripAdminHandler()
+	router.POST("/api/v1/admin/trips/:id/cancel", tripAdminHandler.ForceCancelTrip)
+
+	completed, missing := uuid.New(), uuid.New()
+	mockTripRepo.On("GetByID", mock.Anything, completed.String()).
+		Return(&models.Trip{ID: completed, Status: models.TripStatusCompleted}, nil)
+	mockTripRepo.On("GetByID", mock.Anything, missing.String()).
+		Return((*models.Trip)(nil), &models.NotFoundError{Resource: "trip", ID: missing.String()})
+
+	post := func(id string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/api/v1/admin/trips/"+id+"/cancel", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(completed.String())
+	assert.Equal(t, http.StatusConflict, w.Code)
+	var response handlers.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, string(models.ErrorCodeTripNotCancellable), response.Code)
+
+	assert.Equal(t, http.StatusNotFound, post(missing.String()).Code)
+	assert.Equal(t, http.StatusBadRequest, post("not-a-uuid").Code)
+	mockRideService.AssertNotCalled(t, "CancelRide", mock.Anything, mock.Anything, mock.Anything)
+}
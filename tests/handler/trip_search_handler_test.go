@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/tests/utils"
+)
+
+func TestTripSearchHandler_SearchTrips_Success(t *testing.T) {
+	router, mockTripRepo, tripSearchHandler := utils.SetupTripSearchHandler()
+	router.GET("/api/v1/admin/trips/search", tripSearchHandler.SearchTrips)
+
+	at := time.Date(2024, 5, 1, 8, 30, 0, 0, time.UTC)
+	plate := "B 1234 XY"
+	result := &models.TripSearchResult{
+		Trip:           &models.Trip{ID: uuid.New(), Status: models.TripStatusCompleted},
+		PassengerName:  "Rider",
+		PassengerPhone: "+628123456",
+		PassengerEmail: "rider@example.com",
+		DriverPlate:    &plate,
+	}
+	mockTripRepo.On("Search", mock.Anything, mock.MatchedBy(func(f models.TripSearchFilter) bool {
+		return f.PassengerPhone == "+628123456" && f.DriverPlate == "b 1234 xy" &&
+			f.RequestedFrom.Equal(at.Add(-15*time.Minute)) && f.RequestedTo.Equal(at.Add(15*time.Minute)) &&
+			f.PickupBounds != nil && f.PickupBounds.MinLat < -6.2 && f.PickupBounds.MaxLat > -6.2 &&
+			f.Limit == 20 && f.Offset == 0
+	})).Return([]*models.TripSearchResult{result}, int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/admin/trips/search?phone=%2B62+812-3456&plate=b+1234+xy&at=2024-05-01T08:30:00Z&window=15m&lat=-6.2&lng=106.8", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data  []models.TripSearchResult `json:"data"`
+		Total int64                     `json:"total"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, int64(1), response.Total)
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, result.ID, response.Data[0].ID)
+	assert.Equal(t, "rider@example.com", response.Data[0].PassengerEmail)
+	assert.Equal(t, plate, *response.Data[0].DriverPlate)
+
+	mockTripRepo.AssertExpectations(t)
+}
+
+func TestTripSearchHandler_SearchTrips_InvalidCriteria(t *testing.T) {
+	router, mockTripRepo, tripSearchHandler := utils.SetupTripSearchHandler()
+	router.GET("/api/v1/admin/trips/search", tripSearchHandler.SearchTrips)
+
+	for name, query := range map[string]string{
+		"no criteria":       "",
+		"short trip ID":     "trip_id=ab",
+		"trip ID wildcards": "trip_id=ab%25cd",
+		"invalid time":      "at=yesterday",
+		"window too long":   "at=2024-05-01T08:30:00Z&window=72h",
+		"latitude only":     "lat=-6.2",
+		"radius too large":  "lat=-6.2&lng=106.8&radius_km=500",
+	} {
+		t.Run(name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/v1/admin/trips/search?"+query, nil)
+			w := httptest.NewRecorder()
+
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+	mockTripRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything)
+}
@@ -2,10 +2,12 @@ package handler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -15,6 +17,7 @@ import (
 
 	"actor-model-observability/internal/handlers"
 	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
 	"actor-model-observability/tests/utils"
 )
 
@@ -560,6 +563,79 @@ func TestUserHandler_ListUsers_InvalidLimit(t *testing.T) {
 	assert.Contains(t, response, "error")
 }
 
+func TestUserHandler_ListUsers_Fields(t *testing.T) {
+	// Setup
+	userRepo := &utils.MockUserRepository{}
+	driverRepo := &utils.MockDriverRepository{}
+	passengerRepo := &utils.MockPassengerRepository{}
+
+	user := &models.User{
+		ID:       uuid.New(),
+		Email:    "user1@example.com",
+		Phone:    "+1234567890",
+		Name:     "User 1",
+		UserType: "passenger",
+	}
+	userRepo.On("List", mock.MatchedBy(func(ctx context.Context) bool {
+		return assert.ObjectsAreEqual([]string{"id", "name"}, repository.ColumnsFromContext(ctx))
+	}), 20, 0).Return([]*models.User{user}, nil)
+
+	userHandler := handlers.NewUserHandler(userRepo, driverRepo, passengerRepo)
+
+	// Setup Gin router
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users", userHandler.ListUsers)
+
+	req := httptest.NewRequest("GET", "/users?fields=id,%20name,id", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, map[string]interface{}{"id": user.ID.String(), "name": "User 1"}, response.Data[0])
+	userRepo.AssertExpectations(t)
+}
+
+func TestUserHandler_ListUsers_UnknownField(t *testing.T) {
+	// Setup
+	userRepo := &utils.MockUserRepository{}
+	driverRepo := &utils.MockDriverRepository{}
+	passengerRepo := &utils.MockPassengerRepository{}
+
+	userHandler := handlers.NewUserHandler(userRepo, driverRepo, passengerRepo)
+
+	// Setup Gin router
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users", userHandler.ListUsers)
+
+	req := httptest.NewRequest("GET", "/users?fields=id,password", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var response map[string]interface{}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Invalid fields", response["error"])
+	assert.Equal(t, `Unknown field "password"`, response["message"])
+	userRepo.AssertNotCalled(t, "List", mock.Anything, mock.Anything, mock.Anything)
+}
+
 // Test UserHandler.GetDriver endpoint
 func TestUserHandler_GetDriver_Success(t *testing.T) {
 	// Setup
@@ -578,6 +654,13 @@ func TestUserHandler_GetDriver_Success(t *testing.T) {
 		Status:        "online",
 	}
 	driverRepo.On("GetByUserID", mock.Anything, "b412752c-e710-4647-8bdf-252abe290fa1").Return(expectedDriver, nil)
+	driverRepo.On("GetTripStats", mock.Anything, expectedDriver.ID.String()).Return(&models.DriverTripStats{
+		DriverID:        expectedDriver.ID,
+		TotalTrips:      12,
+		TotalDistanceKm: 84.5,
+		AverageRating:   4.5,
+		RecentEarnings:  310,
+	}, nil)
 
 	userHandler := handlers.NewUserHandler(userRepo, driverRepo, passengerRepo)
 
@@ -603,6 +686,10 @@ func TestUserHandler_GetDriver_Success(t *testing.T) {
 	assert.Contains(t, response, "id")
 	assert.Contains(t, response, "user_id")
 	assert.Contains(t, response, "status")
+	stats := response["trip_stats"].(map[string]interface{})
+	assert.Equal(t, 12.0, stats["total_trips"])
+	assert.Equal(t, 84.5, stats["total_distance_km"])
+	assert.Equal(t, 310.0, stats["last_30_day_earnings"])
 }
 
 func TestUserHandler_GetDriver_InvalidUUID(t *testing.T) {
@@ -1036,3 +1123,62 @@ func TestUserHandler_UpdateDriverStatus_InvalidStatus(t *testing.T) {
 	assert.Contains(t, response, "error")
 	assert.Equal(t, "Invalid request payload", response["error"])
 }
+
+func TestUserHandler_SetDriverDestination_DailyLimit(t *testing.T) {
+	userRepo := &utils.MockUserRepository{}
+	driverRepo := &utils.MockDriverRepository{}
+	passengerRepo := &utils.MockPassengerRepository{}
+
+	driverID := uuid.New()
+	driverRepo.On("GetByID", mock.Anything, driverID.String()).Return(&models.Driver{ID: driverID}, nil)
+	driverRepo.On("CountDestinationsSince", mock.Anything, driverID.String(), mock.AnythingOfType("time.Time")).
+		Return(models.DriverDestinationDailyLimit, nil)
+
+	userHandler := handlers.NewUserHandler(userRepo, driverRepo, passengerRepo)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/drivers/:id/destination", userHandler.SetDriverDestination)
+
+	body, _ := json.Marshal(handlers.SetDriverDestinationRequest{Latitude: 37.7749, Longitude: -122.4194})
+	req := httptest.NewRequest("PUT", "/drivers/"+driverID.String()+"/destination", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	driverRepo.AssertNotCalled(t, "SetDestination", mock.Anything, mock.Anything)
+}
+
+func TestUserHandler_SetDriverDestination_Success(t *testing.T) {
+	userRepo := &utils.MockUserRepository{}
+	driverRepo := &utils.MockDriverRepository{}
+	passengerRepo := &utils.MockPassengerRepository{}
+
+	driverID := uuid.New()
+	driverRepo.On("GetByID", mock.Anything, driverID.String()).Return(&models.Driver{ID: driverID}, nil)
+	driverRepo.On("CountDestinationsSince", mock.Anything, driverID.String(), mock.AnythingOfType("time.Time")).Return(0, nil)
+	driverRepo.On("SetDestination", mock.Anything, mock.AnythingOfType("*models.DriverDestination")).Return(nil)
+
+	userHandler := handlers.NewUserHandler(userRepo, driverRepo, passengerRepo)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.PUT("/drivers/:id/destination", userHandler.SetDriverDestination)
+
+	body, _ := json.Marshal(handlers.SetDriverDestinationRequest{Latitude: 37.7749, Longitude: -122.4194, DurationMinutes: 30})
+	req := httptest.NewRequest("PUT", "/drivers/"+driverID.String()+"/destination", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var response handlers.DriverDestinationResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, driverID, response.DriverID)
+	assert.Equal(t, models.DriverDestinationDailyLimit-1, response.RemainingToday)
+	assert.WithinDuration(t, response.CreatedAt.Add(30*time.Minute), response.ExpiresAt, time.Second)
+	driverRepo.AssertExpectations(t)
+}
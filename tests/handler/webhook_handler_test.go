@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/tests/utils"
+)
+
+func TestWebhookHandler_RegisterWebhook_Success(t *testing.T) {
+	router, mockWebhookRepo, webhookHandler := utils.SetupWebhookHandler()
+	router.POST("/api/v1/webhooks", webhookHandler.RegisterWebhook)
+
+	mockWebhookRepo.On("CreateSubscription", mock.Anything, mock.MatchedBy(func(s *models.WebhookSubscription) bool {
+		return s.Owner == "consumer-key" && s.URL == "https://example.com/hooks" &&
+			len(s.Events) == 1 && s.Events[0] == models.WebhookEventTripCompleted && s.Active
+	})).Return(nil)
+
+	body, _ := json.Marshal(handlers.RegisterWebhookRequest{
+		URL:    "https://example.com/hooks",
+		Events: []string{"trip.completed"},
+	})
+	req, _ := http.NewRequest("POST", "/api/v1/webhooks", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(handlers.APIKeyHeader, "consumer-key")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var response models.WebhookSubscription
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.NotEqual(t, uuid.Nil, response.ID)
+	assert.Len(t, response.Secret, 64, "a secret is generated when none is given")
+	assert.NotContains(t, w.Body.String(), "consumer-key")
+
+	mockWebhookRepo.AssertExpectations(t)
+}
+
+func TestWebhookHandler_RegisterWebhook_MissingAPIKey(t *testing.T) {
+	router, mockWebhookRepo, webhookHandler := utils.SetupWebhookHandler()
+	router.POST("/api/v1/webhooks", webhookHandler.RegisterWebhook)
+
+	body, _ := json.Marshal(handlers.RegisterWebhookRequest{URL: "https://example.com/hooks"})
+	req, _ := http.NewRequest("POST", "/api/v1/webhooks", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockWebhookRepo.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything)
+}
+
+func TestWebhookHandler_RegisterWebhook_InvalidEvent(t *testing.T) {
+	router, mockWebhookRepo, webhookHandler := utils.SetupWebhookHandler()
+	router.POST("/api/v1/webhooks", webhookHandler.RegisterWebhook)
+
+	body, _ := json.Marshal(handlers.RegisterWebhookRequest{
+		URL:    "https://example.com/hooks",
+		Events: []string{"trip.teleported"},
+	})
+	req, _ := http.NewRequest("POST", "/api/v1/webhooks", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(handlers.APIKeyHeader, "consumer-key")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockWebhookRepo.AssertNotCalled(t, "CreateSubscription", mock.Anything, mock.Anything)
+}
+
+func TestWebhookHandler_DeleteWebhook_OtherOwner(t *testing.T) {
+	router, mockWebhookRepo, webhookHandler := utils.SetupWebhookHandler()
+	router.DELETE("/api/v1/webhooks/:id", webhookHandler.DeleteWebhook)
+
+	subscription := &models.WebhookSubscription{ID: uuid.New(), Owner: "someone-else"}
+	mockWebhookRepo.On("GetSubscription", mock.Anything, subscription.ID.String()).Return(subscription, nil)
+
+	req, _ := http.NewRequest("DELETE", "/api/v1/webhooks/"+subscription.ID.String(), nil)
+	req.Header.Set(handlers.APIKeyHeader, "consumer-key")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockWebhookRepo.AssertNotCalled(t, "DeleteSubscription", mock.Anything, mock.Anything)
+}
+
+func TestWebhookHandler_ListWebhookDeliveries(t *testing.T) {
+	router, mockWebhookRepo, webhookHandler := utils.SetupWebhookHandler()
+	router.GET("/api/v1/admin/webhooks/deliveries", webhookHandler.ListWebhookDeliveries)
+
+	subscriptionID := uuid.New()
+	deliveries := []*models.WebhookDelivery{
+		{
+			ID:             uuid.New(),
+			SubscriptionID: subscriptionID,
+			Event:          models.WebhookEventTripMatched,
+			TripID:         uuid.New(),
+			Payload:        json.RawMessage(`{}`),
+			Status:         models.WebhookDeliveryFailed,
+			Attempts:       6,
+			ResponseStatus: utils.IntPtr(500),
+			LastError:      utils.StringPtr("webhook endpoint returned 500"),
+			CreatedAt:      time.Now(),
+			UpdatedAt:      time.Now(),
+		},
+	}
+	mockWebhookRepo.On("ListDeliveries", mock.Anything, models.WebhookDeliveryFilter{
+		SubscriptionID: &subscriptionID,
+		Status:         models.WebhookDeliveryFailed,
+		Limit:          20,
+		Offset:         0,
+	}).Return(deliveries, int64(1), nil)
+
+	req, _ := http.NewRequest("GET", "/api/v1/admin/webhooks/deliveries?status=failed&subscription_id="+subscriptionID.String(), nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data    []models.WebhookDelivery `json:"data"`
+		Total   int64                    `json:"total"`
+		HasMore bool                     `json:"has_more"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.Data, 1)
+	assert.Equal(t, int64(1), response.Total)
+	assert.False(t, response.HasMore)
+	assert.Equal(t, 6, response.Data[0].Attempts)
+	assert.Equal(t, 500, *response.Data[0].ResponseStatus)
+
+	mockWebhookRepo.AssertExpectations(t)
+}
+
+func TestWebhookHandler_ListWebhookDeliveries_InvalidStatus(t *testing.T) {
+	router, _, webhookHandler := utils.SetupWebhookHandler()
+	router.GET("/api/v1/admin/webhooks/deliveries", webhookHandler.ListWebhookDeliveries)
+
+	req, _ := http.NewRequest("GET", "/api/v1/admin/webhooks/deliveries?status=lost", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,128 @@
+package hlc
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/hlc"
+	"actor-model-observability/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// frozenClock returns a clock of source whose wall clock stays at now until moved
+func frozenClock(source string, maxSkew time.Duration, now *time.Time) *hlc.Clock {
+	clock := hlc.NewClock(source, maxSkew)
+	clock.SetNow(func() time.Time { return *now })
+	return clock
+}
+
+func TestClock_NowIsMonotonic(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := frozenClock("api-1", time.Second, &now)
+
+	first := clock.Now()
+	second := clock.Now()
+	assert.Equal(t, now.UnixNano(), first.WallTime)
+	assert.True(t, first.Before(second))
+	assert.Equal(t, first.Logical+1, second.Logical)
+
+	// The wall clock going backwards does not take the timestamps with it
+	now = now.Add(-time.Minute)
+	third := clock.Now()
+	assert.True(t, second.Before(third))
+
+	now = now.Add(2 * time.Minute)
+	fourth := clock.Now()
+	assert.Equal(t, now.UnixNano(), fourth.WallTime)
+	assert.Equal(t, uint32(0), fourth.Logical)
+	assert.Equal(t, "api-1", fourth.Source)
+}
+
+func TestClock_UpdateOrdersAfterRemote(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := frozenClock("api-1", time.Second, &now)
+
+	// A sender whose clock is ahead, within the tolerated skew
+	remote := hlc.Timestamp{WallTime: now.Add(300 * time.Millisecond).UnixNano(), Logical: 4, Source: "api-2"}
+	received := clock.Update(remote)
+	assert.True(t, remote.Before(received))
+	assert.Equal(t, remote.WallTime, received.WallTime)
+	assert.Equal(t, uint32(5), received.Logical)
+	assert.True(t, received.Before(clock.Now()))
+	assert.Zero(t, clock.Skews())
+}
+
+func TestClock_ReportsAndCapsSkew(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := frozenClock("api-1", time.Second, &now)
+	var skews []hlc.Skew
+	clock.SetSkewHandler(func(skew hlc.Skew) { skews = append(skews, skew) })
+
+	remote := hlc.Timestamp{WallTime: now.Add(time.Hour).UnixNano(), Source: "api-2"}
+	received := clock.Update(remote)
+	require.Len(t, skews, 1)
+	assert.Equal(t, hlc.Skew{Source: "api-2", Offset: time.Hour}, skews[0])
+	// One bad clock does not drag the local timestamps an hour ahead
+	assert.Equal(t, now.Add(time.Second).UnixNano(), received.WallTime)
+
+	clock.Observe("driver-7", now.Add(2*time.Second))
+	clock.Observe("driver-8", now.Add(500*time.Millisecond))
+	require.Len(t, skews, 2)
+	assert.Equal(t, "driver-7", skews[1].Source)
+	assert.Equal(t, int64(2), clock.Skews())
+}
+
+func TestClock_ZeroMaxSkewDisablesCheck(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := frozenClock("api-1", 0, &now)
+
+	remote := hlc.Timestamp{WallTime: now.Add(time.Hour).UnixNano(), Source: "api-2"}
+	received := clock.Update(remote)
+	assert.Equal(t, remote.WallTime, received.WallTime)
+	assert.Zero(t, clock.Skews())
+}
+
+func TestClock_StampKeepsExistingTimestamp(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := frozenClock("api-1", time.Second, &now)
+
+	actorID := "driver-7"
+	event := &models.EventLog{EventType: "location_updated", ActorID: &actorID, Timestamp: now.Add(-time.Second)}
+	clock.Stamp(event)
+	require.NotNil(t, event.HLC)
+	stamped := *event.HLC
+	assert.Equal(t, now.UnixNano(), stamped.WallTime)
+
+	// Publishing it again, e.g. by another instance, keeps the first timestamp
+	clock.Stamp(event)
+	assert.Equal(t, stamped, *event.HLC)
+	assert.True(t, stamped.Before(clock.Now()))
+}
+
+func TestTimestamp_EncodingSortsInOrder(t *testing.T) {
+	timestamps := []hlc.Timestamp{
+		{WallTime: 1714564800000000000, Logical: 10, Source: "api-1"},
+		{WallTime: 1714564800000000000, Logical: 2, Source: "api-2"},
+		{WallTime: 999999999, Logical: 0, Source: "api-1"},
+		{WallTime: 1714564800000000000, Logical: 2, Source: "api-1"},
+	}
+
+	encoded := make([]string, len(timestamps))
+	for i, ts := range timestamps {
+		encoded[i] = ts.String()
+		parsed, err := hlc.Parse(encoded[i])
+		require.NoError(t, err)
+		assert.Equal(t, ts, parsed)
+	}
+	sort.Strings(encoded)
+	sort.Slice(timestamps, func(i, j int) bool { return timestamps[i].Before(timestamps[j]) })
+	for i, ts := range timestamps {
+		assert.Equal(t, ts.String(), encoded[i])
+	}
+
+	_, err := hlc.Parse("1714564800000000000")
+	assert.Error(t, err)
+}
@@ -0,0 +1,165 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/httpclient"
+	"actor-model-observability/tests/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newClient(t *testing.T, cfg config.HTTPClientConfig) (*httpclient.Client, *tracetest.SpanRecorder) {
+	logger := utils.NewTestLogger(t)
+
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	inst, err := httpclient.NewInstrumentation(tracerProvider.Tracer("test"), nil)
+	require.NoError(t, err)
+	return httpclient.New("test", cfg, inst, logger), recorder
+}
+
+// testConfig retries quickly and opens circuits after three failures
+func testConfig() config.HTTPClientConfig {
+	cfg := config.DefaultHTTPClientConfig()
+	cfg.InitialBackoff = time.Millisecond
+	cfg.MaxBackoff = time.Millisecond
+	cfg.BreakerFailures = 3
+	cfg.BreakerCooldown = 50 * time.Millisecond
+	return cfg
+}
+
+// flakyServer fails the first failures requests with 503, then echoes the request body
+func flakyServer(t *testing.T, failures int32) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Traceparent", r.Header.Get("traceparent"))
+		w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func post(t *testing.T, client *httpclient.Client, target, body string, idempotencyKey string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, target, strings.NewReader(body))
+	require.NoError(t, err)
+	if idempotencyKey != "" {
+		req.Header.Set(httpclient.IdempotencyKeyHeader, idempotencyKey)
+	}
+	return client.Do(req)
+}
+
+func TestClient_RetriesIdempotentRequests(t *testing.T) {
+	server, calls := flakyServer(t, 2)
+	client, recorder := newClient(t, testConfig())
+
+	resp, err := post(t, client, server.URL, `{"event":"trip.completed"}`, "delivery-1")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, `{"event":"trip.completed"}`, string(body), "the body is replayed on retries")
+	assert.Equal(t, int32(3), calls.Load())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "HTTP POST", spans[0].Name())
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range spans[0].Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	assert.Equal(t, int64(3), attrs["http.attempts"].AsInt64())
+	assert.Equal(t, int64(http.StatusOK), attrs["http.status_code"].AsInt64())
+	// The destination joins the client span's trace
+	assert.Contains(t, resp.Header.Get("X-Traceparent"), spans[0].SpanContext().TraceID().String())
+}
+
+func TestClient_DoesNotRetryNonIdempotentRequests(t *testing.T) {
+	server, calls := flakyServer(t, 1)
+	client, _ := newClient(t, testConfig())
+
+	resp, err := post(t, client, server.URL, `{}`, "")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_RetryBudgetLimitsRetries(t *testing.T) {
+	server, calls := flakyServer(t, 100)
+	cfg := testConfig()
+	cfg.BreakerFailures = 0
+	cfg.RetryBudgetRatio = 0
+	cfg.RetryBudgetBurst = 3
+	client, _ := newClient(t, cfg)
+
+	// Two retries for the first request and one for the second spend the budget
+	for i := 0; i < 3; i++ {
+		resp, err := post(t, client, server.URL, `{}`, "delivery-1")
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	}
+	assert.Equal(t, int32(3+2+1), calls.Load())
+}
+
+func TestClient_CircuitBreakerPerDestination(t *testing.T) {
+	failing, failingCalls := flakyServer(t, 3)
+	healthy, _ := flakyServer(t, 0)
+	cfg := testConfig()
+	cfg.MaxRetries = 0
+	client, _ := newClient(t, cfg)
+
+	for i := 0; i < 3; i++ {
+		resp, err := post(t, client, failing.URL, `{}`, "")
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	failingHost := hostOf(t, failing.URL)
+	assert.Equal(t, httpclient.BreakerOpen, client.Breakers()[failingHost])
+
+	// Requests fail fast while the circuit is open, other destinations are unaffected
+	_, err := post(t, client, failing.URL, `{}`, "")
+	assert.True(t, errors.Is(err, httpclient.ErrCircuitOpen))
+	assert.Equal(t, int32(3), failingCalls.Load())
+
+	resp, err := post(t, client, healthy.URL, `{}`, "")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, httpclient.BreakerClosed, client.Breakers()[hostOf(t, healthy.URL)])
+
+	// After the cooldown a successful trial request closes the circuit
+	time.Sleep(cfg.BreakerCooldown)
+	resp, err = post(t, client, failing.URL, `{}`, "")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, httpclient.BreakerClosed, client.Breakers()[failingHost])
+}
+
+func hostOf(t *testing.T, target string) string {
+	u, err := url.Parse(target)
+	require.NoError(t, err)
+	return u.Host
+}
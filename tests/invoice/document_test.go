@@ -0,0 +1,62 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"actor-model-observability/internal/invoice"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDocument(rows int) *invoice.Document {
+	doc := &invoice.Document{
+		Title:   "Invoice (March)",
+		Header:  []invoice.Field{{Label: "Party", Value: "Acme"}, {Label: "Period", Value: "2026-03"}},
+		Columns: []string{"passenger_id", "trips", "amount"},
+		Summary: []invoice.Field{{Label: "Total due", Value: "12.50"}},
+	}
+	for i := 0; i < rows; i++ {
+		doc.Rows = append(doc.Rows, []string{fmt.Sprintf("p-%d", i), "1", "12.50"})
+	}
+	return doc
+}
+
+func TestDocument_WriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newDocument(2).WriteCSV(&buf))
+	assert.Equal(t, "passenger_id,trips,amount\np-0,1,12.50\np-1,1,12.50\n", buf.String())
+}
+
+func TestDocument_WritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newDocument(1).WritePDF(&buf))
+	pdf := buf.String()
+
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(buf.Bytes(), []byte("%%EOF\n")))
+	assert.Contains(t, pdf, "/Count 1")
+	assert.Contains(t, pdf, `(Invoice \(March\)) Tj`)
+	assert.Contains(t, pdf, "(Page 1 of 1) Tj")
+
+	// startxref points at the cross-reference table
+	match := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(pdf)
+	require.Len(t, match, 2)
+	offset, err := strconv.Atoi(match[1])
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(buf.Bytes()[offset:], []byte("xref\n")))
+}
+
+func TestDocument_WritePDFPaginates(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newDocument(100).WritePDF(&buf))
+	pdf := buf.String()
+
+	assert.Contains(t, pdf, "/Count 2")
+	assert.Contains(t, pdf, "(Page 2 of 2) Tj")
+	assert.Contains(t, pdf, "(p-99          1      12.50) Tj")
+}
@@ -0,0 +1,140 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/leader"
+	"actor-model-observability/internal/lock"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/tests/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingWorker counts its starts and whether it is running
+type countingWorker struct {
+	mu      sync.Mutex
+	starts  int
+	running bool
+}
+
+func (w *countingWorker) Start(ctx context.Context) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.starts++
+	w.running = true
+	return nil
+}
+
+func (w *countingWorker) Stop() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.running = false
+	return nil
+}
+
+func (w *countingWorker) state() (int, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.starts, w.running
+}
+
+func newLogger(t *testing.T) *logging.Logger {
+	logger := utils.NewTestLogger(t)
+	return logger
+}
+
+func newRedisElector(t *testing.T, node *utils.RedisMock) (*leader.Elector, *countingWorker) {
+	cfg := config.DefaultLockConfig()
+	cfg.TTL = 60 * time.Millisecond
+	locker, err := lock.NewLocker([]redis.Cmdable{node}, cfg, nil, newLogger(t))
+	require.NoError(t, err)
+
+	elector, err := leader.NewElector("workers", leader.NewRedisBackend(locker), 10*time.Millisecond, nil, newLogger(t))
+	require.NoError(t, err)
+	worker := &countingWorker{}
+	elector.Add("worker", worker)
+	return elector, worker
+}
+
+func TestElector_RunsWorkersOnOneInstance(t *testing.T) {
+	node := utils.NewRedisMock()
+	first, firstWorker := newRedisElector(t, node)
+	second, secondWorker := newRedisElector(t, node)
+
+	require.NoError(t, first.Start(context.Background()))
+	require.Eventually(t, first.IsLeader, time.Second, 5*time.Millisecond)
+	require.NoError(t, second.Start(context.Background()))
+	defer second.Stop()
+
+	// Leadership is kept past the lock's TTL
+	time.Sleep(150 * time.Millisecond)
+	assert.True(t, first.IsLeader())
+	assert.False(t, second.IsLeader())
+	_, running := firstWorker.state()
+	assert.True(t, running)
+	starts, _ := secondWorker.state()
+	assert.Zero(t, starts)
+
+	// Stopping the leader stops its workers and hands leadership over
+	require.NoError(t, first.Stop())
+	assert.False(t, first.IsLeader())
+	_, running = firstWorker.state()
+	assert.False(t, running)
+
+	require.Eventually(t, second.IsLeader, time.Second, 5*time.Millisecond)
+	_, running = secondWorker.state()
+	assert.True(t, running)
+}
+
+func TestElector_StopsWorkersWhenLeadershipLost(t *testing.T) {
+	node := utils.NewRedisMock()
+	elector, worker := newRedisElector(t, node)
+
+	require.NoError(t, elector.Start(context.Background()))
+	defer elector.Stop()
+	require.Eventually(t, elector.IsLeader, time.Second, 5*time.Millisecond)
+
+	// The lock's node is unreachable, so the lock cannot be renewed
+	node.SetDown(true)
+	require.Eventually(t, func() bool { return !elector.IsLeader() }, time.Second, 5*time.Millisecond)
+	_, running := worker.state()
+	assert.False(t, running)
+
+	// Leadership is taken again once the node is back
+	node.SetDown(false)
+	require.Eventually(t, elector.IsLeader, time.Second, 5*time.Millisecond)
+	starts, running := worker.state()
+	assert.Equal(t, 2, starts)
+	assert.True(t, running)
+}
+
+func TestPostgresBackend_AdvisoryLock(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+	backend := leader.NewPostgresBackend(db, time.Minute)
+	ctx := context.Background()
+
+	mock.ExpectQuery(`SELECT pg_try_advisory_lock\(\$1\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+	_, err = backend.TryAcquire(ctx, "workers")
+	assert.True(t, errors.Is(err, leader.ErrHeld))
+
+	mock.ExpectQuery(`SELECT pg_try_advisory_lock\(\$1\)`).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	mock.ExpectExec(`SELECT pg_advisory_unlock\(\$1\)`).WillReturnResult(sqlmock.NewResult(0, 1))
+	lease, err := backend.TryAcquire(ctx, "workers")
+	require.NoError(t, err)
+	require.NoError(t, lease.Release(ctx))
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,105 @@
+package loadtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/loadtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrivals_IntendedTimesFollowRate(t *testing.T) {
+	arrivals := loadtest.NewArrivals(200)
+	require.Equal(t, 5*time.Millisecond, arrivals.Interval())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	out := make(chan time.Time, 100)
+	sent := arrivals.Run(ctx, out)
+
+	var times []time.Time
+	for intended := range out {
+		times = append(times, intended)
+	}
+	require.Equal(t, int(sent), len(times))
+	assert.InDelta(t, 20, len(times), 3)
+	for i := 1; i < len(times); i++ {
+		assert.Equal(t, arrivals.Interval(), times[i].Sub(times[i-1]))
+	}
+}
+
+func TestArrivals_SlowConsumerKeepsSchedule(t *testing.T) {
+	arrivals := loadtest.NewArrivals(1000)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	out := make(chan time.Time)
+	go arrivals.Run(ctx, out)
+
+	// A consumer taking 20 ticks per arrival must not move the intended times of later ones
+	var times []time.Time
+	for intended := range out {
+		times = append(times, intended)
+		time.Sleep(20 * time.Millisecond)
+	}
+	require.GreaterOrEqual(t, len(times), 2)
+	for i := 1; i < len(times); i++ {
+		assert.Equal(t, time.Millisecond, times[i].Sub(times[i-1]))
+	}
+	assert.Greater(t, arrivals.Missed(), int64(0))
+
+	// The arrivals due but never sent are reported with how long they waited
+	overdue := arrivals.Overdue()
+	assert.Greater(t, overdue.Count(), int64(50))
+	assert.Greater(t, overdue.Max(), 50*time.Millisecond)
+}
+
+func TestArrivals_PhasesChangeRate(t *testing.T) {
+	arrivals := loadtest.NewPhasedArrivals([]loadtest.Phase{
+		{Duration: 50 * time.Millisecond, Rate: 100},
+		{Duration: 50 * time.Millisecond, Rate: 0},
+		{Duration: time.Hour, Rate: 200}, // the last phase continues until the run ends
+	})
+	require.Equal(t, 10*time.Millisecond, arrivals.Interval())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 148*time.Millisecond)
+	defer cancel()
+	out := make(chan time.Time, 100)
+	sent := arrivals.Run(ctx, out)
+
+	var times []time.Time
+	for intended := range out {
+		times = append(times, intended)
+	}
+	require.Equal(t, int(sent), len(times))
+
+	// Five arrivals 10ms apart, none in the quiet phase, then one every 5ms from 100ms on
+	var want []time.Duration
+	for offset := time.Duration(0); offset < 50*time.Millisecond; offset += 10 * time.Millisecond {
+		want = append(want, offset)
+	}
+	for offset := 100 * time.Millisecond; offset < 148*time.Millisecond; offset += 5 * time.Millisecond {
+		want = append(want, offset)
+	}
+	// The last arrival may be cut off when the scheduler runs late
+	require.InDelta(t, len(want), len(times), 1)
+	for i, intended := range times {
+		assert.Equal(t, want[i], intended.Sub(times[0]))
+	}
+}
+
+func TestArrivals_NoneDueAfterLastPhase(t *testing.T) {
+	arrivals := loadtest.NewPhasedArrivals([]loadtest.Phase{
+		{Duration: 20 * time.Millisecond, Rate: 100},
+		{Rate: 0},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+	out := make(chan time.Time, 10)
+	assert.Equal(t, int64(2), arrivals.Run(ctx, out))
+	assert.Zero(t, arrivals.Overdue().Count())
+}
@@ -0,0 +1,86 @@
+package loadtest
+
+import (
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/loadtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogram_QuantilesWithinOnePercent(t *testing.T) {
+	h := loadtest.NewHistogram()
+	for i := 1; i <= 10000; i++ {
+		h.Record(time.Duration(i) * 100 * time.Microsecond) // 0.1ms to 1s
+	}
+
+	require.Equal(t, int64(10000), h.Count())
+	assert.Equal(t, 100*time.Microsecond, h.Min())
+	assert.Equal(t, time.Second, h.Max())
+
+	for _, tc := range []struct {
+		q    float64
+		want time.Duration
+	}{
+		{0.50, 500 * time.Millisecond},
+		{0.90, 900 * time.Millisecond},
+		{0.99, 990 * time.Millisecond},
+		{0.999, 999 * time.Millisecond},
+	} {
+		got := h.Quantile(tc.q)
+		assert.GreaterOrEqual(t, got, tc.want, "q=%v", tc.q)
+		assert.InDelta(t, float64(tc.want), float64(got), float64(tc.want)/100, "q=%v", tc.q)
+	}
+	assert.Equal(t, time.Second, h.Quantile(1))
+}
+
+func TestHistogram_MergeAndBuckets(t *testing.T) {
+	a, b := loadtest.NewHistogram(), loadtest.NewHistogram()
+	a.Record(50 * time.Microsecond)
+	a.Record(50 * time.Microsecond)
+	b.Record(10 * time.Millisecond)
+
+	a.Merge(b)
+	assert.Equal(t, int64(3), a.Count())
+	assert.Equal(t, 10*time.Millisecond, a.Max())
+
+	buckets := a.Buckets()
+	require.Len(t, buckets, 2)
+	assert.Equal(t, loadtest.Bucket{From: 50 * time.Microsecond, To: 50 * time.Microsecond, Count: 2}, buckets[0])
+	assert.LessOrEqual(t, buckets[1].From, 10*time.Millisecond)
+	assert.GreaterOrEqual(t, buckets[1].To, 10*time.Millisecond)
+	assert.Equal(t, int64(1), buckets[1].Count)
+}
+
+func TestSLA_Check(t *testing.T) {
+	h := loadtest.NewHistogram()
+	h.RecordN(20*time.Millisecond, 98)
+	h.RecordN(500*time.Millisecond, 2)
+
+	sla := loadtest.SLA{P95: 200 * time.Millisecond, ErrorRate: 0.01}
+	assert.Empty(t, sla.Check(h, 0.01))
+
+	sla.P99 = 100 * time.Millisecond
+	violations := sla.Check(h, 0.02)
+	require.Len(t, violations, 2)
+	assert.Equal(t, "p99", violations[0].Metric)
+	assert.Equal(t, "error rate", violations[1].Metric)
+	assert.Equal(t, "error rate 2.00% exceeds 1.00%", violations[1].String())
+}
+
+func TestParsePercent(t *testing.T) {
+	rate, err := loadtest.ParsePercent("1%")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.01, rate, 1e-9)
+
+	rate, err = loadtest.ParsePercent("0.005")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.005, rate, 1e-9)
+
+	_, err = loadtest.ParsePercent("150%")
+	assert.Error(t, err)
+	_, err = loadtest.ParsePercent("fast")
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/loadtest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecording_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traffic.jsonl")
+	writer, err := loadtest.CreateRecording(path)
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, writer.Write(now.Add(40*time.Millisecond), &loadtest.RecordedRequest{Method: "GET", Path: "/api/v1/rides/42/status", Status: 200}))
+	require.NoError(t, writer.Write(now.Add(10*time.Millisecond), &loadtest.RecordedRequest{
+		Method: "POST", Path: "/api/v1/rides/request?approach=actor", ContentType: "application/json",
+		Body: json.RawMessage(`{"ride_type":"standard"}`), Status: 201,
+	}))
+	assert.Equal(t, int64(2), writer.Written())
+	require.NoError(t, writer.Close())
+	assert.Error(t, writer.Write(now, &loadtest.RecordedRequest{Method: "GET", Path: "/"}), "closed recordings take no requests")
+
+	requests, err := loadtest.ReadRecordingFile(path)
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	// Requests finishing out of order are read back by arrival
+	assert.Equal(t, "POST", requests[0].Method)
+	assert.JSONEq(t, `{"ride_type":"standard"}`, string(requests[0].Body))
+	assert.Equal(t, "/api/v1/rides/42/status", requests[1].Path)
+	assert.Less(t, requests[0].Offset(), requests[1].Offset())
+
+	_, err = loadtest.ReadRecording(strings.NewReader(`{"method":"GET","path":"/ok"}` + "\n\n" + `{"method":"GET","path":"relative"}`))
+	assert.ErrorContains(t, err, "line 3")
+}
+
+func TestReplay_ScalesRecordedPace(t *testing.T) {
+	requests := []*loadtest.RecordedRequest{
+		{OffsetMs: 0, Method: "GET", Path: "/a"},
+		{OffsetMs: 100, Method: "GET", Path: "/b"},
+		{OffsetMs: 100, Method: "GET", Path: "/c"},
+		{OffsetMs: 200, Method: "GET", Path: "/d"},
+	}
+	replay := loadtest.NewReplay(requests, 4)
+	assert.Equal(t, 50*time.Millisecond, replay.Length())
+
+	out := make(chan loadtest.ReplayArrival, len(requests))
+	sent := replay.Run(context.Background(), out)
+	assert.Equal(t, int64(4), sent)
+
+	var arrivals []loadtest.ReplayArrival
+	for arrival := range out {
+		arrivals = append(arrivals, arrival)
+	}
+	require.Len(t, arrivals, 4)
+	assert.Equal(t, "/c", arrivals[2].Request.Path)
+	assert.Equal(t, 25*time.Millisecond, arrivals[1].Intended.Sub(arrivals[0].Intended))
+	assert.Equal(t, arrivals[1].Intended, arrivals[2].Intended)
+	assert.Equal(t, 50*time.Millisecond, arrivals[3].Intended.Sub(arrivals[0].Intended))
+	assert.Zero(t, replay.Missed())
+}
+
+func TestReplay_StopsWithContext(t *testing.T) {
+	requests := []*loadtest.RecordedRequest{
+		{OffsetMs: 0, Method: "GET", Path: "/a"},
+		{OffsetMs: 10, Method: "GET", Path: "/b"},
+		{OffsetMs: 10_000, Method: "GET", Path: "/c"},
+	}
+	replay := loadtest.NewReplay(requests, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	// Nobody takes the arrivals, so the due ones are overdue when the run ends
+	out := make(chan loadtest.ReplayArrival)
+	sent := replay.Run(ctx, out)
+	assert.Zero(t, sent)
+	assert.Equal(t, int64(2), replay.Overdue().Count())
+}
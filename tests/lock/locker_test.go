@@ -0,0 +1,187 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/lock"
+	"actor-model-observability/tests/utils"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig renews locks every 20ms and gives up acquiring after 100ms
+func testConfig() config.LockConfig {
+	cfg := config.DefaultLockConfig()
+	cfg.TTL = 60 * time.Millisecond
+	cfg.RetryInterval = 5 * time.Millisecond
+	cfg.AcquireTimeout = 100 * time.Millisecond
+	return cfg
+}
+
+func newLocker(t *testing.T, cfg config.LockConfig, nodes ...*utils.RedisMock) *lock.Locker {
+	logger := utils.NewTestLogger(t)
+
+	cmdables := make([]redis.Cmdable, len(nodes))
+	for i, node := range nodes {
+		cmdables[i] = node
+	}
+	locker, err := lock.NewLocker(cmdables, cfg, nil, logger)
+	require.NoError(t, err)
+	return locker
+}
+
+func TestLocker_ExcludesOtherOwners(t *testing.T) {
+	node := utils.NewRedisMock()
+	first, second := newLocker(t, testConfig(), node), newLocker(t, testConfig(), node)
+	ctx := context.Background()
+
+	held, err := first.TryAcquire(ctx, "retention")
+	require.NoError(t, err)
+	assert.Equal(t, "retention", held.Name())
+
+	_, err = second.TryAcquire(ctx, "retention")
+	assert.True(t, errors.Is(err, lock.ErrNotAcquired))
+
+	// Renewals keep the lock held past its TTL
+	time.Sleep(150 * time.Millisecond)
+	_, err = second.TryAcquire(ctx, "retention")
+	assert.True(t, errors.Is(err, lock.ErrNotAcquired))
+	assert.Len(t, first.Held(), 1)
+
+	require.NoError(t, held.Release(ctx))
+	assert.Empty(t, first.Held())
+	assert.Error(t, held.Context().Err())
+
+	other, err := second.TryAcquire(ctx, "retention")
+	require.NoError(t, err)
+	require.NoError(t, other.Release(ctx))
+}
+
+func TestLocker_AcquireWaitsForRelease(t *testing.T) {
+	node := utils.NewRedisMock()
+	first, second := newLocker(t, testConfig(), node), newLocker(t, testConfig(), node)
+	ctx := context.Background()
+
+	held, err := first.TryAcquire(ctx, "driver:42")
+	require.NoError(t, err)
+	time.AfterFunc(30*time.Millisecond, func() { held.Release(ctx) })
+
+	acquired, err := second.Acquire(ctx, "driver:42")
+	require.NoError(t, err)
+	defer acquired.Release(ctx)
+
+	// Held past the acquire timeout, the lock is not acquired
+	_, err = first.Acquire(ctx, "driver:42")
+	assert.True(t, errors.Is(err, lock.ErrNotAcquired))
+}
+
+func TestLocker_RequiresMajorityOfNodes(t *testing.T) {
+	nodes := []*utils.RedisMock{utils.NewRedisMock(), utils.NewRedisMock(), utils.NewRedisMock()}
+	locker := newLocker(t, testConfig(), nodes...)
+	ctx := context.Background()
+
+	// One node down still leaves a majority
+	nodes[2].SetDown(true)
+	held, err := locker.TryAcquire(ctx, "scheduler")
+	require.NoError(t, err)
+	require.NoError(t, held.Release(ctx))
+
+	// Another owner holding the lock on one of the two reachable nodes denies the majority, and
+	// the key set on the other node is deleted again
+	nodes[1].Put("lock:scheduler", "other/token", time.Minute)
+	_, err = locker.TryAcquire(ctx, "scheduler")
+	assert.True(t, errors.Is(err, lock.ErrNotAcquired))
+	_, set := nodes[0].Value("lock:scheduler")
+	assert.False(t, set)
+
+	// Without a reachable majority acquiring fails with an error
+	nodes[1].SetDown(true)
+	_, err = locker.TryAcquire(ctx, "scheduler")
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, lock.ErrNotAcquired))
+}
+
+func TestLocker_LostLockCancelsWork(t *testing.T) {
+	node := utils.NewRedisMock()
+	locker := newLocker(t, testConfig(), node)
+
+	err := locker.WithLock(context.Background(), "reservation:7", func(ctx context.Context) error {
+		// Another client takes over the key, so the next renewal fails
+		node.Put("lock:reservation:7", "other/token", time.Minute)
+		<-ctx.Done()
+		return context.Cause(ctx)
+	})
+	assert.True(t, errors.Is(err, lock.ErrLockLost))
+	assert.Empty(t, locker.Held())
+
+	value, _ := node.Value("lock:reservation:7")
+	assert.Equal(t, "other/token", value, "a lost lock does not delete the new owner's key")
+}
+
+func TestLocker_RenewalSurvivesTransientFailures(t *testing.T) {
+	node := utils.NewRedisMock()
+	locker := newLocker(t, testConfig(), node)
+
+	err := locker.WithLock(context.Background(), "reservation:7", func(ctx context.Context) error {
+		// The first renewal fails, a retry after the node is back within the TTL succeeds
+		node.SetDown(true)
+		time.Sleep(30 * time.Millisecond)
+		node.SetDown(false)
+
+		select {
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		case <-time.After(150 * time.Millisecond):
+			return nil
+		}
+	})
+	require.NoError(t, err)
+	assert.Empty(t, locker.Held())
+}
+
+func TestLocker_OutageOutlastingTTLLosesLock(t *testing.T) {
+	node := utils.NewRedisMock()
+	locker := newLocker(t, testConfig(), node)
+
+	start := time.Now()
+	err := locker.WithLock(context.Background(), "reservation:7", func(ctx context.Context) error {
+		node.SetDown(true)
+		<-ctx.Done()
+		return context.Cause(ctx)
+	})
+	assert.True(t, errors.Is(err, lock.ErrLockLost))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond, "the lock is kept while it is valid")
+	assert.Empty(t, locker.Held())
+}
+
+func TestLocker_ListsLocksOfEveryOwner(t *testing.T) {
+	node := utils.NewRedisMock()
+	locker := newLocker(t, testConfig(), node)
+	ctx := context.Background()
+
+	held, err := locker.TryAcquire(ctx, "retention")
+	require.NoError(t, err)
+	defer held.Release(ctx)
+	node.Put("lock:scheduler", "worker-2-17/abc", time.Minute)
+	node.Put("other:key", "value", time.Minute)
+
+	locks, err := locker.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, locks, 2)
+
+	assert.Equal(t, "retention", locks[0].Name)
+	assert.Equal(t, locker.Owner(), locks[0].Owner)
+	assert.True(t, locks[0].Local)
+	assert.NotNil(t, locks[0].AcquiredAt)
+
+	assert.Equal(t, "scheduler", locks[1].Name)
+	assert.Equal(t, "worker-2-17", locks[1].Owner)
+	assert.False(t, locks[1].Local)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), locks[1].ExpiresAt, time.Second)
+}
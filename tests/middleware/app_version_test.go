@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"actor-model-observability/internal/appversion"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAppVersionRouter gates iOS apps below 2.3.0 and echoes the app stored in the context
+func setupAppVersionRouter(t *testing.T) (*gin.Engine, *appversion.Gate) {
+	gate, err := appversion.NewGate(config.AppVersionConfig{
+		MinVersions: map[string]string{"ios": "2.3.0"},
+	}, nil)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.AppVersionMiddleware(gate))
+	router.GET("/rides", func(c *gin.Context) {
+		client, ok := middleware.AppClientFromContext(c)
+		c.JSON(http.StatusOK, gin.H{"reported": ok, "version": client.Version.String()})
+	})
+	return router, gate
+}
+
+func getWithApp(router *gin.Engine, platform, version string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", "/rides", nil)
+	if platform != "" {
+		req.Header.Set(appversion.PlatformHeader, platform)
+	}
+	if version != "" {
+		req.Header.Set(appversion.VersionHeader, version)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAppVersionMiddleware_RejectsOutdatedApps(t *testing.T) {
+	router, gate := setupAppVersionRouter(t)
+
+	w := getWithApp(router, "ios", "2.2.4")
+	assert.Equal(t, http.StatusUpgradeRequired, w.Code)
+	assert.Equal(t, "2.3.0", w.Header().Get(appversion.MinVersionHeader))
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, string(models.ErrorCodeAppUpgradeRequired), body["code"])
+	assert.Equal(t, "2.3.0", body["min_version"])
+
+	w = getWithApp(router, "ios", "2.3")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"reported":true,"version":"2.3.0"}`, w.Body.String())
+
+	// Other platforms are not gated
+	assert.Equal(t, http.StatusOK, getWithApp(router, "android", "1.0").Code)
+
+	usage := gate.Usage()
+	require.Len(t, usage, 3)
+	assert.Equal(t, appversion.Usage{Platform: "ios", Version: "2.2.4", Requests: 1, Rejected: 1, LastSeenAt: usage[2].LastSeenAt}, usage[2])
+}
+
+func TestAppVersionMiddleware_UnreportedAndInvalidVersions(t *testing.T) {
+	router, gate := setupAppVersionRouter(t)
+
+	w := getWithApp(router, "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"reported":false,"version":"0.0.0"}`, w.Body.String())
+
+	assert.Equal(t, http.StatusBadRequest, getWithApp(router, "ios", "2.3-beta").Code)
+	assert.Equal(t, http.StatusBadRequest, getWithApp(router, "", "2.3.0").Code)
+	assert.Empty(t, gate.Usage())
+}
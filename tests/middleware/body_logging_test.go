@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/redaction"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupBodyLoggingRouter(t *testing.T, cfg config.BodyLoggingConfig) (*gin.Engine, *middleware.BodyLogger, repository.TraditionalRepository) {
+	logger, err := logging.NewLogger(&config.LoggingConfig{Level: "fatal", Format: "text", Output: "stdout"})
+	require.NoError(t, err)
+	redactor, err := redaction.New(config.DefaultRedactionRules(), "test-key")
+	require.NoError(t, err)
+
+	logs := memory.NewTraditionalRepository(memory.NewStore())
+	bodyLogger := middleware.NewBodyLogger(cfg, logs, redactor, "test-service", logger)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("request_id", "req-123")
+		c.Next()
+	})
+	router.Use(bodyLogger.Middleware())
+	router.POST("/api/v1/rides/:id/echo", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Data(http.StatusCreated, "application/json", body)
+	})
+	router.GET("/api/v1/users/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"name": "Ana", "phone": "+62811111111"})
+	})
+	return router, bodyLogger, logs
+}
+
+func TestBodyLogger_SwitchedPerRoute(t *testing.T) {
+	cfg := config.DefaultBodyLoggingConfig()
+	cfg.SampleRate = 1
+	router, bodyLogger, logs := setupBodyLoggingRouter(t, cfg)
+	ctx := context.Background()
+
+	// Off until switched on
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users/42", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	stored, err := logs.ListTraditionalLogs(ctx, "", "", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+
+	bodyLogger.SetRoute("/api/v1/users/:id", true)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users/42", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "+62811111111")
+
+	stored, err = logs.ListTraditionalLogs(ctx, "", "", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, "HTTP body sample: GET /api/v1/users/:id 200", stored[0].Message)
+	assert.Equal(t, "test-service", stored[0].ServiceName)
+
+	var fields struct {
+		RequestID string `json:"request_id"`
+		Route     string `json:"route"`
+		Status    int    `json:"status"`
+		Response  struct {
+			Size int                    `json:"size"`
+			Body map[string]interface{} `json:"body"`
+		} `json:"response"`
+	}
+	require.NoError(t, json.Unmarshal(stored[0].Fields, &fields))
+	assert.Equal(t, "req-123", fields.RequestID)
+	assert.Equal(t, "/api/v1/users/:id", fields.Route)
+	assert.Equal(t, http.StatusOK, fields.Status)
+	assert.Equal(t, w.Body.Len(), fields.Response.Size)
+	assert.Equal(t, "Ana", fields.Response.Body["name"])
+	assert.NotEqual(t, "+62811111111", fields.Response.Body["phone"])
+
+	bodyLogger.SetRoute("/api/v1/users/:id", false)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/users/42", nil))
+	stored, err = logs.ListTraditionalLogs(ctx, "", "", 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, stored, 1)
+
+	settings := bodyLogger.Settings()
+	assert.Equal(t, []middleware.BodyLogRoute{{Route: "/api/v1/users/:id", Enabled: false}}, settings.Overrides)
+	bodyLogger.ResetRoute("/api/v1/users/:id")
+	assert.Empty(t, bodyLogger.Settings().Overrides)
+}
+
+func TestBodyLogger_OmitsLargeAndNonJSONBodies(t *testing.T) {
+	cfg := config.DefaultBodyLoggingConfig()
+	cfg.Enabled = true
+	cfg.SampleRate = 1
+	cfg.MaxBodySize = 32
+	cfg.Routes = []string{"/api/v1/rides/:id/echo"}
+	router, _, logs := setupBodyLoggingRouter(t, cfg)
+	ctx := context.Background()
+
+	large := `{"note":"` + strings.Repeat("x", 64) + `"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/rides/42/echo", strings.NewReader(large))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+	// The handler still reads the whole body
+	assert.Equal(t, large, w.Body.String())
+
+	req = httptest.NewRequest(http.MethodPost, "/api/v1/rides/42/echo", strings.NewReader("plain text"))
+	req.Header.Set("Content-Type", "text/plain")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	// Not a configured route
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/users/42", nil))
+
+	stored, err := logs.ListTraditionalLogs(ctx, "", "", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+
+	omitted := map[string]int{}
+	for _, log := range stored {
+		var fields struct {
+			Request struct {
+				Size    int             `json:"size"`
+				Body    json.RawMessage `json:"body"`
+				Omitted string          `json:"omitted"`
+			} `json:"request"`
+		}
+		require.NoError(t, json.Unmarshal(log.Fields, &fields))
+		assert.Nil(t, fields.Request.Body)
+		omitted[fields.Request.Omitted] = fields.Request.Size
+	}
+	assert.Equal(t, map[string]int{"too_large": len(large), "not_json": len("plain text")}, omitted)
+}
@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"actor-model-observability/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupCompressionRouter serves a large JSON body, a small one and a Prometheus-style endpoint
+func setupCompressionRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.CompressionMiddleware(5, 1024, []string{"/prometheus"}))
+
+	large := strings.Repeat("observability ", 200)
+	router.GET("/large", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": large})
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": "ok"})
+	})
+	router.GET("/api/v1/observability/prometheus", func(c *gin.Context) {
+		c.String(http.StatusOK, large)
+	})
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(large))
+	})
+
+	return router
+}
+
+func serve(router *gin.Engine, path, acceptEncoding string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("GET", path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCompressionMiddleware_CompressesLargeResponses(t *testing.T) {
+	router := setupCompressionRouter()
+
+	w := serve(router, "/large", "br;q=1.0, gzip;q=0.8")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	body, err := io.ReadAll(reader)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), `{"data":"observability observability`)
+	assert.Less(t, w.Body.Len(), len(body))
+}
+
+func TestCompressionMiddleware_SkipsUncompressedResponses(t *testing.T) {
+	router := setupCompressionRouter()
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+	}{
+		{"below minimum size", "/small", "gzip"},
+		{"gzip not accepted", "/large", ""},
+		{"gzip refused", "/large", "gzip;q=0, identity"},
+		{"excluded path", "/api/v1/observability/prometheus", "gzip"},
+		{"already compressed content", "/image", "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve(router, tt.path, tt.acceptEncoding)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Header().Get("Content-Encoding"))
+			assert.NotEmpty(t, w.Body.String())
+			assert.NotEqual(t, byte(0x1f), w.Body.Bytes()[0])
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupErrorCodeRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorCodeMiddleware())
+
+	router.GET("/uncoded", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found", "message": "trip not found"})
+	})
+	router.GET("/coded", func(c *gin.Context) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "No drivers available", "code": models.ErrorCodeRideNoDrivers})
+	})
+	router.GET("/empty-code", func(c *gin.Context) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Conflict", "code": ""})
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	router.GET("/text", func(c *gin.Context) {
+		c.String(http.StatusBadRequest, "bad request")
+	})
+	router.GET("/list", func(c *gin.Context) {
+		c.JSON(http.StatusInternalServerError, []string{"failed"})
+	})
+	return router
+}
+
+func serveErrorCode(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestErrorCodeMiddleware_AddsGenericCode(t *testing.T) {
+	router := setupErrorCodeRouter()
+
+	w := serveErrorCode(router, "/uncoded")
+	require.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"Resource not found","message":"trip not found","code":"NOT_FOUND"}`, w.Body.String())
+
+	w = serveErrorCode(router, "/empty-code")
+	assert.JSONEq(t, `{"error":"Conflict","code":"CONFLICT"}`, w.Body.String())
+}
+
+func TestErrorCodeMiddleware_KeepsOtherResponses(t *testing.T) {
+	router := setupErrorCodeRouter()
+
+	tests := []struct {
+		path string
+		body string
+	}{
+		{"/coded", `{"code":"RIDE_NO_DRIVERS","error":"No drivers available"}`},
+		{"/ok", `{"status":"ok"}`},
+		{"/text", "bad request"},
+		{"/list", `["failed"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.body, serveErrorCode(router, tt.path).Body.String())
+		})
+	}
+}
+
+func TestErrorCatalog_CoversCodes(t *testing.T) {
+	seen := make(map[models.ErrorCode]bool)
+	for _, info := range models.ErrorCatalog() {
+		assert.False(t, seen[info.Code], "duplicate code %s", info.Code)
+		seen[info.Code] = true
+		assert.NotEmpty(t, info.Description)
+	}
+
+	// Every generic code of a status is in the catalog
+	for status := http.StatusBadRequest; status <= http.StatusNetworkAuthenticationRequired; status++ {
+		code := models.ErrorCodeForStatus(status)
+		assert.True(t, seen[code], "status %d has code %s outside the catalog", status, code)
+	}
+
+	// Domain errors keep their code when wrapped
+	assert.Equal(t, models.ErrorCodeRideNoDrivers, models.ErrorCodeOf(fmt.Errorf("matching: %w", models.ErrNoDriversAvailable)))
+	assert.Equal(t, models.ErrorCodeDriverNotVerified, models.ErrorCodeOf(models.ErrDriverNotActivated))
+	assert.Equal(t, models.ErrorCodeRideOutsideHours, models.ErrorCodeOf(&models.RideRejectedError{Reason: models.RideRejectionOutsideHours}))
+	assert.Equal(t, models.ErrorCodeValidationFailed, models.ErrorCodeOf(&models.ValidationError{Field: "email", Message: "invalid"}))
+	assert.Equal(t, models.ErrorCode(""), models.ErrorCodeOf(fmt.Errorf("connection reset")))
+}
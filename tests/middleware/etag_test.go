@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"actor-model-observability/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupETagRouter serves a trip status that changes when status is updated
+func setupETagRouter(status *string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	rides := router.Group("/rides", middleware.ETagMiddleware("private, no-cache"))
+	rides.GET("/:id/status", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id"), "status": *status})
+	})
+	rides.GET("/missing", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Ride not found"})
+	})
+	rides.POST("/request", func(c *gin.Context) {
+		c.JSON(http.StatusCreated, gin.H{"status": "requested"})
+	})
+
+	return router
+}
+
+func request(router *gin.Engine, method, path, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestETagMiddleware_ConditionalGet(t *testing.T) {
+	status := "matched"
+	router := setupETagRouter(&status)
+
+	// First poll returns the body with an ETag
+	w := request(router, "GET", "/rides/1/status", "")
+	require.Equal(t, http.StatusOK, w.Code)
+	etag := w.Header().Get("ETag")
+	assert.Regexp(t, `^W/"[0-9a-f]{32}"$`, etag)
+	assert.Equal(t, "private, no-cache", w.Header().Get("Cache-Control"))
+	assert.JSONEq(t, `{"id":"1","status":"matched"}`, w.Body.String())
+
+	// Unchanged trip is not downloaded again
+	w = request(router, "GET", "/rides/1/status", etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, etag, w.Header().Get("ETag"))
+	assert.Empty(t, w.Body.String())
+
+	// Strong and listed forms of the same tag match too
+	w = request(router, "GET", "/rides/1/status", `"other", `+etag[2:])
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	// A status change produces a new ETag
+	status = "accepted"
+	w = request(router, "GET", "/rides/1/status", etag)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	assert.JSONEq(t, `{"id":"1","status":"accepted"}`, w.Body.String())
+}
+
+func TestETagMiddleware_SkipsErrorsAndWrites(t *testing.T) {
+	status := "matched"
+	router := setupETagRouter(&status)
+
+	w := request(router, "GET", "/rides/missing", "*")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.JSONEq(t, `{"error":"Ride not found"}`, w.Body.String())
+
+	w = request(router, "POST", "/rides/request", "*")
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.Empty(t, w.Header().Get("Cache-Control"))
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventRecorder keeps every published message
+type eventRecorder struct {
+	mu       sync.Mutex
+	messages []bus.Message
+}
+
+func (r *eventRecorder) Publish(topic string, payload interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.messages = append(r.messages, bus.Message{Topic: topic, Payload: payload})
+}
+
+func setupRecoveryRouter(t *testing.T, events bus.Publisher) *gin.Engine {
+	logger, err := logging.NewLogger(&config.LoggingConfig{Level: "fatal", Format: "text", Output: "stdout"})
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RecoveryMiddleware(logger, nil, events))
+	router.Use(func(c *gin.Context) {
+		c.Set("request_id", "req-123")
+		c.Next()
+	})
+	router.GET("/api/v1/rides/:id", func(c *gin.Context) {
+		panic("nil trip")
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	return router
+}
+
+func TestRecoveryMiddleware_Panic(t *testing.T) {
+	events := &eventRecorder{}
+	router := setupRecoveryRouter(t, events)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/rides/42", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, middleware.ProblemContentType, w.Header().Get("Content-Type"))
+	var problem middleware.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, middleware.Problem{
+		Type:      "about:blank",
+		Title:     "Internal Server Error",
+		Status:    http.StatusInternalServerError,
+		Detail:    "An unexpected error occurred",
+		Instance:  "/api/v1/rides/42",
+		RequestID: "req-123",
+		Code:      "INTERNAL_ERROR",
+	}, problem)
+
+	require.Len(t, events.messages, 1)
+	assert.Equal(t, bus.TopicEventLog, events.messages[0].Topic)
+	event, ok := events.messages[0].Payload.(*models.EventLog)
+	require.True(t, ok)
+	assert.Equal(t, "panic_recovered", event.EventType)
+	assert.Equal(t, models.EventSeverityFatal, event.Severity)
+	assert.Equal(t, models.EventCategoryError, event.EventCategory)
+
+	var data map[string]string
+	require.NoError(t, json.Unmarshal(event.EventData, &data))
+	assert.Equal(t, "req-123", data["request_id"])
+	assert.Equal(t, "/api/v1/rides/:id", data["route"])
+	assert.Equal(t, "nil trip", data["panic"])
+	assert.Contains(t, data["stack"], "runtime/debug.Stack")
+}
+
+func TestRecoveryMiddleware_NoPanic(t *testing.T) {
+	events := &eventRecorder{}
+	router := setupRecoveryRouter(t, events)
+
+	req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, events.messages)
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/deadline"
+	"actor-model-observability/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTimeoutRouter gives the ride endpoints a 20ms budget and the others none
+func setupTimeoutRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.TimeoutMiddleware(config.TimeoutConfig{
+		Endpoints: []config.EndpointTimeout{
+			{Method: "POST", Endpoint: "/rides/request", Timeout: 20 * time.Millisecond},
+			{Method: "GET", Endpoint: "/rides/:id/status", Timeout: 20 * time.Millisecond},
+			{Method: "POST", Endpoint: "/rides/:id/cancel", Timeout: 20 * time.Millisecond},
+		},
+	}, nil))
+
+	// A repository call runs out of time and the handler reports the failure as usual
+	router.POST("/rides/request", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		<-ctx.Done()
+		deadline.Exceeded(ctx, deadline.LayerRepository, ctx.Err())
+		deadline.Exceeded(ctx, deadline.LayerService, ctx.Err())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	})
+	router.GET("/rides/:id/status", func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{"has_deadline": ok})
+	})
+	// The handler gives up without answering
+	router.POST("/rides/:id/cancel", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+	router.GET("/rides", func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{"has_deadline": ok})
+	})
+	return router
+}
+
+func decodeTimeout(t *testing.T, w *httptest.ResponseRecorder) middleware.TimeoutResponse {
+	var body middleware.TimeoutResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+	return body
+}
+
+func TestTimeoutMiddleware_ReplacesErrorWithGatewayTimeout(t *testing.T) {
+	router := setupTimeoutRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/rides/request", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	body := decodeTimeout(t, w)
+	assert.Equal(t, middleware.DeadlineExceededCode, body.Code)
+	assert.Equal(t, deadline.LayerRepository, body.Layer, "the first layer to run out of time is reported")
+	assert.Equal(t, int64(20), body.TimeoutMs)
+}
+
+func TestTimeoutMiddleware_AnswersUnansweredRequests(t *testing.T) {
+	router := setupTimeoutRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/rides/42/cancel", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Equal(t, deadline.LayerHTTP, decodeTimeout(t, w).Layer)
+}
+
+func TestTimeoutMiddleware_OnlyBudgetedEndpoints(t *testing.T) {
+	router := setupTimeoutRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rides/42/status", nil))
+	assert.Equal(t, http.StatusOK, w.Code, "requests within their budget are untouched")
+	assert.JSONEq(t, `{"has_deadline":true}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rides", nil))
+	assert.JSONEq(t, `{"has_deadline":false}`, w.Body.String(), "there is no default budget")
+
+	// Event streams are long-lived
+	req := httptest.NewRequest(http.MethodGet, "/rides/42/status", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.JSONEq(t, `{"has_deadline":false}`, w.Body.String())
+}
+
+func TestTimeoutMiddleware_StreamRoutesWithoutAcceptHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.TimeoutMiddleware(config.TimeoutConfig{Default: 20 * time.Millisecond}, nil,
+		"/rides/:id/status/stream", "/rides/:id/chat/ws"))
+
+	// The streams outlive the default budget
+	stream := func(c *gin.Context) {
+		time.Sleep(40 * time.Millisecond)
+		_, ok := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{"has_deadline": ok})
+	}
+	router.GET("/rides/:id/status/stream", stream)
+	router.GET("/rides/:id/chat/ws", stream)
+	router.GET("/rides/:id/status", func(c *gin.Context) {
+		_, ok := c.Request.Context().Deadline()
+		c.JSON(http.StatusOK, gin.H{"has_deadline": ok})
+	})
+
+	for _, path := range []string{"/rides/42/status/stream", "/rides/42/chat/ws"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+		assert.JSONEq(t, `{"has_deadline":false}`, w.Body.String(), path)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/rides/42/status", nil))
+	assert.JSONEq(t, `{"has_deadline":true}`, w.Body.String(), "other routes keep the default budget")
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/loadtest"
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/redaction"
+	"actor-model-observability/tests/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrafficRecorder_RecordsSanitizedRequests(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+	redactor, err := redaction.New([]config.RedactionRule{
+		{Path: "*email", Action: redaction.ActionRemove},
+		{Path: "token", Action: redaction.ActionRemove},
+	}, "test-key")
+	require.NoError(t, err)
+
+	cfg := config.DefaultTrafficRecordingConfig()
+	cfg.Path = filepath.Join(t.TempDir(), "traffic.jsonl")
+	cfg.MaxBodySize = 64
+	cfg.MaxRequests = 4
+	recorder, err := middleware.NewTrafficRecorder(cfg, redactor, logger)
+	require.NoError(t, err)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(recorder.Middleware())
+	router.POST("/api/v1/passengers", func(c *gin.Context) {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, body)
+	})
+	router.GET("/api/v1/rides/:id/status", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+	})
+	router.GET("/health", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	send := func(method, path, contentType, body string) {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		req.Header.Set("Authorization", "Bearer secret")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	send(http.MethodPost, "/api/v1/passengers", "application/json", `{"name":"Rider","email":"rider@example.com"}`)
+	send(http.MethodGet, "/api/v1/rides/42/status?approach=actor&token=secret", "", "")
+	send(http.MethodGet, "/health", "", "")
+	send(http.MethodPost, "/api/v1/passengers", "application/json", `{"name":"`+string(bytes.Repeat([]byte("x"), 80))+`"}`)
+	send(http.MethodPost, "/api/v1/passengers", "text/plain", "name=Rider")
+	require.NoError(t, recorder.Close())
+
+	requests, err := loadtest.ReadRecordingFile(cfg.Path)
+	require.NoError(t, err)
+	require.Len(t, requests, 2, "health checks, oversized and non-JSON bodies are not recorded")
+
+	assert.Equal(t, http.MethodPost, requests[0].Method)
+	assert.Equal(t, "/api/v1/passengers", requests[0].Route)
+	assert.Equal(t, http.StatusCreated, requests[0].Status, "handlers still get the body")
+	assert.Equal(t, "application/json", requests[0].ContentType)
+	assert.JSONEq(t, `{"name":"Rider"}`, string(requests[0].Body))
+
+	assert.Equal(t, "/api/v1/rides/42/status?approach=actor", requests[1].Path)
+	assert.Equal(t, "/api/v1/rides/:id/status", requests[1].Route)
+	assert.Equal(t, http.StatusNotFound, requests[1].Status)
+	assert.Empty(t, requests[1].Body)
+}
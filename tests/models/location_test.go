@@ -0,0 +1,64 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocation_DistanceTo(t *testing.T) {
+	tests := []struct {
+		name string
+		from models.Location
+		to   models.Location
+		want float64
+	}{
+		{"same point", models.Location{Latitude: -6.2, Longitude: 106.82}, models.Location{Latitude: -6.2, Longitude: 106.82}, 0},
+		{"one degree of latitude", models.Location{Latitude: 0, Longitude: 0}, models.Location{Latitude: 1, Longitude: 0}, 111.195},
+		{"one degree of longitude at 60 degrees", models.Location{Latitude: 60, Longitude: 10}, models.Location{Latitude: 60, Longitude: 11}, 55.597},
+		{"across Jakarta", models.Location{Latitude: -6.1754, Longitude: 106.8272}, models.Location{Latitude: -6.2615, Longitude: 106.8106}, 9.749},
+		{"London to Paris", models.Location{Latitude: 51.5074, Longitude: -0.1278}, models.Location{Latitude: 48.8566, Longitude: 2.3522}, 343.556},
+		{"antipodes", models.Location{Latitude: 0, Longitude: 0}, models.Location{Latitude: 0, Longitude: 180}, 20015.087},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, tt.from.DistanceTo(tt.to), 0.01)
+			assert.InDelta(t, tt.want, tt.to.DistanceTo(tt.from), 0.01, "distance is symmetric")
+		})
+	}
+}
+
+func TestDriverDestination_AllowsTrip(t *testing.T) {
+	now := time.Now()
+	// Heading from Central Jakarta to Bogor, about 45 km south
+	destination := &models.DriverDestination{
+		DriverID:  uuid.New(),
+		Latitude:  -6.5950,
+		Longitude: 106.8166,
+		CreatedAt: now,
+		ExpiresAt: now.Add(time.Hour),
+	}
+	current := models.Location{Latitude: -6.1754, Longitude: 106.8272}
+
+	tests := []struct {
+		name    string
+		pickup  models.Location
+		dropoff models.Location
+		want    bool
+	}{
+		{"on the way south", models.Location{Latitude: -6.19, Longitude: 106.82}, models.Location{Latitude: -6.36, Longitude: 106.83}, true},
+		{"dropoff north, away from the destination", models.Location{Latitude: -6.18, Longitude: 106.83}, models.Location{Latitude: -6.12, Longitude: 106.84}, false},
+		{"long detour east", models.Location{Latitude: -6.20, Longitude: 107.05}, models.Location{Latitude: -6.40, Longitude: 107.10}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, destination.AllowsTrip(current, tt.pickup, tt.dropoff))
+		})
+	}
+}
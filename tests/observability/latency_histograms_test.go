@@ -0,0 +1,121 @@
+package observability
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/internal/traditional"
+	"actor-model-observability/tests/utils"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatherHistogram returns the histogram of a metric family gathered from the default registry
+// with a label value
+func gatherHistogram(t *testing.T, name, label, value string) *dto.Histogram {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, pair := range m.GetLabel() {
+				if pair.GetName() == label && pair.GetValue() == value {
+					return m.GetHistogram()
+				}
+			}
+		}
+	}
+	t.Fatalf("histogram %s{%s=%q} not gathered", name, label, value)
+	return nil
+}
+
+func TestOTelMonitor_NativeLatencyHistograms(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+
+	cfg := config.OpenTelemetryConfig{
+		ServiceName:     "latency-test",
+		MetricsEnabled:  true,
+		MetricsExporter: "prometheus",
+		Histograms:      config.DefaultHistogramConfig(),
+	}
+	cfg.Histograms.MatchingBuckets = []float64{0.5, 1, 5}
+	cfg.Histograms.NativeHistograms = true
+	cfg.Histograms.NativeBucketFactor = 1.1
+	monitor, err := observability.NewOTelMonitor(&cfg, logger)
+	require.NoError(t, err)
+
+	tm := traditional.NewTraditionalMonitor(logger, monitor)
+	tm.RecordMatchingDuration("actor", 750*time.Millisecond)
+	tm.RecordMatchingDuration("actor", 3*time.Second)
+	monitor.RecordMessageProcessing(context.Background(), "driver", "location_update", 2*time.Millisecond)
+
+	matching := gatherHistogram(t, observability.MatchingDurationMetric, "mode", "actor")
+	assert.Equal(t, uint64(2), matching.GetSampleCount())
+	assert.InDelta(t, 3.75, matching.GetSampleSum(), 1e-9)
+
+	// The configured buckets are kept as classic buckets next to the native ones
+	bounds := make([]float64, len(matching.GetBucket()))
+	counts := make([]uint64, len(matching.GetBucket()))
+	for i, bucket := range matching.GetBucket() {
+		bounds[i] = bucket.GetUpperBound()
+		counts[i] = bucket.GetCumulativeCount()
+	}
+	assert.Equal(t, []float64{0.5, 1, 5}, bounds)
+	assert.Equal(t, []uint64{0, 1, 2}, counts)
+
+	// A factor of 1.1 is served by schema 3, whose buckets grow by 2^(1/8)
+	assert.Equal(t, int32(3), matching.GetSchema())
+	assert.NotEmpty(t, matching.GetPositiveSpan())
+
+	processing := gatherHistogram(t, observability.MessageProcessingMetric, "message_type", "location_update")
+	assert.Equal(t, uint64(1), processing.GetSampleCount())
+	assert.Equal(t, int32(3), processing.GetSchema())
+}
+
+func TestOTelMonitor_MatchingQueueWaitHistogram(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+
+	cfg := config.OpenTelemetryConfig{
+		ServiceName:     "queue-wait-test",
+		MetricsEnabled:  true,
+		MetricsExporter: "prometheus",
+		Histograms:      config.DefaultHistogramConfig(),
+	}
+	cfg.Histograms.NativeHistograms = true
+	monitor, err := observability.NewOTelMonitor(&cfg, logger)
+	require.NoError(t, err)
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	before := uint64(0)
+	for _, family := range families {
+		if family.GetName() == observability.MatchingQueueWaitMetric {
+			before = family.GetMetric()[0].GetHistogram().GetSampleCount()
+		}
+	}
+
+	tm := traditional.NewTraditionalMonitor(logger, monitor)
+	tm.RecordMatchingQueueWait(5 * time.Millisecond)
+	tm.RecordMatchingQueueWait(300 * time.Millisecond)
+
+	families, err = prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	var wait *dto.Histogram
+	for _, family := range families {
+		if family.GetName() == observability.MatchingQueueWaitMetric {
+			wait = family.GetMetric()[0].GetHistogram()
+		}
+	}
+	require.NotNil(t, wait, "histogram %s not gathered", observability.MatchingQueueWaitMetric)
+	// Monitors created by other tests share the registered histogram
+	assert.Equal(t, before+2, wait.GetSampleCount())
+	assert.NotEmpty(t, wait.GetPositiveSpan())
+}
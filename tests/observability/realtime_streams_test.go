@@ -0,0 +1,163 @@
+package observability
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/tests/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const streamGroup = "dashboards"
+
+type streamRecord struct {
+	N int `json:"n"`
+}
+
+// streamHandler records the entries it's handed and fails them while failing is set
+type streamHandler struct {
+	mu      sync.Mutex
+	handled []int
+	failing bool
+}
+
+func (h *streamHandler) handle(ctx context.Context, entry observability.StreamEntry) error {
+	var record streamRecord
+	if err := json.Unmarshal(entry.Data, &record); err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handled = append(h.handled, record.N)
+	if h.failing {
+		return errors.New("handler failed")
+	}
+	return nil
+}
+
+func (h *streamHandler) records() []int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]int(nil), h.handled...)
+}
+
+// runConsumer runs a consumer of the test group until the returned stop is called
+func runConsumer(t *testing.T, client *utils.RedisMock, handler *streamHandler) (stop func()) {
+	logger := utils.NewTestLogger(t)
+	consumer := observability.NewStreamConsumer(client, observability.MessagesStream, streamGroup, "consumer-1", logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(ctx, handler.handle) }()
+	return func() {
+		cancel()
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("consumer didn't stop")
+		}
+	}
+}
+
+func addRecords(t *testing.T, streams *observability.RealtimeStreams, from, to int) {
+	for n := from; n <= to; n++ {
+		_, err := streams.Add(context.Background(), observability.MessagesStream, streamRecord{N: n})
+		require.NoError(t, err)
+	}
+}
+
+func TestStreamConsumer_ReplaysPendingEntriesBeforeNewOnes(t *testing.T) {
+	client := utils.NewRedisMock()
+	streams := observability.NewRealtimeStreams(client, 100)
+	require.NoError(t, client.XGroupCreateMkStream(context.Background(), observability.MessagesStream, streamGroup, "$").Err())
+
+	// The first run fails every entry, leaving them pending
+	failing := &streamHandler{failing: true}
+	stop := runConsumer(t, client, failing)
+	addRecords(t, streams, 1, 4)
+	require.Eventually(t, func() bool { return len(failing.records()) == 4 }, 5*time.Second, 10*time.Millisecond)
+	stop()
+	assert.Len(t, client.Pending(observability.MessagesStream, streamGroup), 4)
+
+	// Entries added while the consumer was down are read after the pending ones
+	addRecords(t, streams, 5, 6)
+	handler := &streamHandler{}
+	stop = runConsumer(t, client, handler)
+	defer stop()
+	require.Eventually(t, func() bool { return len(handler.records()) == 6 }, 5*time.Second, 10*time.Millisecond)
+
+	// Once the pending entries are through, new entries are delivered as they're added
+	addRecords(t, streams, 7, 7)
+	require.Eventually(t, func() bool { return len(handler.records()) == 7 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, handler.records())
+	assert.Empty(t, client.Pending(observability.MessagesStream, streamGroup))
+}
+
+func TestStreamConsumer_DropsPendingEntriesTrimmedFromStream(t *testing.T) {
+	client := utils.NewRedisMock()
+	streams := observability.NewRealtimeStreams(client, 3)
+	require.NoError(t, client.XGroupCreateMkStream(context.Background(), observability.MessagesStream, streamGroup, "$").Err())
+
+	failing := &streamHandler{failing: true}
+	stop := runConsumer(t, client, failing)
+	addRecords(t, streams, 1, 3)
+	require.Eventually(t, func() bool { return len(failing.records()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	stop()
+
+	// MAXLEN trims the pending entries away
+	addRecords(t, streams, 4, 6)
+	length, err := client.XLen(context.Background(), observability.MessagesStream).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), length)
+
+	handler := &streamHandler{}
+	stop = runConsumer(t, client, handler)
+	defer stop()
+	require.Eventually(t, func() bool { return len(handler.records()) == 3 }, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []int{4, 5, 6}, handler.records())
+	require.Eventually(t, func() bool {
+		return len(client.Pending(observability.MessagesStream, streamGroup)) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRealtimeStreams_StatsReportsGroupLag(t *testing.T) {
+	ctx := context.Background()
+	client := utils.NewRedisMock()
+	streams := observability.NewRealtimeStreams(client, 100)
+	require.NoError(t, client.XGroupCreateMkStream(ctx, observability.MessagesStream, streamGroup, "$").Err())
+
+	// Two of five entries are delivered and still pending, three are waiting
+	failing := &streamHandler{failing: true}
+	addRecords(t, streams, 1, 2)
+	stop := runConsumer(t, client, failing)
+	require.Eventually(t, func() bool { return len(failing.records()) == 2 }, 5*time.Second, 10*time.Millisecond)
+	stop()
+	addRecords(t, streams, 3, 5)
+
+	stats, err := streams.Stats(ctx, observability.MessagesStream)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), stats.Length)
+	assert.Equal(t, []observability.StreamGroupLag{{
+		Stream:    observability.MessagesStream,
+		Group:     streamGroup,
+		Consumers: 1,
+		Pending:   2,
+		Lag:       3,
+	}}, stats.Groups)
+
+	// Redis before 7 doesn't report the lag
+	client.SetLegacyStreamInfo(true)
+	stats, err = streams.Stats(ctx, observability.MessagesStream)
+	require.NoError(t, err)
+	require.Len(t, stats.Groups, 1)
+	assert.Equal(t, int64(2), stats.Groups[0].Pending)
+	assert.Equal(t, int64(-1), stats.Groups[0].Lag)
+}
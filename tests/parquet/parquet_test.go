@@ -0,0 +1,93 @@
+package parquet
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/parquet"
+	"actor-model-observability/tests/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriter_WritesColumnsAndSchema(t *testing.T) {
+	columns := []parquet.Column{
+		{Name: "name", Type: parquet.String},
+		{Name: "value", Type: parquet.Double},
+		{Name: "count", Type: parquet.Int64, Optional: true},
+		{Name: "at", Type: parquet.Timestamp},
+		{Name: "actor_id", Type: parquet.String, Optional: true},
+	}
+	at := time.Date(2024, 3, 1, 12, 30, 0, 123456000, time.UTC)
+
+	var buf bytes.Buffer
+	w, err := parquet.NewWriter(&buf, columns)
+	require.NoError(t, err)
+	require.NoError(t, w.WriteRows([][]any{
+		{"actor_count", 12.5, int64(3), at, "trip-1"},
+		{"queue_size", -1.25, nil, at.Add(time.Second), nil},
+	}))
+	require.NoError(t, w.WriteRows([][]any{
+		{"actor_count", 0.0, nil, at.Add(time.Minute), nil},
+	}))
+	require.NoError(t, w.Close())
+
+	values, metadata := utils.ReadParquet(t, buf.Bytes(), columns)
+	assert.Equal(t, int64(3), metadata[3])
+	assert.Len(t, metadata[4], 2, "a row group per write")
+	assert.Equal(t, parquet.CreatedBy, metadata[6])
+
+	schema := metadata[2].([]any)
+	require.Len(t, schema, 6)
+	assert.Equal(t, int64(5), schema[0].(map[int16]any)[5], "the root holds every column")
+	for i, column := range columns {
+		element := schema[i+1].(map[int16]any)
+		assert.Equal(t, column.Name, element[4])
+		if column.Optional {
+			assert.Equal(t, int64(1), element[3])
+		} else {
+			assert.Equal(t, int64(0), element[3])
+		}
+	}
+	assert.Equal(t, int64(6), schema[1].(map[int16]any)[1], "strings are byte arrays")
+	assert.Equal(t, int64(0), schema[1].(map[int16]any)[6], "annotated UTF-8")
+	assert.Equal(t, int64(10), schema[4].(map[int16]any)[6], "timestamps are in microseconds")
+	assert.Equal(t, true, schema[4].(map[int16]any)[10].(map[int16]any)[8].(map[int16]any)[1], "timestamps are in UTC")
+
+	assert.Equal(t, []any{"actor_count", "queue_size", "actor_count"}, values["name"])
+	assert.Equal(t, []any{12.5, -1.25, 0.0}, values["value"])
+	assert.Equal(t, []any{int64(3), nil, nil}, values["count"])
+	assert.Equal(t, []any{at, at.Add(time.Second), at.Add(time.Minute)}, values["at"])
+	assert.Equal(t, []any{"trip-1", nil, nil}, values["actor_id"])
+}
+
+func TestWriter_RejectsInvalidRows(t *testing.T) {
+	columns := []parquet.Column{{Name: "name", Type: parquet.String}, {Name: "value", Type: parquet.Double}}
+	w, err := parquet.NewWriter(io.Discard, columns)
+	require.NoError(t, err)
+
+	assert.Error(t, w.WriteRows([][]any{{"name"}}), "a value is missing")
+	assert.Error(t, w.WriteRows([][]any{{nil, 1.0}}), "a required value is null")
+	assert.Error(t, w.WriteRows([][]any{{"name", 1}}), "an int is not a double")
+
+	require.NoError(t, w.Close())
+	assert.ErrorIs(t, w.WriteRows([][]any{{"name", 1.0}}), parquet.ErrClosed)
+
+	_, err = parquet.NewWriter(io.Discard, []parquet.Column{{Name: "a"}, {Name: "a"}})
+	assert.Error(t, err)
+}
+
+func TestWriter_EmptyFile(t *testing.T) {
+	columns := []parquet.Column{{Name: "name", Type: parquet.String}}
+	var buf bytes.Buffer
+	w, err := parquet.NewWriter(&buf, columns)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	values, metadata := utils.ReadParquet(t, buf.Bytes(), columns)
+	assert.Empty(t, values)
+	assert.Equal(t, int64(0), metadata[3])
+}
@@ -0,0 +1,121 @@
+package payload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/payload"
+	"actor-model-observability/internal/repository/memory"
+)
+
+// repetitivePayload returns a compressible JSON document of roughly size bytes
+func repetitivePayload(size int) json.RawMessage {
+	points := make([]string, 0, size/40)
+	for i := 0; len(points)*40 < size; i++ {
+		points = append(points, fmt.Sprintf(`{"lat":-6.2%04d,"lng":106.8%04d}`, i%100, i%100))
+	}
+	return json.RawMessage(`{"route":[` + strings.Join(points, ",") + `]}`)
+}
+
+func TestGuard_SmallPayloadsAreStoredVerbatim(t *testing.T) {
+	guard := payload.NewGuard(config.DefaultPayloadConfig())
+	message := &models.ActorMessage{ID: uuid.New(), MessagePayload: json.RawMessage(`{"ride_id":"r-1"}`)}
+
+	assert.Same(t, message, guard.ActorMessage(message))
+	assert.Equal(t, payload.Stats{}, guard.Stats())
+}
+
+func TestGuard_CompressesLargePayloads(t *testing.T) {
+	guard := payload.NewGuard(config.DefaultPayloadConfig())
+	original := repetitivePayload(20 * 1024)
+	event := &models.EventLog{ID: uuid.New(), EventData: original}
+
+	guarded := guard.EventLog(event)
+	require.NotSame(t, event, guarded)
+	assert.Equal(t, original, event.EventData, "the original event is not modified")
+	assert.Less(t, len(guarded.EventData), len(original))
+	assert.True(t, json.Valid(guarded.EventData), "compressed payloads stay valid JSON")
+	assert.Contains(t, string(guarded.EventData), `"_compressed":"gzip"`)
+
+	assert.JSONEq(t, string(original), string(payload.Decode(guarded.EventData)))
+	assert.Equal(t, payload.Stats{Compressed: 1}, guard.Stats())
+}
+
+func TestGuard_TruncatesPayloadsOverTheLimit(t *testing.T) {
+	guard := payload.NewGuard(config.PayloadConfig{MaxSize: 256, CompressionThreshold: 0, PreviewSize: 32})
+	original := repetitivePayload(1024)
+
+	guarded := guard.JSON(original)
+	var marker payload.TruncatedPayload
+	require.NoError(t, json.Unmarshal(guarded, &marker))
+	assert.True(t, marker.Truncated)
+	assert.Equal(t, len(original), marker.OriginalSize)
+	assert.Equal(t, string(original[:32]), marker.Preview)
+
+	// Truncation markers are returned as stored
+	assert.Equal(t, guarded, payload.Decode(guarded))
+	assert.Equal(t, payload.Stats{Truncated: 1}, guard.Stats())
+}
+
+func TestGuard_TruncatesWhenCompressionIsNotEnough(t *testing.T) {
+	guard := payload.NewGuard(config.PayloadConfig{MaxSize: 128, CompressionThreshold: 64, PreviewSize: 16})
+
+	// Random UUIDs barely compress, so the envelope stays over the limit
+	ids := make([]string, 50)
+	for i := range ids {
+		ids[i] = `"` + uuid.NewString() + `"`
+	}
+	guarded := guard.JSON(json.RawMessage(`[` + strings.Join(ids, ",") + `]`))
+
+	assert.Contains(t, string(guarded), `"_truncated":true`)
+	assert.Equal(t, int64(1), guard.Stats().Truncated)
+}
+
+func TestDecode_LeavesOtherPayloadsUnchanged(t *testing.T) {
+	for _, raw := range []string{
+		`{"ride_id":"r-1"}`,
+		`{"_compressed":"zstd","data":"AAAA"}`,
+		`{"_compressed":"gzip","data":"bm90IGd6aXA="}`,
+		`null`,
+	} {
+		assert.Equal(t, json.RawMessage(raw), payload.Decode(json.RawMessage(raw)), raw)
+	}
+}
+
+func TestMemoryRepository_ReturnsDecodedPayloads(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewObservabilityRepository(memory.NewStore())
+	guard := payload.NewGuard(config.DefaultPayloadConfig())
+
+	original := repetitivePayload(20 * 1024)
+	message := &models.ActorMessage{
+		ID:             uuid.New(),
+		TraceID:        uuid.New(),
+		SpanID:         uuid.New(),
+		MessageType:    "route_update",
+		MessagePayload: original,
+		Status:         models.MessageStatusSent,
+		SentAt:         time.Now(),
+		CreatedAt:      time.Now(),
+	}
+	require.NoError(t, repo.CreateActorMessage(ctx, guard.ActorMessage(message)))
+
+	stored, err := repo.GetActorMessage(ctx, message.ID.String())
+	require.NoError(t, err)
+	assert.JSONEq(t, string(original), string(stored.MessagePayload))
+
+	listed, err := repo.GetMessagesByTraceID(ctx, message.TraceID.String())
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.JSONEq(t, string(original), string(listed[0].MessagePayload))
+}
@@ -0,0 +1,120 @@
+package redaction
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/redaction"
+)
+
+func newRedactor(t *testing.T, rules ...config.RedactionRule) *redaction.Redactor {
+	if len(rules) == 0 {
+		rules = config.DefaultRedactionRules()
+	}
+	r, err := redaction.New(rules, "test-key")
+	require.NoError(t, err)
+	return r
+}
+
+func TestRedactor_DefaultRules(t *testing.T) {
+	r := newRedactor(t)
+
+	redacted := r.JSON(json.RawMessage(`{
+		"passenger": {"email": "rider@example.com", "phone": "+6281234567890", "name": "Rider"},
+		"pickup": {"latitude": -6.208812, "longitude": 106.845599, "address": "Jl. Sudirman 1"},
+		"route": [{"lat": -6.2, "lng": 106.81234}, {"lat": "-6.21777", "lng": null}],
+		"average_latency": 12.3456,
+		"fare": 25000
+	}`))
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(redacted, &doc))
+
+	passenger := doc["passenger"].(map[string]interface{})
+	assert.Regexp(t, `^sha256:[0-9a-f]{16}$`, passenger["email"])
+	assert.Regexp(t, `^sha256:[0-9a-f]{16}$`, passenger["phone"])
+	assert.Equal(t, "Rider", passenger["name"])
+
+	pickup := doc["pickup"].(map[string]interface{})
+	assert.Equal(t, -6.21, pickup["latitude"])
+	assert.Equal(t, 106.85, pickup["longitude"])
+	assert.NotContains(t, pickup, "address")
+
+	route := doc["route"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"lat": -6.2, "lng": 106.81}, route[0])
+	assert.Equal(t, map[string]interface{}{"lat": "-6.22", "lng": nil}, route[1])
+
+	// Fields that only resemble a rule are untouched
+	assert.Equal(t, 12.3456, doc["average_latency"])
+	assert.Equal(t, float64(25000), doc["fare"])
+}
+
+func TestRedactor_HashesAreStablePerKey(t *testing.T) {
+	payload := json.RawMessage(`{"phone": "+6281234567890"}`)
+
+	first := newRedactor(t).JSON(payload)
+	assert.JSONEq(t, string(first), string(newRedactor(t).JSON(payload)))
+
+	otherKey, err := redaction.New(config.DefaultRedactionRules(), "another-key")
+	require.NoError(t, err)
+	assert.NotEqual(t, string(first), string(otherKey.JSON(payload)))
+}
+
+func TestRedactor_NestedPathRules(t *testing.T) {
+	r := newRedactor(t,
+		config.RedactionRule{Path: "driver.phone", Action: "remove"},
+		config.RedactionRule{Path: "*.vehicle_*", Action: "hash"},
+	)
+
+	redacted := r.JSON(json.RawMessage(`{
+		"phone": "+6200000000001",
+		"driver": {"phone": "+6200000000002", "vehicle_plate": "B 1234 XYZ", "vehicle_type": "car"}
+	}`))
+
+	var doc map[string]interface{}
+	require.NoError(t, json.Unmarshal(redacted, &doc))
+	assert.Equal(t, "+6200000000001", doc["phone"], "only the nested phone matches driver.phone")
+
+	driver := doc["driver"].(map[string]interface{})
+	assert.NotContains(t, driver, "phone")
+	assert.Regexp(t, `^sha256:`, driver["vehicle_plate"])
+	assert.Regexp(t, `^sha256:`, driver["vehicle_type"])
+}
+
+func TestRedactor_InvalidJSONIsDropped(t *testing.T) {
+	assert.Nil(t, newRedactor(t).JSON(json.RawMessage(`{"phone": "+62`)))
+}
+
+func TestRedactor_InvalidRules(t *testing.T) {
+	_, err := redaction.New([]config.RedactionRule{{Path: "phone", Action: "encrypt"}}, "")
+	assert.Error(t, err)
+
+	_, err = redaction.New([]config.RedactionRule{{Path: "driver..phone", Action: "remove"}}, "")
+	assert.Error(t, err)
+
+	_, err = redaction.New([]config.RedactionRule{{Path: "[phone", Action: "remove"}}, "")
+	assert.Error(t, err)
+}
+
+func TestRedactor_OperatorsSeeUnredactedData(t *testing.T) {
+	r := newRedactor(t)
+	event := &models.EventLog{ID: uuid.New(), EventData: json.RawMessage(`{"email":"rider@example.com"}`)}
+
+	redacted := r.EventLogs(context.Background(), []*models.EventLog{event})
+	assert.NotContains(t, string(redacted[0].EventData), "rider@example.com")
+	assert.Contains(t, string(event.EventData), "rider@example.com", "the original event is not modified")
+
+	operator := r.EventLogs(redaction.WithOperator(context.Background()), []*models.EventLog{event})
+	assert.Same(t, event, operator[0])
+
+	// A nil redactor leaves data unchanged
+	var disabled *redaction.Redactor
+	assert.Same(t, event, disabled.EventLog(context.Background(), event))
+}
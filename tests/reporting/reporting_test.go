@@ -0,0 +1,60 @@
+package reporting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"actor-model-observability/internal/reporting"
+)
+
+func TestDays_DaylightSavingChanges(t *testing.T) {
+	loc, err := reporting.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// The clocks go forward on 2024-03-10 and back on 2024-11-03
+	spring, err := reporting.Days("2024-03-09", "2024-03-11", loc)
+	require.NoError(t, err)
+	require.Len(t, spring, 3)
+	assert.Equal(t, []int{24, 23, 24}, []int{spring[0].Hours(), spring[1].Hours(), spring[2].Hours()})
+	assert.Equal(t, time.Date(2024, 3, 10, 5, 0, 0, 0, time.UTC), spring[1].Start.UTC())
+	assert.Equal(t, time.Date(2024, 3, 11, 4, 0, 0, 0, time.UTC), spring[1].End.UTC())
+
+	autumn, err := reporting.Days("2024-11-03", "2024-11-03", loc)
+	require.NoError(t, err)
+	require.Len(t, autumn, 1)
+	assert.Equal(t, "2024-11-03", autumn[0].Date)
+	assert.Equal(t, 25, autumn[0].Hours())
+
+	// Consecutive days share their boundaries
+	boundaries := reporting.Boundaries(spring)
+	require.Len(t, boundaries, 4)
+	for i, day := range spring {
+		assert.True(t, boundaries[i].Equal(day.Start))
+		assert.True(t, boundaries[i+1].Equal(day.End))
+	}
+}
+
+func TestDays_Errors(t *testing.T) {
+	_, err := reporting.Days("2024-03-10", "2024-03-09", time.UTC)
+	assert.Error(t, err)
+	_, err = reporting.Days("10/03/2024", "2024-03-11", time.UTC)
+	assert.Error(t, err)
+
+	for _, name := range []string{"", "Local", "Mars/Olympus_Mons"} {
+		_, err := reporting.LoadLocation(name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestStartOfHourAndDay(t *testing.T) {
+	loc, err := reporting.LoadLocation("Asia/Kolkata")
+	require.NoError(t, err)
+
+	at := time.Date(2024, 6, 1, 20, 10, 0, 0, time.UTC) // 01:40 on 2024-06-02 in India
+	assert.Equal(t, time.Date(2024, 6, 1, 18, 30, 0, 0, time.UTC), reporting.StartOfDay(at, loc).UTC())
+	assert.Equal(t, time.Date(2024, 6, 1, 19, 30, 0, 0, time.UTC), reporting.StartOfHour(at, loc).UTC())
+	assert.Equal(t, "2024-06-02", reporting.DateOf(at, loc))
+}
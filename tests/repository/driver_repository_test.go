@@ -14,6 +14,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDriverRepository_GetByID_Success(t *testing.T) {
@@ -45,12 +46,13 @@ func TestDriverRepository_GetByID_Success(t *testing.T) {
 	// Setup mock expectations
 	rows := sqlmock.NewRows([]string{
 		"id", "user_id", "license_number", "vehicle_type", "vehicle_plate",
-		"status", "current_latitude", "current_longitude", "rating", "total_trips", "created_at", "updated_at",
+		"status", "current_latitude", "current_longitude", "rating", "total_trips", "fleet_id",
+		"pet_friendly", "wheelchair_accessible", "quiet_rides", "child_seat", "created_at", "updated_at",
 	}).AddRow(
 		expectedDriver.ID, expectedDriver.UserID, expectedDriver.LicenseNumber,
 		expectedDriver.VehicleType, expectedDriver.VehiclePlate, expectedDriver.Status,
 		expectedDriver.CurrentLatitude, expectedDriver.CurrentLongitude, expectedDriver.Rating, expectedDriver.TotalTrips,
-		expectedDriver.CreatedAt, expectedDriver.UpdatedAt,
+		expectedDriver.FleetID, false, false, false, false, expectedDriver.CreatedAt, expectedDriver.UpdatedAt,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM drivers WHERE id = \$1`).
@@ -131,12 +133,13 @@ func TestDriverRepository_GetOnlineDrivers_Success(t *testing.T) {
 	// Setup mock expectations
 	rows := sqlmock.NewRows([]string{
 		"id", "user_id", "license_number", "vehicle_type", "vehicle_plate",
-		"status", "current_latitude", "current_longitude", "rating", "total_trips", "created_at", "updated_at",
+		"status", "current_latitude", "current_longitude", "rating", "total_trips", "fleet_id",
+		"pet_friendly", "wheelchair_accessible", "quiet_rides", "child_seat", "created_at", "updated_at",
 	}).AddRow(
 		expectedDriver.ID, expectedDriver.UserID, expectedDriver.LicenseNumber,
 		expectedDriver.VehicleType, expectedDriver.VehiclePlate, expectedDriver.Status,
 		expectedDriver.CurrentLatitude, expectedDriver.CurrentLongitude, expectedDriver.Rating, expectedDriver.TotalTrips,
-		expectedDriver.CreatedAt, expectedDriver.UpdatedAt,
+		expectedDriver.FleetID, false, false, false, false, expectedDriver.CreatedAt, expectedDriver.UpdatedAt,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM drivers WHERE status = 'online'`).
@@ -164,7 +167,8 @@ func TestDriverRepository_GetOnlineDrivers_Empty(t *testing.T) {
 	// Setup mock expectations - empty result
 	rows := sqlmock.NewRows([]string{
 		"id", "user_id", "license_number", "vehicle_type", "vehicle_plate",
-		"status", "current_latitude", "current_longitude", "rating", "total_trips", "created_at", "updated_at",
+		"status", "current_latitude", "current_longitude", "rating", "total_trips", "fleet_id",
+		"pet_friendly", "wheelchair_accessible", "quiet_rides", "child_seat", "created_at", "updated_at",
 	})
 
 	mock.ExpectQuery(`SELECT (.+) FROM drivers WHERE status = 'online'`).
@@ -292,6 +296,40 @@ func TestDriverRepository_UpdateLocation_Success(t *testing.T) {
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestDriverRepository_UpdateLocations_Success(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := postgres.NewDriverRepository(db)
+
+	// Setup test data; the second driver no longer exists
+	updates := []*models.DriverLocationUpdate{
+		{DriverID: uuid.New(), Latitude: 40.7589, Longitude: -73.9851, At: time.Now()},
+		{DriverID: uuid.New(), Latitude: 40.7128, Longitude: -74.0060, At: time.Now()},
+	}
+
+	// Setup mock expectations
+	mock.ExpectBegin()
+	prepared := mock.ExpectPrepare("UPDATE drivers SET current_latitude = \\$2, current_longitude = \\$3, updated_at = CURRENT_TIMESTAMP WHERE id = \\$1")
+	prepared.ExpectExec().
+		WithArgs(updates[0].DriverID, updates[0].Latitude, updates[0].Longitude).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	prepared.ExpectExec().
+		WithArgs(updates[1].DriverID, updates[1].Latitude, updates[1].Longitude).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	// Execute
+	updated, err := repo.UpdateLocations(context.Background(), updates)
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), updated)
+
+	// Verify all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestDriverRepository_UpdateOnlineStatus_Success(t *testing.T) {
 	db, mock := setupMockDB(t)
 	defer db.Close()
@@ -316,3 +354,46 @@ func TestDriverRepository_UpdateOnlineStatus_Success(t *testing.T) {
 	// Verify all expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestDriverRepository_ListByFleet_Success(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := postgres.NewDriverRepository(db)
+
+	// Setup test data
+	fleetID := uuid.New()
+	driverID := uuid.New()
+	now := time.Now()
+
+	// Setup mock expectations
+	rows := sqlmock.NewRows([]string{
+		"id", "user_id", "license_number", "vehicle_type", "vehicle_plate",
+		"status", "current_latitude", "current_longitude", "rating", "total_trips", "fleet_id",
+		"pet_friendly", "wheelchair_accessible", "quiet_rides", "child_seat", "created_at", "updated_at",
+	}).AddRow(
+		driverID, uuid.New(), "DL123456789", "sedan", "ABC123", models.DriverStatusOffline,
+		nil, nil, 4.5, 10, fleetID, true, false, true, true, now, now,
+	)
+
+	mock.ExpectQuery(`SELECT (.+) FROM drivers WHERE fleet_id = \$1 ORDER BY created_at, id`).
+		WithArgs(fleetID.String()).
+		WillReturnRows(rows)
+
+	// Execute
+	result, err := repo.ListByFleet(context.Background(), fleetID.String())
+
+	// Assert
+	assert.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, driverID, result[0].ID)
+	require.NotNil(t, result[0].FleetID)
+	assert.Equal(t, fleetID, *result[0].FleetID)
+	assert.True(t, result[0].PetFriendly)
+	assert.False(t, result[0].WheelchairAccessible)
+	assert.True(t, result[0].QuietRides)
+	assert.True(t, result[0].ChildSeat)
+
+	// Verify all expectations were met
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,740 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/filter"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/internal/repository/factory"
+	"actor-model-observability/internal/repository/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUser(email, phone string, createdAt time.Time) *models.User {
+	return &models.User{
+		ID:        uuid.New(),
+		Email:     email,
+		Phone:     phone,
+		Name:      "Test User",
+		UserType:  models.UserTypeDriver,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	}
+}
+
+func TestMemoryUserRepository_CRUD(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository(memory.NewStore())
+
+	user := newTestUser("test@example.com", "+1234567890", time.Now())
+	require.NoError(t, repo.Create(ctx, user))
+
+	found, err := repo.GetByEmail(ctx, "test@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, found.ID)
+
+	// Returned rows are copies
+	found.Name = "Changed"
+	again, err := repo.GetByID(ctx, user.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "Test User", again.Name)
+
+	err = repo.Create(ctx, newTestUser("test@example.com", "+1999999999", time.Now()))
+	var validationErr *models.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "email", validationErr.Field)
+
+	require.NoError(t, repo.Delete(ctx, user.ID.String()))
+	_, err = repo.GetByID(ctx, user.ID.String())
+	var notFoundErr *models.NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+}
+
+func TestMemoryUserRepository_ListPagination(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository(memory.NewStore())
+
+	base := time.Now()
+	oldest := newTestUser("a@example.com", "+1000000001", base.Add(-2*time.Hour))
+	middle := newTestUser("b@example.com", "+1000000002", base.Add(-time.Hour))
+	newest := newTestUser("c@example.com", "+1000000003", base)
+	for _, u := range []*models.User{oldest, middle, newest} {
+		require.NoError(t, repo.Create(ctx, u))
+	}
+
+	page, err := repo.List(ctx, 2, 0)
+	require.NoError(t, err)
+	require.Len(t, page, 2)
+	assert.Equal(t, newest.ID, page[0].ID)
+	assert.Equal(t, middle.ID, page[1].ID)
+
+	page, err = repo.List(ctx, 2, 2)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, oldest.ID, page[0].ID)
+
+	page, err = repo.List(ctx, 2, 5)
+	require.NoError(t, err)
+	assert.Nil(t, page)
+}
+
+func TestMemoryDriverRepository_GetDriversInRadius(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	drivers := memory.NewDriverRepository(store)
+
+	newDriver := func(plate string, lat, lng float64, status models.DriverStatus) *models.Driver {
+		user := newTestUser(plate+"@example.com", "+62"+plate, time.Now())
+		require.NoError(t, users.Create(ctx, user))
+
+		driver := &models.Driver{
+			ID:            uuid.New(),
+			UserID:        user.ID,
+			LicenseNumber: "LIC-" + plate,
+			VehicleType:   "sedan",
+			VehiclePlate:  plate,
+			Status:        status,
+			Rating:        4.5,
+			CreatedAt:     time.Now(),
+			UpdatedAt:     time.Now(),
+		}
+		driver.SetLocation(lat, lng)
+		require.NoError(t, drivers.Create(ctx, driver))
+		return driver
+	}
+
+	near := newDriver("1001", -6.2001, 106.8001, models.DriverStatusOnline)
+	nearer := newDriver("1002", -6.2000, 106.8000, models.DriverStatusOnline)
+	newDriver("1003", -6.2000, 106.8000, models.DriverStatusBusy)
+	newDriver("1004", -7.2500, 112.7500, models.DriverStatusOnline)
+
+	result, err := drivers.GetDriversInRadius(ctx, -6.2000, 106.8000, 5)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, nearer.ID, result[0].ID)
+	assert.Equal(t, near.ID, result[1].ID)
+}
+
+func TestMemoryDriverRepository_Create_UserMustExist(t *testing.T) {
+	repo := memory.NewDriverRepository(memory.NewStore())
+
+	err := repo.Create(context.Background(), &models.Driver{
+		ID:            uuid.New(),
+		UserID:        uuid.New(),
+		LicenseNumber: "LIC-1",
+		VehiclePlate:  "B 1234 XYZ",
+	})
+
+	var validationErr *models.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "user_id", validationErr.Field)
+}
+
+func TestMemoryDriverRepository_GetTripStats(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	drivers := memory.NewDriverRepository(store)
+	passengers := memory.NewPassengerRepository(store)
+	trips := memory.NewTripRepository(store)
+
+	driverUser := newTestUser("2101@example.com", "+622101", time.Now())
+	require.NoError(t, users.Create(ctx, driverUser))
+	driver := &models.Driver{ID: uuid.New(), UserID: driverUser.ID, LicenseNumber: "LIC-2101", VehicleType: "sedan", VehiclePlate: "2101", Status: models.DriverStatusOnline, Rating: 4.8}
+	require.NoError(t, drivers.Create(ctx, driver))
+	passengerUser := newTestUser("rider@example.com", "+622999", time.Now())
+	require.NoError(t, users.Create(ctx, passengerUser))
+	passenger := &models.Passenger{ID: uuid.New(), UserID: passengerUser.ID}
+	require.NoError(t, passengers.Create(ctx, passenger))
+
+	// Drivers without completed trips have zero stats
+	stats, err := drivers.GetTripStats(ctx, driver.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.TotalTrips)
+	assert.Nil(t, stats.LastTripAt)
+	assert.Equal(t, 4.8, stats.AverageRating)
+
+	complete := func(completedAt time.Time, distanceKm, fare float64) *models.Trip {
+		trip := &models.Trip{ID: uuid.New(), PassengerID: passenger.ID, DriverID: &driver.ID, Status: models.TripStatusInProgress, RequestedAt: completedAt.Add(-time.Hour)}
+		require.NoError(t, trips.Create(ctx, trip))
+		trip.Status = models.TripStatusCompleted
+		trip.CompletedAt = &completedAt
+		require.NoError(t, trips.Complete(ctx, trip, models.TripStatusInProgress, &models.TripCompletion{
+			TripID:           trip.ID,
+			DriverID:         driver.ID,
+			BilledDistanceKm: distanceKm,
+			FinalFare:        fare,
+			CreatedAt:        completedAt,
+		}))
+		return trip
+	}
+	now := time.Now()
+	complete(now.Add(-40*24*time.Hour), 20, 50) // before the earnings window
+	complete(now.Add(-2*24*time.Hour), 5.5, 12)
+	last := complete(now, 3, 8.5)
+
+	stats, err = drivers.GetTripStats(ctx, driver.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.TotalTrips)
+	assert.InDelta(t, 28.5, stats.TotalDistanceKm, 1e-9)
+	assert.InDelta(t, 20.5, stats.RecentEarnings, 1e-9)
+	require.NotNil(t, stats.LastTripAt)
+	assert.True(t, now.Equal(*stats.LastTripAt))
+
+	// Completions failing on a status conflict are not rolled up
+	assert.ErrorIs(t, trips.Complete(ctx, last, models.TripStatusInProgress, &models.TripCompletion{TripID: last.ID, DriverID: driver.ID, FinalFare: 8.5, CreatedAt: now}), models.ErrTripStatusConflict)
+	stats, err = drivers.GetTripStats(ctx, driver.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 3, stats.TotalTrips)
+
+	_, err = drivers.GetTripStats(ctx, uuid.NewString())
+	var notFound *models.NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+}
+
+func TestMemoryDriverRepository_GetDriverStats(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	drivers := memory.NewDriverRepository(store)
+	passengers := memory.NewPassengerRepository(store)
+	trips := memory.NewTripRepository(store)
+
+	newDriver := func(plate string) *models.Driver {
+		user := newTestUser(plate+"@example.com", "+62"+plate, time.Now())
+		require.NoError(t, users.Create(ctx, user))
+		driver := &models.Driver{
+			ID:            uuid.New(),
+			UserID:        user.ID,
+			LicenseNumber: "LIC-" + plate,
+			VehicleType:   "sedan",
+			VehiclePlate:  plate,
+			Status:        models.DriverStatusOffline,
+			Rating:        4.5,
+		}
+		require.NoError(t, drivers.Create(ctx, driver))
+		return driver
+	}
+	busy := newDriver("2001")
+	idle := newDriver("2002")
+	require.NoError(t, drivers.UpdateStatus(ctx, busy.ID.String(), models.DriverStatusOnline))
+
+	passengerUser := newTestUser("rider@example.com", "+622999", time.Now())
+	require.NoError(t, users.Create(ctx, passengerUser))
+	passenger := &models.Passenger{ID: uuid.New(), UserID: passengerUser.ID}
+	require.NoError(t, passengers.Create(ctx, passenger))
+
+	fare := 12.5
+	now := time.Now()
+	for i, status := range []models.TripStatus{models.TripStatusCompleted, models.TripStatusCompleted, models.TripStatusCancelled} {
+		require.NoError(t, trips.Create(ctx, &models.Trip{
+			ID:          uuid.New(),
+			PassengerID: passenger.ID,
+			DriverID:    &busy.ID,
+			Status:      status,
+			FareAmount:  &fare,
+			RequestedAt: now.Add(-time.Duration(i+1) * time.Minute),
+		}))
+	}
+	// Outside the period
+	require.NoError(t, trips.Create(ctx, &models.Trip{
+		ID:          uuid.New(),
+		PassengerID: passenger.ID,
+		DriverID:    &busy.ID,
+		Status:      models.TripStatusCompleted,
+		FareAmount:  &fare,
+		RequestedAt: now.Add(-48 * time.Hour),
+	}))
+
+	stats, total, err := drivers.GetDriverStats(ctx, models.DriverStatsFilter{
+		From:       now.Add(-time.Hour),
+		To:         now.Add(time.Hour),
+		SortBy:     models.DriverStatsSortTripsCompleted,
+		Descending: true,
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, busy.ID, stats[0].DriverID)
+	assert.Equal(t, "Test User", stats[0].Name)
+	assert.Equal(t, 3, stats[0].TripsAssigned)
+	assert.Equal(t, 2, stats[0].TripsCompleted)
+	assert.Equal(t, 1, stats[0].TripsCancelled)
+	assert.InDelta(t, 2.0/3.0, stats[0].AcceptanceRate, 1e-9)
+	assert.InDelta(t, 25.0, stats[0].Earnings, 1e-9)
+
+	assert.Equal(t, idle.ID, stats[1].DriverID)
+	assert.Zero(t, stats[1].TripsAssigned)
+	assert.Zero(t, stats[1].OnlineHours)
+
+	_, _, err = drivers.GetDriverStats(ctx, models.DriverStatsFilter{SortBy: "name"})
+	var validationErr *models.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+}
+
+func TestMemoryTripRepository_Search(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	drivers := memory.NewDriverRepository(store)
+	passengers := memory.NewPassengerRepository(store)
+	trips := memory.NewTripRepository(store)
+
+	driverUser := newTestUser("driver@example.com", "+623001", time.Now())
+	require.NoError(t, users.Create(ctx, driverUser))
+	driver := &models.Driver{
+		ID:            uuid.New(),
+		UserID:        driverUser.ID,
+		LicenseNumber: "LIC-3001",
+		VehicleType:   "sedan",
+		VehiclePlate:  "B 1234 XY",
+		Status:        models.DriverStatusOffline,
+	}
+	require.NoError(t, drivers.Create(ctx, driver))
+
+	newPassenger := func(email, phone string) *models.Passenger {
+		user := newTestUser(email, phone, time.Now())
+		user.Name = "Rider " + phone
+		require.NoError(t, users.Create(ctx, user))
+		passenger := &models.Passenger{ID: uuid.New(), UserID: user.ID}
+		require.NoError(t, passengers.Create(ctx, passenger))
+		return passenger
+	}
+	alice := newPassenger("Alice@Example.com", "+623101")
+	bob := newPassenger("bob@example.com", "+623102")
+
+	now := time.Now()
+	newTrip := func(passenger *models.Passenger, driverID *uuid.UUID, requestedAt time.Time, lat, lng float64) *models.Trip {
+		trip := &models.Trip{
+			ID:              uuid.New(),
+			PassengerID:     passenger.ID,
+			DriverID:        driverID,
+			Status:          models.TripStatusCompleted,
+			PickupLatitude:  lat,
+			PickupLongitude: lng,
+			RequestedAt:     requestedAt,
+		}
+		require.NoError(t, trips.Create(ctx, trip))
+		return trip
+	}
+	recent := newTrip(alice, &driver.ID, now.Add(-time.Hour), -6.2, 106.8)
+	older := newTrip(alice, nil, now.Add(-48*time.Hour), -6.9, 107.6)
+	other := newTrip(bob, &driver.ID, now.Add(-2*time.Hour), -6.2, 106.81)
+
+	search := func(filter models.TripSearchFilter) ([]*models.TripSearchResult, int64) {
+		filter.Limit = 10
+		results, total, err := trips.Search(ctx, filter)
+		require.NoError(t, err)
+		return results, total
+	}
+	ids := func(results []*models.TripSearchResult) []uuid.UUID {
+		var ids []uuid.UUID
+		for _, r := range results {
+			ids = append(ids, r.ID)
+		}
+		return ids
+	}
+
+	results, total := search(models.TripSearchFilter{PassengerEmail: "alice@example.COM"})
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []uuid.UUID{recent.ID, older.ID}, ids(results), "most recently requested first")
+	assert.Equal(t, "Rider +623101", results[0].PassengerName)
+	assert.Equal(t, "+623101", results[0].PassengerPhone)
+	require.NotNil(t, results[0].DriverPlate)
+	assert.Equal(t, "B 1234 XY", *results[0].DriverPlate)
+	assert.Equal(t, "Test User", *results[0].DriverName)
+	assert.Nil(t, results[1].DriverPlate)
+
+	results, _ = search(models.TripSearchFilter{DriverPlate: "b1234xy"})
+	assert.Equal(t, []uuid.UUID{recent.ID, other.ID}, ids(results))
+
+	// Criteria combine
+	results, _ = search(models.TripSearchFilter{DriverPlate: "B 1234 XY", PassengerPhone: "+623102"})
+	assert.Equal(t, []uuid.UUID{other.ID}, ids(results))
+
+	results, _ = search(models.TripSearchFilter{TripIDPrefix: strings.ToUpper(older.ID.String()[:8])})
+	assert.Equal(t, []uuid.UUID{older.ID}, ids(results))
+
+	from, to := now.Add(-90*time.Minute), now.Add(-30*time.Minute)
+	results, _ = search(models.TripSearchFilter{RequestedFrom: &from, RequestedTo: &to})
+	assert.Equal(t, []uuid.UUID{recent.ID}, ids(results))
+
+	bounds := models.GeoBoundsAround(models.Location{Latitude: -6.2, Longitude: 106.8}, 2)
+	results, _ = search(models.TripSearchFilter{PickupBounds: &bounds})
+	assert.Equal(t, []uuid.UUID{recent.ID, other.ID}, ids(results))
+
+	results, total = search(models.TripSearchFilter{PassengerPhone: "+629999"})
+	assert.Empty(t, results)
+	assert.Zero(t, total)
+}
+
+func TestMemoryTripRepository_ListStuck(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	passengers := memory.NewPassengerRepository(store)
+	trips := memory.NewTripRepository(store)
+
+	user := newTestUser("stuck@example.com", "+623201", time.Now())
+	require.NoError(t, users.Create(ctx, user))
+	passenger := &models.Passenger{ID: uuid.New(), UserID: user.ID}
+	require.NoError(t, passengers.Create(ctx, passenger))
+
+	now := time.Now()
+	newTrip := func(status models.TripStatus, updatedAt time.Time) *models.Trip {
+		trip := &models.Trip{ID: uuid.New(), PassengerID: passenger.ID, Status: status, RequestedAt: updatedAt, UpdatedAt: updatedAt}
+		require.NoError(t, trips.Create(ctx, trip))
+		return trip
+	}
+	oldest := newTrip(models.TripStatusRequested, now.Add(-3*time.Hour))
+	stalled := newTrip(models.TripStatusInProgress, now.Add(-time.Hour))
+	newTrip(models.TripStatusMatched, now.Add(-time.Minute))
+	newTrip(models.TripStatusCompleted, now.Add(-5*time.Hour))
+	newTrip(models.TripStatusCancelled, now.Add(-5*time.Hour))
+
+	stuck, err := trips.ListStuck(ctx, now.Add(-15*time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, stuck, 2)
+	assert.Equal(t, oldest.ID, stuck[0].ID, "least recently updated first")
+	assert.Equal(t, stalled.ID, stuck[1].ID)
+
+	stuck, err = trips.ListStuck(ctx, now.Add(-15*time.Minute), 1)
+	require.NoError(t, err)
+	require.Len(t, stuck, 1)
+	assert.Equal(t, oldest.ID, stuck[0].ID)
+}
+
+func TestMemorySavedLocationRepository_OneHomePerPassenger(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	passengers := memory.NewPassengerRepository(store)
+	locations := memory.NewSavedLocationRepository(store)
+
+	user := newTestUser("home@example.com", "+623001", time.Now())
+	require.NoError(t, users.Create(ctx, user))
+	passenger := &models.Passenger{ID: uuid.New(), UserID: user.ID}
+	require.NoError(t, passengers.Create(ctx, passenger))
+
+	newLocation := func(label models.SavedLocationLabel, name string) *models.SavedLocation {
+		return &models.SavedLocation{ID: uuid.New(), PassengerID: passenger.ID, Label: label, Name: name, CreatedAt: time.Now()}
+	}
+
+	require.NoError(t, locations.Create(ctx, newLocation(models.SavedLocationCustom, "Gym")))
+	require.NoError(t, locations.Create(ctx, newLocation(models.SavedLocationHome, "Home")))
+	require.NoError(t, locations.Create(ctx, newLocation(models.SavedLocationCustom, "Cafe")))
+
+	err := locations.Create(ctx, newLocation(models.SavedLocationHome, "Second home"))
+	var validationErr *models.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "label", validationErr.Field)
+
+	list, err := locations.ListByPassengerID(ctx, passenger.ID.String())
+	require.NoError(t, err)
+	require.Len(t, list, 3)
+	assert.Equal(t, "Home", list[0].Name)
+
+	// Deleting the passenger removes its saved locations
+	require.NoError(t, passengers.Delete(ctx, passenger.ID.String()))
+	list, err = locations.ListByPassengerID(ctx, passenger.ID.String())
+	require.NoError(t, err)
+	assert.Empty(t, list)
+}
+
+func TestMemoryObservabilityRepository_EventLogsByTimeRange(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewObservabilityRepository(memory.NewStore())
+
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, offset := range []time.Duration{-3 * time.Hour, -30 * time.Minute, 0} {
+		require.NoError(t, repo.CreateEventLog(ctx, &models.EventLog{
+			ID:            uuid.New(),
+			EventType:     "test_event",
+			EventCategory: models.EventCategorySystem,
+			Severity:      models.EventSeverityInfo,
+			Message:       string(rune('a' + i)),
+			Timestamp:     now.Add(offset),
+			CreatedAt:     now.Add(offset),
+		}))
+	}
+
+	logs, err := repo.GetEventLogsByTimeRange(ctx,
+		now.Add(-time.Hour).Format(time.RFC3339), now.Format(time.RFC3339), 10, 0)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "c", logs[0].Message)
+	assert.Equal(t, "b", logs[1].Message)
+
+	_, err = repo.GetEventLogsByTimeRange(ctx, "yesterday", now.Format(time.RFC3339), 10, 0)
+	assert.Error(t, err)
+}
+
+func TestMemoryIntegrityRepository_ChecksumHours(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewStore()
+	observability := memory.NewObservabilityRepository(store)
+	repo := memory.NewIntegrityRepository(store)
+
+	hour := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	for i, at := range []time.Time{hour.Add(5 * time.Minute), hour.Add(50 * time.Minute), hour.Add(70 * time.Minute), hour.Add(3 * time.Hour)} {
+		require.NoError(t, observability.CreateEventLog(ctx, &models.EventLog{
+			ID:            uuid.MustParse(fmt.Sprintf("00000000-0000-0000-0000-%012d", i+1)),
+			EventType:     "test_event",
+			EventCategory: models.EventCategorySystem,
+			Severity:      models.EventSeverityInfo,
+			Timestamp:     at,
+			CreatedAt:     at,
+		}))
+	}
+
+	checksums, err := repo.ChecksumHours(ctx, "event_logs", hour, hour.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, checksums, 2)
+	assert.Equal(t, hour, checksums[0].Hour)
+	assert.Equal(t, int64(2), checksums[0].RowCount)
+	// The first 32 bits of the MD5 of each ID, as PostgreSQL computes them
+	assert.Equal(t, int64(3378874862+2180261600), checksums[0].Checksum)
+	assert.Equal(t, hour.Add(time.Hour), checksums[1].Hour)
+	assert.Equal(t, int64(1), checksums[1].RowCount)
+
+	_, err = repo.ChecksumHours(ctx, "users", hour, hour.Add(time.Hour))
+	assert.Error(t, err)
+
+	old := &models.IntegrityRun{ID: uuid.New(), WindowStart: hour, WindowEnd: hour.Add(2 * time.Hour), StartedAt: hour.Add(2 * time.Hour)}
+	latest := &models.IntegrityRun{ID: uuid.New(), WindowStart: hour, WindowEnd: hour.Add(3 * time.Hour), StartedAt: hour.Add(3 * time.Hour)}
+	require.NoError(t, repo.CreateRun(ctx, old, checksums))
+	require.NoError(t, repo.CreateRun(ctx, latest, nil))
+
+	previous, err := repo.GetPreviousRun(ctx, latest)
+	require.NoError(t, err)
+	require.NotNil(t, previous)
+	assert.Equal(t, old.ID, previous.ID)
+	stored, err := repo.ListChecksums(ctx, old.ID.String())
+	require.NoError(t, err)
+	require.Len(t, stored, 2)
+	assert.Equal(t, old.ID, stored[0].RunID)
+
+	deleted, err := repo.DeleteRunsBefore(ctx, latest.StartedAt)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	previous, err = repo.GetPreviousRun(ctx, latest)
+	require.NoError(t, err)
+	assert.Nil(t, previous)
+	stored, err = repo.ListChecksums(ctx, old.ID.String())
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func TestMemoryObservabilityRepository_GetMessageStats(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewObservabilityRepository(memory.NewStore())
+
+	now := time.Now()
+	create := func(messageType string, receiver models.ActorType, status models.MessageStatus, durationMs *int, createdAt time.Time) {
+		require.NoError(t, repo.CreateActorMessage(ctx, &models.ActorMessage{
+			ID:                   uuid.New(),
+			TraceID:              uuid.New(),
+			SpanID:               uuid.New(),
+			SenderActorType:      models.ActorTypePassenger,
+			ReceiverActorType:    receiver,
+			MessageType:          messageType,
+			Status:               status,
+			ProcessingDurationMs: durationMs,
+			CreatedAt:            createdAt,
+		}))
+	}
+	// Processing durations of 10, 20, ... 100ms, of which the last two failed
+	for i := 1; i <= 10; i++ {
+		duration := i * 10
+		status := models.MessageStatusProcessed
+		if i > 8 {
+			status = models.MessageStatusFailed
+		}
+		create("request_ride", models.ActorTypeTrip, status, &duration, now)
+	}
+	create("request_ride", models.ActorTypeMatching, models.MessageStatusDeadLettered, nil, now)
+	create("request_ride", models.ActorTypeTrip, models.MessageStatusFailed, nil, now.Add(-2*time.Hour))
+
+	stats, err := repo.GetMessageStats(ctx, time.Hour)
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+
+	assert.Equal(t, models.ActorTypeMatching, stats[0].ActorType)
+	assert.Equal(t, int64(1), stats[0].Total)
+	assert.Equal(t, 1.0, stats[0].FailureRatio)
+	assert.Nil(t, stats[0].P50ProcessingMs)
+
+	trip := stats[1]
+	assert.Equal(t, models.ActorTypeTrip, trip.ActorType)
+	assert.Equal(t, int64(10), trip.Total)
+	assert.Equal(t, int64(2), trip.Failed)
+	assert.InDelta(t, 0.2, trip.FailureRatio, 1e-9)
+	assert.InDelta(t, 55, *trip.P50ProcessingMs, 1e-9)
+	assert.InDelta(t, 95.5, *trip.P95ProcessingMs, 1e-9)
+	assert.InDelta(t, 99.1, *trip.P99ProcessingMs, 1e-9)
+}
+
+func TestMemoryObservabilityRepository_GetActorLifecycleActivity(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewObservabilityRepository(memory.NewStore())
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	spawn := func(actorType models.ActorType, actorID string, at time.Time) {
+		require.NoError(t, repo.CreateActorInstance(ctx, &models.ActorInstance{
+			ID:        uuid.New(),
+			ActorType: actorType,
+			ActorID:   actorID,
+			Status:    models.ActorStatusActive,
+			CreatedAt: at,
+			UpdatedAt: at,
+		}))
+	}
+	event := func(eventType, actorID string, at time.Time) {
+		require.NoError(t, repo.CreateEventLog(ctx, &models.EventLog{
+			ID:            uuid.New(),
+			EventType:     eventType,
+			EventCategory: models.EventCategorySystem,
+			ActorID:       &actorID,
+			Severity:      models.EventSeverityInfo,
+			Timestamp:     at,
+			CreatedAt:     at,
+		}))
+	}
+	processed := func(actorID string, at time.Time, durationMs int) {
+		require.NoError(t, repo.CreateActorMessage(ctx, &models.ActorMessage{
+			ID:                   uuid.New(),
+			TraceID:              uuid.New(),
+			SpanID:               uuid.New(),
+			ReceiverActorID:      actorID,
+			MessageType:          "location_update",
+			Status:               models.MessageStatusProcessed,
+			ProcessedAt:          &at,
+			ProcessingDurationMs: &durationMs,
+			CreatedAt:            at,
+		}))
+	}
+
+	// Alive before the window, then processing, failing and stopping in it
+	spawn(models.ActorTypeDriver, "driver-1", start.Add(-time.Hour))
+	processed("driver-1", start.Add(150*time.Second), 40)
+	event("actor_failed", "driver-1", start.Add(310*time.Second))
+	event("actor_stopped", "driver-1", start.Add(7*time.Minute))
+	// Spawned within the window
+	spawn(models.ActorTypeTrip, "trip-1", start.Add(210*time.Second))
+	event("actor_started", "trip-1", start.Add(210*time.Second))
+	processed("trip-1", start.Add(250*time.Second), 15)
+	processed("trip-1", start.Add(255*time.Second), 5)
+	// Stopped before the window
+	spawn(models.ActorTypePassenger, "passenger-1", start.Add(-2*time.Hour))
+	event("actor_stopped", "passenger-1", start.Add(-time.Hour))
+
+	lf := models.ActorLifecycleFilter{Start: start, End: start.Add(10 * time.Minute), Bucket: time.Minute, Limit: 10}
+	activity, err := repo.GetActorLifecycleActivity(ctx, lf)
+	require.NoError(t, err)
+
+	lifecycles := observability.BuildActorLifecycles(lf, activity)
+	require.Len(t, lifecycles, 2)
+	segment := func(phase models.ActorLifecyclePhase, from, to int, messages, processingMs, failures int64) models.ActorLifecycleSegment {
+		return models.ActorLifecycleSegment{
+			Phase:        phase,
+			Start:        start.Add(time.Duration(from) * time.Minute),
+			End:          start.Add(time.Duration(to) * time.Minute),
+			Messages:     messages,
+			ProcessingMs: processingMs,
+			Failures:     failures,
+		}
+	}
+
+	assert.Equal(t, "trip-1", lifecycles[0].ActorID)
+	assert.Equal(t, []models.ActorLifecycleSegment{
+		segment(models.ActorLifecycleSpawned, 3, 4, 0, 0, 0),
+		segment(models.ActorLifecycleProcessing, 4, 5, 2, 20, 0),
+		segment(models.ActorLifecycleIdle, 5, 10, 0, 0, 0),
+	}, lifecycles[0].Segments)
+
+	assert.Equal(t, "driver-1", lifecycles[1].ActorID)
+	assert.Equal(t, []models.ActorLifecycleSegment{
+		segment(models.ActorLifecycleIdle, 0, 2, 0, 0, 0),
+		segment(models.ActorLifecycleProcessing, 2, 3, 1, 40, 0),
+		segment(models.ActorLifecycleIdle, 3, 5, 0, 0, 0),
+		segment(models.ActorLifecycleFailed, 5, 6, 0, 0, 1),
+		segment(models.ActorLifecycleIdle, 6, 7, 0, 0, 0),
+		segment(models.ActorLifecycleStopped, 7, 8, 0, 0, 0),
+	}, lifecycles[1].Segments)
+
+	lf.ActorType = string(models.ActorTypeDriver)
+	activity, err = repo.GetActorLifecycleActivity(ctx, lf)
+	require.NoError(t, err)
+	require.Len(t, activity, 3)
+	for _, a := range activity {
+		assert.Equal(t, "driver-1", a.ActorID)
+		assert.True(t, a.AliveAtStart)
+	}
+}
+
+func TestMemoryObservabilityRepository_FilterEventLogs(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewObservabilityRepository(memory.NewStore())
+
+	now := time.Now().UTC()
+	tripActor := models.ActorTypeTrip
+	for i, event := range []struct {
+		severity  models.EventSeverity
+		actorType *models.ActorType
+		message   string
+	}{
+		{models.EventSeverityWarn, &tripActor, "Request timeout after 5s"},
+		{models.EventSeverityInfo, &tripActor, "timeout configured"},
+		{models.EventSeverityError, nil, "connection timeout"},
+		{models.EventSeverityFatal, &tripActor, "matching timeout"},
+	} {
+		require.NoError(t, repo.CreateEventLog(ctx, &models.EventLog{
+			ID:            uuid.New(),
+			EventType:     "test_event",
+			EventCategory: models.EventCategorySystem,
+			ActorType:     event.actorType,
+			Severity:      event.severity,
+			Message:       event.message,
+			Timestamp:     now.Add(time.Duration(i) * time.Second),
+		}))
+	}
+
+	f, err := filter.Parse(`severity>=warning AND actor_type=trip AND message~"TIMEOUT"`, filter.EventLogFields)
+	require.NoError(t, err)
+
+	logs, err := repo.FilterEventLogs(ctx, f, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+	assert.Equal(t, "matching timeout", logs[0].Message)
+	assert.Equal(t, "Request timeout after 5s", logs[1].Message)
+
+	logs, err = repo.FilterEventLogs(ctx, f, 10, 1)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+}
+
+func TestRepositoryFactory_SelectsBackend(t *testing.T) {
+	repos, err := factory.NewRepositories(&config.DatabaseConfig{Driver: "memory"}, nil, nil)
+	require.NoError(t, err)
+	assert.IsType(t, &memory.UserRepositoryImpl{}, repos.Users)
+	assert.IsType(t, &memory.TraditionalRepositoryImpl{}, repos.Traditional)
+
+	_, err = factory.NewRepositories(&config.DatabaseConfig{Driver: "postgres"}, nil, nil)
+	assert.Error(t, err)
+
+	_, err = factory.NewRepositories(&config.DatabaseConfig{Driver: "mysql"}, nil, nil)
+	assert.Error(t, err)
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"actor-model-observability/internal/filter"
 	"actor-model-observability/internal/models"
 	"actor-model-observability/internal/repository/postgres"
 	"actor-model-observability/tests/utils"
@@ -13,6 +14,7 @@ import (
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestObservabilityRepository_CreateActorInstance_Success(t *testing.T) {
@@ -24,11 +26,13 @@ func TestObservabilityRepository_CreateActorInstance_Success(t *testing.T) {
 	actorID := uuid.New()
 	now := time.Now()
 
+	entityType := models.EntityTypePassenger
 	entityID := uuid.New()
 	instance := &models.ActorInstance{
 		ID:            actorID,
 		ActorType:     models.ActorTypePassenger,
 		ActorID:       "passenger-123",
+		EntityType:    &entityType,
 		EntityID:      &entityID,
 		Status:        models.ActorStatusActive,
 		CreatedAt:     now,
@@ -38,7 +42,7 @@ func TestObservabilityRepository_CreateActorInstance_Success(t *testing.T) {
 
 	mock.ExpectExec(`INSERT INTO actor_instances`).
 		WithArgs(
-			actorID, models.ActorTypePassenger, "passenger-123", &entityID, models.ActorStatusActive,
+			actorID, models.ActorTypePassenger, "passenger-123", &entityType, &entityID, models.ActorStatusActive,
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
@@ -60,9 +64,9 @@ func TestObservabilityRepository_GetActorInstance_Success(t *testing.T) {
 	now := time.Now()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "actor_type", "actor_id", "entity_id", "status", "last_heartbeat", "created_at", "updated_at",
+		"id", "actor_type", "actor_id", "entity_type", "entity_id", "status", "last_heartbeat", "created_at", "updated_at",
 	}).AddRow(
-		actorID, models.ActorTypePassenger, "passenger-123", &entityID, models.ActorStatusActive, now, now, now,
+		actorID, models.ActorTypePassenger, "passenger-123", models.EntityTypePassenger, &entityID, models.ActorStatusActive, now, now, now,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM actor_instances WHERE id = \$1`).
@@ -103,14 +107,15 @@ func TestObservabilityRepository_CreateActorMessage_Success(t *testing.T) {
 		ReceiverActorID:   "driver-456",
 		SentAt:            now,
 		MessagePayload:    json.RawMessage(`{"pickup_lat": 40.7128, "pickup_lng": -74.0060}`),
+		MessageVersion:    1,
 		CreatedAt:         now,
 	}
 
 	mock.ExpectExec(`INSERT INTO actor_messages`).
 		WithArgs(
 			messageID, traceID, spanID, sqlmock.AnyArg(), "passenger", "passenger-123",
-			"driver", "driver-456", "ride_request", sqlmock.AnyArg(), sqlmock.AnyArg(),
-			now, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), now,
+			"driver", "driver-456", sqlmock.AnyArg(), sqlmock.AnyArg(), "ride_request", sqlmock.AnyArg(), 1,
+			sqlmock.AnyArg(), now, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 0, sqlmock.AnyArg(), nil, now,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -135,11 +140,11 @@ func TestObservabilityRepository_ListActorMessages_Success(t *testing.T) {
 	spanID2 := uuid.New()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "trace_id", "span_id", "parent_span_id", "sender_actor_type", "sender_actor_id", "receiver_actor_type", "receiver_actor_id", "message_type", "message_payload", "status", "sent_at", "received_at", "processed_at", "processing_duration_ms", "error_message", "created_at",
+		"id", "trace_id", "span_id", "parent_span_id", "sender_actor_type", "sender_actor_id", "receiver_actor_type", "receiver_actor_id", "entity_type", "entity_id", "message_type", "message_payload", "message_version", "status", "sent_at", "received_at", "processed_at", "processing_duration_ms", "error_message", "retry_count", "effects", "hlc", "created_at",
 	}).AddRow(
-		messageID1, traceID, spanID1, nil, models.ActorTypePassenger, "passenger-123", models.ActorTypeDriver, "driver-456", "ride_request", json.RawMessage(`{"pickup_lat": 40.7128}`), models.MessageStatusSent, now, nil, nil, nil, nil, now,
+		messageID1, traceID, spanID1, nil, models.ActorTypePassenger, "passenger-123", models.ActorTypeDriver, "driver-456", nil, nil, "ride_request", json.RawMessage(`{"pickup_lat": 40.7128}`), 1, models.MessageStatusSent, now, nil, nil, nil, nil, 0, nil, nil, now,
 	).AddRow(
-		messageID2, traceID, spanID2, nil, models.ActorTypeDriver, "driver-456", models.ActorTypePassenger, "passenger-123", "ride_accepted", json.RawMessage(`{"eta": 5}`), models.MessageStatusSent, now, nil, nil, nil, nil, now,
+		messageID2, traceID, spanID2, nil, models.ActorTypeDriver, "driver-456", models.ActorTypePassenger, "passenger-123", nil, nil, "ride_accepted", json.RawMessage(`{"eta": 5}`), 2, models.MessageStatusFailed, now, nil, nil, nil, nil, 2, json.RawMessage(`[{"entity_type":"driver","entity_id":"driver-456","action":"status_changed"}]`), nil, now,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM actor_messages WHERE sender_actor_id = \$1 AND receiver_actor_id = \$2`).
@@ -154,6 +159,101 @@ func TestObservabilityRepository_ListActorMessages_Success(t *testing.T) {
 	assert.Equal(t, "ride_request", messages[0].MessageType)
 	assert.Equal(t, messageID2, messages[1].ID)
 	assert.Equal(t, "ride_accepted", messages[1].MessageType)
+	assert.Equal(t, 2, messages[1].RetryCount)
+	assert.Equal(t, 2, messages[1].MessageVersion)
+	assert.True(t, messages[1].Affects(models.EntityTypeDriver, "driver-456"))
+	assert.False(t, messages[0].Affects(models.EntityTypeDriver, "driver-456"))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestObservabilityRepository_ListActorMessagesByEntity_Success(t *testing.T) {
+	db, mock := utils.SetupMockDB(t)
+	defer db.Close()
+
+	repo := postgres.NewObservabilityRepository(db)
+
+	messageID := uuid.New()
+	tripID := uuid.New()
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "trace_id", "span_id", "parent_span_id", "sender_actor_type", "sender_actor_id", "receiver_actor_type", "receiver_actor_id", "entity_type", "entity_id", "message_type", "message_payload", "message_version", "status", "sent_at", "received_at", "processed_at", "processing_duration_ms", "error_message", "retry_count", "effects", "hlc", "created_at",
+	}).AddRow(
+		messageID, uuid.New(), uuid.New(), nil, models.ActorTypePassenger, "passenger-123", models.ActorTypeTrip, "trip-matcher", models.EntityTypeTrip, tripID, "request_ride", json.RawMessage(`{"pickup_lat": 40.7128}`), 1, models.MessageStatusSent, now, nil, nil, nil, nil, 0, nil, nil, now,
+	)
+
+	mock.ExpectQuery(`SELECT (.+) FROM actor_messages WHERE entity_type = \$1 AND entity_id = \$2`).
+		WithArgs(models.EntityTypeTrip, tripID.String(), 10, 0).
+		WillReturnRows(rows)
+
+	messages, err := repo.ListActorMessagesByEntity(context.Background(), models.EntityTypeTrip, tripID.String(), 10, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, messages, 1)
+	assert.Equal(t, messageID, messages[0].ID)
+	assert.Equal(t, models.EntityTypeTrip, *messages[0].EntityType)
+	assert.Equal(t, tripID, *messages[0].EntityID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestObservabilityRepository_GetMessageStats_Success(t *testing.T) {
+	db, mock := utils.SetupMockDB(t)
+	defer db.Close()
+
+	repo := postgres.NewObservabilityRepository(db)
+
+	rows := sqlmock.NewRows([]string{
+		"message_type", "actor_type", "total", "failed",
+		"p50_processing_duration_ms", "p95_processing_duration_ms", "p99_processing_duration_ms",
+	}).AddRow(
+		"request_ride", models.ActorTypeTrip, 20, 5, 12.0, 40.5, 80.0,
+	).AddRow(
+		"status_update", models.ActorTypeDriver, 3, 0, nil, nil, nil,
+	)
+
+	mock.ExpectQuery(`SELECT message_type, receiver_actor_type AS actor_type, (.+) percentile_cont\(0.99\) WITHIN GROUP \(ORDER BY processing_duration_ms\) (.+) FROM actor_messages WHERE created_at >= \$1 GROUP BY message_type, receiver_actor_type`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(rows)
+
+	stats, err := repo.GetMessageStats(context.Background(), time.Hour)
+
+	assert.NoError(t, err)
+	assert.Len(t, stats, 2)
+	assert.Equal(t, "request_ride", stats[0].MessageType)
+	assert.Equal(t, int64(5), stats[0].Failed)
+	assert.Equal(t, 0.25, stats[0].FailureRatio)
+	assert.Equal(t, 40.5, *stats[0].P95ProcessingMs)
+	assert.Equal(t, 0.0, stats[1].FailureRatio)
+	assert.Nil(t, stats[1].P50ProcessingMs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestObservabilityRepository_ListActorInstancesByEntity_Success(t *testing.T) {
+	db, mock := utils.SetupMockDB(t)
+	defer db.Close()
+
+	repo := postgres.NewObservabilityRepository(db)
+
+	actorID := uuid.New()
+	driverID := uuid.New()
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{
+		"id", "actor_type", "actor_id", "entity_type", "entity_id", "status", "last_heartbeat", "created_at", "updated_at",
+	}).AddRow(
+		actorID, models.ActorTypeDriver, "driver-"+driverID.String(), models.EntityTypeDriver, driverID, models.ActorStatusActive, now, now, now,
+	)
+
+	mock.ExpectQuery(`SELECT (.+) FROM actor_instances WHERE entity_type = \$1 AND entity_id = \$2`).
+		WithArgs(models.EntityTypeDriver, driverID.String(), 20, 0).
+		WillReturnRows(rows)
+
+	instances, err := repo.ListActorInstancesByEntity(context.Background(), models.EntityTypeDriver, driverID.String(), 20, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, instances, 1)
+	assert.Equal(t, actorID, instances[0].ID)
+	assert.Equal(t, driverID, *instances[0].EntityID)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -332,7 +432,7 @@ func TestObservabilityRepository_CreateEventLog_Success(t *testing.T) {
 			eventID, nil, "ride_requested", models.EventCategoryBusiness,
 			nil, nil, nil, nil,
 			json.RawMessage(`{"passenger_id": "123", "pickup_lat": 40.7128}`),
-			models.EventSeverityInfo, "Passenger requested a ride", now, now,
+			models.EventSeverityInfo, "Passenger requested a ride", now, nil, now,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -353,11 +453,11 @@ func TestObservabilityRepository_ListEventLogs_Success(t *testing.T) {
 	now := time.Now()
 
 	rows := sqlmock.NewRows([]string{
-		"id", "trace_id", "event_type", "event_category", "actor_type", "actor_id", "entity_type", "entity_id", "event_data", "severity", "message", "timestamp", "created_at",
+		"id", "trace_id", "event_type", "event_category", "actor_type", "actor_id", "entity_type", "entity_id", "event_data", "severity", "message", "timestamp", "hlc", "created_at",
 	}).AddRow(
-		eventID1, nil, "ride_requested", models.EventCategoryBusiness, nil, nil, nil, nil, json.RawMessage(`{"passenger_id": "123"}`), models.EventSeverityInfo, "Passenger requested a ride", now, now,
+		eventID1, nil, "ride_requested", models.EventCategoryBusiness, nil, nil, nil, nil, json.RawMessage(`{"passenger_id": "123"}`), models.EventSeverityInfo, "Passenger requested a ride", now, nil, now,
 	).AddRow(
-		eventID2, nil, "ride_requested", models.EventCategoryBusiness, nil, nil, nil, nil, json.RawMessage(`{"passenger_id": "456"}`), models.EventSeverityInfo, "Another ride requested", now, now,
+		eventID2, nil, "ride_requested", models.EventCategoryBusiness, nil, nil, nil, nil, json.RawMessage(`{"passenger_id": "456"}`), models.EventSeverityInfo, "Another ride requested", now, nil, now,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM event_logs WHERE event_type = \$1`).
@@ -387,9 +487,9 @@ func TestObservabilityRepository_GetEventLogsByTimeRange_Success(t *testing.T) {
 	endTime := now
 
 	rows := sqlmock.NewRows([]string{
-		"id", "trace_id", "event_type", "event_category", "actor_type", "actor_id", "entity_type", "entity_id", "event_data", "severity", "message", "timestamp", "created_at",
+		"id", "trace_id", "event_type", "event_category", "actor_type", "actor_id", "entity_type", "entity_id", "event_data", "severity", "message", "timestamp", "hlc", "created_at",
 	}).AddRow(
-		eventID, nil, "ride_requested", models.EventCategoryBusiness, nil, nil, nil, nil, json.RawMessage(`{"passenger_id": "123"}`), models.EventSeverityInfo, "Passenger requested a ride", now, now,
+		eventID, nil, "ride_requested", models.EventCategoryBusiness, nil, nil, nil, nil, json.RawMessage(`{"passenger_id": "123"}`), models.EventSeverityInfo, "Passenger requested a ride", now, nil, now,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM event_logs WHERE (.+)`).
@@ -404,3 +504,33 @@ func TestObservabilityRepository_GetEventLogsByTimeRange_Success(t *testing.T) {
 	assert.Equal(t, "ride_requested", eventLogs[0].EventType)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestObservabilityRepository_FilterEventLogs_Success(t *testing.T) {
+	db, mock := utils.SetupMockDB(t)
+	defer db.Close()
+
+	repo := postgres.NewObservabilityRepository(db)
+
+	f, err := filter.Parse(`severity>=error AND message~timeout`, filter.EventLogFields)
+	require.NoError(t, err)
+
+	logID := uuid.New()
+	rows := sqlmock.NewRows([]string{
+		"id", "trace_id", "event_type", "event_category", "actor_type", "actor_id", "entity_type",
+		"entity_id", "event_data", "severity", "message", "timestamp", "hlc", "created_at",
+	}).AddRow(
+		logID, nil, "matching_failed", models.EventCategoryError, nil, nil, nil,
+		nil, json.RawMessage(`{}`), models.EventSeverityError, "matching timeout", time.Now(), nil, time.Now(),
+	)
+
+	mock.ExpectQuery(`SELECT (.+) FROM event_logs WHERE \(severity IN \(\$1, \$2\) AND LOWER\(message\) LIKE \$3 ESCAPE '\\'\) ORDER BY timestamp DESC LIMIT \$4 OFFSET \$5`).
+		WithArgs("error", "fatal", "%timeout%", 10, 0).
+		WillReturnRows(rows)
+
+	logs, err := repo.FilterEventLogs(context.Background(), f, 10, 0)
+
+	assert.NoError(t, err)
+	assert.Len(t, logs, 1)
+	assert.Equal(t, logID, logs[0].ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
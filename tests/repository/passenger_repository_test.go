@@ -36,10 +36,11 @@ func TestPassengerRepository_GetByID_Success(t *testing.T) {
 
 	// Setup mock expectations
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "rating", "total_trips", "created_at", "updated_at",
+		"id", "user_id", "rating", "total_trips", "corporate_account_id",
+		"quiet_ride", "pet_friendly", "accessible_vehicle", "min_driver_rating", "created_at", "updated_at",
 	}).AddRow(
-		expectedPassenger.ID, expectedPassenger.UserID, expectedPassenger.Rating, expectedPassenger.TotalTrips,
-		expectedPassenger.CreatedAt, expectedPassenger.UpdatedAt,
+		expectedPassenger.ID, expectedPassenger.UserID, expectedPassenger.Rating, expectedPassenger.TotalTrips, nil,
+		false, false, false, nil, expectedPassenger.CreatedAt, expectedPassenger.UpdatedAt,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM passengers WHERE id = \$1`).
@@ -111,10 +112,11 @@ func TestPassengerRepository_GetByUserID_Success(t *testing.T) {
 
 	// Setup mock expectations
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "rating", "total_trips", "created_at", "updated_at",
+		"id", "user_id", "rating", "total_trips", "corporate_account_id",
+		"quiet_ride", "pet_friendly", "accessible_vehicle", "min_driver_rating", "created_at", "updated_at",
 	}).AddRow(
-		expectedPassenger.ID, expectedPassenger.UserID, expectedPassenger.Rating, expectedPassenger.TotalTrips,
-		expectedPassenger.CreatedAt, expectedPassenger.UpdatedAt,
+		expectedPassenger.ID, expectedPassenger.UserID, expectedPassenger.Rating, expectedPassenger.TotalTrips, nil,
+		false, false, false, nil, expectedPassenger.CreatedAt, expectedPassenger.UpdatedAt,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM passengers WHERE user_id = \$1`).
@@ -153,8 +155,8 @@ func TestPassengerRepository_Create_Success(t *testing.T) {
 	// Setup mock expectations
 	mock.ExpectExec(`INSERT INTO passengers`).
 		WithArgs(
-			passenger.ID, passenger.UserID, passenger.Rating, passenger.TotalTrips,
-			passenger.CreatedAt, passenger.UpdatedAt,
+			passenger.ID, passenger.UserID, passenger.Rating, passenger.TotalTrips, nil,
+			false, false, false, nil, passenger.CreatedAt, passenger.UpdatedAt,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -187,8 +189,8 @@ func TestPassengerRepository_Create_UserIDExists(t *testing.T) {
 	// Setup mock expectations - unique violation on user_id
 	mock.ExpectExec(`INSERT INTO passengers`).
 		WithArgs(
-			passenger.ID, passenger.UserID, passenger.Rating, passenger.TotalTrips,
-			passenger.CreatedAt, passenger.UpdatedAt,
+			passenger.ID, passenger.UserID, passenger.Rating, passenger.TotalTrips, nil,
+			false, false, false, nil, passenger.CreatedAt, passenger.UpdatedAt,
 		).
 		WillReturnError(&pq.Error{
 			Code:       "23505", // unique_violation
@@ -231,7 +233,7 @@ func TestPassengerRepository_Update_Success(t *testing.T) {
 	mock.ExpectExec(`UPDATE passengers SET`).
 		WithArgs(
 			passenger.ID,
-			passenger.Rating, passenger.TotalTrips, passenger.UpdatedAt,
+			passenger.Rating, passenger.TotalTrips, false, false, false, nil, passenger.UpdatedAt,
 		).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
@@ -265,7 +267,7 @@ func TestPassengerRepository_Update_NotFound(t *testing.T) {
 	mock.ExpectExec(`UPDATE passengers SET`).
 		WithArgs(
 			passenger.ID,
-			passenger.Rating, passenger.TotalTrips, passenger.UpdatedAt,
+			passenger.Rating, passenger.TotalTrips, false, false, false, nil, passenger.UpdatedAt,
 		).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
@@ -312,13 +314,14 @@ func TestPassengerRepository_List_Success(t *testing.T) {
 
 	// Setup mock expectations
 	rows := sqlmock.NewRows([]string{
-		"id", "user_id", "rating", "total_trips", "created_at", "updated_at",
+		"id", "user_id", "rating", "total_trips", "corporate_account_id",
+		"quiet_ride", "pet_friendly", "accessible_vehicle", "min_driver_rating", "created_at", "updated_at",
 	}).AddRow(
-		passenger1.ID, passenger1.UserID, passenger1.Rating, passenger1.TotalTrips,
-		passenger1.CreatedAt, passenger1.UpdatedAt,
+		passenger1.ID, passenger1.UserID, passenger1.Rating, passenger1.TotalTrips, nil,
+		false, false, false, nil, passenger1.CreatedAt, passenger1.UpdatedAt,
 	).AddRow(
-		passenger2.ID, passenger2.UserID, passenger2.Rating, passenger2.TotalTrips,
-		passenger2.CreatedAt, passenger2.UpdatedAt,
+		passenger2.ID, passenger2.UserID, passenger2.Rating, passenger2.TotalTrips, nil,
+		false, false, false, nil, passenger2.CreatedAt, passenger2.UpdatedAt,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM passengers ORDER BY created_at DESC LIMIT \$1 OFFSET \$2`).
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository/factory"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/repository/traced"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTracedRepositories(t *testing.T) (*factory.Repositories, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	recorder := tracetest.NewSpanRecorder()
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	reader := sdkmetric.NewManualReader()
+	meterProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	inst, err := traced.New(tracerProvider.Tracer("test"), meterProvider.Meter("test"), "memory")
+	require.NoError(t, err)
+	return traced.Wrap(factory.NewMemoryRepositories(memory.NewStore()), inst), recorder, reader
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestTracedRepositories_Spans(t *testing.T) {
+	ctx := context.Background()
+	repos, recorder, _ := newTracedRepositories(t)
+
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		require.NoError(t, repos.Users.Create(ctx, newTestUser(email, email, time.Now())))
+	}
+	users, err := repos.Users.List(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+
+	_, err = repos.Users.GetByID(ctx, uuid.New().String())
+	var notFoundErr *models.NotFoundError
+	require.True(t, errors.As(err, &notFoundErr), "errors pass through unchanged")
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+
+	create := spanAttributes(spans[0])
+	assert.Equal(t, "UserRepository.Create", spans[0].Name())
+	assert.Equal(t, "INSERT", create["db.operation"].AsString())
+	assert.Equal(t, "memory", create["db.system"].AsString())
+	_, hasRows := create["db.rows_affected"]
+	assert.False(t, hasRows, "calls returning only an error report no row count")
+
+	list := spanAttributes(spans[2])
+	assert.Equal(t, "UserRepository.List", spans[2].Name())
+	assert.Equal(t, "SELECT", list["db.operation"].AsString())
+	assert.Equal(t, int64(2), list["db.rows_affected"].AsInt64())
+	assert.GreaterOrEqual(t, list["db.duration"].AsFloat64(), 0.0)
+
+	assert.Equal(t, "UserRepository.GetByID", spans[3].Name())
+	assert.Equal(t, codes.Error, spans[3].Status().Code)
+	require.Len(t, spans[3].Events(), 1, "the error is recorded on the span")
+}
+
+func TestTracedRepositories_DurationHistogram(t *testing.T) {
+	ctx := context.Background()
+	repos, _, reader := newTracedRepositories(t)
+
+	_, err := repos.Trips.GetActiveTrips(ctx)
+	require.NoError(t, err)
+	_, err = repos.Trips.GetActiveTrips(ctx)
+	require.NoError(t, err)
+	_, err = repos.Drivers.GetByID(ctx, uuid.New().String())
+	require.Error(t, err)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	require.Len(t, rm.ScopeMetrics, 1)
+	require.Len(t, rm.ScopeMetrics[0].Metrics, 1)
+
+	metric := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, "repository_operation_duration_seconds", metric.Name)
+	histogram, ok := metric.Data.(metricdata.Histogram[float64])
+	require.True(t, ok)
+
+	counts := make(map[string]uint64)
+	for _, point := range histogram.DataPoints {
+		repository, _ := point.Attributes.Value("repository")
+		method, _ := point.Attributes.Value("method")
+		status, _ := point.Attributes.Value("status")
+		counts[repository.AsString()+"."+method.AsString()+" "+status.AsString()] = point.Count
+	}
+	assert.Equal(t, map[string]uint64{
+		"TripRepository.GetActiveTrips success": 2,
+		"DriverRepository.GetByID error":        1,
+	}, counts)
+}
+
+func TestTracedOperation(t *testing.T) {
+	for method, operation := range map[string]string{
+		"GetByID":                   "SELECT",
+		"ListByFleet":               "SELECT",
+		"CountUnread":               "SELECT",
+		"Create":                    "INSERT",
+		"ChargeTrip":                "INSERT",
+		"UpdateStatus":              "UPDATE",
+		"ReplaceHourlyTrips":        "UPDATE",
+		"Reserve":                   "UPDATE",
+		"Delete":                    "DELETE",
+		"DeleteForTripsEndedBefore": "DELETE",
+		"ClearDestination":          "DELETE",
+	} {
+		assert.Equal(t, operation, traced.Operation(method), method)
+	}
+}
+
+// slowQueryRecorder keeps every call reported as slow
+type slowQueryRecorder struct {
+	threshold time.Duration
+	queries   []*models.SlowQuery
+}
+
+func (r *slowQueryRecorder) Threshold() time.Duration { return r.threshold }
+
+func (r *slowQueryRecorder) Record(ctx context.Context, query *models.SlowQuery) {
+	r.queries = append(r.queries, query)
+}
+
+func TestTracedRepositories_SlowQueries(t *testing.T) {
+	ctx := context.Background()
+	inst, err := traced.New(nil, nil, "memory")
+	require.NoError(t, err)
+	recorder := &slowQueryRecorder{}
+	inst.SetSlowQueryRecorder(recorder)
+	repos := traced.Wrap(factory.NewMemoryRepositories(memory.NewStore()), inst)
+
+	user := newTestUser("slow@example.com", "+1555000111", time.Now())
+	require.NoError(t, repos.Users.Create(ctx, user))
+	_, err = repos.Sessions.GetByAccessTokenHash(ctx, "a-secret-hash")
+	require.Error(t, err)
+	_, err = repos.Trips.GetByPassengerID(ctx, "passenger-1", 10, 0)
+	require.NoError(t, err)
+
+	require.Len(t, recorder.queries, 3)
+
+	create := recorder.queries[0]
+	assert.Equal(t, "UserRepository", create.Repository)
+	assert.Equal(t, "INSERT", create.Operation)
+	assert.Equal(t, "models.User{ID: "+user.ID.String()+"}", create.Params["user"], "entities are reduced to their ID")
+
+	lookup := recorder.queries[1]
+	assert.Equal(t, "[REDACTED]", lookup.Params["hash"])
+	assert.NotEmpty(t, lookup.Error)
+
+	list := recorder.queries[2]
+	assert.Equal(t, map[string]string{"passengerID": "passenger-1", "limit": "10", "offset": "0"}, list.Params)
+	assert.GreaterOrEqual(t, list.DurationMs, 0.0)
+
+	// Calls faster than the threshold are not reported
+	recorder.threshold = time.Hour
+	_, err = repos.Trips.GetActiveTrips(ctx)
+	require.NoError(t, err)
+	assert.Len(t, recorder.queries, 3)
+}
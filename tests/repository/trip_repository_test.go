@@ -50,6 +50,7 @@ func TestTripRepository_Create_Success(t *testing.T) {
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -75,13 +76,13 @@ func TestTripRepository_GetByID_Success(t *testing.T) {
 		"pickup_latitude", "pickup_longitude", "destination_latitude", "destination_longitude",
 		"pickup_address", "destination_address", "fare_amount", "distance_km",
 		"duration_minutes", "requested_at", "matched_at", "accepted_at", "pickup_at", "completed_at", "cancelled_at",
-		"created_at", "updated_at",
+		"cancellation_reason", "cancellation_note", "cancellation_mode", "created_at", "updated_at",
 	}).AddRow(
 		tripID, passengerID, driverID, models.TripStatusRequested,
 		40.7128, -74.0060, 40.7589, -73.9851,
 		"123 Main St", "456 Broadway", nil, nil,
 		nil, now, nil, nil, nil, nil, nil,
-		now, now,
+		nil, nil, nil, now, now,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM trips WHERE id = \$1`).
@@ -150,6 +151,7 @@ func TestTripRepository_Update_Success(t *testing.T) {
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
@@ -175,19 +177,19 @@ func TestTripRepository_GetByPassengerID_Success(t *testing.T) {
 		"pickup_latitude", "pickup_longitude", "destination_latitude", "destination_longitude",
 		"pickup_address", "destination_address", "fare_amount", "distance_km",
 		"duration_minutes", "requested_at", "matched_at", "accepted_at", "pickup_at", "completed_at", "cancelled_at",
-		"created_at", "updated_at",
+		"cancellation_reason", "cancellation_note", "cancellation_mode", "created_at", "updated_at",
 	}).AddRow(
 		tripID1, passengerID, nil, models.TripStatusRequested,
 		40.7128, -74.0060, 40.7589, -73.9851,
 		"123 Main St", "456 Broadway", nil, nil,
 		nil, now, nil, nil, nil, nil, nil,
-		now, now,
+		nil, nil, nil, now, now,
 	).AddRow(
 		tripID2, passengerID, nil, models.TripStatusCompleted,
 		40.7500, -74.0000, 40.7600, -73.9800,
 		"789 Oak St", "321 Pine St", nil, nil,
 		nil, now, nil, nil, nil, &now, nil,
-		now, now,
+		nil, nil, nil, now, now,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM trips WHERE passenger_id = \$1`).
@@ -219,13 +221,13 @@ func TestTripRepository_GetActiveTrips_Success(t *testing.T) {
 		"pickup_latitude", "pickup_longitude", "destination_latitude", "destination_longitude",
 		"pickup_address", "destination_address", "fare_amount", "distance_km",
 		"duration_minutes", "requested_at", "matched_at", "accepted_at", "pickup_at", "completed_at", "cancelled_at",
-		"created_at", "updated_at",
+		"cancellation_reason", "cancellation_note", "cancellation_mode", "created_at", "updated_at",
 	}).AddRow(
 		tripID, passengerID, driverID, models.TripStatusInProgress,
 		40.7128, -74.0060, 40.7589, -73.9851,
 		"123 Main St", "456 Broadway", nil, nil,
 		nil, now, &now, &now, &now, nil, nil,
-		now, now,
+		nil, nil, nil, now, now,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM trips WHERE status IN`).
@@ -255,13 +257,13 @@ func TestTripRepository_GetTripsByStatus_Success(t *testing.T) {
 		"pickup_latitude", "pickup_longitude", "destination_latitude", "destination_longitude",
 		"pickup_address", "destination_address", "fare_amount", "distance_km",
 		"duration_minutes", "requested_at", "matched_at", "accepted_at", "pickup_at", "completed_at", "cancelled_at",
-		"created_at", "updated_at",
+		"cancellation_reason", "cancellation_note", "cancellation_mode", "created_at", "updated_at",
 	}).AddRow(
 		tripID, passengerID, nil, models.TripStatusRequested,
 		40.7128, -74.0060, 40.7589, -73.9851,
 		"123 Main St", "456 Broadway", nil, nil,
 		nil, now, nil, nil, nil, nil, nil,
-		now, now,
+		nil, nil, nil, now, now,
 	)
 
 	mock.ExpectQuery(`SELECT (.+) FROM trips WHERE status = \$1`).
@@ -293,3 +295,93 @@ func TestTripRepository_Delete_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestTripRepository_GetGridCounts_Success(t *testing.T) {
+	db, mock := utils.SetupMockDB(t)
+	defer db.Close()
+
+	repo := postgres.NewTripRepository(db)
+
+	end := time.Now()
+	start := end.Add(-24 * time.Hour)
+
+	rows := sqlmock.NewRows([]string{"grid_row", "grid_col", "pickups", "dropoffs"}).
+		AddRow(1906, 6526, 2, 0).
+		AddRow(1904, 6527, 0, 2)
+
+	mock.ExpectQuery(`SELECT grid_row, grid_col, SUM\(pickup\) AS pickups, SUM\(dropoff\) AS dropoffs`).
+		WithArgs(0.0439453125, 0.0439453125, start, end).
+		WillReturnRows(rows)
+
+	counts, err := repo.GetGridCounts(context.Background(), start, end, 0.0439453125, 0.0439453125)
+
+	assert.NoError(t, err)
+	assert.Len(t, counts, 2)
+	assert.Equal(t, 1906, counts[0].Row)
+	assert.Equal(t, int64(2), counts[0].Pickups)
+	assert.Equal(t, int64(2), counts[1].Dropoffs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTripRepository_GetDailyCounts_Success(t *testing.T) {
+	db, mock := utils.SetupMockDB(t)
+	defer db.Close()
+
+	repo := postgres.NewTripRepository(db)
+
+	// Local midnights in New York around the end of daylight saving time
+	boundaries := []time.Time{
+		time.Date(2024, 11, 3, 4, 0, 0, 0, time.UTC),
+		time.Date(2024, 11, 4, 5, 0, 0, 0, time.UTC),
+		time.Date(2024, 11, 5, 5, 0, 0, 0, time.UTC),
+	}
+
+	rows := sqlmock.NewRows([]string{"day", "requested", "completed", "cancelled", "revenue"}).
+		AddRow(0, 2, 2, 0, 22.5).
+		AddRow(1, 1, 0, 1, 0)
+
+	mock.ExpectQuery(`CASE WHEN requested_at < \$2 THEN 0 ELSE 1 END AS day`).
+		WithArgs(boundaries[0], boundaries[1], boundaries[2]).
+		WillReturnRows(rows)
+
+	counts, err := repo.GetDailyCounts(context.Background(), boundaries)
+
+	assert.NoError(t, err)
+	assert.Len(t, counts, 2)
+	assert.Equal(t, int64(2), counts[0].Completed)
+	assert.Equal(t, 22.5, counts[0].Revenue)
+	assert.Equal(t, 1, counts[1].Day)
+	assert.Equal(t, int64(1), counts[1].Cancelled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTripRepository_GetDailyCancellations_Success(t *testing.T) {
+	db, mock := utils.SetupMockDB(t)
+	defer db.Close()
+
+	repo := postgres.NewTripRepository(db)
+
+	boundaries := []time.Time{
+		time.Date(2024, 11, 3, 4, 0, 0, 0, time.UTC),
+		time.Date(2024, 11, 4, 5, 0, 0, 0, time.UTC),
+		time.Date(2024, 11, 5, 5, 0, 0, 0, time.UTC),
+	}
+
+	rows := sqlmock.NewRows([]string{"day", "reason", "count"}).
+		AddRow(0, "", 1).
+		AddRow(1, "wait_too_long", 3)
+
+	mock.ExpectQuery(`CASE WHEN cancelled_at < \$2 THEN 0 ELSE 1 END AS day, COALESCE\(cancellation_reason, ''\) AS reason`).
+		WithArgs(boundaries[0], boundaries[1], boundaries[2]).
+		WillReturnRows(rows)
+
+	counts, err := repo.GetDailyCancellations(context.Background(), boundaries)
+
+	assert.NoError(t, err)
+	assert.Len(t, counts, 2)
+	assert.Empty(t, counts[0].Reason)
+	assert.Equal(t, 1, counts[1].Day)
+	assert.Equal(t, "wait_too_long", counts[1].Reason)
+	assert.Equal(t, int64(3), counts[1].Count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
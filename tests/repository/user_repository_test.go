@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
 	"actor-model-observability/internal/repository/postgres"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -46,10 +47,10 @@ func TestUserRepository_GetByID_Success(t *testing.T) {
 	}
 
 	// Setup mock expectations
-	rows := sqlmock.NewRows([]string{"id", "email", "phone", "name", "user_type", "created_at", "updated_at"}).
-		AddRow(expectedUser.ID, expectedUser.Email, expectedUser.Phone, expectedUser.Name, expectedUser.UserType, expectedUser.CreatedAt, expectedUser.UpdatedAt)
+	rows := sqlmock.NewRows([]string{"id", "email", "phone", "name", "user_type", "avatar_key", "created_at", "updated_at"}).
+		AddRow(expectedUser.ID, expectedUser.Email, expectedUser.Phone, expectedUser.Name, expectedUser.UserType, "avatars/a.png", expectedUser.CreatedAt, expectedUser.UpdatedAt)
 
-	mock.ExpectQuery(`SELECT id, email, phone, name, user_type, created_at, updated_at\s+FROM users\s+WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, email, phone, name, user_type, avatar_key, created_at, updated_at\s+FROM users\s+WHERE id = \$1`).
 		WithArgs(userID.String()).
 		WillReturnRows(rows)
 
@@ -63,6 +64,8 @@ func TestUserRepository_GetByID_Success(t *testing.T) {
 	assert.Equal(t, expectedUser.Email, result.Email)
 	assert.Equal(t, expectedUser.Name, result.Name)
 	assert.Equal(t, expectedUser.UserType, result.UserType)
+	require.NotNil(t, result.AvatarKey)
+	assert.Equal(t, "avatars/a.png", *result.AvatarKey)
 
 	// Verify all expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
@@ -77,7 +80,7 @@ func TestUserRepository_GetByID_NotFound(t *testing.T) {
 	userID := uuid.New()
 
 	// Setup mock expectations - no rows returned
-	mock.ExpectQuery(`SELECT id, email, phone, name, user_type, created_at, updated_at FROM users WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, email, phone, name, user_type, avatar_key, created_at, updated_at FROM users WHERE id = \$1`).
 		WithArgs(userID.String()).
 		WillReturnError(sql.ErrNoRows)
 
@@ -107,7 +110,7 @@ func TestUserRepository_GetByID_DatabaseError(t *testing.T) {
 	userID := uuid.New()
 
 	// Setup mock expectations - database error
-	mock.ExpectQuery(`SELECT id, email, phone, name, user_type, created_at, updated_at FROM users WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, email, phone, name, user_type, avatar_key, created_at, updated_at FROM users WHERE id = \$1`).
 		WithArgs(userID.String()).
 		WillReturnError(sql.ErrConnDone)
 
@@ -142,7 +145,7 @@ func TestUserRepository_Create_Success(t *testing.T) {
 
 	// Setup mock expectations
 	mock.ExpectExec(`INSERT INTO users`).
-		WithArgs(user.ID, user.Email, user.Phone, user.Name, user.UserType, user.CreatedAt, user.UpdatedAt).
+		WithArgs(user.ID, user.Email, user.Phone, user.Name, user.UserType, user.AvatarKey, user.CreatedAt, user.UpdatedAt).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
 	// Execute
@@ -174,7 +177,7 @@ func TestUserRepository_Create_EmailExists(t *testing.T) {
 
 	// Setup mock expectations - unique violation on email
 	mock.ExpectExec(`INSERT INTO users`).
-		WithArgs(user.ID, user.Email, user.Phone, user.Name, user.UserType, user.CreatedAt, user.UpdatedAt).
+		WithArgs(user.ID, user.Email, user.Phone, user.Name, user.UserType, user.AvatarKey, user.CreatedAt, user.UpdatedAt).
 		WillReturnError(&pq.Error{
 			Code:       "23505", // unique_violation
 			Constraint: "users_email_key",
@@ -215,7 +218,7 @@ func TestUserRepository_Update_Success(t *testing.T) {
 
 	// Setup mock expectations
 	mock.ExpectExec(`UPDATE users SET`).
-		WithArgs(user.Email, user.Phone, user.Name, user.UserType, user.UpdatedAt, user.ID).
+		WithArgs(user.Email, user.Phone, user.Name, user.UserType, user.AvatarKey, user.UpdatedAt, user.ID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
 
 	// Execute
@@ -247,7 +250,7 @@ func TestUserRepository_Update_NotFound(t *testing.T) {
 
 	// Setup mock expectations - no rows affected
 	mock.ExpectExec(`UPDATE users SET`).
-		WithArgs(user.Email, user.Phone, user.Name, user.UserType, user.UpdatedAt, user.ID).
+		WithArgs(user.Email, user.Phone, user.Name, user.UserType, user.AvatarKey, user.UpdatedAt, user.ID).
 		WillReturnResult(sqlmock.NewResult(0, 0))
 
 	// Execute
@@ -293,11 +296,11 @@ func TestUserRepository_List_Success(t *testing.T) {
 	}
 
 	// Setup mock expectations
-	rows := sqlmock.NewRows([]string{"id", "email", "phone", "name", "user_type", "created_at", "updated_at"}).
-		AddRow(user1.ID, user1.Email, user1.Phone, user1.Name, user1.UserType, user1.CreatedAt, user1.UpdatedAt).
-		AddRow(user2.ID, user2.Email, user2.Phone, user2.Name, user2.UserType, user2.CreatedAt, user2.UpdatedAt)
+	rows := sqlmock.NewRows([]string{"id", "email", "phone", "name", "user_type", "avatar_key", "created_at", "updated_at"}).
+		AddRow(user1.ID, user1.Email, user1.Phone, user1.Name, user1.UserType, nil, user1.CreatedAt, user1.UpdatedAt).
+		AddRow(user2.ID, user2.Email, user2.Phone, user2.Name, user2.UserType, nil, user2.CreatedAt, user2.UpdatedAt)
 
-	mock.ExpectQuery(`SELECT id, email, phone, name, user_type, created_at, updated_at FROM users ORDER BY created_at DESC LIMIT \$1 OFFSET \$2`).
+	mock.ExpectQuery(`SELECT id, email, phone, name, user_type, avatar_key, created_at, updated_at FROM users ORDER BY created_at DESC LIMIT \$1 OFFSET \$2`).
 		WithArgs(10, 0).
 		WillReturnRows(rows)
 
@@ -313,3 +316,29 @@ func TestUserRepository_List_Success(t *testing.T) {
 	// Verify all expectations were met
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestUserRepository_List_SelectedColumns(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	repo := postgres.NewUserRepository(db)
+
+	userID := uuid.New()
+	rows := sqlmock.NewRows([]string{"id", "name"}).AddRow(userID, "User 1")
+
+	// Unknown columns are dropped and the rest are selected in table order
+	mock.ExpectQuery(`SELECT id, name FROM users ORDER BY created_at DESC LIMIT \$1 OFFSET \$2`).
+		WithArgs(10, 0).
+		WillReturnRows(rows)
+
+	ctx := repository.WithColumns(context.Background(), []string{"name", "id", "password"})
+	result, err := repo.List(ctx, 10, 0)
+
+	assert.NoError(t, err)
+	require.Len(t, result, 1)
+	assert.Equal(t, userID, result[0].ID)
+	assert.Equal(t, "User 1", result[0].Name)
+	assert.Empty(t, result[0].Email)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,152 @@
+package retention
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/retention"
+	"actor-model-observability/tests/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	day  = 24 * time.Hour
+	week = 7 * day
+)
+
+func TestPeriodStart(t *testing.T) {
+	tests := []struct {
+		name     string
+		t        time.Time
+		interval time.Duration
+		want     time.Time
+	}{
+		{name: "day", t: time.Date(2024, 1, 31, 15, 4, 5, 6, time.UTC), interval: day, want: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "day at midnight", t: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), interval: day, want: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "week from a wednesday", t: time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC), interval: week, want: time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "week from a monday", t: time.Date(2024, 1, 29, 8, 0, 0, 0, time.UTC), interval: week, want: time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "week from a sunday", t: time.Date(2024, 2, 4, 23, 59, 0, 0, time.UTC), interval: week, want: time.Date(2024, 1, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "week across a year", t: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), interval: week, want: time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, retention.PeriodStart(tt.t, tt.interval))
+		})
+	}
+}
+
+func TestPartitionStart(t *testing.T) {
+	tests := []struct {
+		name      string
+		table     string
+		partition string
+		want      time.Time
+		wantOK    bool
+	}{
+		{name: "partition", table: "actor_messages", partition: "actor_messages_p20240131", want: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), wantOK: true},
+		{name: "default partition", table: "actor_messages", partition: "actor_messages_default"},
+		{name: "other table", table: "event_logs", partition: "actor_messages_p20240131"},
+		{name: "invalid date", table: "event_logs", partition: "event_logs_p20241345"},
+		{name: "trailing text", table: "event_logs", partition: "event_logs_p20240131_old"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := retention.PartitionStart(tt.table, tt.partition)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPartitionSpec_Expired(t *testing.T) {
+	now := time.Date(2024, 2, 10, 12, 0, 0, 0, time.UTC)
+	daily := retention.PartitionSpec{Table: "event_logs", Interval: day}
+	weekly := retention.PartitionSpec{Table: "system_metrics", Interval: week}
+	ownRetention := retention.PartitionSpec{Table: "driver_location_pings", Interval: day, Retention: 30 * day}
+
+	tests := []struct {
+		name      string
+		spec      retention.PartitionSpec
+		partition string
+		want      bool
+	}{
+		// The cutoff is 2024-02-03 12:00 with the default retention of a week
+		{name: "ends before the cutoff", spec: daily, partition: "event_logs_p20240202", want: true},
+		{name: "spans the cutoff", spec: daily, partition: "event_logs_p20240203"},
+		{name: "after the cutoff", spec: daily, partition: "event_logs_p20240209"},
+		{name: "week ending before the cutoff", spec: weekly, partition: "system_metrics_p20240122", want: true},
+		{name: "week spanning the cutoff", spec: weekly, partition: "system_metrics_p20240129"},
+		{name: "default partition", spec: daily, partition: "event_logs_default"},
+		{name: "own retention keeps it", spec: ownRetention, partition: "driver_location_pings_p20240202"},
+		{name: "own retention expires it", spec: ownRetention, partition: "driver_location_pings_p20240109", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.spec.Expired(tt.partition, week, now))
+		})
+	}
+}
+
+func TestPartitionManager_RetentionByTable(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	defer mockDB.Close()
+
+	logger := utils.NewTestLogger(t)
+
+	cfg := config.Development()
+	cfg.Metrics.RetentionPeriod = 7 * day
+	cfg.Retention.PartitionPremakeDays = 0
+	cfg.Retention.ActorMessagesPeriod = 2 * day
+	cfg.Retention.EventLogsPeriod = 30 * day
+	cfg.LocationTrail.RetentionPeriod = 90 * day
+	manager := retention.NewPartitionManager(sqlx.NewDb(mockDB, "postgres"), cfg, logger)
+
+	// Partitions named relative to today, whatever day the test runs
+	today := retention.PeriodStart(time.Now().UTC(), day)
+	name := func(table string, daysAgo int) string {
+		return table + "_p" + today.AddDate(0, 0, -daysAgo).Format("20060102")
+	}
+	thisWeek := retention.PeriodStart(time.Now().UTC(), week)
+	weekName := func(weeksAgo int) string {
+		return "system_metrics_p" + thisWeek.AddDate(0, 0, -7*weeksAgo).Format("20060102")
+	}
+
+	tables := []struct {
+		table      string
+		partitions []string
+		dropped    []string
+	}{
+		{table: "actor_messages", partitions: []string{name("actor_messages", 5), name("actor_messages", 1), "actor_messages_default"}, dropped: []string{name("actor_messages", 5)}},
+		{table: "event_logs", partitions: []string{name("event_logs", 40), name("event_logs", 10)}, dropped: []string{name("event_logs", 40)}},
+		{table: "system_metrics", partitions: []string{weekName(3), weekName(0)}, dropped: []string{weekName(3)}},
+		{table: "driver_location_pings", partitions: []string{name("driver_location_pings", 60)}},
+	}
+	for _, tt := range tables {
+		mock.ExpectExec(regexp.QuoteMeta("SELECT create_time_partition($1, $2, $3)")).
+			WithArgs(tt.table, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		rows := sqlmock.NewRows([]string{"relname"})
+		for _, partition := range tt.partitions {
+			rows.AddRow(partition)
+		}
+		mock.ExpectQuery("FROM pg_inherits").WithArgs(tt.table).WillReturnRows(rows)
+		for _, partition := range tt.dropped {
+			mock.ExpectExec(regexp.QuoteMeta(`DROP TABLE IF EXISTS "` + partition + `"`)).
+				WillReturnResult(sqlmock.NewResult(0, 0))
+		}
+	}
+
+	require.NoError(t, manager.RunMaintenance(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
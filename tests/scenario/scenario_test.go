@@ -0,0 +1,88 @@
+package scenario
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/scenario"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoad_ExampleScenarios(t *testing.T) {
+	for _, path := range []string{"../../scenarios/steady.yaml", "../../scenarios/rush_hour.yaml"} {
+		s, err := scenario.Load(path)
+		require.NoError(t, err, path)
+		assert.NotEmpty(t, s.Name, path)
+	}
+}
+
+func TestParse_KeepsDefaultsForMissingFields(t *testing.T) {
+	s, err := scenario.Parse([]byte(`
+name: small
+duration: 1m
+drivers:
+  count: 3
+`))
+	require.NoError(t, err)
+
+	defaults := scenario.Default()
+	assert.Equal(t, "small", s.Name)
+	assert.Equal(t, time.Minute, s.Duration)
+	assert.Equal(t, 3, s.Drivers.Count)
+	assert.Equal(t, defaults.Drivers.SpeedKmh, s.Drivers.SpeedKmh)
+	assert.Equal(t, defaults.Passengers.MatchTimeout, s.Passengers.MatchTimeout)
+	assert.Equal(t, defaults.Area, s.Area)
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+	}{
+		{"negative drivers", "drivers: {count: -1}"},
+		{"cancellation probability above one", "passengers: {cancellation_probability: 1.5}"},
+		{"hotspot without weight", "hotspots: [{name: a, latitude: 1, longitude: 1, radius_km: 1}]"},
+		{"phase without duration", "phases: [{name: a, arrival_rate: 10}]"},
+		{"invalid area", "area: {latitude: 91, longitude: 0, radius_km: 1}"},
+		{"bad duration", "duration: soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := scenario.Parse([]byte(tt.yaml))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestScenario_ArrivalRate(t *testing.T) {
+	s := scenario.Default()
+	assert.Equal(t, s.Passengers.ArrivalRate, s.ArrivalRate(time.Hour))
+
+	s.Phases = []scenario.Phase{
+		{Name: "warm-up", Duration: 5 * time.Minute, ArrivalRate: 10},
+		{Name: "peak", Duration: 10 * time.Minute, ArrivalRate: 100},
+	}
+	assert.Equal(t, 10.0, s.ArrivalRate(0))
+	assert.Equal(t, 100.0, s.ArrivalRate(5*time.Minute))
+	assert.Equal(t, 100.0, s.ArrivalRate(14*time.Minute))
+	// The last phase continues until the end of the run
+	assert.Equal(t, 100.0, s.ArrivalRate(time.Hour))
+}
+
+func TestScenario_RandomLocation_UsesHotspots(t *testing.T) {
+	s := scenario.Default()
+	hotspot := scenario.Hotspot{Name: "station", Latitude: 37.7764, Longitude: -122.3942, RadiusKm: 0.5, Weight: 1}
+	s.Hotspots = []scenario.Hotspot{hotspot}
+
+	center := models.Location{Latitude: hotspot.Latitude, Longitude: hotspot.Longitude}
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		loc := s.RandomLocation(rng)
+		assert.LessOrEqual(t, center.DistanceTo(loc), hotspot.RadiusKm+0.01)
+	}
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/middleware"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fleetAuthenticator authenticates every API key as its fleet
+type fleetAuthenticator struct {
+	fleet *models.Fleet
+}
+
+func (a *fleetAuthenticator) Authenticate(ctx context.Context, apiKey string) (*models.Fleet, error) {
+	return a.fleet, nil
+}
+
+// usageFixture is an API usage service over an in-memory store, recording the requests of a
+// router whose fleet routes answer with the status in the path
+type usageFixture struct {
+	svc     *service.APIUsageService
+	usage   repository.APIUsageRepository
+	metrics *metricsRecorder
+	router  *gin.Engine
+	fleet   *models.Fleet
+	logger  *logging.Logger
+}
+
+func newUsageFixture(t *testing.T, quota *int64) *usageFixture {
+	logger := utils.NewTestLogger(t)
+
+	f := &usageFixture{
+		usage:   memory.NewAPIUsageRepository(memory.NewStore()),
+		metrics: &metricsRecorder{},
+		fleet:   &models.Fleet{ID: uuid.New(), Name: "Jakarta Cabs", APIKeyHash: "0123456789abcdef0123456789abcdef", DailyRequestQuota: quota},
+		logger:  logger,
+	}
+	f.svc = f.newInstance()
+
+	gin.SetMode(gin.TestMode)
+	f.router = gin.New()
+	f.router.GET("/fleet/:status",
+		middleware.FleetAuthMiddleware(&fleetAuthenticator{fleet: f.fleet}),
+		middleware.APIUsageMiddleware(f.svc, middleware.FleetAPIKey),
+		func(c *gin.Context) {
+			status, _ := strconv.Atoi(c.Param("status"))
+			c.Status(status)
+		})
+	return f
+}
+
+// newInstance returns another instance's usage service sharing the fixture's store
+func (f *usageFixture) newInstance() *service.APIUsageService {
+	cfg := config.DefaultAPIUsageConfig()
+	cfg.FlushInterval = time.Hour
+	return service.NewAPIUsageService(f.usage, cfg, f.metrics, f.logger)
+}
+
+func (f *usageFixture) request(t *testing.T, status int) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/fleet/"+strconv.Itoa(status), nil)
+	req.Header.Set(middleware.FleetKeyHeader, "fleet-key")
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAPIUsageService_ReportsRequestsByKeyAndBucket(t *testing.T) {
+	f := newUsageFixture(t, nil)
+	ctx := context.Background()
+
+	for _, status := range []int{http.StatusOK, http.StatusOK, http.StatusNotFound, http.StatusInternalServerError} {
+		w := f.request(t, status)
+		assert.Equal(t, status, w.Code)
+		assert.Empty(t, w.Header().Get(middleware.QuotaLimitHeader), "keys without a quota get no quota headers")
+	}
+
+	now := time.Now()
+	filter := models.APIUsageFilter{From: now.Add(-time.Hour), To: now.Add(time.Minute)}
+	report, err := f.svc.Report(ctx, filter, models.APIUsageBucketHour)
+	require.NoError(t, err)
+	assert.Empty(t, report.Buckets, "usage is reported once flushed")
+
+	require.NoError(t, f.svc.Flush(ctx))
+	report, err = f.svc.Report(ctx, filter, models.APIUsageBucketDay)
+	require.NoError(t, err)
+	require.Len(t, report.Buckets, 1)
+
+	bucket := report.Buckets[0]
+	assert.Equal(t, now.UTC().Truncate(24*time.Hour), bucket.BucketStart)
+	assert.Equal(t, models.APIKeyID(f.fleet.APIKeyHash), bucket.KeyID)
+	assert.Len(t, bucket.KeyID, 12)
+	assert.Equal(t, f.fleet.ID, bucket.TenantID)
+	assert.Equal(t, int64(4), bucket.Requests)
+	assert.Equal(t, int64(1), bucket.ClientErrors)
+	assert.Equal(t, int64(1), bucket.ServerErrors)
+	assert.Equal(t, 0.5, bucket.ErrorRate)
+	assert.GreaterOrEqual(t, bucket.MaxLatencyMs, bucket.AvgLatencyMs)
+	assert.Equal(t, bucket.APIUsageStats, report.Total)
+
+	// Another tenant's usage is filtered out
+	other := uuid.New()
+	filter.TenantID = &other
+	report, err = f.svc.Report(ctx, filter, models.APIUsageBucketMinute)
+	require.NoError(t, err)
+	assert.Empty(t, report.Buckets)
+	assert.Zero(t, report.Total.Requests)
+}
+
+func TestAPIUsageService_EnforcesDailyQuota(t *testing.T) {
+	quota := int64(3)
+	f := newUsageFixture(t, &quota)
+	ctx := context.Background()
+
+	w := f.request(t, http.StatusOK)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "3", w.Header().Get(middleware.QuotaLimitHeader))
+	assert.Equal(t, "2", w.Header().Get(middleware.QuotaRemainingHeader))
+	reset, err := strconv.ParseInt(w.Header().Get(middleware.QuotaResetHeader), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, time.Now().UTC().Truncate(24*time.Hour).Add(24*time.Hour).Unix(), reset)
+
+	// Failed requests count against the quota too, and flushing counts them once
+	assert.Equal(t, http.StatusBadRequest, f.request(t, http.StatusBadRequest).Code)
+	require.NoError(t, f.svc.Flush(ctx))
+	assert.Equal(t, http.StatusOK, f.request(t, http.StatusOK).Code)
+
+	w = f.request(t, http.StatusOK)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "0", w.Header().Get(middleware.QuotaRemainingHeader))
+	assert.NotEmpty(t, w.Header().Get("Retry-After"))
+	assert.Equal(t, float64(1), f.metrics.metrics["api_quota_rejections_total:"+f.fleet.ID.String()])
+
+	// Another instance sees the requests once they are flushed, rejected ones aside
+	require.NoError(t, f.svc.Flush(ctx))
+	status := f.newInstance().CheckQuota(ctx, f.fleet.APIKey())
+	assert.Equal(t, int64(3), status.Used)
+	assert.True(t, status.Exceeded())
+
+	// Raising the fleet's quota lets the key make requests again
+	raised := int64(5)
+	f.fleet.DailyRequestQuota = &raised
+	w = f.request(t, http.StatusOK)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "1", w.Header().Get(middleware.QuotaRemainingHeader))
+
+	require.NoError(t, f.svc.Flush(ctx))
+	report, err := f.svc.Report(ctx, models.APIUsageFilter{KeyID: models.APIKeyID(f.fleet.APIKeyHash), From: time.Now().Add(-time.Hour), To: time.Now().Add(time.Minute)}, models.APIUsageBucketHour)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), report.Total.Requests)
+	assert.Equal(t, int64(1), report.Total.QuotaRejections)
+	assert.Equal(t, int64(2), report.Total.ClientErrors)
+}
+
+func TestAPIUsageService_Report_ValidatesWindow(t *testing.T) {
+	f := newUsageFixture(t, nil)
+	ctx := context.Background()
+	now := time.Now()
+	var validation *models.ValidationError
+
+	_, err := f.svc.Report(ctx, models.APIUsageFilter{From: now.Add(-time.Hour), To: now}, "week")
+	assert.ErrorAs(t, err, &validation)
+
+	_, err = f.svc.Report(ctx, models.APIUsageFilter{From: now, To: now.Add(-time.Hour)}, models.APIUsageBucketHour)
+	assert.ErrorAs(t, err, &validation)
+
+	// Two days of minutes is too many buckets, two days of hours is not
+	_, err = f.svc.Report(ctx, models.APIUsageFilter{From: now.Add(-48 * time.Hour), To: now}, models.APIUsageBucketMinute)
+	assert.ErrorAs(t, err, &validation)
+	_, err = f.svc.Report(ctx, models.APIUsageFilter{From: now.Add(-48 * time.Hour), To: now}, models.APIUsageBucketHour)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancellationPolicy_Quote(t *testing.T) {
+	policy, err := service.NewCancellationPolicy(config.DefaultCancellationPolicyConfig())
+	require.NoError(t, err)
+
+	now := time.Now()
+	driverID := uuid.New()
+	matched := func(ago time.Duration) *time.Time {
+		at := now.Add(-ago)
+		return &at
+	}
+
+	tests := []struct {
+		name        string
+		trip        models.Trip
+		reason      models.CancellationReason
+		rule        models.CancellationRule
+		cancellable bool
+		fee         float64
+	}{
+		{
+			name:        "no driver matched yet",
+			trip:        models.Trip{Status: models.TripStatusRequested},
+			rule:        models.CancellationRuleNoDriver,
+			cancellable: true,
+		},
+		{
+			name:        "within the grace period",
+			trip:        models.Trip{Status: models.TripStatusMatched, DriverID: &driverID, MatchedAt: matched(time.Minute)},
+			rule:        models.CancellationRuleGracePeriod,
+			cancellable: true,
+		},
+		{
+			name:        "after the grace period",
+			trip:        models.Trip{Status: models.TripStatusAccepted, DriverID: &driverID, MatchedAt: matched(5 * time.Minute)},
+			reason:      models.CancellationReasonChangedPlans,
+			rule:        models.CancellationRuleLate,
+			cancellable: true,
+			fee:         3,
+		},
+		{
+			name:        "after the driver arrived",
+			trip:        models.Trip{Status: models.TripStatusDriverArrived, DriverID: &driverID, MatchedAt: matched(time.Minute)},
+			rule:        models.CancellationRuleArrived,
+			cancellable: true,
+			fee:         5,
+		},
+		{
+			name:        "for a free reason",
+			trip:        models.Trip{Status: models.TripStatusDriverArrived, DriverID: &driverID, MatchedAt: matched(time.Hour)},
+			reason:      models.CancellationReasonSafetyConcern,
+			rule:        models.CancellationRuleFreeReason,
+			cancellable: true,
+		},
+		{
+			name: "already completed",
+			trip: models.Trip{Status: models.TripStatusCompleted, DriverID: &driverID},
+			rule: models.CancellationRuleTripEnded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.trip.ID = uuid.New()
+			quote := policy.Quote(&tt.trip, tt.reason, now)
+			assert.Equal(t, tt.trip.ID, quote.TripID)
+			assert.Equal(t, tt.rule, quote.Rule)
+			assert.Equal(t, tt.cancellable, quote.Cancellable)
+			assert.Equal(t, tt.fee, quote.Fee)
+			assert.Equal(t, tt.cancellable && tt.fee == 0, quote.Free)
+			if tt.rule == models.CancellationRuleGracePeriod {
+				require.NotNil(t, quote.GraceEndsAt)
+				assert.Equal(t, tt.trip.MatchedAt.Add(2*time.Minute), *quote.GraceEndsAt)
+			} else {
+				assert.Nil(t, quote.GraceEndsAt)
+			}
+		})
+	}
+
+	// Free reasons must be part of the taxonomy
+	cfg := config.DefaultCancellationPolicyConfig()
+	cfg.FreeReasons = []string{"bad_weather"}
+	_, err = service.NewCancellationPolicy(cfg)
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCancellationService_Analytics(t *testing.T) {
+	ctx := context.Background()
+	mem := utils.NewMemoryFixture(t)
+	trips := mem.Trips
+	passenger := mem.NewPassenger()
+
+	start := time.Date(2024, 3, 10, 8, 0, 0, 0, time.UTC)
+
+	cancel := func(at time.Duration, lat, lng float64, reason models.CancellationReason, mode string) {
+		t.Helper()
+		cancelledAt := start.Add(at)
+		trip := &models.Trip{
+			ID:              uuid.New(),
+			PassengerID:     passenger.ID,
+			Status:          models.TripStatusCancelled,
+			PickupLatitude:  lat,
+			PickupLongitude: lng,
+			RequestedAt:     cancelledAt.Add(-5 * time.Minute),
+			CancelledAt:     &cancelledAt,
+			CreatedAt:       cancelledAt,
+			UpdatedAt:       cancelledAt,
+		}
+		if reason != "" {
+			trip.RecordCancellation(models.TripCancellation{Reason: reason}, mode)
+		}
+		require.NoError(t, trips.Create(ctx, trip))
+	}
+	cancel(10*time.Minute, -6.2, 106.8, models.CancellationReasonWaitTooLong, service.TripEventModeActor)
+	cancel(20*time.Minute, -6.2, 106.8, models.CancellationReasonWaitTooLong, service.TripEventModeTraditional)
+	cancel(70*time.Minute, -6.3, 106.9, models.CancellationReasonFareTooHigh, service.TripEventModeActor)
+	// Cancelled before reasons were recorded
+	cancel(80*time.Minute, -6.2, 106.8, "", "")
+	// Outside the period
+	cancel(3*time.Hour, -6.2, 106.8, models.CancellationReasonOther, service.TripEventModeActor)
+
+	svc := service.NewCancellationService(trips)
+	analytics, err := svc.Analytics(ctx, start, start.Add(2*time.Hour), time.Hour, 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(4), analytics.Total)
+	assert.Equal(t, "1h0m0s", analytics.Bucket)
+	require.Len(t, analytics.ByReason, len(models.CancellationReasons)+1, "every reason is listed")
+	assert.Equal(t, models.CancellationCount{Key: "wait_too_long", Count: 2, Share: 0.5}, analytics.ByReason[0])
+	assert.Equal(t, models.CancellationCount{Key: "fare_too_high", Count: 1, Share: 0.25}, analytics.ByReason[1])
+	assert.Equal(t, models.CancellationCount{Key: models.CancellationUnknown, Count: 1, Share: 0.25}, analytics.ByReason[2])
+	assert.Zero(t, analytics.ByReason[3].Count)
+
+	assert.Equal(t, []models.CancellationCount{
+		{Key: service.TripEventModeActor, Count: 2, Share: 0.5},
+		{Key: service.TripEventModeTraditional, Count: 1, Share: 0.25},
+		{Key: models.CancellationUnknown, Count: 1, Share: 0.25},
+	}, analytics.ByMode)
+
+	require.Len(t, analytics.ByZone, 2)
+	assert.Equal(t, int64(3), analytics.ByZone[0].Count)
+	assert.Len(t, analytics.ByZone[0].Key, 5)
+
+	require.Len(t, analytics.ByTime, 2)
+	assert.Equal(t, start, analytics.ByTime[0].Start)
+	assert.Equal(t, int64(2), analytics.ByTime[0].Count)
+	assert.Equal(t, map[string]int64{"wait_too_long": 2}, analytics.ByTime[0].ByReason)
+	assert.Equal(t, map[string]int64{"fare_too_high": 1, models.CancellationUnknown: 1}, analytics.ByTime[1].ByReason)
+
+	var validationErr *models.ValidationError
+	_, err = svc.Analytics(ctx, start, start.Add(2*time.Hour), time.Second, 5)
+	assert.ErrorAs(t, err, &validationErr, "bucket shorter than a minute")
+	_, err = svc.Analytics(ctx, start, start.Add(24*time.Hour), time.Minute, 5)
+	assert.ErrorAs(t, err, &validationErr, "more than the maximum buckets")
+	_, err = svc.Analytics(ctx, start, start.Add(2*time.Hour), time.Hour, 13)
+	assert.ErrorAs(t, err, &validationErr, "zone precision past the geohash maximum")
+}
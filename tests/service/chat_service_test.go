@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/chat"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chatFixture is a chat service over in-memory repositories with a trip in progress between a
+// passenger and a driver, and a passenger of another trip
+type chatFixture struct {
+	svc           *service.ChatService
+	trips         repository.TripRepository
+	trip          *models.Trip
+	passengerUser uuid.UUID
+	driverUser    uuid.UUID
+	outsiderUser  uuid.UUID
+}
+
+func newChatFixture(t *testing.T) *chatFixture {
+	mem := utils.NewMemoryFixture(t)
+	passenger := mem.NewPassenger()
+	outsider := mem.NewPassenger()
+	driver := mem.NewDriver(models.DriverStatusBusy)
+	trip := mem.NewTrip(passenger, driver, models.TripStatusInProgress)
+
+	filters, err := chat.NewFilters([]string{"profanity", "pii"}, []string{"darn"})
+	require.NoError(t, err)
+	cfg := config.DefaultChatConfig()
+	cfg.RetentionPeriod = 24 * time.Hour
+
+	return &chatFixture{
+		svc:           service.NewChatService(memory.NewChatRepository(mem.Store), mem.Trips, mem.Drivers, mem.Passengers, filters, cfg, mem.Logger),
+		trips:         mem.Trips,
+		trip:          trip,
+		passengerUser: passenger.UserID,
+		driverUser:    driver.UserID,
+		outsiderUser:  outsider.UserID,
+	}
+}
+
+// endTrip completes the fixture's trip at completedAt
+func (f *chatFixture) endTrip(t *testing.T, completedAt time.Time) {
+	f.trip.Status = models.TripStatusCompleted
+	f.trip.CompletedAt = &completedAt
+	require.NoError(t, f.trips.Update(context.Background(), f.trip))
+}
+
+func TestChatService_SendFiltersAndCountsUnread(t *testing.T) {
+	f := newChatFixture(t)
+	ctx := context.Background()
+	tripID := f.trip.ID.String()
+
+	msg, err := f.svc.SendMessage(ctx, tripID, f.passengerUser, models.UserTypePassenger, "Darn traffic, text me at +62 812 3456 7890")
+	require.NoError(t, err)
+	assert.Equal(t, models.ChatSenderPassenger, msg.SenderRole)
+	assert.Equal(t, "**** traffic, text me at [phone removed]", msg.Body)
+
+	_, err = f.svc.SendMessage(ctx, tripID, f.driverUser, models.UserTypeDriver, "I'm 2 minutes away")
+	require.NoError(t, err)
+	_, err = f.svc.SendMessage(ctx, tripID, f.passengerUser, models.UserTypePassenger, "Thanks!")
+	require.NoError(t, err)
+
+	unread, err := f.svc.UnreadCounts(ctx, tripID)
+	require.NoError(t, err)
+	assert.Equal(t, &models.TripChatUnreadCounts{Passenger: 1, Driver: 2}, unread)
+
+	marked, err := f.svc.MarkRead(ctx, tripID, f.driverUser, models.UserTypeDriver)
+	require.NoError(t, err)
+	assert.Equal(t, 2, marked)
+
+	unread, err = f.svc.UnreadCounts(ctx, tripID)
+	require.NoError(t, err)
+	assert.Equal(t, &models.TripChatUnreadCounts{Passenger: 1, Driver: 0}, unread)
+
+	messages, err := f.svc.ListMessages(ctx, tripID, f.driverUser, models.UserTypeDriver, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+	assert.Equal(t, msg.ID, messages[0].ID)
+	assert.NotNil(t, messages[0].ReadAt)
+	assert.Nil(t, messages[1].ReadAt)
+}
+
+func TestChatService_OnlyParticipantsOfActiveTripsSend(t *testing.T) {
+	f := newChatFixture(t)
+	ctx := context.Background()
+	tripID := f.trip.ID.String()
+
+	_, err := f.svc.SendMessage(ctx, tripID, f.outsiderUser, models.UserTypePassenger, "Hello?")
+	assert.ErrorIs(t, err, models.ErrUnauthorizedOperation)
+	_, err = f.svc.ListMessages(ctx, tripID, f.passengerUser, models.UserTypeDriver, 10, 0)
+	assert.ErrorIs(t, err, models.ErrUnauthorizedOperation)
+
+	_, err = f.svc.SendMessage(ctx, tripID, f.passengerUser, models.UserTypePassenger, "   ")
+	var validation *models.ValidationError
+	assert.ErrorAs(t, err, &validation)
+
+	_, err = f.svc.SendMessage(ctx, tripID, f.passengerUser, models.UserTypePassenger, "See you")
+	require.NoError(t, err)
+
+	// The chat closes with the trip but stays readable
+	f.endTrip(t, time.Now())
+	_, err = f.svc.SendMessage(ctx, tripID, f.driverUser, models.UserTypeDriver, "Bye")
+	assert.ErrorIs(t, err, models.ErrTripChatClosed)
+
+	messages, err := f.svc.ListMessages(ctx, tripID, f.driverUser, models.UserTypeDriver, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, messages, 1)
+}
+
+func TestChatService_SubscribersReceiveMessages(t *testing.T) {
+	f := newChatFixture(t)
+	ctx := context.Background()
+	tripID := f.trip.ID.String()
+
+	_, _, err := f.svc.Subscribe(ctx, tripID, f.outsiderUser, models.UserTypePassenger)
+	assert.ErrorIs(t, err, models.ErrUnauthorizedOperation)
+
+	messages, unsubscribe, err := f.svc.Subscribe(ctx, tripID, f.driverUser, models.UserTypeDriver)
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	sent, err := f.svc.SendMessage(ctx, tripID, f.passengerUser, models.UserTypePassenger, "I'm by the blue gate")
+	require.NoError(t, err)
+
+	select {
+	case msg := <-messages:
+		assert.Equal(t, sent.ID, msg.ID)
+	case <-time.After(time.Second):
+		t.Fatal("message not delivered to subscriber")
+	}
+}
+
+func TestChatService_PurgeExpired(t *testing.T) {
+	f := newChatFixture(t)
+	ctx := context.Background()
+	tripID := f.trip.ID.String()
+
+	_, err := f.svc.SendMessage(ctx, tripID, f.passengerUser, models.UserTypePassenger, "Almost there")
+	require.NoError(t, err)
+
+	// Messages of trips in progress are kept
+	deleted, err := f.svc.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	// Messages are kept for the retention period after the trip ended
+	f.endTrip(t, time.Now().Add(-23*time.Hour))
+	deleted, err = f.svc.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+
+	f.endTrip(t, time.Now().Add(-25*time.Hour))
+	deleted, err = f.svc.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	messages, err := f.svc.ListMessages(ctx, tripID, f.passengerUser, models.UserTypePassenger, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigReloader_ReloadFromEnvFile(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+
+	path := filepath.Join(t.TempDir(), "app.env")
+	require.NoError(t, os.WriteFile(path, []byte("LOG_LEVEL=info\nSERVER_PORT=8080\n"), 0o600))
+	t.Setenv(config.EnvFileKey, path)
+
+	cfg, err := config.Load()
+	require.NoError(t, err)
+	require.Equal(t, "info", cfg.Logging.Level)
+
+	events := &eventRecorder{}
+	reloader := service.NewConfigReloader(cfg, events, logger)
+	var reloaded []string
+	reloader.OnReload(func(cfg *config.Config) {
+		reloaded = append(reloaded, cfg.Logging.Level)
+	})
+
+	// Nothing changed
+	changes, err := reloader.Reload(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+	assert.Empty(t, reloaded)
+
+	require.NoError(t, os.WriteFile(path, []byte("LOG_LEVEL=warn\nSERVER_PORT=9090\n"), 0o600))
+	changes, err = reloader.Reload(context.Background())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []config.Change{
+		{Field: "Logging.Level", OldValue: "info", NewValue: "warn", Applied: true},
+		{Field: "Server.Port", OldValue: "8080", NewValue: "9090", Applied: false},
+	}, changes)
+
+	assert.Equal(t, "warn", cfg.Logging.Level, "reloadable settings are applied to the live configuration")
+	assert.Equal(t, "8080", cfg.Server.Port, "settings requiring a restart are not")
+	assert.Equal(t, []string{"warn"}, reloaded)
+	assert.Equal(t, []string{"config_reloaded", "config_reloaded"}, events.types())
+
+	// An unreadable file fails the reload and leaves the configuration alone
+	require.NoError(t, os.WriteFile(path, []byte("LOG_LEVEL\n"), 0o600))
+	_, err = reloader.Reload(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, "warn", cfg.Logging.Level)
+}
@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/traditional"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// corporateFixture is a corporate account service over in-memory repositories with a passenger
+type corporateFixture struct {
+	*utils.MemoryFixture
+	svc       *service.CorporateAccountService
+	accounts  repository.CorporateAccountRepository
+	passenger *models.Passenger
+}
+
+func newCorporateFixture(t *testing.T) *corporateFixture {
+	mem := utils.NewMemoryFixture(t)
+	f := &corporateFixture{
+		MemoryFixture: mem,
+		accounts:      memory.NewCorporateAccountRepository(mem.Store),
+		passenger:     mem.NewPassenger(),
+	}
+	f.svc = service.NewCorporateAccountService(f.accounts, f.Passengers, f.Logger)
+	return f
+}
+
+// addTrip stores a trip of the fixture's passenger
+func (f *corporateFixture) addTrip(t *testing.T, status models.TripStatus, fare *float64) *models.Trip {
+	return f.NewTrip(f.passenger, nil, status, func(trip *models.Trip) {
+		trip.FareAmount = fare
+	})
+}
+
+// linkedPassenger returns the fixture's passenger after linking it to the account
+func (f *corporateFixture) linkedPassenger(t *testing.T, account *models.CorporateAccount) *models.Passenger {
+	ctx := context.Background()
+	require.NoError(t, f.svc.LinkPassenger(ctx, account.ID.String(), f.passenger.ID.String()))
+	passenger, err := f.Passengers.GetByID(ctx, f.passenger.ID.String())
+	require.NoError(t, err)
+	return passenger
+}
+
+func TestCorporateAccountService_CreateAccountValidates(t *testing.T) {
+	f := newCorporateFixture(t)
+	ctx := context.Background()
+
+	_, err := f.svc.CreateAccount(ctx, "  ", nil)
+	var validation *models.ValidationError
+	require.True(t, errors.As(err, &validation))
+	assert.Equal(t, "name", validation.Field)
+
+	negative := -1.0
+	_, err = f.svc.CreateAccount(ctx, "Acme", &negative)
+	require.True(t, errors.As(err, &validation))
+	assert.Equal(t, "monthly_spending_limit", validation.Field)
+
+	account, err := f.svc.CreateAccount(ctx, " Acme ", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", account.Name)
+	assert.Nil(t, account.MonthlySpendingLimit)
+}
+
+func TestCorporateAccountService_LinkAndUnlinkPassenger(t *testing.T) {
+	f := newCorporateFixture(t)
+	ctx := context.Background()
+	account, err := f.svc.CreateAccount(ctx, "Acme", nil)
+	require.NoError(t, err)
+	other, err := f.svc.CreateAccount(ctx, "Globex", nil)
+	require.NoError(t, err)
+
+	passenger := f.linkedPassenger(t, account)
+	require.NotNil(t, passenger.CorporateAccountID)
+	assert.Equal(t, account.ID, *passenger.CorporateAccountID)
+
+	var notFound *models.NotFoundError
+	assert.True(t, errors.As(f.svc.LinkPassenger(ctx, uuid.NewString(), f.passenger.ID.String()), &notFound))
+	assert.True(t, errors.As(f.svc.LinkPassenger(ctx, account.ID.String(), uuid.NewString()), &notFound))
+
+	// The passenger is not on the other account
+	assert.True(t, errors.As(f.svc.UnlinkPassenger(ctx, other.ID.String(), f.passenger.ID.String()), &notFound))
+
+	require.NoError(t, f.svc.UnlinkPassenger(ctx, account.ID.String(), f.passenger.ID.String()))
+	passenger, err = f.Passengers.GetByID(ctx, f.passenger.ID.String())
+	require.NoError(t, err)
+	assert.Nil(t, passenger.CorporateAccountID)
+}
+
+func TestCorporateAccountService_ChargeTripEnforcesMonthlyLimit(t *testing.T) {
+	f := newCorporateFixture(t)
+	ctx := context.Background()
+	limit := 30.0
+	account, err := f.svc.CreateAccount(ctx, "Acme", &limit)
+	require.NoError(t, err)
+	passenger := f.linkedPassenger(t, account)
+
+	first := f.addTrip(t, models.TripStatusRequested, nil)
+	require.NoError(t, f.svc.ChargeTrip(ctx, passenger, first, 20))
+
+	second := f.addTrip(t, models.TripStatusRequested, nil)
+	err = f.svc.ChargeTrip(ctx, passenger, second, 15)
+	assert.True(t, errors.Is(err, models.ErrCorporateSpendingLimitExceeded))
+
+	// The first trip completes cheaper than estimated, leaving room for the second
+	fare := 12.5
+	first.Status, first.FareAmount = models.TripStatusCompleted, &fare
+	require.NoError(t, f.Trips.Update(ctx, first))
+	require.NoError(t, f.svc.ChargeTrip(ctx, passenger, second, 15))
+
+	// Cancelled trips cost nothing
+	third := f.addTrip(t, models.TripStatusRequested, nil)
+	assert.True(t, errors.Is(f.svc.ChargeTrip(ctx, passenger, third, 5), models.ErrCorporateSpendingLimitExceeded))
+	second.Status = models.TripStatusCancelled
+	require.NoError(t, f.Trips.Update(ctx, second))
+	require.NoError(t, f.svc.ChargeTrip(ctx, passenger, third, 5))
+
+	// Removing the limit makes billing unlimited
+	_, err = f.svc.SetSpendingLimit(ctx, account.ID.String(), nil)
+	require.NoError(t, err)
+	require.NoError(t, f.svc.ChargeTrip(ctx, passenger, f.addTrip(t, models.TripStatusRequested, nil), 500))
+
+	unlinked := *passenger
+	unlinked.CorporateAccountID = nil
+	assert.True(t, errors.Is(f.svc.ChargeTrip(ctx, &unlinked, f.addTrip(t, models.TripStatusRequested, nil), 5), models.ErrNoCorporateAccount))
+}
+
+func TestCorporateAccountService_Statements(t *testing.T) {
+	f := newCorporateFixture(t)
+	ctx := context.Background()
+	limit := 100.0
+	account, err := f.svc.CreateAccount(ctx, "Acme", &limit)
+	require.NoError(t, err)
+	passenger := f.linkedPassenger(t, account)
+
+	fare := 18.25
+	completed := f.addTrip(t, models.TripStatusCompleted, &fare)
+	cancelled := f.addTrip(t, models.TripStatusCancelled, nil)
+	pending := f.addTrip(t, models.TripStatusRequested, nil)
+	for _, trip := range []*models.Trip{completed, cancelled, pending} {
+		require.NoError(t, f.svc.ChargeTrip(ctx, passenger, trip, 10))
+	}
+
+	// A trip billed last month only shows on last month's statement
+	now := time.Now().UTC()
+	lastMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	old := f.addTrip(t, models.TripStatusRequested, nil)
+	require.NoError(t, f.accounts.ChargeTrip(ctx, &models.CorporateTripCharge{
+		TripID:        old.ID,
+		AccountID:     account.ID,
+		PassengerID:   passenger.ID,
+		EstimatedFare: 40,
+		CreatedAt:     lastMonth.Add(36 * time.Hour),
+	}, nil, lastMonth, lastMonth.AddDate(0, 1, 0)))
+
+	statement, err := f.svc.GetStatement(ctx, account.ID.String(), now.Format(models.CorporateStatementMonthLayout))
+	require.NoError(t, err)
+	assert.Equal(t, "Acme", statement.AccountName)
+	assert.Equal(t, 3, statement.Trips)
+	assert.Equal(t, 1, statement.CompletedTrips)
+	assert.Equal(t, 1, statement.CancelledTrips)
+	assert.InDelta(t, 28.25, statement.TotalSpend, 0.001)
+	require.NotNil(t, statement.RemainingLimit)
+	assert.InDelta(t, 71.75, *statement.RemainingLimit, 0.001)
+	require.Len(t, statement.Passengers, 1)
+	assert.Equal(t, models.CorporateStatementLine{PassengerID: passenger.ID, Trips: 3, TotalSpend: 28.25}, statement.Passengers[0])
+
+	statements, err := f.svc.ListStatements(ctx, account.ID.String(), 3)
+	require.NoError(t, err)
+	require.Len(t, statements, 3)
+	assert.Equal(t, statement.Month, statements[0].Month)
+	assert.Empty(t, statements[0].Passengers)
+	assert.Equal(t, lastMonth.Format(models.CorporateStatementMonthLayout), statements[1].Month)
+	assert.Equal(t, 1, statements[1].Trips)
+	assert.InDelta(t, 40, statements[1].TotalSpend, 0.001)
+	assert.Zero(t, statements[2].Trips)
+
+	var validation *models.ValidationError
+	_, err = f.svc.GetStatement(ctx, account.ID.String(), "2024-13")
+	assert.True(t, errors.As(err, &validation))
+	_, err = f.svc.ListStatements(ctx, account.ID.String(), 25)
+	assert.True(t, errors.As(err, &validation))
+}
+
+func TestRideService_RequestRideBilledToCorporateAccount(t *testing.T) {
+	f := newCorporateFixture(t)
+	ctx := context.Background()
+
+	actorSystem := actor.NewActorSystem("test-system")
+	require.NoError(t, actorSystem.Start(ctx))
+	defer actorSystem.Stop()
+	rideService := service.NewRideService(
+		f.Users, f.Drivers, f.Passengers, f.Trips,
+		actorSystem, observability.NewMetricsCollector(nil, nil, &config.Config{}, f.Logger), traditional.NewTraditionalMonitor(f.Logger, nil),
+		f.Logger, false,
+	)
+	rideService.SetCorporateBilling(f.svc)
+
+	// Online drivers next to the pickup for the traditional matching
+	pickup := models.Location{Latitude: -6.2, Longitude: 106.82}
+	dropoff := models.Location{Latitude: -6.2, Longitude: 106.83}
+	for i := 0; i < 2; i++ {
+		f.NewDriver(models.DriverStatusOnline, func(d *models.Driver) {
+			lat, lng := pickup.Latitude, pickup.Longitude
+			d.CurrentLatitude, d.CurrentLongitude = &lat, &lng
+		})
+	}
+
+	// Without an account the request is rejected before a trip is created
+	_, err := rideService.RequestRide(ctx, f.passenger.ID.String(), pickup, dropoff, "", "", service.BillToCorporateAccount())
+	var validation *models.ValidationError
+	require.True(t, errors.As(err, &validation))
+	assert.Equal(t, "bill_to", validation.Field)
+
+	// The limit covers one ride of about 7.2
+	limit := 10.0
+	account, err := f.svc.CreateAccount(ctx, "Acme", &limit)
+	require.NoError(t, err)
+	f.linkedPassenger(t, account)
+
+	trip, err := rideService.RequestRide(ctx, f.passenger.ID.String(), pickup, dropoff, "", "", service.BillToCorporateAccount())
+	require.NoError(t, err)
+
+	_, err = rideService.RequestRide(ctx, f.passenger.ID.String(), pickup, dropoff, "", "", service.BillToCorporateAccount())
+	assert.True(t, errors.Is(err, models.ErrCorporateSpendingLimitExceeded))
+
+	// Personal rides are not billed or limited
+	_, err = rideService.RequestRide(ctx, f.passenger.ID.String(), pickup, dropoff, "", "")
+	require.NoError(t, err)
+
+	trips, err := f.Trips.List(ctx, 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, trips, 2, "the rejected trip is deleted")
+
+	statement, err := f.svc.GetStatement(ctx, account.ID.String(), time.Now().UTC().Format(models.CorporateStatementMonthLayout))
+	require.NoError(t, err)
+	assert.Equal(t, 1, statement.Trips)
+	require.Len(t, statement.Passengers, 1)
+	assert.Equal(t, trip.PassengerID, statement.Passengers[0].PassengerID)
+}
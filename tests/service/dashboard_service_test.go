@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestDashboardService creates a dashboard service over four trips requested two hours ago,
+// one trip requested before the refresh window, and drivers in Central Jakarta (qqguw) and
+// South Jakarta. It returns the hour the recent trips were requested in.
+func newTestDashboardService(t *testing.T) (*service.DashboardService, time.Time) {
+	ctx := context.Background()
+	mem := utils.NewMemoryFixture(t)
+	store, trips := mem.Store, mem.Trips
+	passenger := mem.NewPassenger()
+
+	hour := time.Now().UTC().Truncate(time.Hour).Add(-2 * time.Hour)
+	requested := hour.Add(5 * time.Minute)
+	at := func(d time.Duration) *time.Time {
+		t := requested.Add(d)
+		return &t
+	}
+	for _, trip := range []*models.Trip{
+		{Status: models.TripStatusCompleted, MatchedAt: at(10 * time.Second), CompletedAt: at(20 * time.Minute)},
+		{Status: models.TripStatusInProgress, MatchedAt: at(20 * time.Second)},
+		{Status: models.TripStatusMatched, MatchedAt: at(30 * time.Second)},
+		{Status: models.TripStatusCancelled, CancelledAt: at(time.Minute)},
+		{Status: models.TripStatusCompleted, RequestedAt: hour.Add(-72 * time.Hour)},
+	} {
+		trip.ID = uuid.New()
+		trip.PassengerID = passenger.ID
+		trip.PickupLatitude, trip.PickupLongitude = -6.2, 106.82
+		trip.DestinationLatitude, trip.DestinationLongitude = -6.26, 106.81
+		if trip.RequestedAt.IsZero() {
+			trip.RequestedAt = requested
+		}
+		trip.CreatedAt, trip.UpdatedAt = trip.RequestedAt, trip.RequestedAt
+		require.NoError(t, trips.Create(ctx, trip))
+	}
+
+	for _, d := range []struct {
+		status   models.DriverStatus
+		lat, lng float64
+	}{
+		{models.DriverStatusOnline, -6.2, 106.82},
+		{models.DriverStatusOnline, -6.2, 106.83},
+		{models.DriverStatusBusy, -6.2, 106.82},
+		{models.DriverStatusOnline, -6.26, 106.81},
+		{models.DriverStatusOffline, -6.2, 106.82},
+	} {
+		mem.NewDriver(d.status, func(driver *models.Driver) {
+			driver.SetLocation(d.lat, d.lng)
+		})
+	}
+
+	return service.NewDashboardService(memory.NewDashboardRepository(store), config.DefaultDashboardConfig(), mem.Logger), hour
+}
+
+func TestDashboardService_StaleUntilRefreshed(t *testing.T) {
+	svc, _ := newTestDashboardService(t)
+
+	summary, err := svc.HourlyTrips(context.Background(), 24)
+	require.NoError(t, err)
+	assert.True(t, summary.Stale)
+	assert.Nil(t, summary.RefreshedAt)
+	assert.Equal(t, "5m0s", summary.RefreshInterval)
+	assert.Empty(t, summary.Data)
+}
+
+func TestDashboardService_RefreshesSummaries(t *testing.T) {
+	svc, hour := newTestDashboardService(t)
+	ctx := context.Background()
+
+	freshness, err := svc.RefreshAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, freshness, len(models.DashboardViews))
+	for _, f := range freshness {
+		assert.False(t, f.Stale, f.View)
+		require.NotNil(t, f.RefreshedAt, f.View)
+	}
+
+	hourly, err := svc.HourlyTrips(ctx, 24)
+	require.NoError(t, err)
+	assert.False(t, hourly.Stale)
+	rows := hourly.Data.([]*models.HourlyTripSummary)
+	require.Len(t, rows, 24, "hours without trips are included")
+	var found *models.HourlyTripSummary
+	for _, row := range rows {
+		if row.Hour.Equal(hour) {
+			found = row
+		} else {
+			assert.Zero(t, row.Requested)
+		}
+	}
+	require.NotNil(t, found)
+	assert.Equal(t, models.HourlyTripSummary{Hour: hour, Requested: 4, Matched: 3, Completed: 1, Cancelled: 1}, *found)
+
+	matching, err := svc.MatchingTimes(ctx, 24)
+	require.NoError(t, err)
+	waits := matching.Data.([]*models.MatchingTimeSummary)
+	require.Len(t, waits, 1, "hours without matched trips are omitted")
+	assert.Equal(t, models.MatchingTimeSummary{Hour: hour, Samples: 3, AvgSeconds: 20, P50Seconds: 20, P90Seconds: 30, P99Seconds: 30}, *waits[0])
+
+	supply, err := svc.ZoneSupply(ctx)
+	require.NoError(t, err)
+	zones := supply.Data.([]*models.ZoneSupplySummary)
+	require.Len(t, zones, 2, "offline drivers aren't supply")
+	assert.Equal(t, "qqguw", zones[0].Zone)
+	assert.Equal(t, 2, zones[0].AvailableDrivers)
+	assert.Equal(t, 1, zones[0].BusyDrivers)
+	assert.InDelta(t, -6.2, zones[0].CenterLat, 0.03)
+	assert.InDelta(t, 106.82, zones[0].CenterLng, 0.03)
+	assert.Equal(t, 1, zones[1].AvailableDrivers)
+}
+
+func TestDashboardService_Validation(t *testing.T) {
+	svc, _ := newTestDashboardService(t)
+	ctx := context.Background()
+
+	var validationErr *models.ValidationError
+	_, err := svc.HourlyTrips(ctx, 0)
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = svc.MatchingTimes(ctx, 1000)
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = svc.Refresh(ctx, "trips")
+	assert.ErrorAs(t, err, &validationErr)
+}
+
+func TestDashboardService_StartRefreshesImmediately(t *testing.T) {
+	svc, _ := newTestDashboardService(t)
+
+	require.NoError(t, svc.Start(context.Background()))
+	defer svc.Stop()
+
+	supply, err := svc.ZoneSupply(context.Background())
+	require.NoError(t, err)
+	assert.False(t, supply.Stale)
+	assert.Len(t, supply.Data, 2)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// metricsRecorder records the business metrics counted by a service, keyed by name and tag
+// values, e.g. "driver_fatigue_rests_total:online_time"
+type metricsRecorder struct {
+	mu      sync.Mutex
+	metrics map[string]float64
+}
+
+func (r *metricsRecorder) RecordBusinessMetrics(metricName string, value float64, tags map[string]string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.metrics == nil {
+		r.metrics = make(map[string]float64)
+	}
+	values := make([]string, 0, len(tags))
+	for _, v := range tags {
+		values = append(values, v)
+	}
+	sort.Strings(values)
+	r.metrics[metricName+":"+strings.Join(values, ",")] += value
+}
+
+type fatigueFixture struct {
+	*utils.MemoryFixture
+	svc     *service.DriverFatigueService
+	events  *eventRecorder
+	metrics *metricsRecorder
+}
+
+// newFatigueFixture creates a fatigue service over an in-memory store. Limits below a second
+// are reached as soon as a driver is online.
+func newFatigueFixture(t *testing.T, cfg config.FatigueConfig) *fatigueFixture {
+	mem := utils.NewMemoryFixture(t)
+	events := &eventRecorder{}
+	metrics := &metricsRecorder{}
+	return &fatigueFixture{
+		MemoryFixture: mem,
+		svc:           service.NewDriverFatigueService(mem.Drivers, cfg, events, metrics, mem.Logger),
+		events:        events,
+		metrics:       metrics,
+	}
+}
+
+func TestDriverFatigueService_SetsOnlineDriversOfflineToRest(t *testing.T) {
+	cfg := config.DefaultFatigueConfig()
+	cfg.MaxOnline = time.Nanosecond
+	f := newFatigueFixture(t, cfg)
+	ctx := context.Background()
+
+	online := f.NewDriver(models.DriverStatusOnline)
+	busy := f.NewDriver(models.DriverStatusBusy)
+
+	fatigue, err := f.svc.GetFatigue(ctx, online.ID.String())
+	require.NoError(t, err)
+	assert.Nil(t, fatigue.Rest)
+	assert.Equal(t, int64(cfg.MaxOnline.Seconds()), fatigue.OnlineLimitSeconds)
+	assert.Equal(t, int64(cfg.MaxDriving.Seconds()), fatigue.DrivingLimitSeconds)
+	assert.Equal(t, cfg.Window, fatigue.WindowEnd.Sub(fatigue.WindowStart))
+
+	// Drivers busy with a trip are left alone until they are back online
+	rests, err := f.svc.Enforce(ctx)
+	require.NoError(t, err)
+	require.Len(t, rests, 1)
+	assert.Equal(t, online.ID, rests[0].DriverID)
+	assert.Equal(t, models.DriverFatigueLimitOnline, rests[0].Limit)
+	assert.Equal(t, rests[0].StartedAt.Add(cfg.RestPeriod), rests[0].RestUntil)
+
+	driver, err := f.Drivers.GetByID(ctx, online.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.DriverStatusOffline, driver.Status)
+	driver, err = f.Drivers.GetByID(ctx, busy.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.DriverStatusBusy, driver.Status)
+
+	err = f.svc.CheckOnline(ctx, online.ID.String())
+	assert.ErrorIs(t, err, models.ErrDriverResting)
+	assert.NoError(t, f.svc.CheckOnline(ctx, busy.ID.String()))
+
+	fatigue, err = f.svc.GetFatigue(ctx, online.ID.String())
+	require.NoError(t, err)
+	require.NotNil(t, fatigue.Rest)
+	assert.Equal(t, rests[0].ID, fatigue.Rest.ID)
+
+	assert.Equal(t, []string{"driver_fatigue_rest_started"}, f.events.types())
+	assert.Equal(t, online.ID, *f.events.events[0].EntityID)
+	assert.Equal(t, map[string]float64{"driver_fatigue_rests_total:online_time": 1}, f.metrics.metrics)
+
+	// Resting drivers are offline and not checked again
+	rests, err = f.svc.Enforce(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, rests)
+}
+
+func TestDriverFatigueService_WindowRestartsAfterRest(t *testing.T) {
+	cfg := config.DefaultFatigueConfig()
+	cfg.MaxOnline = time.Hour
+	cfg.MaxDriving = time.Nanosecond
+	cfg.RestPeriod = time.Millisecond
+	f := newFatigueFixture(t, cfg)
+	ctx := context.Background()
+
+	driver := f.NewDriver(models.DriverStatusOnline)
+	rests, err := f.svc.Enforce(ctx)
+	require.NoError(t, err)
+	require.Len(t, rests, 1)
+	assert.Equal(t, models.DriverFatigueLimitDriving, rests[0].Limit)
+
+	time.Sleep(2 * time.Millisecond)
+	require.NoError(t, f.svc.CheckOnline(ctx, driver.ID.String()))
+	require.NoError(t, f.Drivers.UpdateStatus(ctx, driver.ID.String(), models.DriverStatusOnline))
+
+	fatigue, err := f.svc.GetFatigue(ctx, driver.ID.String())
+	require.NoError(t, err)
+	assert.Nil(t, fatigue.Rest)
+	assert.Equal(t, rests[0].RestUntil, fatigue.WindowStart)
+
+	// Disabled limits don't keep resting drivers offline
+	cfg.Enabled = false
+	cfg.RestPeriod = time.Hour
+	disabled := newFatigueFixture(t, cfg)
+	driver = disabled.NewDriver(models.DriverStatusOnline)
+	_, err = disabled.svc.Enforce(ctx)
+	require.NoError(t, err)
+	assert.NoError(t, disabled.svc.CheckOnline(ctx, driver.ID.String()))
+}
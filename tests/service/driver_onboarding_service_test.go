@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOnboardingFixture creates a driver onboarding service over in-memory repositories and a
+// function creating drivers
+func newOnboardingFixture(t *testing.T) (*service.DriverOnboardingService, repository.DriverOnboardingRepository, func() uuid.UUID, *eventRecorder) {
+	mem := utils.NewMemoryFixture(t)
+	onboardings := memory.NewDriverOnboardingRepository(mem.Store)
+	newDriver := func() uuid.UUID {
+		t.Helper()
+		return mem.NewDriver(models.DriverStatusOffline).ID
+	}
+
+	events := &eventRecorder{}
+	return service.NewDriverOnboardingService(onboardings, config.OnboardingConfig{RequireActivation: true}, events, mem.Logger), onboardings, newDriver, events
+}
+
+func TestDriverOnboardingService_Stages(t *testing.T) {
+	ctx := context.Background()
+	svc, _, newDriver, events := newOnboardingFixture(t)
+	driverID := newDriver()
+	id := driverID.String()
+
+	_, err := svc.Register(ctx, driverID)
+	require.NoError(t, err)
+	_, err = svc.Register(ctx, driverID)
+	assert.ErrorIs(t, err, models.ErrDuplicateEntry)
+
+	progress, err := svc.Progress(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, models.OnboardingRegistered, progress.Stage)
+	require.NotNil(t, progress.NextStage)
+	assert.Equal(t, models.OnboardingDocumentsSubmitted, *progress.NextStage)
+	assert.Len(t, progress.Stages, len(models.OnboardingStages))
+	require.Len(t, progress.Outstanding, len(models.RequiredOnboardingDocuments), "every document is outstanding")
+	assert.ErrorIs(t, svc.CheckActivated(ctx, id), models.ErrDriverNotActivated)
+
+	// Verifying before every document is in reports what is missing
+	_, err = svc.Verify(ctx, id, "admin@example.com")
+	assert.ErrorIs(t, err, models.ErrOnboardingRequirementsOpen)
+	assert.ErrorContains(t, err, "insurance")
+
+	var validationErr *models.ValidationError
+	_, err = svc.SubmitDocuments(ctx, id, []models.OnboardingDocument{"passport"})
+	assert.ErrorAs(t, err, &validationErr)
+
+	progress, err = svc.SubmitDocuments(ctx, id, []models.OnboardingDocument{models.OnboardingDocumentDriverLicense, models.OnboardingDocumentVehicleRegistration})
+	require.NoError(t, err)
+	assert.Equal(t, models.OnboardingRegistered, progress.Stage)
+	require.Len(t, progress.Outstanding, 1)
+	assert.Equal(t, models.OnboardingDocumentInsurance, *progress.Outstanding[0].Document)
+
+	progress, err = svc.SubmitDocuments(ctx, id, []models.OnboardingDocument{models.OnboardingDocumentInsurance})
+	require.NoError(t, err)
+	assert.Equal(t, models.OnboardingDocumentsSubmitted, progress.Stage)
+	assert.NotNil(t, progress.DocumentsSubmittedAt)
+	assert.False(t, progress.Outstanding[0].ByDriver, "the driver waits on the review")
+
+	_, err = svc.CompleteTraining(ctx, id)
+	assert.ErrorIs(t, err, models.ErrOnboardingRequirementsOpen, "training before verification")
+
+	_, err = svc.Verify(ctx, id, "admin@example.com")
+	require.NoError(t, err)
+	_, err = svc.Verify(ctx, id, "admin@example.com")
+	assert.ErrorIs(t, err, models.ErrInvalidStatusTransition, "verified twice")
+	_, err = svc.CompleteTraining(ctx, id)
+	require.NoError(t, err)
+	progress, err = svc.Activate(ctx, id, "admin@example.com")
+	require.NoError(t, err)
+
+	assert.Equal(t, models.OnboardingActivated, progress.Stage)
+	assert.Nil(t, progress.NextStage)
+	assert.Empty(t, progress.Outstanding)
+	require.NotNil(t, progress.ActivatedBy)
+	assert.Equal(t, "admin@example.com", *progress.ActivatedBy)
+	for _, stage := range progress.Stages {
+		assert.True(t, stage.Completed, stage.Stage)
+		assert.NotNil(t, stage.ReachedAt, stage.Stage)
+	}
+	assert.NoError(t, svc.CheckActivated(ctx, id))
+
+	assert.Equal(t, []string{
+		"driver_onboarding_registered",
+		"driver_onboarding_documents_submitted",
+		"driver_onboarding_verified",
+		"driver_onboarding_training_completed",
+		"driver_onboarding_activated",
+	}, events.types())
+
+	// Drivers without an onboarding are not held back
+	assert.NoError(t, svc.CheckActivated(ctx, uuid.New().String()))
+	_, err = svc.Progress(ctx, uuid.New().String())
+	var notFound *models.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestDriverOnboardingService_CreateDriver(t *testing.T) {
+	ctx := context.Background()
+	mem := utils.NewMemoryFixture(t)
+	drivers := mem.Drivers
+	onboardings := memory.NewDriverOnboardingRepository(mem.Store)
+	svc := service.NewDriverOnboardingService(onboardings, config.DefaultOnboardingConfig(), &eventRecorder{}, mem.Logger)
+
+	user := mem.NewUser(models.UserTypeDriver)
+	driver := &models.Driver{ID: uuid.New(), UserID: user.ID, LicenseNumber: "LIC-NEW", VehicleType: "sedan", VehiclePlate: "B 1 NEW", Status: models.DriverStatusOffline, Rating: 5}
+
+	onboarding, err := svc.CreateDriver(ctx, driver)
+	require.NoError(t, err)
+	assert.Equal(t, models.OnboardingRegistered, onboarding.Stage)
+	_, err = drivers.GetByID(ctx, driver.ID.String())
+	require.NoError(t, err)
+
+	// Without activation required, drivers go online at any stage
+	assert.NoError(t, svc.CheckActivated(ctx, driver.ID.String()))
+
+	// A driver that cannot be stored leaves no onboarding behind
+	duplicate := &models.Driver{ID: uuid.New(), UserID: user.ID, LicenseNumber: "LIC-NEW", VehicleType: "sedan", VehiclePlate: "B 2 NEW", Status: models.DriverStatusOffline, Rating: 5}
+	_, err = svc.CreateDriver(ctx, duplicate)
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	_, err = onboardings.GetByDriverID(ctx, duplicate.ID.String())
+	var notFound *models.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+}
+
+func TestDriverOnboardingService_StageConflict(t *testing.T) {
+	ctx := context.Background()
+	_, onboardings, newDriver, _ := newOnboardingFixture(t)
+	driverID := newDriver()
+
+	require.NoError(t, onboardings.Create(ctx, models.NewDriverOnboarding(driverID, time.Now())))
+	stale, err := onboardings.GetByDriverID(ctx, driverID.String())
+	require.NoError(t, err)
+
+	current, err := onboardings.GetByDriverID(ctx, driverID.String())
+	require.NoError(t, err)
+	current.SubmitDocuments(models.RequiredOnboardingDocuments, time.Now())
+	require.NoError(t, onboardings.Update(ctx, current, models.OnboardingRegistered))
+
+	stale.Verify("admin@example.com", time.Now())
+	assert.ErrorIs(t, onboardings.Update(ctx, stale, models.OnboardingRegistered), models.ErrOnboardingStageConflict)
+}
+
+func TestDriverOnboardingService_Funnel(t *testing.T) {
+	ctx := context.Background()
+	svc, onboardings, newDriver, _ := newOnboardingFixture(t)
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	onboard := func(registeredAt time.Time, stage models.OnboardingStage) {
+		t.Helper()
+		onboarding := models.NewDriverOnboarding(newDriver(), registeredAt)
+		require.NoError(t, onboardings.Create(ctx, onboarding))
+		for onboarding.Stage != stage {
+			from := onboarding.Stage
+			switch from {
+			case models.OnboardingRegistered:
+				onboarding.SubmitDocuments(models.RequiredOnboardingDocuments, registeredAt)
+			case models.OnboardingDocumentsSubmitted:
+				onboarding.Verify("admin@example.com", registeredAt)
+			case models.OnboardingVerified:
+				onboarding.CompleteTraining(registeredAt)
+			case models.OnboardingTrainingCompleted:
+				onboarding.Activate("admin@example.com", registeredAt)
+			}
+			require.NoError(t, onboardings.Update(ctx, onboarding, from))
+		}
+	}
+	onboard(start.Add(time.Hour), models.OnboardingRegistered)
+	onboard(start.Add(2*time.Hour), models.OnboardingRegistered)
+	onboard(start.Add(3*time.Hour), models.OnboardingDocumentsSubmitted)
+	onboard(start.Add(4*time.Hour), models.OnboardingActivated)
+	// Registered outside the period
+	onboard(start.Add(-time.Hour), models.OnboardingActivated)
+
+	funnel, err := svc.Funnel(ctx, start, start.Add(24*time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(4), funnel.Total)
+	assert.Equal(t, []models.OnboardingFunnelStage{
+		{Stage: models.OnboardingRegistered, Reached: 4, Current: 2, Conversion: 1, OverallConversion: 1},
+		{Stage: models.OnboardingDocumentsSubmitted, Reached: 2, Current: 1, Conversion: 0.5, OverallConversion: 0.5},
+		{Stage: models.OnboardingVerified, Reached: 1, Current: 0, Conversion: 0.5, OverallConversion: 0.25},
+		{Stage: models.OnboardingTrainingCompleted, Reached: 1, Current: 0, Conversion: 1, OverallConversion: 0.25},
+		{Stage: models.OnboardingActivated, Reached: 1, Current: 1, Conversion: 1, OverallConversion: 0.25},
+	}, funnel.Stages)
+
+	empty, err := svc.Funnel(ctx, start.Add(48*time.Hour), start.Add(72*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, empty.Total)
+	assert.Len(t, empty.Stages, len(models.OnboardingStages))
+	assert.Zero(t, empty.Stages[1].Conversion)
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"mime"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/notification"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEmailSender fails the first failures sends and delivers the rest to sent
+type fakeEmailSender struct {
+	mu       sync.Mutex
+	failures int
+	attempts int
+	sent     chan *notification.Message
+}
+
+func (s *fakeEmailSender) Send(ctx context.Context, msg *notification.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attempts++
+	if s.attempts <= s.failures {
+		return errors.New("451 try again later")
+	}
+	s.sent <- msg
+	return nil
+}
+
+type emailFixture struct {
+	*utils.MemoryFixture
+	notifier *service.EmailNotifier
+	sender   *fakeEmailSender
+	metrics  *metricsRecorder
+}
+
+// newEmailFixture creates an email notifier over in-memory repositories that retries almost
+// immediately. The notifier is not started.
+func newEmailFixture(t *testing.T, failures int, modify func(*config.EmailConfig)) *emailFixture {
+	cfg := config.DefaultEmailConfig()
+	cfg.InitialBackoff = time.Millisecond
+	cfg.MaxBackoff = 5 * time.Millisecond
+	cfg.MaxAttempts = 3
+	if modify != nil {
+		modify(&cfg)
+	}
+
+	mem := utils.NewMemoryFixture(t)
+	sender := &fakeEmailSender{failures: failures, sent: make(chan *notification.Message, 10)}
+	metrics := &metricsRecorder{}
+	notifier, err := service.NewEmailNotifier(sender, mem.Users, mem.Passengers, mem.Drivers, metrics, cfg, mem.Logger)
+	require.NoError(t, err)
+
+	return &emailFixture{MemoryFixture: mem, notifier: notifier, sender: sender, metrics: metrics}
+}
+
+func (f *emailFixture) start(t *testing.T) {
+	require.NoError(t, f.notifier.Start(context.Background()))
+	t.Cleanup(func() { f.notifier.Stop() })
+}
+
+func (f *emailFixture) received(t *testing.T) *notification.Message {
+	t.Helper()
+	select {
+	case msg := <-f.sender.sent:
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("no email was sent")
+		return nil
+	}
+}
+
+func TestEmailNotifier_ReceiptRetriedUntilSent(t *testing.T) {
+	f := newEmailFixture(t, 2, nil)
+	f.start(t)
+	ctx := context.Background()
+
+	passenger := f.NewPassenger()
+	user := f.User(passenger.UserID)
+
+	fare, distance, duration := 42.5, 12.3, 25
+	pickup := "Jl. Sudirman 1"
+	completedAt := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+	trip := &models.Trip{ID: uuid.New(), PassengerID: passenger.ID, Status: models.TripStatusCompleted, PickupAddress: &pickup,
+		DestinationLatitude: -6.2, DestinationLongitude: 106.8, FareAmount: &fare, DistanceKm: &distance, DurationMinutes: &duration, CompletedAt: &completedAt}
+
+	// Only completed trips get a receipt
+	require.NoError(t, f.notifier.PublishTripEvent(ctx, models.WebhookEventTripMatched, trip))
+	require.NoError(t, f.notifier.PublishTripEvent(ctx, models.WebhookEventTripCompleted, trip))
+
+	msg := f.received(t)
+	assert.Equal(t, notification.TemplateReceipt, msg.Template)
+	assert.Equal(t, user.Email, msg.To)
+	assert.Equal(t, "Your trip receipt: 42.50", msg.Subject)
+	assert.Contains(t, msg.Text, "From:      Jl. Sudirman 1")
+	assert.Contains(t, msg.Text, "To:        -6.20000, 106.80000", "coordinates stand in for a missing address")
+	assert.Contains(t, msg.Text, "Distance:  12.3 km")
+	assert.Contains(t, msg.Text, "5 Mar 2024 14:30 UTC")
+	assert.Contains(t, msg.HTML, "Jl. Sudirman 1")
+
+	require.Eventually(t, func() bool { return f.notifier.Stats().Sent == 1 }, time.Second, 5*time.Millisecond)
+	stats := f.notifier.Stats()
+	assert.Equal(t, int64(1), stats.Queued)
+	assert.Equal(t, int64(2), stats.Retried)
+	assert.Zero(t, stats.Failed)
+
+	f.metrics.mu.Lock()
+	defer f.metrics.mu.Unlock()
+	assert.Equal(t, 1.0, f.metrics.metrics["email_messages_total:receipt,sent"])
+	assert.Equal(t, 2.0, f.metrics.metrics["email_send_attempts_total:error,receipt"])
+	assert.Equal(t, 1.0, f.metrics.metrics["email_send_attempts_total:receipt,success"])
+}
+
+func TestEmailNotifier_GivesUpAfterMaxAttempts(t *testing.T) {
+	f := newEmailFixture(t, 10, nil)
+	f.start(t)
+
+	user := &models.User{Email: "rider@example.com", Name: "Rider"}
+	require.NoError(t, f.notifier.SendPasswordReset(user, "https://example.com/reset?token=abc", time.Now().Add(time.Hour)))
+
+	require.Eventually(t, func() bool { return f.notifier.Stats().Failed == 1 }, time.Second, 5*time.Millisecond)
+	assert.Equal(t, int64(2), f.notifier.Stats().Retried)
+	assert.Zero(t, f.notifier.Stats().Sent)
+}
+
+func TestEmailNotifier_DropsWhenQueueFull(t *testing.T) {
+	f := newEmailFixture(t, 0, func(cfg *config.EmailConfig) { cfg.QueueSize = 1 })
+
+	user := &models.User{Email: "rider@example.com", Name: "Rider"}
+	require.NoError(t, f.notifier.SendPasswordReset(user, "https://example.com/reset", time.Now()))
+	assert.ErrorIs(t, f.notifier.SendPasswordReset(user, "https://example.com/reset", time.Now()), service.ErrEmailQueueFull)
+
+	stats := f.notifier.Stats()
+	assert.Equal(t, int64(1), stats.Queued)
+	assert.Equal(t, int64(1), stats.Dropped)
+	assert.Equal(t, 1, stats.QueueDepth)
+}
+
+func TestEmailNotifier_WeeklyEarnings(t *testing.T) {
+	f := newEmailFixture(t, 0, nil)
+	f.start(t)
+	ctx := context.Background()
+	weekStart := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)
+
+	passenger := f.NewPassenger()
+	newDriver := func() (*models.User, uuid.UUID) {
+		driver := f.NewDriver(models.DriverStatusOffline, func(d *models.Driver) {
+			d.Rating = 4.8
+		})
+		return f.User(driver.UserID), driver.ID
+	}
+	trips := f.Trips
+	addTrip := func(driverID uuid.UUID, status models.TripStatus, requestedAt time.Time, fare float64) {
+		require.NoError(t, trips.Create(ctx, &models.Trip{ID: uuid.New(), PassengerID: passenger.ID, DriverID: &driverID,
+			Status: status, RequestedAt: requestedAt, FareAmount: &fare}))
+	}
+
+	earner, earnerID := newDriver()
+	addTrip(earnerID, models.TripStatusCompleted, weekStart.Add(24*time.Hour), 30)
+	addTrip(earnerID, models.TripStatusCompleted, weekStart.Add(48*time.Hour), 20.25)
+	addTrip(earnerID, models.TripStatusCancelled, weekStart.Add(72*time.Hour), 0)
+	addTrip(earnerID, models.TripStatusCompleted, weekStart.AddDate(0, 0, 7), 99)
+	// Drivers who completed no trip in the week get no summary
+	_, idleID := newDriver()
+	addTrip(idleID, models.TripStatusCompleted, weekStart.AddDate(0, 0, -1), 15)
+
+	queued, err := f.notifier.SendWeeklyEarnings(ctx, weekStart)
+	require.NoError(t, err)
+	assert.Equal(t, 1, queued)
+
+	msg := f.received(t)
+	assert.Equal(t, notification.TemplateWeeklyEarnings, msg.Template)
+	assert.Equal(t, earner.Email, msg.To)
+	assert.Equal(t, "Your earnings for the week of 4 Mar 2024: 50.25", msg.Subject)
+	assert.Contains(t, msg.Text, "4 Mar 2024 to 10 Mar 2024")
+	assert.Contains(t, msg.Text, "Trips completed: 2")
+	assert.Contains(t, msg.Text, "Trips cancelled: 1")
+	assert.Contains(t, msg.Text, "Rating:          4.80")
+}
+
+func TestBuildMIME(t *testing.T) {
+	renderer, err := notification.NewRenderer()
+	require.NoError(t, err)
+	msg, err := renderer.Render(notification.TemplatePasswordReset, "rider@example.com", notification.PasswordResetData{
+		Name:      "Siti <script>",
+		ResetURL:  "https://example.com/reset?token=abc",
+		ExpiresAt: time.Date(2024, 3, 5, 15, 0, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	assert.NotContains(t, msg.HTML, "<script>", "HTML bodies are escaped")
+
+	from := &mail.Address{Name: "Rides", Address: "no-reply@example.com"}
+	to := &mail.Address{Address: msg.To}
+	body, err := notification.BuildMIME(from, to, msg, time.Date(2024, 3, 5, 14, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(string(body)))
+	require.NoError(t, err)
+	assert.Equal(t, `"Rides" <no-reply@example.com>`, parsed.Header.Get("From"))
+	assert.Equal(t, "<rider@example.com>", parsed.Header.Get("To"))
+	assert.True(t, strings.HasSuffix(parsed.Header.Get("Message-Id"), "@example.com>"))
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, msg.Subject, subject)
+	assert.Contains(t, parsed.Header.Get("Content-Type"), "multipart/alternative")
+	assert.Contains(t, string(body), "Content-Type: text/plain; charset=utf-8")
+	assert.Contains(t, string(body), "Content-Type: text/html; charset=utf-8")
+	assert.Equal(t, 2, strings.Count(string(body), "Content-Transfer-Encoding: quoted-printable"))
+}
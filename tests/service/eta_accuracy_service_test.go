@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// etaFixture is an ETA accuracy service over in-memory repositories with a driver 5km north of
+// the pickups
+type etaFixture struct {
+	svc         *service.ETAAccuracyService
+	model       *service.ETAModel
+	predictions repository.ETAPredictionRepository
+	driver      *models.Driver
+}
+
+func newETAFixture(t *testing.T, cfg config.ETAConfig) *etaFixture {
+	mem := utils.NewMemoryFixture(t)
+	drivers := mem.Drivers
+	predictions := memory.NewETAPredictionRepository(mem.Store)
+	driver := mem.NewDriver(models.DriverStatusBusy, func(d *models.Driver) {
+		lat, lng := -6.155, 106.82
+		d.CurrentLatitude, d.CurrentLongitude = &lat, &lng
+	})
+
+	model := service.NewETAModel(cfg.AverageSpeedKmh)
+	return &etaFixture{
+		svc:         service.NewETAAccuracyService(predictions, drivers, model, cfg, config.DefaultReportingConfig(), mem.Logger),
+		model:       model,
+		predictions: predictions,
+		driver:      driver,
+	}
+}
+
+// publish delivers a trip in a status to the service as the event bus would
+func (f *etaFixture) publish(trip *models.Trip, status models.TripStatus, at time.Time) {
+	snapshot := *trip
+	snapshot.Status = status
+	f.svc.HandleMessage(bus.Message{Topic: bus.TopicTripStatus, Payload: &snapshot, PublishedAt: at})
+}
+
+// matchedTrip returns a trip matched with the fixture's driver at the given time
+func (f *etaFixture) matchedTrip(at time.Time) *models.Trip {
+	return &models.Trip{
+		ID:              uuid.New(),
+		DriverID:        &f.driver.ID,
+		PickupLatitude:  -6.2,
+		PickupLongitude: 106.82,
+		MatchedAt:       &at,
+	}
+}
+
+func TestETAAccuracyService_RecordsPredictionsAndArrivals(t *testing.T) {
+	f := newETAFixture(t, config.DefaultETAConfig())
+	ctx := context.Background()
+	matched := time.Now().Add(-3 * time.Hour).Truncate(time.Second)
+
+	// The driver is 5km away: 10 minutes at 30 km/h
+	late := f.matchedTrip(matched)
+	f.publish(late, models.TripStatusMatched, matched)
+	prediction, err := f.predictions.GetByTrip(ctx, late.ID.String())
+	require.NoError(t, err)
+	assert.InDelta(t, 5.0, prediction.DistanceKm, 0.01)
+	assert.InDelta(t, 600, prediction.PredictedSeconds, 1)
+	assert.Equal(t, 30.0, prediction.SpeedKmh)
+	assert.Equal(t, matched.UTC().Hour(), prediction.Hour)
+	assert.NotEmpty(t, prediction.Zone)
+
+	// Only the first match and the first arrival count
+	f.publish(late, models.TripStatusMatched, matched.Add(time.Minute))
+	f.publish(late, models.TripStatusDriverArrived, matched.Add(15*time.Minute))
+	f.publish(late, models.TripStatusInProgress, matched.Add(20*time.Minute))
+	prediction, err = f.predictions.GetByTrip(ctx, late.ID.String())
+	require.NoError(t, err)
+	assert.True(t, prediction.PredictedAt.Equal(matched))
+	require.NotNil(t, prediction.ActualSeconds)
+	assert.Equal(t, 900, *prediction.ActualSeconds)
+	assert.Equal(t, 900-prediction.PredictedSeconds, *prediction.ErrorSeconds)
+
+	// Trips started without reporting the arrival arrived at pickup
+	onTime := f.matchedTrip(matched)
+	f.publish(onTime, models.TripStatusMatched, matched)
+	pickedUp := matched.Add(10 * time.Minute)
+	onTime.PickupAt = &pickedUp
+	f.publish(onTime, models.TripStatusInProgress, pickedUp.Add(time.Second))
+
+	report, err := f.svc.Compute(ctx, matched.Add(-time.Hour), time.Now())
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, report.Overall.Samples)
+	assert.InDelta(t, 150, report.Overall.MAESeconds, 1)
+	assert.InDelta(t, 150, report.Overall.BiasSeconds, 1)
+	assert.InDelta(t, 750, report.Overall.AvgActualSeconds, 0.01)
+	assert.InDelta(t, 24, report.Overall.ObservedSpeedKmh, 0.1)
+	require.Len(t, report.Buckets, 1)
+	assert.Equal(t, prediction.Zone, report.Buckets[0].Zone)
+	assert.Equal(t, "UTC", report.Timezone)
+
+	// Unmatched trips are ignored
+	f.publish(f.matchedTrip(matched), models.TripStatusDriverArrived, matched)
+	_, err = f.svc.Compute(ctx, time.Now(), matched)
+	var validation *models.ValidationError
+	assert.True(t, errors.As(err, &validation))
+}
+
+func TestETAAccuracyService_AdjustsSpeed(t *testing.T) {
+	cfg := config.DefaultETAConfig()
+	cfg.AutoAdjust = true
+	cfg.AdjustRate = 0.5
+	cfg.MinSamples = 2
+	f := newETAFixture(t, cfg)
+	ctx := context.Background()
+	matched := time.Now().Add(-time.Hour)
+
+	trip := f.matchedTrip(matched)
+	f.publish(trip, models.TripStatusMatched, matched)
+	f.publish(trip, models.TripStatusDriverArrived, matched.Add(15*time.Minute))
+
+	// Too few samples to adjust
+	report, err := f.svc.Refresh(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, report.Overall.Samples)
+	assert.Equal(t, 30.0, f.model.SpeedKmh())
+
+	trip = f.matchedTrip(matched)
+	f.publish(trip, models.TripStatusMatched, matched)
+	f.publish(trip, models.TripStatusDriverArrived, matched.Add(15*time.Minute))
+
+	// Drivers drove 5km in 15 minutes, 20 km/h: half way there from 30 km/h
+	report, err = f.svc.Refresh(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 20, report.Overall.ObservedSpeedKmh, 0.1)
+	assert.InDelta(t, 25, f.model.SpeedKmh(), 0.05)
+	assert.Equal(t, f.model.SpeedKmh(), report.SpeedKmh)
+	assert.Same(t, report, f.svc.Latest())
+
+	// New ETAs assume the adjusted speed
+	trip = f.matchedTrip(matched)
+	f.publish(trip, models.TripStatusMatched, matched)
+	prediction, err := f.predictions.GetByTrip(ctx, trip.ID.String())
+	require.NoError(t, err)
+	assert.InDelta(t, 720, prediction.PredictedSeconds, 2)
+}
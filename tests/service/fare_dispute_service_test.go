@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// disputeNotifier records the dispute decisions sent to webhook subscribers
+type disputeNotifier struct {
+	mu     sync.Mutex
+	events []models.WebhookEvent
+	fares  []float64
+}
+
+func (n *disputeNotifier) PublishFareDisputeEvent(ctx context.Context, event models.WebhookEvent, trip *models.Trip, dispute *models.FareDispute) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, event)
+	n.fares = append(n.fares, *trip.FareAmount)
+	return nil
+}
+
+// newTestFareDisputeService creates a dispute service over in-memory repositories with one completed trip
+func newTestFareDisputeService(t *testing.T) (*service.FareDisputeService, repository.TripRepository, *models.Trip, *disputeNotifier, *eventRecorder) {
+	mem := utils.NewMemoryFixture(t)
+	trips := mem.Trips
+	fare := 25.0
+	completedAt := time.Now()
+	trip := mem.NewTrip(mem.NewPassenger(), nil, models.TripStatusCompleted, func(trip *models.Trip) {
+		trip.FareAmount = &fare
+		trip.RequestedAt = completedAt.Add(-30 * time.Minute)
+		trip.CompletedAt = &completedAt
+		trip.CreatedAt, trip.UpdatedAt = completedAt, completedAt
+	})
+
+	notifier := &disputeNotifier{}
+	events := &eventRecorder{}
+	svc := service.NewFareDisputeService(memory.NewFareDisputeRepository(mem.Store), trips, notifier, events, mem.Logger)
+	return svc, trips, trip, notifier, events
+}
+
+func TestFareDisputeService_ResolveAdjustsFare(t *testing.T) {
+	svc, trips, trip, notifier, events := newTestFareDisputeService(t)
+	ctx := context.Background()
+
+	requested := 18.0
+	dispute, err := svc.OpenDispute(ctx, trip.ID.String(), trip.PassengerID.String(), "Driver took a longer route", &requested)
+	require.NoError(t, err)
+	assert.Equal(t, models.FareDisputeOpen, dispute.Status)
+	assert.Equal(t, 25.0, dispute.OriginalFare)
+
+	// Disputes are reviewed before they are resolved
+	_, err = svc.Resolve(ctx, dispute.ID.String(), "admin@example.com", 18, "")
+	assert.ErrorIs(t, err, models.ErrInvalidStatusTransition)
+
+	_, err = svc.StartReview(ctx, dispute.ID.String(), "admin@example.com")
+	require.NoError(t, err)
+
+	resolved, err := svc.Resolve(ctx, dispute.ID.String(), "admin@example.com", 18.004, "Refunded the detour")
+	require.NoError(t, err)
+	assert.Equal(t, models.FareDisputeResolved, resolved.Status)
+	require.NotNil(t, resolved.AdjustedFare)
+	assert.Equal(t, 18.0, *resolved.AdjustedFare)
+	assert.NotNil(t, resolved.ClosedAt)
+
+	stored, err := trips.GetByID(ctx, trip.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 18.0, *stored.FareAmount)
+
+	adjustments, err := svc.ListAdjustments(ctx, trip.ID.String())
+	require.NoError(t, err)
+	require.Len(t, adjustments, 1)
+	assert.Equal(t, 25.0, adjustments[0].PreviousFare)
+	assert.Equal(t, 18.0, adjustments[0].NewFare)
+	assert.Equal(t, -7.0, adjustments[0].Amount)
+	assert.Equal(t, dispute.ID, adjustments[0].DisputeID)
+
+	assert.Equal(t, []models.WebhookEvent{models.WebhookEventFareDisputeResolved}, notifier.events)
+	assert.Equal(t, []float64{18}, notifier.fares)
+
+	assert.Equal(t, []string{"fare_dispute_opened", "fare_dispute_review_started", "fare_dispute_resolved"}, events.types())
+	for _, event := range events.events {
+		assert.Equal(t, models.EventCategoryBusiness, event.EventCategory)
+		assert.Equal(t, dispute.ID, *event.EntityID)
+	}
+
+	// Closed disputes can't be decided again
+	_, err = svc.Reject(ctx, dispute.ID.String(), "admin@example.com", "Changed my mind")
+	assert.ErrorIs(t, err, models.ErrInvalidStatusTransition)
+}
+
+func TestFareDisputeService_RejectLeavesFareUnchanged(t *testing.T) {
+	svc, trips, trip, notifier, _ := newTestFareDisputeService(t)
+	ctx := context.Background()
+
+	dispute, err := svc.OpenDispute(ctx, trip.ID.String(), trip.PassengerID.String(), "Too expensive", nil)
+	require.NoError(t, err)
+
+	var validationErr *models.ValidationError
+	_, err = svc.Reject(ctx, dispute.ID.String(), "admin@example.com", "")
+	assert.ErrorAs(t, err, &validationErr, "a rejection note is required")
+
+	rejected, err := svc.Reject(ctx, dispute.ID.String(), "admin@example.com", "Fare matches the route taken")
+	require.NoError(t, err)
+	assert.Equal(t, models.FareDisputeRejected, rejected.Status)
+	assert.Nil(t, rejected.AdjustedFare)
+
+	stored, err := trips.GetByID(ctx, trip.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 25.0, *stored.FareAmount)
+
+	adjustments, err := svc.ListAdjustments(ctx, trip.ID.String())
+	require.NoError(t, err)
+	assert.Empty(t, adjustments)
+	assert.Equal(t, []models.WebhookEvent{models.WebhookEventFareDisputeRejected}, notifier.events)
+
+	// A rejected dispute no longer blocks a new one
+	_, err = svc.OpenDispute(ctx, trip.ID.String(), trip.PassengerID.String(), "Still too expensive", nil)
+	assert.NoError(t, err)
+}
+
+func TestFareDisputeService_OpenDisputeChecks(t *testing.T) {
+	svc, trips, trip, _, _ := newTestFareDisputeService(t)
+	ctx := context.Background()
+
+	_, err := svc.OpenDispute(ctx, trip.ID.String(), uuid.NewString(), "Not my trip", nil)
+	assert.ErrorIs(t, err, models.ErrUnauthorizedOperation)
+
+	var validationErr *models.ValidationError
+	_, err = svc.OpenDispute(ctx, trip.ID.String(), trip.PassengerID.String(), "", nil)
+	assert.ErrorAs(t, err, &validationErr)
+
+	_, err = svc.OpenDispute(ctx, trip.ID.String(), trip.PassengerID.String(), "Overcharged", nil)
+	require.NoError(t, err)
+	_, err = svc.OpenDispute(ctx, trip.ID.String(), trip.PassengerID.String(), "Overcharged again", nil)
+	assert.ErrorIs(t, err, models.ErrDisputeAlreadyOpen)
+
+	ongoing := *trip
+	ongoing.ID = uuid.New()
+	ongoing.Status = models.TripStatusInProgress
+	require.NoError(t, trips.Create(ctx, &ongoing))
+	_, err = svc.OpenDispute(ctx, ongoing.ID.String(), trip.PassengerID.String(), "Overcharged", nil)
+	assert.ErrorIs(t, err, models.ErrTripNotDisputable)
+
+	var notFound *models.NotFoundError
+	_, err = svc.OpenDispute(ctx, uuid.NewString(), trip.PassengerID.String(), "Overcharged", nil)
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestFareDisputeService_ListDisputes(t *testing.T) {
+	svc, _, trip, _, _ := newTestFareDisputeService(t)
+	ctx := context.Background()
+
+	first, err := svc.OpenDispute(ctx, trip.ID.String(), trip.PassengerID.String(), "Overcharged", nil)
+	require.NoError(t, err)
+	_, err = svc.Reject(ctx, first.ID.String(), "admin@example.com", "Fare is correct")
+	require.NoError(t, err)
+	second, err := svc.OpenDispute(ctx, trip.ID.String(), trip.PassengerID.String(), "Overcharged", nil)
+	require.NoError(t, err)
+
+	open, total, err := svc.ListDisputes(ctx, models.FareDisputeFilter{Status: models.FareDisputeOpen, Limit: 20})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, open, 1)
+	assert.Equal(t, second.ID, open[0].ID)
+
+	all, total, err := svc.ListDisputes(ctx, models.FareDisputeFilter{TripID: &trip.ID, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, all, 1)
+	assert.Equal(t, first.ID, all[0].ID, "disputes are listed oldest first")
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fleetFixture is a fleet service over in-memory repositories with a fleet of two drivers,
+// one of them on a trip, and a driver of another fleet
+type fleetFixture struct {
+	svc       *service.FleetService
+	drivers   repository.DriverRepository
+	trips     repository.TripRepository
+	fleet     *models.Fleet
+	apiKey    string
+	idle      *models.Driver
+	busy      *models.Driver
+	outsider  *models.Driver
+	passenger *models.Passenger
+}
+
+func newFleetFixture(t *testing.T) *fleetFixture {
+	ctx := context.Background()
+	mem := utils.NewMemoryFixture(t)
+	svc := service.NewFleetService(memory.NewFleetRepository(mem.Store), mem.Drivers, mem.Trips, mem.Logger)
+
+	fleet, apiKey, err := svc.CreateFleet(ctx, "Jakarta Cabs", "Asia/Jakarta")
+	require.NoError(t, err)
+	other, _, err := svc.CreateFleet(ctx, "Bandung Cabs", "")
+	require.NoError(t, err)
+
+	created := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	newDriver := func(n int, fleetID uuid.UUID, status models.DriverStatus) *models.Driver {
+		return mem.NewDriver(status, func(d *models.Driver) {
+			d.FleetID = &fleetID
+			d.CreatedAt = created.Add(time.Duration(n) * time.Minute)
+		})
+	}
+
+	return &fleetFixture{
+		svc:       svc,
+		drivers:   mem.Drivers,
+		trips:     mem.Trips,
+		fleet:     fleet,
+		apiKey:    apiKey,
+		idle:      newDriver(1, fleet.ID, models.DriverStatusOffline),
+		busy:      newDriver(2, fleet.ID, models.DriverStatusBusy),
+		outsider:  newDriver(3, other.ID, models.DriverStatusOffline),
+		passenger: mem.NewPassenger(),
+	}
+}
+
+// addTrip stores a trip of driver requested at requestedAt
+func (f *fleetFixture) addTrip(t *testing.T, driver *models.Driver, status models.TripStatus, requestedAt time.Time, fare *float64) *models.Trip {
+	driverID := driver.ID
+	trip := &models.Trip{
+		ID:                   uuid.New(),
+		PassengerID:          f.passenger.ID,
+		DriverID:             &driverID,
+		PickupLatitude:       -6.2,
+		PickupLongitude:      106.82,
+		DestinationLatitude:  -6.3,
+		DestinationLongitude: 106.85,
+		Status:               status,
+		FareAmount:           fare,
+		RequestedAt:          requestedAt,
+		CreatedAt:            requestedAt,
+		UpdatedAt:            requestedAt,
+	}
+	if status == models.TripStatusCompleted {
+		completed := requestedAt.Add(20 * time.Minute)
+		trip.CompletedAt = &completed
+	}
+	require.NoError(t, f.trips.Create(context.Background(), trip))
+	return trip
+}
+
+// readCSV parses CSV written by an export
+func readCSV(t *testing.T, write func(io.Writer) error) [][]string {
+	var buf bytes.Buffer
+	require.NoError(t, write(&buf))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestFleetService_Authenticate(t *testing.T) {
+	f := newFleetFixture(t)
+
+	fleet, err := f.svc.Authenticate(context.Background(), f.apiKey)
+	require.NoError(t, err)
+	assert.Equal(t, f.fleet.ID, fleet.ID)
+	assert.NotEqual(t, f.apiKey, fleet.APIKeyHash)
+
+	_, err = f.svc.Authenticate(context.Background(), "not-a-key")
+	assert.ErrorIs(t, err, models.ErrInvalidFleetAPIKey)
+}
+
+func TestFleetService_SetDailyRequestQuota(t *testing.T) {
+	f := newFleetFixture(t)
+	ctx := context.Background()
+	quota := int64(1000)
+
+	fleet, err := f.svc.SetDailyRequestQuota(ctx, f.fleet.ID.String(), &quota)
+	require.NoError(t, err)
+	assert.Equal(t, quota, *fleet.DailyRequestQuota)
+
+	// The quota follows the fleet's API key
+	authenticated, err := f.svc.Authenticate(ctx, f.apiKey)
+	require.NoError(t, err)
+	assert.Equal(t, quota, *authenticated.APIKey().DailyRequestQuota)
+
+	zero := int64(0)
+	_, err = f.svc.SetDailyRequestQuota(ctx, f.fleet.ID.String(), &zero)
+	var validation *models.ValidationError
+	assert.ErrorAs(t, err, &validation)
+
+	fleet, err = f.svc.SetDailyRequestQuota(ctx, f.fleet.ID.String(), nil)
+	require.NoError(t, err)
+	assert.Nil(t, fleet.DailyRequestQuota)
+
+	_, err = f.svc.SetDailyRequestQuota(ctx, uuid.New().String(), &quota)
+	var notFound *models.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestFleetService_ImportDriverStatuses(t *testing.T) {
+	f := newFleetFixture(t)
+	ctx := context.Background()
+
+	file := strings.Join([]string{
+		"status,driver_id,note",
+		"online," + f.idle.ID.String() + ",morning shift",
+		"offline," + f.busy.ID.String() + ",",
+		"online," + f.outsider.ID.String() + ",",
+		"online,not-a-uuid,",
+		"sleeping," + f.idle.ID.String() + ",",
+	}, "\n")
+
+	result, err := f.svc.ImportDriverStatuses(ctx, f.fleet, strings.NewReader(file))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+	assert.Equal(t, 4, result.Failed)
+
+	var errs []string
+	for _, row := range result.Rows {
+		errs = append(errs, row.Error)
+	}
+	assert.Equal(t, []string{
+		"",
+		"driver is on a trip",
+		"driver not found in fleet",
+		"driver_id must be a valid UUID",
+		"status must be online or offline",
+	}, errs)
+
+	idle, err := f.drivers.GetByID(ctx, f.idle.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.DriverStatusOnline, idle.Status)
+	busy, err := f.drivers.GetByID(ctx, f.busy.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.DriverStatusBusy, busy.Status)
+	outsider, err := f.drivers.GetByID(ctx, f.outsider.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.DriverStatusOffline, outsider.Status)
+}
+
+func TestFleetService_ImportDriverStatuses_RejectsMalformedFile(t *testing.T) {
+	f := newFleetFixture(t)
+
+	for name, file := range map[string]string{
+		"empty":          "",
+		"missing column": "driver_id\n" + f.idle.ID.String(),
+		"no rows":        "driver_id,status\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := f.svc.ImportDriverStatuses(context.Background(), f.fleet, strings.NewReader(file))
+			var validation *models.ValidationError
+			assert.ErrorAs(t, err, &validation)
+		})
+	}
+}
+
+func TestFleetService_ExportDriversRoundTrips(t *testing.T) {
+	f := newFleetFixture(t)
+	ctx := context.Background()
+
+	write, err := f.svc.ExportDrivers(ctx, f.fleet)
+	require.NoError(t, err)
+	records := readCSV(t, write)
+
+	require.Len(t, records, 3)
+	assert.Equal(t, "driver_id", records[0][0])
+	assert.Equal(t, f.idle.ID.String(), records[1][0])
+	assert.Equal(t, "offline", records[1][5])
+	assert.Equal(t, f.busy.ID.String(), records[2][0])
+
+	// Set the idle driver online in the export and import it back
+	records[1][5] = "online"
+	records = records[:2]
+	var buf bytes.Buffer
+	require.NoError(t, csv.NewWriter(&buf).WriteAll(records))
+	result, err := f.svc.ImportDriverStatuses(ctx, f.fleet, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Updated)
+
+	idle, err := f.drivers.GetByID(ctx, f.idle.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.DriverStatusOnline, idle.Status)
+}
+
+func TestFleetService_ExportTripsAndEarnings(t *testing.T) {
+	f := newFleetFixture(t)
+	ctx := context.Background()
+
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	fare := func(v float64) *float64 { return &v }
+
+	first := f.addTrip(t, f.idle, models.TripStatusCompleted, from.Add(time.Hour), fare(25000))
+	f.addTrip(t, f.idle, models.TripStatusCompleted, from.Add(2*time.Hour), fare(17500.5))
+	f.addTrip(t, f.idle, models.TripStatusCancelled, from.Add(3*time.Hour), nil)
+	f.addTrip(t, f.idle, models.TripStatusCompleted, to, fare(99999))                      // outside the period
+	f.addTrip(t, f.outsider, models.TripStatusCompleted, from.Add(time.Hour), fare(99999)) // another fleet
+
+	writeTrips, err := f.svc.ExportTrips(ctx, f.fleet, from, to)
+	require.NoError(t, err)
+	trips := readCSV(t, writeTrips)
+	require.Len(t, trips, 4)
+	assert.Equal(t, []string{
+		first.ID.String(), f.idle.ID.String(), f.passenger.ID.String(), "completed",
+		"2024-06-01T01:00:00Z", "2024-06-01T01:20:00Z", "", "", "", "25000",
+	}, trips[1])
+
+	earnings, err := f.svc.GetEarnings(ctx, f.fleet, from, to)
+	require.NoError(t, err)
+	require.Len(t, earnings, 2)
+	assert.Equal(t, f.idle.ID, earnings[0].DriverID)
+	assert.Equal(t, 2, earnings[0].TripsCompleted)
+	assert.Equal(t, 1, earnings[0].TripsCancelled)
+	assert.InDelta(t, 42500.5, earnings[0].Earnings, 0.001)
+	assert.Equal(t, f.busy.ID, earnings[1].DriverID)
+	assert.Zero(t, earnings[1].Earnings)
+
+	writeEarnings, err := f.svc.ExportEarnings(ctx, f.fleet, from, to)
+	require.NoError(t, err)
+	rows := readCSV(t, writeEarnings)
+	assert.Equal(t, []string{f.idle.ID.String(), f.idle.VehiclePlate, "2", "1", "42500.5"}, rows[1])
+
+	_, err = f.svc.ExportTrips(ctx, f.fleet, to, from)
+	var validation *models.ValidationError
+	assert.ErrorAs(t, err, &validation)
+}
+
+func TestFleetService_DailyEarningsInFleetTimezone(t *testing.T) {
+	f := newFleetFixture(t)
+	ctx := context.Background()
+
+	from := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 7)
+	fare := func(v float64) *float64 { return &v }
+
+	// Jakarta is seven hours ahead of UTC, so 18:00 UTC is already the next day there
+	f.addTrip(t, f.idle, models.TripStatusCompleted, from.Add(2*time.Hour), fare(20000))
+	f.addTrip(t, f.idle, models.TripStatusCompleted, from.Add(18*time.Hour), fare(30000))
+	f.addTrip(t, f.busy, models.TripStatusCancelled, from.Add(19*time.Hour), nil)
+
+	earnings, err := f.svc.GetDailyEarnings(ctx, f.fleet, from, to, "")
+	require.NoError(t, err)
+	require.Len(t, earnings, 3)
+	assert.Equal(t, "2024-06-01", earnings[0].Date)
+	assert.Equal(t, f.idle.ID, earnings[0].DriverID)
+	assert.Equal(t, 20000.0, earnings[0].Earnings)
+	assert.Equal(t, "2024-06-02", earnings[1].Date)
+	assert.Equal(t, f.idle.ID, earnings[1].DriverID)
+	assert.Equal(t, "2024-06-02", earnings[2].Date)
+	assert.Equal(t, f.busy.ID, earnings[2].DriverID)
+	assert.Equal(t, 1, earnings[2].TripsCancelled)
+
+	// In UTC every trip is on the first day
+	write, err := f.svc.ExportDailyEarnings(ctx, f.fleet, from, to, "UTC")
+	require.NoError(t, err)
+	rows := readCSV(t, write)
+	require.Len(t, rows, 3)
+	assert.Equal(t, "date", rows[0][0])
+	assert.Equal(t, []string{"2024-06-01", f.idle.ID.String(), f.idle.VehiclePlate, "2", "0", "50000"}, rows[1])
+
+	_, err = f.svc.GetDailyEarnings(ctx, f.fleet, from, to, "Jakarta")
+	var validation *models.ValidationError
+	assert.ErrorAs(t, err, &validation)
+	_, _, err = f.svc.CreateFleet(ctx, "Surabaya Cabs", "Jakarta")
+	require.ErrorAs(t, err, &validation)
+	assert.Equal(t, "timezone", validation.Field)
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/forecast"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestForecastService creates a forecast service over trips requested every hour for the
+// given number of days, in two zones: one trip an hour picked up in Central Jakarta (qqguw)
+// and, during the evening peak, two more from South Jakarta
+func newTestForecastService(t *testing.T, days int) *service.ForecastService {
+	ctx := context.Background()
+	mem := utils.NewMemoryFixture(t)
+	trips := mem.Trips
+	passenger := mem.NewPassenger()
+
+	request := func(at time.Time, lat, lng float64) {
+		require.NoError(t, trips.Create(ctx, &models.Trip{
+			ID:                   uuid.New(),
+			PassengerID:          passenger.ID,
+			PickupLatitude:       lat,
+			PickupLongitude:      lng,
+			DestinationLatitude:  -6.17,
+			DestinationLongitude: 106.82,
+			Status:               models.TripStatusCompleted,
+			RequestedAt:          at,
+			CreatedAt:            at,
+			UpdatedAt:            at,
+		}))
+	}
+
+	end := time.Now().UTC().Truncate(time.Hour)
+	for at := end.Add(-time.Duration(days) * 24 * time.Hour); at.Before(end); at = at.Add(time.Hour) {
+		request(at.Add(10*time.Minute), -6.2, 106.82)
+		if hour := at.Hour(); hour >= 17 && hour <= 19 {
+			request(at.Add(20*time.Minute), -6.26, 106.81)
+			request(at.Add(30*time.Minute), -6.26, 106.81)
+		}
+	}
+
+	return service.NewForecastService(trips, config.DefaultForecastConfig(), config.DefaultReportingConfig())
+}
+
+func TestForecastService_ForecastsZoneDemand(t *testing.T) {
+	svc := newTestForecastService(t, 7)
+	ctx := context.Background()
+
+	result, err := svc.Forecast(ctx, "qqguw", 12, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, forecast.MethodHoltWinters, result.Method)
+	assert.Equal(t, 7*24, result.HistoryTrips, "only trips picked up in the zone are counted")
+	require.Len(t, result.Points, 12)
+	assert.Equal(t, time.Hour, result.Points[1].Timestamp.Sub(result.Points[0].Timestamp))
+	for _, point := range result.Points {
+		assert.InDelta(t, 1, point.Demand, 0.05)
+	}
+	require.NotNil(t, result.Backtest)
+	assert.Equal(t, 12, result.Backtest.Horizon)
+	assert.Less(t, result.Backtest.MAE, 0.05)
+	require.NotNil(t, result.Backtest.MAPE)
+
+	all, err := svc.Forecast(ctx, "", 24, forecast.MethodMovingAverage, "")
+	require.NoError(t, err)
+	assert.Empty(t, all.Zone)
+	assert.Equal(t, 7*24+7*3*2, all.HistoryTrips)
+	assert.InDelta(t, 1.25, all.Points[0].Demand, 0.01, "moving average of the last day")
+}
+
+func TestForecastService_Errors(t *testing.T) {
+	svc := newTestForecastService(t, 1)
+	ctx := context.Background()
+
+	var validationErr *models.ValidationError
+	_, err := svc.Forecast(ctx, "qqguw", 0, "", "")
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = svc.Forecast(ctx, "qqguw", 24, "arima", "")
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = svc.Forecast(ctx, "not-a-zone", 24, "", "")
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = svc.Forecast(ctx, "qqguw", 24, "", "Mars/Olympus_Mons")
+	assert.ErrorAs(t, err, &validationErr)
+
+	// One day of history is enough for a moving average but not for Holt-Winters
+	result, err := svc.Forecast(ctx, "qqguw", 24, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, forecast.MethodMovingAverage, result.Method)
+	assert.Nil(t, result.Backtest, "backtesting needs history beyond the horizon")
+
+	// The error reports the hours of history actually found, not the length of the window
+	_, err = svc.Forecast(ctx, "qqguw", 24, forecast.MethodHoltWinters, "")
+	assert.ErrorIs(t, err, models.ErrInsufficientForecastHistory)
+	assert.ErrorContains(t, err, "holt_winters needs 48 hours, found 24")
+	_, err = svc.Forecast(ctx, "u4pru", 24, "", "")
+	assert.ErrorIs(t, err, models.ErrInsufficientForecastHistory, "no trips in the zone")
+	assert.ErrorContains(t, err, "moving_average needs 24 hours, found 0")
+}
+
+func TestForecastService_LocalHoursAndDays(t *testing.T) {
+	svc := newTestForecastService(t, 3)
+	ctx := context.Background()
+
+	// India is five and a half hours ahead of UTC, so its hours start on the half hour in UTC
+	result, err := svc.Forecast(ctx, "qqguw", 48, forecast.MethodMovingAverage, "Asia/Kolkata")
+	require.NoError(t, err)
+	assert.Equal(t, "Asia/Kolkata", result.Timezone)
+	for _, point := range result.Points {
+		assert.Equal(t, "Asia/Kolkata", point.Timestamp.Location().String())
+		assert.Zero(t, point.Timestamp.Minute())
+		assert.Equal(t, 30, point.Timestamp.UTC().Minute())
+	}
+
+	// 48 hours span two or three local dates, the first and last partial
+	require.GreaterOrEqual(t, len(result.Daily), 2)
+	hours := 0
+	for i, day := range result.Daily {
+		hours += day.Hours
+		if i > 0 {
+			assert.Greater(t, day.Date, result.Daily[i-1].Date)
+		}
+	}
+	assert.Equal(t, 48, hours)
+	assert.Equal(t, result.Points[0].Timestamp.Format("2006-01-02"), result.Daily[0].Date)
+}
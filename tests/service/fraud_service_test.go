@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fraudFixture is a fraud detection service over in-memory repositories with a passenger and a
+// driver on separate accounts
+type fraudFixture struct {
+	svc           *service.FraudService
+	trips         repository.TripRepository
+	sessions      repository.SessionRepository
+	passenger     *models.Passenger
+	driver        *models.Driver
+	passengerUser *models.User
+	driverUser    *models.User
+	metrics       *metricsRecorder
+	events        *eventRecorder
+}
+
+func newFraudFixture(t *testing.T) *fraudFixture {
+	mem := utils.NewMemoryFixture(t)
+	sessions := memory.NewSessionRepository(mem.Store)
+	passenger := mem.NewPassenger()
+	driver := mem.NewDriver(models.DriverStatusOnline)
+
+	metrics := &metricsRecorder{}
+	events := &eventRecorder{}
+	return &fraudFixture{
+		svc:           service.NewFraudService(memory.NewFraudSignalRepository(mem.Store), mem.Trips, mem.Drivers, mem.Passengers, sessions, config.DefaultFraudConfig(), events, metrics, mem.Logger),
+		trips:         mem.Trips,
+		sessions:      sessions,
+		passenger:     passenger,
+		driver:        driver,
+		passengerUser: mem.User(passenger.UserID),
+		driverUser:    mem.User(driver.UserID),
+		metrics:       metrics,
+		events:        events,
+	}
+}
+
+// trip stores a trip of the fixture's passenger and driver ending in status at the given time
+// and delivers it to the service as the event bus would. Completed trips drove distanceKm from
+// the pickup ten minutes before.
+func (f *fraudFixture) trip(t *testing.T, status models.TripStatus, at time.Time, distanceKm float64) *models.Trip {
+	matched, pickup := at.Add(-20*time.Minute), at.Add(-10*time.Minute)
+	trip := &models.Trip{
+		ID:                   uuid.New(),
+		PassengerID:          f.passenger.ID,
+		DriverID:             &f.driver.ID,
+		PickupLatitude:       -6.2,
+		PickupLongitude:      106.82,
+		DestinationLatitude:  -6.3,
+		DestinationLongitude: 106.85,
+		Status:               status,
+		MatchedAt:            &matched,
+		RequestedAt:          matched,
+		CreatedAt:            matched,
+		UpdatedAt:            at,
+	}
+	switch status {
+	case models.TripStatusCompleted:
+		trip.PickupAt, trip.CompletedAt, trip.DistanceKm = &pickup, &at, &distanceKm
+	case models.TripStatusCancelled:
+		trip.CancelledAt = &at
+	}
+	require.NoError(t, f.trips.Create(context.Background(), trip))
+
+	snapshot := *trip
+	f.svc.HandleMessage(bus.Message{Topic: bus.TopicTripStatus, Payload: &snapshot, PublishedAt: at})
+	return trip
+}
+
+// signals returns the signals raised by rule, newest first
+func (f *fraudFixture) signals(t *testing.T, rule models.FraudRule) []*models.FraudSignal {
+	signals, _, err := f.svc.ListSignals(context.Background(), models.FraudSignalFilter{Rule: rule, Limit: 100})
+	require.NoError(t, err)
+	return signals
+}
+
+func TestFraudService_FlagsImpossibleSpeed(t *testing.T) {
+	f := newFraudFixture(t)
+	now := time.Now()
+
+	// 10km in 10 minutes is 60 km/h; 60km is 360 km/h
+	f.trip(t, models.TripStatusCompleted, now, 10)
+	assert.Empty(t, f.signals(t, models.FraudRuleImpossibleSpeed))
+
+	trip := f.trip(t, models.TripStatusCompleted, now, 60)
+	signals := f.signals(t, models.FraudRuleImpossibleSpeed)
+	require.Len(t, signals, 1)
+	assert.Equal(t, models.FraudSeverityHigh, signals[0].Severity)
+	assert.Equal(t, models.UserTypeDriver, signals[0].SubjectType)
+	assert.Equal(t, f.driver.ID, signals[0].SubjectID)
+	assert.Equal(t, trip.ID, *signals[0].TripID)
+	var details map[string]float64
+	require.NoError(t, json.Unmarshal(signals[0].Details, &details))
+	assert.Equal(t, 360.0, details["speed_kmh"])
+
+	// The same trip delivered again is flagged once
+	snapshot := *trip
+	f.svc.HandleMessage(bus.Message{Topic: bus.TopicTripStatus, Payload: &snapshot, PublishedAt: now})
+	assert.Len(t, f.signals(t, models.FraudRuleImpossibleSpeed), 1)
+	assert.Equal(t, 2.0, f.metrics.metrics["fraud_rule_hits_total:high,impossible_speed"])
+	assert.Equal(t, []string{"fraud_signal_raised"}, f.events.types())
+}
+
+func TestFraudService_FlagsRepeatedCancellations(t *testing.T) {
+	f := newFraudFixture(t)
+	now := time.Now()
+
+	// Cancellations outside the window and before the match do not count
+	f.trip(t, models.TripStatusCancelled, now.Add(-48*time.Hour), 0)
+	unmatched := f.trip(t, models.TripStatusCancelled, now.Add(-time.Hour), 0)
+	unmatched.MatchedAt = nil
+	require.NoError(t, f.trips.Update(context.Background(), unmatched))
+	f.trip(t, models.TripStatusCancelled, now.Add(-30*time.Minute), 0)
+	f.trip(t, models.TripStatusCancelled, now.Add(-20*time.Minute), 0)
+	assert.Empty(t, f.signals(t, models.FraudRuleRepeatedCancellations))
+
+	f.trip(t, models.TripStatusCancelled, now, 0)
+	signals := f.signals(t, models.FraudRuleRepeatedCancellations)
+	require.Len(t, signals, 2)
+	subjects := map[models.UserType]uuid.UUID{}
+	for _, signal := range signals {
+		subjects[signal.SubjectType] = signal.SubjectID
+		assert.Equal(t, models.FraudSeverityMedium, signal.Severity)
+	}
+	assert.Equal(t, f.passenger.ID, subjects[models.UserTypePassenger])
+	assert.Equal(t, f.driver.ID, subjects[models.UserTypeDriver])
+
+	// Subjects awaiting review are not flagged again
+	f.trip(t, models.TripStatusCancelled, now.Add(time.Minute), 0)
+	assert.Len(t, f.signals(t, models.FraudRuleRepeatedCancellations), 2)
+}
+
+func TestFraudService_FlagsGPSTeleport(t *testing.T) {
+	f := newFraudFixture(t)
+	now := time.Now()
+	report := func(lat, lng float64, at time.Time) {
+		f.svc.HandleMessage(bus.Message{Topic: bus.TopicDriverLocation, Payload: &models.DriverLocationUpdate{
+			DriverID: f.driver.ID, Latitude: lat, Longitude: lng, At: at,
+		}})
+	}
+
+	// About 1.1km in a minute is 67 km/h, and a fast but short hop is GPS noise
+	report(-6.2, 106.82, now)
+	report(-6.21, 106.82, now.Add(time.Minute))
+	report(-6.2145, 106.82, now.Add(time.Minute+time.Second))
+	assert.Empty(t, f.signals(t, models.FraudRuleGPSTeleport))
+
+	// About 11km in 10 seconds; older pings are ignored
+	report(-6.3145, 106.82, now.Add(time.Minute+11*time.Second))
+	report(-6.0, 106.82, now)
+	signals := f.signals(t, models.FraudRuleGPSTeleport)
+	require.Len(t, signals, 1)
+	assert.Equal(t, f.driver.ID, signals[0].SubjectID)
+	assert.Nil(t, signals[0].TripID)
+	assert.Equal(t, models.FraudSeverityHigh, signals[0].Severity)
+}
+
+func TestFraudService_FlagsSelfReferral(t *testing.T) {
+	f := newFraudFixture(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	f.trip(t, models.TripStatusCompleted, now, 5)
+	assert.Empty(t, f.signals(t, models.FraudRuleSelfReferral))
+
+	// The driver and the passenger signed in on the same device
+	for i, user := range []*models.User{f.driverUser, f.passengerUser} {
+		require.NoError(t, f.sessions.Create(ctx, &models.Session{
+			ID:               uuid.New(),
+			UserID:           user.ID,
+			UserType:         user.UserType,
+			DeviceID:         "device-1",
+			AccessTokenHash:  "access-" + user.ID.String(),
+			RefreshTokenHash: "refresh-" + user.ID.String(),
+			AccessExpiresAt:  now.Add(time.Hour),
+			ExpiresAt:        now.Add(24 * time.Hour),
+			LastUsedAt:       now.Add(time.Duration(i) * time.Second),
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}))
+	}
+	trip := f.trip(t, models.TripStatusCompleted, now, 5)
+	signals := f.signals(t, models.FraudRuleSelfReferral)
+	require.Len(t, signals, 1)
+	assert.Equal(t, trip.ID, *signals[0].TripID)
+	assert.Equal(t, models.FraudSeverityHigh, signals[0].Severity)
+	var details map[string]string
+	require.NoError(t, json.Unmarshal(signals[0].Details, &details))
+	assert.Equal(t, "shared_device", details["reason"])
+	assert.Equal(t, "device-1", details["device_id"])
+}
+
+func TestFraudService_FlagsRepeatedPairs(t *testing.T) {
+	f := newFraudFixture(t)
+	now := time.Now()
+
+	for i := 4; i > 0; i-- {
+		f.trip(t, models.TripStatusCompleted, now.Add(-time.Duration(i)*time.Hour), 5)
+	}
+	assert.Empty(t, f.signals(t, models.FraudRuleSelfReferral))
+
+	f.trip(t, models.TripStatusCompleted, now, 5)
+	signals := f.signals(t, models.FraudRuleSelfReferral)
+	require.Len(t, signals, 1)
+	assert.Equal(t, models.FraudSeverityMedium, signals[0].Severity)
+	var details map[string]interface{}
+	require.NoError(t, json.Unmarshal(signals[0].Details, &details))
+	assert.Equal(t, "repeated_pair", details["reason"])
+	assert.Equal(t, 5.0, details["completed_trips"])
+}
+
+func TestFraudService_Review(t *testing.T) {
+	f := newFraudFixture(t)
+	ctx := context.Background()
+	f.trip(t, models.TripStatusCompleted, time.Now(), 60)
+	signal := f.signals(t, models.FraudRuleImpossibleSpeed)[0]
+
+	_, err := f.svc.Review(ctx, signal.ID.String(), models.FraudSignalOpen, "analyst@example.com", "")
+	var validation *models.ValidationError
+	assert.True(t, errors.As(err, &validation))
+
+	reviewed, err := f.svc.Review(ctx, signal.ID.String(), models.FraudSignalConfirmed, "analyst@example.com", "  Odometer spoofed  ")
+	require.NoError(t, err)
+	assert.Equal(t, models.FraudSignalConfirmed, reviewed.Status)
+	assert.Equal(t, "Odometer spoofed", *reviewed.ReviewNote)
+	assert.NotNil(t, reviewed.ReviewedAt)
+
+	stored, err := f.svc.GetSignal(ctx, signal.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.FraudSignalConfirmed, stored.Status)
+	assert.Equal(t, 1.0, f.metrics.metrics["fraud_signal_reviews_total:confirmed,impossible_speed"])
+	assert.Contains(t, f.events.types(), "fraud_signal_confirmed")
+
+	// Decisions are final
+	_, err = f.svc.Review(ctx, signal.ID.String(), models.FraudSignalDismissed, "analyst@example.com", "")
+	assert.ErrorIs(t, err, models.ErrInvalidStatusTransition)
+
+	_, err = f.svc.Review(ctx, uuid.NewString(), models.FraudSignalDismissed, "analyst@example.com", "")
+	var notFound *models.NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+}
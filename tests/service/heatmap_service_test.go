@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestHeatmapService creates a heatmap service over three trips requested an hour before
+// end: two picked up in Central Jakarta (qqguw) and completed further south (qqgup), and one
+// still in progress picked up in South Jakarta
+func newTestHeatmapService(t *testing.T, end time.Time, cache *redis.Client) *service.HeatmapService {
+	ctx := context.Background()
+	mem := utils.NewMemoryFixture(t)
+	trips := mem.Trips
+	passenger := mem.NewPassenger()
+
+	requested := end.Add(-time.Hour)
+	completed := end.Add(-30 * time.Minute)
+	for _, trip := range []*models.Trip{
+		{PickupLatitude: -6.2, PickupLongitude: 106.82, Status: models.TripStatusCompleted, CompletedAt: &completed},
+		{PickupLatitude: -6.2, PickupLongitude: 106.83, Status: models.TripStatusCompleted, CompletedAt: &completed},
+		{PickupLatitude: -6.26, PickupLongitude: 106.81, Status: models.TripStatusInProgress},
+	} {
+		trip.ID = uuid.New()
+		trip.PassengerID = passenger.ID
+		trip.DestinationLatitude = -6.3
+		trip.DestinationLongitude = 106.85
+		trip.RequestedAt = requested
+		trip.CreatedAt = requested
+		trip.UpdatedAt = requested
+		require.NoError(t, trips.Create(ctx, trip))
+	}
+
+	return service.NewHeatmapService(trips, cache, config.DefaultHeatmapConfig(), mem.Logger)
+}
+
+func TestHeatmapService_CountsPickupsAndDropoffsPerGeohash(t *testing.T) {
+	end := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc := newTestHeatmapService(t, end, nil)
+
+	heatmap, err := svc.GetHeatmap(context.Background(), time.Time{}, end, "")
+	require.NoError(t, err)
+	assert.Equal(t, service.DefaultHeatmapBucket, heatmap.Bucket)
+	assert.Equal(t, end.Add(-24*time.Hour), heatmap.Start)
+	assert.Equal(t, int64(3), heatmap.Pickups)
+	assert.Equal(t, int64(2), heatmap.Dropoffs)
+
+	require.Len(t, heatmap.Cells, 3)
+	cells := make(map[string]models.HeatmapCell)
+	for _, cell := range heatmap.Cells {
+		cells[cell.Geohash] = cell
+	}
+	assert.Equal(t, int64(2), cells["qqguw"].Pickups)
+	assert.Zero(t, cells["qqguw"].Dropoffs)
+	assert.True(t, cells["qqguw"].Bounds.MinLat <= -6.2 && -6.2 < cells["qqguw"].Bounds.MaxLat)
+	assert.InDelta(t, -6.2, cells["qqguw"].CenterLat, 0.03)
+	assert.Equal(t, int64(2), cells["qqgup"].Dropoffs)
+	assert.Equal(t, int64(1), cells["qqguq"].Pickups)
+	assert.Equal(t, "qqguq", heatmap.Cells[2].Geohash, "busiest cells first")
+
+	coarse, err := svc.GetHeatmap(context.Background(), time.Time{}, end, "geohash3")
+	require.NoError(t, err)
+	require.Len(t, coarse.Cells, 1)
+	assert.Equal(t, "qqg", coarse.Cells[0].Geohash)
+	assert.Equal(t, int64(3), coarse.Cells[0].Pickups)
+	assert.Equal(t, int64(2), coarse.Cells[0].Dropoffs)
+
+	// Trips requested before the period still count their dropoffs within it
+	later, err := svc.GetHeatmap(context.Background(), end.Add(-45*time.Minute), end, "geohash3")
+	require.NoError(t, err)
+	assert.Zero(t, later.Pickups)
+	assert.Equal(t, int64(2), later.Dropoffs)
+}
+
+func TestHeatmapService_Validation(t *testing.T) {
+	end := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc := newTestHeatmapService(t, end, nil)
+	ctx := context.Background()
+
+	var validationErr *models.ValidationError
+	for _, bucket := range []string{"geohash", "geohash2", "geohash8", "h3", "5"} {
+		_, err := svc.GetHeatmap(ctx, time.Time{}, end, bucket)
+		assert.ErrorAs(t, err, &validationErr, bucket)
+	}
+	_, err := svc.GetHeatmap(ctx, end, end.Add(-time.Hour), "")
+	assert.ErrorAs(t, err, &validationErr, "start after end")
+	_, err = svc.GetHeatmap(ctx, end.Add(-60*24*time.Hour), end, "")
+	assert.ErrorAs(t, err, &validationErr, "period longer than the max range")
+}
+
+func TestHeatmapService_WorksWhileCacheUnavailable(t *testing.T) {
+	cache := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", DialTimeout: 50 * time.Millisecond, MaxRetries: -1})
+	defer cache.Close()
+
+	end := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	svc := newTestHeatmapService(t, end, cache)
+
+	heatmap, err := svc.GetHeatmap(context.Background(), time.Time{}, end, "")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), heatmap.Pickups)
+}
@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// incentiveFixture is an incentive service over in-memory repositories with one driver and passenger
+type incentiveFixture struct {
+	svc       *service.IncentiveService
+	trips     repository.TripRepository
+	driver    *models.Driver
+	passenger *models.Passenger
+	events    *eventRecorder
+}
+
+func newIncentiveFixture(t *testing.T) *incentiveFixture {
+	mem := utils.NewMemoryFixture(t)
+	driver := mem.NewDriver(models.DriverStatusOnline, func(d *models.Driver) {
+		d.Rating = 4.8
+	})
+	passenger := mem.NewPassenger()
+
+	events := &eventRecorder{}
+	return &incentiveFixture{
+		svc:       service.NewIncentiveService(memory.NewIncentiveRepository(mem.Store), mem.Drivers, events, mem.Logger),
+		trips:     mem.Trips,
+		driver:    driver,
+		passenger: passenger,
+		events:    events,
+	}
+}
+
+// completeTrip stores a trip completed by the fixture's driver and publishes its completion
+func (f *incentiveFixture) completeTrip(t *testing.T, completedAt time.Time) *models.Trip {
+	trip := &models.Trip{
+		ID:          uuid.New(),
+		PassengerID: f.passenger.ID,
+		DriverID:    &f.driver.ID,
+		Status:      models.TripStatusCompleted,
+		RequestedAt: completedAt.Add(-20 * time.Minute),
+		CompletedAt: &completedAt,
+		CreatedAt:   completedAt,
+		UpdatedAt:   completedAt,
+	}
+	require.NoError(t, f.trips.Create(context.Background(), trip))
+	require.NoError(t, f.svc.PublishTripEvent(context.Background(), models.WebhookEventTripCompleted, trip))
+	return trip
+}
+
+func (f *incentiveFixture) createCampaign(t *testing.T, requiredTrips int, startsAt, endsAt time.Time) *models.IncentiveCampaign {
+	campaign, err := f.svc.CreateCampaign(context.Background(), &models.IncentiveCampaign{
+		Name:          "Weekend rush",
+		RequiredTrips: requiredTrips,
+		BonusAmount:   15,
+		StartsAt:      startsAt,
+		EndsAt:        endsAt,
+	})
+	require.NoError(t, err)
+	return campaign
+}
+
+func TestIncentiveService_QuestCompletionRecordsPayout(t *testing.T) {
+	f := newIncentiveFixture(t)
+	ctx := context.Background()
+	now := time.Now()
+	campaign := f.createCampaign(t, 2, now.Add(-time.Hour), now.Add(time.Hour))
+
+	quests, err := f.svc.GetDriverQuests(ctx, f.driver.ID.String())
+	require.NoError(t, err)
+	require.Len(t, quests, 1)
+	assert.Equal(t, 2, quests[0].RemainingTrips)
+	assert.False(t, quests[0].Completed)
+
+	first := f.completeTrip(t, now)
+	// Replayed completion events are counted once
+	require.NoError(t, f.svc.PublishTripEvent(ctx, models.WebhookEventTripCompleted, first))
+
+	quests, err = f.svc.GetDriverQuests(ctx, f.driver.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 1, quests[0].CompletedTrips)
+	assert.Equal(t, 1, quests[0].RemainingTrips)
+	assert.Nil(t, quests[0].Payout)
+
+	f.completeTrip(t, now)
+	f.completeTrip(t, now)
+
+	quests, err = f.svc.GetDriverQuests(ctx, f.driver.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, 3, quests[0].CompletedTrips)
+	assert.Equal(t, 0, quests[0].RemainingTrips)
+	assert.True(t, quests[0].Completed)
+	require.NotNil(t, quests[0].Payout)
+
+	payouts, err := f.svc.ListDriverPayouts(ctx, f.driver.ID.String())
+	require.NoError(t, err)
+	require.Len(t, payouts, 1, "a driver is paid once per campaign")
+	assert.Equal(t, campaign.ID, payouts[0].CampaignID)
+	assert.Equal(t, 15.0, payouts[0].Amount)
+	assert.Equal(t, 2, payouts[0].TripCount)
+
+	assert.Equal(t, []string{"incentive_quest_completed"}, f.events.types())
+	assert.Equal(t, models.EventCategoryBusiness, f.events.events[0].EventCategory)
+	assert.Equal(t, f.driver.ID, *f.events.events[0].EntityID)
+}
+
+func TestIncentiveService_OnlyCountsTripsInRunningCampaigns(t *testing.T) {
+	f := newIncentiveFixture(t)
+	ctx := context.Background()
+	now := time.Now()
+	running := f.createCampaign(t, 1, now.Add(-time.Hour), now.Add(time.Hour))
+	paused := f.createCampaign(t, 1, now.Add(-time.Hour), now.Add(time.Hour))
+	_, err := f.svc.SetCampaignActive(ctx, paused.ID.String(), false)
+	require.NoError(t, err)
+
+	// Completed before the window opened
+	f.completeTrip(t, now.Add(-2*time.Hour))
+	payouts, err := f.svc.ListDriverPayouts(ctx, f.driver.ID.String())
+	require.NoError(t, err)
+	assert.Empty(t, payouts)
+
+	// Other trip events are ignored
+	f.completeTrip(t, now)
+	trip := f.completeTrip(t, now)
+	require.NoError(t, f.svc.PublishTripEvent(ctx, models.WebhookEventTripCancelled, trip))
+
+	payouts, err = f.svc.ListDriverPayouts(ctx, f.driver.ID.String())
+	require.NoError(t, err)
+	require.Len(t, payouts, 1)
+	assert.Equal(t, running.ID, payouts[0].CampaignID)
+
+	campaigns, err := f.svc.ListCampaigns(ctx, true)
+	require.NoError(t, err)
+	require.Len(t, campaigns, 1)
+	assert.Equal(t, running.ID, campaigns[0].ID)
+}
+
+func TestIncentiveService_ValidatesCampaigns(t *testing.T) {
+	f := newIncentiveFixture(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	for name, campaign := range map[string]*models.IncentiveCampaign{
+		"no name":          {RequiredTrips: 5, BonusAmount: 10, StartsAt: now, EndsAt: now.Add(time.Hour)},
+		"no trips":         {Name: "Quest", BonusAmount: 10, StartsAt: now, EndsAt: now.Add(time.Hour)},
+		"no bonus":         {Name: "Quest", RequiredTrips: 5, StartsAt: now, EndsAt: now.Add(time.Hour)},
+		"ends before open": {Name: "Quest", RequiredTrips: 5, BonusAmount: 10, StartsAt: now, EndsAt: now.Add(-time.Hour)},
+	} {
+		var validationErr *models.ValidationError
+		_, err := f.svc.CreateCampaign(ctx, campaign)
+		assert.ErrorAs(t, err, &validationErr, name)
+	}
+
+	var notFound *models.NotFoundError
+	_, err := f.svc.GetDriverQuests(ctx, uuid.NewString())
+	assert.ErrorAs(t, err, &notFound)
+	_, err = f.svc.SetCampaignActive(ctx, uuid.NewString(), false)
+	assert.ErrorAs(t, err, &notFound)
+}
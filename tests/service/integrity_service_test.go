@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lossyIntegrityRepository checksums the tables of the repository it wraps as if rows of a table
+// had been lost
+type lossyIntegrityRepository struct {
+	repository.IntegrityRepository
+	lost map[string]int64 // rows lost from the first hour of each table
+}
+
+func (r *lossyIntegrityRepository) ChecksumHours(ctx context.Context, table string, start, end time.Time) ([]*models.IntegrityChecksum, error) {
+	checksums, err := r.IntegrityRepository.ChecksumHours(ctx, table, start, end)
+	if err == nil && len(checksums) > 0 && r.lost[table] > 0 {
+		checksums[0].RowCount -= r.lost[table]
+		checksums[0].Checksum--
+	}
+	return checksums, err
+}
+
+func newIntegrityFixture(t *testing.T) (*service.IntegrityService, repository.ObservabilityRepository, *lossyIntegrityRepository, *eventRecorder) {
+	logger := utils.NewTestLogger(t)
+
+	store := memory.NewStore()
+	integrity := &lossyIntegrityRepository{IntegrityRepository: memory.NewIntegrityRepository(store), lost: map[string]int64{}}
+	events := &eventRecorder{}
+	svc := service.NewIntegrityService(integrity, config.DefaultIntegrityCheckConfig(), events, &metricsRecorder{}, logger)
+	return svc, memory.NewObservabilityRepository(store), integrity, events
+}
+
+func createIntegrityEventLogs(t *testing.T, repo repository.ObservabilityRepository, at time.Time, n int) {
+	for i := 0; i < n; i++ {
+		require.NoError(t, repo.CreateEventLog(context.Background(), &models.EventLog{
+			ID:            uuid.New(),
+			EventType:     "trip_requested",
+			EventCategory: models.EventCategoryBusiness,
+			Severity:      models.EventSeverityInfo,
+			Message:       "Trip requested",
+			Timestamp:     at,
+			CreatedAt:     at,
+		}))
+	}
+}
+
+func TestIntegrityService_DetectsLostRows(t *testing.T) {
+	ctx := context.Background()
+	svc, observability, integrity, events := newIntegrityFixture(t)
+
+	hour := time.Now().UTC().Truncate(time.Hour)
+	createIntegrityEventLogs(t, observability, hour.Add(-3*time.Hour+time.Minute), 3)
+	createIntegrityEventLogs(t, observability, hour.Add(-2*time.Hour+time.Minute), 2)
+	createIntegrityEventLogs(t, observability, hour.Add(time.Minute), 4) // current hour, not finished
+
+	first, err := svc.Run(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, first.Previous)
+	assert.Equal(t, int64(5), first.Current.TotalRows)
+	assert.Equal(t, hour, first.Current.WindowEnd)
+	assert.Equal(t, hour.Add(-24*time.Hour), first.Current.WindowStart)
+	assert.Empty(t, first.Differences)
+
+	// A late write to a checked hour is not a loss
+	createIntegrityEventLogs(t, observability, hour.Add(-2*time.Hour+2*time.Minute), 1)
+	second, err := svc.Run(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, second.Previous)
+	assert.Equal(t, first.Current.ID, second.Previous.ID)
+	assert.Equal(t, int64(0), second.RowsMissing)
+	require.Len(t, second.Differences, 1)
+	assert.Equal(t, models.IntegrityRowsAdded, second.Differences[0].Kind)
+	assert.Equal(t, hour.Add(-2*time.Hour), second.Differences[0].Hour)
+	assert.Empty(t, events.types())
+
+	integrity.lost["event_logs"] = 2
+	third, err := svc.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), third.RowsMissing)
+	require.Len(t, third.Differences, 1)
+	assert.Equal(t, models.IntegrityDifference{
+		Table:         "event_logs",
+		Hour:          hour.Add(-3 * time.Hour),
+		Kind:          models.IntegrityRowsMissing,
+		PreviousCount: 3,
+		CurrentCount:  1,
+	}, *third.Differences[0])
+	assert.Equal(t, []string{"observability_rows_missing"}, events.types())
+
+	// Comparing again gives the same result as the run
+	compared, err := svc.Compare(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, third.Current.ID, compared.Current.ID)
+	assert.Equal(t, int64(2), compared.RowsMissing)
+
+	compared, err = svc.Compare(ctx, second.Current.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, first.Current.ID, compared.Previous.ID)
+	assert.Len(t, compared.Differences, 1)
+
+	runs, err := svc.ListRuns(ctx, 2)
+	require.NoError(t, err)
+	require.Len(t, runs, 2)
+	assert.Equal(t, third.Current.ID, runs[0].ID)
+}
+
+func TestIntegrityService_CompareWithoutRuns(t *testing.T) {
+	svc, _, _, _ := newIntegrityFixture(t)
+
+	_, err := svc.Compare(context.Background(), "")
+	var notFound *models.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	_, err = svc.Compare(context.Background(), uuid.NewString())
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestCompareIntegrityChecksums_OverlappingWindows(t *testing.T) {
+	hour := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	previous := &models.IntegrityRun{ID: uuid.New(), WindowStart: hour.Add(-3 * time.Hour), WindowEnd: hour}
+	current := &models.IntegrityRun{ID: uuid.New(), WindowStart: hour.Add(-2 * time.Hour), WindowEnd: hour.Add(time.Hour)}
+
+	sum := func(table string, h time.Time, rows, checksum int64) *models.IntegrityChecksum {
+		return &models.IntegrityChecksum{TableName: table, Hour: h, RowCount: rows, Checksum: checksum}
+	}
+	previousSums := []*models.IntegrityChecksum{
+		sum("event_logs", hour.Add(-3*time.Hour), 9, 90), // outside the current window
+		sum("event_logs", hour.Add(-2*time.Hour), 5, 50),
+		sum("actor_messages", hour.Add(-time.Hour), 4, 40),
+		sum("system_metrics", hour.Add(-time.Hour), 2, 20),
+	}
+	currentSums := []*models.IntegrityChecksum{
+		sum("event_logs", hour.Add(-2*time.Hour), 5, 50),
+		sum("actor_messages", hour.Add(-time.Hour), 4, 41),
+		sum("traditional_logs", hour.Add(-time.Hour), 1, 10),
+		sum("traditional_logs", hour, 7, 70), // outside the previous window
+	}
+
+	comparison := models.CompareIntegrityChecksums(previous, current, previousSums, currentSums)
+	assert.Equal(t, 2*len(models.IntegrityTables), comparison.HoursCompared)
+	assert.Equal(t, int64(2), comparison.RowsMissing)
+	require.Len(t, comparison.Differences, 3)
+	assert.Equal(t, "actor_messages", comparison.Differences[0].Table)
+	assert.Equal(t, models.IntegrityRowsReplaced, comparison.Differences[0].Kind)
+	assert.Equal(t, "system_metrics", comparison.Differences[1].Table)
+	assert.Equal(t, models.IntegrityRowsMissing, comparison.Differences[1].Kind)
+	assert.Equal(t, int64(0), comparison.Differences[1].CurrentCount)
+	assert.Equal(t, "traditional_logs", comparison.Differences[2].Table)
+	assert.Equal(t, models.IntegrityRowsAdded, comparison.Differences[2].Kind)
+}
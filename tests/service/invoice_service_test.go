@@ -0,0 +1,245 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/storage"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyStore is a file store whose writes fail while failing is set
+type flakyStore struct {
+	storage.Store
+	failing bool
+}
+
+func (s *flakyStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	if s.failing {
+		return errors.New("storage unavailable")
+	}
+	return s.Store.Put(ctx, key, data, contentType)
+}
+
+// invoiceFixture is an invoice service over in-memory repositories with a corporate account and
+// a fleet that both had trips in the previous month, and an account opened after it
+type invoiceFixture struct {
+	svc      *service.InvoiceService
+	invoices repository.InvoiceRepository
+	store    *flakyStore
+	month    string
+	account  *models.CorporateAccount
+	fleet    *models.Fleet
+}
+
+func newInvoiceFixture(t *testing.T) *invoiceFixture {
+	ctx := context.Background()
+	mem := utils.NewMemoryFixture(t)
+	logger := mem.Logger
+	accounts := memory.NewCorporateAccountRepository(mem.Store)
+	fleets := memory.NewFleetRepository(mem.Store)
+	invoices := memory.NewInvoiceRepository(mem.Store)
+
+	local, err := storage.NewLocalStore(t.TempDir(), "secret")
+	require.NoError(t, err)
+	store := &flakyStore{Store: local}
+
+	now := time.Now().UTC()
+	start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0)
+	opened := start.AddDate(0, 0, -1)
+
+	account := &models.CorporateAccount{ID: uuid.New(), Name: "Acme", CreatedAt: opened, UpdatedAt: opened}
+	require.NoError(t, accounts.Create(ctx, account))
+	late := &models.CorporateAccount{ID: uuid.New(), Name: "Initech", CreatedAt: now, UpdatedAt: now}
+	require.NoError(t, accounts.Create(ctx, late))
+	fleet := &models.Fleet{ID: uuid.New(), Name: "Jakarta Cabs", APIKeyHash: "hash", Timezone: "UTC", CreatedAt: opened, UpdatedAt: opened}
+	require.NoError(t, fleets.Create(ctx, fleet))
+
+	passenger := mem.NewPassenger()
+	driver := mem.NewDriver(models.DriverStatusOffline, func(d *models.Driver) {
+		d.FleetID = &fleet.ID
+		d.CreatedAt = opened
+	})
+
+	addTrip := func(status models.TripStatus, fare float64, at time.Time, charged bool) {
+		trip := mem.NewTrip(passenger, driver, status, func(trip *models.Trip) {
+			trip.RequestedAt, trip.CreatedAt, trip.UpdatedAt = at, at, at
+			if status == models.TripStatusCompleted {
+				trip.FareAmount = &fare
+				completed := at.Add(20 * time.Minute)
+				trip.CompletedAt = &completed
+			}
+		})
+		if charged {
+			charge := &models.CorporateTripCharge{TripID: trip.ID, AccountID: account.ID, PassengerID: passenger.ID, EstimatedFare: fare, CreatedAt: at}
+			require.NoError(t, accounts.ChargeTrip(ctx, charge, nil, start, start.AddDate(0, 1, 0)))
+		}
+	}
+	addTrip(models.TripStatusCompleted, 12.5, start.Add(24*time.Hour), true)
+	addTrip(models.TripStatusCancelled, 9, start.Add(48*time.Hour), true)
+	addTrip(models.TripStatusCompleted, 20, start.Add(72*time.Hour), false)
+	// Trips of the current month are left out
+	addTrip(models.TripStatusCompleted, 30, now, true)
+
+	cfg := config.DefaultInvoiceConfig()
+	cfg.MaxAttempts = 2
+	svc := service.NewInvoiceService(
+		invoices,
+		accounts,
+		fleets,
+		service.NewCorporateAccountService(accounts, mem.Passengers, logger),
+		service.NewFleetService(fleets, mem.Drivers, mem.Trips, logger),
+		store,
+		cfg,
+		time.Minute,
+		logger,
+	)
+
+	return &invoiceFixture{
+		svc:      svc,
+		invoices: invoices,
+		store:    store,
+		month:    start.Format(models.CorporateStatementMonthLayout),
+		account:  account,
+		fleet:    fleet,
+	}
+}
+
+// file reads a stored invoice file
+func (f *invoiceFixture) file(t *testing.T, key *string) []byte {
+	require.NotNil(t, key)
+	object, err := f.store.Get(context.Background(), *key)
+	require.NoError(t, err)
+	defer object.Body.Close()
+	data, err := io.ReadAll(object.Body)
+	require.NoError(t, err)
+	return data
+}
+
+// invoiceOf returns the invoice of a party within a run
+func invoiceOf(t *testing.T, run *models.InvoiceRun, partyID uuid.UUID) *models.Invoice {
+	for _, invoice := range run.Invoices {
+		if invoice.PartyID == partyID {
+			return invoice
+		}
+	}
+	t.Fatalf("no invoice for party %s", partyID)
+	return nil
+}
+
+func TestInvoiceService_Generate(t *testing.T) {
+	f := newInvoiceFixture(t)
+	ctx := context.Background()
+
+	run, err := f.svc.Generate(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, f.month, run.Month)
+	assert.Equal(t, 2, run.Generated)
+	assert.Zero(t, run.Failed)
+	// The account opened after the month has no invoice
+	require.Len(t, run.Invoices, 2)
+
+	corporate := invoiceOf(t, run, f.account.ID)
+	assert.Equal(t, models.InvoicePartyCorporateAccount, corporate.PartyType)
+	assert.Equal(t, models.InvoiceGenerated, corporate.Status)
+	assert.Equal(t, 1, corporate.Attempts)
+	assert.Equal(t, 2, corporate.Trips)
+	assert.Equal(t, 12.5, corporate.TotalAmount)
+	assert.Contains(t, corporate.CSVURL, storage.LocalFilesPath+"invoices/"+f.month+"/corporate_account/")
+	assert.NotEmpty(t, corporate.PDFURL)
+
+	records, err := csv.NewReader(bytes.NewReader(f.file(t, corporate.CSVKey))).ReadAll()
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"passenger_id", "trips", "amount"}, {records[1][0], "2", "12.50"}}, records)
+
+	pdf := f.file(t, corporate.PDFKey)
+	assert.Contains(t, string(pdf[:8]), "%PDF-1.4")
+	assert.Contains(t, string(pdf), "Total due:")
+
+	fleet := invoiceOf(t, run, f.fleet.ID)
+	assert.Equal(t, models.InvoicePartyFleet, fleet.PartyType)
+	assert.Equal(t, "Jakarta Cabs", fleet.PartyName)
+	// The fleet's driver drove both completed trips of the month
+	assert.Equal(t, 2, fleet.Trips)
+	assert.Equal(t, 32.5, fleet.TotalAmount)
+
+	// Generated invoices are not generated twice
+	run, err = f.svc.Generate(ctx, f.month)
+	require.NoError(t, err)
+	assert.Zero(t, run.Generated)
+	assert.Equal(t, 2, run.Skipped)
+
+	listed, total, err := f.svc.List(ctx, models.InvoiceFilter{Month: f.month, PartyType: models.InvoicePartyFleet, Limit: 10})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	require.Len(t, listed, 1)
+	assert.NotEmpty(t, listed[0].CSVURL)
+}
+
+func TestInvoiceService_GenerateRecordsFailures(t *testing.T) {
+	f := newInvoiceFixture(t)
+	ctx := context.Background()
+	f.store.failing = true
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		run, err := f.svc.Generate(ctx, f.month)
+		require.NoError(t, err)
+		assert.Equal(t, 2, run.Failed)
+
+		invoice := invoiceOf(t, run, f.account.ID)
+		assert.Equal(t, models.InvoiceFailed, invoice.Status)
+		assert.Equal(t, attempt, invoice.Attempts)
+		require.NotNil(t, invoice.LastError)
+		assert.Contains(t, *invoice.LastError, "storage unavailable")
+		assert.Empty(t, invoice.CSVURL)
+	}
+
+	// Exhausted invoices are left for admins to retry
+	f.store.failing = false
+	run, err := f.svc.Generate(ctx, f.month)
+	require.NoError(t, err)
+	assert.Equal(t, 2, run.Skipped)
+
+	failed := invoiceOf(t, run, f.account.ID)
+	invoice, err := f.svc.Retry(ctx, failed.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.InvoiceGenerated, invoice.Status)
+	assert.Equal(t, 3, invoice.Attempts)
+	assert.Nil(t, invoice.LastError)
+	assert.NotEmpty(t, invoice.PDFURL)
+
+	stored, err := f.invoices.GetByID(ctx, failed.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.InvoiceGenerated, stored.Status)
+}
+
+func TestInvoiceService_GenerateValidatesMonth(t *testing.T) {
+	f := newInvoiceFixture(t)
+	ctx := context.Background()
+
+	var validation *models.ValidationError
+	_, err := f.svc.Generate(ctx, "September")
+	assert.True(t, errors.As(err, &validation))
+
+	// The current month is not over
+	_, err = f.svc.Generate(ctx, time.Now().UTC().Format(models.CorporateStatementMonthLayout))
+	assert.True(t, errors.As(err, &validation))
+
+	var notFound *models.NotFoundError
+	_, err = f.svc.Retry(ctx, uuid.New().String())
+	assert.True(t, errors.As(err, &notFound))
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/traditional"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newLocationIngester(t *testing.T, cfg config.LocationIngestConfig, drivers repository.DriverRepository) *service.LocationIngester {
+	logger := utils.NewTestLogger(t)
+	return service.NewLocationIngester(cfg, drivers, nil, traditional.NewTraditionalMonitor(logger, nil), logger)
+}
+
+// createLocationDriver stores an online driver at a known location
+func createLocationDriver(mem *utils.MemoryFixture) *models.Driver {
+	return mem.NewDriver(models.DriverStatusOnline, func(d *models.Driver) {
+		d.SetLocation(-6.2, 106.8)
+	})
+}
+
+func TestLocationIngester_WritesLatestLocationOfEachDriver(t *testing.T) {
+	mem := utils.NewMemoryFixture(t)
+	drivers := mem.Drivers
+	ingester := newLocationIngester(t, config.DefaultLocationIngestConfig(), drivers)
+	ctx := context.Background()
+	first, second := createLocationDriver(mem), createLocationDriver(mem)
+
+	at := time.Now()
+	for i, lat := range []float64{-6.21, -6.22, -6.23} {
+		require.NoError(t, ingester.Ingest(ctx, &models.DriverLocationUpdate{DriverID: first.ID, Latitude: lat, Longitude: 106.81, At: at.Add(time.Duration(i) * time.Second)}))
+	}
+	require.NoError(t, ingester.Ingest(ctx, &models.DriverLocationUpdate{DriverID: second.ID, Latitude: -6.3, Longitude: 106.9, At: at}))
+	// Arrived out of order, older than the buffered location
+	require.NoError(t, ingester.Ingest(ctx, &models.DriverLocationUpdate{DriverID: first.ID, Latitude: -6.5, Longitude: 106.5, At: at.Add(-time.Second)}))
+
+	stats := ingester.Stats()
+	assert.Equal(t, 2, stats.Pending)
+	assert.Equal(t, int64(5), stats.Received)
+	assert.Equal(t, int64(3), stats.Coalesced)
+
+	// Matching sees the latest location before it is written
+	latest := ingester.LatestLocations(ctx, []uuid.UUID{first.ID, uuid.New()})
+	require.Len(t, latest, 1)
+	assert.Equal(t, -6.23, latest[first.ID].Latitude)
+	stored, err := drivers.GetByID(ctx, first.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, -6.2, *stored.CurrentLatitude)
+
+	require.NoError(t, ingester.Flush(ctx))
+	stored, err = drivers.GetByID(ctx, first.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, -6.23, *stored.CurrentLatitude)
+	stored, err = drivers.GetByID(ctx, second.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, -6.3, *stored.CurrentLatitude)
+
+	stats = ingester.Stats()
+	assert.Zero(t, stats.Pending)
+	assert.Equal(t, int64(2), stats.Written)
+	assert.Equal(t, int64(1), stats.Flushes)
+	require.NotNil(t, stats.LastFlushAt)
+	assert.Empty(t, ingester.LatestLocations(ctx, []uuid.UUID{first.ID}), "written locations are no newer than the database's")
+}
+
+func TestLocationIngester_RejectsUnknownDrivers(t *testing.T) {
+	ingester := newLocationIngester(t, config.DefaultLocationIngestConfig(), memory.NewDriverRepository(memory.NewStore()))
+
+	err := ingester.Ingest(context.Background(), &models.DriverLocationUpdate{DriverID: uuid.New(), Latitude: -6.2, Longitude: 106.8, At: time.Now()})
+	var notFound *models.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+	assert.Zero(t, ingester.Stats().Pending)
+}
+
+func TestLocationIngester_KeepsLocationsOfFailedWrites(t *testing.T) {
+	drivers := &utils.MockDriverRepository{}
+	ingester := newLocationIngester(t, config.DefaultLocationIngestConfig(), drivers)
+	ctx := context.Background()
+	driverID := uuid.New()
+	drivers.On("GetByID", mock.Anything, driverID.String()).Return(&models.Driver{ID: driverID}, nil).Once()
+	drivers.On("UpdateLocations", mock.Anything, mock.Anything).Return(int64(0), errors.New("connection reset")).Once()
+
+	at := time.Now()
+	require.NoError(t, ingester.Ingest(ctx, &models.DriverLocationUpdate{DriverID: driverID, Latitude: -6.2, Longitude: 106.8, At: at}))
+	assert.Error(t, ingester.Flush(ctx))
+
+	stats := ingester.Stats()
+	assert.Equal(t, 1, stats.Pending)
+	assert.Equal(t, int64(1), stats.FailedFlushes)
+
+	// The next write has the location, and the driver is not looked up again
+	drivers.On("UpdateLocations", mock.Anything, mock.MatchedBy(func(updates []*models.DriverLocationUpdate) bool {
+		return len(updates) == 1 && updates[0].Latitude == -6.25
+	})).Return(int64(1), nil).Once()
+	require.NoError(t, ingester.Ingest(ctx, &models.DriverLocationUpdate{DriverID: driverID, Latitude: -6.25, Longitude: 106.8, At: at.Add(time.Second)}))
+	require.NoError(t, ingester.Flush(ctx))
+	assert.Equal(t, int64(1), ingester.Stats().Written)
+	drivers.AssertExpectations(t)
+}
+
+func TestLocationIngester_WritesOnIntervalAndWhenFull(t *testing.T) {
+	mem := utils.NewMemoryFixture(t)
+	drivers := mem.Drivers
+	ingester := newLocationIngester(t, config.LocationIngestConfig{Enabled: true, FlushInterval: time.Hour, MaxPending: 2, CacheTTL: time.Minute}, drivers)
+	require.NoError(t, ingester.Start(context.Background()))
+	ctx := context.Background()
+	first, second := createLocationDriver(mem), createLocationDriver(mem)
+
+	require.NoError(t, ingester.Ingest(ctx, &models.DriverLocationUpdate{DriverID: first.ID, Latitude: -6.21, Longitude: 106.81, At: time.Now()}))
+	require.NoError(t, ingester.Ingest(ctx, &models.DriverLocationUpdate{DriverID: second.ID, Latitude: -6.22, Longitude: 106.82, At: time.Now()}))
+	require.Eventually(t, func() bool { return ingester.Stats().Written == 2 }, time.Second, time.Millisecond)
+
+	// Stopping writes what is still buffered
+	require.NoError(t, ingester.Ingest(ctx, &models.DriverLocationUpdate{DriverID: first.ID, Latitude: -6.3, Longitude: 106.9, At: time.Now()}))
+	require.NoError(t, ingester.Stop())
+	stored, err := drivers.GetByID(ctx, first.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, -6.3, *stored.CurrentLatitude)
+}
+
+func TestRideService_MatchesOnLatestReportedLocations(t *testing.T) {
+	f := newRematchFixture(t, 2, false)
+	ctx := context.Background()
+	ingester := newLocationIngester(t, config.DefaultLocationIngestConfig(), f.drivers)
+	f.svc.SetLocationIngester(ingester)
+
+	// The only online driver is stored near the pickup but has since driven away
+	require.NoError(t, ingester.Ingest(ctx, &models.DriverLocationUpdate{DriverID: f.other.ID, Latitude: -7.25, Longitude: 112.75, At: time.Now()}))
+	pickup := models.Location{Latitude: f.trip.PickupLatitude, Longitude: f.trip.PickupLongitude}
+	dropoff := models.Location{Latitude: f.trip.DestinationLatitude, Longitude: f.trip.DestinationLongitude}
+	_, err := f.svc.RequestRide(ctx, f.trip.PassengerID.String(), pickup, dropoff, "Pickup", "Dropoff")
+	require.Error(t, err)
+	assert.Equal(t, models.DriverStatusOnline, f.driverStatus(t, f.other))
+
+	// And came back
+	require.NoError(t, ingester.Ingest(ctx, &models.DriverLocationUpdate{DriverID: f.other.ID, Latitude: -6.201, Longitude: 106.821, At: time.Now()}))
+	trip, err := f.svc.RequestRide(ctx, f.trip.PassengerID.String(), pickup, dropoff, "Pickup", "Dropoff")
+	require.NoError(t, err)
+	assert.Equal(t, f.other.ID, *trip.DriverID)
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trailFixture is a location trail service over in-memory repositories with one driver
+type trailFixture struct {
+	svc    *service.LocationTrailService
+	driver *models.Driver
+}
+
+func newTrailFixture(t *testing.T, cfg config.LocationTrailConfig) *trailFixture {
+	mem := utils.NewMemoryFixture(t)
+	driver := mem.NewDriver(models.DriverStatusOnline)
+
+	return &trailFixture{
+		svc:    service.NewLocationTrailService(memory.NewDriverLocationRepository(mem.Store), mem.Drivers, cfg, mem.Logger),
+		driver: driver,
+	}
+}
+
+// report delivers pings of the fixture's driver every interval from start, as the event bus would
+func (f *trailFixture) report(start time.Time, interval time.Duration, count int) {
+	for i := 0; i < count; i++ {
+		f.svc.HandleMessage(bus.Message{Topic: bus.TopicDriverLocation, Payload: &models.DriverLocationUpdate{
+			DriverID:  f.driver.ID,
+			Latitude:  -6.2 + float64(i)*0.001,
+			Longitude: 106.82,
+			At:        start.Add(time.Duration(i) * interval),
+		}})
+	}
+}
+
+func TestLocationTrailService_DownsamplesAgedPings(t *testing.T) {
+	f := newTrailFixture(t, config.DefaultLocationTrailConfig())
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	// Twenty minutes of pings every 10 seconds, two weeks, two days and one hour ago
+	weekOld := now.Add(-14 * 24 * time.Hour).Truncate(10 * time.Minute)
+	dayOld := now.Add(-2 * 24 * time.Hour).Truncate(10 * time.Minute)
+	recent := now.Add(-time.Hour).Truncate(10 * time.Minute)
+	for _, start := range []time.Time{weekOld, dayOld, recent} {
+		f.report(start, 10*time.Second, 120)
+	}
+
+	removed, err := f.svc.Downsample(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 120-2+120-20, removed)
+
+	trail, err := f.svc.GetTrail(ctx, f.driver.ID.String(), weekOld, weekOld.Add(20*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, trail.Pings, 2)
+	for _, ping := range trail.Pings {
+		assert.Equal(t, 600, ping.ResolutionSeconds)
+		assert.Equal(t, 60, ping.Samples)
+	}
+	// A downsampled ping is the last of its interval
+	assert.True(t, trail.Pings[0].RecordedAt.Equal(weekOld.Add(590*time.Second)))
+	assert.InDelta(t, -6.2+59*0.001, trail.Pings[0].Latitude, 1e-9)
+
+	trail, err = f.svc.GetTrail(ctx, f.driver.ID.String(), dayOld, dayOld.Add(20*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, trail.Pings, 20)
+	assert.Equal(t, 60, trail.Pings[0].ResolutionSeconds)
+	assert.Equal(t, 6, trail.Pings[0].Samples)
+
+	trail, err = f.svc.GetTrail(ctx, f.driver.ID.String(), recent, now)
+	require.NoError(t, err)
+	assert.Len(t, trail.Pings, 120)
+	assert.Equal(t, 0, trail.Pings[0].ResolutionSeconds)
+
+	// Downsampling again changes nothing
+	removed, err = f.svc.Downsample(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+}
+
+func TestLocationTrailService_GetTrail(t *testing.T) {
+	cfg := config.DefaultLocationTrailConfig()
+	cfg.MaxPoints = 5
+	f := newTrailFixture(t, cfg)
+	ctx := context.Background()
+	start := time.Now().Add(-time.Hour).Truncate(time.Second)
+	f.report(start, time.Second, 8)
+
+	trail, err := f.svc.GetTrail(ctx, f.driver.ID.String(), start.Add(time.Second), start.Add(4*time.Second))
+	require.NoError(t, err)
+	require.Len(t, trail.Pings, 3)
+	assert.False(t, trail.Truncated)
+	assert.True(t, trail.Pings[0].RecordedAt.Equal(start.Add(time.Second)))
+
+	trail, err = f.svc.GetTrail(ctx, f.driver.ID.String(), start, start.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Len(t, trail.Pings, 5)
+	assert.True(t, trail.Truncated)
+
+	trail, err = f.svc.GetTrail(ctx, f.driver.ID.String(), start.Add(-2*time.Hour), start.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.NotNil(t, trail.Pings)
+	assert.Empty(t, trail.Pings)
+
+	_, err = f.svc.GetTrail(ctx, f.driver.ID.String(), start, start)
+	var validation *models.ValidationError
+	assert.True(t, errors.As(err, &validation))
+
+	_, err = f.svc.GetTrail(ctx, uuid.NewString(), start, start.Add(time.Minute))
+	var notFound *models.NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/parquet"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/storage"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archivedSystemMetricColumns are the columns of archived system metrics
+var archivedSystemMetricColumns = []parquet.Column{
+	{Name: "id", Type: parquet.String},
+	{Name: "metric_name", Type: parquet.String},
+	{Name: "metric_type", Type: parquet.String},
+	{Name: "metric_value", Type: parquet.Double},
+	{Name: "labels", Type: parquet.String, Optional: true},
+	{Name: "actor_type", Type: parquet.String, Optional: true},
+	{Name: "actor_id", Type: parquet.String, Optional: true},
+	{Name: "timestamp", Type: parquet.Timestamp},
+	{Name: "created_at", Type: parquet.Timestamp},
+}
+
+type metricsArchiveFixture struct {
+	svc           *service.MetricsArchiveService
+	store         *storage.LocalStore
+	observability repository.ObservabilityRepository
+	traditional   repository.TraditionalRepository
+}
+
+func newMetricsArchiveFixture(t *testing.T) *metricsArchiveFixture {
+	logger := utils.NewTestLogger(t)
+	store, err := storage.NewLocalStore(t.TempDir(), "secret")
+	require.NoError(t, err)
+
+	memoryStore := memory.NewStore()
+	f := &metricsArchiveFixture{
+		store:         store,
+		observability: memory.NewObservabilityRepository(memoryStore),
+		traditional:   memory.NewTraditionalRepository(memoryStore),
+	}
+	cfg := config.DefaultMetricsArchiveConfig()
+	cfg.BackfillDays = 2
+	cfg.BatchSize = 1
+	f.svc = service.NewMetricsArchiveService(f.observability, f.traditional, store, cfg, logger)
+	return f
+}
+
+// systemMetric records a system metric at a time, changed by the options
+func (f *metricsArchiveFixture) systemMetric(t *testing.T, name string, at time.Time, options ...func(*models.SystemMetric)) *models.SystemMetric {
+	metric := &models.SystemMetric{
+		ID: uuid.New(), MetricName: name, MetricType: models.MetricTypeGauge, MetricValue: 2.5,
+		Timestamp: at, CreatedAt: at,
+	}
+	for _, option := range options {
+		option(metric)
+	}
+	require.NoError(t, f.observability.CreateSystemMetric(context.Background(), metric))
+	return metric
+}
+
+// read returns the archived file of a key
+func (f *metricsArchiveFixture) read(t *testing.T, key string) []byte {
+	object, err := f.store.Get(context.Background(), key)
+	require.NoError(t, err)
+	defer object.Body.Close()
+	data, err := io.ReadAll(object.Body)
+	require.NoError(t, err)
+	return data
+}
+
+func TestMetricsArchiveService_Archive_WritesDayPartitions(t *testing.T) {
+	f := newMetricsArchiveFixture(t)
+	ctx := context.Background()
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	actorType := models.ActorTypeDriver
+	actorID := "driver-1"
+	labelled := f.systemMetric(t, "actor_queue_size", day.Add(10*time.Hour), func(m *models.SystemMetric) {
+		m.Labels = json.RawMessage(`{"pool":"drivers"}`)
+		m.ActorType = &actorType
+		m.ActorID = &actorID
+	})
+	f.systemMetric(t, "active_actors", day)
+	f.systemMetric(t, "next_day", day.AddDate(0, 0, 1))
+	f.systemMetric(t, "previous_day", day.Add(-time.Second))
+	require.NoError(t, f.traditional.CreateTraditionalMetric(ctx, &models.TraditionalMetric{
+		ID: uuid.New(), MetricName: "http_requests_total", MetricType: models.MetricTypeCounter, MetricValue: 42,
+		ServiceName: "ride-hailing", InstanceID: "api-1", Timestamp: day.Add(time.Hour), CreatedAt: day.Add(time.Hour),
+	}))
+
+	require.NoError(t, f.svc.Archive(ctx, day.Add(15*time.Hour)))
+
+	file := f.read(t, "metrics/system_metrics/date=2024-03-01/metrics.parquet")
+	values, metadata := utils.ReadParquet(t, file, archivedSystemMetricColumns)
+	assert.Equal(t, int64(2), metadata[3], "only the metrics of the day are archived")
+	assert.Equal(t, []any{"actor_queue_size", "active_actors"}, values["metric_name"])
+	assert.Equal(t, []any{labelled.ID.String(), values["id"][1]}, values["id"])
+	assert.Equal(t, []any{"gauge", "gauge"}, values["metric_type"])
+	assert.Equal(t, []any{2.5, 2.5}, values["metric_value"])
+	assert.Equal(t, []any{`{"pool":"drivers"}`, nil}, values["labels"])
+	assert.Equal(t, []any{"driver", nil}, values["actor_type"])
+	assert.Equal(t, []any{"driver-1", nil}, values["actor_id"])
+	assert.Equal(t, []any{day.Add(10 * time.Hour), day}, values["timestamp"])
+
+	file = f.read(t, "metrics/traditional_metrics/date=2024-03-01/metrics.parquet")
+	_, metadata = utils.ReadParquet(t, file, []parquet.Column{
+		{Name: "id", Type: parquet.String},
+		{Name: "metric_name", Type: parquet.String},
+		{Name: "metric_type", Type: parquet.String},
+		{Name: "metric_value", Type: parquet.Double},
+		{Name: "labels", Type: parquet.String, Optional: true},
+		{Name: "service_name", Type: parquet.String},
+		{Name: "instance_id", Type: parquet.String},
+		{Name: "timestamp", Type: parquet.Timestamp},
+		{Name: "created_at", Type: parquet.Timestamp},
+	})
+	assert.Equal(t, int64(1), metadata[3])
+}
+
+func TestMetricsArchiveService_ArchiveDue_ArchivesMissingFinishedDays(t *testing.T) {
+	f := newMetricsArchiveFixture(t)
+	ctx := context.Background()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	yesterday := today.AddDate(0, 0, -1)
+	yesterdayKey := "metrics/system_metrics/date=" + yesterday.Format("2006-01-02") + "/metrics.parquet"
+
+	f.systemMetric(t, "active_actors", yesterday.Add(time.Hour))
+	f.systemMetric(t, "active_actors", today)
+
+	f.svc.ArchiveDue(ctx)
+	_, metadata := utils.ReadParquet(t, f.read(t, yesterdayKey), archivedSystemMetricColumns)
+	assert.Equal(t, int64(1), metadata[3])
+
+	// The day before is archived empty, and today is not archived until it is over
+	_, metadata = utils.ReadParquet(t, f.read(t, "metrics/system_metrics/date="+yesterday.AddDate(0, 0, -1).Format("2006-01-02")+"/metrics.parquet"), archivedSystemMetricColumns)
+	assert.Equal(t, int64(0), metadata[3])
+	_, err := f.store.Get(ctx, "metrics/system_metrics/date="+today.Format("2006-01-02")+"/metrics.parquet")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Archived days are kept as they are
+	f.systemMetric(t, "late", yesterday.Add(2*time.Hour))
+	f.svc.ArchiveDue(ctx)
+	_, metadata = utils.ReadParquet(t, f.read(t, yesterdayKey), archivedSystemMetricColumns)
+	assert.Equal(t, int64(1), metadata[3])
+}
@@ -0,0 +1,215 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// paymentFixture is a payment reconciliation service over in-memory repositories with a passenger
+type paymentFixture struct {
+	svc       *service.PaymentReconciliationService
+	trips     repository.TripRepository
+	payments  repository.PaymentRepository
+	passenger *models.Passenger
+	metrics   *metricsRecorder
+	events    *eventRecorder
+}
+
+func newPaymentFixture(t *testing.T) *paymentFixture {
+	mem := utils.NewMemoryFixture(t)
+	trips := mem.Trips
+	payments := memory.NewPaymentRepository(mem.Store)
+	metrics := &metricsRecorder{}
+	events := &eventRecorder{}
+	return &paymentFixture{
+		svc:       service.NewPaymentReconciliationService(payments, trips, config.DefaultPaymentReconciliationConfig(), events, metrics, mem.Logger),
+		trips:     trips,
+		payments:  payments,
+		passenger: mem.NewPassenger(),
+		metrics:   metrics,
+		events:    events,
+	}
+}
+
+// trip stores a trip of the fixture's passenger completed at the given time for fare
+func (f *paymentFixture) trip(t *testing.T, completedAt time.Time, fare float64) *models.Trip {
+	requested := completedAt.Add(-30 * time.Minute)
+	trip := &models.Trip{
+		ID:                   uuid.New(),
+		PassengerID:          f.passenger.ID,
+		PickupLatitude:       -6.2,
+		PickupLongitude:      106.82,
+		DestinationLatitude:  -6.3,
+		DestinationLongitude: 106.85,
+		Status:               models.TripStatusCompleted,
+		FareAmount:           &fare,
+		RequestedAt:          requested,
+		CompletedAt:          &completedAt,
+		CreatedAt:            requested,
+		UpdatedAt:            completedAt,
+	}
+	require.NoError(t, f.trips.Create(context.Background(), trip))
+	return trip
+}
+
+func (f *paymentFixture) pay(t *testing.T, trip *models.Trip, kind models.PaymentKind, amount float64) {
+	_, err := f.svc.RecordPayment(context.Background(), &models.TripPayment{TripID: trip.ID, Kind: kind, Method: models.PaymentMethodCard, Amount: amount})
+	require.NoError(t, err)
+}
+
+func (f *paymentFixture) open(t *testing.T) []*models.PaymentDiscrepancy {
+	discrepancies, _, err := f.svc.ListDiscrepancies(context.Background(), models.PaymentDiscrepancyFilter{Status: models.PaymentDiscrepancyOpen, Limit: 100})
+	require.NoError(t, err)
+	return discrepancies
+}
+
+func TestPaymentReconciliationService_FindsDiscrepancies(t *testing.T) {
+	f := newPaymentFixture(t)
+	ctx := context.Background()
+	completed := time.Now().Add(-2 * time.Hour)
+
+	unpaid := f.trip(t, completed, 20)
+	underpaid := f.trip(t, completed, 30)
+	f.pay(t, underpaid, models.PaymentKindCharge, 25)
+	overpaid := f.trip(t, completed, 15)
+	f.pay(t, overpaid, models.PaymentKindCharge, 18)
+	refunded := f.trip(t, completed, 10)
+	f.pay(t, refunded, models.PaymentKindCharge, 12)
+	f.pay(t, refunded, models.PaymentKindRefund, 2)
+	f.trip(t, completed, 0)                        // free rides need no payment
+	f.trip(t, time.Now().Add(-10*time.Minute), 40) // still within the grace period
+	f.trip(t, time.Now().Add(-8*24*time.Hour), 40) // older than the lookback
+
+	run, err := f.svc.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 5, run.Checked)
+	assert.Equal(t, 2, run.Balanced)
+	require.Len(t, run.Discrepancies, 3)
+
+	byTrip := make(map[uuid.UUID]*models.PaymentDiscrepancy)
+	for _, d := range run.Discrepancies {
+		byTrip[d.TripID] = d
+	}
+	assert.Equal(t, models.PaymentDiscrepancyMissing, byTrip[unpaid.ID].Type)
+	assert.Equal(t, 20.0, byTrip[unpaid.ID].ExpectedAmount)
+	assert.Equal(t, models.PaymentDiscrepancyMismatch, byTrip[underpaid.ID].Type)
+	assert.Equal(t, -5.0, byTrip[underpaid.ID].Difference())
+	assert.Equal(t, 3.0, byTrip[overpaid.ID].Difference())
+	assert.Equal(t, 1.0, f.metrics.metrics["payment_discrepancies_detected_total:missing_payment"])
+	assert.Equal(t, 2.0, f.metrics.metrics["payment_discrepancies_detected_total:amount_mismatch"])
+	assert.Equal(t, []string{"payment_discrepancy_detected", "payment_discrepancy_detected", "payment_discrepancy_detected"}, f.events.types())
+	assert.Same(t, run, f.svc.LatestRun())
+
+	// Discrepancies still open are not reported again
+	run, err = f.svc.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, run.Discrepancies)
+	assert.Equal(t, 3, run.AlreadyOpen)
+	assert.Len(t, f.open(t), 3)
+}
+
+func TestPaymentReconciliationService_Resolve(t *testing.T) {
+	f := newPaymentFixture(t)
+	ctx := context.Background()
+	completed := time.Now().Add(-2 * time.Hour)
+	underpaid := f.trip(t, completed, 30)
+	f.pay(t, underpaid, models.PaymentKindCharge, 25)
+	overpaid := f.trip(t, completed, 15)
+	f.pay(t, overpaid, models.PaymentKindCharge, 18)
+	_, err := f.svc.Reconcile(ctx)
+	require.NoError(t, err)
+
+	byTrip := make(map[uuid.UUID]*models.PaymentDiscrepancy)
+	for _, d := range f.open(t) {
+		byTrip[d.TripID] = d
+	}
+
+	// An underpaid trip has nothing to refund
+	_, err = f.svc.Resolve(ctx, byTrip[underpaid.ID].ID.String(), models.PaymentResolutionRefund, models.PaymentMethodCard, "", "ops@example.com", "")
+	var validation *models.ValidationError
+	assert.True(t, errors.As(err, &validation))
+	_, err = f.svc.Resolve(ctx, byTrip[underpaid.ID].ID.String(), models.PaymentResolutionRecordPayment, "", "", "ops@example.com", "")
+	assert.True(t, errors.As(err, &validation), "charges need a method")
+
+	resolved, err := f.svc.Resolve(ctx, byTrip[underpaid.ID].ID.String(), models.PaymentResolutionRecordPayment, models.PaymentMethodCash, "RCPT-1", "ops@example.com", " Collected by driver ")
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentDiscrepancyResolved, resolved.Status)
+	assert.Equal(t, "Collected by driver", *resolved.ResolutionNote)
+	require.NotNil(t, resolved.PaymentID)
+	payments, err := f.svc.ListPayments(ctx, underpaid.ID.String())
+	require.NoError(t, err)
+	require.Len(t, payments, 2)
+	assert.Equal(t, *resolved.PaymentID, payments[1].ID)
+	assert.Equal(t, 5.0, payments[1].Amount)
+	assert.Equal(t, "RCPT-1", *payments[1].Reference)
+
+	refunded, err := f.svc.Resolve(ctx, byTrip[overpaid.ID].ID.String(), models.PaymentResolutionRefund, models.PaymentMethodCard, "", "ops@example.com", "")
+	require.NoError(t, err)
+	stored, err := f.svc.GetDiscrepancy(ctx, refunded.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, refunded.PaymentID, stored.PaymentID)
+	payments, err = f.svc.ListPayments(ctx, overpaid.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentKindRefund, payments[1].Kind)
+	assert.Equal(t, 3.0, payments[1].Amount)
+
+	assert.Equal(t, 1.0, f.metrics.metrics["payment_discrepancy_resolutions_total:amount_mismatch,record_payment"])
+	assert.Contains(t, f.events.types(), "payment_discrepancy_resolved")
+
+	// Resolutions are final, and the trips balance now
+	_, err = f.svc.Resolve(ctx, refunded.ID.String(), models.PaymentResolutionDismiss, "", "", "ops@example.com", "")
+	assert.ErrorIs(t, err, models.ErrInvalidStatusTransition)
+	run, err := f.svc.Reconcile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, run.Balanced)
+
+	_, err = f.svc.Resolve(ctx, uuid.NewString(), models.PaymentResolutionWriteOff, "", "", "ops@example.com", "")
+	var notFound *models.NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+}
+
+func TestPaymentReconciliationService_LaterPaymentsSettleDiscrepancies(t *testing.T) {
+	f := newPaymentFixture(t)
+	ctx := context.Background()
+	trip := f.trip(t, time.Now().Add(-2*time.Hour), 30)
+	_, err := f.svc.Reconcile(ctx)
+	require.NoError(t, err)
+	require.Len(t, f.open(t), 1)
+
+	// A partial payment settles the missing payment, and the shortfall is found as a mismatch
+	f.pay(t, trip, models.PaymentKindCharge, 20)
+	assert.Empty(t, f.open(t))
+	_, err = f.svc.Reconcile(ctx)
+	require.NoError(t, err)
+	open := f.open(t)
+	require.Len(t, open, 1)
+	assert.Equal(t, models.PaymentDiscrepancyMismatch, open[0].Type)
+
+	f.pay(t, trip, models.PaymentKindCharge, 10)
+	assert.Empty(t, f.open(t))
+	settled, err := f.svc.GetDiscrepancy(ctx, open[0].ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.PaymentResolutionSettled, *settled.Resolution)
+	assert.Equal(t, "reconciliation", *settled.ResolvedBy)
+
+	// Only completed trips are paid for
+	requested := f.trip(t, time.Now(), 10)
+	requested.Status, requested.CompletedAt = models.TripStatusRequested, nil
+	require.NoError(t, f.trips.Update(ctx, requested))
+	_, err = f.svc.RecordPayment(ctx, &models.TripPayment{TripID: requested.ID, Kind: models.PaymentKindCharge, Method: models.PaymentMethodCard, Amount: 10})
+	var validation *models.ValidationError
+	assert.True(t, errors.As(err, &validation))
+}
@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPickupWaitService creates a pickup wait service over the repositories of a completion
+// fixture, whose trip is accepted instead of in progress
+func newPickupWaitService(t *testing.T, cfg config.PickupWaitConfig) (*service.PickupWaitService, *service.TripCompletionService, *completionFixture) {
+	logger := utils.NewTestLogger(t)
+
+	f := newCompletionFixture(t)
+	f.trip.Status = models.TripStatusAccepted
+	f.trip.PickupAt = nil
+	require.NoError(t, f.trips.Update(context.Background(), f.trip))
+
+	waits := service.NewPickupWaitService(f.trips, f.drivers, f.passengers, cfg, f.tripEvents, f.events, logger)
+	return waits, f.svc, f
+}
+
+func TestPickupWaitService_ChargesWaitPastFreePeriod(t *testing.T) {
+	cfg := config.DefaultPickupWaitConfig()
+	cfg.FreePeriod = 0
+	svc, completions, f := newPickupWaitService(t, cfg)
+	ctx := context.Background()
+	tripID := f.trip.ID.String()
+
+	_, err := svc.Arrive(ctx, tripID, f.passengerUser, models.UserTypePassenger)
+	assert.ErrorIs(t, err, models.ErrUnauthorizedOperation)
+	_, err = svc.PickUp(ctx, tripID, f.driverUser, models.UserTypeDriver)
+	assert.ErrorIs(t, err, models.ErrDriverNotArrived)
+
+	wait, err := svc.Arrive(ctx, tripID, f.driverUser, models.UserTypeDriver)
+	require.NoError(t, err)
+	assert.Equal(t, models.PickupWaitWaiting, wait.Outcome)
+	assert.Equal(t, "qqguw", wait.Zone)
+	assert.Equal(t, wait.ArrivedAt.Add(cfg.NoShowWindow), wait.NoShowAfter)
+
+	_, err = svc.Arrive(ctx, tripID, f.driverUser, models.UserTypeDriver)
+	assert.ErrorIs(t, err, models.ErrTripNotAwaitingPickup)
+	_, err = svc.ReportNoShow(ctx, tripID, f.driverUser, models.UserTypeDriver)
+	assert.ErrorIs(t, err, models.ErrNoShowTooEarly)
+
+	// The first started minute past the free period is charged
+	time.Sleep(time.Millisecond)
+	picked, err := svc.PickUp(ctx, tripID, f.driverUser, models.UserTypeDriver)
+	require.NoError(t, err)
+	assert.Equal(t, models.PickupWaitPickedUp, picked.Outcome)
+	require.NotNil(t, picked.WaitSeconds)
+	assert.Equal(t, cfg.FeePerMinute, picked.WaitFee)
+
+	trip, err := f.trips.GetByID(ctx, tripID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TripStatusInProgress, trip.Status)
+	assert.NotNil(t, trip.PickupAt)
+
+	completion, err := completions.Complete(ctx, tripID, f.driverUser, models.UserTypeDriver, models.TripCompletionDetails{
+		Dropoff:            models.Location{Latitude: -6.3, Longitude: 106.85},
+		OdometerDistanceKm: 13,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, cfg.FeePerMinute, completion.WaitFee)
+	assert.Equal(t, 5+2*13+cfg.FeePerMinute, completion.FinalFare)
+}
+
+func TestPickupWaitService_NoShowCancelsWithFee(t *testing.T) {
+	cfg := config.DefaultPickupWaitConfig()
+	cfg.NoShowWindow = time.Nanosecond
+	svc, _, f := newPickupWaitService(t, cfg)
+	ctx := context.Background()
+	tripID := f.trip.ID.String()
+
+	_, err := svc.Arrive(ctx, tripID, f.driverUser, models.UserTypeDriver)
+	require.NoError(t, err)
+	_, err = svc.ReportNoShow(ctx, tripID, uuid.New(), models.UserTypeDriver)
+	assert.ErrorIs(t, err, models.ErrUnauthorizedOperation)
+
+	wait, err := svc.ReportNoShow(ctx, tripID, f.driverUser, models.UserTypeDriver)
+	require.NoError(t, err)
+	assert.Equal(t, models.PickupWaitNoShow, wait.Outcome)
+	assert.Equal(t, cfg.NoShowFee, wait.NoShowFee)
+	assert.Zero(t, wait.WaitFee)
+
+	trip, err := f.trips.GetByID(ctx, tripID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TripStatusCancelled, trip.Status)
+	require.NotNil(t, trip.FareAmount)
+	assert.Equal(t, cfg.NoShowFee, *trip.FareAmount)
+	require.NotNil(t, trip.CancellationReason)
+	assert.Equal(t, models.CancellationReasonPassengerNoShow, *trip.CancellationReason)
+
+	driver, err := f.drivers.GetByID(ctx, f.driver.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.DriverStatusOnline, driver.Status)
+
+	assert.Equal(t, []models.WebhookEvent{models.WebhookEventTripCancelled}, f.tripEvents.events)
+	assert.Equal(t, []string{"passenger_no_show"}, f.events.types())
+
+	stats, err := svc.GetZoneStats(ctx, time.Now().Add(-time.Hour), time.Now().Add(time.Minute))
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+	assert.Equal(t, models.PickupWaitZoneStats{
+		Zone:           "qqguw",
+		Arrivals:       1,
+		NoShows:        1,
+		AvgWaitSeconds: float64(*wait.WaitSeconds),
+		MaxWaitSeconds: *wait.WaitSeconds,
+		NoShowFees:     cfg.NoShowFee,
+	}, *stats[0])
+
+	_, err = svc.GetZoneStats(ctx, time.Now(), time.Now().Add(-time.Hour))
+	var validation *models.ValidationError
+	assert.ErrorAs(t, err, &validation)
+}
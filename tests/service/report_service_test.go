@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestReportService creates a report service over trips requested around the end of daylight
+// saving time in New York on 2024-11-03, when the clocks went back from 02:00 to 01:00
+func newTestReportService(t *testing.T) *service.ReportService {
+	ctx := context.Background()
+	mem := utils.NewMemoryFixture(t)
+	trips := mem.Trips
+	passenger := mem.NewPassenger()
+
+	trip := func(requestedAt time.Time, status models.TripStatus, endedAt time.Time, fare float64) {
+		t.Helper()
+		tr := &models.Trip{
+			ID:          uuid.New(),
+			PassengerID: passenger.ID,
+			Status:      status,
+			RequestedAt: requestedAt,
+			CreatedAt:   requestedAt,
+			UpdatedAt:   requestedAt,
+		}
+		switch status {
+		case models.TripStatusCompleted:
+			tr.CompletedAt, tr.FareAmount = &endedAt, &fare
+		case models.TripStatusCancelled:
+			tr.CancelledAt = &endedAt
+			tr.RecordCancellation(models.TripCancellation{Reason: models.CancellationReasonWaitTooLong}, service.TripEventModeTraditional)
+		}
+		require.NoError(t, trips.Create(ctx, tr))
+	}
+
+	// 23:00 EDT on 2024-11-01, before the report
+	trip(time.Date(2024, 11, 2, 3, 0, 0, 0, time.UTC), models.TripStatusRequested, time.Time{}, 0)
+	// 00:30 EDT on 2024-11-03
+	trip(time.Date(2024, 11, 3, 4, 30, 0, 0, time.UTC), models.TripStatusCompleted, time.Date(2024, 11, 3, 4, 50, 0, 0, time.UTC), 10)
+	// 23:30 EST on 2024-11-03, which is already 2024-11-04 in UTC
+	trip(time.Date(2024, 11, 4, 4, 30, 0, 0, time.UTC), models.TripStatusCompleted, time.Date(2024, 11, 4, 4, 50, 0, 0, time.UTC), 12.5)
+	// 00:10 EST on 2024-11-04, cancelled ten minutes later
+	trip(time.Date(2024, 11, 4, 5, 10, 0, 0, time.UTC), models.TripStatusCancelled, time.Date(2024, 11, 4, 5, 20, 0, 0, time.UTC), 0)
+
+	return service.NewReportService(trips, config.DefaultReportingConfig())
+}
+
+func TestReportService_DailySummaryInLocalDays(t *testing.T) {
+	svc := newTestReportService(t)
+
+	report, err := svc.DailySummary(context.Background(), "2024-11-02", "2024-11-04", "America/New_York")
+	require.NoError(t, err)
+	assert.Equal(t, "America/New_York", report.Timezone)
+	require.Len(t, report.Days, 3)
+
+	assert.Equal(t, "2024-11-02", report.Days[0].Date)
+	assert.Equal(t, 24, report.Days[0].Hours)
+	assert.Zero(t, report.Days[0].TripsRequested)
+
+	sunday := report.Days[1]
+	assert.Equal(t, "2024-11-03", sunday.Date)
+	assert.Equal(t, 25, sunday.Hours)
+	assert.Equal(t, time.Date(2024, 11, 3, 4, 0, 0, 0, time.UTC), sunday.Start.UTC())
+	assert.Equal(t, time.Date(2024, 11, 4, 5, 0, 0, 0, time.UTC), sunday.End.UTC())
+	assert.Equal(t, int64(2), sunday.TripsRequested)
+	assert.Equal(t, int64(2), sunday.TripsCompleted)
+	assert.Equal(t, 22.5, sunday.Revenue)
+
+	monday := report.Days[2]
+	assert.Equal(t, int64(1), monday.TripsRequested)
+	assert.Equal(t, int64(1), monday.TripsCancelled)
+	assert.Equal(t, map[string]int64{"wait_too_long": 1}, monday.CancellationsByReason)
+	assert.Zero(t, monday.TripsCompleted)
+	assert.Nil(t, sunday.CancellationsByReason)
+
+	// The same trips by UTC date
+	utc, err := svc.DailySummary(context.Background(), "2024-11-03", "2024-11-04", "")
+	require.NoError(t, err)
+	assert.Equal(t, "UTC", utc.Timezone)
+	assert.Equal(t, int64(1), utc.Days[0].TripsRequested)
+	assert.Equal(t, int64(2), utc.Days[1].TripsRequested)
+	assert.Equal(t, 24, utc.Days[0].Hours)
+}
+
+func TestReportService_DailySummaryErrors(t *testing.T) {
+	svc := newTestReportService(t)
+	ctx := context.Background()
+
+	var validationErr *models.ValidationError
+	_, err := svc.DailySummary(ctx, "2024-11-04", "2024-11-02", "")
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = svc.DailySummary(ctx, "2024-11-02", "2024-11-04", "Local")
+	assert.ErrorAs(t, err, &validationErr)
+	_, err = svc.DailySummary(ctx, "2024-01-01", "2024-12-31", "")
+	assert.ErrorAs(t, err, &validationErr, "more than the configured maximum days")
+
+	// Without from the report covers the week ending on to
+	report, err := svc.DailySummary(ctx, "", "2024-11-04", "Asia/Jakarta")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-10-29", report.From)
+	assert.Len(t, report.Days, 7)
+}
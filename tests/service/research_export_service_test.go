@@ -0,0 +1,117 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/geohash"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// exportCSV exports the trips requested in [from, to) and parses the dataset
+func exportCSV(t *testing.T, svc *service.ResearchExportService, from, to time.Time) ([][]string, *service.ResearchExport) {
+	export, err := svc.ExportTrips(context.Background(), from, to)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, export.Write(&buf))
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	return records, export
+}
+
+func TestResearchExportService_ExportTrips_AnonymizesAsDeclared(t *testing.T) {
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	// Five trips of one passenger between the same two blocks, and one trip elsewhere
+	passengerID := uuid.New()
+	var trips []*models.Trip
+	for i := 0; i < 5; i++ {
+		requested := from.Add(time.Duration(i) * time.Hour)
+		completed := requested.Add(25 * time.Minute)
+		fare := 12.6
+		trips = append(trips, &models.Trip{
+			ID: uuid.New(), PassengerID: passengerID, Status: models.TripStatusCompleted,
+			PickupLatitude: -6.2001 + float64(i)*0.0001, PickupLongitude: 106.8166,
+			DestinationLatitude: -6.1751, DestinationLongitude: 106.8272,
+			RequestedAt: requested, CompletedAt: &completed, FareAmount: &fare,
+		})
+	}
+	trips = append(trips, &models.Trip{
+		ID: uuid.New(), PassengerID: uuid.New(), Status: models.TripStatusCancelled,
+		PickupLatitude: -6.9175, PickupLongitude: 107.6191, DestinationLatitude: -6.9147, DestinationLongitude: 107.6098,
+		RequestedAt: from.Add(time.Hour),
+	})
+	repo := &utils.MockTripRepository{}
+	repo.On("ListRequested", mock.Anything, from, to).Return(trips, nil)
+
+	cfg := config.DefaultResearchExportConfig()
+	cfg.Columns = []config.AnonymizationRule{
+		{Column: "trip_id", Action: "hash"},
+		{Column: "passenger_id", Action: "hash"},
+		{Column: "pickup", Action: "geohash"},
+		{Column: "dropoff", Action: "geohash"},
+		{Column: "requested_at", Action: "jitter"},
+		{Column: "completed_at", Action: "jitter"},
+		{Column: "fare_amount", Action: "round"},
+	}
+	cfg.KAnonymity = 5
+	cfg.HashKey = "research-key"
+	svc, err := service.NewResearchExportService(repo, cfg)
+	require.NoError(t, err)
+
+	records, export := exportCSV(t, svc, from, to)
+	assert.Equal(t, 5, export.Exported)
+	assert.Equal(t, 1, export.Suppressed, "the trip alone in its cells is left out")
+	require.Len(t, records, 6)
+	assert.Equal(t, []string{"trip_id", "passenger_id", "pickup", "dropoff", "requested_at", "completed_at", "fare_amount"}, records[0])
+
+	for _, record := range records[1:] {
+		assert.Len(t, record[0], 16)
+		assert.NotContains(t, []string{trips[0].ID.String(), trips[1].ID.String()}, record[0])
+		assert.Equal(t, records[1][1], record[1], "the passenger's trips stay linkable")
+		assert.NotEqual(t, passengerID.String(), record[1])
+		box, err := geohash.Decode(record[2])
+		require.NoError(t, err)
+		assert.Len(t, record[2], 6)
+		assert.True(t, box.Contains(-6.2001, 106.8166), "the pickup is generalized to its cell")
+		assert.Len(t, record[3], 6)
+		assert.Equal(t, "13", record[6])
+
+		requested, err := time.Parse(time.RFC3339, record[4])
+		require.NoError(t, err)
+		completed, err := time.Parse(time.RFC3339, record[5])
+		require.NoError(t, err)
+		assert.Equal(t, 25*time.Minute, completed.Sub(requested), "a trip's timestamps share their jitter")
+		assert.False(t, requested.Before(from.Add(-cfg.TimestampJitter)))
+		assert.False(t, requested.After(from.Add(4*time.Hour+cfg.TimestampJitter)))
+	}
+
+	// Exporting again gives the same dataset, so the jitter cannot be averaged out
+	again, _ := exportCSV(t, svc, from, to)
+	assert.Equal(t, records, again)
+}
+
+func TestResearchExportService_ExportTrips_ValidatesPeriod(t *testing.T) {
+	svc, err := service.NewResearchExportService(&utils.MockTripRepository{}, config.DefaultResearchExportConfig())
+	require.NoError(t, err)
+	var validation *models.ValidationError
+	now := time.Now()
+
+	_, err = svc.ExportTrips(context.Background(), now, now.Add(-time.Hour))
+	assert.ErrorAs(t, err, &validation)
+
+	_, err = svc.ExportTrips(context.Background(), now.AddDate(-1, 0, 0), now)
+	assert.ErrorAs(t, err, &validation)
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/traditional"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// preferenceFixture is a traditional ride service over mocks with two drivers near the pickup:
+// the nearest drives a plain car, the other is accessible, has a child seat, and is pet friendly
+// and quiet but rated lower
+type preferenceFixture struct {
+	service    *service.RideService
+	drivers    *utils.MockDriverRepository
+	passengers *utils.MockPassengerRepository
+	trips      *utils.MockTripRepository
+	plain      *models.Driver
+	equipped   *models.Driver
+}
+
+func newPreferenceFixture(t *testing.T) *preferenceFixture {
+	logger := utils.NewTestLogger(t)
+
+	actorSystem := actor.NewActorSystem("test-system")
+	require.NoError(t, actorSystem.Start(context.Background()))
+	t.Cleanup(func() { actorSystem.Stop() })
+
+	f := &preferenceFixture{
+		drivers:    &utils.MockDriverRepository{},
+		passengers: &utils.MockPassengerRepository{},
+		trips:      &utils.MockTripRepository{},
+	}
+	f.service = service.NewRideService(
+		&utils.MockUserRepository{}, f.drivers, f.passengers, f.trips,
+		actorSystem, observability.NewMetricsCollector(nil, nil, &config.Config{}, logger),
+		traditional.NewTraditionalMonitor(logger, nil),
+		logger, false,
+	)
+
+	plainLat, plainLng := 40.7127, -74.0061
+	equippedLat, equippedLng := 40.7200, -74.0100
+	f.plain = &models.Driver{ID: uuid.New(), CurrentLatitude: &plainLat, CurrentLongitude: &plainLng, Status: models.DriverStatusOnline, Rating: 4.9}
+	f.equipped = &models.Driver{
+		ID: uuid.New(), CurrentLatitude: &equippedLat, CurrentLongitude: &equippedLng, Status: models.DriverStatusOnline, Rating: 4.2,
+		PetFriendly: true, WheelchairAccessible: true, QuietRides: true, ChildSeat: true,
+	}
+
+	f.trips.On("Create", mock.Anything, mock.AnythingOfType("*models.Trip")).Return(nil)
+	f.trips.On("Update", mock.Anything, mock.AnythingOfType("*models.Trip")).Return(nil)
+	f.drivers.On("GetActiveDestinations", mock.Anything).Return(map[string]*models.DriverDestination{}, nil)
+	f.drivers.On("Reserve", mock.Anything, mock.Anything).Return(nil)
+	return f
+}
+
+// request requests a ride for a passenger with prefs on their profile
+func (f *preferenceFixture) request(t *testing.T, drivers []*models.Driver, prefs models.RidePreferences, opts ...service.RideOption) (*models.Trip, error) {
+	passenger := &models.Passenger{ID: uuid.New(), UserID: uuid.New(), RidePreferences: prefs}
+	f.passengers.On("GetByID", mock.Anything, passenger.ID.String()).Return(passenger, nil)
+	f.drivers.On("GetOnlineDrivers", mock.Anything).Return(drivers, nil).Once()
+
+	pickup := models.Location{Latitude: 40.7128, Longitude: -74.0060}
+	dropoff := models.Location{Latitude: 40.7589, Longitude: -73.9851}
+	return f.service.RequestRide(context.Background(), passenger.ID.String(), pickup, dropoff, "pickup", "dropoff", opts...)
+}
+
+func TestRideService_RequestRide_MatchesDriverMeetingHardPreferences(t *testing.T) {
+	f := newPreferenceFixture(t)
+
+	trip, err := f.request(t, []*models.Driver{f.plain, f.equipped}, models.RidePreferences{AccessibleVehicle: true, PetFriendly: true})
+
+	require.NoError(t, err)
+	assert.Equal(t, f.equipped.ID, *trip.DriverID)
+	f.drivers.AssertNotCalled(t, "Reserve", mock.Anything, f.plain.ID.String())
+}
+
+func TestRideService_RequestRide_NeverRelaxesHardPreferences(t *testing.T) {
+	f := newPreferenceFixture(t)
+
+	trip, err := f.request(t, []*models.Driver{f.plain}, models.RidePreferences{AccessibleVehicle: true})
+
+	assert.Error(t, err)
+	assert.Nil(t, trip)
+	assert.Contains(t, err.Error(), "no available drivers found")
+	f.drivers.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything)
+}
+
+func TestRideService_RequestRide_RelaxesSoftPreferences(t *testing.T) {
+	f := newPreferenceFixture(t)
+	minRating := 4.5
+
+	// Only the plain driver is rated highly enough, and no driver is both: quiet ride is relaxed
+	// before the minimum rating
+	trip, err := f.request(t, []*models.Driver{f.plain, f.equipped}, models.RidePreferences{QuietRide: true, MinDriverRating: &minRating})
+	require.NoError(t, err)
+	assert.Equal(t, f.plain.ID, *trip.DriverID)
+
+	// With the quiet driver alone, the minimum rating is relaxed as well
+	trip, err = f.request(t, []*models.Driver{f.equipped}, models.RidePreferences{QuietRide: true, MinDriverRating: &minRating})
+	require.NoError(t, err)
+	assert.Equal(t, f.equipped.ID, *trip.DriverID)
+}
+
+func TestRideService_RequestRide_RequestPreferencesOverrideProfile(t *testing.T) {
+	f := newPreferenceFixture(t)
+
+	trip, err := f.request(t, []*models.Driver{f.plain, f.equipped}, models.RidePreferences{},
+		service.WithRidePreferences(models.RidePreferences{PetFriendly: true}))
+	require.NoError(t, err)
+	assert.Equal(t, f.equipped.ID, *trip.DriverID)
+
+	trip, err = f.request(t, []*models.Driver{f.plain, f.equipped}, models.RidePreferences{PetFriendly: true},
+		service.WithRidePreferences(models.RidePreferences{}))
+	require.NoError(t, err)
+	assert.Equal(t, f.plain.ID, *trip.DriverID)
+}
+
+func TestRideService_RequestRide_RequiresVehicleCapabilities(t *testing.T) {
+	f := newPreferenceFixture(t)
+
+	trip, err := f.request(t, []*models.Driver{f.plain, f.equipped}, models.RidePreferences{},
+		service.RequireVehicleCapabilities(models.VehicleCapabilityChildSeat))
+	require.NoError(t, err)
+	assert.Equal(t, f.equipped.ID, *trip.DriverID)
+
+	// A required capability is never relaxed, even with drivers nearby
+	trip, err = f.request(t, []*models.Driver{f.plain}, models.RidePreferences{},
+		service.RequireVehicleCapabilities(models.VehicleCapabilityWheelchairAccessible, models.VehicleCapabilityChildSeat))
+	assert.Error(t, err)
+	assert.Nil(t, trip)
+	assert.Contains(t, err.Error(), "no available drivers found")
+	f.drivers.AssertNotCalled(t, "Reserve", mock.Anything, f.plain.ID.String())
+}
@@ -3,7 +3,9 @@ package service
 import (
 	"context"
 	"errors"
+	"sync"
 	"testing"
+	"time"
 
 	"actor-model-observability/internal/actor"
 	"actor-model-observability/internal/config"
@@ -146,8 +148,9 @@ func TestRideService_RequestRide_Traditional_Success(t *testing.T) {
 	passengerRepo.On("GetByID", mock.Anything, passengerID.String()).Return(passenger, nil)
 	tripRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Trip")).Return(nil)
 	driverRepo.On("GetOnlineDrivers", mock.Anything).Return([]*models.Driver{driver}, nil)
+	driverRepo.On("GetActiveDestinations", mock.Anything).Return(map[string]*models.DriverDestination{}, nil)
+	driverRepo.On("Reserve", mock.Anything, driverID.String()).Return(nil)
 	tripRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Trip")).Return(nil)
-	driverRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Driver")).Return(nil)
 
 	// Execute
 	trip, err := rideService.RequestRide(context.Background(), passengerID.String(), pickup, dropoff, pickupAddr, dropoffAddr)
@@ -383,6 +386,198 @@ func TestRideService_RequestRide_Traditional_NoDriversAvailable(t *testing.T) {
 	driverRepo.AssertExpectations(t)
 }
 
+func TestRideService_RequestRide_Traditional_SkipsDriverHeadingElsewhere(t *testing.T) {
+	userRepo := &utils.MockUserRepository{}
+	driverRepo := &utils.MockDriverRepository{}
+	passengerRepo := &utils.MockPassengerRepository{}
+	tripRepo := &utils.MockTripRepository{}
+
+	logger := utils.NewTestLogger(t)
+
+	actorSystemReal := actor.NewActorSystem("test-system")
+	require.NoError(t, actorSystemReal.Start(context.Background()))
+	defer actorSystemReal.Stop()
+
+	rideService := service.NewRideService(
+		userRepo, driverRepo, passengerRepo, tripRepo,
+		actorSystemReal, observability.NewMetricsCollector(nil, nil, &config.Config{}, logger),
+		traditional.NewTraditionalMonitor(logger, nil),
+		logger, false,
+	)
+
+	passenger := &models.Passenger{ID: uuid.New(), UserID: uuid.New()}
+	lat, lng := 40.7100, -74.0050
+	driver := &models.Driver{
+		ID:               uuid.New(),
+		UserID:           uuid.New(),
+		CurrentLatitude:  &lat,
+		CurrentLongitude: &lng,
+		Status:           models.DriverStatusOnline,
+	}
+	// The trip heads north but the driver is heading home to the south
+	pickup := models.Location{Latitude: 40.7128, Longitude: -74.0060}
+	dropoff := models.Location{Latitude: 40.7589, Longitude: -73.9851}
+	home := &models.DriverDestination{
+		DriverID:  driver.ID,
+		Latitude:  40.6000,
+		Longitude: -74.0000,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	passengerRepo.On("GetByID", mock.Anything, passenger.ID.String()).Return(passenger, nil)
+	tripRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Trip")).Return(nil)
+	driverRepo.On("GetOnlineDrivers", mock.Anything).Return([]*models.Driver{driver}, nil)
+	driverRepo.On("GetActiveDestinations", mock.Anything).
+		Return(map[string]*models.DriverDestination{driver.ID.String(): home}, nil)
+
+	trip, err := rideService.RequestRide(context.Background(), passenger.ID.String(), pickup, dropoff, "pickup", "dropoff")
+
+	assert.Error(t, err)
+	assert.Nil(t, trip)
+	assert.Contains(t, err.Error(), "no available drivers found")
+	driverRepo.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything)
+}
+
+func TestRideService_RequestRide_Traditional_TriesNextDriverOnReservationConflict(t *testing.T) {
+	userRepo := &utils.MockUserRepository{}
+	driverRepo := &utils.MockDriverRepository{}
+	passengerRepo := &utils.MockPassengerRepository{}
+	tripRepo := &utils.MockTripRepository{}
+
+	logger := utils.NewTestLogger(t)
+
+	actorSystemReal := actor.NewActorSystem("test-system")
+	require.NoError(t, actorSystemReal.Start(context.Background()))
+	defer actorSystemReal.Stop()
+
+	rideService := service.NewRideService(
+		userRepo, driverRepo, passengerRepo, tripRepo,
+		actorSystemReal, observability.NewMetricsCollector(nil, nil, &config.Config{}, logger),
+		traditional.NewTraditionalMonitor(logger, nil),
+		logger, false,
+	)
+
+	passenger := &models.Passenger{ID: uuid.New(), UserID: uuid.New()}
+	nearLat, nearLng := 40.7127, -74.0061
+	farLat, farLng := 40.7300, -74.0100
+	near := &models.Driver{ID: uuid.New(), CurrentLatitude: &nearLat, CurrentLongitude: &nearLng, Status: models.DriverStatusOnline, Rating: 4.5}
+	far := &models.Driver{ID: uuid.New(), CurrentLatitude: &farLat, CurrentLongitude: &farLng, Status: models.DriverStatusOnline, Rating: 4.5}
+	pickup := models.Location{Latitude: 40.7128, Longitude: -74.0060}
+	dropoff := models.Location{Latitude: 40.7589, Longitude: -73.9851}
+
+	passengerRepo.On("GetByID", mock.Anything, passenger.ID.String()).Return(passenger, nil)
+	tripRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Trip")).Return(nil)
+	driverRepo.On("GetOnlineDrivers", mock.Anything).Return([]*models.Driver{far, near}, nil)
+	driverRepo.On("GetActiveDestinations", mock.Anything).Return(map[string]*models.DriverDestination{}, nil)
+	// The nearest driver was reserved by a concurrent request after the drivers were listed
+	driverRepo.On("Reserve", mock.Anything, near.ID.String()).Return(models.ErrDriverNotOnline).Once()
+	driverRepo.On("Reserve", mock.Anything, far.ID.String()).Return(nil).Once()
+	tripRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Trip")).Return(nil)
+
+	trip, err := rideService.RequestRide(context.Background(), passenger.ID.String(), pickup, dropoff, "pickup", "dropoff")
+
+	require.NoError(t, err)
+	assert.Equal(t, far.ID, *trip.DriverID)
+	assert.Equal(t, models.TripStatusMatched, trip.Status)
+	driverRepo.AssertExpectations(t)
+}
+
+func TestRideService_RequestRide_Traditional_ReleasesDriverWhenTripUpdateFails(t *testing.T) {
+	userRepo := &utils.MockUserRepository{}
+	driverRepo := &utils.MockDriverRepository{}
+	passengerRepo := &utils.MockPassengerRepository{}
+	tripRepo := &utils.MockTripRepository{}
+
+	logger := utils.NewTestLogger(t)
+
+	actorSystemReal := actor.NewActorSystem("test-system")
+	require.NoError(t, actorSystemReal.Start(context.Background()))
+	defer actorSystemReal.Stop()
+
+	rideService := service.NewRideService(
+		userRepo, driverRepo, passengerRepo, tripRepo,
+		actorSystemReal, observability.NewMetricsCollector(nil, nil, &config.Config{}, logger),
+		traditional.NewTraditionalMonitor(logger, nil),
+		logger, false,
+	)
+
+	passenger := &models.Passenger{ID: uuid.New(), UserID: uuid.New()}
+	lat, lng := 40.7100, -74.0050
+	driver := &models.Driver{ID: uuid.New(), CurrentLatitude: &lat, CurrentLongitude: &lng, Status: models.DriverStatusOnline}
+	pickup := models.Location{Latitude: 40.7128, Longitude: -74.0060}
+	dropoff := models.Location{Latitude: 40.7589, Longitude: -73.9851}
+
+	passengerRepo.On("GetByID", mock.Anything, passenger.ID.String()).Return(passenger, nil)
+	tripRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Trip")).Return(nil)
+	driverRepo.On("GetOnlineDrivers", mock.Anything).Return([]*models.Driver{driver}, nil)
+	driverRepo.On("GetActiveDestinations", mock.Anything).Return(map[string]*models.DriverDestination{}, nil)
+	driverRepo.On("Reserve", mock.Anything, driver.ID.String()).Return(nil)
+	tripRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Trip")).Return(errors.New("database error"))
+	driverRepo.On("Release", mock.Anything, driver.ID.String()).Return(nil)
+
+	trip, err := rideService.RequestRide(context.Background(), passenger.ID.String(), pickup, dropoff, "pickup", "dropoff")
+
+	assert.Error(t, err)
+	assert.Nil(t, trip)
+	assert.Contains(t, err.Error(), "failed to update trip")
+	driverRepo.AssertExpectations(t)
+}
+
+func TestRideService_RequestRide_Traditional_ConcurrentRequestsReserveDriverOnce(t *testing.T) {
+	ctx := context.Background()
+	f := utils.NewMemoryFixture(t)
+	drivers := f.Drivers
+
+	actorSystemReal := actor.NewActorSystem("test-system")
+	require.NoError(t, actorSystemReal.Start(ctx))
+	defer actorSystemReal.Stop()
+
+	rideService := service.NewRideService(
+		f.Users, drivers, f.Passengers, f.Trips,
+		actorSystemReal, observability.NewMetricsCollector(nil, nil, &config.Config{}, f.Logger),
+		traditional.NewTraditionalMonitor(f.Logger, nil),
+		f.Logger, false,
+	)
+
+	driver := f.NewDriver(models.DriverStatusOnline, func(d *models.Driver) {
+		lat, lng := 40.7100, -74.0050
+		d.CurrentLatitude, d.CurrentLongitude = &lat, &lng
+	})
+
+	const requests = 8
+	var passengerIDs []string
+	for i := 0; i < requests; i++ {
+		passengerIDs = append(passengerIDs, f.NewPassenger().ID.String())
+	}
+
+	pickup := models.Location{Latitude: 40.7128, Longitude: -74.0060}
+	dropoff := models.Location{Latitude: 40.7589, Longitude: -73.9851}
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	matched := 0
+	for _, passengerID := range passengerIDs {
+		wg.Add(1)
+		go func(passengerID string) {
+			defer wg.Done()
+			trip, err := rideService.RequestRide(ctx, passengerID, pickup, dropoff, "pickup", "dropoff")
+			if err == nil {
+				mu.Lock()
+				matched++
+				mu.Unlock()
+				assert.Equal(t, driver.ID, *trip.DriverID)
+			} else {
+				assert.Contains(t, err.Error(), "no available drivers found")
+			}
+		}(passengerID)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1, matched)
+	stored, err := drivers.GetByID(ctx, driver.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.DriverStatusBusy, stored.Status)
+}
+
 func TestRideService_CancelRide_Success(t *testing.T) {
 	// Setup mocks
 	userRepo := &utils.MockUserRepository{}
@@ -425,13 +620,30 @@ func TestRideService_CancelRide_Success(t *testing.T) {
 	tripRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Trip")).Return(nil)
 
 	// Execute
-	err = rideService.CancelRide(context.Background(), tripID.String(), "passenger cancelled")
+	err = rideService.CancelRide(context.Background(), tripID.String(), models.TripCancellation{
+		Reason: models.CancellationReasonWaitTooLong,
+		Note:   "passenger cancelled",
+	})
 
 	// Assert
 	assert.NoError(t, err)
+	assert.Equal(t, models.TripStatusCancelled, trip.Status)
+	require.NotNil(t, trip.CancellationReason)
+	assert.Equal(t, models.CancellationReasonWaitTooLong, *trip.CancellationReason)
+	require.NotNil(t, trip.CancellationNote)
+	assert.Equal(t, "passenger cancelled", *trip.CancellationNote)
+	require.NotNil(t, trip.CancellationMode)
+	assert.Equal(t, service.TripEventModeActor, *trip.CancellationMode)
+
+	// An unknown reason is rejected before the trip is loaded
+	err = rideService.CancelRide(context.Background(), tripID.String(), models.TripCancellation{Reason: "passenger cancelled"})
+	var validationErr *models.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "reason", validationErr.Field)
 
 	// Verify mocks
 	tripRepo.AssertExpectations(t)
+	tripRepo.AssertNumberOfCalls(t, "GetByID", 1)
 }
 
 func TestRideService_GetTripStatus_Success(t *testing.T) {
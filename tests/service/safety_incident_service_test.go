@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/chat"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// incidentNotifier records the safety incidents alerted to webhook subscribers
+type incidentNotifier struct {
+	mu        sync.Mutex
+	incidents []uuid.UUID
+}
+
+func (n *incidentNotifier) PublishSafetyIncident(ctx context.Context, trip *models.Trip, incident *models.SafetyIncident) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.incidents = append(n.incidents, incident.ID)
+	return nil
+}
+
+// incidentFixture is a safety incident service over in-memory repositories with a trip in
+// progress between a passenger and a driver whose location is known
+type incidentFixture struct {
+	svc           *service.SafetyIncidentService
+	chat          *service.ChatService
+	trips         repository.TripRepository
+	trip          *models.Trip
+	passengerUser uuid.UUID
+	driverUser    uuid.UUID
+	notifier      *incidentNotifier
+	events        *eventRecorder
+}
+
+func newIncidentFixture(t *testing.T) *incidentFixture {
+	mem := utils.NewMemoryFixture(t)
+	store, trips, drivers, passengers, logger := mem.Store, mem.Trips, mem.Drivers, mem.Passengers, mem.Logger
+	passenger := mem.NewPassenger()
+	driver := mem.NewDriver(models.DriverStatusBusy, func(d *models.Driver) {
+		d.SetLocation(-6.25, 106.83)
+	})
+	trip := mem.NewTrip(passenger, driver, models.TripStatusInProgress)
+
+	filters, err := chat.NewFilters(nil, nil)
+	require.NoError(t, err)
+	chatCfg := config.DefaultChatConfig()
+	chatCfg.RetentionPeriod = time.Hour
+
+	notifier := &incidentNotifier{}
+	events := &eventRecorder{}
+	return &incidentFixture{
+		svc:           service.NewSafetyIncidentService(memory.NewSafetyIncidentRepository(store), trips, drivers, passengers, nil, notifier, events, logger),
+		chat:          service.NewChatService(memory.NewChatRepository(store), trips, drivers, passengers, filters, chatCfg, logger),
+		trips:         trips,
+		trip:          trip,
+		passengerUser: passenger.UserID,
+		driverUser:    driver.UserID,
+		notifier:      notifier,
+		events:        events,
+	}
+}
+
+func TestSafetyIncidentService_ReportAlertsAndSnapshots(t *testing.T) {
+	f := newIncidentFixture(t)
+	ctx := context.Background()
+	tripID := f.trip.ID.String()
+
+	_, err := f.svc.Report(ctx, tripID, uuid.New(), models.UserTypePassenger, nil, "")
+	assert.ErrorIs(t, err, models.ErrUnauthorizedOperation)
+
+	// Without a device location the driver's last known location is used
+	incident, err := f.svc.Report(ctx, tripID, f.passengerUser, models.UserTypePassenger, nil, "  Driver is speeding  ")
+	require.NoError(t, err)
+	assert.Equal(t, models.SafetyIncidentOpen, incident.Status)
+	assert.Equal(t, models.TripStatusInProgress, incident.TripStatus)
+	require.NotNil(t, incident.Latitude)
+	assert.Equal(t, -6.25, *incident.Latitude)
+	assert.Equal(t, models.IncidentLocationDriver, *incident.LocationSource)
+	assert.Equal(t, "Driver is speeding", *incident.Note)
+	assert.Contains(t, string(incident.Snapshot), tripID)
+
+	located, err := f.svc.Report(ctx, tripID, f.driverUser, models.UserTypeDriver, &models.Location{Latitude: -6.21, Longitude: 106.8}, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.IncidentLocationReporter, *located.LocationSource)
+	assert.Equal(t, -6.21, *located.Latitude)
+
+	_, err = f.svc.Report(ctx, tripID, f.driverUser, models.UserTypeDriver, &models.Location{Latitude: 91, Longitude: 0}, "")
+	var validation *models.ValidationError
+	assert.ErrorAs(t, err, &validation)
+
+	assert.Equal(t, []uuid.UUID{incident.ID, located.ID}, f.notifier.incidents)
+	assert.Equal(t, []string{"safety_incident_reported", "safety_incident_reported"}, f.events.types())
+	for _, event := range f.events.events {
+		assert.Equal(t, models.EventSeverityFatal, event.Severity)
+		assert.Equal(t, models.EventCategorySecurity, event.EventCategory)
+	}
+}
+
+func TestSafetyIncidentService_FreezesTripUntilClosed(t *testing.T) {
+	f := newIncidentFixture(t)
+	ctx := context.Background()
+	tripID := f.trip.ID.String()
+
+	_, err := f.chat.SendMessage(ctx, tripID, f.passengerUser, models.UserTypePassenger, "Please stop the car")
+	require.NoError(t, err)
+	incident, err := f.svc.Report(ctx, tripID, f.passengerUser, models.UserTypePassenger, nil, "")
+	require.NoError(t, err)
+
+	completedAt := time.Now().Add(-2 * time.Hour)
+	f.trip.Status = models.TripStatusCompleted
+	f.trip.CompletedAt = &completedAt
+	require.NoError(t, f.trips.Update(ctx, f.trip))
+
+	// The chat outlives its retention period and the trip cannot be deleted
+	deleted, err := f.chat.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+	assert.ErrorIs(t, f.trips.Delete(ctx, tripID), models.ErrTripFrozen)
+
+	_, err = f.svc.StartReview(ctx, incident.ID.String(), "safety@example.com")
+	require.NoError(t, err)
+	_, err = f.svc.StartReview(ctx, incident.ID.String(), "safety@example.com")
+	assert.ErrorIs(t, err, models.ErrInvalidStatusTransition)
+
+	closed, err := f.svc.Close(ctx, incident.ID.String(), "safety@example.com", "Contacted the passenger")
+	require.NoError(t, err)
+	assert.Equal(t, models.SafetyIncidentClosed, closed.Status)
+	assert.NotNil(t, closed.ClosedAt)
+
+	open, total, err := f.svc.ListIncidents(ctx, models.SafetyIncidentFilter{Status: models.SafetyIncidentOpen, Limit: 10})
+	require.NoError(t, err)
+	assert.Empty(t, open)
+	assert.Zero(t, total)
+
+	// Closing lifts the chat freeze; the incident record still restricts deleting the trip
+	deleted, err = f.chat.PurgeExpired(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	assert.Equal(t, []string{"safety_incident_reported", "safety_incident_review_started", "safety_incident_closed"}, f.events.types())
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/traditional"
+	"actor-model-observability/tests/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// jakarta is a service area around central Jakarta
+var jakarta = config.ServiceArea{
+	Name: "jakarta",
+	Polygon: []config.GeoPoint{
+		{Lat: -6.08, Lng: 106.68}, {Lat: -6.08, Lng: 107.0}, {Lat: -6.38, Lng: 107.0}, {Lat: -6.38, Lng: 106.68},
+	},
+}
+
+var (
+	monas   = models.Location{Latitude: -6.1754, Longitude: 106.8272}
+	airport = models.Location{Latitude: -6.2, Longitude: 106.85}
+	bandung = models.Location{Latitude: -6.9175, Longitude: 107.6191}
+)
+
+func newServiceAreaPolicy(t *testing.T, cfg config.ServiceAreaConfig) (*service.ServiceAreaPolicy, *metricsRecorder) {
+	metrics := &metricsRecorder{}
+	policy, err := service.NewServiceAreaPolicy(cfg, metrics)
+	require.NoError(t, err)
+	return policy, metrics
+}
+
+func rejectionReason(err error) models.RideRejectionReason {
+	var rejected *models.RideRejectedError
+	if errors.As(err, &rejected) {
+		return rejected.Reason
+	}
+	return ""
+}
+
+func TestServiceAreaPolicy_RejectsOutsideArea(t *testing.T) {
+	policy, metrics := newServiceAreaPolicy(t, config.ServiceAreaConfig{
+		Enabled:  true,
+		Areas:    []config.ServiceArea{jakarta},
+		Timezone: "UTC",
+	})
+
+	assert.NoError(t, policy.Check(monas, airport, time.Now()))
+	assert.Equal(t, models.RideRejectionPickupOutsideArea, rejectionReason(policy.Check(bandung, monas, time.Now())))
+	assert.Equal(t, models.RideRejectionDestinationOutsideArea, rejectionReason(policy.Check(monas, bandung, time.Now())))
+	assert.Equal(t, models.RideRejectionDestinationOutsideArea, rejectionReason(policy.Check(monas, bandung, time.Now())))
+
+	assert.Equal(t, map[string]float64{
+		"ride_request_rejections_total:pickup_outside_service_area":      1,
+		"ride_request_rejections_total:destination_outside_service_area": 2,
+	}, metrics.metrics)
+
+	// A disabled policy serves everywhere
+	disabled, _ := newServiceAreaPolicy(t, config.ServiceAreaConfig{Areas: []config.ServiceArea{jakarta}, Timezone: "UTC"})
+	assert.NoError(t, disabled.Check(bandung, bandung, time.Now()))
+}
+
+func TestServiceAreaPolicy_RejectsOutsideHours(t *testing.T) {
+	// Wednesday 1 May 2024 in Jakarta
+	wednesday := func(hour, minute int) time.Time {
+		return time.Date(2024, 5, 1, hour, minute, 0, 0, time.FixedZone("WIB", 7*60*60))
+	}
+	weekdays := config.OperatingHours{
+		Days:  []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+		Open:  6 * time.Hour,
+		Close: 22 * time.Hour,
+	}
+	// Tuesday night until 2am on Wednesday
+	tuesdayNight := config.OperatingHours{
+		Days:  []time.Weekday{time.Tuesday},
+		Open:  22 * time.Hour,
+		Close: 2 * time.Hour,
+	}
+
+	tests := []struct {
+		name  string
+		hours []config.OperatingHours
+		at    time.Time
+		open  bool
+	}{
+		{"no hours", nil, wednesday(3, 0), true},
+		{"within weekday hours", []config.OperatingHours{weekdays}, wednesday(6, 0), true},
+		{"before opening", []config.OperatingHours{weekdays}, wednesday(5, 59), false},
+		{"at closing", []config.OperatingHours{weekdays}, wednesday(22, 0), false},
+		{"not open on the day", []config.OperatingHours{{Days: []time.Weekday{time.Saturday}, Close: 24 * time.Hour}}, wednesday(12, 0), false},
+		{"past midnight from the day before", []config.OperatingHours{tuesdayNight}, wednesday(1, 59), true},
+		{"after closing past midnight", []config.OperatingHours{tuesdayNight}, wednesday(2, 0), false},
+		{"past midnight on the opening day", []config.OperatingHours{tuesdayNight}, wednesday(23, 0), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, metrics := newServiceAreaPolicy(t, config.ServiceAreaConfig{Enabled: true, Timezone: "Asia/Jakarta", Hours: tt.hours})
+
+			err := policy.Check(monas, bandung, tt.at.UTC())
+			if tt.open {
+				assert.NoError(t, err)
+				assert.Empty(t, metrics.metrics)
+			} else {
+				assert.Equal(t, models.RideRejectionOutsideHours, rejectionReason(err))
+				assert.Equal(t, map[string]float64{"ride_request_rejections_total:outside_operating_hours": 1}, metrics.metrics)
+			}
+		})
+	}
+}
+
+func TestRideService_RequestRide_RejectedOutsideServiceArea(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+
+	passengerRepo := &utils.MockPassengerRepository{}
+	tripRepo := &utils.MockTripRepository{}
+	rideService := service.NewRideService(
+		&utils.MockUserRepository{}, &utils.MockDriverRepository{}, passengerRepo, tripRepo,
+		actor.NewActorSystem("test-system"), observability.NewMetricsCollector(nil, nil, &config.Config{}, logger),
+		traditional.NewTraditionalMonitor(logger, nil), logger, true,
+	)
+	policy, _ := newServiceAreaPolicy(t, config.ServiceAreaConfig{Enabled: true, Areas: []config.ServiceArea{jakarta}, Timezone: "UTC"})
+	rideService.SetServiceArea(policy)
+
+	trip, err := rideService.RequestRide(context.Background(), "passenger-1", bandung, monas, "", "")
+	assert.Nil(t, trip)
+	assert.Equal(t, models.RideRejectionPickupOutsideArea, rejectionReason(err))
+
+	// Nothing is looked up or created for a rejected request
+	passengerRepo.AssertNotCalled(t, "GetByID", mock.Anything, mock.Anything)
+	tripRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
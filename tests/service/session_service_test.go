@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventRecorder collects the event logs published by a service
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []*models.EventLog
+}
+
+func (r *eventRecorder) Publish(topic string, payload interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if event, ok := payload.(*models.EventLog); ok {
+		r.events = append(r.events, event)
+	}
+}
+
+func (r *eventRecorder) types() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	types := make([]string, len(r.events))
+	for i, event := range r.events {
+		types[i] = event.EventType
+	}
+	return types
+}
+
+// newTestSessionService creates a session service over in-memory repositories with one driver
+func newTestSessionService(t *testing.T, cfg config.AuthConfig) (*service.SessionService, repository.SessionRepository, *models.User, *eventRecorder) {
+	mem := utils.NewMemoryFixture(t)
+	sessions := memory.NewSessionRepository(mem.Store)
+
+	// Logins in the tests name the user's email and phone
+	user := &models.User{
+		ID:        uuid.New(),
+		Email:     "driver@example.com",
+		Phone:     "+6281234567890",
+		Name:      "Test Driver",
+		UserType:  models.UserTypeDriver,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, mem.Users.Create(context.Background(), user))
+
+	events := &eventRecorder{}
+	return service.NewSessionService(sessions, mem.Users, cfg, events, mem.Logger), sessions, user, events
+}
+
+func login(t *testing.T, svc *service.SessionService, deviceID string) *models.SessionTokens {
+	tokens, err := svc.Login(context.Background(), service.LoginCredentials{
+		Email:    "driver@example.com",
+		Phone:    "+6281234567890",
+		DeviceID: deviceID,
+		ClientIP: "10.0.0.1",
+	})
+	require.NoError(t, err)
+	return tokens
+}
+
+func TestSessionService_LoginAndAuthenticate(t *testing.T) {
+	svc, _, user, events := newTestSessionService(t, config.DefaultAuthConfig())
+	ctx := context.Background()
+
+	tokens := login(t, svc, "device-1")
+	assert.Equal(t, "Bearer", tokens.TokenType)
+	assert.Equal(t, 900, tokens.ExpiresIn)
+	assert.NotEqual(t, tokens.AccessToken, tokens.RefreshToken)
+
+	session, err := svc.Authenticate(ctx, tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, session.UserID)
+	assert.Equal(t, models.UserTypeDriver, session.UserType)
+	assert.NotContains(t, session.AccessTokenHash, tokens.AccessToken, "only token hashes are stored")
+
+	_, err = svc.Authenticate(ctx, tokens.RefreshToken)
+	assert.ErrorIs(t, err, models.ErrInvalidToken)
+
+	_, err = svc.Login(ctx, service.LoginCredentials{Email: "driver@example.com", Phone: "+6200000000000", DeviceID: "device-2"})
+	assert.ErrorIs(t, err, models.ErrInvalidCredentials)
+	_, err = svc.Login(ctx, service.LoginCredentials{Email: "nobody@example.com", Phone: "+6281234567890", DeviceID: "device-2"})
+	assert.ErrorIs(t, err, models.ErrInvalidCredentials)
+
+	assert.Equal(t, []string{"session_created", "login_failed", "login_failed"}, events.types())
+	for _, event := range events.events {
+		assert.Equal(t, models.EventCategorySecurity, event.EventCategory)
+	}
+}
+
+func TestSessionService_RefreshRotatesTokens(t *testing.T) {
+	svc, _, _, events := newTestSessionService(t, config.DefaultAuthConfig())
+	ctx := context.Background()
+
+	tokens := login(t, svc, "device-1")
+	refreshed, err := svc.Refresh(ctx, tokens.RefreshToken, "10.0.0.1", service.ClientApp{})
+	require.NoError(t, err)
+	assert.Equal(t, tokens.Session.ID, refreshed.Session.ID)
+	assert.Equal(t, tokens.Session.ExpiresAt, refreshed.Session.ExpiresAt, "refreshing doesn't extend the session")
+
+	// The previous token pair stops working
+	_, err = svc.Refresh(ctx, tokens.RefreshToken, "10.0.0.1", service.ClientApp{})
+	assert.ErrorIs(t, err, models.ErrInvalidToken)
+	_, err = svc.Authenticate(ctx, tokens.AccessToken)
+	assert.ErrorIs(t, err, models.ErrInvalidToken)
+
+	_, err = svc.Authenticate(ctx, refreshed.AccessToken)
+	assert.NoError(t, err)
+
+	assert.Equal(t, []string{"session_created", "session_refreshed", "refresh_failed"}, events.types())
+}
+
+func TestSessionService_AccessTokenExpires(t *testing.T) {
+	cfg := config.DefaultAuthConfig()
+	cfg.AccessTokenTTL = 20 * time.Millisecond
+	svc, _, _, _ := newTestSessionService(t, cfg)
+	ctx := context.Background()
+
+	tokens := login(t, svc, "device-1")
+	time.Sleep(30 * time.Millisecond)
+
+	_, err := svc.Authenticate(ctx, tokens.AccessToken)
+	assert.ErrorIs(t, err, models.ErrInvalidToken)
+
+	// The refresh token is still valid and issues a fresh access token
+	refreshed, err := svc.Refresh(ctx, tokens.RefreshToken, "", service.ClientApp{})
+	require.NoError(t, err)
+	_, err = svc.Authenticate(ctx, refreshed.AccessToken)
+	assert.NoError(t, err)
+}
+
+func TestSessionService_EnforcesSessionLimit(t *testing.T) {
+	cfg := config.DefaultAuthConfig()
+	cfg.MaxSessionsPerUser = 2
+	svc, _, user, events := newTestSessionService(t, cfg)
+	ctx := context.Background()
+
+	first := login(t, svc, "device-1")
+	time.Sleep(time.Millisecond)
+	second := login(t, svc, "device-2")
+	time.Sleep(time.Millisecond)
+
+	// Using the first session makes the second one the least recently used
+	first, err := svc.Refresh(ctx, first.RefreshToken, "", service.ClientApp{})
+	require.NoError(t, err)
+	time.Sleep(time.Millisecond)
+
+	third := login(t, svc, "device-3")
+
+	sessions, err := svc.ListSessions(ctx, user.ID.String())
+	require.NoError(t, err)
+	require.Len(t, sessions, 2)
+	assert.Equal(t, third.Session.ID, sessions[0].ID)
+	assert.Equal(t, first.Session.ID, sessions[1].ID)
+
+	_, err = svc.Authenticate(ctx, second.AccessToken)
+	assert.ErrorIs(t, err, models.ErrInvalidToken)
+	assert.Contains(t, events.types(), "session_evicted")
+}
+
+func TestSessionService_LoginFromSameDeviceReplacesSession(t *testing.T) {
+	svc, sessions, user, _ := newTestSessionService(t, config.DefaultAuthConfig())
+	ctx := context.Background()
+
+	first := login(t, svc, "device-1")
+	second := login(t, svc, "device-1")
+
+	active, err := svc.ListSessions(ctx, user.ID.String())
+	require.NoError(t, err)
+	require.Len(t, active, 1)
+	assert.Equal(t, second.Session.ID, active[0].ID)
+
+	replaced, err := sessions.GetByID(ctx, first.Session.ID.String())
+	require.NoError(t, err)
+	require.NotNil(t, replaced.RevokedReason)
+	assert.Equal(t, models.SessionRevokedReplaced, *replaced.RevokedReason)
+}
+
+func TestSessionService_RevokeSession(t *testing.T) {
+	svc, _, user, events := newTestSessionService(t, config.DefaultAuthConfig())
+	ctx := context.Background()
+
+	tokens := login(t, svc, "device-1")
+
+	// Sessions of other users are reported as missing
+	err := svc.RevokeSession(ctx, uuid.New().String(), tokens.Session.ID.String(), "")
+	var notFound *models.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	require.NoError(t, svc.RevokeSession(ctx, user.ID.String(), tokens.Session.ID.String(), "10.0.0.1"))
+
+	_, err = svc.Authenticate(ctx, tokens.AccessToken)
+	assert.ErrorIs(t, err, models.ErrInvalidToken)
+	_, err = svc.Refresh(ctx, tokens.RefreshToken, "", service.ClientApp{})
+	assert.ErrorIs(t, err, models.ErrInvalidToken)
+
+	// Revoking twice reports the session as missing
+	err = svc.RevokeSession(ctx, user.ID.String(), tokens.Session.ID.String(), "")
+	assert.ErrorAs(t, err, &notFound)
+
+	assert.Contains(t, events.types(), "session_revoked")
+}
+
+func TestSessionService_RecordsAppVersions(t *testing.T) {
+	svc, sessions, _, _ := newTestSessionService(t, config.DefaultAuthConfig())
+	ctx := context.Background()
+
+	tokens, err := svc.Login(ctx, service.LoginCredentials{
+		Email:    "driver@example.com",
+		Phone:    "+6281234567890",
+		DeviceID: "device-1",
+		App:      service.ClientApp{Platform: "ios", Version: "2.3.0"},
+	})
+	require.NoError(t, err)
+	login(t, svc, "device-2")
+
+	// A refresh without a reported version keeps the recorded one
+	tokens, err = svc.Refresh(ctx, tokens.RefreshToken, "", service.ClientApp{})
+	require.NoError(t, err)
+	require.NotNil(t, tokens.Session.AppVersion)
+	assert.Equal(t, "2.3.0", *tokens.Session.AppVersion)
+
+	_, err = svc.Refresh(ctx, tokens.RefreshToken, "", service.ClientApp{Platform: "ios", Version: "2.4.0"})
+	require.NoError(t, err)
+	stored, err := sessions.GetByID(ctx, tokens.Session.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "ios", *stored.AppPlatform)
+	assert.Equal(t, "2.4.0", *stored.AppVersion)
+
+	counts, err := svc.CountSessionsByAppVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []*models.AppVersionSessions{
+		{Platform: "", Version: "", Sessions: 1},
+		{Platform: "ios", Version: "2.4.0", Sessions: 1},
+	}, counts)
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// storageCostFixture writes observability rows to a memory store measured by a storage cost service
+type storageCostFixture struct {
+	t           *testing.T
+	svc         *service.StorageCostService
+	observed    repository.ObservabilityRepository
+	traditional repository.TraditionalRepository
+}
+
+func newStorageCostFixture(t *testing.T) *storageCostFixture {
+	store := memory.NewStore()
+	return &storageCostFixture{
+		t:           t,
+		svc:         service.NewStorageCostService(memory.NewStorageCostRepository(store)),
+		observed:    memory.NewObservabilityRepository(store),
+		traditional: memory.NewTraditionalRepository(store),
+	}
+}
+
+// stateChange stores the trip state change event the trip event emitter records
+func (f *storageCostFixture) stateChange(at time.Time, from, mode string) {
+	data, err := json.Marshal(map[string]string{"trip_id": uuid.NewString(), "from_status": from, "to_status": "requested", "mode": mode})
+	require.NoError(f.t, err)
+	require.NoError(f.t, f.observed.CreateEventLog(context.Background(), &models.EventLog{
+		ID:            uuid.New(),
+		EventType:     service.TripStateChangeEvent,
+		EventCategory: models.EventCategoryBusiness,
+		Severity:      models.EventSeverityInfo,
+		Message:       "Trip status changed",
+		EventData:     data,
+		Timestamp:     at,
+		CreatedAt:     at,
+	}))
+}
+
+func (f *storageCostFixture) message(at time.Time) {
+	require.NoError(f.t, f.observed.CreateActorMessage(context.Background(), &models.ActorMessage{
+		ID:                uuid.New(),
+		TraceID:           uuid.New(),
+		SpanID:            uuid.New(),
+		SenderActorType:   models.ActorTypePassenger,
+		SenderActorID:     "passenger-1",
+		ReceiverActorType: models.ActorTypeTrip,
+		ReceiverActorID:   "trip-1",
+		MessageType:       "request_ride",
+		Status:            models.MessageStatusSent,
+		SentAt:            at,
+	}))
+}
+
+func (f *storageCostFixture) log(at time.Time) {
+	require.NoError(f.t, f.traditional.CreateTraditionalLog(context.Background(), &models.TraditionalLog{
+		ID:          uuid.New(),
+		Level:       models.LogLevelInfo,
+		Message:     "Trip status changed",
+		ServiceName: "ride_service",
+		Timestamp:   at,
+		CreatedAt:   at,
+	}))
+}
+
+// pipeline returns the named pipeline of a cost breakdown
+func pipeline(t *testing.T, pipelines []*models.StoragePipelineCost, name string) *models.StoragePipelineCost {
+	for _, p := range pipelines {
+		if p.Pipeline == name {
+			return p
+		}
+	}
+	require.Failf(t, "pipeline not found", "pipeline %s", name)
+	return nil
+}
+
+func TestStorageCostService_ComparesPipelinesPerRequestByMode(t *testing.T) {
+	f := newStorageCostFixture(t)
+	start := time.Now().UTC().Truncate(time.Hour).Add(-4 * time.Hour)
+
+	// Two actor model requests, with one later change and their messages and logs
+	f.stateChange(start.Add(time.Minute), "none", "actor_model")
+	f.stateChange(start.Add(2*time.Minute), "none", "actor_model")
+	f.stateChange(start.Add(3*time.Minute), "requested", "actor_model")
+	for i := 0; i < 3; i++ {
+		f.message(start.Add(time.Minute))
+	}
+	f.log(start.Add(time.Minute))
+	f.log(start.Add(2 * time.Minute))
+	// One traditional request
+	f.stateChange(start.Add(time.Hour+time.Minute), "none", "traditional")
+	f.log(start.Add(time.Hour + time.Minute))
+	// Requests of both modes in the same hour cannot be attributed
+	f.stateChange(start.Add(2*time.Hour+time.Minute), "none", "actor_model")
+	f.stateChange(start.Add(2*time.Hour+2*time.Minute), "none", "traditional")
+
+	report, err := f.svc.Report(context.Background(), start, start.Add(4*time.Hour), time.Hour)
+	require.NoError(t, err)
+	require.Len(t, report.Buckets, 4)
+	assert.Equal(t, "actor_model", report.Buckets[0].Mode)
+	assert.Equal(t, "traditional", report.Buckets[1].Mode)
+	assert.Equal(t, models.StorageModeMixed, report.Buckets[2].Mode)
+	assert.Equal(t, map[string]int64{"actor_model": 1, "traditional": 1}, report.Buckets[2].Requests)
+	assert.Empty(t, report.Buckets[3].Mode)
+	assert.Zero(t, pipeline(t, report.Buckets[3].Pipelines, models.StoragePipelineActor).Rows)
+
+	require.Len(t, report.Modes, 2)
+	actorMode, traditionalMode := report.Modes[0], report.Modes[1]
+	assert.Equal(t, "actor_model", actorMode.Mode)
+	assert.Equal(t, 1, actorMode.Buckets)
+	assert.Equal(t, int64(2), actorMode.Requests)
+
+	actorPipeline := pipeline(t, actorMode.Pipelines, models.StoragePipelineActor)
+	assert.Equal(t, int64(6), actorPipeline.Rows) // 3 events and 3 messages
+	assert.InDelta(t, 3, actorPipeline.RowsPerRequest, 1e-9)
+	assert.Positive(t, actorPipeline.Bytes)
+	assert.InDelta(t, float64(actorPipeline.Bytes)/2, actorPipeline.BytesPerRequest, 1e-9)
+	require.Len(t, actorPipeline.Tables, 3)
+	assert.Equal(t, "actor_messages", actorPipeline.Tables[0].Table)
+	assert.Equal(t, int64(3), actorPipeline.Tables[0].Rows)
+	assert.Zero(t, actorPipeline.Tables[2].Rows) // no traces
+
+	traditionalPipeline := pipeline(t, actorMode.Pipelines, models.StoragePipelineTraditional)
+	assert.Equal(t, int64(2), traditionalPipeline.Rows)
+	assert.InDelta(t, 1, traditionalPipeline.RowsPerRequest, 1e-9)
+
+	assert.Equal(t, "traditional", traditionalMode.Mode)
+	assert.Equal(t, int64(1), traditionalMode.Requests)
+	assert.Equal(t, int64(1), pipeline(t, traditionalMode.Pipelines, models.StoragePipelineActor).Rows)
+	assert.Equal(t, int64(1), pipeline(t, traditionalMode.Pipelines, models.StoragePipelineTraditional).Rows)
+}
+
+func TestStorageCostService_ValidatesBuckets(t *testing.T) {
+	f := newStorageCostFixture(t)
+	start := time.Now().Add(-24 * time.Hour)
+
+	_, err := f.svc.Report(context.Background(), start, start.Add(24*time.Hour), 30*time.Second)
+	var validation *models.ValidationError
+	require.ErrorAs(t, err, &validation)
+	assert.Equal(t, "bucket", validation.Field)
+
+	_, err = f.svc.Report(context.Background(), start, start.Add(24*time.Hour), time.Minute)
+	require.ErrorAs(t, err, &validation)
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/hlc"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/redaction"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// timelineFixture is a completed trip with records about it, and about another trip, in every source
+type timelineFixture struct {
+	svc     *service.TimelineService
+	trip    *models.Trip
+	traceID uuid.UUID
+}
+
+func newTimelineFixture(t *testing.T) *timelineFixture {
+	ctx := context.Background()
+	mem := utils.NewMemoryFixture(t)
+	trips := mem.Trips
+	observability := memory.NewObservabilityRepository(mem.Store)
+	traditional := memory.NewTraditionalRepository(mem.Store)
+	passenger := mem.NewPassenger()
+
+	requested := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	matched := requested.Add(10 * time.Second)
+	completed := requested.Add(20 * time.Minute)
+	trip := mem.NewTrip(passenger, nil, models.TripStatusCompleted, func(trip *models.Trip) {
+		trip.RequestedAt = requested
+		trip.MatchedAt = &matched
+		trip.CompletedAt = &completed
+		trip.CreatedAt, trip.UpdatedAt = requested, completed
+	})
+
+	otherTrip := uuid.New()
+	traceID := uuid.New()
+	at := func(offset time.Duration) time.Time { return requested.Add(offset) }
+	payload := func(tripID uuid.UUID) json.RawMessage {
+		return json.RawMessage(`{"trip_id":"` + tripID.String() + `","passenger_phone":"+6281234567892"}`)
+	}
+
+	for _, message := range []*models.ActorMessage{
+		{TraceID: traceID, MessageType: "RequestRide", MessagePayload: payload(trip.ID), SentAt: at(time.Second)},
+		{TraceID: uuid.New(), MessageType: "RequestRide", MessagePayload: payload(otherTrip), SentAt: at(2 * time.Second)},
+		// Not naming the trip in its payload, but completing it
+		{TraceID: uuid.New(), MessageType: "CompleteRide", MessagePayload: json.RawMessage(`{}`), SentAt: at(19 * time.Minute),
+			Effects: models.MessageEffects{{EntityType: models.EntityTypeTrip, EntityID: trip.ID.String(), Action: "status_changed"}}},
+	} {
+		message.ID = uuid.New()
+		message.SpanID = uuid.New()
+		message.SenderActorType = models.ActorTypePassenger
+		message.SenderActorID = passenger.ID.String()
+		message.ReceiverActorType = models.ActorTypeTrip
+		message.ReceiverActorID = trip.ID.String()
+		message.Status = models.MessageStatusProcessed
+		message.CreatedAt = message.SentAt
+		require.NoError(t, observability.CreateActorMessage(ctx, message))
+	}
+
+	duration := 40
+	require.NoError(t, observability.CreateDistributedTrace(ctx, &models.DistributedTrace{
+		ID: uuid.New(), TraceID: traceID, SpanID: uuid.New(), OperationName: "match_driver",
+		StartTime: at(5 * time.Second), DurationMs: &duration, Status: models.TraceStatusOK, CreatedAt: at(5 * time.Second),
+	}))
+
+	entityType := "trip"
+	for _, entityID := range []uuid.UUID{trip.ID, otherTrip} {
+		entityID := entityID
+		require.NoError(t, observability.CreateEventLog(ctx, &models.EventLog{
+			ID: uuid.New(), EventType: "trip_matched", EventCategory: models.EventCategoryBusiness,
+			EntityType: &entityType, EntityID: &entityID, EventData: payload(entityID),
+			Severity: models.EventSeverityInfo, Message: "Trip matched", Timestamp: matched, CreatedAt: matched,
+		}))
+	}
+
+	for _, log := range []*models.TraditionalLog{
+		{Message: "Trip completed", Fields: payload(trip.ID), Timestamp: completed},
+		{Message: "Trip completed", Fields: payload(otherTrip), Timestamp: completed},
+		{Message: "Trip completed", Fields: payload(trip.ID), Timestamp: at(2 * time.Hour)},
+	} {
+		log.ID = uuid.New()
+		log.Level = models.LogLevelInfo
+		log.ServiceName = "ride-service"
+		log.CreatedAt = log.Timestamp
+		require.NoError(t, traditional.CreateTraditionalLog(ctx, log))
+	}
+
+	redactor, err := redaction.New(config.DefaultRedactionRules(), "test-key")
+	require.NoError(t, err)
+	return &timelineFixture{
+		svc:     service.NewTimelineService(trips, observability, traditional, redactor),
+		trip:    trip,
+		traceID: traceID,
+	}
+}
+
+func TestTimelineService_MergesSourcesChronologically(t *testing.T) {
+	f := newTimelineFixture(t)
+
+	timeline, err := f.svc.GetTripTimeline(context.Background(), f.trip.ID.String())
+	require.NoError(t, err)
+
+	assert.Equal(t, f.trip.ID, timeline.TripID)
+	assert.Empty(t, timeline.Truncated)
+
+	var sources []string
+	for _, entry := range timeline.Entries {
+		sources = append(sources, entry.Source+":"+entry.Type)
+	}
+	assert.Equal(t, []string{
+		"trip:requested",
+		"message:RequestRide",
+		"trace:match_driver",
+		"trip:matched",
+		"event:trip_matched",
+		"message:CompleteRide",
+		"trip:completed",
+		"log:info",
+	}, sources)
+
+	for i := 1; i < len(timeline.Entries); i++ {
+		assert.False(t, timeline.Entries[i].Timestamp.Before(timeline.Entries[i-1].Timestamp))
+	}
+	require.NotNil(t, timeline.Entries[1].TraceID)
+	assert.Equal(t, f.traceID, *timeline.Entries[1].TraceID)
+}
+
+func TestTimelineService_RedactsUnlessOperator(t *testing.T) {
+	f := newTimelineFixture(t)
+
+	timeline, err := f.svc.GetTripTimeline(context.Background(), f.trip.ID.String())
+	require.NoError(t, err)
+	message := timeline.Entries[1].Data.(*models.ActorMessage)
+	assert.NotContains(t, string(message.MessagePayload), "+6281234567892")
+
+	timeline, err = f.svc.GetTripTimeline(redaction.WithOperator(context.Background()), f.trip.ID.String())
+	require.NoError(t, err)
+	message = timeline.Entries[1].Data.(*models.ActorMessage)
+	assert.Contains(t, string(message.MessagePayload), "+6281234567892")
+}
+
+func TestTimelineService_UnknownTrip(t *testing.T) {
+	f := newTimelineFixture(t)
+
+	_, err := f.svc.GetTripTimeline(context.Background(), uuid.New().String())
+	var notFound *models.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestTimelineService_OrdersByHybridTimestamp(t *testing.T) {
+	ctx := context.Background()
+	mem := utils.NewMemoryFixture(t)
+	trips := mem.Trips
+	observability := memory.NewObservabilityRepository(mem.Store)
+	passenger := mem.NewPassenger()
+
+	requested := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	trip := mem.NewTrip(passenger, nil, models.TripStatusRequested, func(trip *models.Trip) {
+		trip.RequestedAt, trip.CreatedAt, trip.UpdatedAt = requested, requested, requested
+	})
+
+	// The message was sent by an instance whose clock is 5s ahead, before the event it caused was
+	// recorded; its hybrid timestamp orders it first despite its later wall clock time
+	sent := hlc.Timestamp{WallTime: requested.Add(time.Second).UnixNano(), Source: "api-2"}
+	recorded := hlc.Timestamp{WallTime: sent.WallTime, Logical: 1, Source: "api-1"}
+	require.NoError(t, observability.CreateActorMessage(ctx, &models.ActorMessage{
+		ID: uuid.New(), TraceID: uuid.New(), SpanID: uuid.New(), MessageType: "RequestRide",
+		MessagePayload: json.RawMessage(`{"trip_id":"` + trip.ID.String() + `"}`), Status: models.MessageStatusSent,
+		SentAt: requested.Add(6 * time.Second), CreatedAt: requested.Add(6 * time.Second), HLC: &sent,
+	}))
+	entityType := "trip"
+	require.NoError(t, observability.CreateEventLog(ctx, &models.EventLog{
+		ID: uuid.New(), EventType: "trip_requested", EventCategory: models.EventCategoryBusiness,
+		EntityType: &entityType, EntityID: &trip.ID, EventData: json.RawMessage(`{}`), Severity: models.EventSeverityInfo,
+		Message: "Trip requested", Timestamp: requested.Add(2 * time.Second), CreatedAt: requested.Add(2 * time.Second), HLC: &recorded,
+	}))
+
+	redactor, err := redaction.New(config.DefaultRedactionRules(), "test-key")
+	require.NoError(t, err)
+	svc := service.NewTimelineService(trips, observability, memory.NewTraditionalRepository(mem.Store), redactor)
+
+	timeline, err := svc.GetTripTimeline(ctx, trip.ID.String())
+	require.NoError(t, err)
+
+	var sources []string
+	for _, entry := range timeline.Entries {
+		sources = append(sources, entry.Source+":"+entry.Type)
+	}
+	assert.Equal(t, []string{"trip:requested", "message:RequestRide", "event:trip_requested"}, sources)
+	require.NotNil(t, timeline.Entries[1].HLC)
+	assert.Equal(t, sent, *timeline.Entries[1].HLC)
+}
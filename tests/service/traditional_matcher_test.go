@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/traditional"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTraditionalMatcher creates and starts a matcher over an in-memory driver repository
+func newTraditionalMatcher(t *testing.T, cfg config.MatchingConfig) (*service.TraditionalMatcher, *utils.MemoryFixture) {
+	mem := utils.NewMemoryFixture(t)
+	matcher := service.NewTraditionalMatcher(cfg, mem.Drivers, traditional.NewTraditionalMonitor(mem.Logger, nil), mem.Logger)
+	require.NoError(t, matcher.Start(context.Background()))
+	t.Cleanup(func() { matcher.Stop() })
+	return matcher, mem
+}
+
+// blockingMatch returns a match that signals started and then waits for release
+func blockingMatch(started chan<- struct{}, release <-chan struct{}) service.MatchFunc {
+	return func(ctx context.Context) (*models.Driver, error) {
+		started <- struct{}{}
+		<-release
+		return nil, nil
+	}
+}
+
+func TestTraditionalMatcher_BoundsConcurrency(t *testing.T) {
+	matcher, _ := newTraditionalMatcher(t, config.MatchingConfig{Workers: 2, QueueSize: 10, QueueTimeout: time.Second})
+
+	var running, maxRunning atomic.Int32
+	driver := &models.Driver{ID: uuid.New()}
+	match := func(ctx context.Context) (*models.Driver, error) {
+		n := running.Add(1)
+		for {
+			current := maxRunning.Load()
+			if n <= current || maxRunning.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		running.Add(-1)
+		return driver, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			matched, err := matcher.Match(context.Background(), match)
+			assert.NoError(t, err)
+			assert.Equal(t, driver, matched)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), maxRunning.Load())
+	stats := matcher.Stats()
+	assert.Equal(t, int64(6), stats.Matched)
+	assert.Zero(t, stats.QueueDepth)
+}
+
+func TestTraditionalMatcher_RejectsWhenQueueFull(t *testing.T) {
+	matcher, _ := newTraditionalMatcher(t, config.MatchingConfig{Workers: 1, QueueSize: 1, QueueTimeout: time.Second})
+	started, release := make(chan struct{}, 2), make(chan struct{})
+
+	// The only worker is busy and the second request takes the only place in the queue
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := matcher.Match(context.Background(), blockingMatch(started, release))
+			results <- err
+		}()
+		if i == 0 {
+			<-started
+		}
+	}
+	require.Eventually(t, func() bool { return matcher.Stats().QueueDepth == 1 }, time.Second, time.Millisecond)
+
+	_, err := matcher.Match(context.Background(), blockingMatch(started, release))
+	assert.ErrorIs(t, err, service.ErrMatchingQueueFull)
+	assert.Equal(t, int64(1), matcher.Stats().Rejected)
+
+	close(release)
+	assert.NoError(t, <-results)
+	assert.NoError(t, <-results)
+}
+
+func TestTraditionalMatcher_TimesOutWaitingForWorker(t *testing.T) {
+	matcher, _ := newTraditionalMatcher(t, config.MatchingConfig{Workers: 1, QueueSize: 1, QueueTimeout: 20 * time.Millisecond})
+	started, release := make(chan struct{}, 1), make(chan struct{})
+
+	// The queue timeout only covers waiting for a worker, not matching
+	first := make(chan error, 1)
+	go func() {
+		_, err := matcher.Match(context.Background(), blockingMatch(started, release))
+		first <- err
+	}()
+	<-started
+
+	var called atomic.Bool
+	_, err := matcher.Match(context.Background(), func(ctx context.Context) (*models.Driver, error) {
+		called.Store(true)
+		return nil, nil
+	})
+	assert.ErrorIs(t, err, service.ErrMatchingQueueTimeout)
+
+	time.Sleep(30 * time.Millisecond)
+	close(release)
+	assert.NoError(t, <-first)
+	require.Eventually(t, func() bool { return matcher.Stats().QueueDepth == 0 }, time.Second, time.Millisecond)
+	assert.False(t, called.Load(), "requests given up on are never matched")
+	assert.Equal(t, int64(1), matcher.Stats().TimedOut)
+}
+
+func TestTraditionalMatcher_ReleasesDriverOfAbandonedRequest(t *testing.T) {
+	matcher, mem := newTraditionalMatcher(t, config.MatchingConfig{Workers: 1, QueueSize: 1, QueueTimeout: time.Second})
+	ctx := context.Background()
+	drivers := mem.Drivers
+	driver := mem.NewDriver(models.DriverStatusOnline)
+
+	requestCtx, cancel := context.WithCancel(ctx)
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		_, err := matcher.Match(requestCtx, func(ctx context.Context) (*models.Driver, error) {
+			if err := drivers.Reserve(ctx, driver.ID.String()); err != nil {
+				return nil, err
+			}
+			started <- struct{}{}
+			<-release
+			return driver, nil
+		})
+		result <- err
+	}()
+
+	// The requester leaves after the driver was reserved for them
+	<-started
+	cancel()
+	assert.ErrorIs(t, <-result, context.Canceled)
+	close(release)
+
+	require.Eventually(t, func() bool {
+		stored, err := drivers.GetByID(ctx, driver.ID.String())
+		return err == nil && stored.Status == models.DriverStatusOnline
+	}, time.Second, time.Millisecond)
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tripEventRecorder records the trip events sent to webhook subscribers
+type tripEventRecorder struct {
+	mu     sync.Mutex
+	events []models.WebhookEvent
+}
+
+func (r *tripEventRecorder) PublishTripEvent(ctx context.Context, eventType models.WebhookEvent, trip *models.Trip) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, eventType)
+	return nil
+}
+
+// completionFixture is a trip completion service over in-memory repositories with a trip in
+// progress, requested from pickup to a destination about 11.6 km away
+type completionFixture struct {
+	svc           *service.TripCompletionService
+	trips         repository.TripRepository
+	drivers       repository.DriverRepository
+	passengers    repository.PassengerRepository
+	trip          *models.Trip
+	driver        *models.Driver
+	passengerUser uuid.UUID
+	driverUser    uuid.UUID
+	tripEvents    *tripEventRecorder
+	events        *eventRecorder
+}
+
+func newCompletionFixture(t *testing.T) *completionFixture {
+	mem := utils.NewMemoryFixture(t)
+	trips, drivers, passengers := mem.Trips, mem.Drivers, mem.Passengers
+	passenger := mem.NewPassenger()
+	driver := mem.NewDriver(models.DriverStatusBusy)
+	pickupAt := time.Now().Add(-25 * time.Minute)
+	trip := mem.NewTrip(passenger, driver, models.TripStatusInProgress, func(trip *models.Trip) {
+		trip.RequestedAt = pickupAt.Add(-10 * time.Minute)
+		trip.PickupAt = &pickupAt
+	})
+
+	tripEvents := &tripEventRecorder{}
+	events := &eventRecorder{}
+	return &completionFixture{
+		svc:           service.NewTripCompletionService(trips, drivers, passengers, config.DefaultFareConfig(), tripEvents, events, mem.Logger),
+		trips:         trips,
+		drivers:       drivers,
+		passengers:    passengers,
+		trip:          trip,
+		driver:        driver,
+		passengerUser: passenger.UserID,
+		driverUser:    driver.UserID,
+		tripEvents:    tripEvents,
+		events:        events,
+	}
+}
+
+func TestTripCompletionService_CompletesWithFinalFare(t *testing.T) {
+	f := newCompletionFixture(t)
+	ctx := context.Background()
+	tripID := f.trip.ID.String()
+	details := models.TripCompletionDetails{
+		Dropoff:            models.Location{Latitude: -6.3, Longitude: 106.85},
+		OdometerDistanceKm: 13,
+		TollsAmount:        2,
+		ExtrasAmount:       1.5,
+	}
+
+	_, err := f.svc.Complete(ctx, tripID, f.passengerUser, models.UserTypePassenger, details)
+	assert.ErrorIs(t, err, models.ErrUnauthorizedOperation)
+	_, err = f.svc.Complete(ctx, tripID, uuid.New(), models.UserTypeDriver, details)
+	assert.ErrorIs(t, err, models.ErrUnauthorizedOperation)
+
+	completion, err := f.svc.Complete(ctx, tripID, f.driverUser, models.UserTypeDriver, details)
+	require.NoError(t, err)
+	assert.False(t, completion.Flagged)
+	assert.Nil(t, completion.FlagReason)
+	assert.Equal(t, 11.6, completion.RouteDistanceKm)
+	assert.Zero(t, completion.DropoffDeviationKm)
+	assert.Equal(t, 13.0, completion.BilledDistanceKm)
+	assert.Equal(t, 5+2*13+2+1.5, completion.FinalFare)
+
+	trip, err := f.trips.GetByID(ctx, tripID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TripStatusCompleted, trip.Status)
+	require.NotNil(t, trip.FareAmount)
+	assert.Equal(t, completion.FinalFare, *trip.FareAmount)
+	require.NotNil(t, trip.DurationMinutes)
+	assert.Equal(t, 25, *trip.DurationMinutes)
+
+	driver, err := f.drivers.GetByID(ctx, f.driver.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.DriverStatusOnline, driver.Status)
+
+	stored, err := f.svc.GetCompletion(ctx, tripID)
+	require.NoError(t, err)
+	assert.Equal(t, completion.FinalFare, stored.FinalFare)
+
+	// A trip is completed once
+	_, err = f.svc.Complete(ctx, tripID, f.driverUser, models.UserTypeDriver, details)
+	assert.ErrorIs(t, err, models.ErrTripNotCompletable)
+
+	assert.Equal(t, []models.WebhookEvent{models.WebhookEventTripCompleted}, f.tripEvents.events)
+	assert.Empty(t, f.events.types())
+}
+
+func TestTripCompletionService_FlagsDiscrepancies(t *testing.T) {
+	f := newCompletionFixture(t)
+	ctx := context.Background()
+	tripID := f.trip.ID.String()
+
+	_, err := f.svc.Complete(ctx, tripID, f.driverUser, models.UserTypeDriver, models.TripCompletionDetails{
+		Dropoff:            models.Location{Latitude: -6.3, Longitude: 106.85},
+		OdometerDistanceKm: -1,
+	})
+	var validation *models.ValidationError
+	assert.ErrorAs(t, err, &validation)
+
+	// Dropped off 5.5 km past the destination after driving four times the straight line
+	completion, err := f.svc.Complete(ctx, tripID, f.driverUser, models.UserTypeDriver, models.TripCompletionDetails{
+		Dropoff:            models.Location{Latitude: -6.35, Longitude: 106.85},
+		OdometerDistanceKm: 68,
+	})
+	require.NoError(t, err)
+	assert.True(t, completion.Flagged)
+	require.NotNil(t, completion.FlagReason)
+	assert.Contains(t, *completion.FlagReason, "exceeds 2.0 times")
+	assert.Contains(t, *completion.FlagReason, "from the requested destination")
+	assert.Contains(t, *completion.FlagReason, "deviates from the")
+	assert.Equal(t, 5.56, completion.DropoffDeviationKm)
+	assert.Equal(t, []string{"trip_completion_flagged"}, f.events.types())
+	assert.Equal(t, models.EventSeverityWarn, f.events.events[0].Severity)
+
+	flagged := true
+	completions, total, err := f.svc.ListCompletions(ctx, models.TripCompletionFilter{Flagged: &flagged, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, completions, 1)
+	assert.Equal(t, f.trip.ID, completions[0].TripID)
+
+	flagged = false
+	_, total, err = f.svc.ListCompletions(ctx, models.TripCompletionFilter{Flagged: &flagged, Limit: 10})
+	require.NoError(t, err)
+	assert.Zero(t, total)
+}
+
+func TestTripCompletionService_ShortOdometerBillsStraightLine(t *testing.T) {
+	f := newCompletionFixture(t)
+
+	completion, err := f.svc.Complete(context.Background(), f.trip.ID.String(), f.driverUser, models.UserTypeDriver, models.TripCompletionDetails{
+		Dropoff:            models.Location{Latitude: -6.3, Longitude: 106.85},
+		OdometerDistanceKm: 4,
+	})
+	require.NoError(t, err)
+	assert.True(t, completion.Flagged)
+	assert.Contains(t, *completion.FlagReason, "shorter than the 11.60 km straight line")
+	assert.Equal(t, completion.RouteDistanceKm, completion.BilledDistanceKm)
+}
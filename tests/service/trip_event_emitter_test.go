@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/traditional"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stateChangeRecorder records the trip state changes recorded for the event logs
+type stateChangeRecorder struct {
+	mu     sync.Mutex
+	events []map[string]interface{}
+}
+
+func (r *stateChangeRecorder) RecordEvent(eventType, source, description string, metadata map[string]interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if eventType == service.TripStateChangeEvent {
+		r.events = append(r.events, metadata)
+	}
+}
+
+// transitions returns the from and to statuses of the recorded trip state changes
+func (r *stateChangeRecorder) transitions(mode string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var transitions []string
+	for _, event := range r.events {
+		if event["mode"] == mode {
+			transitions = append(transitions, event["from_status"].(string)+"->"+event["to_status"].(string))
+		}
+	}
+	return transitions
+}
+
+func TestRideService_EmitsTripStateChangesToBothPipelinesInEitherMode(t *testing.T) {
+	logger := utils.NewTestLogger(t)
+
+	actorSystem := actor.NewActorSystem("test-system")
+	require.NoError(t, actorSystem.Start(context.Background()))
+	t.Cleanup(func() { actorSystem.Stop() })
+
+	tests := []struct {
+		name          string
+		useActorModel bool
+		mode          string
+		transitions   []string
+		// Sorted label values of the requested trip's change
+		requestedLabels string
+		// Rule of the cancellation policy the cancellation was quoted under
+		cancellationRule string
+	}{
+		{
+			name:          "actor model",
+			useActorModel: true,
+			mode:          service.TripEventModeActor,
+			// Matching happens asynchronously, after the ride is cancelled
+			transitions:      []string{"none->requested", "requested->cancelled"},
+			requestedLabels:  "actor_model,none,requested",
+			cancellationRule: "no_driver",
+		},
+		{
+			name:             "traditional",
+			useActorModel:    false,
+			mode:             service.TripEventModeTraditional,
+			transitions:      []string{"none->requested", "requested->matched", "matched->cancelled"},
+			requestedLabels:  "none,requested,traditional",
+			cancellationRule: "grace_period",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			events := &stateChangeRecorder{}
+			logs := memory.NewTraditionalRepository(memory.NewStore())
+			metrics := &metricsRecorder{}
+
+			drivers := &utils.MockDriverRepository{}
+			passengers := &utils.MockPassengerRepository{}
+			trips := &utils.MockTripRepository{}
+			rideService := service.NewRideService(
+				&utils.MockUserRepository{}, drivers, passengers, trips,
+				actorSystem, observability.NewMetricsCollector(nil, nil, &config.Config{}, logger),
+				traditional.NewTraditionalMonitor(logger, nil),
+				logger, tt.useActorModel,
+			)
+			rideService.SetTripEventEmitter(service.NewTripEventEmitter(events, logs, metrics, "ride-hailing", logger))
+
+			lat, lng := 40.7127, -74.0061
+			driver := &models.Driver{ID: uuid.New(), CurrentLatitude: &lat, CurrentLongitude: &lng, Status: models.DriverStatusOnline}
+			passenger := &models.Passenger{ID: uuid.New(), UserID: uuid.New()}
+			passengers.On("GetByID", mock.Anything, passenger.ID.String()).Return(passenger, nil)
+			trips.On("Create", mock.Anything, mock.AnythingOfType("*models.Trip")).Return(nil)
+			trips.On("Update", mock.Anything, mock.AnythingOfType("*models.Trip")).Return(nil)
+			drivers.On("GetOnlineDrivers", mock.Anything).Return([]*models.Driver{driver}, nil)
+			drivers.On("GetActiveDestinations", mock.Anything).Return(map[string]*models.DriverDestination{}, nil)
+			drivers.On("Reserve", mock.Anything, mock.Anything).Return(nil)
+			drivers.On("GetByID", mock.Anything, driver.ID.String()).Return(driver, nil)
+			drivers.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+			pickup := models.Location{Latitude: 40.7128, Longitude: -74.0060}
+			dropoff := models.Location{Latitude: 40.7589, Longitude: -73.9851}
+			trip, err := rideService.RequestRide(ctx, passenger.ID.String(), pickup, dropoff, "pickup", "dropoff")
+			require.NoError(t, err)
+
+			trips.On("GetByID", mock.Anything, trip.ID.String()).Return(trip, nil)
+			require.NoError(t, rideService.CancelRide(ctx, trip.ID.String(), models.TripCancellation{Reason: models.CancellationReasonChangedPlans}))
+
+			// The actor pipeline's event logs
+			assert.Equal(t, tt.transitions, events.transitions(tt.mode))
+
+			// The traditional pipeline's logs, which reference the trip
+			stored, err := logs.ListTraditionalLogs(ctx, "", "ride-hailing", 10, 0)
+			require.NoError(t, err)
+			var logged []string
+			for _, log := range stored {
+				var fields map[string]interface{}
+				require.NoError(t, json.Unmarshal(log.Fields, &fields))
+				assert.Equal(t, trip.ID.String(), fields["trip_id"])
+				assert.Equal(t, tt.mode, fields["mode"])
+				logged = append(logged, fields["from_status"].(string)+"->"+fields["to_status"].(string))
+
+				// The cancellation carries the quote it was charged, free right after requesting
+				if fields["to_status"] == string(models.TripStatusCancelled) {
+					assert.Equal(t, true, fields["cancellation_free"])
+					assert.Equal(t, float64(0), fields["cancellation_fee"])
+					assert.Equal(t, tt.cancellationRule, fields["cancellation_rule"])
+				} else {
+					assert.NotContains(t, fields, "cancellation_fee")
+				}
+			}
+			assert.ElementsMatch(t, tt.transitions, logged)
+
+			// The metrics registry, labelled by mode
+			assert.Equal(t, float64(1), metrics.metrics["trip_state_changes_total:"+tt.requestedLabels])
+			var counted float64
+			for name, value := range metrics.metrics {
+				if strings.HasPrefix(name, "trip_state_changes_total:") && strings.Contains(name, tt.mode) {
+					counted += value
+				}
+			}
+			assert.Equal(t, float64(len(tt.transitions)), counted)
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/actor"
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/observability"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/traditional"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rematchFixture is a ride service over in-memory repositories with an accepted trip and two
+// drivers near its pickup: the trip's driver and another one online
+type rematchFixture struct {
+	svc           *service.RideService
+	trips         repository.TripRepository
+	drivers       repository.DriverRepository
+	trip          *models.Trip
+	driver        *models.Driver
+	other         *models.Driver
+	passengerUser uuid.UUID
+	driverUser    uuid.UUID
+	otherUser     uuid.UUID
+	tripEvents    *tripEventRecorder
+}
+
+func newRematchFixture(t *testing.T, maxRematches int, useActorModel bool) *rematchFixture {
+	ctx := context.Background()
+
+	actorSystem := actor.NewActorSystem("test-system")
+	require.NoError(t, actorSystem.Start(ctx))
+	t.Cleanup(func() { actorSystem.Stop() })
+
+	mem := utils.NewMemoryFixture(t)
+	users, drivers, passengers, trips := mem.Users, mem.Drivers, mem.Passengers, mem.Trips
+	newDriver := func(status models.DriverStatus, lat, lng float64) *models.Driver {
+		return mem.NewDriver(status, func(d *models.Driver) {
+			d.SetLocation(lat, lng)
+		})
+	}
+
+	passenger := mem.NewPassenger()
+	driver := newDriver(models.DriverStatusBusy, -6.201, 106.821)
+	other := newDriver(models.DriverStatusOnline, -6.21, 106.83)
+
+	matchedAt := time.Now().Add(-2 * time.Minute)
+	trip := mem.NewTrip(passenger, driver, models.TripStatusAccepted, func(trip *models.Trip) {
+		trip.RequestedAt = matchedAt.Add(-time.Minute)
+		trip.MatchedAt = &matchedAt
+		trip.AcceptedAt = &matchedAt
+	})
+
+	svc := service.NewRideService(users, drivers, passengers, trips, actorSystem,
+		observability.NewMetricsCollector(nil, nil, &config.Config{}, mem.Logger),
+		traditional.NewTraditionalMonitor(mem.Logger, nil), mem.Logger, useActorModel)
+	svc.SetRematch(config.RematchConfig{MaxRematches: maxRematches})
+	tripEvents := &tripEventRecorder{}
+	svc.SetEventPublisher(tripEvents)
+
+	return &rematchFixture{
+		svc:           svc,
+		trips:         trips,
+		drivers:       drivers,
+		trip:          trip,
+		driver:        driver,
+		other:         other,
+		passengerUser: passenger.UserID,
+		driverUser:    driver.UserID,
+		otherUser:     other.UserID,
+		tripEvents:    tripEvents,
+	}
+}
+
+func (f *rematchFixture) driverStatus(t *testing.T, driver *models.Driver) models.DriverStatus {
+	stored, err := f.drivers.GetByID(context.Background(), driver.ID.String())
+	require.NoError(t, err)
+	return stored.Status
+}
+
+func TestRideService_CancelByDriver_RematchesWithoutCancellingDrivers(t *testing.T) {
+	f := newRematchFixture(t, 2, false)
+	ctx := context.Background()
+	tripID := f.trip.ID.String()
+
+	_, err := f.svc.CancelByDriver(ctx, tripID, f.passengerUser, models.UserTypePassenger, "")
+	assert.ErrorIs(t, err, models.ErrUnauthorizedOperation)
+	_, err = f.svc.CancelByDriver(ctx, tripID, f.otherUser, models.UserTypeDriver, "")
+	assert.ErrorIs(t, err, models.ErrUnauthorizedOperation)
+
+	rematch, err := f.svc.CancelByDriver(ctx, tripID, f.driverUser, models.UserTypeDriver, "Flat tyre")
+	require.NoError(t, err)
+	assert.Equal(t, 1, rematch.Attempt)
+	assert.Equal(t, models.TripRematchMatched, rematch.Outcome)
+	require.NotNil(t, rematch.MatchedDriverID)
+	assert.Equal(t, f.other.ID, *rematch.MatchedDriverID)
+	require.NotNil(t, rematch.AddedWaitSeconds)
+
+	trip, err := f.trips.GetByID(ctx, tripID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TripStatusMatched, trip.Status)
+	assert.Equal(t, f.other.ID, *trip.DriverID)
+	assert.Nil(t, trip.AcceptedAt)
+	assert.Equal(t, models.DriverStatusOnline, f.driverStatus(t, f.driver))
+	assert.Equal(t, models.DriverStatusBusy, f.driverStatus(t, f.other))
+	// The passenger sees the trip go back to requested and then matched again
+	assert.Equal(t, []models.WebhookEvent{models.WebhookEventTripRequested, models.WebhookEventTripMatched}, f.tripEvents.events)
+
+	// The first driver is online again but never matched with the trip they cancelled, so the
+	// trip is cancelled when the second driver cancels too
+	rematch, err = f.svc.CancelByDriver(ctx, tripID, f.otherUser, models.UserTypeDriver, "")
+	require.NoError(t, err)
+	assert.Equal(t, 2, rematch.Attempt)
+	assert.Equal(t, models.TripRematchUnmatched, rematch.Outcome)
+	assert.Nil(t, rematch.MatchedDriverID)
+
+	trip, err = f.trips.GetByID(ctx, tripID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TripStatusCancelled, trip.Status)
+	require.NotNil(t, trip.CancellationReason)
+	assert.Equal(t, models.CancellationReasonDriverCancelled, *trip.CancellationReason)
+	assert.Equal(t, models.DriverStatusOnline, f.driverStatus(t, f.other))
+
+	rematches, err := f.svc.ListRematches(ctx, tripID)
+	require.NoError(t, err)
+	require.Len(t, rematches, 2)
+	assert.Equal(t, f.driver.ID, rematches[0].CancelledDriverID)
+	require.NotNil(t, rematches[0].Note)
+	assert.Equal(t, "Flat tyre", *rematches[0].Note)
+	assert.Equal(t, models.TripRematchMatched, rematches[0].Outcome)
+	assert.Equal(t, f.other.ID, rematches[1].CancelledDriverID)
+	assert.Equal(t, models.TripRematchUnmatched, rematches[1].Outcome)
+
+	_, err = f.svc.CancelByDriver(ctx, tripID, f.otherUser, models.UserTypeDriver, "")
+	assert.ErrorIs(t, err, models.ErrUnauthorizedOperation, "the trip no longer has a driver")
+}
+
+func TestRideService_CancelByDriver_CancelsOnceLimitReached(t *testing.T) {
+	f := newRematchFixture(t, 0, false)
+	ctx := context.Background()
+	tripID := f.trip.ID.String()
+
+	rematch, err := f.svc.CancelByDriver(ctx, tripID, f.driverUser, models.UserTypeDriver, "Car broke down")
+	require.NoError(t, err)
+	assert.Equal(t, models.TripRematchLimitReached, rematch.Outcome)
+	require.NotNil(t, rematch.AddedWaitSeconds)
+	assert.Zero(t, *rematch.AddedWaitSeconds)
+
+	trip, err := f.trips.GetByID(ctx, tripID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TripStatusCancelled, trip.Status)
+	assert.Nil(t, trip.DriverID)
+	require.NotNil(t, trip.CancellationNote)
+	assert.Equal(t, "Car broke down", *trip.CancellationNote)
+	assert.Equal(t, models.DriverStatusOnline, f.driverStatus(t, f.driver))
+	assert.Equal(t, models.DriverStatusOnline, f.driverStatus(t, f.other), "the trip is not matched again")
+	assert.Equal(t, []models.WebhookEvent{models.WebhookEventTripCancelled}, f.tripEvents.events)
+}
+
+func TestRideService_CancelByDriver_OnlyBeforePickup(t *testing.T) {
+	f := newRematchFixture(t, 2, false)
+	ctx := context.Background()
+
+	pickupAt := time.Now()
+	f.trip.Status = models.TripStatusInProgress
+	f.trip.PickupAt = &pickupAt
+	require.NoError(t, f.trips.Update(ctx, f.trip))
+
+	_, err := f.svc.CancelByDriver(ctx, f.trip.ID.String(), f.driverUser, models.UserTypeDriver, "")
+	assert.ErrorIs(t, err, models.ErrTripNotDriverCancellable)
+}
+
+func TestRideService_CancelByDriver_RematchesInBackgroundInActorMode(t *testing.T) {
+	f := newRematchFixture(t, 2, true)
+	ctx := context.Background()
+	tripID := f.trip.ID.String()
+
+	rematch, err := f.svc.CancelByDriver(ctx, tripID, f.driverUser, models.UserTypeDriver, "")
+	require.NoError(t, err)
+	assert.Equal(t, models.TripRematchMatching, rematch.Outcome)
+	assert.Equal(t, service.TripEventModeActor, rematch.Mode)
+
+	require.Eventually(t, func() bool {
+		rematches, err := f.svc.ListRematches(ctx, tripID)
+		return err == nil && len(rematches) == 1 && rematches[0].Outcome == models.TripRematchMatched
+	}, 2*time.Second, 10*time.Millisecond)
+
+	trip, err := f.trips.GetByID(ctx, tripID)
+	require.NoError(t, err)
+	assert.Equal(t, models.TripStatusMatched, trip.Status)
+	assert.Equal(t, f.other.ID, *trip.DriverID)
+}
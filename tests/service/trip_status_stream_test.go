@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/bus"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTripStatusStream creates a trip status stream over a requested trip from Monas to
+// Bundaran HI in Central Jakarta and a driver parked about 3 km north of the pickup
+func newTestTripStatusStream(t *testing.T) (*service.TripStatusStream, repository.TripRepository, *models.Trip, *models.Driver) {
+	mem := utils.NewMemoryFixture(t)
+	trips, drivers := mem.Trips, mem.Drivers
+	driver := mem.NewDriver(models.DriverStatusBusy, func(d *models.Driver) {
+		lat, lng := -6.148, 106.8272
+		d.CurrentLatitude, d.CurrentLongitude = &lat, &lng
+	})
+	trip := mem.NewTrip(mem.NewPassenger(), nil, models.TripStatusRequested, func(trip *models.Trip) {
+		trip.PickupLatitude, trip.PickupLongitude = -6.1754, 106.8272
+		trip.DestinationLatitude, trip.DestinationLongitude = -6.1951, 106.8231
+	})
+
+	return service.NewTripStatusStream(trips, drivers), trips, trip, driver
+}
+
+// receive returns the next update of a subscription
+func receive(t *testing.T, updates <-chan *models.TripUpdate) *models.TripUpdate {
+	t.Helper()
+	select {
+	case update := <-updates:
+		return update
+	case <-time.After(time.Second):
+		t.Fatal("no trip update received")
+		return nil
+	}
+}
+
+func TestTripStatusStream_PushesStatusAndETA(t *testing.T) {
+	stream, trips, trip, driver := newTestTripStatusStream(t)
+	ctx := context.Background()
+
+	current, updates, unsubscribe, err := stream.Subscribe(ctx, trip.ID.String())
+	require.NoError(t, err)
+	defer unsubscribe()
+	require.Len(t, current, 1, "no ETA before a driver is matched")
+	assert.Equal(t, models.TripUpdateStatus, current[0].Type)
+	assert.Equal(t, models.TripStatusRequested, current[0].Status)
+
+	// Locations of drivers without a subscribed trip are ignored
+	stream.HandleMessage(bus.Message{Topic: bus.TopicDriverLocation, Payload: &models.DriverLocationUpdate{DriverID: driver.ID, Latitude: -6.15, Longitude: 106.8272}})
+
+	matched := *trip
+	matched.Status = models.TripStatusMatched
+	matched.DriverID = &driver.ID
+	stream.HandleMessage(bus.Message{Topic: bus.TopicTripStatus, Payload: &matched, PublishedAt: time.Now()})
+	update := receive(t, updates)
+	assert.Equal(t, models.TripUpdateStatus, update.Type)
+	assert.Equal(t, models.TripStatusMatched, update.Status)
+	assert.Equal(t, &driver.ID, update.DriverID)
+
+	// About 1 km from the pickup at 30 km/h
+	stream.HandleMessage(bus.Message{Topic: bus.TopicDriverLocation, Payload: &models.DriverLocationUpdate{DriverID: driver.ID, Latitude: -6.1664, Longitude: 106.8272, At: time.Now()}})
+	update = receive(t, updates)
+	assert.Equal(t, models.TripUpdateETA, update.Type)
+	require.NotNil(t, update.ETASeconds)
+	assert.InDelta(t, 1.0, *update.DistanceKm, 0.01)
+	assert.InDelta(t, 120, *update.ETASeconds, 2)
+
+	// Other drivers don't affect the trip
+	stream.HandleMessage(bus.Message{Topic: bus.TopicDriverLocation, Payload: &models.DriverLocationUpdate{DriverID: uuid.New(), Latitude: -6.1754, Longitude: 106.8272}})
+	assert.Empty(t, updates)
+
+	// A new subscriber starts with the latest status and the ETA from the driver's stored location
+	require.NoError(t, trips.Update(ctx, &matched))
+	current, _, unsubscribeOther, err := stream.Subscribe(ctx, trip.ID.String())
+	require.NoError(t, err)
+	unsubscribeOther()
+	require.Len(t, current, 2)
+	assert.Equal(t, models.TripStatusMatched, current[0].Status)
+	assert.Equal(t, models.TripUpdateETA, current[1].Type)
+	assert.InDelta(t, 3.04, *current[1].DistanceKm, 0.05)
+
+	// While in progress the ETA is to the destination
+	inProgress := matched
+	inProgress.Status = models.TripStatusInProgress
+	stream.HandleMessage(bus.Message{Topic: bus.TopicTripStatus, Payload: &inProgress})
+	receive(t, updates)
+	stream.HandleMessage(bus.Message{Topic: bus.TopicDriverLocation, Payload: &models.DriverLocationUpdate{DriverID: driver.ID, Latitude: -6.1754, Longitude: 106.8272}})
+	update = receive(t, updates)
+	assert.InDelta(t, 2.23, *update.DistanceKm, 0.05)
+}
+
+func TestTripStatusStream_UnknownTrip(t *testing.T) {
+	stream, _, _, _ := newTestTripStatusStream(t)
+
+	_, _, _, err := stream.Subscribe(context.Background(), uuid.New().String())
+	var notFound *models.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestTripStatusStream_RefreshSendsChangesMadeElsewhere(t *testing.T) {
+	stream, trips, trip, _ := newTestTripStatusStream(t)
+	ctx := context.Background()
+
+	// Trips without subscribers are not loaded
+	require.NoError(t, stream.Refresh(ctx, uuid.New().String()))
+
+	_, updates, unsubscribe, err := stream.Subscribe(ctx, trip.ID.String())
+	require.NoError(t, err)
+	defer unsubscribe()
+
+	// Unchanged since subscribing
+	require.NoError(t, stream.Refresh(ctx, trip.ID.String()))
+	assert.Empty(t, updates)
+
+	// Cancelled by a manual fix, without going through the event bus
+	cancelled := *trip
+	cancelled.Status = models.TripStatusCancelled
+	cancelled.UpdatedAt = trip.UpdatedAt.Add(time.Second)
+	require.NoError(t, trips.Update(ctx, &cancelled))
+	require.NoError(t, stream.RefreshAll(ctx))
+	update := receive(t, updates)
+	assert.Equal(t, models.TripUpdateStatus, update.Type)
+	assert.Equal(t, models.TripStatusCancelled, update.Status)
+
+	// Notified again, e.g. for a change already streamed, nothing is sent twice
+	require.NoError(t, stream.Refresh(ctx, trip.ID.String()))
+	assert.Empty(t, updates)
+}
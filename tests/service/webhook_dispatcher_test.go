@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestWebhookDispatcher creates a dispatcher over an in-memory repository that retries immediately
+func newTestWebhookDispatcher(t *testing.T, maxAttempts int) (*service.WebhookDispatcher, repository.WebhookRepository) {
+	logger := utils.NewTestLogger(t)
+
+	repo := memory.NewWebhookRepository(memory.NewStore())
+	cfg := config.DefaultWebhookConfig()
+	cfg.MaxAttempts = maxAttempts
+	cfg.InitialBackoff = 0
+
+	return service.NewWebhookDispatcher(repo, cfg, logger), repo
+}
+
+func createTestSubscription(t *testing.T, repo repository.WebhookRepository, url string, events ...models.WebhookEvent) *models.WebhookSubscription {
+	subscription := &models.WebhookSubscription{
+		ID:        uuid.New(),
+		Owner:     "consumer-key",
+		URL:       url,
+		Secret:    "0123456789abcdef",
+		Events:    events,
+		Active:    true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	require.NoError(t, repo.CreateSubscription(context.Background(), subscription))
+	return subscription
+}
+
+func TestWebhookDispatcher_DeliversSignedPayload(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	requests := make(chan received, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		requests <- received{header: r.Header, body: body}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dispatcher, repo := newTestWebhookDispatcher(t, 3)
+	subscription := createTestSubscription(t, repo, server.URL, models.WebhookEventTripCompleted)
+	createTestSubscription(t, repo, server.URL, models.WebhookEventTripCancelled)
+
+	ctx := context.Background()
+	trip := &models.Trip{ID: uuid.New(), PassengerID: uuid.New(), Status: models.TripStatusCompleted}
+	require.NoError(t, dispatcher.PublishTripEvent(ctx, models.WebhookEventTripCompleted, trip))
+
+	attempted, err := dispatcher.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempted, "only the subscription to trip.completed receives the event")
+
+	req := <-requests
+	timestamp, err := strconv.ParseInt(req.header.Get(service.WebhookTimestampHeader), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, service.SignWebhookPayload(subscription.Secret, timestamp, req.body), req.header.Get(service.WebhookSignatureHeader))
+	assert.Equal(t, string(models.WebhookEventTripCompleted), req.header.Get(service.WebhookEventHeader))
+
+	var payload models.WebhookPayload
+	require.NoError(t, json.Unmarshal(req.body, &payload))
+	assert.Equal(t, models.WebhookEventTripCompleted, payload.Event)
+	assert.Equal(t, trip.ID, payload.Trip.ID)
+	assert.Equal(t, req.header.Get(service.WebhookDeliveryHeader), payload.ID.String())
+
+	deliveries, total, err := repo.ListDeliveries(ctx, models.WebhookDeliveryFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, models.WebhookDeliverySucceeded, deliveries[0].Status)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, http.StatusNoContent, *deliveries[0].ResponseStatus)
+	assert.NotNil(t, deliveries[0].DeliveredAt)
+	assert.Nil(t, deliveries[0].NextAttemptAt)
+}
+
+func TestWebhookDispatcher_RetriesThenFails(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	dispatcher, repo := newTestWebhookDispatcher(t, 3)
+	createTestSubscription(t, repo, server.URL)
+
+	ctx := context.Background()
+	trip := &models.Trip{ID: uuid.New(), PassengerID: uuid.New(), Status: models.TripStatusMatched}
+	require.NoError(t, dispatcher.PublishTripEvent(ctx, models.WebhookEventTripMatched, trip))
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		attempted, err := dispatcher.DeliverDue(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, attempted, "attempt %d", attempt)
+
+		deliveries, _, err := repo.ListDeliveries(ctx, models.WebhookDeliveryFilter{TripID: &trip.ID, Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, attempt, deliveries[0].Attempts)
+		assert.Contains(t, *deliveries[0].LastError, "503")
+		if attempt < 3 {
+			assert.Equal(t, models.WebhookDeliveryPending, deliveries[0].Status)
+			assert.NotNil(t, deliveries[0].NextAttemptAt)
+		} else {
+			assert.Equal(t, models.WebhookDeliveryFailed, deliveries[0].Status)
+			assert.Nil(t, deliveries[0].NextAttemptAt)
+		}
+	}
+
+	attempted, err := dispatcher.DeliverDue(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, attempted, "failed deliveries are not retried")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,239 @@
+package simulate
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/scenario"
+	"actor-model-observability/internal/simulate"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI serves the endpoints the simulator calls, matching every ride request to the first
+// registered driver and recording the locations and statuses drivers report
+type fakeAPI struct {
+	mu        sync.Mutex
+	drivers   []uuid.UUID
+	locations map[uuid.UUID][]models.Location
+	statuses  map[uuid.UUID][]string
+	trips     map[uuid.UUID]handlers.RequestRideRequest
+}
+
+func newFakeAPI(t *testing.T) (*fakeAPI, *httptest.Server) {
+	api := &fakeAPI{
+		locations: make(map[uuid.UUID][]models.Location),
+		statuses:  make(map[uuid.UUID][]string),
+		trips:     make(map[uuid.UUID]handlers.RequestRideRequest),
+	}
+
+	mux := http.NewServeMux()
+	ok := func(w http.ResponseWriter, r *http.Request) { writeJSON(w, map[string]string{"status": "ok"}) }
+	mux.HandleFunc("POST /api/v1/drivers", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		driver := models.Driver{ID: uuid.New(), UserID: uuid.New()}
+		api.drivers = append(api.drivers, driver.ID)
+		writeJSON(w, driver)
+	})
+	mux.HandleFunc("POST /api/v1/drivers/{id}/onboarding/{step}", ok)
+	mux.HandleFunc("POST /api/v1/admin/driver-onboarding/{id}/{step}", ok)
+	mux.HandleFunc("PUT /api/v1/drivers/{id}/status", func(w http.ResponseWriter, r *http.Request) {
+		var req handlers.UpdateDriverStatusRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		id := uuid.MustParse(r.PathValue("id"))
+		api.statuses[id] = append(api.statuses[id], req.Status)
+		ok(w, r)
+	})
+	mux.HandleFunc("PUT /api/v1/drivers/{id}/location", func(w http.ResponseWriter, r *http.Request) {
+		var req handlers.UpdateDriverLocationRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		id := uuid.MustParse(r.PathValue("id"))
+		api.locations[id] = append(api.locations[id], models.Location{Latitude: req.Latitude, Longitude: req.Longitude})
+		ok(w, r)
+	})
+	mux.HandleFunc("POST /api/v1/passengers", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, models.User{ID: uuid.New()})
+	})
+	mux.HandleFunc("GET /api/v1/users/{id}/passenger", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, models.Passenger{ID: uuid.New(), UserID: uuid.MustParse(r.PathValue("id"))})
+	})
+	mux.HandleFunc("POST /api/v1/rides/request", func(w http.ResponseWriter, r *http.Request) {
+		var req handlers.RequestRideRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		tripID := uuid.New()
+		api.trips[tripID] = req
+		writeJSON(w, handlers.RequestRideResponse{TripID: tripID, Status: string(models.TripStatusRequested)})
+	})
+	mux.HandleFunc("GET /api/v1/rides/{id}/status", func(w http.ResponseWriter, r *http.Request) {
+		api.mu.Lock()
+		defer api.mu.Unlock()
+		trip := models.Trip{ID: uuid.MustParse(r.PathValue("id")), Status: models.TripStatusMatched}
+		trip.DriverID = &api.drivers[0]
+		writeJSON(w, trip)
+	})
+	mux.HandleFunc("POST /api/v1/rides/{id}/cancel", ok)
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return api, server
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// testScenario returns a short, fast-ticking scenario in a small area
+func testScenario(drivers, passengers int, speedKmh float64) *scenario.Scenario {
+	sc := scenario.Default()
+	sc.Seed = 42
+	sc.Duration = 400 * time.Millisecond
+	sc.Tick = 10 * time.Millisecond
+	sc.Area.RadiusKm = 2
+	sc.Drivers.Count = drivers
+	sc.Drivers.SpeedKmh = speedKmh
+	sc.Passengers.Count = passengers
+	sc.Passengers.ArrivalRate = 6000
+	sc.Passengers.MatchTimeout = time.Second
+	return sc
+}
+
+func runSimulation(t *testing.T, server *httptest.Server, sc *scenario.Scenario) *simulate.Simulation {
+	cfg := simulate.Config{BaseURL: server.URL, Approach: "actor", RequestTimeout: time.Second, Scenario: sc}
+	require.NoError(t, cfg.Validate())
+	sim := simulate.New(cfg)
+	require.NoError(t, sim.Run(context.Background()))
+	return sim
+}
+
+func TestMoveToward(t *testing.T) {
+	start := models.Location{Latitude: -6.2, Longitude: 106.82}
+	target := models.Location{Latitude: -6.25, Longitude: 106.85}
+	total := start.DistanceTo(target)
+
+	tests := []struct {
+		name    string
+		stepKm  float64
+		arrived bool
+	}{
+		{"short step stays on the way", 0.5, false},
+		{"step just short of the target", total - 0.01, false},
+		{"step exactly to the target", total, true},
+		{"long step stops at the target", total * 3, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pos := start
+			assert.Equal(t, tt.arrived, simulate.MoveToward(&pos, target, tt.stepKm))
+			if tt.arrived {
+				assert.Equal(t, target, pos)
+				return
+			}
+			assert.InDelta(t, tt.stepKm, start.DistanceTo(pos), 0.01, "moves the step length")
+			assert.InDelta(t, total-tt.stepKm, pos.DistanceTo(target), 0.01, "moves straight toward the target")
+		})
+	}
+
+	t.Run("repeated steps arrive", func(t *testing.T) {
+		pos := start
+		steps := 0
+		for !simulate.MoveToward(&pos, target, 0.25) {
+			steps++
+			require.Less(t, steps, 100)
+		}
+		assert.Equal(t, int(total/0.25), steps)
+		assert.Equal(t, target, pos)
+	})
+}
+
+func TestSimulation_DriversStepEveryTick(t *testing.T) {
+	api, server := newFakeAPI(t)
+	sc := testScenario(2, 0, 36000) // 0.1 km per tick
+	sim := runSimulation(t, server, sc)
+
+	stats := sim.Stats()
+	assert.Equal(t, int64(2), stats.DriversRegistered)
+	assert.Zero(t, stats.APIErrors)
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	require.Len(t, api.drivers, 2)
+	stepKm := sc.Drivers.SpeedKmh * sc.Tick.Hours()
+	var updates int64
+	for _, id := range api.drivers {
+		locations := api.locations[id]
+		// The first location places the driver at registration; every later one is a step
+		require.Greater(t, len(locations), 5, "drivers report their location every tick")
+		updates += int64(len(locations) - 1)
+		for i := 1; i < len(locations); i++ {
+			// Steps interpolate the coordinates linearly, a close approximation of the great circle
+			assert.LessOrEqual(t, locations[i-1].DistanceTo(locations[i]), stepKm*1.001, "a tick moves at most the driver's speed")
+		}
+		statuses := api.statuses[id]
+		assert.Equal(t, string(models.DriverStatusOnline), statuses[0])
+		assert.Equal(t, string(models.DriverStatusOffline), statuses[len(statuses)-1], "drivers go offline when the run ends")
+	}
+	// A location update cut off by the end of the run may reach the server without being counted
+	assert.LessOrEqual(t, stats.LocationUpdates, updates)
+	assert.GreaterOrEqual(t, stats.LocationUpdates, updates-2)
+}
+
+func TestSimulation_DriverDrivesMatchedTrip(t *testing.T) {
+	api, server := newFakeAPI(t)
+	sc := testScenario(1, 1, 3600000) // 10 km per tick, so every target is reached in one tick
+	sim := runSimulation(t, server, sc)
+
+	stats := sim.Stats()
+	require.Positive(t, stats.RidesRequested)
+	assert.Positive(t, stats.RidesMatched)
+	assert.Positive(t, stats.TripsDriven)
+	assert.LessOrEqual(t, stats.TripsDriven, stats.RidesMatched)
+	assert.Zero(t, stats.APIErrors)
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	driverID := api.drivers[0]
+	visited := func(loc models.Location) bool {
+		for _, l := range api.locations[driverID] {
+			if l.DistanceTo(loc) < 1e-6 {
+				return true
+			}
+		}
+		return false
+	}
+	driven := 0
+	for _, trip := range api.trips {
+		pickup := models.Location{Latitude: trip.PickupLat, Longitude: trip.PickupLng}
+		dropoff := models.Location{Latitude: trip.DestinationLat, Longitude: trip.DestinationLng}
+		if visited(pickup) && visited(dropoff) {
+			driven++
+		}
+	}
+	// The run may end right after the last dropoff, before it is reported and the driver freed
+	assert.GreaterOrEqual(t, int64(driven), stats.TripsDriven-1, "the driver reports the pickup and dropoff of every trip it drove")
+
+	online := 0
+	for _, status := range api.statuses[driverID] {
+		if status == string(models.DriverStatusOnline) {
+			online++
+		}
+	}
+	assert.GreaterOrEqual(t, int64(online), stats.TripsDriven, "the driver is freed after every trip")
+}
@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore_PutGetDelete(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir(), "secret")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	require.NoError(t, store.Put(ctx, "avatars/u1/a.png", []byte("picture"), "image/png"))
+
+	object, err := store.Get(ctx, "avatars/u1/a.png")
+	require.NoError(t, err)
+	defer object.Body.Close()
+	body, err := io.ReadAll(object.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "picture", string(body))
+	assert.Equal(t, "image/png", object.ContentType)
+	assert.Equal(t, int64(7), object.Size)
+
+	require.NoError(t, store.Delete(ctx, "avatars/u1/a.png"))
+	_, err = store.Get(ctx, "avatars/u1/a.png")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Deleting a missing file succeeds
+	assert.NoError(t, store.Delete(ctx, "avatars/u1/a.png"))
+}
+
+func TestLocalStore_InvalidKey(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir(), "secret")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, key := range []string{"", "/etc/passwd", "../outside.png", "avatars/../../outside.png", "avatars//a.png"} {
+		assert.ErrorIs(t, store.Put(ctx, key, []byte("x"), "image/png"), storage.ErrInvalidKey, key)
+		_, err := store.Get(ctx, key)
+		assert.ErrorIs(t, err, storage.ErrInvalidKey, key)
+	}
+}
+
+func TestLocalStore_SignedURL(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir(), "secret")
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	signed, err := store.SignedURL(ctx, "avatars/u1/a.png", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(signed, storage.LocalFilesPath+"avatars/u1/a.png?"))
+
+	parsed, err := url.Parse(signed)
+	require.NoError(t, err)
+	query := parsed.Query()
+	assert.NoError(t, store.VerifyURL("avatars/u1/a.png", query))
+
+	// The signature covers the key
+	assert.ErrorIs(t, store.VerifyURL("avatars/u2/a.png", query), storage.ErrInvalidSignature)
+
+	// and the expiry
+	tampered := url.Values{"expires": {"9999999999"}, "signature": {query.Get("signature")}}
+	assert.ErrorIs(t, store.VerifyURL("avatars/u1/a.png", tampered), storage.ErrInvalidSignature)
+
+	// Another key does not sign the same URL
+	other, err := storage.NewLocalStore(t.TempDir(), "other")
+	require.NoError(t, err)
+	assert.ErrorIs(t, other.VerifyURL("avatars/u1/a.png", query), storage.ErrInvalidSignature)
+}
+
+func TestLocalStore_SignedURL_Expired(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir(), "secret")
+	require.NoError(t, err)
+
+	signed, err := store.SignedURL(context.Background(), "avatars/u1/a.png", -time.Minute)
+	require.NoError(t, err)
+	parsed, err := url.Parse(signed)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, store.VerifyURL("avatars/u1/a.png", parsed.Query()), storage.ErrInvalidSignature)
+}
@@ -0,0 +1,82 @@
+package timerange
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/timerange"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var now = time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+func parser(maxWindow time.Duration) timerange.Parser {
+	return timerange.Parser{MaxWindow: maxWindow, Now: func() time.Time { return now }}
+}
+
+func TestParser_Parse_RelativeBounds(t *testing.T) {
+	tests := []struct {
+		start, end string
+		want       timerange.Range
+	}{
+		{"-15m", "now", timerange.Range{Start: now.Add(-15 * time.Minute), End: now}},
+		{"now-7d", "now-1d", timerange.Range{Start: now.AddDate(0, 0, -7), End: now.AddDate(0, 0, -1)}},
+		{"-1w", "", timerange.Range{Start: now.AddDate(0, 0, -7), End: now}},
+		{"-1d12h", "+30m", timerange.Range{Start: now.Add(-36 * time.Hour), End: now.Add(30 * time.Minute)}},
+		{"2024-03-10T11:00:00Z", "-30m", timerange.Range{Start: now.Add(-time.Hour), End: now.Add(-30 * time.Minute)}},
+		{"", "", timerange.Range{Start: now.Add(-time.Hour), End: now}},
+		{"", "2024-03-01T00:00:00Z", timerange.Range{Start: time.Date(2024, 2, 29, 23, 0, 0, 0, time.UTC), End: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)}},
+	}
+	for _, tt := range tests {
+		got, err := parser(0).Parse(tt.start, tt.end, time.Hour)
+		require.NoError(t, err, "start=%s end=%s", tt.start, tt.end)
+		assert.True(t, tt.want.Start.Equal(got.Start), "start=%s end=%s: start %s", tt.start, tt.end, got.Start)
+		assert.True(t, tt.want.End.Equal(got.End), "start=%s end=%s: end %s", tt.start, tt.end, got.End)
+	}
+}
+
+func TestParser_Parse_RejectsInvalidRanges(t *testing.T) {
+	tests := []struct {
+		start, end string
+		param      string
+	}{
+		{"yesterday", "now", "start"},
+		{"-15", "now", "start"},
+		{"-", "now", "start"},
+		{"-1x", "now", "start"},
+		{"-15m", "now-", "end"},
+		{"now", "-15m", ""},
+		{"-15m", "-15m", ""},
+		{"-8d", "now", ""},
+	}
+	for _, tt := range tests {
+		_, err := parser(7*24*time.Hour).Parse(tt.start, tt.end, time.Hour)
+		var rangeErr *timerange.Error
+		require.True(t, errors.As(err, &rangeErr), "start=%s end=%s: %v", tt.start, tt.end, err)
+		assert.Equal(t, tt.param, rangeErr.Param, "start=%s end=%s", tt.start, tt.end)
+	}
+
+	_, err := parser(7*24*time.Hour).Parse("-8d", "now", time.Hour)
+	assert.EqualError(t, err, "the range may span at most 7d")
+
+	_, err = parser(0).Parse("-30d", "now", time.Hour)
+	assert.NoError(t, err, "ranges are not limited without a max window")
+}
+
+func TestParseDuration(t *testing.T) {
+	d, err := timerange.ParseDuration("2w3d4h30m")
+	require.NoError(t, err)
+	assert.Equal(t, 17*24*time.Hour+4*time.Hour+30*time.Minute, d)
+
+	d, err = timerange.ParseDuration("-1.5d")
+	require.NoError(t, err)
+	assert.Equal(t, -36*time.Hour, d)
+
+	for _, value := range []string{"", "d", "1d1w", "1h1d", "--1d", "-1d-1h"} {
+		_, err := timerange.ParseDuration(value)
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,153 @@
+package tripcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+	"actor-model-observability/internal/service"
+	"actor-model-observability/internal/tripcache"
+	"actor-model-observability/tests/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixture is a trip cache over a Redis mock writing through a memory trip repository with one
+// passenger
+type fixture struct {
+	cache     *tripcache.Cache
+	redis     *utils.RedisMock
+	trips     repository.TripRepository // written through the cache
+	stored    repository.TripRepository // bypassing the cache
+	rides     *service.RideService
+	passenger *models.Passenger
+}
+
+func newFixture(t *testing.T) *fixture {
+	logger := utils.NewTestLogger(t)
+
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	passengers := memory.NewPassengerRepository(store)
+	user := &models.User{ID: uuid.New(), Email: "rider@example.com", Phone: "+6281234567832", Name: "Test Rider", UserType: models.UserTypePassenger}
+	require.NoError(t, users.Create(ctx, user))
+	passenger := &models.Passenger{ID: uuid.New(), UserID: user.ID}
+	require.NoError(t, passengers.Create(ctx, passenger))
+
+	redis := utils.NewRedisMock()
+	cache, err := tripcache.New(redis, config.DefaultTripCacheConfig(), nil, logger)
+	require.NoError(t, err)
+
+	stored := memory.NewTripRepository(store)
+	trips := cache.Wrap(stored)
+	rides := service.NewRideService(users, memory.NewDriverRepository(store), passengers, trips, nil, nil, nil, logger, true)
+	rides.SetTripCache(cache)
+	return &fixture{cache: cache, redis: redis, trips: trips, stored: stored, rides: rides, passenger: passenger}
+}
+
+func (f *fixture) requestTrip(t *testing.T) *models.Trip {
+	now := time.Now()
+	trip := &models.Trip{
+		ID:                   uuid.New(),
+		PassengerID:          f.passenger.ID,
+		PickupLatitude:       -6.2,
+		PickupLongitude:      106.82,
+		DestinationLatitude:  -6.3,
+		DestinationLongitude: 106.85,
+		Status:               models.TripStatusRequested,
+		RequestedAt:          now,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	require.NoError(t, f.trips.Create(context.Background(), trip))
+	return trip
+}
+
+func (f *fixture) cached(trip *models.Trip) bool {
+	_, ok := f.redis.Value("trip_status:" + trip.ID.String())
+	return ok
+}
+
+func TestCache_WritesThroughActiveTrips(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	trip := f.requestTrip(t)
+	assert.True(t, f.cached(trip))
+
+	trip.Status = models.TripStatusMatched
+	require.NoError(t, f.trips.Update(ctx, trip))
+
+	// The lookup is served from the cache, even though the database is changed behind its back
+	bypassed := *trip
+	bypassed.Status = models.TripStatusDriverArrived
+	require.NoError(t, f.stored.Update(ctx, &bypassed))
+	status, err := f.rides.GetTripStatus(ctx, trip.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.TripStatusMatched, status.Status)
+
+	// until the change is invalidated, as the change listener does
+	f.cache.Invalidate(ctx, trip.ID.String())
+	status, err = f.rides.GetTripStatus(ctx, trip.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.TripStatusDriverArrived, status.Status)
+	assert.True(t, f.cached(trip))
+
+	stats := f.cache.Stats()
+	assert.Equal(t, int64(1), stats.Hits)
+	assert.Equal(t, int64(1), stats.Misses)
+	assert.InDelta(t, 0.5, stats.HitRatio, 1e-9)
+}
+
+func TestCache_DropsTerminalTrips(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	trip := f.requestTrip(t)
+	trip.Status = models.TripStatusCancelled
+	require.NoError(t, f.trips.Update(ctx, trip))
+	assert.False(t, f.cached(trip))
+
+	// Terminal trips read from the database are not cached either
+	status, err := f.rides.GetTripStatus(ctx, trip.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, models.TripStatusCancelled, status.Status)
+	assert.False(t, f.cached(trip))
+
+	require.NoError(t, f.trips.Delete(ctx, trip.ID.String()))
+	_, err = f.rides.GetTripStatus(ctx, trip.ID.String())
+	var notFound *models.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestCache_FailedWriteDropsTrip(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	trip := f.requestTrip(t)
+	// The stored trip is no longer matching, so the conditional write fails
+	err := f.trips.RecordDriverCancellation(ctx, trip, models.TripStatusMatched, &models.TripRematch{ID: uuid.New(), TripID: trip.ID})
+	require.Error(t, err)
+	assert.False(t, f.cached(trip))
+}
+
+func TestCache_FallsBackToDatabaseWhenRedisIsDown(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	trip := f.requestTrip(t)
+	f.redis.SetDown(true)
+
+	status, err := f.rides.GetTripStatus(ctx, trip.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, trip.ID, status.ID)
+	assert.Equal(t, int64(1), f.cache.Stats().Errors)
+	assert.Zero(t, f.cache.Stats().HitRatio)
+}
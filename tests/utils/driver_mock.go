@@ -4,6 +4,7 @@ import (
 	"actor-model-observability/internal/models"
 	"context"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 // MockDriverRepository MockUserRepository Mock repositories
@@ -51,12 +52,93 @@ func (m *MockDriverRepository) UpdateLocation(ctx context.Context, driverID stri
 	return args.Error(0)
 }
 
+func (m *MockDriverRepository) UpdateLocations(ctx context.Context, updates []*models.DriverLocationUpdate) (int64, error) {
+	args := m.Called(ctx, updates)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockDriverRepository) UpdateStatus(ctx context.Context, driverID string, status models.DriverStatus) error {
 	args := m.Called(ctx, driverID, status)
 	return args.Error(0)
 }
 
+func (m *MockDriverRepository) Reserve(ctx context.Context, driverID string) error {
+	args := m.Called(ctx, driverID)
+	return args.Error(0)
+}
+
+func (m *MockDriverRepository) Release(ctx context.Context, driverID string) error {
+	args := m.Called(ctx, driverID)
+	return args.Error(0)
+}
+
 func (m *MockDriverRepository) List(ctx context.Context, limit, offset int) ([]*models.Driver, error) {
 	args := m.Called(ctx, limit, offset)
 	return args.Get(0).([]*models.Driver), args.Error(1)
 }
+
+func (m *MockDriverRepository) ListByFleet(ctx context.Context, fleetID string) ([]*models.Driver, error) {
+	args := m.Called(ctx, fleetID)
+	return args.Get(0).([]*models.Driver), args.Error(1)
+}
+
+func (m *MockDriverRepository) GetDriverStats(ctx context.Context, filter models.DriverStatsFilter) ([]*models.DriverStats, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*models.DriverStats), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockDriverRepository) GetTripStats(ctx context.Context, driverID string) (*models.DriverTripStats, error) {
+	args := m.Called(ctx, driverID)
+	return args.Get(0).(*models.DriverTripStats), args.Error(1)
+}
+
+func (m *MockDriverRepository) SetDestination(ctx context.Context, destination *models.DriverDestination) error {
+	args := m.Called(ctx, destination)
+	return args.Error(0)
+}
+
+func (m *MockDriverRepository) GetActiveDestination(ctx context.Context, driverID string) (*models.DriverDestination, error) {
+	args := m.Called(ctx, driverID)
+	return args.Get(0).(*models.DriverDestination), args.Error(1)
+}
+
+func (m *MockDriverRepository) GetActiveDestinations(ctx context.Context) (map[string]*models.DriverDestination, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(map[string]*models.DriverDestination), args.Error(1)
+}
+
+func (m *MockDriverRepository) ClearDestination(ctx context.Context, driverID string) error {
+	args := m.Called(ctx, driverID)
+	return args.Error(0)
+}
+
+func (m *MockDriverRepository) CountDestinationsSince(ctx context.Context, driverID string, since time.Time) (int, error) {
+	args := m.Called(ctx, driverID, since)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockDriverRepository) GetDriverHours(ctx context.Context, driverID string, from, to time.Time) (*models.DriverHours, error) {
+	args := m.Called(ctx, driverID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DriverHours), args.Error(1)
+}
+
+func (m *MockDriverRepository) ListOnlineDriverHours(ctx context.Context, from, to time.Time) ([]*models.DriverHours, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).([]*models.DriverHours), args.Error(1)
+}
+
+func (m *MockDriverRepository) StartRest(ctx context.Context, rest *models.DriverRest) error {
+	args := m.Called(ctx, rest)
+	return args.Error(0)
+}
+
+func (m *MockDriverRepository) GetLatestRest(ctx context.Context, driverID string) (*models.DriverRest, error) {
+	args := m.Called(ctx, driverID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DriverRest), args.Error(1)
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/config"
+	"actor-model-observability/internal/logging"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/repository"
+	"actor-model-observability/internal/repository/memory"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// NewTestLogger returns a logger writing only errors, for the services under test
+func NewTestLogger(t *testing.T) *logging.Logger {
+	logger, err := logging.NewLogger(&config.LoggingConfig{Level: "error", Format: "text", Output: "stdout"})
+	require.NoError(t, err)
+	return logger
+}
+
+// MemoryFixture is an in-memory store with the repositories most services under test need, and
+// builders for the users, passengers, drivers and trips they work on. Every record built gets its
+// own email, phone, license number and plate.
+type MemoryFixture struct {
+	Store      *memory.Store
+	Users      repository.UserRepository
+	Passengers repository.PassengerRepository
+	Drivers    repository.DriverRepository
+	Trips      repository.TripRepository
+	Logger     *logging.Logger
+
+	t    *testing.T
+	next int // numbers the next record built
+}
+
+// NewMemoryFixture creates an empty in-memory store
+func NewMemoryFixture(t *testing.T) *MemoryFixture {
+	store := memory.NewStore()
+	return &MemoryFixture{
+		Store:      store,
+		Users:      memory.NewUserRepository(store),
+		Passengers: memory.NewPassengerRepository(store),
+		Drivers:    memory.NewDriverRepository(store),
+		Trips:      memory.NewTripRepository(store),
+		Logger:     NewTestLogger(t),
+		t:          t,
+	}
+}
+
+// NewUser creates a user of a type
+func (f *MemoryFixture) NewUser(userType models.UserType) *models.User {
+	f.next++
+	user := &models.User{
+		ID:       uuid.New(),
+		Email:    fmt.Sprintf("%s%d@example.com", userType, f.next),
+		Phone:    fmt.Sprintf("+62812345%05d", f.next),
+		Name:     fmt.Sprintf("Test User %d", f.next),
+		UserType: userType,
+	}
+	require.NoError(f.t, f.Users.Create(context.Background(), user))
+	return user
+}
+
+// NewPassenger creates a passenger and their user
+func (f *MemoryFixture) NewPassenger() *models.Passenger {
+	user := f.NewUser(models.UserTypePassenger)
+	passenger := &models.Passenger{ID: uuid.New(), UserID: user.ID}
+	require.NoError(f.t, f.Passengers.Create(context.Background(), passenger))
+	return passenger
+}
+
+// NewDriver creates a sedan driver rated 4.5 and their user; modify adjusts the driver before it
+// is stored, e.g. to place it or assign a fleet
+func (f *MemoryFixture) NewDriver(status models.DriverStatus, modify ...func(*models.Driver)) *models.Driver {
+	user := f.NewUser(models.UserTypeDriver)
+	driver := &models.Driver{
+		ID:            uuid.New(),
+		UserID:        user.ID,
+		LicenseNumber: fmt.Sprintf("LIC-%d", f.next),
+		VehicleType:   "sedan",
+		VehiclePlate:  fmt.Sprintf("B %d XY", f.next),
+		Status:        status,
+		Rating:        4.5,
+	}
+	for _, m := range modify {
+		m(driver)
+	}
+	require.NoError(f.t, f.Drivers.Create(context.Background(), driver))
+	return driver
+}
+
+// NewTrip creates a trip of a passenger across Jakarta, requested now; driver may be nil.
+// modify adjusts the trip before it is stored.
+func (f *MemoryFixture) NewTrip(passenger *models.Passenger, driver *models.Driver, status models.TripStatus, modify ...func(*models.Trip)) *models.Trip {
+	now := time.Now()
+	trip := &models.Trip{
+		ID:                   uuid.New(),
+		PassengerID:          passenger.ID,
+		PickupLatitude:       -6.2,
+		PickupLongitude:      106.82,
+		DestinationLatitude:  -6.3,
+		DestinationLongitude: 106.85,
+		Status:               status,
+		RequestedAt:          now,
+		CreatedAt:            now,
+		UpdatedAt:            now,
+	}
+	if driver != nil {
+		trip.DriverID = &driver.ID
+	}
+	for _, m := range modify {
+		m(trip)
+	}
+	require.NoError(f.t, f.Trips.Create(context.Background(), trip))
+	return trip
+}
+
+// User returns a stored user, e.g. the user of a passenger or driver built before
+func (f *MemoryFixture) User(id uuid.UUID) *models.User {
+	user, err := f.Users.GetByID(context.Background(), id.String())
+	require.NoError(f.t, err)
+	return user
+}
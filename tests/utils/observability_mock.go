@@ -1,9 +1,12 @@
 package utils
 
 import (
+	"actor-model-observability/internal/filter"
 	"actor-model-observability/internal/handlers"
 	"actor-model-observability/internal/models"
 	"context"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
 )
@@ -33,6 +36,11 @@ func (m *MockObservabilityRepository) ListActorInstances(ctx context.Context, ac
 	return args.Get(0).([]*models.ActorInstance), args.Error(1)
 }
 
+func (m *MockObservabilityRepository) ListActorInstancesByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.ActorInstance, error) {
+	args := m.Called(ctx, entityType, entityID, limit, offset)
+	return args.Get(0).([]*models.ActorInstance), args.Error(1)
+}
+
 func (m *MockObservabilityRepository) CreateActorMessage(ctx context.Context, message *models.ActorMessage) error {
 	args := m.Called(ctx, message)
 	return args.Error(0)
@@ -51,6 +59,14 @@ func (m *MockObservabilityRepository) ListActorMessages(ctx context.Context, fro
 	return args.Get(0).([]*models.ActorMessage), args.Error(1)
 }
 
+func (m *MockObservabilityRepository) ListActorMessagesByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.ActorMessage, error) {
+	args := m.Called(ctx, entityType, entityID, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ActorMessage), args.Error(1)
+}
+
 func (m *MockObservabilityRepository) GetMessagesByTimeRange(ctx context.Context, startTime, endTime string, limit, offset int) ([]*models.ActorMessage, error) {
 	args := m.Called(ctx, startTime, endTime, limit, offset)
 	if args.Get(0) == nil {
@@ -59,6 +75,35 @@ func (m *MockObservabilityRepository) GetMessagesByTimeRange(ctx context.Context
 	return args.Get(0).([]*models.ActorMessage), args.Error(1)
 }
 
+func (m *MockObservabilityRepository) GetMessagesByTraceID(ctx context.Context, traceID string) ([]*models.ActorMessage, error) {
+	args := m.Called(ctx, traceID)
+	return args.Get(0).([]*models.ActorMessage), args.Error(1)
+}
+
+func (m *MockObservabilityRepository) FilterActorMessages(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.ActorMessage, error) {
+	args := m.Called(ctx, f.String(), limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ActorMessage), args.Error(1)
+}
+
+func (m *MockObservabilityRepository) GetMessageStats(ctx context.Context, window time.Duration) ([]*models.MessageStats, error) {
+	args := m.Called(ctx, window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.MessageStats), args.Error(1)
+}
+
+func (m *MockObservabilityRepository) GetActorLifecycleActivity(ctx context.Context, lf models.ActorLifecycleFilter) ([]*models.ActorLifecycleActivity, error) {
+	args := m.Called(ctx, lf)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.ActorLifecycleActivity), args.Error(1)
+}
+
 func (m *MockObservabilityRepository) CreateSystemMetric(ctx context.Context, metric *models.SystemMetric) error {
 	args := m.Called(ctx, metric)
 	return args.Error(0)
@@ -99,6 +144,14 @@ func (m *MockObservabilityRepository) ListDistributedTraces(ctx context.Context,
 	return args.Get(0).([]*models.DistributedTrace), args.Error(1)
 }
 
+func (m *MockObservabilityRepository) FilterDistributedTraces(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.DistributedTrace, error) {
+	args := m.Called(ctx, f.String(), limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.DistributedTrace), args.Error(1)
+}
+
 func (m *MockObservabilityRepository) CreateEventLog(ctx context.Context, log *models.EventLog) error {
 	args := m.Called(ctx, log)
 	return args.Error(0)
@@ -119,6 +172,19 @@ func (m *MockObservabilityRepository) GetEventLogsByTimeRange(ctx context.Contex
 	return args.Get(0).([]*models.EventLog), args.Error(1)
 }
 
+func (m *MockObservabilityRepository) ListEventLogsByEntity(ctx context.Context, entityType, entityID string, limit, offset int) ([]*models.EventLog, error) {
+	args := m.Called(ctx, entityType, entityID, limit, offset)
+	return args.Get(0).([]*models.EventLog), args.Error(1)
+}
+
+func (m *MockObservabilityRepository) FilterEventLogs(ctx context.Context, f *filter.Filter, limit, offset int) ([]*models.EventLog, error) {
+	args := m.Called(ctx, f.String(), limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.EventLog), args.Error(1)
+}
+
 // SetupObservabilityHandler creates a test setup for observability handler
 func SetupObservabilityHandler() (*gin.Engine, *MockObservabilityRepository, *MockTraditionalRepository, *handlers.ObservabilityHandler) {
 	gin.SetMode(gin.TestMode)
@@ -127,7 +193,20 @@ func SetupObservabilityHandler() (*gin.Engine, *MockObservabilityRepository, *Mo
 	mockObsRepo := &MockObservabilityRepository{}
 	mockTradRepo := &MockTraditionalRepository{}
 
-	obsHandler := handlers.NewObservabilityHandler(mockObsRepo, mockTradRepo)
+	obsHandler := handlers.NewObservabilityHandler(mockObsRepo, mockTradRepo, nil, nil)
 
 	return router, mockObsRepo, mockTradRepo, obsHandler
 }
+
+// SetupGraphQLHandler creates a test setup for the GraphQL handler
+func SetupGraphQLHandler() (*gin.Engine, *MockObservabilityRepository, *MockTripRepository, *handlers.GraphQLHandler) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockObsRepo := &MockObservabilityRepository{}
+	mockTripRepo := &MockTripRepository{}
+
+	graphqlHandler := handlers.NewGraphQLHandler(mockObsRepo, mockTripRepo, nil)
+
+	return router, mockObsRepo, mockTripRepo, graphqlHandler
+}
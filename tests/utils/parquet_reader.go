@@ -0,0 +1,159 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"actor-model-observability/internal/parquet"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// thriftReader decodes Thrift compact protocol structs into maps of field ID to value: bools,
+// int64s, strings, lists and nested structs
+type thriftReader struct {
+	t    *testing.T
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) byte() byte {
+	require.Less(r.t, r.pos, len(r.data), "truncated thrift data")
+	b := r.data[r.pos]
+	r.pos++
+	return b
+}
+
+func (r *thriftReader) uvarint() uint64 {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	require.Positive(r.t, n, "invalid varint")
+	r.pos += n
+	return v
+}
+
+func (r *thriftReader) varint() int64 {
+	v := r.uvarint()
+	return int64(v>>1) ^ -int64(v&1)
+}
+
+func (r *thriftReader) value(typ byte) any {
+	switch typ {
+	case 1:
+		return true
+	case 2:
+		return false
+	case 5, 6:
+		return r.varint()
+	case 8:
+		n := int(r.uvarint())
+		s := string(r.data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	case 9:
+		header := r.byte()
+		n := int(header >> 4)
+		if n == 15 {
+			n = int(r.uvarint())
+		}
+		list := make([]any, n)
+		for i := range list {
+			list[i] = r.value(header & 0x0f)
+		}
+		return list
+	case 12:
+		return r.readStruct()
+	}
+	r.t.Fatalf("unexpected thrift type %d", typ)
+	return nil
+}
+
+func (r *thriftReader) readStruct() map[int16]any {
+	fields := make(map[int16]any)
+	var last int16
+	for {
+		header := r.byte()
+		if header == 0 {
+			return fields
+		}
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(r.varint())
+		}
+		fields[id] = r.value(header & 0x0f)
+		last = id
+	}
+}
+
+// ReadParquet decodes a Parquet file written with the schema into its values by column name,
+// nil for nulls
+func ReadParquet(t *testing.T, file []byte, columns []parquet.Column) (map[string][]any, map[int16]any) {
+	require.Equal(t, "PAR1", string(file[:4]))
+	require.Equal(t, "PAR1", string(file[len(file)-4:]))
+	footerLength := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	footerStart := len(file) - 8 - footerLength
+	metadata := (&thriftReader{t: t, data: file[footerStart : len(file)-8]}).readStruct()
+
+	values := make(map[string][]any)
+	for _, group := range metadata[4].([]any) {
+		for c, chunk := range group.(map[int16]any)[1].([]any) {
+			column := columns[c]
+			meta := chunk.(map[int16]any)[3].(map[int16]any)
+			reader := &thriftReader{t: t, data: file, pos: int(meta[9].(int64))}
+			header := reader.readStruct()
+			compressed := file[reader.pos : reader.pos+int(header[3].(int64))]
+
+			zr, err := gzip.NewReader(bytes.NewReader(compressed))
+			require.NoError(t, err)
+			page, err := io.ReadAll(zr)
+			require.NoError(t, err)
+			require.Len(t, page, int(header[2].(int64)))
+			numValues := int(header[5].(map[int16]any)[1].(int64))
+
+			defined := make([]bool, numValues)
+			for i := range defined {
+				defined[i] = true
+			}
+			if column.Optional {
+				length := int(binary.LittleEndian.Uint32(page))
+				levels := &thriftReader{t: t, data: page[4 : 4+length]}
+				for i := 0; levels.pos < len(levels.data); {
+					run := int(levels.uvarint() >> 1)
+					value := levels.byte() == 1
+					for j := 0; j < run; j++ {
+						defined[i] = value
+						i++
+					}
+				}
+				page = page[4+length:]
+			}
+
+			for _, isDefined := range defined {
+				if !isDefined {
+					values[column.Name] = append(values[column.Name], nil)
+					continue
+				}
+				var value any
+				switch column.Type {
+				case parquet.String:
+					n := int(binary.LittleEndian.Uint32(page))
+					value, page = string(page[4:4+n]), page[4+n:]
+				case parquet.Double:
+					value, page = math.Float64frombits(binary.LittleEndian.Uint64(page)), page[8:]
+				case parquet.Int64:
+					value, page = int64(binary.LittleEndian.Uint64(page)), page[8:]
+				case parquet.Timestamp:
+					value, page = time.UnixMicro(int64(binary.LittleEndian.Uint64(page))).UTC(), page[8:]
+				}
+				values[column.Name] = append(values[column.Name], value)
+			}
+			assert.Empty(t, page, "every value of the page is read")
+		}
+	}
+	return values, metadata
+}
@@ -0,0 +1,191 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisMock is an in-memory Redis node supporting the commands the distributed locks and caches
+// use: SET, SETNX, GET, DEL, PTTL, SCAN and the lock release and extend scripts, and the stream
+// commands of the real-time streams. Calls of other commands panic.
+type RedisMock struct {
+	redis.UniversalClient
+
+	mu      sync.Mutex
+	values  map[string]string
+	expiry  map[string]time.Time
+	streams map[string]*mockStream
+	added   chan struct{} // closed and replaced whenever an entry is added to a stream
+	legacy  bool
+	down    bool
+}
+
+// ErrRedisDown is returned by a RedisMock set down
+var ErrRedisDown = errors.New("redis mock down")
+
+// NewRedisMock creates an empty RedisMock
+func NewRedisMock() *RedisMock {
+	return &RedisMock{
+		values:  make(map[string]string),
+		expiry:  make(map[string]time.Time),
+		streams: make(map[string]*mockStream),
+		added:   make(chan struct{}),
+	}
+}
+
+// SetDown makes every command fail, as if the node were unreachable
+func (m *RedisMock) SetDown(down bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.down = down
+}
+
+// Put sets a key as another client would
+func (m *RedisMock) Put(key, value string, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = value
+	m.expiry[key] = time.Now().Add(ttl)
+}
+
+// Value returns the value of a key and whether it is set
+func (m *RedisMock) Value(key string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.get(key)
+}
+
+func (m *RedisMock) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return redis.NewStatusResult("", ErrRedisDown)
+	}
+	switch v := value.(type) {
+	case []byte:
+		m.values[key] = string(v)
+	default:
+		m.values[key] = v.(string)
+	}
+	if expiration > 0 {
+		m.expiry[key] = time.Now().Add(expiration)
+	} else {
+		m.expiry[key] = time.Now().Add(24 * time.Hour)
+	}
+	return redis.NewStatusResult("OK", nil)
+}
+
+func (m *RedisMock) SetNX(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.BoolCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return redis.NewBoolResult(false, ErrRedisDown)
+	}
+	if _, ok := m.get(key); ok {
+		return redis.NewBoolResult(false, nil)
+	}
+	m.values[key] = value.(string)
+	m.expiry[key] = time.Now().Add(expiration)
+	return redis.NewBoolResult(true, nil)
+}
+
+func (m *RedisMock) Get(ctx context.Context, key string) *redis.StringCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return redis.NewStringResult("", ErrRedisDown)
+	}
+	value, ok := m.get(key)
+	if !ok {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(value, nil)
+}
+
+func (m *RedisMock) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return redis.NewIntResult(0, ErrRedisDown)
+	}
+	var deleted int64
+	for _, key := range keys {
+		if _, ok := m.get(key); ok {
+			deleted++
+		}
+		delete(m.values, key)
+		delete(m.expiry, key)
+	}
+	return redis.NewIntResult(deleted, nil)
+}
+
+func (m *RedisMock) PTTL(ctx context.Context, key string) *redis.DurationCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return redis.NewDurationResult(0, ErrRedisDown)
+	}
+	if _, ok := m.get(key); !ok {
+		return redis.NewDurationResult(-2*time.Millisecond, nil)
+	}
+	return redis.NewDurationResult(time.Until(m.expiry[key]), nil)
+}
+
+func (m *RedisMock) Scan(ctx context.Context, cursor uint64, match string, count int64) *redis.ScanCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return redis.NewScanCmdResult(nil, 0, ErrRedisDown)
+	}
+	var keys []string
+	for key := range m.values {
+		if _, ok := m.get(key); !ok {
+			continue
+		}
+		if matched, _ := path.Match(match, key); matched {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return redis.NewScanCmdResult(keys, 0, nil)
+}
+
+// Eval runs the lock release script, which deletes the key, or the extend script, which resets
+// its expiry to ARGV[2] milliseconds, while the key holds ARGV[1]
+func (m *RedisMock) Eval(ctx context.Context, script string, keys []string, args ...interface{}) *redis.Cmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return redis.NewCmdResult(nil, ErrRedisDown)
+	}
+	value, ok := m.get(keys[0])
+	if !ok || value != args[0].(string) {
+		return redis.NewCmdResult(int64(0), nil)
+	}
+	switch {
+	case strings.Contains(script, "pexpire"):
+		m.expiry[keys[0]] = time.Now().Add(time.Duration(args[1].(int64)) * time.Millisecond)
+	case strings.Contains(script, "del"):
+		delete(m.values, keys[0])
+		delete(m.expiry, keys[0])
+	default:
+		panic("unsupported script: " + script)
+	}
+	return redis.NewCmdResult(int64(1), nil)
+}
+
+// get returns the value of a key unless it expired
+func (m *RedisMock) get(key string) (string, bool) {
+	value, ok := m.values[key]
+	if !ok || time.Now().After(m.expiry[key]) {
+		return "", false
+	}
+	return value, true
+}
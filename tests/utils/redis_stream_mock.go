@@ -0,0 +1,274 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// mockStream is a stream of a RedisMock. Entry IDs are the number of the entry followed by -0.
+type mockStream struct {
+	entries []redis.XMessage
+	added   int64 // entries ever added, the last entry ID
+	groups  map[string]*mockGroup
+	order   []string
+}
+
+// mockGroup is a consumer group of a mockStream
+type mockGroup struct {
+	lastDelivered int64
+	entriesRead   int64
+	pending       map[int64]string // entry ID to the consumer it was delivered to
+	consumers     map[string]bool
+}
+
+// SetLegacyStreamInfo makes XINFO GROUPS answer like Redis before 7, without entries-read and lag
+func (m *RedisMock) SetLegacyStreamInfo(legacy bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.legacy = legacy
+}
+
+// Pending returns the IDs of the entries delivered to a consumer group but not yet acknowledged
+func (m *RedisMock) Pending(stream, group string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.streams[stream]
+	if !ok || s.groups[group] == nil {
+		return nil
+	}
+	var ids []int64
+	for id := range s.groups[group].pending {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	pending := make([]string, len(ids))
+	for i, id := range ids {
+		pending[i] = formatStreamID(id)
+	}
+	return pending
+}
+
+// XAdd appends an entry and trims the stream to exactly MaxLen entries, also when asked to trim
+// approximately
+func (m *RedisMock) XAdd(ctx context.Context, a *redis.XAddArgs) *redis.StringCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return redis.NewStringResult("", ErrRedisDown)
+	}
+	s := m.stream(a.Stream)
+	s.added++
+	values := make(map[string]interface{})
+	for field, value := range a.Values.(map[string]interface{}) {
+		if data, ok := value.([]byte); ok {
+			value = string(data)
+		}
+		values[field] = value
+	}
+	id := formatStreamID(s.added)
+	s.entries = append(s.entries, redis.XMessage{ID: id, Values: values})
+	if a.MaxLen > 0 && int64(len(s.entries)) > a.MaxLen {
+		s.entries = s.entries[int64(len(s.entries))-a.MaxLen:]
+	}
+	close(m.added)
+	m.added = make(chan struct{})
+	return redis.NewStringResult(id, nil)
+}
+
+func (m *RedisMock) XLen(ctx context.Context, stream string) *redis.IntCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return redis.NewIntResult(0, ErrRedisDown)
+	}
+	s, ok := m.streams[stream]
+	if !ok {
+		return redis.NewIntResult(0, nil)
+	}
+	return redis.NewIntResult(int64(len(s.entries)), nil)
+}
+
+// XGroupCreateMkStream creates a group reading from the start ("0") or the end ("$") of a stream
+func (m *RedisMock) XGroupCreateMkStream(ctx context.Context, stream, group, start string) *redis.StatusCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return redis.NewStatusResult("", ErrRedisDown)
+	}
+	s := m.stream(stream)
+	if s.groups[group] != nil {
+		return redis.NewStatusResult("", errors.New("BUSYGROUP Consumer Group name already exists"))
+	}
+	g := &mockGroup{pending: make(map[int64]string), consumers: make(map[string]bool)}
+	if start == "$" {
+		g.lastDelivered = s.added
+		g.entriesRead = s.added
+	}
+	s.groups[group] = g
+	s.order = append(s.order, group)
+	return redis.NewStatusResult("OK", nil)
+}
+
+// XReadGroup reads a single stream. ">" waits up to Block for entries never delivered to the
+// group; an ID returns the consumer's pending entries after it, without values once trimmed.
+func (m *RedisMock) XReadGroup(ctx context.Context, a *redis.XReadGroupArgs) *redis.XStreamSliceCmd {
+	stream, start := a.Streams[0], a.Streams[1]
+	var timeout <-chan time.Time
+	if a.Block > 0 {
+		timer := time.NewTimer(a.Block)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	for {
+		m.mu.Lock()
+		if m.down {
+			m.mu.Unlock()
+			return redis.NewXStreamSliceCmdResult(nil, ErrRedisDown)
+		}
+		s, ok := m.streams[stream]
+		if !ok || s.groups[a.Group] == nil {
+			m.mu.Unlock()
+			return redis.NewXStreamSliceCmdResult(nil, fmt.Errorf("NOGROUP No such key '%s' or consumer group '%s'", stream, a.Group))
+		}
+		g := s.groups[a.Group]
+		g.consumers[a.Consumer] = true
+
+		if start != ">" {
+			messages := s.pendingAfter(g, a.Consumer, parseStreamID(start), a.Count)
+			m.mu.Unlock()
+			return redis.NewXStreamSliceCmdResult([]redis.XStream{{Stream: stream, Messages: messages}}, nil)
+		}
+
+		var messages []redis.XMessage
+		for _, entry := range s.entries {
+			id := parseStreamID(entry.ID)
+			if id <= g.lastDelivered {
+				continue
+			}
+			if a.Count > 0 && int64(len(messages)) == a.Count {
+				break
+			}
+			messages = append(messages, entry)
+			g.pending[id] = a.Consumer
+			g.lastDelivered = id
+			g.entriesRead++
+		}
+		added := m.added
+		m.mu.Unlock()
+		if len(messages) > 0 {
+			return redis.NewXStreamSliceCmdResult([]redis.XStream{{Stream: stream, Messages: messages}}, nil)
+		}
+
+		select {
+		case <-ctx.Done():
+			return redis.NewXStreamSliceCmdResult(nil, ctx.Err())
+		case <-timeout:
+			return redis.NewXStreamSliceCmdResult(nil, redis.Nil)
+		case <-added:
+		}
+	}
+}
+
+func (m *RedisMock) XAck(ctx context.Context, stream, group string, ids ...string) *redis.IntCmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return redis.NewIntResult(0, ErrRedisDown)
+	}
+	s, ok := m.streams[stream]
+	if !ok || s.groups[group] == nil {
+		return redis.NewIntResult(0, nil)
+	}
+	var acked int64
+	for _, id := range ids {
+		if _, ok := s.groups[group].pending[parseStreamID(id)]; ok {
+			delete(s.groups[group].pending, parseStreamID(id))
+			acked++
+		}
+	}
+	return redis.NewIntResult(acked, nil)
+}
+
+// Do runs XINFO GROUPS, replying with the flat field lists Redis sends
+func (m *RedisMock) Do(ctx context.Context, args ...interface{}) *redis.Cmd {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.down {
+		return redis.NewCmdResult(nil, ErrRedisDown)
+	}
+	if len(args) != 3 || args[0] != "XINFO" || args[1] != "GROUPS" {
+		panic(fmt.Sprintf("unsupported command: %v", args))
+	}
+	s, ok := m.streams[args[2].(string)]
+	if !ok {
+		return redis.NewCmdResult(nil, errors.New("ERR no such key"))
+	}
+	reply := make([]interface{}, 0, len(s.order))
+	for _, name := range s.order {
+		g := s.groups[name]
+		fields := []interface{}{
+			"name", name,
+			"consumers", int64(len(g.consumers)),
+			"pending", int64(len(g.pending)),
+			"last-delivered-id", formatStreamID(g.lastDelivered),
+		}
+		if !m.legacy {
+			fields = append(fields, "entries-read", g.entriesRead, "lag", s.added-g.entriesRead)
+		}
+		reply = append(reply, fields)
+	}
+	return redis.NewCmdResult(reply, nil)
+}
+
+// stream returns a stream, creating it when missing
+func (m *RedisMock) stream(key string) *mockStream {
+	s, ok := m.streams[key]
+	if !ok {
+		s = &mockStream{groups: make(map[string]*mockGroup)}
+		m.streams[key] = s
+	}
+	return s
+}
+
+// pendingAfter returns up to count entries pending for a consumer with IDs after an ID
+func (s *mockStream) pendingAfter(g *mockGroup, consumer string, after, count int64) []redis.XMessage {
+	var ids []int64
+	for id, owner := range g.pending {
+		if owner == consumer && id > after {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	if count > 0 && int64(len(ids)) > count {
+		ids = ids[:count]
+	}
+
+	messages := make([]redis.XMessage, 0, len(ids))
+	for _, id := range ids {
+		message := redis.XMessage{ID: formatStreamID(id)}
+		for _, entry := range s.entries {
+			if entry.ID == message.ID {
+				message.Values = entry.Values
+			}
+		}
+		messages = append(messages, message)
+	}
+	return messages
+}
+
+func formatStreamID(id int64) string {
+	return strconv.FormatInt(id, 10) + "-0"
+}
+
+func parseStreamID(id string) int64 {
+	n, _ := strconv.ParseInt(strings.SplitN(id, "-", 2)[0], 10, 64)
+	return n
+}
@@ -15,16 +15,24 @@ type MockRideService struct {
 
 var _ service.RideServiceInterface = (*MockRideService)(nil)
 
-func (m *MockRideService) RequestRide(ctx context.Context, passengerID string, pickup, dropoff models.Location, pickupAddr, dropoffAddr string) (*models.Trip, error) {
+func (m *MockRideService) RequestRide(ctx context.Context, passengerID string, pickup, dropoff models.Location, pickupAddr, dropoffAddr string, opts ...service.RideOption) (*models.Trip, error) {
 	args := m.Called(ctx, passengerID, pickup, dropoff, pickupAddr, dropoffAddr)
 	return args.Get(0).(*models.Trip), args.Error(1)
 }
 
-func (m *MockRideService) CancelRide(ctx context.Context, tripID string, reason string) error {
-	args := m.Called(ctx, tripID, reason)
+func (m *MockRideService) CancelRide(ctx context.Context, tripID string, cancellation models.TripCancellation) error {
+	args := m.Called(ctx, tripID, cancellation)
 	return args.Error(0)
 }
 
+func (m *MockRideService) CancellationQuote(ctx context.Context, tripID string, reason models.CancellationReason) (*models.CancellationQuote, error) {
+	args := m.Called(ctx, tripID, reason)
+	if quote, ok := args.Get(0).(*models.CancellationQuote); ok {
+		return quote, args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
 func (m *MockRideService) GetTripStatus(ctx context.Context, tripID string) (*models.Trip, error) {
 	args := m.Called(ctx, tripID)
 	return args.Get(0).(*models.Trip), args.Error(1)
@@ -37,7 +45,14 @@ func (m *MockRideService) ListRides(ctx context.Context, passengerID, driverID *
 
 // SetupRideHandler creates a test handler with mocked dependencies
 func SetupRideHandler() (*handlers.RideHandler, *MockRideService) {
-	mockService := new(MockRideService)
-	handler := handlers.NewRideHandler(mockService)
+	handler, mockService, _ := SetupRideHandlerWithSavedLocations()
 	return handler, mockService
 }
+
+// SetupRideHandlerWithSavedLocations also returns the mocked saved location repository
+func SetupRideHandlerWithSavedLocations() (*handlers.RideHandler, *MockRideService, *MockSavedLocationRepository) {
+	mockService := new(MockRideService)
+	mockLocations := new(MockSavedLocationRepository)
+	handler := handlers.NewRideHandler(mockService, mockLocations)
+	return handler, mockService, mockLocations
+}
@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"actor-model-observability/internal/models"
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSavedLocationRepository Mock repository for passenger saved locations
+type MockSavedLocationRepository struct {
+	mock.Mock
+}
+
+func (m *MockSavedLocationRepository) Create(ctx context.Context, location *models.SavedLocation) error {
+	args := m.Called(ctx, location)
+	return args.Error(0)
+}
+
+func (m *MockSavedLocationRepository) GetByID(ctx context.Context, id string) (*models.SavedLocation, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.SavedLocation), args.Error(1)
+}
+
+func (m *MockSavedLocationRepository) Update(ctx context.Context, location *models.SavedLocation) error {
+	args := m.Called(ctx, location)
+	return args.Error(0)
+}
+
+func (m *MockSavedLocationRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockSavedLocationRepository) ListByPassengerID(ctx context.Context, passengerID string) ([]*models.SavedLocation, error) {
+	args := m.Called(ctx, passengerID)
+	return args.Get(0).([]*models.SavedLocation), args.Error(1)
+}
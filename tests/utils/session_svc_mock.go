@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/models"
+	"actor-model-observability/internal/service"
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockSessionService is a mock implementation of SessionServiceInterface
+type MockSessionService struct {
+	mock.Mock
+}
+
+var _ service.SessionServiceInterface = (*MockSessionService)(nil)
+
+func (m *MockSessionService) Login(ctx context.Context, creds service.LoginCredentials) (*models.SessionTokens, error) {
+	args := m.Called(ctx, creds)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SessionTokens), args.Error(1)
+}
+
+func (m *MockSessionService) Refresh(ctx context.Context, refreshToken, clientIP string, app service.ClientApp) (*models.SessionTokens, error) {
+	args := m.Called(ctx, refreshToken, clientIP, app)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.SessionTokens), args.Error(1)
+}
+
+func (m *MockSessionService) Authenticate(ctx context.Context, accessToken string) (*models.Session, error) {
+	args := m.Called(ctx, accessToken)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *MockSessionService) ListSessions(ctx context.Context, userID string) ([]*models.Session, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]*models.Session), args.Error(1)
+}
+
+func (m *MockSessionService) RevokeSession(ctx context.Context, userID, sessionID, clientIP string) error {
+	args := m.Called(ctx, userID, sessionID, clientIP)
+	return args.Error(0)
+}
+
+func (m *MockSessionService) CountSessionsByAppVersion(ctx context.Context) ([]*models.AppVersionSessions, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*models.AppVersionSessions), args.Error(1)
+}
+
+// SetupAuthHandler creates a test handler with a mocked session service
+func SetupAuthHandler() (*gin.Engine, *MockSessionService, *handlers.AuthHandler) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockSessionService := &MockSessionService{}
+	authHandler := handlers.NewAuthHandler(mockSessionService)
+
+	return router, mockSessionService, authHandler
+}
@@ -1,9 +1,12 @@
 package utils
 
 import (
+	"actor-model-observability/internal/handlers"
 	"actor-model-observability/internal/models"
 	"context"
+	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/mock"
+	"time"
 )
 
 // MockTripRepository Mock repositories for Trip
@@ -46,6 +49,11 @@ func (m *MockTripRepository) GetActiveTrips(ctx context.Context) ([]*models.Trip
 	return args.Get(0).([]*models.Trip), args.Error(1)
 }
 
+func (m *MockTripRepository) CountByStatus(ctx context.Context, status models.TripStatus) (int64, error) {
+	args := m.Called(ctx, status)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 func (m *MockTripRepository) GetTripsByStatus(ctx context.Context, status models.TripStatus, limit, offset int) ([]*models.Trip, error) {
 	args := m.Called(ctx, status, limit, offset)
 	return args.Get(0).([]*models.Trip), args.Error(1)
@@ -60,3 +68,126 @@ func (m *MockTripRepository) List(ctx context.Context, limit, offset int) ([]*mo
 	args := m.Called(ctx, limit, offset)
 	return args.Get(0).([]*models.Trip), args.Error(1)
 }
+
+func (m *MockTripRepository) GetRecentDestinations(ctx context.Context, passengerID string, limit int) ([]*models.RecentDestination, error) {
+	args := m.Called(ctx, passengerID, limit)
+	return args.Get(0).([]*models.RecentDestination), args.Error(1)
+}
+
+func (m *MockTripRepository) GetRequestTimes(ctx context.Context, start, end time.Time, bounds *models.GeoBounds) ([]time.Time, error) {
+	args := m.Called(ctx, start, end, bounds)
+	return args.Get(0).([]time.Time), args.Error(1)
+}
+
+func (m *MockTripRepository) GetGridCounts(ctx context.Context, start, end time.Time, cellHeight, cellWidth float64) ([]*models.TripGridCount, error) {
+	args := m.Called(ctx, start, end, cellHeight, cellWidth)
+	return args.Get(0).([]*models.TripGridCount), args.Error(1)
+}
+
+func (m *MockTripRepository) ListByFleet(ctx context.Context, fleetID string, start, end time.Time) ([]*models.Trip, error) {
+	args := m.Called(ctx, fleetID, start, end)
+	return args.Get(0).([]*models.Trip), args.Error(1)
+}
+
+func (m *MockTripRepository) ListRequested(ctx context.Context, start, end time.Time) ([]*models.Trip, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]*models.Trip), args.Error(1)
+}
+
+func (m *MockTripRepository) GetDailyCounts(ctx context.Context, boundaries []time.Time) ([]*models.DailyTripCount, error) {
+	args := m.Called(ctx, boundaries)
+	return args.Get(0).([]*models.DailyTripCount), args.Error(1)
+}
+
+func (m *MockTripRepository) ListCancelled(ctx context.Context, start, end time.Time) ([]*models.Trip, error) {
+	args := m.Called(ctx, start, end)
+	return args.Get(0).([]*models.Trip), args.Error(1)
+}
+
+func (m *MockTripRepository) GetDailyCancellations(ctx context.Context, boundaries []time.Time) ([]*models.DailyCancellationCount, error) {
+	args := m.Called(ctx, boundaries)
+	return args.Get(0).([]*models.DailyCancellationCount), args.Error(1)
+}
+
+func (m *MockTripRepository) Complete(ctx context.Context, trip *models.Trip, from models.TripStatus, completion *models.TripCompletion) error {
+	args := m.Called(ctx, trip, from, completion)
+	return args.Error(0)
+}
+
+func (m *MockTripRepository) GetCompletion(ctx context.Context, tripID string) (*models.TripCompletion, error) {
+	args := m.Called(ctx, tripID)
+	return args.Get(0).(*models.TripCompletion), args.Error(1)
+}
+
+func (m *MockTripRepository) ListCompletions(ctx context.Context, filter models.TripCompletionFilter) ([]*models.TripCompletion, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*models.TripCompletion), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTripRepository) StartPickupWait(ctx context.Context, trip *models.Trip, from models.TripStatus, wait *models.PickupWait) error {
+	args := m.Called(ctx, trip, from, wait)
+	return args.Error(0)
+}
+
+func (m *MockTripRepository) EndPickupWait(ctx context.Context, trip *models.Trip, from models.TripStatus, wait *models.PickupWait) error {
+	args := m.Called(ctx, trip, from, wait)
+	return args.Error(0)
+}
+
+func (m *MockTripRepository) GetPickupWait(ctx context.Context, tripID string) (*models.PickupWait, error) {
+	args := m.Called(ctx, tripID)
+	return args.Get(0).(*models.PickupWait), args.Error(1)
+}
+
+func (m *MockTripRepository) GetPickupWaitStats(ctx context.Context, from, to time.Time) ([]*models.PickupWaitZoneStats, error) {
+	args := m.Called(ctx, from, to)
+	return args.Get(0).([]*models.PickupWaitZoneStats), args.Error(1)
+}
+
+func (m *MockTripRepository) Search(ctx context.Context, filter models.TripSearchFilter) ([]*models.TripSearchResult, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*models.TripSearchResult), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTripRepository) ListStuck(ctx context.Context, updatedBefore time.Time, limit int) ([]*models.Trip, error) {
+	args := m.Called(ctx, updatedBefore, limit)
+	return args.Get(0).([]*models.Trip), args.Error(1)
+}
+
+// SetupTripSearchHandler creates a trip search handler over a mock trip repository
+func SetupTripSearchHandler() (*gin.Engine, *MockTripRepository, *handlers.TripSearchHandler) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockTripRepo := &MockTripRepository{}
+	tripSearchHandler := handlers.NewTripSearchHandler(mockTripRepo)
+
+	return router, mockTripRepo, tripSearchHandler
+}
+
+// SetupTripAdminHandler creates a trip admin handler over a mock trip repository and ride service
+func SetupTripAdminHandler() (*gin.Engine, *MockTripRepository, *MockRideService, *handlers.TripAdminHandler) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockTripRepo := &MockTripRepository{}
+	mockRideService := &MockRideService{}
+	tripAdminHandler := handlers.NewTripAdminHandler(mockTripRepo, mockRideService)
+
+	return router, mockTripRepo, mockRideService, tripAdminHandler
+}
+
+func (m *MockTripRepository) RecordDriverCancellation(ctx context.Context, trip *models.Trip, from models.TripStatus, rematch *models.TripRematch) error {
+	args := m.Called(ctx, trip, from, rematch)
+	return args.Error(0)
+}
+
+func (m *MockTripRepository) EndRematch(ctx context.Context, rematch *models.TripRematch) error {
+	args := m.Called(ctx, rematch)
+	return args.Error(0)
+}
+
+func (m *MockTripRepository) ListRematches(ctx context.Context, tripID string) ([]*models.TripRematch, error) {
+	args := m.Called(ctx, tripID)
+	return args.Get(0).([]*models.TripRematch), args.Error(1)
+}
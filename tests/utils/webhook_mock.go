@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"actor-model-observability/internal/handlers"
+	"actor-model-observability/internal/models"
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockWebhookRepository Mock repository for webhook subscriptions and deliveries
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	args := m.Called(ctx, subscription)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetSubscription(ctx context.Context, id string) (*models.WebhookSubscription, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteSubscription(ctx context.Context, id string) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) ListSubscriptionsByOwner(ctx context.Context, owner string) ([]*models.WebhookSubscription, error) {
+	args := m.Called(ctx, owner)
+	return args.Get(0).([]*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListSubscriptionsForEvent(ctx context.Context, event models.WebhookEvent) ([]*models.WebhookSubscription, error) {
+	args := m.Called(ctx, event)
+	return args.Get(0).([]*models.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	args := m.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]*models.WebhookDelivery, error) {
+	args := m.Called(ctx, now, limit)
+	return args.Get(0).([]*models.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, filter models.WebhookDeliveryFilter) ([]*models.WebhookDelivery, int64, error) {
+	args := m.Called(ctx, filter)
+	return args.Get(0).([]*models.WebhookDelivery), args.Get(1).(int64), args.Error(2)
+}
+
+// SetupWebhookHandler creates a test setup for webhook handler
+func SetupWebhookHandler() (*gin.Engine, *MockWebhookRepository, *handlers.WebhookHandler) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+
+	mockWebhookRepo := &MockWebhookRepository{}
+	webhookHandler := handlers.NewWebhookHandler(mockWebhookRepo)
+
+	return router, mockWebhookRepo, webhookHandler
+}